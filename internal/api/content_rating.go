@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+// ErrContentRatingLocked is returned when the content rating tiers are
+// changed in a session restricted by content rating.
+var ErrContentRatingLocked = errors.New("content rating must be unlocked to change the content rating tiers")
+
+// getMaxContentRating returns the maximum content rating accessible in the
+// current session. Returns nil if all content is accessible.
+func getMaxContentRating(ctx context.Context) *int {
+	tiers := config.GetInstance().GetContentRatingTiers()
+	return tiers.MaxAllowed(session.GetContentRatingLevel(ctx))
+}
+
+// contentRatingHandler restricts the content accessible to the request to the
+// content rating unlocked in the session. The restriction is applied by the
+// stores, so it covers every query, route and loader in the request.
+func contentRatingHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if max := getMaxContentRating(r.Context()); max != nil {
+			r = r.WithContext(models.WithMaxContentRating(r.Context(), *max))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+const (
+	// maxUnlockFailures is the number of failed unlock attempts allowed from
+	// a host before further attempts are refused.
+	maxUnlockFailures = 5
+	// unlockLockout is how long unlock attempts are refused once
+	// maxUnlockFailures has been reached.
+	unlockLockout = 5 * time.Minute
+)
+
+type unlockFailures struct {
+	count int
+	last  time.Time
+}
+
+// unlockThrottle limits the rate of failed content rating unlock attempts
+// per remote host.
+type unlockThrottle struct {
+	mutex    sync.Mutex
+	failures map[string]*unlockFailures
+}
+
+func newUnlockThrottle() *unlockThrottle {
+	return &unlockThrottle{
+		failures: make(map[string]*unlockFailures),
+	}
+}
+
+func unlockHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// expire removes failures older than the lockout period. Assumes lock held.
+func (t *unlockThrottle) expire(now time.Time) {
+	for host, f := range t.failures {
+		if now.Sub(f.last) >= unlockLockout {
+			delete(t.failures, host)
+		}
+	}
+}
+
+// allowed returns false if host has reached the maximum number of failed
+// attempts within the lockout period.
+func (t *unlockThrottle) allowed(host string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire(time.Now())
+	f := t.failures[host]
+	return f == nil || f.count < maxUnlockFailures
+}
+
+func (t *unlockThrottle) fail(host string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	f := t.failures[host]
+	if f == nil {
+		f = &unlockFailures{}
+		t.failures[host] = f
+	}
+	f.count++
+	f.last = time.Now()
+}
+
+func (t *unlockThrottle) succeed(host string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.failures, host)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnlockThrottle(t *testing.T) {
+	throttle := newUnlockThrottle()
+
+	for i := 0; i < maxUnlockFailures; i++ {
+		assert.True(t, throttle.allowed("a"))
+		throttle.fail("a")
+	}
+	assert.False(t, throttle.allowed("a"))
+
+	// failures are per host
+	assert.True(t, throttle.allowed("b"))
+
+	// failures expire after the lockout period
+	throttle.failures["a"].last = time.Now().Add(-unlockLockout)
+	assert.True(t, throttle.allowed("a"))
+
+	throttle.fail("b")
+	throttle.succeed("b")
+	assert.Nil(t, throttle.failures["b"])
+}
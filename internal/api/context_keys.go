@@ -13,4 +13,8 @@ const (
 	tagKey
 	downloadKey
 	imageKey
+	apiKeyIDKey
+	audioKey
+	galleryKey
+	usageTrackerKey
 )
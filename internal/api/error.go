@@ -7,6 +7,7 @@ import (
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -36,7 +37,21 @@ func gqlErrorHandler(ctx context.Context, e error) *gqlerror.Error {
 		}
 	}
 
-	// we may also want to transform the error message for the response
-	// for now just return the original error
-	return graphql.DefaultErrorPresenter(ctx, e)
+	ret := graphql.DefaultErrorPresenter(ctx, e)
+
+	// expose filter validation details so that clients can highlight the
+	// invalid criterion
+	var filterErr *models.FilterValidationError
+	if errors.As(e, &filterErr) {
+		if ret.Extensions == nil {
+			ret.Extensions = make(map[string]interface{})
+		}
+		ret.Extensions["code"] = "INVALID_FILTER"
+		ret.Extensions["path"] = filterErr.Path
+		if len(filterErr.AllowedValues) > 0 {
+			ret.Extensions["allowed_values"] = filterErr.AllowedValues
+		}
+	}
+
+	return ret
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/stashapp/stash/pkg/plugin"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/session"
 )
 
 var (
@@ -37,8 +38,10 @@ type Resolver struct {
 	sceneService   manager.SceneService
 	imageService   manager.ImageService
 	galleryService manager.GalleryService
+	audioService   manager.AudioService
 
 	hookExecutor hookExecutor
+	usageTracker *usageTracker
 }
 
 func (r *Resolver) scraperCache() *scraper.Cache {
@@ -63,18 +66,27 @@ func (r *Resolver) Query() QueryResolver {
 func (r *Resolver) Scene() SceneResolver {
 	return &sceneResolver{r}
 }
+func (r *Resolver) Audio() AudioResolver {
+	return &audioResolver{r}
+}
 func (r *Resolver) Image() ImageResolver {
 	return &imageResolver{r}
 }
 func (r *Resolver) SceneMarker() SceneMarkerResolver {
 	return &sceneMarkerResolver{r}
 }
+func (r *Resolver) SceneRelationship() SceneRelationshipResolver {
+	return &sceneRelationshipResolver{r}
+}
 func (r *Resolver) Studio() StudioResolver {
 	return &studioResolver{r}
 }
 func (r *Resolver) Movie() MovieResolver {
 	return &movieResolver{r}
 }
+func (r *Resolver) MovieScene() MovieSceneResolver {
+	return &movieSceneResolver{r}
+}
 func (r *Resolver) Subscription() SubscriptionResolver {
 	return &subscriptionResolver{r}
 }
@@ -84,9 +96,18 @@ func (r *Resolver) Tag() TagResolver {
 func (r *Resolver) SavedFilter() SavedFilterResolver {
 	return &savedFilterResolver{r}
 }
+func (r *Resolver) FileVerification() FileVerificationResolver {
+	return &fileVerificationResolver{r}
+}
+func (r *Resolver) SelectionSet() SelectionSetResolver {
+	return &selectionSetResolver{r}
+}
 func (r *Resolver) ConfigResult() ConfigResultResolver {
 	return &configResultResolver{r}
 }
+func (r *Resolver) CriterionRating() CriterionRatingResolver {
+	return &criterionRatingResolver{r}
+}
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -97,15 +118,32 @@ type galleryChapterResolver struct{ *Resolver }
 type performerResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
 type sceneMarkerResolver struct{ *Resolver }
+type sceneRelationshipResolver struct{ *Resolver }
+type audioResolver struct{ *Resolver }
 type imageResolver struct{ *Resolver }
 type studioResolver struct{ *Resolver }
 type movieResolver struct{ *Resolver }
+type movieSceneResolver struct{ *Resolver }
 type tagResolver struct{ *Resolver }
 type savedFilterResolver struct{ *Resolver }
+type fileVerificationResolver struct{ *Resolver }
+type selectionSetResolver struct{ *Resolver }
 type configResultResolver struct{ *Resolver }
+type criterionRatingResolver struct{ *Resolver }
 
 func (r *Resolver) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.repository.WithTxn(ctx, fn)
+	return r.repository.WithTxn(withEditSource(ctx), fn)
+}
+
+// withEditSource returns a context in which changes made through the API
+// are recorded in the edit history.
+func withEditSource(ctx context.Context) context.Context {
+	source := models.EditSourceUI
+	if session.IsPluginRequest(ctx) {
+		source = models.EditSourcePlugin
+	}
+
+	return models.WithEditSource(ctx, source)
 }
 
 func (r *Resolver) withReadTxn(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -173,6 +211,7 @@ func (r *queryResolver) Stats(ctx context.Context) (*StatsResultType, error) {
 		totalPlayDuration, _ := scenesQB.PlayDuration(ctx)
 		totalPlayCount, _ := scenesQB.PlayCount(ctx)
 		uniqueScenePlayCount, _ := scenesQB.UniqueScenePlayCount(ctx)
+		filesSize, _ := repo.File.Size(ctx)
 
 		ret = StatsResultType{
 			SceneCount:        scenesCount,
@@ -189,6 +228,7 @@ func (r *queryResolver) Stats(ctx context.Context) (*StatsResultType, error) {
 			TotalPlayDuration: totalPlayDuration,
 			TotalPlayCount:    totalPlayCount,
 			ScenesPlayed:      uniqueScenePlayCount,
+			FilesSize:         filesSize,
 		}
 
 		return nil
@@ -196,6 +236,10 @@ func (r *queryResolver) Stats(ctx context.Context) (*StatsResultType, error) {
 		return nil, err
 	}
 
+	mgr := manager.GetInstance()
+	ret.GeneratedSize = mgr.GetGeneratedSize()
+	ret.Storage = mgr.GetStorageVolumes()
+
 	return &ret, nil
 }
 
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *audioResolver) Date(ctx context.Context, obj *models.Audio) (*string, error) {
+	if obj.Date != nil {
+		result := obj.Date.String()
+		return &result, nil
+	}
+	return nil, nil
+}
+
+func (r *audioResolver) Rating100(ctx context.Context, obj *models.Audio) (*int, error) {
+	return obj.Rating, nil
+}
+
+func (r *audioResolver) Files(ctx context.Context, obj *models.Audio) ([]*models.AudioFile, error) {
+	if !obj.Files.Loaded() {
+		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+			return obj.LoadFiles(ctx, r.repository.Audio)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var ret []*models.AudioFile
+
+	for _, f := range obj.Files.List() {
+		// filter out non-audio files
+		audioFile, ok := f.(*models.AudioFile)
+		if !ok {
+			continue
+		}
+
+		ret = append(ret, audioFile)
+	}
+
+	return ret, nil
+}
+
+func (r *audioResolver) Paths(ctx context.Context, obj *models.Audio) (*AudioPathsType, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewAudioURLBuilder(baseURL, obj)
+	streamPath := builder.GetStreamURL()
+	return &AudioPathsType{
+		Stream: &streamPath,
+	}, nil
+}
+
+func (r *audioResolver) Studio(ctx context.Context, obj *models.Audio) (ret *models.Studio, err error) {
+	if obj.StudioID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).StudioByID.Load(*obj.StudioID)
+}
+
+func (r *audioResolver) Tags(ctx context.Context, obj *models.Audio) (ret []*models.Tag, err error) {
+	if !obj.TagIDs.Loaded() {
+		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+			return obj.LoadTagIDs(ctx, r.repository.Audio)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).TagByID.LoadAll(obj.TagIDs.List())
+	return ret, firstError(errs)
+}
+
+func (r *audioResolver) Performers(ctx context.Context, obj *models.Audio) (ret []*models.Performer, err error) {
+	if !obj.PerformerIDs.Loaded() {
+		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+			return obj.LoadPerformerIDs(ctx, r.repository.Audio)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).PerformerByID.LoadAll(obj.PerformerIDs.List())
+	return ret, firstError(errs)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *fileVerificationResolver) Path(ctx context.Context, obj *models.FileVerification) (string, error) {
+	f, err := loaders.From(ctx).FileByID.Load(obj.FileID)
+	if err != nil {
+		return "", err
+	}
+
+	return f.Base().Path, nil
+}
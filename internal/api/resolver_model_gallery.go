@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/api/urlbuilders"
 	"github.com/stashapp/stash/internal/manager/config"
 
 	"github.com/stashapp/stash/pkg/image"
@@ -110,6 +111,33 @@ func (r *galleryResolver) Studio(ctx context.Context, obj *models.Gallery) (ret
 	return loaders.From(ctx).StudioByID.Load(*obj.StudioID)
 }
 
+func (r *galleryResolver) ParentGallery(ctx context.Context, obj *models.Gallery) (ret *models.Gallery, err error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	// the parent gallery may not be accessible, in which case nil is returned
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Gallery.Find(ctx, *obj.ParentID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *galleryResolver) ChildGalleries(ctx context.Context, obj *models.Gallery) (ret []*models.Gallery, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Gallery.FindChildren(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *galleryResolver) Tags(ctx context.Context, obj *models.Gallery) (ret []*models.Tag, err error) {
 	if !obj.TagIDs.Loaded() {
 		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -138,10 +166,14 @@ func (r *galleryResolver) Performers(ctx context.Context, obj *models.Gallery) (
 	return ret, firstError(errs)
 }
 
-func (r *galleryResolver) ImageCount(ctx context.Context, obj *models.Gallery) (ret int, err error) {
+func (r *galleryResolver) ImageCount(ctx context.Context, obj *models.Gallery, depth *int) (ret int, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		var err error
-		ret, err = r.repository.Image.CountByGalleryID(ctx, obj.ID)
+		if depth == nil {
+			ret, err = r.repository.Image.CountByGalleryID(ctx, obj.ID)
+		} else {
+			ret, err = image.CountByGalleryID(ctx, r.repository.Image, obj.ID, depth)
+		}
 		return err
 	}); err != nil {
 		return 0, err
@@ -189,3 +221,51 @@ func (r *galleryResolver) Urls(ctx context.Context, obj *models.Gallery) ([]stri
 
 	return obj.URLs.List(), nil
 }
+
+func (r *galleryResolver) CustomFields(ctx context.Context, obj *models.Gallery) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Gallery.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *galleryResolver) Document(ctx context.Context, obj *models.Gallery) (*models.DocumentFile, error) {
+	files, err := r.getFiles(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first file is the primary file
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	ret, _ := files[0].(*models.DocumentFile)
+	return ret, nil
+}
+
+func (r *galleryResolver) Pages(ctx context.Context, obj *models.Gallery) ([]*GalleryPagePathsType, error) {
+	f, err := r.Document(ctx, obj)
+	if err != nil || f == nil {
+		return []*GalleryPagePathsType{}, err
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewGalleryURLBuilder(baseURL, obj)
+
+	ret := make([]*GalleryPagePathsType, f.PageCount)
+	for i := range ret {
+		page := i + 1
+		ret[i] = &GalleryPagePathsType{
+			Page:      page,
+			Image:     builder.GetPageImageURL(page),
+			Thumbnail: builder.GetPageThumbnailURL(page),
+		}
+	}
+
+	return ret, nil
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/gallery"
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -16,3 +19,58 @@ func (r *galleryChapterResolver) Gallery(ctx context.Context, obj *models.Galler
 
 	return ret, nil
 }
+
+func (r *galleryChapterResolver) Thumbnail(ctx context.Context, obj *models.GalleryChapter) (string, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	return urlbuilders.NewGalleryChapterURLBuilder(baseURL, obj).GetThumbnailURL(), nil
+}
+
+// imageRange returns the 1-based, inclusive range of the gallery images of
+// the chapter.
+func (r *galleryChapterResolver) imageRange(ctx context.Context, obj *models.GalleryChapter) (start int, end int, err error) {
+	err = r.withReadTxn(ctx, func(ctx context.Context) error {
+		chapters, err := r.repository.GalleryChapter.FindByGalleryID(ctx, obj.GalleryID)
+		if err != nil {
+			return err
+		}
+
+		imageCount, err := r.repository.Image.CountByGalleryID(ctx, obj.GalleryID)
+		if err != nil {
+			return err
+		}
+
+		start, end = gallery.ChapterImageRange(obj, chapters, imageCount)
+		return nil
+	})
+
+	return start, end, err
+}
+
+func (r *galleryChapterResolver) ImageCount(ctx context.Context, obj *models.GalleryChapter) (int, error) {
+	start, end, err := r.imageRange(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	if end < start {
+		return 0, nil
+	}
+
+	return end - start + 1, nil
+}
+
+func (r *galleryChapterResolver) Images(ctx context.Context, obj *models.GalleryChapter) (ret []*models.Image, err error) {
+	start, end, err := r.imageRange(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = image.FindGalleryImageRange(ctx, r.repository.Image, obj.GalleryID, start, end)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
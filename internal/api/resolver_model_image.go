@@ -84,10 +84,12 @@ func (r *imageResolver) Paths(ctx context.Context, obj *models.Image) (*ImagePat
 	thumbnailPath := builder.GetThumbnailURL()
 	previewPath := builder.GetPreviewURL()
 	imagePath := builder.GetImageURL()
+	videoPath := builder.GetVideoURL()
 	return &ImagePathsType{
 		Image:     &imagePath,
 		Thumbnail: &thumbnailPath,
 		Preview:   &previewPath,
+		Video:     &videoPath,
 	}, nil
 }
 
@@ -173,3 +175,48 @@ func (r *imageResolver) Urls(ctx context.Context, obj *models.Image) ([]string,
 
 	return obj.URLs.List(), nil
 }
+
+func (r *imageResolver) ColorPalette(ctx context.Context, obj *models.Image) ([]string, error) {
+	var colors []models.Color
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		colors, err = r.repository.Image.GetColorPalette(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, len(colors))
+	for i, c := range colors {
+		ret[i] = c.Hex()
+	}
+
+	return ret, nil
+}
+
+func (r *imageResolver) SimilarByColor(ctx context.Context, obj *models.Image, limit *int) (ret []*models.Image, err error) {
+	l := 20
+	if limit != nil {
+		l = *limit
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Image.FindSimilarByColor(ctx, obj.ID, l)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *imageResolver) CustomFields(ctx context.Context, obj *models.Image) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Image.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stashapp/stash/internal/api/loaders"
 	"github.com/stashapp/stash/internal/api/urlbuilders"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 )
 
 func (r *movieResolver) Date(ctx context.Context, obj *models.Movie) (*string, error) {
@@ -28,6 +29,25 @@ func (r *movieResolver) Studio(ctx context.Context, obj *models.Movie) (ret *mod
 	return loaders.From(ctx).StudioByID.Load(*obj.StudioID)
 }
 
+func (r *movieResolver) ParentMovie(ctx context.Context, obj *models.Movie) (ret *models.Movie, err error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).MovieByID.Load(*obj.ParentID)
+}
+
+func (r *movieResolver) ChildMovies(ctx context.Context, obj *models.Movie) (ret []*models.Movie, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Movie.FindChildren(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *movieResolver) FrontImagePath(ctx context.Context, obj *models.Movie) (*string, error) {
 	var hasImage bool
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -63,9 +83,13 @@ func (r *movieResolver) BackImagePath(ctx context.Context, obj *models.Movie) (*
 	return &imagePath, nil
 }
 
-func (r *movieResolver) SceneCount(ctx context.Context, obj *models.Movie) (ret int, err error) {
+func (r *movieResolver) SceneCount(ctx context.Context, obj *models.Movie, depth *int) (ret int, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
-		ret, err = r.repository.Scene.CountByMovieID(ctx, obj.ID)
+		if depth == nil {
+			ret, err = r.repository.Scene.CountByMovieID(ctx, obj.ID)
+		} else {
+			ret, err = scene.CountByMovieID(ctx, r.repository.Scene, obj.ID, depth)
+		}
 		return err
 	}); err != nil {
 		return 0, err
@@ -85,3 +109,41 @@ func (r *movieResolver) Scenes(ctx context.Context, obj *models.Movie) (ret []*m
 
 	return ret, nil
 }
+
+func (r *movieResolver) OrderedScenes(ctx context.Context, obj *models.Movie) ([]*models.MovieScene, error) {
+	return r.getMovieSceneIndexes(ctx, obj.ID)
+}
+
+func (r *Resolver) getMovieSceneIndexes(ctx context.Context, movieID int) (ret []*models.MovieScene, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		indexes, err := r.repository.Movie.GetSceneIndexes(ctx, movieID)
+		if err != nil {
+			return err
+		}
+
+		for i := range indexes {
+			ret = append(ret, &indexes[i])
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *movieSceneResolver) Scene(ctx context.Context, obj *models.MovieScene) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *movieResolver) CustomFields(ctx context.Context, obj *models.Movie) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Movie.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
 )
 
 func (r *performerResolver) AliasList(ctx context.Context, obj *models.Performer) ([]string, error) {
@@ -189,3 +190,26 @@ func (r *performerResolver) Movies(ctx context.Context, obj *models.Performer) (
 
 	return ret, nil
 }
+
+func (r *performerResolver) CustomFields(ctx context.Context, obj *models.Performer) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Performer.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *performerResolver) MergedIds(ctx context.Context, obj *models.Performer) (ret []string, err error) {
+	var ids []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ids, err = r.repository.Performer.GetMergedIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return intslice.IntSliceToStringSlice(ids), nil
+}
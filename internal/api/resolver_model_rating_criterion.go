@@ -0,0 +1,18 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *criterionRatingResolver) Criterion(ctx context.Context, obj *models.CriterionRating) (ret *models.RatingCriterion, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.RatingCriterion.Find(ctx, obj.CriterionID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
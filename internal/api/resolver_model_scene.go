@@ -7,6 +7,8 @@ import (
 	"github.com/stashapp/stash/internal/api/loaders"
 	"github.com/stashapp/stash/internal/api/urlbuilders"
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -82,6 +84,55 @@ func (r *sceneResolver) Files(ctx context.Context, obj *models.Scene) ([]*models
 	return files, nil
 }
 
+func (r *sceneResolver) Parts(ctx context.Context, obj *models.Scene) ([]*models.VideoFile, error) {
+	var partIDs []models.FileID
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		partIDs, err = r.repository.Scene.GetPartFileIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	files, errs := loaders.From(ctx).FileByID.LoadAll(partIDs)
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.VideoFile, len(files))
+	for i, f := range files {
+		var err error
+		ret[i], err = convertVideoFile(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) Duration(ctx context.Context, obj *models.Scene) (*float64, error) {
+	parts, err := r.Parts(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) > 0 {
+		var ret float64
+		for _, f := range parts {
+			ret += f.Duration
+		}
+		return &ret, nil
+	}
+
+	f, err := r.getPrimaryFile(ctx, obj)
+	if err != nil || f == nil {
+		return nil, err
+	}
+
+	return &f.Duration, nil
+}
+
 func (r *sceneResolver) Rating(ctx context.Context, obj *models.Scene) (*int, error) {
 	if obj.Rating != nil {
 		rating := models.Rating100To5(*obj.Rating)
@@ -109,6 +160,13 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*ScenePat
 	captionBasePath := builder.GetCaptionURL()
 	interactiveHeatmap := builder.GetInteractiveHeatmapURL()
 
+	// the waveform is only returned if it has been generated
+	var waveformPath *string
+	if exists, _ := fsutil.FileExists(manager.GetInstance().Paths.Scene.GetWaveformPath(objHash)); exists {
+		p := builder.GetWaveformURL()
+		waveformPath = &p
+	}
+
 	return &ScenePathsType{
 		Screenshot:         &screenshotPath,
 		Preview:            &previewPath,
@@ -118,6 +176,7 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*ScenePat
 		Sprite:             &spritePath,
 		Funscript:          &funscriptPath,
 		InteractiveHeatmap: &interactiveHeatmap,
+		Waveform:           waveformPath,
 		Caption:            &captionBasePath,
 	}, nil
 }
@@ -133,6 +192,17 @@ func (r *sceneResolver) SceneMarkers(ctx context.Context, obj *models.Scene) (re
 	return ret, nil
 }
 
+func (r *sceneResolver) Relationships(ctx context.Context, obj *models.Scene) (ret []*models.SceneRelationship, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SceneRelationship.FindBySceneID(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []*models.VideoCaption, err error) {
 	primaryFile, err := r.getPrimaryFile(ctx, obj)
 	if err != nil {
@@ -152,6 +222,25 @@ func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []
 	return ret, err
 }
 
+func (r *sceneResolver) EmbeddedCaptions(ctx context.Context, obj *models.Scene) ([]*manager.EmbeddedCaption, error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return nil, nil
+	}
+
+	// don't fail the whole query if the file cannot be probed
+	ret, err := manager.GetEmbeddedCaptions(obj, primaryFile.Path)
+	if err != nil {
+		logger.Warnf("error getting embedded captions for scene %d: %v", obj.ID, err)
+		return nil, nil
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Galleries(ctx context.Context, obj *models.Scene) (ret []*models.Gallery, err error) {
 	if !obj.GalleryIDs.Loaded() {
 		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -283,6 +372,42 @@ func (r *sceneResolver) InteractiveSpeed(ctx context.Context, obj *models.Scene)
 	return primaryFile.InteractiveSpeed, nil
 }
 
+func (r *sceneResolver) InteractiveAverageSpeed(ctx context.Context, obj *models.Scene) (*int, error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return nil, nil
+	}
+
+	return primaryFile.InteractiveAverageSpeed, nil
+}
+
+func (r *sceneResolver) InteractiveMaxSpeed(ctx context.Context, obj *models.Scene) (*int, error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return nil, nil
+	}
+
+	return primaryFile.InteractiveMaxSpeed, nil
+}
+
+func (r *sceneResolver) InteractiveActionDensity(ctx context.Context, obj *models.Scene) (*int, error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return nil, nil
+	}
+
+	return primaryFile.InteractiveActionDensity, nil
+}
+
 func (r *sceneResolver) URL(ctx context.Context, obj *models.Scene) (*string, error) {
 	if !obj.URLs.Loaded() {
 		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -311,3 +436,89 @@ func (r *sceneResolver) Urls(ctx context.Context, obj *models.Scene) ([]string,
 
 	return obj.URLs.List(), nil
 }
+
+func (r *sceneResolver) ColorPalette(ctx context.Context, obj *models.Scene) ([]string, error) {
+	var colors []models.Color
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		colors, err = r.repository.Scene.GetColorPalette(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([]string, len(colors))
+	for i, c := range colors {
+		ret[i] = c.Hex()
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) SimilarByColor(ctx context.Context, obj *models.Scene, limit *int) (ret []*models.Scene, err error) {
+	l := 20
+	if limit != nil {
+		l = *limit
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.FindSimilarByColor(ctx, obj.ID, l)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) PreviewOptions(ctx context.Context, obj *models.Scene) (ret *models.ScenePreviewOptions, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.GetPreviewOptions(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) CriteriaRatings(ctx context.Context, obj *models.Scene) (ret []*models.CriterionRating, err error) {
+	var ratings []models.CriterionRating
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ratings, err = r.repository.Scene.GetCriteriaRatings(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret = make([]*models.CriterionRating, len(ratings))
+	for i := range ratings {
+		ret[i] = &ratings[i]
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) CriteriaRatingAverage(ctx context.Context, obj *models.Scene) (*int, error) {
+	var ratings []models.CriterionRating
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ratings, err = r.repository.Scene.GetCriteriaRatings(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return models.AverageCriteriaRating(ratings), nil
+}
+
+func (r *sceneResolver) CustomFields(ctx context.Context, obj *models.Scene) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -40,6 +40,10 @@ func (r *sceneMarkerResolver) Tags(ctx context.Context, obj *models.SceneMarker)
 	return ret, err
 }
 
+func (r *sceneMarkerResolver) Duration(ctx context.Context, obj *models.SceneMarker) (*float64, error) {
+	return obj.Duration(), nil
+}
+
 func (r *sceneMarkerResolver) Stream(ctx context.Context, obj *models.SceneMarker) (string, error) {
 	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 	return urlbuilders.NewSceneMarkerURLBuilder(baseURL, obj).GetStreamURL(), nil
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *sceneRelationshipResolver) Scene(ctx context.Context, obj *models.SceneRelationship) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *sceneRelationshipResolver) RelatedScene(ctx context.Context, obj *models.SceneRelationship) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.RelatedSceneID)
+}
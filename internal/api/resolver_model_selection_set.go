@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+func (r *selectionSetResolver) ObjectIds(ctx context.Context, obj *models.SelectionSet) (ret []string, err error) {
+	var ids []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ids, err = r.repository.SelectionSet.GetObjectIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return intslice.IntSliceToStringSlice(ids), nil
+}
+
+func (r *selectionSetResolver) Count(ctx context.Context, obj *models.SelectionSet) (ret int, err error) {
+	var ids []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ids, err = r.repository.SelectionSet.GetObjectIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
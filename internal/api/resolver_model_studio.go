@@ -140,3 +140,14 @@ func (r *studioResolver) Movies(ctx context.Context, obj *models.Studio) (ret []
 
 	return ret, nil
 }
+
+func (r *studioResolver) CustomFields(ctx context.Context, obj *models.Studio) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Studio.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -135,3 +135,14 @@ func (r *tagResolver) ChildCount(ctx context.Context, obj *models.Tag) (ret int,
 
 	return ret, nil
 }
+
+func (r *tagResolver) CustomFields(ctx context.Context, obj *models.Tag) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Tag.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func (r *mutationResolver) AudioUpdate(ctx context.Context, input AudioUpdateInput) (ret *models.Audio, err error) {
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	audioID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	// Populate audio from the input
+	updatedAudio := models.NewAudioPartial()
+
+	updatedAudio.Title = translator.optionalString(input.Title, "title")
+	updatedAudio.Details = translator.optionalString(input.Details, "details")
+	updatedAudio.Rating = translator.optionalInt(input.Rating100, "rating100")
+	updatedAudio.Organized = translator.optionalBool(input.Organized, "organized")
+
+	updatedAudio.Date, err = translator.optionalDate(input.Date, "date")
+	if err != nil {
+		return nil, fmt.Errorf("converting date: %w", err)
+	}
+	updatedAudio.StudioID, err = translator.optionalIntFromString(input.StudioID, "studio_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting studio id: %w", err)
+	}
+
+	updatedAudio.PrimaryFileID, err = translator.fileIDPtrFromString(input.PrimaryFileID)
+	if err != nil {
+		return nil, fmt.Errorf("converting primary file id: %w", err)
+	}
+
+	updatedAudio.PerformerIDs, err = translator.updateIds(input.PerformerIds, "performer_ids")
+	if err != nil {
+		return nil, fmt.Errorf("converting performer ids: %w", err)
+	}
+	updatedAudio.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
+	if err != nil {
+		return nil, fmt.Errorf("converting tag ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Audio
+
+		a, err := qb.Find(ctx, audioID)
+		if err != nil {
+			return err
+		}
+
+		if a == nil {
+			return fmt.Errorf("audio with id %d not found", audioID)
+		}
+
+		if updatedAudio.PrimaryFileID != nil {
+			primaryFileID := *updatedAudio.PrimaryFileID
+
+			if err := a.LoadFiles(ctx, qb); err != nil {
+				return err
+			}
+
+			// ensure that new primary file is associated with audio
+			var f models.File
+			for _, ff := range a.Files.List() {
+				if ff.Base().ID == primaryFileID {
+					f = ff
+				}
+			}
+
+			if f == nil {
+				return fmt.Errorf("file with id %d not associated with audio", primaryFileID)
+			}
+		}
+
+		ret, err = qb.UpdatePartial(ctx, audioID, updatedAudio)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) AudioDestroy(ctx context.Context, input models.AudioDestroyInput) (ret bool, err error) {
+	audioID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	fileDeleter := manager.GetInstance().NewFileDeleter()
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		a, err := r.repository.Audio.Find(ctx, audioID)
+		if err != nil {
+			return err
+		}
+
+		if a == nil {
+			return fmt.Errorf("audio with id %d not found", audioID)
+		}
+
+		return r.audioService.Destroy(ctx, a, fileDeleter, utils.IsTrue(input.DeleteFile))
+	}); err != nil {
+		fileDeleter.Rollback()
+		return false, err
+	}
+
+	// perform the post-commit actions
+	fileDeleter.Commit()
+
+	return true, nil
+}
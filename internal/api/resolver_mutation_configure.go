@@ -9,9 +9,13 @@ import (
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil"
 )
 
 var ErrOverriddenConfig = errors.New("cannot set overridden value")
@@ -29,7 +33,62 @@ func (r *mutationResolver) Migrate(ctx context.Context, input manager.MigrateInp
 func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGeneralInput) (*ConfigGeneralResult, error) {
 	c := config.GetInstance()
 
+	// a restricted session must not be able to lift its own restriction
+	if input.ContentRatingTiers != nil && models.MaxContentRating(ctx) != nil {
+		return makeConfigGeneralResult(), ErrContentRatingLocked
+	}
+
+	if input.ObjectStorage != nil {
+		if err := c.SetObjectStorage(input.ObjectStorage); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+	}
+
+	if input.NetworkShares != nil {
+		if err := c.SetNetworkShares(input.NetworkShares); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+	}
+
 	existingPaths := c.GetStashPaths()
+
+	if input.Libraries != nil || input.Stashes != nil {
+		libraries := c.GetLibraries()
+		if input.Libraries != nil {
+			libraries = config.LibraryList(input.Libraries)
+		}
+
+		// ensure that the libraries of the stash paths exist
+		var stashLibraries []string
+		if input.Stashes != nil {
+			for _, s := range input.Stashes {
+				stashLibraries = append(stashLibraries, s.Library)
+			}
+		} else {
+			for _, s := range existingPaths {
+				stashLibraries = append(stashLibraries, s.Library)
+			}
+		}
+
+		for _, name := range stashLibraries {
+			if name != "" && libraries.Get(name) == nil {
+				return makeConfigGeneralResult(), fmt.Errorf("library %q does not exist", name)
+			}
+		}
+
+		if input.Libraries != nil {
+			if err := c.SetLibraries(libraries); err != nil {
+				return makeConfigGeneralResult(), err
+			}
+		}
+	}
+
+	if input.ScheduledTasks != nil {
+		if err := c.SetScheduledTasks(config.ScheduledTaskList(input.ScheduledTasks)); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+	}
+
 	if input.Stashes != nil {
 		for _, s := range input.Stashes {
 			// Only validate existence of new paths
@@ -40,7 +99,8 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 					break
 				}
 			}
-			if isNew {
+			// mounted paths are not on the local file system
+			if isNew && !c.IsMountedPath(s.Path) {
 				exists, err := fsutil.DirExists(s.Path)
 				if !exists {
 					return makeConfigGeneralResult(), err
@@ -94,6 +154,47 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.BackupDirectoryPath, input.BackupDirectoryPath)
 	}
 
+	existingTrashPath := c.GetTrashPath()
+	if input.TrashPath != nil && existingTrashPath != *input.TrashPath {
+		if err := validateDir(config.TrashPath, *input.TrashPath, true); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+
+		c.Set(config.TrashPath, input.TrashPath)
+	}
+
+	if input.TrashRetentionDays != nil {
+		if *input.TrashRetentionDays < 0 {
+			return makeConfigGeneralResult(), errors.New("trash retention days must not be negative")
+		}
+		c.Set(config.TrashRetentionDays, *input.TrashRetentionDays)
+	}
+
+	if input.GeneratedRetentionDays != nil {
+		if *input.GeneratedRetentionDays < 0 {
+			return makeConfigGeneralResult(), errors.New("generated retention days must not be negative")
+		}
+		c.Set(config.GeneratedRetentionDays, *input.GeneratedRetentionDays)
+	}
+
+	if input.QuarantineDays != nil {
+		if *input.QuarantineDays < 0 {
+			return makeConfigGeneralResult(), errors.New("quarantine days must not be negative")
+		}
+		c.Set(config.QuarantineDays, *input.QuarantineDays)
+	}
+
+	if input.MinFreeSpaceGb != nil {
+		if *input.MinFreeSpaceGb < 0 {
+			return makeConfigGeneralResult(), errors.New("minimum free space must not be negative")
+		}
+		c.Set(config.MinFreeSpaceGB, *input.MinFreeSpaceGb)
+	}
+
+	if input.StorageAlertWebhook != nil {
+		c.Set(config.StorageAlertWebhook, *input.StorageAlertWebhook)
+	}
+
 	existingGeneratedPath := c.GetGeneratedPath()
 	if input.GeneratedPath != nil && existingGeneratedPath != *input.GeneratedPath {
 		if err := validateDir(config.Generated, *input.GeneratedPath, false); err != nil {
@@ -179,10 +280,36 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.CalculateMD5, *input.CalculateMd5)
 	}
 
+	if input.FingerprintAlgorithms != nil {
+		for _, v := range input.FingerprintAlgorithms {
+			if !manager.IsAdditionalFingerprintType(v) {
+				return makeConfigGeneralResult(), fmt.Errorf("unsupported fingerprint algorithm: %s", v)
+			}
+		}
+
+		c.Set(config.FingerprintAlgorithms, input.FingerprintAlgorithms)
+	}
+
 	if input.ParallelTasks != nil {
 		c.Set(config.ParallelTasks, *input.ParallelTasks)
 	}
 
+	if input.ScanWalkParallelTasks != nil {
+		c.Set(config.ScanWalkParallelTasks, *input.ScanWalkParallelTasks)
+	}
+
+	if input.ImageThumbnailBackend != nil {
+		c.Set(config.ImageThumbnailBackend, input.ImageThumbnailBackend.String())
+	}
+
+	if input.ImageThumbnailParallelTasks != nil {
+		c.Set(config.ImageThumbnailParallelTasks, *input.ImageThumbnailParallelTasks)
+	}
+
+	if input.BestFrameCovers != nil {
+		c.Set(config.BestFrameCovers, *input.BestFrameCovers)
+	}
+
 	if input.PreviewAudio != nil {
 		c.Set(config.PreviewAudio, *input.PreviewAudio)
 	}
@@ -202,10 +329,53 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if input.PreviewPreset != nil {
 		c.Set(config.PreviewPreset, input.PreviewPreset.String())
 	}
+	if input.PreviewImageFormat != nil {
+		c.Set(config.PreviewImageFormat, input.PreviewImageFormat.String())
+	}
+	if input.PreviewVideoCodec != nil {
+		c.Set(config.PreviewVideoCodec, input.PreviewVideoCodec.String())
+	}
 
 	if input.TranscodeHardwareAcceleration != nil {
 		c.Set(config.TranscodeHardwareAcceleration, *input.TranscodeHardwareAcceleration)
 	}
+
+	refreshHWSupport := false
+	if input.TranscodeHardwareAccelerationCodec != nil && *input.TranscodeHardwareAccelerationCodec != c.GetTranscodeHardwareAccelerationCodec() {
+		codec := *input.TranscodeHardwareAccelerationCodec
+		if codec != "" && !ffmpeg.IsHWCodec(ffmpeg.VideoCodec(codec)) {
+			return makeConfigGeneralResult(), fmt.Errorf("invalid hardware encoder %q", codec)
+		}
+		c.Set(config.TranscodeHardwareAccelerationCodec, *input.TranscodeHardwareAccelerationCodec)
+		refreshHWSupport = true
+	}
+	if input.TranscodeHardwareAccelerationDevice != nil && *input.TranscodeHardwareAccelerationDevice != c.GetTranscodeHardwareAccelerationDevice() {
+		c.Set(config.TranscodeHardwareAccelerationDevice, *input.TranscodeHardwareAccelerationDevice)
+		refreshHWSupport = true
+	}
+	refreshCodecArgs := false
+	if input.FfmpegLiveCodecArgs != nil {
+		if err := validateFFMpegCodecArgs(input.FfmpegLiveCodecArgs, ffmpeg.DefaultCodecArgs); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.SetFFMpegLiveCodecArgs(input.FfmpegLiveCodecArgs)
+		// hardware codecs are tested with their live transcoding options
+		refreshHWSupport = true
+	}
+	if input.FfmpegGenerateCodecArgs != nil {
+		if err := validateFFMpegCodecArgs(input.FfmpegGenerateCodecArgs, ffmpeg.DefaultGenerateCodecArgs); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.SetFFMpegGenerateCodecArgs(input.FfmpegGenerateCodecArgs)
+		refreshCodecArgs = true
+	}
+	if input.StreamCacheSizeMb != nil {
+		if *input.StreamCacheSizeMb < 0 {
+			return makeConfigGeneralResult(), errors.New("stream cache size must not be negative")
+		}
+		c.Set(config.StreamCacheSizeMB, *input.StreamCacheSizeMb)
+	}
+
 	if input.MaxTranscodeSize != nil {
 		c.Set(config.MaxTranscodeSize, input.MaxTranscodeSize.String())
 	}
@@ -222,6 +392,10 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.CreateImageClipsFromVideos, *input.CreateImageClipsFromVideos)
 	}
 
+	if input.WatchLibrary != nil {
+		c.Set(config.WatchLibrary, *input.WatchLibrary)
+	}
+
 	if input.GalleryCoverRegex != nil {
 
 		_, err := regexp.Compile(*input.GalleryCoverRegex)
@@ -306,14 +480,52 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.ImageExtensions, input.ImageExtensions)
 	}
 
+	if input.AudioExtensions != nil {
+		c.Set(config.AudioExtensions, input.AudioExtensions)
+	}
+
 	if input.GalleryExtensions != nil {
 		c.Set(config.GalleryExtensions, input.GalleryExtensions)
 	}
 
+	if input.DocumentExtensions != nil {
+		c.Set(config.DocumentExtensions, input.DocumentExtensions)
+	}
+
+	if input.SidecarFormats != nil {
+		formats := scene.SidecarFormats()
+		for _, f := range input.SidecarFormats {
+			if !sliceutil.Contains(formats, f) {
+				return makeConfigGeneralResult(), fmt.Errorf("unsupported sidecar format %q", f)
+			}
+		}
+
+		c.Set(config.SidecarFormats, input.SidecarFormats)
+	}
+
+	if input.VerifyFilesInterval != nil {
+		if *input.VerifyFilesInterval < 0 {
+			return makeConfigGeneralResult(), errors.New("verify files interval must not be negative")
+		}
+		c.Set(config.VerifyFilesInterval, *input.VerifyFilesInterval)
+	}
+
+	if input.VerifyFilesSampleSize != nil {
+		if *input.VerifyFilesSampleSize < 0 {
+			return makeConfigGeneralResult(), errors.New("verify files sample size must not be negative")
+		}
+		c.Set(config.VerifyFilesSampleSize, *input.VerifyFilesSampleSize)
+	}
+
 	if input.CreateGalleriesFromFolders != nil {
 		c.Set(config.CreateGalleriesFromFolders, input.CreateGalleriesFromFolders)
 	}
 
+	if input.CaseInsensitivePaths != nil {
+		c.Set(config.CaseInsensitivePaths, *input.CaseInsensitivePaths)
+		manager.GetInstance().SetCaseInsensitivePaths()
+	}
+
 	if input.CustomPerformerImageLocation != nil {
 		c.Set(config.CustomPerformerImageLocation, *input.CustomPerformerImageLocation)
 		initCustomPerformerImages(*input.CustomPerformerImageLocation)
@@ -326,10 +538,66 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.StashBoxes, input.StashBoxes)
 	}
 
+	if input.ContentRatingTiers != nil {
+		if err := c.SetContentRatingTiers(input.ContentRatingTiers); err != nil {
+			return nil, err
+		}
+	}
+
 	if input.PythonPath != nil {
 		c.Set(config.PythonPath, input.PythonPath)
 	}
 
+	if input.QuietHours != nil {
+		if err := c.SetQuietHours(*input.QuietHours); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.ExportFilenameTemplate != nil {
+		if *input.ExportFilenameTemplate != "" {
+			if _, err := jsonschema.ParseFilenameTemplate(*input.ExportFilenameTemplate); err != nil {
+				return nil, err
+			}
+		}
+		c.Set(config.ExportFilenameTemplate, input.ExportFilenameTemplate)
+	}
+
+	if input.ExportMaxThroughputMb != nil {
+		if *input.ExportMaxThroughputMb < 0 {
+			return nil, errors.New("export max throughput must not be negative")
+		}
+		c.Set(config.ExportMaxThroughputMB, *input.ExportMaxThroughputMb)
+	}
+
+	if input.ExportMaxIops != nil {
+		if *input.ExportMaxIops < 0 {
+			return nil, errors.New("export max iops must not be negative")
+		}
+		c.Set(config.ExportMaxIOPS, *input.ExportMaxIops)
+	}
+
+	if input.APIKeyRequestQuota != nil {
+		if *input.APIKeyRequestQuota < 0 {
+			return nil, errors.New("api key request quota must not be negative")
+		}
+		c.Set(config.APIKeyRequestQuota, *input.APIKeyRequestQuota)
+	}
+
+	if input.APIKeyMutationQuota != nil {
+		if *input.APIKeyMutationQuota < 0 {
+			return nil, errors.New("api key mutation quota must not be negative")
+		}
+		c.Set(config.APIKeyMutationQuota, *input.APIKeyMutationQuota)
+	}
+
+	if input.APIKeyStreamQuotaGb != nil {
+		if *input.APIKeyStreamQuotaGb < 0 {
+			return nil, errors.New("api key stream quota must not be negative")
+		}
+		c.Set(config.APIKeyStreamQuotaGB, *input.APIKeyStreamQuotaGb)
+	}
+
 	if input.TranscodeInputArgs != nil {
 		c.Set(config.TranscodeInputArgs, input.TranscodeInputArgs)
 	}
@@ -342,6 +610,19 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if input.LiveTranscodeOutputArgs != nil {
 		c.Set(config.LiveTranscodeOutputArgs, input.LiveTranscodeOutputArgs)
 	}
+	if input.LiveTranscodeLoudnorm != nil {
+		c.Set(config.LiveTranscodeLoudnorm, *input.LiveTranscodeLoudnorm)
+	}
+	if input.LiveTranscodeLoudnormTarget != nil {
+		// range supported by the loudnorm filter
+		if *input.LiveTranscodeLoudnormTarget < -70 || *input.LiveTranscodeLoudnormTarget > -5 {
+			return makeConfigGeneralResult(), errors.New("loudness target must be between -70 and -5 LUFS")
+		}
+		c.Set(config.LiveTranscodeLoudnormTarget, *input.LiveTranscodeLoudnormTarget)
+	}
+	if input.HdrToneMapping != nil {
+		c.Set(config.HDRToneMapping, *input.HdrToneMapping)
+	}
 
 	if input.DrawFunscriptHeatmapRange != nil {
 		c.Set(config.DrawFunscriptHeatmapRange, input.DrawFunscriptHeatmapRange)
@@ -358,6 +639,12 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if refreshStreamManager {
 		manager.GetInstance().RefreshStreamManager()
 	}
+	if refreshHWSupport {
+		// also sets the codec args
+		manager.GetInstance().RefreshHWSupport()
+	} else if refreshCodecArgs {
+		manager.GetInstance().RefreshCodecArgs()
+	}
 	if refreshBlobStorage {
 		manager.GetInstance().SetBlobStoreOptions()
 	}
@@ -365,6 +652,37 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	return makeConfigGeneralResult(), nil
 }
 
+// validateFFMpegCodecArgs returns an error if the encoding options of any
+// codec in input cannot be set. Options that are the same as the built-in
+// options returned by def are cleared, so that changes to the built-in
+// options apply.
+func validateFFMpegCodecArgs(input []*config.FFMpegCodecArgs, def func(ffmpeg.VideoCodec) ffmpeg.Args) error {
+	for _, a := range input {
+		codec := ffmpeg.VideoCodec(a.Codec)
+		if !ffmpeg.IsTemplateCodec(codec) {
+			return fmt.Errorf("encoding options of %q cannot be set", a.Codec)
+		}
+
+		if equalArgs(a.Args, def(codec)) {
+			a.Args = nil
+		}
+	}
+
+	return nil
+}
+
+func equalArgs(a []string, b ffmpeg.Args) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *mutationResolver) ConfigureInterface(ctx context.Context, input ConfigInterfaceInput) (*ConfigInterfaceResult, error) {
 	c := config.GetInstance()
 
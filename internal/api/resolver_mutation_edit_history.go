@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *mutationResolver) EditHistoryRevert(ctx context.Context, input EditHistoryRevertInput) (ret *models.EditHistory, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	force := input.Force != nil && *input.Force
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.EditHistory
+
+		if err := qb.Revert(ctx, id, force); err != nil {
+			return err
+		}
+
+		ret, err = qb.Find(ctx, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
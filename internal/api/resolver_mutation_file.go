@@ -149,7 +149,7 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 		return false, fmt.Errorf("converting ids: %w", err)
 	}
 
-	fileDeleter := file.NewDeleter()
+	fileDeleter := manager.GetInstance().NewFileDeleter()
 	destroyer := &file.ZipDestroyer{
 		FileDestroyer:   r.repository.File,
 		FolderDestroyer: r.repository.Folder,
@@ -207,3 +207,20 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 
 	return true, nil
 }
+
+func (r *mutationResolver) RestoreTrash(ctx context.Context, ids []string) (bool, error) {
+	if err := manager.GetInstance().RestoreTrash(ctx, ids); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) PurgeTrash(ctx context.Context, input manager.PurgeTrashInput) (string, error) {
+	jobID, err := manager.GetInstance().PurgeTrash(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
@@ -8,11 +8,11 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -45,6 +45,7 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 	newGallery.Title = input.Title
 	newGallery.Details = translator.string(input.Details)
 	newGallery.Rating = input.Rating100
+	newGallery.Favorite = translator.bool(input.Favorite)
 
 	var err error
 
@@ -56,6 +57,10 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 	if err != nil {
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
+	newGallery.ParentID, err = translator.intPtrFromString(input.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("converting parent id: %w", err)
+	}
 
 	newGallery.PerformerIDs, err = translator.relatedIds(input.PerformerIds)
 	if err != nil {
@@ -79,6 +84,13 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 	// Start the transaction and save the gallery
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Gallery
+
+		if newGallery.ParentID != nil {
+			if err := gallery.ValidateParent(ctx, 0, *newGallery.ParentID, qb); err != nil {
+				return err
+			}
+		}
+
 		if err := qb.Create(ctx, &newGallery, nil); err != nil {
 			return err
 		}
@@ -185,6 +197,8 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 	updatedGallery.Details = translator.optionalString(input.Details, "details")
 	updatedGallery.Rating = translator.optionalInt(input.Rating100, "rating100")
 	updatedGallery.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedGallery.Favorite = translator.optionalBool(input.Favorite, "favorite")
+	updatedGallery.ContentRating = translator.optionalInt(input.ContentRating, "content_rating")
 
 	updatedGallery.Date, err = translator.optionalDate(input.Date, "date")
 	if err != nil {
@@ -194,6 +208,16 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 	if err != nil {
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
+	updatedGallery.ParentID, err = translator.optionalIntFromString(input.ParentID, "parent_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting parent id: %w", err)
+	}
+
+	if updatedGallery.ParentID.Ptr() != nil {
+		if err := gallery.ValidateParent(ctx, galleryID, *updatedGallery.ParentID.Ptr(), qb); err != nil {
+			return nil, err
+		}
+	}
 
 	updatedGallery.URLs = translator.optionalURLs(input.Urls, input.URL)
 
@@ -241,10 +265,22 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 		return nil, err
 	}
 
+	if input.CustomFields != nil {
+		if err := qb.SetCustomFields(ctx, galleryID, *input.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+
 	return gallery, nil
 }
 
 func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGalleryUpdateInput) ([]*models.Gallery, error) {
+	setIDs, err := r.getSelectionSetIDs(ctx, input.SelectionSetID, models.FilterModeGalleries)
+	if err != nil {
+		return nil, err
+	}
+	input.Ids = sliceutil.AppendUniques(input.Ids, setIDs)
+
 	galleryIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, fmt.Errorf("converting ids: %w", err)
@@ -260,6 +296,8 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 	updatedGallery.Details = translator.optionalString(input.Details, "details")
 	updatedGallery.Rating = translator.optionalInt(input.Rating100, "rating100")
 	updatedGallery.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedGallery.Favorite = translator.optionalBool(input.Favorite, "favorite")
+	updatedGallery.ContentRating = translator.optionalInt(input.ContentRating, "content_rating")
 	updatedGallery.URLs = translator.optionalURLsBulk(input.Urls, input.URL)
 
 	updatedGallery.Date, err = translator.optionalDate(input.Date, "date")
@@ -329,7 +367,7 @@ func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.Gall
 	var galleries []*models.Gallery
 	var imgsDestroyed []*models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.GetInstance().NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 
@@ -493,6 +531,7 @@ func (r *mutationResolver) GalleryChapterCreate(ctx context.Context, input Galle
 
 	newChapter.Title = input.Title
 	newChapter.ImageIndex = input.ImageIndex
+	newChapter.EndImageIndex = input.EndImageIndex
 	newChapter.GalleryID = galleryID
 
 	// Start the transaction and save the gallery chapter
@@ -507,6 +546,14 @@ func (r *mutationResolver) GalleryChapterCreate(ctx context.Context, input Galle
 			return errors.New("Image # must greater than zero and in range of the gallery images")
 		}
 
+		chapters, err := r.repository.GalleryChapter.FindByGalleryID(ctx, galleryID)
+		if err != nil {
+			return err
+		}
+		if err := gallery.ValidateChapterRange(0, newChapter.ImageIndex, newChapter.EndImageIndex, chapters, imageCount); err != nil {
+			return err
+		}
+
 		return r.repository.GalleryChapter.Create(ctx, &newChapter)
 	}); err != nil {
 		return nil, err
@@ -531,6 +578,7 @@ func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input Galle
 
 	updatedChapter.Title = translator.optionalString(input.Title, "title")
 	updatedChapter.ImageIndex = translator.optionalInt(input.ImageIndex, "image_index")
+	updatedChapter.EndImageIndex = translator.optionalInt(input.EndImageIndex, "end_image_index")
 	updatedChapter.GalleryID, err = translator.optionalIntFromString(input.GalleryID, "gallery_id")
 	if err != nil {
 		return nil, fmt.Errorf("converting gallery id: %w", err)
@@ -550,6 +598,7 @@ func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input Galle
 
 		galleryID := existingChapter.GalleryID
 		imageIndex := existingChapter.ImageIndex
+		endImageIndex := existingChapter.EndImageIndex
 
 		if updatedChapter.GalleryID.Set {
 			galleryID = updatedChapter.GalleryID.Value
@@ -557,6 +606,9 @@ func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input Galle
 		if updatedChapter.ImageIndex.Set {
 			imageIndex = updatedChapter.ImageIndex.Value
 		}
+		if updatedChapter.EndImageIndex.Set {
+			endImageIndex = updatedChapter.EndImageIndex.Ptr()
+		}
 
 		imageCount, err := r.repository.Image.CountByGalleryID(ctx, galleryID)
 		if err != nil {
@@ -568,6 +620,14 @@ func (r *mutationResolver) GalleryChapterUpdate(ctx context.Context, input Galle
 			return errors.New("Image # must greater than zero and in range of the gallery images")
 		}
 
+		chapters, err := qb.FindByGalleryID(ctx, galleryID)
+		if err != nil {
+			return err
+		}
+		if err := gallery.ValidateChapterRange(chapterID, imageIndex, endImageIndex, chapters, imageCount); err != nil {
+			return err
+		}
+
 		_, err = qb.UpdatePartial(ctx, chapterID, updatedChapter)
 		if err != nil {
 			return err
@@ -6,7 +6,6 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
@@ -109,6 +108,7 @@ func (r *mutationResolver) imageUpdate(ctx context.Context, input ImageUpdateInp
 	updatedImage.Title = translator.optionalString(input.Title, "title")
 	updatedImage.Rating = translator.optionalInt(input.Rating100, "rating100")
 	updatedImage.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedImage.Favorite = translator.optionalBool(input.Favorite, "favorite")
 
 	updatedImage.Date, err = translator.optionalDate(input.Date, "date")
 	if err != nil {
@@ -179,6 +179,12 @@ func (r *mutationResolver) imageUpdate(ctx context.Context, input ImageUpdateInp
 		return nil, err
 	}
 
+	if input.CustomFields != nil {
+		if err := qb.SetCustomFields(ctx, imageID, *input.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+
 	// #3759 - update all impacted galleries
 	for _, galleryID := range updatedGalleryIDs {
 		if err := r.galleryService.Updated(ctx, galleryID); err != nil {
@@ -190,6 +196,12 @@ func (r *mutationResolver) imageUpdate(ctx context.Context, input ImageUpdateInp
 }
 
 func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageUpdateInput) (ret []*models.Image, err error) {
+	setIDs, err := r.getSelectionSetIDs(ctx, input.SelectionSetID, models.FilterModeImages)
+	if err != nil {
+		return nil, err
+	}
+	input.Ids = sliceutil.AppendUniques(input.Ids, setIDs)
+
 	imageIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, fmt.Errorf("converting ids: %w", err)
@@ -205,6 +217,7 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 	updatedImage.Title = translator.optionalString(input.Title, "title")
 	updatedImage.Rating = translator.optionalInt(input.Rating100, "rating100")
 	updatedImage.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedImage.Favorite = translator.optionalBool(input.Favorite, "favorite")
 
 	updatedImage.Date, err = translator.optionalDate(input.Date, "date")
 	if err != nil {
@@ -303,7 +316,7 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 
 	var i *models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.GetInstance().NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -343,7 +356,7 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 
 	var images []*models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.GetInstance().NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
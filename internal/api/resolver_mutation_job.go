@@ -22,3 +22,8 @@ func (r *mutationResolver) StopAllJobs(ctx context.Context) (bool, error) {
 	manager.GetInstance().JobManager.CancelAll()
 	return true, nil
 }
+
+func (r *mutationResolver) SetQuietHoursOverride(ctx context.Context, input *manager.QuietHoursOverrideInput) (*manager.QuietHoursStatus, error) {
+	ret := manager.GetInstance().SetQuietHoursOverride(input)
+	return &ret, nil
+}
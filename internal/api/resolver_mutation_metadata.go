@@ -14,6 +14,8 @@ import (
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
 )
 
 func (r *mutationResolver) MetadataScan(ctx context.Context, input manager.ScanMetadataInput) (string, error) {
@@ -26,6 +28,10 @@ func (r *mutationResolver) MetadataScan(ctx context.Context, input manager.ScanM
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) ScanFile(ctx context.Context, path string) (*manager.ScanFileResult, error) {
+	return manager.GetInstance().ScanFile(ctx, path)
+}
+
 func (r *mutationResolver) MetadataImport(ctx context.Context) (string, error) {
 	jobID, err := manager.GetInstance().Import(ctx)
 	if err != nil {
@@ -41,11 +47,19 @@ func (r *mutationResolver) ImportObjects(ctx context.Context, input manager.Impo
 		return "", err
 	}
 
-	jobID := manager.GetInstance().RunSingleTask(ctx, t)
+	jobID := manager.GetInstance().JobManager.Add(ctx, t.GetDescription(), t)
 
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) ResolveImportConflicts(ctx context.Context, input []*manager.ImportConflictResolutionInput) (bool, error) {
+	if err := manager.GetInstance().ResolveImportConflicts(input); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *mutationResolver) MetadataExport(ctx context.Context) (string, error) {
 	jobID, err := manager.GetInstance().Export(ctx)
 	if err != nil {
@@ -55,8 +69,49 @@ func (r *mutationResolver) MetadataExport(ctx context.Context) (string, error) {
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataValidateExport(ctx context.Context, input manager.ValidateExportInput) (string, error) {
+	jobID, err := manager.GetInstance().ValidateExport(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) ExportObjects(ctx context.Context, input manager.ExportObjectsInput) (*string, error) {
-	t := manager.CreateExportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
+	for _, v := range []struct {
+		input *manager.ExportObjectTypeInput
+		mode  models.FilterMode
+	}{
+		{input.Scenes, models.FilterModeScenes},
+		{input.Images, models.FilterModeImages},
+		{input.Studios, models.FilterModeStudios},
+		{input.Performers, models.FilterModePerformers},
+		{input.Tags, models.FilterModeTags},
+		{input.Movies, models.FilterModeMovies},
+		{input.Galleries, models.FilterModeGalleries},
+		{input.Markers, models.FilterModeSceneMarkers},
+	} {
+		if v.input == nil {
+			continue
+		}
+
+		setIDs, err := r.getSelectionSetIDs(ctx, v.input.SelectionSetID, v.mode)
+		if err != nil {
+			return nil, err
+		}
+		v.input.Ids = sliceutil.AppendUniques(v.input.Ids, setIDs)
+	}
+
+	if input.FilenameTemplate == nil {
+		template := config.GetInstance().GetExportFilenameTemplate()
+		input.FilenameTemplate = &template
+	}
+
+	t, err := manager.CreateExportTask(config.GetInstance().GetVideoFileNamingAlgorithm(), input)
+	if err != nil {
+		return nil, err
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -74,7 +129,48 @@ func (r *mutationResolver) ExportObjects(ctx context.Context, input manager.Expo
 	return nil, nil
 }
 
+func (r *mutationResolver) ExportManifest(ctx context.Context) (*string, error) {
+	t := manager.CreateExportManifestTask(config.GetInstance().GetVideoFileNamingAlgorithm())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	t.Start(ctx, &wg)
+
+	if t.DownloadHash != "" {
+		baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+
+		// generate timestamp
+		suffix := time.Now().Format("20060102-150405")
+		ret := baseURL + "/downloads/" + t.DownloadHash + "/manifest" + suffix + ".json"
+		return &ret, nil
+	}
+
+	return nil, nil
+}
+
 func (r *mutationResolver) MetadataGenerate(ctx context.Context, input manager.GenerateMetadataInput) (string, error) {
+	if input.SelectionSetID != nil {
+		s, ids, err := r.getSelectionSet(ctx, *input.SelectionSetID)
+		if err != nil {
+			return "", err
+		}
+
+		// empty ids generate for the whole library, which is not what is
+		// intended for an empty or stale selection set
+		if len(ids) == 0 {
+			return "", fmt.Errorf("selection set %q contains no objects", s.Name)
+		}
+
+		switch s.Mode {
+		case models.FilterModeScenes:
+			input.SceneIDs = sliceutil.AppendUniques(input.SceneIDs, ids)
+		case models.FilterModeSceneMarkers:
+			input.MarkerIDs = sliceutil.AppendUniques(input.MarkerIDs, ids)
+		default:
+			return "", fmt.Errorf("selection set %q contains %s, not scenes or scene markers", s.Name, s.Mode)
+		}
+	}
+
 	jobID, err := manager.GetInstance().Generate(ctx, input)
 
 	if err != nil {
@@ -101,6 +197,57 @@ func (r *mutationResolver) MetadataClean(ctx context.Context, input manager.Clea
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataCleanGenerated(ctx context.Context, input manager.CleanGeneratedInput) (string, error) {
+	jobID := manager.GetInstance().CleanGenerated(ctx, input)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataImportNfo(ctx context.Context, input manager.ImportNFOInput) (string, error) {
+	jobID := manager.GetInstance().ImportNFO(ctx, input)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataVerifyFiles(ctx context.Context, input manager.VerifyFilesInput) (string, error) {
+	jobID := manager.GetInstance().VerifyFiles(ctx, input)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataRenameScenes(ctx context.Context, input manager.RenameScenesInput) (string, error) {
+	jobID, err := manager.GetInstance().RenameScenes(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataOrganizeScenes(ctx context.Context, input manager.OrganizeScenesInput) (string, error) {
+	jobID, err := manager.GetInstance().OrganizeScenes(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataResolveDuplicates(ctx context.Context, input manager.ResolveDuplicatesInput) (string, error) {
+	jobID, err := manager.GetInstance().ResolveDuplicates(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataReencodeScenes(ctx context.Context, input manager.ReencodeScenesInput) (string, error) {
+	jobID, err := manager.GetInstance().ReencodeScenes(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MigrateHashNaming(ctx context.Context) (string, error) {
 	jobID := manager.GetInstance().MigrateHash(ctx)
 	return strconv.Itoa(jobID), nil
@@ -209,6 +356,17 @@ func (r *mutationResolver) AnonymiseDatabase(ctx context.Context, input Anonymis
 	return nil, nil
 }
 
+func (r *mutationResolver) DatabaseDoctor(ctx context.Context, input DatabaseDoctorInput) (string, error) {
+	repair := input.Repair != nil && *input.Repair
+	jobID := manager.GetInstance().DatabaseDoctor(ctx, repair)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MergeCaseDuplicatePaths(ctx context.Context) (string, error) {
+	jobID := manager.GetInstance().MergeCaseDuplicatePaths(ctx)
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) OptimiseDatabase(ctx context.Context) (string, error) {
 	jobID := manager.GetInstance().OptimiseDatabase(ctx)
 	return strconv.Itoa(jobID), nil
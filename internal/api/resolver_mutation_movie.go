@@ -7,7 +7,9 @@ import (
 
 	"github.com/stashapp/stash/internal/static"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/movie"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -50,6 +52,10 @@ func (r *mutationResolver) MovieCreate(ctx context.Context, input MovieCreateInp
 	if err != nil {
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
+	newMovie.ParentID, err = translator.intPtrFromString(input.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("converting parent id: %w", err)
+	}
 
 	// Process the base 64 encoded image string
 	var frontimageData []byte
@@ -79,6 +85,12 @@ func (r *mutationResolver) MovieCreate(ctx context.Context, input MovieCreateInp
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Movie
 
+		if newMovie.ParentID != nil {
+			if err := movie.ValidateParent(ctx, 0, *newMovie.ParentID, qb); err != nil {
+				return err
+			}
+		}
+
 		err = qb.Create(ctx, &newMovie)
 		if err != nil {
 			return err
@@ -135,6 +147,10 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 	if err != nil {
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
+	updatedMovie.ParentID, err = translator.optionalIntFromString(input.ParentID, "parent_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting parent id: %w", err)
+	}
 
 	var frontimageData []byte
 	frontImageIncluded := translator.hasField("front_image")
@@ -155,23 +171,36 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 	}
 
 	// Start the transaction and save the movie
-	var movie *models.Movie
+	var m *models.Movie
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Movie
-		movie, err = qb.UpdatePartial(ctx, movieID, updatedMovie)
+
+		if updatedMovie.ParentID.Ptr() != nil {
+			if err := movie.ValidateParent(ctx, movieID, *updatedMovie.ParentID.Ptr(), qb); err != nil {
+				return err
+			}
+		}
+
+		m, err = qb.UpdatePartial(ctx, movieID, updatedMovie)
 		if err != nil {
 			return err
 		}
 
 		// update image table
 		if frontImageIncluded {
-			if err := qb.UpdateFrontImage(ctx, movie.ID, frontimageData); err != nil {
+			if err := qb.UpdateFrontImage(ctx, m.ID, frontimageData); err != nil {
 				return err
 			}
 		}
 
 		if backImageIncluded {
-			if err := qb.UpdateBackImage(ctx, movie.ID, backimageData); err != nil {
+			if err := qb.UpdateBackImage(ctx, m.ID, backimageData); err != nil {
+				return err
+			}
+		}
+
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, m.ID, *input.CustomFields); err != nil {
 				return err
 			}
 		}
@@ -181,11 +210,17 @@ func (r *mutationResolver) MovieUpdate(ctx context.Context, input MovieUpdateInp
 		return nil, err
 	}
 
-	r.hookExecutor.ExecutePostHooks(ctx, movie.ID, plugin.MovieUpdatePost, input, translator.getFields())
-	return r.getMovie(ctx, movie.ID)
+	r.hookExecutor.ExecutePostHooks(ctx, m.ID, plugin.MovieUpdatePost, input, translator.getFields())
+	return r.getMovie(ctx, m.ID)
 }
 
 func (r *mutationResolver) BulkMovieUpdate(ctx context.Context, input BulkMovieUpdateInput) ([]*models.Movie, error) {
+	setIDs, err := r.getSelectionSetIDs(ctx, input.SelectionSetID, models.FilterModeMovies)
+	if err != nil {
+		return nil, err
+	}
+	input.Ids = sliceutil.AppendUniques(input.Ids, setIDs)
+
 	movieIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, fmt.Errorf("converting ids: %w", err)
@@ -282,3 +317,63 @@ func (r *mutationResolver) MoviesDestroy(ctx context.Context, movieIDs []string)
 
 	return true, nil
 }
+
+func (r *mutationResolver) MovieSetSceneIndex(ctx context.Context, input MovieSetSceneIndexInput) ([]*models.MovieScene, error) {
+	movieID, err := strconv.Atoi(input.MovieID)
+	if err != nil {
+		return nil, fmt.Errorf("converting movie id: %w", err)
+	}
+
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return movie.SetSceneIndex(ctx, r.repository.Movie, movieID, sceneID, input.SceneIndex)
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, movieID, plugin.MovieUpdatePost, input, nil)
+	return r.getMovieSceneIndexes(ctx, movieID)
+}
+
+func (r *mutationResolver) MovieMoveScene(ctx context.Context, input MovieMoveSceneInput) ([]*models.MovieScene, error) {
+	movieID, err := strconv.Atoi(input.MovieID)
+	if err != nil {
+		return nil, fmt.Errorf("converting movie id: %w", err)
+	}
+
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	up := input.Direction == MovieSceneMoveDirectionUp
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return movie.MoveScene(ctx, r.repository.Movie, movieID, sceneID, up)
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, movieID, plugin.MovieUpdatePost, input, nil)
+	return r.getMovieSceneIndexes(ctx, movieID)
+}
+
+func (r *mutationResolver) MovieAutoOrderScenes(ctx context.Context, input MovieAutoOrderScenesInput) ([]*models.MovieScene, error) {
+	movieID, err := strconv.Atoi(input.MovieID)
+	if err != nil {
+		return nil, fmt.Errorf("converting movie id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return movie.AutoOrderScenes(ctx, r.repository.Movie, r.repository.Scene, movieID, input.OrderBy)
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, movieID, plugin.MovieUpdatePost, input, nil)
+	return r.getMovieSceneIndexes(ctx, movieID)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/performer"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -112,17 +113,8 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 	return r.getPerformer(ctx, newPerformer.ID)
 }
 
-func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.PerformerUpdateInput) (*models.Performer, error) {
-	performerID, err := strconv.Atoi(input.ID)
-	if err != nil {
-		return nil, fmt.Errorf("converting id: %w", err)
-	}
-
-	translator := changesetTranslator{
-		inputMap: getUpdateInputMap(ctx),
-	}
-
-	// Populate performer from the input
+func performerPartialFromInput(input models.PerformerUpdateInput, translator changesetTranslator) (models.PerformerPartial, error) {
+	var err error
 	updatedPerformer := models.NewPerformerPartial()
 
 	updatedPerformer.Name = translator.optionalString(input.Name, "name")
@@ -151,11 +143,11 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 
 	updatedPerformer.Birthdate, err = translator.optionalDate(input.Birthdate, "birthdate")
 	if err != nil {
-		return nil, fmt.Errorf("converting birthdate: %w", err)
+		return updatedPerformer, fmt.Errorf("converting birthdate: %w", err)
 	}
 	updatedPerformer.DeathDate, err = translator.optionalDate(input.DeathDate, "death_date")
 	if err != nil {
-		return nil, fmt.Errorf("converting death date: %w", err)
+		return updatedPerformer, fmt.Errorf("converting death date: %w", err)
 	}
 
 	// prefer height_cm over height
@@ -170,7 +162,25 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 
 	updatedPerformer.TagIDs, err = translator.updateIds(input.TagIds, "tag_ids")
 	if err != nil {
-		return nil, fmt.Errorf("converting tag ids: %w", err)
+		return updatedPerformer, fmt.Errorf("converting tag ids: %w", err)
+	}
+
+	return updatedPerformer, nil
+}
+
+func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.PerformerUpdateInput) (*models.Performer, error) {
+	performerID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	updatedPerformer, err := performerPartialFromInput(input, translator)
+	if err != nil {
+		return nil, err
 	}
 
 	var imageData []byte
@@ -202,6 +212,12 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 			}
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, performerID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -212,6 +228,12 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 }
 
 func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input BulkPerformerUpdateInput) ([]*models.Performer, error) {
+	setIDs, err := r.getSelectionSetIDs(ctx, input.SelectionSetID, models.FilterModePerformers)
+	if err != nil {
+		return nil, err
+	}
+	input.Ids = sliceutil.AppendUniques(input.Ids, setIDs)
+
 	performerIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, fmt.Errorf("converting ids: %w", err)
@@ -352,3 +374,119 @@ func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs [
 
 	return true, nil
 }
+
+func (r *mutationResolver) PerformersMerge(ctx context.Context, input PerformersMergeInput) (*models.Performer, error) {
+	source, err := stringslice.StringSliceToIntSlice(input.Source)
+	if err != nil {
+		return nil, fmt.Errorf("converting source ids: %w", err)
+	}
+
+	destination, err := strconv.Atoi(input.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("converting destination id: %w", err)
+	}
+
+	if len(source) == 0 {
+		return nil, nil
+	}
+
+	// values defined in the input override the values of the destination
+	values := models.NewPerformerPartial()
+	var imageData []byte
+	imageIncluded := false
+	var customFields *models.CustomFieldsInput
+
+	if input.Values != nil {
+		translator := changesetTranslator{
+			inputMap: getNamedUpdateInputMap(ctx, "input.values"),
+		}
+
+		values, err = performerPartialFromInput(*input.Values, translator)
+		if err != nil {
+			return nil, err
+		}
+
+		imageIncluded = translator.hasField("image")
+		if input.Values.Image != nil {
+			imageData, err = utils.ProcessImageInput(ctx, *input.Values.Image)
+			if err != nil {
+				return nil, fmt.Errorf("processing image: %w", err)
+			}
+		}
+
+		customFields = input.Values.CustomFields
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Performer
+
+		dest, err := qb.Find(ctx, destination)
+		if err != nil {
+			return err
+		}
+
+		if dest == nil {
+			return fmt.Errorf("performer with id %d not found", destination)
+		}
+
+		sources, err := qb.FindMany(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		if err := dest.LoadAliases(ctx, qb); err != nil {
+			return err
+		}
+		for _, s := range sources {
+			if err := s.LoadAliases(ctx, qb); err != nil {
+				return err
+			}
+		}
+
+		if err := qb.Merge(ctx, source, destination); err != nil {
+			return err
+		}
+
+		// the names and aliases of the sources become aliases of the
+		// destination, unless the aliases are set explicitly
+		if values.Aliases == nil {
+			name := dest.Name
+			if values.Name.Set {
+				name = values.Name.Value
+			}
+
+			values.Aliases = &models.UpdateStrings{
+				Values: performer.MergeAliases(name, dest, sources),
+				Mode:   models.RelationshipUpdateModeSet,
+			}
+		}
+
+		if err := performer.ValidateUpdate(ctx, destination, values, qb); err != nil {
+			return err
+		}
+
+		if _, err := qb.UpdatePartial(ctx, destination, values); err != nil {
+			return err
+		}
+
+		if imageIncluded {
+			if err := qb.UpdateImage(ctx, destination, imageData); err != nil {
+				return err
+			}
+		}
+
+		if customFields != nil {
+			if err := qb.SetCustomFields(ctx, destination, *customFields); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, destination, plugin.PerformerMergePost, input, nil)
+
+	return r.getPerformer(ctx, destination)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// validateRatingCriterionName returns an error if the name is empty or used
+// by a rating criterion other than the one with id.
+func (r *mutationResolver) validateRatingCriterionName(ctx context.Context, name string, id int) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("name must be non-empty")
+	}
+
+	existing, err := r.repository.RatingCriterion.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("rating criterion with name %q already exists", name)
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) RatingCriterionCreate(ctx context.Context, input RatingCriterionCreateInput) (*models.RatingCriterion, error) {
+	newCriterion := models.NewRatingCriterion()
+	newCriterion.Name = strings.TrimSpace(input.Name)
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.validateRatingCriterionName(ctx, newCriterion.Name, 0); err != nil {
+			return err
+		}
+
+		return r.repository.RatingCriterion.Create(ctx, &newCriterion)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newCriterion, nil
+}
+
+func (r *mutationResolver) RatingCriterionUpdate(ctx context.Context, input RatingCriterionUpdateInput) (ret *models.RatingCriterion, err error) {
+	criterionID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	updatedCriterion := models.NewRatingCriterionPartial()
+	if input.Name != nil {
+		updatedCriterion.Name = models.NewOptionalString(strings.TrimSpace(*input.Name))
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.RatingCriterion
+
+		existing, err := qb.Find(ctx, criterionID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("rating criterion with id %d not found", criterionID)
+		}
+
+		if updatedCriterion.Name.Set {
+			if err := r.validateRatingCriterionName(ctx, updatedCriterion.Name.Value, criterionID); err != nil {
+				return err
+			}
+		}
+
+		ret, err = qb.UpdatePartial(ctx, criterionID, updatedCriterion)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) RatingCriterionDestroy(ctx context.Context, id string) (bool, error) {
+	criterionID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.RatingCriterion
+
+		existing, err := qb.Find(ctx, criterionID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("rating criterion with id %d not found", criterionID)
+		}
+
+		// ratings for the criterion are removed by the foreign key
+		return qb.Destroy(ctx, criterionID)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
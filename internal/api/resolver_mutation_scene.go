@@ -7,10 +7,10 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
 	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scene/generate"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 	"github.com/stashapp/stash/pkg/utils"
@@ -47,6 +47,7 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 	newScene.Director = translator.string(input.Director)
 	newScene.Rating = input.Rating100
 	newScene.Organized = translator.bool(input.Organized)
+	newScene.Favorite = translator.bool(input.Favorite)
 	newScene.StashIDs = models.NewRelatedStashIDs(input.StashIds)
 
 	newScene.Date, err = translator.datePtr(input.Date)
@@ -173,6 +174,8 @@ func scenePartialFromInput(input models.SceneUpdateInput, translator changesetTr
 	updatedScene.PlayCount = translator.optionalInt(input.PlayCount, "play_count")
 	updatedScene.PlayDuration = translator.optionalFloat64(input.PlayDuration, "play_duration")
 	updatedScene.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedScene.Favorite = translator.optionalBool(input.Favorite, "favorite")
+	updatedScene.ContentRating = translator.optionalInt(input.ContentRating, "content_rating")
 	updatedScene.StashIDs = translator.updateStashIDs(input.StashIds, "stash_ids")
 
 	var err error
@@ -288,9 +291,89 @@ func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUp
 		return nil, err
 	}
 
+	if input.CustomFields != nil {
+		if err := qb.SetCustomFields(ctx, sceneID, *input.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(input.CriteriaRatings) > 0 {
+		criteriaRatings, err := criteriaRatingsFromInput(input.CriteriaRatings)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.SetCriteriaRatings(ctx, sceneID, criteriaRatings); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.PreviewOptions != nil {
+		if err := scene.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return nil, err
+		}
+
+		var duration float64
+		if f := scene.Files.Primary(); f != nil {
+			duration = f.Duration
+		}
+
+		if err := validateScenePreviewOptions(*input.PreviewOptions, duration); err != nil {
+			return nil, err
+		}
+
+		if err := qb.SetPreviewOptions(ctx, sceneID, *input.PreviewOptions); err != nil {
+			return nil, err
+		}
+	}
+
 	return scene, nil
 }
 
+func criteriaRatingsFromInput(input []models.CriterionRatingInput) (models.CriteriaRatingsInput, error) {
+	var ret models.CriteriaRatingsInput
+	for _, v := range input {
+		criterionID, err := strconv.Atoi(v.CriterionID)
+		if err != nil {
+			return ret, fmt.Errorf("converting criterion id: %w", err)
+		}
+
+		if v.Rating == nil {
+			ret.Remove = append(ret.Remove, criterionID)
+			continue
+		}
+
+		ret.Set = append(ret.Set, models.CriterionRating{
+			CriterionID: criterionID,
+			Rating:      *v.Rating,
+		})
+	}
+
+	return ret, nil
+}
+
+// validateScenePreviewOptions validates the preview options of a scene with
+// the given duration. The excluded durations in seconds are only checked
+// against the duration if it is known.
+func validateScenePreviewOptions(o models.ScenePreviewOptions, duration float64) error {
+	if o.Segments != nil && *o.Segments < 1 {
+		return errors.New("preview segments must be at least 1")
+	}
+	if o.SegmentDuration != nil && *o.SegmentDuration <= 0 {
+		return errors.New("preview segment duration must be positive")
+	}
+
+	var excludeStart, excludeEnd string
+	if o.ExcludeStart != nil {
+		excludeStart = *o.ExcludeStart
+	}
+	if o.ExcludeEnd != nil {
+		excludeEnd = *o.ExcludeEnd
+	}
+
+	return generate.ValidateExcludes(duration, excludeStart, excludeEnd)
+}
+
 func (r *mutationResolver) sceneUpdateCoverImage(ctx context.Context, s *models.Scene, coverImageData []byte) error {
 	if len(coverImageData) > 0 {
 		qb := r.repository.Scene
@@ -305,6 +388,12 @@ func (r *mutationResolver) sceneUpdateCoverImage(ctx context.Context, s *models.
 }
 
 func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneUpdateInput) ([]*models.Scene, error) {
+	setIDs, err := r.getSelectionSetIDs(ctx, input.SelectionSetID, models.FilterModeScenes)
+	if err != nil {
+		return nil, err
+	}
+	input.Ids = sliceutil.AppendUniques(input.Ids, setIDs)
+
 	sceneIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
 	if err != nil {
 		return nil, fmt.Errorf("converting ids: %w", err)
@@ -323,6 +412,8 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 	updatedScene.Director = translator.optionalString(input.Director, "director")
 	updatedScene.Rating = translator.optionalInt(input.Rating100, "rating100")
 	updatedScene.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedScene.Favorite = translator.optionalBool(input.Favorite, "favorite")
+	updatedScene.ContentRating = translator.optionalInt(input.ContentRating, "content_rating")
 
 	updatedScene.Date, err = translator.optionalDate(input.Date, "date")
 	if err != nil {
@@ -399,7 +490,7 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 
 	var s *models.Scene
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.GetInstance().NewFileDeleter(),
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -452,7 +543,7 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
 
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.GetInstance().NewFileDeleter(),
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -582,6 +673,40 @@ func (r *mutationResolver) SceneMerge(ctx context.Context, input SceneMergeInput
 	return ret, nil
 }
 
+func (r *mutationResolver) SceneMergeMultipart(ctx context.Context, input SceneMergeMultipartInput) (*models.Scene, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	if len(sceneIDs) == 0 {
+		return nil, errors.New("scene_ids must not be empty")
+	}
+
+	destID := sceneIDs[0]
+
+	var ret *models.Scene
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.Resolver.sceneService.MergeMultipart(ctx, sceneIDs); err != nil {
+			return err
+		}
+
+		ret, err = r.Resolver.repository.Scene.Find(ctx, destID)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("scene with id %d not found", destID)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *mutationResolver) getSceneMarker(ctx context.Context, id int) (ret *models.SceneMarker, err error) {
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.SceneMarker.Find(ctx, id)
@@ -609,8 +734,16 @@ func (r *mutationResolver) SceneMarkerCreate(ctx context.Context, input SceneMar
 
 	newMarker.Title = input.Title
 	newMarker.Seconds = input.Seconds
+	newMarker.EndSeconds = input.EndSeconds
 	newMarker.PrimaryTagID = primaryTagID
 	newMarker.SceneID = sceneID
+	newMarker.PreviewFormat = input.PreviewFormat
+	newMarker.PreviewDuration = input.PreviewDuration
+	newMarker.PreviewWidth = input.PreviewWidth
+
+	if err := newMarker.Validate(); err != nil {
+		return nil, err
+	}
 
 	tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
 	if err != nil {
@@ -652,6 +785,10 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 
 	updatedMarker.Title = translator.optionalString(input.Title, "title")
 	updatedMarker.Seconds = translator.optionalFloat64(input.Seconds, "seconds")
+	updatedMarker.EndSeconds = translator.optionalFloat64(input.EndSeconds, "end_seconds")
+	updatedMarker.PreviewFormat = translator.optionalString((*string)(input.PreviewFormat), "preview_format")
+	updatedMarker.PreviewDuration = translator.optionalFloat64(input.PreviewDuration, "preview_duration")
+	updatedMarker.PreviewWidth = translator.optionalInt(input.PreviewWidth, "preview_width")
 	updatedMarker.SceneID, err = translator.optionalIntFromString(input.SceneID, "scene_id")
 	if err != nil {
 		return nil, fmt.Errorf("converting scene id: %w", err)
@@ -673,7 +810,7 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 	mgr := manager.GetInstance()
 
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.GetInstance().NewFileDeleter(),
 		FileNamingAlgo: mgr.Config.GetVideoFileNamingAlgorithm(),
 		Paths:          mgr.Paths,
 	}
@@ -697,6 +834,12 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 			return err
 		}
 
+		// the range is validated after the update, since either end may
+		// not have been provided
+		if err := newMarker.Validate(); err != nil {
+			return err
+		}
+
 		existingScene, err := sqb.Find(ctx, existingMarker.SceneID)
 		if err != nil {
 			return err
@@ -705,8 +848,12 @@ func (r *mutationResolver) SceneMarkerUpdate(ctx context.Context, input SceneMar
 			return fmt.Errorf("scene with id %d not found", existingMarker.SceneID)
 		}
 
+		// the preview covers at most the marker range
+		endChanged := (existingMarker.EndSeconds == nil) != (newMarker.EndSeconds == nil) ||
+			(existingMarker.EndSeconds != nil && *existingMarker.EndSeconds != *newMarker.EndSeconds)
+
 		// remove the marker preview if the scene changed or if the timestamp was changed
-		if existingMarker.SceneID != newMarker.SceneID || existingMarker.Seconds != newMarker.Seconds {
+		if existingMarker.SceneID != newMarker.SceneID || existingMarker.Seconds != newMarker.Seconds || endChanged {
 			seconds := int(existingMarker.Seconds)
 			if err := fileDeleter.MarkMarkerFiles(existingScene, seconds); err != nil {
 				return err
@@ -744,7 +891,7 @@ func (r *mutationResolver) SceneMarkerDestroy(ctx context.Context, id string) (b
 	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
 
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.GetInstance().NewFileDeleter(),
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -786,6 +933,59 @@ func (r *mutationResolver) SceneMarkerDestroy(ctx context.Context, id string) (b
 	return true, nil
 }
 
+func (r *mutationResolver) SceneMarkersImport(ctx context.Context, input SceneMarkersImportInput) ([]*models.SceneMarker, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	primaryTagID, err := strconv.Atoi(input.PrimaryTagID)
+	if err != nil {
+		return nil, fmt.Errorf("converting primary tag id: %w", err)
+	}
+
+	tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting tag ids: %w", err)
+	}
+
+	chapters, err := scene.ParseChapters(input.Format, input.Data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing chapters: %w", err)
+	}
+
+	var ret []*models.SceneMarker
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		s, err := r.repository.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		var duration float64
+		if f := s.Files.Primary(); f != nil {
+			duration = f.Duration
+		}
+
+		ret, err = scene.ImportMarkerChapters(ctx, r.repository.SceneMarker, s, chapters, duration, primaryTagID, tagIDs)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, m := range ret {
+		r.hookExecutor.ExecutePostHooks(ctx, m.ID, plugin.SceneMarkerCreatePost, input, nil)
+	}
+
+	return ret, nil
+}
+
 func (r *mutationResolver) SceneSaveActivity(ctx context.Context, id string, resumeTime *float64, playDuration *float64) (ret bool, err error) {
 	sceneID, err := strconv.Atoi(id)
 	if err != nil {
@@ -885,3 +1085,50 @@ func (r *mutationResolver) SceneGenerateScreenshot(ctx context.Context, id strin
 
 	return "todo", nil
 }
+
+func (r *mutationResolver) SceneExtractClip(ctx context.Context, input SceneExtractClipInput) (*SceneExtractClipResult, error) {
+	sceneID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	var scene *models.Scene
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		scene, err = r.repository.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if scene == nil || !models.ContentRatingAccessible(ctx, scene.ContentRating) {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		return scene.LoadFiles(ctx, r.repository.Scene)
+	}); err != nil {
+		return nil, err
+	}
+
+	options := manager.ExtractClipOptions{
+		Start:      input.Start,
+		End:        input.End,
+		VideoCodec: input.VideoCodec,
+	}
+
+	addToLibrary := input.AddToLibrary != nil && *input.AddToLibrary
+
+	jobID, downloadHash, fileName, err := manager.GetInstance().ExtractClip(ctx, scene, options, addToLibrary)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &SceneExtractClipResult{
+		JobID: strconv.Itoa(jobID),
+	}
+
+	if downloadHash != "" {
+		baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+		u := baseURL + "/downloads/" + downloadHash + "/" + fileName
+		ret.DownloadURL = &u
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+func (r *mutationResolver) getSceneRelationship(ctx context.Context, id int) (ret *models.SceneRelationship, err error) {
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SceneRelationship.Find(ctx, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) SceneRelationshipCreate(ctx context.Context, input SceneRelationshipCreateInput) (*models.SceneRelationship, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	relatedSceneID, err := strconv.Atoi(input.RelatedSceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting related scene id: %w", err)
+	}
+
+	// Populate a new scene relationship from the input
+	newRelationship := models.NewSceneRelationship()
+
+	newRelationship.SceneID = sceneID
+	newRelationship.RelatedSceneID = relatedSceneID
+	newRelationship.Type = input.Type
+
+	// Start the transaction and save the scene relationship
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := scene.ValidateRelationship(ctx, sceneID, relatedSceneID, r.repository.Scene); err != nil {
+			return err
+		}
+
+		return r.repository.SceneRelationship.Create(ctx, &newRelationship)
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, newRelationship.ID, plugin.SceneRelationshipCreatePost, input, nil)
+	return r.getSceneRelationship(ctx, newRelationship.ID)
+}
+
+func (r *mutationResolver) SceneRelationshipUpdate(ctx context.Context, input SceneRelationshipUpdateInput) (*models.SceneRelationship, error) {
+	relationshipID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	// Populate scene relationship from the input
+	updatedRelationship := models.NewSceneRelationshipPartial()
+
+	updatedRelationship.SceneID, err = translator.optionalIntFromString(input.SceneID, "scene_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+	updatedRelationship.RelatedSceneID, err = translator.optionalIntFromString(input.RelatedSceneID, "related_scene_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting related scene id: %w", err)
+	}
+	if input.Type != nil {
+		updatedRelationship.Type = models.NewOptionalString(input.Type.String())
+	}
+
+	// Start the transaction and save the scene relationship
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SceneRelationship
+
+		existing, err := qb.Find(ctx, relationshipID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return fmt.Errorf("scene relationship with id %d not found", relationshipID)
+		}
+
+		sceneID := existing.SceneID
+		relatedSceneID := existing.RelatedSceneID
+
+		if updatedRelationship.SceneID.Set {
+			sceneID = updatedRelationship.SceneID.Value
+		}
+		if updatedRelationship.RelatedSceneID.Set {
+			relatedSceneID = updatedRelationship.RelatedSceneID.Value
+		}
+
+		if err := scene.ValidateRelationship(ctx, sceneID, relatedSceneID, r.repository.Scene); err != nil {
+			return err
+		}
+
+		_, err = qb.UpdatePartial(ctx, relationshipID, updatedRelationship)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, relationshipID, plugin.SceneRelationshipUpdatePost, input, translator.getFields())
+	return r.getSceneRelationship(ctx, relationshipID)
+}
+
+func (r *mutationResolver) SceneRelationshipDestroy(ctx context.Context, id string) (bool, error) {
+	relationshipID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SceneRelationship
+
+		relationship, err := qb.Find(ctx, relationshipID)
+		if err != nil {
+			return err
+		}
+
+		if relationship == nil {
+			return fmt.Errorf("scene relationship with id %d not found", relationshipID)
+		}
+
+		return qb.Destroy(ctx, relationshipID)
+	}); err != nil {
+		return false, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, relationshipID, plugin.SceneRelationshipDestroyPost, id, nil)
+
+	return true, nil
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) SelectionSetCreate(ctx context.Context, input SelectionSetCreateInput) (*models.SelectionSet, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, errors.New("name must be non-empty")
+	}
+
+	ids, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return nil, fmt.Errorf("converting ids: %w", err)
+	}
+
+	newSet := models.NewSelectionSet()
+	newSet.Name = input.Name
+	newSet.Mode = input.Mode
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if input.FindFilter != nil || input.ObjectFilter != nil {
+			filterIDs, err := r.selectionSetFilterIDs(ctx, input.Mode, input.FindFilter, input.ObjectFilter)
+			if err != nil {
+				return err
+			}
+
+			ids = sliceutil.AppendUniques(ids, filterIDs)
+		}
+
+		qb := r.repository.SelectionSet
+		if err := qb.Create(ctx, &newSet); err != nil {
+			return err
+		}
+
+		return qb.UpdateObjectIDs(ctx, newSet.ID, ids, models.RelationshipUpdateModeSet)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newSet, nil
+}
+
+func (r *mutationResolver) SelectionSetUpdate(ctx context.Context, input SelectionSetUpdateInput) (ret *models.SelectionSet, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return nil, errors.New("name must be non-empty")
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SelectionSet
+
+		ret, err = qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("selection set with id %d not found", id)
+		}
+
+		if input.Name != nil {
+			ret.Name = *input.Name
+		}
+		ret.UpdatedAt = time.Now()
+
+		if err := qb.Update(ctx, ret); err != nil {
+			return err
+		}
+
+		if input.Ids != nil {
+			ids, err := stringslice.StringSliceToIntSlice(input.Ids.Ids)
+			if err != nil {
+				return fmt.Errorf("converting ids: %w", err)
+			}
+
+			if err := qb.UpdateObjectIDs(ctx, id, ids, input.Ids.Mode); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) SelectionSetDestroy(ctx context.Context, input SelectionSetDestroyInput) (bool, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.SelectionSet.Destroy(ctx, id)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getSelectionSet returns the selection set with the provided id and its
+// object ids.
+func (r *Resolver) getSelectionSet(ctx context.Context, id string) (ret *models.SelectionSet, ids []string, err error) {
+	setID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("converting selection set id: %w", err)
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SelectionSet
+
+		ret, err = qb.Find(ctx, setID)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("selection set with id %d not found", setID)
+		}
+
+		objectIDs, err := qb.GetObjectIDs(ctx, setID)
+		if err != nil {
+			return err
+		}
+
+		ids = intslice.IntSliceToStringSlice(objectIDs)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return ret, ids, nil
+}
+
+// getSelectionSetIDs returns the object ids of the selection set with the
+// provided id. Returns nil if id is nil. Returns an error if the selection set
+// does not contain objects of the provided mode.
+func (r *Resolver) getSelectionSetIDs(ctx context.Context, id *string, mode models.FilterMode) ([]string, error) {
+	if id == nil {
+		return nil, nil
+	}
+
+	s, ids, err := r.getSelectionSet(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Mode != mode {
+		return nil, fmt.Errorf("selection set %q contains %s, not %s", s.Name, s.Mode, mode)
+	}
+
+	return ids, nil
+}
+
+// selectionSetFilterIDs returns the ids of the objects matching the provided
+// filters. objectFilter is decoded into the filter type of mode. All matching
+// objects are returned if findFilter does not specify a page size.
+func (r *Resolver) selectionSetFilterIDs(ctx context.Context, mode models.FilterMode, findFilter *models.FindFilterType, objectFilter map[string]interface{}) ([]int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+	if findFilter.PerPage == nil {
+		all := -1
+		findFilter.PerPage = &all
+	}
+
+	decode := func(v interface{}) error {
+		if objectFilter == nil {
+			return nil
+		}
+
+		encoded, err := json.Marshal(objectFilter)
+		if err != nil {
+			return fmt.Errorf("encoding object filter: %w", err)
+		}
+
+		if err := json.Unmarshal(encoded, v); err != nil {
+			return fmt.Errorf("decoding object filter: %w", err)
+		}
+
+		return nil
+	}
+
+	switch mode {
+	case models.FilterModeScenes:
+		var f models.SceneFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		result, err := r.repository.Scene.Query(ctx, scene.QueryOptions(&f, findFilter, false))
+		if err != nil {
+			return nil, err
+		}
+		return result.IDs, nil
+	case models.FilterModeImages:
+		var f models.ImageFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		result, err := r.repository.Image.Query(ctx, image.QueryOptions(&f, findFilter, false))
+		if err != nil {
+			return nil, err
+		}
+		return result.IDs, nil
+	case models.FilterModeGalleries:
+		var f models.GalleryFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		galleries, _, err := r.repository.Gallery.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(galleries, func(o *models.Gallery) int { return o.ID }), nil
+	case models.FilterModePerformers:
+		var f models.PerformerFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		performers, _, err := r.repository.Performer.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(performers, func(o *models.Performer) int { return o.ID }), nil
+	case models.FilterModeStudios:
+		var f models.StudioFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		studios, _, err := r.repository.Studio.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(studios, func(o *models.Studio) int { return o.ID }), nil
+	case models.FilterModeMovies:
+		var f models.MovieFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		movies, _, err := r.repository.Movie.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(movies, func(o *models.Movie) int { return o.ID }), nil
+	case models.FilterModeTags:
+		var f models.TagFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		tags, _, err := r.repository.Tag.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(tags, func(o *models.Tag) int { return o.ID }), nil
+	case models.FilterModeSceneMarkers:
+		var f models.SceneMarkerFilterType
+		if err := decode(&f); err != nil {
+			return nil, err
+		}
+		markers, _, err := r.repository.SceneMarker.Query(ctx, &f, findFilter)
+		if err != nil {
+			return nil, err
+		}
+		return sliceutil.Map(markers, func(o *models.SceneMarker) int { return o.ID }), nil
+	}
+
+	return nil, fmt.Errorf("unsupported selection set mode: %s", mode)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func (r *mutationResolver) SubmitStashBoxFingerprints(ctx context.Context, input StashBoxFingerprintSubmissionInput) (bool, error) {
@@ -107,3 +109,33 @@ func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, inp
 
 	return res, err
 }
+
+func (r *mutationResolver) ExportStashBoxDrafts(ctx context.Context, input StashBoxDraftExportInput) (*string, error) {
+	boxes := config.GetInstance().GetStashBoxes()
+
+	if input.StashBoxIndex < 0 || input.StashBoxIndex >= len(boxes) {
+		return nil, fmt.Errorf("invalid stash_box_index %d", input.StashBoxIndex)
+	}
+
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	performerIDs, err := stringslice.StringSliceToIntSlice(input.PerformerIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting performer ids: %w", err)
+	}
+
+	t := manager.CreateStashBoxDraftExportTask(boxes[input.StashBoxIndex], sceneIDs, performerIDs)
+	if err := t.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+
+	// generate timestamp
+	suffix := time.Now().Format("20060102-150405")
+	ret := baseURL + "/downloads/" + t.DownloadHash + "/drafts" + suffix + ".zip"
+	return &ret, nil
+}
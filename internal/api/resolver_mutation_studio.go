@@ -148,6 +148,12 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 			}
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, studioID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -199,3 +205,40 @@ func (r *mutationResolver) StudiosDestroy(ctx context.Context, studioIDs []strin
 
 	return true, nil
 }
+
+func (r *mutationResolver) StudiosMerge(ctx context.Context, input StudiosMergeInput) (*models.Studio, error) {
+	source, err := stringslice.StringSliceToIntSlice(input.Source)
+	if err != nil {
+		return nil, fmt.Errorf("converting source ids: %w", err)
+	}
+
+	destination, err := strconv.Atoi(input.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("converting destination id: %w", err)
+	}
+
+	if len(source) == 0 {
+		return nil, nil
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Studio
+
+		s, err := qb.Find(ctx, destination)
+		if err != nil {
+			return err
+		}
+
+		if s == nil {
+			return fmt.Errorf("studio with id %d not found", destination)
+		}
+
+		return qb.Merge(ctx, source, destination)
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, destination, plugin.StudioMergePost, input, nil)
+
+	return r.getStudio(ctx, destination)
+}
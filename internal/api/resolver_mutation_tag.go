@@ -221,6 +221,12 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 			}
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, tagID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		// FIXME: This should be called before any changes are made, but
 		// requires a rewrite of ValidateHierarchy.
 		if parentIDs != nil || childIDs != nil {
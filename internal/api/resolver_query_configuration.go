@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
@@ -78,55 +79,97 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 	maxStreamingTranscodeSize := config.GetMaxStreamingTranscodeSize()
 
 	customPerformerImageLocation := config.GetCustomPerformerImageLocation()
+	storageAlertWebhook := config.GetStorageAlertWebhook()
+	quietHours := config.GetQuietHours()
 
 	return &ConfigGeneralResult{
-		Stashes:                       config.GetStashPaths(),
-		DatabasePath:                  config.GetDatabasePath(),
-		BackupDirectoryPath:           config.GetBackupDirectoryPath(),
-		GeneratedPath:                 config.GetGeneratedPath(),
-		MetadataPath:                  config.GetMetadataPath(),
-		ConfigFilePath:                config.GetConfigFile(),
-		ScrapersPath:                  config.GetScrapersPath(),
-		CachePath:                     config.GetCachePath(),
-		BlobsPath:                     config.GetBlobsPath(),
-		BlobsStorage:                  config.GetBlobsStorage(),
-		CalculateMd5:                  config.IsCalculateMD5(),
-		VideoFileNamingAlgorithm:      config.GetVideoFileNamingAlgorithm(),
-		ParallelTasks:                 config.GetParallelTasks(),
-		PreviewAudio:                  config.GetPreviewAudio(),
-		PreviewSegments:               config.GetPreviewSegments(),
-		PreviewSegmentDuration:        config.GetPreviewSegmentDuration(),
-		PreviewExcludeStart:           config.GetPreviewExcludeStart(),
-		PreviewExcludeEnd:             config.GetPreviewExcludeEnd(),
-		PreviewPreset:                 config.GetPreviewPreset(),
-		TranscodeHardwareAcceleration: config.GetTranscodeHardwareAcceleration(),
-		MaxTranscodeSize:              &maxTranscodeSize,
-		MaxStreamingTranscodeSize:     &maxStreamingTranscodeSize,
-		WriteImageThumbnails:          config.IsWriteImageThumbnails(),
-		CreateImageClipsFromVideos:    config.IsCreateImageClipsFromVideos(),
-		GalleryCoverRegex:             config.GetGalleryCoverRegex(),
-		APIKey:                        config.GetAPIKey(),
-		Username:                      config.GetUsername(),
-		Password:                      config.GetPasswordHash(),
-		MaxSessionAge:                 config.GetMaxSessionAge(),
-		LogFile:                       &logFile,
-		LogOut:                        config.GetLogOut(),
-		LogLevel:                      config.GetLogLevel(),
-		LogAccess:                     config.GetLogAccess(),
-		VideoExtensions:               config.GetVideoExtensions(),
-		ImageExtensions:               config.GetImageExtensions(),
-		GalleryExtensions:             config.GetGalleryExtensions(),
-		CreateGalleriesFromFolders:    config.GetCreateGalleriesFromFolders(),
-		Excludes:                      config.GetExcludes(),
-		ImageExcludes:                 config.GetImageExcludes(),
-		CustomPerformerImageLocation:  &customPerformerImageLocation,
-		StashBoxes:                    config.GetStashBoxes(),
-		PythonPath:                    config.GetPythonPath(),
-		TranscodeInputArgs:            config.GetTranscodeInputArgs(),
-		TranscodeOutputArgs:           config.GetTranscodeOutputArgs(),
-		LiveTranscodeInputArgs:        config.GetLiveTranscodeInputArgs(),
-		LiveTranscodeOutputArgs:       config.GetLiveTranscodeOutputArgs(),
-		DrawFunscriptHeatmapRange:     config.GetDrawFunscriptHeatmapRange(),
+		Stashes:                             config.GetStashPaths(),
+		Libraries:                           config.GetLibraries(),
+		ScheduledTasks:                      config.GetScheduledTasks(),
+		ObjectStorage:                       config.GetObjectStorage().WithoutSecretKeys(),
+		NetworkShares:                       config.GetNetworkShares().WithoutPasswords(),
+		DatabasePath:                        config.GetDatabasePath(),
+		BackupDirectoryPath:                 config.GetBackupDirectoryPath(),
+		TrashPath:                           config.GetTrashPath(),
+		TrashRetentionDays:                  config.GetTrashRetentionDays(),
+		GeneratedRetentionDays:              config.GetGeneratedRetentionDays(),
+		QuarantineDays:                      config.GetQuarantineDays(),
+		MinFreeSpaceGb:                      config.GetMinFreeSpaceGB(),
+		StorageAlertWebhook:                 &storageAlertWebhook,
+		GeneratedPath:                       config.GetGeneratedPath(),
+		MetadataPath:                        config.GetMetadataPath(),
+		ConfigFilePath:                      config.GetConfigFile(),
+		ScrapersPath:                        config.GetScrapersPath(),
+		CachePath:                           config.GetCachePath(),
+		BlobsPath:                           config.GetBlobsPath(),
+		BlobsStorage:                        config.GetBlobsStorage(),
+		CalculateMd5:                        config.IsCalculateMD5(),
+		FingerprintAlgorithms:               config.GetFingerprintAlgorithms(),
+		VideoFileNamingAlgorithm:            config.GetVideoFileNamingAlgorithm(),
+		ParallelTasks:                       config.GetParallelTasks(),
+		ScanWalkParallelTasks:               config.GetScanWalkParallelTasks(),
+		ImageThumbnailBackend:               config.GetImageThumbnailBackend(),
+		ImageThumbnailParallelTasks:         config.GetImageThumbnailParallelTasks(),
+		BestFrameCovers:                     config.GetBestFrameCovers(),
+		PreviewAudio:                        config.GetPreviewAudio(),
+		PreviewSegments:                     config.GetPreviewSegments(),
+		PreviewSegmentDuration:              config.GetPreviewSegmentDuration(),
+		PreviewExcludeStart:                 config.GetPreviewExcludeStart(),
+		PreviewExcludeEnd:                   config.GetPreviewExcludeEnd(),
+		PreviewPreset:                       config.GetPreviewPreset(),
+		PreviewImageFormat:                  config.GetPreviewImageFormat(),
+		PreviewVideoCodec:                   config.GetPreviewVideoCodec(),
+		TranscodeHardwareAcceleration:       config.GetTranscodeHardwareAcceleration(),
+		TranscodeHardwareAccelerationCodec:  config.GetTranscodeHardwareAccelerationCodec(),
+		TranscodeHardwareAccelerationDevice: config.GetTranscodeHardwareAccelerationDevice(),
+		StreamCacheSizeMb:                   config.GetStreamCacheSizeMB(),
+		MaxTranscodeSize:                    &maxTranscodeSize,
+		MaxStreamingTranscodeSize:           &maxStreamingTranscodeSize,
+		WriteImageThumbnails:                config.IsWriteImageThumbnails(),
+		CreateImageClipsFromVideos:          config.IsCreateImageClipsFromVideos(),
+		WatchLibrary:                        config.IsWatchLibrary(),
+		GalleryCoverRegex:                   config.GetGalleryCoverRegex(),
+		APIKey:                              config.GetAPIKey(),
+		Username:                            config.GetUsername(),
+		Password:                            config.GetPasswordHash(),
+		MaxSessionAge:                       config.GetMaxSessionAge(),
+		LogFile:                             &logFile,
+		LogOut:                              config.GetLogOut(),
+		LogLevel:                            config.GetLogLevel(),
+		LogAccess:                           config.GetLogAccess(),
+		VideoExtensions:                     config.GetVideoExtensions(),
+		ImageExtensions:                     config.GetImageExtensions(),
+		AudioExtensions:                     config.GetAudioExtensions(),
+		GalleryExtensions:                   config.GetGalleryExtensions(),
+		DocumentExtensions:                  config.GetDocumentExtensions(),
+		SidecarFormats:                      config.GetSidecarFormats(),
+		VerifyFilesInterval:                 config.GetVerifyFilesInterval(),
+		VerifyFilesSampleSize:               config.GetVerifyFilesSampleSize(),
+		CreateGalleriesFromFolders:          config.GetCreateGalleriesFromFolders(),
+		CaseInsensitivePaths:                config.IsCaseInsensitivePaths(),
+		Excludes:                            config.GetExcludes(),
+		ImageExcludes:                       config.GetImageExcludes(),
+		CustomPerformerImageLocation:        &customPerformerImageLocation,
+		StashBoxes:                          config.GetStashBoxes(),
+		ContentRatingTiers:                  config.GetContentRatingTierLevels(),
+		PythonPath:                          config.GetPythonPath(),
+		ExportFilenameTemplate:              config.GetExportFilenameTemplate(),
+		ExportMaxThroughputMb:               config.GetExportMaxThroughputMB(),
+		ExportMaxIops:                       config.GetExportMaxIOPS(),
+		APIKeyRequestQuota:                  config.GetAPIKeyRequestQuota(),
+		APIKeyMutationQuota:                 config.GetAPIKeyMutationQuota(),
+		APIKeyStreamQuotaGb:                 config.GetAPIKeyStreamQuotaGB(),
+		QuietHours:                          &quietHours,
+		TranscodeInputArgs:                  config.GetTranscodeInputArgs(),
+		TranscodeOutputArgs:                 config.GetTranscodeOutputArgs(),
+		LiveTranscodeInputArgs:              config.GetLiveTranscodeInputArgs(),
+		LiveTranscodeOutputArgs:             config.GetLiveTranscodeOutputArgs(),
+		LiveTranscodeLoudnorm:               config.GetLiveTranscodeLoudnorm(),
+		LiveTranscodeLoudnormTarget:         config.GetLiveTranscodeLoudnormTarget(),
+		HdrToneMapping:                      config.GetHDRToneMapping(),
+		FfmpegLiveCodecArgs:                 makeFFMpegCodecArgsResult(config.GetFFMpegLiveCodecArgs(), ffmpeg.DefaultCodecArgs),
+		FfmpegGenerateCodecArgs:             makeFFMpegCodecArgsResult(config.GetFFMpegGenerateCodecArgs(), ffmpeg.DefaultGenerateCodecArgs),
+		DrawFunscriptHeatmapRange:           config.GetDrawFunscriptHeatmapRange(),
 	}
 }
 
@@ -269,3 +312,26 @@ func (r *queryResolver) ValidateStashBoxCredentials(ctx context.Context, input c
 
 	return &result, nil
 }
+
+// makeFFMpegCodecArgsResult returns the effective encoding options of each
+// codec that can be configured. Codecs without configured options return the
+// built-in options returned by def.
+func makeFFMpegCodecArgsResult(configured []*config.FFMpegCodecArgs, def func(ffmpeg.VideoCodec) ffmpeg.Args) []*FFMpegCodecArgs {
+	ret := make([]*FFMpegCodecArgs, len(ffmpeg.TemplateCodecs))
+	for i, codec := range ffmpeg.TemplateCodecs {
+		ret[i] = &FFMpegCodecArgs{
+			Codec:   string(codec),
+			Args:    append([]string{}, def(codec)...),
+			BuiltIn: true,
+		}
+
+		for _, c := range configured {
+			if c.Codec == string(codec) {
+				ret[i].Args = c.Args
+				ret[i].BuiltIn = false
+			}
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+func (r *queryResolver) FindAudio(ctx context.Context, id string) (ret *models.Audio, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Audio.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindAudios(ctx context.Context, audioFilter *models.AudioFilterType, filter *models.FindFilterType) (ret *FindAudiosResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		fields := graphql.CollectAllFields(ctx)
+
+		result, err := r.repository.Audio.Query(ctx, models.AudioQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: filter,
+				Count:      sliceutil.Contains(fields, "count"),
+			},
+			AudioFilter:   audioFilter,
+			TotalDuration: sliceutil.Contains(fields, "duration"),
+			TotalSize:     sliceutil.Contains(fields, "filesize"),
+		})
+		if err != nil {
+			return err
+		}
+
+		audios, err := result.Resolve(ctx)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindAudiosResultType{
+			Count:    result.Count,
+			Audios:   audios,
+			Duration: result.TotalDuration,
+			Filesize: result.TotalSize,
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
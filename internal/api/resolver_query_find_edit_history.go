@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindEditHistory(ctx context.Context, objectType models.EditObjectType, objectID string) (ret []*models.EditHistory, err error) {
+	idInt, err := strconv.Atoi(objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.EditHistory.FindByObject(ctx, objectType, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
@@ -14,7 +14,14 @@ func (r *queryResolver) FindPerformer(ctx context.Context, id string) (ret *mode
 	}
 
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
-		ret, err = r.repository.Performer.Find(ctx, idInt)
+		qb := r.repository.Performer
+		ret, err = qb.Find(ctx, idInt)
+		if err != nil || ret != nil {
+			return err
+		}
+
+		// redirect to the performer that it was merged into
+		ret, err = qb.FindByMergedID(ctx, idInt)
 		return err
 	}); err != nil {
 		return nil, err
@@ -66,6 +66,24 @@ func (r *queryResolver) FindSceneByHash(ctx context.Context, input SceneHashInpu
 			}
 		}
 
+		if scene == nil && len(input.Fingerprints) > 0 {
+			fp := make([]models.Fingerprint, len(input.Fingerprints))
+			for i, v := range input.Fingerprints {
+				fp[i] = models.Fingerprint{
+					Type:        v.Type,
+					Fingerprint: v.Value,
+				}
+			}
+
+			scenes, err := qb.FindByFingerprints(ctx, fp)
+			if err != nil {
+				return err
+			}
+			if len(scenes) > 0 {
+				scene = scenes[0]
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -214,7 +232,7 @@ func (r *queryResolver) ParseSceneFilenames(ctx context.Context, filter *models.
 	return ret, nil
 }
 
-func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64) (ret [][]*models.Scene, err error) {
+func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64, excludeRelated *bool) (ret [][]*models.Scene, err error) {
 	dist := 0
 	durDiff := -1.
 	if distance != nil {
@@ -225,6 +243,24 @@ func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int,
 	}
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.Scene.FindDuplicates(ctx, dist, durDiff)
+		if err != nil {
+			return err
+		}
+
+		if excludeRelated == nil || *excludeRelated {
+			ret, err = scene.ExcludeRelated(ctx, r.repository.SceneRelationship, ret)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindMultipartScenes(ctx context.Context, paths []string) (ret []*scene.MultipartGroup, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = scene.FindMultipart(ctx, r.repository.Scene, r.repository.File, paths)
 		return err
 	}); err != nil {
 		return nil, err
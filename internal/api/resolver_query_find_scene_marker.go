@@ -2,8 +2,11 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 )
 
 func (r *queryResolver) FindSceneMarkers(ctx context.Context, sceneMarkerFilter *models.SceneMarkerFilterType, filter *models.FindFilterType) (ret *FindSceneMarkersResultType, err error) {
@@ -35,3 +38,41 @@ func (r *queryResolver) AllSceneMarkers(ctx context.Context) (ret []*models.Scen
 
 	return ret, nil
 }
+
+func (r *queryResolver) SceneMarkersExport(ctx context.Context, sceneID string, format models.MarkerChapterFormat) (ret string, err error) {
+	id, err := strconv.Atoi(sceneID)
+	if err != nil {
+		return "", fmt.Errorf("converting scene id: %w", err)
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		s, err := r.repository.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", id)
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		var duration float64
+		if f := s.Files.Primary(); f != nil {
+			duration = f.Duration
+		}
+
+		chapters, err := scene.GetMarkerChapters(ctx, r.repository.SceneMarker, r.repository.Tag, s)
+		if err != nil {
+			return err
+		}
+
+		ret, err = scene.FormatChapters(format, chapters, duration)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindSelectionSet(ctx context.Context, id string) (ret *models.SelectionSet, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SelectionSet.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) FindSelectionSets(ctx context.Context, mode *models.FilterMode) (ret []*models.SelectionSet, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		if mode != nil {
+			ret, err = r.repository.SelectionSet.FindByMode(ctx, *mode)
+		} else {
+			ret, err = r.repository.SelectionSet.All(ctx)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
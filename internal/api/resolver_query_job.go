@@ -8,6 +8,11 @@ import (
 	"github.com/stashapp/stash/pkg/job"
 )
 
+func (r *queryResolver) QuietHoursStatus(ctx context.Context) (*manager.QuietHoursStatus, error) {
+	ret := manager.GetInstance().GetQuietHoursStatus()
+	return &ret, nil
+}
+
 func (r *queryResolver) JobQueue(ctx context.Context) ([]*Job, error) {
 	queue := manager.GetInstance().JobManager.GetQueue()
 
@@ -34,13 +39,14 @@ func (r *queryResolver) FindJob(ctx context.Context, input FindJobInput) (*Job,
 
 func jobToJobModel(j job.Job) *Job {
 	ret := &Job{
-		ID:          strconv.Itoa(j.ID),
-		Status:      JobStatus(j.Status),
-		Description: j.Description,
-		SubTasks:    j.Details,
-		StartTime:   j.StartTime,
-		EndTime:     j.EndTime,
-		AddTime:     j.AddTime,
+		ID:               strconv.Itoa(j.ID),
+		Status:           JobStatus(j.Status),
+		Description:      j.Description,
+		SubTasks:         j.Details,
+		StartTime:        j.StartTime,
+		EndTime:          j.EndTime,
+		AddTime:          j.AddTime,
+		EstimatedEndTime: j.EstimatedEndTime,
 	}
 
 	if j.Progress != -1 {
@@ -3,9 +3,69 @@ package api
 import (
 	"context"
 
+	"github.com/99designs/gqlgen/graphql"
+
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
 )
 
 func (r *queryResolver) SystemStatus(ctx context.Context) (*manager.SystemStatus, error) {
 	return manager.GetInstance().GetSystemStatus(), nil
 }
+
+func (r *queryResolver) OrphanedRows(ctx context.Context) (ret []*sqlite.OrphanedRows, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		rows, err := manager.GetInstance().Database.FindOrphanedRows(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := range rows {
+			ret = append(ret, &rows[i])
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) ImportConflicts(ctx context.Context) ([]*manager.ImportConflict, error) {
+	return manager.GetInstance().GetImportConflicts(), nil
+}
+
+func (r *queryResolver) ExportArchiveManifest(ctx context.Context, file graphql.Upload) (*manager.ExportArchiveManifest, error) {
+	return manager.ReadExportArchiveManifest(ctx, file)
+}
+
+func (r *queryResolver) Trash(ctx context.Context) ([]*file.TrashEntry, error) {
+	trash := manager.GetInstance().Trash()
+	if trash == nil {
+		return nil, nil
+	}
+
+	return trash.List()
+}
+
+func (r *queryResolver) FailedFileVerifications(ctx context.Context) (ret []*models.FileVerification, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		verifications, err := r.repository.File.FindFailedVerifications(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := range verifications {
+			ret = append(ret, &verifications[i])
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+)
+
+func (r *queryResolver) APIKeyUsage(ctx context.Context) ([]*APIKeyUsage, error) {
+	requestQuota := r.usageTracker.config.GetAPIKeyRequestQuota()
+	mutationQuota := r.usageTracker.config.GetAPIKeyMutationQuota()
+	streamQuota := r.usageTracker.streamQuota()
+
+	var ret []*APIKeyUsage
+	for _, u := range r.usageTracker.all() {
+		v := &APIKeyUsage{
+			KeyID:         u.KeyID,
+			Date:          u.Date,
+			Requests:      u.Requests,
+			Mutations:     u.Mutations,
+			BytesStreamed: u.BytesStreamed,
+		}
+
+		if requestQuota > 0 {
+			v.RequestQuota = &requestQuota
+		}
+		if mutationQuota > 0 {
+			v.MutationQuota = &mutationQuota
+		}
+		if streamQuota > 0 {
+			v.StreamQuota = &streamQuota
+		}
+
+		ret = append(ret, v)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type audioRoutes struct {
+	routes
+	audioFinder models.AudioGetter
+	fileGetter  models.FileGetter
+}
+
+func getAudioRoutes(repo models.Repository) chi.Router {
+	return audioRoutes{
+		routes:      routes{txnManager: repo.TxnManager},
+		audioFinder: repo.Audio,
+		fileGetter:  repo.File,
+	}.Routes()
+}
+
+func (rs audioRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{audioId}", func(r chi.Router) {
+		r.Use(rs.AudioCtx)
+
+		r.With(streamUsageHandler).Get("/stream", rs.Stream)
+	})
+
+	return r
+}
+
+func (rs audioRoutes) Stream(w http.ResponseWriter, r *http.Request) {
+	a := r.Context().Value(audioKey).(*models.Audio)
+
+	f := a.Files.Primary()
+	if f == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err := f.Base().Serve(manager.GetInstance().FS, w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (rs audioRoutes) AudioCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		audioID, err := strconv.Atoi(chi.URLParam(r, "audioId"))
+		if err != nil {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		var audio *models.Audio
+		_ = rs.withReadTxn(r, func(ctx context.Context) error {
+			audio, _ = rs.audioFinder.Find(ctx, audioID)
+
+			if audio != nil {
+				if err := audio.LoadPrimaryFile(ctx, rs.fileGetter); err != nil {
+					if !errors.Is(err, context.Canceled) {
+						logger.Errorf("error loading primary file for audio %d: %v", audioID, err)
+					}
+					// set audio to nil so that it doesn't try to use the primary file
+					audio = nil
+				}
+			}
+
+			return nil
+		})
+		if audio == nil {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), audioKey, audio)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
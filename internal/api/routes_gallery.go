@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/file/document"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// documentPageImageSize is the maximum size of document pages served to the
+// gallery viewer.
+const documentPageImageSize = 1920
+
+type galleryRoutes struct {
+	routes
+	galleryFinder models.GalleryGetter
+	chapterFinder models.GalleryChapterGetter
+	imageFinder   models.ImageQueryer
+	fileGetter    models.FileGetter
+}
+
+func getGalleryRoutes(repo models.Repository) chi.Router {
+	return galleryRoutes{
+		routes:        routes{txnManager: repo.TxnManager},
+		galleryFinder: repo.Gallery,
+		chapterFinder: repo.GalleryChapter,
+		imageFinder:   repo.Image,
+		fileGetter:    repo.File,
+	}.Routes()
+}
+
+func (rs galleryRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{galleryId}", func(r chi.Router) {
+		r.Use(rs.GalleryCtx)
+
+		r.Get("/page/{page}/image", rs.PageImage)
+		r.Get("/page/{page}/thumbnail", rs.PageThumbnail)
+		r.Get("/chapter/{chapterId}/thumbnail", rs.ChapterThumbnail)
+	})
+
+	return r
+}
+
+func (rs galleryRoutes) getDocumentPage(w http.ResponseWriter, r *http.Request) (*models.DocumentFile, int, bool) {
+	g := r.Context().Value(galleryKey).(*models.Gallery)
+
+	f, ok := g.Files.Primary().(*models.DocumentFile)
+	if !ok {
+		http.Error(w, "gallery is not a document", http.StatusNotFound)
+		return nil, 0, false
+	}
+
+	page, err := strconv.Atoi(chi.URLParam(r, "page"))
+	if err != nil || page < 1 || page > f.PageCount {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return nil, 0, false
+	}
+
+	return f, page, true
+}
+
+func (rs galleryRoutes) PageImage(w http.ResponseWriter, r *http.Request) {
+	f, page, ok := rs.getDocumentPage(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := manager.GetInstance().DocumentRenderer.RenderPage(f, page, documentPageImageSize)
+	if err != nil {
+		logger.Errorf("error rendering page %d of %s: %v", page, f.Path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	utils.ServeStaticContent(w, r, data)
+}
+
+func (rs galleryRoutes) PageThumbnail(w http.ResponseWriter, r *http.Request) {
+	f, page, ok := rs.getDocumentPage(w, r)
+	if !ok {
+		return
+	}
+
+	mgr := manager.GetInstance()
+	filepath := mgr.Paths.Generated.GetDocumentPagePath(document.Checksum(f), page, models.DefaultGthumbWidth)
+
+	// if the thumbnail doesn't exist, render on the fly
+	if exists, _ := fsutil.FileExists(filepath); exists {
+		utils.ServeStaticFile(w, r, filepath)
+		return
+	}
+
+	data, err := mgr.DocumentRenderer.RenderPage(f, page, models.DefaultGthumbWidth)
+	if err != nil {
+		logger.Errorf("error rendering page %d of %s: %v", page, f.Path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// write the generated thumbnail to disk if enabled
+	if mgr.Config.IsWriteImageThumbnails() {
+		if err := fsutil.WriteFile(filepath, data); err != nil {
+			logger.Errorf("error writing page thumbnail for %s: %v", f.Path, err)
+		}
+	}
+
+	utils.ServeStaticContent(w, r, data)
+}
+
+// ChapterThumbnail serves the thumbnail of the first image of a chapter.
+func (rs galleryRoutes) ChapterThumbnail(w http.ResponseWriter, r *http.Request) {
+	g := r.Context().Value(galleryKey).(*models.Gallery)
+	chapterID, _ := strconv.Atoi(chi.URLParam(r, "chapterId"))
+
+	var img *models.Image
+	readTxnErr := rs.withReadTxn(r, func(ctx context.Context) error {
+		chapter, err := rs.chapterFinder.Find(ctx, chapterID)
+		if err != nil || chapter == nil || chapter.GalleryID != g.ID {
+			return err
+		}
+
+		img, err = image.FindGalleryChapterImage(ctx, rs.imageFinder, g.ID, chapter.ImageIndex)
+		if err != nil || img == nil {
+			return err
+		}
+
+		return img.LoadPrimaryFile(ctx, rs.fileGetter)
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch gallery chapter thumbnail: %v", readTxnErr)
+		http.Error(w, readTxnErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if img == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	serveImageThumbnail(w, r, img)
+}
+
+func (rs galleryRoutes) GalleryCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		galleryID, err := strconv.Atoi(chi.URLParam(r, "galleryId"))
+		if err != nil {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		var gallery *models.Gallery
+		_ = rs.withReadTxn(r, func(ctx context.Context) error {
+			gallery, _ = rs.galleryFinder.Find(ctx, galleryID)
+
+			if gallery != nil {
+				if err := gallery.LoadPrimaryFile(ctx, rs.fileGetter); err != nil {
+					if !errors.Is(err, context.Canceled) {
+						logger.Errorf("error loading primary file for gallery %d: %v", galleryID, err)
+					}
+					// set gallery to nil so that it doesn't try to use the primary file
+					gallery = nil
+				}
+			}
+
+			return nil
+		})
+		// the stores exclude restricted content, but check again so that a
+		// restricted gallery is never served
+		if gallery == nil || !models.ContentRatingAccessible(r.Context(), gallery.ContentRating) {
+			http.Error(w, http.StatusText(404), 404)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), galleryKey, gallery)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
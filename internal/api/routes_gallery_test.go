@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGalleryCtx_ContentRating(t *testing.T) {
+	const (
+		galleryID        = 1
+		restrictedRating = 2
+	)
+
+	db := mocks.NewDatabase()
+	db.Gallery.On("Find", mock.Anything, galleryID).Return(&models.Gallery{
+		ID:            galleryID,
+		ContentRating: restrictedRating,
+		Files:         models.NewRelatedFiles(nil),
+	}, nil)
+
+	repo := db.Repository()
+	rs := galleryRoutes{
+		routes:        routes{txnManager: repo.TxnManager},
+		galleryFinder: repo.Gallery,
+		fileGetter:    repo.File,
+	}
+
+	r := chi.NewRouter()
+	r.Route("/{galleryId}", func(r chi.Router) {
+		r.Use(rs.GalleryCtx)
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	tests := []struct {
+		name string
+		max  *int
+		want int
+	}{
+		{"unrestricted", nil, http.StatusOK},
+		{"accessible", &[]int{restrictedRating}[0], http.StatusOK},
+		{"restricted", &[]int{restrictedRating - 1}[0], http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/1/", nil)
+			if tt.max != nil {
+				req = req.WithContext(models.WithMaxContentRating(req.Context(), *tt.max))
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/static"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/logger"
@@ -48,6 +47,7 @@ func (rs imageRoutes) Routes() chi.Router {
 		r.Get("/image", rs.Image)
 		r.Get("/thumbnail", rs.Thumbnail)
 		r.Get("/preview", rs.Preview)
+		r.Get("/video", rs.Video)
 	})
 
 	return r
@@ -55,6 +55,12 @@ func (rs imageRoutes) Routes() chi.Router {
 
 func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
 	img := r.Context().Value(imageKey).(*models.Image)
+	serveImageThumbnail(w, r, img)
+}
+
+// serveImageThumbnail serves the generated thumbnail of img, encoding it on
+// the fly if it has not been generated. img must have its primary file loaded.
+func serveImageThumbnail(w http.ResponseWriter, r *http.Request, img *models.Image) {
 	filepath := manager.GetInstance().Paths.Generated.GetThumbnailPath(img.Checksum, models.DefaultGthumbWidth)
 
 	// if the thumbnail doesn't exist, encode on the fly
@@ -66,7 +72,7 @@ func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
 
 		f := img.Files.Primary()
 		if f == nil {
-			rs.serveImage(w, r, img, useDefault)
+			serveImage(w, r, img, useDefault)
 			return
 		}
 
@@ -77,6 +83,8 @@ func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
 		}
 
 		encoder := image.NewThumbnailEncoder(manager.GetInstance().FFMPEG, manager.GetInstance().FFProbe, clipPreviewOptions)
+		encoder.FS = manager.GetInstance().FS
+		encoder.Backend = manager.GetInstance().Config.GetImageThumbnailBackend()
 		data, err := encoder.GetThumbnail(f, models.DefaultGthumbWidth)
 		if err != nil {
 			// don't log for unsupported image format
@@ -91,7 +99,7 @@ func (rs imageRoutes) Thumbnail(w http.ResponseWriter, r *http.Request) {
 			}
 
 			// backwards compatibility - fallback to original image instead
-			rs.serveImage(w, r, img, useDefault)
+			serveImage(w, r, img, useDefault)
 			return
 		}
 
@@ -116,16 +124,24 @@ func (rs imageRoutes) Preview(w http.ResponseWriter, r *http.Request) {
 	utils.ServeStaticFile(w, r, filepath)
 }
 
+func (rs imageRoutes) Video(w http.ResponseWriter, r *http.Request) {
+	img := r.Context().Value(imageKey).(*models.Image)
+	filepath := manager.GetInstance().Paths.Generated.GetClipTranscodePath(img.Checksum)
+
+	// don't check if the transcode exists - we'll just return a 404 if it doesn't
+	utils.ServeStaticFile(w, r, filepath)
+}
+
 func (rs imageRoutes) Image(w http.ResponseWriter, r *http.Request) {
 	i := r.Context().Value(imageKey).(*models.Image)
 
 	const useDefault = false
-	rs.serveImage(w, r, i, useDefault)
+	serveImage(w, r, i, useDefault)
 }
 
-func (rs imageRoutes) serveImage(w http.ResponseWriter, r *http.Request, i *models.Image, useDefault bool) {
+func serveImage(w http.ResponseWriter, r *http.Request, i *models.Image, useDefault bool) {
 	if i.Files.Primary() != nil {
-		err := i.Files.Primary().Base().Serve(&file.OsFS{}, w, r)
+		err := i.Files.Primary().Base().Serve(manager.GetInstance().FS, w, r)
 		if err == nil {
 			return
 		}
@@ -1,7 +1,6 @@
 package api
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"net/http"
@@ -69,17 +68,24 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Use(rs.SceneCtx)
 
 		// streaming endpoints
-		r.Get("/stream", rs.StreamDirect)
-		r.Get("/stream.mp4", rs.StreamMp4)
-		r.Get("/stream.webm", rs.StreamWebM)
-		r.Get("/stream.mkv", rs.StreamMKV)
-		r.Get("/stream.m3u8", rs.StreamHLS)
-		r.Get("/stream.m3u8/{segment}.ts", rs.StreamHLSSegment)
-		r.Get("/stream.mpd", rs.StreamDASH)
-		r.Get("/stream.mpd/{segment}_v.webm", rs.StreamDASHVideoSegment)
-		r.Get("/stream.mpd/{segment}_a.webm", rs.StreamDASHAudioSegment)
+		r.Group(func(r chi.Router) {
+			r.Use(streamUsageHandler)
+
+			r.Get("/stream", rs.StreamDirect)
+			r.Get("/stream.mp4", rs.StreamMp4)
+			r.Get("/stream.av1.mp4", rs.StreamMp4AV1)
+			r.Get("/stream.webm", rs.StreamWebM)
+			r.Get("/stream.mkv", rs.StreamMKV)
+			r.Get("/stream.m3u8", rs.StreamHLS)
+			r.Get("/stream.m3u8/{segment}.ts", rs.StreamHLSSegment)
+			r.Get("/stream_adaptive.m3u8", rs.StreamHLSAdaptive)
+			r.Get("/stream.mpd", rs.StreamDASH)
+			r.Get("/stream.mpd/{segment}_v.webm", rs.StreamDASHVideoSegment)
+			r.Get("/stream.mpd/{segment}_a.webm", rs.StreamDASHAudioSegment)
+		})
 
 		r.Get("/screenshot", rs.Screenshot)
+		r.Get("/frame", rs.Frame)
 		r.Get("/preview", rs.Preview)
 		r.Get("/webp", rs.Webp)
 		r.Get("/vtt/chapter", rs.VttChapter)
@@ -88,9 +94,11 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Get("/funscript", rs.Funscript)
 		r.Get("/interactive_csv", rs.InteractiveCSV)
 		r.Get("/interactive_heatmap", rs.InteractiveHeatmap)
+		r.Get("/waveform", rs.Waveform)
 		r.Get("/caption", rs.CaptionLang)
+		r.Get("/caption/embedded/{track}", rs.CaptionEmbedded)
 
-		r.Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
+		r.With(streamUsageHandler).Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
 		r.Get("/scene_marker/{sceneMarkerId}/preview", rs.SceneMarkerPreview)
 		r.Get("/scene_marker/{sceneMarkerId}/screenshot", rs.SceneMarkerScreenshot)
 	})
@@ -113,6 +121,10 @@ func (rs sceneRoutes) StreamMp4(w http.ResponseWriter, r *http.Request) {
 	rs.streamTranscode(w, r, ffmpeg.StreamTypeMP4)
 }
 
+func (rs sceneRoutes) StreamMp4AV1(w http.ResponseWriter, r *http.Request) {
+	rs.streamTranscode(w, r, ffmpeg.StreamTypeMP4AV1)
+}
+
 func (rs sceneRoutes) StreamWebM(w http.ResponseWriter, r *http.Request) {
 	rs.streamTranscode(w, r, ffmpeg.StreamTypeWEBM)
 }
@@ -179,6 +191,26 @@ func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	rs.streamManifest(w, r, ffmpeg.StreamTypeHLS, "HLS")
 }
 
+// StreamHLSAdaptive serves a master playlist for multi-bitrate HLS
+// streaming. The variants are the HLS streams at each resolution.
+func (rs sceneRoutes) StreamHLSAdaptive(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	streamManager := manager.GetInstance().StreamManager
+	if streamManager == nil {
+		http.Error(w, "Live transcoding disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	f := scene.Files.Primary()
+	if f == nil {
+		return
+	}
+
+	logger.Debugf("[transcode] returning adaptive HLS manifest for scene %d", scene.ID)
+	streamManager.ServeHLSMasterManifest(w, r, f, "stream.m3u8")
+}
+
 func (rs sceneRoutes) StreamDASH(w http.ResponseWriter, r *http.Request) {
 	rs.streamManifest(w, r, ffmpeg.StreamTypeDASHVideo, "DASH")
 }
@@ -273,8 +305,18 @@ func (rs sceneRoutes) Preview(w http.ResponseWriter, r *http.Request) {
 
 func (rs sceneRoutes) Webp(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
-	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
-	filepath := manager.GetInstance().Paths.Scene.GetWebpPreviewPath(sceneHash)
+	config := config.GetInstance()
+	sceneHash := scene.GetHash(config.GetVideoFileNamingAlgorithm())
+	paths := manager.GetInstance().Paths.Scene.GetPreviewImagePaths(sceneHash, config.GetPreviewImageFormat())
+
+	// serve whichever format has been generated, preferring the configured one
+	filepath := paths[0]
+	for _, p := range paths {
+		if exists, _ := fsutil.FileExists(p); exists {
+			filepath = p
+			break
+		}
+	}
 
 	utils.ServeStaticFile(w, r, filepath)
 }
@@ -362,6 +404,7 @@ func (rs sceneRoutes) VttThumbs(w http.ResponseWriter, r *http.Request) {
 		sceneHash = chi.URLParam(r, "sceneHash")
 	}
 	filepath := manager.GetInstance().Paths.Scene.GetSpriteVttFilePath(sceneHash)
+	manager.GetInstance().RecordGeneratedAccess(filepath)
 
 	w.Header().Set("Content-Type", "text/vtt")
 	utils.ServeStaticFile(w, r, filepath)
@@ -376,6 +419,7 @@ func (rs sceneRoutes) VttSprite(w http.ResponseWriter, r *http.Request) {
 		sceneHash = chi.URLParam(r, "sceneHash")
 	}
 	filepath := manager.GetInstance().Paths.Scene.GetSpriteImageFilePath(sceneHash)
+	manager.GetInstance().RecordGeneratedAccess(filepath)
 
 	utils.ServeStaticFile(w, r, filepath)
 }
@@ -409,6 +453,14 @@ func (rs sceneRoutes) InteractiveHeatmap(w http.ResponseWriter, r *http.Request)
 	utils.ServeStaticFile(w, r, filepath)
 }
 
+func (rs sceneRoutes) Waveform(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
+	filepath := manager.GetInstance().Paths.Scene.GetWaveformPath(sceneHash)
+
+	utils.ServeStaticFile(w, r, filepath)
+}
+
 func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request, lang string, ext string) {
 	s := r.Context().Value(sceneKey).(*models.Scene)
 
@@ -438,23 +490,15 @@ func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request, lang strin
 			continue
 		}
 
-		sub, err := video.ReadSubs(caption.Path(s.Path))
+		vtt, err := manager.GetCaptionVTT(s, caption, caption.Path(s.Path))
 		if err != nil {
 			logger.Warnf("error while reading subs: %v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		var buf bytes.Buffer
-
-		err = sub.WriteToWebVTT(&buf)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
 		w.Header().Set("Content-Type", "text/vtt")
-		utils.ServeStaticContent(w, r, buf.Bytes())
+		utils.ServeStaticContent(w, r, vtt)
 		return
 	}
 }
@@ -470,6 +514,77 @@ func (rs sceneRoutes) CaptionLang(w http.ResponseWriter, r *http.Request) {
 	rs.Caption(w, r, l, ext)
 }
 
+func (rs sceneRoutes) CaptionEmbedded(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+
+	track, err := strconv.Atoi(chi.URLParam(r, "track"))
+	if err != nil || track < 0 {
+		http.Error(w, "invalid track", http.StatusBadRequest)
+		return
+	}
+
+	primaryFile := s.Files.Primary()
+	if primaryFile == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	vttPath, err := manager.GetEmbeddedCaptionVTT(r.Context(), s, primaryFile.Path, track)
+	if errors.Is(err, manager.ErrCaptionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logger.Warnf("error while extracting embedded subs: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vtt")
+	utils.ServeStaticFile(w, r, vttPath)
+}
+
+// Frame serves a JPEG of the scene at the time in seconds given by the t
+// query parameter, optionally scaled to the width query parameter.
+func (rs sceneRoutes) Frame(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+
+	t, err := strconv.ParseFloat(r.URL.Query().Get("t"), 64)
+	if err != nil {
+		http.Error(w, "invalid time", http.StatusBadRequest)
+		return
+	}
+
+	width := 0
+	if v := r.URL.Query().Get("width"); v != "" {
+		width, err = strconv.Atoi(v)
+		if err != nil || width < 0 {
+			http.Error(w, "invalid width", http.StatusBadRequest)
+			return
+		}
+	}
+
+	framePath, err := manager.GetSceneFrame(r.Context(), s, t, width)
+	if errors.Is(err, manager.ErrInvalidFrameTime) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logger.Warnf("error while extracting frame: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	manager.GetInstance().RecordGeneratedAccess(framePath)
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	utils.ServeStaticFile(w, r, framePath)
+}
+
 func (rs sceneRoutes) SceneMarkerStream(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
@@ -494,10 +609,35 @@ func (rs sceneRoutes) SceneMarkerStream(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	filepath := manager.GetInstance().Paths.SceneMarkers.GetVideoPreviewPath(sceneHash, int(sceneMarker.Seconds))
+	filepath := markerPreviewPath(sceneHash, sceneMarker)
+	manager.GetInstance().RecordGeneratedAccess(filepath)
 	utils.ServeStaticFile(w, r, filepath)
 }
 
+// markerPreviewPath returns the path of the preview of sceneMarker, which
+// depends on the preview format of the marker. The format defaults to that
+// of the default generate settings.
+func markerPreviewPath(sceneHash string, sceneMarker *models.SceneMarker) string {
+	format := models.MarkerPreviewFormatVideo
+	if sceneMarker.PreviewFormat != nil {
+		format = *sceneMarker.PreviewFormat
+	} else if o := config.GetInstance().GetDefaultGenerateSettings(); o != nil && o.MarkerPreviewOptions != nil && o.MarkerPreviewOptions.MarkerPreviewFormat != nil {
+		format = *o.MarkerPreviewOptions.MarkerPreviewFormat
+	}
+
+	paths := manager.GetInstance().Paths.SceneMarkers
+	seconds := int(sceneMarker.Seconds)
+
+	switch format {
+	case models.MarkerPreviewFormatWebp:
+		return paths.GetWebpFormatPreviewPath(sceneHash, seconds)
+	case models.MarkerPreviewFormatStatic:
+		return paths.GetStaticFormatPreviewPath(sceneHash, seconds)
+	default:
+		return paths.GetVideoPreviewPath(sceneHash, seconds)
+	}
+}
+
 func (rs sceneRoutes) SceneMarkerPreview(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
@@ -523,6 +663,7 @@ func (rs sceneRoutes) SceneMarkerPreview(w http.ResponseWriter, r *http.Request)
 	}
 
 	filepath := manager.GetInstance().Paths.SceneMarkers.GetWebpPreviewPath(sceneHash, int(sceneMarker.Seconds))
+	manager.GetInstance().RecordGeneratedAccess(filepath)
 
 	// If the image doesn't exist, send the placeholder
 	exists, _ := fsutil.FileExists(filepath)
@@ -559,6 +700,7 @@ func (rs sceneRoutes) SceneMarkerScreenshot(w http.ResponseWriter, r *http.Reque
 	}
 
 	filepath := manager.GetInstance().Paths.SceneMarkers.GetScreenshotPath(sceneHash, int(sceneMarker.Seconds))
+	manager.GetInstance().RecordGeneratedAccess(filepath)
 
 	// If the image doesn't exist, send the placeholder
 	exists, _ := fsutil.FileExists(filepath)
@@ -595,7 +737,9 @@ func (rs sceneRoutes) SceneCtx(next http.Handler) http.Handler {
 
 			return nil
 		})
-		if scene == nil {
+		// the stores exclude restricted content, but check again so that a
+		// restricted scene is never served
+		if scene == nil || !models.ContentRatingAccessible(r.Context(), scene.ContentRating) {
 			http.Error(w, http.StatusText(404), 404)
 			return
 		}
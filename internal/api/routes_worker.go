@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// workerRoutes are used by remote generate workers to lease generate work,
+// read the scene files and upload the generated files.
+type workerRoutes struct{}
+
+func getWorkerRoutes() chi.Router {
+	return workerRoutes{}.Routes()
+}
+
+func (rs workerRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/lease", rs.lease)
+	r.Route("/items/{workId}", func(r chi.Router) {
+		r.Get("/source", rs.source)
+		r.Post("/heartbeat", rs.heartbeat)
+		r.Put("/files/{name}", rs.file)
+		r.Post("/complete", rs.complete)
+	})
+
+	return r
+}
+
+func workError(w http.ResponseWriter, err error) {
+	if errors.Is(err, manager.ErrGenerateWorkNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	logger.Warnf("[worker] %v", err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+func (rs workerRoutes) lease(w http.ResponseWriter, r *http.Request) {
+	item := manager.GetInstance().LeaseGenerateWork(r.Context())
+	if item == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(item); err != nil {
+		logger.Warnf("[worker] error writing work: %v", err)
+	}
+}
+
+func (rs workerRoutes) source(w http.ResponseWriter, r *http.Request) {
+	path, err := manager.GetInstance().GenerateWorkSource(chi.URLParam(r, "workId"))
+	if err != nil {
+		workError(w, err)
+		return
+	}
+
+	utils.ServeStaticFile(w, r, path)
+}
+
+func (rs workerRoutes) heartbeat(w http.ResponseWriter, r *http.Request) {
+	if err := manager.GetInstance().HeartbeatGenerateWork(chi.URLParam(r, "workId")); err != nil {
+		workError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rs workerRoutes) file(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "workId")
+	name := chi.URLParam(r, "name")
+
+	if err := manager.GetInstance().SaveGenerateWorkFile(id, name, r.Body); err != nil {
+		workError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rs workerRoutes) complete(w http.ResponseWriter, r *http.Request) {
+	var result manager.GenerateWorkResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := manager.GetInstance().CompleteGenerateWork(chi.URLParam(r, "workId"), result); err != nil {
+		workError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
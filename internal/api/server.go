@@ -44,6 +44,9 @@ const (
 	logoutEndpoint     = "/logout"
 	gqlEndpoint        = "/graphql"
 	playgroundEndpoint = "/playground"
+
+	contentRatingUnlockEndpoint = "/content-rating/unlock"
+	contentRatingLockEndpoint   = "/content-rating/lock"
 )
 
 var uiBox = ui.UIBox
@@ -61,6 +64,12 @@ func Start() error {
 	r.Use(authenticateHandler())
 	visitedPluginHandler := manager.GetInstance().SessionStore.VisitedPluginHandler()
 	r.Use(visitedPluginHandler)
+	r.Use(manager.GetInstance().SessionStore.ContentRatingHandler())
+	r.Use(contentRatingHandler)
+
+	// must be after authentication so that only valid api keys are tracked
+	usageTracker := newUsageTracker(c)
+	r.Use(usageHandler(usageTracker))
 
 	r.Use(middleware.Recoverer)
 
@@ -95,12 +104,15 @@ func Start() error {
 	sceneService := manager.GetInstance().SceneService
 	imageService := manager.GetInstance().ImageService
 	galleryService := manager.GetInstance().GalleryService
+	audioService := manager.GetInstance().AudioService
 	resolver := &Resolver{
 		repository:     repo,
 		sceneService:   sceneService,
 		imageService:   imageService,
 		galleryService: galleryService,
+		audioService:   audioService,
 		hookExecutor:   pluginCache,
+		usageTracker:   usageTracker,
 	}
 
 	gqlSrv := gqlHandler.New(NewExecutableSchema(Config{Resolvers: resolver}))
@@ -122,6 +134,7 @@ func Start() error {
 
 	gqlSrv.SetQueryCache(gqlLru.New(1000))
 	gqlSrv.Use(gqlExtension.Introspection{})
+	gqlSrv.AroundOperations(mutationUsageHandler(usageTracker))
 
 	gqlSrv.SetErrorPresenter(gqlErrorHandler)
 
@@ -146,10 +159,13 @@ func Start() error {
 	r.Mount("/performer", getPerformerRoutes(repo))
 	r.Mount("/scene", getSceneRoutes(repo))
 	r.Mount("/image", getImageRoutes(repo))
+	r.Mount("/audio", getAudioRoutes(repo))
+	r.Mount("/gallery", getGalleryRoutes(repo))
 	r.Mount("/studio", getStudioRoutes(repo))
 	r.Mount("/movie", getMovieRoutes(repo))
 	r.Mount("/tag", getTagRoutes(repo))
 	r.Mount("/downloads", getDownloadsRoutes())
+	r.Mount("/worker", getWorkerRoutes())
 
 	r.HandleFunc("/css", cssHandler(c, pluginCache))
 	r.HandleFunc("/javascript", javascriptHandler(c, pluginCache))
@@ -160,6 +176,8 @@ func Start() error {
 	r.Get(loginEndpoint, handleLogin(loginUIBox))
 	r.Post(loginEndpoint, handleLoginPost(loginUIBox))
 	r.Get(logoutEndpoint, handleLogout())
+	r.Post(contentRatingUnlockEndpoint, handleContentRatingUnlock())
+	r.Post(contentRatingLockEndpoint, handleContentRatingLock())
 	r.HandleFunc(loginEndpoint+"/*", func(w http.ResponseWriter, r *http.Request) {
 		r.URL.Path = strings.TrimPrefix(r.URL.Path, loginEndpoint)
 		w.Header().Set("Cache-Control", "no-cache")
@@ -119,3 +119,46 @@ func handleLogout() http.HandlerFunc {
 		}
 	}
 }
+
+const pinFormKey = "pin"
+
+func handleContentRatingUnlock() http.HandlerFunc {
+	throttle := newUnlockThrottle()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := unlockHost(r)
+		if !throttle.allowed(host) {
+			logger.Warnf("Too many failed content rating unlock attempts from %s", host)
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		level := config.GetInstance().GetContentRatingTiers().Unlock(r.FormValue(pinFormKey))
+		if level == 0 {
+			throttle.fail(host)
+			logger.Warn("Invalid content rating PIN")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		throttle.succeed(host)
+
+		if err := manager.GetInstance().SessionStore.UnlockContentRating(w, r, level); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func handleContentRatingLock() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := manager.GetInstance().SessionStore.LockContentRating(w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,29 @@
+package urlbuilders
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type GalleryURLBuilder struct {
+	BaseURL   string
+	GalleryID string
+	UpdatedAt string
+}
+
+func NewGalleryURLBuilder(baseURL string, gallery *models.Gallery) GalleryURLBuilder {
+	return GalleryURLBuilder{
+		BaseURL:   baseURL,
+		GalleryID: strconv.Itoa(gallery.ID),
+		UpdatedAt: strconv.FormatInt(gallery.UpdatedAt.Unix(), 10),
+	}
+}
+
+func (b GalleryURLBuilder) GetPageImageURL(page int) string {
+	return b.BaseURL + "/gallery/" + b.GalleryID + "/page/" + strconv.Itoa(page) + "/image?t=" + b.UpdatedAt
+}
+
+func (b GalleryURLBuilder) GetPageThumbnailURL(page int) string {
+	return b.BaseURL + "/gallery/" + b.GalleryID + "/page/" + strconv.Itoa(page) + "/thumbnail?t=" + b.UpdatedAt
+}
@@ -0,0 +1,27 @@
+package urlbuilders
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type GalleryChapterURLBuilder struct {
+	BaseURL   string
+	GalleryID string
+	ChapterID string
+	UpdatedAt string
+}
+
+func NewGalleryChapterURLBuilder(baseURL string, chapter *models.GalleryChapter) GalleryChapterURLBuilder {
+	return GalleryChapterURLBuilder{
+		BaseURL:   baseURL,
+		GalleryID: strconv.Itoa(chapter.GalleryID),
+		ChapterID: strconv.Itoa(chapter.ID),
+		UpdatedAt: strconv.FormatInt(chapter.UpdatedAt.Unix(), 10),
+	}
+}
+
+func (b GalleryChapterURLBuilder) GetThumbnailURL() string {
+	return b.BaseURL + "/gallery/" + b.GalleryID + "/chapter/" + b.ChapterID + "/thumbnail?t=" + b.UpdatedAt
+}
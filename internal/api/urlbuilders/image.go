@@ -32,6 +32,13 @@ func (b ImageURLBuilder) GetThumbnailURL() string {
 	return b.BaseURL + "/image/" + b.ImageID + "/thumbnail?t=" + b.UpdatedAt
 }
 
+func (b ImageURLBuilder) GetVideoURL() string {
+	if exists, _ := fsutil.FileExists(manager.GetInstance().Paths.Generated.GetClipTranscodePath(b.Checksum)); exists {
+		return b.BaseURL + "/image/" + b.ImageID + "/video?t=" + b.UpdatedAt
+	}
+	return ""
+}
+
 func (b ImageURLBuilder) GetPreviewURL() string {
 	if exists, err := fsutil.FileExists(manager.GetInstance().Paths.Generated.GetClipPreviewPath(b.Checksum, models.DefaultGthumbWidth)); exists && err == nil {
 		return b.BaseURL + "/image/" + b.ImageID + "/preview?" + b.UpdatedAt
@@ -68,3 +68,7 @@ func (b SceneURLBuilder) GetCaptionURL() string {
 func (b SceneURLBuilder) GetInteractiveHeatmapURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/interactive_heatmap"
 }
+
+func (b SceneURLBuilder) GetWaveformURL() string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/waveform?t=" + b.UpdatedAt
+}
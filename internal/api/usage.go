@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/stashapp/stash/pkg/session"
+)
+
+const (
+	apiKeyIDLength = 12
+
+	// maxTrackedAPIKeys is the maximum number of API keys whose usage is
+	// tracked during a day.
+	maxTrackedAPIKeys = 1000
+)
+
+var (
+	ErrRequestQuotaExceeded  = errors.New("daily request quota exceeded for API key")
+	ErrMutationQuotaExceeded = errors.New("daily mutation quota exceeded for API key")
+	ErrStreamQuotaExceeded   = errors.New("daily streaming quota exceeded for API key")
+)
+
+type usageQuotaConfig interface {
+	GetAPIKeyRequestQuota() int
+	GetAPIKeyMutationQuota() int
+	GetAPIKeyStreamQuotaGB() float64
+}
+
+// apiKeyUsage is the usage of an API key during a single day.
+type apiKeyUsage struct {
+	KeyID         string
+	Date          string
+	Requests      int
+	Mutations     int
+	BytesStreamed int64
+}
+
+// usageTracker tracks the daily usage of API keys and enforces the configured
+// quotas. Usage is held in memory and is reset when the server restarts.
+// Only the usage of the current day is kept, for at most maxKeys keys.
+type usageTracker struct {
+	config  usageQuotaConfig
+	now     func() time.Time
+	maxKeys int
+
+	mutex sync.Mutex
+	date  string
+	usage map[string]*apiKeyUsage
+}
+
+func newUsageTracker(c usageQuotaConfig) *usageTracker {
+	return &usageTracker{
+		config:  c,
+		now:     time.Now,
+		maxKeys: maxTrackedAPIKeys,
+		usage:   make(map[string]*apiKeyUsage),
+	}
+}
+
+// apiKeyID returns an identifier for apiKey that does not expose the key.
+func apiKeyID(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])[:apiKeyIDLength]
+}
+
+// expire removes the usage of previous days. The mutex must be held by the
+// caller.
+func (t *usageTracker) expire() {
+	date := t.now().Format("2006-01-02")
+	if date != t.date {
+		t.date = date
+		t.usage = make(map[string]*apiKeyUsage)
+	}
+}
+
+// evict removes the usage of the key with the fewest requests, so that keys
+// that are used the most remain subject to their quotas. The mutex must be
+// held by the caller.
+func (t *usageTracker) evict() {
+	var least *apiKeyUsage
+	for _, u := range t.usage {
+		if least == nil || u.Requests < least.Requests {
+			least = u
+		}
+	}
+
+	if least != nil {
+		delete(t.usage, least.KeyID)
+	}
+}
+
+// get returns the usage of keyID for the current day. The mutex must be held
+// by the caller.
+func (t *usageTracker) get(keyID string) *apiKeyUsage {
+	t.expire()
+
+	ret := t.usage[keyID]
+	if ret == nil {
+		if len(t.usage) >= t.maxKeys {
+			t.evict()
+		}
+
+		ret = &apiKeyUsage{
+			KeyID: keyID,
+			Date:  t.date,
+		}
+		t.usage[keyID] = ret
+	}
+
+	return ret
+}
+
+// addRequest records a request made using keyID. Returns
+// ErrRequestQuotaExceeded without recording the request if the request quota
+// has been reached.
+func (t *usageTracker) addRequest(keyID string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	u := t.get(keyID)
+	if quota := t.config.GetAPIKeyRequestQuota(); quota > 0 && u.Requests >= quota {
+		return ErrRequestQuotaExceeded
+	}
+
+	u.Requests++
+	return nil
+}
+
+// addMutation records a mutation performed using keyID. Returns
+// ErrMutationQuotaExceeded without recording the mutation if the mutation
+// quota has been reached.
+func (t *usageTracker) addMutation(keyID string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	u := t.get(keyID)
+	if quota := t.config.GetAPIKeyMutationQuota(); quota > 0 && u.Mutations >= quota {
+		return ErrMutationQuotaExceeded
+	}
+
+	u.Mutations++
+	return nil
+}
+
+// streamQuota returns the daily streaming quota in bytes, or 0 if unlimited.
+func (t *usageTracker) streamQuota() int64 {
+	return int64(t.config.GetAPIKeyStreamQuotaGB() * 1024 * 1024 * 1024)
+}
+
+// checkStream returns ErrStreamQuotaExceeded if keyID has reached its
+// streaming quota. Streams that have already started are not interrupted.
+func (t *usageTracker) checkStream(keyID string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	u := t.get(keyID)
+	if quota := t.streamQuota(); quota > 0 && u.BytesStreamed >= quota {
+		return ErrStreamQuotaExceeded
+	}
+
+	return nil
+}
+
+func (t *usageTracker) addBytesStreamed(keyID string, n int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.get(keyID).BytesStreamed += n
+}
+
+// all returns the usage of all API keys for the current day, sorted by key id.
+func (t *usageTracker) all() []apiKeyUsage {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire()
+
+	var ret []apiKeyUsage
+	for _, u := range t.usage {
+		ret = append(ret, *u)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].KeyID < ret[j].KeyID
+	})
+
+	return ret
+}
+
+// usageHandler records the requests made using an API key. Requests
+// exceeding the configured quota are rejected. The streamed bytes of
+// requests are recorded by streamUsageHandler.
+func usageHandler(t *usageTracker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := session.GetRequestAPIKey(r)
+			if apiKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			keyID := apiKeyID(apiKey)
+
+			if err := t.addRequest(keyID); err != nil {
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+
+			ctx := setAPIKeyID(r.Context(), keyID)
+			ctx = context.WithValue(ctx, usageTrackerKey, t)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// streamUsageHandler marks a streaming endpoint. The bytes streamed using an
+// API key are recorded, and requests are rejected once the streaming quota
+// of the key is reached. Requests that were not made using an API key are
+// not affected.
+func streamUsageHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyID := getAPIKeyID(r.Context())
+		t, _ := r.Context().Value(usageTrackerKey).(*usageTracker)
+		if keyID == "" || t == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := t.checkStream(keyID); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			t.addBytesStreamed(keyID, int64(ww.BytesWritten()))
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// mutationUsageHandler records the mutations performed using an API key.
+// Mutations exceeding the configured quota are rejected.
+func mutationUsageHandler(t *usageTracker) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		keyID := getAPIKeyID(ctx)
+		op := graphql.GetOperationContext(ctx)
+
+		if keyID != "" && op.Operation != nil && op.Operation.Operation == ast.Mutation {
+			if err := t.addMutation(keyID); err != nil {
+				return graphql.OneShot(graphql.ErrorResponse(ctx, err.Error()))
+			}
+		}
+
+		return next(ctx)
+	}
+}
+
+func setAPIKeyID(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey, keyID)
+}
+
+func getAPIKeyID(ctx context.Context) string {
+	ret, _ := ctx.Value(apiKeyIDKey).(string)
+	return ret
+}
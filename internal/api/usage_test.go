@@ -0,0 +1,143 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testQuotaConfig struct {
+	requests  int
+	mutations int
+	streamGB  float64
+}
+
+func (c testQuotaConfig) GetAPIKeyRequestQuota() int      { return c.requests }
+func (c testQuotaConfig) GetAPIKeyMutationQuota() int     { return c.mutations }
+func (c testQuotaConfig) GetAPIKeyStreamQuotaGB() float64 { return c.streamGB }
+
+func TestUsageTrackerQuotas(t *testing.T) {
+	tracker := newUsageTracker(testQuotaConfig{requests: 2, mutations: 1})
+
+	assert.Nil(t, tracker.addRequest("a"))
+	assert.Nil(t, tracker.addRequest("a"))
+	assert.Equal(t, ErrRequestQuotaExceeded, tracker.addRequest("a"))
+
+	// quotas are per key
+	assert.Nil(t, tracker.addRequest("b"))
+
+	assert.Nil(t, tracker.addMutation("a"))
+	assert.Equal(t, ErrMutationQuotaExceeded, tracker.addMutation("a"))
+
+	// streaming is unlimited
+	tracker.addBytesStreamed("a", 1<<40)
+	assert.Nil(t, tracker.checkStream("a"))
+
+	assert.Equal(t, []apiKeyUsage{
+		{KeyID: "a", Date: tracker.now().Format("2006-01-02"), Requests: 2, Mutations: 1, BytesStreamed: 1 << 40},
+		{KeyID: "b", Date: tracker.now().Format("2006-01-02"), Requests: 1},
+	}, tracker.all())
+}
+
+func TestUsageTrackerDailyReset(t *testing.T) {
+	now := time.Date(2023, 1, 1, 23, 0, 0, 0, time.Local)
+
+	tracker := newUsageTracker(testQuotaConfig{streamGB: 1})
+	tracker.now = func() time.Time { return now }
+
+	tracker.addBytesStreamed("a", 1<<30)
+	assert.Equal(t, ErrStreamQuotaExceeded, tracker.checkStream("a"))
+
+	now = now.Add(2 * time.Hour)
+	assert.Nil(t, tracker.checkStream("a"))
+	assert.Equal(t, []apiKeyUsage{{KeyID: "a", Date: "2023-01-02"}}, tracker.all())
+}
+
+func TestUsageTrackerExpiry(t *testing.T) {
+	now := time.Date(2023, 1, 1, 23, 0, 0, 0, time.Local)
+
+	tracker := newUsageTracker(testQuotaConfig{})
+	tracker.now = func() time.Time { return now }
+
+	assert.Nil(t, tracker.addRequest("a"))
+	assert.Nil(t, tracker.addRequest("b"))
+
+	// keys that are not used on the next day are removed
+	now = now.Add(2 * time.Hour)
+	assert.Nil(t, tracker.addRequest("b"))
+	assert.Equal(t, []apiKeyUsage{{KeyID: "b", Date: "2023-01-02", Requests: 1}}, tracker.all())
+}
+
+func TestUsageTrackerMaxKeys(t *testing.T) {
+	tracker := newUsageTracker(testQuotaConfig{})
+	tracker.maxKeys = 2
+
+	assert.Nil(t, tracker.addRequest("a"))
+	assert.Nil(t, tracker.addRequest("a"))
+	assert.Nil(t, tracker.addRequest("b"))
+
+	// the least used key is evicted
+	assert.Nil(t, tracker.addRequest("c"))
+
+	date := tracker.now().Format("2006-01-02")
+	assert.Equal(t, []apiKeyUsage{
+		{KeyID: "a", Date: date, Requests: 2},
+		{KeyID: "c", Date: date, Requests: 1},
+	}, tracker.all())
+}
+
+func TestUsageHandler(t *testing.T) {
+	tracker := newUsageTracker(testQuotaConfig{requests: 3})
+
+	body := []byte("stream")
+	write := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(body)
+	})
+
+	// only the bytes written by handlers marked as streams are recorded
+	streamHandler := usageHandler(tracker)(streamUsageHandler(write))
+	otherHandler := usageHandler(tracker)(write)
+
+	serve := func(handler http.Handler, apiKey string) int {
+		r := httptest.NewRequest(http.MethodGet, "/audio/1/stream", nil)
+		if apiKey != "" {
+			r.Header.Set("ApiKey", apiKey)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// requests without an api key are not tracked
+	assert.Equal(t, http.StatusOK, serve(streamHandler, ""))
+	assert.Nil(t, tracker.all())
+
+	assert.Equal(t, http.StatusOK, serve(streamHandler, "key"))
+	assert.Equal(t, http.StatusOK, serve(otherHandler, "key"))
+	assert.Equal(t, http.StatusOK, serve(streamHandler, "key"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(streamHandler, "key"))
+
+	usage := tracker.all()
+	if assert.Len(t, usage, 1) {
+		assert.Equal(t, apiKeyID("key"), usage[0].KeyID)
+		assert.Equal(t, 3, usage[0].Requests)
+		assert.Equal(t, int64(2*len(body)), usage[0].BytesStreamed)
+	}
+}
+
+func TestStreamUsageHandlerQuota(t *testing.T) {
+	tracker := newUsageTracker(testQuotaConfig{streamGB: 1})
+	tracker.addBytesStreamed(apiKeyID("key"), 1024*1024*1024)
+
+	handler := usageHandler(tracker)(streamUsageHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	r := httptest.NewRequest(http.MethodGet, "/audio/1/stream", nil)
+	r.Header.Set("ApiKey", "key")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -683,7 +683,7 @@ func (me *contentDirectoryService) getMovies() []interface{} {
 
 func (me *contentDirectoryService) getMovieScenes(paths []string, host string) []interface{} {
 	sceneFilter := &models.SceneFilterType{
-		Movies: &models.MultiCriterionInput{
+		Movies: &models.HierarchicalMultiCriterionInput{
 			Modifier: models.CriterionModifierIncludes,
 			Value:    []string{paths[0]},
 		},
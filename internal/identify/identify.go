@@ -127,37 +127,7 @@ func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene)
 
 // Returns a MetadataOptions object with any default options overwritten by source specific options
 func (t *SceneIdentifier) getOptions(source ScraperSource) MetadataOptions {
-	var options MetadataOptions
-	if t.DefaultOptions != nil {
-		options = *t.DefaultOptions
-	}
-	if source.Options == nil {
-		return options
-	}
-
-	if source.Options.SetCoverImage != nil {
-		options.SetCoverImage = source.Options.SetCoverImage
-	}
-	if source.Options.SetOrganized != nil {
-		options.SetOrganized = source.Options.SetOrganized
-	}
-	if source.Options.IncludeMalePerformers != nil {
-		options.IncludeMalePerformers = source.Options.IncludeMalePerformers
-	}
-	if source.Options.SkipMultipleMatches != nil {
-		options.SkipMultipleMatches = source.Options.SkipMultipleMatches
-	}
-	if source.Options.SkipMultipleMatchTag != nil && len(*source.Options.SkipMultipleMatchTag) > 0 {
-		options.SkipMultipleMatchTag = source.Options.SkipMultipleMatchTag
-	}
-	if source.Options.SkipSingleNamePerformers != nil {
-		options.SkipSingleNamePerformers = source.Options.SkipSingleNamePerformers
-	}
-	if source.Options.SkipSingleNamePerformerTag != nil && len(*source.Options.SkipSingleNamePerformerTag) > 0 {
-		options.SkipSingleNamePerformerTag = source.Options.SkipSingleNamePerformerTag
-	}
-
-	return options
+	return *MergeOptions(t.DefaultOptions, source.Options)
 }
 
 func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene, result *scrapeResult) (*scene.UpdateSet, error) {
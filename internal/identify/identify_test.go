@@ -338,6 +338,84 @@ func Test_getFieldOptions(t *testing.T) {
 	}
 }
 
+func TestMergeOptions(t *testing.T) {
+	var (
+		setTrue  = true
+		setFalse = false
+		tag      = "1"
+		emptyTag = ""
+
+		titleIgnore = &FieldOptions{
+			Field:    "title",
+			Strategy: FieldStrategyIgnore,
+		}
+		titleOverwrite = &FieldOptions{
+			Field:    "title",
+			Strategy: FieldStrategyOverwrite,
+		}
+	)
+
+	defaults := &MetadataOptions{
+		FieldOptions:         []*FieldOptions{titleIgnore},
+		SetOrganized:         &setFalse,
+		SkipMultipleMatchTag: &tag,
+	}
+
+	tests := []struct {
+		name     string
+		defaults *MetadataOptions
+		override *MetadataOptions
+		want     *MetadataOptions
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+			&MetadataOptions{},
+		},
+		{
+			"nil override",
+			defaults,
+			nil,
+			defaults,
+		},
+		{
+			"nil defaults",
+			nil,
+			&MetadataOptions{
+				SetOrganized: &setTrue,
+			},
+			&MetadataOptions{
+				SetOrganized: &setTrue,
+			},
+		},
+		{
+			"override",
+			defaults,
+			&MetadataOptions{
+				FieldOptions:         []*FieldOptions{titleOverwrite},
+				SetOrganized:         &setTrue,
+				SkipMultipleMatchTag: &emptyTag,
+			},
+			&MetadataOptions{
+				FieldOptions:         []*FieldOptions{titleOverwrite, titleIgnore},
+				SetOrganized:         &setTrue,
+				SkipMultipleMatchTag: &tag,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeOptions(tt.defaults, tt.override)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// defaults must not be modified
+	assert.Equal(t, []*FieldOptions{titleIgnore}, defaults.FieldOptions)
+	assert.Equal(t, &setFalse, defaults.SetOrganized)
+}
+
 func Test_getScenePartial(t *testing.T) {
 	var (
 		originalTitle   = "originalTitle"
@@ -23,6 +23,19 @@ type Options struct {
 	SceneIDs []string `json:"sceneIDs"`
 	// paths of scenes to identify - ignored if scene ids are set
 	Paths []string `json:"paths"`
+	// Sources and options to use for scenes of specific studios
+	StudioOptions []*StudioOptions `json:"studioOptions"`
+}
+
+type StudioOptions struct {
+	// ID of the studio. Also applies to scenes of child studios that do not
+	// have their own options.
+	StudioID string `json:"studioID"`
+	// An ordered list of sources to use instead of the default sources.
+	// The default sources are used if empty.
+	Sources []*Source `json:"sources"`
+	// Options defined here override the default options
+	Options *MetadataOptions `json:"options"`
 }
 
 type MetadataOptions struct {
@@ -43,6 +56,46 @@ type MetadataOptions struct {
 	SkipSingleNamePerformerTag *string `json:"skipSingleNamePerformerTag"`
 }
 
+// MergeOptions returns the default options overwritten by any options set
+// in override. Field options in override take precedence over those in
+// defaults.
+func MergeOptions(defaults *MetadataOptions, override *MetadataOptions) *MetadataOptions {
+	var options MetadataOptions
+	if defaults != nil {
+		options = *defaults
+	}
+	if override == nil {
+		return &options
+	}
+
+	if len(override.FieldOptions) > 0 {
+		options.FieldOptions = append(append([]*FieldOptions{}, override.FieldOptions...), options.FieldOptions...)
+	}
+	if override.SetCoverImage != nil {
+		options.SetCoverImage = override.SetCoverImage
+	}
+	if override.SetOrganized != nil {
+		options.SetOrganized = override.SetOrganized
+	}
+	if override.IncludeMalePerformers != nil {
+		options.IncludeMalePerformers = override.IncludeMalePerformers
+	}
+	if override.SkipMultipleMatches != nil {
+		options.SkipMultipleMatches = override.SkipMultipleMatches
+	}
+	if override.SkipMultipleMatchTag != nil && len(*override.SkipMultipleMatchTag) > 0 {
+		options.SkipMultipleMatchTag = override.SkipMultipleMatchTag
+	}
+	if override.SkipSingleNamePerformers != nil {
+		options.SkipSingleNamePerformers = override.SkipSingleNamePerformers
+	}
+	if override.SkipSingleNamePerformerTag != nil && len(*override.SkipSingleNamePerformerTag) > 0 {
+		options.SkipSingleNamePerformerTag = override.SkipSingleNamePerformerTag
+	}
+
+	return &options
+}
+
 type FieldOptions struct {
 	Field    string        `json:"field"`
 	Strategy FieldStrategy `json:"strategy"`
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// FFMpegCodecArgs are the encoding options used in place of the built-in
+// options of a video codec.
+type FFMpegCodecArgs struct {
+	// Codec is the name of the ffmpeg encoder, such as libx264
+	Codec string   `json:"codec" mapstructure:"codec"`
+	Args  []string `json:"args" mapstructure:"args"`
+}
+
+// GetFFMpegLiveCodecArgs returns the configured encoding options used when
+// live transcoding. Codecs without configured options use the built-in
+// options.
+func (i *Instance) GetFFMpegLiveCodecArgs() []*FFMpegCodecArgs {
+	return i.getFFMpegCodecArgs(LiveCodecArgs)
+}
+
+// SetFFMpegLiveCodecArgs sets the encoding options used when live
+// transcoding. See setFFMpegCodecArgs.
+func (i *Instance) SetFFMpegLiveCodecArgs(input []*FFMpegCodecArgs) {
+	i.setFFMpegCodecArgs(LiveCodecArgs, input)
+}
+
+// GetFFMpegGenerateCodecArgs returns the configured encoding options used
+// when generating files. Codecs without configured options use the built-in
+// options.
+func (i *Instance) GetFFMpegGenerateCodecArgs() []*FFMpegCodecArgs {
+	return i.getFFMpegCodecArgs(GenerateCodecArgs)
+}
+
+// SetFFMpegGenerateCodecArgs sets the encoding options used when generating
+// files. See setFFMpegCodecArgs.
+func (i *Instance) SetFFMpegGenerateCodecArgs(input []*FFMpegCodecArgs) {
+	i.setFFMpegCodecArgs(GenerateCodecArgs, input)
+}
+
+func (i *Instance) getFFMpegCodecArgs(key string) []*FFMpegCodecArgs {
+	var ret []*FFMpegCodecArgs
+	if err := i.unmarshalKey(key, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// setFFMpegCodecArgs sets the encoding options of the codecs in input,
+// leaving the options of other codecs unchanged. The options of codecs with
+// no args are removed, so that the built-in options are used.
+func (i *Instance) setFFMpegCodecArgs(key string, input []*FFMpegCodecArgs) {
+	current := i.getFFMpegCodecArgs(key)
+
+	for _, in := range input {
+		found := false
+		for j, c := range current {
+			if c.Codec == in.Codec {
+				current[j] = in
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			current = append(current, in)
+		}
+	}
+
+	var codecArgs []map[string]interface{}
+	for _, c := range current {
+		if len(c.Args) == 0 {
+			continue
+		}
+
+		codecArgs = append(codecArgs, map[string]interface{}{
+			"codec": c.Codec,
+			"args":  c.Args,
+		})
+	}
+
+	i.Set(key, codecArgs)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFFMpegCodecArgs(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(LiveCodecArgs, nil)
+	defer i.Set(GenerateCodecArgs, nil)
+
+	i.SetFFMpegLiveCodecArgs([]*FFMpegCodecArgs{
+		{Codec: "libx264", Args: []string{"-crf", "18"}},
+		{Codec: "h264_nvenc", Args: []string{"-cq", "20"}},
+	})
+
+	// other codecs are unchanged, and empty args remove the options
+	i.SetFFMpegLiveCodecArgs([]*FFMpegCodecArgs{
+		{Codec: "libx264", Args: []string{"-crf", "20"}},
+		{Codec: "h264_nvenc"},
+		{Codec: "libvpx-vp9", Args: []string{"-crf", "28"}},
+	})
+
+	assert.Equal(t, []*FFMpegCodecArgs{
+		{Codec: "libx264", Args: []string{"-crf", "20"}},
+		{Codec: "libvpx-vp9", Args: []string{"-crf", "28"}},
+	}, i.GetFFMpegLiveCodecArgs())
+
+	// generate options are set separately
+	assert.Len(t, i.GetFFMpegGenerateCodecArgs(), 0)
+	i.SetFFMpegGenerateCodecArgs([]*FFMpegCodecArgs{
+		{Codec: "libx264", Args: []string{"-crf", "22"}},
+	})
+	assert.Equal(t, []*FFMpegCodecArgs{
+		{Codec: "libx264", Args: []string{"-crf", "22"}},
+	}, i.GetFFMpegGenerateCodecArgs())
+	assert.Len(t, i.GetFFMpegLiveCodecArgs(), 2)
+}
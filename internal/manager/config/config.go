@@ -27,6 +27,7 @@ const (
 	Stash               = "stash"
 	Cache               = "cache"
 	BackupDirectoryPath = "backup_directory_path"
+	TrashPath           = "trash_path"
 	Generated           = "generated"
 	Metadata            = "metadata"
 	BlobsPath           = "blobs_path"
@@ -36,8 +37,54 @@ const (
 	Password            = "password"
 	MaxSessionAge       = "max_session_age"
 
+	// ContentRatingTiers is the config key for the PIN protected content
+	// rating tiers.
+	ContentRatingTiers = "content_rating_tiers"
+
+	// daily API key quotas. Zero means unlimited.
+	APIKeyRequestQuota  = "api_key_request_quota"
+	APIKeyMutationQuota = "api_key_mutation_quota"
+	APIKeyStreamQuotaGB = "api_key_stream_quota_gb"
+
+	// QuietHours is the config key for the times during which heavy jobs
+	// are paused.
+	QuietHours = "quiet_hours"
+
 	BlobsStorage = "blobs_storage"
 
+	// TrashRetentionDays is the number of days that deleted files are kept
+	// in the trash before being purged. Zero keeps them until purged
+	// manually.
+	TrashRetentionDays = "trash_retention_days"
+
+	// GeneratedRetentionDays is the number of days that transcodes, sprites
+	// and marker previews are kept without being accessed before they are
+	// removed by a clean of generated files. Zero disables the clean.
+	GeneratedRetentionDays = "generated_retention_days"
+
+	// QuarantineDays is the number of days that scenes whose files are
+	// missing are quarantined before being deleted by a clean. Zero deletes
+	// them immediately.
+	QuarantineDays        = "quarantine_days"
+	quarantineDaysDefault = 30
+
+	// MinFreeSpaceGB is the free space, in gigabytes, below which the
+	// volumes containing the library and generated directories are low on
+	// space. Generate and export jobs are paused while any volume is low.
+	// Zero disables the check.
+	MinFreeSpaceGB = "min_free_space_gb"
+
+	// StorageAlertWebhook is the URL that alerts are posted to when a
+	// volume becomes low on space.
+	StorageAlertWebhook = "storage_alert_webhook"
+
+	// GenerateWorkerPrimaryURL is the URL of the stash instance that this
+	// instance generates previews, sprites, phashes and transcodes for.
+	// When set, this instance runs as a generate worker, authenticating
+	// with GenerateWorkerAPIKey.
+	GenerateWorkerPrimaryURL = "generate_worker.primary_url"
+	GenerateWorkerAPIKey     = "generate_worker.api_key"
+
 	DefaultMaxSessionAge = 60 * 60 * 1 // 1 hours
 
 	Database = "database"
@@ -47,13 +94,34 @@ const (
 
 	VideoExtensions            = "video_extensions"
 	ImageExtensions            = "image_extensions"
+	AudioExtensions            = "audio_extensions"
 	GalleryExtensions          = "gallery_extensions"
+	DocumentExtensions         = "document_extensions"
 	CreateGalleriesFromFolders = "create_galleries_from_folders"
 
+	// CaseInsensitivePaths is the config key for comparing file and folder
+	// paths case-insensitively, for case-insensitive file systems.
+	CaseInsensitivePaths = "case_insensitive_paths"
+
+	// SidecarFormats is the config key for the formats of sidecar files
+	// that are read when creating scenes during scan.
+	SidecarFormats = "sidecar_formats"
+
+	// VerifyFilesInterval is the config key for the number of hours between
+	// scheduled file verifications. Verification is not scheduled if <= 0.
+	VerifyFilesInterval = "verify_files_interval"
+	// VerifyFilesSampleSize is the config key for the number of files
+	// verified by each scheduled verification.
+	VerifyFilesSampleSize = "verify_files_sample_size"
+
 	// CalculateMD5 is the config key used to determine if MD5 should be calculated
 	// for video files.
 	CalculateMD5 = "calculate_md5"
 
+	// FingerprintAlgorithms is the config key for the additional fingerprint
+	// algorithms calculated from the full contents of files, such as blake3.
+	FingerprintAlgorithms = "fingerprint_algorithms"
+
 	// VideoFileNamingAlgorithm is the config key used to determine what hash
 	// should be used when generating and using generated files for scenes.
 	VideoFileNamingAlgorithm = "video_file_naming_algorithm"
@@ -67,15 +135,58 @@ const (
 	LiveTranscodeInputArgs  = "ffmpeg.live_transcode.input_args"
 	LiveTranscodeOutputArgs = "ffmpeg.live_transcode.output_args"
 
+	// LiveTranscodeLoudnorm enables loudness normalization of the audio of
+	// live transcodes, to LiveTranscodeLoudnormTarget LUFS.
+	LiveTranscodeLoudnorm              = "ffmpeg.live_transcode.loudnorm"
+	LiveTranscodeLoudnormTarget        = "ffmpeg.live_transcode.loudnorm_target"
+	liveTranscodeLoudnormTargetDefault = -16.0
+
+	// HDRToneMapping enables tone mapping of HDR video to SDR when generating
+	// previews and transcodes, and when live transcoding.
+	HDRToneMapping        = "ffmpeg.hdr_tone_mapping"
+	hdrToneMappingDefault = true
+
+	// LiveCodecArgs and GenerateCodecArgs are the config keys for the
+	// encoding options used in place of the built-in options of video
+	// codecs, when live transcoding and when generating files respectively.
+	LiveCodecArgs     = "ffmpeg.live_codec_args"
+	GenerateCodecArgs = "ffmpeg.generate_codec_args"
+
 	ParallelTasks        = "parallel_tasks"
 	parallelTasksDefault = 1
 
+	ScanWalkParallelTasks        = "scan_walk_parallel_tasks"
+	scanWalkParallelTasksDefault = 1
+
+	// ImageThumbnailBackend is the config key for how image thumbnails are
+	// resized.
+	ImageThumbnailBackend = "image_thumbnail_backend"
+
+	// ImageThumbnailParallelTasks is the config key for the number of image
+	// thumbnails generated at once by generate tasks, within the parallel
+	// tasks limit. Detected if <= 0.
+	ImageThumbnailParallelTasks = "image_thumbnail_parallel_tasks"
+
 	PreviewPreset                 = "preview_preset"
+	PreviewImageFormat            = "preview_image_format"
+	PreviewVideoCodec             = "preview_video_codec"
 	TranscodeHardwareAcceleration = "ffmpeg.hardware_acceleration"
 
+	// StreamCacheSizeMB is the maximum size of live transcoded segments kept
+	// after streaming finishes. Zero disables the segment cache.
+	StreamCacheSizeMB = "stream_cache_size_mb"
+
+	TranscodeHardwareAccelerationCodec  = "ffmpeg.hardware_acceleration_codec"
+	TranscodeHardwareAccelerationDevice = "ffmpeg.hardware_acceleration_device"
+
 	SequentialScanning        = "sequential_scanning"
 	SequentialScanningDefault = false
 
+	// BestFrameCovers selects the best of a sample of frames when
+	// generating scene covers, instead of the frame at a fixed offset.
+	BestFrameCovers        = "best_frame_covers"
+	bestFrameCoversDefault = true
+
 	PreviewAudio        = "preview_audio"
 	previewAudioDefault = true
 
@@ -97,6 +208,27 @@ const (
 	CreateImageClipsFromVideos        = "create_image_clip_from_videos"
 	createImageClipsFromVideosDefault = false
 
+	// WatchLibrary is the config key for watching the stash paths for
+	// changes, and scanning changed files as they appear
+	WatchLibrary        = "watch_library"
+	watchLibraryDefault = false
+
+	// ObjectStorageMounts is the config key for the S3-compatible buckets
+	// mounted at local paths
+	ObjectStorageMounts = "object_storage"
+
+	// NetworkShares is the config key for the WebDAV and SMB shares mounted
+	// at local paths
+	NetworkShares = "network_shares"
+
+	// Libraries is the config key for the named libraries that stash paths
+	// can be assigned to
+	Libraries = "libraries"
+
+	// ScheduledTasks is the config key for the tasks that are started on a
+	// cron schedule.
+	ScheduledTasks = "scheduled_tasks"
+
 	Host        = "host"
 	hostDefault = "0.0.0.0"
 
@@ -130,6 +262,12 @@ const (
 
 	PythonPath = "python_path"
 
+	// export options
+	ExportFilenameTemplate = "export_filename_template"
+	// maximum export write rates. Zero means unlimited.
+	ExportMaxThroughputMB = "export_max_throughput_mb"
+	ExportMaxIOPS         = "export_max_iops"
+
 	// plugin options
 	PluginsPath          = "plugins_path"
 	PluginsSetting       = "plugins.settings"
@@ -249,14 +387,19 @@ const (
 
 	// File upload options
 	MaxUploadSize = "max_upload_size"
+
+	// Archive options
+	MaxNestedArchiveSize = "max_nested_archive_size"
 )
 
 // slice default values
 var (
-	defaultVideoExtensions   = []string{"m4v", "mp4", "mov", "wmv", "avi", "mpg", "mpeg", "rmvb", "rm", "flv", "asf", "mkv", "webm"}
-	defaultImageExtensions   = []string{"png", "jpg", "jpeg", "gif", "webp"}
-	defaultGalleryExtensions = []string{"zip", "cbz"}
-	defaultMenuItems         = []string{"scenes", "images", "movies", "markers", "galleries", "performers", "studios", "tags"}
+	defaultVideoExtensions    = []string{"m4v", "mp4", "mov", "wmv", "avi", "mpg", "mpeg", "rmvb", "rm", "flv", "asf", "mkv", "webm"}
+	defaultImageExtensions    = []string{"png", "jpg", "jpeg", "gif", "webp"}
+	defaultAudioExtensions    = []string{"mp3", "m4a", "m4b", "flac", "ogg", "opus", "wav", "aac", "wma"}
+	defaultGalleryExtensions  = []string{"zip", "cbz", "rar", "cbr", "7z", "cb7", "epub"}
+	defaultDocumentExtensions = []string{"pdf"}
+	defaultMenuItems          = []string{"scenes", "images", "movies", "markers", "galleries", "performers", "studios", "tags"}
 )
 
 type MissingConfigError struct {
@@ -584,6 +727,40 @@ func (i *Instance) GetBackupDirectoryPathOrDefault() string {
 	return ret
 }
 
+// GetTrashPath returns the directory that deleted files are moved to. Files
+// are deleted immediately if empty.
+func (i *Instance) GetTrashPath() string {
+	return i.getString(TrashPath)
+}
+
+func (i *Instance) GetTrashRetentionDays() int {
+	return i.getInt(TrashRetentionDays)
+}
+
+func (i *Instance) GetGeneratedRetentionDays() int {
+	return i.getInt(GeneratedRetentionDays)
+}
+
+func (i *Instance) GetQuarantineDays() int {
+	return i.getInt(QuarantineDays)
+}
+
+func (i *Instance) GetMinFreeSpaceGB() int {
+	return i.getInt(MinFreeSpaceGB)
+}
+
+func (i *Instance) GetStorageAlertWebhook() string {
+	return i.getString(StorageAlertWebhook)
+}
+
+func (i *Instance) GetGenerateWorkerPrimaryURL() string {
+	return strings.TrimSuffix(i.getString(GenerateWorkerPrimaryURL), "/")
+}
+
+func (i *Instance) GetGenerateWorkerAPIKey() string {
+	return i.getString(GenerateWorkerAPIKey)
+}
+
 func (i *Instance) GetJWTSignKey() []byte {
 	return []byte(i.getString(JWTSignKey))
 }
@@ -623,6 +800,14 @@ func (i *Instance) GetImageExtensions() []string {
 	return ret
 }
 
+func (i *Instance) GetAudioExtensions() []string {
+	ret := i.getStringSlice(AudioExtensions)
+	if ret == nil {
+		ret = defaultAudioExtensions
+	}
+	return ret
+}
+
 func (i *Instance) GetGalleryExtensions() []string {
 	ret := i.getStringSlice(GalleryExtensions)
 	if ret == nil {
@@ -631,6 +816,41 @@ func (i *Instance) GetGalleryExtensions() []string {
 	return ret
 }
 
+// GetDocumentExtensions returns the extensions of paged document files, such
+// as PDFs, that are scanned as galleries.
+func (i *Instance) GetDocumentExtensions() []string {
+	ret := i.getStringSlice(DocumentExtensions)
+	if ret == nil {
+		ret = defaultDocumentExtensions
+	}
+	return ret
+}
+
+// GetSidecarFormats returns the formats of the sidecar files that are read
+// when creating scenes during scan, in order of preference. Returns an empty
+// slice if sidecar files are not read.
+func (i *Instance) GetSidecarFormats() []string {
+	return i.getStringSlice(SidecarFormats)
+}
+
+// GetVerifyFilesInterval returns the number of hours between scheduled file
+// verifications. Returns 0 if verification is not scheduled.
+func (i *Instance) GetVerifyFilesInterval() int {
+	return i.getInt(VerifyFilesInterval)
+}
+
+// GetVerifyFilesSampleSize returns the number of files verified by each
+// scheduled verification. Returns 0 if all files are verified.
+func (i *Instance) GetVerifyFilesSampleSize() int {
+	return i.getInt(VerifyFilesSampleSize)
+}
+
+// IsCaseInsensitivePaths returns true if file and folder paths are compared
+// case-insensitively when finding existing files and folders.
+func (i *Instance) IsCaseInsensitivePaths() bool {
+	return i.getBool(CaseInsensitivePaths)
+}
+
 func (i *Instance) GetCreateGalleriesFromFolders() bool {
 	return i.getBool(CreateGalleriesFromFolders)
 }
@@ -652,6 +872,12 @@ func (i *Instance) IsCalculateMD5() bool {
 	return i.getBool(CalculateMD5)
 }
 
+// GetFingerprintAlgorithms returns the additional fingerprint algorithms to
+// calculate for files when scanning.
+func (i *Instance) GetFingerprintAlgorithms() []string {
+	return i.getStringSlice(FingerprintAlgorithms)
+}
+
 // GetVideoFileNamingAlgorithm returns what hash algorithm should be used for
 // naming generated scene video files.
 func (i *Instance) GetVideoFileNamingAlgorithm() models.HashAlgorithm {
@@ -780,6 +1006,25 @@ func (i *Instance) GetPythonPath() string {
 	return i.getString(PythonPath)
 }
 
+// GetExportFilenameTemplate returns the template used to generate the
+// filenames of exported scene, image and gallery JSON files. Returns an empty
+// string if the default filenames should be used.
+func (i *Instance) GetExportFilenameTemplate() string {
+	return i.getString(ExportFilenameTemplate)
+}
+
+// GetExportMaxThroughputMB returns the maximum number of megabytes per second
+// written when exporting and zipping metadata. Returns 0 if unlimited.
+func (i *Instance) GetExportMaxThroughputMB() int {
+	return i.getInt(ExportMaxThroughputMB)
+}
+
+// GetExportMaxIOPS returns the maximum number of writes per second when
+// exporting and zipping metadata. Returns 0 if unlimited.
+func (i *Instance) GetExportMaxIOPS() int {
+	return i.getInt(ExportMaxIOPS)
+}
+
 func (i *Instance) GetHost() string {
 	ret := i.getString(Host)
 	if ret == "" {
@@ -826,6 +1071,50 @@ func (i *Instance) GetParallelTasksWithAutoDetection() int {
 	return parallelTasks
 }
 
+// GetImageThumbnailBackend returns how image thumbnails are resized.
+// Defaults to ImageThumbnailBackendAuto.
+func (i *Instance) GetImageThumbnailBackend() models.ImageThumbnailBackend {
+	ret := models.ImageThumbnailBackend(i.getString(ImageThumbnailBackend))
+
+	if !ret.IsValid() {
+		return models.ImageThumbnailBackendAuto
+	}
+
+	return ret
+}
+
+// GetImageThumbnailParallelTasks returns the configured number of image
+// thumbnails generated at once.
+func (i *Instance) GetImageThumbnailParallelTasks() int {
+	return i.getInt(ImageThumbnailParallelTasks)
+}
+
+// GetImageThumbnailParallelTasksWithAutoDetection returns the number of
+// image thumbnails generated at once. Resizing a single image mostly uses a
+// single core with all of the backends, so one task per core is used if
+// not configured. See BenchmarkImageThumbnail in pkg/image.
+func (i *Instance) GetImageThumbnailParallelTasksWithAutoDetection() int {
+	ret := i.getInt(ImageThumbnailParallelTasks)
+	if ret <= 0 {
+		ret = runtime.NumCPU()
+	}
+	return ret
+}
+
+// GetScanWalkParallelTasks returns the number of directories that are read
+// concurrently while walking the library during a scan. This is separate
+// from the parallel tasks used to hash files, and is mostly useful for
+// libraries on network shares.
+func (i *Instance) GetScanWalkParallelTasks() int {
+	return i.getInt(ScanWalkParallelTasks)
+}
+
+// GetBestFrameCovers returns true if scene covers should be generated from
+// the best of a sample of frames.
+func (i *Instance) GetBestFrameCovers() bool {
+	return i.getBoolDefault(BestFrameCovers, bestFrameCoversDefault)
+}
+
 func (i *Instance) GetPreviewAudio() bool {
 	return i.getBool(PreviewAudio)
 }
@@ -867,10 +1156,53 @@ func (i *Instance) GetPreviewPreset() models.PreviewPreset {
 	return models.PreviewPreset(ret)
 }
 
+// GetPreviewImageFormat returns the format of generated animated image
+// previews. Defaults to lossless WebP.
+func (i *Instance) GetPreviewImageFormat() models.PreviewImageFormat {
+	ret := models.PreviewImageFormat(i.getString(PreviewImageFormat))
+
+	if !ret.IsValid() {
+		return models.PreviewImageFormatWebpLossless
+	}
+
+	return ret
+}
+
+// GetPreviewVideoCodec returns the video codec of generated preview videos.
+// Defaults to H.264.
+func (i *Instance) GetPreviewVideoCodec() models.PreviewVideoCodec {
+	ret := models.PreviewVideoCodec(i.getString(PreviewVideoCodec))
+
+	if !ret.IsValid() {
+		return models.PreviewVideoCodecH264
+	}
+
+	return ret
+}
+
 func (i *Instance) GetTranscodeHardwareAcceleration() bool {
 	return i.getBool(TranscodeHardwareAcceleration)
 }
 
+// GetStreamCacheSizeMB returns the maximum number of megabytes of live
+// transcoded segments kept in the cache directory after streaming finishes.
+// Returns 0 if the segments are removed.
+func (i *Instance) GetStreamCacheSizeMB() int {
+	return i.getInt(StreamCacheSizeMB)
+}
+
+// GetTranscodeHardwareAccelerationCodec returns the preferred hardware
+// encoder. If empty, the first detected encoder is used.
+func (i *Instance) GetTranscodeHardwareAccelerationCodec() string {
+	return i.getString(TranscodeHardwareAccelerationCodec)
+}
+
+// GetTranscodeHardwareAccelerationDevice returns the render device used for
+// VAAPI encoding. If empty, the default device is used.
+func (i *Instance) GetTranscodeHardwareAccelerationDevice() string {
+	return i.getString(TranscodeHardwareAccelerationDevice)
+}
+
 func (i *Instance) GetMaxTranscodeSize() models.StreamingResolutionEnum {
 	ret := i.getString(MaxTranscodeSize)
 
@@ -909,6 +1241,27 @@ func (i *Instance) GetLiveTranscodeOutputArgs() []string {
 	return i.getStringSlice(LiveTranscodeOutputArgs)
 }
 
+func (i *Instance) GetLiveTranscodeLoudnorm() bool {
+	return i.getBool(LiveTranscodeLoudnorm)
+}
+
+// GetLiveTranscodeLoudnormTarget returns the integrated loudness target, in
+// LUFS, of loudness normalized live transcodes. Defaults to -16.
+func (i *Instance) GetLiveTranscodeLoudnormTarget() float64 {
+	ret := i.getFloat64(LiveTranscodeLoudnormTarget)
+	if ret == 0 {
+		return liveTranscodeLoudnormTargetDefault
+	}
+
+	return ret
+}
+
+// GetHDRToneMapping returns true if HDR video should be tone mapped to SDR
+// when it is transcoded. Defaults to true.
+func (i *Instance) GetHDRToneMapping() bool {
+	return i.getBoolDefault(HDRToneMapping, hdrToneMappingDefault)
+}
+
 func (i *Instance) GetDrawFunscriptHeatmapRange() bool {
 	return i.getBoolDefault(DrawFunscriptHeatmapRange, drawFunscriptHeatmapRangeDefault)
 }
@@ -923,10 +1276,34 @@ func (i *Instance) IsCreateImageClipsFromVideos() bool {
 	return i.getBool(CreateImageClipsFromVideos)
 }
 
+// IsWatchLibrary returns true if the stash paths should be watched for
+// changes.
+func (i *Instance) IsWatchLibrary() bool {
+	return i.getBool(WatchLibrary)
+}
+
 func (i *Instance) GetAPIKey() string {
 	return i.getString(ApiKey)
 }
 
+// GetAPIKeyRequestQuota returns the maximum number of requests per day that
+// may be made using an API key. Returns 0 if unlimited.
+func (i *Instance) GetAPIKeyRequestQuota() int {
+	return i.getInt(APIKeyRequestQuota)
+}
+
+// GetAPIKeyMutationQuota returns the maximum number of GraphQL mutations per
+// day that may be performed using an API key. Returns 0 if unlimited.
+func (i *Instance) GetAPIKeyMutationQuota() int {
+	return i.getInt(APIKeyMutationQuota)
+}
+
+// GetAPIKeyStreamQuotaGB returns the maximum number of gigabytes per day that
+// may be streamed using an API key. Returns 0 if unlimited.
+func (i *Instance) GetAPIKeyStreamQuotaGB() float64 {
+	return i.getFloat64(APIKeyStreamQuotaGB)
+}
+
 func (i *Instance) GetUsername() string {
 	return i.getString(Username)
 }
@@ -1490,6 +1867,21 @@ func (i *Instance) GetMaxUploadSize() int64 {
 	return ret << 20
 }
 
+// GetMaxNestedArchiveSize returns the maximum size in bytes of an archive
+// within another archive that is extracted to be scanned or read. It is
+// configured in megabytes and defaults to 4096.
+func (i *Instance) GetMaxNestedArchiveSize() int64 {
+	i.RLock()
+	defer i.RUnlock()
+	ret := int64(4096)
+
+	v := i.viper(MaxNestedArchiveSize)
+	if v.IsSet(MaxNestedArchiveSize) {
+		ret = v.GetInt64(MaxNestedArchiveSize)
+	}
+	return ret << 20
+}
+
 // GetProxy returns the url of a http proxy to be used for all outgoing http calls.
 func (i *Instance) GetProxy() string {
 	// Validate format
@@ -1565,6 +1957,8 @@ func (i *Instance) setDefaultValues(write bool) error {
 	i.main.SetDefault(Port, portDefault)
 
 	i.main.SetDefault(ParallelTasks, parallelTasksDefault)
+	i.main.SetDefault(QuarantineDays, quarantineDaysDefault)
+	i.main.SetDefault(ScanWalkParallelTasks, scanWalkParallelTasksDefault)
 	i.main.SetDefault(SequentialScanning, SequentialScanningDefault)
 	i.main.SetDefault(PreviewSegmentDuration, previewSegmentDurationDefault)
 	i.main.SetDefault(PreviewSegments, previewSegmentsDefault)
@@ -1577,6 +1971,7 @@ func (i *Instance) setDefaultValues(write bool) error {
 
 	i.main.SetDefault(WriteImageThumbnails, writeImageThumbnailsDefault)
 	i.main.SetDefault(CreateImageClipsFromVideos, createImageClipsFromVideosDefault)
+	i.main.SetDefault(WatchLibrary, watchLibraryDefault)
 
 	i.main.SetDefault(Database, defaultDatabaseFilePath)
 
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// ContentRatingTier is a content rating level protected by a PIN. Content
+// with a content rating at or above Level is locked until a PIN for the tier
+// or a higher tier is entered.
+type ContentRatingTier struct {
+	Level   int    `mapstructure:"level"`
+	PINHash string `mapstructure:"pin_hash"`
+}
+
+type ContentRatingTierInput struct {
+	Level int `json:"level"`
+	// PIN may be omitted to keep the PIN of an existing tier with the same level
+	Pin *string `json:"pin"`
+}
+
+type ContentRatingTierList []ContentRatingTier
+
+// Unlock returns the highest tier level unlocked by pin. Returns 0 if pin
+// does not match any tier.
+func (t ContentRatingTierList) Unlock(pin string) int {
+	ret := 0
+	for _, tier := range t {
+		if tier.Level > ret && bcrypt.CompareHashAndPassword([]byte(tier.PINHash), []byte(pin)) == nil {
+			ret = tier.Level
+		}
+	}
+
+	return ret
+}
+
+// MaxAllowed returns the maximum content rating that is accessible when all
+// tiers up to and including unlockedLevel are unlocked. Returns nil if all
+// content is accessible.
+func (t ContentRatingTierList) MaxAllowed(unlockedLevel int) *int {
+	var ret *int
+	for _, tier := range t {
+		if tier.Level <= unlockedLevel {
+			continue
+		}
+
+		if v := tier.Level - 1; ret == nil || v < *ret {
+			ret = &v
+		}
+	}
+
+	return ret
+}
+
+func (i *Instance) GetContentRatingTiers() ContentRatingTierList {
+	var ret ContentRatingTierList
+	if err := i.unmarshalKey(ContentRatingTiers, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	sort.Slice(ret, func(a, b int) bool {
+		return ret[a].Level < ret[b].Level
+	})
+
+	return ret
+}
+
+// GetContentRatingTierLevels returns the levels of the configured content
+// rating tiers in ascending order.
+func (i *Instance) GetContentRatingTierLevels() []int {
+	tiers := i.GetContentRatingTiers()
+	ret := make([]int, len(tiers))
+	for j, t := range tiers {
+		ret[j] = t.Level
+	}
+
+	return ret
+}
+
+// SetContentRatingTiers replaces the configured content rating tiers. PINs
+// are stored hashed.
+func (i *Instance) SetContentRatingTiers(input []*ContentRatingTierInput) error {
+	existing := i.GetContentRatingTiers()
+
+	var tiers []map[string]interface{}
+	seen := make(map[int]bool)
+	for _, in := range input {
+		if in.Level < 1 {
+			return errors.New("content rating tier level must be greater than 0")
+		}
+
+		if seen[in.Level] {
+			return fmt.Errorf("duplicate content rating tier level %d", in.Level)
+		}
+		seen[in.Level] = true
+
+		pinHash := ""
+		if in.Pin != nil && *in.Pin != "" {
+			pinHash = hashPassword(*in.Pin)
+		} else {
+			for _, e := range existing {
+				if e.Level == in.Level {
+					pinHash = e.PINHash
+				}
+			}
+		}
+
+		if pinHash == "" {
+			return fmt.Errorf("PIN is required for content rating tier level %d", in.Level)
+		}
+
+		tiers = append(tiers, map[string]interface{}{
+			"level":    in.Level,
+			"pin_hash": pinHash,
+		})
+	}
+
+	i.Set(ContentRatingTiers, tiers)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentRatingTierList(t *testing.T) {
+	tiers := ContentRatingTierList{
+		{Level: 2, PINHash: hashPassword("1234")},
+		{Level: 4, PINHash: hashPassword("5678")},
+	}
+
+	intPtr := func(i int) *int { return &i }
+
+	assert.Equal(t, 0, tiers.Unlock("0000"))
+	assert.Equal(t, 2, tiers.Unlock("1234"))
+	assert.Equal(t, 4, tiers.Unlock("5678"))
+
+	assert.Equal(t, intPtr(1), tiers.MaxAllowed(0))
+	assert.Equal(t, intPtr(3), tiers.MaxAllowed(2))
+	assert.Nil(t, tiers.MaxAllowed(4))
+
+	var none ContentRatingTierList
+	assert.Nil(t, none.MaxAllowed(0))
+	assert.Equal(t, 0, none.Unlock("1234"))
+}
+
+func TestSetContentRatingTiers(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(ContentRatingTiers, nil)
+
+	pin := "1234"
+	if err := i.SetContentRatingTiers([]*ContentRatingTierInput{
+		{Level: 3, Pin: &pin},
+	}); err != nil {
+		t.Errorf("SetContentRatingTiers() error = %v", err)
+		return
+	}
+
+	// omitting the PIN keeps the existing PIN
+	if err := i.SetContentRatingTiers([]*ContentRatingTierInput{
+		{Level: 3},
+	}); err != nil {
+		t.Errorf("SetContentRatingTiers() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, []int{3}, i.GetContentRatingTierLevels())
+	assert.Equal(t, 3, i.GetContentRatingTiers().Unlock(pin))
+
+	// PIN is required for new tiers
+	assert.NotNil(t, i.SetContentRatingTiers([]*ContentRatingTierInput{
+		{Level: 3},
+		{Level: 5},
+	}))
+
+	assert.NotNil(t, i.SetContentRatingTiers([]*ContentRatingTierInput{
+		{Level: 0, Pin: &pin},
+	}))
+}
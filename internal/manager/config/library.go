@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// Library is a named group of stash paths with its own settings. Stash paths
+// are assigned to a library by name.
+type Library struct {
+	Name string `json:"name" mapstructure:"name"`
+	// Excludes are regexps of video paths to exclude, in addition to the
+	// global video excludes.
+	Excludes []string `json:"excludes" mapstructure:"excludes"`
+	// ImageExcludes are regexps of image and gallery paths to exclude, in
+	// addition to the global image excludes.
+	ImageExcludes []string `json:"imageExcludes" mapstructure:"imageExcludes"`
+	// ScanOptions are the generation options used for files in the library
+	// when scanning. Nil to use the options of the scan.
+	ScanOptions *ScanMetadataOptions `json:"scanOptions" mapstructure:"scanOptions"`
+	// ScanInterval is the number of minutes between automatic scans of the
+	// library. Zero to disable automatic scans.
+	ScanInterval int `json:"scanInterval" mapstructure:"scanInterval"`
+}
+
+type LibraryList []*Library
+
+// Get returns the library with the given name. Returns nil if there is no
+// such library.
+func (l LibraryList) Get(name string) *Library {
+	for _, lib := range l {
+		if lib.Name == name {
+			return lib
+		}
+	}
+
+	return nil
+}
+
+// Validate returns an error if any of the libraries have an empty or
+// duplicate name, an invalid exclude pattern or a negative scan interval.
+func (l LibraryList) Validate() error {
+	names := make(map[string]bool)
+	for _, lib := range l {
+		if lib.Name == "" {
+			return errors.New("library name must not be empty")
+		}
+		if names[lib.Name] {
+			return fmt.Errorf("duplicate library name %q", lib.Name)
+		}
+		names[lib.Name] = true
+
+		for _, p := range append(append([]string{}, lib.Excludes...), lib.ImageExcludes...) {
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("invalid exclude pattern %q in library %q: %w", p, lib.Name, err)
+			}
+		}
+
+		if lib.ScanInterval < 0 {
+			return fmt.Errorf("scan interval of library %q must not be negative", lib.Name)
+		}
+	}
+
+	return nil
+}
+
+func (i *Instance) GetLibraries() LibraryList {
+	var ret LibraryList
+	if err := i.unmarshalKey(Libraries, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// SetLibraries validates and replaces the configured libraries.
+func (i *Instance) SetLibraries(input LibraryList) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	libraries := make([]map[string]interface{}, len(input))
+	for j, lib := range input {
+		v := map[string]interface{}{
+			"name":          lib.Name,
+			"excludes":      lib.Excludes,
+			"imageExcludes": lib.ImageExcludes,
+			"scanInterval":  lib.ScanInterval,
+		}
+
+		if o := lib.ScanOptions; o != nil {
+			v["scanOptions"] = map[string]interface{}{
+				"scanGenerateCovers":        o.ScanGenerateCovers,
+				"scanGeneratePreviews":      o.ScanGeneratePreviews,
+				"scanGenerateImagePreviews": o.ScanGenerateImagePreviews,
+				"scanGenerateSprites":       o.ScanGenerateSprites,
+				"scanGeneratePhashes":       o.ScanGeneratePhashes,
+				"scanGenerateThumbnails":    o.ScanGenerateThumbnails,
+				"scanGenerateClipPreviews":  o.ScanGenerateClipPreviews,
+				"scanNewestFirst":           o.ScanNewestFirst,
+			}
+		}
+
+		libraries[j] = v
+	}
+
+	i.Set(Libraries, libraries)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLibraries(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(Libraries, nil)
+
+	input := LibraryList{
+		{
+			Name:          "movies",
+			Excludes:      []string{`/trailers/`},
+			ImageExcludes: []string{},
+			ScanOptions: &ScanMetadataOptions{
+				ScanGenerateCovers:  true,
+				ScanGeneratePhashes: true,
+			},
+			ScanInterval: 60,
+		},
+		{
+			Name: "photos",
+		},
+	}
+
+	if err := i.SetLibraries(input); err != nil {
+		t.Errorf("SetLibraries() error = %v", err)
+		return
+	}
+
+	got := i.GetLibraries()
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, input[0], got[0])
+		assert.Equal(t, "photos", got[1].Name)
+		assert.Nil(t, got[1].ScanOptions)
+	}
+
+	assert.NotNil(t, got.Get("photos"))
+	assert.Nil(t, got.Get("other"))
+}
+
+func TestLibraryList_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		l       LibraryList
+		wantErr bool
+	}{
+		{"valid", LibraryList{{Name: "a", Excludes: []string{`\.tmp$`}, ScanInterval: 30}, {Name: "b"}}, false},
+		{"empty name", LibraryList{{Name: ""}}, true},
+		{"duplicate name", LibraryList{{Name: "a"}, {Name: "a"}}, true},
+		{"invalid exclude", LibraryList{{Name: "a", Excludes: []string{"("}}}, true},
+		{"invalid image exclude", LibraryList{{Name: "a", ImageExcludes: []string{"["}}}, true},
+		{"negative interval", LibraryList{{Name: "a", ScanInterval: -1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.l.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, "Validate() error = %v", err)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/file/smb"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// NetworkShare is a WebDAV or SMB share mounted at a local path. Stash paths
+// within Path are read from the share instead of the local file system.
+type NetworkShare struct {
+	Path string `json:"path" mapstructure:"path"`
+	// URL is the location of the shared directory. WebDAV shares use http or
+	// https URLs, and SMB shares use smb://host[:port]/share[/path] URLs.
+	URL      string `json:"url" mapstructure:"url"`
+	Username string `json:"username" mapstructure:"username"`
+	Password string `json:"password" mapstructure:"password"`
+}
+
+type NetworkShareList []*NetworkShare
+
+// GetMount returns the network share that path is mounted in. Returns nil if
+// path is not within a network share.
+func (l NetworkShareList) GetMount(path string) *NetworkShare {
+	for _, s := range l {
+		if fsutil.IsPathInDir(s.Path, path) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// WithoutPasswords returns a copy of the list with the passwords removed, so
+// that it can be returned to clients.
+func (l NetworkShareList) WithoutPasswords() NetworkShareList {
+	ret := make(NetworkShareList, len(l))
+	for j, s := range l {
+		c := *s
+		c.Password = ""
+		ret[j] = &c
+	}
+
+	return ret
+}
+
+func (i *Instance) GetNetworkShares() NetworkShareList {
+	var ret NetworkShareList
+	if err := i.unmarshalKey(NetworkShares, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+func validateNetworkShareURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("network share URL %q is invalid: %w", s, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("network share URL %q is missing a host", s)
+		}
+	case "smb":
+		if _, err := smb.ParseURL(u); err != nil {
+			return fmt.Errorf("network share URL %q is invalid: %w", s, err)
+		}
+	default:
+		return fmt.Errorf("network share URL %q must be an http, https or smb URL", s)
+	}
+
+	return nil
+}
+
+// SetNetworkShares replaces the configured network shares. A blank password
+// keeps the existing password of the share at the same path, so that shares
+// returned without their passwords can be saved unchanged.
+func (i *Instance) SetNetworkShares(input []*NetworkShare) error {
+	var paths []string
+	for _, s := range input {
+		if err := validateNetworkShareURL(s.URL); err != nil {
+			return err
+		}
+
+		paths = append(paths, s.Path)
+	}
+	for _, s := range i.GetObjectStorage() {
+		paths = append(paths, s.Path)
+	}
+
+	if err := validateMountPaths(paths); err != nil {
+		return err
+	}
+
+	existing := i.GetNetworkShares()
+
+	shares := make([]map[string]interface{}, len(input))
+	for j, s := range input {
+		password := s.Password
+		if password == "" {
+			for _, e := range existing {
+				if filepath.Clean(e.Path) == filepath.Clean(s.Path) {
+					password = e.Password
+					break
+				}
+			}
+		}
+
+		shares[j] = map[string]interface{}{
+			"path":     filepath.Clean(s.Path),
+			"url":      s.URL,
+			"username": s.Username,
+			"password": password,
+		}
+	}
+
+	i.Set(NetworkShares, shares)
+
+	return nil
+}
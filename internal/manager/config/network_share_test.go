@@ -0,0 +1,88 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetNetworkShares(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(NetworkShares, nil)
+
+	mountPath := filepath.FromSlash("/mnt/share")
+
+	input := []*NetworkShare{
+		{
+			Path:     mountPath,
+			URL:      "smb://nas/media/library",
+			Username: "user",
+			Password: "password",
+		},
+	}
+
+	if err := i.SetNetworkShares(input); err != nil {
+		t.Errorf("SetNetworkShares() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, NetworkShareList(input), i.GetNetworkShares())
+	assert.True(t, i.IsMountedPath(filepath.Join(mountPath, "a.mp4")))
+	assert.False(t, i.IsMountedPath(filepath.FromSlash("/mnt/other/a.mp4")))
+
+	// passwords are not returned to clients
+	withoutPasswords := i.GetNetworkShares().WithoutPasswords()
+	assert.Equal(t, "", withoutPasswords[0].Password)
+
+	// a blank password keeps the existing password
+	if err := i.SetNetworkShares(withoutPasswords); err != nil {
+		t.Errorf("SetNetworkShares() error = %v", err)
+		return
+	}
+	assert.Equal(t, NetworkShareList(input), i.GetNetworkShares())
+
+	// a new password replaces the existing password
+	withoutPasswords[0].Password = "new password"
+	if err := i.SetNetworkShares(withoutPasswords); err != nil {
+		t.Errorf("SetNetworkShares() error = %v", err)
+		return
+	}
+	assert.Equal(t, "new password", i.GetNetworkShares()[0].Password)
+}
+
+func TestSetNetworkSharesInvalid(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(NetworkShares, nil)
+	defer i.Set(ObjectStorageMounts, nil)
+
+	if err := i.SetObjectStorage([]*ObjectStorage{
+		{Path: filepath.FromSlash("/mnt/bucket"), Endpoint: "s3.example.com", Bucket: "library"},
+	}); err != nil {
+		t.Fatalf("SetObjectStorage() error = %v", err)
+	}
+
+	share := func(path string, url string) *NetworkShare {
+		return &NetworkShare{
+			Path: filepath.FromSlash(path),
+			URL:  url,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []*NetworkShare
+	}{
+		{"relative path", []*NetworkShare{share("share", "https://nas/dav")}},
+		{"unsupported scheme", []*NetworkShare{share("/mnt/share", "ftp://nas/media")}},
+		{"missing host", []*NetworkShare{share("/mnt/share", "https:///dav")}},
+		{"missing share name", []*NetworkShare{share("/mnt/share", "smb://nas")}},
+		{"nested paths", []*NetworkShare{share("/mnt/share", "https://nas/dav"), share("/mnt/share/sub", "smb://nas/media")}},
+		{"within object storage", []*NetworkShare{share("/mnt/bucket/share", "https://nas/dav")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, i.SetNetworkShares(tt.input))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// ObjectStorage is an S3-compatible bucket mounted at a local path. Stash
+// paths within Path are read from the bucket instead of the local file
+// system.
+type ObjectStorage struct {
+	Path      string `json:"path" mapstructure:"path"`
+	Endpoint  string `json:"endpoint" mapstructure:"endpoint"`
+	Region    string `json:"region" mapstructure:"region"`
+	Bucket    string `json:"bucket" mapstructure:"bucket"`
+	Prefix    string `json:"prefix" mapstructure:"prefix"`
+	AccessKey string `json:"accessKey" mapstructure:"access_key"`
+	SecretKey string `json:"secretKey" mapstructure:"secret_key"`
+	UseSSL    bool   `json:"useSSL" mapstructure:"use_ssl"`
+	PathStyle bool   `json:"pathStyle" mapstructure:"path_style"`
+}
+
+type ObjectStorageList []*ObjectStorage
+
+// GetMount returns the object storage that path is mounted in. Returns nil
+// if path is not within an object storage mount.
+func (l ObjectStorageList) GetMount(path string) *ObjectStorage {
+	for _, s := range l {
+		if fsutil.IsPathInDir(s.Path, path) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// WithoutSecretKeys returns a copy of the list with the secret keys removed,
+// so that it can be returned to clients.
+func (l ObjectStorageList) WithoutSecretKeys() ObjectStorageList {
+	ret := make(ObjectStorageList, len(l))
+	for j, s := range l {
+		c := *s
+		c.SecretKey = ""
+		ret[j] = &c
+	}
+
+	return ret
+}
+
+func (i *Instance) GetObjectStorage() ObjectStorageList {
+	var ret ObjectStorageList
+	if err := i.unmarshalKey(ObjectStorageMounts, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+func validateObjectStorage(input []*ObjectStorage) error {
+	for _, s := range input {
+		if s.Endpoint == "" {
+			return errors.New("object storage endpoint cannot be blank")
+		}
+
+		if s.Bucket == "" {
+			return errors.New("object storage bucket cannot be blank")
+		}
+	}
+
+	return nil
+}
+
+// validateMountPaths returns an error if any of the paths that object
+// storage and network shares are mounted at are not absolute, or are within
+// each other.
+func validateMountPaths(paths []string) error {
+	for j, p := range paths {
+		if p == "" || !filepath.IsAbs(p) {
+			return fmt.Errorf("mount path %q must be an absolute path", p)
+		}
+
+		for _, other := range paths[:j] {
+			if fsutil.IsPathInDir(other, p) || fsutil.IsPathInDir(p, other) {
+				return fmt.Errorf("mount paths %q and %q overlap", other, p)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetObjectStorage replaces the configured object storage mounts. A blank
+// secret key keeps the existing secret key of the mount at the same path, so
+// that mounts returned without their secret keys can be saved unchanged.
+func (i *Instance) SetObjectStorage(input []*ObjectStorage) error {
+	if err := validateObjectStorage(input); err != nil {
+		return err
+	}
+
+	existing := i.GetObjectStorage()
+
+	var paths []string
+	for _, s := range input {
+		paths = append(paths, s.Path)
+	}
+	for _, s := range i.GetNetworkShares() {
+		paths = append(paths, s.Path)
+	}
+
+	if err := validateMountPaths(paths); err != nil {
+		return err
+	}
+
+	mounts := make([]map[string]interface{}, len(input))
+	for j, s := range input {
+		secretKey := s.SecretKey
+		if secretKey == "" {
+			for _, e := range existing {
+				if filepath.Clean(e.Path) == filepath.Clean(s.Path) {
+					secretKey = e.SecretKey
+					break
+				}
+			}
+		}
+
+		mounts[j] = map[string]interface{}{
+			"path":       filepath.Clean(s.Path),
+			"endpoint":   s.Endpoint,
+			"region":     s.Region,
+			"bucket":     s.Bucket,
+			"prefix":     s.Prefix,
+			"access_key": s.AccessKey,
+			"secret_key": secretKey,
+			"use_ssl":    s.UseSSL,
+			"path_style": s.PathStyle,
+		}
+	}
+
+	i.Set(ObjectStorageMounts, mounts)
+
+	return nil
+}
+
+// IsMountedPath returns true if path is within object storage or a network
+// share, rather than the local file system.
+func (i *Instance) IsMountedPath(path string) bool {
+	return i.GetObjectStorage().GetMount(path) != nil || i.GetNetworkShares().GetMount(path) != nil
+}
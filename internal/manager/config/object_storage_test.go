@@ -0,0 +1,85 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetObjectStorage(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(ObjectStorageMounts, nil)
+
+	mountPath := filepath.FromSlash("/mnt/bucket")
+
+	input := []*ObjectStorage{
+		{
+			Path:      mountPath,
+			Endpoint:  "s3.example.com",
+			Bucket:    "library",
+			AccessKey: "access",
+			SecretKey: "secret",
+			UseSSL:    true,
+		},
+	}
+
+	if err := i.SetObjectStorage(input); err != nil {
+		t.Errorf("SetObjectStorage() error = %v", err)
+		return
+	}
+
+	got := i.GetObjectStorage()
+	assert.Equal(t, ObjectStorageList(input), got)
+
+	assert.Equal(t, input[0], got.GetMount(filepath.Join(mountPath, "a.mp4")))
+	assert.Nil(t, got.GetMount(filepath.FromSlash("/mnt/other/a.mp4")))
+
+	// secret keys are not returned to clients
+	withoutSecrets := got.WithoutSecretKeys()
+	assert.Equal(t, "", withoutSecrets[0].SecretKey)
+	assert.Equal(t, "secret", got[0].SecretKey)
+
+	// a blank secret key keeps the existing secret key
+	if err := i.SetObjectStorage(withoutSecrets); err != nil {
+		t.Errorf("SetObjectStorage() error = %v", err)
+		return
+	}
+	assert.Equal(t, ObjectStorageList(input), i.GetObjectStorage())
+
+	// a new secret key replaces the existing secret key
+	withoutSecrets[0].SecretKey = "new secret"
+	if err := i.SetObjectStorage(withoutSecrets); err != nil {
+		t.Errorf("SetObjectStorage() error = %v", err)
+		return
+	}
+	assert.Equal(t, "new secret", i.GetObjectStorage()[0].SecretKey)
+}
+
+func TestSetObjectStorageInvalid(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(ObjectStorageMounts, nil)
+
+	valid := func(path string) *ObjectStorage {
+		return &ObjectStorage{
+			Path:     filepath.FromSlash(path),
+			Endpoint: "s3.example.com",
+			Bucket:   "library",
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input []*ObjectStorage
+	}{
+		{"relative path", []*ObjectStorage{valid("bucket")}},
+		{"blank endpoint", []*ObjectStorage{{Path: filepath.FromSlash("/mnt/bucket"), Bucket: "library"}}},
+		{"blank bucket", []*ObjectStorage{{Path: filepath.FromSlash("/mnt/bucket"), Endpoint: "s3.example.com"}}},
+		{"nested paths", []*ObjectStorage{valid("/mnt/bucket"), valid("/mnt/bucket/sub")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, i.SetObjectStorage(tt.input))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+const (
+	quietHoursTimeFormat = "15:04"
+	quietHoursDateFormat = "2006-01-02"
+)
+
+// QuietHoursWindow is a daily period during which heavy jobs are paused.
+type QuietHoursWindow struct {
+	// Days of the week on which the window starts, where 0 is Sunday.
+	// The window starts on every day if empty.
+	Days []int `json:"days" mapstructure:"days"`
+	// Start time in 24-hour HH:MM format
+	Start string `json:"start" mapstructure:"start"`
+	// End time in 24-hour HH:MM format. If not after Start, the window ends
+	// on the following day.
+	End string `json:"end" mapstructure:"end"`
+}
+
+// QuietHoursOptions configures the times during which heavy jobs are paused.
+type QuietHoursOptions struct {
+	Enabled bool                `json:"enabled" mapstructure:"enabled"`
+	Windows []*QuietHoursWindow `json:"windows" mapstructure:"windows"`
+	// Dates in YYYY-MM-DD format on which no windows start
+	Exceptions []string `json:"exceptions" mapstructure:"exceptions"`
+}
+
+func parseQuietHoursTime(s string) (time.Duration, error) {
+	t, err := time.Parse(quietHoursTimeFormat, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quiet hours time %q: must be in HH:MM format", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Validate returns an error if any of the times, days or dates are invalid.
+func (o QuietHoursOptions) Validate() error {
+	for _, w := range o.Windows {
+		if _, err := parseQuietHoursTime(w.Start); err != nil {
+			return err
+		}
+		if _, err := parseQuietHoursTime(w.End); err != nil {
+			return err
+		}
+
+		for _, d := range w.Days {
+			if d < int(time.Sunday) || d > int(time.Saturday) {
+				return fmt.Errorf("invalid quiet hours day %d: must be between 0 and 6", d)
+			}
+		}
+	}
+
+	for _, e := range o.Exceptions {
+		if _, err := time.Parse(quietHoursDateFormat, e); err != nil {
+			return fmt.Errorf("invalid quiet hours exception %q: must be in YYYY-MM-DD format", e)
+		}
+	}
+
+	return nil
+}
+
+// Active returns true if t is within one of the windows. Always returns
+// false if quiet hours are disabled.
+func (o QuietHoursOptions) Active(t time.Time) bool {
+	if !o.Enabled {
+		return false
+	}
+
+	today := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	// windows that started yesterday may not have ended yet
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		if sliceutil.Contains(o.Exceptions, day.Format(quietHoursDateFormat)) {
+			continue
+		}
+
+		for _, w := range o.Windows {
+			if w.active(day, t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// active returns true if the window starting on day contains t.
+func (w QuietHoursWindow) active(day time.Time, t time.Time) bool {
+	if len(w.Days) > 0 && !sliceutil.Contains(w.Days, int(day.Weekday())) {
+		return false
+	}
+
+	start, err := parseQuietHoursTime(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseQuietHoursTime(w.End)
+	if err != nil {
+		return false
+	}
+
+	startTime := day.Add(start)
+	endTime := day.Add(end)
+	if end <= start {
+		endTime = day.AddDate(0, 0, 1).Add(end)
+	}
+
+	return !t.Before(startTime) && t.Before(endTime)
+}
+
+func (i *Instance) GetQuietHours() QuietHoursOptions {
+	var ret QuietHoursOptions
+	if err := i.unmarshalKey(QuietHours, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// SetQuietHours validates and sets the quiet hours options.
+func (i *Instance) SetQuietHours(o QuietHoursOptions) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	var windows []map[string]interface{}
+	for _, w := range o.Windows {
+		windows = append(windows, map[string]interface{}{
+			"days":  w.Days,
+			"start": w.Start,
+			"end":   w.End,
+		})
+	}
+
+	i.Set(QuietHours, map[string]interface{}{
+		"enabled":    o.Enabled,
+		"windows":    windows,
+		"exceptions": o.Exceptions,
+	})
+
+	return nil
+}
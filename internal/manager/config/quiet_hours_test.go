@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHoursOptions_Active(t *testing.T) {
+	// 2023-05-01 is a Monday
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2023, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	o := QuietHoursOptions{
+		Enabled: true,
+		Windows: []*QuietHoursWindow{
+			{Start: "19:00", End: "23:30"},
+			{Days: []int{int(time.Friday)}, Start: "23:30", End: "02:00"},
+		},
+		Exceptions: []string{"2023-05-03"},
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"before window", at(1, 18, 59), false},
+		{"window start", at(1, 19, 0), true},
+		{"window end", at(1, 23, 30), false},
+		{"exception", at(3, 20, 0), false},
+		{"overnight window", at(5, 23, 45), true},
+		{"overnight window next day", at(6, 1, 0), true},
+		{"overnight window end", at(6, 2, 0), false},
+		{"overnight window wrong day", at(4, 23, 45), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, o.Active(tt.t))
+		})
+	}
+
+	o.Enabled = false
+	assert.False(t, o.Active(at(1, 20, 0)))
+}
+
+func TestQuietHoursOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		o       QuietHoursOptions
+		wantErr bool
+	}{
+		{"valid", QuietHoursOptions{
+			Windows:    []*QuietHoursWindow{{Days: []int{0, 6}, Start: "22:00", End: "06:00"}},
+			Exceptions: []string{"2023-12-25"},
+		}, false},
+		{"invalid start", QuietHoursOptions{Windows: []*QuietHoursWindow{{Start: "25:00", End: "06:00"}}}, true},
+		{"invalid day", QuietHoursOptions{Windows: []*QuietHoursWindow{{Days: []int{7}, Start: "22:00", End: "06:00"}}}, true},
+		{"invalid exception", QuietHoursOptions{Exceptions: []string{"25/12/2023"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.o.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
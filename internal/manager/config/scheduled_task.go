@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type ScheduledTaskType string
+
+const (
+	ScheduledTaskTypeScan     ScheduledTaskType = "SCAN"
+	ScheduledTaskTypeGenerate ScheduledTaskType = "GENERATE"
+	ScheduledTaskTypeClean    ScheduledTaskType = "CLEAN"
+	ScheduledTaskTypeAutoTag  ScheduledTaskType = "AUTO_TAG"
+	// ScheduledTaskTypeCleanGenerated removes generated files that have not
+	// been accessed within the generated retention period.
+	ScheduledTaskTypeCleanGenerated ScheduledTaskType = "CLEAN_GENERATED"
+)
+
+var AllScheduledTaskType = []ScheduledTaskType{
+	ScheduledTaskTypeScan,
+	ScheduledTaskTypeGenerate,
+	ScheduledTaskTypeClean,
+	ScheduledTaskTypeAutoTag,
+	ScheduledTaskTypeCleanGenerated,
+}
+
+func (e ScheduledTaskType) IsValid() bool {
+	switch e {
+	case ScheduledTaskTypeScan, ScheduledTaskTypeGenerate, ScheduledTaskTypeClean, ScheduledTaskTypeAutoTag, ScheduledTaskTypeCleanGenerated:
+		return true
+	}
+	return false
+}
+
+func (e ScheduledTaskType) String() string {
+	return string(e)
+}
+
+func (e *ScheduledTaskType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduledTaskType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduledTaskType", str)
+	}
+	return nil
+}
+
+func (e ScheduledTaskType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// ScheduledTask is a task that is started on a cron schedule. Scan, generate
+// and auto-tag tasks use the default task settings.
+type ScheduledTask struct {
+	Task ScheduledTaskType `json:"task" mapstructure:"task"`
+	// Cron is a five field cron expression, in the server's local time.
+	Cron string `json:"cron" mapstructure:"cron"`
+	// Paths restricts the scan, clean and auto-tag tasks to the paths.
+	// Empty for all stash paths. Ignored by generate tasks.
+	Paths []string `json:"paths" mapstructure:"paths"`
+}
+
+type ScheduledTaskList []*ScheduledTask
+
+// Validate returns an error if any of the tasks have an invalid task type or
+// cron expression.
+func (l ScheduledTaskList) Validate() error {
+	for _, t := range l {
+		if !t.Task.IsValid() {
+			return fmt.Errorf("invalid scheduled task %q", t.Task)
+		}
+
+		if _, err := utils.ParseCron(t.Cron); err != nil {
+			return fmt.Errorf("invalid schedule of %s task: %w", t.Task, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Instance) GetScheduledTasks() ScheduledTaskList {
+	var ret ScheduledTaskList
+	if err := i.unmarshalKey(ScheduledTasks, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// SetScheduledTasks validates and replaces the scheduled tasks.
+func (i *Instance) SetScheduledTasks(input ScheduledTaskList) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	tasks := make([]map[string]interface{}, len(input))
+	for j, t := range input {
+		tasks[j] = map[string]interface{}{
+			"task":  string(t.Task),
+			"cron":  t.Cron,
+			"paths": t.Paths,
+		}
+	}
+
+	i.Set(ScheduledTasks, tasks)
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetScheduledTasks(t *testing.T) {
+	i := GetInstance()
+	defer i.Set(ScheduledTasks, nil)
+
+	input := ScheduledTaskList{
+		{
+			Task:  ScheduledTaskTypeScan,
+			Cron:  "0 3 * * *",
+			Paths: []string{"/media"},
+		},
+		{
+			Task: ScheduledTaskTypeGenerate,
+			Cron: "@weekly",
+		},
+	}
+
+	if err := i.SetScheduledTasks(input); err != nil {
+		t.Errorf("SetScheduledTasks() error = %v", err)
+		return
+	}
+
+	got := i.GetScheduledTasks()
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, input[0], got[0])
+		assert.Equal(t, ScheduledTaskTypeGenerate, got[1].Task)
+		assert.Equal(t, "@weekly", got[1].Cron)
+		assert.Empty(t, got[1].Paths)
+	}
+}
+
+func TestScheduledTaskList_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		l       ScheduledTaskList
+		wantErr bool
+	}{
+		{"valid", ScheduledTaskList{{Task: ScheduledTaskTypeClean, Cron: "*/30 * * * *"}, {Task: ScheduledTaskTypeAutoTag, Cron: "@daily"}}, false},
+		{"invalid task", ScheduledTaskList{{Task: "BACKUP", Cron: "@daily"}}, true},
+		{"invalid cron", ScheduledTaskList{{Task: ScheduledTaskTypeScan, Cron: "* * *"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.l.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, "Validate() error = %v", err)
+		})
+	}
+}
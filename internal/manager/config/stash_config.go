@@ -1,22 +1,111 @@
 package config
 
 import (
+	"io/fs"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/stashapp/stash/pkg/fsutil"
 )
 
 // Stash configuration details
 type StashConfigInput struct {
-	Path         string `json:"path"`
-	ExcludeVideo bool   `json:"excludeVideo"`
-	ExcludeImage bool   `json:"excludeImage"`
+	Path          string       `json:"path"`
+	ExcludeVideo  bool         `json:"excludeVideo"`
+	ExcludeImage  bool         `json:"excludeImage"`
+	MinFileSize   int64        `json:"minFileSize"`
+	Extensions    []string     `json:"extensions"`
+	ModifiedAfter *time.Time   `json:"modifiedAfter"`
+	Library       string       `json:"library"`
+	ScanProfile   *ScanProfile `json:"scanProfile"`
 }
 
 type StashConfig struct {
 	Path         string `json:"path"`
 	ExcludeVideo bool   `json:"excludeVideo"`
 	ExcludeImage bool   `json:"excludeImage"`
+	// MinFileSize is the minimum size in bytes of files to scan. Zero to
+	// scan files of any size.
+	MinFileSize int64 `json:"minFileSize"`
+	// Extensions limits the scanned files to those with the given
+	// extensions. Empty to scan files with any supported extension.
+	Extensions []string `json:"extensions"`
+	// ModifiedAfter limits the scanned files to those modified after the
+	// given time. Nil to scan files regardless of modification time.
+	ModifiedAfter *time.Time `json:"modifiedAfter"`
+	// Library is the name of the library that the stash path belongs to.
+	// Empty if the stash path is not in a library.
+	Library string `json:"library"`
+	// ScanProfile restricts what is generated for files in the stash path
+	// when scanning. Nil to generate according to the scan options.
+	ScanProfile *ScanProfile `json:"scanProfile"`
+}
+
+// ScanProfile restricts the generation performed at scan time for the files
+// of a stash path. A profile can only disable generation that the scan
+// options would otherwise perform.
+type ScanProfile struct {
+	// GeneratePreviews allows generation of video previews, image previews,
+	// sprites and image clip previews.
+	GeneratePreviews bool `json:"generatePreviews"`
+	// GeneratePhashes allows generation of phashes.
+	GeneratePhashes bool `json:"generatePhashes"`
+	// CoverOnly restricts generation to scene covers and image thumbnails,
+	// regardless of the other settings.
+	CoverOnly bool `json:"coverOnly"`
+}
+
+// Apply returns the scan options with the generation disallowed by the
+// profile disabled.
+func (p *ScanProfile) Apply(o ScanMetadataOptions) ScanMetadataOptions {
+	if p == nil {
+		return o
+	}
+
+	if p.CoverOnly || !p.GeneratePreviews {
+		o.ScanGeneratePreviews = false
+		o.ScanGenerateImagePreviews = false
+		o.ScanGenerateSprites = false
+		o.ScanGenerateClipPreviews = false
+	}
+
+	if p.CoverOnly || !p.GeneratePhashes {
+		o.ScanGeneratePhashes = false
+	}
+
+	return o
+}
+
+// ExcludesFile returns true if the file at path is excluded from the stash
+// path by its extension filter.
+func (s *StashConfig) ExcludesFile(path string) bool {
+	if len(s.Extensions) == 0 {
+		return false
+	}
+
+	exts := make([]string, len(s.Extensions))
+	for i, e := range s.Extensions {
+		exts[i] = strings.TrimPrefix(e, ".")
+	}
+
+	return !fsutil.MatchExtension(path, exts)
+}
+
+// SkipsFile returns true if the file should not be scanned due to the size or
+// modification time filters of the stash path. These filters only narrow the
+// files that are scanned; files that they skip are not excluded from the stash
+// path and must not be cleaned.
+func (s *StashConfig) SkipsFile(info fs.FileInfo) bool {
+	if s.MinFileSize > 0 && info.Size() < s.MinFileSize {
+		return true
+	}
+
+	if s.ModifiedAfter != nil && !info.ModTime().After(*s.ModifiedAfter) {
+		return true
+	}
+
+	return false
 }
 
 type StashConfigs []*StashConfig
@@ -38,3 +127,14 @@ func (s StashConfigs) GetStashFromDirPath(dirPath string) *StashConfig {
 	}
 	return nil
 }
+
+// LibraryPaths returns the paths of the stash paths in the named library.
+func (s StashConfigs) LibraryPaths(name string) []string {
+	var ret []string
+	for _, f := range s {
+		if f.Library == name {
+			ret = append(ret, f.Path)
+		}
+	}
+	return ret
+}
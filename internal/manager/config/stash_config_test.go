@@ -0,0 +1,66 @@
+package config
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+func (fi testFileInfo) Name() string       { return "" }
+func (fi testFileInfo) Size() int64        { return fi.size }
+func (fi testFileInfo) Mode() fs.FileMode  { return 0644 }
+func (fi testFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi testFileInfo) IsDir() bool        { return false }
+func (fi testFileInfo) Sys() interface{}   { return nil }
+
+func TestStashConfig_ExcludesFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		stash StashConfig
+		path  string
+		want  bool
+	}{
+		{"no filters", StashConfig{}, "a.mp4", false},
+		{"extension allowed", StashConfig{Extensions: []string{"mkv", "mp4"}}, "a.MP4", false},
+		{"extension with dot allowed", StashConfig{Extensions: []string{".mp4"}}, "a.mp4", false},
+		{"extension not allowed", StashConfig{Extensions: []string{"mkv"}}, "a.mp4", true},
+		{"size and age ignored", StashConfig{MinFileSize: 100}, "a.mp4", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.stash.ExcludesFile(tt.path))
+		})
+	}
+}
+
+func TestStashConfig_SkipsFile(t *testing.T) {
+	cutoff := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := cutoff.Add(-time.Hour)
+	after := cutoff.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		stash StashConfig
+		info  testFileInfo
+		want  bool
+	}{
+		{"no filters", StashConfig{}, testFileInfo{1, before}, false},
+		{"below min size", StashConfig{MinFileSize: 100}, testFileInfo{99, after}, true},
+		{"at min size", StashConfig{MinFileSize: 100}, testFileInfo{100, after}, false},
+		{"modified before", StashConfig{ModifiedAfter: &cutoff}, testFileInfo{1, before}, true},
+		{"modified after", StashConfig{ModifiedAfter: &cutoff}, testFileInfo{1, after}, false},
+		{"extension ignored", StashConfig{Extensions: []string{"mkv"}}, testFileInfo{1, after}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.stash.SkipsFile(tt.info))
+		})
+	}
+}
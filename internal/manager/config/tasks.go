@@ -15,6 +15,8 @@ type ScanMetadataOptions struct {
 	ScanGenerateThumbnails bool `json:"scanGenerateThumbnails"`
 	// Generate image thumbnails during scan
 	ScanGenerateClipPreviews bool `json:"scanGenerateClipPreviews"`
+	// Scan the most recently modified files first
+	ScanNewestFirst bool `json:"scanNewestFirst"`
 }
 
 type AutoTagMetadataOptions struct {
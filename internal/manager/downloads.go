@@ -71,6 +71,14 @@ func (s *DownloadStore) Serve(hash string, w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// the file may be registered before it is generated by a job, so it is
+	// not removed until it has been served
+	if _, err := os.Stat(f.path); err != nil {
+		s.mutex.Unlock()
+		http.NotFound(w, r)
+		return
+	}
+
 	if !f.keep {
 		s.waitAndRemoveFile(hash, &w, r)
 	}
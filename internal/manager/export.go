@@ -0,0 +1,50 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type ExportFormatEnum string
+
+const (
+	// ExportFormatEnumJSON writes each object to its own JSON file
+	ExportFormatEnumJSON ExportFormatEnum = "JSON"
+	// ExportFormatEnumNdjson writes one newline-delimited JSON file per type
+	ExportFormatEnumNdjson ExportFormatEnum = "NDJSON"
+)
+
+var AllExportFormatEnum = []ExportFormatEnum{
+	ExportFormatEnumJSON,
+	ExportFormatEnumNdjson,
+}
+
+func (e ExportFormatEnum) IsValid() bool {
+	switch e {
+	case ExportFormatEnumJSON, ExportFormatEnumNdjson:
+		return true
+	}
+	return false
+}
+
+func (e ExportFormatEnum) String() string {
+	return string(e)
+}
+
+func (e *ExportFormatEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportFormatEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportFormatEnum", str)
+	}
+	return nil
+}
+
+func (e ExportFormatEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
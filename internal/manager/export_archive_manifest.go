@@ -0,0 +1,205 @@
+package manager
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/internal/build"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models/json"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+// ExportArchiveManifest is the manifest of an export archive, along with the
+// results of verifying the archive against it.
+type ExportArchiveManifest struct {
+	StashVersion string                          `json:"stashVersion"`
+	CreatedAt    time.Time                       `json:"createdAt"`
+	Counts       *jsonschema.ArchiveObjectCounts `json:"counts"`
+	Files        []*jsonschema.ArchiveFile       `json:"files"`
+	// InvalidFiles are the paths of the files listed in the manifest that are
+	// missing from the archive, or whose contents do not match the manifest
+	InvalidFiles []string `json:"invalidFiles"`
+}
+
+// newArchiveManifest returns the manifest of the export, without any files.
+func (t *ExportTask) newArchiveManifest() *jsonschema.ArchiveManifest {
+	version, _, _ := build.Version()
+	if version == "" {
+		version = build.VersionString()
+	}
+
+	jp := t.json.json
+	counts := t.json.counts
+
+	return &jsonschema.ArchiveManifest{
+		StashVersion: version,
+		CreatedAt:    json.JSONTime{Time: time.Now()},
+		Counts: jsonschema.ArchiveObjectCounts{
+			Scenes:     counts.get(jp.Scenes),
+			Images:     counts.get(jp.Images),
+			Galleries:  counts.get(jp.Galleries),
+			Performers: counts.get(jp.Performers),
+			Studios:    counts.get(jp.Studios),
+			Tags:       counts.get(jp.Tags),
+			Movies:     counts.get(jp.Movies),
+			Files:      counts.get(jp.Files),
+		},
+	}
+}
+
+// zipArchiveManifest adds manifest to the export zip file.
+func zipArchiveManifest(manifest *jsonschema.ArchiveManifest, z *zip.Writer) error {
+	data, err := jsonschema.EncodeArchiveManifest(manifest)
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+
+	w, err := z.Create(jsonschema.ArchiveManifestFilename)
+	if err != nil {
+		return fmt.Errorf("error creating zip entry for manifest: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("error writing manifest to zip: %w", err)
+	}
+
+	return nil
+}
+
+// writeArchiveManifest writes the manifest of the export to the export
+// directory.
+func (t *ExportTask) writeArchiveManifest() error {
+	manifest := t.newArchiveManifest()
+
+	for _, f := range t.writtenFiles() {
+		af, err := hashExportedFile(f)
+		if err != nil {
+			logger.Warnf("error hashing %s: %v", f.path, err)
+			continue
+		}
+
+		manifest.Files = append(manifest.Files, af)
+	}
+
+	return jsonschema.SaveArchiveManifestFile(filepath.Join(t.baseDir, jsonschema.ArchiveManifestFilename), manifest)
+}
+
+func hashExportedFile(f exportedFile) (jsonschema.ArchiveFile, error) {
+	i, err := os.Open(f.path)
+	if err != nil {
+		return jsonschema.ArchiveFile{}, err
+	}
+	defer i.Close()
+
+	return jsonschema.NewArchiveFile(f.archivePath, i)
+}
+
+// ErrNoArchiveManifest is returned when an export archive does not contain a
+// manifest.
+var ErrNoArchiveManifest = errors.New("archive does not contain a manifest")
+
+// ReadExportArchiveManifest reads the manifest of the uploaded export zip file,
+// and verifies the contents of the zip file against it.
+func ReadExportArchiveManifest(ctx context.Context, upload graphql.Upload) (*ExportArchiveManifest, error) {
+	// zip files require random access, so write the upload to a temporary
+	// file first
+	f, err := instance.Paths.Generated.TempFile("manifest*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			logger.Warnf("error removing %s: %v", f.Name(), err)
+		}
+	}()
+
+	size, err := io.Copy(f, upload.File)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("reading zip file: %w", err)
+	}
+
+	return readExportArchiveManifest(ctx, z)
+}
+
+func readExportArchiveManifest(ctx context.Context, z *zip.Reader) (*ExportArchiveManifest, error) {
+	files := make(map[string]*zip.File)
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+
+	mf := files[jsonschema.ArchiveManifestFilename]
+	if mf == nil {
+		return nil, ErrNoArchiveManifest
+	}
+
+	manifest, err := readZipArchiveManifest(mf)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	ret := &ExportArchiveManifest{
+		StashVersion: manifest.StashVersion,
+		CreatedAt:    manifest.CreatedAt.GetTime(),
+		Counts:       &manifest.Counts,
+		InvalidFiles: []string{},
+	}
+
+	for i := range manifest.Files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		expected := &manifest.Files[i]
+		ret.Files = append(ret.Files, expected)
+
+		if !verifyZipArchiveFile(files[expected.Path], *expected) {
+			ret.InvalidFiles = append(ret.InvalidFiles, expected.Path)
+		}
+	}
+
+	return ret, nil
+}
+
+func readZipArchiveManifest(f *zip.File) (*jsonschema.ArchiveManifest, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return jsonschema.LoadArchiveManifest(r)
+}
+
+// verifyZipArchiveFile returns true if f exists and matches expected.
+func verifyZipArchiveFile(f *zip.File, expected jsonschema.ArchiveFile) bool {
+	if f == nil {
+		return false
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return false
+	}
+	defer r.Close()
+
+	actual, err := jsonschema.NewArchiveFile(expected.Path, r)
+	if err != nil {
+		return false
+	}
+
+	return actual == expected
+}
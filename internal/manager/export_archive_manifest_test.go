@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestArchiveFile(t *testing.T, p string, contents string) jsonschema.ArchiveFile {
+	af, err := jsonschema.NewArchiveFile(p, strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("NewArchiveFile: %v", err)
+	}
+
+	return af
+}
+
+func writeTestZip(t *testing.T, files map[string]string, manifest *jsonschema.ArchiveManifest) *zip.Reader {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+
+	for p, contents := range files {
+		w, err := z.Create(p)
+		if err != nil {
+			t.Fatalf("creating %s: %v", p, err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatalf("writing %s: %v", p, err)
+		}
+	}
+
+	if manifest != nil {
+		if err := zipArchiveManifest(manifest, z); err != nil {
+			t.Fatalf("zipArchiveManifest: %v", err)
+		}
+	}
+
+	if err := z.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+
+	return r
+}
+
+func TestReadExportArchiveManifest(t *testing.T) {
+	const (
+		validPath    = "performers/valid.json"
+		modifiedPath = "performers/modified.json"
+		missingPath  = "tags/missing.json"
+	)
+
+	manifest := &jsonschema.ArchiveManifest{
+		StashVersion: "v1.0.0",
+		Counts: jsonschema.ArchiveObjectCounts{
+			Performers: 2,
+			Tags:       1,
+		},
+		Files: []jsonschema.ArchiveFile{
+			newTestArchiveFile(t, validPath, `{"name":"valid"}`),
+			newTestArchiveFile(t, modifiedPath, `{"name":"modified"}`),
+			newTestArchiveFile(t, missingPath, `{"name":"missing"}`),
+		},
+	}
+
+	z := writeTestZip(t, map[string]string{
+		validPath:    `{"name":"valid"}`,
+		modifiedPath: `{"name":"changed"}`,
+	}, manifest)
+
+	got, err := readExportArchiveManifest(context.Background(), z)
+	if err != nil {
+		t.Fatalf("readExportArchiveManifest: %v", err)
+	}
+
+	assert.Equal(t, "v1.0.0", got.StashVersion)
+	assert.Equal(t, manifest.Counts, *got.Counts)
+	assert.Len(t, got.Files, 3)
+	assert.Equal(t, []string{modifiedPath, missingPath}, got.InvalidFiles)
+}
+
+func TestReadExportArchiveManifestMissing(t *testing.T) {
+	z := writeTestZip(t, map[string]string{
+		"performers/performer.json": "{}",
+	}, nil)
+
+	_, err := readExportArchiveManifest(context.Background(), z)
+	if !errors.Is(err, ErrNoArchiveManifest) {
+		t.Errorf("expected %v, got %v", ErrNoArchiveManifest, err)
+	}
+}
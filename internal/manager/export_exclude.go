@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type ExportExcludeEnum string
+
+const (
+	// ExportExcludeEnumFingerprints omits file fingerprints
+	ExportExcludeEnumFingerprints ExportExcludeEnum = "FINGERPRINTS"
+	// ExportExcludeEnumFilePaths omits files, folders and all file paths
+	ExportExcludeEnumFilePaths ExportExcludeEnum = "FILE_PATHS"
+	// ExportExcludeEnumUrls omits URLs and social media handles
+	ExportExcludeEnumUrls ExportExcludeEnum = "URLS"
+	// ExportExcludeEnumStashIds omits stash-box ids
+	ExportExcludeEnumStashIds ExportExcludeEnum = "STASH_IDS"
+	// ExportExcludeEnumImages omits covers and other images
+	ExportExcludeEnumImages ExportExcludeEnum = "IMAGES"
+	// ExportExcludeEnumCustomFields omits custom fields
+	ExportExcludeEnumCustomFields ExportExcludeEnum = "CUSTOM_FIELDS"
+)
+
+var AllExportExcludeEnum = []ExportExcludeEnum{
+	ExportExcludeEnumFingerprints,
+	ExportExcludeEnumFilePaths,
+	ExportExcludeEnumUrls,
+	ExportExcludeEnumStashIds,
+	ExportExcludeEnumImages,
+	ExportExcludeEnumCustomFields,
+}
+
+func (e ExportExcludeEnum) IsValid() bool {
+	switch e {
+	case ExportExcludeEnumFingerprints, ExportExcludeEnumFilePaths, ExportExcludeEnumUrls, ExportExcludeEnumStashIds, ExportExcludeEnumImages, ExportExcludeEnumCustomFields:
+		return true
+	}
+	return false
+}
+
+func (e ExportExcludeEnum) String() string {
+	return string(e)
+}
+
+func (e *ExportExcludeEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportExcludeEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportExcludeEnum", str)
+	}
+	return nil
+}
+
+func (e ExportExcludeEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// exportExclusions is the set of sections omitted from an export.
+type exportExclusions map[ExportExcludeEnum]bool
+
+func newExportExclusions(exclude []ExportExcludeEnum) exportExclusions {
+	ret := make(exportExclusions)
+	for _, e := range exclude {
+		ret[e] = true
+	}
+
+	return ret
+}
+
+func (e exportExclusions) galleryRefs(refs []jsonschema.GalleryRef) []jsonschema.GalleryRef {
+	if !e[ExportExcludeEnumFilePaths] {
+		return refs
+	}
+
+	// galleries without a title cannot be referenced without a path
+	var ret []jsonschema.GalleryRef
+	for _, r := range refs {
+		if r.Title != "" {
+			ret = append(ret, jsonschema.GalleryRef{Title: r.Title})
+		}
+	}
+
+	return ret
+}
+
+func (e exportExclusions) scene(j *jsonschema.Scene) {
+	if e[ExportExcludeEnumFilePaths] {
+		j.Files = nil
+		j.Galleries = e.galleryRefs(j.Galleries)
+	}
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+		j.URLs = nil
+	}
+	if e[ExportExcludeEnumStashIds] {
+		j.StashIDs = nil
+	}
+	if e[ExportExcludeEnumImages] {
+		j.Cover = ""
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) image(j *jsonschema.Image) {
+	if e[ExportExcludeEnumFilePaths] {
+		j.Files = nil
+		j.Galleries = e.galleryRefs(j.Galleries)
+	}
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+		j.URLs = nil
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) gallery(j *jsonschema.Gallery) {
+	if e[ExportExcludeEnumFilePaths] {
+		j.ZipFiles = nil
+		j.FolderPath = ""
+		j.ZipEntries = nil
+	}
+	if e[ExportExcludeEnumFingerprints] {
+		for i := range j.ZipEntries {
+			j.ZipEntries[i].Checksum = ""
+		}
+	}
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+		j.URLs = nil
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) performer(j *jsonschema.Performer) {
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+		j.Twitter = ""
+		j.Instagram = ""
+	}
+	if e[ExportExcludeEnumStashIds] {
+		j.StashIDs = nil
+	}
+	if e[ExportExcludeEnumImages] {
+		j.Image = ""
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) studio(j *jsonschema.Studio) {
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+	}
+	if e[ExportExcludeEnumStashIds] {
+		j.StashIDs = nil
+	}
+	if e[ExportExcludeEnumImages] {
+		j.Image = ""
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) tag(j *jsonschema.Tag) {
+	if e[ExportExcludeEnumImages] {
+		j.Image = ""
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+func (e exportExclusions) movie(j *jsonschema.Movie) {
+	if e[ExportExcludeEnumUrls] {
+		j.URL = ""
+	}
+	if e[ExportExcludeEnumImages] {
+		j.FrontImage = ""
+		j.BackImage = ""
+	}
+	if e[ExportExcludeEnumCustomFields] {
+		j.CustomFields = nil
+	}
+}
+
+// basename returns the base name of path, or an empty string if file paths
+// are excluded.
+func (e exportExclusions) basename(path string) string {
+	if e[ExportExcludeEnumFilePaths] {
+		return ""
+	}
+
+	return filepath.Base(path)
+}
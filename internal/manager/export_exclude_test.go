@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportExclusionsScene(t *testing.T) {
+	newScene := func() *jsonschema.Scene {
+		return &jsonschema.Scene{
+			Title: "title",
+			URLs:  []string{"https://example.com"},
+			Files: []string{"/path/to/scene.mp4"},
+			Galleries: []jsonschema.GalleryRef{
+				{FolderPath: "/path/to/gallery"},
+				{ZipFiles: []string{"/path/to/gallery.zip"}, Title: "gallery"},
+			},
+			Cover:        "cover",
+			StashIDs:     []models.StashID{{StashID: "id", Endpoint: "endpoint"}},
+			CustomFields: map[string]interface{}{"field": "value"},
+		}
+	}
+
+	// no exclusions should leave the scene unchanged
+	s := newScene()
+	newExportExclusions(nil).scene(s)
+	assert.Equal(t, newScene(), s)
+
+	newExportExclusions(AllExportExcludeEnum).scene(s)
+	assert.Equal(t, &jsonschema.Scene{
+		Title: "title",
+		Galleries: []jsonschema.GalleryRef{
+			{Title: "gallery"},
+		},
+	}, s)
+}
+
+func TestExportExclusionsBasename(t *testing.T) {
+	assert.Equal(t, "scene.mp4", newExportExclusions(nil).basename("/path/to/scene.mp4"))
+	assert.Equal(t, "", newExportExclusions([]ExportExcludeEnum{ExportExcludeEnumFilePaths}).basename("/path/to/scene.mp4"))
+}
@@ -0,0 +1,181 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type ExportImageLayoutEnum string
+
+const (
+	// ExportImageLayoutEnumEmbedded embeds images in the object JSON as
+	// base64 encoded strings
+	ExportImageLayoutEnumEmbedded ExportImageLayoutEnum = "EMBEDDED"
+	// ExportImageLayoutEnumFiles writes images to separate files, which are
+	// referenced from the object JSON
+	ExportImageLayoutEnumFiles ExportImageLayoutEnum = "FILES"
+)
+
+var AllExportImageLayoutEnum = []ExportImageLayoutEnum{
+	ExportImageLayoutEnumEmbedded,
+	ExportImageLayoutEnumFiles,
+}
+
+func (e ExportImageLayoutEnum) IsValid() bool {
+	switch e {
+	case ExportImageLayoutEnumEmbedded, ExportImageLayoutEnumFiles:
+		return true
+	}
+	return false
+}
+
+func (e ExportImageLayoutEnum) String() string {
+	return string(e)
+}
+
+func (e *ExportImageLayoutEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportImageLayoutEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportImageLayoutEnum", str)
+	}
+	return nil
+}
+
+func (e ExportImageLayoutEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// object image subdirectories, relative to the object images directory
+const (
+	objectImagesPerformers = "performers"
+	objectImagesStudios    = "studios"
+	objectImagesTags       = "tags"
+	objectImagesMovies     = "movies"
+	objectImagesScenes     = "scenes"
+)
+
+var objectImageDirs = []string{
+	objectImagesPerformers,
+	objectImagesStudios,
+	objectImagesTags,
+	objectImagesMovies,
+	objectImagesScenes,
+}
+
+// imageExtension returns the file extension for the detected type of the
+// image data.
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/bmp":
+		return ".bmp"
+	}
+
+	return ".bin"
+}
+
+// imageBasename returns the basename of the object JSON filename fn, for use
+// as the basename of its image files.
+func imageBasename(fn string) string {
+	return strings.TrimSuffix(fn, filepath.Ext(fn))
+}
+
+// saveObjectImage writes the base64 encoded image to a file named name in the
+// dir subdirectory of the object images directory. The file extension is
+// added based on the image type. Returns the slash-separated path of the
+// written file, relative to the export directory.
+func (jp *jsonUtils) saveObjectImage(dir string, name string, encoded string) (string, error) {
+	data, err := utils.GetDataFromBase64String(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding image: %w", err)
+	}
+
+	outDir := filepath.Join(jp.json.ObjectImages, dir)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return "", err
+	}
+
+	fn := name + imageExtension(data)
+	if err := os.WriteFile(filepath.Join(outDir, fn), data, 0644); err != nil {
+		return "", err
+	}
+
+	jp.throttle.Wait(len(data))
+
+	rel, err := filepath.Rel(jp.json.Metadata, filepath.Join(outDir, fn))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// exportObjectImage moves the base64 encoded image in data to a file if the
+// FILES image layout is selected, setting file to the path of the written file
+// and clearing data. Does nothing if data is empty or the images are embedded.
+func (t *ExportTask) exportObjectImage(dir string, name string, data *string, file *string) error {
+	if t.imageLayout != ExportImageLayoutEnumFiles || *data == "" {
+		return nil
+	}
+
+	p, err := t.json.saveObjectImage(dir, name, *data)
+	if err != nil {
+		return err
+	}
+
+	*file = p
+	*data = ""
+	return nil
+}
+
+// readObjectImage returns the base64 encoded contents of the image file at
+// the slash-separated path p, relative to the import directory baseDir.
+func readObjectImage(baseDir string, p string) (string, error) {
+	fn := filepath.Join(baseDir, filepath.FromSlash(p))
+	if !fsutil.IsPathInDir(baseDir, fn) {
+		return "", fmt.Errorf("image path %q is outside of the import directory", p)
+	}
+
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.GetBase64StringFromData(data), nil
+}
+
+// loadObjectImage sets data to the contents of the image file referenced by
+// file, if the image is not embedded.
+func loadObjectImage(baseDir string, file string, data *string) error {
+	if file == "" || *data != "" {
+		return nil
+	}
+
+	encoded, err := readObjectImage(baseDir, file)
+	if err != nil {
+		return fmt.Errorf("reading image %s: %w", file, err)
+	}
+
+	*data = encoded
+	return nil
+}
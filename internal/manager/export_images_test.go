@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// a minimal PNG header, enough for content type detection
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestExportObjectImageRoundTrip(t *testing.T) {
+	baseDir := t.TempDir()
+	encoded := utils.GetBase64StringFromData(testPNG)
+
+	task := &ExportTask{
+		json:        jsonUtils{json: *paths.GetJSONPaths(baseDir)},
+		imageLayout: ExportImageLayoutEnumFiles,
+	}
+
+	data := encoded
+	var file string
+	if err := task.exportObjectImage(objectImagesPerformers, imageBasename("performer.json"), &data, &file); err != nil {
+		t.Fatalf("exportObjectImage: %v", err)
+	}
+
+	assert.Equal(t, "object_images/performers/performer.png", file)
+	assert.Empty(t, data)
+
+	if err := loadObjectImage(baseDir, file, &data); err != nil {
+		t.Fatalf("loadObjectImage: %v", err)
+	}
+
+	assert.Equal(t, encoded, data)
+}
+
+func TestExportObjectImageEmbedded(t *testing.T) {
+	task := &ExportTask{
+		imageLayout: ExportImageLayoutEnumEmbedded,
+	}
+
+	data := "image"
+	var file string
+	if err := task.exportObjectImage(objectImagesTags, "tag", &data, &file); err != nil {
+		t.Fatalf("exportObjectImage: %v", err)
+	}
+
+	assert.Equal(t, "image", data)
+	assert.Empty(t, file)
+}
+
+func TestLoadObjectImageOutsideDir(t *testing.T) {
+	var data string
+	err := loadObjectImage(t.TempDir(), "../image.png", &data)
+	assert.Error(t, err)
+	assert.Empty(t, data)
+}
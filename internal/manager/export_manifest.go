@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models/json"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type manifestType int
+
+const (
+	manifestScenes manifestType = iota
+	manifestImages
+	manifestGalleries
+	manifestPerformers
+	manifestStudios
+	manifestTags
+	manifestMovies
+)
+
+// manifestSet is the manifest entries of a single object type.
+type manifestSet struct {
+	remote  map[string]jsonschema.ManifestEntry
+	entries []jsonschema.ManifestEntry
+}
+
+func newManifestSet(remote []jsonschema.ManifestEntry) *manifestSet {
+	ret := &manifestSet{
+		remote: make(map[string]jsonschema.ManifestEntry),
+	}
+
+	for _, e := range remote {
+		ret.remote[e.Key] = e
+	}
+
+	return ret
+}
+
+// exportManifest records the objects of an export. Objects that are current
+// in the remote manifest are not exported. It is safe for concurrent use.
+type exportManifest struct {
+	mutex sync.Mutex
+
+	// if true, objects are recorded but never exported
+	manifestOnly bool
+
+	sets map[manifestType]*manifestSet
+}
+
+func newExportManifest(remote *jsonschema.Manifest) *exportManifest {
+	if remote == nil {
+		remote = &jsonschema.Manifest{}
+	}
+
+	return &exportManifest{
+		sets: map[manifestType]*manifestSet{
+			manifestScenes:     newManifestSet(remote.Scenes),
+			manifestImages:     newManifestSet(remote.Images),
+			manifestGalleries:  newManifestSet(remote.Galleries),
+			manifestPerformers: newManifestSet(remote.Performers),
+			manifestStudios:    newManifestSet(remote.Studios),
+			manifestTags:       newManifestSet(remote.Tags),
+			manifestMovies:     newManifestSet(remote.Movies),
+		},
+	}
+}
+
+// skip records the exported object obj. Returns true if the object should
+// not be exported, because it is current in the remote manifest or because
+// only the manifest is being generated. Returns false if m is nil.
+func (m *exportManifest) skip(typ manifestType, key string, updatedAt json.JSONTime, obj interface{}) bool {
+	if m == nil {
+		return false
+	}
+
+	e, err := jsonschema.NewManifestEntry(key, updatedAt, obj)
+	if err != nil {
+		logger.Warnf("error generating manifest entry for %s: %v", key, err)
+		return m.manifestOnly
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	set := m.sets[typ]
+	set.entries = append(set.entries, e)
+
+	if m.manifestOnly {
+		return true
+	}
+
+	remote, found := set.remote[key]
+	return found && key != "" && remote.IsCurrent(e)
+}
+
+// manifest returns the manifest of the recorded objects.
+func (m *exportManifest) manifest() *jsonschema.Manifest {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return &jsonschema.Manifest{
+		Scenes:     m.sets[manifestScenes].entries,
+		Images:     m.sets[manifestImages].entries,
+		Galleries:  m.sets[manifestGalleries].entries,
+		Performers: m.sets[manifestPerformers].entries,
+		Studios:    m.sets[manifestStudios].entries,
+		Tags:       m.sets[manifestTags].entries,
+		Movies:     m.sets[manifestMovies].entries,
+	}
+}
@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models/json"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportManifestSkip(t *testing.T) {
+	earlier := json.JSONTime{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	later := json.JSONTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	tag := func(name string, updatedAt json.JSONTime) *jsonschema.Tag {
+		return &jsonschema.Tag{
+			Name:      name,
+			UpdatedAt: updatedAt,
+		}
+	}
+
+	unchanged := tag("unchanged", earlier)
+	unchangedEntry, err := jsonschema.NewManifestEntry(unchanged.Name, unchanged.UpdatedAt, unchanged)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	changedEntry, err := jsonschema.NewManifestEntry("changed", earlier, tag("changed", earlier))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	remote := &jsonschema.Manifest{
+		Tags: []jsonschema.ManifestEntry{
+			unchangedEntry,
+			changedEntry,
+			{Key: "current", UpdatedAt: later},
+			{Key: "stale", UpdatedAt: earlier},
+		},
+	}
+
+	tests := []struct {
+		name string
+		obj  *jsonschema.Tag
+		want bool
+	}{
+		{"same checksum", unchanged, true},
+		{"different checksum", tag("changed", later), false},
+		{"updated before remote", tag("current", earlier), true},
+		{"updated after remote", tag("stale", later), false},
+		{"missing from remote", tag("missing", earlier), false},
+	}
+
+	m := newExportManifest(remote)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.skip(manifestTags, tt.obj.Name, tt.obj.UpdatedAt, tt.obj)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Len(t, m.manifest().Tags, len(tests))
+
+	var nilManifest *exportManifest
+	assert.False(t, nilManifest.skip(manifestTags, unchanged.Name, unchanged.UpdatedAt, unchanged))
+}
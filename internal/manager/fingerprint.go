@@ -1,20 +1,40 @@
 package manager
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/hash/blake3"
 	"github.com/stashapp/stash/pkg/hash/md5"
 	"github.com/stashapp/stash/pkg/hash/oshash"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
+// additionalFingerprints calculate the optional fingerprints of the full
+// contents of files, keyed by fingerprint type.
+var additionalFingerprints = map[string]func(ctx context.Context, src io.Reader, progress func(read int64)) (string, error){
+	models.FingerprintTypeBlake3: blake3.FromReaderContext,
+}
+
+// IsAdditionalFingerprintType returns true if t is a fingerprint type that
+// can be enabled in the fingerprint algorithms config.
+func IsAdditionalFingerprintType(t string) bool {
+	_, ok := additionalFingerprints[t]
+	return ok
+}
+
 type fingerprintCalculator struct {
 	Config *config.Instance
+
+	// verify calculates the MD5 of video and audio files that have a stored
+	// MD5, regardless of the config. The oshash only samples the start and
+	// end of the file, so it cannot detect changes elsewhere in the file.
+	verify bool
 }
 
 func (c *fingerprintCalculator) calculateOshash(f *models.BaseFile, o file.Opener) (*models.Fingerprint, error) {
@@ -41,7 +61,7 @@ func (c *fingerprintCalculator) calculateOshash(f *models.BaseFile, o file.Opene
 	}, nil
 }
 
-func (c *fingerprintCalculator) calculateMD5(o file.Opener) (*models.Fingerprint, error) {
+func (c *fingerprintCalculator) calculateMD5(ctx context.Context, o file.Opener, progress func(read int64)) (*models.Fingerprint, error) {
 	r, err := o.Open()
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
@@ -49,7 +69,7 @@ func (c *fingerprintCalculator) calculateMD5(o file.Opener) (*models.Fingerprint
 
 	defer r.Close()
 
-	hash, err := md5.FromReader(r)
+	hash, err := md5.FromReaderContext(ctx, r, progress)
 	if err != nil {
 		return nil, fmt.Errorf("calculating md5: %w", err)
 	}
@@ -60,11 +80,35 @@ func (c *fingerprintCalculator) calculateMD5(o file.Opener) (*models.Fingerprint
 	}, nil
 }
 
-func (c *fingerprintCalculator) CalculateFingerprints(f *models.BaseFile, o file.Opener, useExisting bool) ([]models.Fingerprint, error) {
+func (c *fingerprintCalculator) calculateAdditional(ctx context.Context, fpType string, o file.Opener, progress func(read int64)) (*models.Fingerprint, error) {
+	fn, ok := additionalFingerprints[fpType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported fingerprint algorithm: %s", fpType)
+	}
+
+	r, err := o.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+
+	defer r.Close()
+
+	hash, err := fn(ctx, r, progress)
+	if err != nil {
+		return nil, fmt.Errorf("calculating %s: %w", fpType, err)
+	}
+
+	return &models.Fingerprint{
+		Type:        fpType,
+		Fingerprint: hash,
+	}, nil
+}
+
+func (c *fingerprintCalculator) CalculateFingerprints(ctx context.Context, f *models.BaseFile, o file.Opener, useExisting bool, progress func(read int64)) ([]models.Fingerprint, error) {
 	var ret []models.Fingerprint
 	calculateMD5 := true
 
-	if useAsVideo(f.Path) {
+	if useAsVideo(f.Path) || useAsAudio(f.Path) {
 		var (
 			fp  *models.Fingerprint
 			err error
@@ -85,7 +129,7 @@ func (c *fingerprintCalculator) CalculateFingerprints(f *models.BaseFile, o file
 		ret = append(ret, *fp)
 
 		// only calculate MD5 if enabled in config
-		calculateMD5 = c.Config.IsCalculateMD5()
+		calculateMD5 = c.Config.IsCalculateMD5() || (c.verify && f.Fingerprints.For(models.FingerprintTypeMD5) != nil)
 	}
 
 	if calculateMD5 {
@@ -104,7 +148,24 @@ func (c *fingerprintCalculator) CalculateFingerprints(f *models.BaseFile, o file
 				logger.Infof("Calculating checksum for %s ...", f.Path)
 			}
 
-			fp, err = c.calculateMD5(o)
+			fp, err = c.calculateMD5(ctx, o, progress)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ret = append(ret, *fp)
+	}
+
+	for _, fpType := range c.Config.GetFingerprintAlgorithms() {
+		var fp *models.Fingerprint
+		if useExisting {
+			fp = f.Fingerprints.For(fpType)
+		}
+
+		if fp == nil {
+			var err error
+			fp, err = c.calculateAdditional(ctx, fpType, o, progress)
 			if err != nil {
 				return nil, err
 			}
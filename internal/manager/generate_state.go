@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/stashapp/stash/pkg/hash/md5"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+// sceneGenerateState is the generate state of a scene that was found to have
+// all of its artifacts generated.
+type sceneGenerateState struct {
+	sceneID   int
+	stateHash string
+}
+
+// generateOptionsHash returns a hash of the artifacts selected for
+// generation and the options used to generate them. Generate states are
+// recorded against this hash, so that changing the generation settings
+// causes all scenes to be examined again.
+func generateOptionsHash(input GenerateMetadataInput, previewOptions generate.PreviewOptions, fileNamingAlgo models.HashAlgorithm, maxTranscodeSize models.StreamingResolutionEnum) string {
+	// the scope of the job does not affect the generated artifacts
+	input.SceneIDs = nil
+	input.MarkerIDs = nil
+	input.SelectionSetID = nil
+	input.Overwrite = false
+	input.PreviewOptions = nil
+
+	v := struct {
+		Input            GenerateMetadataInput          `json:"input"`
+		PreviewOptions   generate.PreviewOptions        `json:"preview_options"`
+		FileNamingAlgo   models.HashAlgorithm           `json:"file_naming_algo"`
+		MaxTranscodeSize models.StreamingResolutionEnum `json:"max_transcode_size"`
+	}{
+		Input:            input,
+		PreviewOptions:   previewOptions,
+		FileNamingAlgo:   fileNamingAlgo,
+		MaxTranscodeSize: maxTranscodeSize,
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		// should not happen
+		panic(err)
+	}
+
+	return md5.FromBytes(data)
+}
+
+type fileGenerateState struct {
+	ID               models.FileID       `json:"id"`
+	Size             int64               `json:"size"`
+	ModTime          time.Time           `json:"mod_time"`
+	Fingerprints     models.Fingerprints `json:"fingerprints"`
+	Interactive      bool                `json:"interactive"`
+	InteractiveSpeed *int                `json:"interactive_speed"`
+}
+
+type markerGenerateState struct {
+	ID        int       `json:"id"`
+	Seconds   float64   `json:"seconds"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// sceneStateHash returns a hash of the state of the scene that its
+// generated artifacts depend on. Scene files must be loaded.
+func (j *GenerateJob) sceneStateHash(ctx context.Context, scene *models.Scene, previewOptions generate.PreviewOptions) (string, error) {
+	v := struct {
+		UpdatedAt      time.Time               `json:"updated_at"`
+		Files          []fileGenerateState     `json:"files"`
+		PreviewOptions generate.PreviewOptions `json:"preview_options"`
+		Markers        []markerGenerateState   `json:"markers"`
+	}{
+		UpdatedAt:      scene.UpdatedAt,
+		PreviewOptions: previewOptions,
+	}
+
+	for _, f := range scene.Files.List() {
+		v.Files = append(v.Files, fileGenerateState{
+			ID:               f.ID,
+			Size:             f.Size,
+			ModTime:          f.ModTime,
+			Fingerprints:     f.Fingerprints,
+			Interactive:      f.Interactive,
+			InteractiveSpeed: f.InteractiveSpeed,
+		})
+	}
+
+	if j.input.Markers {
+		markers, err := j.repository.SceneMarker.FindBySceneID(ctx, scene.ID)
+		if err != nil {
+			return "", err
+		}
+
+		for _, m := range markers {
+			v.Markers = append(v.Markers, markerGenerateState{
+				ID:        m.ID,
+				Seconds:   m.Seconds,
+				UpdatedAt: m.UpdatedAt,
+			})
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return md5.FromBytes(data), nil
+}
+
+// sceneUpToDate returns true if the recorded generate state of the scene
+// matches stateHash.
+func (j *GenerateJob) sceneUpToDate(ctx context.Context, sceneID int, stateHash string) bool {
+	current, err := j.repository.Scene.GetGenerateState(ctx, sceneID, j.optionsHash)
+	if err != nil {
+		logger.Errorf("error getting generate state for scene %d: %v", sceneID, err)
+		return false
+	}
+
+	return current == stateHash
+}
+
+// generatedFilesExist returns true if the generated files selected by the
+// job exist for the scene. Generated files may be removed without the
+// generate state of the scene being cleared, such as by cleaning generated
+// files, migrating hashes or deleting them manually, so the recorded state
+// alone does not mean that the files are present.
+func (j *GenerateJob) generatedFilesExist(ctx context.Context, g *generate.Generator, scene *models.Scene, options generate.PreviewOptions) bool {
+	if j.input.Sprites {
+		task := &GenerateSpriteTask{
+			Scene:               *scene,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+		}
+		if task.required() {
+			return false
+		}
+	}
+
+	if j.input.Previews {
+		task := &GeneratePreviewTask{
+			Scene:               *scene,
+			ImagePreview:        j.input.ImagePreviews,
+			Options:             options,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			generator:           g,
+		}
+		if task.required() {
+			return false
+		}
+	}
+
+	if j.input.Markers {
+		task := &GenerateMarkersTask{
+			repository:          j.repository,
+			Scene:               scene,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			ImagePreview:        j.input.MarkerImagePreviews,
+			Screenshot:          j.input.MarkerScreenshots,
+			PreviewOptions:      getMarkerPreviewOptions(j.input.MarkerPreviewOptions),
+			generator:           g,
+		}
+		if task.markersNeeded(ctx) > 0 {
+			return false
+		}
+	}
+
+	if j.input.Transcodes {
+		task := &GenerateTranscodeTask{
+			Scene:               *scene,
+			Force:               j.input.ForceTranscodes,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			g:                   g,
+		}
+		if task.required() {
+			return false
+		}
+	}
+
+	if j.input.InteractiveHeatmapsSpeeds {
+		task := &GenerateInteractiveHeatmapSpeedTask{
+			repository:          j.repository,
+			Scene:               *scene,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+		}
+		if task.required() {
+			return false
+		}
+	}
+
+	if j.input.Waveforms {
+		task := &GenerateWaveformTask{
+			Scene:               *scene,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			generator:           g,
+		}
+		if task.required() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// saveGenerateStates records the generate states of the scenes that were
+// found to be up to date.
+func (j *GenerateJob) saveGenerateStates(ctx context.Context, states []sceneGenerateState) {
+	if len(states) == 0 {
+		return
+	}
+
+	r := j.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		for _, s := range states {
+			if err := r.Scene.SetGenerateState(ctx, s.sceneID, j.optionsHash, s.stateHash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		logger.Warnf("error saving generate states: %v", err)
+	}
+}
@@ -0,0 +1,503 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+const (
+	// generateWorkLeaseTimeout is the time after which work leased to a
+	// worker is returned to the queue, if the worker has not reported that
+	// it is still working on it.
+	generateWorkLeaseTimeout = 5 * time.Minute
+
+	// generateWorkerTimeout is the time since a worker was last heard from
+	// after which it is no longer considered connected.
+	generateWorkerTimeout = time.Minute
+
+	// generateWorkPollWait is the maximum time that a request for work
+	// waits for work to become available.
+	generateWorkPollWait = 30 * time.Second
+
+	// remoteGenerateParallelTasks is the maximum number of generate tasks
+	// that are waiting on workers at once. The workers limit how many of
+	// these are run in parallel.
+	remoteGenerateParallelTasks = 16
+)
+
+// ErrGenerateWorkNotFound is returned when the work item does not exist or
+// is no longer leased to a worker.
+var ErrGenerateWorkNotFound = errors.New("generate work not found")
+
+var errNoGenerateWorkers = errors.New("no generate workers connected")
+
+type GenerateWorkType string
+
+const (
+	GenerateWorkPreview   GenerateWorkType = "preview"
+	GenerateWorkSprite    GenerateWorkType = "sprite"
+	GenerateWorkPhash     GenerateWorkType = "phash"
+	GenerateWorkTranscode GenerateWorkType = "transcode"
+)
+
+// GenerateWorkItem is a generate task for a scene that is run by a remote
+// worker. The worker reads the scene file from the primary instance, and
+// uploads the generated files to it.
+type GenerateWorkItem struct {
+	ID          string           `json:"id"`
+	Type        GenerateWorkType `json:"type"`
+	Description string           `json:"description"`
+	SceneID     int              `json:"scene_id"`
+	// Hash is used to name the generated files
+	Hash string `json:"hash"`
+
+	Duration   float64 `json:"duration"`
+	VideoCodec string  `json:"video_codec"`
+	AudioCodec string  `json:"audio_codec"`
+	Format     string  `json:"format"`
+
+	PreviewVideo   bool                     `json:"preview_video,omitempty"`
+	PreviewImage   bool                     `json:"preview_image,omitempty"`
+	PreviewOptions *generate.PreviewOptions `json:"preview_options,omitempty"`
+
+	TranscodeSize  models.StreamingResolutionEnum `json:"transcode_size,omitempty"`
+	ForceTranscode bool                           `json:"force_transcode,omitempty"`
+}
+
+// GenerateWorkResult is reported by a worker once it has finished the work
+// and uploaded the generated files.
+type GenerateWorkResult struct {
+	Error string `json:"error,omitempty"`
+	// Phash is the hexadecimal perceptual hash generated by phash work.
+	Phash string `json:"phash,omitempty"`
+}
+
+func newSceneWorkItem(workType GenerateWorkType, description string, scene *models.Scene, sceneHash string) GenerateWorkItem {
+	ret := GenerateWorkItem{
+		Type:        workType,
+		Description: description,
+		SceneID:     scene.ID,
+		Hash:        sceneHash,
+	}
+
+	if f := scene.Files.Primary(); f != nil {
+		ret.Duration = f.Duration
+		ret.VideoCodec = f.VideoCodec
+		ret.AudioCodec = f.AudioCodec
+		ret.Format = f.Format
+	}
+
+	return ret
+}
+
+// files returns the names of the files generated by the work, mapped to
+// their paths in the generated directory of p.
+func (i GenerateWorkItem) files(p *paths.Paths) map[string]string {
+	ret := make(map[string]string)
+
+	switch i.Type {
+	case GenerateWorkPreview:
+		if i.PreviewVideo {
+			ret["video"] = p.Scene.GetVideoPreviewPath(i.Hash)
+		}
+		if i.PreviewImage {
+			if i.PreviewOptions != nil && i.PreviewOptions.ImageFormat == models.PreviewImageFormatAvif {
+				ret["image"] = p.Scene.GetAvifPreviewPath(i.Hash)
+			} else {
+				ret["image"] = p.Scene.GetWebpPreviewPath(i.Hash)
+			}
+		}
+	case GenerateWorkSprite:
+		ret["sprite"] = p.Scene.GetSpriteImageFilePath(i.Hash)
+		ret["vtt"] = p.Scene.GetSpriteVttFilePath(i.Hash)
+	case GenerateWorkTranscode:
+		ret["transcode"] = p.Scene.GetTranscodePath(i.Hash)
+	}
+
+	return ret
+}
+
+type generateWork struct {
+	item GenerateWorkItem
+	// source is the path of the scene file
+	source string
+	// files are the paths that the generated files are written to, keyed
+	// by name
+	files  map[string]string
+	result chan GenerateWorkResult
+
+	leased  bool
+	expires time.Time
+}
+
+// generateDispatcher queues generate work for remote workers. Workers poll
+// for work, which is leased to them until they complete it or stop
+// reporting progress.
+type generateDispatcher struct {
+	mutex  sync.Mutex
+	queue  []*generateWork
+	work   map[string]*generateWork
+	notify chan struct{}
+	// lastSeen is the time that a worker last asked for work, reported
+	// progress or uploaded a file
+	lastSeen time.Time
+}
+
+func newGenerateDispatcher() *generateDispatcher {
+	return &generateDispatcher{
+		work:   make(map[string]*generateWork),
+		notify: make(chan struct{}),
+	}
+}
+
+// connected returns true if a worker has been heard from recently.
+func (d *generateDispatcher) connected(now time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.connectedLocked(now)
+}
+
+func (d *generateDispatcher) connectedLocked(now time.Time) bool {
+	return !d.lastSeen.IsZero() && now.Sub(d.lastSeen) < generateWorkerTimeout
+}
+
+// signalLocked wakes the workers waiting for work.
+func (d *generateDispatcher) signalLocked() {
+	close(d.notify)
+	d.notify = make(chan struct{})
+}
+
+// expireLocked returns work whose lease has expired to the queue.
+func (d *generateDispatcher) expireLocked(now time.Time) {
+	for _, w := range d.work {
+		if w.leased && now.After(w.expires) {
+			logger.Warnf("[worker] lease of %q expired, returning it to the queue", w.item.Description)
+			w.leased = false
+			d.queue = append(d.queue, w)
+			d.signalLocked()
+		}
+	}
+}
+
+func (d *generateDispatcher) removeLocked(id string) {
+	delete(d.work, id)
+	for i, w := range d.queue {
+		if w.item.ID == id {
+			d.queue = append(d.queue[:i], d.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+// submit queues the work and waits for a worker to complete it. Returns
+// errNoGenerateWorkers if the workers disconnect before the work is leased.
+func (d *generateDispatcher) submit(ctx context.Context, item GenerateWorkItem, source string, files map[string]string) (*GenerateWorkResult, error) {
+	id, err := hash.GenerateRandomKey(16)
+	if err != nil {
+		return nil, err
+	}
+	item.ID = id
+
+	w := &generateWork{
+		item:   item,
+		source: source,
+		files:  files,
+		result: make(chan GenerateWorkResult, 1),
+	}
+
+	d.mutex.Lock()
+	d.work[id] = w
+	d.queue = append(d.queue, w)
+	d.signalLocked()
+	d.mutex.Unlock()
+
+	ticker := time.NewTicker(generateWorkerTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case result := <-w.result:
+			return &result, nil
+		case <-ctx.Done():
+			d.mutex.Lock()
+			d.removeLocked(id)
+			d.mutex.Unlock()
+			return nil, ctx.Err()
+		case now := <-ticker.C:
+			d.mutex.Lock()
+			d.expireLocked(now)
+			withdraw := !w.leased && !d.connectedLocked(now)
+			if withdraw {
+				d.removeLocked(id)
+			}
+			d.mutex.Unlock()
+
+			if withdraw {
+				return nil, errNoGenerateWorkers
+			}
+		}
+	}
+}
+
+// lease returns the next queued work, waiting up to wait for work to be
+// queued. Returns nil if no work is available.
+func (d *generateDispatcher) lease(ctx context.Context, wait time.Duration) *GenerateWorkItem {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		d.mutex.Lock()
+		now := time.Now()
+		d.lastSeen = now
+		d.expireLocked(now)
+
+		if len(d.queue) > 0 {
+			w := d.queue[0]
+			d.queue = d.queue[1:]
+			w.leased = true
+			w.expires = now.Add(generateWorkLeaseTimeout)
+			item := w.item
+			d.mutex.Unlock()
+			return &item
+		}
+
+		notify := d.notify
+		d.mutex.Unlock()
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// leased returns the leased work with the given id, extending its lease.
+// The worker is considered connected while it is working.
+func (d *generateDispatcher) leased(id string) (*generateWork, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	w, found := d.work[id]
+	if !found || !w.leased {
+		return nil, ErrGenerateWorkNotFound
+	}
+
+	now := time.Now()
+	d.lastSeen = now
+	w.expires = now.Add(generateWorkLeaseTimeout)
+	return w, nil
+}
+
+// complete reports the result of the leased work to its submitter.
+func (d *generateDispatcher) complete(id string, result GenerateWorkResult) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	w, found := d.work[id]
+	if !found || !w.leased {
+		return ErrGenerateWorkNotFound
+	}
+
+	delete(d.work, id)
+	w.result <- result
+	return nil
+}
+
+// LeaseGenerateWork returns the next generate work for a remote worker,
+// waiting for work to be queued if there is none. Returns nil if no work
+// became available.
+func (s *Manager) LeaseGenerateWork(ctx context.Context) *GenerateWorkItem {
+	return s.generateDispatcher.lease(ctx, generateWorkPollWait)
+}
+
+// GenerateWorkSource returns the path of the scene file of the leased work.
+func (s *Manager) GenerateWorkSource(id string) (string, error) {
+	w, err := s.generateDispatcher.leased(id)
+	if err != nil {
+		return "", err
+	}
+
+	return w.source, nil
+}
+
+// HeartbeatGenerateWork extends the lease of the work.
+func (s *Manager) HeartbeatGenerateWork(id string) error {
+	_, err := s.generateDispatcher.leased(id)
+	return err
+}
+
+// SaveGenerateWorkFile writes a file generated by the leased work to the
+// generated directory.
+func (s *Manager) SaveGenerateWorkFile(id string, name string, r io.Reader) error {
+	w, err := s.generateDispatcher.leased(id)
+	if err != nil {
+		return err
+	}
+
+	outputPath, found := w.files[name]
+	if !found {
+		return fmt.Errorf("%w: no file named %q", ErrGenerateWorkNotFound, name)
+	}
+
+	dir := filepath.Dir(outputPath)
+	if err := fsutil.EnsureDirAll(dir); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that a failed upload does not
+	// leave a partial file in place
+	tmpFile, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+
+	_, err = io.Copy(tmpFile, r)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// the upload may take longer than the worker timeout, so refresh
+		// the lease and liveness once it is received
+		_, err = s.generateDispatcher.leased(id)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, outputPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	return nil
+}
+
+// CompleteGenerateWork reports the result of the leased work.
+func (s *Manager) CompleteGenerateWork(id string, result GenerateWorkResult) error {
+	return s.generateDispatcher.complete(id, result)
+}
+
+// remoteGenerateTask runs a generate task on a remote worker if one is
+// connected, and runs the task locally otherwise.
+type remoteGenerateTask struct {
+	Task
+
+	item   GenerateWorkItem
+	source string
+
+	// apply applies the result of work that does not generate files
+	apply func(ctx context.Context, result *GenerateWorkResult) error
+}
+
+func (t *remoteGenerateTask) Start(ctx context.Context) {
+	d := instance.generateDispatcher
+	if !d.connected(time.Now()) {
+		t.Task.Start(ctx)
+		return
+	}
+
+	result, err := d.submit(ctx, t.item, t.source, t.item.files(instance.Paths))
+	if errors.Is(err, errNoGenerateWorkers) {
+		logger.Warnf("[worker] no workers connected, generating locally")
+		t.Task.Start(ctx)
+		return
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("[worker] error dispatching %q: %v", t.item.Description, err)
+		}
+		return
+	}
+
+	if result.Error != "" {
+		logger.Errorf("[worker] error generating %q: %s", t.item.Description, result.Error)
+		return
+	}
+
+	if t.apply != nil {
+		if err := t.apply(ctx, result); err != nil {
+			logger.Errorf("[worker] error applying result of %q: %v", t.item.Description, err)
+		}
+	}
+}
+
+func newRemoteSpriteTask(t *GenerateSpriteTask) *remoteGenerateTask {
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	return &remoteGenerateTask{
+		Task:   t,
+		item:   newSceneWorkItem(GenerateWorkSprite, t.GetDescription(), &t.Scene, sceneHash),
+		source: t.Scene.Path,
+	}
+}
+
+func newRemotePreviewTask(t *GeneratePreviewTask) *remoteGenerateTask {
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	item := newSceneWorkItem(GenerateWorkPreview, t.GetDescription(), &t.Scene, sceneHash)
+	item.PreviewVideo = t.videoPreviewRequired()
+	item.PreviewImage = t.imagePreviewRequired()
+	options := t.Options
+	item.PreviewOptions = &options
+
+	return &remoteGenerateTask{
+		Task:   t,
+		item:   item,
+		source: t.Scene.Path,
+	}
+}
+
+func newRemoteTranscodeTask(t *GenerateTranscodeTask) *remoteGenerateTask {
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	item := newSceneWorkItem(GenerateWorkTranscode, t.GetDescription(), &t.Scene, sceneHash)
+	item.TranscodeSize = instance.Config.GetMaxTranscodeSize()
+	item.ForceTranscode = t.Force
+
+	return &remoteGenerateTask{
+		Task:   t,
+		item:   item,
+		source: t.Scene.Path,
+	}
+}
+
+func newRemotePhashTask(t *GeneratePhashTask, sceneID int) *remoteGenerateTask {
+	item := GenerateWorkItem{
+		Type:        GenerateWorkPhash,
+		Description: t.GetDescription(),
+		SceneID:     sceneID,
+		Duration:    t.File.Duration,
+		VideoCodec:  t.File.VideoCodec,
+		AudioCodec:  t.File.AudioCodec,
+		Format:      t.File.Format,
+	}
+
+	return &remoteGenerateTask{
+		Task:   t,
+		item:   item,
+		source: t.File.Path,
+		apply: func(ctx context.Context, result *GenerateWorkResult) error {
+			phash, err := strconv.ParseUint(result.Phash, 16, 64)
+			if err != nil {
+				return fmt.Errorf("invalid phash %q: %w", result.Phash, err)
+			}
+
+			return t.setPhash(ctx, phash)
+		},
+	}
+}
@@ -0,0 +1,468 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/remeh/sizedwaitgroup"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/hash/videophash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stashapp/stash/pkg/scene/generate"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+const (
+	// generateWorkerIdleInterval is the time between checks of whether
+	// worker mode is configured.
+	generateWorkerIdleInterval = time.Minute
+
+	// generateWorkerRetryInterval is the time waited after failing to reach
+	// the primary instance.
+	generateWorkerRetryInterval = 30 * time.Second
+
+	// generateWorkerHeartbeatInterval is the time between reports to the
+	// primary instance that work is still in progress.
+	generateWorkerHeartbeatInterval = time.Minute
+
+	generateWorkerRequestTimeout = time.Minute
+)
+
+// generateWorker runs generate work for a primary stash instance when
+// worker mode is configured. The scene files are read from the primary
+// instance and the generated files are uploaded to it.
+type generateWorker struct {
+	config *config.Instance
+	client *http.Client
+}
+
+func newGenerateWorker(cfg *config.Instance) *generateWorker {
+	return &generateWorker{
+		config: cfg,
+		client: &http.Client{},
+	}
+}
+
+// primaryURL returns the URL of the primary instance, or an empty string if
+// worker mode is not configured.
+func (w *generateWorker) primaryURL() string {
+	// the generated directory is needed to hold generated files until they
+	// are uploaded
+	if w.config.GetGeneratedPath() == "" {
+		return ""
+	}
+
+	return w.config.GetGenerateWorkerPrimaryURL()
+}
+
+// run leases and runs work from the primary instance until ctx is done.
+func (w *generateWorker) run(ctx context.Context) {
+	wg := sizedwaitgroup.New(w.config.GetParallelTasksWithAutoDetection())
+	defer wg.Wait()
+
+	logged := false
+	for ctx.Err() == nil {
+		primary := w.primaryURL()
+		if primary == "" {
+			logged = false
+			waitOrDone(ctx, generateWorkerIdleInterval)
+			continue
+		}
+
+		if !logged {
+			logger.Infof("[worker] generating for %s", primary)
+			logged = true
+		}
+
+		// wait for a free slot before leasing, so that leased work is
+		// started immediately
+		if err := wg.AddWithContext(ctx); err != nil {
+			return
+		}
+
+		item, err := w.lease(ctx, primary)
+		if err != nil {
+			wg.Done()
+			if ctx.Err() == nil {
+				logger.Warnf("[worker] error requesting work from %s: %v", primary, err)
+				waitOrDone(ctx, generateWorkerRetryInterval)
+			}
+			continue
+		}
+
+		if item == nil {
+			wg.Done()
+			continue
+		}
+
+		go func() {
+			defer wg.Done()
+			w.work(ctx, primary, item)
+		}()
+	}
+}
+
+func waitOrDone(ctx context.Context, d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-ctx.Done():
+	}
+}
+
+func (w *generateWorker) newRequest(ctx context.Context, method string, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(session.ApiKeyHeader, w.config.GetGenerateWorkerAPIKey())
+	return req, nil
+}
+
+func (w *generateWorker) do(req *http.Request) (*http.Response, error) {
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return resp, nil
+}
+
+// lease requests work from the primary instance. Returns nil if no work is
+// available.
+func (w *generateWorker) lease(ctx context.Context, primary string) (*GenerateWorkItem, error) {
+	// the primary holds the request open while waiting for work
+	reqCtx, cancel := context.WithTimeout(ctx, generateWorkPollWait+generateWorkerRequestTimeout)
+	defer cancel()
+
+	req, err := w.newRequest(reqCtx, http.MethodPost, primary+"/worker/lease", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := w.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var ret GenerateWorkItem
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (w *generateWorker) itemURL(primary string, item *GenerateWorkItem) string {
+	return primary + "/worker/items/" + url.PathEscape(item.ID)
+}
+
+// serveSource serves the scene file of the work on a local address, proxying
+// requests to the primary instance with the API key header. ffmpeg does not
+// send our headers, and passing the API key in the URL would expose it in
+// the ffmpeg command line and logs. Returns the local URL of the scene file
+// and a function that stops serving it.
+func (w *generateWorker) serveSource(primary string, item *GenerateWorkItem) (string, func(), error) {
+	target, err := url.Parse(w.itemURL(primary, item) + "/source")
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the local URL is only valid for this work
+	token, err := hash.GenerateRandomKey(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+
+	apiKey := w.config.GetGenerateWorkerAPIKey()
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			r.URL = target
+			r.Host = target.Host
+			r.Header.Set(session.ApiKeyHeader, apiKey)
+		},
+	}
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/"+token {
+				http.NotFound(rw, r)
+				return
+			}
+			proxy.ServeHTTP(rw, r)
+		}),
+		ReadHeaderTimeout: generateWorkerRequestTimeout,
+	}
+
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warnf("[worker] error serving source of %q: %v", item.Description, err)
+		}
+	}()
+
+	source := fmt.Sprintf("http://%s/%s", l.Addr().String(), token)
+	return source, func() { _ = srv.Close() }, nil
+}
+
+// work runs the leased work and reports the result to the primary instance.
+func (w *generateWorker) work(ctx context.Context, primary string, item *GenerateWorkItem) {
+	logger.Infof("[worker] %s", item.Description)
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.heartbeat(workCtx, primary, item)
+
+	result := w.generate(workCtx, primary, item)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if result.Error != "" {
+		logger.Errorf("[worker] error generating %q: %s", item.Description, result.Error)
+	}
+
+	if err := w.complete(ctx, primary, item, result); err != nil && ctx.Err() == nil {
+		logger.Errorf("[worker] error reporting result of %q: %v", item.Description, err)
+	}
+}
+
+func (w *generateWorker) heartbeat(ctx context.Context, primary string, item *GenerateWorkItem) {
+	ticker := time.NewTicker(generateWorkerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reqCtx, cancel := context.WithTimeout(ctx, generateWorkerRequestTimeout)
+			req, err := w.newRequest(reqCtx, http.MethodPost, w.itemURL(primary, item)+"/heartbeat", nil)
+			if err == nil {
+				var resp *http.Response
+				resp, err = w.do(req)
+				if err == nil {
+					resp.Body.Close()
+				}
+			}
+			cancel()
+
+			if err != nil && ctx.Err() == nil {
+				logger.Warnf("[worker] error reporting progress of %q: %v", item.Description, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// generate runs the work using the local generate tasks, reading from the
+// primary instance, and uploads the generated files.
+func (w *generateWorker) generate(ctx context.Context, primary string, item *GenerateWorkItem) GenerateWorkResult {
+	source, stop, err := w.serveSource(primary, item)
+	if err != nil {
+		return GenerateWorkResult{Error: err.Error()}
+	}
+	defer stop()
+
+	f := &models.VideoFile{
+		BaseFile: &models.BaseFile{
+			Path: source,
+		},
+		Duration:   item.Duration,
+		VideoCodec: item.VideoCodec,
+		AudioCodec: item.AudioCodec,
+		Format:     item.Format,
+	}
+
+	// the generated files are named using the hash of the primary instance
+	const fileNamingAlgorithm = models.HashAlgorithmOshash
+	scene := models.Scene{
+		ID:     item.SceneID,
+		Path:   source,
+		OSHash: item.Hash,
+		Files:  models.NewRelatedVideoFiles([]*models.VideoFile{f}),
+	}
+
+	// generate into a directory of our own, so that the generated files of
+	// this instance are not touched
+	workDir, err := instance.Paths.Generated.TempDir("worker")
+	if err != nil {
+		return GenerateWorkResult{Error: err.Error()}
+	}
+	defer func() {
+		if err := fsutil.RemoveDir(workDir); err != nil {
+			logger.Warnf("[worker] could not remove %s: %v", workDir, err)
+		}
+	}()
+
+	workPaths := paths.NewPaths(workDir, "")
+	files := item.files(&workPaths)
+	if err := workPaths.Generated.EnsureTmpDir(); err != nil {
+		return GenerateWorkResult{Error: err.Error()}
+	}
+	for _, p := range files {
+		if err := fsutil.EnsureDirAll(filepath.Dir(p)); err != nil {
+			return GenerateWorkResult{Error: err.Error()}
+		}
+	}
+
+	g := &generate.Generator{
+		Encoder:      instance.FFMPEG,
+		FFMpegConfig: instance.Config,
+		LockManager:  instance.ReadLockManager,
+		MarkerPaths:  workPaths.SceneMarkers,
+		ScenePaths:   workPaths.Scene,
+		Overwrite:    true,
+	}
+
+	switch item.Type {
+	case GenerateWorkPreview:
+		if item.PreviewOptions == nil {
+			return GenerateWorkResult{Error: "missing preview options"}
+		}
+
+		// the image preview is generated from the video preview, so the
+		// video preview is always generated
+		videoExists := false
+		imageExists := !item.PreviewImage
+		task := &GeneratePreviewTask{
+			Scene:               scene,
+			ImagePreview:        item.PreviewImage,
+			Options:             *item.PreviewOptions,
+			fileNamingAlgorithm: fileNamingAlgorithm,
+			generator:           g,
+			videoPreviewExists:  &videoExists,
+			imagePreviewExists:  &imageExists,
+		}
+		task.Start(ctx)
+	case GenerateWorkSprite:
+		videoFile, err := instance.FFProbe.NewVideoFile(source)
+		if err != nil {
+			return GenerateWorkResult{Error: err.Error()}
+		}
+
+		generator, err := NewSpriteGenerator(*videoFile, item.Hash, files["sprite"], files["vtt"], 9, 9)
+		if err != nil {
+			return GenerateWorkResult{Error: err.Error()}
+		}
+		generator.Overwrite = true
+
+		if err := generator.Generate(); err != nil {
+			return GenerateWorkResult{Error: err.Error()}
+		}
+	case GenerateWorkTranscode:
+		task := &GenerateTranscodeTask{
+			Scene:               scene,
+			Overwrite:           true,
+			Force:               item.ForceTranscode,
+			fileNamingAlgorithm: fileNamingAlgorithm,
+			maxTranscodeSize:    item.TranscodeSize,
+			g:                   g,
+		}
+		task.Start(ctx)
+	case GenerateWorkPhash:
+		phash, err := videophash.Generate(instance.FFMPEG, f)
+		if err != nil {
+			return GenerateWorkResult{Error: err.Error()}
+		}
+
+		return GenerateWorkResult{Phash: strconv.FormatUint(*phash, 16)}
+	default:
+		return GenerateWorkResult{Error: fmt.Sprintf("unsupported work type %q", item.Type)}
+	}
+
+	if ctx.Err() != nil {
+		return GenerateWorkResult{Error: ctx.Err().Error()}
+	}
+
+	// the tasks log rather than return their errors
+	for name, p := range files {
+		if exists, _ := fsutil.FileExists(p); !exists {
+			return GenerateWorkResult{Error: fmt.Sprintf("%s was not generated, see the worker log for details", name)}
+		}
+	}
+
+	for name, p := range files {
+		if err := w.upload(ctx, primary, item, name, p); err != nil {
+			return GenerateWorkResult{Error: fmt.Sprintf("uploading %s: %v", name, err)}
+		}
+	}
+
+	return GenerateWorkResult{}
+}
+
+func (w *generateWorker) upload(ctx context.Context, primary string, item *GenerateWorkItem, name string, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := w.newRequest(ctx, http.MethodPut, w.itemURL(primary, item)+"/files/"+url.PathEscape(name), f)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (w *generateWorker) complete(ctx context.Context, primary string, item *GenerateWorkItem, result GenerateWorkResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, generateWorkerRequestTimeout)
+	defer cancel()
+
+	req, err := w.newRequest(reqCtx, http.MethodPost, w.itemURL(primary, item)+"/complete", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDispatcher_LeaseComplete(t *testing.T) {
+	d := newGenerateDispatcher()
+	ctx := context.Background()
+
+	assert.False(t, d.connected(time.Now()))
+	assert.Nil(t, d.lease(ctx, time.Millisecond))
+	assert.True(t, d.connected(time.Now()))
+
+	type submitted struct {
+		result *GenerateWorkResult
+		err    error
+	}
+	done := make(chan submitted, 1)
+	go func() {
+		result, err := d.submit(ctx, GenerateWorkItem{Type: GenerateWorkPhash, SceneID: 1}, "/a.mp4", nil)
+		done <- submitted{result, err}
+	}()
+
+	item := d.lease(ctx, time.Second)
+	if !assert.NotNil(t, item) {
+		return
+	}
+	assert.Equal(t, GenerateWorkPhash, item.Type)
+	assert.NotEmpty(t, item.ID)
+
+	w, err := d.leased(item.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/a.mp4", w.source)
+	}
+
+	assert.NoError(t, d.complete(item.ID, GenerateWorkResult{Phash: "ff"}))
+
+	s := <-done
+	assert.NoError(t, s.err)
+	assert.Equal(t, &GenerateWorkResult{Phash: "ff"}, s.result)
+
+	_, err = d.leased(item.ID)
+	assert.ErrorIs(t, err, ErrGenerateWorkNotFound)
+	assert.ErrorIs(t, d.complete(item.ID, GenerateWorkResult{}), ErrGenerateWorkNotFound)
+}
+
+func TestGenerateDispatcher_Expire(t *testing.T) {
+	d := newGenerateDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.submit(ctx, GenerateWorkItem{Type: GenerateWorkSprite}, "/a.mp4", nil)
+		done <- err
+	}()
+
+	item := d.lease(ctx, time.Second)
+	if !assert.NotNil(t, item) {
+		cancel()
+		return
+	}
+
+	// leased work is not leased again until its lease expires
+	assert.Nil(t, d.lease(ctx, time.Millisecond))
+
+	d.mutex.Lock()
+	d.expireLocked(time.Now().Add(generateWorkLeaseTimeout + time.Second))
+	d.mutex.Unlock()
+
+	_, err := d.leased(item.ID)
+	assert.ErrorIs(t, err, ErrGenerateWorkNotFound)
+
+	again := d.lease(ctx, time.Second)
+	if assert.NotNil(t, again) {
+		assert.Equal(t, item.ID, again.ID)
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	d.mutex.Lock()
+	assert.Empty(t, d.work)
+	assert.Empty(t, d.queue)
+	d.mutex.Unlock()
+}
+
+func TestGenerateDispatcher_Heartbeat(t *testing.T) {
+	d := newGenerateDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_, _ = d.submit(ctx, GenerateWorkItem{Type: GenerateWorkSprite}, "/a.mp4", nil)
+	}()
+
+	item := d.lease(ctx, time.Second)
+	if !assert.NotNil(t, item) {
+		return
+	}
+
+	// a worker busy with work is still connected after the worker timeout
+	d.mutex.Lock()
+	d.lastSeen = time.Now().Add(-2 * generateWorkerTimeout)
+	d.mutex.Unlock()
+	assert.False(t, d.connected(time.Now()))
+
+	_, err := d.leased(item.ID)
+	assert.NoError(t, err)
+	assert.True(t, d.connected(time.Now()))
+}
@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const generatedAccessFlushInterval = 5 * time.Minute
+
+// GeneratedAccessStore stores the times that generated files were last
+// accessed, keyed by their path relative to the generated directory.
+type GeneratedAccessStore interface {
+	SetGeneratedAccessTimes(ctx context.Context, times map[string]time.Time) error
+	GetGeneratedAccessTimes(ctx context.Context) (map[string]time.Time, error)
+	DeleteGeneratedAccessTimes(ctx context.Context, paths []string) error
+}
+
+// generatedAccessRecorder records the times that generated files are served.
+// Access times are held in memory and written to the database periodically,
+// so that serving a file does not require a write transaction.
+type generatedAccessRecorder struct {
+	repository models.Repository
+	store      GeneratedAccessStore
+
+	mu       sync.Mutex
+	accessed map[string]time.Time
+}
+
+func newGeneratedAccessRecorder(repository models.Repository, store GeneratedAccessStore) *generatedAccessRecorder {
+	return &generatedAccessRecorder{
+		repository: repository,
+		store:      store,
+		accessed:   make(map[string]time.Time),
+	}
+}
+
+// generatedRelPath returns path relative to the generated directory, using
+// forward slashes. Returns false if path is not within the generated
+// directory.
+func generatedRelPath(generatedPath string, path string) (string, bool) {
+	if generatedPath == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(generatedPath, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+func (r *generatedAccessRecorder) record(generatedPath string, path string, t time.Time) {
+	rel, ok := generatedRelPath(generatedPath, path)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accessed[rel] = t
+}
+
+// flush writes the recorded access times to the database. If writing fails,
+// the access times are kept to be written on the next flush.
+func (r *generatedAccessRecorder) flush(ctx context.Context) error {
+	r.mu.Lock()
+	pending := r.accessed
+	r.accessed = make(map[string]time.Time)
+	r.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := r.repository.WithTxn(ctx, func(ctx context.Context) error {
+		return r.store.SetGeneratedAccessTimes(ctx, pending)
+	}); err != nil {
+		r.mu.Lock()
+		for p, t := range pending {
+			// keep newer accesses recorded since the flush began
+			if _, found := r.accessed[p]; !found {
+				r.accessed[p] = t
+			}
+		}
+		r.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// run flushes the recorded access times periodically until ctx is done.
+func (r *generatedAccessRecorder) run(ctx context.Context, ready func() error) {
+	ticker := time.NewTicker(generatedAccessFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if ready() != nil {
+				continue
+			}
+
+			if err := r.flush(ctx); err != nil {
+				logger.Warnf("error recording generated file access times: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RecordGeneratedAccess records that the generated file at path was served,
+// so that it is not removed by a clean of unused generated files. Paths
+// outside the generated directory are ignored.
+func (s *Manager) RecordGeneratedAccess(path string) {
+	s.generatedAccess.record(s.Config.GetGeneratedPath(), path, time.Now())
+}
@@ -18,6 +18,7 @@ import (
 
 type InteractiveHeatmapSpeedGenerator struct {
 	InteractiveSpeed int
+	Statistics       ScriptStatistics
 	Funscript        Script
 	Width            int
 	Height           int
@@ -49,6 +50,17 @@ type Action struct {
 	Speed     float64
 }
 
+// ScriptStatistics summarises the actions of a script.
+type ScriptStatistics struct {
+	// AverageSpeed is the mean speed of the actions, in positions per second.
+	AverageSpeed int
+	// MaxSpeed is the speed of the fastest action, in positions per second.
+	MaxSpeed int
+	// ActionDensity is the number of actions per minute, between the first
+	// and last action.
+	ActionDensity int
+}
+
 type GradientTable []struct {
 	Col    colorful.Color
 	Pos    float64
@@ -85,6 +97,7 @@ func (g *InteractiveHeatmapSpeedGenerator) Generate(funscriptPath string, heatma
 		return err
 	}
 
+	g.Statistics = g.Funscript.CalculateStatistics()
 	g.InteractiveSpeed = g.Funscript.CalculateMedian()
 
 	return nil
@@ -202,6 +215,34 @@ func (g *InteractiveHeatmapSpeedGenerator) RenderHeatmap(heatmapPath string, sce
 	return err
 }
 
+// CalculateStatistics returns the statistics of the actions of the script.
+// The actions must be sorted by time, and have their speed updated first.
+func (funscript *Script) CalculateStatistics() ScriptStatistics {
+	var ret ScriptStatistics
+	if len(funscript.Actions) < 2 {
+		return ret
+	}
+
+	// the first action has no speed, since there is no movement before it
+	moves := funscript.Actions[1:]
+
+	var total, maxSpeed float64
+	for _, a := range moves {
+		total += a.Speed
+		maxSpeed = math.Max(maxSpeed, a.Speed)
+	}
+
+	ret.AverageSpeed = int(math.Round(total / float64(len(moves))))
+	ret.MaxSpeed = int(math.Round(maxSpeed))
+
+	span := funscript.Actions[len(funscript.Actions)-1].At - funscript.Actions[0].At
+	if span > 0 {
+		ret.ActionDensity = int(math.Round(float64(len(funscript.Actions)) / (float64(span) / 60000)))
+	}
+
+	return ret
+}
+
 func (funscript *Script) CalculateMedian() int {
 	sort.Slice(funscript.Actions, func(i, j int) bool {
 		return funscript.Actions[i].Speed < funscript.Actions[j].Speed
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript_CalculateStatistics(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []Action
+		want    ScriptStatistics
+	}{
+		{
+			"no actions",
+			nil,
+			ScriptStatistics{},
+		},
+		{
+			"single action",
+			[]Action{{At: 1000, Pos: 50}},
+			ScriptStatistics{},
+		},
+		{
+			"strokes",
+			[]Action{
+				{At: 0, Pos: 0},
+				{At: 500, Pos: 100},
+				{At: 1000, Pos: 0},
+				{At: 2000, Pos: 50},
+			},
+			// speeds of 200, 200 and 50
+			ScriptStatistics{
+				AverageSpeed:  150,
+				MaxSpeed:      200,
+				ActionDensity: 120,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Script{Actions: tt.actions}
+			s.UpdateIntensityAndSpeed()
+			assert.Equal(t, tt.want, s.CalculateStatistics())
+		})
+	}
+}
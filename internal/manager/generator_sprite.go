@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"image"
 	"math"
+	"strings"
 
 	"github.com/disintegration/imaging"
 
@@ -31,9 +32,12 @@ type SpriteGenerator struct {
 }
 
 func NewSpriteGenerator(videoFile ffmpeg.VideoFile, videoChecksum string, imageOutputPath string, vttOutputPath string, rows int, cols int) (*SpriteGenerator, error) {
-	exists, err := fsutil.FileExists(videoFile.Path)
-	if !exists {
-		return nil, err
+	// files read by generate workers are streamed from the primary instance
+	if !strings.Contains(videoFile.Path, "://") {
+		exists, err := fsutil.FileExists(videoFile.Path)
+		if !exists {
+			return nil, err
+		}
 	}
 	slowSeek := false
 	chunkCount := rows * cols
@@ -15,17 +15,21 @@ const (
 	ImportDuplicateEnumIgnore    ImportDuplicateEnum = "IGNORE"
 	ImportDuplicateEnumOverwrite ImportDuplicateEnum = "OVERWRITE"
 	ImportDuplicateEnumFail      ImportDuplicateEnum = "FAIL"
+	// ImportDuplicateEnumResolve records existing objects that differ from
+	// the import data as conflicts to be resolved by the user.
+	ImportDuplicateEnumResolve ImportDuplicateEnum = "RESOLVE"
 )
 
 var AllImportDuplicateEnum = []ImportDuplicateEnum{
 	ImportDuplicateEnumIgnore,
 	ImportDuplicateEnumOverwrite,
 	ImportDuplicateEnumFail,
+	ImportDuplicateEnumResolve,
 }
 
 func (e ImportDuplicateEnum) IsValid() bool {
 	switch e {
-	case ImportDuplicateEnumIgnore, ImportDuplicateEnumOverwrite, ImportDuplicateEnumFail:
+	case ImportDuplicateEnumIgnore, ImportDuplicateEnumOverwrite, ImportDuplicateEnumFail, ImportDuplicateEnumResolve:
 		return true
 	}
 	return false
@@ -81,6 +85,15 @@ func performImport(ctx context.Context, i importer, duplicateBehaviour ImportDup
 		} else if duplicateBehaviour == ImportDuplicateEnumIgnore {
 			logger.Infof("Skipping existing object %q", name)
 			return nil
+		} else if duplicateBehaviour == ImportDuplicateEnumResolve {
+			// existing objects that cannot be compared are kept
+			r, ok := i.(*resolvableImporter)
+			if !ok {
+				logger.Infof("Skipping existing object %q", name)
+				return nil
+			}
+
+			return r.checkConflict(ctx, *existing)
 		}
 
 		// must be overwriting
@@ -0,0 +1,619 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/movie"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/studio"
+	"github.com/stashapp/stash/pkg/tag"
+)
+
+type ImportConflictResolution string
+
+const (
+	// Keep the existing object unchanged
+	ImportConflictResolutionKeepExisting ImportConflictResolution = "KEEP_EXISTING"
+	// Overwrite the existing object with the import data
+	ImportConflictResolutionTakeImport ImportConflictResolution = "TAKE_IMPORT"
+	// Use the import data for fields that are empty on the existing object,
+	// and combine lists
+	ImportConflictResolutionMerge ImportConflictResolution = "MERGE"
+)
+
+var AllImportConflictResolution = []ImportConflictResolution{
+	ImportConflictResolutionKeepExisting,
+	ImportConflictResolutionTakeImport,
+	ImportConflictResolutionMerge,
+}
+
+func (e ImportConflictResolution) IsValid() bool {
+	switch e {
+	case ImportConflictResolutionKeepExisting, ImportConflictResolutionTakeImport, ImportConflictResolutionMerge:
+		return true
+	}
+	return false
+}
+
+func (e ImportConflictResolution) String() string {
+	return string(e)
+}
+
+func (e *ImportConflictResolution) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportConflictResolution(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportConflictResolution", str)
+	}
+	return nil
+}
+
+func (e ImportConflictResolution) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// ImportConflictField is a field with different values in the existing
+// object and the import data.
+type ImportConflictField struct {
+	Field    string      `json:"field"`
+	Existing interface{} `json:"existing"`
+	Import   interface{} `json:"import"`
+}
+
+// ImportConflict is an existing object that differs from the import data.
+type ImportConflict struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	ExistingID string                 `json:"existingID"`
+	Fields     []*ImportConflictField `json:"fields"`
+
+	objectID int
+	existing map[string]interface{}
+	imported map[string]interface{}
+	// returns an importer of the provided import data
+	newImporter func(data []byte) (importer, error)
+
+	resolution *ImportConflictResolution
+}
+
+type ImportConflictResolutionInput struct {
+	ID         string                   `json:"id"`
+	Resolution ImportConflictResolution `json:"resolution"`
+}
+
+var ErrImportConflictNotFound = errors.New("import conflict not found")
+
+// importConflicts holds the conflicts of running imports. It is safe for
+// concurrent use.
+type importConflicts struct {
+	mutex     sync.Mutex
+	lastID    int
+	conflicts []*ImportConflict
+	// closed and replaced when a conflict is resolved
+	resolved chan struct{}
+}
+
+func newImportConflicts() *importConflicts {
+	return &importConflicts{
+		resolved: make(chan struct{}),
+	}
+}
+
+func (c *importConflicts) add(conflict *ImportConflict) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastID++
+	conflict.ID = strconv.Itoa(c.lastID)
+	c.conflicts = append(c.conflicts, conflict)
+}
+
+// remove removes the provided conflicts.
+func (c *importConflicts) remove(toRemove []*ImportConflict) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var kept []*ImportConflict
+	for _, conflict := range c.conflicts {
+		found := false
+		for _, r := range toRemove {
+			if r == conflict {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			kept = append(kept, conflict)
+		}
+	}
+
+	c.conflicts = kept
+}
+
+// unresolved returns the conflicts that have not been resolved.
+func (c *importConflicts) unresolved() []*ImportConflict {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var ret []*ImportConflict
+	for _, conflict := range c.conflicts {
+		if conflict.resolution == nil {
+			ret = append(ret, conflict)
+		}
+	}
+
+	return ret
+}
+
+// resolve sets the resolutions of the conflicts. Returns an error without
+// resolving any conflicts if any of the conflicts do not exist.
+func (c *importConflicts) resolve(input []*ImportConflictResolutionInput) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	toResolve := make([]*ImportConflict, len(input))
+	for i, in := range input {
+		for _, conflict := range c.conflicts {
+			if conflict.ID == in.ID {
+				toResolve[i] = conflict
+				break
+			}
+		}
+
+		if toResolve[i] == nil {
+			return fmt.Errorf("%w: %s", ErrImportConflictNotFound, in.ID)
+		}
+	}
+
+	for i, conflict := range toResolve {
+		resolution := input[i].Resolution
+		conflict.resolution = &resolution
+	}
+
+	close(c.resolved)
+	c.resolved = make(chan struct{})
+
+	return nil
+}
+
+// wait blocks until all of the provided conflicts are resolved. Returns the
+// context error if ctx is done first.
+func (c *importConflicts) wait(ctx context.Context, conflicts []*ImportConflict) error {
+	for {
+		c.mutex.Lock()
+		resolved := c.resolved
+		done := true
+		for _, conflict := range conflicts {
+			if conflict.resolution == nil {
+				done = false
+				break
+			}
+		}
+		c.mutex.Unlock()
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-resolved:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// resolvableImporter is an importer of objects that can be compared with the
+// existing object, so that differences are recorded as conflicts.
+type resolvableImporter struct {
+	importer
+	objectType string
+	input      interface{}
+	// returns the JSON of the existing object
+	getExisting func(ctx context.Context, id int) (interface{}, error)
+	newImporter func(data []byte) (importer, error)
+	addConflict func(conflict *ImportConflict)
+}
+
+// checkConflict compares the existing object with the import data, and
+// records a conflict if they differ.
+func (i *resolvableImporter) checkConflict(ctx context.Context, existingID int) error {
+	existingJSON, err := i.getExisting(ctx, existingID)
+	if err != nil {
+		return fmt.Errorf("error getting existing object: %v", err)
+	}
+
+	existing, err := toJSONMap(existingJSON)
+	if err != nil {
+		return err
+	}
+
+	imported, err := toJSONMap(i.input)
+	if err != nil {
+		return err
+	}
+
+	fields := diffJSONMaps(existing, imported)
+	if len(fields) == 0 {
+		logger.Infof("Skipping identical existing object %q", i.Name())
+		return nil
+	}
+
+	logger.Infof("Existing object %q differs from import data", i.Name())
+
+	i.addConflict(&ImportConflict{
+		Type:        i.objectType,
+		Name:        i.Name(),
+		ExistingID:  strconv.Itoa(existingID),
+		Fields:      fields,
+		objectID:    existingID,
+		existing:    existing,
+		imported:    imported,
+		newImporter: i.newImporter,
+	})
+
+	return nil
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret map[string]interface{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// fields that are not compared
+var ignoredConflictFields = []string{"created_at", "updated_at"}
+
+func isIgnoredConflictField(field string) bool {
+	for _, f := range ignoredConflictFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diffJSONMaps returns the fields with different values in existing and
+// imported, sorted by field name.
+func diffJSONMaps(existing, imported map[string]interface{}) []*ImportConflictField {
+	keys := make(map[string]struct{})
+	for k := range existing {
+		keys[k] = struct{}{}
+	}
+	for k := range imported {
+		keys[k] = struct{}{}
+	}
+
+	var ret []*ImportConflictField
+	for k := range keys {
+		if isIgnoredConflictField(k) {
+			continue
+		}
+
+		if !reflect.DeepEqual(existing[k], imported[k]) {
+			ret = append(ret, &ImportConflictField{
+				Field:    k,
+				Existing: existing[k],
+				Import:   imported[k],
+			})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Field < ret[j].Field
+	})
+
+	return ret
+}
+
+func isEmptyJSONValue(v interface{}) bool {
+	switch vv := v.(type) {
+	case nil:
+		return true
+	case string:
+		return vv == ""
+	case float64:
+		return vv == 0
+	case bool:
+		return !vv
+	case []interface{}:
+		return len(vv) == 0
+	case map[string]interface{}:
+		return len(vv) == 0
+	}
+
+	return false
+}
+
+// mergeJSONMaps returns existing with empty fields set from imported. Lists
+// are combined and objects are merged recursively.
+func mergeJSONMaps(existing, imported map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for k, v := range existing {
+		ret[k] = v
+	}
+
+	for k, iv := range imported {
+		ev := ret[k]
+		if isEmptyJSONValue(ev) {
+			ret[k] = iv
+			continue
+		}
+
+		switch evv := ev.(type) {
+		case []interface{}:
+			if ivv, ok := iv.([]interface{}); ok {
+				merged := append([]interface{}{}, evv...)
+				for _, v := range ivv {
+					found := false
+					for _, e := range merged {
+						if reflect.DeepEqual(e, v) {
+							found = true
+							break
+						}
+					}
+
+					if !found {
+						merged = append(merged, v)
+					}
+				}
+				ret[k] = merged
+			}
+		case map[string]interface{}:
+			if ivv, ok := iv.(map[string]interface{}); ok {
+				ret[k] = mergeJSONMaps(evv, ivv)
+			}
+		}
+	}
+
+	return ret
+}
+
+// importConflictTimeout is the maximum time to wait for the conflicts of an
+// import to be resolved. Existing objects of unresolved conflicts are kept
+// unchanged.
+const importConflictTimeout = 24 * time.Hour
+
+// applyConflictResolution updates the existing object of c according to the
+// resolution of c. It must be called within a transaction.
+func applyConflictResolution(ctx context.Context, c *ImportConflict) error {
+	var data map[string]interface{}
+	switch *c.resolution {
+	case ImportConflictResolutionKeepExisting:
+		return nil
+	case ImportConflictResolutionTakeImport:
+		data = c.imported
+	case ImportConflictResolutionMerge:
+		data = mergeJSONMaps(c.existing, c.imported)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	i, err := c.newImporter(encoded)
+	if err != nil {
+		return err
+	}
+
+	if err := i.PreImport(ctx); err != nil {
+		return err
+	}
+
+	if err := i.Update(ctx, c.objectID); err != nil {
+		return fmt.Errorf("error updating existing object: %v", err)
+	}
+
+	return i.PostImport(ctx, c.objectID)
+}
+
+// resolveConflicts waits for the conflicts found during the import to be
+// resolved, then applies the resolutions in the import transactions. Stops
+// waiting if ctx is cancelled or after importConflictTimeout.
+func (t *ImportTask) resolveConflicts(ctx context.Context) {
+	if len(t.pendingConflicts) == 0 {
+		return
+	}
+
+	defer t.conflicts.remove(t.pendingConflicts)
+
+	logger.Infof("[conflicts] waiting for %d conflicts to be resolved", len(t.pendingConflicts))
+
+	waitCtx, cancel := context.WithTimeout(ctx, importConflictTimeout)
+	defer cancel()
+
+	if err := t.conflicts.wait(waitCtx, t.pendingConflicts); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.Warnf("[conflicts] conflicts not resolved after %s, keeping existing objects", importConflictTimeout)
+		} else {
+			logger.Info("[conflicts] stopping due to user request")
+		}
+		return
+	}
+
+	batch := t.newBatch()
+
+	for _, c := range t.pendingConflicts {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
+			return applyConflictResolution(ctx, c)
+		}); err != nil {
+			logger.Errorf("[conflicts] <%s> failed to resolve conflict: %v", c.Name, err)
+		}
+	}
+
+	commitBatch("[conflicts]", batch)
+
+	logger.Info("[conflicts] conflicts resolved")
+}
+
+func (t *ImportTask) addConflict(c *ImportConflict) {
+	t.conflicts.add(c)
+	t.pendingConflicts = append(t.pendingConflicts, c)
+}
+
+func (t *ImportTask) resolvablePerformer(i *performer.Importer) importer {
+	r := t.repository
+	return &resolvableImporter{
+		importer:   i,
+		objectType: "performer",
+		input:      &i.Input,
+		getExisting: func(ctx context.Context, id int) (interface{}, error) {
+			p, err := r.Performer.Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if p == nil {
+				return nil, fmt.Errorf("performer with id %d not found", id)
+			}
+
+			ret, _, err := performerToJSON(ctx, r, p)
+			return ret, err
+		},
+		newImporter: func(data []byte) (importer, error) {
+			var input jsonschema.Performer
+			if err := jsonschema.Unmarshal(data, &input); err != nil {
+				return nil, err
+			}
+			return &performer.Importer{
+				ReaderWriter: r.Performer,
+				TagWriter:    r.Tag,
+				Input:        input,
+			}, nil
+		},
+		addConflict: t.addConflict,
+	}
+}
+
+func (t *ImportTask) resolvableStudio(i *studio.Importer) importer {
+	r := t.repository
+	return &resolvableImporter{
+		importer:   i,
+		objectType: "studio",
+		input:      &i.Input,
+		getExisting: func(ctx context.Context, id int) (interface{}, error) {
+			s, err := r.Studio.Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if s == nil {
+				return nil, fmt.Errorf("studio with id %d not found", id)
+			}
+
+			return studioToJSON(ctx, r, s)
+		},
+		newImporter: func(data []byte) (importer, error) {
+			var input jsonschema.Studio
+			if err := jsonschema.Unmarshal(data, &input); err != nil {
+				return nil, err
+			}
+			return &studio.Importer{
+				ReaderWriter:        r.Studio,
+				Input:               input,
+				MissingRefBehaviour: t.MissingRefBehaviour,
+			}, nil
+		},
+		addConflict: t.addConflict,
+	}
+}
+
+func (t *ImportTask) resolvableTag(i *tag.Importer) importer {
+	r := t.repository
+	return &resolvableImporter{
+		importer:   i,
+		objectType: "tag",
+		input:      &i.Input,
+		getExisting: func(ctx context.Context, id int) (interface{}, error) {
+			tt, err := r.Tag.Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if tt == nil {
+				return nil, fmt.Errorf("tag with id %d not found", id)
+			}
+
+			return tagToJSON(ctx, r, tt)
+		},
+		newImporter: func(data []byte) (importer, error) {
+			var input jsonschema.Tag
+			if err := jsonschema.Unmarshal(data, &input); err != nil {
+				return nil, err
+			}
+			return &tag.Importer{
+				ReaderWriter:        r.Tag,
+				Input:               input,
+				MissingRefBehaviour: t.MissingRefBehaviour,
+			}, nil
+		},
+		addConflict: t.addConflict,
+	}
+}
+
+func (t *ImportTask) resolvableMovie(i *movie.Importer) importer {
+	r := t.repository
+	return &resolvableImporter{
+		importer:   i,
+		objectType: "movie",
+		input:      &i.Input,
+		getExisting: func(ctx context.Context, id int) (interface{}, error) {
+			m, err := r.Movie.Find(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if m == nil {
+				return nil, fmt.Errorf("movie with id %d not found", id)
+			}
+
+			return movieToJSON(ctx, r, m)
+		},
+		newImporter: func(data []byte) (importer, error) {
+			var input jsonschema.Movie
+			if err := jsonschema.Unmarshal(data, &input); err != nil {
+				return nil, err
+			}
+			return &movie.Importer{
+				ReaderWriter:        r.Movie,
+				StudioWriter:        r.Studio,
+				Input:               input,
+				MissingRefBehaviour: t.MissingRefBehaviour,
+			}, nil
+		},
+		addConflict: t.addConflict,
+	}
+}
+
+// GetImportConflicts returns the unresolved conflicts of running imports.
+func (s *Manager) GetImportConflicts() []*ImportConflict {
+	return s.importConflicts.unresolved()
+}
+
+// ResolveImportConflicts sets the resolutions of import conflicts.
+func (s *Manager) ResolveImportConflicts(input []*ImportConflictResolutionInput) error {
+	return s.importConflicts.resolve(input)
+}
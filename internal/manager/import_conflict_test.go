@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJSONMaps(t *testing.T) {
+	existing := map[string]interface{}{
+		"name":       "name",
+		"url":        "existing",
+		"aliases":    []interface{}{"a"},
+		"updated_at": "2023-01-01",
+	}
+	imported := map[string]interface{}{
+		"name":       "name",
+		"url":        "imported",
+		"details":    "details",
+		"updated_at": "2023-02-01",
+	}
+
+	assert.Equal(t, []*ImportConflictField{
+		{Field: "aliases", Existing: []interface{}{"a"}, Import: nil},
+		{Field: "details", Existing: nil, Import: "details"},
+		{Field: "url", Existing: "existing", Import: "imported"},
+	}, diffJSONMaps(existing, imported))
+}
+
+func TestMergeJSONMaps(t *testing.T) {
+	existing := map[string]interface{}{
+		"name":    "name",
+		"url":     "",
+		"aliases": []interface{}{"a", "b"},
+		"custom":  map[string]interface{}{"x": "1"},
+	}
+	imported := map[string]interface{}{
+		"name":    "other",
+		"url":     "url",
+		"aliases": []interface{}{"b", "c"},
+		"custom":  map[string]interface{}{"x": "2", "y": "3"},
+		"details": "details",
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":    "name",
+		"url":     "url",
+		"aliases": []interface{}{"a", "b", "c"},
+		"custom":  map[string]interface{}{"x": "1", "y": "3"},
+		"details": "details",
+	}, mergeJSONMaps(existing, imported))
+}
+
+func TestImportConflicts_Resolve(t *testing.T) {
+	c := newImportConflicts()
+
+	c1 := &ImportConflict{Name: "1"}
+	c2 := &ImportConflict{Name: "2"}
+	c.add(c1)
+	c.add(c2)
+
+	assert.Len(t, c.unresolved(), 2)
+
+	err := c.resolve([]*ImportConflictResolutionInput{
+		{ID: c1.ID, Resolution: ImportConflictResolutionKeepExisting},
+		{ID: "invalid", Resolution: ImportConflictResolutionKeepExisting},
+	})
+	assert.True(t, errors.Is(err, ErrImportConflictNotFound))
+	assert.Len(t, c.unresolved(), 2)
+
+	done := make(chan error)
+	go func() {
+		done <- c.wait(context.Background(), []*ImportConflict{c1, c2})
+	}()
+
+	assert.Nil(t, c.resolve([]*ImportConflictResolutionInput{
+		{ID: c1.ID, Resolution: ImportConflictResolutionKeepExisting},
+	}))
+	assert.Equal(t, []*ImportConflict{c2}, c.unresolved())
+
+	assert.Nil(t, c.resolve([]*ImportConflictResolutionInput{
+		{ID: c2.ID, Resolution: ImportConflictResolutionMerge},
+	}))
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("wait did not return after conflicts were resolved")
+	}
+
+	c.remove([]*ImportConflict{c1, c2})
+	assert.Len(t, c.unresolved(), 0)
+}
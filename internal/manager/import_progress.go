@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// importProgress reports the progress of an import across all object types,
+// so that the time remaining can be estimated. It is not safe for concurrent
+// use.
+type importProgress struct {
+	// may be nil
+	job *job.Progress
+
+	start     time.Time
+	total     int
+	processed int
+}
+
+// importStepCount is the number of objects of a single type to be imported.
+type importStepCount struct {
+	name  string
+	count int
+}
+
+// newImportProgress returns an importProgress for the provided per-type
+// counts. The counts are logged, and the total is set on progress.
+func newImportProgress(progress *job.Progress, counts []importStepCount) *importProgress {
+	ret := &importProgress{
+		job:   progress,
+		start: time.Now(),
+	}
+
+	var desc []string
+	for _, c := range counts {
+		ret.total += c.count
+		desc = append(desc, fmt.Sprintf("%d %s", c.count, c.name))
+	}
+
+	logger.Infof("[import] importing %s", strings.Join(desc, ", "))
+
+	if progress != nil {
+		progress.SetTotal(ret.total)
+	}
+
+	return ret
+}
+
+// read is called when the index'th of total objects is read. Progress is
+// logged using prefix.
+func (p *importProgress) read(prefix string, index int, total int) {
+	if p == nil {
+		logger.Progressf("%s %d of %d", prefix, index, total)
+		return
+	}
+
+	p.processed++
+	if p.job != nil {
+		p.job.Increment()
+	}
+
+	remaining, ok := p.remaining(time.Now())
+	if !ok {
+		logger.Progressf("%s %d of %d", prefix, index, total)
+		return
+	}
+
+	logger.Progressf("%s %d of %d (about %s remaining)", prefix, index, total, remaining)
+}
+
+// remaining returns the estimated time remaining for the import, rounded to
+// the second.
+func (p *importProgress) remaining(now time.Time) (time.Duration, bool) {
+	if p.total <= 0 {
+		return 0, false
+	}
+
+	remaining, ok := job.EstimateRemaining(now.Sub(p.start), float64(p.processed)/float64(p.total))
+	return remaining.Round(time.Second), ok
+}
@@ -0,0 +1,26 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportProgressRemaining(t *testing.T) {
+	p := newImportProgress(nil, []importStepCount{
+		{name: "tags", count: 10},
+		{name: "scenes", count: 30},
+	})
+
+	_, ok := p.remaining(p.start)
+	assert.False(t, ok, "no estimate before any objects are read")
+
+	for i := 1; i <= 10; i++ {
+		p.read("[tags]", i, 10)
+	}
+
+	remaining, ok := p.remaining(p.start.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Minute, remaining)
+}
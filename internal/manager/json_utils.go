@@ -1,44 +1,402 @@
 package manager
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"sync"
 
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models/jsonschema"
 	"github.com/stashapp/stash/pkg/models/paths"
 )
 
 type jsonUtils struct {
 	json paths.JSONPaths
+
+	// if set, objects are written to a single NDJSON file per type instead
+	// of individual files
+	ndjson *ndjsonWriters
+
+	// limits the rate at which objects are written. May be nil.
+	throttle *fsutil.Throttle
+
+	// counts the objects written to each directory. May be nil.
+	counts *objectCounts
+
+	// reports the progress of objects read. May be nil, in which case
+	// progress is only logged.
+	progress *importProgress
+}
+
+// objectCounts counts the objects written to each object directory. It is
+// safe for concurrent use.
+type objectCounts struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+func newObjectCounts() *objectCounts {
+	return &objectCounts{
+		counts: make(map[string]int),
+	}
+}
+
+func (c *objectCounts) add(dir string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.counts[dir]++
+}
+
+func (c *objectCounts) get(dir string) int {
+	if c == nil {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.counts[dir]
+}
+
+// saveJSONFile calls save with the path of fn in dir, and then waits for the
+// throttle to allow for the written file.
+func (jp *jsonUtils) saveJSONFile(dir string, fn string, save func(path string) error) error {
+	path := filepath.Join(dir, fn)
+	if err := save(path); err != nil {
+		return err
+	}
+
+	jp.counts.add(dir)
+
+	if jp.throttle != nil {
+		if info, err := os.Stat(path); err == nil {
+			jp.throttle.Wait(int(info.Size()))
+		}
+	}
+
+	return nil
+}
+
+// writeNDJSON writes obj to the NDJSON file of dir.
+func (jp *jsonUtils) writeNDJSON(dir string, obj interface{}) error {
+	if err := jp.ndjson.write(dir, obj); err != nil {
+		return err
+	}
+
+	jp.counts.add(dir)
+	return nil
 }
 
 func (jp *jsonUtils) savePerformer(fn string, performer *jsonschema.Performer) error {
-	return jsonschema.SavePerformerFile(filepath.Join(jp.json.Performers, fn), performer)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Performers, performer)
+	}
+	return jp.saveJSONFile(jp.json.Performers, fn, func(path string) error {
+		return jsonschema.SavePerformerFile(path, performer)
+	})
 }
 
 func (jp *jsonUtils) saveStudio(fn string, studio *jsonschema.Studio) error {
-	return jsonschema.SaveStudioFile(filepath.Join(jp.json.Studios, fn), studio)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Studios, studio)
+	}
+	return jp.saveJSONFile(jp.json.Studios, fn, func(path string) error {
+		return jsonschema.SaveStudioFile(path, studio)
+	})
 }
 
 func (jp *jsonUtils) saveTag(fn string, tag *jsonschema.Tag) error {
-	return jsonschema.SaveTagFile(filepath.Join(jp.json.Tags, fn), tag)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Tags, tag)
+	}
+	return jp.saveJSONFile(jp.json.Tags, fn, func(path string) error {
+		return jsonschema.SaveTagFile(path, tag)
+	})
 }
 
 func (jp *jsonUtils) saveMovie(fn string, movie *jsonschema.Movie) error {
-	return jsonschema.SaveMovieFile(filepath.Join(jp.json.Movies, fn), movie)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Movies, movie)
+	}
+	return jp.saveJSONFile(jp.json.Movies, fn, func(path string) error {
+		return jsonschema.SaveMovieFile(path, movie)
+	})
 }
 
 func (jp *jsonUtils) saveScene(fn string, scene *jsonschema.Scene) error {
-	return jsonschema.SaveSceneFile(filepath.Join(jp.json.Scenes, fn), scene)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Scenes, scene)
+	}
+	return jp.saveJSONFile(jp.json.Scenes, fn, func(path string) error {
+		return jsonschema.SaveSceneFile(path, scene)
+	})
 }
 
 func (jp *jsonUtils) saveImage(fn string, image *jsonschema.Image) error {
-	return jsonschema.SaveImageFile(filepath.Join(jp.json.Images, fn), image)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Images, image)
+	}
+	return jp.saveJSONFile(jp.json.Images, fn, func(path string) error {
+		return jsonschema.SaveImageFile(path, image)
+	})
 }
 
 func (jp *jsonUtils) saveGallery(fn string, gallery *jsonschema.Gallery) error {
-	return jsonschema.SaveGalleryFile(filepath.Join(jp.json.Galleries, fn), gallery)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Galleries, gallery)
+	}
+	return jp.saveJSONFile(jp.json.Galleries, fn, func(path string) error {
+		return jsonschema.SaveGalleryFile(path, gallery)
+	})
 }
 
 func (jp *jsonUtils) saveFile(fn string, file jsonschema.DirEntry) error {
-	return jsonschema.SaveFileFile(filepath.Join(jp.json.Files, fn), file)
+	if jp.ndjson != nil {
+		return jp.writeNDJSON(jp.json.Files, file)
+	}
+	return jp.saveJSONFile(jp.json.Files, fn, func(path string) error {
+		return jsonschema.SaveFileFile(path, file)
+	})
+}
+
+func (jp *jsonUtils) saveChapters(fn string, vtt string) error {
+	if err := fsutil.EnsureDir(jp.json.Chapters); err != nil {
+		return err
+	}
+
+	jp.throttle.Wait(len(vtt))
+	return os.WriteFile(filepath.Join(jp.json.Chapters, fn), []byte(vtt), 0644)
+}
+
+// ndjsonWriters writes exported objects to one NDJSON file per object
+// directory. It is safe for concurrent use.
+type ndjsonWriters struct {
+	mutex    sync.Mutex
+	writers  map[string]*jsonschema.NDJSONWriter
+	throttle *fsutil.Throttle
+}
+
+// newNDJSONWriters returns ndjsonWriters that write at the rate allowed by
+// throttle. throttle may be nil.
+func newNDJSONWriters(throttle *fsutil.Throttle) *ndjsonWriters {
+	return &ndjsonWriters{
+		writers:  make(map[string]*jsonschema.NDJSONWriter),
+		throttle: throttle,
+	}
+}
+
+func (w *ndjsonWriters) get(dir string) (*jsonschema.NDJSONWriter, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	ret := w.writers[dir]
+	if ret == nil {
+		f, err := os.Create(paths.NDJSONPath(dir))
+		if err != nil {
+			return nil, err
+		}
+
+		ret = jsonschema.NewNDJSONWriter(w.throttle.Writer(f), f)
+
+		w.writers[dir] = ret
+	}
+
+	return ret, nil
+}
+
+func (w *ndjsonWriters) write(dir string, v interface{}) error {
+	ww, err := w.get(dir)
+	if err != nil {
+		return err
+	}
+
+	return ww.Write(v)
+}
+
+// close closes all of the NDJSON files, logging any errors.
+func (w *ndjsonWriters) close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for dir, ww := range w.writers {
+		if err := ww.Close(); err != nil {
+			logger.Errorf("error writing %s: %v", paths.NDJSONPath(dir), err)
+		}
+	}
+
+	w.writers = make(map[string]*jsonschema.NDJSONWriter)
+}
+
+// jsonObject is a single exported object.
+type jsonObject struct {
+	// name identifies the object in log messages
+	name string
+	// open returns a reader of the JSON data of the object
+	open func() (io.ReadCloser, error)
+}
+
+// decode decodes the object into v. The object is decoded directly from its
+// source, without reading it into memory first.
+func (o jsonObject) decode(v interface{}) error {
+	r, err := o.open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return jsonschema.Decode(r, v)
+}
+
+// dirEntry decodes the object as a file or folder.
+func (o jsonObject) dirEntry() (jsonschema.DirEntry, error) {
+	r, err := o.open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonschema.ParseDirEntry(data)
+}
+
+// readDirBatchSize is the number of directory entries read at a time.
+const readDirBatchSize = 1000
+
+// readObjects calls fn with each object exported to dir. If the NDJSON file
+// of dir exists, objects are read from it instead. Progress and read errors
+// are logged using prefix.
+//
+// Objects are read one at a time, so that memory use does not depend on the
+// number of objects. Objects in dir are read in directory order.
+func (jp *jsonUtils) readObjects(prefix string, dir string, fn func(o jsonObject)) {
+	ndjsonPath := paths.NDJSONPath(dir)
+	if exists, _ := fsutil.FileExists(ndjsonPath); exists {
+		jp.readNDJSONObjects(prefix, ndjsonPath, fn)
+		return
+	}
+
+	// count the files first so that progress can be reported
+	total, err := countDirObjects(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Errorf("%s failed to read directory: %v", prefix, err)
+		}
+
+		return
+	}
+
+	index := 0
+	if err := readDirNames(dir, func(name string) {
+		index++
+		jp.progress.read(prefix, index, total)
+
+		fn(jsonObject{
+			name: name,
+			open: func() (io.ReadCloser, error) {
+				return os.Open(filepath.Join(dir, name))
+			},
+		})
+	}); err != nil {
+		logger.Errorf("%s failed to read directory: %v", prefix, err)
+	}
+}
+
+// countObjects returns the number of objects exported to dir, or to the
+// NDJSON file of dir if it exists. Returns 0 if neither exist.
+func countObjects(dir string) (int, error) {
+	ndjsonPath := paths.NDJSONPath(dir)
+	if exists, _ := fsutil.FileExists(ndjsonPath); exists {
+		return countNDJSONObjects(ndjsonPath)
+	}
+
+	total, err := countDirObjects(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	return total, err
+}
+
+func countDirObjects(dir string) (int, error) {
+	total := 0
+	err := readDirNames(dir, func(name string) {
+		total++
+	})
+
+	return total, err
+}
+
+func countNDJSONObjects(ndjsonPath string) (int, error) {
+	total := 0
+	err := jsonschema.ReadNDJSONFile(ndjsonPath, func(line int, data []byte) error {
+		total++
+		return nil
+	})
+
+	return total, err
+}
+
+// readDirNames calls fn with the name of each entry in dir, reading
+// readDirBatchSize entries at a time.
+func readDirNames(dir string, fn func(name string)) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for {
+		names, err := f.Readdirnames(readDirBatchSize)
+		for _, name := range names {
+			fn(name)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (jp *jsonUtils) readNDJSONObjects(prefix string, ndjsonPath string, fn func(o jsonObject)) {
+	// count the objects first so that progress can be reported
+	total, err := countNDJSONObjects(ndjsonPath)
+	if err != nil {
+		logger.Errorf("%s failed to read %s: %v", prefix, ndjsonPath, err)
+		return
+	}
+
+	base := filepath.Base(ndjsonPath)
+	index := 0
+	if err := jsonschema.ReadNDJSONFile(ndjsonPath, func(line int, data []byte) error {
+		index++
+		jp.progress.read(prefix, index, total)
+
+		// data is only valid until fn returns
+		fn(jsonObject{
+			name: fmt.Sprintf("%s:%d", base, line),
+			open: func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			},
+		})
+		return nil
+	}); err != nil {
+		logger.Errorf("%s failed to read %s: %v", prefix, ndjsonPath, err)
+	}
 }
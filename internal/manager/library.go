@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// libraryConfig resolves the library settings of files in the stash paths.
+type libraryConfig struct {
+	stashPaths config.StashConfigs
+	libraries  config.LibraryList
+
+	videoExcludeRegex map[string][]*regexp.Regexp
+	imageExcludeRegex map[string][]*regexp.Regexp
+}
+
+func newLibraryConfig(c *config.Instance) *libraryConfig {
+	ret := &libraryConfig{
+		stashPaths:        c.GetStashPaths(),
+		libraries:         c.GetLibraries(),
+		videoExcludeRegex: make(map[string][]*regexp.Regexp),
+		imageExcludeRegex: make(map[string][]*regexp.Regexp),
+	}
+
+	for _, l := range ret.libraries {
+		ret.videoExcludeRegex[l.Name] = generateRegexps(l.Excludes)
+		ret.imageExcludeRegex[l.Name] = generateRegexps(l.ImageExcludes)
+	}
+
+	return ret
+}
+
+// library returns the library of the stash path. Returns nil if the stash
+// path is not in a library.
+func (l *libraryConfig) library(s *config.StashConfig) *config.Library {
+	if s == nil || s.Library == "" {
+		return nil
+	}
+
+	return l.libraries.Get(s.Library)
+}
+
+// videoExcludes returns the video exclusion patterns of the library of the
+// stash path, appended to global.
+func (l *libraryConfig) videoExcludes(s *config.StashConfig, global []*regexp.Regexp) []*regexp.Regexp {
+	if l == nil || s == nil {
+		return global
+	}
+
+	return append(global[:len(global):len(global)], l.videoExcludeRegex[s.Library]...)
+}
+
+// imageExcludes returns the image exclusion patterns of the library of the
+// stash path, appended to global.
+func (l *libraryConfig) imageExcludes(s *config.StashConfig, global []*regexp.Regexp) []*regexp.Regexp {
+	if l == nil || s == nil {
+		return global
+	}
+
+	return append(global[:len(global):len(global)], l.imageExcludeRegex[s.Library]...)
+}
+
+// scanOptions returns the scan options of the library of the file at path,
+// restricted by the scan profile of its stash path. The library options
+// default to def if the file is not in a library with scan options.
+func (l *libraryConfig) scanOptions(path string, def config.ScanMetadataOptions) config.ScanMetadataOptions {
+	if l == nil {
+		return def
+	}
+
+	s := l.stashPaths.GetStashFromPath(path)
+	ret := def
+	if lib := l.library(s); lib != nil && lib.ScanOptions != nil {
+		ret = *lib.ScanOptions
+	}
+
+	if s != nil {
+		ret = s.ScanProfile.Apply(ret)
+	}
+
+	return ret
+}
+
+// libraryScanScheduler starts scans of libraries that have a scan interval.
+type libraryScanScheduler struct {
+	manager  *Manager
+	lastScan map[string]time.Time
+}
+
+func newLibraryScanScheduler(manager *Manager) *libraryScanScheduler {
+	return &libraryScanScheduler{
+		manager:  manager,
+		lastScan: make(map[string]time.Time),
+	}
+}
+
+// update starts a scan of each library whose scan interval has passed since
+// it was last scanned. Libraries are first scanned one interval after they
+// are seen by the scheduler.
+func (s *libraryScanScheduler) update(ctx context.Context, now time.Time) {
+	mgr := s.manager
+	c := mgr.Config
+
+	if c.IsNewSystem() || mgr.Database.Ready() != nil {
+		return
+	}
+
+	stashPaths := c.GetStashPaths()
+	for _, l := range c.GetLibraries() {
+		if l.ScanInterval <= 0 {
+			delete(s.lastScan, l.Name)
+			continue
+		}
+
+		last, found := s.lastScan[l.Name]
+		if !found {
+			s.lastScan[l.Name] = now
+			continue
+		}
+
+		if now.Sub(last) < time.Duration(l.ScanInterval)*time.Minute {
+			continue
+		}
+
+		s.lastScan[l.Name] = now
+
+		paths := stashPaths.LibraryPaths(l.Name)
+		if len(paths) == 0 {
+			continue
+		}
+
+		input := ScanMetadataInput{
+			Paths: paths,
+		}
+		if l.ScanOptions != nil {
+			input.ScanMetadataOptions = *l.ScanOptions
+		} else if opts := c.GetDefaultScanSettings(); opts != nil {
+			input.ScanMetadataOptions = *opts
+		}
+
+		logger.Infof("Starting scheduled scan of library %q", l.Name)
+		if _, err := mgr.Scan(ctx, input); err != nil {
+			logger.Errorf("error starting scan of library %q: %v", l.Name, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLibraryConfig(t *testing.T) {
+	moviesPath := filepath.FromSlash("/media/movies")
+	otherPath := filepath.FromSlash("/media/other")
+
+	movies := &config.StashConfig{Path: moviesPath, Library: "movies"}
+	other := &config.StashConfig{Path: otherPath}
+
+	scanOptions := &config.ScanMetadataOptions{
+		ScanGeneratePhashes: true,
+	}
+
+	l := &libraryConfig{
+		stashPaths: config.StashConfigs{movies, other},
+		libraries: config.LibraryList{
+			{Name: "movies", ScanOptions: scanOptions},
+		},
+		videoExcludeRegex: map[string][]*regexp.Regexp{
+			"movies": generateRegexps([]string{`trailer`}),
+		},
+		imageExcludeRegex: map[string][]*regexp.Regexp{},
+	}
+
+	global := generateRegexps([]string{`\.tmp$`})
+
+	t.Run("excludes", func(t *testing.T) {
+		got := l.videoExcludes(movies, global)
+		assert.True(t, matchFileRegex(filepath.Join(moviesPath, "a trailer.mp4"), got))
+		assert.True(t, matchFileRegex(filepath.Join(moviesPath, "a.tmp"), got))
+		assert.Len(t, global, 1)
+
+		got = l.videoExcludes(other, global)
+		assert.False(t, matchFileRegex(filepath.Join(otherPath, "a trailer.mp4"), got))
+
+		assert.Equal(t, global, l.imageExcludes(movies, global))
+	})
+
+	t.Run("scan options", func(t *testing.T) {
+		def := config.ScanMetadataOptions{ScanGenerateCovers: true}
+
+		assert.Equal(t, *scanOptions, l.scanOptions(filepath.Join(moviesPath, "a.mp4"), def))
+		assert.Equal(t, def, l.scanOptions(filepath.Join(otherPath, "a.mp4"), def))
+		assert.Equal(t, def, l.scanOptions(filepath.FromSlash("/elsewhere/a.mp4"), def))
+	})
+
+	t.Run("scan profile", func(t *testing.T) {
+		bulkPath := filepath.FromSlash("/media/bulk")
+		bulk := &config.StashConfig{
+			Path:        bulkPath,
+			Library:     "movies",
+			ScanProfile: &config.ScanProfile{CoverOnly: true},
+		}
+
+		l := *l
+		l.stashPaths = config.StashConfigs{bulk, other}
+
+		def := config.ScanMetadataOptions{ScanGenerateCovers: true, ScanGeneratePhashes: true}
+		assert.Equal(t, config.ScanMetadataOptions{}, l.scanOptions(filepath.Join(bulkPath, "a.mp4"), def))
+		assert.Equal(t, def, l.scanOptions(filepath.Join(otherPath, "a.mp4"), def))
+	})
+}
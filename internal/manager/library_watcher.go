@@ -0,0 +1,228 @@
+package manager
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// libraryWatchDelay is the time to wait after the last change to a watched
+// path before the changed paths are scanned, so that files that are still
+// being written are not scanned until they are complete.
+const libraryWatchDelay = 10 * time.Second
+
+// libraryWatcher watches the stash paths for new and changed files, and
+// scans them once they stop changing.
+type libraryWatcher struct {
+	config *config.Instance
+	// scan is called with the paths to be scanned
+	scan  func(paths []string)
+	delay time.Duration
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newLibraryWatcher(cfg *config.Instance, scan func(paths []string)) *libraryWatcher {
+	return &libraryWatcher{
+		config: cfg,
+		scan:   scan,
+		delay:  libraryWatchDelay,
+	}
+}
+
+// refresh restarts the watcher using the current configuration. The watcher
+// is stopped if watching is disabled.
+func (w *libraryWatcher) refresh() {
+	w.stop()
+
+	if !w.config.IsWatchLibrary() {
+		return
+	}
+
+	var roots []string
+	for _, s := range w.config.GetStashPaths() {
+		if s.ExcludeVideo && s.ExcludeImage {
+			continue
+		}
+
+		// changes in object storage and network shares cannot be watched
+		if w.config.IsMountedPath(s.Path) {
+			continue
+		}
+		roots = append(roots, s.Path)
+	}
+
+	if err := w.watch(roots); err != nil {
+		logger.Errorf("[watch] error starting library watcher: %v", err)
+	}
+}
+
+// watch starts watching roots and all directories within them.
+func (w *libraryWatcher) watch(roots []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	for _, root := range roots {
+		addWatchRecursive(watcher, root)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	w.mutex.Lock()
+	w.cancel = cancel
+	w.done = done
+	w.mutex.Unlock()
+
+	logger.Infof("[watch] watching %d stash paths for changes", len(roots))
+
+	go func() {
+		defer close(done)
+		defer watcher.Close()
+		w.run(ctx, watcher)
+	}()
+
+	return nil
+}
+
+// stop stops the watcher if it is running, and waits for it to finish.
+func (w *libraryWatcher) stop() {
+	w.mutex.Lock()
+	cancel := w.cancel
+	done := w.done
+	w.cancel = nil
+	w.done = nil
+	w.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+func (w *libraryWatcher) run(ctx context.Context, watcher *fsnotify.Watcher) {
+	pending := make(map[string]struct{})
+
+	timer := time.NewTimer(w.delay)
+	stopTimer(timer)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("[watch] %v", err)
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			// removed files are handled by the clean task
+			if !e.Has(fsnotify.Create) && !e.Has(fsnotify.Write) {
+				continue
+			}
+
+			if e.Has(fsnotify.Create) {
+				if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+					addWatchRecursive(watcher, e.Name)
+				}
+			}
+
+			pending[e.Name] = struct{}{}
+
+			// wait for changes to settle before scanning
+			stopTimer(timer)
+			timer.Reset(w.delay)
+		case <-timer.C:
+			var paths []string
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			pending = make(map[string]struct{})
+
+			w.scan(reduceWatchedPaths(paths))
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
+
+// addWatchRecursive adds root and all directories within it to watcher.
+func addWatchRecursive(watcher *fsnotify.Watcher, root string) {
+	if err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logger.Warnf("[watch] error reading %s: %v", p, err)
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if err := watcher.Add(p); err != nil {
+			logger.Warnf("[watch] could not watch %s: %v", p, err)
+		}
+
+		return nil
+	}); err != nil {
+		logger.Warnf("[watch] error walking %s: %v", root, err)
+	}
+}
+
+// reduceWatchedPaths returns the sorted paths, omitting paths within other
+// paths, since scanning a directory scans everything within it.
+func reduceWatchedPaths(paths []string) []string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	var ret []string
+	for _, p := range sorted {
+		if fsutil.IsPathInDirs(ret, p) {
+			continue
+		}
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// scanWatchedPaths starts a scan of paths changed in the stash paths, using
+// the default scan settings.
+func (s *Manager) scanWatchedPaths(paths []string) {
+	input := ScanMetadataInput{
+		Paths: paths,
+	}
+
+	if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+		input.ScanMetadataOptions = *opts
+	}
+
+	logger.Infof("[watch] scanning %d changed paths", len(paths))
+
+	if _, err := s.Scan(context.Background(), input); err != nil {
+		logger.Errorf("[watch] error starting scan: %v", err)
+	}
+}
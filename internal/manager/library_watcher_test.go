@@ -0,0 +1,67 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduceWatchedPaths(t *testing.T) {
+	sep := string(filepath.Separator)
+	dir := sep + "stash"
+	sub := filepath.Join(dir, "sub")
+	subFile := filepath.Join(sub, "file.mp4")
+	sibling := dir + " other"
+
+	assert.Equal(t, []string{dir, sibling}, reduceWatchedPaths([]string{subFile, sibling, sub, dir}))
+	assert.Equal(t, []string{subFile}, reduceWatchedPaths([]string{subFile}))
+	assert.Nil(t, reduceWatchedPaths(nil))
+}
+
+func TestLibraryWatcher(t *testing.T) {
+	root := t.TempDir()
+
+	scanned := make(chan []string, 1)
+	w := &libraryWatcher{
+		scan: func(paths []string) {
+			scanned <- paths
+		},
+		delay: 50 * time.Millisecond,
+	}
+
+	if err := w.watch([]string{root}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	defer w.stop()
+
+	// files in new directories are picked up by the directory
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatalf("creating directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "file.mp4"), []byte("data"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	select {
+	case paths := <-scanned:
+		assert.Equal(t, []string{sub}, paths)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for scan")
+	}
+
+	f := filepath.Join(root, "file.mp4")
+	if err := os.WriteFile(f, []byte("data"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	select {
+	case paths := <-scanned:
+		assert.Equal(t, []string{f}, paths)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for scan")
+	}
+}
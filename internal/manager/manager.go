@@ -17,8 +17,11 @@ import (
 	"github.com/stashapp/stash/internal/dlna"
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/audio"
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/file"
+	file_audio "github.com/stashapp/stash/pkg/file/audio"
+	"github.com/stashapp/stash/pkg/file/document"
 	file_image "github.com/stashapp/stash/pkg/file/image"
 	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/fsutil"
@@ -46,6 +49,8 @@ type SystemStatus struct {
 	ConfigPath     *string          `json:"configPath"`
 	AppSchema      int              `json:"appSchema"`
 	Status         SystemStatusEnum `json:"status"`
+	// HardwareEncoders are the hardware codecs detected by the last probe
+	HardwareEncoders []string `json:"hardwareEncoders"`
 }
 
 type SystemStatusEnum string
@@ -117,6 +122,8 @@ type Manager struct {
 	FFProbe       ffmpeg.FFProbe
 	StreamManager *ffmpeg.StreamManager
 
+	DocumentRenderer *document.Renderer
+
 	ReadLockManager *fsutil.ReadLockManager
 
 	SessionStore *session.Store
@@ -136,11 +143,29 @@ type Manager struct {
 	SceneService   SceneService
 	ImageService   ImageService
 	GalleryService GalleryService
+	AudioService   AudioService
 
+	// FS is the file system that library files are read from, including
+	// files in object storage.
+	FS      *file.MountFS
 	Scanner *file.Scanner
 	Cleaner *file.Cleaner
 
 	scanSubs *subscriptionManager
+
+	quietHours      *quietHours
+	storage         *storageMonitor
+	importConflicts *importConflicts
+	libraryWatcher  *libraryWatcher
+	libraryScans    *libraryScanScheduler
+	fileVerifier    *verifyFilesScheduler
+	scheduledTasks  *taskScheduler
+	trashPurge      *trashPurgeScheduler
+	generatedAccess *generatedAccessRecorder
+	fileProxy       *fileProxy
+
+	generateDispatcher *generateDispatcher
+	generateWorker     *generateWorker
 }
 
 var instance *Manager
@@ -189,10 +214,30 @@ func initialize() error {
 		Database:   db,
 		Repository: repo,
 		Paths:      &emptyPaths,
+		FS:         &file.MountFS{},
 
 		scanSubs: &subscriptionManager{},
+
+		quietHours:      &quietHours{config: cfg},
+		storage:         newStorageMonitor(cfg),
+		importConflicts: newImportConflicts(),
+
+		DocumentRenderer: &document.Renderer{
+			VipsPath: image.GetVipsPath(),
+		},
 	}
 
+	instance.libraryWatcher = newLibraryWatcher(cfg, instance.scanWatchedPaths)
+	instance.libraryScans = newLibraryScanScheduler(instance)
+	instance.fileVerifier = &verifyFilesScheduler{manager: instance}
+	instance.trashPurge = &trashPurgeScheduler{manager: instance}
+	instance.scheduledTasks = newTaskScheduler(instance, instance.libraryScans, instance.fileVerifier, instance.trashPurge)
+	instance.generatedAccess = newGeneratedAccessRecorder(repo, db)
+	instance.fileProxy = newFileProxy(instance.FS)
+	instance.generateDispatcher = newGenerateDispatcher()
+	instance.generateWorker = newGenerateWorker(cfg)
+	ffmpeg.SetInputResolver(instance.resolveFFMpegInput)
+
 	instance.SceneService = &scene.Service{
 		File:             repo.File,
 		Repository:       repo.Scene,
@@ -215,7 +260,17 @@ func initialize() error {
 		Folder:       repo.Folder,
 	}
 
+	instance.AudioService = &audio.Service{
+		File:       repo.File,
+		Repository: repo.Audio,
+	}
+
 	instance.JobManager = initJobManager()
+	go instance.quietHours.run(ctx)
+	go instance.storage.run(ctx)
+	go instance.scheduledTasks.run(ctx)
+	go instance.generatedAccess.run(ctx, db.Ready)
+	go instance.generateWorker.run(ctx)
 
 	sceneServer := SceneServer{
 		TxnManager:       repo.TxnManager,
@@ -266,6 +321,10 @@ func initialize() error {
 	instance.Scanner = makeScanner(repo, instance.PluginCache)
 	instance.Cleaner = makeCleaner(repo, instance.PluginCache)
 
+	if !cfg.IsNewSystem() && instance.Database.Ready() == nil {
+		instance.resumeScan(ctx)
+	}
+
 	// if DLNA is enabled, start it now
 	if instance.Config.GetDLNADefaultEnabled() {
 		if err := instance.DLNAService.Start(nil); err != nil {
@@ -284,8 +343,16 @@ func imageFileFilter(ctx context.Context, f models.File) bool {
 	return useAsImage(f.Base().Path)
 }
 
+func audioFileFilter(ctx context.Context, f models.File) bool {
+	return useAsAudio(f.Base().Path)
+}
+
 func galleryFileFilter(ctx context.Context, f models.File) bool {
-	return isZip(f.Base().Basename)
+	return isZip(f.Base().Basename) || isDocument(f.Base().Basename)
+}
+
+func documentFileFilter(ctx context.Context, f models.File) bool {
+	return isDocument(f.Base().Basename)
 }
 
 func makeScanner(repo models.Repository, pluginCache *plugin.Cache) *file.Scanner {
@@ -304,19 +371,33 @@ func makeScanner(repo models.Repository, pluginCache *plugin.Cache) *file.Scanne
 				},
 				Filter: file.FilterFunc(imageFileFilter),
 			},
+			&file.FilteredDecorator{
+				Decorator: &file_audio.Decorator{
+					FFProbe: instance.FFProbe,
+				},
+				Filter: file.FilterFunc(audioFileFilter),
+			},
+			&file.FilteredDecorator{
+				Decorator: &document.Decorator{
+					Renderer: instance.DocumentRenderer,
+				},
+				Filter: file.FilterFunc(documentFileFilter),
+			},
 		},
-		FingerprintCalculator: &fingerprintCalculator{instance.Config},
-		FS:                    &file.OsFS{},
+		FingerprintCalculator: &fingerprintCalculator{Config: instance.Config},
+		FS:                    instance.FS,
+		PluginCache:           pluginCache,
 	}
 }
 
 func makeCleaner(repo models.Repository, pluginCache *plugin.Cache) *file.Cleaner {
 	return &file.Cleaner{
-		FS:         &file.OsFS{},
+		FS:         instance.FS,
 		Repository: file.NewRepository(repo),
 		Handlers: []file.CleanHandler{
 			&cleanHandler{},
 		},
+		PluginCache: pluginCache,
 	}
 }
 
@@ -410,6 +491,8 @@ func initFFMPEG(ctx context.Context) error {
 		instance.FFMPEG = ffmpeg.NewEncoder(ffmpegPath)
 		instance.FFProbe = ffmpeg.FFProbe(ffprobePath)
 
+		instance.FFMPEG.SetHWAccelOptions(instance.hwAccelOptions())
+		instance.setCodecArgs()
 		instance.FFMPEG.InitHWSupport(ctx)
 		instance.RefreshStreamManager()
 	}
@@ -444,6 +527,7 @@ func (s *Manager) PostInit(ctx context.Context) error {
 	}
 
 	s.SetBlobStoreOptions()
+	s.SetCaseInsensitivePaths()
 
 	s.ScraperCache = instance.initScraperCache()
 	writeStashIcon()
@@ -498,6 +582,25 @@ func (s *Manager) SetBlobStoreOptions() {
 	})
 }
 
+// SetCaseInsensitivePaths sets whether the database compares paths
+// case-insensitively from the configuration.
+func (s *Manager) SetCaseInsensitivePaths() {
+	s.Database.SetCaseInsensitivePaths(s.Config.IsCaseInsensitivePaths())
+}
+
+// SetLibraryPaths sets the stash paths of the libraries used by the database
+// when filtering by library from the configuration.
+func (s *Manager) SetLibraryPaths() {
+	stashPaths := s.Config.GetStashPaths()
+
+	paths := make(map[string][]string)
+	for _, l := range s.Config.GetLibraries() {
+		paths[l.Name] = stashPaths.LibraryPaths(l.Name)
+	}
+
+	s.Database.SetLibraryPaths(paths)
+}
+
 func writeStashIcon() {
 	iconPath := filepath.Join(instance.Config.GetConfigPath(), "icon.png")
 	err := os.WriteFile(iconPath, ui.FaviconProvider.GetFaviconPng(), 0644)
@@ -520,6 +623,10 @@ func (s *Manager) initScraperCache() *scraper.Cache {
 
 func (s *Manager) RefreshConfig() {
 	*s.Paths = paths.NewPaths(s.Config.GetGeneratedPath(), s.Config.GetBlobsPath())
+	s.refreshMounts()
+	s.SetLibraryPaths()
+	file.SetMaxNestedArchiveSize(s.Config.GetMaxNestedArchiveSize())
+	scene.SetVideoExtensions(s.Config.GetVideoExtensions())
 	config := s.Config
 	if config.Validate() == nil {
 		if err := fsutil.EnsureDir(s.Paths.Generated.Screenshots); err != nil {
@@ -540,6 +647,17 @@ func (s *Manager) RefreshConfig() {
 		if err := fsutil.EnsureDir(s.Paths.Generated.InteractiveHeatmap); err != nil {
 			logger.Warnf("could not create directory for Interactive Heatmaps: %v", err)
 		}
+		if err := fsutil.EnsureDir(s.Paths.Generated.Captions); err != nil {
+			logger.Warnf("could not create directory for Captions: %v", err)
+		}
+		if err := fsutil.EnsureDir(s.Paths.Generated.Frames); err != nil {
+			logger.Warnf("could not create directory for Frames: %v", err)
+		}
+		if err := fsutil.EnsureDir(s.Paths.Generated.Waveforms); err != nil {
+			logger.Warnf("could not create directory for Waveforms: %v", err)
+		}
+
+		s.libraryWatcher.refresh()
 	}
 }
 
@@ -562,6 +680,49 @@ func (s *Manager) RefreshStreamManager() {
 	s.StreamManager = ffmpeg.NewStreamManager(cacheDir, s.FFMPEG, s.FFProbe, s.Config, s.ReadLockManager)
 }
 
+func (s *Manager) hwAccelOptions() ffmpeg.HWAccelOptions {
+	return ffmpeg.HWAccelOptions{
+		Codec:       ffmpeg.VideoCodec(s.Config.GetTranscodeHardwareAccelerationCodec()),
+		VAAPIDevice: s.Config.GetTranscodeHardwareAccelerationDevice(),
+	}
+}
+
+// setCodecArgs sets the configured encoding options of each codec in the
+// encoder.
+func (s *Manager) setCodecArgs() {
+	toMap := func(args []*config.FFMpegCodecArgs) map[ffmpeg.VideoCodec]ffmpeg.Args {
+		ret := make(map[ffmpeg.VideoCodec]ffmpeg.Args)
+		for _, c := range args {
+			ret[ffmpeg.VideoCodec(c.Codec)] = c.Args
+		}
+		return ret
+	}
+
+	s.FFMPEG.SetCodecArgs(toMap(s.Config.GetFFMpegLiveCodecArgs()), toMap(s.Config.GetFFMpegGenerateCodecArgs()))
+}
+
+// RefreshCodecArgs sets the configured encoding options in the encoder.
+// RefreshHWSupport also sets the encoding options.
+func (s *Manager) RefreshCodecArgs() {
+	if s.FFMPEG == nil {
+		return
+	}
+
+	s.setCodecArgs()
+}
+
+// RefreshHWSupport probes the hardware encoders again in the background.
+// Call this when the hardware acceleration or codec configuration changes.
+func (s *Manager) RefreshHWSupport() {
+	if s.FFMPEG == nil {
+		return
+	}
+
+	s.FFMPEG.SetHWAccelOptions(s.hwAccelOptions())
+	s.setCodecArgs()
+	go s.FFMPEG.InitHWSupport(context.Background())
+}
+
 func setSetupDefaults(input *SetupInput) {
 	if input.ConfigLocation == "" {
 		input.ConfigLocation = filepath.Join(fsutil.GetHomeDirectory(), ".stash", "config.yml")
@@ -753,12 +914,20 @@ func (s *Manager) GetSystemStatus() *SystemStatus {
 		status = SystemStatusEnumNeedsMigration
 	}
 
+	hardwareEncoders := []string{}
+	if s.FFMPEG != nil {
+		for _, c := range s.FFMPEG.HWCodecSupport() {
+			hardwareEncoders = append(hardwareEncoders, string(c))
+		}
+	}
+
 	return &SystemStatus{
-		DatabaseSchema: &dbSchema,
-		DatabasePath:   &dbPath,
-		AppSchema:      appSchema,
-		Status:         status,
-		ConfigPath:     &configFile,
+		DatabaseSchema:   &dbSchema,
+		DatabasePath:     &dbPath,
+		AppSchema:        appSchema,
+		Status:           status,
+		ConfigPath:       &configFile,
+		HardwareEncoders: hardwareEncoders,
 	}
 }
 
@@ -9,10 +9,14 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scene/generate"
 )
 
 func useAsVideo(pathname string) bool {
@@ -29,11 +33,23 @@ func useAsImage(pathname string) bool {
 	return isImage(pathname)
 }
 
+// useAsAudio returns true if the file should be treated as an audio file.
+// Video extensions take precedence, so that containers such as webm that may
+// hold either are scanned as scenes.
+func useAsAudio(pathname string) bool {
+	return isAudio(pathname) && !isVideo(pathname)
+}
+
 func isZip(pathname string) bool {
 	gExt := config.GetInstance().GetGalleryExtensions()
 	return fsutil.MatchExtension(pathname, gExt)
 }
 
+func isDocument(pathname string) bool {
+	docExt := config.GetInstance().GetDocumentExtensions()
+	return fsutil.MatchExtension(pathname, docExt)
+}
+
 func isVideo(pathname string) bool {
 	vidExt := config.GetInstance().GetVideoExtensions()
 	return fsutil.MatchExtension(pathname, vidExt)
@@ -44,6 +60,11 @@ func isImage(pathname string) bool {
 	return fsutil.MatchExtension(pathname, imgExt)
 }
 
+func isAudio(pathname string) bool {
+	audExt := config.GetInstance().GetAudioExtensions()
+	return fsutil.MatchExtension(pathname, audExt)
+}
+
 func getScanPaths(inputPaths []string) []*config.StashConfig {
 	stashPaths := config.GetInstance().GetStashPaths()
 
@@ -94,13 +115,27 @@ func (s *Manager) Scan(ctx context.Context, input ScanMetadataInput) (int, error
 		return 0, err
 	}
 
+	return s.addScanJob(ctx, input, nil), nil
+}
+
+// addScanJob adds a scan job, resuming from the saved state if not nil.
+func (s *Manager) addScanJob(ctx context.Context, input ScanMetadataInput, resume *scanState) int {
 	scanJob := ScanJob{
 		scanner:       s.Scanner,
 		input:         input,
 		subscriptions: s.scanSubs,
+		state:         s.scanStateStore(),
+		resume:        resume,
 	}
 
-	return s.JobManager.Add(ctx, "Scanning...", &scanJob), nil
+	return s.JobManager.Add(s.quietHours.context(maintenanceContext(ctx)), "Scanning...", &scanJob)
+}
+
+// maintenanceContext returns ctx without the content rating restriction of
+// the session. It is used for maintenance jobs, which must see the whole
+// library to avoid treating restricted objects as missing.
+func maintenanceContext(ctx context.Context) context.Context {
+	return models.WithoutContentRating(ctx)
 }
 
 func (s *Manager) Import(ctx context.Context) (int, error) {
@@ -110,20 +145,17 @@ func (s *Manager) Import(ctx context.Context) (int, error) {
 		return 0, errors.New("metadata path must be set in config")
 	}
 
-	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
-		task := ImportTask{
-			repository:          s.Repository,
-			resetter:            s.Database,
-			BaseDir:             metadataPath,
-			Reset:               true,
-			DuplicateBehaviour:  ImportDuplicateEnumFail,
-			MissingRefBehaviour: models.ImportMissingRefEnumFail,
-			fileNamingAlgorithm: config.GetVideoFileNamingAlgorithm(),
-		}
-		task.Start(ctx)
-	})
+	task := &ImportTask{
+		repository:          s.Repository,
+		resetter:            s.Database,
+		BaseDir:             metadataPath,
+		Reset:               true,
+		DuplicateBehaviour:  ImportDuplicateEnumFail,
+		MissingRefBehaviour: models.ImportMissingRefEnumFail,
+		fileNamingAlgorithm: config.GetVideoFileNamingAlgorithm(),
+	}
 
-	return s.JobManager.Add(ctx, "Importing...", j), nil
+	return s.JobManager.Add(ctx, task.GetDescription(), task), nil
 }
 
 func (s *Manager) Export(ctx context.Context) (int, error) {
@@ -133,18 +165,56 @@ func (s *Manager) Export(ctx context.Context) (int, error) {
 		return 0, errors.New("metadata path must be set in config")
 	}
 
+	var filenameTemplate *jsonschema.FilenameTemplate
+	if v := config.GetExportFilenameTemplate(); v != "" {
+		var err error
+		filenameTemplate, err = jsonschema.ParseFilenameTemplate(v)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		// the export runs in a single transaction, so wait for free space
+		// before starting rather than pausing part way through
+		if err := job.WaitIfPaused(ctx); err != nil {
+			return
+		}
+
 		var wg sync.WaitGroup
 		wg.Add(1)
 		task := ExportTask{
 			repository:          s.Repository,
 			full:                true,
 			fileNamingAlgorithm: config.GetVideoFileNamingAlgorithm(),
+			filenameTemplate:    filenameTemplate,
 		}
 		task.Start(ctx, &wg)
 	})
 
-	return s.JobManager.Add(ctx, "Exporting...", j), nil
+	return s.JobManager.Add(s.storage.context(ctx), "Exporting...", j), nil
+}
+
+type ValidateExportInput struct {
+	// Path of the export directory or zip file. Defaults to the metadata path.
+	Path *string `json:"path"`
+}
+
+func (s *Manager) ValidateExport(ctx context.Context, input ValidateExportInput) (int, error) {
+	path := config.GetInstance().GetMetadataPath()
+	if input.Path != nil && *input.Path != "" {
+		path = *input.Path
+	}
+
+	if path == "" {
+		return 0, errors.New("path must be provided if the metadata path is not set in config")
+	}
+
+	j := validateExportJob{
+		path: path,
+	}
+
+	return s.JobManager.Add(ctx, "Validating export...", &j), nil
 }
 
 func (s *Manager) RunSingleTask(ctx context.Context, t Task) int {
@@ -163,6 +233,9 @@ func (s *Manager) Generate(ctx context.Context, input GenerateMetadataInput) (in
 	if err := s.validateFFMPEG(); err != nil {
 		return 0, err
 	}
+	if err := validateMarkerPreviewOptions(input.MarkerPreviewOptions); err != nil {
+		return 0, err
+	}
 	if err := instance.Paths.Generated.EnsureTmpDir(); err != nil {
 		logger.Warnf("could not generate temporary directory: %v", err)
 	}
@@ -172,7 +245,7 @@ func (s *Manager) Generate(ctx context.Context, input GenerateMetadataInput) (in
 		input:      input,
 	}
 
-	return s.JobManager.Add(ctx, "Generating...", j), nil
+	return s.JobManager.Add(s.storage.context(s.quietHours.context(ctx)), "Generating...", j), nil
 }
 
 func (s *Manager) GenerateDefaultScreenshot(ctx context.Context, sceneId string) int {
@@ -224,7 +297,8 @@ func (s *Manager) generateScreenshot(ctx context.Context, sceneId string, at *fl
 		logger.Infof("Generate screenshot finished")
 	})
 
-	return s.JobManager.Add(ctx, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), j)
+	// the user is waiting on the screenshot, so don't queue it behind bulk jobs
+	return s.JobManager.AddWithPriority(ctx, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), job.PriorityHigh, j)
 }
 
 type AutoTagMetadataInput struct {
@@ -247,6 +321,127 @@ func (s *Manager) AutoTag(ctx context.Context, input AutoTagMetadataInput) int {
 	return s.JobManager.Add(ctx, "Auto-tagging...", &j)
 }
 
+type ImportNFOInput struct {
+	// Paths to import, null for all files
+	Paths []string `json:"paths"`
+	// Replace existing scene values. Performers and tags are always added.
+	Overwrite bool `json:"overwrite"`
+}
+
+// RenameScenes starts a job to move scene files to paths formatted from their
+// metadata.
+func (s *Manager) RenameScenes(ctx context.Context, input RenameScenesInput) (int, error) {
+	if err := scene.ValidatePathTemplate(input.Template); err != nil {
+		return 0, err
+	}
+
+	j := renameScenesJob{
+		repository: s.Repository,
+		config:     s.Config,
+		input:      input,
+	}
+
+	return s.JobManager.Add(ctx, "Renaming scene files...", &j), nil
+}
+
+// OrganizeScenes starts a job to move scene files into folders formatted
+// from their metadata.
+func (s *Manager) OrganizeScenes(ctx context.Context, input OrganizeScenesInput) (int, error) {
+	if err := scene.ValidatePathTemplate(input.Template); err != nil {
+		return 0, err
+	}
+
+	j := organizeScenesJob{
+		repository: s.Repository,
+		config:     s.Config,
+		input:      input,
+	}
+
+	return s.JobManager.Add(ctx, "Organizing scene files...", &j), nil
+}
+
+// ResolveDuplicates starts a job to merge each group of duplicate scenes into
+// the scene chosen by the rules of the input.
+func (s *Manager) ResolveDuplicates(ctx context.Context, input ResolveDuplicatesInput) (int, error) {
+	for _, rule := range input.Rules {
+		if !rule.IsValid() {
+			return 0, fmt.Errorf("invalid duplicate rule: %s", rule)
+		}
+	}
+
+	j := resolveDuplicatesJob{
+		repository:   s.Repository,
+		sceneService: s.SceneService,
+		newDeleter:   s.NewFileDeleter,
+		input:        input,
+	}
+
+	return s.JobManager.Add(ctx, "Resolving duplicate scenes...", &j), nil
+}
+
+// ReencodeScenes starts a job to re-encode the primary files of scenes
+// matching the filter of the input.
+func (s *Manager) ReencodeScenes(ctx context.Context, input ReencodeScenesInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	if !input.VideoCodec.IsValid() {
+		return 0, fmt.Errorf("invalid video codec: %s", input.VideoCodec)
+	}
+
+	if input.MaxHeight != nil && *input.MaxHeight < 0 {
+		return 0, fmt.Errorf("invalid maximum height: %d", *input.MaxHeight)
+	}
+
+	j := reencodeScenesJob{
+		repository: s.Repository,
+		config:     s.Config,
+		generator: &generate.Generator{
+			Encoder:      s.FFMPEG,
+			FFMpegConfig: s.Config,
+			LockManager:  s.ReadLockManager,
+			ScenePaths:   s.Paths.Scene,
+		},
+		ffprobe:  &s.FFProbe,
+		scanFile: s.ScanFile,
+		input:    input,
+	}
+
+	return s.JobManager.Add(s.storage.context(s.quietHours.context(ctx)), "Re-encoding scene files...", &j), nil
+}
+
+func (s *Manager) ImportNFO(ctx context.Context, input ImportNFOInput) int {
+	j := importNFOJob{
+		repository: s.Repository,
+		input:      input,
+	}
+
+	return s.JobManager.Add(ctx, "Importing nfo files...", &j)
+}
+
+type VerifyFilesInput struct {
+	// Paths to verify, null for all files
+	Paths []string `json:"paths"`
+	// Maximum number of files to verify, least recently verified first. Null for all files
+	Limit *int `json:"limit"`
+}
+
+// VerifyFiles starts a job to re-calculate the fingerprints of files and
+// record files whose contents no longer match.
+func (s *Manager) VerifyFiles(ctx context.Context, input VerifyFilesInput) int {
+	j := verifyFilesJob{
+		verifier: &file.Verifier{
+			FS:                    s.FS,
+			Repository:            file.NewRepository(s.Repository),
+			FingerprintCalculator: &fingerprintCalculator{Config: s.Config, verify: true},
+		},
+		input: input,
+	}
+
+	return s.JobManager.Add(ctx, "Verifying files...", &j)
+}
+
 type CleanMetadataInput struct {
 	Paths []string `json:"paths"`
 	// Do a dry run. Don't delete any files
@@ -263,7 +458,7 @@ func (s *Manager) Clean(ctx context.Context, input CleanMetadataInput) int {
 		scanSubs:     s.scanSubs,
 	}
 
-	return s.JobManager.Add(ctx, "Cleaning...", &j)
+	return s.JobManager.Add(maintenanceContext(ctx), "Cleaning...", &j)
 }
 
 func (s *Manager) OptimiseDatabase(ctx context.Context) int {
@@ -274,6 +469,57 @@ func (s *Manager) OptimiseDatabase(ctx context.Context) int {
 	return s.JobManager.Add(ctx, "Optimising database...", &j)
 }
 
+func (s *Manager) DatabaseDoctor(ctx context.Context, repair bool) int {
+	j := DatabaseDoctorJob{
+		Repository: s.Repository,
+		Repairer:   s.Database,
+		Repair:     repair,
+	}
+
+	return s.JobManager.Add(maintenanceContext(ctx), "Checking database...", &j)
+}
+
+type CleanGeneratedInput struct {
+	Transcodes bool `json:"transcodes"`
+	Sprites    bool `json:"sprites"`
+	Markers    bool `json:"markers"`
+	// Number of days without access after which files are removed.
+	// Defaults to the configured retention period.
+	OlderThanDays *int `json:"olderThanDays"`
+	// Do a dry run. Don't delete any files
+	DryRun bool `json:"dryRun"`
+}
+
+func (s *Manager) CleanGenerated(ctx context.Context, input CleanGeneratedInput) int {
+	g := s.Paths.Generated
+	j := cleanGeneratedJob{
+		repository:    s.Repository,
+		store:         s.Database,
+		recorder:      s.generatedAccess,
+		generatedPath: s.Config.GetGeneratedPath(),
+		dirs: cleanGeneratedDirs{
+			Transcodes: g.Transcodes,
+			Sprites:    g.Vtt,
+			Markers:    g.Markers,
+		},
+		input:         input,
+		retentionDays: s.Config.GetGeneratedRetentionDays(),
+	}
+
+	return s.JobManager.Add(maintenanceContext(ctx), "Cleaning generated files...", &j)
+}
+
+// MergeCaseDuplicatePaths starts a job to merge folders and files whose paths
+// differ only by case.
+func (s *Manager) MergeCaseDuplicatePaths(ctx context.Context) int {
+	j := MergeCaseDuplicatePathsJob{
+		Repository: s.Repository,
+		Merger:     s.Database,
+	}
+
+	return s.JobManager.Add(maintenanceContext(ctx), "Merging duplicate paths...", &j)
+}
+
 func (s *Manager) MigrateHash(ctx context.Context) int {
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
 		fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
@@ -319,7 +565,7 @@ func (s *Manager) MigrateHash(ctx context.Context) int {
 		logger.Info("Finished migrating")
 	})
 
-	return s.JobManager.Add(ctx, "Migrating scene hashes...", j)
+	return s.JobManager.Add(maintenanceContext(ctx), "Migrating scene hashes...", j)
 }
 
 // If neither ids nor names are set, tag all items
@@ -0,0 +1,162 @@
+package manager
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/s3"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// refreshMounts mounts the configured object storage buckets and network
+// shares in the manager file system.
+func (s *Manager) refreshMounts() {
+	mounts := s.objectStorageMounts()
+	mounts = append(mounts, s.networkShareMounts()...)
+
+	s.FS.SetMounts(mounts)
+}
+
+// resolveFFMpegInput returns the input used by ffmpeg to read the file at
+// path. Files in object storage are read using a presigned URL, and files in
+// network shares are read through the file proxy.
+func (s *Manager) resolveFFMpegInput(path string) string {
+	m := s.FS.Mount(path)
+	if m == nil {
+		return path
+	}
+
+	var (
+		u   string
+		err error
+	)
+
+	if bucket, ok := m.(*s3.FS); ok {
+		u, err = bucket.PresignedURL(path, objectStorageURLExpiry)
+	} else {
+		u, err = s.fileProxy.url(path)
+	}
+
+	if err != nil {
+		logger.Warnf("error getting URL for %s: %v", path, err)
+		return path
+	}
+
+	return u
+}
+
+// fileProxy serves files in mounted file systems over HTTP on the loopback
+// interface, so that they can be read by ffmpeg. Requests must include a
+// random token, so that the files cannot be read by other local users.
+type fileProxy struct {
+	fs    *file.MountFS
+	token string
+
+	once sync.Once
+	addr string
+	err  error
+}
+
+func newFileProxy(fs *file.MountFS) *fileProxy {
+	return &fileProxy{
+		fs: fs,
+	}
+}
+
+// start starts the proxy server the first time it is called.
+func (p *fileProxy) start() error {
+	p.once.Do(func() {
+		p.token, p.err = hash.GenerateRandomKey(32)
+		if p.err != nil {
+			return
+		}
+
+		var l net.Listener
+		l, p.err = net.Listen("tcp", "127.0.0.1:0")
+		if p.err != nil {
+			return
+		}
+
+		p.addr = l.Addr().String()
+
+		go func() {
+			if err := http.Serve(l, p); err != nil {
+				logger.Errorf("file proxy stopped: %v", err)
+			}
+		}()
+	})
+
+	return p.err
+}
+
+// url returns the URL that the file at path is served at.
+func (p *fileProxy) url(path string) (string, error) {
+	if err := p.start(); err != nil {
+		return "", err
+	}
+
+	// the file name is included so that ffmpeg can detect the format
+	u := url.URL{
+		Scheme:   "http",
+		Host:     p.addr,
+		Path:     "/" + p.token + "/" + filepath.Base(path),
+		RawQuery: url.Values{"path": {path}}.Encode(),
+	}
+
+	return u.String(), nil
+}
+
+func (p *fileProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	path := r.URL.Query().Get("path")
+
+	// only files in mounted file systems are served
+	if subtle.ConstantTimeCompare([]byte(token), []byte(p.token)) != 1 || p.fs.Mount(path) == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	serveMountedFile(w, r, p.fs, path)
+}
+
+// serveMountedFile serves the file at path in fsys, supporting range
+// requests.
+func serveMountedFile(w http.ResponseWriter, r *http.Request, fsys models.FS, path string) {
+	f, err := fsys.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		logger.Errorf("error opening %s: %v", path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		logger.Errorf("error reading %s: %v", path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), rs)
+}
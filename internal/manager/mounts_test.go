@@ -0,0 +1,134 @@
+package manager
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+// localMount is a directory on the local file system mounted at its own path.
+type localMount struct {
+	file.OsFS
+	path string
+}
+
+func (m *localMount) MountPath() string {
+	return m.path
+}
+
+func TestResolveFFMpegInput(t *testing.T) {
+	bucketPath := filepath.FromSlash("/mnt/bucket")
+
+	bucket, err := s3.New(bucketPath, s3.Options{
+		Endpoint:  "s3.example.com",
+		Region:    "us-east-1",
+		Bucket:    "library",
+		AccessKey: "access",
+		SecretKey: "secret",
+		UseSSL:    true,
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("s3.New: %v", err)
+	}
+
+	sharePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sharePath, "video.mp4"), []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	s := &Manager{FS: &file.MountFS{}}
+	s.FS.SetMounts([]file.MountedFS{bucket, &localMount{path: sharePath}})
+	s.fileProxy = newFileProxy(s.FS)
+
+	// files in object storage are read using presigned URLs
+	got := s.resolveFFMpegInput(filepath.Join(bucketPath, "video.mp4"))
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", got, err)
+	}
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "s3.example.com", u.Host)
+	assert.Equal(t, "/library/video.mp4", u.Path)
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+
+	// files in other mounts are read through the file proxy
+	got = s.resolveFFMpegInput(filepath.Join(sharePath, "video.mp4"))
+	assert.True(t, strings.HasPrefix(got, "http://127.0.0.1:"), got)
+	assert.True(t, strings.HasSuffix(strings.Split(got, "?")[0], "/video.mp4"), got)
+
+	req, err := http.NewRequest(http.MethodGet, got, nil)
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+	req.Header.Set("Range", "bytes=2-5")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("requesting %s: %v", got, err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "2345", string(body))
+
+	// local paths are passed unchanged
+	localPath := filepath.FromSlash("/videos/video.mp4")
+	assert.Equal(t, localPath, s.resolveFFMpegInput(localPath))
+}
+
+func TestFileProxy_ServeHTTP(t *testing.T) {
+	sharePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sharePath, "video.mp4"), []byte("video"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	localPath := filepath.Join(t.TempDir(), "local.mp4")
+	if err := os.WriteFile(localPath, []byte("local"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	mfs := &file.MountFS{}
+	mfs.SetMounts([]file.MountedFS{&localMount{path: sharePath}})
+
+	p := newFileProxy(mfs)
+	validURL, err := p.url(filepath.Join(sharePath, "video.mp4"))
+	if err != nil {
+		t.Fatalf("url: %v", err)
+	}
+
+	localURL, err := p.url(localPath)
+	if err != nil {
+		t.Fatalf("url: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{"valid", validURL, http.StatusOK},
+		{"invalid token", strings.Replace(validURL, p.token, "invalid", 1), http.StatusNotFound},
+		{"missing file", strings.Replace(validURL, "video.mp4", "missing.mp4", -1), http.StatusNotFound},
+		{"not mounted", localURL, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(tt.url)
+			if err != nil {
+				t.Fatalf("requesting %s: %v", tt.url, err)
+			}
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
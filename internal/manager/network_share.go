@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/smb"
+	"github.com/stashapp/stash/pkg/file/webdav"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// networkShareMounts returns the file systems of the configured network
+// shares.
+func (s *Manager) networkShareMounts() []file.MountedFS {
+	var mounts []file.MountedFS
+	for _, c := range s.Config.GetNetworkShares() {
+		m, err := newNetworkShareFS(c)
+		if err != nil {
+			logger.Errorf("[network share] error mounting %q at %q: %v", c.URL, c.Path, err)
+			continue
+		}
+
+		mounts = append(mounts, m)
+	}
+
+	return mounts
+}
+
+func newNetworkShareFS(c *config.NetworkShare) (file.MountedFS, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return webdav.New(c.Path, u, c.Username, c.Password), nil
+	case "smb":
+		share, err := smb.ParseURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return smb.New(c.Path, *share, c.Username, c.Password), nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+}
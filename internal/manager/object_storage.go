@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/s3"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// objectStorageURLExpiry is the time that presigned URLs of files in object
+// storage are valid for. URLs passed to ffmpeg must remain valid for the
+// duration of long transcodes.
+const objectStorageURLExpiry = 24 * time.Hour
+
+// objectStorageMounts returns the file systems of the configured object
+// storage buckets.
+func (s *Manager) objectStorageMounts() []file.MountedFS {
+	var mounts []file.MountedFS
+	for _, c := range s.Config.GetObjectStorage() {
+		m, err := s3.New(c.Path, s3.Options{
+			Endpoint:  c.Endpoint,
+			Region:    c.Region,
+			Bucket:    c.Bucket,
+			Prefix:    c.Prefix,
+			AccessKey: c.AccessKey,
+			SecretKey: c.SecretKey,
+			UseSSL:    c.UseSSL,
+			PathStyle: c.PathStyle,
+		})
+		if err != nil {
+			logger.Errorf("[object storage] error mounting bucket %q at %q: %v", c.Bucket, c.Path, err)
+			continue
+		}
+
+		mounts = append(mounts, m)
+	}
+
+	return mounts
+}
+
+// ObjectStorageURL returns a presigned URL to read the file at path. Returns
+// an empty string if path is not in object storage.
+func (s *Manager) ObjectStorageURL(path string) (string, error) {
+	m, ok := s.FS.Mount(path).(*s3.FS)
+	if !ok {
+		return "", nil
+	}
+
+	return m.PresignedURL(path, objectStorageURLExpiry)
+}
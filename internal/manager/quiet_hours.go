@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const quietHoursCheckInterval = time.Minute
+
+type QuietHoursOverrideInput struct {
+	// If true, heavy jobs are paused. If false, heavy jobs run during quiet hours.
+	Paused bool `json:"paused"`
+	// If nil, the override expires when quiet hours next start or end.
+	Until *time.Time `json:"until"`
+}
+
+type QuietHoursStatus struct {
+	// True if heavy jobs are currently paused
+	Paused bool `json:"paused"`
+	// True if the current time is within quiet hours
+	Scheduled bool                     `json:"scheduled"`
+	Override  *QuietHoursOverrideInput `json:"override"`
+}
+
+type quietHoursOverride struct {
+	QuietHoursOverrideInput
+	// whether quiet hours were scheduled when the override was set
+	scheduled bool
+}
+
+// quietHours pauses heavy jobs during the configured quiet hours.
+type quietHours struct {
+	config *config.Instance
+	pauser job.Pauser
+
+	mutex    sync.Mutex
+	override *quietHoursOverride
+}
+
+// context returns a context for a heavy job, which is paused during quiet
+// hours.
+func (q *quietHours) context(ctx context.Context) context.Context {
+	return job.WithPauser(ctx, &q.pauser)
+}
+
+// run updates the paused state until ctx is done.
+func (q *quietHours) run(ctx context.Context) {
+	ticker := time.NewTicker(quietHoursCheckInterval)
+	defer ticker.Stop()
+
+	q.update(time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			q.update(time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// update sets the paused state for the time now, clearing the override if
+// it has expired.
+func (q *quietHours) update(now time.Time) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	scheduled := q.config.GetQuietHours().Active(now)
+	paused := scheduled
+
+	if o := q.override; o != nil {
+		expired := (o.Until != nil && !now.Before(*o.Until)) || (o.Until == nil && o.scheduled != scheduled)
+		if expired {
+			logger.Info("Quiet hours override expired")
+			q.override = nil
+		} else {
+			paused = o.Paused
+		}
+	}
+
+	if q.pauser.SetPaused(paused) {
+		if paused {
+			logger.Info("Pausing background jobs for quiet hours")
+		} else {
+			logger.Info("Resuming background jobs")
+		}
+	}
+}
+
+// setOverride sets or clears the manual override, and updates the paused
+// state.
+func (q *quietHours) setOverride(input *QuietHoursOverrideInput) {
+	now := time.Now()
+
+	q.mutex.Lock()
+	if input == nil {
+		q.override = nil
+	} else {
+		q.override = &quietHoursOverride{
+			QuietHoursOverrideInput: *input,
+			scheduled:               q.config.GetQuietHours().Active(now),
+		}
+	}
+	q.mutex.Unlock()
+
+	q.update(now)
+}
+
+func (q *quietHours) status() QuietHoursStatus {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	ret := QuietHoursStatus{
+		Paused:    q.pauser.Paused(),
+		Scheduled: q.config.GetQuietHours().Active(time.Now()),
+	}
+
+	if q.override != nil {
+		o := q.override.QuietHoursOverrideInput
+		ret.Override = &o
+	}
+
+	return ret
+}
+
+// SetQuietHoursOverride manually pauses or resumes heavy jobs regardless of
+// the configured quiet hours. A nil input clears the override.
+func (s *Manager) SetQuietHoursOverride(input *QuietHoursOverrideInput) QuietHoursStatus {
+	s.quietHours.setOverride(input)
+	return s.quietHours.status()
+}
+
+func (s *Manager) GetQuietHoursStatus() QuietHoursStatus {
+	return s.quietHours.status()
+}
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 
+	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scene"
@@ -12,6 +13,7 @@ type SceneService interface {
 	Create(ctx context.Context, input *models.Scene, fileIDs []models.FileID, coverImage []byte) (*models.Scene, error)
 	AssignFile(ctx context.Context, sceneID int, fileID models.FileID) error
 	Merge(ctx context.Context, sourceIDs []int, destinationID int, values models.ScenePartial) error
+	MergeMultipart(ctx context.Context, sceneIDs []int) error
 	Destroy(ctx context.Context, scene *models.Scene, fileDeleter *scene.FileDeleter, deleteGenerated, deleteFile bool) error
 }
 
@@ -20,6 +22,10 @@ type ImageService interface {
 	DestroyZipImages(ctx context.Context, zipFile models.File, fileDeleter *image.FileDeleter, deleteGenerated bool) ([]*models.Image, error)
 }
 
+type AudioService interface {
+	Destroy(ctx context.Context, audio *models.Audio, fileDeleter *file.Deleter, deleteFile bool) error
+}
+
 type GalleryService interface {
 	AddImages(ctx context.Context, g *models.Gallery, toAdd ...int) error
 	RemoveImages(ctx context.Context, g *models.Gallery, toRemove ...int) error
@@ -47,6 +47,27 @@ func (s *SceneServer) StreamSceneDirect(scene *models.Scene, w http.ResponseWrit
 	sceneHash := scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm())
 
 	filepath := GetInstance().Paths.Scene.GetStreamPath(scene.Path, sceneHash)
+	// only records transcodes, since the scene file is not generated
+	GetInstance().RecordGeneratedAccess(filepath)
+
+	// files in object storage are streamed directly from the storage service
+	objectURL, err := GetInstance().ObjectStorageURL(filepath)
+	if err != nil {
+		logger.Errorf("error getting object storage URL for %s: %v", filepath, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if objectURL != "" {
+		http.Redirect(w, r, objectURL, http.StatusFound)
+		return
+	}
+
+	// files in network shares are streamed through the file system
+	if GetInstance().FS.Mount(filepath) != nil {
+		serveMountedFile(w, r, GetInstance().FS, filepath)
+		return
+	}
+
 	streamRequestCtx := ffmpeg.NewStreamRequestContext(w, r)
 
 	// #2579 - hijacking and closing the connection here causes video playback to fail in Safari
@@ -0,0 +1,261 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// relinkedFile is an existing file that was found at a new path during a
+// scan.
+type relinkedFile struct {
+	OldPath string
+	NewPath string
+	// Phash is true if the file was matched by its perceptual hash, rather
+	// than by its exact fingerprints.
+	Phash bool
+}
+
+// relinkRecorder is a scan handler that records files that were moved or
+// renamed, and the video files that were scanned as new files.
+type relinkRecorder struct {
+	mutex    sync.Mutex
+	relinked map[models.FileID]relinkedFile
+	scanned  map[models.FileID]bool
+}
+
+func newRelinkRecorder() *relinkRecorder {
+	return &relinkRecorder{
+		relinked: make(map[models.FileID]relinkedFile),
+		scanned:  make(map[models.FileID]bool),
+	}
+}
+
+func (r *relinkRecorder) Handle(ctx context.Context, f models.File, oldFile models.File) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	id := f.Base().ID
+
+	if oldFile != nil {
+		// the scanner relinks files with matching fingerprints at a missing path
+		if oldFile.Base().Path != f.Base().Path {
+			r.relinked[id] = relinkedFile{
+				OldPath: oldFile.Base().Path,
+				NewPath: f.Base().Path,
+			}
+		}
+		return nil
+	}
+
+	if _, ok := f.(*models.VideoFile); ok {
+		r.scanned[id] = true
+	}
+
+	return nil
+}
+
+// scannedVideoFiles returns the IDs of video files that were not matched to
+// an existing file.
+func (r *relinkRecorder) scannedVideoFiles() []models.FileID {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var ret []models.FileID
+	for id := range r.scanned {
+		if _, found := r.relinked[id]; !found {
+			ret = append(ret, id)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i] < ret[j]
+	})
+
+	return ret
+}
+
+func (r *relinkRecorder) add(id models.FileID, f relinkedFile) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.relinked[id] = f
+}
+
+// report logs the files that were relinked during the scan.
+func (r *relinkRecorder) report() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(r.relinked) == 0 {
+		return
+	}
+
+	files := make([]relinkedFile, 0, len(r.relinked))
+	for _, f := range r.relinked {
+		files = append(files, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].NewPath < files[j].NewPath
+	})
+
+	logger.Infof("Relinked %d moved files:", len(files))
+	for _, f := range files {
+		method := "fingerprint"
+		if f.Phash {
+			method = "phash"
+		}
+		logger.Infof("  %s -> %s (%s)", f.OldPath, f.NewPath, method)
+	}
+}
+
+// relinkByPhash matches new video files from the scan against missing video
+// files with the same perceptual hash. Where the missing file belongs to a
+// scene, the scene created for the new file during the scan is merged into
+// it, and the missing file is removed.
+//
+// Moved files are usually relinked by the scanner using their exact
+// fingerprints. This catches files that were remuxed or re-encoded when
+// moved. New files only have a phash if phashes were generated during the
+// scan.
+func (j *ScanJob) relinkByPhash(ctx context.Context, start time.Time) {
+	mgr := GetInstance()
+	r := mgr.Repository
+
+	for _, id := range j.relinks.scannedVideoFiles() {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			relinked, err := relinkFileByPhash(ctx, id, start)
+			if err != nil {
+				return err
+			}
+
+			if relinked != nil {
+				j.relinks.add(id, *relinked)
+			}
+
+			return nil
+		}); err != nil {
+			logger.Errorf("error relinking file %d: %v", id, err)
+		}
+	}
+}
+
+func relinkFileByPhash(ctx context.Context, id models.FileID, start time.Time) (*relinkedFile, error) {
+	mgr := GetInstance()
+	r := mgr.Repository
+
+	files, err := r.File.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	f := files[0].Base()
+	phash := f.Fingerprints.For(models.FingerprintTypePhash)
+	if phash == nil {
+		return nil, nil
+	}
+
+	// only relink the scene created for the file by this scan
+	newScene, err := relinkableScene(ctx, id, start)
+	if err != nil || newScene == nil {
+		return nil, err
+	}
+
+	others, err := r.File.FindByFingerprint(ctx, *phash)
+	if err != nil {
+		return nil, fmt.Errorf("finding files by phash: %w", err)
+	}
+
+	for _, other := range others {
+		otherBase := other.Base()
+		if otherBase.ID == id || otherBase.ZipFileID != nil {
+			continue
+		}
+
+		// only relink files that are known to be missing. Other errors, such
+		// as an unmounted drive or permissions, do not mean the file has moved.
+		if _, err := mgr.FS.Lstat(otherBase.Path); !errors.Is(err, fs.ErrNotExist) {
+			if err != nil {
+				logger.Debugf("not relinking %s: %v", otherBase.Path, err)
+			}
+			continue
+		}
+
+		existing, err := r.Scene.FindByFileID(ctx, otherBase.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(existing) != 1 {
+			continue
+		}
+
+		dest := existing[0]
+		logger.Infof("%s moved to %s. Relinking scene %q...", otherBase.Path, f.Path, dest.DisplayName())
+
+		if err := mgr.SceneService.Merge(ctx, []int{newScene.ID}, dest.ID, models.NewScenePartial()); err != nil {
+			return nil, fmt.Errorf("merging scene %d into %d: %w", newScene.ID, dest.ID, err)
+		}
+
+		scenePartial := models.NewScenePartial()
+		scenePartial.PrimaryFileID = &id
+		if _, err := r.Scene.UpdatePartial(ctx, dest.ID, scenePartial); err != nil {
+			return nil, err
+		}
+
+		if err := r.File.Destroy(ctx, otherBase.ID); err != nil {
+			return nil, fmt.Errorf("destroying missing file %q: %w", otherBase.Path, err)
+		}
+
+		return &relinkedFile{
+			OldPath: otherBase.Path,
+			NewPath: f.Path,
+			Phash:   true,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// relinkableScene returns the scene of the file if it was created after start
+// and has no other files. Returns nil otherwise.
+func relinkableScene(ctx context.Context, id models.FileID, start time.Time) (*models.Scene, error) {
+	r := GetInstance().Repository
+
+	scenes, err := r.Scene.FindByFileID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scenes) != 1 || scenes[0].CreatedAt.Before(start.Truncate(time.Second)) {
+		return nil, nil
+	}
+
+	s := scenes[0]
+	if err := s.LoadFiles(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+
+	if len(s.Files.List()) != 1 {
+		return nil, nil
+	}
+
+	return s, nil
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelinkRecorder(t *testing.T) {
+	ctx := context.Background()
+	r := newRelinkRecorder()
+
+	videoFile := func(id models.FileID, path string) *models.VideoFile {
+		return &models.VideoFile{
+			BaseFile: &models.BaseFile{
+				ID:   id,
+				Path: path,
+			},
+		}
+	}
+
+	handle := func(f models.File, oldFile models.File) {
+		if err := r.Handle(ctx, f, oldFile); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+
+	// new video files
+	handle(videoFile(2, "/new/b.mp4"), nil)
+	handle(videoFile(1, "/new/a.mp4"), nil)
+
+	// new image files are ignored
+	handle(&models.ImageFile{
+		BaseFile: &models.BaseFile{ID: 3, Path: "/new/c.jpg"},
+	}, nil)
+
+	// moved file
+	handle(videoFile(4, "/new/d.mp4"), videoFile(4, "/old/d.mp4"))
+
+	// updated file at the same path
+	handle(videoFile(5, "/new/e.mp4"), videoFile(5, "/new/e.mp4"))
+
+	assert.Equal(t, []models.FileID{1, 2}, r.scannedVideoFiles())
+	assert.Equal(t, map[models.FileID]relinkedFile{
+		4: {OldPath: "/old/d.mp4", NewPath: "/new/d.mp4"},
+	}, r.relinked)
+
+	// files relinked by phash are no longer new
+	r.add(2, relinkedFile{OldPath: "/old/b.mp4", NewPath: "/new/b.mp4", Phash: true})
+	assert.Equal(t, []models.FileID{1}, r.scannedVideoFiles())
+}
@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// scanStateFile is the name of the file in the config directory that the
+// progress of the running scan is saved to.
+const scanStateFile = "scan_state.json"
+
+// scanState is the saved progress of a scan, used to resume the scan if stash
+// is restarted before it completes.
+type scanState struct {
+	Input ScanMetadataInput `json:"input"`
+	// Paths are the scan paths that the position refers to.
+	Paths    []string           `json:"paths"`
+	Position *file.ScanPosition `json:"position,omitempty"`
+}
+
+// resumePosition returns the position to resume the scan of paths from.
+// Returns nil if the saved position is for different paths.
+func (s *scanState) resumePosition(paths []string) *file.ScanPosition {
+	if s.Position == nil || len(paths) != len(s.Paths) {
+		return nil
+	}
+
+	for i, p := range paths {
+		if s.Paths[i] != p {
+			return nil
+		}
+	}
+
+	return s.Position
+}
+
+// scanStateStore reads and writes the scan state file.
+type scanStateStore struct {
+	path string
+}
+
+func (s *scanStateStore) load() (*scanState, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret scanState
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (s *scanStateStore) save(state scanState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		logger.Warnf("error encoding scan state: %v", err)
+		return
+	}
+
+	// write to a temporary file first so that the state is not corrupted if
+	// stash is stopped mid-write
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		logger.Warnf("error writing scan state: %v", err)
+		return
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		logger.Warnf("error writing scan state: %v", err)
+	}
+}
+
+func (s *scanStateStore) clear() {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warnf("error removing scan state: %v", err)
+	}
+}
+
+func (s *Manager) scanStateStore() *scanStateStore {
+	return &scanStateStore{
+		path: filepath.Join(s.Config.GetConfigPath(), scanStateFile),
+	}
+}
+
+// resumeScan restarts the scan that was running when stash was last stopped,
+// if any.
+func (s *Manager) resumeScan(ctx context.Context) {
+	store := s.scanStateStore()
+	state, err := store.load()
+	if err != nil {
+		logger.Warnf("error reading scan state: %v", err)
+		store.clear()
+		return
+	}
+
+	if state == nil {
+		return
+	}
+
+	if err := s.validateFFMPEG(); err != nil {
+		logger.Warnf("not resuming interrupted scan: %v", err)
+		return
+	}
+
+	logger.Info("Resuming interrupted scan")
+	s.addScanJob(ctx, state.Input, state)
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanStateStore(t *testing.T) {
+	store := &scanStateStore{
+		path: filepath.Join(t.TempDir(), scanStateFile),
+	}
+
+	state, err := store.load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	assert.Nil(t, state)
+
+	want := scanState{
+		Input: ScanMetadataInput{
+			Paths: []string{"/stash"},
+		},
+		Paths: []string{"/stash"},
+		Position: &file.ScanPosition{
+			Path: "/stash/a.mp4",
+		},
+	}
+	want.Input.ScanGenerateCovers = true
+
+	store.save(want)
+
+	state, err = store.load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	assert.Equal(t, &want, state)
+
+	store.clear()
+
+	state, err = store.load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	assert.Nil(t, state)
+}
+
+func TestScanState_resumePosition(t *testing.T) {
+	pos := &file.ScanPosition{
+		PathIndex: 1,
+		Path:      "/b/c.mp4",
+	}
+	state := &scanState{
+		Paths:    []string{"/a", "/b"},
+		Position: pos,
+	}
+
+	assert.Equal(t, pos, state.resumePosition([]string{"/a", "/b"}))
+	assert.Nil(t, state.resumePosition([]string{"/b", "/a"}))
+	assert.Nil(t, state.resumePosition([]string{"/a"}))
+}
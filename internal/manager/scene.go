@@ -39,6 +39,11 @@ var (
 		mimeType:  ffmpeg.MimeMp4Video,
 		extension: ".mkv",
 	}
+	av1EndpointType = endpointType{
+		label:     "AV1",
+		mimeType:  ffmpeg.MimeMp4AV1Video,
+		extension: ".av1.mp4",
+	}
 	webmEndpointType = endpointType{
 		label:     "WEBM",
 		mimeType:  ffmpeg.MimeWebmVideo,
@@ -49,6 +54,11 @@ var (
 		mimeType:  ffmpeg.MimeHLS,
 		extension: ".m3u8",
 	}
+	hlsAdaptiveEndpointType = endpointType{
+		label:     "HLS Adaptive",
+		mimeType:  ffmpeg.MimeHLS,
+		extension: "_adaptive.m3u8",
+	}
 	dashEndpointType = endpointType{
 		label:     "DASH",
 		mimeType:  ffmpeg.MimeDASH,
@@ -166,14 +176,26 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 
 	mp4Streams := []*SceneStreamEndpoint{}
 	webmStreams := []*SceneStreamEndpoint{}
-	hlsStreams := []*SceneStreamEndpoint{}
+	hlsStreams := []*SceneStreamEndpoint{
+		// adaptive stream switches between the resolutions as needed
+		makeStreamEndpoint(hlsAdaptiveEndpointType, ""),
+	}
 	dashStreams := []*SceneStreamEndpoint{}
+	av1Streams := []*SceneStreamEndpoint{}
+
+	// AV1 streams are only offered if there is an encoder for them. The
+	// codec is included in the mime type, so that clients can skip them if
+	// they cannot decode AV1.
+	includeAV1 := av1Supported()
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumOriginal) {
 		mp4Streams = append(mp4Streams, makeStreamEndpoint(mp4EndpointType, models.StreamingResolutionEnumOriginal))
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumOriginal))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumOriginal))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumOriginal))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumOriginal))
+		}
 	}
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumFourK) {
@@ -181,6 +203,9 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumFourK))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumFourK))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumFourK))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumFourK))
+		}
 	}
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumFullHd) {
@@ -188,6 +213,9 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumFullHd))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumFullHd))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumFullHd))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumFullHd))
+		}
 	}
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumStandardHd) {
@@ -195,6 +223,9 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumStandardHd))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumStandardHd))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumStandardHd))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumStandardHd))
+		}
 	}
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumStandard) {
@@ -202,6 +233,9 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumStandard))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumStandard))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumStandard))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumStandard))
+		}
 	}
 
 	if includeSceneStreamPath(models.StreamingResolutionEnumLow) {
@@ -209,16 +243,31 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 		webmStreams = append(webmStreams, makeStreamEndpoint(webmEndpointType, models.StreamingResolutionEnumLow))
 		hlsStreams = append(hlsStreams, makeStreamEndpoint(hlsEndpointType, models.StreamingResolutionEnumLow))
 		dashStreams = append(dashStreams, makeStreamEndpoint(dashEndpointType, models.StreamingResolutionEnumLow))
+		if includeAV1 {
+			av1Streams = append(av1Streams, makeStreamEndpoint(av1EndpointType, models.StreamingResolutionEnumLow))
+		}
 	}
 
 	endpoints = append(endpoints, mp4Streams...)
 	endpoints = append(endpoints, webmStreams...)
 	endpoints = append(endpoints, hlsStreams...)
 	endpoints = append(endpoints, dashStreams...)
+	endpoints = append(endpoints, av1Streams...)
 
 	return endpoints, nil
 }
 
+// av1Supported returns true if an AV1 encoder is available for live
+// transcoding.
+func av1Supported() bool {
+	encoder := GetInstance().FFMPEG
+	if encoder == nil {
+		return false
+	}
+
+	return encoder.AV1Codec(config.GetInstance().GetTranscodeHardwareAcceleration()) != nil
+}
+
 // HasTranscode returns true if a transcoded video exists for the provided
 // scene. It will check using the OSHash of the scene first, then fall back
 // to the checksum.
@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ErrCaptionNotFound is returned when the requested caption track does not
+// exist or cannot be converted to WebVTT.
+var ErrCaptionNotFound = errors.New("caption not found")
+
+// EmbeddedCaption describes a text subtitle track embedded in a video file.
+type EmbeddedCaption struct {
+	// TrackIndex is the index of the track amongst the subtitle streams of
+	// the file.
+	TrackIndex   int     `json:"track_index"`
+	LanguageCode string  `json:"language_code"`
+	Title        *string `json:"title"`
+	Codec        string  `json:"codec"`
+}
+
+// GetEmbeddedCaptions returns the embedded subtitle tracks of the scene
+// file at path that can be converted to WebVTT. The result of probing the
+// file is cached in the generated captions folder of the scene.
+func GetEmbeddedCaptions(scene *models.Scene, path string) ([]*EmbeddedCaption, error) {
+	sceneHash := scene.GetHash(instance.Config.GetVideoFileNamingAlgorithm())
+	if sceneHash == "" {
+		return probeEmbeddedCaptions(path)
+	}
+
+	cachePath := instance.Paths.Scene.GetEmbeddedCaptionsListPath(sceneHash)
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var ret []*EmbeddedCaption
+		if err := json.Unmarshal(data, &ret); err == nil {
+			return ret, nil
+		}
+	}
+
+	ret, err := probeEmbeddedCaptions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(ret)
+	if err == nil {
+		err = fsutil.WriteFile(cachePath, data)
+	}
+	if err != nil {
+		logger.Warnf("could not cache embedded captions of %s: %v", path, err)
+	}
+
+	return ret, nil
+}
+
+func probeEmbeddedCaptions(path string) ([]*EmbeddedCaption, error) {
+	probeResult, err := instance.FFProbe.NewVideoFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*EmbeddedCaption{}
+	for i, s := range probeResult.SubtitleStreams() {
+		if !ffmpeg.IsTextSubtitleCodec(s.CodecName) {
+			continue
+		}
+
+		lang := s.Tags.Language
+		if lang == "" || lang == "und" {
+			lang = video.LangUnknown
+		}
+
+		c := &EmbeddedCaption{
+			TrackIndex:   i,
+			LanguageCode: lang,
+			Codec:        s.CodecName,
+		}
+		if s.Tags.Title != "" {
+			title := s.Tags.Title
+			c.Title = &title
+		}
+
+		ret = append(ret, c)
+	}
+
+	return ret, nil
+}
+
+// GetEmbeddedCaptionVTT returns the path of the WebVTT conversion of the
+// embedded subtitle track of the file at path. The conversion is cached in
+// the generated captions folder of the scene, keyed by the scene hash and
+// track index.
+func GetEmbeddedCaptionVTT(ctx context.Context, scene *models.Scene, path string, track int) (string, error) {
+	sceneHash := scene.GetHash(instance.Config.GetVideoFileNamingAlgorithm())
+	if sceneHash == "" {
+		return "", fmt.Errorf("scene %d has no hash", scene.ID)
+	}
+
+	outputPath := instance.Paths.Scene.GetEmbeddedCaptionPath(sceneHash, track)
+	if exists, _ := fsutil.FileExists(outputPath); exists {
+		return outputPath, nil
+	}
+
+	captions, err := GetEmbeddedCaptions(scene, path)
+	if err != nil {
+		return "", err
+	}
+
+	found := false
+	for _, c := range captions {
+		if c.TrackIndex == track {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", ErrCaptionNotFound
+	}
+
+	if err := fsutil.EnsureDirAll(filepath.Dir(outputPath)); err != nil {
+		return "", err
+	}
+
+	// write to a temporary file first so that concurrent requests never
+	// serve a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(outputPath), "*.vtt.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+
+	args := transcoder.ExtractSubtitle(path, track, tmpPath)
+	if err := instance.FFMPEG.Generate(ctx, args); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("extracting subtitle track %d: %w", track, err)
+	}
+
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+
+	return outputPath, nil
+}
+
+// GetCaptionVTT returns the WebVTT conversion of the caption file, using
+// the cached conversion in the generated captions folder of the scene if
+// it is newer than the caption file.
+func GetCaptionVTT(scene *models.Scene, caption *models.VideoCaption, captionPath string) ([]byte, error) {
+	sceneHash := scene.GetHash(instance.Config.GetVideoFileNamingAlgorithm())
+	if sceneHash == "" {
+		return convertCaption(captionPath)
+	}
+
+	outputPath := instance.Paths.Scene.GetCaptionPath(sceneHash, caption.LanguageCode, caption.CaptionType)
+
+	captionInfo, err := os.Stat(captionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if cachedInfo, err := os.Stat(outputPath); err == nil && !cachedInfo.ModTime().Before(captionInfo.ModTime()) {
+		return os.ReadFile(outputPath)
+	}
+
+	ret, err := convertCaption(captionPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fsutil.WriteFile(outputPath, ret); err != nil {
+		logger.Warnf("could not cache converted caption %s: %v", captionPath, err)
+	}
+
+	return ret, nil
+}
+
+func convertCaption(captionPath string) ([]byte, error) {
+	sub, err := video.ReadSubs(captionPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading subs: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := sub.WriteToWebVTT(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
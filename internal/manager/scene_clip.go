@@ -0,0 +1,209 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+// ErrInvalidClipRange is returned when the time range of a clip is not
+// within the scene file.
+var ErrInvalidClipRange = errors.New("invalid clip range")
+
+type ExtractClipOptions struct {
+	// Start of the clip, in seconds
+	Start float64
+	// End of the clip, in seconds
+	End float64
+	// VideoCodec is the codec to re-encode the clip with. The source streams
+	// are copied if nil.
+	VideoCodec *models.ClipVideoCodec
+}
+
+func formatClipTime(t float64) string {
+	return strconv.FormatFloat(t, 'f', -1, 64)
+}
+
+// clipBasename returns the file name of a clip of the file at path.
+func clipBasename(path string, options ExtractClipOptions) string {
+	base := filepath.Base(path)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	ext := generate.ClipExtension(path, options.VideoCodec)
+
+	return fmt.Sprintf("%s.clip-%s-%s%s", stem, formatClipTime(options.Start), formatClipTime(options.End), ext)
+}
+
+// validateClip returns the primary file of the scene if the clip given by
+// options can be extracted from it. The scene files must be loaded.
+func validateClip(scene *models.Scene, options ExtractClipOptions) (*models.VideoFile, error) {
+	f := scene.Files.Primary()
+	if f == nil {
+		return nil, fmt.Errorf("scene %d has no files", scene.ID)
+	}
+
+	if f.ZipFileID != nil {
+		return nil, fmt.Errorf("extracting clips from files in zip files is not supported")
+	}
+
+	if options.Start < 0 || options.End <= options.Start || (f.Duration > 0 && options.End > f.Duration) {
+		return nil, fmt.Errorf("%w: %s-%s", ErrInvalidClipRange, formatClipTime(options.Start), formatClipTime(options.End))
+	}
+
+	return f, nil
+}
+
+// ExtractClip queues a job that extracts a clip of the scene. If
+// addToLibrary is true, the clip is added to the library as by
+// AddSceneClip. Otherwise the clip is written to the downloads directory,
+// and the returned download hash serves it once the job has finished. The
+// file name of the clip is returned with the hash. The scene files must be
+// loaded.
+func (s *Manager) ExtractClip(ctx context.Context, scene *models.Scene, options ExtractClipOptions, addToLibrary bool) (jobID int, downloadHash string, fileName string, err error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, "", "", err
+	}
+
+	f, err := validateClip(scene, options)
+	if err != nil {
+		return 0, "", "", err
+	}
+
+	outputDir := s.Paths.Generated.Downloads
+	if !addToLibrary {
+		fileName = clipBasename(f.Path, options)
+		downloadHash, err = s.DownloadStore.RegisterFile(filepath.Join(outputDir, fileName), "", false)
+		if err != nil {
+			return 0, "", "", fmt.Errorf("error registering file for download: %w", err)
+		}
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		var err error
+		if addToLibrary {
+			_, err = s.AddSceneClip(ctx, scene, options)
+		} else {
+			_, err = s.ExtractSceneClip(ctx, scene, outputDir, options)
+		}
+
+		if err != nil && !job.IsCancelled(ctx) {
+			logger.Errorf("error extracting clip of scene %q: %v", scene.DisplayName(), err)
+		}
+	})
+
+	jobID = s.JobManager.Add(ctx, fmt.Sprintf("Extracting clip of %s...", scene.DisplayName()), j)
+	return jobID, downloadHash, fileName, nil
+}
+
+// ExtractSceneClip renders the time range of the primary file of the scene
+// given by options to a new file in outputDir, and returns the path of the
+// new file. The scene files must be loaded.
+func (s *Manager) ExtractSceneClip(ctx context.Context, scene *models.Scene, outputDir string, options ExtractClipOptions) (string, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return "", err
+	}
+
+	f, err := validateClip(scene, options)
+	if err != nil {
+		return "", err
+	}
+
+	if err := fsutil.EnsureDir(outputDir); err != nil {
+		return "", err
+	}
+
+	output := filepath.Join(outputDir, clipBasename(f.Path, options))
+
+	g := &generate.Generator{
+		Encoder:      s.FFMPEG,
+		FFMpegConfig: s.Config,
+		LockManager:  s.ReadLockManager,
+		ScenePaths:   s.Paths.Scene,
+	}
+
+	if err := g.Clip(ctx, f.Path, output, generate.ClipOptions{
+		StartTime:  options.Start,
+		Duration:   options.End - options.Start,
+		VideoCodec: options.VideoCodec,
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Infof("Extracted clip %s", output)
+
+	return output, nil
+}
+
+// AddSceneClip renders a clip of the scene next to the scene file, and adds
+// it to the library as a new scene. The studio, performers and tags of the
+// scene are copied to the new scene.
+func (s *Manager) AddSceneClip(ctx context.Context, scene *models.Scene, options ExtractClipOptions) (*models.Scene, error) {
+	r := s.Repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		if err := scene.LoadFiles(ctx, r.Scene); err != nil {
+			return err
+		}
+		if err := scene.LoadPerformerIDs(ctx, r.Scene); err != nil {
+			return err
+		}
+		return scene.LoadTagIDs(ctx, r.Scene)
+	}); err != nil {
+		return nil, err
+	}
+
+	f := scene.Files.Primary()
+	if f == nil {
+		return nil, fmt.Errorf("scene %d has no files", scene.ID)
+	}
+
+	output, err := s.ExtractSceneClip(ctx, scene, filepath.Dir(f.Path), options)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.ScanFile(ctx, output)
+	if err != nil {
+		return nil, fmt.Errorf("scanning clip %s: %w", output, err)
+	}
+
+	if len(result.Scenes) == 0 {
+		return nil, fmt.Errorf("clip %s was not added as a scene", output)
+	}
+
+	clip := result.Scenes[0]
+
+	partial := models.NewScenePartial()
+	if scene.Title != "" {
+		partial.Title = models.NewOptionalString(fmt.Sprintf("%s (%s-%s)", scene.Title, formatClipTime(options.Start), formatClipTime(options.End)))
+	}
+	if scene.StudioID != nil {
+		partial.StudioID = models.NewOptionalInt(*scene.StudioID)
+	}
+	partial.PerformerIDs = &models.UpdateIDs{
+		IDs:  scene.PerformerIDs.List(),
+		Mode: models.RelationshipUpdateModeSet,
+	}
+	partial.TagIDs = &models.UpdateIDs{
+		IDs:  scene.TagIDs.List(),
+		Mode: models.RelationshipUpdateModeSet,
+	}
+
+	var ret *models.Scene
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = r.Scene.UpdatePartial(ctx, clip.ID, partial)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
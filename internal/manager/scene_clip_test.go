@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_clipBasename(t *testing.T) {
+	h264 := models.ClipVideoCodecH264
+	vp9 := models.ClipVideoCodecVp9
+
+	tests := []struct {
+		name    string
+		path    string
+		options ExtractClipOptions
+		want    string
+	}{
+		{
+			"copy",
+			"/stash/videos/scene.mkv",
+			ExtractClipOptions{Start: 65, End: 90.5},
+			"scene.clip-65-90.5.mkv",
+		},
+		{
+			"h264",
+			"/stash/videos/scene.wmv",
+			ExtractClipOptions{Start: 0, End: 30, VideoCodec: &h264},
+			"scene.clip-0-30.mp4",
+		},
+		{
+			"vp9",
+			"/stash/videos/scene.name.mp4",
+			ExtractClipOptions{Start: 1.25, End: 2, VideoCodec: &vp9},
+			"scene.name.clip-1.25-2.webm",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipBasename(tt.path, tt.options); got != tt.want {
+				t.Errorf("clipBasename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
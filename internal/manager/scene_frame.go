@@ -0,0 +1,106 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ErrInvalidFrameTime is returned when the requested frame time is outside
+// of the duration of the scene.
+var ErrInvalidFrameTime = errors.New("frame time is outside of the scene duration")
+
+// frameExtractLimit limits the number of frames that are extracted at once,
+// since scrubbing clients may request many frames in quick succession.
+var frameExtractLimit = make(chan struct{}, 4)
+
+// frameQuality is the ffmpeg quality scale used for extracted frames.
+const frameQuality = 2
+
+// frameTimeMs returns t rounded to the nearest millisecond. Frames are
+// cached at millisecond precision.
+func frameTimeMs(t float64) int64 {
+	return int64(math.Round(t * 1000))
+}
+
+// GetSceneFrame returns the path of a JPEG of the frame of the primary file
+// of the scene at t seconds, scaled to width. A width of 0, or a width
+// larger than the file, uses the width of the file. The frame is cached in
+// the generated frames folder of the scene.
+func GetSceneFrame(ctx context.Context, scene *models.Scene, t float64, width int) (string, error) {
+	f := scene.Files.Primary()
+	if f == nil {
+		return "", fmt.Errorf("scene %d has no files", scene.ID)
+	}
+
+	if t < 0 || math.IsNaN(t) || (f.Duration > 0 && t >= f.Duration) {
+		return "", ErrInvalidFrameTime
+	}
+
+	if width < 0 || width >= f.Width {
+		width = 0
+	}
+
+	sceneHash := scene.GetHash(instance.Config.GetVideoFileNamingAlgorithm())
+	if sceneHash == "" {
+		return "", fmt.Errorf("scene %d has no hash", scene.ID)
+	}
+
+	ms := frameTimeMs(t)
+	outputPath := instance.Paths.Scene.GetFramePath(sceneHash, ms, width)
+	if exists, _ := fsutil.FileExists(outputPath); exists {
+		return outputPath, nil
+	}
+
+	select {
+	case frameExtractLimit <- struct{}{}:
+		defer func() { <-frameExtractLimit }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	// another request may have extracted the frame while waiting
+	if exists, _ := fsutil.FileExists(outputPath); exists {
+		return outputPath, nil
+	}
+
+	if err := fsutil.EnsureDirAll(filepath.Dir(outputPath)); err != nil {
+		return "", err
+	}
+
+	// write to a temporary file first so that concurrent requests never
+	// serve a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(outputPath), "*.jpg.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+
+	// seeking before the input decodes up to the requested time, so the
+	// extracted frame is accurate rather than the nearest keyframe
+	args := transcoder.ScreenshotTime(f.Path, float64(ms)/1000, transcoder.ScreenshotOptions{
+		OutputPath: tmpPath,
+		OutputType: transcoder.ScreenshotOutputTypeImage2,
+		Quality:    frameQuality,
+		Width:      width,
+	})
+	if err := instance.FFMPEG.Generate(ctx, args); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("extracting frame at %vs: %w", t, err)
+	}
+
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", err
+	}
+
+	return outputPath, nil
+}
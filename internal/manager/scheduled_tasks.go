@@ -0,0 +1,221 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const scheduledTaskCheckInterval = 30 * time.Second
+
+// periodicUpdater performs periodic work when updated by the task scheduler.
+type periodicUpdater interface {
+	update(ctx context.Context, now time.Time)
+}
+
+// taskScheduler starts the configured scheduled tasks when their cron
+// schedule is due. It also updates the periodic updaters on each check, so
+// that all periodic work shares a single ticker.
+type taskScheduler struct {
+	manager  *Manager
+	updaters []periodicUpdater
+	// next is the next time that each task is due, by task key
+	next map[string]time.Time
+}
+
+func newTaskScheduler(manager *Manager, updaters ...periodicUpdater) *taskScheduler {
+	return &taskScheduler{
+		manager:  manager,
+		updaters: updaters,
+		next:     make(map[string]time.Time),
+	}
+}
+
+func scheduledTaskKey(t *config.ScheduledTask) string {
+	return fmt.Sprintf("%s|%s|%s", t.Task, t.Cron, strings.Join(t.Paths, "|"))
+}
+
+// run starts due scheduled tasks and updates the periodic updaters until ctx
+// is done.
+func (s *taskScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(scheduledTaskCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			s.update(ctx, now)
+			for _, u := range s.updaters {
+				u.update(ctx, now)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// update starts the scheduled tasks that are due.
+func (s *taskScheduler) update(ctx context.Context, now time.Time) {
+	mgr := s.manager
+	c := mgr.Config
+
+	if c.IsNewSystem() || mgr.Database.Ready() != nil {
+		return
+	}
+
+	for _, t := range s.due(c.GetScheduledTasks(), now) {
+		logger.Infof("Starting scheduled %s task", strings.ToLower(t.Task.String()))
+		if err := s.start(ctx, t); err != nil {
+			logger.Errorf("error starting scheduled %s task: %v", strings.ToLower(t.Task.String()), err)
+		}
+	}
+}
+
+// due returns the tasks whose next scheduled time is not after now, and
+// advances their next scheduled time. Tasks are first due at the first
+// scheduled time after they are seen by the scheduler.
+func (s *taskScheduler) due(tasks config.ScheduledTaskList, now time.Time) []*config.ScheduledTask {
+	var ret []*config.ScheduledTask
+	seen := make(map[string]bool)
+
+	for _, t := range tasks {
+		key := scheduledTaskKey(t)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		schedule, err := utils.ParseCron(t.Cron)
+		if err != nil {
+			logger.Warnf("invalid schedule of scheduled %s task: %v", t.Task, err)
+			continue
+		}
+
+		next, found := s.next[key]
+		if found && !now.Before(next) {
+			ret = append(ret, t)
+		}
+
+		if !found || !now.Before(next) {
+			s.next[key] = schedule.Next(now)
+		}
+	}
+
+	// forget tasks that are no longer configured
+	for key := range s.next {
+		if !seen[key] {
+			delete(s.next, key)
+		}
+	}
+
+	return ret
+}
+
+func (s *taskScheduler) start(ctx context.Context, t *config.ScheduledTask) error {
+	mgr := s.manager
+	c := mgr.Config
+
+	switch t.Task {
+	case config.ScheduledTaskTypeScan:
+		input := ScanMetadataInput{
+			Paths: t.Paths,
+		}
+		if opts := c.GetDefaultScanSettings(); opts != nil {
+			input.ScanMetadataOptions = *opts
+		}
+
+		_, err := mgr.Scan(ctx, input)
+		return err
+	case config.ScheduledTaskTypeGenerate:
+		opts := c.GetDefaultGenerateSettings()
+		if opts == nil {
+			return fmt.Errorf("default generate settings are not set")
+		}
+
+		_, err := mgr.Generate(ctx, generateInputFromOptions(*opts))
+		return err
+	case config.ScheduledTaskTypeClean:
+		mgr.Clean(ctx, CleanMetadataInput{
+			Paths: t.Paths,
+		})
+	case config.ScheduledTaskTypeAutoTag:
+		input := AutoTagMetadataInput{
+			Paths:      t.Paths,
+			Performers: []string{"*"},
+			Studios:    []string{"*"},
+			Tags:       []string{"*"},
+		}
+		if opts := c.GetDefaultAutoTagSettings(); opts != nil {
+			input.Performers = opts.Performers
+			input.Studios = opts.Studios
+			input.Tags = opts.Tags
+		}
+
+		mgr.AutoTag(ctx, input)
+	case config.ScheduledTaskTypeCleanGenerated:
+		if c.GetGeneratedRetentionDays() <= 0 {
+			return fmt.Errorf("generated retention days is not set")
+		}
+
+		mgr.CleanGenerated(ctx, CleanGeneratedInput{
+			Transcodes: true,
+			Sprites:    true,
+			Markers:    true,
+		})
+	default:
+		return fmt.Errorf("unknown task %q", t.Task)
+	}
+
+	return nil
+}
+
+func generateInputFromOptions(o models.GenerateMetadataOptions) GenerateMetadataInput {
+	ret := GenerateMetadataInput{
+		Covers:                    o.Covers,
+		Sprites:                   o.Sprites,
+		Previews:                  o.Previews,
+		ImagePreviews:             o.ImagePreviews,
+		Markers:                   o.Markers,
+		MarkerImagePreviews:       o.MarkerImagePreviews,
+		MarkerScreenshots:         o.MarkerScreenshots,
+		Transcodes:                o.Transcodes,
+		Phashes:                   o.Phashes,
+		ImagePhashes:              o.ImagePhashes,
+		ImageThumbnails:           o.ImageThumbnails,
+		InteractiveHeatmapsSpeeds: o.InteractiveHeatmapsSpeeds,
+		Waveforms:                 o.Waveforms,
+		ClipPreviews:              o.ClipPreviews,
+		ClipTranscodes:            o.ClipTranscodes,
+		ColorPalettes:             o.ColorPalettes,
+		DocumentPages:             o.DocumentPages,
+	}
+
+	if p := o.PreviewOptions; p != nil {
+		ret.PreviewOptions = &GeneratePreviewOptionsInput{
+			PreviewSegments:        p.PreviewSegments,
+			PreviewSegmentDuration: p.PreviewSegmentDuration,
+			PreviewExcludeStart:    p.PreviewExcludeStart,
+			PreviewExcludeEnd:      p.PreviewExcludeEnd,
+			PreviewPreset:          p.PreviewPreset,
+			PreviewImageFormat:     p.PreviewImageFormat,
+			PreviewVideoCodec:      p.PreviewVideoCodec,
+		}
+	}
+
+	if p := o.MarkerPreviewOptions; p != nil {
+		ret.MarkerPreviewOptions = &GenerateMarkerPreviewOptionsInput{
+			MarkerPreviewFormat:   p.MarkerPreviewFormat,
+			MarkerPreviewDuration: p.MarkerPreviewDuration,
+			MarkerPreviewWidth:    p.MarkerPreviewWidth,
+		}
+	}
+
+	return ret
+}
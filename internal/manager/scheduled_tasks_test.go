@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskScheduler_due(t *testing.T) {
+	scan := &config.ScheduledTask{Task: config.ScheduledTaskTypeScan, Cron: "15 3 * * *"}
+	clean := &config.ScheduledTask{Task: config.ScheduledTaskTypeClean, Cron: "*/30 * * * *"}
+	tasks := config.ScheduledTaskList{scan, clean}
+
+	s := newTaskScheduler(nil)
+	at := func(hour, min int) time.Time {
+		return time.Date(2023, 3, 15, hour, min, 10, 0, time.UTC)
+	}
+
+	// tasks are not due when first seen
+	assert.Empty(t, s.due(tasks, at(2, 50)))
+
+	assert.Equal(t, []*config.ScheduledTask{clean}, s.due(tasks, at(3, 0)))
+	assert.Equal(t, []*config.ScheduledTask{scan}, s.due(config.ScheduledTaskList{scan}, at(3, 15)))
+
+	// clean was removed, so is not due until a schedule after it is seen again
+	assert.Empty(t, s.due(tasks, at(3, 20)))
+	assert.Equal(t, []*config.ScheduledTask{clean}, s.due(tasks, at(3, 30)))
+
+	// not due again until the next scheduled time
+	assert.Empty(t, s.due(tasks, at(3, 31)))
+}
+
+func TestGenerateInputFromOptions(t *testing.T) {
+	segments := 5
+	got := generateInputFromOptions(models.GenerateMetadataOptions{
+		Covers:  true,
+		Phashes: true,
+		PreviewOptions: &models.GeneratePreviewOptions{
+			PreviewSegments: &segments,
+		},
+	})
+
+	assert.True(t, got.Covers)
+	assert.True(t, got.Phashes)
+	assert.False(t, got.Sprites)
+	if assert.NotNil(t, got.PreviewOptions) {
+		assert.Equal(t, &segments, got.PreviewOptions.PreviewSegments)
+	}
+}
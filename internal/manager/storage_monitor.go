@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	storageCheckInterval  = 5 * time.Minute
+	generatedSizeInterval = time.Hour
+	storageAlertTimeout   = 30 * time.Second
+
+	bytesPerGB = 1 << 30
+)
+
+// StorageVolume is the space on the volume containing a monitored path.
+type StorageVolume struct {
+	Path string `json:"path"`
+	// Size of the volume in bytes
+	Total float64 `json:"total"`
+	// Space available in bytes
+	Free float64 `json:"free"`
+	// True if the free space is below the configured minimum
+	Low bool `json:"low"`
+}
+
+// storageAlert is the body of the request posted to the storage alert
+// webhook.
+type storageAlert struct {
+	Event     string  `json:"event"`
+	Path      string  `json:"path"`
+	Free      float64 `json:"free"`
+	Total     float64 `json:"total"`
+	Threshold float64 `json:"threshold"`
+}
+
+// storageMonitor checks the free space on the volumes containing the library
+// and generated directories, and pauses space-hungry jobs while any are low.
+type storageMonitor struct {
+	config *config.Instance
+	pauser job.Pauser
+	client *http.Client
+
+	// diskUsage returns the usage of the volume containing a path
+	diskUsage func(path string) (*fsutil.DiskUsage, error)
+
+	mutex         sync.Mutex
+	volumes       []*StorageVolume
+	generatedSize *float64
+	generatedAt   time.Time
+}
+
+func newStorageMonitor(cfg *config.Instance) *storageMonitor {
+	return &storageMonitor{
+		config:    cfg,
+		client:    &http.Client{Timeout: storageAlertTimeout},
+		diskUsage: fsutil.GetDiskUsage,
+	}
+}
+
+// context returns a context for a space-hungry job, which is paused while a
+// volume is low on space.
+func (m *storageMonitor) context(ctx context.Context) context.Context {
+	return job.WithPauser(ctx, &m.pauser)
+}
+
+// run checks the volumes periodically until ctx is done.
+func (m *storageMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(storageCheckInterval)
+	defer ticker.Stop()
+
+	m.check(ctx, time.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			m.check(ctx, time.Now())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// paths returns the local library paths and the generated path.
+func (m *storageMonitor) paths() []string {
+	var ret []string
+	for _, s := range m.config.GetStashPaths() {
+		if !m.config.IsMountedPath(s.Path) {
+			ret = append(ret, s.Path)
+		}
+	}
+
+	if p := m.config.GetGeneratedPath(); p != "" {
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// check updates the usage of the volumes, alerting on those that have
+// become low on space, and pauses or resumes jobs. The size of the generated
+// directory is recalculated if it is out of date.
+func (m *storageMonitor) check(ctx context.Context, now time.Time) {
+	threshold := float64(m.config.GetMinFreeSpaceGB()) * bytesPerGB
+
+	m.mutex.Lock()
+	wasLow := make(map[string]bool)
+	for _, v := range m.volumes {
+		wasLow[v.Path] = v.Low
+	}
+	m.mutex.Unlock()
+
+	var volumes []*StorageVolume
+	var alerts []*StorageVolume
+	paused := false
+	for _, p := range m.paths() {
+		usage, err := m.diskUsage(p)
+		if err != nil {
+			logger.Warnf("error getting free space for %s: %v", p, err)
+			continue
+		}
+
+		v := &StorageVolume{
+			Path:  p,
+			Total: float64(usage.Total),
+			Free:  float64(usage.Free),
+		}
+		v.Low = threshold > 0 && v.Free < threshold
+		volumes = append(volumes, v)
+
+		if v.Low {
+			paused = true
+			if !wasLow[p] {
+				alerts = append(alerts, v)
+			}
+		}
+	}
+
+	m.mutex.Lock()
+	m.volumes = volumes
+	m.mutex.Unlock()
+
+	for _, v := range alerts {
+		logger.Warnf("Free space on the volume containing %s is low: %.1f GB of %.1f GB free", v.Path, v.Free/bytesPerGB, v.Total/bytesPerGB)
+		m.sendAlert(ctx, v, threshold)
+	}
+
+	if m.pauser.SetPaused(paused) {
+		if paused {
+			logger.Warn("Pausing generate and export jobs until free space is available")
+		} else {
+			logger.Info("Resuming generate and export jobs")
+		}
+	}
+
+	if now.Sub(m.generatedAt) >= generatedSizeInterval {
+		m.updateGeneratedSize(now)
+	}
+}
+
+// sendAlert posts the alert to the configured webhook, if set.
+func (m *storageMonitor) sendAlert(ctx context.Context, v *StorageVolume, threshold float64) {
+	url := m.config.GetStorageAlertWebhook()
+	if url == "" {
+		return
+	}
+
+	body, err := json.Marshal(storageAlert{
+		Event:     "storage_low",
+		Path:      v.Path,
+		Free:      v.Free,
+		Total:     v.Total,
+		Threshold: threshold,
+	})
+	if err != nil {
+		logger.Errorf("error encoding storage alert: %v", err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		logger.Errorf("error creating storage alert request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		logger.Warnf("error sending storage alert: %v", err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		logger.Warnf("error sending storage alert: %s", resp.Status)
+	}
+}
+
+func (m *storageMonitor) updateGeneratedSize(now time.Time) {
+	p := m.config.GetGeneratedPath()
+	if p == "" {
+		return
+	}
+
+	size, err := dirSize(p)
+	if err != nil {
+		logger.Warnf("error getting size of %s: %v", p, err)
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.generatedSize = &size
+	m.generatedAt = now
+}
+
+// dirSize returns the total size of the files in dir and its
+// subdirectories.
+func dirSize(dir string) (float64, error) {
+	var ret float64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", path, err)
+			}
+			ret += float64(info.Size())
+		}
+		return nil
+	})
+
+	return ret, err
+}
+
+func (m *storageMonitor) status() ([]*StorageVolume, *float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.volumes, m.generatedSize
+}
+
+// GetStorageVolumes returns the space on the volumes containing the library
+// and generated directories, as of the last check.
+func (s *Manager) GetStorageVolumes() []*StorageVolume {
+	ret, _ := s.storage.status()
+	return ret
+}
+
+// GetGeneratedSize returns the total size of the generated directory, or nil
+// if it has not been calculated yet.
+func (s *Manager) GetGeneratedSize() *float64 {
+	_, ret := s.storage.status()
+	return ret
+}
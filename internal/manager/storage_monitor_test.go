@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]int{
+		filepath.Join(dir, "a"): 10,
+		filepath.Join(sub, "b"): 25,
+		filepath.Join(sub, "c"): 0,
+	}
+	for p, n := range files {
+		if err := os.WriteFile(p, make([]byte, n), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, err := dirSize(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, float64(35), size)
+
+	_, err = dirSize(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -38,11 +38,21 @@ func (j *cleanJob) Execute(ctx context.Context, progress *job.Progress) {
 		logger.Infof("Running in Dry Mode")
 	}
 
-	j.cleaner.Clean(ctx, file.CleanOptions{
+	options := file.CleanOptions{
 		Paths:      j.input.Paths,
 		DryRun:     j.input.DryRun,
-		PathFilter: newCleanFilter(instance.Config),
-	}, progress)
+		PathFilter: newCleanFilter(instance.Config, instance.FS),
+	}
+
+	if days := instance.Config.GetQuarantineDays(); days > 0 {
+		options.Quarantiner = &sceneQuarantiner{
+			sceneRepository: j.repository.Scene,
+			gracePeriod:     time.Duration(days) * 24 * time.Hour,
+			now:             start,
+		}
+	}
+
+	j.cleaner.Clean(ctx, options, progress)
 
 	if job.IsCancelled(ctx) {
 		logger.Info("Stopping due to user request")
@@ -144,14 +154,16 @@ type cleanFilter struct {
 	scanFilter
 }
 
-func newCleanFilter(c *config.Instance) *cleanFilter {
+func newCleanFilter(c *config.Instance, fs models.FS) *cleanFilter {
 	return &cleanFilter{
 		scanFilter: scanFilter{
 			extensionConfig:   newExtensionConfig(c),
 			stashPaths:        c.GetStashPaths(),
+			libraries:         newLibraryConfig(c),
 			generatedPath:     c.GetGeneratedPath(),
 			videoExcludeRegex: generateRegexps(c.GetExcludes()),
 			imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+			ignore:            &file.IgnoreMatcher{FS: fs},
 		},
 	}
 }
@@ -180,6 +192,11 @@ func (f *cleanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	if f.ignore.Ignored(stash.Path, path, info.IsDir()) {
+		logger.Infof("%s excluded by a %s file. Marking to clean: \"%s\"", fileOrFolder, file.IgnoreFilename, path)
+		return false
+	}
+
 	if info.IsDir() {
 		return !f.shouldCleanFolder(path, stash)
 	}
@@ -190,7 +207,7 @@ func (f *cleanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 func (f *cleanFilter) shouldCleanFolder(path string, s *config.StashConfig) bool {
 	// only delete folders where it is excluded from everything
 	pathExcludeTest := path + string(filepath.Separator)
-	if (s.ExcludeVideo || matchFileRegex(pathExcludeTest, f.videoExcludeRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.imageExcludeRegex)) {
+	if (s.ExcludeVideo || matchFileRegex(pathExcludeTest, f.libraries.videoExcludes(s, f.videoExcludeRegex))) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.libraries.imageExcludes(s, f.imageExcludeRegex))) {
 		logger.Infof("Folder is excluded from both video and image. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -199,10 +216,17 @@ func (f *cleanFilter) shouldCleanFolder(path string, s *config.StashConfig) bool
 }
 
 func (f *cleanFilter) shouldCleanFile(path string, info fs.FileInfo, stash *config.StashConfig) bool {
+	// the size and modification time filters only narrow what is scanned, so
+	// only the extension filter is a reason to clean
+	if !info.IsDir() && stash.ExcludesFile(path) {
+		logger.Infof("File excluded by stash library extension filter. Marking to clean: \"%s\"", path)
+		return true
+	}
+
 	switch {
-	case info.IsDir() || fsutil.MatchExtension(path, f.zipExt):
+	case info.IsDir() || fsutil.MatchExtension(path, f.zipExt) || isDocument(path):
 		return f.shouldCleanGallery(path, stash)
-	case useAsVideo(path):
+	case useAsVideo(path), useAsAudio(path):
 		return f.shouldCleanVideoFile(path, stash)
 	case useAsImage(path):
 		return f.shouldCleanImage(path, stash)
@@ -218,7 +242,7 @@ func (f *cleanFilter) shouldCleanVideoFile(path string, stash *config.StashConfi
 		return true
 	}
 
-	if matchFileRegex(path, f.videoExcludeRegex) {
+	if matchFileRegex(path, f.libraries.videoExcludes(stash, f.videoExcludeRegex)) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -232,7 +256,7 @@ func (f *cleanFilter) shouldCleanGallery(path string, stash *config.StashConfig)
 		return true
 	}
 
-	if matchFileRegex(path, f.imageExcludeRegex) {
+	if matchFileRegex(path, f.libraries.imageExcludes(stash, f.imageExcludeRegex)) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -246,7 +270,7 @@ func (f *cleanFilter) shouldCleanImage(path string, stash *config.StashConfig) b
 		return true
 	}
 
-	if matchFileRegex(path, f.imageExcludeRegex) {
+	if matchFileRegex(path, f.libraries.imageExcludes(stash, f.imageExcludeRegex)) {
 		logger.Infof("File matched regex. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -254,6 +278,59 @@ func (f *cleanFilter) shouldCleanImage(path string, stash *config.StashConfig) b
 	return false
 }
 
+// sceneQuarantiner quarantines scenes whose only file is missing, rather
+// than deleting them. Scenes that have been quarantined for longer than the
+// grace period are deleted. Quarantined scenes are restored by the scan if
+// the file is found again.
+type sceneQuarantiner struct {
+	sceneRepository models.SceneReaderWriter
+	gracePeriod     time.Duration
+	now             time.Time
+}
+
+func (q *sceneQuarantiner) QuarantineFile(ctx context.Context, fileID models.FileID) (bool, error) {
+	scenes, err := q.sceneRepository.FindByFileID(ctx, fileID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(scenes) == 0 {
+		return false, nil
+	}
+
+	for _, s := range scenes {
+		if err := s.LoadFiles(ctx, q.sceneRepository); err != nil {
+			return false, err
+		}
+
+		// the file is removed from scenes with other files as usual
+		if len(s.Files.List()) > 1 {
+			return false, nil
+		}
+
+		if s.QuarantinedAt != nil && !q.now.Before(s.QuarantinedAt.Add(q.gracePeriod)) {
+			logger.Infof("Quarantine of scene %q has expired", s.DisplayName())
+			return false, nil
+		}
+	}
+
+	for _, s := range scenes {
+		if s.QuarantinedAt != nil {
+			continue
+		}
+
+		logger.Infof("Quarantining scene %q since its file is missing", s.DisplayName())
+
+		scenePartial := models.NewScenePartial()
+		scenePartial.QuarantinedAt = models.NewOptionalTime(q.now)
+		if _, err := q.sceneRepository.UpdatePartial(ctx, s.ID, scenePartial); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 type cleanHandler struct{}
 
 func (h *cleanHandler) HandleFile(ctx context.Context, fileDeleter *file.Deleter, fileID models.FileID) error {
@@ -266,6 +343,9 @@ func (h *cleanHandler) HandleFile(ctx context.Context, fileDeleter *file.Deleter
 	if err := h.handleRelatedImages(ctx, fileDeleter, fileID); err != nil {
 		return err
 	}
+	if err := h.handleRelatedAudios(ctx, fileDeleter, fileID); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -375,6 +455,47 @@ func (h *cleanHandler) handleRelatedGalleries(ctx context.Context, fileID models
 	return nil
 }
 
+func (h *cleanHandler) handleRelatedAudios(ctx context.Context, fileDeleter *file.Deleter, fileID models.FileID) error {
+	mgr := GetInstance()
+	qb := mgr.Repository.Audio
+	audios, err := qb.FindByFileID(ctx, fileID)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range audios {
+		if err := a.LoadFiles(ctx, qb); err != nil {
+			return err
+		}
+
+		// only delete if the audio has no other files
+		if len(a.Files.List()) <= 1 {
+			logger.Infof("Deleting audio %q since it has no other related files", a.DisplayName())
+			if err := mgr.AudioService.Destroy(ctx, a, fileDeleter, false); err != nil {
+				return err
+			}
+		} else {
+			// set the primary file to a remaining file
+			var newPrimaryID models.FileID
+			for _, f := range a.Files.List() {
+				if f.Base().ID != fileID {
+					newPrimaryID = f.Base().ID
+					break
+				}
+			}
+
+			audioPartial := models.NewAudioPartial()
+			audioPartial.PrimaryFileID = &newPrimaryID
+
+			if _, err := qb.UpdatePartial(ctx, a.ID, audioPartial); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (h *cleanHandler) deleteRelatedFolderGalleries(ctx context.Context, folderID models.FolderID) error {
 	mgr := GetInstance()
 	qb := mgr.Repository.Gallery
@@ -0,0 +1,261 @@
+package manager
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	spriteImageSuffix = "_sprite.jpg"
+	spriteVttSuffix   = "_thumbs.vtt"
+)
+
+// generatedGroup is a set of generated files that are only useful together,
+// and are removed together.
+type generatedGroup []string
+
+// cleanGeneratedJob removes generated files that have not been accessed
+// within the retention period. Files that have never been accessed are
+// aged from when they were generated.
+type cleanGeneratedJob struct {
+	repository    models.Repository
+	store         GeneratedAccessStore
+	recorder      *generatedAccessRecorder
+	generatedPath string
+	dirs          cleanGeneratedDirs
+	input         CleanGeneratedInput
+	retentionDays int
+}
+
+// cleanGeneratedDirs are the directories of the generated files that may be
+// cleaned. Directories that are not set are skipped.
+type cleanGeneratedDirs struct {
+	Transcodes string
+	Sprites    string
+	Markers    string
+}
+
+func (j *cleanGeneratedJob) Execute(ctx context.Context, progress *job.Progress) {
+	days := j.retentionDays
+	if j.input.OlderThanDays != nil {
+		days = *j.input.OlderThanDays
+	}
+
+	if days <= 0 {
+		logger.Error("Not cleaning generated files: the retention period is not set")
+		return
+	}
+
+	begin := time.Now()
+	cutoff := begin.AddDate(0, 0, -days)
+
+	if err := j.recorder.flush(ctx); err != nil {
+		logger.Errorf("Error recording generated file access times: %v", err)
+		return
+	}
+
+	var accessed map[string]time.Time
+	if err := j.repository.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		accessed, err = j.store.GetGeneratedAccessTimes(ctx)
+		return err
+	}); err != nil {
+		logger.Errorf("Error getting generated file access times: %v", err)
+		return
+	}
+
+	var groups []generatedGroup
+	progress.ExecuteTask("Finding generated files", func() {
+		groups = j.findGroups()
+	})
+
+	progress.SetTotal(len(groups))
+
+	deleted := 0
+	for _, g := range groups {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		if j.lastAccess(g, accessed).Before(cutoff) {
+			deleted += j.deleteGroup(g)
+		}
+
+		progress.Increment()
+	}
+
+	if !j.input.DryRun {
+		if j.dirs.Markers != "" {
+			removeEmptyDirs(j.dirs.Markers)
+		}
+
+		j.pruneAccessTimes(ctx, accessed)
+	}
+
+	logger.Infof("Removed %d generated files not accessed in %d days after %s", deleted, days, time.Since(begin))
+}
+
+// findGroups returns the generated files of the selected types.
+func (j *cleanGeneratedJob) findGroups() []generatedGroup {
+	var ret []generatedGroup
+
+	if j.input.Transcodes && j.dirs.Transcodes != "" {
+		for _, p := range listGeneratedFiles(j.dirs.Transcodes) {
+			ret = append(ret, generatedGroup{p})
+		}
+	}
+
+	if j.input.Sprites && j.dirs.Sprites != "" {
+		ret = append(ret, groupSprites(listGeneratedFiles(j.dirs.Sprites))...)
+	}
+
+	if j.input.Markers && j.dirs.Markers != "" {
+		for _, p := range listGeneratedFiles(j.dirs.Markers) {
+			ret = append(ret, generatedGroup{p})
+		}
+	}
+
+	return ret
+}
+
+// listGeneratedFiles returns the files within dir and its subdirectories.
+func listGeneratedFiles(dir string) []string {
+	var ret []string
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logger.Warnf("error reading %s: %v", path, err)
+			return nil
+		}
+
+		if !d.IsDir() {
+			ret = append(ret, path)
+		}
+		return nil
+	}); err != nil {
+		logger.Warnf("error reading %s: %v", dir, err)
+	}
+
+	return ret
+}
+
+// groupSprites groups the sprite image and vtt file of each scene. Other
+// files are not returned.
+func groupSprites(files []string) []generatedGroup {
+	var ret []generatedGroup
+	index := make(map[string]int)
+	for _, p := range files {
+		var hash string
+		switch {
+		case strings.HasSuffix(p, spriteImageSuffix):
+			hash = strings.TrimSuffix(p, spriteImageSuffix)
+		case strings.HasSuffix(p, spriteVttSuffix):
+			hash = strings.TrimSuffix(p, spriteVttSuffix)
+		default:
+			continue
+		}
+
+		if i, found := index[hash]; found {
+			ret[i] = append(ret[i], p)
+			continue
+		}
+
+		index[hash] = len(ret)
+		ret = append(ret, generatedGroup{p})
+	}
+
+	return ret
+}
+
+// lastAccess returns the latest access or modification time of the files in
+// the group.
+func (j *cleanGeneratedJob) lastAccess(g generatedGroup, accessed map[string]time.Time) time.Time {
+	var ret time.Time
+	for _, p := range g {
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(ret) {
+			ret = info.ModTime()
+		}
+
+		if rel, ok := generatedRelPath(j.generatedPath, p); ok {
+			if t := accessed[rel]; t.After(ret) {
+				ret = t
+			}
+		}
+	}
+
+	return ret
+}
+
+// deleteGroup removes the files in the group, and returns the number of
+// files removed.
+func (j *cleanGeneratedJob) deleteGroup(g generatedGroup) int {
+	ret := 0
+	for _, p := range g {
+		if j.input.DryRun {
+			logger.Infof("Would remove unused generated file %s", p)
+			ret++
+			continue
+		}
+
+		if err := os.Remove(p); err != nil {
+			logger.Warnf("error removing %s: %v", p, err)
+			continue
+		}
+
+		logger.Debugf("Removed unused generated file %s", p)
+		ret++
+	}
+
+	return ret
+}
+
+// pruneAccessTimes removes the access times of files that no longer exist.
+func (j *cleanGeneratedJob) pruneAccessTimes(ctx context.Context, accessed map[string]time.Time) {
+	var missing []string
+	for rel := range accessed {
+		if _, err := os.Stat(filepath.Join(j.generatedPath, filepath.FromSlash(rel))); os.IsNotExist(err) {
+			missing = append(missing, rel)
+		}
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	if err := j.repository.WithTxn(ctx, func(ctx context.Context) error {
+		return j.store.DeleteGeneratedAccessTimes(ctx, missing)
+	}); err != nil {
+		logger.Warnf("error removing generated file access times: %v", err)
+	}
+}
+
+// removeEmptyDirs removes the empty directories within dir.
+func removeEmptyDirs(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		p := filepath.Join(dir, e.Name())
+		removeEmptyDirs(p)
+
+		if sub, err := os.ReadDir(p); err == nil && len(sub) == 0 {
+			if err := os.Remove(p); err != nil {
+				logger.Warnf("error removing %s: %v", p, err)
+			}
+		}
+	}
+}
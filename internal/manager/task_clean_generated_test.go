@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedRelPath(t *testing.T) {
+	generated := filepath.Join("data", "generated")
+
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{filepath.Join(generated, "vtt", "abc_sprite.jpg"), "vtt/abc_sprite.jpg", true},
+		{filepath.Join(generated, "markers", "abc", "10.mp4"), "markers/abc/10.mp4", true},
+		{generated, "", false},
+		{filepath.Join("data", "scene.mp4"), "", false},
+		{filepath.Join("data", "generated2", "a.mp4"), "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := generatedRelPath(generated, tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+}
+
+func TestGroupSprites(t *testing.T) {
+	files := []string{
+		"a_sprite.jpg",
+		"a_thumbs.vtt",
+		"b_thumbs.vtt",
+		"other.txt",
+	}
+
+	assert.Equal(t, []generatedGroup{
+		{"a_sprite.jpg", "a_thumbs.vtt"},
+		{"b_thumbs.vtt"},
+	}, groupSprites(files))
+}
+
+func TestCleanGeneratedJob_lastAccess(t *testing.T) {
+	generated := t.TempDir()
+	vtt := filepath.Join(generated, "vtt")
+	if err := os.MkdirAll(vtt, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	sprite := filepath.Join(vtt, "a"+spriteImageSuffix)
+	thumbs := filepath.Join(vtt, "a"+spriteVttSuffix)
+	for _, p := range []string{sprite, thumbs} {
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	j := &cleanGeneratedJob{generatedPath: generated}
+	g := generatedGroup{sprite, thumbs}
+
+	// never accessed files are aged from when they were generated
+	assert.True(t, j.lastAccess(g, nil).Equal(modTime))
+
+	// the latest access of any file in the group is used
+	accessed := modTime.AddDate(0, 1, 0)
+	assert.True(t, j.lastAccess(g, map[string]time.Time{
+		"vtt/a" + spriteVttSuffix: accessed,
+	}).Equal(accessed))
+}
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSceneQuarantiner_QuarantineFile(t *testing.T) {
+	const (
+		singleFileID models.FileID = iota + 1
+		multiFileID
+		expiredFileID
+		noSceneFileID
+	)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiredAt := now.AddDate(0, 0, -31)
+
+	ctx := context.Background()
+	db := mocks.NewDatabase()
+	qb := db.Scene
+
+	qb.On("FindByFileID", ctx, singleFileID).Return([]*models.Scene{{ID: 1}}, nil)
+	qb.On("FindByFileID", ctx, multiFileID).Return([]*models.Scene{{ID: 2}}, nil)
+	qb.On("FindByFileID", ctx, expiredFileID).Return([]*models.Scene{{ID: 3, QuarantinedAt: &expiredAt}}, nil)
+	qb.On("FindByFileID", ctx, noSceneFileID).Return(nil, nil)
+
+	qb.On("GetFiles", ctx, 1).Return([]*models.VideoFile{{BaseFile: &models.BaseFile{ID: singleFileID}}}, nil)
+	qb.On("GetFiles", ctx, 2).Return([]*models.VideoFile{
+		{BaseFile: &models.BaseFile{ID: multiFileID}},
+		{BaseFile: &models.BaseFile{ID: 10}},
+	}, nil)
+	qb.On("GetFiles", ctx, 3).Return([]*models.VideoFile{{BaseFile: &models.BaseFile{ID: expiredFileID}}}, nil)
+
+	qb.On("UpdatePartial", ctx, 1, mock.MatchedBy(func(p models.ScenePartial) bool {
+		return p.QuarantinedAt.Set && p.QuarantinedAt.Value.Equal(now)
+	})).Return(&models.Scene{ID: 1}, nil).Once()
+
+	q := &sceneQuarantiner{
+		sceneRepository: qb,
+		gracePeriod:     30 * 24 * time.Hour,
+		now:             now,
+	}
+
+	tests := []struct {
+		name   string
+		fileID models.FileID
+		want   bool
+	}{
+		{"only file", singleFileID, true},
+		{"other files", multiFileID, false},
+		{"expired", expiredFileID, false},
+		{"no scene", noSceneFileID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := q.QuarantineFile(ctx, tt.fileID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	qb.AssertExpectations(t)
+}
@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+type OrphanedRowsRepairer interface {
+	FindOrphanedRows(ctx context.Context) ([]sqlite.OrphanedRows, error)
+	DeleteOrphanedRows(ctx context.Context) ([]sqlite.OrphanedRows, error)
+}
+
+// DatabaseDoctorJob detects rows that reference objects that no longer exist.
+// If Repair is set, the rows are deleted in a single transaction.
+type DatabaseDoctorJob struct {
+	Repository models.Repository
+	Repairer   OrphanedRowsRepairer
+	Repair     bool
+}
+
+func (j *DatabaseDoctorJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Info("Checking database for inconsistent rows")
+	progress.SetTotal(1)
+
+	start := time.Now()
+
+	var rows []sqlite.OrphanedRows
+	var err error
+
+	if j.Repair {
+		progress.ExecuteTask("Repairing inconsistent rows", func() {
+			err = j.Repository.WithTxn(ctx, func(ctx context.Context) error {
+				rows, err = j.Repairer.DeleteOrphanedRows(ctx)
+				return err
+			})
+			progress.Increment()
+		})
+	} else {
+		progress.ExecuteTask("Finding inconsistent rows", func() {
+			err = j.Repository.WithReadTxn(ctx, func(ctx context.Context) error {
+				rows, err = j.Repairer.FindOrphanedRows(ctx)
+				return err
+			})
+			progress.Increment()
+		})
+	}
+
+	if err != nil {
+		logger.Errorf("Error checking database: %v", err)
+		return
+	}
+
+	if len(rows) == 0 {
+		logger.Info("No inconsistent rows found")
+	}
+
+	for _, r := range rows {
+		if j.Repair {
+			logger.Infof("Deleted %d rows: %s", r.Count, r.Type)
+		} else {
+			logger.Warnf("Found %d rows: %s", r.Count, r.Type)
+		}
+	}
+
+	elapsed := time.Since(start)
+	logger.Infof("Finished checking database after %s", elapsed)
+}
+
+type CaseDuplicatePathsMerger interface {
+	MergeCaseDuplicatePaths(ctx context.Context) (*sqlite.MergedPaths, error)
+}
+
+// MergeCaseDuplicatePathsJob merges folders and files whose paths differ
+// only by case in a single transaction.
+type MergeCaseDuplicatePathsJob struct {
+	Repository models.Repository
+	Merger     CaseDuplicatePathsMerger
+}
+
+func (j *MergeCaseDuplicatePathsJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Info("Merging paths that differ only by case")
+	progress.SetTotal(1)
+
+	start := time.Now()
+
+	var merged *sqlite.MergedPaths
+	var err error
+
+	progress.ExecuteTask("Merging duplicate paths", func() {
+		err = j.Repository.WithTxn(ctx, func(ctx context.Context) error {
+			merged, err = j.Merger.MergeCaseDuplicatePaths(ctx)
+			return err
+		})
+		progress.Increment()
+	})
+
+	if err != nil {
+		logger.Errorf("Error merging duplicate paths: %v", err)
+		return
+	}
+
+	logger.Infof("Merged %d folders and %d files after %s", merged.Folders, merged.Files, time.Since(start))
+}
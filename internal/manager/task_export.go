@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
@@ -46,15 +47,59 @@ type ExportTask struct {
 	tags       *exportSpec
 	studios    *exportSpec
 	galleries  *exportSpec
+	markers    *exportSpec
+
+	// markerScenes are the selected markers of scenes that are exported only
+	// because of their selected markers, keyed by scene id. Only the selected
+	// markers of these scenes are exported.
+	markerScenes map[int][]*models.SceneMarker
 
 	includeDependencies bool
+	markerChapters      bool
+	zipContents         bool
+	filenameTemplate    *jsonschema.FilenameTemplate
+	format              ExportFormatEnum
+	imageLayout         ExportImageLayoutEnum
+	exclude             exportExclusions
+
+	// files and folders may be shared between objects exported by different
+	// workers. Track exported ids so that each is written only once.
+	exportedFiles   exportedSet[models.FileID]
+	exportedFolders exportedSet[models.FolderID]
+
+	// if set, objects that are current in the remote manifest are skipped
+	manifest *exportManifest
 
 	DownloadHash string
 }
 
+// exportedSet is a set of ids that is safe for concurrent use.
+type exportedSet[T comparable] struct {
+	mutex sync.Mutex
+	ids   map[T]struct{}
+}
+
+// add adds id to the set. Returns false if id was already present.
+func (s *exportedSet[T]) add(id T) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ids == nil {
+		s.ids = make(map[T]struct{})
+	}
+
+	if _, found := s.ids[id]; found {
+		return false
+	}
+
+	s.ids[id] = struct{}{}
+	return true
+}
+
 type ExportObjectTypeInput struct {
-	Ids []string `json:"ids"`
-	All *bool    `json:"all"`
+	Ids            []string `json:"ids"`
+	SelectionSetID *string  `json:"selectionSetID"`
+	All            *bool    `json:"all"`
 }
 
 type ExportObjectsInput struct {
@@ -65,7 +110,15 @@ type ExportObjectsInput struct {
 	Tags                *ExportObjectTypeInput `json:"tags"`
 	Movies              *ExportObjectTypeInput `json:"movies"`
 	Galleries           *ExportObjectTypeInput `json:"galleries"`
+	Markers             *ExportObjectTypeInput `json:"markers"`
 	IncludeDependencies *bool                  `json:"includeDependencies"`
+	MarkerChapters      *bool                  `json:"markerChapters"`
+	ZipContents         *bool                  `json:"zipContents"`
+	FilenameTemplate    *string                `json:"filenameTemplate"`
+	Format              *ExportFormatEnum      `json:"format"`
+	ImageLayout         *ExportImageLayoutEnum `json:"imageLayout"`
+	RemoteManifest      *graphql.Upload        `json:"remoteManifest"`
+	Exclude             []ExportExcludeEnum    `json:"exclude"`
 }
 
 type exportSpec struct {
@@ -91,12 +144,51 @@ func newExportSpec(input *ExportObjectTypeInput) *exportSpec {
 	return ret
 }
 
-func CreateExportTask(a models.HashAlgorithm, input ExportObjectsInput) *ExportTask {
+func CreateExportTask(a models.HashAlgorithm, input ExportObjectsInput) (*ExportTask, error) {
 	includeDeps := false
 	if input.IncludeDependencies != nil {
 		includeDeps = *input.IncludeDependencies
 	}
 
+	markerChapters := false
+	if input.MarkerChapters != nil {
+		markerChapters = *input.MarkerChapters
+	}
+
+	zipContents := false
+	if input.ZipContents != nil {
+		zipContents = *input.ZipContents
+	}
+
+	format := ExportFormatEnumJSON
+	if input.Format != nil {
+		format = *input.Format
+	}
+
+	imageLayout := ExportImageLayoutEnumEmbedded
+	if input.ImageLayout != nil {
+		imageLayout = *input.ImageLayout
+	}
+
+	var filenameTemplate *jsonschema.FilenameTemplate
+	if input.FilenameTemplate != nil && *input.FilenameTemplate != "" {
+		var err error
+		filenameTemplate, err = jsonschema.ParseFilenameTemplate(*input.FilenameTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest *exportManifest
+	if input.RemoteManifest != nil {
+		remote, err := jsonschema.LoadManifest(input.RemoteManifest.File)
+		if err != nil {
+			return nil, fmt.Errorf("reading remote manifest: %w", err)
+		}
+
+		manifest = newExportManifest(remote)
+	}
+
 	return &ExportTask{
 		repository:          GetInstance().Repository,
 		fileNamingAlgorithm: a,
@@ -107,10 +199,59 @@ func CreateExportTask(a models.HashAlgorithm, input ExportObjectsInput) *ExportT
 		tags:                newExportSpec(input.Tags),
 		studios:             newExportSpec(input.Studios),
 		galleries:           newExportSpec(input.Galleries),
+		markers:             newExportSpec(input.Markers),
 		includeDependencies: includeDeps,
+		markerChapters:      markerChapters,
+		zipContents:         zipContents,
+		filenameTemplate:    filenameTemplate,
+		format:              format,
+		imageLayout:         imageLayout,
+		exclude:             newExportExclusions(input.Exclude),
+		manifest:            manifest,
+	}, nil
+}
+
+// CreateExportManifestTask returns a task that generates a manifest of all
+// objects, for use as the remote manifest of an export from another instance.
+func CreateExportManifestTask(a models.HashAlgorithm) *ExportTask {
+	manifest := newExportManifest(nil)
+	manifest.manifestOnly = true
+
+	return &ExportTask{
+		repository:          GetInstance().Repository,
+		fileNamingAlgorithm: a,
+		scenes:              &exportSpec{all: true},
+		images:              &exportSpec{all: true},
+		performers:          &exportSpec{all: true},
+		movies:              &exportSpec{all: true},
+		tags:                &exportSpec{all: true},
+		studios:             &exportSpec{all: true},
+		galleries:           &exportSpec{all: true},
+		manifest:            manifest,
 	}
 }
 
+// templateFilename returns the filename generated by the filename template.
+// Returns def if no template is set, or if the template fails or produces an
+// empty filename.
+func (t *ExportTask) templateFilename(data jsonschema.FilenameTemplateData, def string) string {
+	if t.filenameTemplate == nil {
+		return def
+	}
+
+	fn, err := t.filenameTemplate.Filename(data)
+	if err != nil {
+		logger.Warnf("error generating export filename: %v", err)
+		return def
+	}
+
+	if fn == "" {
+		return def
+	}
+
+	return fn
+}
+
 func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	// @manager.total = Scene.count + Gallery.count + Performer.count + Studio.count + Movie.count
@@ -142,12 +283,18 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 	}
 
 	t.json = jsonUtils{
-		json: *paths.GetJSONPaths(t.baseDir),
+		json:     *paths.GetJSONPaths(t.baseDir),
+		throttle: newExportThrottle(),
+		counts:   newObjectCounts(),
 	}
 
 	paths.EmptyJSONDirs(t.baseDir)
 	paths.EnsureJSONDirs(t.baseDir)
 
+	if t.format == ExportFormatEnumNdjson {
+		t.json.ndjson = newNDJSONWriters(t.json.throttle)
+	}
+
 	txnErr := t.repository.WithTxn(ctx, func(ctx context.Context) error {
 		// include movie scenes and gallery images
 		if !t.full {
@@ -160,6 +307,12 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 			if !t.images.all {
 				t.populateGalleryImages(ctx)
 			}
+
+			// markers are exported with their scenes, so always export the
+			// scenes of selected markers
+			if !t.scenes.all {
+				t.populateMarkerScenes(ctx)
+			}
 		}
 
 		t.ExportScenes(ctx, workerCount)
@@ -176,8 +329,21 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 		logger.Warnf("error while running export transaction: %v", txnErr)
 	}
 
-	if !t.full {
-		err := t.generateDownload()
+	if t.json.ndjson != nil {
+		t.json.ndjson.close()
+	}
+
+	if t.full {
+		if err := t.writeArchiveManifest(); err != nil {
+			logger.Errorf("error writing export manifest: %v", err)
+		}
+	} else {
+		var err error
+		if t.manifest != nil && t.manifest.manifestOnly {
+			err = t.generateManifestDownload()
+		} else {
+			err = t.generateDownload()
+		}
 		if err != nil {
 			logger.Errorf("error generating download link: %s", err.Error())
 			return
@@ -186,6 +352,13 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 	logger.Infof("Export complete in %s.", time.Since(startTime))
 }
 
+// newExportThrottle returns a throttle limiting export writes to the
+// configured rates. Returns nil if export writes are unlimited.
+func newExportThrottle() *fsutil.Throttle {
+	c := config.GetInstance()
+	return fsutil.NewThrottle(int64(c.GetExportMaxThroughputMB())*1024*1024, c.GetExportMaxIOPS())
+}
+
 func (t *ExportTask) generateDownload() error {
 	// zip the files and register a download link
 	if err := fsutil.EnsureDir(instance.Paths.Generated.Downloads); err != nil {
@@ -210,69 +383,144 @@ func (t *ExportTask) generateDownload() error {
 	return nil
 }
 
+func (t *ExportTask) generateManifestDownload() error {
+	if err := fsutil.EnsureDir(instance.Paths.Generated.Downloads); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(instance.Paths.Generated.Downloads, "manifest*.json")
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	if err := jsonschema.SaveManifestFile(f.Name(), t.manifest.manifest()); err != nil {
+		return err
+	}
+
+	t.DownloadHash, err = instance.DownloadStore.RegisterFile(f.Name(), "application/json", false)
+	if err != nil {
+		return fmt.Errorf("error registering file for download: %w", err)
+	}
+	logger.Debugf("Generated manifest file %s with hash %s", f.Name(), t.DownloadHash)
+	return nil
+}
+
 func (t *ExportTask) zipFiles(w io.Writer) error {
 	z := zip.NewWriter(w)
 	defer z.Close()
 
-	u := jsonUtils{
-		json: *paths.GetJSONPaths(""),
-	}
+	manifest := t.newArchiveManifest()
 
-	walkWarn(t.json.json.Tags, t.zipWalkFunc(u.json.Tags, z))
-	walkWarn(t.json.json.Galleries, t.zipWalkFunc(u.json.Galleries, z))
-	walkWarn(t.json.json.Performers, t.zipWalkFunc(u.json.Performers, z))
-	walkWarn(t.json.json.Studios, t.zipWalkFunc(u.json.Studios, z))
-	walkWarn(t.json.json.Movies, t.zipWalkFunc(u.json.Movies, z))
-	walkWarn(t.json.json.Scenes, t.zipWalkFunc(u.json.Scenes, z))
-	walkWarn(t.json.json.Images, t.zipWalkFunc(u.json.Images, z))
+	for _, f := range t.writtenFiles() {
+		af, err := t.zipFile(f, z)
+		if err != nil {
+			logger.Warnf("error zipping %s: %v", f.path, err)
+			continue
+		}
 
-	return nil
+		manifest.Files = append(manifest.Files, af)
+	}
+
+	return zipArchiveManifest(manifest, z)
 }
 
-// like filepath.Walk but issue a warning on error
-func walkWarn(root string, fn filepath.WalkFunc) {
-	if err := filepath.Walk(root, fn); err != nil {
-		logger.Warnf("error walking structure %v: %v", root, err)
-	}
+// exportedFile is a file written by the export.
+type exportedFile struct {
+	path string
+	// archivePath is the slash-separated path of the file, relative to the
+	// export directory
+	archivePath string
 }
 
-func (t *ExportTask) zipWalkFunc(outDir string, z *zip.Writer) filepath.WalkFunc {
-	return func(path string, info os.FileInfo, err error) error {
+// writtenFiles returns the files written by the export that are included in
+// the export zip file.
+func (t *ExportTask) writtenFiles() []exportedFile {
+	var ret []exportedFile
+
+	add := func(fn string) {
+		rel, err := filepath.Rel(t.baseDir, fn)
 		if err != nil {
-			return err
+			logger.Warnf("error getting relative path of %s: %v", fn, err)
+			return
 		}
 
-		if info.IsDir() {
+		ret = append(ret, exportedFile{
+			path:        fn,
+			archivePath: filepath.ToSlash(rel),
+		})
+	}
+
+	walk := func(root string) {
+		walkWarn(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !info.IsDir() {
+				add(path)
+			}
+
 			return nil
+		})
+	}
+
+	walk(t.json.json.Tags)
+	walk(t.json.json.Galleries)
+	walk(t.json.json.Performers)
+	walk(t.json.json.Studios)
+	walk(t.json.json.Movies)
+	walk(t.json.json.Scenes)
+	walk(t.json.json.Images)
+
+	// NDJSON files are written alongside the object directories
+	for _, dir := range t.json.json.ObjectDirs() {
+		fn := paths.NDJSONPath(dir)
+		if exists, _ := fsutil.FileExists(fn); exists {
+			add(fn)
 		}
+	}
 
-		return t.zipFile(path, outDir, z)
+	if t.markerChapters {
+		walk(t.json.json.Chapters)
 	}
-}
 
-func (t *ExportTask) zipFile(fn, outDir string, z *zip.Writer) error {
-	bn := filepath.Base(fn)
+	if t.imageLayout == ExportImageLayoutEnumFiles {
+		for _, dir := range objectImageDirs {
+			walk(filepath.Join(t.json.json.ObjectImages, dir))
+		}
+	}
 
-	p := filepath.Join(outDir, bn)
-	p = filepath.ToSlash(p)
+	return ret
+}
 
-	f, err := z.Create(p)
+// like filepath.Walk but issue a warning on error
+func walkWarn(root string, fn filepath.WalkFunc) {
+	if err := filepath.Walk(root, fn); err != nil {
+		logger.Warnf("error walking structure %v: %v", root, err)
+	}
+}
+
+// zipFile adds f to the zip file and returns its manifest entry.
+func (t *ExportTask) zipFile(f exportedFile, z *zip.Writer) (jsonschema.ArchiveFile, error) {
+	w, err := z.Create(f.archivePath)
 	if err != nil {
-		return fmt.Errorf("error creating zip entry for %s: %s", fn, err.Error())
+		return jsonschema.ArchiveFile{}, fmt.Errorf("error creating zip entry for %s: %s", f.path, err.Error())
 	}
 
-	i, err := os.Open(fn)
+	i, err := os.Open(f.path)
 	if err != nil {
-		return fmt.Errorf("error opening %s: %s", fn, err.Error())
+		return jsonschema.ArchiveFile{}, fmt.Errorf("error opening %s: %s", f.path, err.Error())
 	}
 
 	defer i.Close()
 
-	if _, err := io.Copy(f, i); err != nil {
-		return fmt.Errorf("error writing %s to zip: %s", fn, err.Error())
+	// the file is hashed as it is written to the zip
+	af, err := jsonschema.NewArchiveFile(f.archivePath, io.TeeReader(i, t.json.throttle.Writer(w)))
+	if err != nil {
+		return jsonschema.ArchiveFile{}, fmt.Errorf("error writing %s to zip: %s", f.path, err.Error())
 	}
 
-	return nil
+	return af, nil
 }
 
 func (t *ExportTask) populateMovieScenes(ctx context.Context) {
@@ -306,6 +554,45 @@ func (t *ExportTask) populateMovieScenes(ctx context.Context) {
 	}
 }
 
+// populateMarkerScenes adds the scenes of the selected markers to the
+// exported scenes. Scenes that are not otherwise selected are exported with
+// the selected markers only.
+func (t *ExportTask) populateMarkerScenes(ctx context.Context) {
+	reader := t.repository.SceneMarker
+
+	var markers []*models.SceneMarker
+	var err error
+	if t.markers.all {
+		markers, err = reader.All(ctx)
+	} else if len(t.markers.IDs) > 0 {
+		markers, err = reader.FindMany(ctx, t.markers.IDs)
+	}
+
+	if err != nil {
+		logger.Errorf("[markers] failed to fetch markers: %v", err)
+	}
+
+	selected := make(map[int]bool)
+	for _, id := range t.scenes.IDs {
+		selected[id] = true
+	}
+
+	for _, m := range markers {
+		if m == nil || selected[m.SceneID] {
+			continue
+		}
+
+		if t.markerScenes == nil {
+			t.markerScenes = make(map[int][]*models.SceneMarker)
+		}
+
+		if _, found := t.markerScenes[m.SceneID]; !found {
+			t.scenes.IDs = append(t.scenes.IDs, m.SceneID)
+		}
+		t.markerScenes[m.SceneID] = append(t.markerScenes[m.SceneID], m)
+	}
+}
+
 func (t *ExportTask) populateGalleryImages(ctx context.Context) {
 	r := t.repository
 	reader := r.Gallery
@@ -386,7 +673,11 @@ func (t *ExportTask) ExportScenes(ctx context.Context, workers int) {
 }
 
 func (t *ExportTask) exportFile(f models.File) {
-	newFileJSON := fileToJSON(f)
+	if t.exclude[ExportExcludeEnumFilePaths] || !t.exportedFiles.add(f.Base().ID) {
+		return
+	}
+
+	newFileJSON := fileToJSON(f, t.exclude[ExportExcludeEnumFingerprints])
 
 	fn := newFileJSON.Filename()
 
@@ -395,7 +686,9 @@ func (t *ExportTask) exportFile(f models.File) {
 	}
 }
 
-func fileToJSON(f models.File) jsonschema.DirEntry {
+// fileToJSON returns the export JSON of f. Fingerprints are omitted if
+// excludeFingerprints is true.
+func fileToJSON(f models.File, excludeFingerprints bool) jsonschema.DirEntry {
 	bf := f.Base()
 
 	base := jsonschema.BaseFile{
@@ -414,6 +707,10 @@ func fileToJSON(f models.File) jsonschema.DirEntry {
 	}
 
 	for _, fp := range bf.Fingerprints {
+		if excludeFingerprints {
+			break
+		}
+
 		base.Fingerprints = append(base.Fingerprints, jsonschema.Fingerprint{
 			Type:        fp.Type,
 			Fingerprint: fp.Fingerprint,
@@ -435,6 +732,10 @@ func fileToJSON(f models.File) jsonschema.DirEntry {
 			BitRate:          ff.BitRate,
 			Interactive:      ff.Interactive,
 			InteractiveSpeed: ff.InteractiveSpeed,
+
+			InteractiveAverageSpeed:  ff.InteractiveAverageSpeed,
+			InteractiveMaxSpeed:      ff.InteractiveMaxSpeed,
+			InteractiveActionDensity: ff.InteractiveActionDensity,
 		}
 	case *models.ImageFile:
 		base.Type = jsonschema.DirEntryTypeImage
@@ -450,6 +751,10 @@ func fileToJSON(f models.File) jsonschema.DirEntry {
 }
 
 func (t *ExportTask) exportFolder(f models.Folder) {
+	if t.exclude[ExportExcludeEnumFilePaths] || !t.exportedFolders.add(f.ID) {
+		return
+	}
+
 	newFileJSON := folderToJSON(f)
 
 	fn := newFileJSON.Filename()
@@ -500,17 +805,24 @@ func (t *ExportTask) exportScene(ctx context.Context, wg *sync.WaitGroup, jobCha
 			continue
 		}
 
-		// export files
-		for _, f := range s.Files.List() {
-			t.exportFile(f)
-		}
-
 		newSceneJSON.Studio, err = scene.GetStudioName(ctx, studioReader, s)
 		if err != nil {
 			logger.Errorf("[scenes] <%s> error getting scene studio name: %s", sceneHash, err.Error())
 			continue
 		}
 
+		newSceneJSON.CustomFields, err = sceneReader.GetCustomFields(ctx, s.ID)
+		if err != nil {
+			logger.Errorf("[scenes] <%s> error getting scene custom fields: %v", sceneHash, err)
+			continue
+		}
+
+		newSceneJSON.ODates, newSceneJSON.RatingHistory, err = historyToJSON(ctx, sceneReader, s.ID)
+		if err != nil {
+			logger.Errorf("[scenes] <%s> error getting scene history: %v", sceneHash, err)
+			continue
+		}
+
 		galleries, err := galleryReader.FindBySceneID(ctx, s.ID)
 		if err != nil {
 			logger.Errorf("[scenes] <%s> error getting scene gallery checksums: %s", sceneHash, err.Error())
@@ -544,7 +856,11 @@ func (t *ExportTask) exportScene(ctx context.Context, wg *sync.WaitGroup, jobCha
 			continue
 		}
 
-		newSceneJSON.Markers, err = scene.GetSceneMarkersJSON(ctx, sceneMarkerReader, tagReader, s)
+		if markers, found := t.markerScenes[s.ID]; found {
+			newSceneJSON.Markers, err = scene.SceneMarkersToJSON(ctx, tagReader, markers)
+		} else {
+			newSceneJSON.Markers, err = scene.GetSceneMarkersJSON(ctx, sceneMarkerReader, tagReader, s)
+		}
 		if err != nil {
 			logger.Errorf("[scenes] <%s> error getting scene markers JSON: %s", sceneHash, err.Error())
 			continue
@@ -580,15 +896,67 @@ func (t *ExportTask) exportScene(ctx context.Context, wg *sync.WaitGroup, jobCha
 			t.performers.IDs = sliceutil.AppendUniques(t.performers.IDs, performer.GetIDs(performers))
 		}
 
-		basename := filepath.Base(s.Path)
+		t.exclude.scene(newSceneJSON)
+
+		if t.manifest.skip(manifestScenes, s.Path, newSceneJSON.UpdatedAt, newSceneJSON) {
+			continue
+		}
+
+		// export files
+		for _, f := range s.Files.List() {
+			t.exportFile(f)
+		}
+
+		basename := t.exclude.basename(s.Path)
 		hash := s.OSHash
+		if t.exclude[ExportExcludeEnumFingerprints] {
+			// the filename falls back to the id
+			hash = ""
+		}
 
-		fn := newSceneJSON.Filename(s.ID, basename, hash)
+		fn := t.templateFilename(jsonschema.FilenameTemplateData{
+			ID:       s.ID,
+			Title:    newSceneJSON.Title,
+			Studio:   newSceneJSON.Studio,
+			Date:     newSceneJSON.Date,
+			Basename: basename,
+			Hash:     hash,
+		}, newSceneJSON.Filename(s.ID, basename, hash))
+
+		if err := t.exportObjectImage(objectImagesScenes, imageBasename(fn), &newSceneJSON.Cover, &newSceneJSON.CoverFile); err != nil {
+			logger.Errorf("[scenes] <%s> failed to save cover: %v", sceneHash, err)
+		}
 
 		if err := t.json.saveScene(fn, newSceneJSON); err != nil {
 			logger.Errorf("[scenes] <%s> failed to save json: %s", sceneHash, err.Error())
 		}
+
+		if t.markerChapters {
+			if err := t.exportSceneChapters(ctx, s, sceneHash); err != nil {
+				logger.Errorf("[scenes] <%s> failed to save chapters: %s", sceneHash, err.Error())
+			}
+		}
+	}
+}
+
+// exportSceneChapters writes a WebVTT chapters file generated from the
+// markers of the scene. Scenes without markers are skipped.
+func (t *ExportTask) exportSceneChapters(ctx context.Context, s *models.Scene, sceneHash string) error {
+	var duration float64
+	if f := s.Files.Primary(); f != nil {
+		duration = f.Duration
+	}
+
+	vtt, err := scene.GetMarkerChaptersVTT(ctx, t.repository.SceneMarker, t.repository.Tag, s, duration)
+	if err != nil {
+		return err
 	}
+
+	if vtt == "" {
+		return nil
+	}
+
+	return t.json.saveChapters(sceneHash+".vtt", vtt)
 }
 
 func (t *ExportTask) ExportImages(ctx context.Context, workers int) {
@@ -659,11 +1027,6 @@ func (t *ExportTask) exportImage(ctx context.Context, wg *sync.WaitGroup, jobCha
 
 		newImageJSON := image.ToBasicJSON(s)
 
-		// export files
-		for _, f := range s.Files.List() {
-			t.exportFile(f)
-		}
-
 		var err error
 		newImageJSON.Studio, err = image.GetStudioName(ctx, studioReader, s)
 		if err != nil {
@@ -671,6 +1034,18 @@ func (t *ExportTask) exportImage(ctx context.Context, wg *sync.WaitGroup, jobCha
 			continue
 		}
 
+		newImageJSON.CustomFields, err = r.Image.GetCustomFields(ctx, s.ID)
+		if err != nil {
+			logger.Errorf("[images] <%s> error getting image custom fields: %v", imageHash, err)
+			continue
+		}
+
+		newImageJSON.ODates, newImageJSON.RatingHistory, err = historyToJSON(ctx, r.Image, s.ID)
+		if err != nil {
+			logger.Errorf("[images] <%s> error getting image history: %v", imageHash, err)
+			continue
+		}
+
 		imageGalleries, err := galleryReader.FindByImageID(ctx, s.ID)
 		if err != nil {
 			logger.Errorf("[images] <%s> error getting image galleries: %s", imageHash, err.Error())
@@ -712,7 +1087,31 @@ func (t *ExportTask) exportImage(ctx context.Context, wg *sync.WaitGroup, jobCha
 			t.performers.IDs = sliceutil.AppendUniques(t.performers.IDs, performer.GetIDs(performers))
 		}
 
-		fn := newImageJSON.Filename(filepath.Base(s.Path), s.Checksum)
+		t.exclude.image(newImageJSON)
+
+		if t.manifest.skip(manifestImages, s.Path, newImageJSON.UpdatedAt, newImageJSON) {
+			continue
+		}
+
+		// export files
+		for _, f := range s.Files.List() {
+			t.exportFile(f)
+		}
+
+		basename := t.exclude.basename(s.Path)
+		hash := s.Checksum
+		if t.exclude[ExportExcludeEnumFingerprints] {
+			hash = strconv.Itoa(s.ID)
+		}
+
+		fn := t.templateFilename(jsonschema.FilenameTemplateData{
+			ID:       s.ID,
+			Title:    newImageJSON.Title,
+			Studio:   newImageJSON.Studio,
+			Date:     newImageJSON.Date,
+			Basename: basename,
+			Hash:     hash,
+		}, newImageJSON.Filename(basename, hash))
 
 		if err := t.json.saveImage(fn, newImageJSON); err != nil {
 			logger.Errorf("[images] <%s> failed to save json: %s", imageHash, err.Error())
@@ -787,33 +1186,18 @@ func (t *ExportTask) exportGallery(ctx context.Context, wg *sync.WaitGroup, jobC
 			continue
 		}
 
-		// export files
-		for _, f := range g.Files.List() {
-			t.exportFile(f)
-		}
-
-		// export folder if necessary
-		if g.FolderID != nil {
-			folder, err := r.Folder.Find(ctx, *g.FolderID)
-			if err != nil {
-				logger.Errorf("[galleries] <%s> error getting gallery folder: %v", galleryHash, err)
-				continue
-			}
-
-			if folder == nil {
-				logger.Errorf("[galleries] <%s> unable to find gallery folder", galleryHash)
-				continue
-			}
-
-			t.exportFolder(*folder)
-		}
-
 		newGalleryJSON.Studio, err = gallery.GetStudioName(ctx, studioReader, g)
 		if err != nil {
 			logger.Errorf("[galleries] <%s> error getting gallery studio name: %s", galleryHash, err.Error())
 			continue
 		}
 
+		newGalleryJSON.CustomFields, err = r.Gallery.GetCustomFields(ctx, g.ID)
+		if err != nil {
+			logger.Errorf("[galleries] <%s> error getting gallery custom fields: %v", galleryHash, err)
+			continue
+		}
+
 		performers, err := performerReader.FindByGalleryID(ctx, g.ID)
 		if err != nil {
 			logger.Errorf("[galleries] <%s> error getting gallery performer names: %s", galleryHash, err.Error())
@@ -845,18 +1229,71 @@ func (t *ExportTask) exportGallery(ctx context.Context, wg *sync.WaitGroup, jobC
 			t.performers.IDs = sliceutil.AppendUniques(t.performers.IDs, performer.GetIDs(performers))
 		}
 
+		key := g.Path
+		if key == "" {
+			key = g.Title
+		}
+
+		var zipEntries []models.File
+		if t.zipContents {
+			zipEntries, err = t.galleryZipEntries(ctx, g)
+			if err != nil {
+				logger.Errorf("[galleries] <%s> error getting gallery zip entries: %v", galleryHash, err)
+				continue
+			}
+
+			newGalleryJSON.ZipEntries = zipEntriesToJSON(zipEntries)
+		}
+
+		t.exclude.gallery(newGalleryJSON)
+
+		if t.manifest.skip(manifestGalleries, key, newGalleryJSON.UpdatedAt, newGalleryJSON) {
+			continue
+		}
+
+		// export files
+		for _, f := range g.Files.List() {
+			t.exportFile(f)
+		}
+		for _, f := range zipEntries {
+			t.exportFile(f)
+		}
+
+		// export folder if necessary
+		if g.FolderID != nil {
+			folder, err := r.Folder.Find(ctx, *g.FolderID)
+			if err != nil {
+				logger.Errorf("[galleries] <%s> error getting gallery folder: %v", galleryHash, err)
+				continue
+			}
+
+			if folder == nil {
+				logger.Errorf("[galleries] <%s> unable to find gallery folder", galleryHash)
+				continue
+			}
+
+			t.exportFolder(*folder)
+		}
+
 		basename := ""
 		// use id in case multiple galleries with the same basename
 		hash := strconv.Itoa(g.ID)
 
 		switch {
 		case g.Path != "":
-			basename = filepath.Base(g.Path)
+			basename = t.exclude.basename(g.Path)
 		default:
 			basename = g.Title
 		}
 
-		fn := newGalleryJSON.Filename(basename, hash)
+		fn := t.templateFilename(jsonschema.FilenameTemplateData{
+			ID:       g.ID,
+			Title:    newGalleryJSON.Title,
+			Studio:   newGalleryJSON.Studio,
+			Date:     newGalleryJSON.Date,
+			Basename: basename,
+			Hash:     hash,
+		}, newGalleryJSON.Filename(basename, hash))
 
 		if err := t.json.saveGallery(fn, newGalleryJSON); err != nil {
 			logger.Errorf("[galleries] <%s> failed to save json: %s", galleryHash, err.Error())
@@ -864,6 +1301,33 @@ func (t *ExportTask) exportGallery(ctx context.Context, wg *sync.WaitGroup, jobC
 	}
 }
 
+// galleryZipEntries returns the files contained in the zip files of g.
+func (t *ExportTask) galleryZipEntries(ctx context.Context, g *models.Gallery) ([]models.File, error) {
+	var ret []models.File
+	for _, f := range g.Files.List() {
+		contents, err := t.repository.File.FindByZipFileID(ctx, f.Base().ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, contents...)
+	}
+
+	return ret, nil
+}
+
+func zipEntriesToJSON(files []models.File) []jsonschema.GalleryZipEntry {
+	var ret []jsonschema.GalleryZipEntry
+	for _, f := range files {
+		ret = append(ret, jsonschema.GalleryZipEntry{
+			Path:     f.Base().Path,
+			Checksum: f.Base().Fingerprints.GetString(models.FingerprintTypeMD5),
+		})
+	}
+
+	return ret
+}
+
 func (t *ExportTask) ExportPerformers(ctx context.Context, workers int) {
 	var performersWg sync.WaitGroup
 
@@ -903,34 +1367,77 @@ func (t *ExportTask) ExportPerformers(ctx context.Context, workers int) {
 	logger.Infof("[performers] export complete in %s. %d workers used.", time.Since(startTime), workers)
 }
 
+// historyToJSON returns the o-counter and rating history of the object with
+// the provided id.
+func historyToJSON(ctx context.Context, r models.HistoryReader, id int) ([]json.JSONTime, []jsonschema.RatingHistoryEntry, error) {
+	oDates, err := r.GetODates(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ratingHistory, err := r.GetRatingHistory(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return jsonschema.ODatesToJSON(oDates), jsonschema.RatingHistoryToJSON(ratingHistory), nil
+}
+
+// performerToJSON returns the export JSON of p, and the tags of p.
+func performerToJSON(ctx context.Context, r models.Repository, p *models.Performer) (*jsonschema.Performer, []*models.Tag, error) {
+	ret, err := performer.ToJSON(ctx, r.Performer, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ret.CustomFields, err = r.Performer.GetCustomFields(ctx, p.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	tags, err := r.Tag.FindByPerformerID(ctx, p.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting tags: %w", err)
+	}
+
+	ret.Tags = tag.GetNames(tags)
+
+	return ret, tags, nil
+}
+
 func (t *ExportTask) exportPerformer(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan *models.Performer) {
 	defer wg.Done()
 
 	r := t.repository
-	performerReader := r.Performer
 
 	for p := range jobChan {
-		newPerformerJSON, err := performer.ToJSON(ctx, performerReader, p)
-
+		newPerformerJSON, tags, err := performerToJSON(ctx, r, p)
 		if err != nil {
-			logger.Errorf("[performers] <%s> error getting performer JSON: %s", p.Name, err.Error())
+			logger.Errorf("[performers] <%s> error getting performer JSON: %v", p.Name, err)
 			continue
 		}
 
-		tags, err := r.Tag.FindByPerformerID(ctx, p.ID)
-		if err != nil {
-			logger.Errorf("[performers] <%s> error getting performer tags: %s", p.Name, err.Error())
-			continue
+		if t.includeDependencies {
+			t.tags.IDs = sliceutil.AppendUniques(t.tags.IDs, tag.GetIDs(tags))
+		}
+
+		key := p.Name
+		if p.Disambiguation != "" {
+			key += " (" + p.Disambiguation + ")"
 		}
 
-		newPerformerJSON.Tags = tag.GetNames(tags)
+		t.exclude.performer(newPerformerJSON)
 
-		if t.includeDependencies {
-			t.tags.IDs = sliceutil.AppendUniques(t.tags.IDs, tag.GetIDs(tags))
+		if t.manifest.skip(manifestPerformers, key, newPerformerJSON.UpdatedAt, newPerformerJSON) {
+			continue
 		}
 
 		fn := newPerformerJSON.Filename()
 
+		if err := t.exportObjectImage(objectImagesPerformers, imageBasename(fn), &newPerformerJSON.Image, &newPerformerJSON.ImageFile); err != nil {
+			logger.Errorf("[performers] <%s> failed to save image: %v", p.Name, err)
+		}
+
 		if err := t.json.savePerformer(fn, newPerformerJSON); err != nil {
 			logger.Errorf("[performers] <%s> failed to save json: %s", p.Name, err.Error())
 		}
@@ -977,21 +1484,43 @@ func (t *ExportTask) ExportStudios(ctx context.Context, workers int) {
 	logger.Infof("[studios] export complete in %s. %d workers used.", time.Since(startTime), workers)
 }
 
+// studioToJSON returns the export JSON of s.
+func studioToJSON(ctx context.Context, r models.Repository, s *models.Studio) (*jsonschema.Studio, error) {
+	ret, err := studio.ToJSON(ctx, r.Studio, s)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.CustomFields, err = r.Studio.GetCustomFields(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (t *ExportTask) exportStudio(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan *models.Studio) {
 	defer wg.Done()
 
-	studioReader := t.repository.Studio
-
 	for s := range jobChan {
-		newStudioJSON, err := studio.ToJSON(ctx, studioReader, s)
-
+		newStudioJSON, err := studioToJSON(ctx, t.repository, s)
 		if err != nil {
 			logger.Errorf("[studios] <%s> error getting studio JSON: %v", s.Name, err)
 			continue
 		}
 
+		t.exclude.studio(newStudioJSON)
+
+		if t.manifest.skip(manifestStudios, s.Name, newStudioJSON.UpdatedAt, newStudioJSON) {
+			continue
+		}
+
 		fn := newStudioJSON.Filename()
 
+		if err := t.exportObjectImage(objectImagesStudios, imageBasename(fn), &newStudioJSON.Image, &newStudioJSON.ImageFile); err != nil {
+			logger.Errorf("[studios] <%s> failed to save image: %v", s.Name, err)
+		}
+
 		if err := t.json.saveStudio(fn, newStudioJSON); err != nil {
 			logger.Errorf("[studios] <%s> failed to save json: %v", s.Name, err)
 		}
@@ -1038,21 +1567,43 @@ func (t *ExportTask) ExportTags(ctx context.Context, workers int) {
 	logger.Infof("[tags] export complete in %s. %d workers used.", time.Since(startTime), workers)
 }
 
+// tagToJSON returns the export JSON of t.
+func tagToJSON(ctx context.Context, r models.Repository, t *models.Tag) (*jsonschema.Tag, error) {
+	ret, err := tag.ToJSON(ctx, r.Tag, t)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.CustomFields, err = r.Tag.GetCustomFields(ctx, t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (t *ExportTask) exportTag(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan *models.Tag) {
 	defer wg.Done()
 
-	tagReader := t.repository.Tag
-
 	for thisTag := range jobChan {
-		newTagJSON, err := tag.ToJSON(ctx, tagReader, thisTag)
-
+		newTagJSON, err := tagToJSON(ctx, t.repository, thisTag)
 		if err != nil {
-			logger.Errorf("[tags] <%s> error getting tag JSON: %s", thisTag.Name, err.Error())
+			logger.Errorf("[tags] <%s> error getting tag JSON: %v", thisTag.Name, err)
+			continue
+		}
+
+		t.exclude.tag(newTagJSON)
+
+		if t.manifest.skip(manifestTags, thisTag.Name, newTagJSON.UpdatedAt, newTagJSON) {
 			continue
 		}
 
 		fn := newTagJSON.Filename()
 
+		if err := t.exportObjectImage(objectImagesTags, imageBasename(fn), &newTagJSON.Image, &newTagJSON.ImageFile); err != nil {
+			logger.Errorf("[tags] <%s> failed to save image: %v", fn, err)
+		}
+
 		if err := t.json.saveTag(fn, newTagJSON); err != nil {
 			logger.Errorf("[tags] <%s> failed to save json: %s", fn, err.Error())
 		}
@@ -1099,18 +1650,29 @@ func (t *ExportTask) ExportMovies(ctx context.Context, workers int) {
 	logger.Infof("[movies] export complete in %s. %d workers used.", time.Since(startTime), workers)
 
 }
+
+// movieToJSON returns the export JSON of m.
+func movieToJSON(ctx context.Context, r models.Repository, m *models.Movie) (*jsonschema.Movie, error) {
+	ret, err := movie.ToJSON(ctx, r.Movie, r.Studio, m)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.CustomFields, err = r.Movie.GetCustomFields(ctx, m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	return ret, nil
+}
+
 func (t *ExportTask) exportMovie(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan *models.Movie) {
 	defer wg.Done()
 
-	r := t.repository
-	movieReader := r.Movie
-	studioReader := r.Studio
-
 	for m := range jobChan {
-		newMovieJSON, err := movie.ToJSON(ctx, movieReader, studioReader, m)
-
+		newMovieJSON, err := movieToJSON(ctx, t.repository, m)
 		if err != nil {
-			logger.Errorf("[movies] <%s> error getting tag JSON: %v", m.Name, err)
+			logger.Errorf("[movies] <%s> error getting movie JSON: %v", m.Name, err)
 			continue
 		}
 
@@ -1120,8 +1682,21 @@ func (t *ExportTask) exportMovie(ctx context.Context, wg *sync.WaitGroup, jobCha
 			}
 		}
 
+		t.exclude.movie(newMovieJSON)
+
+		if t.manifest.skip(manifestMovies, m.Name, newMovieJSON.UpdatedAt, newMovieJSON) {
+			continue
+		}
+
 		fn := newMovieJSON.Filename()
 
+		if err := t.exportObjectImage(objectImagesMovies, imageBasename(fn)+"_front", &newMovieJSON.FrontImage, &newMovieJSON.FrontImageFile); err != nil {
+			logger.Errorf("[movies] <%s> failed to save front image: %v", m.Name, err)
+		}
+		if err := t.exportObjectImage(objectImagesMovies, imageBasename(fn)+"_back", &newMovieJSON.BackImage, &newMovieJSON.BackImageFile); err != nil {
+			logger.Errorf("[movies] <%s> failed to save back image: %v", m.Name, err)
+		}
+
 		if err := t.json.saveMovie(fn, newMovieJSON); err != nil {
 			logger.Errorf("[movies] <%s> failed to save json: %v", m.Name, err)
 		}
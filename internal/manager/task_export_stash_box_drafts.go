@@ -0,0 +1,179 @@
+package manager
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+)
+
+// StashBoxDraftExportTask writes stash-box draft submissions of scenes and
+// performers to a zip file, so that they can be submitted in bulk.
+//
+// Each draft is written to scenes/<id>.json or performers/<id>.json, in the
+// format of the input of the stash-box submitSceneDraft and
+// submitPerformerDraft mutations. The scene cover or performer image is
+// written alongside the draft, with the same basename.
+type StashBoxDraftExportTask struct {
+	repository   models.Repository
+	box          *models.StashBox
+	sceneIDs     []int
+	performerIDs []int
+
+	DownloadHash string
+}
+
+func CreateStashBoxDraftExportTask(box *models.StashBox, sceneIDs []int, performerIDs []int) *StashBoxDraftExportTask {
+	return &StashBoxDraftExportTask{
+		repository:   GetInstance().Repository,
+		box:          box,
+		sceneIDs:     sceneIDs,
+		performerIDs: performerIDs,
+	}
+}
+
+func (t *StashBoxDraftExportTask) Start(ctx context.Context) error {
+	if err := fsutil.EnsureDir(instance.Paths.Generated.Downloads); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(instance.Paths.Generated.Downloads, "drafts*.zip")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := t.writeDrafts(ctx, f); err != nil {
+		return err
+	}
+
+	t.DownloadHash, err = instance.DownloadStore.RegisterFile(f.Name(), "", false)
+	if err != nil {
+		return fmt.Errorf("error registering file for download: %w", err)
+	}
+	logger.Debugf("Generated draft bundle %s with hash %s", f.Name(), t.DownloadHash)
+
+	return nil
+}
+
+func (t *StashBoxDraftExportTask) writeDrafts(ctx context.Context, w io.Writer) error {
+	z := zip.NewWriter(w)
+
+	r := t.repository
+	client := stashbox.NewClient(*t.box, stashbox.NewRepository(r))
+
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		for _, id := range t.sceneIDs {
+			if err := t.writeSceneDraft(ctx, client, id, z); err != nil {
+				logger.Errorf("[stash-box drafts] <scene %d> %v", id, err)
+			}
+		}
+
+		for _, id := range t.performerIDs {
+			if err := t.writePerformerDraft(ctx, client, id, z); err != nil {
+				logger.Errorf("[stash-box drafts] <performer %d> %v", id, err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		z.Close()
+		return err
+	}
+
+	return z.Close()
+}
+
+func (t *StashBoxDraftExportTask) writeSceneDraft(ctx context.Context, client *stashbox.Client, id int, z *zip.Writer) error {
+	qb := t.repository.Scene
+	scene, err := qb.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if scene == nil {
+		return fmt.Errorf("scene with id %d not found", id)
+	}
+
+	if err := scene.LoadURLs(ctx, qb); err != nil {
+		return fmt.Errorf("loading scene URLs: %w", err)
+	}
+
+	draft, err := client.SceneDraft(ctx, scene, t.box.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	cover, err := qb.GetCover(ctx, id)
+	if err != nil {
+		logger.Errorf("Error getting scene cover: %v", err)
+	}
+
+	return writeDraft(z, "scenes/"+strconv.Itoa(id), draft, cover)
+}
+
+func (t *StashBoxDraftExportTask) writePerformerDraft(ctx context.Context, client *stashbox.Client, id int, z *zip.Writer) error {
+	qb := t.repository.Performer
+	performer, err := qb.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if performer == nil {
+		return fmt.Errorf("performer with id %d not found", id)
+	}
+
+	draft, err := client.PerformerDraft(ctx, performer, t.box.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	image, err := qb.GetImage(ctx, id)
+	if err != nil {
+		logger.Errorf("Error getting performer image: %v", err)
+	}
+
+	return writeDraft(z, "performers/"+strconv.Itoa(id), draft, image)
+}
+
+// writeDraft writes draft to name.json in the zip file, and image to a file
+// with the same basename if it is not empty.
+func writeDraft(z *zip.Writer, name string, draft interface{}, image []byte) error {
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding draft: %w", err)
+	}
+
+	if err := writeZipEntry(z, name+".json", data); err != nil {
+		return err
+	}
+
+	if len(image) > 0 {
+		if err := writeZipEntry(z, name+imageExtension(image), image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeZipEntry(z *zip.Writer, name string, data []byte) error {
+	w, err := z.Create(name)
+	if err != nil {
+		return fmt.Errorf("error creating zip entry for %s: %w", name, err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("error writing %s to zip: %w", name, err)
+	}
+
+	return nil
+}
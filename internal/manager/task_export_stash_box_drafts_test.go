@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDraft(t *testing.T) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+
+	title := "title"
+	draft := &graphql.SceneDraftInput{
+		Title: &title,
+		Fingerprints: []*graphql.FingerprintInput{
+			{Hash: "hash", Algorithm: graphql.FingerprintAlgorithmOshash, Duration: 10},
+		},
+	}
+
+	if err := writeDraft(z, "scenes/1", draft, testPNG); err != nil {
+		t.Fatalf("writeDraft: %v", err)
+	}
+	if err := writeDraft(z, "scenes/2", draft, nil); err != nil {
+		t.Fatalf("writeDraft: %v", err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading zip: %v", err)
+	}
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"scenes/1.json", "scenes/1.png", "scenes/2.json"}, names)
+
+	f, err := r.File[0].Open()
+	if err != nil {
+		t.Fatalf("opening draft: %v", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("reading draft: %v", err)
+	}
+
+	assert.JSONEq(t, `{
+		"title": "title",
+		"performers": null,
+		"fingerprints": [{"hash": "hash", "algorithm": "OSHASH", "duration": 10}]
+	}`, string(data))
+}
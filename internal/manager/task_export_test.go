@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopulateMarkerScenes(t *testing.T) {
+	const (
+		selectedSceneID = 1
+		markerSceneID   = 2
+	)
+
+	markers := []*models.SceneMarker{
+		{ID: 1, SceneID: selectedSceneID},
+		{ID: 2, SceneID: markerSceneID},
+		{ID: 3, SceneID: markerSceneID},
+	}
+
+	db := mocks.NewDatabase()
+	db.SceneMarker.On("FindMany", context.Background(), []int{1, 2, 3}).Return(markers, nil).Once()
+
+	task := &ExportTask{
+		repository: db.Repository(),
+		scenes:     &exportSpec{IDs: []int{selectedSceneID}},
+		markers:    &exportSpec{IDs: []int{1, 2, 3}},
+	}
+
+	task.populateMarkerScenes(context.Background())
+
+	assert.Equal(t, []int{selectedSceneID, markerSceneID}, task.scenes.IDs)
+	assert.Equal(t, map[int][]*models.SceneMarker{
+		markerSceneID: {markers[1], markers[2]},
+	}, task.markerScenes)
+
+	db.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -25,16 +26,31 @@ type GenerateMetadataInput struct {
 	Markers             bool                         `json:"markers"`
 	MarkerImagePreviews bool                         `json:"markerImagePreviews"`
 	MarkerScreenshots   bool                         `json:"markerScreenshots"`
-	Transcodes          bool                         `json:"transcodes"`
+	// Options of the marker previews
+	MarkerPreviewOptions *GenerateMarkerPreviewOptionsInput `json:"markerPreviewOptions"`
+	Transcodes           bool                               `json:"transcodes"`
 	// Generate transcodes even if not required
-	ForceTranscodes           bool `json:"forceTranscodes"`
-	Phashes                   bool `json:"phashes"`
+	ForceTranscodes bool `json:"forceTranscodes"`
+	Phashes         bool `json:"phashes"`
+	// Generate phashes for still images
+	ImagePhashes bool `json:"imagePhashes"`
+	// Generate thumbnails for still images
+	ImageThumbnails           bool `json:"imageThumbnails"`
 	InteractiveHeatmapsSpeeds bool `json:"interactiveHeatmapsSpeeds"`
-	ClipPreviews              bool `json:"clipPreviews"`
+	// Generate images of the audio waveforms of scenes
+	Waveforms    bool `json:"waveforms"`
+	ClipPreviews bool `json:"clipPreviews"`
+	// Transcode animated images to videos
+	ClipTranscodes bool `json:"clipTranscodes"`
+	ColorPalettes  bool `json:"colorPalettes"`
+	// Generate page thumbnails for document galleries
+	DocumentPages bool `json:"documentPages"`
 	// scene ids to generate for
 	SceneIDs []string `json:"sceneIDs"`
 	// marker ids to generate for
 	MarkerIDs []string `json:"markerIDs"`
+	// scenes or scene markers of the selection set to generate for
+	SelectionSetID *string `json:"selectionSetID"`
 	// overwrite existing media
 	Overwrite bool `json:"overwrite"`
 }
@@ -50,6 +66,19 @@ type GeneratePreviewOptionsInput struct {
 	PreviewExcludeEnd *string `json:"previewExcludeEnd"`
 	// Preset when generating preview
 	PreviewPreset *models.PreviewPreset `json:"previewPreset"`
+	// Format of the animated image preview
+	PreviewImageFormat *models.PreviewImageFormat `json:"previewImageFormat"`
+	// Video codec of the preview video
+	PreviewVideoCodec *models.PreviewVideoCodec `json:"previewVideoCodec"`
+}
+
+type GenerateMarkerPreviewOptionsInput struct {
+	// Format of the marker preview
+	MarkerPreviewFormat *models.MarkerPreviewFormat `json:"markerPreviewFormat"`
+	// Marker preview duration, in seconds
+	MarkerPreviewDuration *float64 `json:"markerPreviewDuration"`
+	// Marker preview width, in pixels
+	MarkerPreviewWidth *int `json:"markerPreviewWidth"`
 }
 
 const generateQueueSize = 200000
@@ -60,6 +89,13 @@ type GenerateJob struct {
 
 	overwrite      bool
 	fileNamingAlgo models.HashAlgorithm
+
+	// optionsHash identifies the generation settings of the job. It is empty
+	// when overwriting, in which case generate states are not used.
+	optionsHash string
+	// upToDate holds the states of the scenes found to have all of their
+	// artifacts generated, to be recorded once queueing is complete.
+	upToDate []sceneGenerateState
 }
 
 type totalsGenerate struct {
@@ -70,8 +106,15 @@ type totalsGenerate struct {
 	markers                  int64
 	transcodes               int64
 	phashes                  int64
+	imagePhashes             int64
+	imageThumbnails          int64
 	interactiveHeatmapSpeeds int64
+	waveforms                int64
 	clipPreviews             int64
+	clipTranscodes           int64
+	colorPalettes            int64
+	documentPages            int64
+	upToDate                 int64
 
 	tasks int
 }
@@ -87,6 +130,15 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 	config := config.GetInstance()
 	parallelTasks := config.GetParallelTasksWithAutoDetection()
 
+	if !j.overwrite {
+		generatePreviewOptions := j.input.PreviewOptions
+		if generatePreviewOptions == nil {
+			generatePreviewOptions = &GeneratePreviewOptionsInput{}
+		}
+		previewOptions := getGeneratePreviewOptions(*generatePreviewOptions)
+		j.optionsHash = generateOptionsHash(j.input, previewOptions, j.fileNamingAlgo, config.GetMaxTranscodeSize())
+	}
+
 	logger.Infof("Generate started with %d parallel tasks", parallelTasks)
 
 	queue := make(chan Task, generateQueueSize)
@@ -146,6 +198,12 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 			return
 		}
 
+		j.saveGenerateStates(ctx, j.upToDate)
+
+		if totals.upToDate > 0 {
+			logger.Infof("Skipping %d up to date scenes", totals.upToDate)
+		}
+
 		logMsg := "Generating"
 		if j.input.Covers {
 			logMsg += fmt.Sprintf(" %d covers", totals.covers)
@@ -168,12 +226,30 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 		if j.input.Phashes {
 			logMsg += fmt.Sprintf(" %d phashes", totals.phashes)
 		}
+		if j.input.ImagePhashes {
+			logMsg += fmt.Sprintf(" %d image phashes", totals.imagePhashes)
+		}
+		if j.input.ImageThumbnails {
+			logMsg += fmt.Sprintf(" %d image thumbnails", totals.imageThumbnails)
+		}
 		if j.input.InteractiveHeatmapsSpeeds {
 			logMsg += fmt.Sprintf(" %d heatmaps & speeds", totals.interactiveHeatmapSpeeds)
 		}
+		if j.input.Waveforms {
+			logMsg += fmt.Sprintf(" %d waveforms", totals.waveforms)
+		}
 		if j.input.ClipPreviews {
 			logMsg += fmt.Sprintf(" %d Image Clip Previews", totals.clipPreviews)
 		}
+		if j.input.ClipTranscodes {
+			logMsg += fmt.Sprintf(" %d Image Clip transcodes", totals.clipTranscodes)
+		}
+		if j.input.ColorPalettes {
+			logMsg += fmt.Sprintf(" %d color palettes", totals.colorPalettes)
+		}
+		if j.input.DocumentPages {
+			logMsg += fmt.Sprintf(" %d document page sets", totals.documentPages)
+		}
 		if logMsg == "Generating" {
 			logMsg = "Nothing selected to generate"
 		}
@@ -183,6 +259,12 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 	}()
 
 	wg := sizedwaitgroup.New(parallelTasks)
+	// tasks dispatched to workers do not use local resources, so are limited
+	// separately
+	remoteWg := sizedwaitgroup.New(remoteGenerateParallelTasks)
+	// image thumbnails count towards the parallel tasks, and are further
+	// limited to the configured number of image thumbnail tasks
+	imageThumbnailWg := sizedwaitgroup.New(config.GetImageThumbnailParallelTasksWithAutoDetection())
 
 	// Start measuring how long the generate has taken. (consider moving this up)
 	start := time.Now()
@@ -201,18 +283,42 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 			break
 		}
 
-		wg.Add()
+		// wait until quiet hours are over
+		if err := job.WaitIfPaused(ctx); err != nil {
+			break
+		}
+
+		taskWg := &wg
+		var limitWg *sizedwaitgroup.SizedWaitGroup
+		switch f.(type) {
+		case *remoteGenerateTask:
+			if instance.generateDispatcher.connected(time.Now()) {
+				taskWg = &remoteWg
+			}
+		case *GenerateImageThumbnailTask:
+			limitWg = &imageThumbnailWg
+		}
+
+		if limitWg != nil {
+			limitWg.Add()
+		}
+		taskWg.Add()
 		// #1879 - need to make a copy of f - otherwise there is a race condition
 		// where f is changed when the goroutine runs
 		localTask := f
 		go progress.ExecuteTask(localTask.GetDescription(), func() {
 			localTask.Start(ctx)
-			wg.Done()
+			taskWg.Done()
+			if limitWg != nil {
+				limitWg.Done()
+			}
 			progress.Increment()
 		})
 	}
 
 	wg.Wait()
+	remoteWg.Wait()
+	imageThumbnailWg.Wait()
 
 	if job.IsCancelled(ctx) {
 		logger.Info("Stopping due to user request")
@@ -264,7 +370,7 @@ func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, que
 	}
 
 	*findFilter.Page = 1
-	for more := j.input.ClipPreviews; more; {
+	for more := j.input.ClipPreviews || j.input.ClipTranscodes || j.input.ColorPalettes || j.input.ImagePhashes || j.input.ImageThumbnails; more; {
 		if job.IsCancelled(ctx) {
 			return totals
 		}
@@ -285,7 +391,7 @@ func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, que
 				return totals
 			}
 
-			j.queueImageJob(g, ss, queue, &totals)
+			j.queueImageJob(ctx, g, ss, queue, &totals)
 		}
 
 		if len(images) != batchSize {
@@ -295,9 +401,80 @@ func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, que
 		}
 	}
 
+	*findFilter.Page = 1
+	for more := j.input.DocumentPages; more; {
+		if job.IsCancelled(ctx) {
+			return totals
+		}
+
+		galleries, _, err := r.Gallery.Query(ctx, nil, findFilter)
+		if err != nil {
+			logger.Errorf("Error encountered queuing files to scan: %s", err.Error())
+			return totals
+		}
+
+		for _, g := range galleries {
+			if job.IsCancelled(ctx) {
+				return totals
+			}
+
+			if err := g.LoadFiles(ctx, r.Gallery); err != nil {
+				logger.Errorf("Error encountered queuing files to scan: %s", err.Error())
+				return totals
+			}
+
+			j.queueGalleryJob(g, queue, &totals)
+		}
+
+		if len(galleries) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
 	return totals
 }
 
+func validateMarkerPreviewOptions(optionsInput *GenerateMarkerPreviewOptionsInput) error {
+	if optionsInput == nil {
+		return nil
+	}
+
+	if optionsInput.MarkerPreviewDuration != nil && *optionsInput.MarkerPreviewDuration <= 0 {
+		return errors.New("marker preview duration must be positive")
+	}
+	if optionsInput.MarkerPreviewWidth != nil && *optionsInput.MarkerPreviewWidth <= 0 {
+		return errors.New("marker preview width must be positive")
+	}
+
+	return nil
+}
+
+func getMarkerPreviewOptions(optionsInput *GenerateMarkerPreviewOptionsInput) markerPreviewOptions {
+	ret := markerPreviewOptions{
+		Format: models.MarkerPreviewFormatVideo,
+	}
+
+	if optionsInput == nil {
+		return ret
+	}
+
+	if optionsInput.MarkerPreviewFormat != nil {
+		ret.Format = *optionsInput.MarkerPreviewFormat
+	}
+
+	if optionsInput.MarkerPreviewDuration != nil {
+		ret.Duration = *optionsInput.MarkerPreviewDuration
+	}
+
+	if optionsInput.MarkerPreviewWidth != nil {
+		ret.Width = *optionsInput.MarkerPreviewWidth
+	}
+
+	return ret
+}
+
 func getGeneratePreviewOptions(optionsInput GeneratePreviewOptionsInput) generate.PreviewOptions {
 	config := config.GetInstance()
 
@@ -308,6 +485,8 @@ func getGeneratePreviewOptions(optionsInput GeneratePreviewOptionsInput) generat
 		ExcludeEnd:      config.GetPreviewExcludeEnd(),
 		Preset:          config.GetPreviewPreset().String(),
 		Audio:           config.GetPreviewAudio(),
+		ImageFormat:     config.GetPreviewImageFormat(),
+		VideoCodec:      config.GetPreviewVideoCodec(),
 	}
 
 	if optionsInput.PreviewSegments != nil {
@@ -330,12 +509,84 @@ func getGeneratePreviewOptions(optionsInput GeneratePreviewOptionsInput) generat
 		ret.Preset = optionsInput.PreviewPreset.String()
 	}
 
+	if optionsInput.PreviewImageFormat != nil {
+		ret.ImageFormat = *optionsInput.PreviewImageFormat
+	}
+
+	if optionsInput.PreviewVideoCodec != nil {
+		ret.VideoCodec = *optionsInput.PreviewVideoCodec
+	}
+
 	return ret
 }
 
+// applyScenePreviewOptions returns options with the options set for a scene
+// taking precedence.
+func applyScenePreviewOptions(options generate.PreviewOptions, sceneOptions *models.ScenePreviewOptions) generate.PreviewOptions {
+	if sceneOptions == nil {
+		return options
+	}
+
+	if sceneOptions.Segments != nil {
+		options.Segments = *sceneOptions.Segments
+	}
+
+	if sceneOptions.SegmentDuration != nil {
+		options.SegmentDuration = *sceneOptions.SegmentDuration
+	}
+
+	if sceneOptions.ExcludeStart != nil {
+		options.ExcludeStart = *sceneOptions.ExcludeStart
+	}
+
+	if sceneOptions.ExcludeEnd != nil {
+		options.ExcludeEnd = *sceneOptions.ExcludeEnd
+	}
+
+	return options
+}
+
 func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator, scene *models.Scene, queue chan<- Task, totals *totalsGenerate) {
 	r := j.repository
 
+	generatePreviewOptions := j.input.PreviewOptions
+	if generatePreviewOptions == nil {
+		generatePreviewOptions = &GeneratePreviewOptionsInput{}
+	}
+	options := getGeneratePreviewOptions(*generatePreviewOptions)
+
+	if j.input.Previews {
+		sceneOptions, err := r.Scene.GetPreviewOptions(ctx, scene.ID)
+		if err != nil {
+			logger.Errorf("error getting preview options for scene %d: %v", scene.ID, err)
+		}
+		options = applyScenePreviewOptions(options, sceneOptions)
+	}
+
+	var stateHash string
+	if j.optionsHash != "" {
+		var err error
+		stateHash, err = j.sceneStateHash(ctx, scene, options)
+		if err != nil {
+			logger.Errorf("error getting generate state for scene %d: %v", scene.ID, err)
+		} else if j.sceneUpToDate(ctx, scene.ID, stateHash) && j.generatedFilesExist(ctx, g, scene, options) {
+			totals.upToDate++
+			return
+		}
+	}
+
+	tasks := totals.tasks
+	defer func() {
+		// the state is only recorded once the scene is found to need nothing
+		// generated, since tasks do not report whether they succeeded
+		if stateHash != "" && totals.tasks == tasks {
+			j.upToDate = append(j.upToDate, sceneGenerateState{
+				sceneID:   scene.ID,
+				stateHash: stateHash,
+			})
+		}
+	}()
+
 	if j.input.Covers {
 		task := &GenerateCoverTask{
 			repository: r,
@@ -360,16 +611,10 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		if task.required() {
 			totals.sprites++
 			totals.tasks++
-			queue <- task
+			queue <- newRemoteSpriteTask(task)
 		}
 	}
 
-	generatePreviewOptions := j.input.PreviewOptions
-	if generatePreviewOptions == nil {
-		generatePreviewOptions = &GeneratePreviewOptionsInput{}
-	}
-	options := getGeneratePreviewOptions(*generatePreviewOptions)
-
 	if j.input.Previews {
 		task := &GeneratePreviewTask{
 			Scene:               *scene,
@@ -389,7 +634,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			}
 
 			totals.tasks++
-			queue <- task
+			queue <- newRemotePreviewTask(task)
 		}
 	}
 
@@ -401,6 +646,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			fileNamingAlgorithm: j.fileNamingAlgo,
 			ImagePreview:        j.input.MarkerImagePreviews,
 			Screenshot:          j.input.MarkerScreenshots,
+			PreviewOptions:      getMarkerPreviewOptions(j.input.MarkerPreviewOptions),
 
 			generator: g,
 		}
@@ -426,7 +672,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		if task.required() {
 			totals.transcodes++
 			totals.tasks++
-			queue <- task
+			queue <- newRemoteTranscodeTask(task)
 		}
 	}
 
@@ -443,7 +689,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			if task.required() {
 				totals.phashes++
 				totals.tasks++
-				queue <- task
+				queue <- newRemotePhashTask(task, scene.ID)
 			}
 		}
 	}
@@ -462,6 +708,35 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			queue <- task
 		}
 	}
+
+	if j.input.Waveforms {
+		task := &GenerateWaveformTask{
+			Scene:               *scene,
+			Overwrite:           j.overwrite,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			generator:           g,
+		}
+
+		if task.required() {
+			totals.waveforms++
+			totals.tasks++
+			queue <- task
+		}
+	}
+
+	if j.input.ColorPalettes {
+		task := &GenerateSceneColorPaletteTask{
+			repository: r,
+			Scene:      *scene,
+			Overwrite:  j.overwrite,
+		}
+
+		if task.required(ctx) {
+			totals.colorPalettes++
+			totals.tasks++
+			queue <- task
+		}
+	}
 }
 
 func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.SceneMarker, queue chan<- Task, totals *totalsGenerate) {
@@ -470,6 +745,7 @@ func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.Scene
 		Marker:              marker,
 		Overwrite:           j.overwrite,
 		fileNamingAlgorithm: j.fileNamingAlgo,
+		PreviewOptions:      getMarkerPreviewOptions(j.input.MarkerPreviewOptions),
 		generator:           g,
 	}
 	totals.markers++
@@ -477,15 +753,95 @@ func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.Scene
 	queue <- task
 }
 
-func (j *GenerateJob) queueImageJob(g *generate.Generator, image *models.Image, queue chan<- Task, totals *totalsGenerate) {
-	task := &GenerateClipPreviewTask{
-		Image:     *image,
-		Overwrite: j.overwrite,
+func (j *GenerateJob) queueImageJob(ctx context.Context, g *generate.Generator, image *models.Image, queue chan<- Task, totals *totalsGenerate) {
+	if j.input.ClipPreviews {
+		task := &GenerateClipPreviewTask{
+			Image:     *image,
+			Overwrite: j.overwrite,
+		}
+
+		if task.required() {
+			totals.clipPreviews++
+			totals.tasks++
+			queue <- task
+		}
+	}
+
+	if j.input.ClipTranscodes {
+		task := &GenerateClipTranscodeTask{
+			Image:     *image,
+			Overwrite: j.overwrite,
+		}
+
+		if task.required() {
+			totals.clipTranscodes++
+			totals.tasks++
+			queue <- task
+		}
+	}
+
+	if j.input.ColorPalettes {
+		task := &GenerateImageColorPaletteTask{
+			repository: j.repository,
+			Image:      *image,
+			Overwrite:  j.overwrite,
+		}
+
+		if task.required(ctx) {
+			totals.colorPalettes++
+			totals.tasks++
+			queue <- task
+		}
 	}
 
-	if task.required() {
-		totals.clipPreviews++
-		totals.tasks++
-		queue <- task
+	if j.input.ImagePhashes {
+		// video files are not supported
+		if f, ok := image.Files.Primary().(*models.ImageFile); ok {
+			task := &GenerateImagePhashTask{
+				repository: j.repository,
+				File:       f,
+				Overwrite:  j.overwrite,
+			}
+
+			if task.required() {
+				totals.imagePhashes++
+				totals.tasks++
+				queue <- task
+			}
+		}
+	}
+
+	if j.input.ImageThumbnails {
+		task := &GenerateImageThumbnailTask{
+			Image:     *image,
+			Overwrite: j.overwrite,
+		}
+
+		if task.required() {
+			totals.imageThumbnails++
+			totals.tasks++
+			queue <- task
+		}
+	}
+}
+
+func (j *GenerateJob) queueGalleryJob(g *models.Gallery, queue chan<- Task, totals *totalsGenerate) {
+	if j.input.DocumentPages {
+		f, ok := g.Files.Primary().(*models.DocumentFile)
+		if !ok {
+			return
+		}
+
+		task := &GenerateDocumentPagesTask{
+			Gallery:   *g,
+			File:      f,
+			Overwrite: j.overwrite,
+		}
+
+		if task.required() {
+			totals.documentPages++
+			totals.tasks++
+			queue <- task
+		}
 	}
 }
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// GenerateClipTranscodeTask transcodes an animated image to a video, which
+// is played in place of the image.
+type GenerateClipTranscodeTask struct {
+	Image     models.Image
+	Overwrite bool
+}
+
+func (t *GenerateClipTranscodeTask) GetDescription() string {
+	return fmt.Sprintf("Transcoding image clip %s", t.Image.Path)
+}
+
+func (t *GenerateClipTranscodeTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	mgr := GetInstance()
+	transcodePath := mgr.Paths.Generated.GetClipTranscodePath(t.Image.Checksum)
+	filePath := t.Image.Files.Primary().Base().Path
+
+	clipPreviewOptions := image.ClipPreviewOptions{
+		InputArgs:  mgr.Config.GetTranscodeInputArgs(),
+		OutputArgs: mgr.Config.GetTranscodeOutputArgs(),
+		Preset:     mgr.Config.GetPreviewPreset().String(),
+	}
+
+	encoder := image.NewThumbnailEncoder(mgr.FFMPEG, mgr.FFProbe, clipPreviewOptions)
+	if err := encoder.GetClipTranscode(filePath, transcodePath); err != nil {
+		logger.Errorf("transcoding image clip %s: %v", filePath, err)
+	}
+}
+
+func (t *GenerateClipTranscodeTask) required() bool {
+	f := t.Image.Files.Primary()
+	// ffmpeg cannot read files in zip files
+	if f == nil || f.Base().ZipFileID != nil {
+		return false
+	}
+
+	if !image.IsAnimatedClip(f) {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	transcodePath := GetInstance().Paths.Generated.GetClipTranscodePath(t.Image.Checksum)
+	exists, _ := fsutil.FileExists(transcodePath)
+	return !exists
+}
@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/palette"
+)
+
+type GenerateSceneColorPaletteTask struct {
+	repository models.Repository
+	Scene      models.Scene
+	Overwrite  bool
+}
+
+func (t *GenerateSceneColorPaletteTask) GetDescription() string {
+	return fmt.Sprintf("Generating color palette for %s", t.Scene.Path)
+}
+
+func (t *GenerateSceneColorPaletteTask) Start(ctx context.Context) {
+	r := t.repository
+
+	var cover []byte
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		cover, err = r.Scene.GetCover(ctx, t.Scene.ID)
+		return err
+	}); err != nil {
+		logger.Errorf("error getting cover for scene %s: %v", t.Scene.DisplayName(), err)
+		return
+	}
+
+	if len(cover) == 0 {
+		return
+	}
+
+	img, err := palette.Decode(bytes.NewReader(cover))
+	if err != nil {
+		logger.Errorf("error decoding cover for scene %s: %v", t.Scene.DisplayName(), err)
+		return
+	}
+
+	colors := palette.Extract(img, palette.DefaultSize)
+
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		return r.Scene.UpdateColorPalette(ctx, t.Scene.ID, colors)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("error saving color palette for scene %s: %v", t.Scene.DisplayName(), err)
+	}
+}
+
+// required must be called within a read transaction.
+func (t *GenerateSceneColorPaletteTask) required(ctx context.Context) bool {
+	if t.Overwrite {
+		return true
+	}
+
+	colors, err := t.repository.Scene.GetColorPalette(ctx, t.Scene.ID)
+	if err != nil {
+		logger.Errorf("error getting color palette for scene %s: %v", t.Scene.DisplayName(), err)
+		return false
+	}
+
+	return len(colors) == 0
+}
+
+type GenerateImageColorPaletteTask struct {
+	repository models.Repository
+	Image      models.Image
+	Overwrite  bool
+}
+
+func (t *GenerateImageColorPaletteTask) GetDescription() string {
+	return fmt.Sprintf("Generating color palette for %s", t.Image.Path)
+}
+
+func (t *GenerateImageColorPaletteTask) Start(ctx context.Context) {
+	f, ok := t.Image.Files.Primary().(*models.ImageFile)
+	if !ok {
+		return
+	}
+
+	reader, err := f.Open(GetInstance().FS)
+	if err != nil {
+		logger.Errorf("error opening image %s: %v", f.Path, err)
+		return
+	}
+	defer reader.Close()
+
+	img, err := palette.Decode(reader)
+	if err != nil {
+		logger.Errorf("error decoding image %s: %v", f.Path, err)
+		return
+	}
+
+	colors := palette.Extract(img, palette.DefaultSize)
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		return r.Image.UpdateColorPalette(ctx, t.Image.ID, colors)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("error saving color palette for image %s: %v", f.Path, err)
+	}
+}
+
+// required must be called within a read transaction.
+func (t *GenerateImageColorPaletteTask) required(ctx context.Context) bool {
+	// video files are not supported
+	if _, ok := t.Image.Files.Primary().(*models.ImageFile); !ok {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	colors, err := t.repository.Image.GetColorPalette(ctx, t.Image.ID)
+	if err != nil {
+		logger.Errorf("error getting color palette for image %s: %v", t.Image.Path, err)
+		return false
+	}
+
+	return len(colors) == 0
+}
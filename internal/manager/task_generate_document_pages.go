@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file/document"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// GenerateDocumentPagesTask generates the page thumbnails of a document
+// gallery.
+type GenerateDocumentPagesTask struct {
+	Gallery   models.Gallery
+	File      *models.DocumentFile
+	Overwrite bool
+}
+
+func (t *GenerateDocumentPagesTask) GetDescription() string {
+	return fmt.Sprintf("Generating page thumbnails for %s", t.File.Path)
+}
+
+func (t *GenerateDocumentPagesTask) Start(ctx context.Context) {
+	renderer := GetInstance().DocumentRenderer
+
+	for page := 1; page <= t.File.PageCount; page++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		thumbPath := t.pagePath(page)
+		if !t.Overwrite {
+			if exists, _ := fsutil.FileExists(thumbPath); exists {
+				continue
+			}
+		}
+
+		data, err := renderer.RenderPage(t.File, page, models.DefaultGthumbWidth)
+		if err != nil {
+			logger.Errorf("rendering page %d of %s: %v", page, t.File.Path, err)
+			return
+		}
+
+		if err := fsutil.WriteFile(thumbPath, data); err != nil {
+			logger.Errorf("writing page thumbnail for %s: %v", t.File.Path, err)
+			return
+		}
+	}
+}
+
+func (t *GenerateDocumentPagesTask) pagePath(page int) string {
+	return GetInstance().Paths.Generated.GetDocumentPagePath(document.Checksum(t.File), page, models.DefaultGthumbWidth)
+}
+
+func (t *GenerateDocumentPagesTask) required() bool {
+	if t.Overwrite {
+		return true
+	}
+
+	for page := 1; page <= t.File.PageCount; page++ {
+		if exists, _ := fsutil.FileExists(t.pagePath(page)); !exists {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/hash/imagephash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type GenerateImagePhashTask struct {
+	repository models.Repository
+	File       *models.ImageFile
+	Overwrite  bool
+}
+
+func (t *GenerateImagePhashTask) GetDescription() string {
+	return fmt.Sprintf("Generating phash for %s", t.File.Path)
+}
+
+func (t *GenerateImagePhashTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	reader, err := t.File.Open(GetInstance().FS)
+	if err != nil {
+		logger.Errorf("error opening image %s: %v", t.File.Path, err)
+		return
+	}
+	defer reader.Close()
+
+	hash, err := imagephash.Generate(reader)
+	if err != nil {
+		logger.Errorf("error generating phash for %s: %v", t.File.Path, err)
+		return
+	}
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		hashValue := int64(*hash)
+		t.File.Fingerprints = t.File.Fingerprints.AppendUnique(models.Fingerprint{
+			Type:        models.FingerprintTypePhash,
+			Fingerprint: hashValue,
+		})
+
+		return r.File.Update(ctx, t.File)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting phash: %v", err)
+	}
+}
+
+func (t *GenerateImagePhashTask) required() bool {
+	if t.Overwrite {
+		return true
+	}
+
+	return t.File.Fingerprints.Get(models.FingerprintTypePhash) == nil
+}
@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type GenerateImageThumbnailTask struct {
+	Image     models.Image
+	Overwrite bool
+}
+
+func (t *GenerateImageThumbnailTask) GetDescription() string {
+	return fmt.Sprintf("Generating thumbnail for %s", t.Image.Path)
+}
+
+func (t *GenerateImageThumbnailTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(t.Image.Checksum, models.DefaultGthumbWidth)
+	if err := generateImageThumbnail(t.Image.Files.Primary(), thumbPath); err != nil {
+		logger.Errorf("Error generating thumbnail for %s: %v", t.Image.Path, err)
+	}
+}
+
+func (t *GenerateImageThumbnailTask) required() bool {
+	f := t.Image.Files.Primary()
+	if f == nil || !imageThumbnailRequired(f) {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	thumbPath := GetInstance().Paths.Generated.GetThumbnailPath(t.Image.Checksum, models.DefaultGthumbWidth)
+	exists, _ := fsutil.FileExists(thumbPath)
+	return !exists
+}
+
+// imageThumbnailRequired returns false if f is small enough to be served as
+// its own thumbnail.
+func imageThumbnailRequired(f models.File) bool {
+	vf, ok := f.(models.VisualFile)
+	if !ok {
+		return false
+	}
+
+	return vf.GetHeight() > models.DefaultGthumbWidth || vf.GetWidth() > models.DefaultGthumbWidth
+}
+
+// generateImageThumbnail writes the thumbnail of f to thumbPath, using the
+// configured thumbnail backend. Animated images are silently skipped.
+func generateImageThumbnail(f models.File, thumbPath string) error {
+	path := f.Base().Path
+
+	logger.Debugf("Generating thumbnail for %s", path)
+
+	mgr := GetInstance()
+	c := mgr.Config
+
+	clipPreviewOptions := image.ClipPreviewOptions{
+		InputArgs:  c.GetTranscodeInputArgs(),
+		OutputArgs: c.GetTranscodeOutputArgs(),
+		Preset:     c.GetPreviewPreset().String(),
+	}
+
+	encoder := image.NewThumbnailEncoder(mgr.FFMPEG, mgr.FFProbe, clipPreviewOptions)
+	encoder.FS = mgr.FS
+	encoder.Backend = c.GetImageThumbnailBackend()
+	data, err := encoder.GetThumbnail(f, models.DefaultGthumbWidth)
+
+	if err != nil {
+		// don't log for animated images
+		if !errors.Is(err, image.ErrNotSupportedForThumbnail) {
+			return fmt.Errorf("getting thumbnail for image %s: %w", path, err)
+		}
+		return nil
+	}
+
+	err = fsutil.WriteFile(thumbPath, data)
+	if err != nil {
+		return fmt.Errorf("writing thumbnail for image %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -41,11 +41,15 @@ func (t *GenerateInteractiveHeatmapSpeedTask) Start(ctx context.Context) {
 	}
 
 	median := generator.InteractiveSpeed
+	stats := generator.Statistics
 
 	r := t.repository
 	if err := r.WithTxn(ctx, func(ctx context.Context) error {
 		primaryFile := t.Scene.Files.Primary()
 		primaryFile.InteractiveSpeed = &median
+		primaryFile.InteractiveAverageSpeed = &stats.AverageSpeed
+		primaryFile.InteractiveMaxSpeed = &stats.MaxSpeed
+		primaryFile.InteractiveActionDensity = &stats.ActionDensity
 		qb := r.File
 		return qb.Update(ctx, primaryFile)
 	}); err != nil && ctx.Err() == nil {
@@ -64,7 +68,7 @@ func (t *GenerateInteractiveHeatmapSpeedTask) required() bool {
 	}
 
 	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
-	return !t.doesHeatmapExist(sceneHash) || primaryFile.InteractiveSpeed == nil
+	return !t.doesHeatmapExist(sceneHash) || primaryFile.InteractiveSpeed == nil || primaryFile.InteractiveAverageSpeed == nil
 }
 
 func (t *GenerateInteractiveHeatmapSpeedTask) doesHeatmapExist(sceneChecksum string) bool {
@@ -18,12 +18,51 @@ type GenerateMarkersTask struct {
 	Overwrite           bool
 	fileNamingAlgorithm models.HashAlgorithm
 
-	ImagePreview bool
-	Screenshot   bool
+	ImagePreview   bool
+	Screenshot     bool
+	PreviewOptions markerPreviewOptions
 
 	generator *generate.Generator
 }
 
+// markerPreviewOptions are the options of the preview of a marker.
+type markerPreviewOptions struct {
+	Format models.MarkerPreviewFormat
+	// Duration and Width use the generator defaults if zero.
+	Duration float64
+	Width    int
+	// MaxDuration is the length of the marker range from the preview start.
+	// Zero if the marker has no end.
+	MaxDuration float64
+}
+
+// forMarker returns the options with the overrides of sceneMarker applied.
+func (o markerPreviewOptions) forMarker(sceneMarker *models.SceneMarker) markerPreviewOptions {
+	if sceneMarker.PreviewFormat != nil {
+		o.Format = *sceneMarker.PreviewFormat
+	}
+	if sceneMarker.PreviewDuration != nil {
+		o.Duration = *sceneMarker.PreviewDuration
+	}
+	if sceneMarker.PreviewWidth != nil {
+		o.Width = *sceneMarker.PreviewWidth
+	}
+	if sceneMarker.EndSeconds != nil {
+		// previews start at the whole second of the marker start
+		o.MaxDuration = *sceneMarker.EndSeconds - float64(int(sceneMarker.Seconds))
+	}
+
+	return o
+}
+
+func (o markerPreviewOptions) generateOptions() generate.MarkerPreviewOptions {
+	return generate.MarkerPreviewOptions{
+		Duration:    o.Duration,
+		Width:       o.Width,
+		MaxDuration: o.MaxDuration,
+	}
+}
+
 func (t *GenerateMarkersTask) GetDescription() string {
 	if t.Scene != nil {
 		return fmt.Sprintf("Generating markers for %s", t.Scene.Path)
@@ -104,18 +143,37 @@ func (t *GenerateMarkersTask) generateSceneMarkers(ctx context.Context) {
 }
 
 func (t *GenerateMarkersTask) generateMarker(videoFile *models.VideoFile, scene *models.Scene, sceneMarker *models.SceneMarker) {
-	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	sceneHash := scene.GetHash(t.fileNamingAlgorithm)
 	seconds := int(sceneMarker.Seconds)
+	options := t.PreviewOptions.forMarker(sceneMarker)
 
 	g := t.generator
 
-	if err := g.MarkerPreviewVideo(context.TODO(), videoFile.Path, sceneHash, seconds, instance.Config.GetPreviewAudio()); err != nil {
-		logger.Errorf("[generator] failed to generate marker video: %v", err)
-		logErrorOutput(err)
+	switch options.Format {
+	case models.MarkerPreviewFormatWebp:
+		if err := g.MarkerPreviewWebp(context.TODO(), videoFile.Path, sceneHash, seconds, options.generateOptions()); err != nil {
+			logger.Errorf("[generator] failed to generate marker preview image: %v", err)
+			logErrorOutput(err)
+		}
+	case models.MarkerPreviewFormatStatic:
+		width := options.Width
+		if width <= 0 {
+			width = videoFile.Width
+		}
+		if err := g.MarkerPreviewStatic(context.TODO(), videoFile.Path, sceneHash, seconds, width); err != nil {
+			logger.Errorf("[generator] failed to generate marker preview screenshot: %v", err)
+			logErrorOutput(err)
+		}
+	default:
+		if err := g.MarkerPreviewVideo(context.TODO(), videoFile.Path, sceneHash, seconds, options.generateOptions(), instance.Config.GetPreviewAudio()); err != nil {
+			logger.Errorf("[generator] failed to generate marker video: %v", err)
+			logErrorOutput(err)
+		}
 	}
 
+	// the duration of the preview only applies to the preview format
 	if t.ImagePreview {
-		if err := g.SceneMarkerWebp(context.TODO(), videoFile.Path, sceneHash, seconds); err != nil {
+		if err := g.SceneMarkerWebp(context.TODO(), videoFile.Path, sceneHash, seconds, generate.MarkerPreviewOptions{Width: options.Width, MaxDuration: options.MaxDuration}); err != nil {
 			logger.Errorf("[generator] failed to generate marker image: %v", err)
 			logErrorOutput(err)
 		}
@@ -143,9 +201,7 @@ func (t *GenerateMarkersTask) markersNeeded(ctx context.Context) int {
 
 	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
 	for _, sceneMarker := range sceneMarkers {
-		seconds := int(sceneMarker.Seconds)
-
-		if t.Overwrite || !t.markerExists(sceneHash, seconds) {
+		if t.Overwrite || !t.markerExists(sceneHash, sceneMarker) {
 			markers++
 		}
 	}
@@ -153,16 +209,28 @@ func (t *GenerateMarkersTask) markersNeeded(ctx context.Context) int {
 	return markers
 }
 
-func (t *GenerateMarkersTask) markerExists(sceneChecksum string, seconds int) bool {
+func (t *GenerateMarkersTask) markerExists(sceneChecksum string, sceneMarker *models.SceneMarker) bool {
 	if sceneChecksum == "" {
 		return false
 	}
 
-	videoExists := t.videoExists(sceneChecksum, seconds)
+	seconds := int(sceneMarker.Seconds)
+	format := t.PreviewOptions.forMarker(sceneMarker).Format
+
+	var previewExists bool
+	switch format {
+	case models.MarkerPreviewFormatWebp:
+		previewExists, _ = fsutil.FileExists(instance.Paths.SceneMarkers.GetWebpFormatPreviewPath(sceneChecksum, seconds))
+	case models.MarkerPreviewFormatStatic:
+		previewExists, _ = fsutil.FileExists(instance.Paths.SceneMarkers.GetStaticFormatPreviewPath(sceneChecksum, seconds))
+	default:
+		previewExists = t.videoExists(sceneChecksum, seconds)
+	}
+
 	imageExists := !t.ImagePreview || t.imageExists(sceneChecksum, seconds)
 	screenshotExists := !t.Screenshot || t.screenshotExists(sceneChecksum, seconds)
 
-	return videoExists && imageExists && screenshotExists
+	return previewExists && imageExists && screenshotExists
 }
 
 func (t *GenerateMarkersTask) videoExists(sceneChecksum string, seconds int) bool {
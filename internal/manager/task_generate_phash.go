@@ -32,18 +32,22 @@ func (t *GeneratePhashTask) Start(ctx context.Context) {
 		return
 	}
 
+	if err := t.setPhash(ctx, *hash); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting phash: %v", err)
+	}
+}
+
+func (t *GeneratePhashTask) setPhash(ctx context.Context, hash uint64) error {
 	r := t.repository
-	if err := r.WithTxn(ctx, func(ctx context.Context) error {
-		hashValue := int64(*hash)
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		hashValue := int64(hash)
 		t.File.Fingerprints = t.File.Fingerprints.AppendUnique(models.Fingerprint{
 			Type:        models.FingerprintTypePhash,
 			Fingerprint: hashValue,
 		})
 
 		return r.File.Update(ctx, t.File)
-	}); err != nil && ctx.Err() == nil {
-		logger.Errorf("Error setting phash: %v", err)
-	}
+	})
 }
 
 func (t *GeneratePhashTask) required() bool {
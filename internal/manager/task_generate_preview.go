@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -40,6 +41,8 @@ func (t *GeneratePreviewTask) Start(ctx context.Context) {
 			return
 		}
 
+		t.Options.HDR = ffmpeg.IsHDRTransfer(videoFile.ColorTransfer)
+
 		if err := t.generateVideo(videoChecksum, videoFile.VideoStreamDuration, videoFile.FrameRate); err != nil {
 			logger.Errorf("error generating preview: %v", err)
 			logErrorOutput(err)
@@ -48,8 +51,8 @@ func (t *GeneratePreviewTask) Start(ctx context.Context) {
 	}
 
 	if t.imagePreviewRequired() {
-		if err := t.generateWebp(videoChecksum); err != nil {
-			logger.Errorf("error generating preview webp: %v", err)
+		if err := t.generateImage(videoChecksum); err != nil {
+			logger.Errorf("error generating preview image: %v", err)
 			logErrorOutput(err)
 		}
 	}
@@ -74,9 +77,9 @@ func (t *GeneratePreviewTask) generateVideo(videoChecksum string, videoDuration
 	return nil
 }
 
-func (t *GeneratePreviewTask) generateWebp(videoChecksum string) error {
+func (t *GeneratePreviewTask) generateImage(videoChecksum string) error {
 	videoFilename := t.Scene.Path
-	return t.generator.PreviewWebp(context.TODO(), videoFilename, videoChecksum)
+	return t.generator.PreviewImage(context.TODO(), videoFilename, videoChecksum, t.Options.ImageFormat)
 }
 
 func (t *GeneratePreviewTask) required() bool {
@@ -124,7 +127,11 @@ func (t *GeneratePreviewTask) imagePreviewRequired() bool {
 	}
 
 	if t.imagePreviewExists == nil {
-		imageExists, _ := fsutil.FileExists(instance.Paths.Scene.GetWebpPreviewPath(sceneChecksum))
+		imagePath := instance.Paths.Scene.GetWebpPreviewPath(sceneChecksum)
+		if t.Options.ImageFormat == models.PreviewImageFormatAvif {
+			imagePath = instance.Paths.Scene.GetAvifPreviewPath(sceneChecksum)
+		}
+		imageExists, _ := fsutil.FileExists(imagePath)
 		t.imagePreviewExists = &imageExists
 	}
 
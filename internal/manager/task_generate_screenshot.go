@@ -43,13 +43,6 @@ func (t *GenerateCoverTask) Start(ctx context.Context) {
 		return
 	}
 
-	var at float64
-	if t.ScreenshotAt == nil {
-		at = float64(videoFile.Duration) * 0.2
-	} else {
-		at = *t.ScreenshotAt
-	}
-
 	// we'll generate the screenshot, grab the generated data and set it
 	// in the database.
 
@@ -64,7 +57,8 @@ func (t *GenerateCoverTask) Start(ctx context.Context) {
 	}
 
 	coverImageData, err := g.Screenshot(context.TODO(), videoFile.Path, videoFile.Width, videoFile.Duration, generate.ScreenshotOptions{
-		At: &at,
+		At:        t.ScreenshotAt,
+		BestFrame: instance.Config.GetBestFrameCovers(),
 	})
 	if err != nil {
 		logger.Errorf("Error generating screenshot: %v", err)
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+// GenerateWaveformTask generates an image of the audio waveform of a scene,
+// which is shown alongside the scrubber of the scene player.
+type GenerateWaveformTask struct {
+	Scene               models.Scene
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+
+	generator *generate.Generator
+}
+
+func (t *GenerateWaveformTask) GetDescription() string {
+	return fmt.Sprintf("Generating waveform for %s", t.Scene.Path)
+}
+
+func (t *GenerateWaveformTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if err := t.generator.Waveform(ctx, t.Scene.Path, sceneHash); err != nil {
+		logger.Errorf("error generating waveform: %v", err)
+		logErrorOutput(err)
+	}
+}
+
+// required returns true if the waveform needs to be generated
+func (t *GenerateWaveformTask) required() bool {
+	f := t.Scene.Files.Primary()
+	// scenes without audio have no waveform
+	if f == nil || f.AudioCodec == "" {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if sceneHash == "" {
+		return false
+	}
+
+	exists, _ := fsutil.FileExists(instance.Paths.Scene.GetWaveformPath(sceneHash))
+	return !exists
+}
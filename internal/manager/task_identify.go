@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/stashapp/stash/internal/identify"
@@ -22,11 +23,25 @@ type IdentifyJob struct {
 	postHookExecutor identify.SceneUpdatePostHookExecutor
 	input            identify.Options
 
-	stashBoxes []*models.StashBox
-	progress   *job.Progress
+	stashBoxes    []*models.StashBox
+	progress      *job.Progress
+	studioOptions map[int]studioIdentifyOptions
+}
+
+// studioIdentifyOptions are the sources and options used for scenes of a studio.
+type studioIdentifyOptions struct {
+	sources []identify.ScraperSource
+	options *identify.MetadataOptions
 }
 
 func CreateIdentifyJob(input identify.Options) *IdentifyJob {
+	// use the configured studio options if none are provided
+	if input.StudioOptions == nil {
+		if defaults := instance.Config.GetDefaultIdentifySettings(); defaults != nil {
+			input.StudioOptions = defaults.StudioOptions
+		}
+	}
+
 	return &IdentifyJob{
 		postHookExecutor: instance.PluginCache,
 		input:            input,
@@ -36,13 +51,20 @@ func CreateIdentifyJob(input identify.Options) *IdentifyJob {
 
 func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 	j.progress = progress
+	ctx = models.WithEditSource(ctx, models.EditSourceScraper)
 
 	// if no sources provided - just return
 	if len(j.input.Sources) == 0 {
 		return
 	}
 
-	sources, err := j.getSources()
+	sources, err := j.getSources(j.input.Sources)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	j.studioOptions, err = j.getStudioOptions()
 	if err != nil {
 		logger.Error(err)
 		return
@@ -132,6 +154,14 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 		return
 	}
 
+	options := j.input.Options
+	if studioOptions := j.findStudioOptions(ctx, s.StudioID); studioOptions != nil {
+		if len(studioOptions.sources) > 0 {
+			sources = studioOptions.sources
+		}
+		options = identify.MergeOptions(options, studioOptions.options)
+	}
+
 	var taskError error
 	j.progress.ExecuteTask("Identifying "+s.Path, func() {
 		r := instance.Repository
@@ -142,7 +172,7 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 			PerformerCreator:   r.Performer,
 			TagFinderCreator:   r.Tag,
 
-			DefaultOptions:              j.input.Options,
+			DefaultOptions:              options,
 			Sources:                     sources,
 			SceneUpdatePostHookExecutor: j.postHookExecutor,
 		}
@@ -157,9 +187,64 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 	j.progress.Increment()
 }
 
-func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
+// findStudioOptions returns the options for the studio with the given id.
+// If the studio has no options, then the options of the closest parent
+// studio are returned. Returns nil if no options are found.
+func (j *IdentifyJob) findStudioOptions(ctx context.Context, studioID *int) *studioIdentifyOptions {
+	if len(j.studioOptions) == 0 {
+		return nil
+	}
+
+	r := instance.Repository
+	visited := make(map[int]bool)
+	for studioID != nil && !visited[*studioID] {
+		if ret, found := j.studioOptions[*studioID]; found {
+			return &ret
+		}
+
+		visited[*studioID] = true
+
+		studio, err := r.Studio.Find(ctx, *studioID)
+		if err != nil {
+			logger.Warnf("error finding studio %d: %v", *studioID, err)
+			return nil
+		}
+
+		if studio == nil {
+			return nil
+		}
+
+		studioID = studio.ParentID
+	}
+
+	return nil
+}
+
+func (j *IdentifyJob) getStudioOptions() (map[int]studioIdentifyOptions, error) {
+	ret := make(map[int]studioIdentifyOptions)
+	for _, o := range j.input.StudioOptions {
+		studioID, err := strconv.Atoi(o.StudioID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid studio id %q", ErrInput, o.StudioID)
+		}
+
+		sources, err := j.getSources(o.Sources)
+		if err != nil {
+			return nil, err
+		}
+
+		ret[studioID] = studioIdentifyOptions{
+			sources: sources,
+			options: o.Options,
+		}
+	}
+
+	return ret, nil
+}
+
+func (j *IdentifyJob) getSources(input []*identify.Source) ([]identify.ScraperSource, error) {
 	var ret []identify.ScraperSource
-	for _, source := range j.input.Sources {
+	for _, source := range input {
 		// get scraper source
 		stashBox, err := j.getStashBox(source.Source)
 		if err != nil {
@@ -8,12 +8,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/models/jsonschema"
@@ -23,6 +25,7 @@ import (
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/studio"
 	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
 )
 
 type Resetter interface {
@@ -39,14 +42,30 @@ type ImportTask struct {
 	Reset               bool
 	DuplicateBehaviour  ImportDuplicateEnum
 	MissingRefBehaviour models.ImportMissingRefEnum
+	// PathMappings are applied to file and folder paths read from the import data
+	PathMappings fsutil.PathMappings
 
 	fileNamingAlgorithm models.HashAlgorithm
+
+	// progress of the import job. May be nil.
+	progress *job.Progress
+
+	// conflicts found when DuplicateBehaviour is RESOLVE are added to
+	// conflicts and pendingConflicts
+	conflicts        *importConflicts
+	pendingConflicts []*ImportConflict
+
+	// ids of imported galleries with zip entries, keyed by object name. The
+	// zip entries are read again when they are imported, so that they are
+	// not held in memory.
+	galleryZipEntryIDs map[string]int
 }
 
 type ImportObjectsInput struct {
 	File                graphql.Upload              `json:"file"`
 	DuplicateBehaviour  ImportDuplicateEnum         `json:"duplicateBehaviour"`
 	MissingRefBehaviour models.ImportMissingRefEnum `json:"missingRefBehaviour"`
+	PathMappings        []fsutil.PathMapping        `json:"pathMappings"`
 }
 
 func CreateImportTask(a models.HashAlgorithm, input ImportObjectsInput) (*ImportTask, error) {
@@ -80,7 +99,9 @@ func CreateImportTask(a models.HashAlgorithm, input ImportObjectsInput) (*Import
 		Reset:               false,
 		DuplicateBehaviour:  input.DuplicateBehaviour,
 		MissingRefBehaviour: input.MissingRefBehaviour,
+		PathMappings:        input.PathMappings,
 		fileNamingAlgorithm: a,
+		conflicts:           mgr.importConflicts,
 	}, nil
 }
 
@@ -88,6 +109,12 @@ func (t *ImportTask) GetDescription() string {
 	return "Importing..."
 }
 
+// Execute runs the import, reporting its progress to progress.
+func (t *ImportTask) Execute(ctx context.Context, progress *job.Progress) {
+	t.progress = progress
+	t.Start(ctx)
+}
+
 func (t *ImportTask) Start(ctx context.Context) {
 	if t.TmpZip != "" {
 		defer func() {
@@ -124,15 +151,54 @@ func (t *ImportTask) Start(ctx context.Context) {
 		}
 	}
 
-	t.ImportTags(ctx)
-	t.ImportPerformers(ctx)
-	t.ImportStudios(ctx)
-	t.ImportMovies(ctx)
-	t.ImportFiles(ctx)
-	t.ImportGalleries(ctx)
+	jp := t.json.json
+	steps := []struct {
+		name string
+		dir  string
+		fn   func(ctx context.Context)
+	}{
+		{"tags", jp.Tags, t.ImportTags},
+		{"performers", jp.Performers, t.ImportPerformers},
+		{"studios", jp.Studios, t.ImportStudios},
+		{"movies", jp.Movies, t.ImportMovies},
+		{"files", jp.Files, t.ImportFiles},
+		{"galleries", jp.Galleries, t.ImportGalleries},
+		{"scenes", jp.Scenes, t.ImportScenes},
+		{"images", jp.Images, t.ImportImages},
+	}
+
+	var counts []importStepCount
+	for _, step := range steps {
+		count, err := countObjects(step.dir)
+		if err != nil {
+			logger.Errorf("[%s] failed to count objects: %v", step.name, err)
+		}
+		counts = append(counts, importStepCount{name: step.name, count: count})
+	}
+
+	t.json.progress = newImportProgress(t.progress, counts)
+
+	for _, step := range steps {
+		t.executeStep("Importing "+step.name, func() {
+			step.fn(ctx)
+		})
+	}
 
-	t.ImportScenes(ctx)
-	t.ImportImages(ctx)
+	t.executeStep("Importing gallery zip entries", func() {
+		t.ImportGalleryZipEntries(ctx)
+	})
+
+	t.resolveConflicts(ctx)
+}
+
+// executeStep runs fn as a task of the import job, if set.
+func (t *ImportTask) executeStep(description string, fn func()) {
+	if t.progress == nil {
+		fn()
+		return
+	}
+
+	t.progress.ExecuteTask(description, fn)
 }
 
 func (t *ImportTask) unzipFile() error {
@@ -143,15 +209,24 @@ func (t *ImportTask) unzipFile() error {
 		}
 	}()
 
-	// now we can read the zip file
-	r, err := zip.OpenReader(t.TmpZip)
+	return unzipExport(t.TmpZip, t.BaseDir)
+}
+
+// unzipExport extracts the export zip file at zipPath to dir.
+func unzipExport(zipPath string, dir string) error {
+	r, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return err
 	}
 	defer r.Close()
 
 	for _, f := range r.File {
-		fn := filepath.Join(t.BaseDir, f.Name)
+		fn := filepath.Join(dir, f.Name)
+
+		// reject entries that would be written outside of dir
+		if !fsutil.IsPathInDir(dir, fn) {
+			return fmt.Errorf("zip entry %q is outside of the export directory", f.Name)
+		}
 
 		if f.FileInfo().IsDir() {
 			if err := os.MkdirAll(fn, os.ModePerm); err != nil {
@@ -188,43 +263,63 @@ func (t *ImportTask) unzipFile() error {
 	return nil
 }
 
-func (t *ImportTask) ImportPerformers(ctx context.Context) {
-	logger.Info("[performers] importing")
+const (
+	// importBatchSize is the maximum number of objects imported per
+	// transaction.
+	importBatchSize = 100
+	// importBatchDuration is the maximum time an import transaction is kept
+	// open for, so that the import does not block other writes for long.
+	importBatchDuration = time.Second
+)
 
-	path := t.json.json.Performers
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[performers] failed to read performers directory: %v", err)
-		}
+// newBatch returns a batch that imports objects in shared transactions.
+func (t *ImportTask) newBatch() *txn.Batch {
+	return &txn.Batch{
+		Manager:     t.repository.TxnManager,
+		Size:        importBatchSize,
+		MaxDuration: importBatchDuration,
+	}
+}
 
-		return
+// commitBatch commits the objects remaining in batch, logging any error
+// using prefix.
+func commitBatch(prefix string, batch *txn.Batch) {
+	if err := batch.Commit(); err != nil {
+		logger.Errorf("%s failed to commit: %v", prefix, err)
 	}
+}
+
+func (t *ImportTask) ImportPerformers(ctx context.Context) {
+	logger.Info("[performers] importing")
 
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-		performerJSON, err := jsonschema.LoadPerformerFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Errorf("[performers] failed to read json: %s", err.Error())
-			continue
+	t.json.readObjects("[performers]", t.json.json.Performers, func(o jsonObject) {
+		var performerJSON jsonschema.Performer
+		if err := o.decode(&performerJSON); err != nil {
+			logger.Errorf("[performers] <%s> failed to read json: %s", o.name, err.Error())
+			return
 		}
 
-		logger.Progressf("[performers] %d of %d", index, len(files))
+		if err := loadObjectImage(t.json.json.Metadata, performerJSON.ImageFile, &performerJSON.Image); err != nil {
+			logger.Warnf("[performers] <%s> %v", o.name, err)
+		}
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			importer := &performer.Importer{
 				ReaderWriter: r.Performer,
 				TagWriter:    r.Tag,
-				Input:        *performerJSON,
+				Input:        performerJSON,
 			}
 
-			return performImport(ctx, importer, t.DuplicateBehaviour)
+			return performImport(ctx, t.resolvablePerformer(importer), t.DuplicateBehaviour)
 		}); err != nil {
-			logger.Errorf("[performers] <%s> import failed: %s", fi.Name(), err.Error())
+			logger.Errorf("[performers] <%s> import failed: %s", o.name, err.Error())
 		}
-	}
+	})
+
+	commitBatch("[performers]", batch)
 
 	logger.Info("[performers] import complete")
 }
@@ -234,29 +329,20 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 
 	logger.Info("[studios] importing")
 
-	path := t.json.json.Studios
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[studios] failed to read studios directory: %v", err)
-		}
-
-		return
-	}
-
-	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-		studioJSON, err := jsonschema.LoadStudioFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Errorf("[studios] failed to read json: %s", err.Error())
-			continue
+	t.json.readObjects("[studios]", t.json.json.Studios, func(o jsonObject) {
+		studioJSON := &jsonschema.Studio{}
+		if err := o.decode(studioJSON); err != nil {
+			logger.Errorf("[studios] <%s> failed to read json: %s", o.name, err.Error())
+			return
 		}
 
-		logger.Progressf("[studios] %d of %d", index, len(files))
+		if err := loadObjectImage(t.json.json.Metadata, studioJSON.ImageFile, &studioJSON.Image); err != nil {
+			logger.Warnf("[studios] <%s> %v", o.name, err)
+		}
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			return t.importStudio(ctx, studioJSON, pendingParent)
 		}); err != nil {
 			if errors.Is(err, studio.ErrParentStudioNotExist) {
@@ -264,13 +350,12 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 				s := pendingParent[studioJSON.ParentStudio]
 				s = append(s, studioJSON)
 				pendingParent[studioJSON.ParentStudio] = s
-				continue
+				return
 			}
 
-			logger.Errorf("[studios] <%s> failed to create: %s", fi.Name(), err.Error())
-			continue
+			logger.Errorf("[studios] <%s> failed to create: %s", o.name, err.Error())
 		}
-	}
+	})
 
 	// create the leftover studios, warning for missing parents
 	if len(pendingParent) > 0 {
@@ -278,7 +363,7 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 
 		for _, s := range pendingParent {
 			for _, orphanStudioJSON := range s {
-				if err := r.WithTxn(ctx, func(ctx context.Context) error {
+				if err := batch.Do(ctx, func(ctx context.Context) error {
 					return t.importStudio(ctx, orphanStudioJSON, nil)
 				}); err != nil {
 					logger.Errorf("[studios] <%s> failed to create: %s", orphanStudioJSON.Name, err.Error())
@@ -288,6 +373,8 @@ func (t *ImportTask) ImportStudios(ctx context.Context) {
 		}
 	}
 
+	commitBatch("[studios]", batch)
+
 	logger.Info("[studios] import complete")
 }
 
@@ -303,7 +390,7 @@ func (t *ImportTask) importStudio(ctx context.Context, studioJSON *jsonschema.St
 		importer.MissingRefBehaviour = models.ImportMissingRefEnumFail
 	}
 
-	if err := performImport(ctx, importer, t.DuplicateBehaviour); err != nil {
+	if err := performImport(ctx, t.resolvableStudio(importer), t.DuplicateBehaviour); err != nil {
 		return err
 	}
 
@@ -325,42 +412,38 @@ func (t *ImportTask) importStudio(ctx context.Context, studioJSON *jsonschema.St
 func (t *ImportTask) ImportMovies(ctx context.Context) {
 	logger.Info("[movies] importing")
 
-	path := t.json.json.Movies
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[movies] failed to read movies directory: %v", err)
-		}
-
-		return
-	}
-
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-		movieJSON, err := jsonschema.LoadMovieFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Errorf("[movies] failed to read json: %s", err.Error())
-			continue
+	t.json.readObjects("[movies]", t.json.json.Movies, func(o jsonObject) {
+		var movieJSON jsonschema.Movie
+		if err := o.decode(&movieJSON); err != nil {
+			logger.Errorf("[movies] <%s> failed to read json: %s", o.name, err.Error())
+			return
 		}
 
-		logger.Progressf("[movies] %d of %d", index, len(files))
+		if err := loadObjectImage(t.json.json.Metadata, movieJSON.FrontImageFile, &movieJSON.FrontImage); err != nil {
+			logger.Warnf("[movies] <%s> %v", o.name, err)
+		}
+		if err := loadObjectImage(t.json.json.Metadata, movieJSON.BackImageFile, &movieJSON.BackImage); err != nil {
+			logger.Warnf("[movies] <%s> %v", o.name, err)
+		}
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			movieImporter := &movie.Importer{
 				ReaderWriter:        r.Movie,
 				StudioWriter:        r.Studio,
-				Input:               *movieJSON,
+				Input:               movieJSON,
 				MissingRefBehaviour: t.MissingRefBehaviour,
 			}
 
-			return performImport(ctx, movieImporter, t.DuplicateBehaviour)
+			return performImport(ctx, t.resolvableMovie(movieImporter), t.DuplicateBehaviour)
 		}); err != nil {
-			logger.Errorf("[movies] <%s> import failed: %s", fi.Name(), err.Error())
-			continue
+			logger.Errorf("[movies] <%s> import failed: %s", o.name, err.Error())
 		}
-	}
+	})
+
+	commitBatch("[movies]", batch)
 
 	logger.Info("[movies] import complete")
 }
@@ -368,45 +451,31 @@ func (t *ImportTask) ImportMovies(ctx context.Context) {
 func (t *ImportTask) ImportFiles(ctx context.Context) {
 	logger.Info("[files] importing")
 
-	path := t.json.json.Files
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[files] failed to read files directory: %v", err)
-		}
-
-		return
-	}
-
-	r := t.repository
+	batch := t.newBatch()
 
 	pendingParent := make(map[string][]jsonschema.DirEntry)
 
-	for i, fi := range files {
-		index := i + 1
-		fileJSON, err := jsonschema.LoadFileFile(filepath.Join(path, fi.Name()))
+	t.json.readObjects("[files]", t.json.json.Files, func(o jsonObject) {
+		fileJSON, err := o.dirEntry()
 		if err != nil {
-			logger.Errorf("[files] failed to read json: %s", err.Error())
-			continue
+			logger.Errorf("[files] <%s> failed to read json: %s", o.name, err.Error())
+			return
 		}
 
-		logger.Progressf("[files] %d of %d", index, len(files))
+		t.mapDirEntryPaths(fileJSON)
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
-			return t.importFile(ctx, fileJSON, pendingParent)
-		}); err != nil {
+		if err := t.importFileTree(ctx, batch, fileJSON, pendingParent); err != nil {
 			if errors.Is(err, file.ErrZipFileNotExist) {
 				// add to the pending parent list so that it is created after the parent
 				s := pendingParent[fileJSON.DirEntry().ZipFile]
 				s = append(s, fileJSON)
 				pendingParent[fileJSON.DirEntry().ZipFile] = s
-				continue
+				return
 			}
 
-			logger.Errorf("[files] <%s> failed to create: %s", fi.Name(), err.Error())
-			continue
+			logger.Errorf("[files] <%s> failed to create: %s", o.name, err.Error())
 		}
-	}
+	})
 
 	// create the leftover studios, warning for missing parents
 	if len(pendingParent) > 0 {
@@ -414,9 +483,7 @@ func (t *ImportTask) ImportFiles(ctx context.Context) {
 
 		for _, s := range pendingParent {
 			for _, orphanFileJSON := range s {
-				if err := r.WithTxn(ctx, func(ctx context.Context) error {
-					return t.importFile(ctx, orphanFileJSON, nil)
-				}); err != nil {
+				if err := t.importFileTree(ctx, batch, orphanFileJSON, nil); err != nil {
 					logger.Errorf("[files] <%s> failed to create: %s", orphanFileJSON.DirEntry().Path, err.Error())
 					continue
 				}
@@ -424,64 +491,67 @@ func (t *ImportTask) ImportFiles(ctx context.Context) {
 		}
 	}
 
+	commitBatch("[files]", batch)
+
 	logger.Info("[files] import complete")
 }
 
-func (t *ImportTask) importFile(ctx context.Context, fileJSON jsonschema.DirEntry, pendingParent map[string][]jsonschema.DirEntry) error {
-	r := t.repository
-
-	fileImporter := &file.Importer{
-		ReaderWriter: r.File,
-		FolderStore:  r.Folder,
-		Input:        fileJSON,
-	}
-
-	// ignore duplicate files - don't overwrite
-	if err := performImport(ctx, fileImporter, ImportDuplicateEnumIgnore); err != nil {
+// importFileTree imports fileJSON followed by the files pending its
+// creation. Each file is imported separately in batch, so that zip files
+// containing many files do not keep the batch transaction open.
+func (t *ImportTask) importFileTree(ctx context.Context, batch *txn.Batch, fileJSON jsonschema.DirEntry, pendingParent map[string][]jsonschema.DirEntry) error {
+	if err := batch.Do(ctx, func(ctx context.Context) error {
+		return t.importFile(ctx, fileJSON)
+	}); err != nil {
 		return err
 	}
 
 	// now create the files pending this file's creation
-	s := pendingParent[fileJSON.DirEntry().Path]
+	path := fileJSON.DirEntry().Path
+	s := pendingParent[path]
+
+	// delete the entry from the map so that we know its not left over
+	delete(pendingParent, path)
+
 	for _, childFileJSON := range s {
-		// map is nil since we're not checking parent studios at this point
-		if err := t.importFile(ctx, childFileJSON, nil); err != nil {
-			return fmt.Errorf("failed to create child file <%s>: %s", childFileJSON.DirEntry().Path, err.Error())
+		if err := t.importFileTree(ctx, batch, childFileJSON, pendingParent); err != nil {
+			logger.Errorf("[files] <%s> failed to create child file <%s>: %s", path, childFileJSON.DirEntry().Path, err.Error())
 		}
 	}
 
-	// delete the entry from the map so that we know its not left over
-	delete(pendingParent, fileJSON.DirEntry().Path)
-
 	return nil
 }
 
-func (t *ImportTask) ImportGalleries(ctx context.Context) {
-	logger.Info("[galleries] importing")
-
-	path := t.json.json.Galleries
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[galleries] failed to read galleries directory: %v", err)
-		}
+func (t *ImportTask) importFile(ctx context.Context, fileJSON jsonschema.DirEntry) error {
+	r := t.repository
 
-		return
+	fileImporter := &file.Importer{
+		ReaderWriter: r.File,
+		FolderStore:  r.Folder,
+		Input:        fileJSON,
 	}
 
+	// ignore duplicate files - don't overwrite
+	return performImport(ctx, fileImporter, ImportDuplicateEnumIgnore)
+}
+
+func (t *ImportTask) ImportGalleries(ctx context.Context) {
+	logger.Info("[galleries] importing")
+
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-		galleryJSON, err := jsonschema.LoadGalleryFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Errorf("[galleries] failed to read json: %s", err.Error())
-			continue
+	t.json.readObjects("[galleries]", t.json.json.Galleries, func(o jsonObject) {
+		var galleryJSON jsonschema.Gallery
+		if err := o.decode(&galleryJSON); err != nil {
+			logger.Errorf("[galleries] <%s> failed to read json: %s", o.name, err.Error())
+			return
 		}
 
-		logger.Progressf("[galleries] %d of %d", index, len(files))
+		galleryJSON.ZipFiles = t.PathMappings.ApplyAll(galleryJSON.ZipFiles)
+		galleryJSON.FolderPath = t.PathMappings.Apply(galleryJSON.FolderPath)
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			galleryImporter := &gallery.Importer{
 				ReaderWriter:        r.Gallery,
 				FolderFinder:        r.Folder,
@@ -489,7 +559,7 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 				PerformerWriter:     r.Performer,
 				StudioWriter:        r.Studio,
 				TagWriter:           r.Tag,
-				Input:               *galleryJSON,
+				Input:               galleryJSON,
 				MissingRefBehaviour: t.MissingRefBehaviour,
 			}
 
@@ -497,6 +567,16 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 				return err
 			}
 
+			if galleryImporter.ID != 0 && len(galleryJSON.ZipEntries) > 0 {
+				galleryID := galleryImporter.ID
+				txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+					if t.galleryZipEntryIDs == nil {
+						t.galleryZipEntryIDs = make(map[string]int)
+					}
+					t.galleryZipEntryIDs[o.name] = galleryID
+				})
+			}
+
 			// import the gallery chapters
 			for _, m := range galleryJSON.Chapters {
 				chapterImporter := &gallery.ChapterImporter{
@@ -513,57 +593,110 @@ func (t *ImportTask) ImportGalleries(ctx context.Context) {
 
 			return nil
 		}); err != nil {
-			logger.Errorf("[galleries] <%s> import failed to commit: %s", fi.Name(), err.Error())
-			continue
+			logger.Errorf("[galleries] <%s> import failed to commit: %s", o.name, err.Error())
 		}
-	}
+	})
+
+	commitBatch("[galleries]", batch)
 
 	logger.Info("[galleries] import complete")
 }
 
-func (t *ImportTask) ImportTags(ctx context.Context) {
-	pendingParent := make(map[string][]*jsonschema.Tag)
-	logger.Info("[tags] importing")
-
-	path := t.json.json.Tags
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[tags] failed to read tags directory: %v", err)
-		}
-
+// ImportGalleryZipEntries creates images for the zip entries of imported
+// galleries that were not imported as images. Must be run after images are
+// imported.
+func (t *ImportTask) ImportGalleryZipEntries(ctx context.Context) {
+	if len(t.galleryZipEntryIDs) == 0 {
 		return
 	}
 
+	logger.Info("[galleries] importing zip entries")
+
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-		tagJSON, err := jsonschema.LoadTagFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Errorf("[tags] failed to read json: %s", err.Error())
-			continue
+	// galleries are read a second time, so do not count them towards the
+	// import progress
+	jp := t.json
+	jp.progress = nil
+
+	jp.readObjects("[galleries]", t.json.json.Galleries, func(o jsonObject) {
+		galleryID, found := t.galleryZipEntryIDs[o.name]
+		if !found {
+			return
 		}
 
-		logger.Progressf("[tags] %d of %d", index, len(files))
+		var galleryJSON jsonschema.Gallery
+		if err := o.decode(&galleryJSON); err != nil {
+			logger.Errorf("[galleries] <%s> failed to read json: %v", o.name, err)
+			return
+		}
+
+		entries := galleryJSON.ZipEntries
+		for i := range entries {
+			entries[i].Path = t.PathMappings.Apply(entries[i].Path)
+		}
+
+		var created int
+		if err := batch.Do(ctx, func(ctx context.Context) error {
+			zipEntriesImporter := &gallery.ZipEntriesImporter{
+				FileFinder:  r.File,
+				ImageWriter: r.Image,
+				GalleryID:   galleryID,
+				Entries:     entries,
+			}
+
+			var err error
+			created, err = zipEntriesImporter.Import(ctx)
+			return err
+		}); err != nil {
+			logger.Errorf("[galleries] <%s> zip entries import failed: %v", o.name, err)
+			return
+		}
+
+		if created > 0 {
+			logger.Infof("[galleries] <%s> created %d images from zip entries", o.name, created)
+		}
+	})
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+	commitBatch("[galleries]", batch)
+
+	logger.Info("[galleries] zip entries import complete")
+}
+
+func (t *ImportTask) ImportTags(ctx context.Context) {
+	pendingParent := make(map[string][]*jsonschema.Tag)
+	logger.Info("[tags] importing")
+
+	batch := t.newBatch()
+
+	t.json.readObjects("[tags]", t.json.json.Tags, func(o jsonObject) {
+		tagJSON := &jsonschema.Tag{}
+		if err := o.decode(tagJSON); err != nil {
+			logger.Errorf("[tags] <%s> failed to read json: %s", o.name, err.Error())
+			return
+		}
+
+		if err := loadObjectImage(t.json.json.Metadata, tagJSON.ImageFile, &tagJSON.Image); err != nil {
+			logger.Warnf("[tags] <%s> %v", o.name, err)
+		}
+
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			return t.importTag(ctx, tagJSON, pendingParent, false)
 		}); err != nil {
 			var parentError tag.ParentTagNotExistError
 			if errors.As(err, &parentError) {
 				pendingParent[parentError.MissingParent()] = append(pendingParent[parentError.MissingParent()], tagJSON)
-				continue
+				return
 			}
 
-			logger.Errorf("[tags] <%s> failed to import: %s", fi.Name(), err.Error())
-			continue
+			logger.Errorf("[tags] <%s> failed to import: %s", o.name, err.Error())
 		}
-	}
+	})
 
 	for _, s := range pendingParent {
 		for _, orphanTagJSON := range s {
-			if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			if err := batch.Do(ctx, func(ctx context.Context) error {
 				return t.importTag(ctx, orphanTagJSON, nil, true)
 			}); err != nil {
 				logger.Errorf("[tags] <%s> failed to create: %s", orphanTagJSON.Name, err.Error())
@@ -572,6 +705,8 @@ func (t *ImportTask) ImportTags(ctx context.Context) {
 		}
 	}
 
+	commitBatch("[tags]", batch)
+
 	logger.Info("[tags] import complete")
 }
 
@@ -587,7 +722,7 @@ func (t *ImportTask) importTag(ctx context.Context, tagJSON *jsonschema.Tag, pen
 		importer.MissingRefBehaviour = models.ImportMissingRefEnumFail
 	}
 
-	if err := performImport(ctx, importer, t.DuplicateBehaviour); err != nil {
+	if err := performImport(ctx, t.resolvableTag(importer), t.DuplicateBehaviour); err != nil {
 		return err
 	}
 
@@ -611,33 +746,27 @@ func (t *ImportTask) importTag(ctx context.Context, tagJSON *jsonschema.Tag, pen
 func (t *ImportTask) ImportScenes(ctx context.Context) {
 	logger.Info("[scenes] importing")
 
-	path := t.json.json.Scenes
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[scenes] failed to read scenes directory: %v", err)
-		}
-
-		return
-	}
-
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
+	t.json.readObjects("[scenes]", t.json.json.Scenes, func(o jsonObject) {
+		var sceneJSON jsonschema.Scene
+		if err := o.decode(&sceneJSON); err != nil {
+			logger.Infof("[scenes] <%s> json parse failure: %s", o.name, err.Error())
+			return
+		}
 
-		logger.Progressf("[scenes] %d of %d", index, len(files))
+		sceneJSON.Files = t.PathMappings.ApplyAll(sceneJSON.Files)
+		t.mapGalleryRefPaths(sceneJSON.Galleries)
 
-		sceneJSON, err := jsonschema.LoadSceneFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Infof("[scenes] <%s> json parse failure: %s", fi.Name(), err.Error())
-			continue
+		if err := loadObjectImage(t.json.json.Metadata, sceneJSON.CoverFile, &sceneJSON.Cover); err != nil {
+			logger.Warnf("[scenes] <%s> %v", o.name, err)
 		}
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			sceneImporter := &scene.Importer{
 				ReaderWriter: r.Scene,
-				Input:        *sceneJSON,
+				Input:        sceneJSON,
 				FileFinder:   r.File,
 
 				FileNamingAlgorithm: t.fileNamingAlgorithm,
@@ -671,9 +800,11 @@ func (t *ImportTask) ImportScenes(ctx context.Context) {
 
 			return nil
 		}); err != nil {
-			logger.Errorf("[scenes] <%s> import failed: %s", fi.Name(), err.Error())
+			logger.Errorf("[scenes] <%s> import failed: %s", o.name, err.Error())
 		}
-	}
+	})
+
+	commitBatch("[scenes]", batch)
 
 	logger.Info("[scenes] import complete")
 }
@@ -681,34 +812,24 @@ func (t *ImportTask) ImportScenes(ctx context.Context) {
 func (t *ImportTask) ImportImages(ctx context.Context) {
 	logger.Info("[images] importing")
 
-	path := t.json.json.Images
-	files, err := os.ReadDir(path)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			logger.Errorf("[images] failed to read images directory: %v", err)
-		}
-
-		return
-	}
-
 	r := t.repository
+	batch := t.newBatch()
 
-	for i, fi := range files {
-		index := i + 1
-
-		logger.Progressf("[images] %d of %d", index, len(files))
-
-		imageJSON, err := jsonschema.LoadImageFile(filepath.Join(path, fi.Name()))
-		if err != nil {
-			logger.Infof("[images] <%s> json parse failure: %s", fi.Name(), err.Error())
-			continue
+	t.json.readObjects("[images]", t.json.json.Images, func(o jsonObject) {
+		var imageJSON jsonschema.Image
+		if err := o.decode(&imageJSON); err != nil {
+			logger.Infof("[images] <%s> json parse failure: %s", o.name, err.Error())
+			return
 		}
 
-		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		imageJSON.Files = t.PathMappings.ApplyAll(imageJSON.Files)
+		t.mapGalleryRefPaths(imageJSON.Galleries)
+
+		if err := batch.Do(ctx, func(ctx context.Context) error {
 			imageImporter := &image.Importer{
 				ReaderWriter: r.Image,
 				FileFinder:   r.File,
-				Input:        *imageJSON,
+				Input:        imageJSON,
 
 				MissingRefBehaviour: t.MissingRefBehaviour,
 
@@ -720,9 +841,28 @@ func (t *ImportTask) ImportImages(ctx context.Context) {
 
 			return performImport(ctx, imageImporter, t.DuplicateBehaviour)
 		}); err != nil {
-			logger.Errorf("[images] <%s> import failed: %s", fi.Name(), err.Error())
+			logger.Errorf("[images] <%s> import failed: %s", o.name, err.Error())
 		}
-	}
+	})
+
+	commitBatch("[images]", batch)
 
 	logger.Info("[images] import complete")
 }
+
+// mapDirEntryPaths rewrites the path and zip file path of the file or folder
+// using the import path mappings.
+func (t *ImportTask) mapDirEntryPaths(fileJSON jsonschema.DirEntry) {
+	e := fileJSON.DirEntry()
+	e.Path = t.PathMappings.Apply(e.Path)
+	if e.ZipFile != "" {
+		e.ZipFile = t.PathMappings.Apply(e.ZipFile)
+	}
+}
+
+func (t *ImportTask) mapGalleryRefPaths(refs []jsonschema.GalleryRef) {
+	for i := range refs {
+		refs[i].ZipFiles = t.PathMappings.ApplyAll(refs[i].ZipFiles)
+		refs[i].FolderPath = t.PathMappings.Apply(refs[i].FolderPath)
+	}
+}
@@ -0,0 +1,242 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// importNFOJob populates scene metadata from Kodi/Jellyfin NFO sidecar files.
+type importNFOJob struct {
+	repository models.Repository
+	input      ImportNFOInput
+}
+
+func (j *importNFOJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+
+	r := j.repository
+	sceneFilter := scene.FilterFromPaths(j.input.Paths)
+
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		pp := 0
+		_, total, err := scene.QueryWithCount(ctx, r.Scene, sceneFilter, &models.FindFilterType{
+			PerPage: &pp,
+		})
+		if err != nil {
+			return err
+		}
+		progress.SetTotal(total)
+		return nil
+	}); err != nil {
+		logger.Errorf("error counting scenes for nfo import: %v", err)
+		return
+	}
+
+	logger.Info("Importing scene metadata from nfo files...")
+
+	const batchSize = 1000
+	findFilter := models.BatchFindFilter(batchSize)
+
+	imported := 0
+	more := true
+	for more {
+		var scenes []*models.Scene
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			var err error
+			scenes, err = scene.Query(ctx, r.Scene, sceneFilter, findFilter)
+			return err
+		}); err != nil {
+			if !job.IsCancelled(ctx) {
+				logger.Errorf("error querying scenes for nfo import: %v", err)
+			}
+			return
+		}
+
+		for _, s := range scenes {
+			if job.IsCancelled(ctx) {
+				logger.Info("Stopping nfo import due to user request")
+				return
+			}
+
+			progress.ExecuteTask("Importing nfo for "+s.Path, func() {
+				ok, err := j.importScene(ctx, s)
+				if err != nil {
+					logger.Errorf("error importing nfo for %s: %v", s.Path, err)
+				} else if ok {
+					imported++
+				}
+			})
+
+			progress.Increment()
+		}
+
+		if len(scenes) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
+	logger.Infof("Imported metadata for %d scenes from nfo files after %s", imported, time.Since(begin).String())
+}
+
+// importScene applies the NFO file of s, if there is one. Returns true if
+// an NFO file was found.
+func (j *importNFOJob) importScene(ctx context.Context, s *models.Scene) (bool, error) {
+	if s.Path == "" {
+		return false, nil
+	}
+
+	nfoPath := scene.FindNFOFile(s.Path)
+	if nfoPath == "" {
+		return false, nil
+	}
+
+	nfo, err := scene.LoadNFOFile(nfoPath)
+	if err != nil {
+		return false, err
+	}
+
+	r := j.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		partial, err := j.scenePartial(ctx, s, nfo)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.Scene.UpdatePartial(ctx, s.ID, partial)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	logger.Debugf("Imported nfo %s for scene %s", nfoPath, s.Path)
+	return true, nil
+}
+
+func (j *importNFOJob) scenePartial(ctx context.Context, s *models.Scene, nfo *scene.NFO) (models.ScenePartial, error) {
+	overwrite := j.input.Overwrite
+	ret := models.NewScenePartial()
+
+	if v := nfo.GetTitle(); v != "" && (overwrite || s.Title == "") {
+		ret.Title = models.NewOptionalString(v)
+	}
+	if v := nfo.GetDetails(); v != "" && (overwrite || s.Details == "") {
+		ret.Details = models.NewOptionalString(v)
+	}
+	if v := nfo.GetDirector(); v != "" && (overwrite || s.Director == "") {
+		ret.Director = models.NewOptionalString(v)
+	}
+	if v := nfo.GetDate(); v != nil && (overwrite || s.Date == nil) {
+		ret.Date = models.NewOptionalDate(*v)
+	}
+
+	if v := nfo.GetStudio(); v != "" && (overwrite || s.StudioID == nil) {
+		studioID, err := j.getStudioID(ctx, v)
+		if err != nil {
+			return ret, err
+		}
+		ret.StudioID = models.NewOptionalInt(studioID)
+	}
+
+	var performerIDs []int
+	for _, name := range nfo.GetPerformerNames() {
+		id, err := j.getPerformerID(ctx, name)
+		if err != nil {
+			return ret, err
+		}
+		performerIDs = append(performerIDs, id)
+	}
+	if len(performerIDs) > 0 {
+		ret.PerformerIDs = &models.UpdateIDs{
+			IDs:  performerIDs,
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+	}
+
+	var tagIDs []int
+	for _, name := range nfo.GetTagNames() {
+		id, err := j.getTagID(ctx, name)
+		if err != nil {
+			return ret, err
+		}
+		tagIDs = append(tagIDs, id)
+	}
+	if len(tagIDs) > 0 {
+		ret.TagIDs = &models.UpdateIDs{
+			IDs:  tagIDs,
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+	}
+
+	return ret, nil
+}
+
+// getStudioID returns the ID of the studio with the given name, creating it
+// if it does not exist.
+func (j *importNFOJob) getStudioID(ctx context.Context, name string) (int, error) {
+	qb := j.repository.Studio
+	existing, err := qb.FindByName(ctx, name, true)
+	if err != nil {
+		return 0, fmt.Errorf("finding studio %q: %w", name, err)
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+
+	newStudio := models.NewStudio()
+	newStudio.Name = name
+	if err := qb.Create(ctx, &newStudio); err != nil {
+		return 0, fmt.Errorf("creating studio %q: %w", name, err)
+	}
+
+	return newStudio.ID, nil
+}
+
+// getPerformerID returns the ID of the performer with the given name,
+// creating it if it does not exist.
+func (j *importNFOJob) getPerformerID(ctx context.Context, name string) (int, error) {
+	qb := j.repository.Performer
+	existing, err := qb.FindByNames(ctx, []string{name}, true)
+	if err != nil {
+		return 0, fmt.Errorf("finding performer %q: %w", name, err)
+	}
+	if len(existing) > 0 {
+		return existing[0].ID, nil
+	}
+
+	newPerformer := models.NewPerformer()
+	newPerformer.Name = name
+	if err := qb.Create(ctx, &newPerformer); err != nil {
+		return 0, fmt.Errorf("creating performer %q: %w", name, err)
+	}
+
+	return newPerformer.ID, nil
+}
+
+// getTagID returns the ID of the tag with the given name, creating it if it
+// does not exist.
+func (j *importNFOJob) getTagID(ctx context.Context, name string) (int, error) {
+	qb := j.repository.Tag
+	existing, err := qb.FindByName(ctx, name, true)
+	if err != nil {
+		return 0, fmt.Errorf("finding tag %q: %w", name, err)
+	}
+	if existing != nil {
+		return existing.ID, nil
+	}
+
+	newTag := models.NewTag()
+	newTag.Name = name
+	if err := qb.Create(ctx, &newTag); err != nil {
+		return 0, fmt.Errorf("creating tag %q: %w", name, err)
+	}
+
+	return newTag.ID, nil
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestExportZip(t *testing.T, path string, names ...string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, name := range names {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnzipExport(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "export")
+
+	zipPath := filepath.Join(tmp, "valid.zip")
+	writeTestExportZip(t, zipPath, "scenes/scene.json")
+
+	assert.Nil(t, unzipExport(zipPath, dir))
+	assert.FileExists(t, filepath.Join(dir, "scenes", "scene.json"))
+
+	// entries escaping the directory are rejected
+	zipPath = filepath.Join(tmp, "escape.zip")
+	writeTestExportZip(t, zipPath, "../escaped.json")
+
+	assert.NotNil(t, unzipExport(zipPath, dir))
+	assert.NoFileExists(t, filepath.Join(tmp, "escaped.json"))
+}
@@ -0,0 +1,299 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// maxOrganizeSuffix is the highest number appended to a file name to avoid
+// a collision before the file is skipped.
+const maxOrganizeSuffix = 1000
+
+type OrganizeCollisionEnum string
+
+const (
+	// OrganizeCollisionEnumSkip leaves files in place if their new path
+	// already exists
+	OrganizeCollisionEnumSkip OrganizeCollisionEnum = "SKIP"
+	// OrganizeCollisionEnumSuffix appends a number to the name of files
+	// whose new path already exists
+	OrganizeCollisionEnumSuffix OrganizeCollisionEnum = "SUFFIX"
+)
+
+var AllOrganizeCollisionEnum = []OrganizeCollisionEnum{
+	OrganizeCollisionEnumSkip,
+	OrganizeCollisionEnumSuffix,
+}
+
+func (e OrganizeCollisionEnum) IsValid() bool {
+	switch e {
+	case OrganizeCollisionEnumSkip, OrganizeCollisionEnumSuffix:
+		return true
+	}
+	return false
+}
+
+func (e OrganizeCollisionEnum) String() string {
+	return string(e)
+}
+
+func (e *OrganizeCollisionEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrganizeCollisionEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrganizeCollisionEnum", str)
+	}
+	return nil
+}
+
+func (e OrganizeCollisionEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrganizeScenesInput struct {
+	// IDs of scenes to organize. If empty, scenes in paths are organized
+	IDs []string `json:"ids"`
+	// Paths of scenes to organize, null for all scenes
+	Paths []string `json:"paths"`
+	// Template for the folder of each scene, relative to its library path
+	Template string `json:"template"`
+	// How to handle files whose new path already exists. Defaults to SKIP
+	Collision *OrganizeCollisionEnum `json:"collision"`
+	// Log the new paths without moving any files
+	DryRun bool `json:"dryRun"`
+}
+
+// organizeScenesJob moves the files of scenes into folders formatted from
+// their metadata, keeping their names.
+type organizeScenesJob struct {
+	repository models.Repository
+	config     *config.Instance
+	input      OrganizeScenesInput
+
+	// planned contains the new paths of files moved by the job, so that
+	// collisions between files are detected in a dry run.
+	planned map[string]bool
+}
+
+func (j *organizeScenesJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+	j.planned = make(map[string]bool)
+
+	sceneIDs, err := findSceneIDs(ctx, j.repository, j.input.IDs, j.input.Paths)
+	if err != nil {
+		if !job.IsCancelled(ctx) {
+			logger.Errorf("error finding scenes to organize: %v", err)
+		}
+		return
+	}
+
+	progress.SetTotal(len(sceneIDs))
+
+	if j.input.DryRun {
+		logger.Info("Organizing scene files (dry run)...")
+	} else {
+		logger.Info("Organizing scene files...")
+	}
+
+	moved := 0
+	for _, id := range sceneIDs {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping organizing due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Organizing scene %d", id), func() {
+			n, err := j.organizeScene(ctx, id)
+			if err != nil {
+				logger.Errorf("error organizing scene %d: %v", id, err)
+			}
+			moved += n
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Moved %d scene files after %s", moved, time.Since(begin).String())
+}
+
+func (j *organizeScenesJob) collision() OrganizeCollisionEnum {
+	if j.input.Collision != nil {
+		return *j.input.Collision
+	}
+	return OrganizeCollisionEnumSkip
+}
+
+// organizeScene moves the files of the scene, along with their funscript
+// and caption files, into its formatted folder.
+// Returns the number of files moved, or that would be moved in a dry run.
+// The files of the scene are moved in a single transaction, so none are
+// moved if an error occurs.
+func (j *organizeScenesJob) organizeScene(ctx context.Context, id int) (int, error) {
+	r := j.repository
+	var moves map[string]string
+
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		moves = make(map[string]string)
+
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene not found")
+		}
+
+		if err := s.LoadFiles(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		data, err := scenePathTemplateData(ctx, r, s)
+		if err != nil {
+			return err
+		}
+
+		rel, err := scene.FormatPathTemplate(j.input.Template, data)
+		if err != nil {
+			return err
+		}
+
+		var mover *file.Mover
+		for _, f := range s.Files.List() {
+			stash := movableFileStash(j.config, f.Base())
+			if stash == nil {
+				continue
+			}
+
+			dir := filepath.Join(stash.Path, rel)
+			if dir == filepath.Dir(f.Path) {
+				continue
+			}
+
+			newPath, err := j.availablePath(ctx, filepath.Join(dir, f.Basename))
+			if err != nil {
+				return err
+			}
+			if newPath == "" {
+				continue
+			}
+
+			moves[newPath] = f.Path
+
+			if j.input.DryRun {
+				logger.Infof("[dry run] would move %s to %s", f.Path, newPath)
+				continue
+			}
+
+			if mover == nil {
+				mover = file.NewMover(r.File, r.Folder)
+				mover.RegisterHooks(ctx)
+			}
+
+			if err := mover.CreateFolderHierarchy(dir); err != nil {
+				return fmt.Errorf("creating folder hierarchy %s in filesystem: %w", dir, err)
+			}
+
+			folder, err := file.GetOrCreateFolderHierarchy(ctx, r.Folder, dir)
+			if err != nil {
+				return fmt.Errorf("getting or creating folder hierarchy: %w", err)
+			}
+
+			oldPath := f.Path
+			if err := mover.Move(ctx, f, folder, filepath.Base(newPath)); err != nil {
+				return err
+			}
+
+			if err := moveVideoSidecars(ctx, r.File, mover, f, oldPath, newPath); err != nil {
+				return err
+			}
+
+			logger.Infof("Moved %s to %s", oldPath, newPath)
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	for newPath := range moves {
+		j.planned[newPath] = true
+	}
+
+	return len(moves), nil
+}
+
+// availablePath returns path, or a path with a number appended to the name
+// if path already exists and the collision behaviour is SUFFIX. Returns an
+// empty string if the file should not be moved.
+func (j *organizeScenesJob) availablePath(ctx context.Context, path string) (string, error) {
+	taken, err := j.pathTaken(ctx, path)
+	if err != nil || !taken {
+		return path, err
+	}
+
+	if j.collision() == OrganizeCollisionEnumSkip {
+		logger.Warnf("not moving to %s: file already exists", path)
+		return "", nil
+	}
+
+	for n := 1; n <= maxOrganizeSuffix; n++ {
+		p := suffixedPath(path, n)
+		taken, err := j.pathTaken(ctx, p)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return p, nil
+		}
+	}
+
+	logger.Warnf("not moving to %s: no available file name", path)
+	return "", nil
+}
+
+// pathTaken returns true if path exists in the filesystem or the database,
+// or is the new path of a file already moved by the job.
+func (j *organizeScenesJob) pathTaken(ctx context.Context, path string) (bool, error) {
+	if j.planned[path] {
+		return true, nil
+	}
+
+	if _, err := os.Lstat(path); err == nil {
+		return true, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+
+	f, err := j.repository.File.FindByPath(ctx, path)
+	if err != nil {
+		return false, err
+	}
+
+	return f != nil, nil
+}
+
+// suffixedPath returns path with " (n)" appended to the name, before the
+// extension.
+func suffixedPath(path string, n int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(path, ext), n, ext)
+}
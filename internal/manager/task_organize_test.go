@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuffixedPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("dir", "file (1).mp4"), suffixedPath(filepath.Join("dir", "file.mp4"), 1))
+	assert.Equal(t, filepath.Join("dir", "file (12)"), suffixedPath(filepath.Join("dir", "file"), 12))
+}
+
+func TestOrganizeScenesJob_AvailablePath(t *testing.T) {
+	dir := t.TempDir()
+	onDisk := filepath.Join(dir, "disk.mp4")
+	if err := os.WriteFile(onDisk, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(suffixedPath(onDisk, 1), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inDB := filepath.Join(dir, "db.mp4")
+	planned := filepath.Join(dir, "planned.mp4")
+	free := filepath.Join(dir, "free.mp4")
+
+	ctx := context.Background()
+	db := mocks.NewDatabase()
+	db.File.On("FindByPath", ctx, inDB).Return(&models.VideoFile{BaseFile: &models.BaseFile{ID: 1, Path: inDB}}, nil)
+	db.File.On("FindByPath", ctx, mock.Anything).Return(nil, nil)
+
+	suffix := OrganizeCollisionEnumSuffix
+	tests := []struct {
+		name      string
+		collision *OrganizeCollisionEnum
+		path      string
+		want      string
+	}{
+		{"free", nil, free, free},
+		{"skip on disk", nil, onDisk, ""},
+		{"skip in database", nil, inDB, ""},
+		{"skip planned", nil, planned, ""},
+		{"suffix on disk", &suffix, onDisk, suffixedPath(onDisk, 2)},
+		{"suffix in database", &suffix, inDB, suffixedPath(inDB, 1)},
+		{"suffix planned", &suffix, planned, suffixedPath(planned, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &organizeScenesJob{
+				repository: db.Repository(),
+				input:      OrganizeScenesInput{Collision: tt.collision},
+				planned:    map[string]bool{planned: true},
+			}
+
+			got, err := j.availablePath(ctx, tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
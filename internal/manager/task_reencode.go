@@ -0,0 +1,315 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+const (
+	// reencodeStagingSuffix is appended to the path of a file to name its
+	// re-encoded copy until it replaces the file.
+	reencodeStagingSuffix = ".reencode"
+	// reencodeOriginalSuffix is appended to the path of a file to name the
+	// original file kept after it is re-encoded.
+	reencodeOriginalSuffix = ".orig"
+)
+
+type ReencodeScenesInput struct {
+	// Filter of scenes to re-encode, null for all scenes
+	SceneFilter *models.SceneFilterType `json:"sceneFilter"`
+	// Codec to re-encode with
+	VideoCodec models.ClipVideoCodec `json:"videoCodec"`
+	// Maximum height of re-encoded files. Null for no limit
+	MaxHeight *int `json:"maxHeight"`
+	// Deinterlace interlaced video
+	Deinterlace bool `json:"deinterlace"`
+	// Keep the original files next to the re-encoded files. Defaults to true
+	KeepOriginal *bool `json:"keepOriginal"`
+	// Log the files that would be re-encoded without changing anything
+	DryRun bool `json:"dryRun"`
+}
+
+// options returns the re-encoding options for the probed source file. Only
+// interlaced video is deinterlaced.
+func (i ReencodeScenesInput) options(source *ffmpeg.VideoFile) generate.ReencodeOptions {
+	ret := generate.ReencodeOptions{
+		VideoCodec:  i.VideoCodec,
+		Deinterlace: i.Deinterlace && source.Interlaced(),
+		Source:      source,
+	}
+	if i.MaxHeight != nil {
+		ret.MaxHeight = *i.MaxHeight
+	}
+	return ret
+}
+
+func (i ReencodeScenesInput) keepOriginal() bool {
+	return i.KeepOriginal == nil || *i.KeepOriginal
+}
+
+// reencodeScenesJob re-encodes the primary files of scenes in place. Files
+// keep their IDs, so the scenes they belong to are unchanged.
+type reencodeScenesJob struct {
+	repository models.Repository
+	config     *config.Instance
+	generator  *generate.Generator
+	ffprobe    *ffmpeg.FFProbe
+	scanFile   func(ctx context.Context, path string) (*ScanFileResult, error)
+	input      ReencodeScenesInput
+}
+
+func (j *reencodeScenesJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+
+	sceneIDs, err := j.findSceneIDs(ctx)
+	if err != nil {
+		if !job.IsCancelled(ctx) {
+			logger.Errorf("error finding scenes to re-encode: %v", err)
+		}
+		return
+	}
+
+	progress.SetTotal(len(sceneIDs))
+
+	if j.input.DryRun {
+		logger.Info("Re-encoding scene files (dry run)...")
+	} else {
+		logger.Info("Re-encoding scene files...")
+	}
+
+	reencoded := 0
+	for _, id := range sceneIDs {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping re-encoding due to user request")
+			return
+		}
+
+		// wait until quiet hours are over
+		if err := job.WaitIfPaused(ctx); err != nil {
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Re-encoding scene %d", id), func() {
+			ok, err := j.reencodeScene(ctx, id)
+			if err != nil {
+				logger.Errorf("error re-encoding scene %d: %v", id, err)
+			} else if ok {
+				reencoded++
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Re-encoded %d scene files after %s", reencoded, time.Since(begin).String())
+}
+
+func (j *reencodeScenesJob) findSceneIDs(ctx context.Context) ([]int, error) {
+	r := j.repository
+
+	const batchSize = 1000
+	findFilter := models.BatchFindFilter(batchSize)
+
+	var ret []int
+	more := true
+	for more {
+		var scenes []*models.Scene
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			var err error
+			scenes, err = scene.Query(ctx, r.Scene, j.input.SceneFilter, findFilter)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, s := range scenes {
+			ret = append(ret, s.ID)
+		}
+
+		if len(scenes) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
+	return ret, nil
+}
+
+// reencodeScene re-encodes the primary file of the scene, replacing it on
+// disk and rescanning it. Returns true if the file was re-encoded, or would
+// be re-encoded in a dry run.
+func (j *reencodeScenesJob) reencodeScene(ctx context.Context, id int) (bool, error) {
+	r := j.repository
+
+	var f *models.VideoFile
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene not found")
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		f = s.Files.Primary()
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	if f == nil || !j.canReencode(f) {
+		return false, nil
+	}
+
+	source, err := j.ffprobe.NewVideoFile(f.Path)
+	if err != nil {
+		return false, fmt.Errorf("probing %s: %w", f.Path, err)
+	}
+
+	options := j.input.options(source)
+	if !reencodeRequired(f, options) {
+		logger.Debugf("not re-encoding %s: file already matches the target profile", f.Path)
+		return false, nil
+	}
+
+	oldPath := f.Path
+	newPath := reencodePath(oldPath, options.VideoCodec)
+	stagingPath := oldPath + reencodeStagingSuffix
+	originalPath := oldPath + reencodeOriginalSuffix
+
+	if newPath != oldPath {
+		if exists, _ := fsutil.FileExists(newPath); exists {
+			return false, fmt.Errorf("%s already exists", newPath)
+		}
+	}
+	if exists, _ := fsutil.FileExists(originalPath); exists {
+		return false, fmt.Errorf("%s already exists", originalPath)
+	}
+
+	if j.input.DryRun {
+		logger.Infof("[dry run] would re-encode %s to %s", oldPath, newPath)
+		return true, nil
+	}
+
+	logger.Infof("Re-encoding %s to %s", oldPath, newPath)
+
+	if err := j.generator.Reencode(ctx, oldPath, stagingPath, options); err != nil {
+		return false, err
+	}
+
+	keepOriginal := j.input.keepOriginal()
+	if err := replaceReencodedFile(oldPath, newPath, stagingPath, originalPath, keepOriginal); err != nil {
+		return false, err
+	}
+
+	if newPath != oldPath {
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			f.Basename = filepath.Base(newPath)
+			return r.File.Update(ctx, f)
+		}); err != nil {
+			return false, fmt.Errorf("renaming %s to %s: %w", oldPath, newPath, err)
+		}
+	}
+
+	// rescanning the file updates its fingerprints and video properties
+	if _, err := j.scanFile(ctx, newPath); err != nil {
+		return false, fmt.Errorf("scanning %s: %w", newPath, err)
+	}
+
+	if keepOriginal {
+		logger.Infof("Re-encoded %s to %s, keeping the original as %s", oldPath, newPath, originalPath)
+	} else {
+		logger.Infof("Re-encoded %s to %s", oldPath, newPath)
+	}
+
+	return true, nil
+}
+
+// canReencode returns false, logging the reason, if the file cannot be
+// replaced on disk.
+func (j *reencodeScenesJob) canReencode(f *models.VideoFile) bool {
+	if f.ZipFileID != nil {
+		logger.Warnf("not re-encoding %s: file is in a zip file", f.Path)
+		return false
+	}
+
+	if j.config.IsMountedPath(f.Path) {
+		logger.Warnf("not re-encoding %s: file is not on the local file system", f.Path)
+		return false
+	}
+
+	return true
+}
+
+// replaceReencodedFile moves the re-encoded file at stagingPath to newPath
+// in place of the file at oldPath. The original file is moved to
+// originalPath if keepOriginal is true, and is deleted otherwise.
+func replaceReencodedFile(oldPath, newPath, stagingPath, originalPath string, keepOriginal bool) error {
+	if err := os.Rename(oldPath, originalPath); err != nil {
+		_ = os.Remove(stagingPath)
+		return fmt.Errorf("moving original file: %w", err)
+	}
+
+	if err := os.Rename(stagingPath, newPath); err != nil {
+		// restore the original file
+		_ = os.Rename(originalPath, oldPath)
+		_ = os.Remove(stagingPath)
+		return fmt.Errorf("moving re-encoded file: %w", err)
+	}
+
+	if !keepOriginal {
+		if err := os.Remove(originalPath); err != nil {
+			logger.Warnf("error deleting original file %s: %v", originalPath, err)
+		}
+	}
+
+	return nil
+}
+
+// reencodeVideoCodecs are the codec names reported by ffprobe for each
+// re-encoding codec.
+var reencodeVideoCodecs = map[models.ClipVideoCodec]string{
+	models.ClipVideoCodecH264: "h264",
+	models.ClipVideoCodecHevc: "hevc",
+	models.ClipVideoCodecVp9:  "vp9",
+	models.ClipVideoCodecAv1:  "av1",
+}
+
+// reencodeRequired returns false if the file already matches the profile
+// given by options. Deinterlace is only set in options for interlaced
+// files, which are re-encoded regardless of their codec.
+func reencodeRequired(f *models.VideoFile, options generate.ReencodeOptions) bool {
+	if options.Deinterlace {
+		return true
+	}
+
+	if options.MaxHeight > 0 && f.Height > options.MaxHeight {
+		return true
+	}
+
+	return !strings.EqualFold(f.VideoCodec, reencodeVideoCodecs[options.VideoCodec])
+}
+
+// reencodePath returns the path of the file at path after re-encoding it
+// with codec.
+func reencodePath(path string, codec models.ClipVideoCodec) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + generate.ClipExtension(path, &codec)
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reencodePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		codec models.ClipVideoCodec
+		want  string
+	}{
+		{"same extension", "/stash/videos/scene.mp4", models.ClipVideoCodecHevc, "/stash/videos/scene.mp4"},
+		{"mpeg2", "/stash/videos/scene.name.mpg", models.ClipVideoCodecH264, "/stash/videos/scene.name.mp4"},
+		{"vp9", "/stash/videos/scene.mkv", models.ClipVideoCodecVp9, "/stash/videos/scene.webm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reencodePath(tt.path, tt.codec); got != tt.want {
+				t.Errorf("reencodePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reencodeRequired(t *testing.T) {
+	hevc1080 := &models.VideoFile{VideoCodec: "hevc", Height: 1080}
+	hevc2160 := &models.VideoFile{VideoCodec: "hevc", Height: 2160}
+	mpeg2 := &models.VideoFile{VideoCodec: "mpeg2video", Height: 576}
+
+	tests := []struct {
+		name    string
+		f       *models.VideoFile
+		options generate.ReencodeOptions
+		want    bool
+	}{
+		{"same codec", hevc1080, generate.ReencodeOptions{VideoCodec: models.ClipVideoCodecHevc}, false},
+		{"different codec", mpeg2, generate.ReencodeOptions{VideoCodec: models.ClipVideoCodecH264}, true},
+		{"within max height", hevc1080, generate.ReencodeOptions{VideoCodec: models.ClipVideoCodecHevc, MaxHeight: 1080}, false},
+		{"above max height", hevc2160, generate.ReencodeOptions{VideoCodec: models.ClipVideoCodecHevc, MaxHeight: 1080}, true},
+		{"deinterlace", hevc1080, generate.ReencodeOptions{VideoCodec: models.ClipVideoCodecHevc, Deinterlace: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reencodeRequired(tt.f, tt.options); got != tt.want {
+				t.Errorf("reencodeRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReencodeScenesInput_options(t *testing.T) {
+	progressive := &ffmpeg.VideoFile{VideoStream: &ffmpeg.FFProbeStream{FieldOrder: "progressive"}}
+	interlaced := &ffmpeg.VideoFile{VideoStream: &ffmpeg.FFProbeStream{FieldOrder: "tt"}}
+	h264 := &models.VideoFile{VideoCodec: "h264", Height: 1080}
+
+	input := ReencodeScenesInput{VideoCodec: models.ClipVideoCodecH264, Deinterlace: true}
+
+	// progressive files are not deinterlaced, so are not re-encoded again
+	assert.False(t, input.options(progressive).Deinterlace)
+	assert.False(t, reencodeRequired(h264, input.options(progressive)))
+
+	assert.True(t, input.options(interlaced).Deinterlace)
+	assert.True(t, reencodeRequired(h264, input.options(interlaced)))
+
+	assert.True(t, input.keepOriginal())
+
+	keepOriginal := false
+	input.KeepOriginal = &keepOriginal
+	assert.False(t, input.keepOriginal())
+}
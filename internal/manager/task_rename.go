@@ -0,0 +1,303 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+type RenameScenesInput struct {
+	// IDs of scenes to rename. If empty, scenes in paths are renamed
+	IDs []string `json:"ids"`
+	// Paths of scenes to rename, null for all scenes
+	Paths []string `json:"paths"`
+	// Template for the path of each scene, relative to its library path
+	Template string `json:"template"`
+	// Log the new paths without moving any files
+	DryRun bool `json:"dryRun"`
+}
+
+// renameScenesJob moves the primary files of scenes to paths formatted from
+// their metadata.
+type renameScenesJob struct {
+	repository models.Repository
+	config     *config.Instance
+	input      RenameScenesInput
+}
+
+func (j *renameScenesJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+
+	sceneIDs, err := findSceneIDs(ctx, j.repository, j.input.IDs, j.input.Paths)
+	if err != nil {
+		if !job.IsCancelled(ctx) {
+			logger.Errorf("error finding scenes to rename: %v", err)
+		}
+		return
+	}
+
+	progress.SetTotal(len(sceneIDs))
+
+	if j.input.DryRun {
+		logger.Info("Renaming scene files (dry run)...")
+	} else {
+		logger.Info("Renaming scene files...")
+	}
+
+	renamed := 0
+	for _, id := range sceneIDs {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping renaming due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Renaming scene %d", id), func() {
+			ok, err := j.renameScene(ctx, id)
+			if err != nil {
+				logger.Errorf("error renaming scene %d: %v", id, err)
+			} else if ok {
+				renamed++
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Renamed %d scene files after %s", renamed, time.Since(begin).String())
+}
+
+// findSceneIDs returns the scenes with the given IDs, or the scenes with files
+// in paths if ids is empty. All scenes are returned if both are empty.
+func findSceneIDs(ctx context.Context, r models.Repository, ids []string, paths []string) ([]int, error) {
+	if len(ids) > 0 {
+		ret := make([]int, len(ids))
+		for i, v := range ids {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("converting id %q: %w", v, err)
+			}
+			ret[i] = id
+		}
+		return ret, nil
+	}
+
+	sceneFilter := scene.FilterFromPaths(paths)
+
+	const batchSize = 1000
+	findFilter := models.BatchFindFilter(batchSize)
+
+	var ret []int
+	more := true
+	for more {
+		var scenes []*models.Scene
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			var err error
+			scenes, err = scene.Query(ctx, r.Scene, sceneFilter, findFilter)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+
+		for _, s := range scenes {
+			ret = append(ret, s.ID)
+		}
+
+		if len(scenes) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
+	return ret, nil
+}
+
+// renameScene moves the primary file of the scene to its formatted path,
+// along with its funscript and caption files. Returns true if the file was
+// moved, or would be moved in a dry run.
+func (j *renameScenesJob) renameScene(ctx context.Context, id int) (bool, error) {
+	r := j.repository
+	moved := false
+
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene not found")
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		f := s.Files.Primary()
+		if f == nil {
+			return nil
+		}
+
+		newPath, err := j.newPath(ctx, s, f)
+		if err != nil {
+			return err
+		}
+
+		oldPath := f.Path
+		if newPath == "" || newPath == oldPath {
+			return nil
+		}
+
+		moved = true
+
+		if j.input.DryRun {
+			logger.Infof("[dry run] would move %s to %s", oldPath, newPath)
+			return nil
+		}
+
+		mover := file.NewMover(r.File, r.Folder)
+		mover.RegisterHooks(ctx)
+
+		dir := filepath.Dir(newPath)
+		if err := mover.CreateFolderHierarchy(dir); err != nil {
+			return fmt.Errorf("creating folder hierarchy %s in filesystem: %w", dir, err)
+		}
+
+		folder, err := file.GetOrCreateFolderHierarchy(ctx, r.Folder, dir)
+		if err != nil {
+			return fmt.Errorf("getting or creating folder hierarchy: %w", err)
+		}
+
+		if err := mover.Move(ctx, f, folder, filepath.Base(newPath)); err != nil {
+			return err
+		}
+
+		if err := moveVideoSidecars(ctx, r.File, mover, f, oldPath, newPath); err != nil {
+			return err
+		}
+
+		logger.Infof("Moved %s to %s", oldPath, newPath)
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return moved, nil
+}
+
+// moveVideoSidecars moves the funscript and caption files of the video file
+// f, which has been moved from oldPath to newPath, so that they keep
+// matching the name of the video file. The files are moved using mover, so
+// that they are moved back if the transaction is rolled back.
+func moveVideoSidecars(ctx context.Context, fileStore models.FileReaderWriter, mover *file.Mover, f *models.VideoFile, oldPath, newPath string) error {
+	if err := mover.MoveSidecar(video.GetFunscriptPath(oldPath), video.GetFunscriptPath(newPath)); err != nil {
+		return err
+	}
+
+	captions, err := fileStore.GetCaptions(ctx, f.ID)
+	if err != nil {
+		return fmt.Errorf("getting captions for %s: %w", newPath, err)
+	}
+	if len(captions) == 0 {
+		return nil
+	}
+
+	for _, c := range captions {
+		captionPath := video.GetCaptionPath(newPath, c.LanguageCode, c.CaptionType)
+		if err := mover.MoveSidecar(c.Path(oldPath), captionPath); err != nil {
+			return err
+		}
+		c.Filename = filepath.Base(captionPath)
+	}
+
+	if err := fileStore.UpdateCaptions(ctx, f.ID, captions); err != nil {
+		return fmt.Errorf("updating captions for %s: %w", newPath, err)
+	}
+
+	return nil
+}
+
+// newPath returns the formatted path of the scene file. Returns an empty
+// string if the file cannot be moved.
+func (j *renameScenesJob) newPath(ctx context.Context, s *models.Scene, f *models.VideoFile) (string, error) {
+	stash := movableFileStash(j.config, f.Base())
+	if stash == nil {
+		return "", nil
+	}
+
+	data, err := scenePathTemplateData(ctx, j.repository, s)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := scene.FormatPathTemplate(j.input.Template, data)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(stash.Path, rel) + filepath.Ext(f.Basename), nil
+}
+
+// movableFileStash returns the library path containing the file, or nil if
+// the file cannot be moved.
+func movableFileStash(c *config.Instance, f *models.BaseFile) *config.StashConfig {
+	if f.ZipFileID != nil {
+		logger.Warnf("not moving %s: file is in a zip file", f.Path)
+		return nil
+	}
+
+	if c.IsMountedPath(f.Path) {
+		logger.Warnf("not moving %s: file is not on the local file system", f.Path)
+		return nil
+	}
+
+	stash := c.GetStashPaths().GetStashFromPath(f.Path)
+	if stash == nil {
+		logger.Warnf("not moving %s: file is not in a library path", f.Path)
+		return nil
+	}
+
+	return stash
+}
+
+func scenePathTemplateData(ctx context.Context, r models.Repository, s *models.Scene) (scene.PathTemplateData, error) {
+	ret := scene.PathTemplateData{
+		Scene: s,
+	}
+
+	if s.StudioID != nil {
+		studio, err := r.Studio.Find(ctx, *s.StudioID)
+		if err != nil {
+			return ret, err
+		}
+		if studio != nil {
+			ret.Studio = studio.Name
+		}
+	}
+
+	if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+		return ret, err
+	}
+
+	performers, err := r.Performer.FindMany(ctx, s.PerformerIDs.List())
+	if err != nil {
+		return ret, err
+	}
+
+	for _, p := range performers {
+		ret.Performers = append(ret.Performers, p.Name)
+	}
+	sort.Strings(ret.Performers)
+
+	return ret, nil
+}
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveVideoSidecars(t *testing.T) {
+	oldDir := t.TempDir()
+	newDir := t.TempDir()
+
+	oldPath := filepath.Join(oldDir, "video.mp4")
+	newPath := filepath.Join(newDir, "renamed.mp4")
+
+	for _, name := range []string{"video.funscript", "video.en.srt", "video.vtt"} {
+		if err := os.WriteFile(filepath.Join(oldDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	db := mocks.NewDatabase()
+
+	const fileID = models.FileID(1)
+	f := &models.VideoFile{BaseFile: &models.BaseFile{ID: fileID, Path: newPath}}
+
+	db.File.On("GetCaptions", ctx, fileID).Return([]*models.VideoCaption{
+		{LanguageCode: "en", Filename: "video.en.srt", CaptionType: "srt"},
+		{LanguageCode: "00", Filename: "video.vtt", CaptionType: "vtt"},
+	}, nil)
+	db.File.On("UpdateCaptions", ctx, fileID, []*models.VideoCaption{
+		{LanguageCode: "en", Filename: "renamed.en.srt", CaptionType: "srt"},
+		{LanguageCode: "00", Filename: "renamed.vtt", CaptionType: "vtt"},
+	}).Return(nil).Once()
+
+	mover := file.NewMover(db.File, db.Folder)
+	if err := moveVideoSidecars(ctx, db.File, mover, f, oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"renamed.funscript", "renamed.en.srt", "renamed.vtt"} {
+		assert.FileExists(t, filepath.Join(newDir, name))
+	}
+
+	entries, err := os.ReadDir(oldDir)
+	if assert.NoError(t, err) {
+		assert.Len(t, entries, 0)
+	}
+
+	db.AssertExpectations(t)
+}
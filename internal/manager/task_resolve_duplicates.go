@@ -0,0 +1,271 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+type ResolveDuplicatesInput struct {
+	// Maximum phash distance between duplicates
+	Distance *int `json:"distance"`
+	// Maximum difference in duration between duplicates, in seconds. Null
+	// for any difference
+	DurationDiff *float64 `json:"durationDiff"`
+	// Rules to choose the scene to keep, in order of priority
+	Rules []scene.DuplicateRule `json:"rules"`
+	// Paths to prefer when using the PREFERRED_PATH rule, in order of
+	// preference
+	PreferredPaths []string `json:"preferredPaths"`
+	// Delete the files of the other scenes, rather than keeping them as
+	// additional files of the kept scene. Files are moved to the trash if
+	// configured
+	DeleteFiles bool `json:"deleteFiles"`
+	// Log the scenes that would be kept without changing anything
+	DryRun bool `json:"dryRun"`
+}
+
+// resolveDuplicatesJob merges each group of duplicate scenes into the scene
+// chosen by a duplicate policy.
+type resolveDuplicatesJob struct {
+	repository   models.Repository
+	sceneService SceneService
+	newDeleter   func() *file.Deleter
+	input        ResolveDuplicatesInput
+}
+
+func (j *resolveDuplicatesJob) policy() scene.DuplicatePolicy {
+	return scene.DuplicatePolicy{
+		Rules:          j.input.Rules,
+		PreferredPaths: j.input.PreferredPaths,
+	}
+}
+
+func (j *resolveDuplicatesJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+	r := j.repository
+
+	distance := 0
+	if j.input.Distance != nil {
+		distance = *j.input.Distance
+	}
+	durationDiff := -1.
+	if j.input.DurationDiff != nil {
+		durationDiff = *j.input.DurationDiff
+	}
+
+	var groups [][]*models.Scene
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		groups, err = r.Scene.FindDuplicates(ctx, distance, durationDiff)
+		if err != nil {
+			return err
+		}
+
+		// scenes that are already linked are kept separate intentionally
+		groups, err = scene.ExcludeRelated(ctx, r.SceneRelationship, groups)
+		return err
+	}); err != nil {
+		if !job.IsCancelled(ctx) {
+			logger.Errorf("error finding duplicate scenes: %v", err)
+		}
+		return
+	}
+
+	progress.SetTotal(len(groups))
+	logger.Infof("Resolving %d groups of duplicate scenes...", len(groups))
+
+	resolved := 0
+	for _, group := range groups {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Resolving %d duplicate scenes", len(group)), func() {
+			if err := j.resolveGroup(ctx, group); err != nil {
+				logger.Errorf("error resolving duplicate scenes: %v", err)
+				return
+			}
+			resolved++
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Resolved %d groups of duplicate scenes after %s", resolved, time.Since(begin).String())
+}
+
+func (j *resolveDuplicatesJob) resolveGroup(ctx context.Context, group []*models.Scene) error {
+	if len(group) < 2 {
+		return nil
+	}
+
+	r := j.repository
+
+	var deleter *file.Deleter
+	if j.input.DeleteFiles && !j.input.DryRun {
+		deleter = j.newDeleter()
+	}
+
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		if deleter != nil {
+			deleter.RegisterHooks(ctx)
+		}
+
+		// reload the scenes in case they were changed by an earlier group
+		ids := make([]int, len(group))
+		for i, s := range group {
+			ids[i] = s.ID
+		}
+
+		scenes, err := r.Scene.FindMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range scenes {
+			if s == nil {
+				return errors.New("scene no longer exists")
+			}
+
+			if err := s.LoadRelationships(ctx, r.Scene); err != nil {
+				return err
+			}
+		}
+
+		j.policy().Sort(scenes)
+		keep, others := scenes[0], scenes[1:]
+
+		for _, o := range others {
+			if j.input.DryRun {
+				logger.Infof("[dry run] would merge %s into %s", o.DisplayName(), keep.DisplayName())
+			} else {
+				logger.Infof("Merging %s into %s", o.DisplayName(), keep.DisplayName())
+			}
+		}
+
+		if j.input.DryRun {
+			return nil
+		}
+
+		otherIDs := make([]int, len(others))
+		var otherFiles []*models.VideoFile
+		for i, o := range others {
+			otherIDs[i] = o.ID
+			otherFiles = append(otherFiles, o.Files.List()...)
+		}
+
+		partial := mergeDuplicatePartial(keep, others)
+		if err := j.sceneService.Merge(ctx, otherIDs, keep.ID, partial); err != nil {
+			return err
+		}
+
+		if deleter == nil {
+			return nil
+		}
+
+		const deleteFile = true
+		for _, f := range otherFiles {
+			logger.Infof("Deleting duplicate file: %s", f.Path)
+			if err := file.Destroy(ctx, r.File, f, deleter, deleteFile); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// mergeDuplicatePartial returns the changes to keep to merge the metadata of
+// the other scenes into it. Relationships are added, and values that are not
+// set on keep are taken from the first of others that has them.
+func mergeDuplicatePartial(keep *models.Scene, others []*models.Scene) models.ScenePartial {
+	ret := models.NewScenePartial()
+
+	var (
+		urls         []string
+		galleryIDs   []int
+		tagIDs       []int
+		performerIDs []int
+		movies       []models.MoviesScenes
+		stashIDs     []models.StashID
+	)
+
+	organized := keep.Organized
+	oCounter := keep.OCounter
+	playCount := keep.PlayCount
+
+	for _, o := range others {
+		if keep.Title == "" && o.Title != "" && !ret.Title.Set {
+			ret.Title = models.NewOptionalString(o.Title)
+		}
+		if keep.Code == "" && o.Code != "" && !ret.Code.Set {
+			ret.Code = models.NewOptionalString(o.Code)
+		}
+		if keep.Details == "" && o.Details != "" && !ret.Details.Set {
+			ret.Details = models.NewOptionalString(o.Details)
+		}
+		if keep.Director == "" && o.Director != "" && !ret.Director.Set {
+			ret.Director = models.NewOptionalString(o.Director)
+		}
+		if keep.Date == nil && o.Date != nil && !ret.Date.Set {
+			ret.Date = models.NewOptionalDate(*o.Date)
+		}
+		if keep.Rating == nil && o.Rating != nil && !ret.Rating.Set {
+			ret.Rating = models.NewOptionalInt(*o.Rating)
+		}
+		if keep.StudioID == nil && o.StudioID != nil && !ret.StudioID.Set {
+			ret.StudioID = models.NewOptionalInt(*o.StudioID)
+		}
+
+		organized = organized || o.Organized
+		oCounter += o.OCounter
+		playCount += o.PlayCount
+
+		urls = append(urls, o.URLs.List()...)
+		galleryIDs = append(galleryIDs, o.GalleryIDs.List()...)
+		tagIDs = append(tagIDs, o.TagIDs.List()...)
+		performerIDs = append(performerIDs, o.PerformerIDs.List()...)
+		movies = append(movies, o.Movies.List()...)
+		stashIDs = append(stashIDs, o.StashIDs.List()...)
+	}
+
+	if organized != keep.Organized {
+		ret.Organized = models.NewOptionalBool(organized)
+	}
+	if oCounter != keep.OCounter {
+		ret.OCounter = models.NewOptionalInt(oCounter)
+	}
+	if playCount != keep.PlayCount {
+		ret.PlayCount = models.NewOptionalInt(playCount)
+	}
+
+	if len(urls) > 0 {
+		ret.URLs = &models.UpdateStrings{Values: urls, Mode: models.RelationshipUpdateModeAdd}
+	}
+	if len(galleryIDs) > 0 {
+		ret.GalleryIDs = &models.UpdateIDs{IDs: galleryIDs, Mode: models.RelationshipUpdateModeAdd}
+	}
+	if len(tagIDs) > 0 {
+		ret.TagIDs = &models.UpdateIDs{IDs: tagIDs, Mode: models.RelationshipUpdateModeAdd}
+	}
+	if len(performerIDs) > 0 {
+		ret.PerformerIDs = &models.UpdateIDs{IDs: performerIDs, Mode: models.RelationshipUpdateModeAdd}
+	}
+	if len(movies) > 0 {
+		ret.MovieIDs = &models.UpdateMovieIDs{Movies: movies, Mode: models.RelationshipUpdateModeAdd}
+	}
+	if len(stashIDs) > 0 {
+		ret.StashIDs = &models.UpdateStashIDs{StashIDs: stashIDs, Mode: models.RelationshipUpdateModeAdd}
+	}
+
+	return ret
+}
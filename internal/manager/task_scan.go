@@ -2,15 +2,16 @@ package manager
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/audio"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/fsutil"
@@ -33,11 +34,27 @@ type ScanJob struct {
 	scanner       scanner
 	input         ScanMetadataInput
 	subscriptions *subscriptionManager
+
+	// state is used to save the progress of the scan. May be nil.
+	state *scanStateStore
+	// resume is the saved state of the interrupted scan to resume. May be nil.
+	resume *scanState
+
+	relinks *relinkRecorder
+
+	// target limits the scan to the file at this path and the folders
+	// containing it. Scans all files in the paths if empty.
+	target string
 }
 
 func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 	input := j.input
 
+	if j.state != nil {
+		// the scan is not resumed if it is cancelled or completes
+		defer j.state.clear()
+	}
+
 	if job.IsCancelled(ctx) {
 		logger.Info("Stopping due to user request")
 		return
@@ -55,6 +72,28 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 
 	start := time.Now()
 
+	var resume *file.ScanPosition
+	if j.resume != nil {
+		resume = j.resume.resumePosition(paths)
+	}
+
+	var checkpoint func(file.ScanPosition)
+	if j.state != nil {
+		j.state.save(scanState{
+			Input:    input,
+			Paths:    paths,
+			Position: resume,
+		})
+
+		checkpoint = func(pos file.ScanPosition) {
+			j.state.save(scanState{
+				Input:    input,
+				Paths:    paths,
+				Position: &pos,
+			})
+		}
+	}
+
 	const taskQueueSize = 200000
 	taskQueue := job.NewTaskQueue(ctx, progress, taskQueueSize, c.GetParallelTasksWithAutoDetection())
 
@@ -63,12 +102,25 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 		minModTime = *j.input.Filter.MinModTime
 	}
 
-	j.scanner.Scan(ctx, getScanHandlers(j.input, taskQueue, progress), file.ScanOptions{
+	j.relinks = newRelinkRecorder()
+	handlers := append(getScanHandlers(j.input, taskQueue, progress), j.relinks)
+
+	scanFilter := newScanFilter(c, repo, mgr.FS, minModTime)
+	var filter file.PathFilter = scanFilter
+	if j.target != "" {
+		filter = &targetFilter{target: j.target, PathFilter: filter}
+	}
+
+	j.scanner.Scan(ctx, handlers, file.ScanOptions{
 		Paths:                  paths,
-		ScanFilters:            []file.PathFilter{newScanFilter(c, repo, minModTime)},
+		ScanFilters:            []file.PathFilter{filter},
 		ZipFileExtensions:      c.GetGalleryExtensions(),
 		ParallelTasks:          c.GetParallelTasksWithAutoDetection(),
+		WalkParallelTasks:      c.GetScanWalkParallelTasks(),
 		HandlerRequiredFilters: []file.Filter{newHandlerRequiredFilter(c, repo)},
+		NewestFirst:            input.ScanNewestFirst,
+		Resume:                 resume,
+		Checkpoint:             checkpoint,
 	}, progress)
 
 	taskQueue.Close()
@@ -78,6 +130,15 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 		return
 	}
 
+	progress.ExecuteTask("Associating captions", func() {
+		scanFilter.associateCaptions(ctx)
+	})
+
+	progress.ExecuteTask("Relinking moved files", func() {
+		j.relinkByPhash(ctx, start)
+	})
+	j.relinks.report()
+
 	elapsed := time.Since(start)
 	logger.Info(fmt.Sprintf("Scan finished (%s)", elapsed))
 
@@ -87,14 +148,18 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 type extensionConfig struct {
 	vidExt []string
 	imgExt []string
+	audExt []string
 	zipExt []string
+	docExt []string
 }
 
 func newExtensionConfig(c *config.Instance) extensionConfig {
 	return extensionConfig{
 		vidExt: c.GetVideoExtensions(),
 		imgExt: c.GetImageExtensions(),
+		audExt: c.GetAudioExtensions(),
 		zipExt: c.GetGalleryExtensions(),
+		docExt: c.GetDocumentExtensions(),
 	}
 }
 
@@ -110,6 +175,7 @@ type galleryFinder interface {
 type sceneFinder interface {
 	fileCounter
 	FindByPrimaryFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error)
+	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error)
 }
 
 // handlerRequiredFilter returns true if a File's handler needs to be executed despite the file not being updated.
@@ -118,6 +184,7 @@ type handlerRequiredFilter struct {
 	txnManager     txn.Manager
 	SceneFinder    sceneFinder
 	ImageFinder    fileCounter
+	AudioFinder    fileCounter
 	GalleryFinder  galleryFinder
 	CaptionUpdater video.CaptionUpdater
 
@@ -134,6 +201,7 @@ func newHandlerRequiredFilter(c *config.Instance, repo models.Repository) *handl
 		txnManager:               repo.TxnManager,
 		SceneFinder:              repo.Scene,
 		ImageFinder:              repo.Image,
+		AudioFinder:              repo.Audio,
 		GalleryFinder:            repo.Gallery,
 		CaptionUpdater:           repo.File,
 		FolderCache:              lru.New(processes * 2),
@@ -145,7 +213,9 @@ func (f *handlerRequiredFilter) Accept(ctx context.Context, ff models.File) bool
 	path := ff.Base().Path
 	isVideoFile := useAsVideo(path)
 	isImageFile := useAsImage(path)
+	isAudioFile := useAsAudio(path)
 	isZipFile := fsutil.MatchExtension(path, f.zipExt)
+	isDocumentFile := fsutil.MatchExtension(path, f.docExt)
 
 	var counter fileCounter
 
@@ -155,7 +225,9 @@ func (f *handlerRequiredFilter) Accept(ctx context.Context, ff models.File) bool
 		counter = f.SceneFinder
 	case isImageFile:
 		counter = f.ImageFinder
-	case isZipFile:
+	case isAudioFile:
+		counter = f.AudioFinder
+	case isZipFile, isDocumentFile:
 		counter = f.GalleryFinder
 	}
 
@@ -196,6 +268,19 @@ func (f *handlerRequiredFilter) Accept(ctx context.Context, ff models.File) bool
 	}
 
 	if isVideoFile {
+		// restore scenes that were quarantined while the file was missing
+		scenes, err := f.SceneFinder.FindByFileID(ctx, ff.Base().ID)
+		if err != nil {
+			// just ignore
+			return false
+		}
+
+		for _, s := range scenes {
+			if s.QuarantinedAt != nil {
+				return true
+			}
+		}
+
 		// TODO - check if the cover exists
 		// hash := scene.GetHash(ff, f.videoFileNamingAlgorithm)
 		// ssPath := instance.Paths.Scene.GetScreenshotPath(hash)
@@ -233,26 +318,53 @@ type scanFilter struct {
 	CaptionUpdater video.CaptionUpdater
 
 	stashPaths        config.StashConfigs
+	libraries         *libraryConfig
 	generatedPath     string
 	videoExcludeRegex []*regexp.Regexp
 	imageExcludeRegex []*regexp.Regexp
+	ignore            *file.IgnoreMatcher
 	minModTime        time.Time
+
+	// captionPaths are the caption files found by the scan, which are
+	// associated with their video files once the scan is complete.
+	captionMutex sync.Mutex
+	captionPaths []string
 }
 
-func newScanFilter(c *config.Instance, repo models.Repository, minModTime time.Time) *scanFilter {
+func newScanFilter(c *config.Instance, repo models.Repository, fs models.FS, minModTime time.Time) *scanFilter {
 	return &scanFilter{
 		extensionConfig:   newExtensionConfig(c),
 		txnManager:        repo.TxnManager,
 		FileFinder:        repo.File,
 		CaptionUpdater:    repo.File,
 		stashPaths:        c.GetStashPaths(),
+		libraries:         newLibraryConfig(c),
 		generatedPath:     c.GetGeneratedPath(),
 		videoExcludeRegex: generateRegexps(c.GetExcludes()),
 		imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+		ignore:            &file.IgnoreMatcher{FS: fs},
 		minModTime:        minModTime,
 	}
 }
 
+// associateCaptions associates the caption files found by the scan with
+// their video files. This is done after the scan so that captions are
+// associated with video files that are new to the scan.
+func (f *scanFilter) associateCaptions(ctx context.Context) {
+	f.captionMutex.Lock()
+	paths := f.captionPaths
+	f.captionPaths = nil
+	f.captionMutex.Unlock()
+
+	for _, p := range paths {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		video.AssociateCaptions(ctx, p, f.txnManager, f.FileFinder, f.CaptionUpdater)
+	}
+}
+
 func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo) bool {
 	if fsutil.IsPathInDir(f.generatedPath, path) {
 		logger.Warnf("Skipping %q as it overlaps with the generated folder", path)
@@ -266,18 +378,22 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 
 	isVideoFile := useAsVideo(path)
 	isImageFile := useAsImage(path)
+	isAudioFile := useAsAudio(path)
 	isZipFile := fsutil.MatchExtension(path, f.zipExt)
+	isDocumentFile := fsutil.MatchExtension(path, f.docExt)
 
 	// handle caption files
 	if fsutil.MatchExtension(path, video.CaptionExts) {
 		// we don't include caption files in the file scan, but we do need
 		// to handle them
-		video.AssociateCaptions(ctx, path, f.txnManager, f.FileFinder, f.CaptionUpdater)
+		f.captionMutex.Lock()
+		f.captionPaths = append(f.captionPaths, path)
+		f.captionMutex.Unlock()
 
 		return false
 	}
 
-	if !info.IsDir() && !isVideoFile && !isImageFile && !isZipFile {
+	if !info.IsDir() && !isVideoFile && !isImageFile && !isAudioFile && !isZipFile && !isDocumentFile {
 		logger.Debugf("Skipping %s as it does not match any known file extensions", path)
 		return false
 	}
@@ -295,18 +411,32 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	if !info.IsDir() && (s.ExcludesFile(path) || s.SkipsFile(info)) {
+		logger.Debugf("Skipping %s as it is excluded by the stash library filters", path)
+		return false
+	}
+
+	if f.ignore.Ignored(s.Path, path, info.IsDir()) {
+		logger.Debugf("Skipping %s as it is excluded by a %s file", path, file.IgnoreFilename)
+		return false
+	}
+
+	videoExcludeRegex := f.libraries.videoExcludes(s, f.videoExcludeRegex)
+	imageExcludeRegex := f.libraries.imageExcludes(s, f.imageExcludeRegex)
+
 	// shortcut: skip the directory entirely if it matches both exclusion patterns
 	// add a trailing separator so that it correctly matches against patterns like path/.*
 	pathExcludeTest := path + string(filepath.Separator)
-	if (matchFileRegex(pathExcludeTest, f.videoExcludeRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, f.imageExcludeRegex)) {
+	if (matchFileRegex(pathExcludeTest, videoExcludeRegex)) && (s.ExcludeImage || matchFileRegex(pathExcludeTest, imageExcludeRegex)) {
 		logger.Debugf("Skipping directory %s as it matches video and image exclusion patterns", path)
 		return false
 	}
 
-	if isVideoFile && (s.ExcludeVideo || matchFileRegex(path, f.videoExcludeRegex)) {
+	// audio files share the video exclusion patterns
+	if (isVideoFile || isAudioFile) && (s.ExcludeVideo || matchFileRegex(path, videoExcludeRegex)) {
 		logger.Debugf("Skipping %s as it matches video exclusion patterns", path)
 		return false
-	} else if (isImageFile || isZipFile) && (s.ExcludeImage || matchFileRegex(path, f.imageExcludeRegex)) {
+	} else if (isImageFile || isZipFile || isDocumentFile) && (s.ExcludeImage || matchFileRegex(path, imageExcludeRegex)) {
 		logger.Debugf("Skipping %s as it matches image exclusion patterns", path)
 		return false
 	}
@@ -330,6 +460,14 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 	c := mgr.Config
 	r := mgr.Repository
 	pluginCache := mgr.PluginCache
+	libraries := newLibraryConfig(c)
+
+	var sidecarReader *scene.SidecarReader
+	if formats := c.GetSidecarFormats(); len(formats) > 0 {
+		sidecarReader = &scene.SidecarReader{
+			Formats: formats,
+		}
+	}
 
 	return []file.Handler{
 		&file.FilteredHandler{
@@ -339,6 +477,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				GalleryFinder:  r.Gallery,
 				ScanGenerator: &imageGenerators{
 					input:              options,
+					libraries:          libraries,
 					taskQueue:          taskQueue,
 					progress:           progress,
 					paths:              mgr.Paths,
@@ -370,6 +509,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				PluginCache:    pluginCache,
 				ScanGenerator: &sceneGenerators{
 					input:               options,
+					libraries:           libraries,
 					taskQueue:           taskQueue,
 					progress:            progress,
 					paths:               mgr.Paths,
@@ -378,6 +518,14 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				},
 				FileNamingAlgorithm: c.GetVideoFileNamingAlgorithm(),
 				Paths:               mgr.Paths,
+				SidecarReader:       sidecarReader,
+				TagFinderCreator:    r.Tag,
+			},
+		},
+		&file.FilteredHandler{
+			Filter: file.FilterFunc(audioFileFilter),
+			Handler: &audio.ScanHandler{
+				CreatorUpdater: r.Audio,
 			},
 		},
 	}
@@ -385,6 +533,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 
 type imageGenerators struct {
 	input     ScanMetadataInput
+	libraries *libraryConfig
 	taskQueue *job.TaskQueue
 	progress  *job.Progress
 
@@ -396,8 +545,8 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 	const overwrite = false
 
 	progress := g.progress
-	t := g.input
 	path := f.Base().Path
+	t := g.libraries.scanOptions(path, g.input.ScanMetadataOptions)
 
 	if t.ScanGenerateThumbnails {
 		// this should be quick, so always generate sequentially
@@ -442,49 +591,20 @@ func (g *imageGenerators) generateThumbnail(ctx context.Context, i *models.Image
 		return nil
 	}
 
-	path := f.Base().Path
-
-	vf, ok := f.(models.VisualFile)
-	if !ok {
-		return fmt.Errorf("file %s is not a visual file", path)
+	if _, ok := f.(models.VisualFile); !ok {
+		return fmt.Errorf("file %s is not a visual file", f.Base().Path)
 	}
 
-	if vf.GetHeight() <= models.DefaultGthumbWidth && vf.GetWidth() <= models.DefaultGthumbWidth {
+	if !imageThumbnailRequired(f) {
 		return nil
 	}
 
-	logger.Debugf("Generating thumbnail for %s", path)
-
-	mgr := GetInstance()
-	c := mgr.Config
-
-	clipPreviewOptions := image.ClipPreviewOptions{
-		InputArgs:  c.GetTranscodeInputArgs(),
-		OutputArgs: c.GetTranscodeOutputArgs(),
-		Preset:     c.GetPreviewPreset().String(),
-	}
-
-	encoder := image.NewThumbnailEncoder(mgr.FFMPEG, mgr.FFProbe, clipPreviewOptions)
-	data, err := encoder.GetThumbnail(f, models.DefaultGthumbWidth)
-
-	if err != nil {
-		// don't log for animated images
-		if !errors.Is(err, image.ErrNotSupportedForThumbnail) {
-			return fmt.Errorf("getting thumbnail for image %s: %w", path, err)
-		}
-		return nil
-	}
-
-	err = fsutil.WriteFile(thumbPath, data)
-	if err != nil {
-		return fmt.Errorf("writing thumbnail for image %s: %w", path, err)
-	}
-
-	return nil
+	return generateImageThumbnail(f, thumbPath)
 }
 
 type sceneGenerators struct {
 	input     ScanMetadataInput
+	libraries *libraryConfig
 	taskQueue *job.TaskQueue
 	progress  *job.Progress
 
@@ -497,8 +617,8 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 	const overwrite = false
 
 	progress := g.progress
-	t := g.input
 	path := f.Path
+	t := g.libraries.scanOptions(path, g.input.ScanMetadataOptions)
 
 	mgr := GetInstance()
 
@@ -546,6 +666,18 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *mode
 		previewsFn := func(ctx context.Context) {
 			options := getGeneratePreviewOptions(GeneratePreviewOptionsInput{})
 
+			r := mgr.Repository
+			if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+				sceneOptions, err := r.Scene.GetPreviewOptions(ctx, s.ID)
+				if err != nil {
+					return err
+				}
+				options = applyScenePreviewOptions(options, sceneOptions)
+				return nil
+			}); err != nil {
+				logger.Errorf("error getting preview options for scene %d: %v", s.ID, err)
+			}
+
 			generator := &generate.Generator{
 				Encoder:      mgr.FFMPEG,
 				FFMpegConfig: mgr.Config,
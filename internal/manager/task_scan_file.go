@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ScanFileResult contains the objects associated with a scanned file.
+type ScanFileResult struct {
+	Scenes    []*models.Scene   `json:"scenes"`
+	Images    []*models.Image   `json:"images"`
+	Galleries []*models.Gallery `json:"galleries"`
+	Audios    []*models.Audio   `json:"audios"`
+}
+
+// targetFilter only accepts the target path, its caption files and the
+// folders containing it, if they are also accepted by the wrapped filter.
+type targetFilter struct {
+	file.PathFilter
+	target string
+}
+
+func (f *targetFilter) Accept(ctx context.Context, path string, info fs.FileInfo) bool {
+	isCaption := !info.IsDir() && video.IsCaptionFor(path, f.target)
+	if path != f.target && !isCaption && !(info.IsDir() && fsutil.IsPathInDir(path, f.target)) {
+		return false
+	}
+
+	return f.PathFilter.Accept(ctx, path, info)
+}
+
+// ScanFile scans the file at path, and returns the objects associated with
+// it. The scan is started immediately rather than queued behind other jobs,
+// and ScanFile returns when it is complete. Folders containing the file are
+// added to the library if necessary, but their other contents are not
+// scanned.
+func (s *Manager) ScanFile(ctx context.Context, path string) (*ScanFileResult, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	stash := s.Config.GetStashPaths().GetStashFromPath(path)
+	if stash == nil {
+		return nil, fmt.Errorf("%s is not in a library path", path)
+	}
+
+	info, err := s.FS.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	scanJob := &ScanJob{
+		scanner:       s.Scanner,
+		input:         ScanMetadataInput{Paths: []string{stash.Path}},
+		subscriptions: s.scanSubs,
+		target:        path,
+	}
+
+	done := make(chan struct{})
+	s.JobManager.Start(ctx, fmt.Sprintf("Scanning %s...", path), job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		defer close(done)
+		scanJob.Execute(ctx, progress)
+	}))
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	ret := &ScanFileResult{}
+	r := s.Repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		f, err := r.File.FindByPath(ctx, path)
+		if err != nil {
+			return err
+		}
+
+		if f == nil {
+			return fmt.Errorf("%s was not added to the library", path)
+		}
+
+		id := f.Base().ID
+		if ret.Scenes, err = r.Scene.FindByFileID(ctx, id); err != nil {
+			return err
+		}
+		if ret.Images, err = r.Image.FindByFileID(ctx, id); err != nil {
+			return err
+		}
+		if ret.Galleries, err = r.Gallery.FindByFileID(ctx, id); err != nil {
+			return err
+		}
+		if ret.Audios, err = r.Audio.FindByFileID(ctx, id); err != nil {
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
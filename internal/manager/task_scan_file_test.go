@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+type acceptAllFilter struct{}
+
+func (acceptAllFilter) Accept(ctx context.Context, path string, info fs.FileInfo) bool {
+	return true
+}
+
+func TestTargetFilter_Accept(t *testing.T) {
+	mapFS := fstest.MapFS{
+		"dir/file.mp4":     {},
+		"dir/other.mp4":    {},
+		"dir/file.en.srt":  {},
+		"dir/other.srt":    {},
+		"dir/sub/file.mp4": {},
+		"other/file.mp4":   {},
+	}
+
+	stat := func(name string) fs.FileInfo {
+		info, err := fs.Stat(mapFS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+
+	root := filepath.FromSlash("/stash")
+	target := filepath.Join(root, "dir", "file.mp4")
+
+	var filter file.PathFilter = &targetFilter{PathFilter: acceptAllFilter{}, target: target}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"root", ".", true},
+		{"parent", "dir", true},
+		{"target", "dir/file.mp4", true},
+		{"sibling", "dir/other.mp4", false},
+		{"caption", "dir/file.en.srt", true},
+		{"sibling caption", "dir/other.srt", false},
+		{"sibling folder", "dir/sub", false},
+		{"other folder", "other", false},
+		{"other file", "other/file.mp4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(root, filepath.FromSlash(tt.path))
+			assert.Equal(t, tt.want, filter.Accept(context.Background(), path, stat(tt.path)))
+		})
+	}
+}
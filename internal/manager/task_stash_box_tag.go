@@ -30,6 +30,8 @@ type StashBoxBatchTagTask struct {
 }
 
 func (t *StashBoxBatchTagTask) Start(ctx context.Context) {
+	ctx = models.WithEditSource(ctx, models.EditSourceScraper)
+
 	switch t.taskType {
 	case Performer:
 		t.stashBoxPerformerTag(ctx)
@@ -19,6 +19,9 @@ type GenerateTranscodeTask struct {
 	// is true, generate even if video is browser-supported
 	Force bool
 
+	// maxTranscodeSize overrides the configured maximum transcode size
+	maxTranscodeSize models.StreamingResolutionEnum
+
 	g *generate.Generator
 }
 
@@ -68,16 +71,23 @@ func (t *GenerateTranscodeTask) Start(ctc context.Context) {
 	}
 
 	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
-	transcodeSize := config.GetInstance().GetMaxTranscodeSize()
+	transcodeSize := t.maxTranscodeSize
+	if transcodeSize == "" {
+		transcodeSize = config.GetInstance().GetMaxTranscodeSize()
+	}
 
 	w, h := videoFile.TranscodeScale(transcodeSize.GetMaxResolution())
 
 	options := generate.TranscodeOptions{
 		Width:  w,
 		Height: h,
+		HDR:    ffmpeg.IsHDRTransfer(videoFile.ColorTransfer),
 	}
 
-	if videoCodec == ffmpeg.H264 { // for non supported h264 files stream copy the video part
+	// HDR video must be re-encoded to be tone mapped
+	toneMap := options.HDR && config.GetInstance().GetHDRToneMapping() && instance.FFMPEG.ToneMapFilter() != ""
+
+	if videoCodec == ffmpeg.H264 && !toneMap { // for non supported h264 files stream copy the video part
 		if audioCodec == ffmpeg.MissingUnsupported {
 			err = t.g.TranscodeCopyVideo(context.TODO(), videoFile.Path, sceneHash, options)
 		} else {
@@ -0,0 +1,387 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/models/paths"
+)
+
+// validateExportJob checks that the export at path - either a directory or
+// an export zip file - can be read and that its references are consistent.
+type validateExportJob struct {
+	path string
+}
+
+func (j *validateExportJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+
+	info, err := os.Stat(j.path)
+	if err != nil {
+		logger.Errorf("error reading export %s: %v", j.path, err)
+		return
+	}
+
+	baseDir := j.path
+	if !info.IsDir() {
+		baseDir, err = instance.Paths.Generated.TempDir("validate")
+		if err != nil {
+			logger.Errorf("error creating temporary directory for export validation: %v", err)
+			return
+		}
+
+		defer func() {
+			if err := fsutil.RemoveDir(baseDir); err != nil {
+				logger.Errorf("error removing directory %s: %v", baseDir, err)
+			}
+		}()
+
+		if err := unzipExport(j.path, baseDir); err != nil {
+			logger.Errorf("error unzipping export %s: %v", j.path, err)
+			return
+		}
+	}
+
+	logger.Infof("Validating export %s", j.path)
+
+	v := newExportValidator(*paths.GetJSONPaths(baseDir))
+	v.validate(ctx, progress)
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping export validation due to user request")
+		return
+	}
+
+	if v.malformed == 0 && v.orphaned == 0 {
+		logger.Infof("Export %s is valid", j.path)
+	} else {
+		logger.Warnf("Export %s has %d malformed and %d orphaned entries", j.path, v.malformed, v.orphaned)
+	}
+
+	logger.Infof("Finished validating export after %s", time.Since(begin).String())
+}
+
+// exportValidator reads each object of an export, counting objects that
+// cannot be read and references to objects that are not in the export.
+// Objects are read in import order, so that references to other object
+// types can be checked as they are read.
+type exportValidator struct {
+	json jsonUtils
+
+	tags       map[string]bool
+	performers map[string]bool
+	studios    map[string]bool
+	movies     map[string]bool
+	files      map[string]bool
+	folders    map[string]bool
+	galleries  map[string]bool
+
+	malformed int
+	orphaned  int
+}
+
+func newExportValidator(json paths.JSONPaths) *exportValidator {
+	return &exportValidator{
+		json:       jsonUtils{json: json},
+		tags:       make(map[string]bool),
+		performers: make(map[string]bool),
+		studios:    make(map[string]bool),
+		movies:     make(map[string]bool),
+		files:      make(map[string]bool),
+		folders:    make(map[string]bool),
+		galleries:  make(map[string]bool),
+	}
+}
+
+// exportRef is a reference to an object of the same type, which can only
+// be checked after all objects of the type have been read.
+type exportRef struct {
+	name  string
+	value string
+}
+
+// steps returns the validation of each object type, in import order.
+func (v *exportValidator) steps() []func() {
+	return []func(){
+		v.validateTags,
+		v.validatePerformers,
+		v.validateStudios,
+		v.validateMovies,
+		v.validateFiles,
+		v.validateGalleries,
+		v.validateScenes,
+		v.validateImages,
+	}
+}
+
+func (v *exportValidator) validate(ctx context.Context, progress *job.Progress) {
+	steps := v.steps()
+
+	progress.SetTotal(len(steps))
+	for _, step := range steps {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		step()
+		progress.Increment()
+	}
+}
+
+func (v *exportValidator) addMalformed(prefix string, name string, format string, args ...interface{}) {
+	v.malformed++
+	logger.Errorf("%s <%s> %s", prefix, name, fmt.Sprintf(format, args...))
+}
+
+func (v *exportValidator) checkRef(prefix string, name string, kind string, set map[string]bool, value string) {
+	if value == "" || set[value] {
+		return
+	}
+
+	v.orphaned++
+	logger.Warnf("%s <%s> references missing %s %q", prefix, name, kind, value)
+}
+
+func (v *exportValidator) checkRefs(prefix string, name string, kind string, set map[string]bool, values []string) {
+	for _, value := range values {
+		v.checkRef(prefix, name, kind, set, value)
+	}
+}
+
+func (v *exportValidator) checkGalleryRef(prefix string, name string, ref jsonschema.GalleryRef) {
+	switch {
+	case ref.FolderPath != "":
+		v.checkRef(prefix, name, "gallery folder", v.galleries, galleryFolderKey(ref.FolderPath))
+	case len(ref.ZipFiles) > 0:
+		for _, p := range ref.ZipFiles {
+			if v.galleries[galleryZipKey(p)] {
+				return
+			}
+		}
+		v.checkRef(prefix, name, "gallery zip file", v.galleries, galleryZipKey(ref.ZipFiles[0]))
+	default:
+		v.checkRef(prefix, name, "gallery", v.galleries, galleryTitleKey(ref.Title))
+	}
+}
+
+func galleryFolderKey(p string) string { return "folder:" + p }
+func galleryZipKey(p string) string    { return "zip:" + p }
+func galleryTitleKey(t string) string  { return "title:" + t }
+
+func (v *exportValidator) validateTags() {
+	const prefix = "[tags]"
+	var parents []exportRef
+
+	v.json.readObjects(prefix, v.json.json.Tags, func(o jsonObject) {
+		var tagJSON jsonschema.Tag
+		if err := o.decode(&tagJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		if tagJSON.Name == "" {
+			v.addMalformed(prefix, o.name, "missing name")
+			return
+		}
+
+		v.tags[tagJSON.Name] = true
+		for _, p := range tagJSON.Parents {
+			parents = append(parents, exportRef{name: o.name, value: p})
+		}
+	})
+
+	for _, r := range parents {
+		v.checkRef(prefix, r.name, "parent tag", v.tags, r.value)
+	}
+}
+
+func (v *exportValidator) validatePerformers() {
+	const prefix = "[performers]"
+
+	v.json.readObjects(prefix, v.json.json.Performers, func(o jsonObject) {
+		var performerJSON jsonschema.Performer
+		if err := o.decode(&performerJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		if performerJSON.Name == "" {
+			v.addMalformed(prefix, o.name, "missing name")
+			return
+		}
+
+		v.performers[performerJSON.Name] = true
+		v.checkRefs(prefix, o.name, "tag", v.tags, performerJSON.Tags)
+	})
+}
+
+func (v *exportValidator) validateStudios() {
+	const prefix = "[studios]"
+	var parents []exportRef
+
+	v.json.readObjects(prefix, v.json.json.Studios, func(o jsonObject) {
+		var studioJSON jsonschema.Studio
+		if err := o.decode(&studioJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		if studioJSON.Name == "" {
+			v.addMalformed(prefix, o.name, "missing name")
+			return
+		}
+
+		v.studios[studioJSON.Name] = true
+		if studioJSON.ParentStudio != "" {
+			parents = append(parents, exportRef{name: o.name, value: studioJSON.ParentStudio})
+		}
+	})
+
+	for _, r := range parents {
+		v.checkRef(prefix, r.name, "parent studio", v.studios, r.value)
+	}
+}
+
+func (v *exportValidator) validateMovies() {
+	const prefix = "[movies]"
+
+	v.json.readObjects(prefix, v.json.json.Movies, func(o jsonObject) {
+		var movieJSON jsonschema.Movie
+		if err := o.decode(&movieJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		if movieJSON.Name == "" {
+			v.addMalformed(prefix, o.name, "missing name")
+			return
+		}
+
+		v.movies[movieJSON.Name] = true
+		v.checkRef(prefix, o.name, "studio", v.studios, movieJSON.Studio)
+	})
+}
+
+func (v *exportValidator) validateFiles() {
+	const prefix = "[files]"
+	var zipFiles []exportRef
+
+	v.json.readObjects(prefix, v.json.json.Files, func(o jsonObject) {
+		entry, err := o.dirEntry()
+		if err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		base := entry.DirEntry()
+		if base.Path == "" {
+			v.addMalformed(prefix, o.name, "missing path")
+			return
+		}
+
+		if entry.IsFile() {
+			v.files[base.Path] = true
+		} else {
+			v.folders[base.Path] = true
+		}
+
+		if base.ZipFile != "" {
+			zipFiles = append(zipFiles, exportRef{name: o.name, value: base.ZipFile})
+		}
+	})
+
+	for _, r := range zipFiles {
+		v.checkRef(prefix, r.name, "zip file", v.files, r.value)
+	}
+}
+
+func (v *exportValidator) validateGalleries() {
+	const prefix = "[galleries]"
+
+	v.json.readObjects(prefix, v.json.json.Galleries, func(o jsonObject) {
+		var galleryJSON jsonschema.Gallery
+		if err := o.decode(&galleryJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		switch {
+		case galleryJSON.FolderPath != "":
+			v.galleries[galleryFolderKey(galleryJSON.FolderPath)] = true
+		case len(galleryJSON.ZipFiles) > 0:
+			for _, p := range galleryJSON.ZipFiles {
+				v.galleries[galleryZipKey(p)] = true
+			}
+		default:
+			v.galleries[galleryTitleKey(galleryJSON.Title)] = true
+		}
+
+		v.checkRef(prefix, o.name, "folder", v.folders, galleryJSON.FolderPath)
+		v.checkRefs(prefix, o.name, "file", v.files, galleryJSON.ZipFiles)
+		v.checkRef(prefix, o.name, "studio", v.studios, galleryJSON.Studio)
+		v.checkRefs(prefix, o.name, "performer", v.performers, galleryJSON.Performers)
+		v.checkRefs(prefix, o.name, "tag", v.tags, galleryJSON.Tags)
+	})
+}
+
+func (v *exportValidator) validateScenes() {
+	const prefix = "[scenes]"
+
+	v.json.readObjects(prefix, v.json.json.Scenes, func(o jsonObject) {
+		var sceneJSON jsonschema.Scene
+		if err := o.decode(&sceneJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		v.checkRefs(prefix, o.name, "file", v.files, sceneJSON.Files)
+		v.checkRef(prefix, o.name, "studio", v.studios, sceneJSON.Studio)
+		v.checkRefs(prefix, o.name, "performer", v.performers, sceneJSON.Performers)
+		v.checkRefs(prefix, o.name, "tag", v.tags, sceneJSON.Tags)
+
+		for _, m := range sceneJSON.Movies {
+			v.checkRef(prefix, o.name, "movie", v.movies, m.MovieName)
+		}
+
+		for _, g := range sceneJSON.Galleries {
+			v.checkGalleryRef(prefix, o.name, g)
+		}
+
+		for _, m := range sceneJSON.Markers {
+			if m.PrimaryTag == "" {
+				v.addMalformed(prefix, o.name, "marker %q missing primary tag", m.Title)
+				continue
+			}
+
+			v.checkRef(prefix, o.name, "tag", v.tags, m.PrimaryTag)
+			v.checkRefs(prefix, o.name, "tag", v.tags, m.Tags)
+		}
+	})
+}
+
+func (v *exportValidator) validateImages() {
+	const prefix = "[images]"
+
+	v.json.readObjects(prefix, v.json.json.Images, func(o jsonObject) {
+		var imageJSON jsonschema.Image
+		if err := o.decode(&imageJSON); err != nil {
+			v.addMalformed(prefix, o.name, "invalid json: %v", err)
+			return
+		}
+
+		v.checkRefs(prefix, o.name, "file", v.files, imageJSON.Files)
+		v.checkRef(prefix, o.name, "studio", v.studios, imageJSON.Studio)
+		v.checkRefs(prefix, o.name, "performer", v.performers, imageJSON.Performers)
+		v.checkRefs(prefix, o.name, "tag", v.tags, imageJSON.Tags)
+
+		for _, g := range imageJSON.Galleries {
+			v.checkGalleryRef(prefix, o.name, g)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeExportFile(t *testing.T, dir string, name string, data string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportValidator(t *testing.T) {
+	json := paths.GetJSONPaths(t.TempDir())
+
+	writeExportFile(t, json.Tags, "parent.json", `{"name":"parent"}`)
+	writeExportFile(t, json.Tags, "child.json", `{"name":"child","parents":["parent","missing parent"]}`)
+	writeExportFile(t, json.Tags, "unnamed.json", `{"description":"no name"}`)
+	writeExportFile(t, json.Performers, "performer.json", `{"name":"performer","tags":["child"]}`)
+	writeExportFile(t, json.Studios, "studio.json", `{"name":"studio"}`)
+	writeExportFile(t, json.Studios, "broken.json", `{"name":`)
+	writeExportFile(t, json.Files, "folder.json", `{"type":"folder","path":"/a"}`)
+	writeExportFile(t, json.Files, "file.json", `{"type":"video","path":"/a/b.mp4","zip_file":"/a/missing.zip"}`)
+	writeExportFile(t, json.Galleries, "gallery.json", `{"folder_path":"/a","studio":"studio"}`)
+	writeExportFile(t, json.Scenes, "scene.json", `{
+		"files":["/a/b.mp4"],
+		"studio":"studio",
+		"performers":["performer","missing performer"],
+		"movies":[{"movieName":"missing movie"}],
+		"galleries":[{"folder_path":"/a"},{"title":"missing gallery"}],
+		"markers":[{"title":"marker","primary_tag":"parent"}]
+	}`)
+
+	v := newExportValidator(*json)
+	for _, step := range v.steps() {
+		step()
+	}
+
+	// unnamed tag and unparsable studio
+	assert.Equal(t, 2, v.malformed)
+	// missing parent tag, zip file, performer, movie and gallery
+	assert.Equal(t, 5, v.orphaned)
+}
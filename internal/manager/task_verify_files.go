@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// verifyFilesJob re-calculates the fingerprints of files to detect files
+// whose contents have changed on disk without being modified.
+type verifyFilesJob struct {
+	verifier *file.Verifier
+	input    VerifyFilesInput
+}
+
+func (j *verifyFilesJob) Execute(ctx context.Context, progress *job.Progress) {
+	begin := time.Now()
+
+	limit := -1
+	if j.input.Limit != nil && *j.input.Limit > 0 {
+		limit = *j.input.Limit
+	}
+
+	j.verifier.Verify(ctx, file.VerifyOptions{
+		Paths: j.input.Paths,
+		Limit: limit,
+	}, progress)
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	logger.Infof("Finished verifying files after %s", time.Since(begin).String())
+}
+
+// verifyFilesScheduler starts file verifications at the configured interval.
+type verifyFilesScheduler struct {
+	manager *Manager
+	last    time.Time
+}
+
+// update starts a verification of the configured sample of files if the
+// verification interval has passed since the last verification. The first
+// verification is started one interval after the interval is seen by the
+// scheduler.
+func (s *verifyFilesScheduler) update(ctx context.Context, now time.Time) {
+	mgr := s.manager
+	c := mgr.Config
+
+	if c.IsNewSystem() || mgr.Database.Ready() != nil {
+		return
+	}
+
+	interval := c.GetVerifyFilesInterval()
+	if interval <= 0 {
+		s.last = time.Time{}
+		return
+	}
+
+	if s.last.IsZero() {
+		s.last = now
+		return
+	}
+
+	if now.Sub(s.last) < time.Duration(interval)*time.Hour {
+		return
+	}
+
+	s.last = now
+
+	input := VerifyFilesInput{}
+	if sample := c.GetVerifyFilesSampleSize(); sample > 0 {
+		input.Limit = &sample
+	}
+
+	logger.Info("Starting scheduled file verification")
+	mgr.VerifyFiles(ctx, input)
+}
@@ -0,0 +1,145 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+var ErrTrashDisabled = errors.New("trash directory is not configured")
+
+const trashPurgeInterval = time.Hour
+
+type PurgeTrashInput struct {
+	// Purge all files, rather than only those older than the retention period
+	All bool `json:"all"`
+}
+
+// Trash returns the trash that deleted files are moved to. Returns nil if
+// the trash is not configured.
+func (s *Manager) Trash() *file.Trash {
+	p := s.Config.GetTrashPath()
+	if p == "" {
+		return nil
+	}
+
+	return &file.Trash{Path: p}
+}
+
+// NewFileDeleter returns a file deleter that moves deleted library files to
+// the trash, if configured.
+func (s *Manager) NewFileDeleter() *file.Deleter {
+	ret := file.NewDeleter()
+	ret.Trash = s.Trash()
+	return ret
+}
+
+// RestoreTrash moves the files of the trash entries back to their original
+// paths, and starts a scan of the restored files.
+func (s *Manager) RestoreTrash(ctx context.Context, ids []string) error {
+	trash := s.Trash()
+	if trash == nil {
+		return ErrTrashDisabled
+	}
+
+	var paths []string
+	var err error
+	for _, id := range ids {
+		var entry *file.TrashEntry
+		entry, err = trash.Restore(id)
+		if err != nil {
+			break
+		}
+
+		logger.Infof("Restored %s from trash", entry.Path)
+		paths = append(paths, entry.Path)
+	}
+
+	// scan the restored files even if some could not be restored
+	if len(paths) > 0 {
+		input := ScanMetadataInput{
+			Paths: paths,
+		}
+		if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+			input.ScanMetadataOptions = *opts
+		}
+
+		if _, scanErr := s.Scan(ctx, input); scanErr != nil {
+			logger.Errorf("error starting scan of restored files: %v", scanErr)
+		}
+	}
+
+	return err
+}
+
+// PurgeTrash starts a job to permanently delete files from the trash.
+func (s *Manager) PurgeTrash(ctx context.Context, input PurgeTrashInput) (int, error) {
+	trash := s.Trash()
+	if trash == nil {
+		return 0, ErrTrashDisabled
+	}
+
+	retentionDays := s.Config.GetTrashRetentionDays()
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		var before time.Time
+		switch {
+		case input.All:
+			before = time.Now()
+		case retentionDays > 0:
+			before = time.Now().AddDate(0, 0, -retentionDays)
+		default:
+			logger.Info("No trash retention period set. Not purging trash.")
+			return
+		}
+
+		n, err := trash.Purge(before)
+		if err != nil {
+			logger.Errorf("error purging trash: %v", err)
+			return
+		}
+
+		logger.Infof("Purged %d files from trash", n)
+	})
+
+	return s.JobManager.Add(ctx, "Purging trash...", j), nil
+}
+
+// trashPurgeScheduler permanently deletes files that have been in the trash
+// for longer than the configured retention period.
+type trashPurgeScheduler struct {
+	manager *Manager
+	last    time.Time
+}
+
+// update purges the expired files from the trash if trashPurgeInterval has
+// passed since the last purge.
+func (s *trashPurgeScheduler) update(ctx context.Context, now time.Time) {
+	mgr := s.manager
+
+	retentionDays := mgr.Config.GetTrashRetentionDays()
+	trash := mgr.Trash()
+	if retentionDays <= 0 || trash == nil {
+		return
+	}
+
+	if !s.last.IsZero() && now.Sub(s.last) < trashPurgeInterval {
+		return
+	}
+
+	s.last = now
+
+	n, err := trash.Purge(now.AddDate(0, 0, -retentionDays))
+	if err != nil {
+		logger.Errorf("error purging expired files from trash: %v", err)
+		return
+	}
+
+	if n > 0 {
+		logger.Infof("Purged %d expired files from trash", n)
+	}
+}
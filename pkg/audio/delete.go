@@ -0,0 +1,51 @@
+package audio
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Destroy destroys an audio, optionally marking its files for deletion.
+func (s *Service) Destroy(ctx context.Context, a *models.Audio, fileDeleter *file.Deleter, deleteFile bool) error {
+	if deleteFile {
+		if err := s.deleteFiles(ctx, a, fileDeleter); err != nil {
+			return err
+		}
+	}
+
+	return s.Repository.Destroy(ctx, a.ID)
+}
+
+// deleteFiles deletes files for the audio from the database and file system, if they are not in use by other audios
+func (s *Service) deleteFiles(ctx context.Context, a *models.Audio, fileDeleter *file.Deleter) error {
+	if err := a.LoadFiles(ctx, s.Repository); err != nil {
+		return err
+	}
+
+	for _, f := range a.Files.List() {
+		// only delete files where there is no other associated audio
+		otherAudios, err := s.Repository.FindByFileID(ctx, f.Base().ID)
+		if err != nil {
+			return err
+		}
+
+		if len(otherAudios) > 1 {
+			// other audio associated, don't remove
+			continue
+		}
+
+		// don't delete files in zip archives
+		const deleteFile = true
+		if f.Base().ZipFileID == nil {
+			logger.Info("Deleting audio file: ", f.Base().Path)
+			if err := file.Destroy(ctx, s.File, f, fileDeleter, deleteFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
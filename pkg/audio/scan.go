@@ -0,0 +1,108 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var errNotAudioFile = errors.New("not an audio file")
+
+type ScanCreatorUpdater interface {
+	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Audio, error)
+	FindByFingerprints(ctx context.Context, fp []models.Fingerprint) ([]*models.Audio, error)
+	GetFiles(ctx context.Context, relatedID int) ([]models.File, error)
+
+	Create(ctx context.Context, newAudio *models.Audio, fileIDs []models.FileID) error
+	UpdatePartial(ctx context.Context, id int, updatedAudio models.AudioPartial) (*models.Audio, error)
+	AddFileID(ctx context.Context, id int, fileID models.FileID) error
+}
+
+type ScanHandler struct {
+	CreatorUpdater ScanCreatorUpdater
+}
+
+func (h *ScanHandler) validate() error {
+	if h.CreatorUpdater == nil {
+		return errors.New("CreatorUpdater is required")
+	}
+
+	return nil
+}
+
+func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.File) error {
+	if err := h.validate(); err != nil {
+		return err
+	}
+
+	audioFile, ok := f.(*models.AudioFile)
+	if !ok {
+		return errNotAudioFile
+	}
+
+	// try to match the file to an audio
+	existing, err := h.CreatorUpdater.FindByFileID(ctx, f.Base().ID)
+	if err != nil {
+		return fmt.Errorf("finding existing audio: %w", err)
+	}
+
+	if len(existing) == 0 {
+		// try also to match file by fingerprints
+		existing, err = h.CreatorUpdater.FindByFingerprints(ctx, audioFile.Fingerprints)
+		if err != nil {
+			return fmt.Errorf("finding existing audio by fingerprints: %w", err)
+		}
+	}
+
+	if len(existing) > 0 {
+		if err := h.associateExisting(ctx, existing, audioFile); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// create a new audio
+	newAudio := models.NewAudio()
+
+	logger.Infof("%s doesn't exist. Creating new audio...", f.Base().Path)
+
+	if err := h.CreatorUpdater.Create(ctx, &newAudio, []models.FileID{audioFile.ID}); err != nil {
+		return fmt.Errorf("creating new audio: %w", err)
+	}
+
+	return nil
+}
+
+func (h *ScanHandler) associateExisting(ctx context.Context, existing []*models.Audio, f *models.AudioFile) error {
+	for _, a := range existing {
+		if err := a.LoadFiles(ctx, h.CreatorUpdater); err != nil {
+			return err
+		}
+
+		found := false
+		for _, af := range a.Files.List() {
+			if af.Base().ID == f.ID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			logger.Infof("Adding %s to audio %s", f.Path, a.DisplayName())
+
+			if err := h.CreatorUpdater.AddFileID(ctx, a.ID, f.ID); err != nil {
+				return fmt.Errorf("adding file to audio: %w", err)
+			}
+
+			// update updated_at time
+			if _, err := h.CreatorUpdater.UpdatePartial(ctx, a.ID, models.NewAudioPartial()); err != nil {
+				return fmt.Errorf("updating audio: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package audio
+
+import (
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type Service struct {
+	File       models.FileReaderWriter
+	Repository models.AudioReaderWriter
+}
@@ -14,6 +14,8 @@ var (
 	// Software codec's
 	VideoCodecLibX264 VideoCodec = "libx264"
 	VideoCodecLibWebP VideoCodec = "libwebp"
+	VideoCodecLibAOM  VideoCodec = "libaom-av1"
+	VideoCodecSVTAV1  VideoCodec = "libsvtav1"
 	VideoCodecBMP     VideoCodec = "bmp"
 	VideoCodecMJpeg   VideoCodec = "mjpeg"
 	VideoCodecVP9     VideoCodec = "libvpx-vp9"
@@ -37,3 +39,18 @@ var (
 	AudioCodecLibOpus AudioCodec = "libopus"
 	AudioCodecCopy    AudioCodec = "copy"
 )
+
+// textSubtitleCodecs are the subtitle codecs that can be converted to
+// WebVTT. Image based subtitles such as PGS and VobSub cannot.
+var textSubtitleCodecs = []string{"subrip", "srt", "ass", "ssa", "webvtt", "mov_text", "text"}
+
+// IsTextSubtitleCodec returns true if the subtitle codec is text based,
+// and can therefore be converted to WebVTT.
+func IsTextSubtitleCodec(codecName string) bool {
+	for _, c := range textSubtitleCodecs {
+		if c == codecName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package ffmpeg
+
+import "github.com/stashapp/stash/pkg/sliceutil"
+
+// TemplateCodecs are the video codecs whose encoding options can be replaced
+// using SetCodecArgs.
+var TemplateCodecs = []VideoCodec{
+	VideoCodecLibX264,
+	VideoCodecVP9,
+	VideoCodecSVTAV1,
+	VideoCodecN264,
+	VideoCodecI264,
+	VideoCodecV264,
+	VideoCodecA264,
+	VideoCodecM264,
+	VideoCodecO264,
+	VideoCodecIVP9,
+	VideoCodecVVP9,
+	VideoCodecNAV1,
+	VideoCodecIAV1,
+	VideoCodecVAV1,
+}
+
+// IsTemplateCodec returns true if codec is one of TemplateCodecs.
+func IsTemplateCodec(codec VideoCodec) bool {
+	return sliceutil.Contains(TemplateCodecs, codec)
+}
+
+// SetCodecArgs sets the encoding options used in place of the built-in
+// options of each codec, when live transcoding and when generating files
+// respectively. Codecs not in the maps use the built-in options.
+func (f *FFMpeg) SetCodecArgs(live map[VideoCodec]Args, generate map[VideoCodec]Args) {
+	f.codecArgsMutex.Lock()
+	defer f.codecArgsMutex.Unlock()
+	f.liveCodecArgs = live
+	f.generateCodecArgs = generate
+}
+
+func (f *FFMpeg) codecArgs(set map[VideoCodec]Args, codec VideoCodec, def Args) Args {
+	f.codecArgsMutex.RLock()
+	defer f.codecArgsMutex.RUnlock()
+
+	if args, ok := set[codec]; ok {
+		return append(Args(nil), args...)
+	}
+	return def
+}
+
+// LiveCodecArgs returns the encoding options of codec when live transcoding.
+// These are the options set using SetCodecArgs, or DefaultCodecArgs if none
+// are set.
+func (f *FFMpeg) LiveCodecArgs(codec VideoCodec) Args {
+	return f.codecArgs(f.liveCodecArgs, codec, DefaultCodecArgs(codec))
+}
+
+// GenerateCodecArgs returns the encoding options of codec when generating
+// files. These are the options set using SetCodecArgs, or
+// DefaultGenerateCodecArgs if none are set. The generators add their own
+// quality options, such as -crf, before these options, so that options set
+// here take precedence.
+func (f *FFMpeg) GenerateCodecArgs(codec VideoCodec) Args {
+	return f.codecArgs(f.generateCodecArgs, codec, DefaultGenerateCodecArgs(codec))
+}
+
+// DefaultGenerateCodecArgs returns the built-in encoding options used for
+// codec when generating files, not including the codec itself.
+func DefaultGenerateCodecArgs(codec VideoCodec) Args {
+	switch codec {
+	case VideoCodecLibX264:
+		return Args{
+			"-pix_fmt", "yuv420p",
+			"-profile:v", "high",
+			"-level", "4.2",
+		}
+	case VideoCodecVP9, VideoCodecSVTAV1:
+		return Args{
+			"-pix_fmt", "yuv420p",
+		}
+	}
+
+	// hardware codecs use the same options as when live transcoding
+	return DefaultCodecArgs(codec)
+}
+
+// CodecInit returns the arguments selecting codec and its encoding options
+// when live transcoding.
+func (f *FFMpeg) CodecInit(codec VideoCodec) (args Args) {
+	args = args.VideoCodec(codec)
+	return append(args, f.LiveCodecArgs(codec)...)
+}
@@ -0,0 +1,42 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFFMpeg_CodecInit(t *testing.T) {
+	f := &FFMpeg{}
+
+	want := append(Args{"-c:v", "libx264"}, DefaultCodecArgs(VideoCodecLibX264)...)
+	assert.Equal(t, want, f.CodecInit(VideoCodecLibX264))
+
+	f.SetCodecArgs(map[VideoCodec]Args{
+		VideoCodecLibX264: {"-preset", "medium", "-crf", "20"},
+	}, nil)
+
+	assert.Equal(t, Args{"-c:v", "libx264", "-preset", "medium", "-crf", "20"}, f.CodecInit(VideoCodecLibX264))
+
+	// codecs without options use the built-in options
+	want = append(Args{"-c:v", "libvpx-vp9"}, DefaultCodecArgs(VideoCodecVP9)...)
+	assert.Equal(t, want, f.CodecInit(VideoCodecVP9))
+}
+
+func TestFFMpeg_GenerateCodecArgs(t *testing.T) {
+	f := &FFMpeg{}
+
+	f.SetCodecArgs(map[VideoCodec]Args{
+		VideoCodecLibX264: {"-crf", "30"},
+	}, map[VideoCodec]Args{
+		VideoCodecSVTAV1: {"-crf", "40"},
+	})
+
+	// live and generate options are set separately
+	assert.Equal(t, DefaultGenerateCodecArgs(VideoCodecLibX264), f.GenerateCodecArgs(VideoCodecLibX264))
+	assert.Equal(t, Args{"-crf", "40"}, f.GenerateCodecArgs(VideoCodecSVTAV1))
+	assert.Equal(t, DefaultCodecArgs(VideoCodecSVTAV1), f.LiveCodecArgs(VideoCodecSVTAV1))
+
+	// hardware codecs use the live transcoding options by default
+	assert.Equal(t, DefaultCodecArgs(VideoCodecN264), f.GenerateCodecArgs(VideoCodecN264))
+}
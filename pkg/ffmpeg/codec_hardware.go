@@ -3,6 +3,7 @@ package ffmpeg
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -22,9 +23,83 @@ var (
 	VideoCodecIVP9 VideoCodec = "vp9_qsv"
 	VideoCodecVVP9 VideoCodec = "vp9_vaapi"
 	VideoCodecVVPX VideoCodec = "vp8_vaapi"
+	VideoCodecNAV1 VideoCodec = "av1_nvenc"
+	VideoCodecIAV1 VideoCodec = "av1_qsv"
+	VideoCodecVAV1 VideoCodec = "av1_vaapi"
 )
 
-// Tests all (given) hardware codec's
+// defaultVAAPIDevice is the render device used by VAAPI codecs if none is
+// configured.
+const defaultVAAPIDevice = "/dev/dri/renderD128"
+
+// HWAccelOptions configures hardware accelerated encoding.
+type HWAccelOptions struct {
+	// Codec is the preferred hardware codec. If empty or not supported, the
+	// first supported codec that is compatible with the output is used.
+	Codec VideoCodec
+	// VAAPIDevice is the render device used by VAAPI codecs. Defaults to
+	// /dev/dri/renderD128 if empty.
+	VAAPIDevice string
+}
+
+// SetHWAccelOptions sets the hardware acceleration options. InitHWSupport
+// should be called afterwards to probe the codecs with the new options.
+func (f *FFMpeg) SetHWAccelOptions(o HWAccelOptions) {
+	f.hwMutex.Lock()
+	defer f.hwMutex.Unlock()
+	f.hwOptions = o
+}
+
+// HWCodecSupport returns the hardware codecs that were found to work when
+// InitHWSupport was last called.
+func (f *FFMpeg) HWCodecSupport() []VideoCodec {
+	f.hwMutex.RLock()
+	defer f.hwMutex.RUnlock()
+
+	return append([]VideoCodec(nil), f.hwCodecSupport...)
+}
+
+// hwCodecs returns the supported hardware codecs, with the preferred codec
+// first.
+func (f *FFMpeg) hwCodecs() []VideoCodec {
+	f.hwMutex.RLock()
+	defer f.hwMutex.RUnlock()
+
+	ret := make([]VideoCodec, 0, len(f.hwCodecSupport))
+	for _, c := range f.hwCodecSupport {
+		if c == f.hwOptions.Codec {
+			ret = append([]VideoCodec{c}, ret...)
+		} else {
+			ret = append(ret, c)
+		}
+	}
+
+	return ret
+}
+
+// IsHWCodec returns true if codec is a hardware codec.
+func IsHWCodec(codec VideoCodec) bool {
+	switch codec {
+	case VideoCodecN264,
+		VideoCodecI264,
+		VideoCodecA264,
+		VideoCodecM264,
+		VideoCodecV264,
+		VideoCodecR264,
+		VideoCodecO264,
+		VideoCodecIVP9,
+		VideoCodecVVP9,
+		VideoCodecVVPX,
+		VideoCodecNAV1,
+		VideoCodecIAV1,
+		VideoCodecVAV1:
+		return true
+	}
+	return false
+}
+
+// Tests all (given) hardware codec's, along with the optional software AV1
+// encoder and tone mapping filters.
 func (f *FFMpeg) InitHWSupport(ctx context.Context) {
 	var hwCodecSupport []VideoCodec
 
@@ -35,43 +110,12 @@ func (f *FFMpeg) InitHWSupport(ctx context.Context) {
 		VideoCodecR264,
 		VideoCodecIVP9,
 		VideoCodecVVP9,
+		VideoCodecNAV1,
+		VideoCodecIAV1,
+		VideoCodecVAV1,
 	} {
-		var args Args
-		args = append(args, "-hide_banner")
-		args = args.LogLevel(LogLevelWarning)
-		args = f.hwDeviceInit(args, codec)
-		args = args.Format("lavfi")
-		args = args.Input("color=c=red")
-		args = args.Duration(0.1)
-
-		videoFilter := f.hwFilterInit(codec)
-		// Test scaling
-		videoFilter = videoFilter.ScaleDimensions(-2, 160)
-		videoFilter = f.hwCodecFilter(videoFilter, codec)
-		args = append(args, CodecInit(codec)...)
-		args = args.VideoFilter(videoFilter)
-
-		args = args.Format("null")
-		args = args.Output("-")
-
-		cmd := f.Command(ctx, args)
-
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-
-		if err := cmd.Start(); err != nil {
-			logger.Debugf("[InitHWSupport] error starting command: %w", err)
-			continue
-		}
-
-		if err := cmd.Wait(); err != nil {
-			errOutput := stderr.String()
-
-			if len(errOutput) == 0 {
-				errOutput = err.Error()
-			}
-
-			logger.Debugf("[InitHWSupport] Codec %s not supported. Error output:\n%s", codec, errOutput)
+		if err := f.testCodec(ctx, codec); err != nil {
+			logger.Debugf("[InitHWSupport] Codec %s not supported. Error output:\n%s", codec, err)
 		} else {
 			hwCodecSupport = append(hwCodecSupport, codec)
 		}
@@ -83,21 +127,89 @@ func (f *FFMpeg) InitHWSupport(ctx context.Context) {
 	}
 	logger.Info(outstr)
 
+	// libsvtav1 is not included in all ffmpeg builds
+	svtAV1Support := true
+	if err := f.testCodec(ctx, VideoCodecSVTAV1); err != nil {
+		logger.Debugf("[InitHWSupport] Codec %s not supported. Error output:\n%s", VideoCodecSVTAV1, err)
+		svtAV1Support = false
+	}
+
+	f.hwMutex.Lock()
 	f.hwCodecSupport = hwCodecSupport
+	f.svtAV1Support = svtAV1Support
+	f.hwMutex.Unlock()
+
+	f.initToneMapSupport(ctx)
+}
+
+// testCodec encodes a short test video with codec, returning an error
+// containing the ffmpeg output if it fails.
+func (f *FFMpeg) testCodec(ctx context.Context, codec VideoCodec) error {
+	var args Args
+	args = append(args, "-hide_banner")
+	args = args.LogLevel(LogLevelWarning)
+	args = f.hwDeviceInit(args, codec)
+	args = args.Format("lavfi")
+	args = args.Input("color=c=red")
+	args = args.Duration(0.1)
+
+	videoFilter := f.hwFilterInit(codec)
+	// Test scaling
+	videoFilter = videoFilter.ScaleDimensions(-2, 160)
+	videoFilter = f.hwCodecFilter(videoFilter, codec)
+	args = append(args, f.CodecInit(codec)...)
+	args = args.VideoFilter(videoFilter)
+
+	args = args.Format("null")
+	args = args.Output("-")
+
+	cmd := f.Command(ctx, args)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting command: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		errOutput := stderr.String()
+
+		if len(errOutput) == 0 {
+			errOutput = err.Error()
+		}
+
+		return errors.New(errOutput)
+	}
+
+	return nil
+}
+
+func (f *FFMpeg) vaapiDevice() string {
+	f.hwMutex.RLock()
+	defer f.hwMutex.RUnlock()
+
+	if f.hwOptions.VAAPIDevice != "" {
+		return f.hwOptions.VAAPIDevice
+	}
+	return defaultVAAPIDevice
 }
 
 // Prepend input for hardware encoding only
 func (f *FFMpeg) hwDeviceInit(args Args, codec VideoCodec) Args {
 	switch codec {
-	case VideoCodecN264:
+	case VideoCodecN264,
+		VideoCodecNAV1:
 		args = append(args, "-hwaccel_device")
 		args = append(args, "0")
 	case VideoCodecV264,
-		VideoCodecVVP9:
+		VideoCodecVVP9,
+		VideoCodecVAV1:
 		args = append(args, "-vaapi_device")
-		args = append(args, "/dev/dri/renderD128")
+		args = append(args, f.vaapiDevice())
 	case VideoCodecI264,
-		VideoCodecIVP9:
+		VideoCodecIVP9,
+		VideoCodecIAV1:
 		args = append(args, "-init_hw_device")
 		args = append(args, "qsv=hw")
 		args = append(args, "-filter_hw_device")
@@ -112,14 +224,17 @@ func (f *FFMpeg) hwFilterInit(codec VideoCodec) VideoFilter {
 	var videoFilter VideoFilter
 	switch codec {
 	case VideoCodecV264,
-		VideoCodecVVP9:
+		VideoCodecVVP9,
+		VideoCodecVAV1:
 		videoFilter = videoFilter.Append("format=nv12")
 		videoFilter = videoFilter.Append("hwupload")
-	case VideoCodecN264:
+	case VideoCodecN264,
+		VideoCodecNAV1:
 		videoFilter = videoFilter.Append("format=nv12")
 		videoFilter = videoFilter.Append("hwupload_cuda")
 	case VideoCodecI264,
-		VideoCodecIVP9:
+		VideoCodecIVP9,
+		VideoCodecIAV1:
 		videoFilter = videoFilter.Append("hwupload=extra_hw_frames=64")
 		videoFilter = videoFilter.Append("format=qsv")
 	}
@@ -133,13 +248,16 @@ func (f *FFMpeg) hwCodecFilter(args VideoFilter, codec VideoCodec) VideoFilter {
 
 	if strings.Contains(sargs, "scale=") {
 		switch codec {
-		case VideoCodecN264:
+		case VideoCodecN264,
+			VideoCodecNAV1:
 			args = VideoFilter(strings.Replace(sargs, "scale=", "scale_cuda=", 1))
 		case VideoCodecV264,
-			VideoCodecVVP9:
+			VideoCodecVVP9,
+			VideoCodecVAV1:
 			args = VideoFilter(strings.Replace(sargs, "scale=", "scale_vaapi=", 1))
 		case VideoCodecI264,
-			VideoCodecIVP9:
+			VideoCodecIVP9,
+			VideoCodecIAV1:
 			// BUG: [scale_qsv]: Size values less than -1 are not acceptable.
 			// Fix: Replace all instances of -2 with -1 in a scale operation
 			re := regexp.MustCompile(`(scale=)([\d:]*)(-2)(.*)`)
@@ -153,7 +271,7 @@ func (f *FFMpeg) hwCodecFilter(args VideoFilter, codec VideoCodec) VideoFilter {
 
 // Returns the max resolution for a given codec, or a default
 func (f *FFMpeg) hwCodecMaxRes(codec VideoCodec, dW int, dH int) (int, int) {
-	if codec == VideoCodecN264 {
+	if codec == VideoCodecN264 || codec == VideoCodecNAV1 {
 		return 4096, 4096
 	}
 
@@ -170,7 +288,7 @@ func (f *FFMpeg) hwMaxResFilter(codec VideoCodec, width int, height int, max int
 
 // Return if a hardware accelerated for HLS is available
 func (f *FFMpeg) hwCodecHLSCompatible() *VideoCodec {
-	for _, element := range f.hwCodecSupport {
+	for _, element := range f.hwCodecs() {
 		switch element {
 		case VideoCodecN264,
 			VideoCodecI264,
@@ -184,7 +302,7 @@ func (f *FFMpeg) hwCodecHLSCompatible() *VideoCodec {
 
 // Return if a hardware accelerated codec for MP4 is available
 func (f *FFMpeg) hwCodecMP4Compatible() *VideoCodec {
-	for _, element := range f.hwCodecSupport {
+	for _, element := range f.hwCodecs() {
 		switch element {
 		case VideoCodecN264,
 			VideoCodecI264:
@@ -196,7 +314,7 @@ func (f *FFMpeg) hwCodecMP4Compatible() *VideoCodec {
 
 // Return if a hardware accelerated codec for WebM is available
 func (f *FFMpeg) hwCodecWEBMCompatible() *VideoCodec {
-	for _, element := range f.hwCodecSupport {
+	for _, element := range f.hwCodecs() {
 		switch element {
 		case VideoCodecIVP9,
 			VideoCodecVVP9:
@@ -205,3 +323,106 @@ func (f *FFMpeg) hwCodecWEBMCompatible() *VideoCodec {
 	}
 	return nil
 }
+
+// Return if a hardware accelerated codec for AV1 is available
+func (f *FFMpeg) hwCodecAV1Compatible() *VideoCodec {
+	for _, element := range f.hwCodecs() {
+		switch element {
+		case VideoCodecNAV1,
+			VideoCodecIAV1,
+			VideoCodecVAV1:
+			return &element
+		}
+	}
+	return nil
+}
+
+// AV1Codec returns the codec to use for AV1 output, or nil if no AV1
+// encoder is supported. Hardware codecs are only returned if useHW is true.
+func (f *FFMpeg) AV1Codec(useHW bool) *VideoCodec {
+	if useHW {
+		if codec := f.hwCodecAV1Compatible(); codec != nil {
+			return codec
+		}
+	}
+
+	f.hwMutex.RLock()
+	defer f.hwMutex.RUnlock()
+
+	if f.svtAV1Support {
+		codec := VideoCodecSVTAV1
+		return &codec
+	}
+	return nil
+}
+
+// HWCodecMP4Compatible returns the hardware codec to use for H.264 MP4
+// output, or nil if none are supported.
+func (f *FFMpeg) HWCodecMP4Compatible() *VideoCodec {
+	return f.hwCodecMP4Compatible()
+}
+
+// HWCodecAV1Compatible returns the hardware codec to use for AV1 output, or
+// nil if none are supported.
+func (f *FFMpeg) HWCodecAV1Compatible() *VideoCodec {
+	return f.hwCodecAV1Compatible()
+}
+
+// HWDeviceArgs returns the arguments that initialise the hardware device
+// for codec. They must precede the input.
+func (f *FFMpeg) HWDeviceArgs(codec VideoCodec) Args {
+	return f.hwDeviceInit(nil, codec)
+}
+
+// HWScaleWidthFilter returns a video filter that uploads frames to the
+// hardware device for codec and scales them to width.
+func (f *FFMpeg) HWScaleWidthFilter(codec VideoCodec, width int) VideoFilter {
+	videoFilter := f.hwFilterInit(codec)
+	videoFilter = videoFilter.ScaleWidth(width)
+	return f.hwCodecFilter(videoFilter, codec)
+}
+
+// HWImageScaleCodec returns the hardware codec whose device is used to scale
+// images, or nil if none are supported. Only CUDA and VAAPI devices are
+// used, since their scale filters can preserve the aspect ratio.
+func (f *FFMpeg) HWImageScaleCodec() *VideoCodec {
+	for _, element := range f.hwCodecs() {
+		switch element {
+		case VideoCodecN264,
+			VideoCodecNAV1,
+			VideoCodecV264,
+			VideoCodecVVP9,
+			VideoCodecVAV1:
+			return &element
+		}
+	}
+	return nil
+}
+
+// HWImageDeviceArgs returns the arguments that decode the input on, and
+// initialise, the hardware device for codec. They must precede the input.
+func (f *FFMpeg) HWImageDeviceArgs(codec VideoCodec) Args {
+	args := f.hwDeviceInit(nil, codec)
+
+	switch codec {
+	case VideoCodecN264,
+		VideoCodecNAV1:
+		args = append(args, "-hwaccel", "cuda")
+	case VideoCodecV264,
+		VideoCodecVVP9,
+		VideoCodecVAV1:
+		args = append(args, "-hwaccel", "vaapi")
+	}
+
+	return args
+}
+
+// HWScaleMaxSizeFilter returns a video filter that uploads frames to the
+// hardware device for codec, scales them to fit within maxDimensions, and
+// downloads them for software encoding.
+func (f *FFMpeg) HWScaleMaxSizeFilter(codec VideoCodec, maxDimensions int) VideoFilter {
+	videoFilter := f.hwFilterInit(codec)
+	videoFilter = videoFilter.ScaleMaxSize(maxDimensions)
+	videoFilter = f.hwCodecFilter(videoFilter, codec)
+	return videoFilter.Append("hwdownload").Append("format=nv12")
+}
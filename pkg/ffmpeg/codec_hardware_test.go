@@ -0,0 +1,37 @@
+package ffmpeg
+
+import "testing"
+
+func TestFFMpeg_AV1Codec(t *testing.T) {
+	codecPtr := func(c VideoCodec) *VideoCodec {
+		return &c
+	}
+
+	tests := []struct {
+		name           string
+		hwCodecSupport []VideoCodec
+		svtAV1Support  bool
+		useHW          bool
+		want           *VideoCodec
+	}{
+		{"none", nil, false, true, nil},
+		{"software", nil, true, true, codecPtr(VideoCodecSVTAV1)},
+		{"hardware", []VideoCodec{VideoCodecN264, VideoCodecNAV1}, true, true, codecPtr(VideoCodecNAV1)},
+		{"hardware disabled", []VideoCodec{VideoCodecNAV1}, true, false, codecPtr(VideoCodecSVTAV1)},
+		{"hardware only", []VideoCodec{VideoCodecVAV1}, false, true, codecPtr(VideoCodecVAV1)},
+		{"no av1 hardware", []VideoCodec{VideoCodecN264}, false, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FFMpeg{
+				hwCodecSupport: tt.hwCodecSupport,
+				svtAV1Support:  tt.svtAV1Support,
+			}
+
+			got := f.AV1Codec(tt.useHW)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("FFMpeg.AV1Codec() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
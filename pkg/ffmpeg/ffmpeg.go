@@ -4,14 +4,24 @@ package ffmpeg
 import (
 	"context"
 	"os/exec"
+	"sync"
 
 	stashExec "github.com/stashapp/stash/pkg/exec"
 )
 
 // FFMpeg provides an interface to ffmpeg.
 type FFMpeg struct {
-	ffmpeg         string
+	ffmpeg string
+
+	hwMutex        sync.RWMutex
+	hwOptions      HWAccelOptions
 	hwCodecSupport []VideoCodec
+	svtAV1Support  bool
+	toneMapFilter  VideoFilter
+
+	codecArgsMutex    sync.RWMutex
+	liveCodecArgs     map[VideoCodec]Args
+	generateCodecArgs map[VideoCodec]Args
 }
 
 // Creates a new FFMpeg encoder
@@ -41,6 +41,9 @@ type VideoFile struct {
 	FrameRate    float64
 	Rotation     int64
 	FrameCount   int64
+	// ColorTransfer is the transfer characteristics of the video stream,
+	// such as smpte2084 for HDR10.
+	ColorTransfer string
 
 	AudioCodec string
 }
@@ -77,7 +80,7 @@ type FFProbe string
 
 // NewVideoFile runs ffprobe on the given path and returns a VideoFile.
 func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_error", videoPath}
+	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_error", resolveInput(videoPath)}
 	cmd := exec.Command(string(*f), args...)
 	out, err := cmd.Output()
 
@@ -96,7 +99,7 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 // GetReadFrameCount counts the actual frames of the video file.
 // Used when the frame count is missing or incorrect.
 func (f *FFProbe) GetReadFrameCount(path string) (int64, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", path}
+	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", resolveInput(path)}
 	out, err := exec.Command(string(*f), args...).Output()
 
 	if err != nil {
@@ -153,6 +156,7 @@ func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 	if videoStream != nil {
 		result.VideoStream = videoStream
 		result.VideoCodec = videoStream.CodecName
+		result.ColorTransfer = videoStream.ColorTransfer
 		result.FrameCount, _ = strconv.ParseInt(videoStream.NbFrames, 10, 64)
 		if videoStream.NbReadFrames != "" { // if ffprobe counted the frames use that instead
 			fc, _ := strconv.ParseInt(videoStream.NbReadFrames, 10, 64)
@@ -228,3 +232,32 @@ func (v *VideoFile) getStreamIndex(fileType string, probeJSON FFProbeJSON) int {
 
 	return ret
 }
+
+// SubtitleStreams returns the subtitle streams of the file, in the order
+// they are numbered by ffmpeg stream specifiers (for example 0:s:1).
+func (v *VideoFile) SubtitleStreams() []*FFProbeStream {
+	var ret []*FFProbeStream
+	for i := range v.JSON.Streams {
+		if v.JSON.Streams[i].CodecType == "subtitle" {
+			ret = append(ret, &v.JSON.Streams[i])
+		}
+	}
+
+	return ret
+}
+
+// Interlaced returns true if the field order of the video stream is
+// interlaced. Returns false if the video is progressive or the field order
+// is unknown.
+func (v *VideoFile) Interlaced() bool {
+	if v.VideoStream == nil {
+		return false
+	}
+
+	switch v.VideoStream.FieldOrder {
+	case "tt", "bb", "tb", "bt":
+		return true
+	default:
+		return false
+	}
+}
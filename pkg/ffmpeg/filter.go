@@ -2,6 +2,7 @@ package ffmpeg
 
 import (
 	"fmt"
+	"strings"
 )
 
 // VideoFilter represents video filter parameters to be passed to ffmpeg.
@@ -107,3 +108,42 @@ func (f VideoFilter) Append(s string) VideoFilter {
 
 	return VideoFilter(fmt.Sprintf("%s,%s", f, s))
 }
+
+// AudioFilter represents audio filter parameters to be passed to ffmpeg.
+type AudioFilter string
+
+// Args converts the audio filter parameters to a slice of arguments to be passed to ffmpeg.
+// Returns an empty slice if the filter is empty.
+func (f AudioFilter) Args() []string {
+	if f == "" {
+		return nil
+	}
+
+	ret := []string{"-af", string(f)}
+
+	// loudnorm upsamples the output to 192kHz, which is resampled back
+	// to a rate supported by the audio codecs
+	if strings.Contains(string(f), loudNormFilter+"=") {
+		ret = append(ret, "-ar", "48000")
+	}
+
+	return ret
+}
+
+const loudNormFilter = "loudnorm"
+
+// LoudNorm returns an AudioFilter normalizing the loudness to the given
+// integrated loudness target, in LUFS, using the EBU R128 loudnorm filter.
+func (f AudioFilter) LoudNorm(target float64) AudioFilter {
+	return f.Append(fmt.Sprintf("%s=I=%v:TP=-1.5:LRA=11", loudNormFilter, target))
+}
+
+// Append returns an AudioFilter appending the given string.
+func (f AudioFilter) Append(s string) AudioFilter {
+	// if filter is empty, then just set
+	if f == "" {
+		return AudioFilter(s)
+	}
+
+	return AudioFilter(fmt.Sprintf("%s,%s", f, s))
+}
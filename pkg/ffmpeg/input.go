@@ -0,0 +1,33 @@
+package ffmpeg
+
+import "sync"
+
+// InputResolver returns the input passed to ffmpeg and ffprobe to read the
+// file at path. This allows files that are not on the local file system to
+// be read, for example using a URL.
+type InputResolver func(path string) string
+
+var (
+	inputResolver      InputResolver
+	inputResolverMutex sync.RWMutex
+)
+
+// SetInputResolver sets the resolver used for input files. Input paths are
+// passed unchanged if r is nil.
+func SetInputResolver(r InputResolver) {
+	inputResolverMutex.Lock()
+	defer inputResolverMutex.Unlock()
+
+	inputResolver = r
+}
+
+func resolveInput(path string) string {
+	inputResolverMutex.RLock()
+	defer inputResolverMutex.RUnlock()
+
+	if inputResolver == nil {
+		return path
+	}
+
+	return inputResolver(path)
+}
@@ -43,8 +43,9 @@ func (a Args) Duration(seconds float64) Args {
 }
 
 // Input adds the input (-i) and returns the result.
+// The input is resolved using the input resolver.
 func (a Args) Input(i string) Args {
-	return append(a, "-i", i)
+	return append(a, "-i", resolveInput(i))
 }
 
 // Output adds the output o and returns the result.
@@ -80,6 +81,11 @@ func (a Args) VideoFilter(vf VideoFilter) Args {
 	return append(a, vf.Args()...)
 }
 
+// AudioFilter adds the af audio filter and returns the result.
+func (a Args) AudioFilter(af AudioFilter) Args {
+	return append(a, af.Args()...)
+}
+
 // VSync adds the VsyncMethod and returns the result.
 func (a Args) VSync(m VSyncMethod) Args {
 	return append(a, m.Args()...)
@@ -18,6 +18,11 @@ const (
 	MimeMkvAudio  string = "audio/x-matroska"
 	MimeMp4Video  string = "video/mp4"
 	MimeMp4Audio  string = "audio/mp4"
+
+	// MimeMp4AV1Video includes the codec so that clients can determine
+	// whether they are able to decode the stream (main profile, level 4.0,
+	// 8-bit).
+	MimeMp4AV1Video string = `video/mp4; codecs="av01.0.08M.08"`
 )
 
 type StreamManager struct {
@@ -40,6 +45,18 @@ type StreamManagerConfig interface {
 	GetLiveTranscodeInputArgs() []string
 	GetLiveTranscodeOutputArgs() []string
 	GetTranscodeHardwareAcceleration() bool
+	// GetStreamCacheSizeMB returns the maximum size of the transcoded segments
+	// kept after streams finish. Returns 0 if segments are not kept.
+	GetStreamCacheSizeMB() int
+	// GetLiveTranscodeLoudnorm returns true if the audio of live transcodes
+	// should be loudness normalized.
+	GetLiveTranscodeLoudnorm() bool
+	// GetLiveTranscodeLoudnormTarget returns the integrated loudness target
+	// of live transcodes, in LUFS.
+	GetLiveTranscodeLoudnormTarget() float64
+	// GetHDRToneMapping returns true if HDR video should be tone mapped to
+	// SDR when live transcoding.
+	GetHDRToneMapping() bool
 }
 
 func NewStreamManager(cacheDir string, encoder *FFMpeg, ffprobe FFProbe, config StreamManagerConfig, lockManager *fsutil.ReadLockManager) *StreamManager {
@@ -60,6 +77,9 @@ func NewStreamManager(cacheDir string, encoder *FFMpeg, ffprobe FFProbe, config
 		runningStreams: make(map[string]*runningStream),
 	}
 
+	// the cache size may have been reduced since the segments were cached
+	ret.evictSegmentCache()
+
 	go func() {
 		for {
 			select {
@@ -74,7 +94,45 @@ func NewStreamManager(cacheDir string, encoder *FFMpeg, ffprobe FFProbe, config
 	return ret
 }
 
-// Shutdown shuts down the stream manager, killing any running transcoding processes and removing all cached files.
+// liveTranscodeAudioFilter returns the audio filter applied to live
+// transcodes, which is empty if no filtering is configured.
+func (sm *StreamManager) liveTranscodeAudioFilter() AudioFilter {
+	var ret AudioFilter
+	if sm.config.GetLiveTranscodeLoudnorm() {
+		ret = ret.LoudNorm(sm.config.GetLiveTranscodeLoudnormTarget())
+	}
+	return ret
+}
+
+// liveTranscodeToneMapFilter returns the filter tone mapping vf to SDR,
+// which is empty if vf is not HDR, or tone mapping is disabled or not
+// supported.
+func (sm *StreamManager) liveTranscodeToneMapFilter(vf *models.VideoFile) VideoFilter {
+	if !IsHDRTransfer(vf.ColorTransfer) || !sm.config.GetHDRToneMapping() {
+		return ""
+	}
+	return sm.encoder.ToneMapFilter()
+}
+
+// liveTranscodeVideoFilter returns the video filter applied to live
+// transcodes of vf with codec, scaling to maxTranscodeSize.
+func (sm *StreamManager) liveTranscodeVideoFilter(vf *models.VideoFile, codec VideoCodec, maxTranscodeSize int) VideoFilter {
+	scaleFilter := sm.encoder.hwMaxResFilter(codec, vf.Width, vf.Height, maxTranscodeSize)
+
+	// tone mapping is done in software, so is only applied with
+	// software codecs
+	ret := VideoFilter("")
+	if !IsHWCodec(codec) {
+		ret = sm.liveTranscodeToneMapFilter(vf)
+	}
+	if scaleFilter != "" {
+		ret = ret.Append(string(scaleFilter))
+	}
+	return ret
+}
+
+// Shutdown shuts down the stream manager, killing any running transcoding processes and removing all cached files
+// that are not kept in the segment cache.
 func (sm *StreamManager) Shutdown() {
 	sm.cancelFunc()
 	sm.stopAndRemoveAll()
@@ -0,0 +1,202 @@
+package ffmpeg
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// segmentedStreamTypes are the stream types whose segments are written to
+// the cache directory.
+var segmentedStreamTypes = []*StreamType{
+	StreamTypeHLS,
+	StreamTypeHLSCopy,
+	StreamTypeDASHVideo,
+	StreamTypeDASHAudio,
+}
+
+// streamArgsKeyLength is the length of the transcode arguments key that
+// ends the names of stream directories.
+const streamArgsKeyLength = 8
+
+// isStreamDir returns true if name is a directory name returned by
+// StreamType.FileDir. Directories of streams cached before the transcode
+// arguments key was added are also matched, so that they are evicted.
+func isStreamDir(name string) bool {
+	if i := strings.LastIndex(name, "_"); i != -1 && len(name)-i-1 == streamArgsKeyLength && isHex(name[i+1:]) {
+		if isLegacyStreamDir(name[:i]) {
+			return true
+		}
+	}
+
+	return isLegacyStreamDir(name)
+}
+
+// isLegacyStreamDir returns true if name is a stream directory name without
+// the transcode arguments key.
+func isLegacyStreamDir(name string) bool {
+	for _, t := range segmentedStreamTypes {
+		suffix := "_" + t.Name
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+
+		// the max transcode size follows the stream type name
+		i := strings.LastIndex(name, "_")
+		if i != -1 && strings.HasSuffix(name[:i], suffix) && isDigits(name[i+1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (sm *StreamManager) segmentCacheSize() int64 {
+	return int64(sm.config.GetStreamCacheSizeMB()) * 1024 * 1024
+}
+
+// finishStream removes the files of a stream that is no longer running. If
+// the segment cache is enabled, the completed segments are kept so that they
+// can be served again without transcoding, and the least recently used
+// cached streams are evicted if the cache is too large.
+// assume lock is held
+func (sm *StreamManager) finishStream(stream *runningStream) {
+	if sm.segmentCacheSize() <= 0 {
+		sm.removeTranscodeFiles(stream)
+		return
+	}
+
+	// remove incomplete segments, which are prefixed with a dot
+	entries, err := os.ReadDir(stream.outputDir)
+	if err != nil && !os.IsNotExist(err) {
+		logger.Warnf("[transcode] error reading segment directory %s: %v", stream.outputDir, err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			os.Remove(filepath.Join(stream.outputDir, e.Name()))
+		}
+	}
+
+	// the modification time of the directory is used as the last access time
+	if len(entries) > 0 {
+		if err := os.Chtimes(stream.outputDir, stream.lastAccessed, stream.lastAccessed); err != nil {
+			logger.Warnf("[transcode] error updating segment directory %s: %v", stream.outputDir, err)
+		}
+	}
+
+	sm.evictSegmentCache()
+}
+
+type cachedStream struct {
+	path     string
+	size     int64
+	accessed time.Time
+}
+
+// evictSegmentCache removes the least recently used cached streams until the
+// total size of the cached streams is within the configured cache size.
+// Running streams are not evicted.
+// assume lock is held
+func (sm *StreamManager) evictSegmentCache() {
+	maxSize := sm.segmentCacheSize()
+	if sm.cacheDir == "" || maxSize <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(sm.cacheDir)
+	if err != nil {
+		logger.Warnf("[transcode] error reading cache directory: %v", err)
+		return
+	}
+
+	var cached []cachedStream
+	var total int64
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || !isStreamDir(name) {
+			continue
+		}
+
+		path := filepath.Join(sm.cacheDir, name)
+		size := dirSize(path)
+		total += size
+
+		if _, running := sm.runningStreams[name]; running {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		cached = append(cached, cachedStream{
+			path:     path,
+			size:     size,
+			accessed: info.ModTime(),
+		})
+	}
+
+	if total <= maxSize {
+		return
+	}
+
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].accessed.Before(cached[j].accessed)
+	})
+
+	for _, c := range cached {
+		if total <= maxSize {
+			break
+		}
+
+		logger.Debugf("[transcode] evicting cached segments %s", c.path)
+		if err := os.RemoveAll(c.path); err != nil {
+			logger.Warnf("[transcode] error removing segment directory %s: %v", c.path, err)
+			continue
+		}
+		total -= c.size
+	}
+}
+
+// dirSize returns the total size of the files in path.
+func dirSize(path string) int64 {
+	var ret int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				ret += info.Size()
+			}
+		}
+		return nil
+	})
+	return ret
+}
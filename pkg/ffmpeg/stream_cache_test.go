@@ -0,0 +1,137 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStreamConfig struct {
+	cacheSizeMB    int
+	loudnorm       bool
+	loudnormTarget float64
+	toneMapping    bool
+}
+
+func (c testStreamConfig) GetMaxStreamingTranscodeSize() models.StreamingResolutionEnum {
+	return models.StreamingResolutionEnumOriginal
+}
+func (c testStreamConfig) GetLiveTranscodeInputArgs() []string    { return nil }
+func (c testStreamConfig) GetLiveTranscodeOutputArgs() []string   { return nil }
+func (c testStreamConfig) GetTranscodeHardwareAcceleration() bool { return false }
+func (c testStreamConfig) GetStreamCacheSizeMB() int              { return c.cacheSizeMB }
+func (c testStreamConfig) GetLiveTranscodeLoudnorm() bool         { return c.loudnorm }
+func (c testStreamConfig) GetLiveTranscodeLoudnormTarget() float64 {
+	return c.loudnormTarget
+}
+func (c testStreamConfig) GetHDRToneMapping() bool { return c.toneMapping }
+
+func TestIsStreamDir(t *testing.T) {
+	assert.True(t, isStreamDir("abcdef_hls"))
+	assert.True(t, isStreamDir("abcdef_hls_720"))
+	assert.True(t, isStreamDir("abcdef_hls-copy"))
+	assert.True(t, isStreamDir("abcdef_dash-v_480"))
+	assert.True(t, isStreamDir("abcdef_dash-a"))
+	assert.True(t, isStreamDir("abcdef_hls_0123abcd"))
+	assert.True(t, isStreamDir("abcdef_hls_720_0123abcd"))
+	assert.True(t, isStreamDir("abcdef_dash-a_0123abcd"))
+	assert.False(t, isStreamDir("abcdef_hls_x"))
+	assert.False(t, isStreamDir("abcdef_mp4"))
+	assert.False(t, isStreamDir("abcdef_mp4_0123abcd"))
+	assert.False(t, isStreamDir("blobs"))
+}
+
+func TestStreamManager_streamArgsKey(t *testing.T) {
+	hdr := &models.VideoFile{
+		BaseFile:      &models.BaseFile{Path: "in.mp4"},
+		Width:         1920,
+		Height:        1080,
+		AudioCodec:    "aac",
+		ColorTransfer: ColorTransferPQ,
+	}
+
+	key := func(config testStreamConfig, vf *models.VideoFile, maxTranscodeSize int) string {
+		sm := &StreamManager{
+			encoder: &FFMpeg{toneMapFilter: "tonemap"},
+			config:  config,
+		}
+
+		return sm.streamArgsKey(&runningStream{
+			streamType:       StreamTypeHLS,
+			vf:               vf,
+			maxTranscodeSize: maxTranscodeSize,
+		})
+	}
+
+	base := key(testStreamConfig{}, hdr, 0)
+	assert.Len(t, base, streamArgsKeyLength)
+	assert.True(t, isStreamDir(StreamTypeHLS.FileDir("abcdef", 0, base)))
+	assert.True(t, isStreamDir(StreamTypeHLS.FileDir("abcdef", 720, base)))
+
+	// the key does not depend on the path of the file
+	moved := *hdr.BaseFile
+	moved.Path = "moved.mp4"
+	movedVF := *hdr
+	movedVF.BaseFile = &moved
+	assert.Equal(t, base, key(testStreamConfig{}, &movedVF, 0))
+
+	assert.NotEqual(t, base, key(testStreamConfig{loudnorm: true}, hdr, 0))
+	assert.NotEqual(t, base, key(testStreamConfig{toneMapping: true}, hdr, 0))
+	assert.NotEqual(t, base, key(testStreamConfig{}, hdr, 720))
+}
+
+func TestStreamManager_evictSegmentCache(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	const mb = 1024 * 1024
+	now := time.Now()
+
+	makeStream := func(name string, size int, accessed time.Time) string {
+		dir := filepath.Join(cacheDir, name)
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "0.ts"), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(dir, accessed, accessed); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	oldest := makeStream("a_hls", mb, now.Add(-3*time.Hour))
+	older := makeStream("b_hls_480", mb, now.Add(-2*time.Hour))
+	newest := makeStream("c_dash-v", mb, now.Add(-time.Hour))
+	running := makeStream("d_hls", mb, now.Add(-4*time.Hour))
+	other := filepath.Join(cacheDir, "other")
+	if err := os.Mkdir(other, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := &StreamManager{
+		cacheDir: cacheDir,
+		config:   testStreamConfig{cacheSizeMB: 2},
+		runningStreams: map[string]*runningStream{
+			"d_hls": {},
+		},
+	}
+
+	sm.evictSegmentCache()
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	// least recently used streams are evicted first, running streams are kept
+	assert.False(t, exists(oldest))
+	assert.False(t, exists(older))
+	assert.True(t, exists(newest))
+	assert.True(t, exists(running))
+	assert.True(t, exists(other))
+}
@@ -3,6 +3,8 @@ package ffmpeg
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -51,7 +53,9 @@ type StreamType struct {
 	Name          string
 	SegmentType   *SegmentType
 	ServeManifest func(sm *StreamManager, w http.ResponseWriter, r *http.Request, vf *models.VideoFile, resolution string)
-	Args          func(codec VideoCodec, segment int, videoFilter VideoFilter, videoOnly bool, outputDir string) Args
+	// Args returns the output arguments of the stream. codecArgs selects the
+	// video codec and its encoding options.
+	Args func(codecArgs Args, segment int, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool, outputDir string) Args
 }
 
 var (
@@ -59,8 +63,8 @@ var (
 		Name:          "hls",
 		SegmentType:   SegmentTypeTS,
 		ServeManifest: serveHLSManifest,
-		Args: func(codec VideoCodec, segment int, videoFilter VideoFilter, videoOnly bool, outputDir string) (args Args) {
-			args = CodecInit(codec)
+		Args: func(codecArgs Args, segment int, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool, outputDir string) (args Args) {
+			args = append(args, codecArgs...)
 			args = append(args,
 				"-flags", "+cgop",
 				"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", segmentLength),
@@ -69,10 +73,9 @@ var (
 			if videoOnly {
 				args = append(args, "-an")
 			} else {
-				args = append(args,
-					"-c:a", "aac",
-					"-ac", "2",
-				)
+				args = append(args, "-c:a", "aac")
+				args = args.AudioFilter(audioFilter)
+				args = append(args, "-ac", "2")
 			}
 			args = append(args,
 				"-sn",
@@ -93,15 +96,14 @@ var (
 		Name:          "hls-copy",
 		SegmentType:   SegmentTypeTS,
 		ServeManifest: serveHLSManifest,
-		Args: func(codec VideoCodec, segment int, videoFilter VideoFilter, videoOnly bool, outputDir string) (args Args) {
-			args = CodecInit(codec)
+		Args: func(codecArgs Args, segment int, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool, outputDir string) (args Args) {
+			args = append(args, codecArgs...)
 			if videoOnly {
 				args = append(args, "-an")
 			} else {
-				args = append(args,
-					"-c:a", "aac",
-					"-ac", "2",
-				)
+				args = append(args, "-c:a", "aac")
+				args = args.AudioFilter(audioFilter)
+				args = append(args, "-ac", "2")
 			}
 			args = append(args,
 				"-sn",
@@ -122,7 +124,7 @@ var (
 		Name:          "dash-v",
 		SegmentType:   SegmentTypeWEBMVideo,
 		ServeManifest: serveDASHManifest,
-		Args: func(codec VideoCodec, segment int, videoFilter VideoFilter, videoOnly bool, outputDir string) (args Args) {
+		Args: func(codecArgs Args, segment int, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool, outputDir string) (args Args) {
 			// only generate the actual init segment (init_v.webm)
 			// when generating the first segment
 			init := ".init"
@@ -130,7 +132,7 @@ var (
 				init = "init"
 			}
 
-			args = CodecInit(codec)
+			args = append(args, codecArgs...)
 			args = append(args,
 				"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", segmentLength),
 			)
@@ -152,15 +154,16 @@ var (
 		Name:          "dash-a",
 		SegmentType:   SegmentTypeWEBMAudio,
 		ServeManifest: serveDASHManifest,
-		Args: func(codec VideoCodec, segment int, videoFilter VideoFilter, videoOnly bool, outputDir string) (args Args) {
+		Args: func(codecArgs Args, segment int, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool, outputDir string) (args Args) {
 			// only generate the actual init segment (init_a.webm)
 			// when generating the first segment
 			init := ".init"
 			if segment == 0 {
 				init = "init"
 			}
+			args = append(args, "-c:a", "libopus")
+			args = args.AudioFilter(audioFilter)
 			args = append(args,
-				"-c:a", "libopus",
 				"-b:a", "96000",
 				"-ar", "48000",
 				"-copyts",
@@ -263,6 +266,7 @@ type transcodeProcess struct {
 	outputDir   string
 	segmentType *SegmentType
 	segment     int
+	codec       VideoCodec
 }
 
 type waitingSegment struct {
@@ -286,17 +290,23 @@ type runningStream struct {
 	tp              *transcodeProcess
 	lastAccessed    time.Time
 	lastSegment     int
+
+	// set if hardware encoding failed for this stream
+	softwareOnly bool
 }
 
 func (t StreamType) String() string {
 	return t.Name
 }
 
-func (t StreamType) FileDir(hash string, maxTranscodeSize int) string {
+// FileDir returns the name of the directory that the segments of a stream
+// are written to. argsKey identifies the transcode arguments, so that
+// segments transcoded with different settings are not served from the cache.
+func (t StreamType) FileDir(hash string, maxTranscodeSize int, argsKey string) string {
 	if maxTranscodeSize == 0 {
-		return fmt.Sprintf("%s_%s", hash, t)
+		return fmt.Sprintf("%s_%s_%s", hash, t, argsKey)
 	} else {
-		return fmt.Sprintf("%s_%s_%d", hash, t, maxTranscodeSize)
+		return fmt.Sprintf("%s_%s_%d_%s", hash, t, maxTranscodeSize, argsKey)
 	}
 }
 
@@ -319,15 +329,32 @@ func HLSGetCodec(sm *StreamManager, name string) (codec VideoCodec) {
 	return codec
 }
 
-func (s *runningStream) makeStreamArgs(sm *StreamManager, segment int) Args {
+// hlsSoftwareCodec returns the software codec used for the stream type name
+// if hardware encoding fails.
+func hlsSoftwareCodec(name string) VideoCodec {
+	switch name {
+	case "hls":
+		return VideoCodecLibX264
+	case "dash-v":
+		return VideoCodecVP9
+	}
+	return VideoCodecCopy
+}
+
+func (s *runningStream) getCodec(sm *StreamManager) VideoCodec {
+	if s.softwareOnly || sm.liveTranscodeToneMapFilter(s.vf) != "" {
+		return hlsSoftwareCodec(s.streamType.Name)
+	}
+	return HLSGetCodec(sm, s.streamType.Name)
+}
+
+func (s *runningStream) makeStreamArgs(sm *StreamManager, segment int, codec VideoCodec) Args {
 	extraInputArgs := sm.config.GetLiveTranscodeInputArgs()
 	extraOutputArgs := sm.config.GetLiveTranscodeOutputArgs()
 
 	args := Args{"-hide_banner"}
 	args = args.LogLevel(LogLevelError)
 
-	codec := HLSGetCodec(sm, s.streamType.Name)
-
 	args = sm.encoder.hwDeviceInit(args, codec)
 	args = append(args, extraInputArgs...)
 
@@ -339,15 +366,37 @@ func (s *runningStream) makeStreamArgs(sm *StreamManager, segment int) Args {
 
 	videoOnly := ProbeAudioCodec(s.vf.AudioCodec) == MissingUnsupported
 
-	videoFilter := sm.encoder.hwMaxResFilter(codec, s.vf.Width, s.vf.Height, s.maxTranscodeSize)
+	videoFilter := sm.liveTranscodeVideoFilter(s.vf, codec, s.maxTranscodeSize)
+
+	audioFilter := sm.liveTranscodeAudioFilter()
 
-	args = append(args, s.streamType.Args(codec, segment, videoFilter, videoOnly, s.outputDir)...)
+	args = append(args, s.streamType.Args(sm.encoder.CodecInit(codec), segment, videoFilter, audioFilter, videoOnly, s.outputDir)...)
 
 	args = append(args, extraOutputArgs...)
 
 	return args
 }
 
+// streamArgsKey returns a short hash of the effective transcode arguments of
+// s, which include the hardware acceleration, loudness normalisation, tone
+// mapping and codec arguments. The input path and the seek position are
+// excluded, since they do not change the transcoded segments.
+func (sm *StreamManager) streamArgsKey(s *runningStream) string {
+	args := s.makeStreamArgs(sm, 0, s.getCodec(sm))
+
+	h := md5.New()
+	for _, arg := range args {
+		if arg == s.vf.Path {
+			continue
+		}
+		// separate args so that they cannot be combined differently
+		_, _ = io.WriteString(h, arg)
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:streamArgsKeyLength]
+}
+
 // checkSegments renames temp segments that have been completely generated.
 // existing segments are not replaced - if a segment is generated
 // multiple times, then only the first one is kept.
@@ -458,6 +507,125 @@ func serveHLSManifest(sm *StreamManager, w http.ResponseWriter, r *http.Request,
 	utils.ServeStaticContent(w, r, buf.Bytes())
 }
 
+// hlsRenditionSizes are the resolutions offered by the adaptive HLS playlist
+// below the source resolution, in increasing order.
+var hlsRenditionSizes = []models.StreamingResolutionEnum{
+	models.StreamingResolutionEnumStandard,
+	models.StreamingResolutionEnumStandardHd,
+	models.StreamingResolutionEnumFullHd,
+}
+
+const (
+	// estimated bits per pixel of transcoded video, used for the bandwidth
+	// of adaptive HLS renditions
+	hlsBitsPerPixel = 0.1
+	hlsAudioBitrate = 128000
+	hlsDefaultFPS   = 30
+)
+
+type hlsRendition struct {
+	resolution models.StreamingResolutionEnum
+	width      int
+	height     int
+	bandwidth  int
+}
+
+// scaleToMax returns width and height scaled so that the smaller dimension
+// is no larger than maxSize, maintaining aspect ratio. The dimensions are
+// returned unchanged if maxSize is 0.
+func scaleToMax(width, height, maxSize int) (int, int) {
+	videoSize := height
+	if width < videoSize {
+		videoSize = width
+	}
+
+	if maxSize == 0 || maxSize >= videoSize {
+		return width, height
+	}
+
+	scaleFactor := float64(maxSize) / float64(videoSize)
+	return int(float64(width) * scaleFactor), int(float64(height) * scaleFactor)
+}
+
+// hlsRenditions returns the renditions of vf offered by the adaptive HLS
+// playlist, in increasing order of bandwidth. Renditions larger than
+// maxStreamingSize are not included.
+func hlsRenditions(vf *models.VideoFile, maxStreamingSize models.StreamingResolutionEnum) []hlsRendition {
+	fps := vf.FrameRate
+	if fps <= 0 {
+		fps = hlsDefaultFPS
+	}
+
+	audioBitrate := hlsAudioBitrate
+	if ProbeAudioCodec(vf.AudioCodec) == MissingUnsupported {
+		audioBitrate = 0
+	}
+
+	makeRendition := func(resolution models.StreamingResolutionEnum) hlsRendition {
+		w, h := scaleToMax(vf.Width, vf.Height, resolution.GetMaxResolution())
+		return hlsRendition{
+			resolution: resolution,
+			width:      w,
+			height:     h,
+			bandwidth:  int(float64(w*h)*fps*hlsBitsPerPixel) + audioBitrate,
+		}
+	}
+
+	videoSize := vf.Height
+	if vf.Width < videoSize {
+		videoSize = vf.Width
+	}
+	maxSize := maxStreamingSize.GetMaxResolution()
+
+	var ret []hlsRendition
+	for _, r := range hlsRenditionSizes {
+		size := r.GetMaxResolution()
+		if size >= videoSize || (maxSize != 0 && size > maxSize) {
+			continue
+		}
+		ret = append(ret, makeRendition(r))
+	}
+
+	// the top rendition is the source, or the maximum streaming size if the
+	// source is larger
+	switch {
+	case maxSize == 0 || maxSize >= videoSize:
+		ret = append(ret, makeRendition(models.StreamingResolutionEnumOriginal))
+	case len(ret) == 0 || ret[len(ret)-1].resolution.GetMaxResolution() != maxSize:
+		ret = append(ret, makeRendition(maxStreamingSize))
+	}
+
+	return ret
+}
+
+// ServeHLSMasterManifest serves an HLS master playlist for vf, with a variant
+// for each rendition. Variants are generated on the fly using the HLS
+// playlist at playlistURL, which may be relative to the master playlist.
+func (sm *StreamManager) ServeHLSMasterManifest(w http.ResponseWriter, r *http.Request, vf *models.VideoFile, playlistURL string) {
+	if sm.cacheDir == "" {
+		logger.Error("[transcode] cannot live transcode with HLS because cache dir is unset")
+		http.Error(w, "cannot live transcode with HLS because cache dir is unset", http.StatusServiceUnavailable)
+		return
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprint(&buf, "#EXTM3U\n")
+	fmt.Fprint(&buf, "#EXT-X-VERSION:3\n")
+
+	for _, rendition := range hlsRenditions(vf, sm.config.GetMaxStreamingTranscodeSize()) {
+		fmt.Fprintf(&buf, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n", rendition.bandwidth, rendition.width, rendition.height)
+		// keep the query of the master playlist request, which may include
+		// authentication
+		q := r.URL.Query()
+		q.Set("resolution", rendition.resolution.String())
+		fmt.Fprintf(&buf, "%s?%s\n", playlistURL, q.Encode())
+	}
+
+	w.Header().Set("Content-Type", MimeHLS)
+	utils.ServeStaticContent(w, r, buf.Bytes())
+}
+
 // serveDASHManifest serves a generated DASH manifest.
 func serveDASHManifest(sm *StreamManager, w http.ResponseWriter, r *http.Request, vf *models.VideoFile, resolution string) {
 	if sm.cacheDir == "" {
@@ -511,18 +679,7 @@ func serveDASHManifest(sm *StreamManager, w http.ResponseWriter, r *http.Request
 		maxTranscodeSize = models.StreamingResolutionEnum(resolution).GetMaxResolution()
 		urlQuery = fmt.Sprintf("?resolution=%s", resolution)
 	}
-	if maxTranscodeSize != 0 {
-		videoSize := videoHeight
-		if videoWidth < videoSize {
-			videoSize = videoWidth
-		}
-
-		if maxTranscodeSize < videoSize {
-			scaleFactor := float64(maxTranscodeSize) / float64(videoSize)
-			videoWidth = int(float64(videoWidth) * scaleFactor)
-			videoHeight = int(float64(videoHeight) * scaleFactor)
-		}
-	}
+	videoWidth, videoHeight = scaleToMax(videoWidth, videoHeight, maxTranscodeSize)
 
 	mediaDuration := mpd.Duration(time.Duration(probeResult.FileDuration * float64(time.Second)))
 	m := mpd.NewMPD(mpd.DASH_PROFILE_LIVE, mediaDuration.String(), "PT4.0S")
@@ -595,7 +752,12 @@ func (sm *StreamManager) ServeSegment(w http.ResponseWriter, r *http.Request, op
 		maxTranscodeSize = models.StreamingResolutionEnum(options.Resolution).GetMaxResolution()
 	}
 
-	dir := options.StreamType.FileDir(options.Hash, maxTranscodeSize)
+	argsKey := sm.streamArgsKey(&runningStream{
+		streamType:       streamType,
+		vf:               options.VideoFile,
+		maxTranscodeSize: maxTranscodeSize,
+	})
+	dir := options.StreamType.FileDir(options.Hash, maxTranscodeSize, argsKey)
 	outputDir := filepath.Join(sm.cacheDir, dir)
 
 	name := streamType.SegmentType.MakeFilename(segment)
@@ -656,7 +818,8 @@ func (sm *StreamManager) startTranscode(stream *runningStream, segment int, done
 
 	lockCtx := sm.lockManager.ReadLock(sm.context, stream.vf.Path)
 
-	args := stream.makeStreamArgs(sm, segment)
+	codec := stream.getCodec(sm)
+	args := stream.makeStreamArgs(sm, segment, codec)
 	cmd := sm.encoder.Command(lockCtx, args)
 
 	stderr, err := cmd.StderrPipe()
@@ -685,6 +848,7 @@ func (sm *StreamManager) startTranscode(stream *runningStream, segment int, done
 		outputDir:   stream.outputDir,
 		segmentType: stream.streamType.SegmentType,
 		segment:     segment,
+		codec:       codec,
 	}
 	stream.tp = tp
 
@@ -728,6 +892,14 @@ func (sm *StreamManager) startTranscode(stream *runningStream, segment int, done
 
 		if stream.tp == tp {
 			stream.tp = nil
+
+			// retry with software encoding if the hardware encoder failed
+			if err != nil && !tp.cancelled && IsHWCodec(tp.codec) && !stream.softwareOnly {
+				logger.Warnf("[transcode] hardware encoding with %s failed for %s, falling back to software encoding", tp.codec, stream.dir)
+				stream.softwareOnly = true
+				sm.startTranscode(stream, tp.segment, done)
+				err = nil
+			}
 		}
 
 		sm.streamsMutex.Unlock()
@@ -753,9 +925,8 @@ func (sm *StreamManager) checkTranscode(stream *runningStream, now time.Time) {
 		logger.Debugf("[transcode] stream for %s not accessed recently. Cancelling transcode and removing files", stream.dir)
 
 		sm.stopTranscode(stream)
-		sm.removeTranscodeFiles(stream)
-
 		delete(sm.runningStreams, stream.dir)
+		sm.finishStream(stream)
 		return
 	}
 
@@ -845,7 +1016,8 @@ func (sm *StreamManager) removeTranscodeFiles(stream *runningStream) {
 	}
 }
 
-// stopAndRemoveAll stops all current streams and removes all cache files
+// stopAndRemoveAll stops all current streams and removes all cache files,
+// other than the segments kept in the segment cache
 func (sm *StreamManager) stopAndRemoveAll() {
 	sm.streamsMutex.Lock()
 	defer sm.streamsMutex.Unlock()
@@ -857,7 +1029,7 @@ func (sm *StreamManager) stopAndRemoveAll() {
 			}
 		}
 		sm.stopTranscode(stream)
-		sm.removeTranscodeFiles(stream)
+		sm.finishStream(stream)
 	}
 
 	// ensure nothing else can use the map
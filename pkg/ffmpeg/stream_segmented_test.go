@@ -0,0 +1,70 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLSRenditions(t *testing.T) {
+	const (
+		original   = models.StreamingResolutionEnumOriginal
+		standard   = models.StreamingResolutionEnumStandard
+		standardHd = models.StreamingResolutionEnumStandardHd
+		fullHd     = models.StreamingResolutionEnumFullHd
+		low        = models.StreamingResolutionEnumLow
+	)
+
+	tests := []struct {
+		name             string
+		width            int
+		height           int
+		maxStreamingSize models.StreamingResolutionEnum
+		want             []models.StreamingResolutionEnum
+	}{
+		{"1080p", 1920, 1080, original, []models.StreamingResolutionEnum{standard, standardHd, original}},
+		{"4k", 3840, 2160, original, []models.StreamingResolutionEnum{standard, standardHd, fullHd, original}},
+		{"portrait 1080p", 1080, 1920, original, []models.StreamingResolutionEnum{standard, standardHd, original}},
+		{"480p", 854, 480, original, []models.StreamingResolutionEnum{original}},
+		{"max 720p", 1920, 1080, standardHd, []models.StreamingResolutionEnum{standard, standardHd}},
+		{"max above source", 1280, 720, fullHd, []models.StreamingResolutionEnum{standard, original}},
+		{"max 240p", 1920, 1080, low, []models.StreamingResolutionEnum{low}},
+		{"unknown size", 0, 0, original, []models.StreamingResolutionEnum{original}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vf := &models.VideoFile{
+				Width:     tt.width,
+				Height:    tt.height,
+				FrameRate: 30,
+			}
+
+			renditions := hlsRenditions(vf, tt.maxStreamingSize)
+
+			var got []models.StreamingResolutionEnum
+			for i, r := range renditions {
+				got = append(got, r.resolution)
+				if i > 0 {
+					assert.Greater(t, r.bandwidth, renditions[i-1].bandwidth)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScaleToMax(t *testing.T) {
+	w, h := scaleToMax(1920, 1080, 720)
+	assert.Equal(t, 1280, w)
+	assert.Equal(t, 720, h)
+
+	w, h = scaleToMax(1080, 1920, 480)
+	assert.Equal(t, 480, w)
+	assert.Equal(t, 853, h)
+
+	w, h = scaleToMax(1920, 1080, 0)
+	assert.Equal(t, 1920, w)
+	assert.Equal(t, 1080, h)
+}
@@ -1,6 +1,7 @@
 package ffmpeg
 
 import (
+	"bufio"
 	"errors"
 	"io"
 	"net/http"
@@ -13,14 +14,20 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 )
 
+// ErrCodecNotSupported is returned when no encoder is available for the
+// requested stream format.
+var ErrCodecNotSupported = errors.New("no encoder available for the requested codec")
+
 type StreamFormat struct {
 	MimeType string
-	Args     func(codec VideoCodec, videoFilter VideoFilter, videoOnly bool) Args
+	// Args returns the output arguments of the stream. codecArgs selects the
+	// video codec and its encoding options.
+	Args func(codecArgs Args, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool) Args
 }
 
-func CodecInit(codec VideoCodec) (args Args) {
-	args = args.VideoCodec(codec)
-
+// DefaultCodecArgs returns the built-in encoding options used for codec when
+// live transcoding, not including the codec itself.
+func DefaultCodecArgs(codec VideoCodec) (args Args) {
 	switch codec {
 	// CPU Codecs
 	case VideoCodecLibX264:
@@ -39,6 +46,13 @@ func CodecInit(codec VideoCodec) (args Args) {
 			"-crf", "30",
 			"-b:v", "0",
 		)
+	case VideoCodecSVTAV1:
+		args = append(args,
+			"-pix_fmt", "yuv420p",
+			"-preset", "10",
+			"-crf", "35",
+			"-g", "120",
+		)
 	// HW Codecs
 	case VideoCodecN264:
 		args = append(args,
@@ -76,6 +90,20 @@ func CodecInit(codec VideoCodec) (args Args) {
 		args = append(args,
 			"-qp", "20",
 		)
+	case VideoCodecNAV1:
+		args = append(args,
+			"-rc", "vbr",
+			"-cq", "30",
+		)
+	case VideoCodecIAV1:
+		args = append(args,
+			"-global_quality", "25",
+			"-preset", "faster",
+		)
+	case VideoCodecVAV1:
+		args = append(args,
+			"-global_quality", "25",
+		)
 	}
 
 	return args
@@ -84,27 +112,34 @@ func CodecInit(codec VideoCodec) (args Args) {
 var (
 	StreamTypeMP4 = StreamFormat{
 		MimeType: MimeMp4Video,
-		Args: func(codec VideoCodec, videoFilter VideoFilter, videoOnly bool) (args Args) {
-			args = CodecInit(codec)
+		Args: func(codecArgs Args, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool) (args Args) {
+			args = append(args, codecArgs...)
 			args = append(args, "-movflags", "frag_keyframe+empty_moov")
 			args = args.VideoFilter(videoFilter)
 			if videoOnly {
 				args = args.SkipAudio()
 			} else {
+				args = args.AudioFilter(audioFilter)
 				args = append(args, "-ac", "2")
 			}
 			args = args.Format(FormatMP4)
 			return
 		},
 	}
+	// StreamTypeMP4AV1 is a fragmented MP4 stream encoded with AV1.
+	StreamTypeMP4AV1 = StreamFormat{
+		MimeType: MimeMp4AV1Video,
+		Args:     StreamTypeMP4.Args,
+	}
 	StreamTypeWEBM = StreamFormat{
 		MimeType: MimeWebmVideo,
-		Args: func(codec VideoCodec, videoFilter VideoFilter, videoOnly bool) (args Args) {
-			args = CodecInit(codec)
+		Args: func(codecArgs Args, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool) (args Args) {
+			args = append(args, codecArgs...)
 			args = args.VideoFilter(videoFilter)
 			if videoOnly {
 				args = args.SkipAudio()
 			} else {
+				args = args.AudioFilter(audioFilter)
 				args = append(args, "-ac", "2")
 			}
 			args = args.Format(FormatWebm)
@@ -113,12 +148,13 @@ var (
 	}
 	StreamTypeMKV = StreamFormat{
 		MimeType: MimeMkvVideo,
-		Args: func(codec VideoCodec, videoFilter VideoFilter, videoOnly bool) (args Args) {
-			args = CodecInit(codec)
+		Args: func(codecArgs Args, videoFilter VideoFilter, audioFilter AudioFilter, videoOnly bool) (args Args) {
+			args = append(args, codecArgs...)
 			if videoOnly {
 				args = args.SkipAudio()
 			} else {
 				args = args.AudioCodec(AudioCodecLibOpus)
+				args = args.AudioFilter(audioFilter)
 				args = append(args,
 					"-b:a", "96k",
 					"-vbr", "on",
@@ -140,6 +176,11 @@ type TranscodeOptions struct {
 
 func FileGetCodec(sm *StreamManager, mimetype string) (codec VideoCodec) {
 	switch mimetype {
+	case MimeMp4AV1Video:
+		// empty if no AV1 encoder is available
+		if av1Codec := sm.encoder.AV1Codec(sm.config.GetTranscodeHardwareAcceleration()); av1Codec != nil {
+			codec = *av1Codec
+		}
 	case MimeMp4Video:
 		codec = VideoCodecLibX264
 		if hwcodec := sm.encoder.hwCodecMP4Compatible(); hwcodec != nil && sm.config.GetTranscodeHardwareAcceleration() {
@@ -157,7 +198,21 @@ func FileGetCodec(sm *StreamManager, mimetype string) (codec VideoCodec) {
 	return codec
 }
 
-func (o TranscodeOptions) makeStreamArgs(sm *StreamManager) Args {
+// fileSoftwareCodec returns the software codec used for mimetype if
+// hardware encoding fails.
+func fileSoftwareCodec(mimetype string) VideoCodec {
+	switch mimetype {
+	case MimeMp4Video:
+		return VideoCodecLibX264
+	case MimeMp4AV1Video:
+		return VideoCodecSVTAV1
+	case MimeWebmVideo:
+		return VideoCodecVP9
+	}
+	return VideoCodecCopy
+}
+
+func (o TranscodeOptions) makeStreamArgs(sm *StreamManager, codec VideoCodec) Args {
 	maxTranscodeSize := sm.config.GetMaxStreamingTranscodeSize().GetMaxResolution()
 	if o.Resolution != "" {
 		maxTranscodeSize = models.StreamingResolutionEnum(o.Resolution).GetMaxResolution()
@@ -168,8 +223,6 @@ func (o TranscodeOptions) makeStreamArgs(sm *StreamManager) Args {
 	args := Args{"-hide_banner"}
 	args = args.LogLevel(LogLevelError)
 
-	codec := FileGetCodec(sm, o.StreamType.MimeType)
-
 	args = sm.encoder.hwDeviceInit(args, codec)
 	args = append(args, extraInputArgs...)
 
@@ -181,9 +234,10 @@ func (o TranscodeOptions) makeStreamArgs(sm *StreamManager) Args {
 
 	videoOnly := ProbeAudioCodec(o.VideoFile.AudioCodec) == MissingUnsupported
 
-	videoFilter := sm.encoder.hwMaxResFilter(codec, o.VideoFile.Width, o.VideoFile.Height, maxTranscodeSize)
+	videoFilter := sm.liveTranscodeVideoFilter(o.VideoFile, codec, maxTranscodeSize)
+	audioFilter := sm.liveTranscodeAudioFilter()
 
-	args = append(args, o.StreamType.Args(codec, videoFilter, videoOnly)...)
+	args = append(args, o.StreamType.Args(sm.encoder.CodecInit(codec), videoFilter, audioFilter, videoOnly)...)
 
 	args = append(args, extraOutputArgs...)
 
@@ -215,7 +269,55 @@ func (sm *StreamManager) ServeTranscode(w http.ResponseWriter, r *http.Request,
 }
 
 func (sm *StreamManager) getTranscodeStream(ctx *fsutil.LockContext, options TranscodeOptions) (http.HandlerFunc, error) {
-	args := options.makeStreamArgs(sm)
+	codec := FileGetCodec(sm, options.StreamType.MimeType)
+	if codec == "" {
+		return nil, ErrCodecNotSupported
+	}
+	if IsHWCodec(codec) && sm.liveTranscodeToneMapFilter(options.VideoFile) != "" {
+		codec = fileSoftwareCodec(options.StreamType.MimeType)
+	}
+
+	stdout, err := sm.startTranscodeStream(ctx, options, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsHWCodec(codec) {
+		// fall back to software encoding if the hardware encoder fails
+		// before producing any output
+		if _, err := stdout.Peek(1); err != nil && ctx.Err() == nil {
+			swCodec := fileSoftwareCodec(options.StreamType.MimeType)
+			logger.Warnf("[transcode] hardware encoding with %s failed for %s, falling back to %s", codec, options.VideoFile.Path, swCodec)
+
+			stdout, err = sm.startTranscodeStream(ctx, options, swCodec)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	mimeType := options.StreamType.MimeType
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", mimeType)
+		w.WriteHeader(http.StatusOK)
+
+		// process killing should be handled by command context
+
+		_, err := io.Copy(w, stdout)
+		if err != nil && !errors.Is(err, syscall.EPIPE) && !errors.Is(err, syscall.ECONNRESET) {
+			logger.Errorf("[transcode] error serving transcoded video file: %v", err)
+		}
+
+		w.(http.Flusher).Flush()
+	}
+	return handler, nil
+}
+
+// startTranscodeStream starts an ffmpeg process transcoding to stdout with
+// codec, returning a reader for its output.
+func (sm *StreamManager) startTranscodeStream(ctx *fsutil.LockContext, options TranscodeOptions, codec VideoCodec) (*bufio.Reader, error) {
+	args := options.makeStreamArgs(sm, codec)
 	cmd := sm.encoder.Command(ctx, args)
 
 	stdout, err := cmd.StdoutPipe()
@@ -257,20 +359,5 @@ func (sm *StreamManager) getTranscodeStream(ctx *fsutil.LockContext, options Tra
 		}
 	}()
 
-	mimeType := options.StreamType.MimeType
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Cache-Control", "no-store")
-		w.Header().Set("Content-Type", mimeType)
-		w.WriteHeader(http.StatusOK)
-
-		// process killing should be handled by command context
-
-		_, err := io.Copy(w, stdout)
-		if err != nil && !errors.Is(err, syscall.EPIPE) && !errors.Is(err, syscall.ECONNRESET) {
-			logger.Errorf("[transcode] error serving transcoded video file: %v", err)
-		}
-
-		w.(http.Flusher).Flush()
-	}
-	return handler, nil
+	return bufio.NewReader(stdout), nil
 }
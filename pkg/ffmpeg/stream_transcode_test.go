@@ -0,0 +1,111 @@
+package ffmpeg
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscodeOptions_makeStreamArgs_loudnorm(t *testing.T) {
+	withAudio := &models.VideoFile{
+		BaseFile:   &models.BaseFile{Path: "in.mp4"},
+		AudioCodec: "aac",
+		Width:      1920,
+		Height:     1080,
+	}
+	videoOnly := &models.VideoFile{
+		BaseFile: &models.BaseFile{Path: "in.mp4"},
+		Width:    1920,
+		Height:   1080,
+	}
+
+	tests := []struct {
+		name      string
+		config    testStreamConfig
+		vf        *models.VideoFile
+		wantAudio []string
+	}{
+		{"disabled", testStreamConfig{}, withAudio, nil},
+		{"enabled", testStreamConfig{loudnorm: true, loudnormTarget: -16}, withAudio, []string{"-af", "loudnorm=I=-16:TP=-1.5:LRA=11", "-ar", "48000"}},
+		{"video only", testStreamConfig{loudnorm: true, loudnormTarget: -16}, videoOnly, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &StreamManager{
+				encoder: &FFMpeg{},
+				config:  tt.config,
+			}
+
+			o := TranscodeOptions{
+				StreamType: StreamTypeMP4,
+				VideoFile:  tt.vf,
+			}
+
+			args := o.makeStreamArgs(sm, VideoCodecLibX264)
+
+			var got []string
+			for i, a := range args {
+				if (a == "-af" || a == "-ar") && i+1 < len(args) {
+					got = append(got, a, args[i+1])
+				}
+			}
+
+			assert.Equal(t, tt.wantAudio, got)
+		})
+	}
+}
+
+func TestTranscodeOptions_makeStreamArgs_toneMap(t *testing.T) {
+	const toneMap = VideoFilter("tonemap")
+
+	sdr := &models.VideoFile{
+		BaseFile:      &models.BaseFile{Path: "in.mp4"},
+		Width:         1920,
+		Height:        1080,
+		ColorTransfer: "bt709",
+	}
+	hdr := &models.VideoFile{
+		BaseFile:      &models.BaseFile{Path: "in.mp4"},
+		Width:         1920,
+		Height:        1080,
+		ColorTransfer: ColorTransferPQ,
+	}
+
+	tests := []struct {
+		name          string
+		config        testStreamConfig
+		toneMapFilter VideoFilter
+		vf            *models.VideoFile
+		wantVideo     []string
+	}{
+		{"sdr", testStreamConfig{toneMapping: true}, toneMap, sdr, nil},
+		{"hdr", testStreamConfig{toneMapping: true}, toneMap, hdr, []string{"-vf", "tonemap"}},
+		{"disabled", testStreamConfig{}, toneMap, hdr, nil},
+		{"not supported", testStreamConfig{toneMapping: true}, "", hdr, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := &StreamManager{
+				encoder: &FFMpeg{toneMapFilter: tt.toneMapFilter},
+				config:  tt.config,
+			}
+
+			o := TranscodeOptions{
+				StreamType: StreamTypeMP4,
+				VideoFile:  tt.vf,
+			}
+
+			args := o.makeStreamArgs(sm, VideoCodecLibX264)
+
+			var got []string
+			for i, a := range args {
+				if a == "-vf" && i+1 < len(args) {
+					got = []string{a, args[i+1]}
+				}
+			}
+
+			assert.Equal(t, tt.wantVideo, got)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// ColorTransferPQ is the transfer characteristics of HDR10 and Dolby
+	// Vision video.
+	ColorTransferPQ = "smpte2084"
+	// ColorTransferHLG is the transfer characteristics of HLG video.
+	ColorTransferHLG = "arib-std-b67"
+)
+
+// IsHDRTransfer returns true if colorTransfer is the transfer
+// characteristics of HDR video.
+func IsHDRTransfer(colorTransfer string) bool {
+	return colorTransfer == ColorTransferPQ || colorTransfer == ColorTransferHLG
+}
+
+// toneMapFilters are the filters that map HDR video to SDR bt709 video, in
+// order of preference. zscale requires ffmpeg to be built with libzimg, and
+// libplacebo requires ffmpeg to be built with libplacebo and a Vulkan device.
+var toneMapFilters = []VideoFilter{
+	"zscale=t=linear:npl=100,format=gbrpf32le,zscale=p=bt709,tonemap=tonemap=hable:desat=0,zscale=t=bt709:m=bt709:r=tv,format=yuv420p",
+	"libplacebo=tonemapping=auto:colorspace=bt709:color_primaries=bt709:color_trc=bt709:range=tv:format=yuv420p",
+}
+
+// initToneMapSupport finds the first tone mapping filter supported by
+// ffmpeg.
+func (f *FFMpeg) initToneMapSupport(ctx context.Context) {
+	var supported VideoFilter
+	for _, filter := range toneMapFilters {
+		if err := f.testToneMapFilter(ctx, filter); err != nil {
+			logger.Debugf("[InitHWSupport] Tone mapping filter %s not supported. Error output:\n%s", filter, err)
+			continue
+		}
+
+		supported = filter
+		break
+	}
+
+	if supported == "" {
+		logger.Info("[InitHWSupport] No supported tone mapping filter found. HDR video will not be tone mapped.")
+	} else {
+		logger.Infof("[InitHWSupport] Supported tone mapping filter: %s", supported)
+	}
+
+	f.hwMutex.Lock()
+	f.toneMapFilter = supported
+	f.hwMutex.Unlock()
+}
+
+// testToneMapFilter tone maps a short HDR10 test video with filter,
+// returning an error containing the ffmpeg output if it fails.
+func (f *FFMpeg) testToneMapFilter(ctx context.Context, filter VideoFilter) error {
+	var args Args
+	args = append(args, "-hide_banner")
+	args = args.LogLevel(LogLevelWarning)
+	args = args.Format("lavfi")
+	args = args.Input("color=c=red:s=64x64")
+	args = args.Duration(0.1)
+
+	var videoFilter VideoFilter
+	videoFilter = videoFilter.Append("format=yuv420p10le")
+	videoFilter = videoFilter.Append("setparams=color_primaries=bt2020:color_trc=" + ColorTransferPQ + ":colorspace=bt2020nc")
+	videoFilter = videoFilter.Append(string(filter))
+	args = args.VideoFilter(videoFilter)
+
+	args = args.Format("null")
+	args = args.Output("-")
+
+	cmd := f.Command(ctx, args)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting command: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		errOutput := stderr.String()
+
+		if len(errOutput) == 0 {
+			errOutput = err.Error()
+		}
+
+		return errors.New(errOutput)
+	}
+
+	return nil
+}
+
+// ToneMapFilter returns the filter used to tone map HDR video to SDR, or an
+// empty filter if none are supported.
+func (f *FFMpeg) ToneMapFilter() VideoFilter {
+	f.hwMutex.RLock()
+	defer f.hwMutex.RUnlock()
+
+	return f.toneMapFilter
+}
@@ -14,15 +14,23 @@ type ImageThumbnailOptions struct {
 	OutputPath    string
 	MaxDimensions int
 	Quality       int
+
+	// HWDeviceArgs initialise the hardware device used by VideoFilter.
+	HWDeviceArgs ffmpeg.Args
+	// VideoFilter replaces the default scaling filter if set.
+	VideoFilter ffmpeg.VideoFilter
 }
 
 func ImageThumbnail(input string, options ImageThumbnailOptions) ffmpeg.Args {
-	var videoFilter ffmpeg.VideoFilter
-	videoFilter = videoFilter.ScaleMaxSize(options.MaxDimensions)
+	videoFilter := options.VideoFilter
+	if videoFilter == "" {
+		videoFilter = videoFilter.ScaleMaxSize(options.MaxDimensions)
+	}
 
 	var args ffmpeg.Args
 	args = append(args, "-hide_banner")
 	args = args.LogLevel(ffmpeg.LogLevelError)
+	args = append(args, options.HWDeviceArgs...)
 
 	args = args.Overwrite().
 		ImageFormat(options.InputFormat).
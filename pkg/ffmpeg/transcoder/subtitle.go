@@ -0,0 +1,23 @@
+package transcoder
+
+import (
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+)
+
+// ExtractSubtitle returns the arguments to convert the subtitle track with
+// the given index of the input to WebVTT. The index is relative to the
+// subtitle streams of the input.
+func ExtractSubtitle(input string, track int, outputPath string) ffmpeg.Args {
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError)
+	args = args.Overwrite()
+	args = args.Input(input)
+
+	args = append(args, "-map", fmt.Sprintf("0:s:%d", track))
+	args = args.Format(ffmpeg.FormatWebVTT)
+	args = args.Output(outputPath)
+
+	return args
+}
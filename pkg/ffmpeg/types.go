@@ -48,6 +48,10 @@ type FFProbeStream struct {
 	CodecType          string `json:"codec_type"`
 	CodedHeight        int    `json:"coded_height,omitempty"`
 	CodedWidth         int    `json:"coded_width,omitempty"`
+	ColorPrimaries     string `json:"color_primaries,omitempty"`
+	ColorRange         string `json:"color_range,omitempty"`
+	ColorSpace         string `json:"color_space,omitempty"`
+	ColorTransfer      string `json:"color_transfer,omitempty"`
 	DisplayAspectRatio string `json:"display_aspect_ratio,omitempty"`
 	Disposition        struct {
 		AttachedPic     int `json:"attached_pic"`
@@ -65,6 +69,7 @@ type FFProbeStream struct {
 	} `json:"disposition"`
 	Duration          string `json:"duration"`
 	DurationTs        int    `json:"duration_ts"`
+	FieldOrder        string `json:"field_order,omitempty"`
 	HasBFrames        int    `json:"has_b_frames,omitempty"`
 	Height            int    `json:"height,omitempty"`
 	Index             int    `json:"index"`
@@ -85,6 +90,7 @@ type FFProbeStream struct {
 		HandlerName  string        `json:"handler_name"`
 		Language     string        `json:"language"`
 		Rotate       string        `json:"rotate"`
+		Title        string        `json:"title"`
 	} `json:"tags"`
 	TimeBase      string `json:"time_base"`
 	Width         int    `json:"width,omitempty"`
@@ -0,0 +1,349 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var errNotReaderAt = errors.New("not a ReaderAt")
+
+var (
+	rarSignature      = []byte("Rar!\x1a\x07")
+	sevenZipSignature = []byte("7z\xbc\xaf\x27\x1c")
+)
+
+// openArchive returns a ZipFS of the contents of the archive at path. Zip,
+// RAR and 7z archives are supported. The format is detected from the
+// contents of the file, so that archives are read correctly regardless of
+// their extension. Files that are not RAR or 7z archives are read as zip
+// files.
+func openArchive(fsys models.FS, path string, info fs.FileInfo) (*zipFS, error) {
+	reader, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	asReaderAt, _ := reader.(io.ReaderAt)
+	if asReaderAt == nil {
+		reader.Close()
+		return nil, errNotReaderAt
+	}
+
+	header := make([]byte, len(sevenZipSignature))
+	n, err := asReaderAt.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		reader.Close()
+		return nil, err
+	}
+	header = header[:n]
+
+	switch {
+	case bytes.HasPrefix(header, rarSignature):
+		// RAR archives are read by name, so that multi-volume archives are
+		// supported
+		reader.Close()
+		return newRarFS(fsys, path, info)
+	case bytes.HasPrefix(header, sevenZipSignature):
+		return newSevenZipFS(reader, asReaderAt, path, info)
+	default:
+		return newZipFS(reader, asReaderAt, path, info)
+	}
+}
+
+// OpenArchive returns a ZipFS of the contents of the archive at path in fsys.
+// File systems that are not backed by the OS use this to implement OpenZip.
+// The file returned by fsys.Open must implement io.ReaderAt.
+func OpenArchive(fsys models.FS, path string, info fs.FileInfo) (models.ZipFS, error) {
+	ret, err := openArchive(fsys, path, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// OpenNestedArchive returns a ZipFS of the contents of the archive at path,
+// which is within another archive in fsys. The archive containing it is
+// found by walking up the path until an existing file is found.
+func OpenNestedArchive(fsys models.FS, path string) (models.ZipFS, error) {
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		info, err := fsys.Lstat(dir)
+		if err != nil {
+			if isNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if info.IsDir() {
+			break
+		}
+
+		outer, err := fsys.OpenZip(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		inner, err := outer.OpenZip(path)
+		if err != nil {
+			outer.Close()
+			return nil, err
+		}
+
+		return &nestedZipFS{ZipFS: inner, outer: outer}, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+}
+
+// nestedZipFS is an archive within another archive. Closing it closes the
+// archive containing it.
+type nestedZipFS struct {
+	models.ZipFS
+	outer io.Closer
+}
+
+func (f *nestedZipFS) Close() error {
+	_ = f.ZipFS.Close()
+	return f.outer.Close()
+}
+
+// OpenOnly returns a ReadCloser where calling Close will close the archives
+// as well.
+func (f *nestedZipFS) OpenOnly(name string) (io.ReadCloser, error) {
+	r, err := f.ZipFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrappedReadCloser{
+		ReadCloser: r,
+		outer:      f,
+	}, nil
+}
+
+// defaultMaxNestedArchiveSize is the maximum size of a nested archive that
+// is extracted if no maximum has been set.
+const defaultMaxNestedArchiveSize = 4 << 30
+
+var errNestedArchiveTooLarge = errors.New("nested archive is too large to extract")
+
+var (
+	maxNestedArchiveSize      int64 = defaultMaxNestedArchiveSize
+	maxNestedArchiveSizeMutex sync.RWMutex
+)
+
+// SetMaxNestedArchiveSize sets the maximum size in bytes of an archive
+// within another archive that is extracted to be read. Larger archives are
+// not opened. The default is used if size is not positive.
+func SetMaxNestedArchiveSize(size int64) {
+	maxNestedArchiveSizeMutex.Lock()
+	defer maxNestedArchiveSizeMutex.Unlock()
+
+	if size <= 0 {
+		size = defaultMaxNestedArchiveSize
+	}
+	maxNestedArchiveSize = size
+}
+
+func getMaxNestedArchiveSize() int64 {
+	maxNestedArchiveSizeMutex.RLock()
+	defer maxNestedArchiveSizeMutex.RUnlock()
+
+	return maxNestedArchiveSize
+}
+
+// extractedKey identifies an archive entry that has been extracted. The
+// size and modification time of the archive containing it are included,
+// so that an archive that has changed is extracted again.
+type extractedKey struct {
+	zipPath    string
+	zipSize    int64
+	zipModTime time.Time
+	name       string
+}
+
+// extractedEntry is an archive entry that has been extracted to a temporary
+// file. The file is removed once refs drops to zero.
+type extractedEntry struct {
+	tmpPath string
+	refs    int
+}
+
+// extracted holds the archive entries that are currently extracted, so that
+// an archive within another archive is only extracted once while it is open,
+// no matter how many times it is opened during a walk or scan.
+var (
+	extracted      = make(map[extractedKey]*extractedEntry)
+	extractedMutex sync.Mutex
+)
+
+// acquireEntry returns the path to the temporary file that the file name
+// in the archive f is extracted to, extracting it if it is not already
+// extracted. releaseEntry must be called with the returned key once the
+// file is no longer needed.
+func (f *zipFS) acquireEntry(name string, info fs.FileInfo) (string, extractedKey, error) {
+	key := extractedKey{
+		zipPath:    f.zipPath,
+		zipSize:    f.zipInfo.Size(),
+		zipModTime: f.zipInfo.ModTime(),
+		name:       name,
+	}
+
+	extractedMutex.Lock()
+	defer extractedMutex.Unlock()
+
+	if e := extracted[key]; e != nil {
+		e.refs++
+		return e.tmpPath, key, nil
+	}
+
+	tmpPath, err := f.extractEntry(name, info)
+	if err != nil {
+		return "", key, err
+	}
+
+	extracted[key] = &extractedEntry{tmpPath: tmpPath, refs: 1}
+	return tmpPath, key, nil
+}
+
+// releaseEntry releases a reference to an extracted entry, removing the
+// temporary file if it is no longer referenced.
+func releaseEntry(key extractedKey) error {
+	extractedMutex.Lock()
+	defer extractedMutex.Unlock()
+
+	e := extracted[key]
+	if e == nil {
+		return nil
+	}
+
+	e.refs--
+	if e.refs > 0 {
+		return nil
+	}
+
+	delete(extracted, key)
+	return os.Remove(e.tmpPath)
+}
+
+// extractEntry copies the file name in the archive f to a temporary file,
+// so that an archive within it can be opened without reading it into memory.
+// Files larger than the maximum nested archive size are not extracted.
+// The caller must remove the returned file.
+func (f *zipFS) extractEntry(name string, info fs.FileInfo) (string, error) {
+	maxSize := getMaxNestedArchiveSize()
+	if info.Size() > maxSize {
+		return "", fmt.Errorf("extracting %q: %w", name, errNestedArchiveTooLarge)
+	}
+
+	r, err := f.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp("", "stash-archive-*")
+	if err != nil {
+		return "", fmt.Errorf("creating temporary file: %w", err)
+	}
+
+	// the reported size is not trusted, so the copy is limited as well
+	n, err := io.Copy(tmp, io.LimitReader(r, maxSize+1))
+	if err == nil && n > maxSize {
+		err = errNestedArchiveTooLarge
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("extracting %q: %w", name, err)
+	}
+
+	return tmp.Name(), nil
+}
+
+// entryFS opens the archive entry name from the temporary file it was
+// extracted to.
+type entryFS struct {
+	name    string
+	tmpPath string
+	info    fs.FileInfo
+}
+
+func (f *entryFS) Open(name string) (fs.ReadDirFile, error) {
+	if name != f.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	file, err := os.Open(f.tmpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entryFile{File: file, info: f.info}, nil
+}
+
+func (f *entryFS) Stat(name string) (fs.FileInfo, error) {
+	if name != f.name {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return f.info, nil
+}
+
+func (f *entryFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *entryFS) OpenZip(name string) (models.ZipFS, error) {
+	return nil, errors.New("not supported")
+}
+
+func (f *entryFS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+// entryFile is an extracted archive entry, which reports the info of the
+// entry.
+type entryFile struct {
+	*os.File
+	info fs.FileInfo
+}
+
+func (f *entryFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// releaseCloser releases the extracted entry after closing the Closer.
+type releaseCloser struct {
+	io.Closer
+	key extractedKey
+}
+
+func (c *releaseCloser) Close() error {
+	err := c.Closer.Close()
+	if releaseErr := releaseEntry(c.key); err == nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// stdFS adapts a models.FS to an fs.FS.
+type stdFS struct {
+	fs models.FS
+}
+
+func (f stdFS) Open(name string) (fs.File, error) {
+	return f.fs.Open(name)
+}
@@ -0,0 +1,371 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testArchiveFiles are the contents of the test archives
+var testArchiveFiles = []struct {
+	name     string
+	contents string
+}{
+	{"a.jpg", "image1"},
+	{"sub/b.jpg", "image2"},
+}
+
+// test7z is a 7z archive of testArchiveFiles, created using:
+// bsdtar --format 7zip --options 7zip:compression=store -cf test.7z a.jpg sub
+const test7z = "N3q8ryccAAPafc/UDAAAAAAAAAC6AAAAAAAAAJ0zGqNpbWFnZTFpbWFnZTIBBAYAAgkGBgAHCwIAAQEAAQEADAYGAAgKAdaezXhsz8ThAAAFAw4BIBEpAGEALgBqAHAAZwAAAHMAdQBiAC8AYgAuAGoAcABnAAAAcwB1AGIAAAAUGgEAgACC31Ye2QGAAILfVh7ZAYAAgt9WHtkBEhoBAKUEktKuXN0BpQSS0q5c3QGlBJLSrlzdARMaAQCAAILfVh7ZAYAAgt9WHtkBgACC31Ye2QEVDgEAIICkgSCApIEQgO1BAAA="
+
+func testZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for _, f := range testArchiveFiles {
+		w, err := z.Create(f.name)
+		if err != nil {
+			t.Fatalf("creating zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(f.contents)); err != nil {
+			t.Fatalf("writing zip entry: %v", err)
+		}
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// testRar returns a RAR 1.5 archive of testArchiveFiles, with the files
+// stored uncompressed.
+func testRar(solid bool) []byte {
+	var buf bytes.Buffer
+
+	writeBlock := func(htype byte, flags uint16, fields []byte) {
+		header := make([]byte, 5, 7+len(fields))
+		header[0] = htype
+		binary.LittleEndian.PutUint16(header[1:], flags)
+		binary.LittleEndian.PutUint16(header[3:], uint16(7+len(fields)))
+		header = append(header, fields...)
+
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(crc32.ChecksumIEEE(header)))
+		buf.Write(header)
+	}
+
+	const (
+		blockArc      = 0x73
+		blockFile     = 0x74
+		blockEnd      = 0x7b
+		blockHasData  = 0x8000
+		arcSolid      = 0x0008
+		fileSolid     = 0x0010
+		hostOSUnix    = 3
+		methodStore   = 0x30
+		unpackVersion = 20
+		// 2023-01-02 03:04:04
+		dosTime = (2023-1980)<<25 | 1<<21 | 2<<16 | 3<<11 | 4<<5 | 2
+	)
+
+	buf.WriteString("Rar!\x1a\x07\x00")
+
+	var arcFlags uint16
+	if solid {
+		arcFlags |= arcSolid
+	}
+	writeBlock(blockArc, arcFlags, make([]byte, 6))
+
+	for i, f := range testArchiveFiles {
+		data := []byte(f.contents)
+
+		var fields bytes.Buffer
+		for _, v := range []interface{}{
+			uint32(len(data)),
+			uint32(len(data)),
+			byte(hostOSUnix),
+			crc32.ChecksumIEEE(data),
+			uint32(dosTime),
+			byte(unpackVersion),
+			byte(methodStore),
+			uint16(len(f.name)),
+			uint32(0100644),
+		} {
+			_ = binary.Write(&fields, binary.LittleEndian, v)
+		}
+		fields.WriteString(f.name)
+
+		flags := uint16(blockHasData)
+		if solid && i > 0 {
+			flags |= fileSolid
+		}
+		writeBlock(blockFile, flags, fields.Bytes())
+		buf.Write(data)
+	}
+
+	writeBlock(blockEnd, 0, nil)
+
+	return buf.Bytes()
+}
+
+func testArchiveFS(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(archivePath, data, 0644); err != nil {
+		t.Fatalf("writing archive: %v", err)
+	}
+
+	zfs, err := (&OsFS{}).OpenZip(archivePath)
+	if err != nil {
+		t.Fatalf("OpenZip: %v", err)
+	}
+	defer zfs.Close()
+
+	root, err := zfs.Open(archivePath)
+	if err != nil {
+		t.Fatalf("opening root: %v", err)
+	}
+	entries, err := root.ReadDir(-1)
+	root.Close()
+	if err != nil {
+		t.Fatalf("reading root: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"a.jpg", "sub"}, names)
+
+	subInfo, err := zfs.Stat(filepath.Join(archivePath, "sub"))
+	if err != nil {
+		t.Fatalf("stat sub: %v", err)
+	}
+	assert.True(t, subInfo.IsDir())
+
+	for _, f := range testArchiveFiles {
+		p := filepath.Join(archivePath, filepath.FromSlash(f.name))
+
+		info, err := zfs.Stat(p)
+		if err != nil {
+			t.Errorf("stat %s: %v", f.name, err)
+			continue
+		}
+		assert.Equal(t, int64(len(f.contents)), info.Size(), f.name)
+		assert.True(t, info.Mode().IsRegular(), f.name)
+
+		r, err := zfs.Open(p)
+		if err != nil {
+			t.Errorf("open %s: %v", f.name, err)
+			continue
+		}
+		contents, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Errorf("read %s: %v", f.name, err)
+			continue
+		}
+		assert.Equal(t, f.contents, string(contents), f.name)
+	}
+
+	_, err = zfs.Stat(filepath.Join(archivePath, "missing.jpg"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestArchiveFS(t *testing.T) {
+	sevenZip, err := base64.StdEncoding.DecodeString(test7z)
+	if err != nil {
+		t.Fatalf("decoding 7z: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		// archive name. The format is detected from the contents.
+		filename string
+		data     []byte
+	}{
+		{"zip", "test.zip", testZip(t)},
+		{"7z", "test.cb7", sevenZip},
+		{"rar", "test.cbr", testRar(false)},
+		{"solid rar", "test.rar", testRar(true)},
+		{"rar with zip extension", "test.zip", testRar(false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testArchiveFS(t, tt.filename, tt.data)
+		})
+	}
+}
+
+// wrapZip returns a zip file containing a single file with the name and data.
+func wrapZip(t *testing.T, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	w, err := z.Create(name)
+	if err != nil {
+		t.Fatalf("creating zip entry: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("writing zip entry: %v", err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestNestedArchiveFS(t *testing.T) {
+	// outer.zip/middle.zip/inner.zip/deep.zip
+	inner := wrapZip(t, "deep.zip", testZip(t))
+	middle := wrapZip(t, "inner.zip", inner)
+	outer := wrapZip(t, "middle.zip", middle)
+
+	outerPath := filepath.Join(t.TempDir(), "outer.zip")
+	if err := os.WriteFile(outerPath, outer, 0644); err != nil {
+		t.Fatalf("writing archive: %v", err)
+	}
+
+	middlePath := filepath.Join(outerPath, "middle.zip")
+	innerPath := filepath.Join(middlePath, "inner.zip")
+	deepPath := filepath.Join(innerPath, "deep.zip")
+
+	// nested archives are extracted to temporary files
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	osFS := &OsFS{}
+
+	// open from the containing archive
+	zfs, err := osFS.OpenZip(outerPath)
+	if err != nil {
+		t.Fatalf("OpenZip: %v", err)
+	}
+	mfs, err := zfs.OpenZip(middlePath)
+	if err != nil {
+		t.Fatalf("OpenZip middle: %v", err)
+	}
+	info, err := mfs.Stat(innerPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(len(inner)), info.Size())
+	}
+	mfs.Close()
+	zfs.Close()
+
+	// open by path
+	ifs, err := osFS.OpenZip(innerPath)
+	if err != nil {
+		t.Fatalf("OpenZip inner: %v", err)
+	}
+	r, err := ifs.OpenOnly(deepPath)
+	if err != nil {
+		t.Fatalf("OpenOnly: %v", err)
+	}
+	contents, err := io.ReadAll(r)
+	r.Close()
+	if assert.NoError(t, err) {
+		assert.Equal(t, testZip(t), contents)
+	}
+
+	// deep.zip is nested too deeply to be opened
+	_, err = osFS.OpenZip(deepPath)
+	assert.ErrorIs(t, err, errZipDepthExceeded)
+
+	_, err = osFS.OpenZip(filepath.Join(middlePath, "missing.zip"))
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// temporary files are removed when the archives are closed
+	entries, err := os.ReadDir(tmpDir)
+	if assert.NoError(t, err) {
+		assert.Len(t, entries, 0)
+	}
+}
+
+func TestNestedArchiveExtractedOnce(t *testing.T) {
+	outer := wrapZip(t, "inner.zip", testZip(t))
+
+	outerPath := filepath.Join(t.TempDir(), "outer.zip")
+	if err := os.WriteFile(outerPath, outer, 0644); err != nil {
+		t.Fatalf("writing archive: %v", err)
+	}
+	innerPath := filepath.Join(outerPath, "inner.zip")
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	countTmp := func() int {
+		entries, err := os.ReadDir(tmpDir)
+		if err != nil {
+			t.Fatalf("reading temporary directory: %v", err)
+		}
+		return len(entries)
+	}
+
+	osFS := &OsFS{}
+
+	zfs, err := osFS.OpenZip(outerPath)
+	if err != nil {
+		t.Fatalf("OpenZip: %v", err)
+	}
+	defer zfs.Close()
+
+	first, err := zfs.OpenZip(innerPath)
+	if err != nil {
+		t.Fatalf("OpenZip inner: %v", err)
+	}
+
+	// opening the archive again while it is open reuses the extraction
+	second, err := osFS.OpenZip(innerPath)
+	if err != nil {
+		t.Fatalf("OpenZip inner by path: %v", err)
+	}
+	assert.Equal(t, 1, countTmp())
+
+	first.Close()
+	assert.Equal(t, 1, countTmp())
+
+	second.Close()
+	assert.Equal(t, 0, countTmp())
+}
+
+func TestNestedArchiveTooLarge(t *testing.T) {
+	inner := testZip(t)
+	outer := wrapZip(t, "inner.zip", inner)
+
+	outerPath := filepath.Join(t.TempDir(), "outer.zip")
+	if err := os.WriteFile(outerPath, outer, 0644); err != nil {
+		t.Fatalf("writing archive: %v", err)
+	}
+	innerPath := filepath.Join(outerPath, "inner.zip")
+
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	SetMaxNestedArchiveSize(int64(len(inner) - 1))
+	defer SetMaxNestedArchiveSize(0)
+
+	_, err := (&OsFS{}).OpenZip(innerPath)
+	assert.ErrorIs(t, err, errNestedArchiveTooLarge)
+
+	entries, err := os.ReadDir(tmpDir)
+	if assert.NoError(t, err) {
+		assert.Len(t, entries, 0)
+	}
+
+	SetMaxNestedArchiveSize(int64(len(inner)))
+
+	zfs, err := (&OsFS{}).OpenZip(innerPath)
+	if assert.NoError(t, err) {
+		zfs.Close()
+	}
+}
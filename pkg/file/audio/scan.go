@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Decorator adds audio specific fields to a File.
+type Decorator struct {
+	FFProbe ffmpeg.FFProbe
+}
+
+func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (models.File, error) {
+	if d.FFProbe == "" {
+		return f, errors.New("ffprobe not configured")
+	}
+
+	base := f.Base()
+	// TODO - copy to temp file if not an OsFS
+	if !file.IsPathFS(fs) {
+		return f, fmt.Errorf("audio.constructFile: only OsFS is supported")
+	}
+
+	probe := d.FFProbe
+	probeFile, err := probe.NewVideoFile(base.Path)
+	if err != nil {
+		return f, fmt.Errorf("running ffprobe on %q: %w", base.Path, err)
+	}
+
+	if probeFile.AudioCodec == "" {
+		return f, fmt.Errorf("no audio stream found in %q", base.Path)
+	}
+
+	return &models.AudioFile{
+		BaseFile:   base,
+		Format:     getFormat(base.Path, probeFile.Container),
+		AudioCodec: probeFile.AudioCodec,
+		Duration:   probeFile.FileDuration,
+		BitRate:    probeFile.Bitrate,
+	}, nil
+}
+
+// getFormat returns the format of the audio file. ffprobe reports several
+// audio containers under a combined name (for example "mov,mp4,m4a"), so the
+// file extension is preferred where present.
+func getFormat(path string, container string) string {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	if ext != "" {
+		return ext
+	}
+
+	return container
+}
+
+func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f models.File) bool {
+	const (
+		unsetString = "unset"
+		unsetNumber = -1
+	)
+
+	af, ok := f.(*models.AudioFile)
+	if !ok {
+		return true
+	}
+
+	return af.AudioCodec == unsetString || af.Format == unsetString ||
+		af.Duration == unsetNumber || af.BitRate == unsetNumber
+}
@@ -7,10 +7,13 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
 )
 
 // Cleaner scans through stored file and folder instances and removes those that are no longer present on disk.
@@ -19,6 +22,9 @@ type Cleaner struct {
 	Repository Repository
 
 	Handlers []CleanHandler
+
+	// PluginCache is used to fire file hooks. Hooks are not fired if nil.
+	PluginCache *plugin.Cache
 }
 
 type cleanJob struct {
@@ -26,6 +32,13 @@ type cleanJob struct {
 
 	progress *job.Progress
 	options  CleanOptions
+
+	links linkSet
+
+	// missing are the files that are cleaned because they no longer exist
+	missing map[models.FileID]models.File
+	// quarantined are the paths of the missing files that were kept
+	quarantined []string
 }
 
 // ScanOptions provides options for scanning files.
@@ -38,6 +51,11 @@ type CleanOptions struct {
 	// PathFilter are used to determine if a file should be included.
 	// Excluded files are marked for cleaning.
 	PathFilter PathFilter
+
+	// Quarantiner is used to keep missing files rather than deleting them.
+	// Files excluded by PathFilter are always deleted. Folders containing
+	// kept files are not deleted.
+	Quarantiner Quarantiner
 }
 
 // Clean starts the clean process.
@@ -46,6 +64,7 @@ func (s *Cleaner) Clean(ctx context.Context, options CleanOptions, progress *job
 		Cleaner:  s,
 		progress: progress,
 		options:  options,
+		missing:  make(map[models.FileID]models.File),
 	}
 
 	if err := j.execute(ctx); err != nil {
@@ -101,6 +120,23 @@ func (s *deleteSet) len() int {
 	return len(s.orderedList)
 }
 
+// linkSet holds the alternate paths of files that are assessed by the clean.
+type linkSet struct {
+	// existing are the alternate paths that exist, by file ID
+	existing map[models.FileID][]string
+	// missing are the alternate paths that no longer exist
+	missing []string
+	// promote are the alternate paths to replace the missing path of a file
+	promote map[models.FileID]string
+}
+
+func newLinkSet() linkSet {
+	return linkSet{
+		existing: make(map[models.FileID][]string),
+		promote:  make(map[models.FileID]string),
+	}
+}
+
 func (j *cleanJob) execute(ctx context.Context) error {
 	progress := j.progress
 
@@ -132,6 +168,11 @@ func (j *cleanJob) execute(ctx context.Context) error {
 	progress.AddTotal(fileCount + folderCount)
 	progress.Definite()
 
+	j.links = newLinkSet()
+	if err := j.assessLinks(ctx); err != nil {
+		return err
+	}
+
 	if err := j.assessFiles(ctx, &toDelete); err != nil {
 		return err
 	}
@@ -140,12 +181,18 @@ func (j *cleanJob) execute(ctx context.Context) error {
 		return err
 	}
 
-	if j.options.DryRun && toDelete.len() > 0 {
-		// add progress for files that would've been deleted
-		progress.AddProcessed(toDelete.len())
+	if j.options.DryRun {
+		if toDelete.len() > 0 {
+			// add progress for files that would've been deleted
+			progress.AddProcessed(toDelete.len())
+		}
 		return nil
 	}
 
+	if err := j.cleanLinks(ctx); err != nil {
+		return err
+	}
+
 	progress.ExecuteTask(fmt.Sprintf("Cleaning %d files and folders", toDelete.len()), func() {
 		for _, ff := range toDelete.orderedList {
 			if job.IsCancelled(ctx) {
@@ -195,11 +242,18 @@ func (j *cleanJob) assessFiles(ctx context.Context, toDelete *deleteSet) error {
 				}
 
 				progress.ExecuteTask(fmt.Sprintf("Assessing file %s for clean", path), func() {
-					if j.shouldClean(ctx, f) {
-						err = j.flagFileForDelete(ctx, toDelete, f)
-					} else {
+					switch {
+					case !j.shouldClean(ctx, f):
 						// increment progress, no further processing
 						progress.Increment()
+					case len(j.links.existing[fileID]) > 0:
+						// replace the path with an alternate path instead of deleting
+						link := j.links.existing[fileID][0]
+						logger.Infof("Marking %q to replace %q", link, path)
+						j.links.promote[fileID] = link
+						progress.Increment()
+					default:
+						err = j.flagFileForDelete(ctx, toDelete, f)
 					}
 				})
 				if err != nil {
@@ -252,6 +306,109 @@ func (j *cleanJob) flagFileForDelete(ctx context.Context, toDelete *deleteSet, f
 	return nil
 }
 
+// assessLinks determines which alternate paths of the files in the clean
+// paths still exist.
+func (j *cleanJob) assessLinks(ctx context.Context) error {
+	r := j.Repository
+	return r.WithReadTxn(ctx, func(ctx context.Context) error {
+		links, err := r.File.FindLinksInPaths(ctx, j.options.Paths)
+		if err != nil {
+			return fmt.Errorf("error querying for alternate paths: %w", err)
+		}
+
+		for _, l := range links {
+			if job.IsCancelled(ctx) {
+				return nil
+			}
+
+			info, err := j.FS.Stat(l.Path)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				logger.Infof("Alternate path not found. Marking to clean: \"%s\"", l.Path)
+				j.links.missing = append(j.links.missing, l.Path)
+				continue
+			case err != nil:
+				// don't clean or promote paths that may still exist
+				logger.Errorf("error getting info for alternate path %q, not cleaning: %v", l.Path, err)
+				continue
+			case !j.options.PathFilter.Accept(ctx, l.Path, info):
+				logger.Infof("Alternate path excluded. Marking to clean: \"%s\"", l.Path)
+				j.links.missing = append(j.links.missing, l.Path)
+				continue
+			}
+
+			j.links.existing[l.FileID] = append(j.links.existing[l.FileID], l.Path)
+		}
+
+		return nil
+	})
+}
+
+// cleanLinks removes the missing alternate paths, and replaces the missing
+// paths of files with one of their alternate paths.
+func (j *cleanJob) cleanLinks(ctx context.Context) error {
+	r := j.Repository
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		for _, path := range j.links.missing {
+			if err := r.File.DestroyLink(ctx, path); err != nil {
+				return err
+			}
+		}
+
+		for fileID, path := range j.links.promote {
+			if err := j.promoteLink(ctx, fileID, path); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (j *cleanJob) promoteLink(ctx context.Context, fileID models.FileID, path string) error {
+	r := j.Repository
+
+	files, err := r.File.Find(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("finding file %d: %w", fileID, err)
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	folder, err := r.Folder.FindByPath(ctx, filepath.Dir(path))
+	if err != nil {
+		return fmt.Errorf("finding folder for %q: %w", path, err)
+	}
+
+	if folder == nil {
+		return fmt.Errorf("folder for %q not found", path)
+	}
+
+	f := files[0]
+	base := f.Base()
+	logger.Infof("%s not found. Replacing with alternate path %s", base.Path, path)
+
+	oldPath := base.Path
+	base.Path = path
+	base.Basename = filepath.Base(path)
+	base.ParentFolderID = folder.ID
+	base.UpdatedAt = time.Now()
+
+	if err := r.File.DestroyLink(ctx, path); err != nil {
+		return err
+	}
+
+	if err := r.File.Update(ctx, f); err != nil {
+		return fmt.Errorf("updating file %q: %w", path, err)
+	}
+
+	registerFileHook(ctx, j.PluginCache, plugin.FileMovePost, f, oldPath)
+
+	return nil
+}
+
 func (j *cleanJob) assessFolders(ctx context.Context, toDelete *deleteSet) error {
 	const batchSize = 1000
 	offset := 0
@@ -330,6 +487,7 @@ func (j *cleanJob) shouldClean(ctx context.Context, f models.File) bool {
 	if info == nil {
 		// info is nil - file not exist
 		logger.Infof("File not found. Marking to clean: \"%s\"", path)
+		j.missing[f.Base().ID] = f
 		return true
 	}
 
@@ -384,7 +542,21 @@ func (j *cleanJob) deleteFile(ctx context.Context, fileID models.FileID, fn stri
 	// delete associated objects
 	fileDeleter := NewDeleter()
 	r := j.Repository
+	quarantined := false
 	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		missing, isMissing := j.missing[fileID]
+		if isMissing {
+			registerFileHook(ctx, j.PluginCache, plugin.FileMissingPost, missing, "")
+		}
+
+		if isMissing && j.options.Quarantiner != nil {
+			var err error
+			quarantined, err = j.options.Quarantiner.QuarantineFile(ctx, fileID)
+			if err != nil || quarantined {
+				return err
+			}
+		}
+
 		fileDeleter.RegisterHooks(ctx)
 
 		if err := j.fireHandlers(ctx, fileDeleter, fileID); err != nil {
@@ -396,9 +568,21 @@ func (j *cleanJob) deleteFile(ctx context.Context, fileID models.FileID, fn stri
 		logger.Errorf("Error deleting file %q from database: %s", fn, err.Error())
 		return
 	}
+
+	if quarantined {
+		j.quarantined = append(j.quarantined, fn)
+	}
 }
 
 func (j *cleanJob) deleteFolder(ctx context.Context, folderID models.FolderID, fn string) {
+	// deleting the folder would delete the files within it
+	for _, p := range j.quarantined {
+		if fsutil.IsPathInDir(fn, p) {
+			logger.Infof("Not deleting folder %q since it contains quarantined files", fn)
+			return
+		}
+	}
+
 	// delete associated objects
 	fileDeleter := NewDeleter()
 	r := j.Repository
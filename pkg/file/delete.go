@@ -65,8 +65,14 @@ func newRenamerRemoverImpl() renamerRemoverImpl {
 // filesystem using the Complete method.
 type Deleter struct {
 	RenamerRemover RenamerRemover
-	files          []string
-	dirs           []string
+
+	// Trash is where files marked with TrashFiles are moved to when the
+	// transaction is committed. If nil, they are deleted.
+	Trash *Trash
+
+	files   []string
+	dirs    []string
+	trashed []string
 }
 
 func NewDeleter() *Deleter {
@@ -92,6 +98,26 @@ func (d *Deleter) RegisterHooks(ctx context.Context) {
 // Abort should be called to restore marked files if this function returns an
 // error.
 func (d *Deleter) Files(paths []string) error {
+	marked, err := d.markFiles(paths)
+	d.files = append(d.files, marked...)
+	return err
+}
+
+// TrashFiles designates library files to be deleted by the user. If Trash is
+// set, the files are moved to the trash instead of being deleted when the
+// transaction is committed. Otherwise it is equivalent to Files.
+func (d *Deleter) TrashFiles(paths []string) error {
+	if d.Trash == nil {
+		return d.Files(paths)
+	}
+
+	marked, err := d.markFiles(paths)
+	d.trashed = append(d.trashed, marked...)
+	return err
+}
+
+func (d *Deleter) markFiles(paths []string) ([]string, error) {
+	var marked []string
 	for _, p := range paths {
 		// fail silently if the file does not exist
 		if _, err := d.RenamerRemover.Stat(p); err != nil {
@@ -100,16 +126,16 @@ func (d *Deleter) Files(paths []string) error {
 				continue
 			}
 
-			return fmt.Errorf("check file %q exists: %w", p, err)
+			return marked, fmt.Errorf("check file %q exists: %w", p, err)
 		}
 
 		if err := d.renameForDelete(p); err != nil {
-			return fmt.Errorf("marking file %q for deletion: %w", p, err)
+			return marked, fmt.Errorf("marking file %q for deletion: %w", p, err)
 		}
-		d.files = append(d.files, p)
+		marked = append(marked, p)
 	}
 
-	return nil
+	return marked, nil
 }
 
 // Dirs designates directories to be deleted. Each directory marked will be renamed to add
@@ -142,7 +168,7 @@ func (d *Deleter) Dirs(paths []string) error {
 // original names and clears the marked list. Any errors encountered are
 // logged. All files will be attempted regardless of any errors occurred.
 func (d *Deleter) Rollback() {
-	for _, f := range append(d.files, d.dirs...) {
+	for _, f := range append(append(d.files, d.trashed...), d.dirs...) {
 		if err := d.renameForRestore(f); err != nil {
 			logger.Warnf("Error restoring %q: %v", f, err)
 		}
@@ -150,6 +176,7 @@ func (d *Deleter) Rollback() {
 
 	d.files = nil
 	d.dirs = nil
+	d.trashed = nil
 }
 
 // Commit deletes all files marked for deletion and clears the marked list.
@@ -168,8 +195,16 @@ func (d *Deleter) Commit() {
 		}
 	}
 
+	for _, f := range d.trashed {
+		// leave the file in place if it could not be moved to the trash
+		if _, err := d.Trash.Add(f+deleteFileSuffix, f); err != nil {
+			logger.Warnf("Error moving file %q to trash: %v", f+deleteFileSuffix, err)
+		}
+	}
+
 	d.files = nil
 	d.dirs = nil
+	d.trashed = nil
 }
 
 func (d *Deleter) renameForDelete(path string) error {
@@ -187,7 +222,7 @@ func Destroy(ctx context.Context, destroyer models.FileDestroyer, f models.File,
 
 	// don't delete files in zip files
 	if deleteFile && f.Base().ZipFileID == nil {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.TrashFiles([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
@@ -230,7 +265,7 @@ func (d *ZipDestroyer) DestroyZip(ctx context.Context, f models.File, fileDelete
 	}
 
 	if deleteFile {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.TrashFiles([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,155 @@
+package file
+
+import (
+	"io/fs"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// maxReadAhead is the maximum number of directories that may be read ahead
+// of the walk.
+const maxReadAhead = 1024
+
+// dirReader reads directories concurrently ahead of a walk. Directories are
+// read by a fixed number of workers, most recently requested first, since
+// the walk is depth first.
+type dirReader struct {
+	fs models.FS
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []string
+	pending map[string]*dirReadResult
+	closed  bool
+}
+
+type dirReadResult struct {
+	started bool
+	ready   chan struct{}
+	entries []fs.DirEntry
+	err     error
+}
+
+func newDirReader(f models.FS, workers int) *dirReader {
+	r := &dirReader{
+		fs:      f,
+		pending: make(map[string]*dirReadResult),
+	}
+	r.cond = sync.NewCond(&r.mu)
+
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+
+	return r
+}
+
+func (r *dirReader) work() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for {
+		for len(r.queue) == 0 && !r.closed {
+			r.cond.Wait()
+		}
+
+		if r.closed {
+			return
+		}
+
+		p := r.queue[len(r.queue)-1]
+		r.queue = r.queue[:len(r.queue)-1]
+
+		// the directory may have been discarded or already read
+		res := r.pending[p]
+		if res == nil || res.started {
+			continue
+		}
+		res.started = true
+
+		r.mu.Unlock()
+		res.entries, res.err = readDirInfo(r.fs, p)
+		close(res.ready)
+		r.mu.Lock()
+	}
+}
+
+// readAhead queues the directories to be read. The first directory is read
+// first.
+func (r *dirReader) readAhead(dirs []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		p := dirs[i]
+		if len(r.pending) >= maxReadAhead {
+			break
+		}
+
+		if _, found := r.pending[p]; found {
+			continue
+		}
+
+		r.pending[p] = &dirReadResult{ready: make(chan struct{})}
+		r.queue = append(r.queue, p)
+	}
+
+	r.cond.Broadcast()
+}
+
+// read returns the sorted entries of the directory. If the directory has not
+// been queued, or a worker has not started reading it, it is read directly.
+func (r *dirReader) read(dirname string) ([]fs.DirEntry, error) {
+	r.mu.Lock()
+	res := r.pending[dirname]
+	delete(r.pending, dirname)
+	r.mu.Unlock()
+
+	if res == nil || !res.started {
+		return readDir(r.fs, dirname)
+	}
+
+	<-res.ready
+	return res.entries, res.err
+}
+
+// discard forgets the directories, so that they do not count towards
+// maxReadAhead if they are not read.
+func (r *dirReader) discard(dirs []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range dirs {
+		delete(r.pending, p)
+	}
+}
+
+// close stops the workers. Directories being read are not waited for.
+func (r *dirReader) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	r.queue = nil
+	r.pending = nil
+	r.cond.Broadcast()
+}
+
+// readDirInfo is readDir, but also reads the file info of the entries, since
+// this requires a further request for each entry on some file systems.
+func readDirInfo(f models.FS, dirname string) ([]fs.DirEntry, error) {
+	dirs, err := readDir(f, dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, d := range dirs {
+		// errors are reported when the walk reads the info
+		if info, err := d.Info(); err == nil {
+			dirs[i] = &statDirEntry{info: info}
+		}
+	}
+
+	return dirs, nil
+}
@@ -0,0 +1,122 @@
+package document
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	stashExec "github.com/stashapp/stash/pkg/exec"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ErrPageOutOfRange is returned when rendering a page that is not in the
+// document.
+var ErrPageOutOfRange = errors.New("page out of range")
+
+// pdfPageRE matches page objects in uncompressed PDF files. It is only used
+// when libvips is not available.
+var pdfPageRE = regexp.MustCompile(`/Type\s*/Page[^s]`)
+
+// Renderer renders the pages of document files to images using libvips.
+// libvips must have been built with PDF support.
+type Renderer struct {
+	// VipsPath is the path to the vips executable. Page rendering is not
+	// available if empty.
+	VipsPath string
+}
+
+func (r *Renderer) vipsheaderPath() string {
+	name := "vipsheader"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	// vipsheader is installed alongside vips
+	ret := filepath.Join(filepath.Dir(r.VipsPath), name)
+	if _, err := os.Stat(ret); err == nil {
+		return ret
+	}
+
+	ret, _ = exec.LookPath("vipsheader")
+	return ret
+}
+
+// PageCount returns the number of pages in the document at path.
+func (r *Renderer) PageCount(path string) (int, error) {
+	if r.VipsPath != "" {
+		if headerPath := r.vipsheaderPath(); headerPath != "" {
+			out, err := run(headerPath, []string{"-f", "n-pages", path})
+			if err != nil {
+				return 0, err
+			}
+
+			return strconv.Atoi(strings.TrimSpace(out))
+		}
+	}
+
+	// fall back to counting page objects. This does not work for files
+	// where the page objects are compressed.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(pdfPageRE.FindAllIndex(data, -1)), nil
+}
+
+// RenderPage renders the page of the document at path to a jpeg image,
+// scaled down to fit maxSize. Pages are numbered from 1.
+func (r *Renderer) RenderPage(f *models.DocumentFile, page int, maxSize int) ([]byte, error) {
+	if r.VipsPath == "" {
+		return nil, errors.New("vips not found - cannot render document pages")
+	}
+
+	if page < 1 || page > f.PageCount {
+		return nil, ErrPageOutOfRange
+	}
+
+	args := []string{
+		"thumbnail",
+		// vips pages are numbered from 0
+		fmt.Sprintf("%s[page=%d,dpi=150]", f.Path, page-1),
+		".jpg[Q=85,strip]",
+		strconv.Itoa(maxSize),
+		"--size", "down",
+	}
+
+	out, err := run(r.VipsPath, args)
+	return []byte(out), err
+}
+
+func run(path string, args []string) (string, error) {
+	cmd := stashExec.Command(path, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// error message should be in the stderr stream
+		logger.Errorf("document renderer error when running command <%s>: %s", strings.Join(cmd.Args, " "), stderr.String())
+		return stdout.String(), err
+	}
+
+	return stdout.String(), nil
+}
+
+// Checksum returns the checksum used to name the generated files of f.
+func Checksum(f *models.DocumentFile) string {
+	if ret := f.Fingerprints.GetString(models.FingerprintTypeMD5); ret != "" {
+		return ret
+	}
+
+	return f.Fingerprints.GetString(models.FingerprintTypeOshash)
+}
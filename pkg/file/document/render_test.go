@@ -0,0 +1,48 @@
+package document
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestRenderer_PageCountWithoutVips(t *testing.T) {
+	const content = `%PDF-1.4
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj
+3 0 obj << /Type /Page /Parent 2 0 R >> endobj
+4 0 obj << /Type/Page /Parent 2 0 R >> endobj
+%%EOF
+`
+
+	path := filepath.Join(t.TempDir(), "test.pdf")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Renderer{}
+	got, err := r.PageCount(path)
+	if err != nil {
+		t.Fatalf("PageCount() error = %v", err)
+	}
+
+	if got != 2 {
+		t.Errorf("PageCount() = %d, want 2", got)
+	}
+}
+
+func TestRenderer_RenderPageOutOfRange(t *testing.T) {
+	r := &Renderer{VipsPath: "vips"}
+	f := &models.DocumentFile{
+		BaseFile:  &models.BaseFile{Path: "test.pdf"},
+		PageCount: 2,
+	}
+
+	for _, page := range []int{0, 3} {
+		if _, err := r.RenderPage(f, page, 100); err != ErrPageOutOfRange {
+			t.Errorf("RenderPage(%d) error = %v, want %v", page, err, ErrPageOutOfRange)
+		}
+	}
+}
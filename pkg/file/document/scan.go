@@ -0,0 +1,54 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Decorator adds document specific fields to a File.
+type Decorator struct {
+	Renderer *Renderer
+}
+
+func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (models.File, error) {
+	base := f.Base()
+	// TODO - copy to temp file if not an OsFS
+	if !file.IsPathFS(fs) {
+		return f, fmt.Errorf("document.constructFile: only OsFS is supported")
+	}
+
+	if d.Renderer == nil {
+		return f, errors.New("document renderer not configured")
+	}
+
+	pageCount, err := d.Renderer.PageCount(base.Path)
+	if err != nil {
+		return f, fmt.Errorf("getting page count of %q: %w", base.Path, err)
+	}
+
+	return &models.DocumentFile{
+		BaseFile:  base,
+		Format:    strings.TrimPrefix(strings.ToLower(filepath.Ext(base.Path)), "."),
+		PageCount: pageCount,
+	}, nil
+}
+
+func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f models.File) bool {
+	const (
+		unsetString = "unset"
+		unsetNumber = -1
+	)
+
+	df, ok := f.(*models.DocumentFile)
+	if !ok {
+		return true
+	}
+
+	return df.Format == unsetString || df.PageCount == unsetNumber
+}
@@ -1,9 +1,11 @@
 package file
 
 import (
+	"errors"
 	"io"
 	"io/fs"
 	"os"
+	"syscall"
 
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/models"
@@ -61,12 +63,21 @@ func (f *OsFS) Open(name string) (fs.ReadDirFile, error) {
 func (f *OsFS) OpenZip(name string) (models.ZipFS, error) {
 	info, err := f.Lstat(name)
 	if err != nil {
+		if isNotExist(err) {
+			return OpenNestedArchive(f, name)
+		}
 		return nil, err
 	}
 
-	return newZipFS(f, name, info)
+	return openArchive(f, name, info)
 }
 
 func (f *OsFS) IsPathCaseSensitive(path string) (bool, error) {
 	return fsutil.IsFsPathCaseSensitive(path)
 }
+
+// isNotExist returns true if err indicates that a path does not exist,
+// including paths that are within a file rather than a directory.
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR)
+}
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
 )
 
 // PathFilter provides a filter function for paths.
@@ -53,3 +54,25 @@ type CleanHandler interface {
 	HandleFile(ctx context.Context, fileDeleter *Deleter, fileID models.FileID) error
 	HandleFolder(ctx context.Context, fileDeleter *Deleter, folderID models.FolderID) error
 }
+
+// Quarantiner keeps files that are missing during a clean, rather than
+// deleting them, so that they can be restored if they are found again.
+type Quarantiner interface {
+	// QuarantineFile is called within a write transaction for a missing
+	// file. If it returns true, the file is kept rather than deleted.
+	QuarantineFile(ctx context.Context, fileID models.FileID) (bool, error)
+}
+
+// registerFileHook registers the plugin hooks for a file event, to be
+// executed after the current transaction is committed. oldPath is the
+// previous path of a moved file.
+func registerFileHook(ctx context.Context, c *plugin.Cache, hookType plugin.HookTriggerEnum, f models.File, oldPath string) {
+	if c == nil {
+		return
+	}
+
+	c.RegisterPostHooks(ctx, int(f.Base().ID), hookType, plugin.FileHookInput{
+		File:    f,
+		OldPath: oldPath,
+	}, nil)
+}
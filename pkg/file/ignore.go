@@ -0,0 +1,215 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// IgnoreFilename is the name of the files that list patterns of paths to
+// exclude from the library, in the format of .gitignore files.
+const IgnoreFilename = ".stashignore"
+
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnorePatterns parses the lines of an ignore file. Blank lines and
+// lines starting with # are ignored. Patterns starting with ! re-include
+// paths excluded by an earlier pattern, and patterns ending with / only
+// match directories. Patterns containing a / other than at the end are
+// relative to the directory of the ignore file; other patterns match names
+// at any depth. * and ? do not match /, and ** matches any number of
+// directories.
+func parseIgnorePatterns(r io.Reader) []ignorePattern {
+	var ret []ignorePattern
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		if line == "" {
+			continue
+		}
+
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			// shouldn't happen since the pattern is escaped
+			continue
+		}
+
+		p.re = re
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// globToRegexp converts a gitignore glob to a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					// **/ matches zero or more directories
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 1 {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			b.WriteByte('[')
+			if class[0] == '!' || class[0] == '^' {
+				b.WriteByte('^')
+				class = class[1:]
+			}
+			b.WriteString(strings.ReplaceAll(class, `\`, `\\`))
+			b.WriteByte(']')
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
+
+// IgnoreMatcher determines if paths are excluded by the ignore files in
+// their directory or the directories above it. Ignore files are read once
+// and cached, so a new IgnoreMatcher should be used for each scan.
+type IgnoreMatcher struct {
+	FS models.FS
+
+	mu    sync.Mutex
+	cache map[string][]ignorePattern
+}
+
+func (m *IgnoreMatcher) patterns(dir string) []ignorePattern {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ret, found := m.cache[dir]; found {
+		return ret
+	}
+
+	if m.cache == nil {
+		m.cache = make(map[string][]ignorePattern)
+	}
+
+	var ret []ignorePattern
+	fn := filepath.Join(dir, IgnoreFilename)
+	f, err := m.FS.Open(fn)
+	if err == nil {
+		var data []byte
+		data, err = io.ReadAll(f)
+		f.Close()
+
+		if err == nil {
+			ret = parseIgnorePatterns(bytes.NewReader(data))
+		}
+	}
+
+	if err != nil && !isNotExist(err) {
+		logger.Warnf("error reading %s: %v", fn, err)
+	}
+
+	m.cache[dir] = ret
+	return ret
+}
+
+// Ignored returns true if path, or a directory containing it, is excluded
+// by the ignore files in the directories from root down to the directory of
+// path. Patterns in deeper ignore files take precedence, and later patterns
+// in a file take precedence over earlier ones.
+func (m *IgnoreMatcher) Ignored(root string, path string, isDir bool) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for n := 1; n <= len(parts); n++ {
+		if m.ignored(root, parts[:n], n < len(parts) || isDir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ignored returns true if the path of parts relative to root is matched by
+// the ignore files, without considering the directories containing it.
+func (m *IgnoreMatcher) ignored(root string, parts []string, isDir bool) bool {
+	ret := false
+	dir := root
+	for i := range parts {
+		relToDir := strings.Join(parts[i:], "/")
+		for _, p := range m.patterns(dir) {
+			if p.dirOnly && !isDir {
+				continue
+			}
+
+			if p.re.MatchString(relToDir) {
+				ret = !p.negate
+			}
+		}
+
+		dir = filepath.Join(dir, parts[i])
+	}
+
+	return ret
+}
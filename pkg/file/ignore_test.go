@@ -0,0 +1,86 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.txt", "notes.txt", true},
+		{"*.txt", "dir/notes.txt", true},
+		{"*.txt", "notes.txt.bak", false},
+		{"/*.txt", "dir/notes.txt", false},
+		{"dir/*.mp4", "dir/a.mp4", true},
+		{"dir/*.mp4", "dir/sub/a.mp4", false},
+		{"dir/**/a.mp4", "dir/a.mp4", true},
+		{"dir/**/a.mp4", "dir/x/y/a.mp4", true},
+		{"**/extras", "a/b/extras", true},
+		{"sample?.mp4", "sample1.mp4", true},
+		{"sample?.mp4", "sample10.mp4", false},
+		{"[ab].mp4", "a.mp4", true},
+		{"[!ab].mp4", "a.mp4", false},
+		{`\#file`, "#file", true},
+	}
+
+	for _, tt := range tests {
+		patterns := parseIgnorePatterns(strings.NewReader(tt.pattern))
+		if !assert.Len(t, patterns, 1, tt.pattern) {
+			continue
+		}
+		assert.Equal(t, tt.want, patterns[0].re.MatchString(tt.path), "%s: %s", tt.pattern, tt.path)
+	}
+}
+
+func TestIgnoreMatcher_Ignored(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile := func(path string, content string) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(IgnoreFilename, "# comment\n\n*.tmp\nextras/\n/top.mp4\n")
+	writeFile("sub/"+IgnoreFilename, "!keep.tmp\nlocal.mp4\n")
+
+	m := &IgnoreMatcher{FS: &OsFS{}}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.mp4", false, false},
+		{"a.tmp", false, true},
+		{"sub/a.tmp", false, true},
+		{"sub/keep.tmp", false, false},
+		{"extras", true, true},
+		{"extras", false, false},
+		{"sub/extras/a.mp4", false, true},
+		{"top.mp4", false, true},
+		{"sub/top.mp4", false, false},
+		{"sub/local.mp4", false, true},
+		{"local.mp4", false, false},
+	}
+
+	for _, tt := range tests {
+		got := m.Ignored(root, filepath.Join(root, filepath.FromSlash(tt.path)), tt.isDir)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+
+	// paths outside of the root are not ignored
+	assert.False(t, m.Ignored(filepath.Join(root, "sub"), filepath.Join(root, "a.tmp"), false))
+}
@@ -73,6 +73,10 @@ func (i *Importer) fileJSONToFile(ctx context.Context, fileJSON jsonschema.DirEn
 			BitRate:          ff.BitRate,
 			Interactive:      ff.Interactive,
 			InteractiveSpeed: ff.InteractiveSpeed,
+
+			InteractiveAverageSpeed:  ff.InteractiveAverageSpeed,
+			InteractiveMaxSpeed:      ff.InteractiveMaxSpeed,
+			InteractiveActionDensity: ff.InteractiveActionDensity,
 		}, nil
 	case *jsonschema.ImageFile:
 		baseFile, err := i.baseFileJSONToBaseFile(ctx, ff.BaseFile)
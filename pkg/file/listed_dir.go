@@ -0,0 +1,65 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+)
+
+var errIsDir = errors.New("is a directory")
+
+// listedDir is an open directory of a file system that lists directories in
+// a single request. The directory is not listed until it is read.
+type listedDir struct {
+	info fs.FileInfo
+	list func() ([]fs.DirEntry, error)
+
+	entries []fs.DirEntry
+	listed  bool
+	offset  int
+}
+
+// NewListedDir returns an open directory with the given file info. list is
+// called to get the directory entries when the directory is first read.
+func NewListedDir(info fs.FileInfo, list func() ([]fs.DirEntry, error)) fs.ReadDirFile {
+	return &listedDir{
+		info: info,
+		list: list,
+	}
+}
+
+func (d *listedDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *listedDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errIsDir}
+}
+
+func (d *listedDir) Close() error {
+	return nil
+}
+
+func (d *listedDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		entries, err := d.list()
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.info.Name(), Err: err}
+		}
+
+		d.entries = entries
+		d.listed = true
+	}
+
+	remaining := d.entries[d.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+
+	d.offset += len(remaining)
+	return remaining, nil
+}
@@ -0,0 +1,110 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// MountedFS is a file system that is mounted at a local path.
+type MountedFS interface {
+	models.FS
+	// MountPath returns the path that the file system is mounted at.
+	MountPath() string
+}
+
+// MountFS is a file system that reads paths within mounted file systems from
+// those file systems, and all other paths from the OS.
+type MountFS struct {
+	os OsFS
+
+	mutex  sync.RWMutex
+	mounts []MountedFS
+}
+
+// SetMounts replaces the mounted file systems. Replaced file systems that
+// implement io.Closer are closed.
+func (f *MountFS) SetMounts(mounts []MountedFS) {
+	f.mutex.Lock()
+	old := f.mounts
+	f.mounts = mounts
+	f.mutex.Unlock()
+
+	for _, m := range old {
+		if c, ok := m.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// Mount returns the mounted file system containing path. Returns nil if path
+// is not within a mounted file system.
+func (f *MountFS) Mount(path string) MountedFS {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for _, m := range f.mounts {
+		if fsutil.IsPathInDir(m.MountPath(), path) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (f *MountFS) fs(path string) models.FS {
+	if m := f.Mount(path); m != nil {
+		return m
+	}
+
+	return &f.os
+}
+
+func (f *MountFS) Stat(name string) (fs.FileInfo, error) {
+	return f.fs(name).Stat(name)
+}
+
+func (f *MountFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.fs(name).Lstat(name)
+}
+
+func (f *MountFS) Open(name string) (fs.ReadDirFile, error) {
+	return f.fs(name).Open(name)
+}
+
+func (f *MountFS) OpenZip(name string) (models.ZipFS, error) {
+	return f.fs(name).OpenZip(name)
+}
+
+func (f *MountFS) IsPathCaseSensitive(path string) (bool, error) {
+	return f.fs(path).IsPathCaseSensitive(path)
+}
+
+// IsPathFS returns true if files in fsys can be read by ffmpeg and ffprobe
+// using their paths. Files in object storage mounts are read using URLs
+// provided by the ffmpeg input resolver.
+func IsPathFS(fsys models.FS) bool {
+	switch fsys.(type) {
+	case *OsFS, *MountFS:
+		return true
+	default:
+		return false
+	}
+}
+
+// MountRelPath returns the path of name relative to mountPath, using "/" as
+// the separator. Returns "." for mountPath itself. Returns false if name is
+// not within mountPath.
+func MountRelPath(mountPath, name string) (string, bool) {
+	rel, err := filepath.Rel(mountPath, name)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
@@ -139,6 +139,25 @@ func (m *Mover) Move(ctx context.Context, f models.File, folder *models.Folder,
 	return m.moveFile(oldPath, newPath)
 }
 
+// MoveSidecar moves a file that is not in the database, such as the
+// funscript of a video file, from oldPath to newPath. The file is moved back
+// if the transaction is rolled back. Nothing is done if oldPath does not
+// exist.
+func (m *Mover) MoveSidecar(oldPath, newPath string) error {
+	if _, err := m.Renamer.Stat(oldPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("getting info for %s: %w", oldPath, err)
+	}
+
+	if _, err := m.Renamer.Stat(newPath); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("file %s already exists", newPath)
+	}
+
+	return m.moveFile(oldPath, newPath)
+}
+
 func (m *Mover) CreateFolderHierarchy(path string) error {
 	info, err := m.Renamer.Stat(path)
 	if err != nil {
@@ -0,0 +1,251 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/nwaples/rardecode/v2"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// rarArchive is an fs.FS of the contents of a RAR archive.
+type rarArchive struct {
+	path string
+	opts []rardecode.Option
+
+	files map[string]*rardecode.File
+	infos map[string]fs.FileInfo
+	dirs  map[string][]fs.DirEntry
+}
+
+func newRarFS(fsys models.FS, path string, info fs.FileInfo) (*zipFS, error) {
+	a, err := newRarArchive(path, info, rardecode.FileSystem(stdFS{fs: fsys}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipFS{
+		archive:       a,
+		zipFileCloser: a,
+		zipInfo:       info,
+		zipPath:       path,
+	}, nil
+}
+
+func newRarArchive(archivePath string, info fs.FileInfo, opts ...rardecode.Option) (*rarArchive, error) {
+	files, err := rardecode.List(archivePath, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &rarArchive{
+		path:  archivePath,
+		opts:  opts,
+		files: make(map[string]*rardecode.File),
+		infos: make(map[string]fs.FileInfo),
+		dirs:  make(map[string][]fs.DirEntry),
+	}
+
+	ret.add(".", rarDirInfo{name: ".", modTime: info.ModTime()})
+
+	for _, f := range files {
+		name := path.Clean(f.Name)
+
+		// encrypted files cannot be read without a password
+		if !fs.ValidPath(name) || name == "." || f.Encrypted {
+			continue
+		}
+
+		if f.IsDir {
+			ret.add(name, rarDirInfo{name: name, modTime: f.ModificationTime})
+			continue
+		}
+
+		ret.files[name] = f
+		ret.add(name, rarFileInfo{&f.FileHeader})
+	}
+
+	return ret, nil
+}
+
+// add adds the entry to the archive, adding any missing parent directories.
+func (a *rarArchive) add(name string, info fs.FileInfo) {
+	if _, found := a.infos[name]; found {
+		return
+	}
+
+	a.infos[name] = info
+
+	if name == "." {
+		return
+	}
+
+	dir := path.Dir(name)
+	a.add(dir, rarDirInfo{name: dir, modTime: info.ModTime()})
+	a.dirs[dir] = append(a.dirs[dir], fs.FileInfoToDirEntry(info))
+}
+
+func (a *rarArchive) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	info, found := a.infos[name]
+	if !found {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if info.IsDir() {
+		entries := append([]fs.DirEntry(nil), a.dirs[name]...)
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Name() < entries[j].Name()
+		})
+
+		return &rarDir{info: info, entries: entries}, nil
+	}
+
+	return &rarFile{
+		info: info,
+		open: func() (io.ReadCloser, error) {
+			return a.openFile(name)
+		},
+	}, nil
+}
+
+func (a *rarArchive) Stat(name string) (fs.FileInfo, error) {
+	info, found := a.infos[name]
+	if !found || !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return info, nil
+}
+
+// Close is a no-op. The archive is opened again each time a file is read.
+func (a *rarArchive) Close() error {
+	return nil
+}
+
+func (a *rarArchive) openFile(name string) (io.ReadCloser, error) {
+	f := a.files[name]
+	if !f.Solid {
+		return f.Open()
+	}
+
+	// the contents of solid files depend on the preceding files, so the
+	// archive must be read from the start
+	r, err := rardecode.OpenReader(a.path, a.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		h, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			r.Close()
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+
+		if path.Clean(h.Name) == name {
+			return r, nil
+		}
+	}
+}
+
+// rarFile is a file in a RAR archive. The file is not decompressed until it
+// is read, so that it can be opened cheaply to get its file info.
+type rarFile struct {
+	info fs.FileInfo
+	open func() (io.ReadCloser, error)
+	r    io.ReadCloser
+}
+
+func (f *rarFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *rarFile) Read(p []byte) (int, error) {
+	if f.r == nil {
+		r, err := f.open()
+		if err != nil {
+			return 0, err
+		}
+		f.r = r
+	}
+
+	return f.r.Read(p)
+}
+
+func (f *rarFile) Close() error {
+	if f.r == nil {
+		return nil
+	}
+
+	return f.r.Close()
+}
+
+// rarDir is a directory in a RAR archive.
+type rarDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *rarDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *rarDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errIsDir}
+}
+
+func (d *rarDir) Close() error {
+	return nil
+}
+
+func (d *rarDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+
+	d.offset += len(remaining)
+	return remaining, nil
+}
+
+type rarFileInfo struct {
+	h *rardecode.FileHeader
+}
+
+func (fi rarFileInfo) Name() string       { return path.Base(fi.h.Name) }
+func (fi rarFileInfo) Size() int64        { return fi.h.UnPackedSize }
+func (fi rarFileInfo) Mode() fs.FileMode  { return fi.h.Mode() }
+func (fi rarFileInfo) ModTime() time.Time { return fi.h.ModificationTime }
+func (fi rarFileInfo) IsDir() bool        { return false }
+func (fi rarFileInfo) Sys() interface{}   { return fi.h }
+
+// rarDirInfo is the file info of a directory in a RAR archive. Directories
+// are not required to have an entry in the archive.
+type rarDirInfo struct {
+	name    string
+	modTime time.Time
+}
+
+func (fi rarDirInfo) Name() string       { return path.Base(fi.name) }
+func (fi rarDirInfo) Size() int64        { return 0 }
+func (fi rarDirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (fi rarDirInfo) ModTime() time.Time { return fi.modTime }
+func (fi rarDirInfo) IsDir() bool        { return true }
+func (fi rarDirInfo) Sys() interface{}   { return nil }
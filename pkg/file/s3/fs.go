@@ -0,0 +1,298 @@
+// Package s3 provides a file system backed by an S3-compatible bucket.
+package s3
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var errNotDir = errors.New("not a directory")
+
+// Options are the options used to connect to a bucket.
+type Options struct {
+	// Endpoint is the host and optional port of the storage service.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix is the key prefix within the bucket that the mount path maps to.
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+	// PathStyle forces path-style requests instead of virtual-hosted-style
+	// requests. Required by most self-hosted storage services.
+	PathStyle bool
+}
+
+// FS is a read-only file system of the objects in a bucket, mounted at a
+// local path. Object keys are mapped to paths within the mount path, using
+// "/" as the directory separator.
+//
+// Files support ranged reads, so that they can be fingerprinted and served
+// without being downloaded in full.
+type FS struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	mountPath string
+}
+
+// New returns a FS of the bucket described by opts, mounted at mountPath.
+func New(mountPath string, opts Options) (*FS, error) {
+	lookup := minio.BucketLookupAuto
+	if opts.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure:       opts.UseSSL,
+		Region:       opts.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FS{
+		client:    client,
+		bucket:    opts.Bucket,
+		prefix:    strings.Trim(opts.Prefix, "/"),
+		mountPath: filepath.Clean(mountPath),
+	}, nil
+}
+
+// MountPath returns the path that the bucket is mounted at.
+func (f *FS) MountPath() string {
+	return f.mountPath
+}
+
+// key returns the object key of name. Returns false if name is not within
+// the mount path.
+func (f *FS) key(name string) (string, bool) {
+	rel, ok := file.MountRelPath(f.mountPath, name)
+	if !ok {
+		return "", false
+	}
+
+	if rel == "." {
+		return f.prefix, true
+	}
+
+	return path.Join(f.prefix, rel), true
+}
+
+// dirPrefix returns the prefix of the keys of the objects in the directory
+// with the given key.
+func dirPrefix(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return key + "/"
+}
+
+func isNotFound(err error) bool {
+	return minio.ToErrorResponse(err).StatusCode == http.StatusNotFound
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	key, ok := f.key(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	// the mount path itself always exists
+	if key == f.prefix {
+		return dirInfo{name: filepath.Base(f.mountPath)}, nil
+	}
+
+	ctx := context.Background()
+	obj, err := f.client.StatObject(ctx, f.bucket, key, minio.StatObjectOptions{})
+	if err == nil {
+		return objectInfo{obj}, nil
+	}
+
+	if !isNotFound(err) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	// directories are not objects, so check for objects within the
+	// directory instead
+	isDir, err := f.hasObjects(ctx, dirPrefix(key))
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	if !isDir {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return dirInfo{name: path.Base(key)}, nil
+}
+
+// Lstat is equivalent to Stat, since buckets do not contain symlinks.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *FS) hasObjects(ctx context.Context, prefix string) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range f.client.ListObjects(ctx, f.bucket, minio.ListObjectsOptions{
+		Prefix:  prefix,
+		MaxKeys: 1,
+	}) {
+		if obj.Err != nil {
+			return false, obj.Err
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// readDir returns the entries of the directory with the given key, sorted by
+// name.
+func (f *FS) readDir(key string) ([]fs.DirEntry, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prefix := dirPrefix(key)
+
+	var ret []fs.DirEntry
+	for obj := range f.client.ListObjects(ctx, f.bucket, minio.ListObjectsOptions{
+		Prefix: prefix,
+	}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		name := strings.TrimPrefix(obj.Key, prefix)
+
+		// common prefixes are returned with a trailing separator
+		if strings.HasSuffix(name, "/") {
+			name = strings.TrimSuffix(name, "/")
+			if name != "" {
+				ret = append(ret, fs.FileInfoToDirEntry(dirInfo{name: name}))
+			}
+			continue
+		}
+
+		if name != "" {
+			ret = append(ret, fs.FileInfoToDirEntry(objectInfo{obj}))
+		}
+	}
+
+	return ret, nil
+}
+
+func (f *FS) Open(name string) (fs.ReadDirFile, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := f.key(name)
+
+	if info.IsDir() {
+		return file.NewListedDir(info, func() ([]fs.DirEntry, error) {
+			return f.readDir(key)
+		}), nil
+	}
+
+	obj, err := f.client.GetObject(context.Background(), f.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &object{
+		Object: obj,
+		info:   info,
+	}, nil
+}
+
+func (f *FS) OpenZip(name string) (models.ZipFS, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file.OpenNestedArchive(f, name)
+		}
+		return nil, err
+	}
+
+	return file.OpenArchive(f, name, info)
+}
+
+// IsPathCaseSensitive returns true, since object keys are case sensitive.
+func (f *FS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+// PresignedURL returns a URL that can be used to read the file at name
+// without credentials until expiry has passed.
+func (f *FS) PresignedURL(name string, expiry time.Duration) (string, error) {
+	key, ok := f.key(name)
+	if !ok || key == f.prefix {
+		return "", &fs.PathError{Op: "presign", Path: name, Err: fs.ErrNotExist}
+	}
+
+	u, err := f.client.PresignedGetObject(context.Background(), f.bucket, key, expiry, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// object is an open object. Reads are made using ranged requests, so seeking
+// within the object does not read the skipped data.
+type object struct {
+	*minio.Object
+	info fs.FileInfo
+}
+
+func (o *object) Stat() (fs.FileInfo, error) {
+	return o.info, nil
+}
+
+func (o *object) ReadDir(n int) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: o.info.Name(), Err: errNotDir}
+}
+
+type objectInfo struct {
+	obj minio.ObjectInfo
+}
+
+func (fi objectInfo) Name() string       { return path.Base(fi.obj.Key) }
+func (fi objectInfo) Size() int64        { return fi.obj.Size }
+func (fi objectInfo) Mode() fs.FileMode  { return 0444 }
+func (fi objectInfo) ModTime() time.Time { return fi.obj.LastModified }
+func (fi objectInfo) IsDir() bool        { return false }
+func (fi objectInfo) Sys() interface{}   { return fi.obj }
+
+// dirInfo is the file info of a directory. Directories are not objects, so
+// they have no modification time.
+type dirInfo struct {
+	name string
+}
+
+func (fi dirInfo) Name() string       { return fi.name }
+func (fi dirInfo) Size() int64        { return 0 }
+func (fi dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0555 }
+func (fi dirInfo) ModTime() time.Time { return time.Time{} }
+func (fi dirInfo) IsDir() bool        { return true }
+func (fi dirInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,330 @@
+package s3
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBucket = "bucket"
+
+var testModTime = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+type listResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Name           string
+	Prefix         string
+	KeyCount       int
+	MaxKeys        int
+	Delimiter      string
+	IsTruncated    bool
+	Contents       []listObject
+	CommonPrefixes []listPrefix
+}
+
+type listObject struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+}
+
+type listPrefix struct {
+	Prefix string
+}
+
+// fakeS3 is a minimal S3-compatible server, supporting the requests used by
+// FS.
+type fakeS3 struct {
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.Path, "/"+testBucket)
+	key := strings.TrimPrefix(p, "/")
+
+	if key == "" && r.URL.Query().Get("list-type") == "2" {
+		s.list(w, r.URL.Query())
+		return
+	}
+
+	data, found := s.objects[key]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("ETag", `"`+strconv.Itoa(len(data))+`"`)
+	http.ServeContent(w, r, key, testModTime, bytes.NewReader(data))
+}
+
+func (s *fakeS3) list(w http.ResponseWriter, q url.Values) {
+	prefix := q.Get("prefix")
+	delimiter := q.Get("delimiter")
+
+	ret := listResult{
+		Name:      testBucket,
+		Prefix:    prefix,
+		MaxKeys:   1000,
+		Delimiter: delimiter,
+	}
+
+	var keys []string
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	prefixes := make(map[string]bool)
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(k, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i != -1 {
+			cp := prefix + rest[:i+1]
+			if !prefixes[cp] {
+				prefixes[cp] = true
+				ret.CommonPrefixes = append(ret.CommonPrefixes, listPrefix{cp})
+			}
+			continue
+		}
+
+		ret.Contents = append(ret.Contents, listObject{
+			Key:          k,
+			LastModified: testModTime.Format(time.RFC3339),
+			ETag:         `"etag"`,
+			Size:         int64(len(s.objects[k])),
+		})
+	}
+
+	ret.KeyCount = len(ret.Contents) + len(ret.CommonPrefixes)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(ret)
+}
+
+func testZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	w, err := z.Create("image.jpg")
+	if err != nil {
+		t.Fatalf("creating zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte("zipped")); err != nil {
+		t.Fatalf("writing zip entry: %v", err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatalf("closing zip: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func testFS(t *testing.T) *FS {
+	server := httptest.NewServer(&fakeS3{
+		objects: map[string][]byte{
+			"library/a.mp4":       []byte("0123456789"),
+			"library/sub/b.jpg":   []byte("image"),
+			"library/gallery.zip": testZip(t),
+			"other/c.mp4":         []byte("other"),
+		},
+	})
+	t.Cleanup(server.Close)
+
+	ret, err := New(mountPath, Options{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Region:    "us-east-1",
+		Bucket:    testBucket,
+		Prefix:    "/library/",
+		AccessKey: "access",
+		SecretKey: "secret",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return ret
+}
+
+var mountPath = filepath.FromSlash("/mnt/bucket")
+
+func mounted(p string) string {
+	return filepath.Join(mountPath, filepath.FromSlash(p))
+}
+
+func TestFS_Stat(t *testing.T) {
+	f := testFS(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		isDir   bool
+		size    int64
+		wantErr error
+	}{
+		{"mount path", mountPath, true, 0, nil},
+		{"file", mounted("a.mp4"), false, 10, nil},
+		{"nested file", mounted("sub/b.jpg"), false, 5, nil},
+		{"directory", mounted("sub"), true, 0, nil},
+		{"missing", mounted("missing.mp4"), false, 0, fs.ErrNotExist},
+		{"outside prefix", mounted("../bucket2/c.mp4"), false, 0, fs.ErrNotExist},
+		{"outside mount", filepath.FromSlash("/mnt/other/a.mp4"), false, 0, fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := f.Stat(tt.path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stat: %v", err)
+			}
+
+			assert.Equal(t, tt.isDir, info.IsDir())
+			assert.Equal(t, tt.size, info.Size())
+			if !tt.isDir {
+				assert.Equal(t, testModTime, info.ModTime().UTC())
+			}
+		})
+	}
+}
+
+func TestFS_ReadDir(t *testing.T) {
+	f := testFS(t)
+
+	d, err := f.Open(mountPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer d.Close()
+
+	entries, err := d.ReadDir(-1)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"a.mp4", "gallery.zip", "sub"}, names)
+	assert.True(t, entries[2].IsDir())
+}
+
+func TestFS_Open(t *testing.T) {
+	f := testFS(t)
+
+	r, err := f.Open(mounted("a.mp4"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	// read the end of the file without reading the start
+	rs := r.(io.ReadSeeker)
+	if _, err := rs.Seek(-3, io.SeekEnd); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+
+	got, err := io.ReadAll(rs)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	assert.Equal(t, "789", string(got))
+
+	buf := make([]byte, 4)
+	if _, err := r.(io.ReaderAt).ReadAt(buf, 2); err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	assert.Equal(t, "2345", string(buf))
+}
+
+func TestFS_OpenZip(t *testing.T) {
+	f := testFS(t)
+
+	zipPath := mounted("gallery.zip")
+	zfs, err := f.OpenZip(zipPath)
+	if err != nil {
+		t.Fatalf("OpenZip: %v", err)
+	}
+	defer zfs.Close()
+
+	r, err := zfs.Open(filepath.Join(zipPath, "image.jpg"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	assert.Equal(t, "zipped", string(got))
+}
+
+func TestFS_PresignedURL(t *testing.T) {
+	f := testFS(t)
+
+	u, err := f.PresignedURL(mounted("sub/b.jpg"), time.Hour)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+
+	parsed, err := url.Parse(u)
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+	assert.Equal(t, "/bucket/library/sub/b.jpg", parsed.Path)
+	assert.NotEmpty(t, parsed.Query().Get("X-Amz-Signature"))
+
+	_, err = f.PresignedURL(mountPath, time.Hour)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestMountFS(t *testing.T) {
+	f := testFS(t)
+
+	mfs := &file.MountFS{}
+	mfs.SetMounts([]file.MountedFS{f})
+
+	assert.Equal(t, f, mfs.Mount(mounted("a.mp4")))
+
+	info, err := mfs.Stat(mounted("a.mp4"))
+	if err != nil {
+		t.Fatalf("Stat mounted: %v", err)
+	}
+	assert.Equal(t, int64(10), info.Size())
+
+	// paths outside mounts are read from the OS
+	localPath := filepath.Join(t.TempDir(), "local.mp4")
+	if err := os.WriteFile(localPath, []byte("local"), 0644); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+
+	assert.Nil(t, mfs.Mount(localPath))
+
+	info, err = mfs.Stat(localPath)
+	if err != nil {
+		t.Fatalf("Stat local: %v", err)
+	}
+	assert.Equal(t, int64(5), info.Size())
+}
@@ -7,13 +7,16 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
+	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
 	"github.com/stashapp/stash/pkg/txn"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -31,6 +34,7 @@ const (
 // in the filesystem. It runs each directory entry through the provided ScanFilters. If none
 // of the filter Accept methods return true, then the file/directory is ignored.
 // Any folders found are handled immediately. Files inside zip files are also handled immediately.
+// Zip files inside zip files are scanned in the same way, up to a maximum nesting depth.
 // All other files encountered are sent to the second goroutine queue.
 //
 // Folders are handled by checking if the folder exists in the database, by its full path.
@@ -48,8 +52,11 @@ const (
 // system. If one is, then the file is treated as renamed and its path is updated. If none are missing,
 // or many are, then the file is treated as a new file.
 //
-// If the file is not a renamed file, then the decorators are fired and the file is created, then
-// the applicable handlers are fired.
+// If the file is not a renamed file, then it is checked whether it is a hardlink or symlink to a file
+// entry with the same fingerprint. If it is, then the path is added as an alternate path of the
+// existing file entry, instead of creating a new one.
+//
+// Otherwise, the decorators are fired and the file is created, then the applicable handlers are fired.
 type Scanner struct {
 	FS                    models.FS
 	Repository            Repository
@@ -57,11 +64,17 @@ type Scanner struct {
 
 	// FileDecorators are applied to files as they are scanned.
 	FileDecorators []Decorator
+
+	// PluginCache is used to fire file hooks. Hooks are not fired if nil.
+	PluginCache *plugin.Cache
 }
 
 // FingerprintCalculator calculates a fingerprint for the provided file.
 type FingerprintCalculator interface {
-	CalculateFingerprints(f *models.BaseFile, o Opener, useExisting bool) ([]models.Fingerprint, error)
+	// CalculateFingerprints returns the context error if ctx is done before
+	// the fingerprints are calculated. If progress is not nil, it is called
+	// with the number of bytes read as the file contents are hashed.
+	CalculateFingerprints(ctx context.Context, f *models.BaseFile, o Opener, useExisting bool, progress func(read int64)) ([]models.Fingerprint, error)
 }
 
 // Decorator wraps the Decorate method to add additional functionality while scanning files.
@@ -97,6 +110,7 @@ type ProgressReporter interface {
 	Increment()
 	Definite()
 	ExecuteTask(description string, fn func())
+	ExecuteTaskWithProgress(description string, fn func(setPercent func(float64)))
 }
 
 type scanJob struct {
@@ -110,6 +124,9 @@ type scanJob struct {
 
 	startTime      time.Time
 	fileQueue      chan scanFile
+	walked         []scanFile
+	checkpoint     *scanCheckpoint
+	pathIndex      int
 	retryList      []scanFile
 	retrying       bool
 	folderPathToID sync.Map
@@ -134,6 +151,24 @@ type ScanOptions struct {
 	HandlerRequiredFilters []Filter
 
 	ParallelTasks int
+
+	// WalkParallelTasks is the number of directories that are read
+	// concurrently while walking the paths. Files are still queued in walk
+	// order. Directories within zip files are always read serially.
+	WalkParallelTasks int
+
+	// NewestFirst scans files in order of modification time, newest first.
+	// Files are not scanned until the directory tree has been walked.
+	NewestFirst bool
+
+	// Resume is the position to resume an interrupted scan from. Files at or
+	// before the position are not scanned.
+	Resume *ScanPosition
+
+	// Checkpoint is called periodically with the position that the scan can
+	// be resumed from. It is not called if NewestFirst is set, since files
+	// are not scanned in walk order.
+	Checkpoint func(ScanPosition)
 }
 
 // Scan starts the scanning process.
@@ -156,6 +191,10 @@ type scanFile struct {
 	*models.BaseFile
 	fs   models.FS
 	info fs.FileInfo
+
+	// queueIndex is the position of the file in the queue, starting at 1.
+	// Zero for files that are not queued.
+	queueIndex int
 }
 
 func (s *scanJob) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -172,6 +211,14 @@ func (s *scanJob) execute(ctx context.Context) {
 	s.startTime = time.Now()
 
 	s.fileQueue = make(chan scanFile, scanQueueSize)
+	if s.options.Checkpoint != nil && !s.options.NewestFirst {
+		s.checkpoint = newScanCheckpoint(s.options.Checkpoint)
+	}
+
+	if s.options.Resume != nil {
+		logger.Infof("resuming scan from %q", s.options.Resume.Path)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -204,14 +251,23 @@ func (s *scanJob) execute(ctx context.Context) {
 func (s *scanJob) queueFiles(ctx context.Context, paths []string) error {
 	var err error
 	s.ProgressReports.ExecuteTask("Walking directory tree", func() {
-		for _, p := range paths {
-			err = symWalk(s.FS, p, s.queueFileFunc(ctx, s.FS, nil))
+		for i, p := range paths {
+			s.pathIndex = i
+			err = parallelSymWalk(s.FS, p, s.options.WalkParallelTasks, s.queueFileFunc(ctx, s.FS, nil))
 			if err != nil {
 				return
 			}
 		}
 	})
 
+	if err == nil && s.options.NewestFirst {
+		sortNewestFirst(s.walked)
+		for _, f := range s.walked {
+			s.fileQueue <- f
+		}
+	}
+	s.walked = nil
+
 	close(s.fileQueue)
 
 	if s.ProgressReports != nil {
@@ -239,6 +295,14 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f models.FS, zipFile *scanF
 			return fmt.Errorf("reading info for %q: %w", path, err)
 		}
 
+		if zipFile == nil && skipResumed(s.options.Resume, s.pathIndex, path, info.IsDir()) {
+			if info.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
 		if !s.acceptEntry(ctx, path, info) {
 			if info.IsDir() {
 				return fs.SkipDir
@@ -302,14 +366,34 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f models.FS, zipFile *scanF
 			return nil
 		}
 
-		s.fileQueue <- ff
-
 		s.count++
+		ff.queueIndex = s.count
+
+		if s.checkpoint != nil {
+			s.checkpoint.queue(ff.queueIndex, ScanPosition{
+				PathIndex: s.pathIndex,
+				Path:      path,
+			})
+		}
+
+		if s.options.NewestFirst {
+			s.walked = append(s.walked, ff)
+		} else {
+			s.fileQueue <- ff
+		}
 
 		return nil
 	}
 }
 
+// sortNewestFirst sorts files by modification time, newest first. Files with
+// the same modification time remain in walk order.
+func sortNewestFirst(files []scanFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].ModTime.After(files[j].ModTime)
+	})
+}
+
 func getFileSize(f models.FS, path string, info fs.FileInfo) (int64, error) {
 	// #2196/#3042 - replace size with target size if file is a symlink
 	if info.Mode()&os.ModeSymlink == os.ModeSymlink {
@@ -346,6 +430,11 @@ func (s *scanJob) scanZipFile(ctx context.Context, f scanFile) error {
 			return nil
 		}
 
+		if errors.Is(err, errZipDepthExceeded) || errors.Is(err, errNestedArchiveTooLarge) {
+			logger.Warnf("Not scanning contents of %q: %v", f.Path, err)
+			return nil
+		}
+
 		return err
 	}
 
@@ -370,6 +459,10 @@ func (s *scanJob) processQueue(ctx context.Context) error {
 				return err
 			}
 
+			if err := job.WaitIfPaused(ctx); err != nil {
+				return err
+			}
+
 			wg.Add()
 			ff := f
 			go func() {
@@ -393,6 +486,10 @@ func (s *scanJob) processQueue(ctx context.Context) error {
 				return err
 			}
 
+			if err := job.WaitIfPaused(ctx); err != nil {
+				return err
+			}
+
 			wg.Add()
 			ff := f
 			go func() {
@@ -429,6 +526,10 @@ func (s *scanJob) processQueueItem(ctx context.Context, f scanFile) {
 		if err != nil && !errors.Is(err, context.Canceled) {
 			logger.Errorf("error processing %q: %v", f.Path, err)
 		}
+
+		if s.checkpoint != nil && f.queueIndex > 0 && !errors.Is(err, context.Canceled) {
+			s.checkpoint.complete(f.queueIndex, s.retrying)
+		}
 	})
 }
 
@@ -653,6 +754,11 @@ func (s *scanJob) handleFile(ctx context.Context, f scanFile) error {
 		}
 
 		if ff == nil {
+			isLink, err := s.isExistingLink(ctx, f)
+			if err != nil || isLink {
+				return err
+			}
+
 			ff, err = s.onNewFile(ctx, f)
 			return err
 		}
@@ -713,6 +819,10 @@ func (s *scanJob) onNewFile(ctx context.Context, f scanFile) (models.File, error
 			return nil, fmt.Errorf("parent folder for %q doesn't exist", path)
 		}
 
+		if s.checkpoint != nil && f.queueIndex > 0 {
+			s.checkpoint.hold(f.queueIndex)
+		}
+
 		s.retryList = append(s.retryList, f)
 		return nil, nil
 	}
@@ -720,7 +830,7 @@ func (s *scanJob) onNewFile(ctx context.Context, f scanFile) (models.File, error
 	baseFile.ParentFolderID = *parentFolderID
 
 	const useExisting = false
-	fp, err := s.calculateFingerprints(f.fs, baseFile, path, useExisting)
+	fp, err := s.calculateFingerprints(ctx, f.fs, baseFile, path, useExisting)
 	if err != nil {
 		return nil, err
 	}
@@ -743,12 +853,19 @@ func (s *scanJob) onNewFile(ctx context.Context, f scanFile) (models.File, error
 		return renamed, nil
 	}
 
-	// if not renamed, queue file for creation
+	// determine if the file is a hardlink or symlink to an existing file
+	if linked, err := s.handleLink(ctx, f, fp); err != nil || linked {
+		return nil, err
+	}
+
+	// if not renamed or linked, queue file for creation
 	if err := s.withTxn(ctx, func(ctx context.Context) error {
 		if err := s.Repository.File.Create(ctx, file); err != nil {
 			return fmt.Errorf("creating file %q: %w", path, err)
 		}
 
+		registerFileHook(ctx, s.PluginCache, plugin.FileCreatePost, file, "")
+
 		if err := s.fireHandlers(ctx, file, nil); err != nil {
 			return err
 		}
@@ -783,17 +900,27 @@ func (s *scanJob) fireHandlers(ctx context.Context, f models.File, oldFile model
 	return nil
 }
 
-func (s *scanJob) calculateFingerprints(fs models.FS, f *models.BaseFile, path string, useExisting bool) (models.Fingerprints, error) {
+func (s *scanJob) calculateFingerprints(ctx context.Context, fs models.FS, f *models.BaseFile, path string, useExisting bool) (models.Fingerprints, error) {
 	// only log if we're (re)calculating fingerprints
 	if !useExisting {
 		logger.Infof("Calculating fingerprints for %s ...", path)
 	}
 
-	// calculate primary fingerprint for the file
-	fp, err := s.FingerprintCalculator.CalculateFingerprints(f, &fsOpener{
+	opener := &fsOpener{
 		fs:   fs,
 		name: path,
-	}, useExisting)
+	}
+
+	// calculate primary fingerprint for the file
+	var fp []models.Fingerprint
+	var err error
+	if s.ProgressReports == nil {
+		fp, err = s.FingerprintCalculator.CalculateFingerprints(ctx, f, opener, useExisting, nil)
+	} else {
+		s.ProgressReports.ExecuteTaskWithProgress("Hashing "+path, func(setPercent func(float64)) {
+			fp, err = s.FingerprintCalculator.CalculateFingerprints(ctx, f, opener, useExisting, hashProgress(f.Size, setPercent))
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("calculating fingerprint for file %q: %w", path, err)
 	}
@@ -801,6 +928,23 @@ func (s *scanJob) calculateFingerprints(fs models.FS, f *models.BaseFile, path s
 	return fp, nil
 }
 
+// hashProgress returns a function that reports the progress of hashing a
+// file of the given size. setPercent is only called when the whole
+// percentage changes.
+func hashProgress(size int64, setPercent func(float64)) func(read int64) {
+	if size <= 0 {
+		return nil
+	}
+
+	last := -1
+	return func(read int64) {
+		if pc := int(read * 100 / size); pc != last {
+			last = pc
+			setPercent(float64(read) / float64(size))
+		}
+	}
+}
+
 func appendFileUnique(v []models.File, toAdd []models.File) []models.File {
 	for _, f := range toAdd {
 		found := false
@@ -834,8 +978,8 @@ func (s *scanJob) getFileFS(f *models.BaseFile) (models.FS, error) {
 	return fs.OpenZip(zipPath)
 }
 
-func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.Fingerprint) (models.File, error) {
-	var others []models.File
+func (s *scanJob) findByFingerprints(ctx context.Context, fp []models.Fingerprint) ([]models.File, error) {
+	var ret []models.File
 
 	for _, tfp := range fp {
 		thisOthers, err := s.Repository.File.FindByFingerprint(ctx, tfp)
@@ -843,7 +987,16 @@ func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.F
 			return nil, fmt.Errorf("getting files by fingerprint %v: %w", tfp, err)
 		}
 
-		others = appendFileUnique(others, thisOthers)
+		ret = appendFileUnique(ret, thisOthers)
+	}
+
+	return ret, nil
+}
+
+func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.Fingerprint) (models.File, error) {
+	others, err := s.findByFingerprints(ctx, fp)
+	if err != nil {
+		return nil, err
 	}
 
 	var missing []models.File
@@ -900,6 +1053,8 @@ func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.F
 			return fmt.Errorf("updating file for rename %q: %w", fBase.Path, err)
 		}
 
+		registerFileHook(ctx, s.PluginCache, plugin.FileMovePost, f, otherBase.Path)
+
 		if s.isZipFile(fBase.Basename) {
 			if err := TransferZipFolderHierarchy(ctx, s.Repository.Folder, fBase.ID, otherBase.Path, fBase.Path); err != nil {
 				return fmt.Errorf("moving folder hierarchy for renamed zip file %q: %w", fBase.Path, err)
@@ -918,6 +1073,92 @@ func (s *scanJob) handleRename(ctx context.Context, f models.File, fp []models.F
 	return f, nil
 }
 
+// isSameFile returns true if both paths refer to the same file on disk.
+// Symlinks are followed.
+func isSameFile(fs models.FS, path, otherPath string) bool {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	otherInfo, err := fs.Stat(otherPath)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(info, otherInfo)
+}
+
+// isExistingLink returns true if the file path is an alternate path of an
+// existing file. If the path no longer refers to the same file, then it is
+// removed as an alternate path, and false is returned.
+func (s *scanJob) isExistingLink(ctx context.Context, f scanFile) (bool, error) {
+	if f.ZipFile != nil {
+		return false, nil
+	}
+
+	linked, err := s.Repository.File.FindByLink(ctx, f.Path)
+	if err != nil {
+		return false, fmt.Errorf("checking for existing link %q: %w", f.Path, err)
+	}
+
+	if linked == nil {
+		return false, nil
+	}
+
+	if isSameFile(f.fs, f.Path, linked.Base().Path) {
+		return true, nil
+	}
+
+	logger.Infof("%s is no longer a link to %s. Removing alternate path...", f.Path, linked.Base().Path)
+	if err := s.withTxn(ctx, func(ctx context.Context) error {
+		return s.Repository.File.DestroyLink(ctx, f.Path)
+	}); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// handleLink adds the file as an alternate path of an existing file with the
+// same fingerprints, if both refer to the same file on disk. This includes
+// hardlinks and symlinks, since isSameFile follows symlinks. Returns true if
+// the file was added as an alternate path.
+func (s *scanJob) handleLink(ctx context.Context, f scanFile, fp []models.Fingerprint) (bool, error) {
+	if f.ZipFile != nil {
+		return false, nil
+	}
+
+	others, err := s.findByFingerprints(ctx, fp)
+	if err != nil {
+		return false, err
+	}
+
+	for _, other := range others {
+		if other.Base().ZipFileID != nil {
+			continue
+		}
+
+		if isSameFile(f.fs, f.Path, other.Base().Path) {
+			return true, s.addLink(ctx, other, f.Path)
+		}
+	}
+
+	return false, nil
+}
+
+func (s *scanJob) addLink(ctx context.Context, existing models.File, path string) error {
+	logger.Infof("%s is a link to %s. Adding as alternate path...", path, existing.Base().Path)
+
+	return s.withTxn(ctx, func(ctx context.Context) error {
+		if err := s.Repository.File.AddLink(ctx, existing.Base().ID, path); err != nil {
+			return fmt.Errorf("adding alternate path %q: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
 func (s *scanJob) isHandlerRequired(ctx context.Context, f models.File) bool {
 	accept := len(s.options.HandlerRequiredFilters) == 0
 	for _, filter := range s.options.HandlerRequiredFilters {
@@ -967,6 +1208,8 @@ func (s *scanJob) setMissingMetadata(ctx context.Context, f scanFile, existing m
 			return fmt.Errorf("updating file %q: %w", path, err)
 		}
 
+		registerFileHook(ctx, s.PluginCache, plugin.FileUpdatePost, existing, "")
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -977,7 +1220,7 @@ func (s *scanJob) setMissingMetadata(ctx context.Context, f scanFile, existing m
 
 func (s *scanJob) setMissingFingerprints(ctx context.Context, f scanFile, existing models.File) (models.File, error) {
 	const useExisting = true
-	fp, err := s.calculateFingerprints(f.fs, existing.Base(), f.Path, useExisting)
+	fp, err := s.calculateFingerprints(ctx, f.fs, existing.Base(), f.Path, useExisting)
 	if err != nil {
 		return nil, err
 	}
@@ -990,6 +1233,8 @@ func (s *scanJob) setMissingFingerprints(ctx context.Context, f scanFile, existi
 				return fmt.Errorf("updating file %q: %w", f.Path, err)
 			}
 
+			registerFileHook(ctx, s.PluginCache, plugin.FileUpdatePost, existing, "")
+
 			return nil
 		}); err != nil {
 			return nil, err
@@ -1020,7 +1265,7 @@ func (s *scanJob) onExistingFile(ctx context.Context, f scanFile, existing model
 
 	// calculate and update fingerprints for the file
 	const useExisting = false
-	fp, err := s.calculateFingerprints(f.fs, base, path, useExisting)
+	fp, err := s.calculateFingerprints(ctx, f.fs, base, path, useExisting)
 	if err != nil {
 		return nil, err
 	}
@@ -1039,6 +1284,8 @@ func (s *scanJob) onExistingFile(ctx context.Context, f scanFile, existing model
 			return fmt.Errorf("updating file %q: %w", path, err)
 		}
 
+		registerFileHook(ctx, s.PluginCache, plugin.FileUpdatePost, existing, "")
+
 		if err := s.fireHandlers(ctx, existing, &oldBase); err != nil {
 			return err
 		}
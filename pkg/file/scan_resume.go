@@ -0,0 +1,129 @@
+package file
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+)
+
+// scanCheckpointInterval is the minimum time between calls to
+// ScanOptions.Checkpoint.
+const scanCheckpointInterval = 10 * time.Second
+
+// ScanPosition is a position in the walk of the scan paths. Every file at or
+// before the position has been scanned.
+type ScanPosition struct {
+	// PathIndex is the index of the scan path being walked.
+	PathIndex int `json:"path_index"`
+	// Path is the last file scanned in the scan path.
+	Path string `json:"path"`
+}
+
+// walkOrderLess returns true if path a is walked before path b. Paths are
+// walked depth first, with the entries of each directory in name order.
+func walkOrderLess(a, b string) bool {
+	aa := strings.Split(filepath.Clean(a), string(filepath.Separator))
+	bb := strings.Split(filepath.Clean(b), string(filepath.Separator))
+
+	for i := 0; i < len(aa) && i < len(bb); i++ {
+		if aa[i] != bb[i] {
+			return aa[i] < bb[i]
+		}
+	}
+
+	// a directory is walked before its contents
+	return len(aa) < len(bb)
+}
+
+// skipResumed returns true if the entry at path in the scan path at pathIndex
+// was scanned before the resume position. Directories are only skipped if
+// all of their contents were scanned.
+func skipResumed(resume *ScanPosition, pathIndex int, path string, isDir bool) bool {
+	if resume == nil || pathIndex > resume.PathIndex {
+		return false
+	}
+
+	if pathIndex < resume.PathIndex {
+		return true
+	}
+
+	if isDir && fsutil.IsPathInDir(path, resume.Path) {
+		return false
+	}
+
+	return !walkOrderLess(resume.Path, path)
+}
+
+// scanCheckpoint tracks the files that have been queued and scanned, to find
+// the position that the scan can be resumed from. Files are queued in walk
+// order but may be scanned out of order, so the position is that of the last
+// file for which all files queued before it have been scanned.
+type scanCheckpoint struct {
+	save func(ScanPosition)
+
+	mutex    sync.Mutex
+	queued   map[int]ScanPosition
+	done     map[int]bool
+	held     map[int]bool
+	next     int
+	position *ScanPosition
+	lastSave time.Time
+}
+
+func newScanCheckpoint(save func(ScanPosition)) *scanCheckpoint {
+	return &scanCheckpoint{
+		save:   save,
+		queued: make(map[int]ScanPosition),
+		done:   make(map[int]bool),
+		held:   make(map[int]bool),
+		next:   1,
+	}
+}
+
+// queue records that the file at pos has been queued as the index'th file.
+func (c *scanCheckpoint) queue(index int, pos ScanPosition) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queued[index] = pos
+}
+
+// hold prevents the file at index from being completed until it is retried.
+func (c *scanCheckpoint) hold(index int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.held[index] = true
+}
+
+// complete records that the file at index has been scanned. Files that are
+// held are only completed when retrying.
+func (c *scanCheckpoint) complete(index int, retrying bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.held[index] {
+		if !retrying {
+			return
+		}
+		delete(c.held, index)
+	}
+
+	c.done[index] = true
+
+	for c.done[c.next] {
+		pos := c.queued[c.next]
+		c.position = &pos
+		delete(c.done, c.next)
+		delete(c.queued, c.next)
+		c.next++
+	}
+
+	if c.position != nil && time.Since(c.lastSave) >= scanCheckpointInterval {
+		c.save(*c.position)
+		c.lastSave = time.Now()
+	}
+}
@@ -0,0 +1,152 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortNewestFirst(t *testing.T) {
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newFile := func(path string, age time.Duration) scanFile {
+		return scanFile{
+			BaseFile: &models.BaseFile{
+				DirEntry: models.DirEntry{
+					ModTime: base.Add(-age),
+				},
+				Path: path,
+			},
+		}
+	}
+
+	files := []scanFile{
+		newFile("old", 48*time.Hour),
+		newFile("new", 0),
+		newFile("same1", time.Hour),
+		newFile("same2", time.Hour),
+	}
+
+	sortNewestFirst(files)
+
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Path)
+	}
+	assert.Equal(t, []string{"new", "same1", "same2", "old"}, got)
+}
+
+func TestSkipResumed(t *testing.T) {
+	resume := &ScanPosition{
+		PathIndex: 1,
+		Path:      filepath.FromSlash("/stash/b/c.mp4"),
+	}
+
+	tests := []struct {
+		name      string
+		pathIndex int
+		path      string
+		isDir     bool
+		want      bool
+	}{
+		{"earlier path", 0, "/other/z.mp4", false, true},
+		{"later path", 2, "/other/a.mp4", false, false},
+		{"root", 1, "/stash", true, false},
+		{"earlier dir", 1, "/stash/a", true, true},
+		{"earlier file", 1, "/stash/a.mp4", false, true},
+		{"containing dir", 1, "/stash/b", true, false},
+		{"earlier sibling", 1, "/stash/b/b.mp4", false, true},
+		{"resume file", 1, "/stash/b/c.mp4", false, true},
+		{"later sibling", 1, "/stash/b/d.mp4", false, false},
+		{"later dir", 1, "/stash/c", true, false},
+		{"later file", 1, "/stash/c.mp4", false, false},
+		{"nil resume", 1, "/stash/a.mp4", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resume
+			if tt.name == "nil resume" {
+				r = nil
+			}
+
+			got := skipResumed(r, tt.pathIndex, filepath.FromSlash(tt.path), tt.isDir)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScanCheckpoint(t *testing.T) {
+	var saved []ScanPosition
+	c := newScanCheckpoint(func(pos ScanPosition) {
+		saved = append(saved, pos)
+	})
+
+	for i := 1; i <= 4; i++ {
+		c.queue(i, ScanPosition{Path: strconv.Itoa(i)})
+	}
+
+	// out of order completion does not advance the position
+	c.complete(2, false)
+	assert.Nil(t, c.position)
+
+	c.complete(1, false)
+	assert.Equal(t, "2", c.position.Path)
+
+	// held files are not completed until retried
+	c.hold(3)
+	c.complete(3, false)
+	c.complete(4, false)
+	assert.Equal(t, "2", c.position.Path)
+
+	c.complete(3, true)
+	assert.Equal(t, "4", c.position.Path)
+
+	// saving is rate limited
+	assert.Len(t, saved, 1)
+}
+
+func TestIsSameFile(t *testing.T) {
+	dir := t.TempDir()
+	fs := &OsFS{}
+
+	original := filepath.Join(dir, "original.mp4")
+	copied := filepath.Join(dir, "copy.mp4")
+	hardlink := filepath.Join(dir, "hardlink.mp4")
+	symlink := filepath.Join(dir, "symlink.mp4")
+
+	writeTestFile(t, original, "contents")
+	writeTestFile(t, copied, "contents")
+
+	if err := os.Link(original, hardlink); err != nil {
+		t.Skipf("hardlinks not supported: %v", err)
+	}
+	if err := os.Symlink(original, symlink); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	assert.True(t, isSameFile(fs, original, hardlink))
+	assert.True(t, isSameFile(fs, symlink, original))
+	assert.True(t, isSameFile(fs, hardlink, symlink))
+	assert.False(t, isSameFile(fs, original, copied))
+	assert.False(t, isSameFile(fs, original, filepath.Join(dir, "missing.mp4")))
+}
+
+func TestHashProgress(t *testing.T) {
+	assert.Nil(t, hashProgress(0, func(float64) {}))
+
+	var got []float64
+	progress := hashProgress(1000, func(pc float64) {
+		got = append(got, pc)
+	})
+
+	for _, read := range []int64{0, 5, 10, 15, 500, 1000} {
+		progress(read)
+	}
+
+	// only called when the whole percentage changes
+	assert.Equal(t, []float64{0, 0.01, 0.5, 1}, got)
+}
@@ -0,0 +1,60 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/bodgit/sevenzip"
+)
+
+// sevenZipArchive is an fs.FS of the contents of a 7z archive.
+type sevenZipArchive struct {
+	*sevenzip.Reader
+	files map[string]*sevenzip.File
+}
+
+func newSevenZipFS(reader io.ReadCloser, asReaderAt io.ReaderAt, path string, info fs.FileInfo) (*zipFS, error) {
+	r, err := sevenzip.NewReader(asReaderAt, info.Size())
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return &zipFS{
+		archive:       newSevenZipArchive(r),
+		zipFileCloser: reader,
+		zipInfo:       info,
+		zipPath:       path,
+	}, nil
+}
+
+func newSevenZipArchive(r *sevenzip.Reader) *sevenZipArchive {
+	ret := &sevenZipArchive{
+		Reader: r,
+		files:  make(map[string]*sevenzip.File),
+	}
+
+	for _, f := range r.File {
+		ret.files[path.Clean(f.Name)] = f
+	}
+
+	return ret
+}
+
+// Stat returns the file info of the named file. Files are not opened, since
+// opening a file in a solid archive decompresses the preceding files.
+func (a *sevenZipArchive) Stat(name string) (fs.FileInfo, error) {
+	if f, found := a.files[name]; found && fs.ValidPath(name) {
+		return f.FileInfo(), nil
+	}
+
+	// directories may not have an entry in the archive
+	f, err := a.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.Stat()
+}
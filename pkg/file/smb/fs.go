@@ -0,0 +1,263 @@
+// Package smb provides a file system backed by an SMB share.
+package smb
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hirochachacha/go-smb2"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	defaultPort = "445"
+	dialTimeout = 30 * time.Second
+)
+
+// Share is the location of a directory in an SMB share.
+type Share struct {
+	// Addr is the host and port of the server.
+	Addr string
+	// Name is the name of the share.
+	Name string
+	// Root is the path of the directory within the share, using "/" as the
+	// separator. Empty for the root of the share.
+	Root string
+}
+
+// ParseURL returns the share location of an smb URL, in the form
+// smb://host[:port]/share[/path].
+func ParseURL(u *url.URL) (*Share, error) {
+	if u.Scheme != "smb" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return nil, errors.New("host is required")
+	}
+
+	p := strings.Trim(u.Path, "/")
+	name, root, _ := strings.Cut(p, "/")
+	if name == "" {
+		return nil, errors.New("share name is required")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return &Share{
+		Addr: net.JoinHostPort(u.Hostname(), port),
+		Name: name,
+		Root: root,
+	}, nil
+}
+
+// FS is a read-only file system of a directory in an SMB share, mounted at a
+// local path. The connection to the server is made when the file system is
+// first used, and is remade if it is lost.
+type FS struct {
+	share     Share
+	initiator *smb2.NTLMInitiator
+	mountPath string
+
+	mutex   sync.Mutex
+	conn    net.Conn
+	session *smb2.Session
+	mounted *smb2.Share
+}
+
+// New returns a FS of the share directory, mounted at mountPath. The username
+// may include the domain, in the form DOMAIN\username.
+func New(mountPath string, share Share, username, password string) *FS {
+	initiator := &smb2.NTLMInitiator{
+		User:     username,
+		Password: password,
+	}
+
+	if domain, user, found := strings.Cut(username, `\`); found {
+		initiator.Domain = domain
+		initiator.User = user
+	}
+
+	return &FS{
+		share:     share,
+		initiator: initiator,
+		mountPath: filepath.Clean(mountPath),
+	}
+}
+
+// MountPath returns the path that the share directory is mounted at.
+func (f *FS) MountPath() string {
+	return f.mountPath
+}
+
+// sharePath returns the path of name within the share.
+func (f *FS) sharePath(name string) (string, bool) {
+	rel, ok := file.MountRelPath(f.mountPath, name)
+	if !ok {
+		return "", false
+	}
+
+	// the root of the share is an empty path
+	p := path.Join(f.share.Root, rel)
+	if p == "." {
+		p = ""
+	}
+
+	return p, true
+}
+
+func (f *FS) connect() (*smb2.Share, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.mounted != nil {
+		return f.mounted, nil
+	}
+
+	conn, err := net.DialTimeout("tcp", f.share.Addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &smb2.Dialer{
+		Initiator: f.initiator,
+	}
+
+	session, err := d.Dial(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connecting to %s: %w", f.share.Addr, err)
+	}
+
+	mounted, err := session.Mount(f.share.Name)
+	if err != nil {
+		_ = session.Logoff()
+		conn.Close()
+		return nil, fmt.Errorf("mounting share %s: %w", f.share.Name, err)
+	}
+
+	f.conn = conn
+	f.session = session
+	f.mounted = mounted
+
+	return mounted, nil
+}
+
+// checkConnection closes the connection if err indicates that it has been
+// lost, so that it is remade when the file system is next used.
+func (f *FS) checkConnection(err error) {
+	var transportErr *smb2.TransportError
+	if errors.As(err, &transportErr) || errors.Is(err, net.ErrClosed) {
+		f.Close()
+	}
+}
+
+// Close closes the connection to the server, if connected.
+func (f *FS) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.mounted == nil {
+		return nil
+	}
+
+	_ = f.mounted.Umount()
+	_ = f.session.Logoff()
+	err := f.conn.Close()
+
+	f.conn = nil
+	f.session = nil
+	f.mounted = nil
+
+	return err
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	p, ok := f.sharePath(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	share, err := f.connect()
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	info, err := share.Stat(p)
+	if err != nil {
+		f.checkConnection(err)
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Lstat is equivalent to Stat. Symlinks are resolved by the server.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *FS) Open(name string) (fs.ReadDirFile, error) {
+	p, ok := f.sharePath(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	share, err := f.connect()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	sf, err := share.Open(p)
+	if err != nil {
+		f.checkConnection(err)
+		return nil, err
+	}
+
+	return &smbFile{sf}, nil
+}
+
+func (f *FS) OpenZip(name string) (models.ZipFS, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file.OpenNestedArchive(f, name)
+		}
+		return nil, err
+	}
+
+	return file.OpenArchive(f, name, info)
+}
+
+// IsPathCaseSensitive returns false, since SMB shares are case insensitive.
+func (f *FS) IsPathCaseSensitive(path string) (bool, error) {
+	return false, nil
+}
+
+// smbFile adapts an smb2.File to fs.ReadDirFile.
+type smbFile struct {
+	*smb2.File
+}
+
+func (f *smbFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	infos, err := f.Readdir(n)
+
+	ret := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		ret[i] = fs.FileInfoToDirEntry(info)
+	}
+
+	return ret, err
+}
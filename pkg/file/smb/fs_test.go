@@ -0,0 +1,68 @@
+package smb
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    *Share
+		wantErr bool
+	}{
+		{"smb://nas/media", &Share{Addr: "nas:445", Name: "media"}, false},
+		{"smb://nas:1445/media/", &Share{Addr: "nas:1445", Name: "media"}, false},
+		{"smb://nas/media/library/videos", &Share{Addr: "nas:445", Name: "media", Root: "library/videos"}, false},
+		{"smb://nas", nil, true},
+		{"smb:///media", nil, true},
+		{"http://nas/media", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("parsing url: %v", err)
+			}
+
+			got, err := ParseURL(u)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFS_sharePath(t *testing.T) {
+	mountPath := filepath.FromSlash("/mnt/share")
+
+	tests := []struct {
+		name   string
+		root   string
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"share root", "", mountPath, "", true},
+		{"share file", "", filepath.Join(mountPath, "a.mp4"), "a.mp4", true},
+		{"directory root", "library", mountPath, "library", true},
+		{"directory file", "library", filepath.Join(mountPath, "sub", "a.mp4"), "library/sub/a.mp4", true},
+		{"outside mount", "", filepath.FromSlash("/mnt/other/a.mp4"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(mountPath, Share{Addr: "nas:445", Name: "media", Root: tt.root}, `DOMAIN\user`, "password")
+			assert.Equal(t, "DOMAIN", f.initiator.Domain)
+			assert.Equal(t, "user", f.initiator.User)
+
+			got, ok := f.sharePath(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const trashInfoSuffix = ".json"
+
+// ErrTrashEntryNotFound is returned when restoring an entry that is not in the
+// trash.
+var ErrTrashEntryNotFound = errors.New("trash entry not found")
+
+// TrashEntry is a file in the trash.
+type TrashEntry struct {
+	ID string `json:"id"`
+	// Path is the path that the file was deleted from.
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Trash is a directory that deleted files are moved to, so that they can be
+// restored. Each file is stored under its entry ID, with a JSON file
+// containing the entry alongside it.
+type Trash struct {
+	Path string
+}
+
+func newTrashID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(b)), nil
+}
+
+// validTrashID returns true if id cannot refer to a path outside of the trash.
+func validTrashID(id string) bool {
+	return id != "" && id == filepath.Base(id) && id != "." && id != ".."
+}
+
+func (t *Trash) filePath(id string) string {
+	return filepath.Join(t.Path, id)
+}
+
+func (t *Trash) infoPath(id string) string {
+	return filepath.Join(t.Path, id+trashInfoSuffix)
+}
+
+// Add moves the file at src into the trash, recording that it was deleted
+// from originalPath.
+func (t *Trash) Add(src string, originalPath string) (*TrashEntry, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fsutil.EnsureDir(t.Path); err != nil {
+		return nil, fmt.Errorf("creating trash directory: %w", err)
+	}
+
+	id, err := newTrashID()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &TrashEntry{
+		ID:        id,
+		Path:      originalPath,
+		Size:      info.Size(),
+		DeletedAt: time.Now(),
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(t.infoPath(id), data, 0644); err != nil {
+		return nil, fmt.Errorf("writing trash entry: %w", err)
+	}
+
+	if err := fsutil.SafeMove(src, t.filePath(id)); err != nil {
+		_ = os.Remove(t.infoPath(id))
+		return nil, fmt.Errorf("moving %q to trash: %w", src, err)
+	}
+
+	return entry, nil
+}
+
+func (t *Trash) readEntry(id string) (*TrashEntry, error) {
+	if !validTrashID(id) {
+		return nil, ErrTrashEntryNotFound
+	}
+
+	data, err := os.ReadFile(t.infoPath(id))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrTrashEntryNotFound
+		}
+		return nil, err
+	}
+
+	var ret TrashEntry
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("reading trash entry %s: %w", id, err)
+	}
+
+	return &ret, nil
+}
+
+// List returns the entries in the trash, most recently deleted first.
+func (t *Trash) List() ([]*TrashEntry, error) {
+	dirEntries, err := os.ReadDir(t.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret []*TrashEntry
+	for _, d := range dirEntries {
+		name := d.Name()
+		if d.IsDir() || !strings.HasSuffix(name, trashInfoSuffix) {
+			continue
+		}
+
+		id := strings.TrimSuffix(name, trashInfoSuffix)
+
+		entry, err := t.readEntry(id)
+		if err != nil {
+			logger.Warnf("%v", err)
+			continue
+		}
+
+		ret = append(ret, entry)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].DeletedAt.After(ret[j].DeletedAt)
+	})
+
+	return ret, nil
+}
+
+// Restore moves the file of the entry back to the path it was deleted from.
+// Returns an error if a file now exists at that path.
+func (t *Trash) Restore(id string) (*TrashEntry, error) {
+	entry, err := t.readEntry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(entry.Path); !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("cannot restore to %q: file already exists", entry.Path)
+	}
+
+	if err := fsutil.EnsureDir(filepath.Dir(entry.Path)); err != nil {
+		return nil, fmt.Errorf("creating directory for %q: %w", entry.Path, err)
+	}
+
+	if err := fsutil.SafeMove(t.filePath(id), entry.Path); err != nil {
+		return nil, fmt.Errorf("restoring %q: %w", entry.Path, err)
+	}
+
+	if err := os.Remove(t.infoPath(id)); err != nil {
+		logger.Warnf("error removing trash entry %s: %v", id, err)
+	}
+
+	return entry, nil
+}
+
+// Remove permanently deletes the file of the entry.
+func (t *Trash) Remove(id string) error {
+	if !validTrashID(id) {
+		return ErrTrashEntryNotFound
+	}
+
+	if err := os.Remove(t.filePath(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return os.Remove(t.infoPath(id))
+}
+
+// Purge permanently deletes the files that were deleted before the given
+// time. Returns the number of files deleted.
+func (t *Trash) Purge(before time.Time) (int, error) {
+	entries, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, e := range entries {
+		if !e.DeletedAt.Before(before) {
+			continue
+		}
+
+		if err := t.Remove(e.ID); err != nil {
+			logger.Warnf("error purging %q from trash: %v", e.Path, err)
+			continue
+		}
+
+		n++
+	}
+
+	return n, nil
+}
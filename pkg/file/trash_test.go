@@ -0,0 +1,133 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	dir := t.TempDir()
+	trash := &Trash{Path: filepath.Join(dir, "trash")}
+
+	// nothing in a trash that has not been created
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assert.Empty(t, entries)
+
+	p := filepath.Join(dir, "library", "a.mp4")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatalf("creating library: %v", err)
+	}
+	writeTestFile(t, p, "video")
+
+	entry, err := trash.Add(p, p)
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	assert.Equal(t, p, entry.Path)
+	assert.Equal(t, int64(5), entry.Size)
+	assert.NoFileExists(t, p)
+
+	entries, err = trash.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	assert.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+
+	// cannot restore over an existing file
+	writeTestFile(t, p, "other")
+	_, err = trash.Restore(entry.ID)
+	assert.Error(t, err)
+	if err := os.Remove(p); err != nil {
+		t.Fatalf("removing file: %v", err)
+	}
+
+	if _, err := trash.Restore(entry.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	assert.FileExists(t, p)
+
+	_, err = trash.Restore(entry.ID)
+	assert.ErrorIs(t, err, ErrTrashEntryNotFound)
+
+	// ids cannot refer to files outside of the trash
+	_, err = trash.Restore("../library/a.mp4")
+	assert.ErrorIs(t, err, ErrTrashEntryNotFound)
+}
+
+func TestTrash_Purge(t *testing.T) {
+	dir := t.TempDir()
+	trash := &Trash{Path: filepath.Join(dir, "trash")}
+
+	p := filepath.Join(dir, "a.mp4")
+	writeTestFile(t, p, "video")
+
+	if _, err := trash.Add(p, p); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	n, err := trash.Purge(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	assert.Equal(t, 0, n)
+
+	n, err = trash.Purge(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	assert.Equal(t, 1, n)
+
+	entries, err := os.ReadDir(trash.Path)
+	if err != nil {
+		t.Fatalf("reading trash: %v", err)
+	}
+	assert.Empty(t, entries)
+}
+
+func TestDeleter_TrashFiles(t *testing.T) {
+	dir := t.TempDir()
+	trash := &Trash{Path: filepath.Join(dir, "trash")}
+
+	trashed := filepath.Join(dir, "a.mp4")
+	deleted := filepath.Join(dir, "a.jpg")
+	writeTestFile(t, trashed, "video")
+	writeTestFile(t, deleted, "image")
+
+	d := NewDeleter()
+	d.Trash = trash
+
+	if err := d.TrashFiles([]string{trashed}); err != nil {
+		t.Fatalf("TrashFiles: %v", err)
+	}
+	if err := d.Files([]string{deleted}); err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+
+	d.Commit()
+
+	assert.NoFileExists(t, trashed)
+	assert.NoFileExists(t, deleted)
+
+	entries, err := trash.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, trashed, entries[0].Path)
+	}
+}
@@ -0,0 +1,175 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const verifyBatchSize = 100
+
+// Verifier re-calculates the fingerprints of stored files and records files
+// whose contents no longer match the stored fingerprints.
+type Verifier struct {
+	FS                    models.FS
+	Repository            Repository
+	FingerprintCalculator FingerprintCalculator
+}
+
+// VerifyOptions provides options for verifying files.
+type VerifyOptions struct {
+	Paths []string
+
+	// Limit is the maximum number of files to verify. Files that were least
+	// recently verified are verified first. All files are verified if < 0.
+	Limit int
+}
+
+// Verify verifies the files in the provided paths. Files whose size or
+// modification time have changed since they were last scanned are skipped,
+// since a change in contents is expected. Files in zip files are not verified.
+//
+// The verification is only as thorough as the fingerprints calculated by the
+// FingerprintCalculator. A video file with only an oshash fingerprint is only
+// quickly checked, since the oshash is calculated from the start and end of
+// the file.
+func (v *Verifier) Verify(ctx context.Context, options VerifyOptions, progress *job.Progress) {
+	start := time.Now()
+	r := v.Repository
+
+	var total int
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		total, err = r.File.CountForVerification(ctx, options.Paths)
+		return err
+	}); err != nil {
+		logger.Errorf("error counting files to verify: %v", err)
+		return
+	}
+
+	if options.Limit >= 0 && options.Limit < total {
+		total = options.Limit
+	}
+
+	progress.SetTotal(total)
+
+	verified := 0
+	failed := 0
+	for options.Limit < 0 || verified < options.Limit {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		limit := verifyBatchSize
+		if options.Limit >= 0 && options.Limit-verified < limit {
+			limit = options.Limit - verified
+		}
+
+		var files []models.File
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			var err error
+			files, err = r.File.FindForVerification(ctx, options.Paths, start, limit)
+			return err
+		}); err != nil {
+			logger.Errorf("error finding files to verify: %v", err)
+			return
+		}
+
+		if len(files) == 0 {
+			break
+		}
+
+		for _, f := range files {
+			if job.IsCancelled(ctx) {
+				return
+			}
+
+			result := models.FileVerification{
+				FileID: f.Base().ID,
+			}
+
+			cancelled := false
+			progress.ExecuteTaskWithProgress(fmt.Sprintf("Verifying %s", f.Base().Path), func(setPercent func(float64)) {
+				err := v.verifyFile(ctx, f.Base(), hashProgress(f.Base().Size, setPercent))
+				switch {
+				case err == nil:
+				case errors.Is(err, context.Canceled):
+					cancelled = true
+				default:
+					logger.Warnf("File %q failed verification: %v", f.Base().Path, err)
+					result.Error = err.Error()
+					failed++
+				}
+			})
+
+			// don't record files that were not fully verified
+			if cancelled {
+				return
+			}
+
+			result.VerifiedAt = time.Now()
+
+			// stop on error, otherwise the file would be returned again
+			if err := r.WithTxn(ctx, func(ctx context.Context) error {
+				return r.File.UpdateVerification(ctx, result)
+			}); err != nil {
+				logger.Errorf("error recording verification of %q: %v", f.Base().Path, err)
+				return
+			}
+
+			verified++
+			progress.Increment()
+		}
+	}
+
+	logger.Infof("Verified %d files, %d failed verification", verified, failed)
+}
+
+func (v *Verifier) verifyFile(ctx context.Context, f *models.BaseFile, progress func(read int64)) error {
+	info, err := v.FS.Stat(f.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return errors.New("file not found")
+		}
+		return err
+	}
+
+	if info.Size() != f.Size || !modTime(info).Equal(f.ModTime) {
+		logger.Debugf("Skipping verification of modified file %q", f.Path)
+		return nil
+	}
+
+	fp, err := v.FingerprintCalculator.CalculateFingerprints(ctx, f, &fsOpener{
+		fs:   v.FS,
+		name: f.Path,
+	}, false, progress)
+	if err != nil {
+		return fmt.Errorf("calculating fingerprints: %w", err)
+	}
+
+	return compareFingerprints(f.Fingerprints, fp)
+}
+
+// compareFingerprints returns an error if any of the calculated fingerprints
+// do not match the stored fingerprint of the same type.
+func compareFingerprints(stored models.Fingerprints, calculated []models.Fingerprint) error {
+	for _, c := range calculated {
+		c := c
+		s := stored.For(c.Type)
+		if s == nil {
+			continue
+		}
+
+		if s.Value() != c.Value() {
+			return fmt.Errorf("%s mismatch: expected %s, got %s", c.Type, s.Value(), c.Value())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestCompareFingerprints(t *testing.T) {
+	stored := models.Fingerprints{
+		{Type: models.FingerprintTypeMD5, Fingerprint: "abc"},
+		{Type: models.FingerprintTypeOshash, Fingerprint: "def"},
+	}
+
+	tests := []struct {
+		name       string
+		calculated []models.Fingerprint
+		wantErr    bool
+	}{
+		{
+			"matching",
+			[]models.Fingerprint{
+				{Type: models.FingerprintTypeMD5, Fingerprint: "abc"},
+				{Type: models.FingerprintTypeOshash, Fingerprint: "def"},
+			},
+			false,
+		},
+		{
+			"mismatch",
+			[]models.Fingerprint{
+				{Type: models.FingerprintTypeMD5, Fingerprint: "abc"},
+				{Type: models.FingerprintTypeOshash, Fingerprint: "xyz"},
+			},
+			true,
+		},
+		{
+			"not stored",
+			[]models.Fingerprint{
+				{Type: models.FingerprintTypePhash, Fingerprint: int64(1)},
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := compareFingerprints(stored, tt.calculated); (err != nil) != tt.wantErr {
+				t.Errorf("compareFingerprints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,15 +7,54 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/asticode/go-astisub"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/txn"
 	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
 )
 
-var CaptionExts = []string{"vtt", "srt"} // in a case where vtt and srt files are both provided prioritize vtt file due to native support
+var CaptionExts = []string{"vtt", "srt", "ass", "ssa"} // in a case where vtt and srt files are both provided prioritize vtt file due to native support
+
+// captionFlags are words in caption filenames that describe the captions
+// rather than their language, for example video.en.forced.srt.
+var captionFlags = []string{"forced", "sdh", "cc", "default", "full"}
+
+// captionNamedLanguages are the languages that are also detected by their
+// English or native names in caption filenames, for example
+// video.English.srt.
+var captionNamedLanguages = []string{
+	"ar", "cs", "da", "de", "el", "en", "es", "fi", "fr", "he", "hi", "hu",
+	"id", "it", "ja", "ko", "nl", "no", "pl", "pt", "ro", "ru", "sv", "th",
+	"tr", "uk", "vi", "zh",
+}
+
+var (
+	captionLanguageNamesOnce sync.Once
+	captionLanguageNames     map[string]string
+)
+
+// languageFromName returns the language code for a lowercase language name.
+func languageFromName(name string) (string, bool) {
+	captionLanguageNamesOnce.Do(func() {
+		captionLanguageNames = make(map[string]string)
+		for _, code := range captionNamedLanguages {
+			tag := language.MustParse(code)
+			for _, n := range []string{display.English.Languages().Name(tag), display.Self.Name(tag)} {
+				if n != "" {
+					captionLanguageNames[strings.ToLower(n)] = code
+				}
+			}
+		}
+	})
+
+	ret, found := captionLanguageNames[name]
+	return ret, found
+}
 
 // to be used for captions without a language code in the filename
 // ISO 639-1 uses 2 or 3 a-z chars for codes so 00 is a safe non valid choise
@@ -59,30 +98,112 @@ func IsLangInCaptions(lang string, ext string, captions []*models.VideoCaption)
 	return false
 }
 
-// getCaptionPrefix returns the prefix used to search for video files for the provided caption path
-func getCaptionPrefix(captionPath string) string {
+// parseCaptionLanguage returns the ISO 639-1 code, or ISO 639-3 code if
+// there is none, of a language code or name in a caption filename. Codes may
+// include a region, such as pt-BR or pt_BR, which is ignored.
+func parseCaptionLanguage(v string) (string, bool) {
+	v = strings.ToLower(v)
+	if code, found := languageFromName(v); found {
+		return code, true
+	}
+
+	if len(v) < 2 {
+		return "", false
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(v, "_", "-"))
+	if err != nil {
+		return "", false
+	}
+
+	base, confidence := tag.Base()
+	if confidence != language.Exact || base.String() == "und" {
+		return "", false
+	}
+
+	return base.String(), true
+}
+
+func isCaptionFlag(v string) bool {
+	v = strings.ToLower(v)
+	for _, f := range captionFlags {
+		if v == f {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCaptionPath returns the prefix used to search for the video files of
+// the caption path, and the language of the captions. A caption file can be
+// named like video.srt, video.en.srt, video.English.srt or
+// video.en.forced.srt. LangUnknown is returned if no language is present.
+func parseCaptionPath(captionPath string) (prefix string, lang string) {
 	basename := strings.TrimSuffix(captionPath, filepath.Ext(captionPath)) // caption filename without the extension
+	lang = LangUnknown
 
-	// a caption file can be something like scene_filename.srt or scene_filename.en.srt
-	// if a language code is present and valid remove it from the basename
-	languageExt := filepath.Ext(basename)
-	if len(languageExt) > 2 && IsValidLanguage(languageExt[1:]) {
-		basename = strings.TrimSuffix(basename, languageExt)
+	for {
+		suffix := filepath.Ext(basename)
+		if len(suffix) < 2 {
+			break
+		}
+
+		if isCaptionFlag(suffix[1:]) {
+			basename = strings.TrimSuffix(basename, suffix)
+			continue
+		}
+
+		if lang == LangUnknown {
+			if l, ok := parseCaptionLanguage(suffix[1:]); ok {
+				lang = l
+				basename = strings.TrimSuffix(basename, suffix)
+				continue
+			}
+		}
+
+		break
 	}
 
-	return basename + "."
+	return basename + ".", lang
 }
 
-// GetCaptionsLangFromPath returns the language code from a given captions path
+// getCaptionPrefix returns the prefix used to search for video files for the provided caption path
+func getCaptionPrefix(captionPath string) string {
+	prefix, _ := parseCaptionPath(captionPath)
+	return prefix
+}
+
+// getCaptionsLangFromPath returns the language code from a given captions path
 // If no valid language is present LangUknown is returned
 func getCaptionsLangFromPath(captionPath string) string {
-	langCode := LangUnknown
-	basename := strings.TrimSuffix(captionPath, filepath.Ext(captionPath)) // caption filename without the extension
-	languageExt := filepath.Ext(basename)
-	if len(languageExt) > 2 && IsValidLanguage(languageExt[1:]) {
-		langCode = languageExt[1:]
+	_, lang := parseCaptionPath(captionPath)
+	return lang
+}
+
+// matchesCaptionPrefix returns true if the video path without its extension
+// matches the prefix of a caption path.
+func matchesCaptionPrefix(prefix string, videoPath string) bool {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath))+"." == prefix
+}
+
+// IsCaptionFor returns true if captionPath is a caption file for the video
+// file at videoPath.
+func IsCaptionFor(captionPath string, videoPath string) bool {
+	if !fsutil.MatchExtension(captionPath, CaptionExts) {
+		return false
 	}
-	return langCode
+
+	prefix, _ := parseCaptionPath(captionPath)
+	return matchesCaptionPrefix(prefix, videoPath)
+}
+
+func hasCaptionFile(captions []*models.VideoCaption, filename string) bool {
+	for _, c := range captions {
+		if c.Filename == filename {
+			return true
+		}
+	}
+	return false
 }
 
 type CaptionUpdater interface {
@@ -90,17 +211,27 @@ type CaptionUpdater interface {
 	UpdateCaptions(ctx context.Context, fileID models.FileID, captions []*models.VideoCaption) error
 }
 
+// hasCaptionKey returns true if captions has a caption with the language and
+// type, which together identify the captions of a file.
+func hasCaptionKey(captions []*models.VideoCaption, lang string, captionType string) bool {
+	for _, c := range captions {
+		if c.LanguageCode == lang && c.CaptionType == captionType {
+			return true
+		}
+	}
+	return false
+}
+
 // associates captions to scene/s with the same basename
 func AssociateCaptions(ctx context.Context, captionPath string, txnMgr txn.Manager, fqb models.FileFinder, w CaptionUpdater) {
-	captionLang := getCaptionsLangFromPath(captionPath)
+	captionPrefix, captionLang := parseCaptionPath(captionPath)
+	filename := filepath.Base(captionPath)
+	captionType := filepath.Ext(captionPath)[1:]
 
-	captionPrefix := getCaptionPrefix(captionPath)
 	if err := txn.WithTxn(ctx, txnMgr, func(ctx context.Context) error {
-		var err error
-		files, er := fqb.FindAllByPath(ctx, captionPrefix+"*")
-
-		if er != nil {
-			return fmt.Errorf("searching for scene %s: %w", captionPrefix, er)
+		files, err := fqb.FindAllByPath(ctx, captionPrefix+"*")
+		if err != nil {
+			return fmt.Errorf("searching for scene %s: %w", captionPrefix, err)
 		}
 
 		for _, f := range files {
@@ -116,28 +247,44 @@ func AssociateCaptions(ctx context.Context, captionPath string, txnMgr txn.Manag
 			fileID := f.Base().ID
 			path := f.Base().Path
 
+			// the prefix search also matches longer names, such as
+			// video.part2.mp4 for video.srt
+			if !matchesCaptionPrefix(captionPrefix, path) {
+				continue
+			}
+
 			logger.Debugf("Matched captions to file %s", path)
-			captions, er := w.GetCaptions(ctx, fileID)
-			if er == nil {
-				fileExt := filepath.Ext(captionPath)
-				ext := fileExt[1:]
-				if !IsLangInCaptions(captionLang, ext, captions) { // only update captions if language code is not present
-					newCaption := &models.VideoCaption{
-						LanguageCode: captionLang,
-						Filename:     filepath.Base(captionPath),
-						CaptionType:  ext,
-					}
-					captions = append(captions, newCaption)
-					er = w.UpdateCaptions(ctx, fileID, captions)
-					if er == nil {
-						logger.Debugf("Updated captions for file %s. Added %s", path, captionLang)
-					}
-				}
+			captions, err := w.GetCaptions(ctx, fileID)
+			if err != nil {
+				return fmt.Errorf("getting captions for file %s: %w", path, err)
 			}
+
+			// only update captions if the file is not already associated
+			if hasCaptionFile(captions, filename) {
+				continue
+			}
+
+			// a file can only have one caption of each language and type
+			if hasCaptionKey(captions, captionLang, captionType) {
+				logger.Infof("Not adding caption %s to file %s: it already has a %s caption of language %s", filename, path, captionType, captionLang)
+				continue
+			}
+
+			captions = append(captions, &models.VideoCaption{
+				LanguageCode: captionLang,
+				Filename:     filename,
+				CaptionType:  captionType,
+			})
+			if err := w.UpdateCaptions(ctx, fileID, captions); err != nil {
+				return fmt.Errorf("updating captions for file %s: %w", path, err)
+			}
+
+			logger.Debugf("Updated captions for file %s. Added %s", path, captionLang)
 		}
-		return err
+
+		return nil
 	}); err != nil {
-		logger.Error(err.Error())
+		logger.Errorf("error associating caption %s: %v", captionPath, err)
 	}
 }
 
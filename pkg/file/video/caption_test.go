@@ -1,9 +1,14 @@
 package video
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type testCase struct {
@@ -38,6 +43,51 @@ var testCases = []testCase{
 		expectedLang:   LangUnknown,
 		expectedResult: "C:\\videos\\video.xx.", // no lang code/lang code invalid xx should remain
 	},
+	{
+		captionPath:    "/stash/video.eng.ass",
+		expectedLang:   "en",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.pt-BR.srt",
+		expectedLang:   "pt",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.pt_BR.srt",
+		expectedLang:   "pt",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.English.srt",
+		expectedLang:   "en",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.Deutsch.srt",
+		expectedLang:   "de",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.en.forced.srt",
+		expectedLang:   "en",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.SDH.en.ssa",
+		expectedLang:   "en",
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/video.forced.vtt",
+		expectedLang:   LangUnknown,
+		expectedResult: "/stash/video.",
+	},
+	{
+		captionPath:    "/stash/My.Video.2020.srt",
+		expectedLang:   LangUnknown,
+		expectedResult: "/stash/My.Video.2020.",
+	},
 }
 
 func TestGenerateCaptionCandidates(t *testing.T) {
@@ -51,3 +101,75 @@ func TestGetCaptionsLangFromPath(t *testing.T) {
 		assert.Equal(t, l.expectedLang, getCaptionsLangFromPath(l.captionPath))
 	}
 }
+
+func TestIsCaptionFor(t *testing.T) {
+	tests := []struct {
+		captionPath string
+		videoPath   string
+		want        bool
+	}{
+		{"/stash/video.srt", "/stash/video.mp4", true},
+		{"/stash/video.en.forced.ass", "/stash/video.mkv", true},
+		{"/stash/video.srt", "/stash/video.part2.mp4", false},
+		{"/stash/video.srt", "/other/video.mp4", false},
+		{"/stash/video.txt", "/stash/video.mp4", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsCaptionFor(tt.captionPath, tt.videoPath), tt.captionPath)
+	}
+}
+
+type testCaptionUpdater struct {
+	captions map[models.FileID][]*models.VideoCaption
+}
+
+func (u *testCaptionUpdater) GetCaptions(ctx context.Context, fileID models.FileID) ([]*models.VideoCaption, error) {
+	return u.captions[fileID], nil
+}
+
+func (u *testCaptionUpdater) UpdateCaptions(ctx context.Context, fileID models.FileID, captions []*models.VideoCaption) error {
+	seen := make(map[string]bool)
+	for _, c := range captions {
+		// mirrors the primary key of the captions table
+		key := c.LanguageCode + "." + c.CaptionType
+		if seen[key] {
+			return errors.New("UNIQUE constraint failed")
+		}
+		seen[key] = true
+	}
+
+	u.captions[fileID] = captions
+	return nil
+}
+
+func TestAssociateCaptions(t *testing.T) {
+	const fileID = models.FileID(1)
+	db := mocks.NewDatabase()
+	db.File.On("FindAllByPath", mock.Anything, "/stash/video.*").Return([]models.File{
+		&models.VideoFile{
+			BaseFile: &models.BaseFile{
+				ID:   fileID,
+				Path: "/stash/video.mp4",
+			},
+		},
+	}, nil)
+
+	u := &testCaptionUpdater{
+		captions: make(map[models.FileID][]*models.VideoCaption),
+	}
+
+	ctx := context.Background()
+	AssociateCaptions(ctx, "/stash/video.en.srt", db, db.File, u)
+	// same language and type as the existing caption
+	AssociateCaptions(ctx, "/stash/video.english.srt", db, db.File, u)
+	AssociateCaptions(ctx, "/stash/video.en.vtt", db, db.File, u)
+	// already associated
+	AssociateCaptions(ctx, "/stash/video.en.srt", db, db.File, u)
+
+	var filenames []string
+	for _, c := range u.captions[fileID] {
+		filenames = append(filenames, c.Filename)
+	}
+	assert.Equal(t, []string{"video.en.srt", "video.en.vtt"}, filenames)
+}
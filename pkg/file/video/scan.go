@@ -22,7 +22,7 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 
 	base := f.Base()
 	// TODO - copy to temp file if not an OsFS
-	if _, isOs := fs.(*file.OsFS); !isOs {
+	if !file.IsPathFS(fs) {
 		return f, fmt.Errorf("video.constructFile: only OsFS is supported")
 	}
 
@@ -44,16 +44,17 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 	}
 
 	return &models.VideoFile{
-		BaseFile:    base,
-		Format:      string(container),
-		VideoCodec:  videoFile.VideoCodec,
-		AudioCodec:  videoFile.AudioCodec,
-		Width:       videoFile.Width,
-		Height:      videoFile.Height,
-		Duration:    videoFile.FileDuration,
-		FrameRate:   videoFile.FrameRate,
-		BitRate:     videoFile.Bitrate,
-		Interactive: interactive,
+		BaseFile:      base,
+		Format:        string(container),
+		VideoCodec:    videoFile.VideoCodec,
+		AudioCodec:    videoFile.AudioCodec,
+		Width:         videoFile.Width,
+		Height:        videoFile.Height,
+		Duration:      videoFile.FileDuration,
+		FrameRate:     videoFile.FrameRate,
+		BitRate:       videoFile.Bitrate,
+		ColorTransfer: videoFile.ColorTransfer,
+		Interactive:   interactive,
 	}, nil
 }
 
@@ -77,5 +78,6 @@ func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f model
 		vf.Format == unsetString || vf.Width == unsetNumber ||
 		vf.Height == unsetNumber || vf.FrameRate == unsetNumber ||
 		vf.Duration == unsetNumber ||
-		vf.BitRate == unsetNumber || interactive != vf.Interactive
+		vf.BitRate == unsetNumber || vf.ColorTransfer == unsetString ||
+		interactive != vf.Interactive
 }
@@ -50,7 +50,7 @@ import (
 //
 // Note that symwalk.Walk does not terminate if there are any non-terminating loops in
 // the file structure.
-func walkSym(f models.FS, filename string, linkDirname string, walkFn fs.WalkDirFunc) error {
+func walkSym(f models.FS, r *dirReader, filename string, linkDirname string, walkFn fs.WalkDirFunc) error {
 	symWalkFunc := func(path string, info fs.DirEntry, err error) error {
 
 		if fname, err := filepath.Rel(filename, path); err == nil {
@@ -72,18 +72,33 @@ func walkSym(f models.FS, filename string, linkDirname string, walkFn fs.WalkDir
 				}, err)
 			}
 			if info.IsDir() {
-				return walkSym(f, finalPath, path, walkFn)
+				return walkSym(f, r, finalPath, path, walkFn)
 			}
 		}
 
 		return walkFn(path, info, err)
 	}
-	return fsWalk(f, filename, symWalkFunc)
+	return fsWalk(f, r, filename, symWalkFunc)
 }
 
 // symWalk extends filepath.Walk to also follow symlinks
 func symWalk(fs models.FS, path string, walkFn fs.WalkDirFunc) error {
-	return walkSym(fs, path, path, walkFn)
+	return walkSym(fs, nil, path, path, walkFn)
+}
+
+// parallelSymWalk is symWalk, but reads up to parallel directories
+// concurrently ahead of the walk. walkFn is still called from a single
+// goroutine, in the same order as symWalk. This hides the latency of
+// reading directories on network file systems.
+func parallelSymWalk(f models.FS, path string, parallel int, walkFn fs.WalkDirFunc) error {
+	if parallel <= 1 {
+		return symWalk(f, path, walkFn)
+	}
+
+	r := newDirReader(f, parallel)
+	defer r.close()
+
+	return walkSym(f, r, path, path, walkFn)
 }
 
 type statDirEntry struct {
@@ -95,12 +110,12 @@ func (d *statDirEntry) IsDir() bool                { return d.info.IsDir() }
 func (d *statDirEntry) Type() fs.FileMode          { return d.info.Mode().Type() }
 func (d *statDirEntry) Info() (fs.FileInfo, error) { return d.info, nil }
 
-func fsWalk(f models.FS, root string, fn fs.WalkDirFunc) error {
+func fsWalk(f models.FS, r *dirReader, root string, fn fs.WalkDirFunc) error {
 	info, err := f.Lstat(root)
 	if err != nil {
 		err = fn(root, nil, err)
 	} else {
-		err = walkDir(f, root, &statDirEntry{info}, fn)
+		err = walkDir(f, r, root, &statDirEntry{info}, fn)
 	}
 	if errors.Is(err, fs.SkipDir) {
 		return nil
@@ -108,7 +123,7 @@ func fsWalk(f models.FS, root string, fn fs.WalkDirFunc) error {
 	return err
 }
 
-func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc) error {
+func walkDir(f models.FS, r *dirReader, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc) error {
 	if err := walkDirFn(path, d, nil); err != nil || !d.IsDir() {
 		if errors.Is(err, fs.SkipDir) && d.IsDir() {
 			// Successfully skipped directory.
@@ -117,7 +132,13 @@ func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc)
 		return err
 	}
 
-	dirs, err := readDir(f, path)
+	var dirs []fs.DirEntry
+	var err error
+	if r != nil {
+		dirs, err = r.read(path)
+	} else {
+		dirs, err = readDir(f, path)
+	}
 	if err != nil {
 		// Second call, to report ReadDir error.
 		err = walkDirFn(path, d, err)
@@ -126,6 +147,19 @@ func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc)
 		}
 	}
 
+	if r != nil {
+		var subdirs []string
+		for _, d1 := range dirs {
+			if d1.IsDir() && d1.Name() != "" && d1.Name() != "." {
+				subdirs = append(subdirs, filepath.Join(path, d1.Name()))
+			}
+		}
+
+		r.readAhead(subdirs)
+		// subdirectories that were skipped are no longer needed
+		defer r.discard(subdirs)
+	}
+
 	for _, d1 := range dirs {
 		name := d1.Name()
 		// Prevent infinite loops; this can happen with certain FS implementations (e.g. ZipFS).
@@ -133,7 +167,7 @@ func walkDir(f models.FS, path string, d fs.DirEntry, walkDirFn fs.WalkDirFunc)
 			continue
 		}
 		path1 := filepath.Join(path, name)
-		if err := walkDir(f, path1, d1, walkDirFn); err != nil {
+		if err := walkDir(f, r, path1, d1, walkDirFn); err != nil {
 			if errors.Is(err, fs.SkipDir) {
 				break
 			}
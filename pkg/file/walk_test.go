@@ -0,0 +1,62 @@
+package file
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelSymWalk(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			dir := filepath.Join(root, "dir"+strconv.Itoa(i), "sub"+strconv.Itoa(j))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for k := 0; k < 3; k++ {
+				if err := os.WriteFile(filepath.Join(dir, "file"+strconv.Itoa(k)), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	skipped := filepath.Join(root, "dir2")
+
+	walk := func(parallel int) []string {
+		var ret []string
+		err := parallelSymWalk(&OsFS{}, root, parallel, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if _, err := d.Info(); err != nil {
+				return err
+			}
+
+			ret = append(ret, path)
+			if path == skipped {
+				return fs.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ret
+	}
+
+	want := walk(1)
+	assert.NotContains(t, want, filepath.Join(skipped, "sub0"))
+
+	for _, parallel := range []int{2, 8} {
+		t.Run(strconv.Itoa(parallel), func(t *testing.T) {
+			assert.Equal(t, want, walk(parallel))
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Package webdav provides a file system backed by a WebDAV server.
+package webdav
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/studio-b12/gowebdav"
+)
+
+var errNotDir = errors.New("not a directory")
+
+// FS is a read-only file system of a directory on a WebDAV server, mounted
+// at a local path.
+//
+// Files are read using HTTP range requests, so that they can be fingerprinted
+// and served without being downloaded in full.
+type FS struct {
+	client    *gowebdav.Client
+	root      string
+	mountPath string
+}
+
+// New returns a FS of the directory at the http or https URL u, mounted at
+// mountPath.
+func New(mountPath string, u *url.URL, username, password string) *FS {
+	server := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+	}
+
+	root := u.Path
+	if root == "" {
+		root = "/"
+	}
+
+	return &FS{
+		client:    gowebdav.NewClient(server.String(), username, password),
+		root:      root,
+		mountPath: filepath.Clean(mountPath),
+	}
+}
+
+// MountPath returns the path that the directory is mounted at.
+func (f *FS) MountPath() string {
+	return f.mountPath
+}
+
+// remotePath returns the path of name on the server.
+func (f *FS) remotePath(name string) (string, bool) {
+	rel, ok := file.MountRelPath(f.mountPath, name)
+	if !ok {
+		return "", false
+	}
+
+	return path.Join(f.root, rel), true
+}
+
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	p, ok := f.remotePath(name)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info, err := f.client.Stat(p)
+	if err != nil {
+		if gowebdav.IsErrNotFound(err) {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, err
+	}
+
+	return fileInfo{info, filepath.Base(name)}, nil
+}
+
+// Lstat is equivalent to Stat, since WebDAV does not expose symlinks.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *FS) readDir(p string) ([]fs.DirEntry, error) {
+	infos, err := f.client.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+
+	ret := make([]fs.DirEntry, len(infos))
+	for i, info := range infos {
+		ret[i] = fs.FileInfoToDirEntry(info)
+	}
+
+	return ret, nil
+}
+
+func (f *FS) Open(name string) (fs.ReadDirFile, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p, _ := f.remotePath(name)
+
+	if info.IsDir() {
+		return file.NewListedDir(info, func() ([]fs.DirEntry, error) {
+			return f.readDir(p)
+		}), nil
+	}
+
+	return &remoteFile{
+		client: f.client,
+		path:   p,
+		info:   info,
+	}, nil
+}
+
+func (f *FS) OpenZip(name string) (models.ZipFS, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file.OpenNestedArchive(f, name)
+		}
+		return nil, err
+	}
+
+	return file.OpenArchive(f, name, info)
+}
+
+// IsPathCaseSensitive returns true, since WebDAV paths are URLs.
+func (f *FS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+// fileInfo overrides the name of the file info returned by the server, which
+// may be a display name rather than the file name.
+type fileInfo struct {
+	fs.FileInfo
+	name string
+}
+
+func (fi fileInfo) Name() string { return fi.name }
+
+// remoteFile is an open file on the server. Each read is made from the
+// current offset using a range request, so seeking does not read the skipped
+// data.
+type remoteFile struct {
+	client *gowebdav.Client
+	path   string
+	info   fs.FileInfo
+
+	offset int64
+	r      io.ReadCloser
+}
+
+func (f *remoteFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *remoteFile) Read(p []byte) (int, error) {
+	if f.offset >= f.info.Size() {
+		return 0, io.EOF
+	}
+
+	if f.r == nil {
+		r, err := f.client.ReadStreamRange(f.path, f.offset, f.info.Size()-f.offset)
+		if err != nil {
+			return 0, err
+		}
+		f.r = r
+	}
+
+	n, err := f.r.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *remoteFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= f.info.Size() {
+		return 0, io.EOF
+	}
+
+	length := int64(len(p))
+	if remaining := f.info.Size() - off; length > remaining {
+		length = remaining
+	}
+
+	r, err := f.client.ReadStreamRange(f.path, off, length)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	n, err := io.ReadFull(r, p[:length])
+	if err == nil && int64(n) < int64(len(p)) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (f *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.Size()
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("negative offset %d", offset)
+	}
+
+	if offset != f.offset {
+		f.closeReader()
+		f.offset = offset
+	}
+
+	return offset, nil
+}
+
+func (f *remoteFile) closeReader() {
+	if f.r != nil {
+		f.r.Close()
+		f.r = nil
+	}
+}
+
+func (f *remoteFile) Close() error {
+	f.closeReader()
+	return nil
+}
+
+func (f *remoteFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.info.Name(), Err: errNotDir}
+}
@@ -0,0 +1,159 @@
+package webdav
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/webdav"
+)
+
+var mountPath = filepath.FromSlash("/mnt/share")
+
+func mounted(p string) string {
+	return filepath.Join(mountPath, filepath.FromSlash(p))
+}
+
+func writeFile(t *testing.T, fsys webdav.FileSystem, name string, contents string) {
+	t.Helper()
+
+	f, err := fsys.OpenFile(context.Background(), name, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("creating %s: %v", name, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte(contents)); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func testFS(t *testing.T) *FS {
+	ctx := context.Background()
+	mem := webdav.NewMemFS()
+	for _, dir := range []string{"/library", "/library/sub"} {
+		if err := mem.Mkdir(ctx, dir, 0755); err != nil {
+			t.Fatalf("creating %s: %v", dir, err)
+		}
+	}
+	writeFile(t, mem, "/library/a.mp4", "0123456789")
+	writeFile(t, mem, "/library/sub/b.jpg", "image")
+	writeFile(t, mem, "/other.mp4", "other")
+
+	server := httptest.NewServer(&webdav.Handler{
+		FileSystem: mem,
+		LockSystem: webdav.NewMemLS(),
+	})
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL + "/library")
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+
+	return New(mountPath, u, "user", "password")
+}
+
+func TestFS_Stat(t *testing.T) {
+	f := testFS(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		isDir   bool
+		size    int64
+		wantErr error
+	}{
+		{"mount path", mountPath, true, 0, nil},
+		{"file", mounted("a.mp4"), false, 10, nil},
+		{"nested file", mounted("sub/b.jpg"), false, 5, nil},
+		{"directory", mounted("sub"), true, 0, nil},
+		{"missing", mounted("missing.mp4"), false, 0, fs.ErrNotExist},
+		{"outside mount", filepath.FromSlash("/mnt/other.mp4"), false, 0, fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := f.Stat(tt.path)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Stat: %v", err)
+			}
+
+			assert.Equal(t, filepath.Base(tt.path), info.Name())
+			assert.Equal(t, tt.isDir, info.IsDir())
+			if !tt.isDir {
+				assert.Equal(t, tt.size, info.Size())
+			}
+		})
+	}
+}
+
+func TestFS_ReadDir(t *testing.T) {
+	f := testFS(t)
+
+	d, err := f.Open(mountPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer d.Close()
+
+	entries, err := d.ReadDir(-1)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"a.mp4", "sub"}, names)
+	assert.True(t, entries[1].IsDir())
+}
+
+func TestFS_Open(t *testing.T) {
+	f := testFS(t)
+
+	r, err := f.Open(mounted("a.mp4"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+
+	rs := r.(io.ReadSeeker)
+	got, err := io.ReadAll(rs)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	assert.Equal(t, "0123456789", string(got))
+
+	// read the end of the file without reading the start
+	if _, err := rs.Seek(-3, io.SeekEnd); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+
+	got, err = io.ReadAll(rs)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	assert.Equal(t, "789", string(got))
+
+	buf := make([]byte, 4)
+	if _, err := r.(io.ReaderAt).ReadAt(buf, 2); err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	assert.Equal(t, "2345", string(buf))
+
+	// reads past the end of the file are truncated
+	n, err := r.(io.ReaderAt).ReadAt(buf, 8)
+	assert.Equal(t, 2, n)
+	assert.ErrorIs(t, err, io.EOF)
+}
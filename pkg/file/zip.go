@@ -17,31 +17,25 @@ import (
 	"golang.org/x/text/transform"
 )
 
-var (
-	errNotReaderAt  = errors.New("not a ReaderAt")
-	errZipFSOpenZip = errors.New("cannot open zip file inside zip file")
-)
+// maxZipDepth is the maximum number of archives that an archive may be
+// nested within. Deeper archives are not opened.
+const maxZipDepth = 2
+
+var errZipDepthExceeded = errors.New("archive is nested too deeply")
 
-// ZipFS is a file system backed by a zip file.
+// ZipFS is a file system backed by a zip file, or another supported archive
+// format.
 type zipFS struct {
-	*zip.Reader
+	archive       fs.FS
 	zipFileCloser io.Closer
 	zipInfo       fs.FileInfo
 	zipPath       string
-}
-
-func newZipFS(fs models.FS, path string, info fs.FileInfo) (*zipFS, error) {
-	reader, err := fs.Open(path)
-	if err != nil {
-		return nil, err
-	}
 
-	asReaderAt, _ := reader.(io.ReaderAt)
-	if asReaderAt == nil {
-		reader.Close()
-		return nil, errNotReaderAt
-	}
+	// depth is the number of archives that the archive is nested within
+	depth int
+}
 
+func newZipFS(reader io.ReadCloser, asReaderAt io.ReaderAt, path string, info fs.FileInfo) (*zipFS, error) {
 	zipReader, err := zip.NewReader(asReaderAt, info.Size())
 	if err != nil {
 		reader.Close()
@@ -87,7 +81,7 @@ func newZipFS(fs models.FS, path string, info fs.FileInfo) (*zipFS, error) {
 	}
 
 	return &zipFS{
-		Reader:        zipReader,
+		archive:       zipReader,
 		zipFileCloser: reader,
 		zipInfo:       info,
 		zipPath:       path,
@@ -112,21 +106,48 @@ func (f *zipFS) rel(name string) (string, error) {
 }
 
 func (f *zipFS) Stat(name string) (fs.FileInfo, error) {
-	reader, err := f.Open(name)
+	relName, err := f.rel(name)
 	if err != nil {
 		return nil, err
 	}
-	defer reader.Close()
 
-	return reader.Stat()
+	return fs.Stat(f.archive, relName)
 }
 
 func (f *zipFS) Lstat(name string) (fs.FileInfo, error) {
 	return f.Stat(name)
 }
 
+// OpenZip opens an archive within the archive. The archive is extracted to
+// a temporary file, which is shared by all opens of the archive and removed
+// when the last of them is closed.
 func (f *zipFS) OpenZip(name string) (models.ZipFS, error) {
-	return nil, errZipFSOpenZip
+	info, err := f.Stat(name)
+	if err != nil {
+		if isNotExist(err) {
+			return OpenNestedArchive(f, name)
+		}
+		return nil, err
+	}
+
+	if f.depth+1 > maxZipDepth {
+		return nil, errZipDepthExceeded
+	}
+
+	tmpPath, key, err := f.acquireEntry(name, info)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := openArchive(&entryFS{name: name, tmpPath: tmpPath, info: info}, name, info)
+	if err != nil {
+		_ = releaseEntry(key)
+		return nil, err
+	}
+
+	ret.zipFileCloser = &releaseCloser{Closer: ret.zipFileCloser, key: key}
+	ret.depth = f.depth + 1
+	return ret, nil
 }
 
 func (f *zipFS) IsPathCaseSensitive(path string) (bool, error) {
@@ -152,7 +173,7 @@ func (f *zipFS) Open(name string) (fs.ReadDirFile, error) {
 		return nil, err
 	}
 
-	r, err := f.Reader.Open(relName)
+	r, err := f.archive.Open(relName)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,8 @@
+package fsutil
+
+// DiskUsage is the size of the volume containing a path, in bytes.
+type DiskUsage struct {
+	Total uint64
+	// Free is the space available to the current user.
+	Free uint64
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !windows
+// +build !linux,!darwin,!freebsd,!windows
+
+package fsutil
+
+import (
+	"errors"
+)
+
+// GetDiskUsage is not supported on this platform.
+func GetDiskUsage(path string) (*DiskUsage, error) {
+	return nil, errors.New("disk usage is not supported on this platform")
+}
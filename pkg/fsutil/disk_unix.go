@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package fsutil
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// GetDiskUsage returns the size and free space of the volume containing
+// path.
+func GetDiskUsage(path string) (*DiskUsage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+
+	return &DiskUsage{
+		Total: uint64(st.Blocks) * uint64(st.Bsize),
+		Free:  uint64(st.Bavail) * uint64(st.Bsize),
+	}, nil
+}
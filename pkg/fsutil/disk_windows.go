@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package fsutil
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// GetDiskUsage returns the size and free space of the volume containing
+// path.
+func GetDiskUsage(path string) (*DiskUsage, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree); err != nil {
+		return nil, err
+	}
+
+	return &DiskUsage{
+		Total: total,
+		Free:  free,
+	}, nil
+}
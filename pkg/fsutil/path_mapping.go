@@ -0,0 +1,83 @@
+package fsutil
+
+import (
+	"strings"
+)
+
+// PathMapping maps paths starting with From to paths starting with To.
+type PathMapping struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PathMappings is a list of path prefix mappings.
+type PathMappings []PathMapping
+
+func isPathSeparator(c byte) bool {
+	return c == '/' || c == '\\'
+}
+
+// separatorOf returns the path separator used in p, or 0 if p does not contain one.
+func separatorOf(p string) byte {
+	i := strings.IndexAny(p, `/\`)
+	if i == -1 {
+		return 0
+	}
+	return p[i]
+}
+
+func (m PathMapping) matches(p string) bool {
+	from := strings.TrimRight(m.From, `/\`)
+	if from == "" || !strings.HasPrefix(p, from) {
+		return false
+	}
+
+	// only match on path boundaries
+	return len(p) == len(from) || isPathSeparator(p[len(from)])
+}
+
+// Apply returns p with the longest matching From prefix replaced by its To
+// prefix. Path separators in the remainder of the path are converted to the
+// separator used in To, so that paths may be mapped between platforms.
+// Returns p unchanged if no mapping matches.
+func (m PathMappings) Apply(p string) string {
+	var best *PathMapping
+	for i := range m {
+		mm := &m[i]
+		if mm.matches(p) && (best == nil || len(mm.From) > len(best.From)) {
+			best = mm
+		}
+	}
+
+	if best == nil {
+		return p
+	}
+
+	from := strings.TrimRight(best.From, `/\`)
+	to := strings.TrimRight(best.To, `/\`)
+	remainder := p[len(from):]
+
+	if sep := separatorOf(best.To); sep != 0 {
+		other := "\\"
+		if sep == '\\' {
+			other = "/"
+		}
+		remainder = strings.ReplaceAll(remainder, other, string(sep))
+	}
+
+	return to + remainder
+}
+
+// ApplyAll returns a copy of v with Apply called on each element.
+func (m PathMappings) ApplyAll(v []string) []string {
+	if v == nil {
+		return nil
+	}
+
+	ret := make([]string, len(v))
+	for i, p := range v {
+		ret[i] = m.Apply(p)
+	}
+
+	return ret
+}
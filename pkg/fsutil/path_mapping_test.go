@@ -0,0 +1,41 @@
+package fsutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathMappingsApply(t *testing.T) {
+	mappings := PathMappings{
+		{From: "/mnt/media", To: `D:\media`},
+		{From: "/mnt/media/archive/", To: "/archive"},
+		{From: `C:\stash`, To: "/data"},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/mnt/media/scene.mp4", `D:\media\scene.mp4`},
+		{"/mnt/media/sub/dir/scene.mp4", `D:\media\sub\dir\scene.mp4`},
+		{"/mnt/media", `D:\media`},
+		{"/mnt/mediaX/scene.mp4", "/mnt/mediaX/scene.mp4"},
+		{"/mnt/media/archive/old.zip", "/archive/old.zip"},
+		{`C:\stash\galleries\a.zip`, "/data/galleries/a.zip"},
+		{"/other/scene.mp4", "/other/scene.mp4"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, mappings.Apply(tt.path))
+		})
+	}
+}
+
+func TestPathMappingsApplyEmpty(t *testing.T) {
+	var mappings PathMappings
+	assert.Equal(t, "/mnt/media/scene.mp4", mappings.Apply("/mnt/media/scene.mp4"))
+	assert.Nil(t, mappings.ApplyAll(nil))
+}
@@ -0,0 +1,92 @@
+package fsutil
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Throttle limits the rate of IO operations. It is safe for concurrent use.
+// A nil Throttle does not limit IO.
+type Throttle struct {
+	bytesPerSecond int64
+	opsPerSecond   int
+
+	mutex sync.Mutex
+	// the time at which the next operation may start
+	next time.Time
+
+	// used to override the clock in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewThrottle returns a Throttle that limits IO to bytesPerSecond bytes and
+// opsPerSecond operations per second. Zero values are unlimited. Returns nil
+// if both values are zero.
+func NewThrottle(bytesPerSecond int64, opsPerSecond int) *Throttle {
+	if bytesPerSecond <= 0 && opsPerSecond <= 0 {
+		return nil
+	}
+
+	return &Throttle{
+		bytesPerSecond: bytesPerSecond,
+		opsPerSecond:   opsPerSecond,
+		now:            time.Now,
+		sleep:          time.Sleep,
+	}
+}
+
+// duration returns the time that an operation of n bytes takes at the
+// maximum rate.
+func (t *Throttle) duration(n int) time.Duration {
+	var ret time.Duration
+	if t.bytesPerSecond > 0 {
+		ret += time.Duration(int64(n) * int64(time.Second) / t.bytesPerSecond)
+	}
+	if t.opsPerSecond > 0 {
+		ret += time.Second / time.Duration(t.opsPerSecond)
+	}
+
+	return ret
+}
+
+// Wait blocks until an operation of n bytes may be performed.
+func (t *Throttle) Wait(n int) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	now := t.now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.duration(n))
+	t.mutex.Unlock()
+
+	if d := start.Sub(now); d > 0 {
+		t.sleep(d)
+	}
+}
+
+// Writer returns a writer that writes to w at the maximum rate of t. Each
+// call to Write counts as a single operation. Returns w if t is nil.
+func (t *Throttle) Writer(w io.Writer) io.Writer {
+	if t == nil {
+		return w
+	}
+
+	return &throttledWriter{w: w, t: t}
+}
+
+type throttledWriter struct {
+	w io.Writer
+	t *Throttle
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	w.t.Wait(len(p))
+	return w.w.Write(p)
+}
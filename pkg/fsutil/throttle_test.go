@@ -0,0 +1,59 @@
+package fsutil
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestThrottle(bytesPerSecond int64, opsPerSecond int) (*Throttle, *[]time.Duration) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+
+	t := NewThrottle(bytesPerSecond, opsPerSecond)
+	t.now = func() time.Time { return now }
+	t.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+
+	return t, &slept
+}
+
+func TestThrottle_Wait(t *testing.T) {
+	assert.Nil(t, NewThrottle(0, 0))
+
+	// nil throttle should not block
+	var nilThrottle *Throttle
+	nilThrottle.Wait(1024)
+
+	throttle, slept := newTestThrottle(1000, 0)
+	throttle.Wait(500)
+	throttle.Wait(500)
+	throttle.Wait(1000)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, *slept)
+
+	throttle, slept = newTestThrottle(0, 4)
+	throttle.Wait(1000)
+	throttle.Wait(1000)
+	assert.Equal(t, []time.Duration{250 * time.Millisecond}, *slept)
+}
+
+func TestThrottle_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	var nilThrottle *Throttle
+	assert.Equal(t, &buf, nilThrottle.Writer(&buf))
+
+	throttle, slept := newTestThrottle(100, 0)
+	w := throttle.Writer(&buf)
+
+	for i := 0; i < 3; i++ {
+		_, err := w.Write([]byte("0123456789"))
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, "012345678901234567890123456789", buf.String())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}, *slept)
+}
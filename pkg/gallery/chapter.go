@@ -0,0 +1,62 @@
+package gallery
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ChapterImageRange returns the 1-based, inclusive range of the gallery image
+// indexes of chapter. Chapters without an end image extend up to the image
+// before the start of the next chapter, or to the last image of the gallery.
+// chapters are the chapters of the gallery, and may include chapter. The
+// range is empty if end is less than start.
+func ChapterImageRange(chapter *models.GalleryChapter, chapters []*models.GalleryChapter, imageCount int) (start int, end int) {
+	start = chapter.ImageIndex
+	end = imageCount
+
+	if chapter.EndImageIndex != nil {
+		end = *chapter.EndImageIndex
+	} else {
+		for _, c := range chapters {
+			if c.ID != chapter.ID && c.ImageIndex > start && c.ImageIndex-1 < end {
+				end = c.ImageIndex - 1
+			}
+		}
+	}
+
+	if end > imageCount {
+		end = imageCount
+	}
+
+	return start, end
+}
+
+// ValidateChapterRange returns an error if the image range of a chapter is
+// invalid. The end image must not be before the start image, beyond the last
+// image of the gallery, or at or after the start of the next chapter. The
+// start image must not be within the explicit range of an earlier chapter.
+// chapterID is the ID of the chapter being validated, or 0 for a new chapter.
+// chapters are the existing chapters of the gallery.
+func ValidateChapterRange(chapterID int, imageIndex int, endImageIndex *int, chapters []*models.GalleryChapter, imageCount int) error {
+	if endImageIndex != nil && (*endImageIndex < imageIndex || *endImageIndex > imageCount) {
+		return errors.New("end image # must not be before the start image and must be in range of the gallery images")
+	}
+
+	for _, c := range chapters {
+		if c.ID == chapterID {
+			continue
+		}
+
+		if endImageIndex != nil && c.ImageIndex > imageIndex && c.ImageIndex <= *endImageIndex {
+			return fmt.Errorf("end image # must be before the start of chapter %q at image %d", c.Title, c.ImageIndex)
+		}
+
+		if c.EndImageIndex != nil && c.ImageIndex < imageIndex && *c.EndImageIndex >= imageIndex {
+			return fmt.Errorf("image # must be after the end of chapter %q at image %d", c.Title, *c.EndImageIndex)
+		}
+	}
+
+	return nil
+}
@@ -24,11 +24,12 @@ type ChapterImporter struct {
 
 func (i *ChapterImporter) PreImport(ctx context.Context) error {
 	i.chapter = models.GalleryChapter{
-		Title:      i.Input.Title,
-		ImageIndex: i.Input.ImageIndex,
-		GalleryID:  i.GalleryID,
-		CreatedAt:  i.Input.CreatedAt.GetTime(),
-		UpdatedAt:  i.Input.UpdatedAt.GetTime(),
+		Title:         i.Input.Title,
+		ImageIndex:    i.Input.ImageIndex,
+		EndImageIndex: i.Input.EndImageIndex,
+		GalleryID:     i.GalleryID,
+		CreatedAt:     i.Input.CreatedAt.GetTime(),
+		UpdatedAt:     i.Input.UpdatedAt.GetTime(),
 	}
 
 	return nil
@@ -0,0 +1,84 @@
+package gallery
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChapterImageRange(t *testing.T) {
+	const imageCount = 20
+
+	intPtr := func(v int) *int {
+		return &v
+	}
+
+	first := &models.GalleryChapter{ID: 1, ImageIndex: 1}
+	ranged := &models.GalleryChapter{ID: 2, ImageIndex: 5, EndImageIndex: intPtr(7)}
+	second := &models.GalleryChapter{ID: 3, ImageIndex: 10}
+	last := &models.GalleryChapter{ID: 4, ImageIndex: 15}
+	overlong := &models.GalleryChapter{ID: 5, ImageIndex: 18, EndImageIndex: intPtr(30)}
+
+	chapters := []*models.GalleryChapter{last, second, ranged, first}
+
+	tests := []struct {
+		name      string
+		chapter   *models.GalleryChapter
+		chapters  []*models.GalleryChapter
+		wantStart int
+		wantEnd   int
+	}{
+		{"up to next chapter", first, chapters, 1, 4},
+		{"explicit end", ranged, chapters, 5, 7},
+		{"after range", second, chapters, 10, 14},
+		{"last chapter", last, chapters, 15, imageCount},
+		{"only chapter", second, nil, 10, imageCount},
+		{"end past last image", overlong, chapters, 18, imageCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := ChapterImageRange(tt.chapter, tt.chapters, imageCount)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestValidateChapterRange(t *testing.T) {
+	const imageCount = 20
+
+	intPtr := func(v int) *int {
+		return &v
+	}
+
+	chapters := []*models.GalleryChapter{
+		{ID: 1, ImageIndex: 1},
+		{ID: 2, ImageIndex: 5, EndImageIndex: intPtr(7)},
+		{ID: 3, ImageIndex: 10},
+	}
+
+	tests := []struct {
+		name          string
+		chapterID     int
+		imageIndex    int
+		endImageIndex *int
+		wantErr       bool
+	}{
+		{"no end", 0, 12, nil, false},
+		{"end before next chapter", 1, 1, intPtr(4), false},
+		{"end at next chapter", 1, 1, intPtr(5), true},
+		{"end past next chapter", 0, 8, intPtr(11), true},
+		{"end before start", 0, 12, intPtr(11), true},
+		{"end past last image", 3, 10, intPtr(21), true},
+		{"updating own range", 2, 5, intPtr(9), false},
+		{"start within earlier range", 0, 6, nil, true},
+		{"start after earlier range", 0, 8, intPtr(9), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChapterRange(tt.chapterID, tt.imageIndex, tt.endImageIndex, chapters, imageCount)
+			assert.Equal(t, tt.wantErr, err != nil, "ValidateChapterRange() error = %v", err)
+		})
+	}
+}
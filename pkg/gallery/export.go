@@ -37,6 +37,8 @@ func ToBasicJSON(gallery *models.Gallery) (*jsonschema.Gallery, error) {
 	}
 
 	newGalleryJSON.Organized = gallery.Organized
+	newGalleryJSON.Favorite = gallery.Favorite
+	newGalleryJSON.ContentRating = gallery.ContentRating
 
 	return &newGalleryJSON, nil
 }
@@ -70,10 +72,11 @@ func GetGalleryChaptersJSON(ctx context.Context, chapterReader models.GalleryCha
 
 	for _, galleryChapter := range galleryChapters {
 		galleryChapterJSON := jsonschema.GalleryChapter{
-			Title:      galleryChapter.Title,
-			ImageIndex: galleryChapter.ImageIndex,
-			CreatedAt:  json.JSONTime{Time: galleryChapter.CreatedAt},
-			UpdatedAt:  json.JSONTime{Time: galleryChapter.UpdatedAt},
+			Title:         galleryChapter.Title,
+			ImageIndex:    galleryChapter.ImageIndex,
+			EndImageIndex: galleryChapter.EndImageIndex,
+			CreatedAt:     json.JSONTime{Time: galleryChapter.CreatedAt},
+			UpdatedAt:     json.JSONTime{Time: galleryChapter.UpdatedAt},
 		}
 
 		results = append(results, galleryChapterJSON)
@@ -195,6 +195,8 @@ const (
 	chapterImageIndex2 = 50
 )
 
+var chapterEndImageIndex1 = 20
+
 type galleryChaptersTestScenario struct {
 	input    models.Gallery
 	expected []jsonschema.GalleryChapter
@@ -206,8 +208,9 @@ var getGalleryChaptersJSONScenarios = []galleryChaptersTestScenario{
 		createEmptyGallery(galleryID),
 		[]jsonschema.GalleryChapter{
 			{
-				Title:      chapterTitle1,
-				ImageIndex: chapterImageIndex1,
+				Title:         chapterTitle1,
+				ImageIndex:    chapterImageIndex1,
+				EndImageIndex: &chapterEndImageIndex1,
 				CreatedAt: json.JSONTime{
 					Time: createTime,
 				},
@@ -242,11 +245,12 @@ var getGalleryChaptersJSONScenarios = []galleryChaptersTestScenario{
 
 var validChapters = []*models.GalleryChapter{
 	{
-		ID:         validChapterID1,
-		Title:      chapterTitle1,
-		ImageIndex: chapterImageIndex1,
-		CreatedAt:  createTime,
-		UpdatedAt:  updateTime,
+		ID:            validChapterID1,
+		Title:         chapterTitle1,
+		ImageIndex:    chapterImageIndex1,
+		EndImageIndex: &chapterEndImageIndex1,
+		CreatedAt:     createTime,
+		UpdatedAt:     updateTime,
 	},
 	{
 		ID:         validChapterID2,
@@ -12,6 +12,7 @@ import (
 
 type ImporterReaderWriter interface {
 	models.GalleryCreatorUpdater
+	models.CustomFieldsWriter
 	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Gallery, error)
 	FindByFolderID(ctx context.Context, folderID models.FolderID) ([]*models.Gallery, error)
 	FindUserGalleryByTitle(ctx context.Context, title string) ([]*models.Gallery, error)
@@ -81,6 +82,8 @@ func (i *Importer) galleryJSONToGallery(galleryJSON jsonschema.Gallery) models.G
 	}
 
 	newGallery.Organized = galleryJSON.Organized
+	newGallery.Favorite = galleryJSON.Favorite
+	newGallery.ContentRating = galleryJSON.ContentRating
 	newGallery.CreatedAt = galleryJSON.CreatedAt.GetTime()
 	newGallery.UpdatedAt = galleryJSON.UpdatedAt.GetTime()
 
@@ -288,6 +291,14 @@ func (i *Importer) populateFilesFolder(ctx context.Context) error {
 }
 
 func (i *Importer) PostImport(ctx context.Context, id int) error {
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting gallery custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -351,6 +362,7 @@ func (i *Importer) Create(ctx context.Context) (*int, error) {
 	}
 
 	id := i.gallery.ID
+	i.ID = id
 	return &id, nil
 }
 
@@ -362,5 +374,6 @@ func (i *Importer) Update(ctx context.Context, id int) error {
 		return fmt.Errorf("error updating existing gallery: %v", err)
 	}
 
+	i.ID = id
 	return nil
 }
@@ -69,7 +69,10 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 			return err
 		}
 
-		if len(images) == 0 {
+		// document galleries contain pages rather than images
+		_, isDocument := f.(*models.DocumentFile)
+
+		if len(images) == 0 && !isDocument {
 			// don't create an empty gallery
 			return nil
 		}
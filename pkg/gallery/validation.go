@@ -58,3 +58,11 @@ func (s *Service) ValidateImageGalleryChange(ctx context.Context, i *models.Imag
 
 	return nil
 }
+
+// ValidateParent returns an error if the gallery with the given id cannot
+// have newParentID as its parent. See models.ValidateParent.
+func ValidateParent(ctx context.Context, galleryID int, newParentID int, qb models.GalleryGetter) error {
+	return models.ValidateParent(ctx, "gallery", galleryID, newParentID, qb.Find, func(g *models.Gallery) *int {
+		return g.ParentID
+	})
+}
@@ -0,0 +1,63 @@
+package gallery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type ZipEntriesImageFinderCreator interface {
+	models.ImageCreator
+	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Image, error)
+}
+
+// ZipEntriesImporter creates images in a gallery for the exported image
+// entries of the gallery zip files. This allows the gallery structure to be
+// reconstructed where the zip files are not available.
+type ZipEntriesImporter struct {
+	FileFinder  models.FileFinder
+	ImageWriter ZipEntriesImageFinderCreator
+	GalleryID   int
+	Entries     []jsonschema.GalleryZipEntry
+}
+
+// Import creates an image for each entry that does not already have an
+// image. Entries without a file are skipped. Returns the number of images
+// created.
+func (i *ZipEntriesImporter) Import(ctx context.Context) (int, error) {
+	created := 0
+	for _, e := range i.Entries {
+		f, err := i.FileFinder.FindByPath(ctx, e.Path)
+		if err != nil {
+			return created, fmt.Errorf("error finding file: %w", err)
+		}
+
+		if f == nil {
+			logger.Warnf("gallery zip entry %q not found", e.Path)
+			continue
+		}
+
+		existing, err := i.ImageWriter.FindByFileID(ctx, f.Base().ID)
+		if err != nil {
+			return created, fmt.Errorf("error finding images: %w", err)
+		}
+
+		if len(existing) > 0 {
+			continue
+		}
+
+		newImage := models.NewImage()
+		newImage.GalleryIDs = models.NewRelatedIDs([]int{i.GalleryID})
+
+		if err := i.ImageWriter.Create(ctx, &newImage, []models.FileID{f.Base().ID}); err != nil {
+			return created, fmt.Errorf("error creating image for %q: %w", e.Path, err)
+		}
+
+		created++
+	}
+
+	return created, nil
+}
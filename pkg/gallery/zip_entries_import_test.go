@@ -0,0 +1,52 @@
+package gallery
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestZipEntriesImporterImport(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	const (
+		galleryID    = 1
+		existingPath = "existing.jpg"
+		newPath      = "new.jpg"
+		missingPath  = "missing.jpg"
+
+		existingFileID models.FileID = 2
+		newFileID      models.FileID = 3
+	)
+
+	i := ZipEntriesImporter{
+		FileFinder:  db.File,
+		ImageWriter: db.Image,
+		GalleryID:   galleryID,
+		Entries: []jsonschema.GalleryZipEntry{
+			{Path: existingPath},
+			{Path: newPath},
+			{Path: missingPath},
+		},
+	}
+
+	db.File.On("FindByPath", testCtx, existingPath).Return(&models.BaseFile{ID: existingFileID, Path: existingPath}, nil).Once()
+	db.File.On("FindByPath", testCtx, newPath).Return(&models.BaseFile{ID: newFileID, Path: newPath}, nil).Once()
+	db.File.On("FindByPath", testCtx, missingPath).Return(nil, nil).Once()
+
+	db.Image.On("FindByFileID", testCtx, existingFileID).Return([]*models.Image{{ID: 1}}, nil).Once()
+	db.Image.On("FindByFileID", testCtx, newFileID).Return(nil, nil).Once()
+	db.Image.On("Create", testCtx, mock.MatchedBy(func(i *models.Image) bool {
+		return assert.Equal(t, []int{galleryID}, i.GalleryIDs.List())
+	}), []models.FileID{newFileID}).Return(nil).Once()
+
+	created, err := i.Import(testCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, created)
+
+	db.AssertExpectations(t)
+}
@@ -0,0 +1,70 @@
+// Package blake3 provides utility functions for generating BLAKE3 hashes.
+package blake3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"lukechampine.com/blake3"
+)
+
+// Size is the size of a BLAKE3 checksum in bytes.
+const Size = 32
+
+// ChunkSize is the number of bytes read from the source between checks for
+// cancellation and progress updates by FromReaderContext.
+const ChunkSize = 4 * 1024 * 1024
+
+// FromBytes returns a BLAKE3 checksum string from data.
+func FromBytes(data []byte) string {
+	result := blake3.Sum256(data)
+	return fmt.Sprintf("%x", result)
+}
+
+// FromFilePath returns a BLAKE3 checksum string for the file at filePath.
+// It returns an empty string and an error if an error occurs opening the file.
+func FromFilePath(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return FromReaderContext(context.Background(), f, nil)
+}
+
+// FromReaderContext returns a BLAKE3 checksum string from data read from
+// src, reading in chunks of ChunkSize. Returns the context error if ctx is
+// done before src is fully read. If progress is not nil, it is called after
+// each chunk with the total number of bytes read.
+func FromReaderContext(ctx context.Context, src io.Reader, progress func(read int64)) (string, error) {
+	h := blake3.New(Size, nil)
+	buf := make([]byte, ChunkSize)
+	var read int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			read += int64(n)
+			if progress != nil {
+				progress(read)
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	checksum := h.Sum(nil)
+	return fmt.Sprintf("%x", checksum), nil
+}
@@ -0,0 +1,68 @@
+package blake3
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testInput returns the input used by the official BLAKE3 test vectors.
+func testInput(n int) []byte {
+	ret := make([]byte, n)
+	for i := range ret {
+		ret[i] = byte(i % 251)
+	}
+	return ret
+}
+
+func TestFromBytes(t *testing.T) {
+	tests := []struct {
+		inputLen int
+		want     string
+	}{
+		{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+		{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+		{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+		{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+		{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+		{102400, "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FromBytes(testInput(tt.inputLen)), "input length %d", tt.inputLen)
+	}
+
+	assert.Equal(t, "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85", FromBytes([]byte("abc")))
+}
+
+func TestFromReaderContext(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), ChunkSize/4)
+	want := FromBytes(data)
+
+	var progress []int64
+	got, err := FromReaderContext(context.Background(), bytes.NewReader(data), func(read int64) {
+		progress = append(progress, read)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, []int64{ChunkSize, 2 * ChunkSize, int64(len(data))}, progress)
+}
+
+func TestFromReaderContext_Cancelled(t *testing.T) {
+	data := make([]byte, ChunkSize*3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	chunks := 0
+	_, err := FromReaderContext(ctx, bytes.NewReader(data), func(read int64) {
+		chunks++
+		cancel()
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, chunks)
+}
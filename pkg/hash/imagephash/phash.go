@@ -0,0 +1,35 @@
+// Package imagephash provides functions to generate perceptual hashes of
+// still images.
+package imagephash
+
+import (
+	"fmt"
+	"image"
+	"io"
+
+	// register image decoders
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/corona10/goimagehash"
+)
+
+// Generate returns the perceptual hash of the gif, jpeg, png or webp image
+// read from r.
+func Generate(r io.Reader) (*uint64, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+
+	hash, err := goimagehash.PerceptionHash(img)
+	if err != nil {
+		return nil, fmt.Errorf("computing phash: %w", err)
+	}
+
+	hashValue := hash.GetHash()
+	return &hashValue, nil
+}
@@ -2,12 +2,17 @@
 package md5
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"io"
 	"os"
 )
 
+// ChunkSize is the number of bytes read from the source between checks for
+// cancellation and progress updates by FromReaderContext.
+const ChunkSize = 4 * 1024 * 1024
+
 // FromBytes returns an MD5 checksum string from data.
 func FromBytes(data []byte) string {
 	result := md5.Sum(data)
@@ -42,3 +47,37 @@ func FromReader(src io.Reader) (string, error) {
 	checksum := h.Sum(nil)
 	return fmt.Sprintf("%x", checksum), nil
 }
+
+// FromReaderContext returns an MD5 checksum string from data read from src,
+// reading in chunks of ChunkSize. Returns the context error if ctx is done
+// before src is fully read. If progress is not nil, it is called after each
+// chunk with the total number of bytes read.
+func FromReaderContext(ctx context.Context, src io.Reader, progress func(read int64)) (string, error) {
+	h := md5.New()
+	buf := make([]byte, ChunkSize)
+	var read int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			read += int64(n)
+			if progress != nil {
+				progress(read)
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	checksum := h.Sum(nil)
+	return fmt.Sprintf("%x", checksum), nil
+}
@@ -0,0 +1,47 @@
+package md5
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromReaderContext(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), ChunkSize/4)
+	want, err := FromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var progress []int64
+	got, err := FromReaderContext(context.Background(), bytes.NewReader(data), func(read int64) {
+		progress = append(progress, read)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, []int64{ChunkSize, 2 * ChunkSize, int64(len(data))}, progress)
+
+	// empty input
+	empty, err := FromReaderContext(context.Background(), bytes.NewReader(nil), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, FromBytes(nil), empty)
+}
+
+func TestFromReaderContext_Cancelled(t *testing.T) {
+	data := make([]byte, ChunkSize*3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	chunks := 0
+	_, err := FromReaderContext(ctx, bytes.NewReader(data), func(read int64) {
+		chunks++
+		cancel()
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, chunks)
+}
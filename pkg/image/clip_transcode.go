@@ -0,0 +1,89 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// IsAnimatedClip returns true if f is an animated gif. These are played
+// poorly by browsers, so may be transcoded to a video. Animated webp images
+// are not transcoded, since ffmpeg cannot decode them.
+func IsAnimatedClip(f models.File) bool {
+	switch f := f.(type) {
+	case *models.VideoFile:
+		// gifs are scanned as image clips
+		return f.VideoCodec == formatGif
+	case *models.ImageFile:
+		return f.Format == formatGif
+	}
+
+	return false
+}
+
+// GetClipTranscode transcodes the animated image at inPath to a full size
+// mp4 video at outPath. The video is written to a temporary file in the same
+// directory, which is renamed to outPath once complete, so that an
+// incomplete video is never served.
+func (e *ThumbnailEncoder) GetClipTranscode(inPath string, outPath string) error {
+	var videoFilter ffmpeg.VideoFilter
+	// yuv420p requires even dimensions
+	videoFilter = videoFilter.Append("scale=trunc(iw/2)*2:trunc(ih/2)*2")
+
+	var videoArgs ffmpeg.Args
+	videoArgs = videoArgs.VideoFilter(videoFilter)
+
+	o := e.ClipPreviewOptions
+
+	videoArgs = append(videoArgs,
+		"-pix_fmt", "yuv420p",
+		"-preset", o.Preset,
+		"-crf", "23",
+		"-movflags", "+faststart",
+		"-an",
+		"-f", "mp4",
+	)
+
+	outDir := filepath.Dir(outPath)
+	if err := fsutil.EnsureDirAll(outDir); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(outDir, ".*_clip.mp4")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+
+	options := transcoder.TranscodeOptions{
+		OutputPath: tmpPath,
+
+		XError: true,
+
+		VideoCodec: ffmpeg.VideoCodecLibX264,
+		VideoArgs:  videoArgs,
+
+		ExtraInputArgs:  o.InputArgs,
+		ExtraOutputArgs: o.OutputArgs,
+	}
+
+	args := transcoder.Transcode(inPath, options)
+	if err := e.FFMpeg.Generate(context.TODO(), args); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming %s to %s: %w", tmpPath, outPath, err)
+	}
+
+	return nil
+}
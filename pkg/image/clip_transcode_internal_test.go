@@ -0,0 +1,37 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestIsAnimatedClip(t *testing.T) {
+	imageFile := func(format string) *models.ImageFile {
+		return &models.ImageFile{
+			BaseFile: &models.BaseFile{},
+			Format:   format,
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    models.File
+		want bool
+	}{
+		{"gif clip", &models.VideoFile{BaseFile: &models.BaseFile{}, VideoCodec: formatGif}, true},
+		{"video clip", &models.VideoFile{BaseFile: &models.BaseFile{}, VideoCodec: "h264"}, false},
+		{"gif image", imageFile(formatGif), true},
+		{"jpeg image", imageFile("jpeg"), false},
+		// ffmpeg cannot decode animated webp images
+		{"webp image", imageFile(formatWebP), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAnimatedClip(tt.f); got != tt.want {
+				t.Errorf("IsAnimatedClip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -30,6 +30,11 @@ func (d *FileDeleter) MarkGeneratedFiles(image *models.Image) error {
 	if exists {
 		files = append(files, prevPath)
 	}
+	transcodePath := d.Paths.Generated.GetClipTranscodePath(image.Checksum)
+	exists, _ = fsutil.FileExists(transcodePath)
+	if exists {
+		files = append(files, transcodePath)
+	}
 
 	return d.Files(files)
 }
@@ -28,6 +28,7 @@ func ToBasicJSON(image *models.Image) *jsonschema.Image {
 	}
 
 	newImageJSON.Organized = image.Organized
+	newImageJSON.Favorite = image.Favorite
 	newImageJSON.OCounter = image.OCounter
 
 	for _, f := range image.Files.List() {
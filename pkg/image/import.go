@@ -17,6 +17,8 @@ type GalleryFinder interface {
 
 type ImporterReaderWriter interface {
 	models.ImageCreatorUpdater
+	models.CustomFieldsWriter
+	models.HistoryWriter
 	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Image, error)
 }
 
@@ -68,6 +70,7 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 
 		Title:     imageJSON.Title,
 		Organized: imageJSON.Organized,
+		Favorite:  imageJSON.Favorite,
 		OCounter:  imageJSON.OCounter,
 		CreatedAt: imageJSON.CreatedAt.GetTime(),
 		UpdatedAt: imageJSON.UpdatedAt.GetTime(),
@@ -292,6 +295,26 @@ func (i *Importer) populateTags(ctx context.Context) error {
 }
 
 func (i *Importer) PostImport(ctx context.Context, id int) error {
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting image custom fields: %v", err)
+		}
+	}
+
+	if len(i.Input.ODates) > 0 {
+		if err := i.ReaderWriter.ReplaceODates(ctx, id, jsonschema.ODatesFromJSON(i.Input.ODates)); err != nil {
+			return fmt.Errorf("error setting image o dates: %v", err)
+		}
+	}
+
+	if len(i.Input.RatingHistory) > 0 {
+		if err := i.ReaderWriter.ReplaceRatingHistory(ctx, id, jsonschema.RatingHistoryFromJSON(i.Input.RatingHistory)); err != nil {
+			return fmt.Errorf("error setting image rating history: %v", err)
+		}
+	}
+
 	return nil
 }
 
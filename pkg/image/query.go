@@ -68,6 +68,18 @@ func CountByTagID(ctx context.Context, r models.ImageQueryer, id int, depth *int
 	return r.QueryCount(ctx, filter, nil)
 }
 
+func CountByGalleryID(ctx context.Context, r models.ImageQueryer, id int, depth *int) (int, error) {
+	filter := &models.ImageFilterType{
+		Galleries: &models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(id)},
+			Modifier: models.CriterionModifierIncludes,
+			Depth:    depth,
+		},
+	}
+
+	return r.QueryCount(ctx, filter, nil)
+}
+
 func FindByGalleryID(ctx context.Context, r models.ImageQueryer, galleryID int, sortBy string, sortDir models.SortDirectionEnum) ([]*models.Image, error) {
 	perPage := -1
 
@@ -84,7 +96,7 @@ func FindByGalleryID(ctx context.Context, r models.ImageQueryer, galleryID int,
 	}
 
 	return Query(ctx, r, &models.ImageFilterType{
-		Galleries: &models.MultiCriterionInput{
+		Galleries: &models.HierarchicalMultiCriterionInput{
 			Value:    []string{strconv.Itoa(galleryID)},
 			Modifier: models.CriterionModifierIncludes,
 		},
@@ -119,7 +131,7 @@ func findGalleryCover(ctx context.Context, r models.ImageQueryer, galleryID int,
 	}
 
 	imageFilter := &models.ImageFilterType{
-		Galleries: &models.MultiCriterionInput{
+		Galleries: &models.HierarchicalMultiCriterionInput{
 			Value:    []string{strconv.Itoa(galleryID)},
 			Modifier: models.CriterionModifierIncludes,
 		},
@@ -143,3 +155,97 @@ func findGalleryCover(ctx context.Context, r models.ImageQueryer, galleryID int,
 
 	return nil, nil
 }
+
+// FindGalleryChapterImage returns the image at the 1-based imageIndex of the
+// gallery, ordered by path. Returns nil if the index is out of range.
+func FindGalleryChapterImage(ctx context.Context, r models.ImageQueryer, galleryID int, imageIndex int) (*models.Image, error) {
+	if imageIndex < 1 {
+		return nil, nil
+	}
+
+	page := imageIndex
+	perPage := 1
+	sortBy := "path"
+	sortDir := models.SortDirectionEnumAsc
+
+	findFilter := models.FindFilterType{
+		Page:      &page,
+		PerPage:   &perPage,
+		Sort:      &sortBy,
+		Direction: &sortDir,
+	}
+
+	imgs, err := Query(ctx, r, &models.ImageFilterType{
+		Galleries: &models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(galleryID)},
+			Modifier: models.CriterionModifierIncludes,
+		},
+	}, &findFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imgs) > 0 {
+		return imgs[0], nil
+	}
+
+	return nil, nil
+}
+
+// FindGalleryImageRange returns the images of the gallery between the 1-based,
+// inclusive start and end indexes, ordered by path.
+func FindGalleryImageRange(ctx context.Context, r models.ImageQueryer, galleryID int, start int, end int) ([]*models.Image, error) {
+	if start < 1 {
+		start = 1
+	}
+
+	if end < start {
+		return nil, nil
+	}
+
+	page, perPage := coveringPage(start-1, end-1)
+	sortBy := "path"
+	sortDir := models.SortDirectionEnumAsc
+
+	findFilter := models.FindFilterType{
+		Page:      &page,
+		PerPage:   &perPage,
+		Sort:      &sortBy,
+		Direction: &sortDir,
+	}
+
+	imgs, err := Query(ctx, r, &models.ImageFilterType{
+		Galleries: &models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(galleryID)},
+			Modifier: models.CriterionModifierIncludes,
+		},
+	}, &findFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// trim the page down to the range
+	offset := (page - 1) * perPage
+	first := start - 1 - offset
+	if len(imgs) <= first {
+		return nil, nil
+	}
+
+	last := end - offset
+	if last > len(imgs) {
+		last = len(imgs)
+	}
+
+	return imgs[first:last], nil
+}
+
+// coveringPage returns the smallest page that contains the 0-based, inclusive
+// range from first to last. The page is 1-based.
+func coveringPage(first int, last int) (page int, perPage int) {
+	perPage = last - first + 1
+	for first/perPage != last/perPage {
+		perPage++
+	}
+
+	return first/perPage + 1, perPage
+}
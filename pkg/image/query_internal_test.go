@@ -0,0 +1,32 @@
+package image
+
+import "testing"
+
+func TestCoveringPage(t *testing.T) {
+	tests := []struct {
+		name        string
+		first       int
+		last        int
+		wantPage    int
+		wantPerPage int
+	}{
+		{"first page", 0, 4, 1, 5},
+		{"aligned", 5, 9, 2, 5},
+		{"single", 6, 6, 7, 1},
+		{"unaligned", 5, 6, 2, 4},
+		{"straddling", 3, 4, 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, perPage := coveringPage(tt.first, tt.last)
+			if page != tt.wantPage || perPage != tt.wantPerPage {
+				t.Errorf("coveringPage(%d, %d) = %d, %d, want %d, %d", tt.first, tt.last, page, perPage, tt.wantPage, tt.wantPerPage)
+			}
+
+			offset := (page - 1) * perPage
+			if tt.first < offset || tt.last >= offset+perPage {
+				t.Errorf("page %d of %d does not contain %d-%d", page, perPage, tt.first, tt.last)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 )
 
@@ -33,7 +34,13 @@ type ThumbnailEncoder struct {
 	FFMpeg             *ffmpeg.FFMpeg
 	FFProbe            ffmpeg.FFProbe
 	ClipPreviewOptions ClipPreviewOptions
-	vips               *vipsEncoder
+	// FS is the file system that image files are read from.
+	// Defaults to the OS file system.
+	FS models.FS
+	// Backend selects how still images are resized. Defaults to
+	// ImageThumbnailBackendAuto.
+	Backend models.ImageThumbnailBackend
+	vips    *vipsEncoder
 }
 
 type ClipPreviewOptions struct {
@@ -54,6 +61,7 @@ func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe ffmpeg.FFProbe, c
 		FFMpeg:             ffmpegEncoder,
 		FFProbe:            ffProbe,
 		ClipPreviewOptions: clipPreviewOptions,
+		FS:                 &file.OsFS{},
 	}
 
 	vipsPath := GetVipsPath()
@@ -70,7 +78,7 @@ func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe ffmpeg.FFProbe, c
 // It returns nil and an error if an error occurs reading, decoding or encoding
 // the image, or if the image is not suitable for thumbnails.
 func (e *ThumbnailEncoder) GetThumbnail(f models.File, maxSize int) ([]byte, error) {
-	reader, err := f.Open(&file.OsFS{})
+	reader, err := f.Open(e.FS)
 	if err != nil {
 		return nil, err
 	}
@@ -103,11 +111,31 @@ func (e *ThumbnailEncoder) GetThumbnail(f models.File, maxSize int) ([]byte, err
 		return e.ffmpegImageThumbnail(buf, maxSize)
 	}
 
+	return e.imageThumbnail(data, maxSize)
+}
+
+// imageThumbnail resizes the still image data using the configured backend.
+func (e *ThumbnailEncoder) imageThumbnail(data []byte, maxSize int) ([]byte, error) {
+	switch e.Backend {
+	case models.ImageThumbnailBackendFfmpeg:
+		return e.ffmpegImageThumbnail(bytes.NewBuffer(data), maxSize)
+	case models.ImageThumbnailBackendGpu:
+		if codec := e.FFMpeg.HWImageScaleCodec(); codec != nil {
+			ret, err := e.ffmpegHWImageThumbnail(bytes.NewBuffer(data), maxSize, *codec)
+			if err == nil {
+				return ret, nil
+			}
+
+			// not all images can be decoded or scaled by the device
+			logger.Debugf("hardware thumbnail generation failed, falling back to software: %v", err)
+		}
+	}
+
 	// vips has issues loading files from stdin on Windows
 	if e.vips != nil && runtime.GOOS != "windows" {
-		return e.vips.ImageThumbnail(buf, maxSize)
+		return e.vips.ImageThumbnail(bytes.NewBuffer(data), maxSize)
 	} else {
-		return e.ffmpegImageThumbnail(buf, maxSize)
+		return e.ffmpegImageThumbnail(bytes.NewBuffer(data), maxSize)
 	}
 }
 
@@ -140,6 +168,19 @@ func (e *ThumbnailEncoder) ffmpegImageThumbnail(image *bytes.Buffer, maxSize int
 	return e.FFMpeg.GenerateOutput(context.TODO(), args, image)
 }
 
+func (e *ThumbnailEncoder) ffmpegHWImageThumbnail(image *bytes.Buffer, maxSize int, codec ffmpeg.VideoCodec) ([]byte, error) {
+	args := transcoder.ImageThumbnail("-", transcoder.ImageThumbnailOptions{
+		OutputFormat:  ffmpeg.ImageFormatJpeg,
+		OutputPath:    "-",
+		MaxDimensions: maxSize,
+		Quality:       ffmpegImageQuality,
+		HWDeviceArgs:  e.FFMpeg.HWImageDeviceArgs(codec),
+		VideoFilter:   e.FFMpeg.HWScaleMaxSizeFilter(codec, maxSize),
+	})
+
+	return e.FFMpeg.GenerateOutput(context.TODO(), args, image)
+}
+
 func (e *ThumbnailEncoder) getClipPreview(inPath string, outPath string, maxSize int, clipDuration float64, frameRate float64) error {
 	var thumbFilter ffmpeg.VideoFilter
 	thumbFilter = thumbFilter.ScaleMaxSize(maxSize)
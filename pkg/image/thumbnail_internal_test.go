@@ -0,0 +1,73 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os/exec"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// benchmarkImage returns a JPEG encoded test image of the given size.
+func benchmarkImage(b *testing.B, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// BenchmarkImageThumbnail measures the thumbnail throughput of each backend
+// with as many images resized at once as GOMAXPROCS. Use -cpu to compare
+// values of image_thumbnail_parallel_tasks.
+func BenchmarkImageThumbnail(b *testing.B) {
+	ffmpegPath, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		b.Skip("ffmpeg not found")
+	}
+
+	encoder := NewThumbnailEncoder(ffmpeg.NewEncoder(ffmpegPath), "", ClipPreviewOptions{})
+	encoder.FFMpeg.InitHWSupport(context.Background())
+
+	data := benchmarkImage(b, 4000, 3000)
+
+	for _, backend := range models.AllImageThumbnailBackend {
+		b.Run(backend.String(), func(b *testing.B) {
+			switch backend {
+			case models.ImageThumbnailBackendVips:
+				if encoder.vips == nil {
+					b.Skip("vips not found")
+				}
+			case models.ImageThumbnailBackendGpu:
+				if encoder.FFMpeg.HWImageScaleCodec() == nil {
+					b.Skip("no supported hardware device")
+				}
+			}
+
+			e := encoder
+			e.Backend = backend
+
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := e.imageThumbnail(data, models.DefaultGthumbWidth); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
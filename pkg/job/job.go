@@ -44,10 +44,22 @@ const (
 	StatusFailed Status = "FAILED"
 )
 
+// Priority is the priority lane of a Job.
+type Priority int
+
+const (
+	// PriorityNormal is used for bulk jobs, such as scanning and generating.
+	PriorityNormal Priority = iota
+	// PriorityHigh is used for on-demand jobs that a user is waiting on.
+	// High priority jobs preempt normal priority jobs.
+	PriorityHigh
+)
+
 // Job represents the status of a queued or running job.
 type Job struct {
-	ID     int
-	Status Status
+	ID       int
+	Status   Status
+	Priority Priority
 	// details of the current operations of the job
 	Details     []string
 	Description string
@@ -56,6 +68,9 @@ type Job struct {
 	StartTime *time.Time
 	EndTime   *time.Time
 	AddTime   time.Time
+	// EstimatedEndTime is the time that the job is expected to end, based on
+	// its progress so far. Nil if the progress is not known.
+	EstimatedEndTime *time.Time
 
 	outerCtx   context.Context
 	exec       JobExec
@@ -75,6 +90,30 @@ func (j *Job) TimeElapsed() time.Duration {
 	return end.Sub(*j.StartTime)
 }
 
+// EstimateRemaining returns the estimated time remaining to reach a progress
+// of 1, given the time elapsed to reach progress. Returns false if progress is
+// not between 0 and 1, exclusive.
+func EstimateRemaining(elapsed time.Duration, progress float64) (time.Duration, bool) {
+	if progress <= 0 || progress >= 1 {
+		return 0, false
+	}
+
+	return time.Duration(float64(elapsed) * (1 - progress) / progress), true
+}
+
+func (j *Job) updateEstimatedEndTime(now time.Time) {
+	j.EstimatedEndTime = nil
+
+	if j.StartTime == nil {
+		return
+	}
+
+	if remaining, ok := EstimateRemaining(now.Sub(*j.StartTime), j.Progress); ok {
+		t := now.Add(remaining)
+		j.EstimatedEndTime = &t
+	}
+}
+
 func (j *Job) cancel() {
 	if j.Status == StatusReady {
 		j.Status = StatusCancelled
@@ -13,14 +13,19 @@ import (
 const maxGraveyardSize = 10
 const defaultThrottleLimit = 100 * time.Millisecond
 
-// Manager maintains a queue of jobs. Jobs are executed one at a time.
+// Manager maintains a queue of jobs. Jobs in each priority lane are executed
+// one at a time. While a high priority job is running, normal priority jobs
+// are not started and running normal priority jobs are paused.
 type Manager struct {
 	queue     []*Job
 	graveyard []*Job
 
-	mutex    sync.Mutex
-	notEmpty *sync.Cond
-	stop     chan struct{}
+	mutex   sync.Mutex
+	changed *sync.Cond
+	stop    chan struct{}
+
+	// paused while a high priority job is queued or running
+	preempt Pauser
 
 	lastID int
 
@@ -35,9 +40,10 @@ func NewManager() *Manager {
 		updateThrottleLimit: defaultThrottleLimit,
 	}
 
-	ret.notEmpty = sync.NewCond(&ret.mutex)
+	ret.changed = sync.NewCond(&ret.mutex)
 
-	go ret.dispatcher()
+	go ret.dispatcher(PriorityNormal)
+	go ret.dispatcher(PriorityHigh)
 
 	return ret
 }
@@ -46,11 +52,23 @@ func NewManager() *Manager {
 // more Jobs will be processed.
 func (m *Manager) Stop() {
 	m.CancelAll()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	close(m.stop)
+	m.changed.Broadcast()
 }
 
-// Add queues a job.
+// Add queues a job with normal priority.
 func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
+	return m.AddWithPriority(ctx, description, PriorityNormal, e)
+}
+
+// AddWithPriority queues a job in the lane of the provided priority. High
+// priority jobs are started ahead of any queued normal priority jobs, and
+// pause the running normal priority job until they are finished.
+func (m *Manager) AddWithPriority(ctx context.Context, description string, priority Priority, e JobExec) int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -60,6 +78,7 @@ func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
 		ID:          m.nextID(),
 		Status:      StatusReady,
 		Description: description,
+		Priority:    priority,
 		AddTime:     t,
 		exec:        e,
 		outerCtx:    ctx,
@@ -67,11 +86,13 @@ func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
 
 	m.queue = append(m.queue, &j)
 
-	if len(m.queue) == 1 {
-		// notify that there is now a job in the queue
-		m.notEmpty.Broadcast()
+	if priority == PriorityHigh {
+		m.preempt.SetPaused(true)
 	}
 
+	// notify that there is now a job in the queue
+	m.changed.Broadcast()
+
 	m.notifyNewJob(&j)
 
 	return j.ID
@@ -117,10 +138,26 @@ func (m *Manager) nextID() int {
 	return m.lastID
 }
 
-func (m *Manager) getReadyJob() *Job {
+func (m *Manager) hasHighPriorityJob() bool {
+	// assumes lock held
+	for _, j := range m.queue {
+		if j.Priority == PriorityHigh {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Manager) getReadyJob(priority Priority) *Job {
 	// assumes lock held
+	if priority == PriorityNormal && m.hasHighPriorityJob() {
+		// normal priority jobs wait for the high priority lane to empty
+		return nil
+	}
+
 	for _, j := range m.queue {
-		if j.Status == StatusReady {
+		if j.Status == StatusReady && j.Priority == priority {
 			return j
 		}
 	}
@@ -128,15 +165,15 @@ func (m *Manager) getReadyJob() *Job {
 	return nil
 }
 
-func (m *Manager) dispatcher() {
+func (m *Manager) dispatcher(priority Priority) {
 	m.mutex.Lock()
 
 	for {
 		// wait until we have something to process
-		j := m.getReadyJob()
+		j := m.getReadyJob(priority)
 
 		for j == nil {
-			m.notEmpty.Wait()
+			m.changed.Wait()
 
 			// it's possible that we have been stopped - check here
 			select {
@@ -145,7 +182,7 @@ func (m *Manager) dispatcher() {
 				return
 			default:
 				// keep going
-				j = m.getReadyJob()
+				j = m.getReadyJob(priority)
 			}
 		}
 
@@ -179,6 +216,10 @@ func (m *Manager) dispatch(ctx context.Context, j *Job) (done chan struct{}) {
 	j.StartTime = &t
 	j.Status = StatusRunning
 
+	if j.Priority == PriorityNormal {
+		ctx = WithPauser(ctx, &m.preempt)
+	}
+
 	ctx, cancelFunc := context.WithCancel(utils.ValueOnlyContext{Context: ctx})
 	j.cancelFunc = cancelFunc
 
@@ -220,6 +261,7 @@ func (m *Manager) onJobFinish(job *Job) {
 	}
 	t := time.Now()
 	job.EndTime = &t
+	job.EstimatedEndTime = nil
 }
 
 func (m *Manager) removeJob(job *Job) {
@@ -239,6 +281,14 @@ func (m *Manager) removeJob(job *Job) {
 		m.graveyard = m.graveyard[1:]
 	}
 
+	if job.Priority == PriorityHigh && !m.hasHighPriorityJob() {
+		// resume the normal priority lane
+		m.preempt.SetPaused(false)
+	}
+
+	// notify that the queue has changed
+	m.changed.Broadcast()
+
 	// notify job removed
 	for _, s := range m.subscriptions {
 		// don't block if channel is full
@@ -395,6 +445,7 @@ func (u *updater) updateProgress(progress float64, details []string) {
 
 	u.job.Progress = progress
 	u.job.Details = details
+	u.job.updateEstimatedEndTime(time.Now())
 
 	if time.Since(u.lastUpdate) < u.m.updateThrottleLimit {
 		if u.updateTimer == nil {
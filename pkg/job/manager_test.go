@@ -124,6 +124,93 @@ func TestAdd(t *testing.T) {
 	assert.NotNil(j2.StartTime)
 }
 
+func TestAddWithPriority(t *testing.T) {
+	m := NewManager()
+
+	// add a normal priority job that waits if paused before finishing
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	resumed := make(chan struct{})
+	jobID := m.Add(context.Background(), "normal job", MakeJobExec(func(ctx context.Context, progress *Progress) {
+		close(started)
+		<-proceed
+		_ = WaitIfPaused(ctx)
+		close(resumed)
+	}))
+
+	select {
+	case <-started:
+		// ok
+	case <-time.After(time.Second):
+		t.Fatal("normal exec was not started")
+	}
+
+	exec2 := newTestExec(make(chan struct{}))
+	job2ID := m.Add(context.Background(), "queued normal job", exec2)
+
+	exec3 := newTestExec(make(chan struct{}))
+	job3ID := m.AddWithPriority(context.Background(), "high job", PriorityHigh, exec3)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+	close(proceed)
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+
+	// expect the high priority job to have started
+	select {
+	case <-exec3.started:
+		// ok
+	default:
+		t.Error("high priority exec was not started")
+	}
+	assert.Equal(StatusRunning, m.GetJob(job3ID).Status)
+
+	// expect the queued normal job to wait for the high priority job
+	select {
+	case <-exec2.started:
+		t.Error("queued normal exec was started")
+	default:
+	}
+
+	// expect the running normal job to be paused
+	assert.True(m.preempt.Paused())
+	select {
+	case <-resumed:
+		t.Error("normal exec was not paused")
+	default:
+	}
+
+	// allow the high priority job to finish
+	close(exec3.finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	// expect the normal job to be resumed and finished
+	assert.False(m.preempt.Paused())
+	select {
+	case <-resumed:
+		// ok
+	default:
+		t.Error("normal exec was not resumed")
+	}
+	assert.Equal(StatusFinished, m.GetJob(jobID).Status)
+
+	// expect the queued normal job to be started
+	select {
+	case <-exec2.started:
+		// ok
+	default:
+		t.Error("queued normal exec was not started")
+	}
+
+	close(exec2.finish)
+	time.Sleep(sleepTime)
+	assert.Equal(StatusFinished, m.GetJob(job2ID).Status)
+}
+
 func TestCancel(t *testing.T) {
 	m := NewManager()
 
@@ -0,0 +1,98 @@
+package job
+
+import (
+	"context"
+	"sync"
+)
+
+// Pauser is used to pause jobs between units of work. The zero value is an
+// unpaused Pauser. It is safe for concurrent use.
+type Pauser struct {
+	mutex sync.Mutex
+	// non-nil while paused. Closed when resumed.
+	resume chan struct{}
+}
+
+// SetPaused pauses or resumes jobs waiting on p. Returns true if the state
+// of p was changed.
+func (p *Pauser) SetPaused(paused bool) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if paused == (p.resume != nil) {
+		return false
+	}
+
+	if paused {
+		p.resume = make(chan struct{})
+	} else {
+		close(p.resume)
+		p.resume = nil
+	}
+
+	return true
+}
+
+// Paused returns true if p is paused.
+func (p *Pauser) Paused() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.resume != nil
+}
+
+// Wait blocks until p is not paused. Returns the context error if ctx is
+// done before p is resumed.
+func (p *Pauser) Wait(ctx context.Context) error {
+	p.mutex.Lock()
+	resume := p.resume
+	p.mutex.Unlock()
+
+	if resume == nil {
+		return nil
+	}
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type pauserCtxKey struct{}
+
+// WithPauser returns a context that causes WaitIfPaused to wait on p, in
+// addition to any Pausers already set in ctx.
+func WithPauser(ctx context.Context, p *Pauser) context.Context {
+	existing, _ := ctx.Value(pauserCtxKey{}).([]*Pauser)
+	pausers := append(existing[:len(existing):len(existing)], p)
+	return context.WithValue(ctx, pauserCtxKey{}, pausers)
+}
+
+// WaitIfPaused blocks while any Pauser set in ctx using WithPauser is
+// paused. Returns immediately if ctx has no Pauser. Returns the context error
+// if ctx is done while waiting.
+func WaitIfPaused(ctx context.Context) error {
+	pausers, _ := ctx.Value(pauserCtxKey{}).([]*Pauser)
+
+	// a Pauser may be paused while waiting on another, so wait until none
+	// are paused
+	for {
+		waited := false
+		for _, p := range pausers {
+			if !p.Paused() {
+				continue
+			}
+
+			waited = true
+			if err := p.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		if !waited {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitIfPaused(t *testing.T) {
+	// no pauser
+	assert.Nil(t, WaitIfPaused(context.Background()))
+
+	p := &Pauser{}
+	ctx := WithPauser(context.Background(), p)
+
+	// not paused
+	assert.Nil(t, WaitIfPaused(ctx))
+
+	assert.True(t, p.SetPaused(true))
+	assert.False(t, p.SetPaused(true))
+	assert.True(t, p.Paused())
+
+	done := make(chan error)
+	go func() {
+		done <- WaitIfPaused(ctx)
+	}()
+
+	select {
+	case <-done:
+		t.Error("WaitIfPaused returned while paused")
+		return
+	case <-time.After(sleepTime):
+	}
+
+	assert.True(t, p.SetPaused(false))
+	assert.Nil(t, <-done)
+
+	// cancelled while paused
+	p.SetPaused(true)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, WaitIfPaused(cancelCtx), context.Canceled)
+}
+
+func TestWaitIfPaused_MultiplePausers(t *testing.T) {
+	p1 := &Pauser{}
+	p2 := &Pauser{}
+	ctx := WithPauser(WithPauser(context.Background(), p1), p2)
+
+	assert.Nil(t, WaitIfPaused(ctx))
+
+	p1.SetPaused(true)
+	p2.SetPaused(true)
+
+	done := make(chan error)
+	go func() {
+		done <- WaitIfPaused(ctx)
+	}()
+
+	// still paused by p2
+	p1.SetPaused(false)
+
+	select {
+	case <-done:
+		t.Error("WaitIfPaused returned while paused")
+		return
+	case <-time.After(sleepTime):
+	}
+
+	assert.True(t, p2.SetPaused(false))
+	assert.Nil(t, <-done)
+}
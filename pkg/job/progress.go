@@ -1,6 +1,9 @@
 package job
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+)
 
 // ProgressIndefinite is the special percent value to indicate that the
 // percent progress is not known.
@@ -21,12 +24,23 @@ type Progress struct {
 
 type task struct {
 	description string
+	// percent is the progress of the task, or ProgressIndefinite if not
+	// known.
+	percent float64
+}
+
+func (t *task) details() string {
+	if t.percent == ProgressIndefinite {
+		return t.description
+	}
+
+	return fmt.Sprintf("%s (%d%%)", t.description, int(t.percent*100))
 }
 
 func (p *Progress) updated() {
 	var details []string
 	for _, t := range p.currentTasks {
-		details = append(details, t.description)
+		details = append(details, t.details())
 	}
 
 	p.updater.updateProgress(p.percent, details)
@@ -168,9 +182,40 @@ func (p *Progress) removeTask(t *task) {
 func (p *Progress) ExecuteTask(description string, fn func()) {
 	t := &task{
 		description: description,
+		percent:     ProgressIndefinite,
 	}
 
 	p.addTask(t)
 	defer p.removeTask(t)
 	fn()
 }
+
+// ExecuteTaskWithProgress executes a task as part of a job, like
+// ExecuteTask. fn is passed a function to set the progress of the task,
+// between 0 and 1, which is shown as a percentage after the description.
+func (p *Progress) ExecuteTaskWithProgress(description string, fn func(setPercent func(float64))) {
+	t := &task{
+		description: description,
+		percent:     ProgressIndefinite,
+	}
+
+	p.addTask(t)
+	defer p.removeTask(t)
+	fn(func(percent float64) {
+		p.setTaskPercent(t, percent)
+	})
+}
+
+func (p *Progress) setTaskPercent(t *task, percent float64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if percent < 0 {
+		percent = 0
+	} else if percent > 1 {
+		percent = 1
+	}
+
+	t.percent = percent
+	p.updated()
+}
@@ -148,3 +148,63 @@ func TestExecuteTask(t *testing.T) {
 	assert.Len(j.Details, 0)
 	m.mutex.Unlock()
 }
+
+func TestExecuteTaskWithProgress(t *testing.T) {
+	m := NewManager()
+	j := &Job{}
+
+	p := createProgress(m, j)
+
+	var details []string
+	p.ExecuteTaskWithProgress("taskDescription", func(setPercent func(float64)) {
+		m.mutex.Lock()
+		details = append(details, j.Details[0])
+		m.mutex.Unlock()
+
+		setPercent(0.456)
+
+		m.mutex.Lock()
+		details = append(details, j.Details[0])
+		m.mutex.Unlock()
+
+		setPercent(2)
+
+		m.mutex.Lock()
+		details = append(details, j.Details[0])
+		m.mutex.Unlock()
+	})
+
+	assert.Equal(t, []string{
+		"taskDescription",
+		"taskDescription (45%)",
+		"taskDescription (100%)",
+	}, details)
+
+	m.mutex.Lock()
+	assert.Len(t, j.Details, 0)
+	m.mutex.Unlock()
+}
+
+func TestProgressEstimatedEndTime(t *testing.T) {
+	m := NewManager()
+	start := time.Now().Add(-time.Minute)
+	j := &Job{
+		StartTime: &start,
+	}
+
+	p := createProgress(m, j)
+
+	p.SetTotal(4)
+	p.SetProcessed(1)
+
+	assert := assert.New(t)
+
+	// a quarter done in a minute, so about three minutes remaining
+	if assert.NotNil(j.EstimatedEndTime) {
+		remaining := time.Until(*j.EstimatedEndTime)
+		assert.InDelta(3*time.Minute, remaining, float64(time.Second))
+	}
+
+	p.Indefinite()
+	assert.Nil(j.EstimatedEndTime)
+}
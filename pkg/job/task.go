@@ -54,6 +54,10 @@ func (tq *TaskQueue) executer(ctx context.Context) {
 			return
 		}
 
+		if err := WaitIfPaused(ctx); err != nil {
+			return
+		}
+
 		tt := task
 
 		tq.wg.Add()
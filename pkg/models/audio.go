@@ -0,0 +1,73 @@
+package models
+
+import "context"
+
+type AudioFilterType struct {
+	And     *AudioFilterType      `json:"AND"`
+	Or      *AudioFilterType      `json:"OR"`
+	Not     *AudioFilterType      `json:"NOT"`
+	ID      *IntCriterionInput    `json:"id"`
+	Title   *StringCriterionInput `json:"title"`
+	Details *StringCriterionInput `json:"details"`
+	// Filter by path
+	Path *StringCriterionInput `json:"path"`
+	// Filter by file checksum
+	Checksum *StringCriterionInput `json:"checksum"`
+	// Filter by rating expressed as 1-100
+	Rating100 *IntCriterionInput `json:"rating100"`
+	// Filter by organized
+	Organized *bool `json:"organized"`
+	// Filter by date
+	Date *DateCriterionInput `json:"date"`
+	// Filter by duration (in seconds)
+	Duration *IntCriterionInput `json:"duration"`
+	// Filter by audio codec
+	AudioCodec *StringCriterionInput `json:"audio_codec"`
+	// Filter to only include audio files with this studio
+	Studios *HierarchicalMultiCriterionInput `json:"studios"`
+	// Filter to only include audio files with these tags
+	Tags *HierarchicalMultiCriterionInput `json:"tags"`
+	// Filter to only include audio files with these performers
+	Performers *MultiCriterionInput `json:"performers"`
+	// Filter by created at
+	CreatedAt *TimestampCriterionInput `json:"created_at"`
+	// Filter by updated at
+	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+}
+
+type AudioDestroyInput struct {
+	ID         string `json:"id"`
+	DeleteFile *bool  `json:"delete_file"`
+}
+
+type AudioQueryOptions struct {
+	QueryOptions
+	AudioFilter *AudioFilterType
+
+	TotalDuration bool
+	TotalSize     bool
+}
+
+type AudioQueryResult struct {
+	QueryResult
+	TotalDuration float64
+	TotalSize     float64
+
+	getter     AudioGetter
+	audios     []*Audio
+	resolveErr error
+}
+
+func NewAudioQueryResult(getter AudioGetter) *AudioQueryResult {
+	return &AudioQueryResult{
+		getter: getter,
+	}
+}
+
+func (r *AudioQueryResult) Resolve(ctx context.Context) ([]*Audio, error) {
+	// cache results
+	if r.audios == nil && r.resolveErr == nil {
+		r.audios, r.resolveErr = r.getter.FindMany(ctx, r.IDs)
+	}
+	return r.audios, r.resolveErr
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type ClipVideoCodec string
+
+const (
+	// H.264 in an MP4 container
+	ClipVideoCodecH264 ClipVideoCodec = "H264"
+	// HEVC in an MP4 container
+	ClipVideoCodecHevc ClipVideoCodec = "HEVC"
+	// VP9 in a WebM container
+	ClipVideoCodecVp9 ClipVideoCodec = "VP9"
+	// AV1 in an MP4 container
+	ClipVideoCodecAv1 ClipVideoCodec = "AV1"
+)
+
+var AllClipVideoCodec = []ClipVideoCodec{
+	ClipVideoCodecH264,
+	ClipVideoCodecHevc,
+	ClipVideoCodecVp9,
+	ClipVideoCodecAv1,
+}
+
+func (e ClipVideoCodec) IsValid() bool {
+	switch e {
+	case ClipVideoCodecH264, ClipVideoCodecHevc, ClipVideoCodecVp9, ClipVideoCodecAv1:
+		return true
+	}
+	return false
+}
+
+func (e ClipVideoCodec) String() string {
+	return string(e)
+}
+
+func (e *ClipVideoCodec) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ClipVideoCodec(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ClipVideoCodec", str)
+	}
+	return nil
+}
+
+func (e ClipVideoCodec) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
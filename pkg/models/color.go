@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// Color is an RGB color.
+type Color struct {
+	R uint8
+	G uint8
+	B uint8
+}
+
+// ParseHexColor parses a color in the form #rrggbb.
+func ParseHexColor(s string) (Color, error) {
+	var ret Color
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &ret.R, &ret.G, &ret.B); err != nil || len(s) != 7 {
+		return Color{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	return ret, nil
+}
+
+// Hex returns the color in the form #rrggbb.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HSV returns the hue of the color in degrees [0, 360), and the saturation
+// and value (brightness) in the range [0, 1].
+func (c Color) HSV() (h, s, v float64) {
+	r := float64(c.R) / 255
+	g := float64(c.G) / 255
+	b := float64(c.B) / 255
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	v = max
+	if max > 0 {
+		s = delta / max
+	}
+
+	if delta == 0 {
+		return 0, s, v
+	}
+
+	switch max {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+
+	return h, s, v
+}
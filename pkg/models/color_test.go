@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#0a80ff")
+	assert.Nil(t, err)
+	assert.Equal(t, Color{R: 0x0a, G: 0x80, B: 0xff}, c)
+	assert.Equal(t, "#0a80ff", c.Hex())
+
+	for _, s := range []string{"", "0a80ff", "#0a80f", "#0a80ffff", "#zzzzzz"} {
+		_, err := ParseHexColor(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestColorHSV(t *testing.T) {
+	tests := []struct {
+		c       Color
+		h, s, v float64
+	}{
+		{Color{}, 0, 0, 0},
+		{Color{255, 255, 255}, 0, 0, 1},
+		{Color{255, 0, 0}, 0, 1, 1},
+		{Color{0, 255, 0}, 120, 1, 1},
+		{Color{0, 0, 255}, 240, 1, 1},
+		{Color{255, 0, 255}, 300, 1, 1},
+		{Color{0, 0, 128}, 240, 1, 128.0 / 255},
+	}
+
+	for _, tt := range tests {
+		h, s, v := tt.c.HSV()
+		assert.InDelta(t, tt.h, h, 0.001, tt.c.Hex())
+		assert.InDelta(t, tt.s, s, 0.001, tt.c.Hex())
+		assert.InDelta(t, tt.v, v, 0.001, tt.c.Hex())
+	}
+}
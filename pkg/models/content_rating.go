@@ -0,0 +1,36 @@
+package models
+
+import "context"
+
+type maxContentRatingCtxKey struct{}
+
+// WithMaxContentRating returns a context in which scenes, galleries and the
+// objects that depend on them are restricted to those with a content rating
+// of at most max.
+func WithMaxContentRating(ctx context.Context, max int) context.Context {
+	return context.WithValue(ctx, maxContentRatingCtxKey{}, max)
+}
+
+// MaxContentRating returns the maximum content rating accessible in ctx.
+// Returns nil if content is not restricted.
+func MaxContentRating(ctx context.Context) *int {
+	max, ok := ctx.Value(maxContentRatingCtxKey{}).(int)
+	if !ok {
+		return nil
+	}
+
+	return &max
+}
+
+// WithoutContentRating returns a context in which content is not restricted,
+// regardless of any restriction set in ctx.
+func WithoutContentRating(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maxContentRatingCtxKey{}, nil)
+}
+
+// ContentRatingAccessible returns true if an object with the given content
+// rating is accessible in ctx.
+func ContentRatingAccessible(ctx context.Context, rating int) bool {
+	max := MaxContentRating(ctx)
+	return max == nil || rating <= *max
+}
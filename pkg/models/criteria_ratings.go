@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var ErrInvalidCriterionRating = errors.New("invalid criterion rating")
+
+// CriterionRating is the rating of an object for a rating criterion.
+type CriterionRating struct {
+	CriterionID int `json:"criterion_id"`
+	// expressed as 1-100
+	Rating int `json:"rating"`
+}
+
+type CriterionRatingInput struct {
+	CriterionID string `json:"criterion_id"`
+	// expressed as 1-100. Removes the rating if null.
+	Rating *int `json:"rating"`
+}
+
+type CriterionRatingCriterionInput struct {
+	CriterionID string            `json:"criterion_id"`
+	Rating      IntCriterionInput `json:"rating"`
+}
+
+// CriteriaRatingsInput is used to modify the criteria ratings of an object.
+type CriteriaRatingsInput struct {
+	// Ratings to set, replacing any existing ratings for the same criteria
+	Set []CriterionRating
+	// IDs of the criteria to remove the ratings for
+	Remove []int
+}
+
+// Validate returns an error if any of the ratings to set are out of range.
+func (i CriteriaRatingsInput) Validate() error {
+	for _, r := range i.Set {
+		if r.Rating < 1 || r.Rating > 100 {
+			return fmt.Errorf("%w: %d is not between 1 and 100", ErrInvalidCriterionRating, r.Rating)
+		}
+	}
+
+	return nil
+}
+
+// AverageCriteriaRating returns the average of the ratings rounded to the
+// nearest integer, or nil if there are no ratings.
+func AverageCriteriaRating(ratings []CriterionRating) *int {
+	if len(ratings) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, r := range ratings {
+		total += r.Rating
+	}
+
+	ret := int(math.Round(float64(total) / float64(len(ratings))))
+	return &ret
+}
+
+type CriteriaRatingsReader interface {
+	// GetCriteriaRatings returns the criteria ratings of the object, in
+	// criterion name order.
+	GetCriteriaRatings(ctx context.Context, id int) ([]CriterionRating, error)
+}
+
+type CriteriaRatingsWriter interface {
+	SetCriteriaRatings(ctx context.Context, id int, input CriteriaRatingsInput) error
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAverageCriteriaRating(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		ratings []CriterionRating
+		want    *int
+	}{
+		{"none", nil, nil},
+		{"single", []CriterionRating{{Rating: 60}}, intPtr(60)},
+		{"rounds", []CriterionRating{{Rating: 60}, {Rating: 65}}, intPtr(63)},
+		{"rounds down", []CriterionRating{{Rating: 60}, {Rating: 60}, {Rating: 61}}, intPtr(60)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, AverageCriteriaRating(tt.ratings))
+		})
+	}
+}
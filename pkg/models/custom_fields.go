@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxCustomFieldNameLength is the maximum length of a custom field name.
+const MaxCustomFieldNameLength = 64
+
+var (
+	ErrInvalidCustomFieldName  = errors.New("invalid custom field name")
+	ErrInvalidCustomFieldValue = errors.New("invalid custom field value")
+)
+
+// CustomFieldsInput is used to modify the custom fields of an object.
+// Custom field values must be strings, numbers or booleans. Dates are stored
+// as strings in YYYY-MM-DD format, so that they sort and compare correctly.
+type CustomFieldsInput struct {
+	// If set, replaces all existing custom fields
+	Full map[string]interface{} `json:"full"`
+	// If set, sets the provided custom fields, leaving other fields unchanged
+	Partial map[string]interface{} `json:"partial"`
+	// Custom fields to remove
+	Remove []string `json:"remove"`
+}
+
+// Validate returns an error if any of the field names or values in the
+// input are invalid.
+func (i CustomFieldsInput) Validate() error {
+	for _, m := range []map[string]interface{}{i.Full, i.Partial} {
+		for k, v := range m {
+			if err := ValidateCustomFieldName(k); err != nil {
+				return err
+			}
+			if err := ValidateCustomFieldValue(v); err != nil {
+				return fmt.Errorf("%w: field %q", err, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateCustomFieldName returns an error if the name is empty, too long,
+// or has leading or trailing whitespace.
+func ValidateCustomFieldName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: name is empty", ErrInvalidCustomFieldName)
+	case len(name) > MaxCustomFieldNameLength:
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidCustomFieldName, name, MaxCustomFieldNameLength)
+	case strings.TrimSpace(name) != name:
+		return fmt.Errorf("%w: %q has leading or trailing whitespace", ErrInvalidCustomFieldName, name)
+	}
+
+	return nil
+}
+
+// ValidateCustomFieldValue returns an error if the value is not a string,
+// number or boolean.
+func ValidateCustomFieldValue(v interface{}) error {
+	switch v.(type) {
+	case string, bool, json.Number, float64, float32, int, int64, int32:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %v is not a string, number or boolean", ErrInvalidCustomFieldValue, v)
+}
+
+// CustomFieldCriterionInput filters objects by the value of a custom field.
+type CustomFieldCriterionInput struct {
+	Field    string            `json:"field"`
+	Value    []interface{}     `json:"value"`
+	Modifier CriterionModifier `json:"modifier"`
+}
+
+type CustomFieldsReader interface {
+	GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error)
+}
+
+type CustomFieldsWriter interface {
+	SetCustomFields(ctx context.Context, id int, fields CustomFieldsInput) error
+}
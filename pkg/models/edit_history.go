@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEditHistoryChanged is returned when reverting a change to a field that
+// has been changed again since.
+var ErrEditHistoryChanged = errors.New("field has changed since the edit")
+
+type EditHistoryReader interface {
+	Find(ctx context.Context, id int) (*EditHistory, error)
+	// FindByObject returns the changes to the object, most recent first.
+	FindByObject(ctx context.Context, objectType EditObjectType, objectID int) ([]*EditHistory, error)
+}
+
+type EditHistoryWriter interface {
+	// Revert sets the field of the change back to its old value. Returns
+	// ErrEditHistoryChanged if the field has changed since, unless force is
+	// true.
+	Revert(ctx context.Context, id int, force bool) error
+}
+
+type EditHistoryReaderWriter interface {
+	EditHistoryReader
+	EditHistoryWriter
+}
@@ -164,6 +164,12 @@ type TimestampCriterionInput struct {
 	Modifier CriterionModifier `json:"modifier"`
 }
 
+type LibraryCriterionInput struct {
+	// Names of the libraries
+	Value    []string          `json:"value"`
+	Modifier CriterionModifier `json:"modifier"`
+}
+
 type PhashDistanceCriterionInput struct {
 	Value    string            `json:"value"`
 	Modifier CriterionModifier `json:"modifier"`
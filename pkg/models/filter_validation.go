@@ -0,0 +1,257 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FilterValidationError is returned when a filter contains an invalid
+// criterion. Path identifies the invalid value using the input field names,
+// for example "scene_filter.AND.title.modifier".
+type FilterValidationError struct {
+	Path    string
+	Message string
+	// AllowedValues is the set of accepted values, if applicable
+	AllowedValues []string
+}
+
+func (e *FilterValidationError) Error() string {
+	ret := fmt.Sprintf("%s: %s", e.Path, e.Message)
+	if len(e.AllowedValues) > 0 {
+		ret += fmt.Sprintf(" (allowed values: %s)", strings.Join(e.AllowedValues, ", "))
+	}
+	return ret
+}
+
+var (
+	numericModifiers = []CriterionModifier{
+		CriterionModifierEquals,
+		CriterionModifierNotEquals,
+		CriterionModifierGreaterThan,
+		CriterionModifierLessThan,
+		CriterionModifierIsNull,
+		CriterionModifierNotNull,
+		CriterionModifierBetween,
+		CriterionModifierNotBetween,
+	}
+
+	stringModifiers = []CriterionModifier{
+		CriterionModifierEquals,
+		CriterionModifierNotEquals,
+		CriterionModifierIncludes,
+		CriterionModifierExcludes,
+		CriterionModifierMatchesRegex,
+		CriterionModifierNotMatchesRegex,
+		CriterionModifierIsNull,
+		CriterionModifierNotNull,
+	}
+
+	multiModifiers = []CriterionModifier{
+		CriterionModifierIncludes,
+		CriterionModifierIncludesAll,
+		CriterionModifierExcludes,
+		CriterionModifierEquals,
+		CriterionModifierIsNull,
+		CriterionModifierNotNull,
+	}
+
+	// hierarchical criteria such as studios additionally accept NOT_EQUALS
+	hierarchicalModifiers = append(multiModifiers[:len(multiModifiers):len(multiModifiers)], CriterionModifierNotEquals)
+)
+
+// timestampLayouts are the accepted formats of timestamp criterion values,
+// in addition to those accepted by ParseDate.
+var timestampLayouts = []string{
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+}
+
+// ValidateFilter checks the criteria of filter, which must be a pointer to
+// a filter type such as *SceneFilterType. name is used as the root of the
+// path of any returned *FilterValidationError.
+func ValidateFilter(name string, filter interface{}) error {
+	return validateFilterValue(name, reflect.ValueOf(filter))
+}
+
+func validateFilterValue(path string, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	switch c := v.Interface().(type) {
+	case StringCriterionInput:
+		return validateStringCriterion(path, c)
+	case IntCriterionInput:
+		return validateModifier(path, c.Modifier, numericModifiers)
+	case FloatCriterionInput:
+		return validateModifier(path, c.Modifier, numericModifiers)
+	case DateCriterionInput:
+		return validateDateCriterion(path, c.Modifier, c.Value, c.Value2, parseDateValue)
+	case TimestampCriterionInput:
+		return validateDateCriterion(path, c.Modifier, c.Value, c.Value2, parseTimestampValue)
+	case MultiCriterionInput:
+		return validateModifier(path, c.Modifier, multiModifiers)
+	case HierarchicalMultiCriterionInput:
+		return validateModifier(path, c.Modifier, hierarchicalModifiers)
+	}
+
+	if !strings.HasSuffix(v.Type().Name(), "FilterType") {
+		return nil
+	}
+
+	if err := validateSubFilters(path, v); err != nil {
+		return err
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+
+		if err := validateFilterValue(path+"."+name, v.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSubFilters returns an error if more than one of the AND, OR and
+// NOT sub-filters of the filter v is set.
+func validateSubFilters(path string, v reflect.Value) error {
+	var set []string
+	for _, name := range []string{"And", "Or", "Not"} {
+		f := v.FieldByName(name)
+		if f.IsValid() && f.Kind() == reflect.Ptr && !f.IsNil() {
+			set = append(set, strings.ToUpper(name))
+		}
+	}
+
+	if len(set) > 1 {
+		return &FilterValidationError{
+			Path:    path,
+			Message: fmt.Sprintf("cannot have %s in the same filter", strings.Join(set, " and ")),
+		}
+	}
+
+	return nil
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		// unexported
+		return ""
+	}
+
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+
+	return f.Name
+}
+
+func validateModifier(path string, modifier CriterionModifier, allowed []CriterionModifier) error {
+	for _, m := range allowed {
+		if m == modifier {
+			return nil
+		}
+	}
+
+	allowedValues := make([]string, len(allowed))
+	for i, m := range allowed {
+		allowedValues[i] = m.String()
+	}
+
+	return &FilterValidationError{
+		Path:          path + ".modifier",
+		Message:       fmt.Sprintf("unsupported modifier %s", modifier),
+		AllowedValues: allowedValues,
+	}
+}
+
+func validateStringCriterion(path string, c StringCriterionInput) error {
+	if err := validateModifier(path, c.Modifier, stringModifiers); err != nil {
+		return err
+	}
+
+	if c.Modifier == CriterionModifierMatchesRegex || c.Modifier == CriterionModifierNotMatchesRegex {
+		if _, err := regexp.Compile(c.Value); err != nil {
+			return &FilterValidationError{
+				Path:    path + ".value",
+				Message: fmt.Sprintf("invalid regular expression: %v", err),
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateDateCriterion(path string, modifier CriterionModifier, value string, value2 *string, parse func(string) error) error {
+	if err := validateModifier(path, modifier, numericModifiers); err != nil {
+		return err
+	}
+
+	if modifier == CriterionModifierIsNull || modifier == CriterionModifierNotNull {
+		return nil
+	}
+
+	if err := parse(value); err != nil {
+		return &FilterValidationError{
+			Path:    path + ".value",
+			Message: err.Error(),
+		}
+	}
+
+	// value2 defaults to the current date if not set
+	if value2 != nil && (modifier == CriterionModifierBetween || modifier == CriterionModifierNotBetween) {
+		if err := parse(*value2); err != nil {
+			return &FilterValidationError{
+				Path:    path + ".value2",
+				Message: err.Error(),
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseDateValue(s string) error {
+	if _, err := ParseDate(s); err != nil {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD", s)
+	}
+	return nil
+}
+
+func parseTimestampValue(s string) error {
+	if _, err := ParseDate(s); err == nil {
+		return nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid timestamp %q: expected YYYY-MM-DD or YYYY-MM-DDTHH:MM", s)
+}
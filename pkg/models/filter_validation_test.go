@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFilter(t *testing.T) {
+	betweenValue := "2023-13-01"
+
+	tests := []struct {
+		name     string
+		filter   *SceneFilterType
+		wantPath string
+	}{
+		{
+			"nil",
+			nil,
+			"",
+		},
+		{
+			"valid",
+			&SceneFilterType{
+				Title:     &StringCriterionInput{Value: "^a.*", Modifier: CriterionModifierMatchesRegex},
+				Date:      &DateCriterionInput{Value: "2023-01-01", Modifier: CriterionModifierGreaterThan},
+				CreatedAt: &TimestampCriterionInput{Value: "2023-01-01T12:00", Modifier: CriterionModifierLessThan},
+				Tags:      &HierarchicalMultiCriterionInput{Value: []string{"1"}, Modifier: CriterionModifierIncludesAll},
+			},
+			"",
+		},
+		{
+			"invalid modifier",
+			&SceneFilterType{
+				OCounter: &IntCriterionInput{Value: 1, Modifier: CriterionModifierIncludesAll},
+			},
+			"scene_filter.o_counter.modifier",
+		},
+		{
+			"invalid regex in sub-filter",
+			&SceneFilterType{
+				And: &SceneFilterType{
+					Title: &StringCriterionInput{Value: "(", Modifier: CriterionModifierNotMatchesRegex},
+				},
+			},
+			"scene_filter.AND.title.value",
+		},
+		{
+			"invalid date",
+			&SceneFilterType{
+				Date: &DateCriterionInput{Value: "yesterday", Modifier: CriterionModifierEquals},
+			},
+			"scene_filter.date.value",
+		},
+		{
+			"invalid between date",
+			&SceneFilterType{
+				Date: &DateCriterionInput{Value: "2023-01-01", Value2: &betweenValue, Modifier: CriterionModifierBetween},
+			},
+			"scene_filter.date.value2",
+		},
+		{
+			"timestamp with seconds",
+			&SceneFilterType{
+				CreatedAt: &TimestampCriterionInput{Value: "2023-01-01 12:00:30", Modifier: CriterionModifierGreaterThan},
+			},
+			"",
+		},
+		{
+			"RFC3339 timestamp",
+			&SceneFilterType{
+				UpdatedAt: &TimestampCriterionInput{Value: "2023-01-01T12:00:30+02:00", Modifier: CriterionModifierLessThan},
+			},
+			"",
+		},
+		{
+			"invalid timestamp",
+			&SceneFilterType{
+				CreatedAt: &TimestampCriterionInput{Value: "2023-01-01 12", Modifier: CriterionModifierGreaterThan},
+			},
+			"scene_filter.created_at.value",
+		},
+		{
+			"null date",
+			&SceneFilterType{
+				Date: &DateCriterionInput{Modifier: CriterionModifierIsNull},
+			},
+			"",
+		},
+		{
+			"and with or",
+			&SceneFilterType{
+				And: &SceneFilterType{},
+				Or:  &SceneFilterType{},
+			},
+			"scene_filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFilter("scene_filter", tt.filter)
+			if tt.wantPath == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			var filterErr *FilterValidationError
+			if assert.True(t, errors.As(err, &filterErr)) {
+				assert.Equal(t, tt.wantPath, filterErr.Path)
+			}
+		})
+	}
+}
+
+func TestValidateFilterAllowedValues(t *testing.T) {
+	err := ValidateFilter("performer_filter", &PerformerFilterType{
+		Tags: &HierarchicalMultiCriterionInput{Modifier: CriterionModifierBetween},
+	})
+
+	var filterErr *FilterValidationError
+	if assert.True(t, errors.As(err, &filterErr)) {
+		assert.Equal(t, "performer_filter.tags.modifier", filterErr.Path)
+		assert.Contains(t, filterErr.AllowedValues, "INCLUDES_ALL")
+		assert.NotContains(t, filterErr.AllowedValues, "BETWEEN")
+	}
+}
@@ -9,6 +9,7 @@ var (
 	FingerprintTypeOshash = "oshash"
 	FingerprintTypeMD5    = "md5"
 	FingerprintTypePhash  = "phash"
+	FingerprintTypeBlake3 = "blake3"
 )
 
 // Fingerprint represents a fingerprint of a file.
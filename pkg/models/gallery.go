@@ -11,6 +11,8 @@ type GalleryFilterType struct {
 	Checksum *StringCriterionInput `json:"checksum"`
 	// Filter by path
 	Path *StringCriterionInput `json:"path"`
+	// Filter by library
+	Library *LibraryCriterionInput `json:"library"`
 	// Filter by zip file count
 	FileCount *IntCriterionInput `json:"file_count"`
 	// Filter to only include galleries missing this property
@@ -21,6 +23,10 @@ type GalleryFilterType struct {
 	Rating100 *IntCriterionInput `json:"rating100"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by favorite
+	Favorite *bool `json:"favorite"`
+	// Filter by content rating tier
+	ContentRating *IntCriterionInput `json:"content_rating"`
 	// Filter by average image resolution
 	AverageResolution *ResolutionCriterionInput `json:"average_resolution"`
 	// Filter to only include scenes which have chapters. `true` or `false`
@@ -43,6 +49,10 @@ type GalleryFilterType struct {
 	PerformerAge *IntCriterionInput `json:"performer_age"`
 	// Filter by number of images in this gallery
 	ImageCount *IntCriterionInput `json:"image_count"`
+	// Filter to only include galleries with these parent galleries
+	Parents *HierarchicalMultiCriterionInput `json:"parents"`
+	// Filter by number of child galleries
+	ChildCount *IntCriterionInput `json:"child_count"`
 	// Filter by url
 	URL *StringCriterionInput `json:"url"`
 	// Filter by date
@@ -51,22 +61,28 @@ type GalleryFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields. All criteria must match
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type GalleryUpdateInput struct {
-	ClientMutationID *string  `json:"clientMutationId"`
-	ID               string   `json:"id"`
-	Title            *string  `json:"title"`
-	Urls             []string `json:"urls"`
-	Date             *string  `json:"date"`
-	Details          *string  `json:"details"`
-	Rating100        *int     `json:"rating100"`
-	Organized        *bool    `json:"organized"`
-	SceneIds         []string `json:"scene_ids"`
-	StudioID         *string  `json:"studio_id"`
-	TagIds           []string `json:"tag_ids"`
-	PerformerIds     []string `json:"performer_ids"`
-	PrimaryFileID    *string  `json:"primary_file_id"`
+	ClientMutationID *string            `json:"clientMutationId"`
+	ID               string             `json:"id"`
+	Title            *string            `json:"title"`
+	Urls             []string           `json:"urls"`
+	Date             *string            `json:"date"`
+	Details          *string            `json:"details"`
+	Rating100        *int               `json:"rating100"`
+	Organized        *bool              `json:"organized"`
+	Favorite         *bool              `json:"favorite"`
+	ContentRating    *int               `json:"content_rating"`
+	SceneIds         []string           `json:"scene_ids"`
+	StudioID         *string            `json:"studio_id"`
+	ParentID         *string            `json:"parent_id"`
+	TagIds           []string           `json:"tag_ids"`
+	PerformerIds     []string           `json:"performer_ids"`
+	PrimaryFileID    *string            `json:"primary_file_id"`
+	CustomFields     *CustomFieldsInput `json:"custom_fields"`
 
 	// deprecated
 	URL *string `json:"url"`
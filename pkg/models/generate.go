@@ -7,18 +7,25 @@ import (
 )
 
 type GenerateMetadataOptions struct {
-	Covers                    bool                    `json:"covers"`
-	Sprites                   bool                    `json:"sprites"`
-	Previews                  bool                    `json:"previews"`
-	ImagePreviews             bool                    `json:"imagePreviews"`
-	PreviewOptions            *GeneratePreviewOptions `json:"previewOptions"`
-	Markers                   bool                    `json:"markers"`
-	MarkerImagePreviews       bool                    `json:"markerImagePreviews"`
-	MarkerScreenshots         bool                    `json:"markerScreenshots"`
-	Transcodes                bool                    `json:"transcodes"`
-	Phashes                   bool                    `json:"phashes"`
-	InteractiveHeatmapsSpeeds bool                    `json:"interactiveHeatmapsSpeeds"`
-	ClipPreviews              bool                    `json:"clipPreviews"`
+	Covers                    bool                          `json:"covers"`
+	Sprites                   bool                          `json:"sprites"`
+	Previews                  bool                          `json:"previews"`
+	ImagePreviews             bool                          `json:"imagePreviews"`
+	PreviewOptions            *GeneratePreviewOptions       `json:"previewOptions"`
+	Markers                   bool                          `json:"markers"`
+	MarkerImagePreviews       bool                          `json:"markerImagePreviews"`
+	MarkerScreenshots         bool                          `json:"markerScreenshots"`
+	MarkerPreviewOptions      *GenerateMarkerPreviewOptions `json:"markerPreviewOptions"`
+	Transcodes                bool                          `json:"transcodes"`
+	Phashes                   bool                          `json:"phashes"`
+	ImagePhashes              bool                          `json:"imagePhashes"`
+	ImageThumbnails           bool                          `json:"imageThumbnails"`
+	InteractiveHeatmapsSpeeds bool                          `json:"interactiveHeatmapsSpeeds"`
+	Waveforms                 bool                          `json:"waveforms"`
+	ClipPreviews              bool                          `json:"clipPreviews"`
+	ClipTranscodes            bool                          `json:"clipTranscodes"`
+	ColorPalettes             bool                          `json:"colorPalettes"`
+	DocumentPages             bool                          `json:"documentPages"`
 }
 
 type GeneratePreviewOptions struct {
@@ -32,6 +39,19 @@ type GeneratePreviewOptions struct {
 	PreviewExcludeEnd *string `json:"previewExcludeEnd"`
 	// Preset when generating preview
 	PreviewPreset *PreviewPreset `json:"previewPreset"`
+	// Format of the animated image preview
+	PreviewImageFormat *PreviewImageFormat `json:"previewImageFormat"`
+	// Video codec of the preview video
+	PreviewVideoCodec *PreviewVideoCodec `json:"previewVideoCodec"`
+}
+
+type GenerateMarkerPreviewOptions struct {
+	// Format of the marker preview
+	MarkerPreviewFormat *MarkerPreviewFormat `json:"markerPreviewFormat"`
+	// Marker preview duration, in seconds
+	MarkerPreviewDuration *float64 `json:"markerPreviewDuration"`
+	// Marker preview width, in pixels
+	MarkerPreviewWidth *int `json:"markerPreviewWidth"`
 }
 
 type PreviewPreset string
@@ -91,3 +111,187 @@ func (e *PreviewPreset) UnmarshalGQL(v interface{}) error {
 func (e PreviewPreset) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type PreviewImageFormat string
+
+const (
+	// Lossless animated WebP
+	PreviewImageFormatWebpLossless PreviewImageFormat = "WEBP_LOSSLESS"
+	// Lossy animated WebP
+	PreviewImageFormatWebp PreviewImageFormat = "WEBP"
+	// Animated AVIF
+	PreviewImageFormatAvif PreviewImageFormat = "AVIF"
+)
+
+var AllPreviewImageFormat = []PreviewImageFormat{
+	PreviewImageFormatWebpLossless,
+	PreviewImageFormatWebp,
+	PreviewImageFormatAvif,
+}
+
+func (e PreviewImageFormat) IsValid() bool {
+	switch e {
+	case PreviewImageFormatWebpLossless, PreviewImageFormatWebp, PreviewImageFormatAvif:
+		return true
+	}
+	return false
+}
+
+func (e PreviewImageFormat) String() string {
+	return string(e)
+}
+
+func (e *PreviewImageFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PreviewImageFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PreviewImageFormat", str)
+	}
+	return nil
+}
+
+func (e PreviewImageFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PreviewVideoCodec string
+
+const (
+	// H.264, playable by all clients
+	PreviewVideoCodecH264 PreviewVideoCodec = "H264"
+	// AV1, smaller but requires client support
+	PreviewVideoCodecAv1 PreviewVideoCodec = "AV1"
+)
+
+var AllPreviewVideoCodec = []PreviewVideoCodec{
+	PreviewVideoCodecH264,
+	PreviewVideoCodecAv1,
+}
+
+func (e PreviewVideoCodec) IsValid() bool {
+	switch e {
+	case PreviewVideoCodecH264, PreviewVideoCodecAv1:
+		return true
+	}
+	return false
+}
+
+func (e PreviewVideoCodec) String() string {
+	return string(e)
+}
+
+func (e *PreviewVideoCodec) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PreviewVideoCodec(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PreviewVideoCodec", str)
+	}
+	return nil
+}
+
+func (e PreviewVideoCodec) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ImageThumbnailBackend string
+
+const (
+	// libvips if available, otherwise ffmpeg
+	ImageThumbnailBackendAuto ImageThumbnailBackend = "AUTO"
+	// libvips, falling back to ffmpeg if not available
+	ImageThumbnailBackendVips ImageThumbnailBackend = "VIPS"
+	// ffmpeg, scaling on the CPU
+	ImageThumbnailBackendFfmpeg ImageThumbnailBackend = "FFMPEG"
+	// ffmpeg, scaling on a CUDA or VAAPI device if available
+	ImageThumbnailBackendGpu ImageThumbnailBackend = "GPU"
+)
+
+var AllImageThumbnailBackend = []ImageThumbnailBackend{
+	ImageThumbnailBackendAuto,
+	ImageThumbnailBackendVips,
+	ImageThumbnailBackendFfmpeg,
+	ImageThumbnailBackendGpu,
+}
+
+func (e ImageThumbnailBackend) IsValid() bool {
+	switch e {
+	case ImageThumbnailBackendAuto, ImageThumbnailBackendVips, ImageThumbnailBackendFfmpeg, ImageThumbnailBackendGpu:
+		return true
+	}
+	return false
+}
+
+func (e ImageThumbnailBackend) String() string {
+	return string(e)
+}
+
+func (e *ImageThumbnailBackend) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageThumbnailBackend(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageThumbnailBackend", str)
+	}
+	return nil
+}
+
+func (e ImageThumbnailBackend) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MarkerPreviewFormat string
+
+const (
+	// mp4 video
+	MarkerPreviewFormatVideo MarkerPreviewFormat = "VIDEO"
+	// animated webp
+	MarkerPreviewFormatWebp MarkerPreviewFormat = "WEBP"
+	// jpg screenshot
+	MarkerPreviewFormatStatic MarkerPreviewFormat = "STATIC"
+)
+
+var AllMarkerPreviewFormat = []MarkerPreviewFormat{
+	MarkerPreviewFormatVideo,
+	MarkerPreviewFormatWebp,
+	MarkerPreviewFormatStatic,
+}
+
+func (e MarkerPreviewFormat) IsValid() bool {
+	switch e {
+	case MarkerPreviewFormatVideo, MarkerPreviewFormatWebp, MarkerPreviewFormatStatic:
+		return true
+	}
+	return false
+}
+
+func (e MarkerPreviewFormat) String() string {
+	return string(e)
+}
+
+func (e *MarkerPreviewFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MarkerPreviewFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MarkerPreviewFormat", str)
+	}
+	return nil
+}
+
+func (e MarkerPreviewFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
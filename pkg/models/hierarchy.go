@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrOwnAncestor is returned when an object would become an ancestor of
+// itself.
+var ErrOwnAncestor = errors.New("cannot be an ancestor of itself")
+
+// ValidateParent returns an error if the object of type typ with the given
+// id cannot have newParentID as its parent, either because the parent does
+// not exist or because the object would become its own ancestor. An id of 0
+// is used for objects that have not been created. find returns nil if no
+// object has the id, and parentID returns the parent of an object.
+func ValidateParent[T any](ctx context.Context, typ string, id int, newParentID int, find func(ctx context.Context, id int) (*T, error), parentID func(*T) *int) error {
+	// walk up from the new parent, ensuring there is no cyclic dependency
+	seen := make(map[int]bool)
+	for ancestorID := &newParentID; ancestorID != nil && !seen[*ancestorID]; {
+		if *ancestorID == id {
+			return fmt.Errorf("%s %w", typ, ErrOwnAncestor)
+		}
+		seen[*ancestorID] = true
+
+		ancestor, err := find(ctx, *ancestorID)
+		if err != nil {
+			return fmt.Errorf("finding parent %s: %w", typ, err)
+		}
+
+		if ancestor == nil {
+			return fmt.Errorf("%s with id %d not found", typ, *ancestorID)
+		}
+
+		ancestorID = parentID(ancestor)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// RatingHistoryEntry is a rating that was set at a point in time.
+type RatingHistoryEntry struct {
+	// expressed as 1-100. Nil if the rating was removed.
+	Rating *int      `json:"rating"`
+	Date   time.Time `json:"date"`
+}
+
+type HistoryReader interface {
+	// GetODates returns the times at which the o-counter was incremented,
+	// oldest first.
+	GetODates(ctx context.Context, id int) ([]time.Time, error)
+	// GetRatingHistory returns the ratings that were set, oldest first.
+	GetRatingHistory(ctx context.Context, id int) ([]RatingHistoryEntry, error)
+}
+
+type HistoryWriter interface {
+	// ReplaceODates replaces the o-counter event times. The o-counter value
+	// is not changed.
+	ReplaceODates(ctx context.Context, id int, dates []time.Time) error
+	ReplaceRatingHistory(ctx context.Context, id int, history []RatingHistoryEntry) error
+}
@@ -12,6 +12,8 @@ type ImageFilterType struct {
 	Checksum *StringCriterionInput `json:"checksum"`
 	// Filter by path
 	Path *StringCriterionInput `json:"path"`
+	// Filter by library
+	Library *LibraryCriterionInput `json:"library"`
 	// Filter by file count
 	FileCount *IntCriterionInput `json:"file_count"`
 	// Filter by rating expressed as 1-100
@@ -22,8 +24,14 @@ type ImageFilterType struct {
 	URL *StringCriterionInput `json:"url"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by favorite
+	Favorite *bool `json:"favorite"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
+	// Filter by hue of the dominant color (in degrees)
+	DominantHue *FloatCriterionInput `json:"dominant_hue"`
+	// Filter by brightness of the dominant color (0 to 1)
+	DominantBrightness *FloatCriterionInput `json:"dominant_brightness"`
 	// Filter by resolution
 	Resolution *ResolutionCriterionInput `json:"resolution"`
 	// Filter to only include images missing this property
@@ -42,12 +50,14 @@ type ImageFilterType struct {
 	PerformerCount *IntCriterionInput `json:"performer_count"`
 	// Filter images that have performers that have been favorited
 	PerformerFavorite *bool `json:"performer_favorite"`
-	// Filter to only include images with these galleries
-	Galleries *MultiCriterionInput `json:"galleries"`
+	// Filter to only include images with these galleries, or their child galleries
+	Galleries *HierarchicalMultiCriterionInput `json:"galleries"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields. All criteria must match
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type ImageDestroyInput struct {
@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stashapp/stash/pkg/models/json"
+)
+
+// ArchiveManifestFilename is the name of the archive manifest, relative to
+// the export directory.
+const ArchiveManifestFilename = "manifest.json"
+
+// ArchiveObjectCounts is the number of exported objects of each type.
+type ArchiveObjectCounts struct {
+	Scenes     int `json:"scenes"`
+	Images     int `json:"images"`
+	Galleries  int `json:"galleries"`
+	Performers int `json:"performers"`
+	Studios    int `json:"studios"`
+	Tags       int `json:"tags"`
+	Movies     int `json:"movies"`
+	// Files is the number of exported files and folders
+	Files int `json:"files"`
+}
+
+// ArchiveFile describes a single file of an export.
+type ArchiveFile struct {
+	// Path is the slash-separated path of the file, relative to the export
+	// directory
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ArchiveManifest describes the contents of an export, so that its integrity
+// can be verified.
+type ArchiveManifest struct {
+	StashVersion string              `json:"stash_version"`
+	CreatedAt    json.JSONTime       `json:"created_at"`
+	Counts       ArchiveObjectCounts `json:"counts"`
+	Files        []ArchiveFile       `json:"files"`
+}
+
+// NewArchiveFile returns the ArchiveFile of the file at path p with the
+// contents read from r.
+func NewArchiveFile(p string, r io.Reader) (ArchiveFile, error) {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return ArchiveFile{}, err
+	}
+
+	return ArchiveFile{
+		Path:   p,
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+func LoadArchiveManifest(r io.Reader) (*ArchiveManifest, error) {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonParser := json.NewDecoder(r)
+
+	var manifest ArchiveManifest
+	if err := jsonParser.Decode(&manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func SaveArchiveManifestFile(filePath string, manifest *ArchiveManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest must not be nil")
+	}
+	return marshalToFile(filePath, manifest)
+}
+
+// EncodeArchiveManifest returns the JSON encoding of manifest.
+func EncodeArchiveManifest(manifest *ArchiveManifest) ([]byte, error) {
+	return encode(manifest)
+}
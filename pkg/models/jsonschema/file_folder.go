@@ -88,6 +88,10 @@ type VideoFile struct {
 
 	Interactive      bool `json:"interactive,omitempty"`
 	InteractiveSpeed *int `json:"interactive_speed,omitempty"`
+
+	InteractiveAverageSpeed  *int `json:"interactive_average_speed,omitempty"`
+	InteractiveMaxSpeed      *int `json:"interactive_max_speed,omitempty"`
+	InteractiveActionDensity *int `json:"interactive_action_density,omitempty"`
 }
 
 type ImageFile struct {
@@ -109,6 +113,12 @@ func LoadFileFile(filePath string) (DirEntry, error) {
 		return nil, err
 	}
 
+	return ParseDirEntry(data)
+}
+
+// ParseDirEntry decodes the file or folder JSON object in data. The type of
+// the returned DirEntry is determined by its type field.
+func ParseDirEntry(data []byte) (DirEntry, error) {
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
 	jsonParser := json.NewDecoder(bytes.NewReader(data))
 
@@ -0,0 +1,74 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+)
+
+// FilenameTemplateData is the data available to export filename templates.
+type FilenameTemplateData struct {
+	ID       int
+	Title    string
+	Studio   string
+	Date     string
+	Basename string
+	Hash     string
+}
+
+// FilenameTemplate generates export filenames using a text/template.
+type FilenameTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseFilenameTemplate parses s as a filename template. Returns an error if
+// s is not a valid template or references unknown fields.
+func ParseFilenameTemplate(s string) (*FilenameTemplate, error) {
+	tmpl, err := template.New("filename").Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("parsing filename template: %w", err)
+	}
+
+	ret := &FilenameTemplate{tmpl: tmpl}
+
+	// execute with empty data to catch references to unknown fields
+	if _, err := ret.execute(FilenameTemplateData{}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (t *FilenameTemplate) execute(data FilenameTemplateData) (string, error) {
+	var sb strings.Builder
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("executing filename template: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// Filename returns the sanitised output of the template followed by the hash,
+// or the id if hash is empty, so that the filename remains unique. Returns an
+// empty string if the template output is empty.
+func (t *FilenameTemplate) Filename(data FilenameTemplateData) (string, error) {
+	out, err := t.execute(data)
+	if err != nil {
+		return "", err
+	}
+
+	ret := fsutil.SanitiseBasename(out)
+	if ret == "" {
+		return "", nil
+	}
+
+	suffix := data.Hash
+	if suffix == "" {
+		suffix = strconv.Itoa(data.ID)
+	}
+
+	return ret + "." + suffix + ".json", nil
+}
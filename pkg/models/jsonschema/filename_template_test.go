@@ -0,0 +1,73 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilenameTemplate(t *testing.T) {
+	data := FilenameTemplateData{
+		ID:       12,
+		Title:    "Scene Title",
+		Studio:   "Studio/Name",
+		Date:     "2021-03-04",
+		Basename: "scene.mp4",
+		Hash:     "abcdef",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     FilenameTemplateData
+		want     string
+	}{
+		{
+			"title",
+			"{{.Title}}",
+			data,
+			"Scene-Title.abcdef.json",
+		},
+		{
+			"studio date title",
+			"{{.Studio}} - {{.Date}} - {{.Title}}",
+			data,
+			"Studio-Name-2021-03-04-Scene-Title.abcdef.json",
+		},
+		{
+			"conditional",
+			"{{if .Title}}{{.Title}}{{else}}{{.Basename}}{{end}}",
+			FilenameTemplateData{ID: 3, Basename: "gallery.zip"},
+			"gallery.zip.3.json",
+		},
+		{
+			"empty output",
+			"{{.Title}}",
+			FilenameTemplateData{ID: 3},
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseFilenameTemplate(tt.template)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got, err := tmpl.Filename(tt.data)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilenameTemplateInvalid(t *testing.T) {
+	for _, s := range []string{
+		"{{.Title",
+		"{{.Unknown}}",
+	} {
+		_, err := ParseFilenameTemplate(s)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -11,27 +11,32 @@ import (
 )
 
 type GalleryChapter struct {
-	Title      string        `json:"title,omitempty"`
-	ImageIndex int           `json:"image_index,omitempty"`
-	CreatedAt  json.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt  json.JSONTime `json:"updated_at,omitempty"`
+	Title         string        `json:"title,omitempty"`
+	ImageIndex    int           `json:"image_index,omitempty"`
+	EndImageIndex *int          `json:"end_image_index,omitempty"`
+	CreatedAt     json.JSONTime `json:"created_at,omitempty"`
+	UpdatedAt     json.JSONTime `json:"updated_at,omitempty"`
 }
 
 type Gallery struct {
-	ZipFiles   []string         `json:"zip_files,omitempty"`
-	FolderPath string           `json:"folder_path,omitempty"`
-	Title      string           `json:"title,omitempty"`
-	URLs       []string         `json:"urls,omitempty"`
-	Date       string           `json:"date,omitempty"`
-	Details    string           `json:"details,omitempty"`
-	Rating     int              `json:"rating,omitempty"`
-	Organized  bool             `json:"organized,omitempty"`
-	Chapters   []GalleryChapter `json:"chapters,omitempty"`
-	Studio     string           `json:"studio,omitempty"`
-	Performers []string         `json:"performers,omitempty"`
-	Tags       []string         `json:"tags,omitempty"`
-	CreatedAt  json.JSONTime    `json:"created_at,omitempty"`
-	UpdatedAt  json.JSONTime    `json:"updated_at,omitempty"`
+	ZipFiles      []string               `json:"zip_files,omitempty"`
+	FolderPath    string                 `json:"folder_path,omitempty"`
+	Title         string                 `json:"title,omitempty"`
+	URLs          []string               `json:"urls,omitempty"`
+	Date          string                 `json:"date,omitempty"`
+	Details       string                 `json:"details,omitempty"`
+	Rating        int                    `json:"rating,omitempty"`
+	Organized     bool                   `json:"organized,omitempty"`
+	Favorite      bool                   `json:"favorite,omitempty"`
+	ContentRating int                    `json:"content_rating,omitempty"`
+	Chapters      []GalleryChapter       `json:"chapters,omitempty"`
+	Studio        string                 `json:"studio,omitempty"`
+	Performers    []string               `json:"performers,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	CreatedAt     json.JSONTime          `json:"created_at,omitempty"`
+	UpdatedAt     json.JSONTime          `json:"updated_at,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
+	ZipEntries    []GalleryZipEntry      `json:"zip_entries,omitempty"`
 
 	// deprecated - for import only
 	URL string `json:"url,omitempty"`
@@ -71,6 +76,14 @@ func SaveGalleryFile(filePath string, gallery *Gallery) error {
 	return marshalToFile(filePath, gallery)
 }
 
+// GalleryZipEntry is a file contained in the zip file of a gallery, as
+// recorded when the gallery was exported.
+type GalleryZipEntry struct {
+	Path string `json:"path"`
+	// MD5 checksum of the file
+	Checksum string `json:"checksum,omitempty"`
+}
+
 // GalleryRef is used to identify a Gallery.
 // Only one field should be populated.
 type GalleryRef struct {
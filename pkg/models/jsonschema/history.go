@@ -0,0 +1,66 @@
+package jsonschema
+
+import (
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/json"
+)
+
+// RatingHistoryEntry is a rating that was set at a point in time. Rating is
+// omitted if the rating was removed.
+type RatingHistoryEntry struct {
+	Rating int           `json:"rating,omitempty"`
+	Date   json.JSONTime `json:"date"`
+}
+
+func ODatesToJSON(dates []time.Time) []json.JSONTime {
+	var ret []json.JSONTime
+	for _, d := range dates {
+		ret = append(ret, json.JSONTime{Time: d})
+	}
+
+	return ret
+}
+
+func ODatesFromJSON(dates []json.JSONTime) []time.Time {
+	var ret []time.Time
+	for _, d := range dates {
+		ret = append(ret, d.GetTime())
+	}
+
+	return ret
+}
+
+func RatingHistoryToJSON(history []models.RatingHistoryEntry) []RatingHistoryEntry {
+	var ret []RatingHistoryEntry
+	for _, e := range history {
+		entry := RatingHistoryEntry{
+			Date: json.JSONTime{Time: e.Date},
+		}
+		if e.Rating != nil {
+			entry.Rating = *e.Rating
+		}
+
+		ret = append(ret, entry)
+	}
+
+	return ret
+}
+
+func RatingHistoryFromJSON(history []RatingHistoryEntry) []models.RatingHistoryEntry {
+	var ret []models.RatingHistoryEntry
+	for _, e := range history {
+		entry := models.RatingHistoryEntry{
+			Date: e.Date.GetTime(),
+		}
+		if e.Rating != 0 {
+			rating := e.Rating
+			entry.Rating = &rating
+		}
+
+		ret = append(ret, entry)
+	}
+
+	return ret
+}
@@ -17,16 +17,20 @@ type Image struct {
 	// deprecated - for import only
 	URL string `json:"url,omitempty"`
 
-	URLs       []string      `json:"urls,omitempty"`
-	Date       string        `json:"date,omitempty"`
-	Organized  bool          `json:"organized,omitempty"`
-	OCounter   int           `json:"o_counter,omitempty"`
-	Galleries  []GalleryRef  `json:"galleries,omitempty"`
-	Performers []string      `json:"performers,omitempty"`
-	Tags       []string      `json:"tags,omitempty"`
-	Files      []string      `json:"files,omitempty"`
-	CreatedAt  json.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt  json.JSONTime `json:"updated_at,omitempty"`
+	URLs          []string               `json:"urls,omitempty"`
+	Date          string                 `json:"date,omitempty"`
+	Organized     bool                   `json:"organized,omitempty"`
+	Favorite      bool                   `json:"favorite,omitempty"`
+	OCounter      int                    `json:"o_counter,omitempty"`
+	Galleries     []GalleryRef           `json:"galleries,omitempty"`
+	Performers    []string               `json:"performers,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Files         []string               `json:"files,omitempty"`
+	CreatedAt     json.JSONTime          `json:"created_at,omitempty"`
+	UpdatedAt     json.JSONTime          `json:"updated_at,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
+	ODates        []json.JSONTime        `json:"o_dates,omitempty"`
+	RatingHistory []RatingHistoryEntry   `json:"rating_history,omitempty"`
 }
 
 func (s Image) Filename(basename string, hash string) string {
@@ -35,10 +39,12 @@ func (s Image) Filename(basename string, hash string) string {
 		ret = basename
 	}
 
-	if hash != "" {
-		ret += "." + hash
+	if ret != "" && hash != "" {
+		ret += "."
 	}
 
+	ret += hash
+
 	return ret + ".json"
 }
 
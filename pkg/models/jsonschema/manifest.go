@@ -0,0 +1,82 @@
+package jsonschema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stashapp/stash/pkg/models/json"
+)
+
+// ManifestEntry describes a single exported object.
+type ManifestEntry struct {
+	// Key identifies the object across instances. It is the path of the
+	// primary file for scenes and images, the folder path, zip file path or
+	// title for galleries, and the name for other objects.
+	Key       string        `json:"key"`
+	UpdatedAt json.JSONTime `json:"updated_at,omitempty"`
+	// Checksum is the SHA-256 hash of the exported JSON of the object.
+	// If empty, UpdatedAt is used to determine if the object is stale.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Manifest describes the objects present in an instance. It is used to
+// export only the objects that another instance is missing or has stale
+// copies of.
+type Manifest struct {
+	Scenes     []ManifestEntry `json:"scenes,omitempty"`
+	Images     []ManifestEntry `json:"images,omitempty"`
+	Galleries  []ManifestEntry `json:"galleries,omitempty"`
+	Performers []ManifestEntry `json:"performers,omitempty"`
+	Studios    []ManifestEntry `json:"studios,omitempty"`
+	Tags       []ManifestEntry `json:"tags,omitempty"`
+	Movies     []ManifestEntry `json:"movies,omitempty"`
+}
+
+// NewManifestEntry returns the manifest entry of the exported object obj.
+func NewManifestEntry(key string, updatedAt json.JSONTime, obj interface{}) (ManifestEntry, error) {
+	data, err := encode(obj)
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return ManifestEntry{
+		Key:       key,
+		UpdatedAt: updatedAt,
+		Checksum:  hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// IsCurrent returns true if the entry describes the same version of the
+// object as local, or a later one. Checksums are compared if the entry has
+// one, otherwise the updated times are compared.
+func (e ManifestEntry) IsCurrent(local ManifestEntry) bool {
+	if e.Checksum != "" {
+		return e.Checksum == local.Checksum
+	}
+
+	return !local.UpdatedAt.GetTime().After(e.UpdatedAt.GetTime())
+}
+
+func LoadManifest(r io.Reader) (*Manifest, error) {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	jsonParser := json.NewDecoder(r)
+
+	var manifest Manifest
+	if err := jsonParser.Decode(&manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+func SaveManifestFile(filePath string, manifest *Manifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest must not be nil")
+	}
+	return marshalToFile(filePath, manifest)
+}
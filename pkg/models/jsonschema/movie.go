@@ -12,19 +12,22 @@ import (
 )
 
 type Movie struct {
-	Name       string        `json:"name,omitempty"`
-	Aliases    string        `json:"aliases,omitempty"`
-	Duration   int           `json:"duration,omitempty"`
-	Date       string        `json:"date,omitempty"`
-	Rating     int           `json:"rating,omitempty"`
-	Director   string        `json:"director,omitempty"`
-	Synopsis   string        `json:"synopsis,omitempty"`
-	FrontImage string        `json:"front_image,omitempty"`
-	BackImage  string        `json:"back_image,omitempty"`
-	URL        string        `json:"url,omitempty"`
-	Studio     string        `json:"studio,omitempty"`
-	CreatedAt  json.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt  json.JSONTime `json:"updated_at,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	Aliases        string                 `json:"aliases,omitempty"`
+	Duration       int                    `json:"duration,omitempty"`
+	Date           string                 `json:"date,omitempty"`
+	Rating         int                    `json:"rating,omitempty"`
+	Director       string                 `json:"director,omitempty"`
+	Synopsis       string                 `json:"synopsis,omitempty"`
+	FrontImage     string                 `json:"front_image,omitempty"`
+	BackImage      string                 `json:"back_image,omitempty"`
+	FrontImageFile string                 `json:"front_image_file,omitempty"`
+	BackImageFile  string                 `json:"back_image_file,omitempty"`
+	URL            string                 `json:"url,omitempty"`
+	Studio         string                 `json:"studio,omitempty"`
+	CreatedAt      json.JSONTime          `json:"created_at,omitempty"`
+	UpdatedAt      json.JSONTime          `json:"updated_at,omitempty"`
+	CustomFields   map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 func (s Movie) Filename() string {
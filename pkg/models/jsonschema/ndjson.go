@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// maxNDJSONLineSize is the maximum size of a single object in an NDJSON file.
+const maxNDJSONLineSize = 64 * 1024 * 1024
+
+// NDJSONWriter writes objects to a newline-delimited JSON file, one object per
+// line. It is safe for concurrent use.
+type NDJSONWriter struct {
+	mutex  sync.Mutex
+	closer io.Closer
+	w      *bufio.Writer
+}
+
+// CreateNDJSONFile creates or truncates the NDJSON file at filePath.
+func CreateNDJSONFile(filePath string) (*NDJSONWriter, error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNDJSONWriter(f, f), nil
+}
+
+// NewNDJSONWriter returns an NDJSONWriter that writes to w. c is closed when
+// the NDJSONWriter is closed.
+func NewNDJSONWriter(w io.Writer, c io.Closer) *NDJSONWriter {
+	return &NDJSONWriter{
+		closer: c,
+		w:      bufio.NewWriter(w),
+	}
+}
+
+// Write writes v as a single line.
+func (w *NDJSONWriter) Write(v interface{}) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+
+	return w.w.WriteByte('\n')
+}
+
+// Close flushes any buffered data and closes the file.
+func (w *NDJSONWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.w.Flush(); err != nil {
+		w.closer.Close()
+		return err
+	}
+
+	return w.closer.Close()
+}
+
+// ReadNDJSONFile calls fn with each non-empty line of the NDJSON file at
+// filePath. line is the 1-based line number. data is only valid until fn
+// returns.
+func ReadNDJSONFile(filePath string, fn func(line int, data []byte) error) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxNDJSONLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		if err := fn(line, data); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Decode decodes the JSON object read from r into v.
+func Decode(r io.Reader, v interface{}) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.NewDecoder(r).Decode(v)
+}
+
+// Unmarshal decodes the JSON object in data into v.
+func Unmarshal(data []byte, v interface{}) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,70 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "tags.ndjson")
+
+	w, err := CreateNDJSONFile(fn)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	const count = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, w.Write(&Tag{Name: "tag\nwith newline", Aliases: []string{"a"}}))
+		}()
+	}
+	wg.Wait()
+
+	assert.Nil(t, w.Close())
+
+	lines := 0
+	err = ReadNDJSONFile(fn, func(line int, data []byte) error {
+		lines++
+		assert.Equal(t, lines, line)
+
+		var tag Tag
+		if assert.Nil(t, Unmarshal(data, &tag)) {
+			assert.Equal(t, "tag\nwith newline", tag.Name)
+			assert.Equal(t, []string{"a"}, tag.Aliases)
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, count, lines)
+}
+
+func TestReadNDJSONFileSkipsBlankLines(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "files.ndjson")
+	data := "{\"type\":\"folder\",\"path\":\"/a\"}\n\n{\"type\":\"file\",\"path\":\"/a/b\"}\n"
+	if !assert.Nil(t, os.WriteFile(fn, []byte(data), 0644)) {
+		return
+	}
+
+	var got []string
+	err := ReadNDJSONFile(fn, func(line int, data []byte) error {
+		e, err := ParseDirEntry(data)
+		if err != nil {
+			return err
+		}
+
+		got = append(got, e.DirEntry().Path)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/a", "/a/b"}, got)
+}
@@ -14,10 +14,15 @@ import (
 type SceneMarker struct {
 	Title      string        `json:"title,omitempty"`
 	Seconds    string        `json:"seconds,omitempty"`
+	EndSeconds string        `json:"end_seconds,omitempty"`
 	PrimaryTag string        `json:"primary_tag,omitempty"`
 	Tags       []string      `json:"tags,omitempty"`
 	CreatedAt  json.JSONTime `json:"created_at,omitempty"`
 	UpdatedAt  json.JSONTime `json:"updated_at,omitempty"`
+
+	PreviewFormat   string   `json:"preview_format,omitempty"`
+	PreviewDuration *float64 `json:"preview_duration,omitempty"`
+	PreviewWidth    *int     `json:"preview_width,omitempty"`
 }
 
 type SceneFile struct {
@@ -46,27 +51,33 @@ type Scene struct {
 	// deprecated - for import only
 	URL string `json:"url,omitempty"`
 
-	URLs         []string         `json:"urls,omitempty"`
-	Date         string           `json:"date,omitempty"`
-	Rating       int              `json:"rating,omitempty"`
-	Organized    bool             `json:"organized,omitempty"`
-	OCounter     int              `json:"o_counter,omitempty"`
-	Details      string           `json:"details,omitempty"`
-	Director     string           `json:"director,omitempty"`
-	Galleries    []GalleryRef     `json:"galleries,omitempty"`
-	Performers   []string         `json:"performers,omitempty"`
-	Movies       []SceneMovie     `json:"movies,omitempty"`
-	Tags         []string         `json:"tags,omitempty"`
-	Markers      []SceneMarker    `json:"markers,omitempty"`
-	Files        []string         `json:"files,omitempty"`
-	Cover        string           `json:"cover,omitempty"`
-	CreatedAt    json.JSONTime    `json:"created_at,omitempty"`
-	UpdatedAt    json.JSONTime    `json:"updated_at,omitempty"`
-	LastPlayedAt json.JSONTime    `json:"last_played_at,omitempty"`
-	ResumeTime   float64          `json:"resume_time,omitempty"`
-	PlayCount    int              `json:"play_count,omitempty"`
-	PlayDuration float64          `json:"play_duration,omitempty"`
-	StashIDs     []models.StashID `json:"stash_ids,omitempty"`
+	URLs          []string               `json:"urls,omitempty"`
+	Date          string                 `json:"date,omitempty"`
+	Rating        int                    `json:"rating,omitempty"`
+	Organized     bool                   `json:"organized,omitempty"`
+	Favorite      bool                   `json:"favorite,omitempty"`
+	ContentRating int                    `json:"content_rating,omitempty"`
+	OCounter      int                    `json:"o_counter,omitempty"`
+	Details       string                 `json:"details,omitempty"`
+	Director      string                 `json:"director,omitempty"`
+	Galleries     []GalleryRef           `json:"galleries,omitempty"`
+	Performers    []string               `json:"performers,omitempty"`
+	Movies        []SceneMovie           `json:"movies,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Markers       []SceneMarker          `json:"markers,omitempty"`
+	Files         []string               `json:"files,omitempty"`
+	Cover         string                 `json:"cover,omitempty"`
+	CoverFile     string                 `json:"cover_file,omitempty"`
+	CreatedAt     json.JSONTime          `json:"created_at,omitempty"`
+	UpdatedAt     json.JSONTime          `json:"updated_at,omitempty"`
+	LastPlayedAt  json.JSONTime          `json:"last_played_at,omitempty"`
+	ResumeTime    float64                `json:"resume_time,omitempty"`
+	PlayCount     int                    `json:"play_count,omitempty"`
+	PlayDuration  float64                `json:"play_duration,omitempty"`
+	StashIDs      []models.StashID       `json:"stash_ids,omitempty"`
+	CustomFields  map[string]interface{} `json:"custom_fields,omitempty"`
+	ODates        []json.JSONTime        `json:"o_dates,omitempty"`
+	RatingHistory []RatingHistoryEntry   `json:"rating_history,omitempty"`
 }
 
 func (s Scene) Filename(id int, basename string, hash string) string {
@@ -75,11 +86,15 @@ func (s Scene) Filename(id int, basename string, hash string) string {
 		ret = basename
 	}
 
+	if ret != "" {
+		ret += "."
+	}
+
 	if hash != "" {
-		ret += "." + hash
+		ret += hash
 	} else {
 		// scenes may have no file and therefore no hash
-		ret += "." + strconv.Itoa(id)
+		ret += strconv.Itoa(id)
 	}
 
 	return ret + ".json"
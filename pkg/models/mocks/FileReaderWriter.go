@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/stashapp/stash/pkg/models"
+
+	time "time"
 )
 
 // FileReaderWriter is an autogenerated mock type for the FileReaderWriter type
@@ -16,6 +18,20 @@ type FileReaderWriter struct {
 	mock.Mock
 }
 
+// AddLink provides a mock function with given fields: ctx, fileID, path
+func (_m *FileReaderWriter) AddLink(ctx context.Context, fileID models.FileID, path string) error {
+	ret := _m.Called(ctx, fileID, path)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.FileID, string) error); ok {
+		r0 = rf(ctx, fileID, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CountAllInPaths provides a mock function with given fields: ctx, p
 func (_m *FileReaderWriter) CountAllInPaths(ctx context.Context, p []string) (int, error) {
 	ret := _m.Called(ctx, p)
@@ -37,6 +53,27 @@ func (_m *FileReaderWriter) CountAllInPaths(ctx context.Context, p []string) (in
 	return r0, r1
 }
 
+// CountForVerification provides a mock function with given fields: ctx, p
+func (_m *FileReaderWriter) CountForVerification(ctx context.Context, p []string) (int, error) {
+	ret := _m.Called(ctx, p)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, []string) int); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CountByFolderID provides a mock function with given fields: ctx, folderID
 func (_m *FileReaderWriter) CountByFolderID(ctx context.Context, folderID models.FolderID) (int, error) {
 	ret := _m.Called(ctx, folderID)
@@ -86,6 +123,20 @@ func (_m *FileReaderWriter) Destroy(ctx context.Context, id models.FileID) error
 	return r0
 }
 
+// DestroyLink provides a mock function with given fields: ctx, path
+func (_m *FileReaderWriter) DestroyLink(ctx context.Context, path string) error {
+	ret := _m.Called(ctx, path)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Find provides a mock function with given fields: ctx, id
 func (_m *FileReaderWriter) Find(ctx context.Context, id ...models.FileID) ([]models.File, error) {
 	_va := make([]interface{}, len(id))
@@ -208,6 +259,29 @@ func (_m *FileReaderWriter) FindByFingerprint(ctx context.Context, fp models.Fin
 	return r0, r1
 }
 
+// FindByLink provides a mock function with given fields: ctx, path
+func (_m *FileReaderWriter) FindByLink(ctx context.Context, path string) (models.File, error) {
+	ret := _m.Called(ctx, path)
+
+	var r0 models.File
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.File); ok {
+		r0 = rf(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(models.File)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByPath provides a mock function with given fields: ctx, path
 func (_m *FileReaderWriter) FindByPath(ctx context.Context, path string) (models.File, error) {
 	ret := _m.Called(ctx, path)
@@ -254,6 +328,75 @@ func (_m *FileReaderWriter) FindByZipFileID(ctx context.Context, zipFileID model
 	return r0, r1
 }
 
+// FindFailedVerifications provides a mock function with given fields: ctx
+func (_m *FileReaderWriter) FindFailedVerifications(ctx context.Context) ([]models.FileVerification, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []models.FileVerification
+	if rf, ok := ret.Get(0).(func(context.Context) []models.FileVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FileVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindForVerification provides a mock function with given fields: ctx, p, before, limit
+func (_m *FileReaderWriter) FindForVerification(ctx context.Context, p []string, before time.Time, limit int) ([]models.File, error) {
+	ret := _m.Called(ctx, p, before, limit)
+
+	var r0 []models.File
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Time, int) []models.File); ok {
+		r0 = rf(ctx, p, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.File)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Time, int) error); ok {
+		r1 = rf(ctx, p, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLinksInPaths provides a mock function with given fields: ctx, p
+func (_m *FileReaderWriter) FindLinksInPaths(ctx context.Context, p []string) ([]models.FileLink, error) {
+	ret := _m.Called(ctx, p)
+
+	var r0 []models.FileLink
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []models.FileLink); ok {
+		r0 = rf(ctx, p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FileLink)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCaptions provides a mock function with given fields: ctx, fileID
 func (_m *FileReaderWriter) GetCaptions(ctx context.Context, fileID models.FileID) ([]*models.VideoCaption, error) {
 	ret := _m.Called(ctx, fileID)
@@ -277,6 +420,29 @@ func (_m *FileReaderWriter) GetCaptions(ctx context.Context, fileID models.FileI
 	return r0, r1
 }
 
+// GetLinks provides a mock function with given fields: ctx, fileID
+func (_m *FileReaderWriter) GetLinks(ctx context.Context, fileID models.FileID) ([]string, error) {
+	ret := _m.Called(ctx, fileID)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, models.FileID) []string); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.FileID) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsPrimary provides a mock function with given fields: ctx, fileID
 func (_m *FileReaderWriter) IsPrimary(ctx context.Context, fileID models.FileID) (bool, error) {
 	ret := _m.Called(ctx, fileID)
@@ -321,6 +487,27 @@ func (_m *FileReaderWriter) Query(ctx context.Context, options models.FileQueryO
 	return r0, r1
 }
 
+// Size provides a mock function with given fields: ctx
+func (_m *FileReaderWriter) Size(ctx context.Context) (float64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(context.Context) float64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, f
 func (_m *FileReaderWriter) Update(ctx context.Context, f models.File) error {
 	ret := _m.Called(ctx, f)
@@ -348,3 +535,17 @@ func (_m *FileReaderWriter) UpdateCaptions(ctx context.Context, fileID models.Fi
 
 	return r0
 }
+
+// UpdateVerification provides a mock function with given fields: ctx, v
+func (_m *FileReaderWriter) UpdateVerification(ctx context.Context, v models.FileVerification) error {
+	ret := _m.Called(ctx, v)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.FileVerification) error); ok {
+		r0 = rf(ctx, v)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
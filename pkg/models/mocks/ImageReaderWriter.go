@@ -7,6 +7,8 @@ import (
 
 	models "github.com/stashapp/stash/pkg/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // ImageReaderWriter is an autogenerated mock type for the ImageReaderWriter type
@@ -324,6 +326,29 @@ func (_m *ImageReaderWriter) FindByZipFileID(ctx context.Context, zipFileID mode
 	return r0, r1
 }
 
+// FindDuplicates provides a mock function with given fields: ctx, distance
+func (_m *ImageReaderWriter) FindDuplicates(ctx context.Context, distance int) ([][]*models.Image, error) {
+	ret := _m.Called(ctx, distance)
+
+	var r0 [][]*models.Image
+	if rf, ok := ret.Get(0).(func(context.Context, int) [][]*models.Image); ok {
+		r0 = rf(ctx, distance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]*models.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, distance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMany provides a mock function with given fields: ctx, ids
 func (_m *ImageReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models.Image, error) {
 	ret := _m.Called(ctx, ids)
@@ -347,6 +372,75 @@ func (_m *ImageReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models
 	return r0, r1
 }
 
+// FindSimilarByColor provides a mock function with given fields: ctx, imageID, limit
+func (_m *ImageReaderWriter) FindSimilarByColor(ctx context.Context, imageID int, limit int) ([]*models.Image, error) {
+	ret := _m.Called(ctx, imageID, limit)
+
+	var r0 []*models.Image
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*models.Image); ok {
+		r0 = rf(ctx, imageID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, imageID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetColorPalette provides a mock function with given fields: ctx, imageID
+func (_m *ImageReaderWriter) GetColorPalette(ctx context.Context, imageID int) ([]models.Color, error) {
+	ret := _m.Called(ctx, imageID)
+
+	var r0 []models.Color
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Color); ok {
+		r0 = rf(ctx, imageID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Color)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *ImageReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFiles provides a mock function with given fields: ctx, relatedID
 func (_m *ImageReaderWriter) GetFiles(ctx context.Context, relatedID int) ([]models.File, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -416,6 +510,29 @@ func (_m *ImageReaderWriter) GetManyFileIDs(ctx context.Context, ids []int) ([][
 	return r0, r1
 }
 
+// GetODates provides a mock function with given fields: ctx, id
+func (_m *ImageReaderWriter) GetODates(ctx context.Context, id int) ([]time.Time, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, int) []time.Time); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPerformerIDs provides a mock function with given fields: ctx, relatedID
 func (_m *ImageReaderWriter) GetPerformerIDs(ctx context.Context, relatedID int) ([]int, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -439,6 +556,29 @@ func (_m *ImageReaderWriter) GetPerformerIDs(ctx context.Context, relatedID int)
 	return r0, r1
 }
 
+// GetRatingHistory provides a mock function with given fields: ctx, id
+func (_m *ImageReaderWriter) GetRatingHistory(ctx context.Context, id int) ([]models.RatingHistoryEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []models.RatingHistoryEntry
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.RatingHistoryEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RatingHistoryEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTagIDs provides a mock function with given fields: ctx, relatedID
 func (_m *ImageReaderWriter) GetTagIDs(ctx context.Context, relatedID int) ([]int, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -571,6 +711,34 @@ func (_m *ImageReaderWriter) QueryCount(ctx context.Context, imageFilter *models
 	return r0, r1
 }
 
+// ReplaceODates provides a mock function with given fields: ctx, id, dates
+func (_m *ImageReaderWriter) ReplaceODates(ctx context.Context, id int, dates []time.Time) error {
+	ret := _m.Called(ctx, id, dates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []time.Time) error); ok {
+		r0 = rf(ctx, id, dates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceRatingHistory provides a mock function with given fields: ctx, id, history
+func (_m *ImageReaderWriter) ReplaceRatingHistory(ctx context.Context, id int, history []models.RatingHistoryEntry) error {
+	ret := _m.Called(ctx, id, history)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.RatingHistoryEntry) error); ok {
+		r0 = rf(ctx, id, history)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResetOCounter provides a mock function with given fields: ctx, id
 func (_m *ImageReaderWriter) ResetOCounter(ctx context.Context, id int) (int, error) {
 	ret := _m.Called(ctx, id)
@@ -592,6 +760,20 @@ func (_m *ImageReaderWriter) ResetOCounter(ctx context.Context, id int) (int, er
 	return r0, r1
 }
 
+// SetCustomFields provides a mock function with given fields: ctx, id, fields
+func (_m *ImageReaderWriter) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, fields)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, fields)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Size provides a mock function with given fields: ctx
 func (_m *ImageReaderWriter) Size(ctx context.Context) (float64, error) {
 	ret := _m.Called(ctx)
@@ -627,6 +809,20 @@ func (_m *ImageReaderWriter) Update(ctx context.Context, updatedImage *models.Im
 	return r0
 }
 
+// UpdateColorPalette provides a mock function with given fields: ctx, imageID, palette
+func (_m *ImageReaderWriter) UpdateColorPalette(ctx context.Context, imageID int, palette []models.Color) error {
+	ret := _m.Called(ctx, imageID, palette)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.Color) error); ok {
+		r0 = rf(ctx, imageID, palette)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePartial provides a mock function with given fields: ctx, id, partial
 func (_m *ImageReaderWriter) UpdatePartial(ctx context.Context, id int, partial models.ImagePartial) (*models.Image, error) {
 	ret := _m.Called(ctx, id, partial)
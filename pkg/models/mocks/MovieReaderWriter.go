@@ -243,6 +243,29 @@ func (_m *MovieReaderWriter) FindByStudioID(ctx context.Context, studioID int) (
 	return r0, r1
 }
 
+// FindChildren provides a mock function with given fields: ctx, id
+func (_m *MovieReaderWriter) FindChildren(ctx context.Context, id int) ([]*models.Movie, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*models.Movie
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*models.Movie); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Movie)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMany provides a mock function with given fields: ctx, ids
 func (_m *MovieReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models.Movie, error) {
 	ret := _m.Called(ctx, ids)
@@ -289,6 +312,29 @@ func (_m *MovieReaderWriter) GetBackImage(ctx context.Context, movieID int) ([]b
 	return r0, r1
 }
 
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *MovieReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFrontImage provides a mock function with given fields: ctx, movieID
 func (_m *MovieReaderWriter) GetFrontImage(ctx context.Context, movieID int) ([]byte, error) {
 	ret := _m.Called(ctx, movieID)
@@ -312,6 +358,29 @@ func (_m *MovieReaderWriter) GetFrontImage(ctx context.Context, movieID int) ([]
 	return r0, r1
 }
 
+// GetSceneIndexes provides a mock function with given fields: ctx, movieID
+func (_m *MovieReaderWriter) GetSceneIndexes(ctx context.Context, movieID int) ([]models.MovieScene, error) {
+	ret := _m.Called(ctx, movieID)
+
+	var r0 []models.MovieScene
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.MovieScene); ok {
+		r0 = rf(ctx, movieID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.MovieScene)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, movieID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasBackImage provides a mock function with given fields: ctx, movieID
 func (_m *MovieReaderWriter) HasBackImage(ctx context.Context, movieID int) (bool, error) {
 	ret := _m.Called(ctx, movieID)
@@ -405,6 +474,20 @@ func (_m *MovieReaderWriter) QueryCount(ctx context.Context, movieFilter *models
 	return r0, r1
 }
 
+// SetCustomFields provides a mock function with given fields: ctx, id, fields
+func (_m *MovieReaderWriter) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, fields)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, fields)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, updatedMovie
 func (_m *MovieReaderWriter) Update(ctx context.Context, updatedMovie *models.Movie) error {
 	ret := _m.Called(ctx, updatedMovie)
@@ -469,3 +552,17 @@ func (_m *MovieReaderWriter) UpdatePartial(ctx context.Context, id int, updatedM
 
 	return r0, r1
 }
+
+// UpdateSceneIndexes provides a mock function with given fields: ctx, movieID, indexes
+func (_m *MovieReaderWriter) UpdateSceneIndexes(ctx context.Context, movieID int, indexes []models.MovieScene) error {
+	ret := _m.Called(ctx, movieID, indexes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.MovieScene) error); ok {
+		r0 = rf(ctx, movieID, indexes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
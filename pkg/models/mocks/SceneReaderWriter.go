@@ -7,6 +7,8 @@ import (
 
 	models "github.com/stashapp/stash/pkg/models"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // SceneReaderWriter is an autogenerated mock type for the SceneReaderWriter type
@@ -593,6 +595,52 @@ func (_m *SceneReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models
 	return r0, r1
 }
 
+// FindSimilarByColor provides a mock function with given fields: ctx, sceneID, limit
+func (_m *SceneReaderWriter) FindSimilarByColor(ctx context.Context, sceneID int, limit int) ([]*models.Scene, error) {
+	ret := _m.Called(ctx, sceneID, limit)
+
+	var r0 []*models.Scene
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*models.Scene); ok {
+		r0 = rf(ctx, sceneID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Scene)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, sceneID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetColorPalette provides a mock function with given fields: ctx, sceneID
+func (_m *SceneReaderWriter) GetColorPalette(ctx context.Context, sceneID int) ([]models.Color, error) {
+	ret := _m.Called(ctx, sceneID)
+
+	var r0 []models.Color
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Color); ok {
+		r0 = rf(ctx, sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Color)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCover provides a mock function with given fields: ctx, sceneID
 func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte, error) {
 	ret := _m.Called(ctx, sceneID)
@@ -616,6 +664,52 @@ func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte,
 	return r0, r1
 }
 
+// GetCriteriaRatings provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) GetCriteriaRatings(ctx context.Context, id int) ([]models.CriterionRating, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []models.CriterionRating
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.CriterionRating); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CriterionRating)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFiles provides a mock function with given fields: ctx, relatedID
 func (_m *SceneReaderWriter) GetFiles(ctx context.Context, relatedID int) ([]*models.VideoFile, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -662,6 +756,27 @@ func (_m *SceneReaderWriter) GetGalleryIDs(ctx context.Context, relatedID int) (
 	return r0, r1
 }
 
+// GetGenerateState provides a mock function with given fields: ctx, sceneID, optionsHash
+func (_m *SceneReaderWriter) GetGenerateState(ctx context.Context, sceneID int, optionsHash string) (string, error) {
+	ret := _m.Called(ctx, sceneID, optionsHash)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) string); ok {
+		r0 = rf(ctx, sceneID, optionsHash)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, sceneID, optionsHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetManyFileIDs provides a mock function with given fields: ctx, ids
 func (_m *SceneReaderWriter) GetManyFileIDs(ctx context.Context, ids []int) ([][]models.FileID, error) {
 	ret := _m.Called(ctx, ids)
@@ -708,6 +823,52 @@ func (_m *SceneReaderWriter) GetMovies(ctx context.Context, id int) ([]models.Mo
 	return r0, r1
 }
 
+// GetODates provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) GetODates(ctx context.Context, id int) ([]time.Time, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, int) []time.Time); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPartFileIDs provides a mock function with given fields: ctx, sceneID
+func (_m *SceneReaderWriter) GetPartFileIDs(ctx context.Context, sceneID int) ([]models.FileID, error) {
+	ret := _m.Called(ctx, sceneID)
+
+	var r0 []models.FileID
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.FileID); ok {
+		r0 = rf(ctx, sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.FileID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPerformerIDs provides a mock function with given fields: ctx, relatedID
 func (_m *SceneReaderWriter) GetPerformerIDs(ctx context.Context, relatedID int) ([]int, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -731,6 +892,52 @@ func (_m *SceneReaderWriter) GetPerformerIDs(ctx context.Context, relatedID int)
 	return r0, r1
 }
 
+// GetPreviewOptions provides a mock function with given fields: ctx, sceneID
+func (_m *SceneReaderWriter) GetPreviewOptions(ctx context.Context, sceneID int) (*models.ScenePreviewOptions, error) {
+	ret := _m.Called(ctx, sceneID)
+
+	var r0 *models.ScenePreviewOptions
+	if rf, ok := ret.Get(0).(func(context.Context, int) *models.ScenePreviewOptions); ok {
+		r0 = rf(ctx, sceneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ScenePreviewOptions)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sceneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRatingHistory provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) GetRatingHistory(ctx context.Context, id int) ([]models.RatingHistoryEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []models.RatingHistoryEntry
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.RatingHistoryEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RatingHistoryEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStashIDs provides a mock function with given fields: ctx, relatedID
 func (_m *SceneReaderWriter) GetStashIDs(ctx context.Context, relatedID int) ([]models.StashID, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -991,6 +1198,34 @@ func (_m *SceneReaderWriter) QueryCount(ctx context.Context, sceneFilter *models
 	return r0, r1
 }
 
+// ReplaceODates provides a mock function with given fields: ctx, id, dates
+func (_m *SceneReaderWriter) ReplaceODates(ctx context.Context, id int, dates []time.Time) error {
+	ret := _m.Called(ctx, id, dates)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []time.Time) error); ok {
+		r0 = rf(ctx, id, dates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceRatingHistory provides a mock function with given fields: ctx, id, history
+func (_m *SceneReaderWriter) ReplaceRatingHistory(ctx context.Context, id int, history []models.RatingHistoryEntry) error {
+	ret := _m.Called(ctx, id, history)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.RatingHistoryEntry) error); ok {
+		r0 = rf(ctx, id, history)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResetOCounter provides a mock function with given fields: ctx, id
 func (_m *SceneReaderWriter) ResetOCounter(ctx context.Context, id int) (int, error) {
 	ret := _m.Called(ctx, id)
@@ -1033,6 +1268,76 @@ func (_m *SceneReaderWriter) SaveActivity(ctx context.Context, sceneID int, resu
 	return r0, r1
 }
 
+// SetCriteriaRatings provides a mock function with given fields: ctx, id, input
+func (_m *SceneReaderWriter) SetCriteriaRatings(ctx context.Context, id int, input models.CriteriaRatingsInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CriteriaRatingsInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCustomFields provides a mock function with given fields: ctx, id, fields
+func (_m *SceneReaderWriter) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, fields)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, fields)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetFileParts provides a mock function with given fields: ctx, sceneID, fileIDs
+func (_m *SceneReaderWriter) SetFileParts(ctx context.Context, sceneID int, fileIDs []models.FileID) error {
+	ret := _m.Called(ctx, sceneID, fileIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.FileID) error); ok {
+		r0 = rf(ctx, sceneID, fileIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetGenerateState provides a mock function with given fields: ctx, sceneID, optionsHash, stateHash
+func (_m *SceneReaderWriter) SetGenerateState(ctx context.Context, sceneID int, optionsHash string, stateHash string) error {
+	ret := _m.Called(ctx, sceneID, optionsHash, stateHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string) error); ok {
+		r0 = rf(ctx, sceneID, optionsHash, stateHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPreviewOptions provides a mock function with given fields: ctx, sceneID, options
+func (_m *SceneReaderWriter) SetPreviewOptions(ctx context.Context, sceneID int, options models.ScenePreviewOptions) error {
+	ret := _m.Called(ctx, sceneID, options)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.ScenePreviewOptions) error); ok {
+		r0 = rf(ctx, sceneID, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Size provides a mock function with given fields: ctx
 func (_m *SceneReaderWriter) Size(ctx context.Context) (float64, error) {
 	ret := _m.Called(ctx)
@@ -1089,6 +1394,20 @@ func (_m *SceneReaderWriter) Update(ctx context.Context, updatedScene *models.Sc
 	return r0
 }
 
+// UpdateColorPalette provides a mock function with given fields: ctx, sceneID, palette
+func (_m *SceneReaderWriter) UpdateColorPalette(ctx context.Context, sceneID int, palette []models.Color) error {
+	ret := _m.Called(ctx, sceneID, palette)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.Color) error); ok {
+		r0 = rf(ctx, sceneID, palette)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateCover provides a mock function with given fields: ctx, sceneID, cover
 func (_m *SceneReaderWriter) UpdateCover(ctx context.Context, sceneID int, cover []byte) error {
 	ret := _m.Called(ctx, sceneID, cover)
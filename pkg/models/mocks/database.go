@@ -9,18 +9,23 @@ import (
 )
 
 type Database struct {
-	File           *FileReaderWriter
-	Folder         *FolderReaderWriter
-	Gallery        *GalleryReaderWriter
-	GalleryChapter *GalleryChapterReaderWriter
-	Image          *ImageReaderWriter
-	Movie          *MovieReaderWriter
-	Performer      *PerformerReaderWriter
-	Scene          *SceneReaderWriter
-	SceneMarker    *SceneMarkerReaderWriter
-	Studio         *StudioReaderWriter
-	Tag            *TagReaderWriter
-	SavedFilter    *SavedFilterReaderWriter
+	Audio             *AudioReaderWriter
+	File              *FileReaderWriter
+	Folder            *FolderReaderWriter
+	Gallery           *GalleryReaderWriter
+	GalleryChapter    *GalleryChapterReaderWriter
+	Image             *ImageReaderWriter
+	Movie             *MovieReaderWriter
+	Performer         *PerformerReaderWriter
+	Scene             *SceneReaderWriter
+	SceneMarker       *SceneMarkerReaderWriter
+	SceneRelationship *SceneRelationshipReaderWriter
+	Studio            *StudioReaderWriter
+	Tag               *TagReaderWriter
+	SavedFilter       *SavedFilterReaderWriter
+	SelectionSet      *SelectionSetReaderWriter
+	EditHistory       *EditHistoryReaderWriter
+	RatingCriterion   *RatingCriterionReaderWriter
 }
 
 func (*Database) Begin(ctx context.Context, exclusive bool) (context.Context, error) {
@@ -58,22 +63,28 @@ func (*Database) Reset() error {
 
 func NewDatabase() *Database {
 	return &Database{
-		File:           &FileReaderWriter{},
-		Folder:         &FolderReaderWriter{},
-		Gallery:        &GalleryReaderWriter{},
-		GalleryChapter: &GalleryChapterReaderWriter{},
-		Image:          &ImageReaderWriter{},
-		Movie:          &MovieReaderWriter{},
-		Performer:      &PerformerReaderWriter{},
-		Scene:          &SceneReaderWriter{},
-		SceneMarker:    &SceneMarkerReaderWriter{},
-		Studio:         &StudioReaderWriter{},
-		Tag:            &TagReaderWriter{},
-		SavedFilter:    &SavedFilterReaderWriter{},
+		Audio:             &AudioReaderWriter{},
+		File:              &FileReaderWriter{},
+		Folder:            &FolderReaderWriter{},
+		Gallery:           &GalleryReaderWriter{},
+		GalleryChapter:    &GalleryChapterReaderWriter{},
+		Image:             &ImageReaderWriter{},
+		Movie:             &MovieReaderWriter{},
+		Performer:         &PerformerReaderWriter{},
+		Scene:             &SceneReaderWriter{},
+		SceneMarker:       &SceneMarkerReaderWriter{},
+		SceneRelationship: &SceneRelationshipReaderWriter{},
+		Studio:            &StudioReaderWriter{},
+		Tag:               &TagReaderWriter{},
+		SavedFilter:       &SavedFilterReaderWriter{},
+		SelectionSet:      &SelectionSetReaderWriter{},
+		EditHistory:       &EditHistoryReaderWriter{},
+		RatingCriterion:   &RatingCriterionReaderWriter{},
 	}
 }
 
 func (db *Database) AssertExpectations(t mock.TestingT) {
+	db.Audio.AssertExpectations(t)
 	db.File.AssertExpectations(t)
 	db.Folder.AssertExpectations(t)
 	db.Gallery.AssertExpectations(t)
@@ -83,25 +94,34 @@ func (db *Database) AssertExpectations(t mock.TestingT) {
 	db.Performer.AssertExpectations(t)
 	db.Scene.AssertExpectations(t)
 	db.SceneMarker.AssertExpectations(t)
+	db.SceneRelationship.AssertExpectations(t)
 	db.Studio.AssertExpectations(t)
 	db.Tag.AssertExpectations(t)
 	db.SavedFilter.AssertExpectations(t)
+	db.SelectionSet.AssertExpectations(t)
+	db.EditHistory.AssertExpectations(t)
+	db.RatingCriterion.AssertExpectations(t)
 }
 
 func (db *Database) Repository() models.Repository {
 	return models.Repository{
-		TxnManager:     db,
-		File:           db.File,
-		Folder:         db.Folder,
-		Gallery:        db.Gallery,
-		GalleryChapter: db.GalleryChapter,
-		Image:          db.Image,
-		Movie:          db.Movie,
-		Performer:      db.Performer,
-		Scene:          db.Scene,
-		SceneMarker:    db.SceneMarker,
-		Studio:         db.Studio,
-		Tag:            db.Tag,
-		SavedFilter:    db.SavedFilter,
+		TxnManager:        db,
+		Audio:             db.Audio,
+		File:              db.File,
+		Folder:            db.Folder,
+		Gallery:           db.Gallery,
+		GalleryChapter:    db.GalleryChapter,
+		Image:             db.Image,
+		Movie:             db.Movie,
+		Performer:         db.Performer,
+		Scene:             db.Scene,
+		SceneMarker:       db.SceneMarker,
+		SceneRelationship: db.SceneRelationship,
+		Studio:            db.Studio,
+		Tag:               db.Tag,
+		SavedFilter:       db.SavedFilter,
+		SelectionSet:      db.SelectionSet,
+		EditHistory:       db.EditHistory,
+		RatingCriterion:   db.RatingCriterion,
 	}
 }
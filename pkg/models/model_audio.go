@@ -0,0 +1,126 @@
+package models
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Audio stores the metadata for a single standalone audio file.
+type Audio struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Details string `json:"details"`
+	Date    *Date  `json:"date"`
+	// Rating expressed in 1-100 scale
+	Rating    *int `json:"rating"`
+	Organized bool `json:"organized"`
+	StudioID  *int `json:"studio_id"`
+
+	// transient - not persisted
+	Files         RelatedFiles
+	PrimaryFileID *FileID
+	// transient - path of primary file - empty if no files
+	Path string
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	TagIDs       RelatedIDs `json:"tag_ids"`
+	PerformerIDs RelatedIDs `json:"performer_ids"`
+}
+
+func NewAudio() Audio {
+	currentTime := time.Now()
+	return Audio{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// AudioPartial represents part of an Audio object. It is used to update
+// the database entry.
+type AudioPartial struct {
+	Title   OptionalString
+	Details OptionalString
+	Date    OptionalDate
+	// Rating expressed in 1-100 scale
+	Rating    OptionalInt
+	Organized OptionalBool
+	StudioID  OptionalInt
+	CreatedAt OptionalTime
+	UpdatedAt OptionalTime
+
+	TagIDs        *UpdateIDs
+	PerformerIDs  *UpdateIDs
+	PrimaryFileID *FileID
+}
+
+func NewAudioPartial() AudioPartial {
+	currentTime := time.Now()
+	return AudioPartial{
+		UpdatedAt: NewOptionalTime(currentTime),
+	}
+}
+
+func (a *Audio) LoadFiles(ctx context.Context, l FileLoader) error {
+	return a.Files.load(func() ([]File, error) {
+		return l.GetFiles(ctx, a.ID)
+	})
+}
+
+func (a *Audio) LoadPrimaryFile(ctx context.Context, l FileGetter) error {
+	return a.Files.loadPrimary(func() (File, error) {
+		if a.PrimaryFileID == nil {
+			return nil, nil
+		}
+
+		f, err := l.Find(ctx, *a.PrimaryFileID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(f) > 0 {
+			return f[0], nil
+		}
+
+		return nil, nil
+	})
+}
+
+func (a *Audio) LoadPerformerIDs(ctx context.Context, l PerformerIDLoader) error {
+	return a.PerformerIDs.load(func() ([]int, error) {
+		return l.GetPerformerIDs(ctx, a.ID)
+	})
+}
+
+func (a *Audio) LoadTagIDs(ctx context.Context, l TagIDLoader) error {
+	return a.TagIDs.load(func() ([]int, error) {
+		return l.GetTagIDs(ctx, a.ID)
+	})
+}
+
+// GetTitle returns the title of the audio. If the Title field is empty,
+// then the base filename is returned.
+func (a Audio) GetTitle() string {
+	if a.Title != "" {
+		return a.Title
+	}
+
+	if a.Path != "" {
+		return filepath.Base(a.Path)
+	}
+
+	return ""
+}
+
+// DisplayName returns a display name for the audio for logging purposes.
+// It returns Path if not empty, otherwise it returns the ID.
+func (a Audio) DisplayName() string {
+	if a.Path != "" {
+		return a.Path
+	}
+
+	return strconv.Itoa(a.ID)
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// EditHistory is a change to a field of an object.
+type EditHistory struct {
+	ID         int            `json:"id"`
+	ObjectType EditObjectType `json:"object_type"`
+	ObjectID   int            `json:"object_id"`
+	// Field is the database column of the field.
+	Field string `json:"field"`
+	// OldValue and NewValue are nil where the field was null.
+	OldValue   *string    `json:"old_value"`
+	NewValue   *string    `json:"new_value"`
+	Source     EditSource `json:"source"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevertedAt *time.Time `json:"reverted_at"`
+}
+
+type EditObjectType string
+
+const (
+	EditObjectTypeScene     EditObjectType = "SCENE"
+	EditObjectTypeImage     EditObjectType = "IMAGE"
+	EditObjectTypeGallery   EditObjectType = "GALLERY"
+	EditObjectTypePerformer EditObjectType = "PERFORMER"
+	EditObjectTypeStudio    EditObjectType = "STUDIO"
+	EditObjectTypeTag       EditObjectType = "TAG"
+	EditObjectTypeMovie     EditObjectType = "MOVIE"
+)
+
+var AllEditObjectType = []EditObjectType{
+	EditObjectTypeScene,
+	EditObjectTypeImage,
+	EditObjectTypeGallery,
+	EditObjectTypePerformer,
+	EditObjectTypeStudio,
+	EditObjectTypeTag,
+	EditObjectTypeMovie,
+}
+
+func (e EditObjectType) IsValid() bool {
+	switch e {
+	case EditObjectTypeScene, EditObjectTypeImage, EditObjectTypeGallery, EditObjectTypePerformer, EditObjectTypeStudio, EditObjectTypeTag, EditObjectTypeMovie:
+		return true
+	}
+	return false
+}
+
+func (e EditObjectType) String() string {
+	return string(e)
+}
+
+func (e *EditObjectType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EditObjectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EditObjectType", str)
+	}
+	return nil
+}
+
+func (e EditObjectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// EditSource is the origin of a change recorded in the edit history.
+type EditSource string
+
+const (
+	EditSourceUI      EditSource = "UI"
+	EditSourceScraper EditSource = "SCRAPER"
+	EditSourcePlugin  EditSource = "PLUGIN"
+)
+
+var AllEditSource = []EditSource{
+	EditSourceUI,
+	EditSourceScraper,
+	EditSourcePlugin,
+}
+
+func (e EditSource) IsValid() bool {
+	switch e {
+	case EditSourceUI, EditSourceScraper, EditSourcePlugin:
+		return true
+	}
+	return false
+}
+
+func (e EditSource) String() string {
+	return string(e)
+}
+
+func (e *EditSource) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EditSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EditSource", str)
+	}
+	return nil
+}
+
+func (e EditSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type editSourceKey struct{}
+
+// WithEditSource returns a context in which changes to objects are recorded
+// in the edit history with the given source. Changes are not recorded
+// otherwise.
+func WithEditSource(ctx context.Context, source EditSource) context.Context {
+	return context.WithValue(ctx, editSourceKey{}, source)
+}
+
+// EditSourceFromContext returns the source set with WithEditSource. Returns
+// false if changes in the context are not recorded.
+func EditSourceFromContext(ctx context.Context) (EditSource, bool) {
+	source, ok := ctx.Value(editSourceKey{}).(EditSource)
+	return source, ok
+}
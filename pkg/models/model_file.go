@@ -258,9 +258,18 @@ type VideoFile struct {
 	AudioCodec string  `json:"audio_codec"`
 	FrameRate  float64 `json:"frame_rate"`
 	BitRate    int64   `json:"bitrate"`
+	// ColorTransfer is the transfer characteristics of the video stream,
+	// as reported by ffprobe. Used to detect HDR video.
+	ColorTransfer string `json:"color_transfer"`
 
 	Interactive      bool `json:"interactive"`
 	InteractiveSpeed *int `json:"interactive_speed"`
+	// Statistics of the funscript, set when generating heatmaps and speeds.
+	// Speeds are in positions per second. Action density is the number of
+	// actions per minute of script.
+	InteractiveAverageSpeed  *int `json:"interactive_average_speed"`
+	InteractiveMaxSpeed      *int `json:"interactive_max_speed"`
+	InteractiveActionDensity *int `json:"interactive_action_density"`
 }
 
 func (f VideoFile) GetWidth() int {
@@ -293,3 +302,45 @@ func (f VideoFile) FrameRateFinite() float64 {
 	}
 	return ret
 }
+
+// AudioFile is an extension of BaseFile to represent audio files.
+type AudioFile struct {
+	*BaseFile
+	Format     string  `json:"format"`
+	Duration   float64 `json:"duration"`
+	AudioCodec string  `json:"audio_codec"`
+	BitRate    int64   `json:"bitrate"`
+}
+
+func (f AudioFile) DurationFinite() float64 {
+	ret := f.Duration
+	if math.IsInf(ret, 0) || math.IsNaN(ret) {
+		return 0
+	}
+	return ret
+}
+
+// DocumentFile is an extension of BaseFile to represent paged document files,
+// such as PDFs.
+type DocumentFile struct {
+	*BaseFile
+	Format    string `json:"format"`
+	PageCount int    `json:"page_count"`
+}
+
+// FileLink is an alternate path of a file, where the path is a hardlink or
+// symlink to the same file on disk.
+type FileLink struct {
+	FileID FileID `json:"file_id"`
+	Path   string `json:"path"`
+}
+
+// FileVerification is the result of the last integrity verification of a
+// file.
+type FileVerification struct {
+	FileID     FileID    `json:"file_id"`
+	VerifiedAt time.Time `json:"verified_at"`
+	// Error describes why the verification failed. Empty if the fingerprints
+	// of the file matched.
+	Error string `json:"error"`
+}
@@ -16,7 +16,11 @@ type Gallery struct {
 	// Rating expressed in 1-100 scale
 	Rating    *int `json:"rating"`
 	Organized bool `json:"organized"`
+	Favorite  bool `json:"favorite"`
 	StudioID  *int `json:"studio_id"`
+	ParentID  *int `json:"parent_id"`
+	// ContentRating is the content rating tier of the gallery. 0 is unrated.
+	ContentRating int `json:"content_rating"`
 
 	// transient - not persisted
 	Files RelatedFiles
@@ -55,9 +59,12 @@ type GalleryPartial struct {
 	Date    OptionalDate
 	Details OptionalString
 	// Rating expressed in 1-100 scale
-	Rating    OptionalInt
-	Organized OptionalBool
-	StudioID  OptionalInt
+	Rating        OptionalInt
+	Organized     OptionalBool
+	Favorite      OptionalBool
+	ContentRating OptionalInt
+	StudioID      OptionalInt
+	ParentID      OptionalInt
 	// FileModTime OptionalTime
 	CreatedAt OptionalTime
 	UpdatedAt OptionalTime
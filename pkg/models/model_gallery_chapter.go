@@ -11,6 +11,10 @@ type GalleryChapter struct {
 	GalleryID  int       `json:"gallery_id"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// EndImageIndex is the 1-based index of the last image of the chapter.
+	// Chapters without an end extend up to the start of the next chapter.
+	EndImageIndex *int `json:"end_image_index"`
 }
 
 func NewGalleryChapter() GalleryChapter {
@@ -29,6 +33,8 @@ type GalleryChapterPartial struct {
 	GalleryID  OptionalInt
 	CreatedAt  OptionalTime
 	UpdatedAt  OptionalTime
+
+	EndImageIndex OptionalInt
 }
 
 func NewGalleryChapterPartial() GalleryChapterPartial {
@@ -15,6 +15,7 @@ type Image struct {
 	// Rating expressed in 1-100 scale
 	Rating    *int           `json:"rating"`
 	Organized bool           `json:"organized"`
+	Favorite  bool           `json:"favorite"`
 	OCounter  int            `json:"o_counter"`
 	StudioID  *int           `json:"studio_id"`
 	URLs      RelatedStrings `json:"urls"`
@@ -51,6 +52,7 @@ type ImagePartial struct {
 	URLs      *UpdateStrings
 	Date      OptionalDate
 	Organized OptionalBool
+	Favorite  OptionalBool
 	OCounter  OptionalInt
 	StudioID  OptionalInt
 	CreatedAt OptionalTime
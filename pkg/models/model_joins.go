@@ -68,3 +68,9 @@ func MoviesScenesFromInput(input []SceneMovieInput) ([]MoviesScenes, error) {
 
 	return ret, nil
 }
+
+// MovieScene represents a scene within a movie, along with its index in the movie.
+type MovieScene struct {
+	SceneID    int  `json:"scene_id"`
+	SceneIndex *int `json:"scene_index"`
+}
@@ -13,6 +13,7 @@ type Movie struct {
 	// Rating expressed in 1-100 scale
 	Rating    *int      `json:"rating"`
 	StudioID  *int      `json:"studio_id"`
+	ParentID  *int      `json:"parent_id"`
 	Director  string    `json:"director"`
 	Synopsis  string    `json:"synopsis"`
 	URL       string    `json:"url"`
@@ -36,6 +37,7 @@ type MoviePartial struct {
 	// Rating expressed in 1-100 scale
 	Rating    OptionalInt
 	StudioID  OptionalInt
+	ParentID  OptionalInt
 	Director  OptionalString
 	Synopsis  OptionalString
 	URL       OptionalString
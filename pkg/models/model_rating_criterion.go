@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// RatingCriterion is a named dimension, such as video quality, that objects
+// can be rated on in addition to their overall rating.
+type RatingCriterion struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewRatingCriterion() RatingCriterion {
+	currentTime := time.Now()
+	return RatingCriterion{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// RatingCriterionPartial represents part of a RatingCriterion object.
+// It is used to update the database entry.
+type RatingCriterionPartial struct {
+	Name      OptionalString
+	CreatedAt OptionalTime
+	UpdatedAt OptionalTime
+}
+
+func NewRatingCriterionPartial() RatingCriterionPartial {
+	currentTime := time.Now()
+	return RatingCriterionPartial{
+		UpdatedAt: NewOptionalTime(currentTime),
+	}
+}
@@ -19,8 +19,11 @@ type Scene struct {
 	// Rating expressed in 1-100 scale
 	Rating    *int `json:"rating"`
 	Organized bool `json:"organized"`
+	Favorite  bool `json:"favorite"`
 	OCounter  int  `json:"o_counter"`
 	StudioID  *int `json:"studio_id"`
+	// ContentRating is the content rating tier of the scene. 0 is unrated.
+	ContentRating int `json:"content_rating"`
 
 	// transient - not persisted
 	Files         RelatedVideoFiles
@@ -40,6 +43,11 @@ type Scene struct {
 	PlayDuration float64    `json:"play_duration"`
 	PlayCount    int        `json:"play_count"`
 
+	// QuarantinedAt is the time that the scene was quarantined because its
+	// file was missing. Quarantined scenes are excluded from scene queries
+	// unless requested.
+	QuarantinedAt *time.Time `json:"quarantined_at"`
+
 	URLs         RelatedStrings  `json:"urls"`
 	GalleryIDs   RelatedIDs      `json:"gallery_ids"`
 	TagIDs       RelatedIDs      `json:"tag_ids"`
@@ -65,16 +73,19 @@ type ScenePartial struct {
 	Director OptionalString
 	Date     OptionalDate
 	// Rating expressed in 1-100 scale
-	Rating       OptionalInt
-	Organized    OptionalBool
-	OCounter     OptionalInt
-	StudioID     OptionalInt
-	CreatedAt    OptionalTime
-	UpdatedAt    OptionalTime
-	ResumeTime   OptionalFloat64
-	PlayDuration OptionalFloat64
-	PlayCount    OptionalInt
-	LastPlayedAt OptionalTime
+	Rating        OptionalInt
+	Organized     OptionalBool
+	Favorite      OptionalBool
+	ContentRating OptionalInt
+	OCounter      OptionalInt
+	StudioID      OptionalInt
+	CreatedAt     OptionalTime
+	UpdatedAt     OptionalTime
+	ResumeTime    OptionalFloat64
+	PlayDuration  OptionalFloat64
+	PlayCount     OptionalInt
+	LastPlayedAt  OptionalTime
+	QuarantinedAt OptionalTime
 
 	URLs          *UpdateStrings
 	GalleryIDs    *UpdateIDs
@@ -213,6 +224,7 @@ func (s ScenePartial) UpdateInput(id int) SceneUpdateInput {
 		Date:         dateStr,
 		Rating100:    s.Rating.Ptr(),
 		Organized:    s.Organized.Ptr(),
+		Favorite:     s.Favorite.Ptr(),
 		StudioID:     s.StudioID.StringPtr(),
 		GalleryIds:   s.GalleryIDs.IDStrings(),
 		PerformerIds: s.PerformerIDs.IDStrings(),
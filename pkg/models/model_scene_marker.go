@@ -1,17 +1,64 @@
 package models
 
 import (
+	"errors"
 	"time"
 )
 
+// ErrMarkerEndBeforeStart is returned when the end of a marker is not after
+// its start.
+var ErrMarkerEndBeforeStart = errors.New("marker end must be after its start")
+
+// ErrMarkerPreviewDuration and ErrMarkerPreviewWidth are returned when a
+// preview override of a marker is not positive.
+var (
+	ErrMarkerPreviewDuration = errors.New("marker preview duration must be positive")
+	ErrMarkerPreviewWidth    = errors.New("marker preview width must be positive")
+)
+
 type SceneMarker struct {
 	ID           int       `json:"id"`
 	Title        string    `json:"title"`
 	Seconds      float64   `json:"seconds"`
+	EndSeconds   *float64  `json:"end_seconds"`
 	PrimaryTagID int       `json:"primary_tag_id"`
 	SceneID      int       `json:"scene_id"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Preview generation overrides. Nil values use the options of the
+	// generate task.
+	PreviewFormat   *MarkerPreviewFormat `json:"preview_format"`
+	PreviewDuration *float64             `json:"preview_duration"`
+	PreviewWidth    *int                 `json:"preview_width"`
+}
+
+// Duration returns the length of the marker range in seconds, or nil if the
+// marker has no end.
+func (m SceneMarker) Duration() *float64 {
+	if m.EndSeconds == nil {
+		return nil
+	}
+
+	ret := *m.EndSeconds - m.Seconds
+	return &ret
+}
+
+// Validate returns ErrMarkerEndBeforeStart if the marker has an end that is
+// not after its start, or ErrMarkerPreviewDuration or ErrMarkerPreviewWidth if
+// a preview override is not positive.
+func (m SceneMarker) Validate() error {
+	if m.EndSeconds != nil && *m.EndSeconds <= m.Seconds {
+		return ErrMarkerEndBeforeStart
+	}
+	if m.PreviewDuration != nil && *m.PreviewDuration <= 0 {
+		return ErrMarkerPreviewDuration
+	}
+	if m.PreviewWidth != nil && *m.PreviewWidth <= 0 {
+		return ErrMarkerPreviewWidth
+	}
+
+	return nil
 }
 
 func NewSceneMarker() SceneMarker {
@@ -27,10 +74,15 @@ func NewSceneMarker() SceneMarker {
 type SceneMarkerPartial struct {
 	Title        OptionalString
 	Seconds      OptionalFloat64
+	EndSeconds   OptionalFloat64
 	PrimaryTagID OptionalInt
 	SceneID      OptionalInt
 	CreatedAt    OptionalTime
 	UpdatedAt    OptionalTime
+
+	PreviewFormat   OptionalString
+	PreviewDuration OptionalFloat64
+	PreviewWidth    OptionalInt
 }
 
 func NewSceneMarkerPartial() SceneMarkerPartial {
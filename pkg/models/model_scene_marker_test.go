@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneMarker_Validate(t *testing.T) {
+	floatPtr := func(v float64) *float64 {
+		return &v
+	}
+	intPtr := func(v int) *int {
+		return &v
+	}
+
+	tests := []struct {
+		name         string
+		m            SceneMarker
+		wantDuration *float64
+		wantErr      error
+	}{
+		{
+			"point",
+			SceneMarker{Seconds: 10},
+			nil,
+			nil,
+		},
+		{
+			"range",
+			SceneMarker{Seconds: 10, EndSeconds: floatPtr(25.5)},
+			floatPtr(15.5),
+			nil,
+		},
+		{
+			"empty range",
+			SceneMarker{Seconds: 10, EndSeconds: floatPtr(10)},
+			floatPtr(0),
+			ErrMarkerEndBeforeStart,
+		},
+		{
+			"end before start",
+			SceneMarker{Seconds: 10, EndSeconds: floatPtr(5)},
+			floatPtr(-5),
+			ErrMarkerEndBeforeStart,
+		},
+		{
+			"preview overrides",
+			SceneMarker{Seconds: 10, PreviewDuration: floatPtr(8), PreviewWidth: intPtr(320)},
+			nil,
+			nil,
+		},
+		{
+			"zero preview duration",
+			SceneMarker{Seconds: 10, PreviewDuration: floatPtr(0)},
+			nil,
+			ErrMarkerPreviewDuration,
+		},
+		{
+			"negative preview width",
+			SceneMarker{Seconds: 10, PreviewWidth: intPtr(-1)},
+			nil,
+			ErrMarkerPreviewWidth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.m.Validate())
+			assert.Equal(t, tt.wantDuration, tt.m.Duration())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// SceneRelationship links a scene to a related scene. The scene is a
+// Type of the related scene, for example a remaster or sequel of it.
+type SceneRelationship struct {
+	ID             int                   `json:"id"`
+	SceneID        int                   `json:"scene_id"`
+	RelatedSceneID int                   `json:"related_scene_id"`
+	Type           SceneRelationshipType `json:"type"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+func NewSceneRelationship() SceneRelationship {
+	currentTime := time.Now()
+	return SceneRelationship{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// OtherSceneID returns the id of the scene at the other end of the
+// relationship from the scene with the given id.
+func (r SceneRelationship) OtherSceneID(sceneID int) int {
+	if r.SceneID == sceneID {
+		return r.RelatedSceneID
+	}
+	return r.SceneID
+}
+
+// SceneRelationshipPartial represents part of a SceneRelationship object.
+// It is used to update the database entry.
+type SceneRelationshipPartial struct {
+	SceneID        OptionalInt
+	RelatedSceneID OptionalInt
+	Type           OptionalString
+	CreatedAt      OptionalTime
+	UpdatedAt      OptionalTime
+}
+
+func NewSceneRelationshipPartial() SceneRelationshipPartial {
+	currentTime := time.Now()
+	return SceneRelationshipPartial{
+		UpdatedAt: NewOptionalTime(currentTime),
+	}
+}
+
+type SceneRelationshipType string
+
+const (
+	// The scene is a remaster of the related scene
+	SceneRelationshipTypeRemaster SceneRelationshipType = "REMASTER"
+	// The scene is a re-encode of the related scene
+	SceneRelationshipTypeReEncode SceneRelationshipType = "RE_ENCODE"
+	// The scene is a director's cut of the related scene
+	SceneRelationshipTypeDirectorsCut SceneRelationshipType = "DIRECTORS_CUT"
+	// The scene is a duplicate of the related scene
+	SceneRelationshipTypeDuplicate SceneRelationshipType = "DUPLICATE"
+	// The scene is a sequel of the related scene
+	SceneRelationshipTypeSequel SceneRelationshipType = "SEQUEL"
+	// The scene is related to the related scene in some other way
+	SceneRelationshipTypeOther SceneRelationshipType = "OTHER"
+)
+
+var AllSceneRelationshipType = []SceneRelationshipType{
+	SceneRelationshipTypeRemaster,
+	SceneRelationshipTypeReEncode,
+	SceneRelationshipTypeDirectorsCut,
+	SceneRelationshipTypeDuplicate,
+	SceneRelationshipTypeSequel,
+	SceneRelationshipTypeOther,
+}
+
+func (e SceneRelationshipType) IsValid() bool {
+	switch e {
+	case SceneRelationshipTypeRemaster, SceneRelationshipTypeReEncode, SceneRelationshipTypeDirectorsCut, SceneRelationshipTypeDuplicate, SceneRelationshipTypeSequel, SceneRelationshipTypeOther:
+		return true
+	}
+	return false
+}
+
+func (e SceneRelationshipType) String() string {
+	return string(e)
+}
+
+func (e *SceneRelationshipType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SceneRelationshipType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SceneRelationshipType", str)
+	}
+	return nil
+}
+
+func (e SceneRelationshipType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// SelectionSet is a persistent, named list of object IDs. The type of the
+// objects is determined by Mode.
+type SelectionSet struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Mode      FilterMode `json:"mode"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func NewSelectionSet() SelectionSet {
+	currentTime := time.Now()
+	return SelectionSet{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
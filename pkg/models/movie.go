@@ -1,5 +1,11 @@
 package models
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type MovieFilterType struct {
 	Name     *StringCriterionInput `json:"name"`
 	Director *StringCriterionInput `json:"director"`
@@ -16,10 +22,59 @@ type MovieFilterType struct {
 	URL *StringCriterionInput `json:"url"`
 	// Filter to only include movies where performer appears in a scene
 	Performers *MultiCriterionInput `json:"performers"`
+	// Filter to only include movies with these parent movies
+	Parents *HierarchicalMultiCriterionInput `json:"parents"`
+	// Filter by number of child movies
+	ChildCount *IntCriterionInput `json:"child_count"`
 	// Filter by date
 	Date *DateCriterionInput `json:"date"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields. All criteria must match
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
+}
+
+type MovieSceneOrderEnum string
+
+const (
+	// Order scenes by date, then title
+	MovieSceneOrderEnumDate MovieSceneOrderEnum = "DATE"
+	// Order scenes by the basename of their primary file
+	MovieSceneOrderEnumFilename MovieSceneOrderEnum = "FILENAME"
+)
+
+var AllMovieSceneOrderEnum = []MovieSceneOrderEnum{
+	MovieSceneOrderEnumDate,
+	MovieSceneOrderEnumFilename,
+}
+
+func (e MovieSceneOrderEnum) IsValid() bool {
+	switch e {
+	case MovieSceneOrderEnumDate, MovieSceneOrderEnumFilename:
+		return true
+	}
+	return false
+}
+
+func (e MovieSceneOrderEnum) String() string {
+	return string(e)
+}
+
+func (e *MovieSceneOrderEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MovieSceneOrderEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MovieSceneOrderEnum", str)
+	}
+	return nil
+}
+
+func (e MovieSceneOrderEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
@@ -21,6 +21,9 @@ type generatedPaths struct {
 	Downloads          string
 	Tmp                string
 	InteractiveHeatmap string
+	Captions           string
+	Frames             string
+	Waveforms          string
 }
 
 func newGeneratedPaths(path string) *generatedPaths {
@@ -33,6 +36,9 @@ func newGeneratedPaths(path string) *generatedPaths {
 	gp.Downloads = filepath.Join(path, "download_stage")
 	gp.Tmp = filepath.Join(path, "tmp")
 	gp.InteractiveHeatmap = filepath.Join(path, "interactive_heatmaps")
+	gp.Captions = filepath.Join(path, "captions")
+	gp.Frames = filepath.Join(path, "frames")
+	gp.Waveforms = filepath.Join(path, "waveforms")
 	return &gp
 }
 
@@ -79,7 +85,21 @@ func (gp *generatedPaths) GetThumbnailPath(checksum string, width int) string {
 	return filepath.Join(gp.Thumbnails, fsutil.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
 }
 
+// GetDocumentPagePath returns the path of a generated page image of a
+// document file. Pages are numbered from 1.
+func (gp *generatedPaths) GetDocumentPagePath(checksum string, page int, width int) string {
+	fname := fmt.Sprintf("%s_p%d_%d.jpg", checksum, page, width)
+	return filepath.Join(gp.Thumbnails, fsutil.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
+}
+
 func (gp *generatedPaths) GetClipPreviewPath(checksum string, width int) string {
 	fname := fmt.Sprintf("%s_%d.webm", checksum, width)
 	return filepath.Join(gp.Thumbnails, fsutil.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
 }
+
+// GetClipTranscodePath returns the path of the full size video transcode of
+// an animated image.
+func (gp *generatedPaths) GetClipTranscodePath(checksum string) string {
+	fname := checksum + "_clip.mp4"
+	return filepath.Join(gp.Thumbnails, fsutil.GetIntraDir(checksum, thumbDirDepth, thumbDirLength), fname)
+}
@@ -1,6 +1,8 @@
 package paths
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 
 	"github.com/stashapp/stash/pkg/fsutil"
@@ -20,6 +22,11 @@ type JSONPaths struct {
 	Tags       string
 	Movies     string
 	Files      string
+	Chapters   string
+
+	// ObjectImages contains the images of exported objects, if they are not
+	// embedded in the object JSON
+	ObjectImages string
 }
 
 func newJSONPaths(baseDir string) *JSONPaths {
@@ -34,6 +41,8 @@ func newJSONPaths(baseDir string) *JSONPaths {
 	jp.Movies = filepath.Join(baseDir, "movies")
 	jp.Tags = filepath.Join(baseDir, "tags")
 	jp.Files = filepath.Join(baseDir, "files")
+	jp.Chapters = filepath.Join(baseDir, "chapters")
+	jp.ObjectImages = filepath.Join(baseDir, "object_images")
 	return &jp
 }
 
@@ -42,8 +51,37 @@ func GetJSONPaths(baseDir string) *JSONPaths {
 	return jp
 }
 
+// NDJSONPath returns the path of the NDJSON file containing the objects that
+// are otherwise exported to individual files in dir.
+func NDJSONPath(dir string) string {
+	return dir + ".ndjson"
+}
+
+// ObjectDirs returns the directories containing the exported objects.
+func (jp *JSONPaths) ObjectDirs() []string {
+	return []string{
+		jp.Scenes,
+		jp.Images,
+		jp.Galleries,
+		jp.Performers,
+		jp.Studios,
+		jp.Movies,
+		jp.Tags,
+		jp.Files,
+	}
+}
+
 func EmptyJSONDirs(baseDir string) {
 	jsonPaths := GetJSONPaths(baseDir)
+
+	// remove NDJSON files so that they do not take precedence over the
+	// exported directories
+	for _, dir := range jsonPaths.ObjectDirs() {
+		if err := os.Remove(NDJSONPath(dir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("couldn't remove %s: %v", NDJSONPath(dir), err)
+		}
+	}
+
 	_ = fsutil.EmptyDir(jsonPaths.Scenes)
 	_ = fsutil.EmptyDir(jsonPaths.Images)
 	_ = fsutil.EmptyDir(jsonPaths.Galleries)
@@ -52,6 +90,10 @@ func EmptyJSONDirs(baseDir string) {
 	_ = fsutil.EmptyDir(jsonPaths.Movies)
 	_ = fsutil.EmptyDir(jsonPaths.Tags)
 	_ = fsutil.EmptyDir(jsonPaths.Files)
+	_ = fsutil.EmptyDir(jsonPaths.Chapters)
+	if err := os.RemoveAll(jsonPaths.ObjectImages); err != nil {
+		logger.Warnf("couldn't remove %s: %v", jsonPaths.ObjectImages, err)
+	}
 }
 
 func EnsureJSONDirs(baseDir string) {
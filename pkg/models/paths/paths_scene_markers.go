@@ -31,3 +31,17 @@ func (sp *sceneMarkerPaths) GetWebpPreviewPath(checksum string, seconds int) str
 func (sp *sceneMarkerPaths) GetScreenshotPath(checksum string, seconds int) string {
 	return filepath.Join(sp.GetFolderPath(checksum), strconv.Itoa(seconds)+".jpg")
 }
+
+// GetWebpFormatPreviewPath returns the path of the preview of a marker with
+// the WEBP preview format. It is separate from the animated image preview,
+// which is always generated with the default duration.
+func (sp *sceneMarkerPaths) GetWebpFormatPreviewPath(checksum string, seconds int) string {
+	return filepath.Join(sp.GetFolderPath(checksum), strconv.Itoa(seconds)+"_preview.webp")
+}
+
+// GetStaticFormatPreviewPath returns the path of the preview of a marker with
+// the STATIC preview format. It is separate from the marker screenshot, which
+// is always generated at the width of the video.
+func (sp *sceneMarkerPaths) GetStaticFormatPreviewPath(checksum string, seconds int) string {
+	return filepath.Join(sp.GetFolderPath(checksum), strconv.Itoa(seconds)+"_preview.jpg")
+}
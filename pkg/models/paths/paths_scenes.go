@@ -2,8 +2,10 @@ package paths
 
 import (
 	"path/filepath"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 type scenePaths struct {
@@ -42,6 +44,22 @@ func (sp *scenePaths) GetWebpPreviewPath(checksum string) string {
 	return filepath.Join(sp.Screenshots, checksum+".webp")
 }
 
+func (sp *scenePaths) GetAvifPreviewPath(checksum string) string {
+	return filepath.Join(sp.Screenshots, checksum+".avif")
+}
+
+// GetPreviewImagePaths returns the possible paths of the animated image
+// preview, in order of preference for format.
+func (sp *scenePaths) GetPreviewImagePaths(checksum string, format models.PreviewImageFormat) []string {
+	webp := sp.GetWebpPreviewPath(checksum)
+	avif := sp.GetAvifPreviewPath(checksum)
+
+	if format == models.PreviewImageFormatAvif {
+		return []string{avif, webp}
+	}
+	return []string{webp, avif}
+}
+
 func (sp *scenePaths) GetSpriteImageFilePath(checksum string) string {
 	return filepath.Join(sp.Vtt, checksum+"_sprite.jpg")
 }
@@ -53,3 +71,46 @@ func (sp *scenePaths) GetSpriteVttFilePath(checksum string) string {
 func (sp *scenePaths) GetInteractiveHeatmapPath(checksum string) string {
 	return filepath.Join(sp.InteractiveHeatmap, checksum+".png")
 }
+
+// GetWaveformPath returns the path of the image of the audio waveform of the
+// scene.
+func (sp *scenePaths) GetWaveformPath(checksum string) string {
+	return filepath.Join(sp.Waveforms, checksum+".png")
+}
+
+// GetCaptionsFolder returns the folder containing the WebVTT conversions of
+// the captions of the scene.
+func (sp *scenePaths) GetCaptionsFolder(checksum string) string {
+	return filepath.Join(sp.Captions, checksum)
+}
+
+// GetEmbeddedCaptionPath returns the path of the WebVTT conversion of the
+// embedded subtitle track with the given index.
+func (sp *scenePaths) GetEmbeddedCaptionPath(checksum string, track int) string {
+	return filepath.Join(sp.GetCaptionsFolder(checksum), strconv.Itoa(track)+".vtt")
+}
+
+// GetEmbeddedCaptionsListPath returns the path of the cached list of
+// embedded subtitle tracks.
+func (sp *scenePaths) GetEmbeddedCaptionsListPath(checksum string) string {
+	return filepath.Join(sp.GetCaptionsFolder(checksum), "embedded.json")
+}
+
+// GetCaptionPath returns the path of the WebVTT conversion of the caption
+// file with the given language and type.
+func (sp *scenePaths) GetCaptionPath(checksum string, lang string, captionType string) string {
+	return filepath.Join(sp.GetCaptionsFolder(checksum), lang+"."+captionType+".vtt")
+}
+
+// GetFramesFolder returns the folder containing the cached frames extracted
+// from the scene.
+func (sp *scenePaths) GetFramesFolder(checksum string) string {
+	return filepath.Join(sp.Frames, checksum)
+}
+
+// GetFramePath returns the path of the cached frame extracted at the given
+// millisecond offset, scaled to width. A width of 0 is the source width.
+func (sp *scenePaths) GetFramePath(checksum string, ms int64, width int) string {
+	fname := strconv.FormatInt(ms, 10) + "_" + strconv.Itoa(width) + ".jpg"
+	return filepath.Join(sp.GetFramesFolder(checksum), fname)
+}
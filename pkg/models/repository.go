@@ -14,18 +14,23 @@ type TxnManager interface {
 type Repository struct {
 	TxnManager TxnManager
 
-	File           FileReaderWriter
-	Folder         FolderReaderWriter
-	Gallery        GalleryReaderWriter
-	GalleryChapter GalleryChapterReaderWriter
-	Image          ImageReaderWriter
-	Movie          MovieReaderWriter
-	Performer      PerformerReaderWriter
-	Scene          SceneReaderWriter
-	SceneMarker    SceneMarkerReaderWriter
-	Studio         StudioReaderWriter
-	Tag            TagReaderWriter
-	SavedFilter    SavedFilterReaderWriter
+	Audio             AudioReaderWriter
+	File              FileReaderWriter
+	Folder            FolderReaderWriter
+	Gallery           GalleryReaderWriter
+	GalleryChapter    GalleryChapterReaderWriter
+	Image             ImageReaderWriter
+	Movie             MovieReaderWriter
+	Performer         PerformerReaderWriter
+	Scene             SceneReaderWriter
+	SceneMarker       SceneMarkerReaderWriter
+	SceneRelationship SceneRelationshipReaderWriter
+	Studio            StudioReaderWriter
+	Tag               TagReaderWriter
+	SavedFilter       SavedFilterReaderWriter
+	SelectionSet      SelectionSetReaderWriter
+	EditHistory       EditHistoryReaderWriter
+	RatingCriterion   RatingCriterionReaderWriter
 }
 
 func (r *Repository) WithTxn(ctx context.Context, fn txn.TxnFunc) error {
@@ -0,0 +1,79 @@
+package models
+
+import "context"
+
+// AudioGetter provides methods to get audio files by ID.
+type AudioGetter interface {
+	FindMany(ctx context.Context, ids []int) ([]*Audio, error)
+	Find(ctx context.Context, id int) (*Audio, error)
+}
+
+// AudioFinder provides methods to find audio files.
+type AudioFinder interface {
+	AudioGetter
+	FindByFingerprints(ctx context.Context, fp []Fingerprint) ([]*Audio, error)
+	FindByFileID(ctx context.Context, fileID FileID) ([]*Audio, error)
+}
+
+// AudioQueryer provides methods to query audio files.
+type AudioQueryer interface {
+	Query(ctx context.Context, options AudioQueryOptions) (*AudioQueryResult, error)
+	QueryCount(ctx context.Context, audioFilter *AudioFilterType, findFilter *FindFilterType) (int, error)
+}
+
+// AudioCounter provides methods to count audio files.
+type AudioCounter interface {
+	Count(ctx context.Context) (int, error)
+	CountByFileID(ctx context.Context, fileID FileID) (int, error)
+}
+
+// AudioCreator provides methods to create audio files.
+type AudioCreator interface {
+	Create(ctx context.Context, newAudio *Audio, fileIDs []FileID) error
+}
+
+// AudioUpdater provides methods to update audio files.
+type AudioUpdater interface {
+	UpdatePartial(ctx context.Context, id int, partial AudioPartial) (*Audio, error)
+}
+
+// AudioDestroyer provides methods to destroy audio files.
+type AudioDestroyer interface {
+	Destroy(ctx context.Context, id int) error
+}
+
+type AudioCreatorUpdater interface {
+	AudioCreator
+	AudioUpdater
+}
+
+// AudioReader provides all methods to read audio files.
+type AudioReader interface {
+	AudioFinder
+	AudioQueryer
+	AudioCounter
+
+	FileIDLoader
+	PerformerIDLoader
+	TagIDLoader
+	FileLoader
+
+	All(ctx context.Context) ([]*Audio, error)
+	Size(ctx context.Context) (float64, error)
+	Duration(ctx context.Context) (float64, error)
+}
+
+// AudioWriter provides all methods to modify audio files.
+type AudioWriter interface {
+	AudioCreator
+	AudioUpdater
+	AudioDestroyer
+
+	AddFileID(ctx context.Context, id int, fileID FileID) error
+}
+
+// AudioReaderWriter provides all audio methods.
+type AudioReaderWriter interface {
+	AudioReader
+	AudioWriter
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"io/fs"
+	"time"
 )
 
 // FileGetter provides methods to get files by ID.
@@ -19,6 +20,7 @@ type FileFinder interface {
 	FindByFingerprint(ctx context.Context, fp Fingerprint) ([]File, error)
 	FindByZipFileID(ctx context.Context, zipFileID FileID) ([]File, error)
 	FindByFileInfo(ctx context.Context, info fs.FileInfo, size int64) ([]File, error)
+	FindByLink(ctx context.Context, path string) (File, error)
 }
 
 // FileQueryer provides methods to query files.
@@ -30,6 +32,9 @@ type FileQueryer interface {
 type FileCounter interface {
 	CountAllInPaths(ctx context.Context, p []string) (int, error)
 	CountByFolderID(ctx context.Context, folderID FolderID) (int, error)
+	// Size returns the total size of the files that are not within zip
+	// files.
+	Size(ctx context.Context) (float64, error)
 }
 
 // FileCreator provides methods to create files.
@@ -70,6 +75,11 @@ type FileReader interface {
 
 	GetCaptions(ctx context.Context, fileID FileID) ([]*VideoCaption, error)
 	IsPrimary(ctx context.Context, fileID FileID) (bool, error)
+	GetLinks(ctx context.Context, fileID FileID) ([]string, error)
+	FindLinksInPaths(ctx context.Context, p []string) ([]FileLink, error)
+	FindForVerification(ctx context.Context, p []string, before time.Time, limit int) ([]File, error)
+	CountForVerification(ctx context.Context, p []string) (int, error)
+	FindFailedVerifications(ctx context.Context) ([]FileVerification, error)
 }
 
 // FileWriter provides all methods to modify files.
@@ -79,6 +89,9 @@ type FileWriter interface {
 	FileDestroyer
 
 	UpdateCaptions(ctx context.Context, fileID FileID, captions []*VideoCaption) error
+	AddLink(ctx context.Context, fileID FileID, path string) error
+	DestroyLink(ctx context.Context, path string) error
+	UpdateVerification(ctx context.Context, v FileVerification) error
 }
 
 // FileReaderWriter provides all file methods.
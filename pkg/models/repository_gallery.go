@@ -20,6 +20,7 @@ type GalleryFinder interface {
 	FindByFolderID(ctx context.Context, folderID FolderID) ([]*Gallery, error)
 	FindBySceneID(ctx context.Context, sceneID int) ([]*Gallery, error)
 	FindByImageID(ctx context.Context, imageID int) ([]*Gallery, error)
+	FindChildren(ctx context.Context, id int) ([]*Gallery, error)
 	FindUserGalleryByTitle(ctx context.Context, title string) ([]*Gallery, error)
 }
 
@@ -62,6 +63,7 @@ type GalleryReader interface {
 	GalleryFinder
 	GalleryQueryer
 	GalleryCounter
+	CustomFieldsReader
 
 	URLLoader
 	FileIDLoader
@@ -79,6 +81,7 @@ type GalleryWriter interface {
 	GalleryCreator
 	GalleryUpdater
 	GalleryDestroyer
+	CustomFieldsWriter
 
 	AddFileID(ctx context.Context, id int, fileID FileID) error
 	AddImages(ctx context.Context, galleryID int, imageIDs ...int) error
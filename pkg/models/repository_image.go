@@ -18,6 +18,8 @@ type ImageFinder interface {
 	FindByFolderID(ctx context.Context, fileID FolderID) ([]*Image, error)
 	FindByZipFileID(ctx context.Context, zipFileID FileID) ([]*Image, error)
 	FindByGalleryID(ctx context.Context, galleryID int) ([]*Image, error)
+	FindSimilarByColor(ctx context.Context, imageID int, limit int) ([]*Image, error)
+	FindDuplicates(ctx context.Context, distance int) ([][]*Image, error)
 }
 
 // ImageQueryer provides methods to query images.
@@ -62,6 +64,8 @@ type ImageReader interface {
 	ImageFinder
 	ImageQueryer
 	ImageCounter
+	CustomFieldsReader
+	HistoryReader
 
 	URLLoader
 	FileIDLoader
@@ -72,6 +76,7 @@ type ImageReader interface {
 
 	All(ctx context.Context) ([]*Image, error)
 	Size(ctx context.Context) (float64, error)
+	GetColorPalette(ctx context.Context, imageID int) ([]Color, error)
 }
 
 // ImageWriter provides all methods to modify images.
@@ -79,11 +84,14 @@ type ImageWriter interface {
 	ImageCreator
 	ImageUpdater
 	ImageDestroyer
+	CustomFieldsWriter
+	HistoryWriter
 
 	AddFileID(ctx context.Context, id int, fileID FileID) error
 	IncrementOCounter(ctx context.Context, id int) (int, error)
 	DecrementOCounter(ctx context.Context, id int) (int, error)
 	ResetOCounter(ctx context.Context, id int) (int, error)
+	UpdateColorPalette(ctx context.Context, imageID int, palette []Color) error
 }
 
 // ImageReaderWriter provides all image methods.
@@ -14,6 +14,7 @@ type MovieFinder interface {
 	MovieGetter
 	FindByPerformerID(ctx context.Context, performerID int) ([]*Movie, error)
 	FindByStudioID(ctx context.Context, studioID int) ([]*Movie, error)
+	FindChildren(ctx context.Context, id int) ([]*Movie, error)
 	FindByName(ctx context.Context, name string, nocase bool) (*Movie, error)
 	FindByNames(ctx context.Context, names []string, nocase bool) ([]*Movie, error)
 }
@@ -44,6 +45,12 @@ type MovieUpdater interface {
 	UpdateBackImage(ctx context.Context, movieID int, backImage []byte) error
 }
 
+// MovieSceneIndexer provides methods to get and set the index of scenes within a movie.
+type MovieSceneIndexer interface {
+	GetSceneIndexes(ctx context.Context, movieID int) ([]MovieScene, error)
+	UpdateSceneIndexes(ctx context.Context, movieID int, indexes []MovieScene) error
+}
+
 // MovieDestroyer provides methods to destroy movies.
 type MovieDestroyer interface {
 	Destroy(ctx context.Context, id int) error
@@ -64,6 +71,7 @@ type MovieReader interface {
 	MovieFinder
 	MovieQueryer
 	MovieCounter
+	CustomFieldsReader
 
 	All(ctx context.Context) ([]*Movie, error)
 	GetFrontImage(ctx context.Context, movieID int) ([]byte, error)
@@ -77,10 +85,12 @@ type MovieWriter interface {
 	MovieCreator
 	MovieUpdater
 	MovieDestroyer
+	CustomFieldsWriter
 }
 
 // MovieReaderWriter provides all movie methods.
 type MovieReaderWriter interface {
 	MovieReader
 	MovieWriter
+	MovieSceneIndexer
 }
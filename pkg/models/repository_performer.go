@@ -18,6 +18,7 @@ type PerformerFinder interface {
 	FindByStashID(ctx context.Context, stashID StashID) ([]*Performer, error)
 	FindByStashIDStatus(ctx context.Context, hasStashID bool, stashboxEndpoint string) ([]*Performer, error)
 	FindByNames(ctx context.Context, names []string, nocase bool) ([]*Performer, error)
+	FindByMergedID(ctx context.Context, mergedID int) (*Performer, error)
 }
 
 // PerformerQueryer provides methods to query performers.
@@ -74,6 +75,7 @@ type PerformerReader interface {
 	PerformerQueryer
 	PerformerAutoTagQueryer
 	PerformerCounter
+	CustomFieldsReader
 
 	AliasLoader
 	StashIDLoader
@@ -82,6 +84,7 @@ type PerformerReader interface {
 	All(ctx context.Context) ([]*Performer, error)
 	GetImage(ctx context.Context, performerID int) ([]byte, error)
 	HasImage(ctx context.Context, performerID int) (bool, error)
+	GetMergedIDs(ctx context.Context, performerID int) ([]int, error)
 }
 
 // PerformerWriter provides all methods to modify performers.
@@ -89,6 +92,9 @@ type PerformerWriter interface {
 	PerformerCreator
 	PerformerUpdater
 	PerformerDestroyer
+	CustomFieldsWriter
+
+	Merge(ctx context.Context, source []int, destination int) error
 }
 
 // PerformerReaderWriter provides all performer methods.
@@ -0,0 +1,50 @@
+package models
+
+import "context"
+
+// RatingCriterionGetter provides methods to get rating criteria by ID.
+type RatingCriterionGetter interface {
+	FindMany(ctx context.Context, ids []int) ([]*RatingCriterion, error)
+	Find(ctx context.Context, id int) (*RatingCriterion, error)
+}
+
+// RatingCriterionFinder provides methods to find rating criteria.
+type RatingCriterionFinder interface {
+	RatingCriterionGetter
+	FindByName(ctx context.Context, name string) (*RatingCriterion, error)
+}
+
+// RatingCriterionCreator provides methods to create rating criteria.
+type RatingCriterionCreator interface {
+	Create(ctx context.Context, newRatingCriterion *RatingCriterion) error
+}
+
+// RatingCriterionUpdater provides methods to update rating criteria.
+type RatingCriterionUpdater interface {
+	UpdatePartial(ctx context.Context, id int, updatedRatingCriterion RatingCriterionPartial) (*RatingCriterion, error)
+}
+
+// RatingCriterionDestroyer provides methods to destroy rating criteria.
+type RatingCriterionDestroyer interface {
+	Destroy(ctx context.Context, id int) error
+}
+
+// RatingCriterionReader provides all methods to read rating criteria.
+type RatingCriterionReader interface {
+	RatingCriterionFinder
+
+	All(ctx context.Context) ([]*RatingCriterion, error)
+}
+
+// RatingCriterionWriter provides all methods to modify rating criteria.
+type RatingCriterionWriter interface {
+	RatingCriterionCreator
+	RatingCriterionUpdater
+	RatingCriterionDestroyer
+}
+
+// RatingCriterionReaderWriter provides all rating criterion methods.
+type RatingCriterionReaderWriter interface {
+	RatingCriterionReader
+	RatingCriterionWriter
+}
@@ -22,6 +22,7 @@ type SceneFinder interface {
 	FindByGalleryID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByMovieID(ctx context.Context, movieID int) ([]*Scene, error)
 	FindDuplicates(ctx context.Context, distance int, durationDiff float64) ([][]*Scene, error)
+	FindSimilarByColor(ctx context.Context, sceneID int, limit int) ([]*Scene, error)
 }
 
 // SceneQueryer provides methods to query scenes.
@@ -73,6 +74,9 @@ type SceneReader interface {
 	SceneFinder
 	SceneQueryer
 	SceneCounter
+	CustomFieldsReader
+	CriteriaRatingsReader
+	HistoryReader
 
 	URLLoader
 	FileIDLoader
@@ -90,6 +94,10 @@ type SceneReader interface {
 	PlayDuration(ctx context.Context) (float64, error)
 	GetCover(ctx context.Context, sceneID int) ([]byte, error)
 	HasCover(ctx context.Context, sceneID int) (bool, error)
+	GetColorPalette(ctx context.Context, sceneID int) ([]Color, error)
+	GetPartFileIDs(ctx context.Context, sceneID int) ([]FileID, error)
+	GetPreviewOptions(ctx context.Context, sceneID int) (*ScenePreviewOptions, error)
+	GetGenerateState(ctx context.Context, sceneID int, optionsHash string) (string, error)
 }
 
 // SceneWriter provides all methods to modify scenes.
@@ -97,15 +105,22 @@ type SceneWriter interface {
 	SceneCreator
 	SceneUpdater
 	SceneDestroyer
+	CustomFieldsWriter
+	CriteriaRatingsWriter
+	HistoryWriter
 
 	AddFileID(ctx context.Context, id int, fileID FileID) error
 	AddGalleryIDs(ctx context.Context, sceneID int, galleryIDs []int) error
 	AssignFiles(ctx context.Context, sceneID int, fileID []FileID) error
+	SetFileParts(ctx context.Context, sceneID int, fileIDs []FileID) error
+	SetPreviewOptions(ctx context.Context, sceneID int, options ScenePreviewOptions) error
+	SetGenerateState(ctx context.Context, sceneID int, optionsHash string, stateHash string) error
 	IncrementOCounter(ctx context.Context, id int) (int, error)
 	DecrementOCounter(ctx context.Context, id int) (int, error)
 	ResetOCounter(ctx context.Context, id int) (int, error)
 	SaveActivity(ctx context.Context, sceneID int, resumeTime *float64, playDuration *float64) (bool, error)
 	IncrementWatchCount(ctx context.Context, sceneID int) (int, error)
+	UpdateColorPalette(ctx context.Context, sceneID int, palette []Color) error
 }
 
 // SceneReaderWriter provides all scene methods.
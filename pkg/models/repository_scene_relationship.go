@@ -0,0 +1,50 @@
+package models
+
+import "context"
+
+// SceneRelationshipGetter provides methods to get scene relationships by ID.
+type SceneRelationshipGetter interface {
+	FindMany(ctx context.Context, ids []int) ([]*SceneRelationship, error)
+	Find(ctx context.Context, id int) (*SceneRelationship, error)
+}
+
+// SceneRelationshipFinder provides methods to find scene relationships.
+type SceneRelationshipFinder interface {
+	SceneRelationshipGetter
+	// FindBySceneID returns the relationships where the scene is either the
+	// scene or the related scene.
+	FindBySceneID(ctx context.Context, sceneID int) ([]*SceneRelationship, error)
+}
+
+// SceneRelationshipCreator provides methods to create scene relationships.
+type SceneRelationshipCreator interface {
+	Create(ctx context.Context, newSceneRelationship *SceneRelationship) error
+}
+
+// SceneRelationshipUpdater provides methods to update scene relationships.
+type SceneRelationshipUpdater interface {
+	UpdatePartial(ctx context.Context, id int, updatedSceneRelationship SceneRelationshipPartial) (*SceneRelationship, error)
+}
+
+// SceneRelationshipDestroyer provides methods to destroy scene relationships.
+type SceneRelationshipDestroyer interface {
+	Destroy(ctx context.Context, id int) error
+}
+
+// SceneRelationshipReader provides all methods to read scene relationships.
+type SceneRelationshipReader interface {
+	SceneRelationshipFinder
+}
+
+// SceneRelationshipWriter provides all methods to modify scene relationships.
+type SceneRelationshipWriter interface {
+	SceneRelationshipCreator
+	SceneRelationshipUpdater
+	SceneRelationshipDestroyer
+}
+
+// SceneRelationshipReaderWriter provides all scene relationship methods.
+type SceneRelationshipReaderWriter interface {
+	SceneRelationshipReader
+	SceneRelationshipWriter
+}
@@ -82,6 +82,7 @@ type TagReader interface {
 	TagQueryer
 	TagAutoTagQueryer
 	TagCounter
+	CustomFieldsReader
 
 	AliasLoader
 
@@ -95,6 +96,7 @@ type TagWriter interface {
 	TagCreator
 	TagUpdater
 	TagDestroyer
+	CustomFieldsWriter
 
 	Merge(ctx context.Context, source []int, destination int) error
 }
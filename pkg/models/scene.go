@@ -27,12 +27,20 @@ type SceneFilterType struct {
 	PhashDistance *PhashDistanceCriterionInput `json:"phash_distance"`
 	// Filter by path
 	Path *StringCriterionInput `json:"path"`
+	// Filter by library
+	Library *LibraryCriterionInput `json:"library"`
 	// Filter by file count
 	FileCount *IntCriterionInput `json:"file_count"`
 	// Filter by rating expressed as 1-100
 	Rating100 *IntCriterionInput `json:"rating100"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by favorite
+	Favorite *bool `json:"favorite"`
+	// Filter by quarantined. Quarantined scenes are excluded if not set.
+	Quarantined *bool `json:"quarantined"`
+	// Filter by content rating tier
+	ContentRating *IntCriterionInput `json:"content_rating"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
 	// Filter Scenes that have an exact phash match available
@@ -53,8 +61,8 @@ type SceneFilterType struct {
 	IsMissing *string `json:"is_missing"`
 	// Filter to only include scenes with this studio
 	Studios *HierarchicalMultiCriterionInput `json:"studios"`
-	// Filter to only include scenes with this movie
-	Movies *MultiCriterionInput `json:"movies"`
+	// Filter to only include scenes with this movie, or its child movies
+	Movies *HierarchicalMultiCriterionInput `json:"movies"`
 	// Filter to only include scenes with these tags
 	Tags *HierarchicalMultiCriterionInput `json:"tags"`
 	// Filter by tag count
@@ -79,6 +87,12 @@ type SceneFilterType struct {
 	Interactive *bool `json:"interactive"`
 	// Filter by InteractiveSpeed
 	InteractiveSpeed *IntCriterionInput `json:"interactive_speed"`
+	// Filter by average interactive speed
+	InteractiveAverageSpeed *IntCriterionInput `json:"interactive_average_speed"`
+	// Filter by maximum interactive speed
+	InteractiveMaxSpeed *IntCriterionInput `json:"interactive_max_speed"`
+	// Filter by interactive actions per minute
+	InteractiveActionDensity *IntCriterionInput `json:"interactive_action_density"`
 	// Filter by captions
 	Captions *StringCriterionInput `json:"captions"`
 	// Filter by resume time
@@ -87,12 +101,22 @@ type SceneFilterType struct {
 	PlayCount *IntCriterionInput `json:"play_count"`
 	// Filter by play duration (in seconds)
 	PlayDuration *IntCriterionInput `json:"play_duration"`
+	// Filter by hue of the dominant cover color (in degrees)
+	DominantHue *FloatCriterionInput `json:"dominant_hue"`
+	// Filter by brightness of the dominant cover color (0 to 1)
+	DominantBrightness *FloatCriterionInput `json:"dominant_brightness"`
 	// Filter by date
 	Date *DateCriterionInput `json:"date"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields. All criteria must match
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
+	// Filter by criteria ratings. All criteria must match
+	CriteriaRatings []CriterionRatingCriterionInput `json:"criteria_ratings"`
+	// Filter by the average of the criteria ratings
+	CriteriaRatingAverage *IntCriterionInput `json:"criteria_rating_average"`
 }
 
 type SceneQueryOptions struct {
@@ -128,6 +152,7 @@ type SceneCreateInput struct {
 	Date         *string           `json:"date"`
 	Rating100    *int              `json:"rating100"`
 	Organized    *bool             `json:"organized"`
+	Favorite     *bool             `json:"favorite"`
 	StudioID     *string           `json:"studio_id"`
 	GalleryIds   []string          `json:"gallery_ids"`
 	PerformerIds []string          `json:"performer_ids"`
@@ -155,18 +180,43 @@ type SceneUpdateInput struct {
 	Rating100        *int              `json:"rating100"`
 	OCounter         *int              `json:"o_counter"`
 	Organized        *bool             `json:"organized"`
+	Favorite         *bool             `json:"favorite"`
+	ContentRating    *int              `json:"content_rating"`
 	StudioID         *string           `json:"studio_id"`
 	GalleryIds       []string          `json:"gallery_ids"`
 	PerformerIds     []string          `json:"performer_ids"`
 	Movies           []SceneMovieInput `json:"movies"`
 	TagIds           []string          `json:"tag_ids"`
 	// This should be a URL or a base64 encoded data URL
-	CoverImage    *string   `json:"cover_image"`
-	StashIds      []StashID `json:"stash_ids"`
-	ResumeTime    *float64  `json:"resume_time"`
-	PlayDuration  *float64  `json:"play_duration"`
-	PlayCount     *int      `json:"play_count"`
-	PrimaryFileID *string   `json:"primary_file_id"`
+	CoverImage    *string            `json:"cover_image"`
+	StashIds      []StashID          `json:"stash_ids"`
+	ResumeTime    *float64           `json:"resume_time"`
+	PlayDuration  *float64           `json:"play_duration"`
+	PlayCount     *int               `json:"play_count"`
+	PrimaryFileID *string            `json:"primary_file_id"`
+	CustomFields  *CustomFieldsInput `json:"custom_fields"`
+	// Sets or removes the ratings of the given criteria
+	CriteriaRatings []CriterionRatingInput `json:"criteria_ratings"`
+	// Replaces the preview generation options of the scene if set
+	PreviewOptions *ScenePreviewOptions `json:"preview_options"`
+}
+
+// ScenePreviewOptions overrides the preview generation options for a scene.
+// Options that are not set use the options of the generate task.
+type ScenePreviewOptions struct {
+	// Number of segments in the preview
+	Segments *int `json:"segments"`
+	// Duration of each segment, in seconds
+	SegmentDuration *float64 `json:"segment_duration"`
+	// Duration or percentage of the start of the video to exclude
+	ExcludeStart *string `json:"exclude_start"`
+	// Duration or percentage of the end of the video to exclude
+	ExcludeEnd *string `json:"exclude_end"`
+}
+
+// IsEmpty returns true if none of the options are set.
+func (o ScenePreviewOptions) IsEmpty() bool {
+	return o.Segments == nil && o.SegmentDuration == nil && o.ExcludeStart == nil && o.ExcludeEnd == nil
 }
 
 type SceneDestroyInput struct {
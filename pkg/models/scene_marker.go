@@ -1,5 +1,11 @@
 package models
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
 type SceneMarkerFilterType struct {
 	// Filter to only include scene markers with this tag
 	TagID *string `json:"tag_id"`
@@ -9,6 +15,9 @@ type SceneMarkerFilterType struct {
 	SceneTags *HierarchicalMultiCriterionInput `json:"scene_tags"`
 	// Filter to only include scene markers with these performers
 	Performers *MultiCriterionInput `json:"performers"`
+	// Filter by the duration of the marker range, in seconds. Markers without
+	// an end have no duration.
+	Duration *FloatCriterionInput `json:"duration"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
@@ -26,3 +35,52 @@ type MarkerStringsResultType struct {
 	ID    string `json:"id"`
 	Title string `json:"title"`
 }
+
+type MarkerChapterFormat string
+
+const (
+	// ffmpeg metadata file, as produced by ffmpeg -f ffmetadata
+	MarkerChapterFormatFfmetadata MarkerChapterFormat = "FFMETADATA"
+	// plain text with one timestamp per line, as used in video descriptions
+	MarkerChapterFormatTimestamps MarkerChapterFormat = "TIMESTAMPS"
+	// WebVTT chapters file
+	MarkerChapterFormatVtt MarkerChapterFormat = "VTT"
+	// funscript with chapters in its metadata
+	MarkerChapterFormatFunscript MarkerChapterFormat = "FUNSCRIPT"
+)
+
+var AllMarkerChapterFormat = []MarkerChapterFormat{
+	MarkerChapterFormatFfmetadata,
+	MarkerChapterFormatTimestamps,
+	MarkerChapterFormatVtt,
+	MarkerChapterFormatFunscript,
+}
+
+func (e MarkerChapterFormat) IsValid() bool {
+	switch e {
+	case MarkerChapterFormatFfmetadata, MarkerChapterFormatTimestamps, MarkerChapterFormatVtt, MarkerChapterFormatFunscript:
+		return true
+	}
+	return false
+}
+
+func (e MarkerChapterFormat) String() string {
+	return string(e)
+}
+
+func (e *MarkerChapterFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MarkerChapterFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MarkerChapterFormat", str)
+	}
+	return nil
+}
+
+func (e MarkerChapterFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
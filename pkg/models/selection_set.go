@@ -0,0 +1,23 @@
+package models
+
+import "context"
+
+type SelectionSetReader interface {
+	All(ctx context.Context) ([]*SelectionSet, error)
+	Find(ctx context.Context, id int) (*SelectionSet, error)
+	FindMany(ctx context.Context, ids []int, ignoreNotFound bool) ([]*SelectionSet, error)
+	FindByMode(ctx context.Context, mode FilterMode) ([]*SelectionSet, error)
+	GetObjectIDs(ctx context.Context, id int) ([]int, error)
+}
+
+type SelectionSetWriter interface {
+	Create(ctx context.Context, obj *SelectionSet) error
+	Update(ctx context.Context, obj *SelectionSet) error
+	UpdateObjectIDs(ctx context.Context, id int, objectIDs []int, mode RelationshipUpdateMode) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type SelectionSetReaderWriter interface {
+	SelectionSetReader
+	SelectionSetWriter
+}
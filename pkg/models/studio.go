@@ -32,6 +32,8 @@ type StudioFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields. All criteria must match
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type StudioCreateInput struct {
@@ -53,10 +55,11 @@ type StudioUpdateInput struct {
 	URL      *string `json:"url"`
 	ParentID *string `json:"parent_id"`
 	// This should be a URL or a base64 encoded data URL
-	Image         *string   `json:"image"`
-	StashIds      []StashID `json:"stash_ids"`
-	Rating100     *int      `json:"rating100"`
-	Details       *string   `json:"details"`
-	Aliases       []string  `json:"aliases"`
-	IgnoreAutoTag *bool     `json:"ignore_auto_tag"`
+	Image         *string            `json:"image"`
+	StashIds      []StashID          `json:"stash_ids"`
+	Rating100     *int               `json:"rating100"`
+	Details       *string            `json:"details"`
+	Aliases       []string           `json:"aliases"`
+	IgnoreAutoTag *bool              `json:"ignore_auto_tag"`
+	CustomFields  *CustomFieldsInput `json:"custom_fields"`
 }
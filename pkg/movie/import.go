@@ -11,6 +11,7 @@ import (
 
 type ImporterReaderWriter interface {
 	models.MovieCreatorUpdater
+	models.CustomFieldsWriter
 	FindByName(ctx context.Context, name string, nocase bool) (*models.Movie, error)
 }
 
@@ -133,6 +134,14 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		}
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting movie custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
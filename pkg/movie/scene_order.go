@@ -0,0 +1,164 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/WithoutPants/sortorder/casefolded"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var ErrSceneNotInMovie = errors.New("scene is not in movie")
+
+// SetSceneIndex sets the index of a scene within a movie. A nil index clears
+// the index of the scene. Returns ErrSceneNotInMovie if the scene is not part of the movie.
+func SetSceneIndex(ctx context.Context, r models.MovieSceneIndexer, movieID int, sceneID int, index *int) error {
+	existing, err := r.GetSceneIndexes(ctx, movieID)
+	if err != nil {
+		return err
+	}
+
+	if indexOfScene(existing, sceneID) == -1 {
+		return fmt.Errorf("%w: scene %d, movie %d", ErrSceneNotInMovie, sceneID, movieID)
+	}
+
+	return r.UpdateSceneIndexes(ctx, movieID, []models.MovieScene{
+		{
+			SceneID:    sceneID,
+			SceneIndex: index,
+		},
+	})
+}
+
+// MoveScene moves a scene one position earlier (up) or later (down) in the
+// movie's scene order. The scene indexes of the movie are renumbered
+// sequentially from 1 in the resulting order.
+func MoveScene(ctx context.Context, r models.MovieSceneIndexer, movieID int, sceneID int, up bool) error {
+	existing, err := r.GetSceneIndexes(ctx, movieID)
+	if err != nil {
+		return err
+	}
+
+	i := indexOfScene(existing, sceneID)
+	if i == -1 {
+		return fmt.Errorf("%w: scene %d, movie %d", ErrSceneNotInMovie, sceneID, movieID)
+	}
+
+	other := i + 1
+	if up {
+		other = i - 1
+	}
+
+	if other >= 0 && other < len(existing) {
+		existing[i], existing[other] = existing[other], existing[i]
+	}
+
+	return r.UpdateSceneIndexes(ctx, movieID, renumber(existing))
+}
+
+// AutoOrderScenes sets the scene indexes of all scenes in the movie,
+// ordered using the provided order. Scenes that cannot be ordered (for
+// example, scenes without a date when ordering by date) are ordered last.
+func AutoOrderScenes(ctx context.Context, r models.MovieSceneIndexer, sceneReader models.SceneGetter, movieID int, orderBy models.MovieSceneOrderEnum) error {
+	existing, err := r.GetSceneIndexes(ctx, movieID)
+	if err != nil {
+		return err
+	}
+
+	sceneIDs := make([]int, len(existing))
+	for i, v := range existing {
+		sceneIDs[i] = v.SceneID
+	}
+
+	scenes, err := sceneReader.FindMany(ctx, sceneIDs)
+	if err != nil {
+		return err
+	}
+
+	var less func(a, b *models.Scene) bool
+	switch orderBy {
+	case models.MovieSceneOrderEnumDate:
+		less = sceneDateLess
+	case models.MovieSceneOrderEnumFilename:
+		less = sceneFilenameLess
+	default:
+		return fmt.Errorf("invalid scene order: %s", orderBy)
+	}
+
+	sort.SliceStable(scenes, func(i, j int) bool {
+		return less(scenes[i], scenes[j])
+	})
+
+	ordered := make([]models.MovieScene, len(scenes))
+	for i, s := range scenes {
+		ordered[i] = models.MovieScene{
+			SceneID: s.ID,
+		}
+	}
+
+	return r.UpdateSceneIndexes(ctx, movieID, renumber(ordered))
+}
+
+func indexOfScene(v []models.MovieScene, sceneID int) int {
+	for i, vv := range v {
+		if vv.SceneID == sceneID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// renumber sets the scene indexes sequentially from 1 in the order provided.
+func renumber(v []models.MovieScene) []models.MovieScene {
+	for i := range v {
+		index := i + 1
+		v[i].SceneIndex = &index
+	}
+
+	return v
+}
+
+func sceneDateLess(a, b *models.Scene) bool {
+	switch {
+	case a.Date == nil && b.Date == nil:
+		return sceneTitleLess(a, b)
+	case a.Date == nil:
+		return false
+	case b.Date == nil:
+		return true
+	case a.Date.Time.Equal(b.Date.Time):
+		return sceneTitleLess(a, b)
+	}
+
+	return a.Date.Time.Before(b.Date.Time)
+}
+
+func sceneTitleLess(a, b *models.Scene) bool {
+	if a.Title == b.Title {
+		return sceneFilenameLess(a, b)
+	}
+
+	return casefolded.NaturalLess(a.Title, b.Title)
+}
+
+func sceneFilenameLess(a, b *models.Scene) bool {
+	aBase := filepath.Base(a.Path)
+	bBase := filepath.Base(b.Path)
+
+	switch {
+	case a.Path == "" && b.Path == "":
+		return a.ID < b.ID
+	case a.Path == "":
+		return false
+	case b.Path == "":
+		return true
+	case aBase == bBase:
+		return a.ID < b.ID
+	}
+
+	return casefolded.NaturalLess(aBase, bBase)
+}
@@ -0,0 +1,164 @@
+package movie
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const orderMovieID = 1
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestMoveScene(t *testing.T) {
+	existing := func() []models.MovieScene {
+		return []models.MovieScene{
+			{SceneID: 10, SceneIndex: intPtr(1)},
+			{SceneID: 11, SceneIndex: intPtr(5)},
+			{SceneID: 12},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		sceneID int
+		up      bool
+		want    []models.MovieScene
+		wantErr bool
+	}{
+		{
+			"move up",
+			11,
+			true,
+			[]models.MovieScene{
+				{SceneID: 11, SceneIndex: intPtr(1)},
+				{SceneID: 10, SceneIndex: intPtr(2)},
+				{SceneID: 12, SceneIndex: intPtr(3)},
+			},
+			false,
+		},
+		{
+			"move down",
+			11,
+			false,
+			[]models.MovieScene{
+				{SceneID: 10, SceneIndex: intPtr(1)},
+				{SceneID: 12, SceneIndex: intPtr(2)},
+				{SceneID: 11, SceneIndex: intPtr(3)},
+			},
+			false,
+		},
+		{
+			"move first up",
+			10,
+			true,
+			[]models.MovieScene{
+				{SceneID: 10, SceneIndex: intPtr(1)},
+				{SceneID: 11, SceneIndex: intPtr(2)},
+				{SceneID: 12, SceneIndex: intPtr(3)},
+			},
+			false,
+		},
+		{
+			"scene not in movie",
+			99,
+			true,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewDatabase()
+
+			db.Movie.On("GetSceneIndexes", testCtx, orderMovieID).Return(existing(), nil).Once()
+			if tt.want != nil {
+				db.Movie.On("UpdateSceneIndexes", testCtx, orderMovieID, tt.want).Return(nil).Once()
+			}
+
+			err := MoveScene(testCtx, db.Movie, orderMovieID, tt.sceneID, tt.up)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MoveScene() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrSceneNotInMovie))
+			}
+
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAutoOrderScenes(t *testing.T) {
+	date := func(s string) *models.Date {
+		d, _ := models.ParseDate(s)
+		return &d
+	}
+
+	scenes := []*models.Scene{
+		{ID: 1, Title: "b", Date: date("2020-01-01"), Path: "/a/scene 10.mp4"},
+		{ID: 2, Title: "a", Path: "/z/scene 2.mp4"},
+		{ID: 3, Title: "c", Date: date("2019-01-01"), Path: "/b/scene 1.mp4"},
+		{ID: 4, Title: "a", Date: date("2020-01-01")},
+	}
+
+	existing := []models.MovieScene{
+		{SceneID: 1},
+		{SceneID: 2},
+		{SceneID: 3},
+		{SceneID: 4},
+	}
+
+	tests := []struct {
+		name    string
+		orderBy models.MovieSceneOrderEnum
+		want    []models.MovieScene
+	}{
+		{
+			"date",
+			models.MovieSceneOrderEnumDate,
+			[]models.MovieScene{
+				{SceneID: 3, SceneIndex: intPtr(1)},
+				{SceneID: 4, SceneIndex: intPtr(2)},
+				{SceneID: 1, SceneIndex: intPtr(3)},
+				{SceneID: 2, SceneIndex: intPtr(4)},
+			},
+		},
+		{
+			"filename",
+			models.MovieSceneOrderEnumFilename,
+			[]models.MovieScene{
+				{SceneID: 3, SceneIndex: intPtr(1)},
+				{SceneID: 2, SceneIndex: intPtr(2)},
+				{SceneID: 1, SceneIndex: intPtr(3)},
+				{SceneID: 4, SceneIndex: intPtr(4)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewDatabase()
+
+			// copy so that sorting doesn't affect other tests
+			found := make([]*models.Scene, len(scenes))
+			copy(found, scenes)
+
+			db.Movie.On("GetSceneIndexes", testCtx, orderMovieID).Return(existing, nil).Once()
+			db.Scene.On("FindMany", testCtx, []int{1, 2, 3, 4}).Return(found, nil).Once()
+			db.Movie.On("UpdateSceneIndexes", testCtx, orderMovieID, tt.want).Return(nil).Once()
+
+			err := AutoOrderScenes(testCtx, db.Movie, db.Scene, orderMovieID, tt.orderBy)
+			assert.Nil(t, err)
+
+			db.AssertExpectations(t)
+		})
+	}
+}
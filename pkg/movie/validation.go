@@ -0,0 +1,15 @@
+package movie
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ValidateParent returns an error if the movie with the given id cannot
+// have newParentID as its parent. See models.ValidateParent.
+func ValidateParent(ctx context.Context, movieID int, newParentID int, qb models.MovieGetter) error {
+	return models.ValidateParent(ctx, "movie", movieID, newParentID, qb.Find, func(m *models.Movie) *int {
+		return m.ParentID
+	})
+}
@@ -0,0 +1,122 @@
+// Package palette provides functions to extract the dominant colors of images.
+package palette
+
+import (
+	"image"
+	"io"
+	"sort"
+
+	// register image decoders
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// DefaultSize is the number of colors extracted for a palette.
+const DefaultSize = 5
+
+const (
+	// sampleSize is the maximum number of pixels sampled in each dimension
+	sampleSize = 100
+
+	// minDistance is the minimum squared RGB distance between palette colors,
+	// so that the palette does not contain several shades of the same color
+	minDistance = 48 * 48
+)
+
+type bucket struct {
+	r, g, b int
+	count   int
+}
+
+func (b bucket) color() models.Color {
+	return models.Color{
+		R: uint8(b.r / b.count),
+		G: uint8(b.g / b.count),
+		B: uint8(b.b / b.count),
+	}
+}
+
+// Decode decodes a gif, jpeg, png or webp image.
+func Decode(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// Extract returns up to size dominant colors of img, most dominant first.
+// Pixels are grouped by color, and the average color of the largest groups
+// is returned. Mostly transparent pixels are ignored.
+func Extract(img image.Image, size int) []models.Color {
+	bounds := img.Bounds()
+	if bounds.Empty() || size <= 0 {
+		return nil
+	}
+
+	stepX := bounds.Dx()/sampleSize + 1
+	stepY := bounds.Dy()/sampleSize + 1
+
+	// quantize to 4 bits per channel
+	var buckets [4096]bucket
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+
+			// un-premultiply alpha
+			r = r * 0xffff / a >> 8
+			g = g * 0xffff / a >> 8
+			b = b * 0xffff / a >> 8
+
+			bb := &buckets[(r>>4)<<8|(g>>4)<<4|b>>4]
+			bb.r += int(r)
+			bb.g += int(g)
+			bb.b += int(b)
+			bb.count++
+		}
+	}
+
+	var used []bucket
+	for _, b := range buckets {
+		if b.count > 0 {
+			used = append(used, b)
+		}
+	}
+
+	sort.SliceStable(used, func(i, j int) bool {
+		return used[i].count > used[j].count
+	})
+
+	var ret []models.Color
+	for _, b := range used {
+		c := b.color()
+		if !isDistinct(c, ret) {
+			continue
+		}
+
+		ret = append(ret, c)
+		if len(ret) == size {
+			break
+		}
+	}
+
+	return ret
+}
+
+func isDistinct(c models.Color, colors []models.Color) bool {
+	for _, o := range colors {
+		dr := int(c.R) - int(o.R)
+		dg := int(c.G) - int(o.G)
+		db := int(c.B) - int(o.B)
+		if dr*dr+dg*dg+db*db < minDistance {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,44 @@
+package palette
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 300; x++ {
+			var c color.RGBA
+			switch {
+			case x < 200:
+				// two thirds red, with a slightly different shade that
+				// should be merged
+				c = color.RGBA{R: 200, A: 255}
+				if y%2 == 0 {
+					c.R = 210
+				}
+			case y < 200:
+				c = color.RGBA{B: 200, A: 255}
+			default:
+				// transparent pixels are ignored
+				c = color.RGBA{}
+			}
+
+			img.Set(x, y, c)
+		}
+	}
+
+	palette := Extract(img, DefaultSize)
+	if assert.Len(t, palette, 2) {
+		assert.InDelta(t, 205, int(palette[0].R), 6)
+		assert.Equal(t, models.Color{B: 200}, palette[1])
+	}
+
+	assert.Len(t, Extract(img, 1), 1)
+	assert.Nil(t, Extract(image.NewRGBA(image.Rect(0, 0, 0, 0)), DefaultSize))
+}
@@ -15,6 +15,7 @@ import (
 
 type ImporterReaderWriter interface {
 	models.PerformerCreatorUpdater
+	models.CustomFieldsWriter
 	models.PerformerQueryer
 }
 
@@ -122,6 +123,14 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		}
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting performer custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
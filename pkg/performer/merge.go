@@ -0,0 +1,41 @@
+package performer
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// MergeAliases returns the aliases of a performer named name after merging
+// the source performers into the destination performer. The aliases are
+// the name and aliases of the destination, followed by the names and
+// aliases of the sources. Names equal to name, and duplicates, are omitted,
+// ignoring case. The aliases of the performers must be loaded.
+func MergeAliases(name string, destination *models.Performer, sources []*models.Performer) []string {
+	seen := map[string]bool{
+		strings.ToLower(name): true,
+	}
+
+	var ret []string
+	add := func(names ...string) {
+		for _, n := range names {
+			n = strings.TrimSpace(n)
+			key := strings.ToLower(n)
+			if n == "" || seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			ret = append(ret, n)
+		}
+	}
+
+	add(destination.Name)
+	add(destination.Aliases.List()...)
+	for _, s := range sources {
+		add(s.Name)
+		add(s.Aliases.List()...)
+	}
+
+	return ret
+}
@@ -0,0 +1,38 @@
+package performer
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeAliases(t *testing.T) {
+	newPerformer := func(name string, aliases ...string) *models.Performer {
+		return &models.Performer{
+			Name:    name,
+			Aliases: models.NewRelatedStrings(aliases),
+		}
+	}
+
+	destination := newPerformer("Jane Doe", "Jane", "JD")
+	sources := []*models.Performer{
+		newPerformer("jane doe", "Janie"),
+		newPerformer("Jane D.", "jd", "Jane Doe"),
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Jane Doe", []string{"Jane", "JD", "Janie", "Jane D."}},
+		{"Jane D.", []string{"Jane Doe", "Jane", "JD", "Janie"}},
+		{"Janet Doe", []string{"Jane Doe", "Jane", "JD", "Janie", "Jane D."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeAliases(tt.name, destination, sources)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -26,6 +26,10 @@ const (
 	SceneUpdatePost  HookTriggerEnum = "Scene.Update.Post"
 	SceneDestroyPost HookTriggerEnum = "Scene.Destroy.Post"
 
+	SceneRelationshipCreatePost  HookTriggerEnum = "SceneRelationship.Create.Post"
+	SceneRelationshipUpdatePost  HookTriggerEnum = "SceneRelationship.Update.Post"
+	SceneRelationshipDestroyPost HookTriggerEnum = "SceneRelationship.Destroy.Post"
+
 	ImageCreatePost  HookTriggerEnum = "Image.Create.Post"
 	ImageUpdatePost  HookTriggerEnum = "Image.Update.Post"
 	ImageDestroyPost HookTriggerEnum = "Image.Destroy.Post"
@@ -44,16 +48,23 @@ const (
 
 	PerformerCreatePost  HookTriggerEnum = "Performer.Create.Post"
 	PerformerUpdatePost  HookTriggerEnum = "Performer.Update.Post"
+	PerformerMergePost   HookTriggerEnum = "Performer.Merge.Post"
 	PerformerDestroyPost HookTriggerEnum = "Performer.Destroy.Post"
 
 	StudioCreatePost  HookTriggerEnum = "Studio.Create.Post"
 	StudioUpdatePost  HookTriggerEnum = "Studio.Update.Post"
+	StudioMergePost   HookTriggerEnum = "Studio.Merge.Post"
 	StudioDestroyPost HookTriggerEnum = "Studio.Destroy.Post"
 
 	TagCreatePost  HookTriggerEnum = "Tag.Create.Post"
 	TagUpdatePost  HookTriggerEnum = "Tag.Update.Post"
 	TagMergePost   HookTriggerEnum = "Tag.Merge.Post"
 	TagDestroyPost HookTriggerEnum = "Tag.Destroy.Post"
+
+	FileCreatePost  HookTriggerEnum = "File.Create.Post"
+	FileUpdatePost  HookTriggerEnum = "File.Update.Post"
+	FileMovePost    HookTriggerEnum = "File.Move.Post"
+	FileMissingPost HookTriggerEnum = "File.Missing.Post"
 )
 
 var AllHookTriggerEnum = []HookTriggerEnum{
@@ -65,6 +76,10 @@ var AllHookTriggerEnum = []HookTriggerEnum{
 	SceneUpdatePost,
 	SceneDestroyPost,
 
+	SceneRelationshipCreatePost,
+	SceneRelationshipUpdatePost,
+	SceneRelationshipDestroyPost,
+
 	ImageCreatePost,
 	ImageUpdatePost,
 	ImageDestroyPost,
@@ -83,16 +98,23 @@ var AllHookTriggerEnum = []HookTriggerEnum{
 
 	PerformerCreatePost,
 	PerformerUpdatePost,
+	PerformerMergePost,
 	PerformerDestroyPost,
 
 	StudioCreatePost,
 	StudioUpdatePost,
+	StudioMergePost,
 	StudioDestroyPost,
 
 	TagCreatePost,
 	TagUpdatePost,
 	TagMergePost,
 	TagDestroyPost,
+
+	FileCreatePost,
+	FileUpdatePost,
+	FileMovePost,
+	FileMissingPost,
 }
 
 func (e HookTriggerEnum) IsValid() bool {
@@ -106,6 +128,10 @@ func (e HookTriggerEnum) IsValid() bool {
 		SceneUpdatePost,
 		SceneDestroyPost,
 
+		SceneRelationshipCreatePost,
+		SceneRelationshipUpdatePost,
+		SceneRelationshipDestroyPost,
+
 		ImageCreatePost,
 		ImageUpdatePost,
 		ImageDestroyPost,
@@ -124,15 +150,22 @@ func (e HookTriggerEnum) IsValid() bool {
 
 		PerformerCreatePost,
 		PerformerUpdatePost,
+		PerformerMergePost,
 		PerformerDestroyPost,
 
 		StudioCreatePost,
 		StudioUpdatePost,
+		StudioMergePost,
 		StudioDestroyPost,
 
 		TagCreatePost,
 		TagUpdatePost,
-		TagDestroyPost:
+		TagDestroyPost,
+
+		FileCreatePost,
+		FileUpdatePost,
+		FileMovePost,
+		FileMissingPost:
 		return true
 	}
 	return false
@@ -146,6 +179,13 @@ func addHookContext(argsMap common.ArgsMap, hookContext common.HookContext) {
 	argsMap[common.HookContextKey] = hookContext
 }
 
+// FileHookInput is the input of file hooks fired during a scan or clean.
+type FileHookInput struct {
+	File models.File `json:"file"`
+	// OldPath is the previous path of a moved file
+	OldPath string `json:"old_path,omitempty"`
+}
+
 // types for destroy hooks, to provide a little more information
 type SceneDestroyInput struct {
 	models.SceneDestroyInput
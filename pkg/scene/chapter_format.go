@@ -0,0 +1,455 @@
+package scene
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const ffmetadataHeader = ";FFMETADATA1"
+
+// ffmpeg assumes nanoseconds if a chapter has no timebase
+const ffmetadataDefaultTimebase = 1e-9
+
+var (
+	timestampPattern = `((?:\d+:)?\d{1,2}:\d{2}(?:[.,]\d+)?)`
+	// timestamp at the start of the line, ie "1:30 - Title"
+	leadingTimestampRE = regexp.MustCompile(`^\s*[\[(]?` + timestampPattern + `[\])]?(?:\s*[-–—:|]\s*|\s+|$)(.*)$`)
+	// timestamp at the end of the line, ie "Title - 1:30"
+	trailingTimestampRE = regexp.MustCompile(`^(.*?)(?:\s*[-–—:|]\s*|\s+)[\[(]?` + timestampPattern + `[\])]?\s*$`)
+)
+
+// ParseChapters parses chapters from data in the provided format. Chapters
+// are returned in the order they appear in the data.
+func ParseChapters(format models.MarkerChapterFormat, data string) ([]Chapter, error) {
+	switch format {
+	case models.MarkerChapterFormatFfmetadata:
+		return parseFfmetadataChapters(data)
+	case models.MarkerChapterFormatTimestamps:
+		return parseTimestampChapters(data)
+	case models.MarkerChapterFormatVtt:
+		return parseVTTChapters(data)
+	case models.MarkerChapterFormatFunscript:
+		return parseFunscriptChapters(data)
+	}
+
+	return nil, fmt.Errorf("unsupported chapter format %q", format)
+}
+
+// FormatChapters returns chapters in the provided format. Chapters without an
+// end end where the next chapter starts, and the last chapter ends at the
+// provided duration. Formats without end times ignore the chapter ends.
+func FormatChapters(format models.MarkerChapterFormat, chapters []Chapter, duration float64) (string, error) {
+	switch format {
+	case models.MarkerChapterFormatFfmetadata:
+		return formatFfmetadataChapters(chapters, duration), nil
+	case models.MarkerChapterFormatTimestamps:
+		return formatTimestampChapters(chapters), nil
+	case models.MarkerChapterFormatVtt:
+		return formatVTTChapters(chapters, duration), nil
+	case models.MarkerChapterFormatFunscript:
+		return formatFunscriptChapters(chapters, duration)
+	}
+
+	return "", fmt.Errorf("unsupported chapter format %q", format)
+}
+
+// parseChapterTime parses a time in [[hh:]mm:]ss[.fff] format into seconds.
+// A comma may be used as the decimal separator.
+func parseChapterTime(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	if s == "" {
+		return 0, fmt.Errorf("empty time")
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	var ret float64
+	for i, p := range parts {
+		isSeconds := i == len(parts)-1
+
+		var v float64
+		var err error
+		if isSeconds {
+			v, err = strconv.ParseFloat(p, 64)
+		} else {
+			var n int
+			n, err = strconv.Atoi(p)
+			v = float64(n)
+		}
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+
+		ret = ret*60 + v
+	}
+
+	return ret, nil
+}
+
+func parseVTTChapters(data string) ([]Chapter, error) {
+	var ret []Chapter
+	var current *Chapter
+	var titleLines []string
+
+	finish := func() {
+		if current != nil {
+			current.Title = strings.Join(titleLines, " ")
+			ret = append(ret, *current)
+		}
+		current = nil
+		titleLines = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			finish()
+		case strings.Contains(line, "-->"):
+			finish()
+
+			start, end, _ := strings.Cut(line, "-->")
+			startSeconds, err := parseChapterTime(start)
+			if err != nil {
+				return nil, err
+			}
+
+			// the end time may be followed by cue settings
+			endFields := strings.Fields(end)
+			if len(endFields) == 0 {
+				return nil, fmt.Errorf("missing end time in %q", line)
+			}
+			endSeconds, err := parseChapterTime(endFields[0])
+			if err != nil {
+				return nil, err
+			}
+
+			current = &Chapter{
+				Seconds:    startSeconds,
+				EndSeconds: &endSeconds,
+			}
+		case current != nil:
+			titleLines = append(titleLines, line)
+		}
+	}
+	finish()
+
+	return ret, scanner.Err()
+}
+
+func formatVTTChapters(chapters []Chapter, duration float64) string {
+	vttLines := []string{"WEBVTT", ""}
+	for i, c := range chapters {
+		end := chapterEnd(chapters, i, duration)
+
+		vttLines = append(vttLines, fmt.Sprintf("Chapter %d", i+1))
+		vttLines = append(vttLines, utils.GetVTTTime(c.Seconds)+" --> "+utils.GetVTTTime(end))
+		vttLines = append(vttLines, c.Title)
+		vttLines = append(vttLines, "")
+	}
+
+	return strings.Join(vttLines, "\n")
+}
+
+// unescapeFfmetadata removes the backslash escapes of ffmetadata values.
+func unescapeFfmetadata(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func escapeFfmetadata(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '=', ';', '#', '\\', '\n':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func parseFfmetadataTimebase(s string) (float64, error) {
+	num, den, found := strings.Cut(s, "/")
+	if !found {
+		return 0, fmt.Errorf("invalid timebase %q", s)
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timebase %q", s)
+	}
+	d, err := strconv.Atoi(den)
+	if err != nil || d == 0 {
+		return 0, fmt.Errorf("invalid timebase %q", s)
+	}
+
+	return float64(n) / float64(d), nil
+}
+
+func parseFfmetadataChapters(data string) ([]Chapter, error) {
+	type ffmetadataChapter struct {
+		timebase float64
+		start    *int64
+		end      *int64
+		title    string
+	}
+
+	var chapters []ffmetadataChapter
+	var current *ffmetadataChapter
+
+	finish := func() {
+		if current != nil {
+			chapters = append(chapters, *current)
+		}
+		current = nil
+	}
+
+	// values may continue onto the next line with an escaped newline
+	var lines []string
+	continued := false
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if continued {
+			lines[len(lines)-1] += "\n" + line
+		} else {
+			lines = append(lines, line)
+		}
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+		continued = trailing%2 == 1
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			finish()
+			if strings.EqualFold(trimmed, "[CHAPTER]") {
+				current = &ffmetadataChapter{
+					timebase: ffmetadataDefaultTimebase,
+				}
+			}
+			continue
+		case current == nil:
+			// global or stream metadata
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "TIMEBASE":
+			timebase, err := parseFfmetadataTimebase(strings.TrimSpace(value))
+			if err != nil {
+				return nil, err
+			}
+			current.timebase = timebase
+		case "START", "END":
+			v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chapter time %q", value)
+			}
+			if strings.EqualFold(strings.TrimSpace(key), "START") {
+				current.start = &v
+			} else {
+				current.end = &v
+			}
+		case "TITLE":
+			current.title = unescapeFfmetadata(value)
+		}
+	}
+	finish()
+
+	var ret []Chapter
+	for _, c := range chapters {
+		if c.start == nil {
+			return nil, fmt.Errorf("chapter %q has no start", c.title)
+		}
+
+		chapter := Chapter{
+			Title:   c.title,
+			Seconds: float64(*c.start) * c.timebase,
+		}
+		if c.end != nil {
+			end := float64(*c.end) * c.timebase
+			chapter.EndSeconds = &end
+		}
+
+		ret = append(ret, chapter)
+	}
+
+	return ret, nil
+}
+
+func formatFfmetadataChapters(chapters []Chapter, duration float64) string {
+	lines := []string{ffmetadataHeader}
+	for i, c := range chapters {
+		end := chapterEnd(chapters, i, duration)
+
+		lines = append(lines,
+			"",
+			"[CHAPTER]",
+			"TIMEBASE=1/1000",
+			fmt.Sprintf("START=%d", int64(math.Round(c.Seconds*1000))),
+			fmt.Sprintf("END=%d", int64(math.Round(end*1000))),
+			"title="+escapeFfmetadata(c.Title),
+		)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func parseTimestampChapters(data string) ([]Chapter, error) {
+	var ret []Chapter
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		var timestamp, title string
+		if m := leadingTimestampRE.FindStringSubmatch(line); m != nil {
+			timestamp, title = m[1], m[2]
+		} else if m := trailingTimestampRE.FindStringSubmatch(line); m != nil {
+			title, timestamp = m[1], m[2]
+		} else {
+			// not a chapter line
+			continue
+		}
+
+		seconds, err := parseChapterTime(timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, Chapter{
+			Title:   strings.TrimSpace(title),
+			Seconds: seconds,
+		})
+	}
+
+	return ret, scanner.Err()
+}
+
+// getTimestamp returns seconds as [h:]mm:ss, or m:ss if under an hour.
+func getTimestamp(seconds float64) string {
+	s := int(seconds)
+	if s < 0 {
+		s = 0
+	}
+
+	hours := s / 3600
+	minutes := (s % 3600) / 60
+	s %= 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, s)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, s)
+}
+
+func formatTimestampChapters(chapters []Chapter) string {
+	var b strings.Builder
+	for _, c := range chapters {
+		b.WriteString(getTimestamp(c.Seconds))
+		if c.Title != "" {
+			b.WriteString(" " + c.Title)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+type funscriptChapter struct {
+	Name      string `json:"name"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+type funscriptChapters struct {
+	Metadata struct {
+		Chapters []funscriptChapter `json:"chapters"`
+	} `json:"metadata"`
+}
+
+func parseFunscriptChapters(data string) ([]Chapter, error) {
+	var script funscriptChapters
+	if err := json.Unmarshal([]byte(data), &script); err != nil {
+		return nil, fmt.Errorf("invalid funscript: %w", err)
+	}
+
+	var ret []Chapter
+	for _, c := range script.Metadata.Chapters {
+		seconds, err := parseChapterTime(c.StartTime)
+		if err != nil {
+			return nil, err
+		}
+
+		chapter := Chapter{
+			Title:   c.Name,
+			Seconds: seconds,
+		}
+
+		if c.EndTime != "" {
+			end, err := parseChapterTime(c.EndTime)
+			if err != nil {
+				return nil, err
+			}
+			chapter.EndSeconds = &end
+		}
+
+		ret = append(ret, chapter)
+	}
+
+	return ret, nil
+}
+
+// formatFunscriptChapters returns the chapters as the metadata of a funscript
+// without actions, to be merged into an existing script.
+func formatFunscriptChapters(chapters []Chapter, duration float64) (string, error) {
+	var script funscriptChapters
+	script.Metadata.Chapters = []funscriptChapter{}
+	for i, c := range chapters {
+		script.Metadata.Chapters = append(script.Metadata.Chapters, funscriptChapter{
+			Name:      c.Title,
+			StartTime: utils.GetVTTTime(c.Seconds),
+			EndTime:   utils.GetVTTTime(chapterEnd(chapters, i, duration)),
+		})
+	}
+
+	ret, err := json.MarshalIndent(script, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(ret) + "\n", nil
+}
@@ -0,0 +1,181 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChapters(t *testing.T) {
+	floatPtr := func(v float64) *float64 {
+		return &v
+	}
+
+	tests := []struct {
+		name    string
+		format  models.MarkerChapterFormat
+		data    string
+		want    []Chapter
+		wantErr bool
+	}{
+		{
+			"ffmetadata",
+			models.MarkerChapterFormatFfmetadata,
+			";FFMETADATA1\ntitle=Scene\n\n[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=90500\ntitle=Intro\n\n[CHAPTER]\nTIMEBASE=1/1\nSTART=90\nEND=120\ntitle=a\\=b\\\nc\n[STREAM]\ntitle=ignored\n",
+			[]Chapter{
+				{Title: "Intro", Seconds: 0, EndSeconds: floatPtr(90.5)},
+				{Title: "a=b\nc", Seconds: 90, EndSeconds: floatPtr(120)},
+			},
+			false,
+		},
+		{
+			"ffmetadata default timebase",
+			models.MarkerChapterFormatFfmetadata,
+			";FFMETADATA1\n[CHAPTER]\nSTART=2000000000\ntitle=Nanoseconds\n",
+			[]Chapter{
+				{Title: "Nanoseconds", Seconds: 2},
+			},
+			false,
+		},
+		{
+			"ffmetadata invalid timebase",
+			models.MarkerChapterFormatFfmetadata,
+			";FFMETADATA1\n[CHAPTER]\nTIMEBASE=1/0\nSTART=0\n",
+			nil,
+			true,
+		},
+		{
+			"timestamps",
+			models.MarkerChapterFormatTimestamps,
+			"Chapters:\n0:00 Intro\n[1:30] - Second\n1:02:03.5 | Third\nFourth - 1:05:00\nnot a chapter\n",
+			[]Chapter{
+				{Title: "Intro", Seconds: 0},
+				{Title: "Second", Seconds: 90},
+				{Title: "Third", Seconds: 3723.5},
+				{Title: "Fourth", Seconds: 3900},
+			},
+			false,
+		},
+		{
+			"vtt",
+			models.MarkerChapterFormatVtt,
+			"WEBVTT\n\nNOTE generated\n\nChapter 1\n00:00:00.000 --> 00:01:30.500\nIntro\n\n01:30.500 --> 00:10:00,000 align:start\nTwo\nlines\n",
+			[]Chapter{
+				{Title: "Intro", Seconds: 0, EndSeconds: floatPtr(90.5)},
+				{Title: "Two lines", Seconds: 90.5, EndSeconds: floatPtr(600)},
+			},
+			false,
+		},
+		{
+			"vtt invalid time",
+			models.MarkerChapterFormatVtt,
+			"WEBVTT\n\n00:00:xx --> 00:01:00.000\nIntro\n",
+			nil,
+			true,
+		},
+		{
+			"funscript",
+			models.MarkerChapterFormatFunscript,
+			`{"actions":[],"metadata":{"chapters":[{"name":"Intro","startTime":"00:00:00.000","endTime":"00:01:30.500"},{"name":"Open","startTime":"00:01:30.500"}]}}`,
+			[]Chapter{
+				{Title: "Intro", Seconds: 0, EndSeconds: floatPtr(90.5)},
+				{Title: "Open", Seconds: 90.5},
+			},
+			false,
+		},
+		{
+			"funscript invalid",
+			models.MarkerChapterFormatFunscript,
+			"{",
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChapters(tt.format, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseChapters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatChapters(t *testing.T) {
+	rangeEnd := 20.0
+	chapters := []Chapter{
+		{Title: "Intro", Seconds: 0},
+		{Title: "a=b", Seconds: 10, EndSeconds: &rangeEnd},
+		{Title: "Last", Seconds: 3723.5},
+	}
+
+	const duration = 4000
+
+	tests := []struct {
+		format models.MarkerChapterFormat
+		want   string
+	}{
+		{
+			models.MarkerChapterFormatFfmetadata,
+			";FFMETADATA1\n\n" +
+				"[CHAPTER]\nTIMEBASE=1/1000\nSTART=0\nEND=10000\ntitle=Intro\n\n" +
+				"[CHAPTER]\nTIMEBASE=1/1000\nSTART=10000\nEND=20000\ntitle=a\\=b\n\n" +
+				"[CHAPTER]\nTIMEBASE=1/1000\nSTART=3723500\nEND=4000000\ntitle=Last\n",
+		},
+		{
+			models.MarkerChapterFormatTimestamps,
+			"0:00 Intro\n0:10 a=b\n1:02:03 Last\n",
+		},
+		{
+			models.MarkerChapterFormatVtt,
+			"WEBVTT\n\n" +
+				"Chapter 1\n00:00:00.000 --> 00:00:10.000\nIntro\n\n" +
+				"Chapter 2\n00:00:10.000 --> 00:00:20.000\na=b\n\n" +
+				"Chapter 3\n01:02:03.500 --> 01:06:40.000\nLast\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			got, err := FormatChapters(tt.format, chapters, duration)
+			if err != nil {
+				t.Errorf("FormatChapters() error = %v", err)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// formats with end times round trip
+	for _, format := range []models.MarkerChapterFormat{
+		models.MarkerChapterFormatFfmetadata,
+		models.MarkerChapterFormatVtt,
+		models.MarkerChapterFormatFunscript,
+	} {
+		t.Run(format.String()+" round trip", func(t *testing.T) {
+			data, err := FormatChapters(format, chapters, duration)
+			if err != nil {
+				t.Errorf("FormatChapters() error = %v", err)
+				return
+			}
+
+			got, err := ParseChapters(format, data)
+			if err != nil {
+				t.Errorf("ParseChapters() error = %v", err)
+				return
+			}
+
+			if assert.Len(t, got, len(chapters)) {
+				for i, c := range chapters {
+					assert.Equal(t, c.Title, got[i].Title)
+					assert.InDelta(t, c.Seconds, got[i].Seconds, chapterTimeTolerance)
+					assert.InDelta(t, chapterEnd(chapters, i, duration), *got[i].EndSeconds, chapterTimeTolerance)
+				}
+			}
+		})
+	}
+}
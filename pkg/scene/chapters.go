@@ -0,0 +1,191 @@
+package scene
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// GetMarkerChapterTitle returns the chapter title of a scene marker. If the
+// marker has no title, the primary tag and tag names are used instead.
+func GetMarkerChapterTitle(ctx context.Context, tagReader TagFinder, marker *models.SceneMarker) (string, error) {
+	if marker.Title != "" {
+		return marker.Title, nil
+	}
+
+	primaryTag, err := tagReader.Find(ctx, marker.PrimaryTagID)
+	if err != nil {
+		return "", fmt.Errorf("error getting primary tag for scene marker: %v", err)
+	}
+
+	title := ""
+	if primaryTag != nil {
+		title = primaryTag.Name
+	}
+
+	tags, err := tagReader.FindBySceneMarkerID(ctx, marker.ID)
+	if err != nil {
+		return "", fmt.Errorf("error getting tags for scene marker: %v", err)
+	}
+
+	for _, t := range tags {
+		title += ", " + t.Name
+	}
+
+	return title, nil
+}
+
+// Chapter is a titled section of a scene, as found in chapter files.
+type Chapter struct {
+	Title   string
+	Seconds float64
+	// EndSeconds is nil if the chapter has no explicit end.
+	EndSeconds *float64
+}
+
+// GetMarkerChapters returns the chapters of the markers of the scene, sorted
+// by start time.
+func GetMarkerChapters(ctx context.Context, markerReader models.SceneMarkerFinder, tagReader TagFinder, scene *models.Scene) ([]Chapter, error) {
+	markers, err := markerReader.FindBySceneID(ctx, scene.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scene markers: %v", err)
+	}
+
+	sort.SliceStable(markers, func(i, j int) bool {
+		return markers[i].Seconds < markers[j].Seconds
+	})
+
+	var ret []Chapter
+	for _, marker := range markers {
+		title, err := GetMarkerChapterTitle(ctx, tagReader, marker)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, Chapter{
+			Title:      title,
+			Seconds:    marker.Seconds,
+			EndSeconds: marker.EndSeconds,
+		})
+	}
+
+	return ret, nil
+}
+
+// chapterEnd returns the end of the chapter at index i. Chapters without an
+// explicit end end where the next chapter starts. The last chapter ends at
+// the provided duration.
+func chapterEnd(chapters []Chapter, i int, duration float64) float64 {
+	c := chapters[i]
+
+	end := duration
+	switch {
+	case c.EndSeconds != nil:
+		end = *c.EndSeconds
+	case i+1 < len(chapters):
+		end = chapters[i+1].Seconds
+	}
+
+	// ensure that the chapter has a valid time range
+	if end < c.Seconds {
+		end = c.Seconds
+	}
+
+	return end
+}
+
+// GetMarkerChaptersVTT returns a WebVTT chapters file generated from the
+// markers of the scene. Markers with an end time end there. Other chapters
+// end where the next marker starts, and the last chapter ends at the provided
+// duration. Returns an empty string if the scene has no markers.
+func GetMarkerChaptersVTT(ctx context.Context, markerReader models.SceneMarkerFinder, tagReader TagFinder, scene *models.Scene, duration float64) (string, error) {
+	chapters, err := GetMarkerChapters(ctx, markerReader, tagReader, scene)
+	if err != nil {
+		return "", err
+	}
+
+	if len(chapters) == 0 {
+		return "", nil
+	}
+
+	return FormatChapters(models.MarkerChapterFormatVtt, chapters, duration)
+}
+
+// chapterTimeTolerance is the difference under which chapter times are
+// considered equal, to allow for the precision of chapter formats.
+const chapterTimeTolerance = 0.001
+
+func chapterTimesEqual(a, b float64) bool {
+	return math.Abs(a-b) < chapterTimeTolerance
+}
+
+// ImportMarkerChapters creates markers on the scene from the provided
+// chapters, with the provided primary tag and tags. Chapter ends that are
+// implied, because they match the start of the next chapter or the end of the
+// scene, are not stored. Chapters starting at the same time as an existing
+// marker of the scene are skipped. Titles are not compared, since exported
+// chapters of untitled markers are titled with their tag names. Returns the
+// created markers.
+func ImportMarkerChapters(ctx context.Context, qb MarkerCreatorUpdater, scene *models.Scene, chapters []Chapter, duration float64, primaryTagID int, tagIDs []int) ([]*models.SceneMarker, error) {
+	existing, err := qb.FindBySceneID(ctx, scene.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scene markers: %v", err)
+	}
+
+	chapters = append([]Chapter(nil), chapters...)
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Seconds < chapters[j].Seconds
+	})
+
+	tagIDs = sliceutil.Exclude(tagIDs, []int{primaryTagID})
+
+	var ret []*models.SceneMarker
+	for i, c := range chapters {
+		exists := false
+		for _, m := range existing {
+			if chapterTimesEqual(m.Seconds, c.Seconds) {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+
+		end := c.EndSeconds
+		if end != nil {
+			invalid := *end <= c.Seconds
+			nextStart := i+1 < len(chapters) && chapterTimesEqual(*end, chapters[i+1].Seconds)
+			sceneEnd := duration > 0 && *end >= duration-chapterTimeTolerance
+			if invalid || nextStart || sceneEnd {
+				end = nil
+			}
+		}
+
+		marker := models.NewSceneMarker()
+		marker.Title = c.Title
+		marker.Seconds = c.Seconds
+		marker.EndSeconds = end
+		marker.SceneID = scene.ID
+		marker.PrimaryTagID = primaryTagID
+
+		if err := qb.Create(ctx, &marker); err != nil {
+			return nil, fmt.Errorf("error creating marker at %s: %v", getTimestamp(c.Seconds), err)
+		}
+
+		if len(tagIDs) > 0 {
+			if err := qb.UpdateTags(ctx, marker.ID, tagIDs); err != nil {
+				return nil, fmt.Errorf("error setting marker tags: %v", err)
+			}
+		}
+
+		existing = append(existing, &marker)
+		ret = append(ret, &marker)
+	}
+
+	return ret, nil
+}
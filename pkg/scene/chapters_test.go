@@ -0,0 +1,208 @@
+package scene
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetMarkerChaptersVTT(t *testing.T) {
+	const (
+		chaptersSceneID = iota + 1
+		noChaptersSceneID
+		errChaptersSceneID
+		rangeChaptersSceneID
+	)
+
+	const (
+		titledMarkerID = iota + 1
+		untitledMarkerID
+	)
+
+	const (
+		primaryTagID = iota + 1
+		otherTagID
+	)
+
+	markers := []*models.SceneMarker{
+		{
+			ID:           untitledMarkerID,
+			PrimaryTagID: primaryTagID,
+			Seconds:      90.5,
+		},
+		{
+			ID:           titledMarkerID,
+			Title:        "Intro",
+			PrimaryTagID: otherTagID,
+			Seconds:      0,
+		},
+	}
+
+	rangeEnd := 20.0
+	rangeMarkers := []*models.SceneMarker{
+		{
+			Title:      "Range",
+			Seconds:    10,
+			EndSeconds: &rangeEnd,
+		},
+		{
+			Title:   "After",
+			Seconds: 30,
+		},
+	}
+
+	db := mocks.NewDatabase()
+
+	markersErr := errors.New("error getting scene markers")
+
+	db.SceneMarker.On("FindBySceneID", testCtx, chaptersSceneID).Return(markers, nil).Once()
+	db.SceneMarker.On("FindBySceneID", testCtx, noChaptersSceneID).Return(nil, nil).Once()
+	db.SceneMarker.On("FindBySceneID", testCtx, errChaptersSceneID).Return(nil, markersErr).Once()
+	db.SceneMarker.On("FindBySceneID", testCtx, rangeChaptersSceneID).Return(rangeMarkers, nil).Once()
+
+	db.Tag.On("Find", testCtx, primaryTagID).Return(&models.Tag{
+		Name: "Primary",
+	}, nil).Once()
+	db.Tag.On("FindBySceneMarkerID", testCtx, untitledMarkerID).Return([]*models.Tag{
+		{
+			Name: "Other",
+		},
+	}, nil).Once()
+
+	tests := []struct {
+		name    string
+		sceneID int
+		want    string
+		wantErr bool
+	}{
+		{
+			"markers",
+			chaptersSceneID,
+			"WEBVTT\n\n" +
+				"Chapter 1\n00:00:00.000 --> 00:01:30.500\nIntro\n\n" +
+				"Chapter 2\n00:01:30.500 --> 00:10:00.000\nPrimary, Other\n",
+			false,
+		},
+		{
+			"marker ranges",
+			rangeChaptersSceneID,
+			"WEBVTT\n\n" +
+				"Chapter 1\n00:00:10.000 --> 00:00:20.000\nRange\n\n" +
+				"Chapter 2\n00:00:30.000 --> 00:10:00.000\nAfter\n",
+			false,
+		},
+		{
+			"no markers",
+			noChaptersSceneID,
+			"",
+			false,
+		},
+		{
+			"error getting markers",
+			errChaptersSceneID,
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMarkerChaptersVTT(testCtx, db.SceneMarker, db.Tag, &models.Scene{ID: tt.sceneID}, 600)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMarkerChaptersVTT() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	db.AssertExpectations(t)
+}
+
+func TestImportMarkerChapters(t *testing.T) {
+	const (
+		sceneID      = 1
+		primaryTagID = 2
+		otherTagID   = 3
+		duration     = 600
+	)
+
+	existingEnd := 40.0
+	existing := []*models.SceneMarker{
+		{
+			ID:         10,
+			Title:      "Existing",
+			Seconds:    30,
+			EndSeconds: &existingEnd,
+		},
+		{
+			// untitled markers are exported with their tag names
+			ID:      11,
+			Seconds: 50,
+		},
+	}
+
+	end := func(v float64) *float64 {
+		return &v
+	}
+
+	chapters := []Chapter{
+		{Title: "Last", Seconds: 300, EndSeconds: end(duration)},
+		{Title: "Intro", Seconds: 0, EndSeconds: end(30)},
+		{Title: "Existing", Seconds: 30.0001, EndSeconds: end(40)},
+		{Title: "Primary Tag", Seconds: 50},
+		{Title: "Range", Seconds: 100, EndSeconds: end(120)},
+		{Title: "Invalid", Seconds: 200, EndSeconds: end(150)},
+	}
+
+	db := mocks.NewDatabase()
+
+	db.SceneMarker.On("FindBySceneID", testCtx, sceneID).Return(existing, nil).Once()
+
+	var created []models.SceneMarker
+	nextID := 100
+	db.SceneMarker.On("Create", testCtx, mock.AnythingOfType("*models.SceneMarker")).Run(func(args mock.Arguments) {
+		m := args.Get(1).(*models.SceneMarker)
+		m.ID = nextID
+		nextID++
+		created = append(created, *m)
+	}).Return(nil)
+	db.SceneMarker.On("UpdateTags", testCtx, mock.AnythingOfType("int"), []int{otherTagID}).Return(nil)
+
+	got, err := ImportMarkerChapters(testCtx, db.SceneMarker, &models.Scene{ID: sceneID}, chapters, duration, primaryTagID, []int{primaryTagID, otherTagID})
+	if err != nil {
+		t.Errorf("ImportMarkerChapters() error = %v", err)
+		return
+	}
+
+	type result struct {
+		title   string
+		seconds float64
+		end     *float64
+	}
+
+	var results []result
+	for _, m := range created {
+		assert.Equal(t, sceneID, m.SceneID)
+		assert.Equal(t, primaryTagID, m.PrimaryTagID)
+		results = append(results, result{m.Title, m.Seconds, m.EndSeconds})
+	}
+
+	assert.Len(t, got, 4)
+	assert.Equal(t, []result{
+		// end matches the start of the next chapter
+		{"Intro", 0, nil},
+		{"Range", 100, end(120)},
+		// end before start
+		{"Invalid", 200, nil},
+		// end matches the scene duration
+		{"Last", 300, nil},
+	}, results)
+
+	db.AssertExpectations(t)
+}
@@ -37,6 +37,24 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		}
 	}
 
+	captionsFolder := d.Paths.Scene.GetCaptionsFolder(sceneHash)
+
+	exists, _ = fsutil.FileExists(captionsFolder)
+	if exists {
+		if err := d.Dirs([]string{captionsFolder}); err != nil {
+			return err
+		}
+	}
+
+	framesFolder := d.Paths.Scene.GetFramesFolder(sceneHash)
+
+	exists, _ = fsutil.FileExists(framesFolder)
+	if exists {
+		if err := d.Dirs([]string{framesFolder}); err != nil {
+			return err
+		}
+	}
+
 	var files []string
 
 	streamPreviewPath := d.Paths.Scene.GetVideoPreviewPath(sceneHash)
@@ -51,6 +69,12 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		files = append(files, streamPreviewImagePath)
 	}
 
+	streamPreviewAvifPath := d.Paths.Scene.GetAvifPreviewPath(sceneHash)
+	exists, _ = fsutil.FileExists(streamPreviewAvifPath)
+	if exists {
+		files = append(files, streamPreviewAvifPath)
+	}
+
 	transcodePath := d.Paths.Scene.GetTranscodePath(sceneHash)
 	exists, _ = fsutil.FileExists(transcodePath)
 	if exists {
@@ -75,31 +99,34 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		files = append(files, heatmapPath)
 	}
 
+	waveformPath := d.Paths.Scene.GetWaveformPath(sceneHash)
+	exists, _ = fsutil.FileExists(waveformPath)
+	if exists {
+		files = append(files, waveformPath)
+	}
+
 	return d.Files(files)
 }
 
 // MarkMarkerFiles deletes generated files for a scene marker with the
 // provided scene and timestamp.
 func (d *FileDeleter) MarkMarkerFiles(scene *models.Scene, seconds int) error {
-	videoPath := d.Paths.SceneMarkers.GetVideoPreviewPath(scene.GetHash(d.FileNamingAlgo), seconds)
-	imagePath := d.Paths.SceneMarkers.GetWebpPreviewPath(scene.GetHash(d.FileNamingAlgo), seconds)
-	screenshotPath := d.Paths.SceneMarkers.GetScreenshotPath(scene.GetHash(d.FileNamingAlgo), seconds)
-
-	var files []string
-
-	exists, _ := fsutil.FileExists(videoPath)
-	if exists {
-		files = append(files, videoPath)
+	hash := scene.GetHash(d.FileNamingAlgo)
+	paths := []string{
+		d.Paths.SceneMarkers.GetVideoPreviewPath(hash, seconds),
+		d.Paths.SceneMarkers.GetWebpPreviewPath(hash, seconds),
+		d.Paths.SceneMarkers.GetScreenshotPath(hash, seconds),
+		d.Paths.SceneMarkers.GetWebpFormatPreviewPath(hash, seconds),
+		d.Paths.SceneMarkers.GetStaticFormatPreviewPath(hash, seconds),
 	}
 
-	exists, _ = fsutil.FileExists(imagePath)
-	if exists {
-		files = append(files, imagePath)
-	}
+	var files []string
 
-	exists, _ = fsutil.FileExists(screenshotPath)
-	if exists {
-		files = append(files, screenshotPath)
+	for _, p := range paths {
+		exists, _ := fsutil.FileExists(p)
+		if exists {
+			files = append(files, p)
+		}
 	}
 
 	return d.Files(files)
@@ -168,7 +195,7 @@ func (s *Service) deleteFiles(ctx context.Context, scene *models.Scene, fileDele
 			funscriptPath := video.GetFunscriptPath(f.Path)
 			funscriptExists, _ := fsutil.FileExists(funscriptPath)
 			if funscriptExists {
-				if err := fileDeleter.Files([]string{funscriptPath}); err != nil {
+				if err := fileDeleter.TrashFiles([]string{funscriptPath}); err != nil {
 					return err
 				}
 			}
@@ -0,0 +1,152 @@
+package scene
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// DuplicateRule is a rule used to choose which of a group of duplicate scenes
+// to keep.
+type DuplicateRule string
+
+const (
+	// DuplicateRulePreferredPath keeps the scene in the earliest of the
+	// preferred paths.
+	DuplicateRulePreferredPath DuplicateRule = "PREFERRED_PATH"
+	// DuplicateRuleResolution keeps the scene with the highest resolution.
+	DuplicateRuleResolution DuplicateRule = "RESOLUTION"
+	// DuplicateRuleBitrate keeps the scene with the highest bitrate.
+	DuplicateRuleBitrate DuplicateRule = "BITRATE"
+	// DuplicateRuleFileSize keeps the scene with the largest file.
+	DuplicateRuleFileSize DuplicateRule = "FILE_SIZE"
+	// DuplicateRuleDuration keeps the longest scene.
+	DuplicateRuleDuration DuplicateRule = "DURATION"
+	// DuplicateRuleOldest keeps the scene that was added first.
+	DuplicateRuleOldest DuplicateRule = "OLDEST"
+)
+
+var AllDuplicateRule = []DuplicateRule{
+	DuplicateRulePreferredPath,
+	DuplicateRuleResolution,
+	DuplicateRuleBitrate,
+	DuplicateRuleFileSize,
+	DuplicateRuleDuration,
+	DuplicateRuleOldest,
+}
+
+func (e DuplicateRule) IsValid() bool {
+	switch e {
+	case DuplicateRulePreferredPath, DuplicateRuleResolution, DuplicateRuleBitrate, DuplicateRuleFileSize, DuplicateRuleDuration, DuplicateRuleOldest:
+		return true
+	}
+	return false
+}
+
+func (e DuplicateRule) String() string {
+	return string(e)
+}
+
+func (e *DuplicateRule) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DuplicateRule(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DuplicateRule", str)
+	}
+	return nil
+}
+
+func (e DuplicateRule) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// DuplicatePolicy chooses which of a group of duplicate scenes to keep.
+type DuplicatePolicy struct {
+	// Rules are applied in order, with later rules only used to choose
+	// between scenes that are equal under the earlier rules.
+	Rules []DuplicateRule
+	// PreferredPaths are used by DuplicateRulePreferredPath, in order of
+	// preference.
+	PreferredPaths []string
+}
+
+// pathRank returns the index of the first preferred path containing path, or
+// the number of preferred paths if none do.
+func (p DuplicatePolicy) pathRank(path string) int {
+	for i, pp := range p.PreferredPaths {
+		if fsutil.IsPathInDir(pp, path) {
+			return i
+		}
+	}
+
+	return len(p.PreferredPaths)
+}
+
+// compare returns a negative number if a should be kept over b, a positive
+// number if b should be kept over a, or zero if the rule does not choose
+// between them.
+func (p DuplicatePolicy) compare(rule DuplicateRule, a, b *models.Scene) int {
+	af := a.Files.Primary()
+	bf := b.Files.Primary()
+
+	// scenes with files are always kept over those without
+	switch {
+	case af == nil && bf == nil:
+		return 0
+	case af == nil:
+		return 1
+	case bf == nil:
+		return -1
+	}
+
+	desc := func(x, y float64) int {
+		switch {
+		case x > y:
+			return -1
+		case x < y:
+			return 1
+		}
+		return 0
+	}
+
+	switch rule {
+	case DuplicateRulePreferredPath:
+		return p.pathRank(af.Path) - p.pathRank(bf.Path)
+	case DuplicateRuleResolution:
+		return desc(float64(af.Width*af.Height), float64(bf.Width*bf.Height))
+	case DuplicateRuleBitrate:
+		return desc(float64(af.BitRate), float64(bf.BitRate))
+	case DuplicateRuleFileSize:
+		return desc(float64(af.Size), float64(bf.Size))
+	case DuplicateRuleDuration:
+		return desc(af.Duration, bf.Duration)
+	case DuplicateRuleOldest:
+		return -desc(float64(a.CreatedAt.UnixNano()), float64(b.CreatedAt.UnixNano()))
+	}
+
+	return 0
+}
+
+// Sort sorts the scenes so that the scene to keep is first. Scenes that are
+// equal under all rules are sorted by ID. The primary files of the scenes
+// must be loaded.
+func (p DuplicatePolicy) Sort(scenes []*models.Scene) {
+	sort.SliceStable(scenes, func(i, j int) bool {
+		a, b := scenes[i], scenes[j]
+		for _, rule := range p.Rules {
+			if c := p.compare(rule, a, b); c != 0 {
+				return c < 0
+			}
+		}
+
+		return a.ID < b.ID
+	})
+}
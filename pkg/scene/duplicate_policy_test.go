@@ -0,0 +1,77 @@
+package scene
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicatePolicySort(t *testing.T) {
+	now := time.Now()
+
+	makeScene := func(id int, path string, width, height int, bitRate, size int64, createdAt time.Time) *models.Scene {
+		return &models.Scene{
+			ID:        id,
+			CreatedAt: createdAt,
+			Files: models.NewRelatedVideoFiles([]*models.VideoFile{
+				{
+					BaseFile: &models.BaseFile{
+						Path: path,
+						Size: size,
+					},
+					Width:   width,
+					Height:  height,
+					BitRate: bitRate,
+				},
+			}),
+		}
+	}
+
+	keepPath := filepath.Join("library", "keep")
+	otherPath := filepath.Join("library", "other")
+
+	small := makeScene(1, filepath.Join(otherPath, "small.mp4"), 640, 480, 5000, 300, now.Add(-time.Hour))
+	large := makeScene(2, filepath.Join(otherPath, "large.mp4"), 1920, 1080, 2000, 100, now)
+	preferred := makeScene(3, filepath.Join(keepPath, "preferred.mp4"), 640, 480, 1000, 200, now)
+	noFile := &models.Scene{
+		ID:    4,
+		Files: models.NewRelatedVideoFiles(nil),
+	}
+
+	tests := []struct {
+		name   string
+		policy DuplicatePolicy
+		want   []int
+	}{
+		{"no rules", DuplicatePolicy{}, []int{1, 2, 3, 4}},
+		{"resolution", DuplicatePolicy{Rules: []DuplicateRule{DuplicateRuleResolution}}, []int{2, 1, 3, 4}},
+		{"bitrate", DuplicatePolicy{Rules: []DuplicateRule{DuplicateRuleBitrate}}, []int{1, 2, 3, 4}},
+		{"file size", DuplicatePolicy{Rules: []DuplicateRule{DuplicateRuleFileSize}}, []int{1, 3, 2, 4}},
+		{"oldest", DuplicatePolicy{Rules: []DuplicateRule{DuplicateRuleOldest}}, []int{1, 2, 3, 4}},
+		{
+			"preferred path then resolution",
+			DuplicatePolicy{
+				Rules:          []DuplicateRule{DuplicateRulePreferredPath, DuplicateRuleResolution},
+				PreferredPaths: []string{keepPath},
+			},
+			[]int{3, 2, 1, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenes := []*models.Scene{noFile, preferred, large, small}
+			tt.policy.Sort(scenes)
+
+			var got []int
+			for _, s := range scenes {
+				got = append(got, s.ID)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -47,6 +47,8 @@ func ToBasicJSON(ctx context.Context, reader CoverGetter, scene *models.Scene) (
 	}
 
 	newSceneJSON.Organized = scene.Organized
+	newSceneJSON.Favorite = scene.Favorite
+	newSceneJSON.ContentRating = scene.ContentRating
 	newSceneJSON.OCounter = scene.OCounter
 
 	for _, f := range scene.Files.List() {
@@ -194,6 +196,12 @@ func GetSceneMarkersJSON(ctx context.Context, markerReader models.SceneMarkerFin
 		return nil, fmt.Errorf("error getting scene markers: %v", err)
 	}
 
+	return SceneMarkersToJSON(ctx, tagReader, sceneMarkers)
+}
+
+// SceneMarkersToJSON converts the provided scene markers into their JSON
+// representation.
+func SceneMarkersToJSON(ctx context.Context, tagReader TagFinder, sceneMarkers []*models.SceneMarker) ([]jsonschema.SceneMarker, error) {
 	var results []jsonschema.SceneMarker
 
 	for _, sceneMarker := range sceneMarkers {
@@ -214,6 +222,17 @@ func GetSceneMarkersJSON(ctx context.Context, markerReader models.SceneMarkerFin
 			Tags:       getTagNames(sceneMarkerTags),
 			CreatedAt:  json.JSONTime{Time: sceneMarker.CreatedAt},
 			UpdatedAt:  json.JSONTime{Time: sceneMarker.UpdatedAt},
+
+			PreviewDuration: sceneMarker.PreviewDuration,
+			PreviewWidth:    sceneMarker.PreviewWidth,
+		}
+
+		if sceneMarker.EndSeconds != nil {
+			sceneMarkerJSON.EndSeconds = getDecimalString(*sceneMarker.EndSeconds)
+		}
+
+		if sceneMarker.PreviewFormat != nil {
+			sceneMarkerJSON.PreviewFormat = sceneMarker.PreviewFormat.String()
 		}
 
 		results = append(results, sceneMarkerJSON)
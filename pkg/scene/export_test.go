@@ -443,6 +443,15 @@ const (
 
 	markerSeconds1Str = "1.0"
 	markerSeconds2Str = "2.3"
+
+	markerEndSeconds2Str = "4.5"
+)
+
+var (
+	markerEndSeconds2     = 4.5
+	markerPreviewFormat   = models.MarkerPreviewFormatWebp
+	markerPreviewDuration = 7.5
+	markerPreviewWidth    = 320
 )
 
 type sceneMarkersTestScenario struct {
@@ -474,6 +483,7 @@ var getSceneMarkersJSONScenarios = []sceneMarkersTestScenario{
 				Title:      markerTitle2,
 				PrimaryTag: validTagName2,
 				Seconds:    markerSeconds2Str,
+				EndSeconds: markerEndSeconds2Str,
 				Tags: []string{
 					validTagName2,
 				},
@@ -483,6 +493,9 @@ var getSceneMarkersJSONScenarios = []sceneMarkersTestScenario{
 				UpdatedAt: json.JSONTime{
 					Time: updateTime,
 				},
+				PreviewFormat:   markerPreviewFormat.String(),
+				PreviewDuration: &markerPreviewDuration,
+				PreviewWidth:    &markerPreviewWidth,
 			},
 		},
 		false,
@@ -523,8 +536,13 @@ var validMarkers = []*models.SceneMarker{
 		Title:        markerTitle2,
 		PrimaryTagID: validTagID2,
 		Seconds:      markerSeconds2,
+		EndSeconds:   &markerEndSeconds2,
 		CreatedAt:    createTime,
 		UpdatedAt:    updateTime,
+
+		PreviewFormat:   &markerPreviewFormat,
+		PreviewDuration: &markerPreviewDuration,
+		PreviewWidth:    &markerPreviewWidth,
 	},
 }
 
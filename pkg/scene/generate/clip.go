@@ -0,0 +1,139 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type ClipOptions struct {
+	StartTime float64
+	Duration  float64
+
+	// VideoCodec is the codec to re-encode the clip with. The streams of the
+	// input are copied if nil, which is fast but cuts at the nearest
+	// keyframes.
+	VideoCodec *models.ClipVideoCodec
+}
+
+// ClipExtension returns the file extension of a clip of the input path
+// rendered with codec.
+func ClipExtension(input string, codec *models.ClipVideoCodec) string {
+	if codec == nil {
+		return filepath.Ext(input)
+	}
+
+	if *codec == models.ClipVideoCodecVp9 {
+		return ".webm"
+	}
+
+	return ".mp4"
+}
+
+// Clip renders the time range of the input video given by options to
+// output. Returns an error if output already exists.
+func (g Generator) Clip(ctx context.Context, input string, output string, options ClipOptions) error {
+	if exists, _ := fsutil.FileExists(output); exists {
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	pattern := "*" + ClipExtension(input, options.VideoCodec)
+	if err := g.generateFile(lockCtx, g.ScenePaths, pattern, output, g.clip(input, options)); err != nil {
+		return err
+	}
+
+	logger.Debug("created clip: ", output)
+
+	return nil
+}
+
+func (g Generator) clip(input string, options ClipOptions) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		trimOptions := transcoder.TranscodeOptions{
+			OutputPath: tmpFn,
+			StartTime:  options.StartTime,
+			Duration:   options.Duration,
+
+			ExtraInputArgs: g.FFMpegConfig.GetTranscodeInputArgs(),
+		}
+
+		if options.VideoCodec == nil {
+			trimOptions.VideoCodec = ffmpeg.VideoCodecCopy
+			trimOptions.AudioCodec = ffmpeg.AudioCodecCopy
+			trimOptions.ExtraOutputArgs = []string{"-avoid_negative_ts", "make_zero"}
+		} else {
+			if err := g.clipCodecOptions(*options.VideoCodec, &trimOptions); err != nil {
+				return err
+			}
+			trimOptions.ExtraOutputArgs = g.FFMpegConfig.GetTranscodeOutputArgs()
+		}
+
+		args := transcoder.Transcode(input, trimOptions)
+
+		return g.generate(lockCtx, args)
+	}
+}
+
+func (g Generator) clipCodecOptions(codec models.ClipVideoCodec, options *transcoder.TranscodeOptions) error {
+	switch codec {
+	case models.ClipVideoCodecHevc:
+		options.Format = ffmpeg.FormatMP4
+		options.VideoCodec = ffmpeg.VideoCodecLibX265
+		options.VideoArgs = ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-preset", "fast",
+			"-crf", "26",
+			"-tag:v", "hvc1",
+		}
+		options.AudioCodec = ffmpeg.AudioCodecAAC
+	case models.ClipVideoCodecVp9:
+		options.Format = ffmpeg.FormatWebm
+		options.VideoCodec = ffmpeg.VideoCodecVP9
+		options.VideoArgs = ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-crf", "32",
+			"-b:v", "0",
+			"-row-mt", "1",
+		}
+		options.AudioCodec = ffmpeg.AudioCodecLibOpus
+	case models.ClipVideoCodecAv1:
+		if g.Encoder.AV1Codec(false) == nil {
+			return fmt.Errorf("generating AV1 clip: %w", ffmpeg.ErrCodecNotSupported)
+		}
+
+		options.Format = ffmpeg.FormatMP4
+		options.VideoCodec = ffmpeg.VideoCodecSVTAV1
+		options.VideoArgs = ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-preset", "8",
+			"-crf", "32",
+		}
+		options.AudioCodec = ffmpeg.AudioCodecAAC
+	default:
+		options.Format = ffmpeg.FormatMP4
+		options.VideoCodec = ffmpeg.VideoCodecLibX264
+		options.VideoArgs = ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-profile:v", "high",
+			"-level", "4.2",
+			"-preset", "veryfast",
+			"-crf", "21",
+		}
+		options.AudioCodec = ffmpeg.AudioCodecAAC
+	}
+
+	if options.Format == ffmpeg.FormatMP4 {
+		options.VideoArgs = append(options.VideoArgs, "-movflags", "+faststart")
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// coverCandidates is the number of frames sampled when selecting the
+	// best frame for a cover.
+	coverCandidates = 10
+	// candidate frames are sampled between these proportions of the
+	// duration, to avoid intros and credits
+	coverSampleStart = 0.1
+	coverSampleEnd   = 0.8
+	// coverCandidateWidth is the width candidate frames are scored at
+	coverCandidateWidth   = 320
+	coverCandidateQuality = 5
+
+	// pixels with a luma below blackLuma are considered black
+	blackLuma = 24
+	// frames with less than minContentFraction of their area outside
+	// black bars are considered blank
+	minContentFraction = 0.1
+	// the variance of the laplacian at which the sharpness score is half
+	// its maximum
+	sharpnessMidpoint = 100
+)
+
+// coverCandidateTimes returns the times of the frames sampled when
+// selecting the best frame of a video of the given duration.
+func coverCandidateTimes(duration float64) []float64 {
+	if duration <= 0 {
+		return []float64{0}
+	}
+
+	start := coverSampleStart * duration
+	step := (coverSampleEnd - coverSampleStart) * duration / (coverCandidates - 1)
+
+	ret := make([]float64, coverCandidates)
+	for i := range ret {
+		ret[i] = start + float64(i)*step
+	}
+	return ret
+}
+
+// bestFrame samples frames of the input video and returns the time of the
+// frame with the highest score.
+func (g Generator) bestFrame(lockCtx *fsutil.LockContext, input string, videoDuration float64) (float64, error) {
+	best := -1.0
+	var bestAt float64
+
+	for _, at := range coverCandidateTimes(videoDuration) {
+		data, err := g.generateBytes(lockCtx, g.ScenePaths, jpgPattern, g.screenshot(input, screenshotOptions{
+			Time:    at,
+			Width:   coverCandidateWidth,
+			Quality: coverCandidateQuality,
+		}))
+		if err != nil {
+			if lockCtx.Err() != nil {
+				return 0, lockCtx.Err()
+			}
+			logger.Debugf("sampling frame of %s at %.2fs: %v", input, at, err)
+			continue
+		}
+
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			logger.Debugf("decoding frame of %s at %.2fs: %v", input, at, err)
+			continue
+		}
+
+		score := scoreFrame(img)
+		logger.Tracef("frame of %s at %.2fs scored %.3f", input, at, score)
+
+		if score > best {
+			best = score
+			bestAt = at
+		}
+	}
+
+	if best < 0 {
+		return 0, fmt.Errorf("no frames of %s could be sampled", input)
+	}
+
+	return bestAt, nil
+}
+
+// scoreFrame returns a score between 0 and 1 of how suitable img is as a
+// cover. Sharp frames of moderate brightness score highest. Black bars and
+// frames that are mostly black, such as fades and credits, lower the score.
+func scoreFrame(img image.Image) float64 {
+	luma := toLuma(img)
+	h := len(luma)
+	if h == 0 {
+		return 0
+	}
+	w := len(luma[0])
+
+	top, bottom, left, right := contentBounds(luma)
+	contentW := right - left
+	contentH := bottom - top
+	contentFraction := float64(contentW*contentH) / float64(w*h)
+	if contentFraction < minContentFraction {
+		return 0
+	}
+
+	var sum, dark float64
+	for y := top; y < bottom; y++ {
+		for x := left; x < right; x++ {
+			v := luma[y][x]
+			sum += v
+			if v < blackLuma {
+				dark++
+			}
+		}
+	}
+	n := float64(contentW * contentH)
+	mean := sum / n
+	darkFraction := dark / n
+
+	sharpness := laplacianVariance(luma, top, bottom, left, right)
+	sharpnessScore := sharpness / (sharpness + sharpnessMidpoint)
+
+	return sharpnessScore * brightnessScore(mean) * (1 - darkFraction) * contentFraction
+}
+
+// brightnessScore returns 1 for a moderate mean luma, falling to 0 for
+// very dark or very bright frames.
+func brightnessScore(mean float64) float64 {
+	const (
+		low      = 16
+		lowFull  = 70
+		highFull = 180
+		high     = 240
+	)
+
+	switch {
+	case mean <= low || mean >= high:
+		return 0
+	case mean < lowFull:
+		return (mean - low) / (lowFull - low)
+	case mean > highFull:
+		return (high - mean) / (high - highFull)
+	default:
+		return 1
+	}
+}
+
+// toLuma returns the luma of each pixel of img, indexed by row then column.
+func toLuma(img image.Image) [][]float64 {
+	b := img.Bounds()
+	ret := make([][]float64, b.Dy())
+	for y := range ret {
+		row := make([]float64, b.Dx())
+		for x := range row {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			// RGBA returns 16-bit values
+			row[x] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+		}
+		ret[y] = row
+	}
+	return ret
+}
+
+// contentBounds returns the bounds of luma inside any black bars. The
+// bottom and right bounds are exclusive.
+func contentBounds(luma [][]float64) (top, bottom, left, right int) {
+	h := len(luma)
+	w := len(luma[0])
+
+	rowBlack := func(y int) bool {
+		var sum float64
+		for x := 0; x < w; x++ {
+			sum += luma[y][x]
+		}
+		return sum/float64(w) < blackLuma
+	}
+	colBlack := func(x int) bool {
+		var sum float64
+		for y := top; y < bottom; y++ {
+			sum += luma[y][x]
+		}
+		return sum/float64(bottom-top) < blackLuma
+	}
+
+	bottom = h
+	for top < bottom && rowBlack(top) {
+		top++
+	}
+	for bottom > top && rowBlack(bottom-1) {
+		bottom--
+	}
+	if top == bottom {
+		return top, bottom, 0, 0
+	}
+
+	right = w
+	for left < right && colBlack(left) {
+		left++
+	}
+	for right > left && colBlack(right-1) {
+		right--
+	}
+
+	return top, bottom, left, right
+}
+
+// laplacianVariance returns the variance of the laplacian of luma within
+// the given bounds, which is higher for sharper images.
+func laplacianVariance(luma [][]float64, top, bottom, left, right int) float64 {
+	var sum, sumSq, n float64
+	for y := top + 1; y < bottom-1; y++ {
+		for x := left + 1; x < right-1; x++ {
+			l := luma[y-1][x] + luma[y+1][x] + luma[y][x-1] + luma[y][x+1] - 4*luma[y][x]
+			sum += l
+			sumSq += l * l
+			n++
+		}
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	mean := sum / n
+	return math.Max(0, sumSq/n-mean*mean)
+}
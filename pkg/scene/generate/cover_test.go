@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+const testFrameSize = 64
+
+// testFrame returns a frame with a checkerboard of the given luma values
+// within the rows [top, bottom), and black elsewhere.
+func testFrame(lo, hi uint8, top, bottom int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, testFrameSize, testFrameSize))
+	for y := top; y < bottom; y++ {
+		for x := 0; x < testFrameSize; x++ {
+			v := lo
+			if (x/4+y/4)%2 == 0 {
+				v = hi
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+// creditsFrame returns a black frame with a line of white text.
+func creditsFrame() image.Image {
+	img := image.NewGray(image.Rect(0, 0, testFrameSize, testFrameSize))
+	for x := 16; x < 48; x += 2 {
+		img.SetGray(x, 32, color.Gray{Y: 255})
+	}
+	return img
+}
+
+func Test_scoreFrame_better(t *testing.T) {
+	detailed := testFrame(60, 200, 0, testFrameSize)
+
+	tests := []struct {
+		name   string
+		better image.Image
+		worse  image.Image
+	}{
+		{"letterboxed", detailed, testFrame(60, 200, 16, 48)},
+		{"low contrast", detailed, testFrame(124, 132, 0, testFrameSize)},
+		{"dark", detailed, testFrame(20, 40, 0, testFrameSize)},
+		{"bright", detailed, testFrame(220, 250, 0, testFrameSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better := scoreFrame(tt.better)
+			worse := scoreFrame(tt.worse)
+			if better <= worse {
+				t.Errorf("scoreFrame() = %v, want greater than %v", better, worse)
+			}
+		})
+	}
+}
+
+func Test_scoreFrame_blank(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{"black", testFrame(0, 0, 0, testFrameSize)},
+		{"flat", testFrame(128, 128, 0, testFrameSize)},
+		{"credits", creditsFrame()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreFrame(tt.img); got != 0 {
+				t.Errorf("scoreFrame() = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func Test_coverCandidateTimes(t *testing.T) {
+	got := coverCandidateTimes(100)
+	if len(got) != coverCandidates {
+		t.Fatalf("len(coverCandidateTimes()) = %d, want %d", len(got), coverCandidates)
+	}
+	if got[0] != 10 || got[len(got)-1] != 80 {
+		t.Errorf("coverCandidateTimes() = %v, want 10 to 80", got)
+	}
+}
@@ -15,7 +15,9 @@ import (
 const (
 	mp4Pattern  = "*.mp4"
 	webpPattern = "*.webp"
+	avifPattern = "*.avif"
 	jpgPattern  = "*.jpg"
+	pngPattern  = "*.png"
 	txtPattern  = "*.txt"
 	vttPattern  = "*.vtt"
 )
@@ -30,6 +32,8 @@ type MarkerPaths interface {
 	GetVideoPreviewPath(checksum string, seconds int) string
 	GetWebpPreviewPath(checksum string, seconds int) string
 	GetScreenshotPath(checksum string, seconds int) string
+	GetWebpFormatPreviewPath(checksum string, seconds int) string
+	GetStaticFormatPreviewPath(checksum string, seconds int) string
 }
 
 type ScenePaths interface {
@@ -37,16 +41,21 @@ type ScenePaths interface {
 
 	GetVideoPreviewPath(checksum string) string
 	GetWebpPreviewPath(checksum string) string
+	GetAvifPreviewPath(checksum string) string
 
 	GetSpriteImageFilePath(checksum string) string
 	GetSpriteVttFilePath(checksum string) string
 
 	GetTranscodePath(checksum string) string
+
+	GetWaveformPath(checksum string) string
 }
 
 type FFMpegConfig interface {
 	GetTranscodeInputArgs() []string
 	GetTranscodeOutputArgs() []string
+	GetTranscodeHardwareAcceleration() bool
+	GetHDRToneMapping() bool
 }
 
 type Generator struct {
@@ -60,6 +69,17 @@ type Generator struct {
 
 type generateFn func(lockCtx *fsutil.LockContext, tmpFn string) error
 
+// toneMapFilter returns the filter tone mapping the input video to SDR if hdr
+// is true. Returns an empty filter if tone mapping is disabled or not
+// supported by ffmpeg.
+func (g Generator) toneMapFilter(hdr bool) ffmpeg.VideoFilter {
+	if !hdr || !g.FFMpegConfig.GetHDRToneMapping() {
+		return ""
+	}
+
+	return g.Encoder.ToneMapFilter()
+}
+
 func (g Generator) tempFile(p Paths, pattern string) (*os.File, error) {
 	tmpFile, err := p.TempFile(pattern) // tmp output in case the process ends abruptly
 	if err != nil {
@@ -20,7 +20,37 @@ const (
 	markerScreenshotQuality = 2
 )
 
-func (g Generator) MarkerPreviewVideo(ctx context.Context, input string, hash string, seconds int, includeAudio bool) error {
+// MarkerPreviewOptions are the options of an animated marker preview.
+type MarkerPreviewOptions struct {
+	// Duration of the preview, in seconds. Defaults to 20 seconds for videos
+	// and 5 seconds for animated images if zero.
+	Duration float64
+	// Width of the preview, in pixels. Defaults to 640 if zero.
+	Width int
+	// MaxDuration limits the duration of the preview, in seconds. Used to
+	// keep the preview of a marker range within the range. Ignored if zero.
+	MaxDuration float64
+}
+
+func (o MarkerPreviewOptions) duration(def float64) float64 {
+	ret := def
+	if o.Duration > 0 {
+		ret = o.Duration
+	}
+	if o.MaxDuration > 0 && o.MaxDuration < ret {
+		ret = o.MaxDuration
+	}
+	return ret
+}
+
+func (o MarkerPreviewOptions) width() int {
+	if o.Width > 0 {
+		return o.Width
+	}
+	return markerPreviewWidth
+}
+
+func (g Generator) MarkerPreviewVideo(ctx context.Context, input string, hash string, seconds int, options MarkerPreviewOptions, includeAudio bool) error {
 	lockCtx := g.LockManager.ReadLock(ctx, input)
 	defer lockCtx.Cancel()
 
@@ -32,8 +62,10 @@ func (g Generator) MarkerPreviewVideo(ctx context.Context, input string, hash st
 	}
 
 	if err := g.generateFile(lockCtx, g.MarkerPaths, mp4Pattern, output, g.markerPreviewVideo(input, sceneMarkerOptions{
-		Seconds: seconds,
-		Audio:   includeAudio,
+		Seconds:  seconds,
+		Duration: options.duration(markerPreviewDuration),
+		Width:    options.width(),
+		Audio:    includeAudio,
 	})); err != nil {
 		return err
 	}
@@ -44,32 +76,33 @@ func (g Generator) MarkerPreviewVideo(ctx context.Context, input string, hash st
 }
 
 type sceneMarkerOptions struct {
-	Seconds int
-	Audio   bool
+	Seconds  int
+	Duration float64
+	Width    int
+	Audio    bool
 }
 
 func (g Generator) markerPreviewVideo(input string, options sceneMarkerOptions) generateFn {
 	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
 		var videoFilter ffmpeg.VideoFilter
-		videoFilter = videoFilter.ScaleWidth(markerPreviewWidth)
+		videoFilter = videoFilter.ScaleWidth(options.Width)
 
 		var videoArgs ffmpeg.Args
 		videoArgs = videoArgs.VideoFilter(videoFilter)
 
 		videoArgs = append(videoArgs,
-			"-pix_fmt", "yuv420p",
-			"-profile:v", "high",
-			"-level", "4.2",
 			"-preset", "veryslow",
 			"-crf", "24",
-			"-movflags", "+faststart",
 			"-threads", "4",
 			"-sws_flags", "lanczos",
 			"-strict", "-2",
 		)
+		videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(ffmpeg.VideoCodecLibX264)...)
+		// required for playback before the preview has fully loaded
+		videoArgs = append(videoArgs, "-movflags", "+faststart")
 
 		trimOptions := transcoder.TranscodeOptions{
-			Duration:   markerPreviewDuration,
+			Duration:   options.Duration,
 			StartTime:  float64(options.Seconds),
 			OutputPath: tmpFn,
 			VideoCodec: ffmpeg.VideoCodecLibX264,
@@ -90,11 +123,20 @@ func (g Generator) markerPreviewVideo(input string, options sceneMarkerOptions)
 	}
 }
 
-func (g Generator) SceneMarkerWebp(ctx context.Context, input string, hash string, seconds int) error {
+func (g Generator) SceneMarkerWebp(ctx context.Context, input string, hash string, seconds int, options MarkerPreviewOptions) error {
+	return g.markerWebpFile(ctx, input, g.MarkerPaths.GetWebpPreviewPath(hash, seconds), seconds, options)
+}
+
+// MarkerPreviewWebp generates the preview of a marker with the WEBP preview
+// format.
+func (g Generator) MarkerPreviewWebp(ctx context.Context, input string, hash string, seconds int, options MarkerPreviewOptions) error {
+	return g.markerWebpFile(ctx, input, g.MarkerPaths.GetWebpFormatPreviewPath(hash, seconds), seconds, options)
+}
+
+func (g Generator) markerWebpFile(ctx context.Context, input string, output string, seconds int, options MarkerPreviewOptions) error {
 	lockCtx := g.LockManager.ReadLock(ctx, input)
 	defer lockCtx.Cancel()
 
-	output := g.MarkerPaths.GetWebpPreviewPath(hash, seconds)
 	if !g.Overwrite {
 		if exists, _ := fsutil.FileExists(output); exists {
 			return nil
@@ -102,7 +144,9 @@ func (g Generator) SceneMarkerWebp(ctx context.Context, input string, hash strin
 	}
 
 	if err := g.generateFile(lockCtx, g.MarkerPaths, webpPattern, output, g.sceneMarkerWebp(input, sceneMarkerOptions{
-		Seconds: seconds,
+		Seconds:  seconds,
+		Duration: options.duration(markerImageDuration),
+		Width:    options.width(),
 	})); err != nil {
 		return err
 	}
@@ -115,7 +159,7 @@ func (g Generator) SceneMarkerWebp(ctx context.Context, input string, hash strin
 func (g Generator) sceneMarkerWebp(input string, options sceneMarkerOptions) generateFn {
 	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
 		var videoFilter ffmpeg.VideoFilter
-		videoFilter = videoFilter.ScaleWidth(markerPreviewWidth)
+		videoFilter = videoFilter.ScaleWidth(options.Width)
 		videoFilter = videoFilter.Fps(markerWebpFPS)
 
 		var videoArgs ffmpeg.Args
@@ -130,7 +174,7 @@ func (g Generator) sceneMarkerWebp(input string, options sceneMarkerOptions) gen
 		)
 
 		trimOptions := transcoder.TranscodeOptions{
-			Duration:   markerImageDuration,
+			Duration:   options.Duration,
 			StartTime:  float64(options.Seconds),
 			OutputPath: tmpFn,
 			VideoCodec: ffmpeg.VideoCodecLibWebP,
@@ -144,10 +188,19 @@ func (g Generator) sceneMarkerWebp(input string, options sceneMarkerOptions) gen
 }
 
 func (g Generator) SceneMarkerScreenshot(ctx context.Context, input string, hash string, seconds int, width int) error {
+	return g.markerScreenshotFile(ctx, input, g.MarkerPaths.GetScreenshotPath(hash, seconds), seconds, width)
+}
+
+// MarkerPreviewStatic generates the preview of a marker with the STATIC
+// preview format.
+func (g Generator) MarkerPreviewStatic(ctx context.Context, input string, hash string, seconds int, width int) error {
+	return g.markerScreenshotFile(ctx, input, g.MarkerPaths.GetStaticFormatPreviewPath(hash, seconds), seconds, width)
+}
+
+func (g Generator) markerScreenshotFile(ctx context.Context, input string, output string, seconds int, width int) error {
 	lockCtx := g.LockManager.ReadLock(ctx, input)
 	defer lockCtx.Cancel()
 
-	output := g.MarkerPaths.GetScreenshotPath(hash, seconds)
 	if !g.Overwrite {
 		if exists, _ := fsutil.FileExists(output); exists {
 			return nil
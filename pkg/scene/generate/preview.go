@@ -3,6 +3,7 @@ package generate
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 const (
@@ -33,6 +35,13 @@ type PreviewOptions struct {
 	Preset string
 
 	Audio bool
+
+	ImageFormat models.PreviewImageFormat
+	VideoCodec  models.PreviewVideoCodec
+
+	// HDR is true if the input video is HDR, in which case the preview is
+	// tone mapped to SDR.
+	HDR bool
 }
 
 func getExcludeValue(videoDuration float64, v string) float64 {
@@ -47,6 +56,60 @@ func getExcludeValue(videoDuration float64, v string) float64 {
 	return prop
 }
 
+// ErrExcludesCoverVideo is returned when the excluded start and end of a
+// preview leave nothing of the video to preview.
+var ErrExcludesCoverVideo = errors.New("excluded start and end of the preview cover the whole video")
+
+// parseExcludeValue parses an excluded duration, which is either a number of
+// seconds or a percentage of the video duration. An empty value excludes
+// nothing.
+func parseExcludeValue(v string) (value float64, proportion bool, err error) {
+	if v == "" {
+		return 0, false, nil
+	}
+
+	n := v
+	if strings.HasSuffix(v, "%") {
+		proportion = true
+		n = v[0 : len(v)-1]
+	}
+
+	value, err = strconv.ParseFloat(n, 64)
+	if err != nil || value < 0 {
+		return 0, false, fmt.Errorf("invalid excluded duration %q", v)
+	}
+
+	return value, proportion, nil
+}
+
+// ValidateExcludes returns an error if excludeStart or excludeEnd is not a
+// valid excluded duration, or if the end of the excluded start is not before
+// the start of the excluded end. Durations in seconds are only compared if
+// videoDuration is positive.
+func ValidateExcludes(videoDuration float64, excludeStart string, excludeEnd string) error {
+	start, startProp, err := parseExcludeValue(excludeStart)
+	if err != nil {
+		return err
+	}
+	end, endProp, err := parseExcludeValue(excludeEnd)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case startProp && endProp:
+		if start+end >= 100 {
+			return ErrExcludesCoverVideo
+		}
+	case videoDuration > 0:
+		if getExcludeValue(videoDuration, excludeStart)+getExcludeValue(videoDuration, excludeEnd) >= videoDuration {
+			return ErrExcludesCoverVideo
+		}
+	}
+
+	return nil
+}
+
 // getStepSizeAndOffset calculates the step size for preview generation and
 // the starting offset.
 //
@@ -129,6 +192,8 @@ func (g *Generator) previewVideo(input string, videoDuration float64, options Pr
 				OutputPath: chunkFile.Name(),
 				Audio:      options.Audio,
 				Preset:     options.Preset,
+				VideoCodec: options.VideoCodec,
+				HDR:        options.HDR,
 			}
 
 			if err := g.previewVideoChunk(lockCtx, input, chunkOptions, fallback, useVsync2); err != nil {
@@ -158,6 +223,8 @@ func (g *Generator) previewVideoSingle(input string, videoDuration float64, opti
 			OutputPath: tmpFn,
 			Audio:      options.Audio,
 			Preset:     options.Preset,
+			VideoCodec: options.VideoCodec,
+			HDR:        options.HDR,
 		}
 
 		return g.previewVideoChunk(lockCtx, input, chunkOptions, fallback, useVsync2)
@@ -170,24 +237,54 @@ type previewChunkOptions struct {
 	OutputPath string
 	Audio      bool
 	Preset     string
+	VideoCodec models.PreviewVideoCodec
+	HDR        bool
 }
 
 func (g Generator) previewVideoChunk(lockCtx *fsutil.LockContext, fn string, options previewChunkOptions, fallback bool, useVsync2 bool) error {
-	var videoFilter ffmpeg.VideoFilter
+	videoFilter := g.toneMapFilter(options.HDR)
+	// tone mapping is done in software, so the hardware codecs can't be used
+	toneMap := videoFilter != ""
 	videoFilter = videoFilter.ScaleWidth(scenePreviewWidth)
 
 	var videoArgs ffmpeg.Args
 	videoArgs = videoArgs.VideoFilter(videoFilter)
 
-	videoArgs = append(videoArgs,
-		"-pix_fmt", "yuv420p",
-		"-profile:v", "high",
-		"-level", "4.2",
-		"-preset", options.Preset,
-		"-crf", "21",
-		"-threads", "4",
-		"-strict", "-2",
-	)
+	codec := ffmpeg.VideoCodecLibX264
+	var hwCodec *ffmpeg.VideoCodec
+	useHW := g.FFMpegConfig.GetTranscodeHardwareAcceleration() && !toneMap
+
+	if options.VideoCodec == models.PreviewVideoCodecAv1 {
+		if useHW {
+			hwCodec = g.Encoder.HWCodecAV1Compatible()
+		}
+
+		if hwCodec == nil && g.Encoder.AV1Codec(false) == nil {
+			return fmt.Errorf("generating AV1 preview: %w", ffmpeg.ErrCodecNotSupported)
+		}
+
+		codec = ffmpeg.VideoCodecSVTAV1
+		videoArgs = append(videoArgs,
+			"-preset", "8",
+			"-crf", "35",
+			"-threads", "4",
+		)
+		videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(codec)...)
+	} else {
+		if useHW {
+			hwCodec = g.Encoder.HWCodecMP4Compatible()
+		}
+
+		videoArgs = append(videoArgs,
+			"-crf", "21",
+			"-threads", "4",
+			"-strict", "-2",
+		)
+		videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(codec)...)
+		// the configured preview preset takes precedence over the encoder
+		// options
+		videoArgs = append(videoArgs, "-preset", options.Preset)
+	}
 
 	if useVsync2 {
 		videoArgs = append(videoArgs, "-vsync", "2")
@@ -201,7 +298,7 @@ func (g Generator) previewVideoChunk(lockCtx *fsutil.LockContext, fn string, opt
 		XError:   !fallback,
 		SlowSeek: fallback,
 
-		VideoCodec: ffmpeg.VideoCodecLibX264,
+		VideoCodec: codec,
 		VideoArgs:  videoArgs,
 
 		ExtraInputArgs:  g.FFMpegConfig.GetTranscodeInputArgs(),
@@ -216,6 +313,37 @@ func (g Generator) previewVideoChunk(lockCtx *fsutil.LockContext, fn string, opt
 		trimOptions.AudioArgs = audioArgs
 	}
 
+	if hwCodec != nil {
+		err := g.previewVideoChunkHW(lockCtx, fn, trimOptions, *hwCodec, useVsync2)
+		if err == nil || lockCtx.Err() != nil {
+			return err
+		}
+
+		logger.Warnf("[generator] hardware encoding with %s failed, falling back to software encoding: %v", *hwCodec, err)
+	}
+
+	args := transcoder.Transcode(fn, trimOptions)
+
+	return g.generate(lockCtx, args)
+}
+
+// previewVideoChunkHW generates a preview chunk using the hardware codec,
+// replacing the software video options in trimOptions.
+func (g Generator) previewVideoChunkHW(lockCtx *fsutil.LockContext, fn string, trimOptions transcoder.TranscodeOptions, codec ffmpeg.VideoCodec, useVsync2 bool) error {
+	videoFilter := g.Encoder.HWScaleWidthFilter(codec, scenePreviewWidth)
+
+	var videoArgs ffmpeg.Args
+	videoArgs = videoArgs.VideoFilter(videoFilter)
+	videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(codec)...)
+
+	if useVsync2 {
+		videoArgs = append(videoArgs, "-vsync", "2")
+	}
+
+	trimOptions.VideoCodec = codec
+	trimOptions.VideoArgs = videoArgs
+	trimOptions.ExtraInputArgs = append(g.Encoder.HWDeviceArgs(codec), trimOptions.ExtraInputArgs...)
+
 	args := transcoder.Transcode(fn, trimOptions)
 
 	return g.generate(lockCtx, args)
@@ -257,24 +385,31 @@ func removeFiles(list []string) {
 	}
 }
 
-// PreviewWebp generates a webp file based on the preview video input.
-// TODO - this should really generate a new webp using chunks.
-func (g Generator) PreviewWebp(ctx context.Context, input string, hash string) error {
+// PreviewImage generates an animated image in format based on the preview
+// video input.
+// TODO - this should really generate a new image using chunks.
+func (g Generator) PreviewImage(ctx context.Context, input string, hash string, format models.PreviewImageFormat) error {
 	lockCtx := g.LockManager.ReadLock(ctx, input)
 	defer lockCtx.Cancel()
 
 	output := g.ScenePaths.GetWebpPreviewPath(hash)
+	pattern := webpPattern
+	if format == models.PreviewImageFormatAvif {
+		output = g.ScenePaths.GetAvifPreviewPath(hash)
+		pattern = avifPattern
+	}
+
 	if !g.Overwrite {
 		if exists, _ := fsutil.FileExists(output); exists {
 			return nil
 		}
 	}
 
-	logger.Infof("[generator] generating webp preview for %s", input)
+	logger.Infof("[generator] generating %s preview for %s", strings.ToLower(format.String()), input)
 
 	src := g.ScenePaths.GetVideoPreviewPath(hash)
 
-	if err := g.generateFile(lockCtx, g.ScenePaths, webpPattern, output, g.previewVideoToImage(src)); err != nil {
+	if err := g.generateFile(lockCtx, g.ScenePaths, pattern, output, g.previewVideoToImage(src, format)); err != nil {
 		return err
 	}
 
@@ -283,7 +418,41 @@ func (g Generator) PreviewWebp(ctx context.Context, input string, hash string) e
 	return nil
 }
 
-func (g Generator) previewVideoToImage(input string) generateFn {
+// previewImageArgs returns the codec and encoding options for an animated
+// image preview in format.
+func previewImageArgs(format models.PreviewImageFormat) (ffmpeg.VideoCodec, ffmpeg.Args) {
+	switch format {
+	case models.PreviewImageFormatWebp:
+		return ffmpeg.VideoCodecLibWebP, ffmpeg.Args{
+			"-lossless", "0",
+			"-q:v", "75",
+			"-compression_level", "6",
+			"-preset", "default",
+			"-loop", "0",
+			"-threads", "4",
+		}
+	case models.PreviewImageFormatAvif:
+		return ffmpeg.VideoCodecLibAOM, ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-crf", "35",
+			"-b:v", "0",
+			"-cpu-used", "6",
+			"-row-mt", "1",
+			"-threads", "4",
+		}
+	default:
+		return ffmpeg.VideoCodecLibWebP, ffmpeg.Args{
+			"-lossless", "1",
+			"-q:v", "70",
+			"-compression_level", "6",
+			"-preset", "default",
+			"-loop", "0",
+			"-threads", "4",
+		}
+	}
+}
+
+func (g Generator) previewVideoToImage(input string, format models.PreviewImageFormat) generateFn {
 	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
 		var videoFilter ffmpeg.VideoFilter
 		videoFilter = videoFilter.ScaleWidth(scenePreviewWidth)
@@ -292,19 +461,13 @@ func (g Generator) previewVideoToImage(input string) generateFn {
 		var videoArgs ffmpeg.Args
 		videoArgs = videoArgs.VideoFilter(videoFilter)
 
-		videoArgs = append(videoArgs,
-			"-lossless", "1",
-			"-q:v", "70",
-			"-compression_level", "6",
-			"-preset", "default",
-			"-loop", "0",
-			"-threads", "4",
-		)
+		codec, codecArgs := previewImageArgs(format)
+		videoArgs = append(videoArgs, codecArgs...)
 
 		encodeOptions := transcoder.TranscodeOptions{
 			OutputPath: tmpFn,
 
-			VideoCodec: ffmpeg.VideoCodecLibWebP,
+			VideoCodec: codec,
 			VideoArgs:  videoArgs,
 
 			ExtraInputArgs:  g.FFMpegConfig.GetTranscodeInputArgs(),
@@ -0,0 +1,34 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExcludes(t *testing.T) {
+	tests := []struct {
+		name          string
+		videoDuration float64
+		excludeStart  string
+		excludeEnd    string
+		wantErr       bool
+	}{
+		{"none", 60, "", "", false},
+		{"seconds", 60, "10", "20", false},
+		{"percentages", 0, "10%", "20%", false},
+		{"mixed", 100, "10%", "30", false},
+		{"seconds overlap", 60, "40", "20", true},
+		{"percentages overlap", 0, "50%", "50%", true},
+		{"mixed overlap", 100, "60%", "40", true},
+		{"unknown duration", 0, "40", "20", false},
+		{"invalid", 60, "abc", "", true},
+		{"negative", 60, "", "-5", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExcludes(tt.videoDuration, tt.excludeStart, tt.excludeEnd)
+			assert.Equal(t, tt.wantErr, err != nil, "ValidateExcludes() error = %v", err)
+		})
+	}
+}
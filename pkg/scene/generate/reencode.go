@@ -0,0 +1,198 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+// deinterlaceFilter deinterlaces frames flagged as interlaced, and passes
+// progressive frames through unchanged.
+const deinterlaceFilter = "bwdif=mode=send_frame:deint=interlaced"
+
+// reencodeAudioCodecs are the audio codecs that are copied, rather than
+// re-encoded, into each output format.
+var reencodeAudioCodecs = map[ffmpeg.Format][]string{
+	ffmpeg.FormatMP4:  {"aac", "ac3", "alac", "eac3", "flac", "mp3", "opus"},
+	ffmpeg.FormatWebm: {"opus", "vorbis"},
+}
+
+// reencodeSubtitleCodecs are the text subtitle codecs of each output format.
+var reencodeSubtitleCodecs = map[ffmpeg.Format]string{
+	ffmpeg.FormatMP4:  "mov_text",
+	ffmpeg.FormatWebm: "webvtt",
+}
+
+// textSubtitleCodecs are the subtitle codecs that can be converted to the
+// subtitle codec of the output format. Image based subtitles cannot be.
+var textSubtitleCodecs = []string{"ass", "mov_text", "ssa", "subrip", "text", "webvtt"}
+
+type ReencodeOptions struct {
+	VideoCodec models.ClipVideoCodec
+
+	// MaxHeight is the maximum height of the output. Taller videos are
+	// scaled down, keeping their aspect ratio. Zero for no limit.
+	MaxHeight int
+	// Deinterlace deinterlaces interlaced frames of the input.
+	Deinterlace bool
+
+	// Source is the probed input file. If set, its audio and subtitle
+	// streams are kept, and its pixel format and colour properties are used
+	// for the output.
+	Source *ffmpeg.VideoFile
+}
+
+// Reencode re-encodes the whole of the input video to output using the
+// profile given by options. Returns an error if output already exists.
+func (g Generator) Reencode(ctx context.Context, input string, output string, options ReencodeOptions) error {
+	if exists, _ := fsutil.FileExists(output); exists {
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	pattern := "*" + ClipExtension(input, &options.VideoCodec)
+	if err := g.generateFile(lockCtx, g.ScenePaths, pattern, output, g.reencode(input, options)); err != nil {
+		return err
+	}
+
+	logger.Debug("re-encoded video: ", output)
+
+	return nil
+}
+
+func (g Generator) reencode(input string, options ReencodeOptions) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		transcodeOptions := transcoder.TranscodeOptions{
+			OutputPath: tmpFn,
+
+			ExtraInputArgs:  g.FFMpegConfig.GetTranscodeInputArgs(),
+			ExtraOutputArgs: g.FFMpegConfig.GetTranscodeOutputArgs(),
+		}
+
+		if err := g.clipCodecOptions(options.VideoCodec, &transcodeOptions); err != nil {
+			return err
+		}
+
+		if options.Source != nil {
+			keepVideoProperties(options.Source, &transcodeOptions)
+			mapStreams(input, options.Source, &transcodeOptions)
+		}
+
+		var videoFilter ffmpeg.VideoFilter
+		if options.Deinterlace {
+			videoFilter = videoFilter.Append(deinterlaceFilter)
+		}
+		if options.MaxHeight > 0 {
+			// -2 keeps the width even, as required by chroma subsampled
+			// pixel formats
+			videoFilter = videoFilter.Append(fmt.Sprintf("scale=-2:'min(ih,%d)'", options.MaxHeight))
+		}
+		if videoFilter != "" {
+			var args ffmpeg.Args
+			args = args.VideoFilter(videoFilter)
+			transcodeOptions.VideoArgs = append(args, transcodeOptions.VideoArgs...)
+		}
+
+		args := transcoder.Transcode(input, transcodeOptions)
+
+		return g.generate(lockCtx, args)
+	}
+}
+
+// keepVideoProperties replaces the pixel format of the clip codec options
+// with that of the source video stream, and sets the colour properties of
+// the output to those of the source.
+func keepVideoProperties(source *ffmpeg.VideoFile, options *transcoder.TranscodeOptions) {
+	stream := source.VideoStream
+	if stream == nil {
+		return
+	}
+
+	// only YUV pixel formats are supported by all of the clip codecs
+	keepPixFmt := strings.HasPrefix(stream.PixFmt, "yuv") && stream.PixFmt != "yuv420p"
+
+	var args ffmpeg.Args
+	for i := 0; i < len(options.VideoArgs); i++ {
+		arg := options.VideoArgs[i]
+		if keepPixFmt && i+1 < len(options.VideoArgs) {
+			switch arg {
+			case "-pix_fmt":
+				args = append(args, arg, stream.PixFmt)
+				i++
+				continue
+			case "-profile:v", "-level":
+				// the clip profile and level only support yuv420p
+				i++
+				continue
+			}
+		}
+		args = append(args, arg)
+	}
+
+	colorArgs := []struct {
+		arg   string
+		value string
+	}{
+		{"-color_primaries", stream.ColorPrimaries},
+		{"-color_trc", stream.ColorTransfer},
+		{"-colorspace", stream.ColorSpace},
+		{"-color_range", stream.ColorRange},
+	}
+	for _, v := range colorArgs {
+		if v.value != "" && v.value != "unknown" {
+			args = append(args, v.arg, v.value)
+		}
+	}
+
+	options.VideoArgs = args
+}
+
+// mapStreams maps the video, audio and subtitle streams of the source to the
+// output. Audio streams are copied if the output format supports their codec,
+// and text subtitles are converted to the subtitle codec of the output format.
+// Streams which cannot be kept in the output format are logged.
+func mapStreams(input string, source *ffmpeg.VideoFile, options *transcoder.TranscodeOptions) {
+	var args ffmpeg.Args
+	hasAudio := false
+	copyAudio := true
+	hasSubtitles := false
+
+	for _, stream := range source.JSON.Streams {
+		streamArg := fmt.Sprintf("0:%d", stream.Index)
+
+		switch {
+		case stream.CodecType == "video" && stream.Disposition.AttachedPic == 0:
+			args = append(args, "-map", streamArg)
+		case stream.CodecType == "audio":
+			args = append(args, "-map", streamArg)
+			hasAudio = true
+			if !sliceutil.Contains(reencodeAudioCodecs[options.Format], stream.CodecName) {
+				copyAudio = false
+			}
+		case stream.CodecType == "subtitle" && sliceutil.Contains(textSubtitleCodecs, stream.CodecName):
+			args = append(args, "-map", streamArg)
+			hasSubtitles = true
+		default:
+			logger.Warnf("not keeping %s stream %d (%s) of %s: not supported in %s", stream.CodecType, stream.Index, stream.CodecName, input, options.Format)
+		}
+	}
+
+	if hasAudio && copyAudio {
+		options.AudioCodec = ffmpeg.AudioCodecCopy
+	}
+
+	if hasSubtitles {
+		args = append(args, "-c:s", reencodeSubtitleCodecs[options.Format])
+	}
+
+	options.VideoArgs = append(args, options.VideoArgs...)
+}
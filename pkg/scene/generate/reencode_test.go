@@ -0,0 +1,71 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepVideoProperties(t *testing.T) {
+	source := &ffmpeg.VideoFile{
+		VideoStream: &ffmpeg.FFProbeStream{
+			PixFmt:         "yuv420p10le",
+			ColorPrimaries: "bt2020",
+			ColorTransfer:  "smpte2084",
+			ColorSpace:     "bt2020nc",
+			ColorRange:     "unknown",
+		},
+	}
+
+	options := transcoder.TranscodeOptions{
+		VideoArgs: ffmpeg.Args{"-pix_fmt", "yuv420p", "-profile:v", "high", "-level", "4.2", "-crf", "21"},
+	}
+	keepVideoProperties(source, &options)
+
+	assert.Equal(t, ffmpeg.Args{
+		"-pix_fmt", "yuv420p10le",
+		"-crf", "21",
+		"-color_primaries", "bt2020",
+		"-color_trc", "smpte2084",
+		"-colorspace", "bt2020nc",
+	}, options.VideoArgs)
+}
+
+func TestMapStreams(t *testing.T) {
+	source := &ffmpeg.VideoFile{}
+	source.JSON.Streams = []ffmpeg.FFProbeStream{
+		{Index: 0, CodecType: "video", CodecName: "mpeg2video"},
+		{Index: 1, CodecType: "audio", CodecName: "aac"},
+		{Index: 2, CodecType: "audio", CodecName: "ac3"},
+		{Index: 3, CodecType: "subtitle", CodecName: "subrip"},
+		{Index: 4, CodecType: "subtitle", CodecName: "hdmv_pgs_subtitle"},
+		{Index: 5, CodecType: "attachment", CodecName: "ttf"},
+	}
+
+	options := transcoder.TranscodeOptions{
+		Format:     ffmpeg.FormatMP4,
+		AudioCodec: ffmpeg.AudioCodecAAC,
+		VideoArgs:  ffmpeg.Args{"-crf", "21"},
+	}
+	mapStreams("input.mkv", source, &options)
+
+	assert.Equal(t, ffmpeg.Args{
+		"-map", "0:0",
+		"-map", "0:1",
+		"-map", "0:2",
+		"-map", "0:3",
+		"-c:s", "mov_text",
+		"-crf", "21",
+	}, options.VideoArgs)
+	assert.Equal(t, ffmpeg.AudioCodecCopy, options.AudioCodec)
+
+	// audio that the container does not support is re-encoded
+	options = transcoder.TranscodeOptions{
+		Format:     ffmpeg.FormatWebm,
+		AudioCodec: ffmpeg.AudioCodecLibOpus,
+	}
+	mapStreams("input.mkv", source, &options)
+	assert.Equal(t, ffmpeg.AudioCodecLibOpus, options.AudioCodec)
+}
@@ -19,6 +19,10 @@ const (
 
 type ScreenshotOptions struct {
 	At *float64
+
+	// BestFrame selects the best of a sample of frames when At is nil,
+	// rather than the frame at a fixed proportion of the duration.
+	BestFrame bool
 }
 
 func (g Generator) Screenshot(ctx context.Context, input string, videoWidth int, videoDuration float64, options ScreenshotOptions) ([]byte, error) {
@@ -28,8 +32,19 @@ func (g Generator) Screenshot(ctx context.Context, input string, videoWidth int,
 	logger.Infof("Creating screenshot for %s", input)
 
 	at := screenshotDurationProportion * videoDuration
-	if options.At != nil {
+	switch {
+	case options.At != nil:
 		at = *options.At
+	case options.BestFrame:
+		best, err := g.bestFrame(lockCtx, input, videoDuration)
+		if err != nil {
+			if lockCtx.Err() != nil {
+				return nil, err
+			}
+			logger.Warnf("Selecting best frame for %s, using frame at %.2fs: %v", input, at, err)
+		} else {
+			at = best
+		}
 	}
 
 	ret, err := g.generateBytes(lockCtx, g.ScenePaths, jpgPattern, g.screenshot(input, screenshotOptions{
@@ -9,9 +9,20 @@ import (
 	"github.com/stashapp/stash/pkg/logger"
 )
 
+// transcodeCodecArgs are the encoding options of generated transcodes,
+// which precede the encoder options.
+var transcodeCodecArgs = ffmpeg.Args{
+	"-preset", "superfast",
+	"-crf", "23",
+}
+
 type TranscodeOptions struct {
 	Width  int
 	Height int
+
+	// HDR is true if the input video is HDR, in which case the transcode is
+	// tone mapped to SDR.
+	HDR bool
 }
 
 func (g Generator) Transcode(ctx context.Context, input string, hash string, options TranscodeOptions) error {
@@ -66,20 +77,16 @@ func (g Generator) makeTranscode(lockCtx *fsutil.LockContext, hash string, gener
 
 func (g Generator) transcode(input string, options TranscodeOptions) generateFn {
 	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
-		var videoArgs ffmpeg.Args
+		videoFilter := g.toneMapFilter(options.HDR)
 		if options.Width != 0 && options.Height != 0 {
-			var videoFilter ffmpeg.VideoFilter
 			videoFilter = videoFilter.ScaleDimensions(options.Width, options.Height)
-			videoArgs = videoArgs.VideoFilter(videoFilter)
 		}
 
-		videoArgs = append(videoArgs,
-			"-pix_fmt", "yuv420p",
-			"-profile:v", "high",
-			"-level", "4.2",
-			"-preset", "superfast",
-			"-crf", "23",
-		)
+		var videoArgs ffmpeg.Args
+		videoArgs = videoArgs.VideoFilter(videoFilter)
+
+		videoArgs = append(videoArgs, transcodeCodecArgs...)
+		videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(ffmpeg.VideoCodecLibX264)...)
 
 		args := transcoder.Transcode(input, transcoder.TranscodeOptions{
 			OutputPath: tmpFn,
@@ -97,20 +104,16 @@ func (g Generator) transcode(input string, options TranscodeOptions) generateFn
 
 func (g Generator) transcodeVideo(input string, options TranscodeOptions) generateFn {
 	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
-		var videoArgs ffmpeg.Args
+		videoFilter := g.toneMapFilter(options.HDR)
 		if options.Width != 0 && options.Height != 0 {
-			var videoFilter ffmpeg.VideoFilter
 			videoFilter = videoFilter.ScaleDimensions(options.Width, options.Height)
-			videoArgs = videoArgs.VideoFilter(videoFilter)
 		}
 
-		videoArgs = append(videoArgs,
-			"-pix_fmt", "yuv420p",
-			"-profile:v", "high",
-			"-level", "4.2",
-			"-preset", "superfast",
-			"-crf", "23",
-		)
+		var videoArgs ffmpeg.Args
+		videoArgs = videoArgs.VideoFilter(videoFilter)
+
+		videoArgs = append(videoArgs, transcodeCodecArgs...)
+		videoArgs = append(videoArgs, g.Encoder.GenerateCodecArgs(ffmpeg.VideoCodecLibX264)...)
 
 		var audioArgs ffmpeg.Args
 		audioArgs = audioArgs.SkipAudio()
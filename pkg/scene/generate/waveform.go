@@ -0,0 +1,59 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// the waveform has the same dimensions as the interactive heatmap, so
+	// that they can be displayed alongside each other
+	waveformWidth  = 1280
+	waveformHeight = 60
+	waveformColor  = "0x8a9ba8"
+)
+
+// Waveform generates an image of the audio waveform of the first audio
+// stream of input.
+func (g Generator) Waveform(ctx context.Context, input string, hash string) error {
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	output := g.ScenePaths.GetWaveformPath(hash)
+	if !g.Overwrite {
+		if exists, _ := fsutil.FileExists(output); exists {
+			return nil
+		}
+	}
+
+	logger.Infof("[generator] generating waveform for %s", input)
+
+	if err := g.generateFile(lockCtx, g.ScenePaths, pngPattern, output, g.waveform(input)); err != nil {
+		return err
+	}
+
+	logger.Debug("created waveform: ", output)
+
+	return nil
+}
+
+func (g Generator) waveform(input string) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		filter := fmt.Sprintf("[0:a:0]aformat=channel_layouts=mono,showwavespic=s=%dx%d:colors=%s[out]", waveformWidth, waveformHeight, waveformColor)
+
+		var args ffmpeg.Args
+		args = args.LogLevel(ffmpeg.LogLevelError).Overwrite()
+		args = append(args, g.FFMpegConfig.GetTranscodeInputArgs()...)
+		args = args.Input(input)
+		args = append(args, "-filter_complex", filter, "-map", "[out]")
+		args = args.VideoFrames(1)
+		args = append(args, g.FFMpegConfig.GetTranscodeOutputArgs()...)
+		args = args.Output(tmpFn)
+
+		return g.generate(lockCtx, args)
+	}
+}
@@ -13,6 +13,8 @@ import (
 
 type ImporterReaderWriter interface {
 	models.SceneCreatorUpdater
+	models.CustomFieldsWriter
+	models.HistoryWriter
 	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error)
 }
 
@@ -101,6 +103,8 @@ func (i *Importer) sceneJSONToScene(sceneJSON jsonschema.Scene) models.Scene {
 	}
 
 	newScene.Organized = sceneJSON.Organized
+	newScene.Favorite = sceneJSON.Favorite
+	newScene.ContentRating = sceneJSON.ContentRating
 	newScene.OCounter = sceneJSON.OCounter
 	newScene.CreatedAt = sceneJSON.CreatedAt.GetTime()
 	newScene.UpdatedAt = sceneJSON.UpdatedAt.GetTime()
@@ -372,6 +376,26 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		}
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting scene custom fields: %v", err)
+		}
+	}
+
+	if len(i.Input.ODates) > 0 {
+		if err := i.ReaderWriter.ReplaceODates(ctx, id, jsonschema.ODatesFromJSON(i.Input.ODates)); err != nil {
+			return fmt.Errorf("error setting scene o dates: %v", err)
+		}
+	}
+
+	if len(i.Input.RatingHistory) > 0 {
+		if err := i.ReaderWriter.ReplaceRatingHistory(ctx, id, jsonschema.RatingHistoryFromJSON(i.Input.RatingHistory)); err != nil {
+			return fmt.Errorf("error setting scene rating history: %v", err)
+		}
+	}
+
 	return nil
 }
 
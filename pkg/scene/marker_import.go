@@ -33,6 +33,21 @@ func (i *MarkerImporter) PreImport(ctx context.Context) error {
 		SceneID:   i.SceneID,
 		CreatedAt: i.Input.CreatedAt.GetTime(),
 		UpdatedAt: i.Input.UpdatedAt.GetTime(),
+
+		PreviewDuration: i.Input.PreviewDuration,
+		PreviewWidth:    i.Input.PreviewWidth,
+	}
+
+	if i.Input.EndSeconds != "" {
+		endSeconds, err := strconv.ParseFloat(i.Input.EndSeconds, 64)
+		if err != nil {
+			return fmt.Errorf("invalid end seconds %q: %w", i.Input.EndSeconds, err)
+		}
+		i.marker.EndSeconds = &endSeconds
+	}
+
+	if format := models.MarkerPreviewFormat(i.Input.PreviewFormat); format.IsValid() {
+		i.marker.PreviewFormat = &format
 	}
 
 	if err := i.populateTags(ctx); err != nil {
@@ -118,6 +118,14 @@ func (s *Service) mergeSceneMarkers(ctx context.Context, dest *models.Scene, src
 				src:  s.Paths.SceneMarkers.GetWebpPreviewPath(srcHash, int(m.Seconds)),
 				dest: s.Paths.SceneMarkers.GetWebpPreviewPath(destHash, int(m.Seconds)),
 			},
+			{
+				src:  s.Paths.SceneMarkers.GetWebpFormatPreviewPath(srcHash, int(m.Seconds)),
+				dest: s.Paths.SceneMarkers.GetWebpFormatPreviewPath(destHash, int(m.Seconds)),
+			},
+			{
+				src:  s.Paths.SceneMarkers.GetStaticFormatPreviewPath(srcHash, int(m.Seconds)),
+				dest: s.Paths.SceneMarkers.GetStaticFormatPreviewPath(destHash, int(m.Seconds)),
+			},
 		}...)
 	}
 
@@ -24,6 +24,10 @@ func MigrateHash(p *paths.Paths, oldHash string, newHash string) {
 	newPath = scenePaths.GetWebpPreviewPath(newHash)
 	migrateSceneFiles(oldPath, newPath)
 
+	oldPath = scenePaths.GetAvifPreviewPath(oldHash)
+	newPath = scenePaths.GetAvifPreviewPath(newHash)
+	migrateSceneFiles(oldPath, newPath)
+
 	oldPath = scenePaths.GetTranscodePath(oldHash)
 	newPath = scenePaths.GetTranscodePath(newHash)
 	migrateSceneFiles(oldPath, newPath)
@@ -41,6 +45,14 @@ func MigrateHash(p *paths.Paths, oldHash string, newHash string) {
 	newPath = scenePaths.GetInteractiveHeatmapPath(newHash)
 	migrateSceneFiles(oldPath, newPath)
 
+	oldPath = scenePaths.GetWaveformPath(oldHash)
+	newPath = scenePaths.GetWaveformPath(newHash)
+	migrateSceneFiles(oldPath, newPath)
+
+	oldPath = scenePaths.GetFramesFolder(oldHash)
+	newPath = scenePaths.GetFramesFolder(newHash)
+	migrateSceneFolder(oldPath, newPath)
+
 	// #3986 - migrate scene marker files
 	markerPaths := p.SceneMarkers
 	oldPath = markerPaths.GetFolderPath(oldHash)
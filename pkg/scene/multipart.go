@@ -0,0 +1,205 @@
+package scene
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var (
+	// partNumberRE matches numbered part indicators at the end of a file
+	// name, such as "cd1", "Part 2", "disc.3" or "pt-4".
+	partNumberRE = regexp.MustCompile(`(?i)^(.+?)[ ._-]+(?:cd|dvd|disc|disk|part|pt)[ ._-]*(\d{1,2})$`)
+	// partLetterRE matches lettered part indicators at the end of a file
+	// name, such as "-A" or " b".
+	partLetterRE = regexp.MustCompile(`(?i)^(.+?)[ ._-]+([a-d])$`)
+)
+
+// ParsePart returns the name without its part indicator and the part number
+// of a file name, which should not include the extension. Returns false if
+// the name does not end with a part indicator.
+func ParsePart(name string) (stem string, part int, ok bool) {
+	if m := partNumberRE.FindStringSubmatch(name); m != nil {
+		part, _ = strconv.Atoi(m[2])
+		if part == 0 {
+			return "", 0, false
+		}
+		return m[1], part, true
+	}
+
+	if m := partLetterRE.FindStringSubmatch(name); m != nil {
+		return m[1], int(strings.ToLower(m[2])[0]-'a') + 1, true
+	}
+
+	return "", 0, false
+}
+
+// MultipartGroup is a set of scenes whose primary files are parts of the
+// same video.
+type MultipartGroup struct {
+	// Scenes in part order
+	Scenes []*models.Scene
+	// Combined duration of the primary files of the scenes
+	Duration float64
+}
+
+// FindMultipart returns the groups of scenes in paths whose primary files
+// are in the same folder, have the same name apart from their part
+// indicators, and together are numbered from the first part without gaps.
+// All scenes are searched if paths is empty. Scenes that are not accessible
+// in ctx are excluded by the scene store.
+func FindMultipart(ctx context.Context, r models.SceneQueryer, fileGetter models.FileGetter, paths []string) ([]*MultipartGroup, error) {
+	sceneFilter := FilterFromPaths(paths)
+
+	const batchSize = 1000
+	findFilter := models.BatchFindFilter(batchSize)
+
+	type candidate struct {
+		scene *models.Scene
+		part  int
+	}
+
+	groups := make(map[string][]candidate)
+	var keys []string
+
+	more := true
+	for more {
+		scenes, err := Query(ctx, r, sceneFilter, findFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := loadPrimaryFiles(ctx, fileGetter, scenes); err != nil {
+			return nil, err
+		}
+
+		for _, s := range scenes {
+			f := s.Files.Primary()
+			if f == nil {
+				continue
+			}
+
+			name := strings.TrimSuffix(f.Basename, filepath.Ext(f.Basename))
+			stem, part, ok := ParsePart(name)
+			if !ok {
+				continue
+			}
+
+			key := filepath.Join(filepath.Dir(f.Path), strings.ToLower(stem))
+			if _, found := groups[key]; !found {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], candidate{scene: s, part: part})
+		}
+
+		if len(scenes) != batchSize {
+			more = false
+		} else {
+			*findFilter.Page++
+		}
+	}
+
+	var ret []*MultipartGroup
+	for _, key := range keys {
+		candidates := groups[key]
+		if len(candidates) < 2 {
+			continue
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].part < candidates[j].part
+		})
+
+		g := &MultipartGroup{}
+		for i, c := range candidates {
+			// parts must be numbered 1, 2, 3...
+			if c.part != i+1 {
+				g = nil
+				break
+			}
+
+			g.Scenes = append(g.Scenes, c.scene)
+			g.Duration += c.scene.Files.Primary().Duration
+		}
+
+		if g != nil {
+			ret = append(ret, g)
+		}
+	}
+
+	return ret, nil
+}
+
+// loadPrimaryFiles loads the primary files of the scenes in a single query.
+func loadPrimaryFiles(ctx context.Context, fileGetter models.FileGetter, scenes []*models.Scene) error {
+	var ids []models.FileID
+	for _, s := range scenes {
+		if s.PrimaryFileID != nil && !s.Files.PrimaryLoaded() {
+			ids = append(ids, *s.PrimaryFileID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	files, err := fileGetter.Find(ctx, ids...)
+	if err != nil {
+		return fmt.Errorf("loading primary files: %w", err)
+	}
+
+	byID := make(map[models.FileID]*models.VideoFile, len(files))
+	for _, f := range files {
+		if vf, ok := f.(*models.VideoFile); ok {
+			byID[vf.ID] = vf
+		}
+	}
+
+	for _, s := range scenes {
+		if s.PrimaryFileID != nil && !s.Files.PrimaryLoaded() {
+			s.Files.SetPrimary(byID[*s.PrimaryFileID])
+		}
+	}
+
+	return nil
+}
+
+// MergeMultipart merges the scenes into the first scene, which becomes a
+// multi-part scene whose parts are the primary files of the scenes, in the
+// order of sceneIDs.
+func (s *Service) MergeMultipart(ctx context.Context, sceneIDs []int) error {
+	if len(sceneIDs) < 2 {
+		return errors.New("at least two scenes are required")
+	}
+
+	scenes, err := s.Repository.FindMany(ctx, sceneIDs)
+	if err != nil {
+		return fmt.Errorf("finding scenes: %w", err)
+	}
+
+	partIDs := make([]models.FileID, len(scenes))
+	for i, scene := range scenes {
+		if scene.PrimaryFileID == nil {
+			return fmt.Errorf("scene %d has no files", scene.ID)
+		}
+		partIDs[i] = *scene.PrimaryFileID
+	}
+
+	destID := sceneIDs[0]
+	if err := s.Merge(ctx, sceneIDs[1:], destID, models.NewScenePartial()); err != nil {
+		return err
+	}
+
+	if err := s.Repository.SetFileParts(ctx, destID, partIDs); err != nil {
+		return fmt.Errorf("setting parts of scene %d: %w", destID, err)
+	}
+
+	return nil
+}
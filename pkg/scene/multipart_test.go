@@ -0,0 +1,38 @@
+package scene
+
+import "testing"
+
+func TestParsePart(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantStem string
+		wantPart int
+		wantOK   bool
+	}{
+		{"movie.cd1", "movie", 1, true},
+		{"movie CD2", "movie", 2, true},
+		{"movie - Part 3", "movie", 3, true},
+		{"movie_part_4", "movie", 4, true},
+		{"movie.pt2", "movie", 2, true},
+		{"movie disc.1", "movie", 1, true},
+		{"movie-dvd12", "movie", 12, true},
+		{"movie-A", "movie", 1, true},
+		{"movie b", "movie", 2, true},
+		{"movie.d", "movie", 4, true},
+		{"movie", "", 0, false},
+		{"movie.cd0", "", 0, false},
+		{"moviecd1", "", 0, false},
+		{"movie-e", "", 0, false},
+		{"movie.2019", "", 0, false},
+		{"a", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stem, part, ok := ParsePart(tt.name)
+			if ok != tt.wantOK || stem != tt.wantStem || part != tt.wantPart {
+				t.Errorf("ParsePart(%q) = %q, %d, %v; want %q, %d, %v", tt.name, stem, part, ok, tt.wantStem, tt.wantPart, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package scene
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// NFO is the metadata of a Kodi or Jellyfin NFO sidecar file. The root
+// element is not checked, so movie, episodedetails and musicvideo files are
+// all accepted.
+type NFO struct {
+	Title         string     `xml:"title"`
+	OriginalTitle string     `xml:"originaltitle"`
+	Plot          string     `xml:"plot"`
+	Outline       string     `xml:"outline"`
+	Premiered     string     `xml:"premiered"`
+	ReleaseDate   string     `xml:"releasedate"`
+	Aired         string     `xml:"aired"`
+	Studios       []string   `xml:"studio"`
+	Directors     []string   `xml:"director"`
+	Genres        []string   `xml:"genre"`
+	Tags          []string   `xml:"tag"`
+	Actors        []NFOActor `xml:"actor"`
+}
+
+type NFOActor struct {
+	Name string `xml:"name"`
+}
+
+// ParseNFO parses the NFO XML in r. Any content after the root element, such
+// as the trailing scraper URL permitted by Kodi, is ignored.
+func ParseNFO(r io.Reader) (*NFO, error) {
+	var ret NFO
+	if err := xml.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("parsing nfo: %w", err)
+	}
+
+	return &ret, nil
+}
+
+// LoadNFOFile parses the NFO file at filePath.
+func LoadNFOFile(filePath string) (*NFO, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseNFO(f)
+}
+
+// FindNFOFile returns the path of the NFO file for the video file at
+// videoPath, or an empty string if there is none. <basename>.nfo is
+// preferred over movie.nfo in the same directory, which is only used if the
+// video file is the only one in the directory.
+func FindNFOFile(videoPath string) string {
+	for _, c := range nfoPaths(videoPath) {
+		if exists, _ := fsutil.FileExists(c); exists {
+			return c
+		}
+	}
+
+	return ""
+}
+
+var (
+	videoExtensions      []string
+	videoExtensionsMutex sync.RWMutex
+)
+
+// SetVideoExtensions sets the extensions of video files, which are used to
+// find whether a video file is the only one in its folder. If none are set,
+// only files with the extension of the video file are counted.
+func SetVideoExtensions(extensions []string) {
+	videoExtensionsMutex.Lock()
+	defer videoExtensionsMutex.Unlock()
+
+	videoExtensions = extensions
+}
+
+func nfoPaths(videoPath string) []string {
+	ret := []string{
+		strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ".nfo",
+	}
+
+	// movie.nfo describes the whole folder, so it is only used if the folder
+	// contains a single video file
+	if isOnlyVideoInFolder(videoPath) {
+		ret = append(ret, filepath.Join(filepath.Dir(videoPath), "movie.nfo"))
+	}
+
+	return ret
+}
+
+// isOnlyVideoInFolder returns true if there are no other video files in the
+// folder of the video file at videoPath.
+func isOnlyVideoInFolder(videoPath string) bool {
+	entries, err := os.ReadDir(filepath.Dir(videoPath))
+	if err != nil {
+		return false
+	}
+
+	videoExtensionsMutex.RLock()
+	extensions := videoExtensions
+	videoExtensionsMutex.RUnlock()
+
+	if len(extensions) == 0 {
+		extensions = []string{strings.TrimPrefix(filepath.Ext(videoPath), ".")}
+	}
+
+	basename := filepath.Base(videoPath)
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == basename {
+			continue
+		}
+
+		if fsutil.MatchExtension(e.Name(), extensions) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTitle returns the title, falling back to the original title.
+func (n NFO) GetTitle() string {
+	return firstNonEmpty(n.Title, n.OriginalTitle)
+}
+
+// GetDetails returns the plot, falling back to the outline.
+func (n NFO) GetDetails() string {
+	return firstNonEmpty(n.Plot, n.Outline)
+}
+
+// GetDate returns the first of the premiered, release and aired dates that
+// can be parsed, or nil if there is none.
+func (n NFO) GetDate() *models.Date {
+	for _, s := range []string{n.Premiered, n.ReleaseDate, n.Aired} {
+		if d, err := models.ParseDate(strings.TrimSpace(s)); err == nil {
+			return &d
+		}
+	}
+
+	return nil
+}
+
+// GetStudio returns the name of the first studio.
+func (n NFO) GetStudio() string {
+	return firstNonEmpty(n.Studios...)
+}
+
+// GetDirector returns the names of the directors, separated by commas.
+func (n NFO) GetDirector() string {
+	return strings.Join(uniqueNonEmpty(n.Directors), ", ")
+}
+
+// GetPerformerNames returns the unique names of the actors.
+func (n NFO) GetPerformerNames() []string {
+	var names []string
+	for _, a := range n.Actors {
+		names = append(names, a.Name)
+	}
+
+	return uniqueNonEmpty(names)
+}
+
+// GetTagNames returns the unique genres and tags.
+func (n NFO) GetTagNames() []string {
+	return uniqueNonEmpty(append(append([]string{}, n.Genres...), n.Tags...))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// uniqueNonEmpty returns the trimmed, non-empty values, removing
+// case-insensitive duplicates.
+func uniqueNonEmpty(values []string) []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+
+		seen[strings.ToLower(v)] = true
+		ret = append(ret, v)
+	}
+
+	return ret
+}
@@ -0,0 +1,87 @@
+package scene
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testNFO = `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<movie>
+    <title></title>
+    <originaltitle>Original Title</originaltitle>
+    <outline>Outline</outline>
+    <premiered>not a date</premiered>
+    <releasedate>2021-02-03</releasedate>
+    <studio>  Studio  </studio>
+    <studio>Other Studio</studio>
+    <director>Director A</director>
+    <director>Director B</director>
+    <genre>Drama</genre>
+    <tag>drama</tag>
+    <tag>Tag</tag>
+    <actor>
+        <name>Performer A</name>
+        <role>Role</role>
+    </actor>
+    <actor>
+        <name>Performer B</name>
+    </actor>
+    <actor>
+        <name></name>
+    </actor>
+</movie>
+https://www.themoviedb.org/movie/1
+`
+
+func TestParseNFO(t *testing.T) {
+	nfo, err := ParseNFO(strings.NewReader(testNFO))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	date, _ := models.ParseDate("2021-02-03")
+
+	assert.Equal(t, "Original Title", nfo.GetTitle())
+	assert.Equal(t, "Outline", nfo.GetDetails())
+	assert.Equal(t, &date, nfo.GetDate())
+	assert.Equal(t, "Studio", nfo.GetStudio())
+	assert.Equal(t, "Director A, Director B", nfo.GetDirector())
+	assert.Equal(t, []string{"Performer A", "Performer B"}, nfo.GetPerformerNames())
+	assert.Equal(t, []string{"Drama", "Tag"}, nfo.GetTagNames())
+}
+
+func TestParseNFOInvalid(t *testing.T) {
+	_, err := ParseNFO(strings.NewReader("not xml"))
+	assert.NotNil(t, err)
+}
+
+func TestFindNFOFile(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "scene.mp4")
+
+	assert.Equal(t, "", FindNFOFile(videoPath))
+
+	moviePath := filepath.Join(dir, "movie.nfo")
+	if !assert.Nil(t, os.WriteFile(moviePath, []byte(testNFO), 0644)) {
+		return
+	}
+	assert.Equal(t, moviePath, FindNFOFile(videoPath))
+
+	// movie.nfo is not used if there are other video files in the directory
+	otherPath := filepath.Join(dir, "other.mp4")
+	if !assert.Nil(t, os.WriteFile(otherPath, nil, 0644)) {
+		return
+	}
+	assert.Equal(t, "", FindNFOFile(videoPath))
+
+	scenePath := filepath.Join(dir, "scene.nfo")
+	if !assert.Nil(t, os.WriteFile(scenePath, []byte(testNFO), 0644)) {
+		return
+	}
+	assert.Equal(t, scenePath, FindNFOFile(videoPath))
+}
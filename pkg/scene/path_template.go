@@ -0,0 +1,119 @@
+package scene
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var (
+	pathTemplateFieldRE = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+	// characters that are not valid in file names on any supported platform
+	invalidPathCharsRE = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+	ErrEmptyPathTemplate = errors.New("path template produced an empty file name")
+)
+
+// PathTemplateData is the scene metadata available to path templates.
+type PathTemplateData struct {
+	Scene      *models.Scene
+	Studio     string
+	Performers []string
+}
+
+func (d PathTemplateData) field(name string) (string, error) {
+	s := d.Scene
+	switch name {
+	case "id":
+		return strconv.Itoa(s.ID), nil
+	case "title":
+		return s.Title, nil
+	case "code":
+		return s.Code, nil
+	case "director":
+		return s.Director, nil
+	case "date":
+		if s.Date == nil {
+			return "", nil
+		}
+		return s.Date.String(), nil
+	case "year":
+		if s.Date == nil {
+			return "", nil
+		}
+		return strconv.Itoa(s.Date.Year()), nil
+	case "studio":
+		return d.Studio, nil
+	case "performers":
+		return strings.Join(d.Performers, ", "), nil
+	}
+
+	return "", fmt.Errorf("unknown path template field %q", name)
+}
+
+// ValidatePathTemplate returns an error if template contains unknown fields.
+func ValidatePathTemplate(template string) error {
+	_, err := FormatPathTemplate(template, PathTemplateData{
+		Scene: &models.Scene{},
+	})
+	if errors.Is(err, ErrEmptyPathTemplate) {
+		return nil
+	}
+	return err
+}
+
+// FormatPathTemplate returns the relative path, without extension, for a
+// scene using template. Fields are written as {{name}}, and directories are
+// separated by "/". Values are stripped of characters that are not valid in
+// file names, and directories that are empty after formatting are omitted.
+//
+// Supported fields are id, title, code, director, date, year, studio and
+// performers.
+func FormatPathTemplate(template string, data PathTemplateData) (string, error) {
+	var segments []string
+	var fieldErr error
+
+	for _, segment := range strings.Split(template, "/") {
+		v := pathTemplateFieldRE.ReplaceAllStringFunc(segment, func(m string) string {
+			name := pathTemplateFieldRE.FindStringSubmatch(m)[1]
+			value, err := data.field(name)
+			if err != nil && fieldErr == nil {
+				fieldErr = err
+			}
+			return sanitisePathSegment(value)
+		})
+
+		v = trimPathSegment(invalidPathCharsRE.ReplaceAllString(v, ""))
+		if v != "" {
+			segments = append(segments, v)
+		}
+	}
+
+	if fieldErr != nil {
+		return "", fieldErr
+	}
+
+	if len(segments) == 0 {
+		return "", ErrEmptyPathTemplate
+	}
+
+	return filepath.Join(segments...), nil
+}
+
+func sanitisePathSegment(v string) string {
+	return strings.TrimSpace(invalidPathCharsRE.ReplaceAllString(v, " "))
+}
+
+// trimPathSegment removes separators left at the ends of a segment by empty
+// fields, and trailing dots which are not valid on Windows.
+func trimPathSegment(v string) string {
+	v = strings.Join(strings.Fields(v), " ")
+	v = strings.Trim(v, " -_")
+	return strings.TrimRight(v, ". ")
+}
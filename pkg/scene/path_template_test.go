@@ -0,0 +1,62 @@
+package scene
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPathTemplate(t *testing.T) {
+	date := models.Date{Time: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}
+	full := PathTemplateData{
+		Scene: &models.Scene{
+			ID:    12,
+			Title: `What: "a" title?`,
+			Code:  "ABC-123",
+			Date:  &date,
+		},
+		Studio:     "Studio/Name",
+		Performers: []string{"Alice", "Bob"},
+	}
+	empty := PathTemplateData{
+		Scene: &models.Scene{
+			ID:    12,
+			Title: "Title",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     PathTemplateData
+		want     string
+		wantErr  bool
+	}{
+		{"studio date title", "{{studio}}/{{date}} - {{title}}", full, "Studio Name/2021-03-04 - What a title", false},
+		{"year performers", "{{ year }}/{{performers}} [{{code}}]", full, "2021/Alice, Bob [ABC-123]", false},
+		{"id", "{{id}}", full, "12", false},
+		{"empty directory omitted", "{{studio}}/{{date}} - {{title}}", empty, "Title", false},
+		{"trailing dots", "{{title}}...", empty, "Title", false},
+		{"empty", "{{studio}}", empty, "", true},
+		{"unknown field", "{{rating}}", full, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatPathTemplate(tt.template, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatPathTemplate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, filepath.FromSlash(tt.want), got)
+		})
+	}
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	assert.NoError(t, ValidatePathTemplate("{{studio}}/{{title}}"))
+	assert.Error(t, ValidatePathTemplate("{{studio}}/{{unknown}}"))
+}
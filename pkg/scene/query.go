@@ -133,6 +133,18 @@ func CountByStudioID(ctx context.Context, r models.SceneQueryer, id int, depth *
 	return r.QueryCount(ctx, filter, nil)
 }
 
+func CountByMovieID(ctx context.Context, r models.SceneQueryer, id int, depth *int) (int, error) {
+	filter := &models.SceneFilterType{
+		Movies: &models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(id)},
+			Modifier: models.CriterionModifierIncludes,
+			Depth:    depth,
+		},
+	}
+
+	return r.QueryCount(ctx, filter, nil)
+}
+
 func CountByTagID(ctx context.Context, r models.SceneQueryer, id int, depth *int) (int, error) {
 	filter := &models.SceneFilterType{
 		Tags: &models.HierarchicalMultiCriterionInput{
@@ -0,0 +1,94 @@
+package scene
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var ErrRelationshipToSelf = errors.New("a scene cannot be related to itself")
+
+// ValidateRelationship returns an error if the scene and related scene are
+// the same or either does not exist.
+func ValidateRelationship(ctx context.Context, sceneID int, relatedSceneID int, qb models.SceneGetter) error {
+	if sceneID == relatedSceneID {
+		return ErrRelationshipToSelf
+	}
+
+	scenes, err := qb.FindMany(ctx, []int{sceneID, relatedSceneID})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range scenes {
+		if s == nil {
+			return fmt.Errorf("scene not found")
+		}
+	}
+
+	return nil
+}
+
+// ExcludeRelated returns the duplicate groups that are not already fully
+// linked. A group is dropped if each of its scenes is connected to the others,
+// directly or indirectly, by scene relationships.
+func ExcludeRelated(ctx context.Context, r models.SceneRelationshipFinder, groups [][]*models.Scene) ([][]*models.Scene, error) {
+	var ret [][]*models.Scene
+	for _, group := range groups {
+		linked, err := isLinked(ctx, r, group)
+		if err != nil {
+			return nil, err
+		}
+
+		if !linked {
+			ret = append(ret, group)
+		}
+	}
+
+	return ret, nil
+}
+
+func isLinked(ctx context.Context, r models.SceneRelationshipFinder, group []*models.Scene) (bool, error) {
+	if len(group) < 2 {
+		return false, nil
+	}
+
+	// each scene id maps to the id of the first scene in its component
+	component := make(map[int]int)
+	for _, s := range group {
+		component[s.ID] = s.ID
+	}
+
+	for _, s := range group {
+		relationships, err := r.FindBySceneID(ctx, s.ID)
+		if err != nil {
+			return false, fmt.Errorf("finding relationships for scene %d: %w", s.ID, err)
+		}
+
+		for _, rel := range relationships {
+			other, found := component[rel.OtherSceneID(s.ID)]
+			if !found {
+				continue
+			}
+
+			// merge the other component into this one
+			this := component[s.ID]
+			for id, c := range component {
+				if c == other {
+					component[id] = this
+				}
+			}
+		}
+	}
+
+	first := component[group[0].ID]
+	for _, c := range component {
+		if c != first {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcludeRelated(t *testing.T) {
+	const (
+		originalID = iota + 1
+		remasterID
+		reEncodeID
+		unrelatedID
+		otherID
+	)
+
+	original := &models.Scene{ID: originalID}
+	remaster := &models.Scene{ID: remasterID}
+	reEncode := &models.Scene{ID: reEncodeID}
+	unrelated := &models.Scene{ID: unrelatedID}
+	other := &models.Scene{ID: otherID}
+
+	remasterRel := &models.SceneRelationship{
+		SceneID:        remasterID,
+		RelatedSceneID: originalID,
+		Type:           models.SceneRelationshipTypeRemaster,
+	}
+	reEncodeRel := &models.SceneRelationship{
+		SceneID:        reEncodeID,
+		RelatedSceneID: remasterID,
+		Type:           models.SceneRelationshipTypeReEncode,
+	}
+	otherRel := &models.SceneRelationship{
+		SceneID:        otherID,
+		RelatedSceneID: originalID,
+		Type:           models.SceneRelationshipTypeOther,
+	}
+
+	db := mocks.NewDatabase()
+
+	db.SceneRelationship.On("FindBySceneID", testCtx, originalID).Return([]*models.SceneRelationship{remasterRel, otherRel}, nil)
+	db.SceneRelationship.On("FindBySceneID", testCtx, remasterID).Return([]*models.SceneRelationship{remasterRel, reEncodeRel}, nil)
+	db.SceneRelationship.On("FindBySceneID", testCtx, reEncodeID).Return([]*models.SceneRelationship{reEncodeRel}, nil)
+	db.SceneRelationship.On("FindBySceneID", testCtx, unrelatedID).Return(nil, nil)
+	db.SceneRelationship.On("FindBySceneID", testCtx, otherID).Return([]*models.SceneRelationship{otherRel}, nil)
+
+	tests := []struct {
+		name   string
+		groups [][]*models.Scene
+		want   [][]*models.Scene
+	}{
+		{
+			"directly related",
+			[][]*models.Scene{{original, remaster}},
+			nil,
+		},
+		{
+			"indirectly related",
+			[][]*models.Scene{{original, reEncode, remaster}},
+			nil,
+		},
+		{
+			"related through scene outside group",
+			[][]*models.Scene{{reEncode, other}},
+			[][]*models.Scene{{reEncode, other}},
+		},
+		{
+			"partially related",
+			[][]*models.Scene{{original, remaster, unrelated}},
+			[][]*models.Scene{{original, remaster, unrelated}},
+		},
+		{
+			"mixed groups",
+			[][]*models.Scene{{original, other}, {remaster, unrelated}},
+			[][]*models.Scene{{remaster, unrelated}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExcludeRelated(testCtx, db.SceneRelationship, tt.groups)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -40,6 +40,12 @@ type ScanHandler struct {
 
 	FileNamingAlgorithm models.HashAlgorithm
 	Paths               *paths.Paths
+
+	// SidecarReader reads the metadata of new scenes from sidecar files.
+	// Nil to not read sidecar files.
+	SidecarReader *SidecarReader
+	// TagFinderCreator is used to create the tags of sidecar files.
+	TagFinderCreator models.TagFinderCreator
 }
 
 func (h *ScanHandler) validate() error {
@@ -103,6 +109,11 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 
 		logger.Infof("%s doesn't exist. Creating new scene...", f.Base().Path)
 
+		if err := h.applySidecar(ctx, &newScene, videoFile); err != nil {
+			// don't fail the scan for an invalid sidecar file
+			logger.Warnf("Error reading sidecar metadata for %s: %v", videoFile.Path, err)
+		}
+
 		if err := h.CreatorUpdater.Create(ctx, &newScene, []models.FileID{videoFile.ID}); err != nil {
 			return fmt.Errorf("creating new scene: %w", err)
 		}
@@ -135,6 +146,19 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 	return nil
 }
 
+func (h *ScanHandler) applySidecar(ctx context.Context, newScene *models.Scene, f *models.VideoFile) error {
+	if h.SidecarReader == nil || f.ZipFileID != nil {
+		return nil
+	}
+
+	sidecar, err := h.SidecarReader.Read(f.Path)
+	if err != nil || sidecar == nil {
+		return err
+	}
+
+	return sidecar.Apply(ctx, newScene, h.TagFinderCreator)
+}
+
 func (h *ScanHandler) associateExisting(ctx context.Context, existing []*models.Scene, f *models.VideoFile, updateExisting bool) error {
 	for _, s := range existing {
 		if err := s.LoadFiles(ctx, h.CreatorUpdater); err != nil {
@@ -155,15 +179,26 @@ func (h *ScanHandler) associateExisting(ctx context.Context, existing []*models.
 			if err := h.CreatorUpdater.AddFileID(ctx, s.ID, f.ID); err != nil {
 				return fmt.Errorf("adding file to scene: %w", err)
 			}
+		}
 
+		restore := s.QuarantinedAt != nil
+		if restore {
+			logger.Infof("Restoring quarantined scene %s since %s was found", s.DisplayName(), f.Path)
+		}
+
+		if !found || restore {
 			// update updated_at time
 			scenePartial := models.NewScenePartial()
+			if restore {
+				scenePartial.QuarantinedAt = models.NewOptionalTimePtr(nil)
+			}
+
 			if _, err := h.CreatorUpdater.UpdatePartial(ctx, s.ID, scenePartial); err != nil {
 				return fmt.Errorf("updating scene: %w", err)
 			}
 		}
 
-		if !found || updateExisting {
+		if !found || updateExisting || restore {
 			h.PluginCache.RegisterPostHooks(ctx, s.ID, plugin.SceneUpdatePost, nil, nil)
 		}
 	}
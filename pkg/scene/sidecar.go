@@ -0,0 +1,290 @@
+package scene
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Sidecar is the scene metadata read from a sidecar file of a video file.
+type Sidecar struct {
+	Title   string
+	Details string
+	Date    *models.Date
+	URLs    []string
+	Tags    []string
+}
+
+// SidecarParser reads scene metadata from sidecar files of a single format.
+type SidecarParser interface {
+	// Paths returns the possible paths of the sidecar file of the video file,
+	// in order of preference.
+	Paths(videoPath string) []string
+	// Parse parses the contents of a sidecar file.
+	Parse(r io.Reader) (*Sidecar, error)
+}
+
+var (
+	sidecarParsersMutex sync.RWMutex
+	sidecarParsers      = map[string]SidecarParser{
+		"json": ytdlpSidecarParser{},
+		"nfo":  nfoSidecarParser{},
+		"txt":  textSidecarParser{},
+	}
+)
+
+// RegisterSidecarParser registers the parser of the sidecar format, replacing
+// any existing parser of the format.
+func RegisterSidecarParser(format string, p SidecarParser) {
+	sidecarParsersMutex.Lock()
+	defer sidecarParsersMutex.Unlock()
+
+	sidecarParsers[strings.ToLower(format)] = p
+}
+
+// SidecarFormats returns the formats that have a registered parser.
+func SidecarFormats() []string {
+	sidecarParsersMutex.RLock()
+	defer sidecarParsersMutex.RUnlock()
+
+	var ret []string
+	for f := range sidecarParsers {
+		ret = append(ret, f)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+func getSidecarParser(format string) SidecarParser {
+	sidecarParsersMutex.RLock()
+	defer sidecarParsersMutex.RUnlock()
+
+	return sidecarParsers[strings.ToLower(format)]
+}
+
+// SidecarReader reads the sidecar metadata of video files.
+type SidecarReader struct {
+	// Formats are the sidecar formats to read, in order of preference.
+	Formats []string
+}
+
+// Read returns the metadata of the first sidecar file found for the video
+// file. Returns nil if the video file has no sidecar file.
+func (r SidecarReader) Read(videoPath string) (*Sidecar, error) {
+	for _, format := range r.Formats {
+		p := getSidecarParser(format)
+		if p == nil {
+			logger.Warnf("no parser for sidecar format %q", format)
+			continue
+		}
+
+		for _, path := range p.Paths(videoPath) {
+			if exists, _ := fsutil.FileExists(path); !exists {
+				continue
+			}
+
+			ret, err := parseSidecarFile(p, path)
+			if err != nil {
+				return nil, fmt.Errorf("parsing sidecar %q: %w", path, err)
+			}
+
+			logger.Debugf("Read sidecar metadata for %s from %s", videoPath, path)
+			return ret, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func parseSidecarFile(p SidecarParser, path string) (*Sidecar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return p.Parse(f)
+}
+
+// Apply sets the metadata of the sidecar on the new scene. Tags that do not
+// exist are created.
+func (s Sidecar) Apply(ctx context.Context, newScene *models.Scene, tagQB models.TagFinderCreator) error {
+	if s.Title != "" {
+		newScene.Title = s.Title
+	}
+	if s.Details != "" {
+		newScene.Details = s.Details
+	}
+	if s.Date != nil {
+		newScene.Date = s.Date
+	}
+	if len(s.URLs) > 0 {
+		newScene.URLs = models.NewRelatedStrings(s.URLs)
+	}
+
+	if len(s.Tags) == 0 || tagQB == nil {
+		return nil
+	}
+
+	var tagIDs []int
+	for _, name := range s.Tags {
+		t, err := tagQB.FindByName(ctx, name, true)
+		if err != nil {
+			return fmt.Errorf("finding tag %q: %w", name, err)
+		}
+
+		if t == nil {
+			newTag := models.NewTag()
+			newTag.Name = name
+			if err := tagQB.Create(ctx, &newTag); err != nil {
+				return fmt.Errorf("creating tag %q: %w", name, err)
+			}
+			t = &newTag
+		}
+
+		tagIDs = append(tagIDs, t.ID)
+	}
+
+	newScene.TagIDs = models.NewRelatedIDs(tagIDs)
+
+	return nil
+}
+
+func sidecarPath(videoPath string, ext string) string {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + ext
+}
+
+// ytdlpSidecarParser parses yt-dlp info.json files.
+type ytdlpSidecarParser struct{}
+
+type ytdlpInfo struct {
+	Title       string   `json:"title"`
+	FullTitle   string   `json:"fulltitle"`
+	Description string   `json:"description"`
+	UploadDate  string   `json:"upload_date"`
+	ReleaseDate string   `json:"release_date"`
+	WebpageURL  string   `json:"webpage_url"`
+	Tags        []string `json:"tags"`
+	Categories  []string `json:"categories"`
+}
+
+func (ytdlpSidecarParser) Paths(videoPath string) []string {
+	return []string{
+		sidecarPath(videoPath, ".info.json"),
+		sidecarPath(videoPath, ".json"),
+	}
+}
+
+func (ytdlpSidecarParser) Parse(r io.Reader) (*Sidecar, error) {
+	var info ytdlpInfo
+	if err := json.NewDecoder(r).Decode(&info); err != nil {
+		return nil, fmt.Errorf("parsing json: %w", err)
+	}
+
+	ret := &Sidecar{
+		Title:   firstNonEmpty(info.Title, info.FullTitle),
+		Details: strings.TrimSpace(info.Description),
+		Tags:    uniqueNonEmpty(append(append([]string{}, info.Tags...), info.Categories...)),
+	}
+
+	// yt-dlp dates are formatted as YYYYMMDD
+	for _, d := range []string{info.ReleaseDate, info.UploadDate} {
+		if len(d) != 8 {
+			continue
+		}
+
+		if date, err := models.ParseDate(d[0:4] + "-" + d[4:6] + "-" + d[6:8]); err == nil {
+			ret.Date = &date
+			break
+		}
+	}
+
+	if u := strings.TrimSpace(info.WebpageURL); u != "" {
+		ret.URLs = []string{u}
+	}
+
+	return ret, nil
+}
+
+// nfoSidecarParser parses Kodi and Jellyfin NFO files.
+type nfoSidecarParser struct{}
+
+func (nfoSidecarParser) Paths(videoPath string) []string {
+	return nfoPaths(videoPath)
+}
+
+func (nfoSidecarParser) Parse(r io.Reader) (*Sidecar, error) {
+	nfo, err := ParseNFO(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sidecar{
+		Title:   nfo.GetTitle(),
+		Details: nfo.GetDetails(),
+		Date:    nfo.GetDate(),
+		Tags:    nfo.GetTagNames(),
+	}, nil
+}
+
+// textSidecarParser parses text files of "key: value" lines. The title,
+// description, date, url and tags keys are read. Multiple url and tags lines
+// may be present, and tags are separated by commas. Other lines are ignored.
+type textSidecarParser struct{}
+
+func (textSidecarParser) Paths(videoPath string) []string {
+	return []string{sidecarPath(videoPath, ".txt")}
+}
+
+func (textSidecarParser) Parse(r io.Reader) (*Sidecar, error) {
+	ret := &Sidecar{}
+	var tags []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			ret.Title = value
+		case "description", "details":
+			ret.Details = value
+		case "date":
+			if d, err := models.ParseDate(value); err == nil {
+				ret.Date = &d
+			}
+		case "url":
+			ret.URLs = append(ret.URLs, value)
+		case "tags":
+			tags = append(tags, strings.Split(value, ",")...)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading text: %w", err)
+	}
+
+	ret.Tags = uniqueNonEmpty(tags)
+
+	return ret, nil
+}
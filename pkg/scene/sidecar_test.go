@@ -0,0 +1,181 @@
+package scene
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testInfoJSON = `{
+	"id": "abc",
+	"title": "Video Title",
+	"description": "Description\n",
+	"upload_date": "20210203",
+	"webpage_url": "https://example.com/watch?v=abc",
+	"tags": ["Tag", "tag", ""],
+	"categories": ["Category"]
+}`
+
+const testSidecarText = `Title: Text Title
+Description: Text description
+Date: 2022-03-04
+URL: https://example.com/a
+URL: https://example.com/b
+Tags: One, Two
+Tags: two, Three
+not a key value line
+Unknown: ignored
+`
+
+func TestSidecarParsers(t *testing.T) {
+	jsonDate, _ := models.ParseDate("2021-02-03")
+	textDate, _ := models.ParseDate("2022-03-04")
+	nfoDate, _ := models.ParseDate("2021-02-03")
+
+	tests := []struct {
+		name   string
+		parser SidecarParser
+		input  string
+		want   *Sidecar
+	}{
+		{
+			"yt-dlp json",
+			ytdlpSidecarParser{},
+			testInfoJSON,
+			&Sidecar{
+				Title:   "Video Title",
+				Details: "Description",
+				Date:    &jsonDate,
+				URLs:    []string{"https://example.com/watch?v=abc"},
+				Tags:    []string{"Tag", "Category"},
+			},
+		},
+		{
+			"nfo",
+			nfoSidecarParser{},
+			testNFO,
+			&Sidecar{
+				Title:   "Original Title",
+				Details: "Outline",
+				Date:    &nfoDate,
+				Tags:    []string{"Drama", "Tag"},
+			},
+		},
+		{
+			"text",
+			textSidecarParser{},
+			testSidecarText,
+			&Sidecar{
+				Title:   "Text Title",
+				Details: "Text description",
+				Date:    &textDate,
+				URLs:    []string{"https://example.com/a", "https://example.com/b"},
+				Tags:    []string{"One", "Two", "Three"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.Parse(strings.NewReader(tt.input))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type testSidecarParser struct{}
+
+func (testSidecarParser) Paths(videoPath string) []string {
+	return []string{sidecarPath(videoPath, ".test")}
+}
+
+func (testSidecarParser) Parse(r io.Reader) (*Sidecar, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sidecar{Title: string(b)}, nil
+}
+
+func TestSidecarReader_Read(t *testing.T) {
+	RegisterSidecarParser("test", testSidecarParser{})
+	assert.Contains(t, SidecarFormats(), "test")
+
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "video.mp4")
+
+	writeFile := func(name, contents string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	r := SidecarReader{Formats: []string{"unknown", "test", "txt"}}
+
+	got, err := r.Read(videoPath)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+
+	writeFile("video.txt", testSidecarText)
+	got, err = r.Read(videoPath)
+	if assert.Nil(t, err) && assert.NotNil(t, got) {
+		assert.Equal(t, "Text Title", got.Title)
+	}
+
+	// earlier formats are preferred
+	writeFile("video.test", "Test Title")
+	got, err = r.Read(videoPath)
+	if assert.Nil(t, err) && assert.NotNil(t, got) {
+		assert.Equal(t, "Test Title", got.Title)
+	}
+
+	writeFile("video.info.json", "not json")
+	_, err = SidecarReader{Formats: []string{"json"}}.Read(videoPath)
+	assert.NotNil(t, err)
+}
+
+func TestSidecar_Apply(t *testing.T) {
+	const existingTagID = 1
+	const newTagID = 2
+
+	db := mocks.NewDatabase()
+	db.Tag.On("FindByName", testCtx, "Existing", true).Return(&models.Tag{ID: existingTagID}, nil).Once()
+	db.Tag.On("FindByName", testCtx, "New", true).Return(nil, nil).Once()
+	db.Tag.On("Create", testCtx, mock.MatchedBy(func(t *models.Tag) bool {
+		return t.Name == "New"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Tag).ID = newTagID
+	}).Return(nil).Once()
+
+	date, _ := models.ParseDate("2021-02-03")
+	sidecar := Sidecar{
+		Title: "Title",
+		Date:  &date,
+		URLs:  []string{"https://example.com"},
+		Tags:  []string{"Existing", "New"},
+	}
+
+	s := models.NewScene()
+	if !assert.Nil(t, sidecar.Apply(testCtx, &s, db.Tag)) {
+		return
+	}
+
+	assert.Equal(t, "Title", s.Title)
+	assert.Equal(t, "", s.Details)
+	assert.Equal(t, &date, s.Date)
+	assert.Equal(t, []string{"https://example.com"}, s.URLs.List())
+	assert.Equal(t, []int{existingTagID, newTagID}, s.TagIDs.List())
+
+	db.AssertExpectations(t)
+}
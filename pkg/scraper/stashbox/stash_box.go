@@ -932,8 +932,36 @@ func appendFingerprintUnique(v []*graphql.FingerprintInput, toAdd *graphql.Finge
 }
 
 func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, endpoint string, cover []byte) (*string, error) {
-	draft := graphql.SceneDraftInput{}
+	draft, err := c.SceneDraft(ctx, scene, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
 	var image io.Reader
+	if len(cover) > 0 {
+		image = bytes.NewReader(cover)
+	}
+
+	var id *string
+	var ret graphql.SubmitSceneDraft
+	err = c.submitDraft(ctx, graphql.SubmitSceneDraftDocument, draft, image, &ret)
+	id = ret.SubmitSceneDraft.ID
+
+	return id, err
+
+	// ret, err := c.client.SubmitSceneDraft(ctx, draft, uploadImage(image))
+	// if err != nil {
+	// 	return nil, err
+	// }
+
+	// id := ret.SubmitSceneDraft.ID
+	// return id, nil
+}
+
+// SceneDraft returns the draft submission of scene for the stash-box
+// endpoint. The draft does not include the scene cover.
+func (c Client) SceneDraft(ctx context.Context, scene *models.Scene, endpoint string) (*graphql.SceneDraftInput, error) {
+	draft := &graphql.SceneDraftInput{}
 	r := c.repository
 	pqb := r.Performer
 	sqb := r.Studio
@@ -1066,10 +1094,6 @@ func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, endpo
 	}
 	draft.Tags = tags
 
-	if len(cover) > 0 {
-		image = bytes.NewReader(cover)
-	}
-
 	if err := scene.LoadStashIDs(ctx, r.Scene); err != nil {
 		return nil, err
 	}
@@ -1085,25 +1109,41 @@ func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, endpo
 	}
 	draft.ID = stashID
 
+	return draft, nil
+}
+
+func (c Client) SubmitPerformerDraft(ctx context.Context, performer *models.Performer, endpoint string) (*string, error) {
+	draft, err := c.PerformerDraft(ctx, performer, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var image io.Reader
+	img, _ := c.repository.Performer.GetImage(ctx, performer.ID)
+	if img != nil {
+		image = bytes.NewReader(img)
+	}
+
 	var id *string
-	var ret graphql.SubmitSceneDraft
-	err = c.submitDraft(ctx, graphql.SubmitSceneDraftDocument, draft, image, &ret)
-	id = ret.SubmitSceneDraft.ID
+	var ret graphql.SubmitPerformerDraft
+	err = c.submitDraft(ctx, graphql.SubmitPerformerDraftDocument, draft, image, &ret)
+	id = ret.SubmitPerformerDraft.ID
 
 	return id, err
 
-	// ret, err := c.client.SubmitSceneDraft(ctx, draft, uploadImage(image))
+	// ret, err := c.client.SubmitPerformerDraft(ctx, draft, uploadImage(image))
 	// if err != nil {
 	// 	return nil, err
 	// }
 
-	// id := ret.SubmitSceneDraft.ID
+	// id := ret.SubmitPerformerDraft.ID
 	// return id, nil
 }
 
-func (c Client) SubmitPerformerDraft(ctx context.Context, performer *models.Performer, endpoint string) (*string, error) {
-	draft := graphql.PerformerDraftInput{}
-	var image io.Reader
+// PerformerDraft returns the draft submission of performer for the stash-box
+// endpoint. The draft does not include the performer image.
+func (c Client) PerformerDraft(ctx context.Context, performer *models.Performer, endpoint string) (*graphql.PerformerDraftInput, error) {
+	draft := &graphql.PerformerDraftInput{}
 	pqb := c.repository.Performer
 
 	if err := performer.LoadAliases(ctx, pqb); err != nil {
@@ -1114,11 +1154,6 @@ func (c Client) SubmitPerformerDraft(ctx context.Context, performer *models.Perf
 		return nil, err
 	}
 
-	img, _ := pqb.GetImage(ctx, performer.ID)
-	if img != nil {
-		image = bytes.NewReader(img)
-	}
-
 	if performer.Name != "" {
 		draft.Name = performer.Name
 	}
@@ -1216,20 +1251,7 @@ func (c Client) SubmitPerformerDraft(ctx context.Context, performer *models.Perf
 	}
 	draft.ID = stashID
 
-	var id *string
-	var ret graphql.SubmitPerformerDraft
-	err = c.submitDraft(ctx, graphql.SubmitPerformerDraftDocument, draft, image, &ret)
-	id = ret.SubmitPerformerDraft.ID
-
-	return id, err
-
-	// ret, err := c.client.SubmitPerformerDraft(ctx, draft, uploadImage(image))
-	// if err != nil {
-	// 	return nil, err
-	// }
-
-	// id := ret.SubmitPerformerDraft.ID
-	// return id, nil
+	return draft, nil
 }
 
 // we can't currently use this due to https://github.com/Yamashou/gqlgenc/issues/109
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"context"
+	"net/http"
+)
+
+const contentRatingLevelKey = "contentRatingLevel"
+
+// UnlockContentRating stores the unlocked content rating level in the session.
+func (s *Store) UnlockContentRating(w http.ResponseWriter, r *http.Request, level int) error {
+	// ignore error - we want a session regardless
+	session, _ := s.sessionStore.Get(r, cookieName)
+
+	session.Values[contentRatingLevelKey] = level
+
+	return session.Save(r, w)
+}
+
+// LockContentRating removes the unlocked content rating level from the session.
+func (s *Store) LockContentRating(w http.ResponseWriter, r *http.Request) error {
+	session, err := s.sessionStore.Get(r, cookieName)
+	if err != nil {
+		return err
+	}
+
+	delete(session.Values, contentRatingLevelKey)
+
+	return session.Save(r, w)
+}
+
+func (s *Store) ContentRatingHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// get the unlocked level from the cookie and set in the context
+			session, err := s.sessionStore.Get(r, cookieName)
+
+			// ignore errors
+			if err == nil {
+				level, _ := session.Values[contentRatingLevelKey].(int)
+
+				ctx := setContentRatingLevel(r.Context(), level)
+				r = r.WithContext(ctx)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetContentRatingLevel returns the content rating level unlocked in the
+// current session. Returns 0 if no level is unlocked.
+func GetContentRatingLevel(ctx context.Context) int {
+	level, _ := ctx.Value(contextContentRatingLevel).(int)
+	return level
+}
+
+func setContentRatingLevel(ctx context.Context, level int) context.Context {
+	return context.WithValue(ctx, contextContentRatingLevel, level)
+}
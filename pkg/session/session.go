@@ -16,11 +16,14 @@ type key int
 const (
 	contextUser key = iota
 	contextVisitedPlugins
+	contextContentRatingLevel
+	contextPluginRequest
 )
 
 const (
 	userIDKey         = "userID"
 	visitedPluginsKey = "visitedPlugins"
+	pluginKey         = "plugin"
 )
 
 const (
@@ -160,6 +163,12 @@ func (s *Store) VisitedPluginHandler() func(http.Handler) http.Handler {
 				visitedPlugins, _ := val.([]string)
 
 				ctx := setVisitedPlugins(r.Context(), visitedPlugins)
+
+				// sessions made with MakePluginCookie are only used by plugins
+				if isPlugin, _ := session.Values[pluginKey].(bool); isPlugin {
+					ctx = context.WithValue(ctx, contextPluginRequest, true)
+				}
+
 				r = r.WithContext(ctx)
 			}
 
@@ -183,6 +192,13 @@ func AddVisitedPlugin(ctx context.Context, pluginID string) context.Context {
 	return setVisitedPlugins(ctx, curVal)
 }
 
+// IsPluginRequest returns true if the request of the context was made by a
+// plugin.
+func IsPluginRequest(ctx context.Context) bool {
+	isPlugin, _ := ctx.Value(contextPluginRequest).(bool)
+	return isPlugin
+}
+
 func setVisitedPlugins(ctx context.Context, visitedPlugins []string) context.Context {
 	return context.WithValue(ctx, contextVisitedPlugins, visitedPlugins)
 }
@@ -197,6 +213,7 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 	}
 
 	session.Values[visitedPluginsKey] = visitedPlugins
+	session.Values[pluginKey] = true
 
 	encoded, err := securecookie.EncodeMulti(session.Name(), session.Values,
 		s.sessionStore.Codecs...)
@@ -208,10 +225,10 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 	return sessions.NewCookie(session.Name(), encoded, session.Options)
 }
 
-func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
-	c := s.config
-
-	// translate api key into current user, if present
+// GetRequestAPIKey returns the API key provided in the request header or, if
+// not present, the query parameters. Returns an empty string if no API key was
+// provided.
+func GetRequestAPIKey(r *http.Request) string {
 	apiKey := r.Header.Get(ApiKeyHeader)
 
 	// try getting the api key as a query parameter
@@ -219,6 +236,15 @@ func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID str
 		apiKey = r.URL.Query().Get(ApiKeyParameter)
 	}
 
+	return apiKey
+}
+
+func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
+	c := s.config
+
+	// translate api key into current user, if present
+	apiKey := GetRequestAPIKey(r)
+
 	if apiKey != "" {
 		// match against configured API and set userID to the
 		// configured username. In future, we'll want to
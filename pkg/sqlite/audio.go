@@ -0,0 +1,829 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const (
+	audioTable            = "audios"
+	audioIDColumn         = "audio_id"
+	audiosFilesTable      = "audios_files"
+	audiosTagsTable       = "audios_tags"
+	performersAudiosTable = "performers_audios"
+)
+
+type audioRow struct {
+	ID      int         `db:"id" goqu:"skipinsert"`
+	Title   zero.String `db:"title"`
+	Details zero.String `db:"details"`
+	Date    NullDate    `db:"date"`
+	// expressed as 1-100
+	Rating    null.Int  `db:"rating"`
+	Organized bool      `db:"organized"`
+	StudioID  null.Int  `db:"studio_id,omitempty"`
+	CreatedAt Timestamp `db:"created_at"`
+	UpdatedAt Timestamp `db:"updated_at"`
+}
+
+func (r *audioRow) fromAudio(o models.Audio) {
+	r.ID = o.ID
+	r.Title = zero.StringFrom(o.Title)
+	r.Details = zero.StringFrom(o.Details)
+	r.Date = NullDateFromDatePtr(o.Date)
+	r.Rating = intFromPtr(o.Rating)
+	r.Organized = o.Organized
+	r.StudioID = intFromPtr(o.StudioID)
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+type audioQueryRow struct {
+	audioRow
+	PrimaryFileID         null.Int    `db:"primary_file_id"`
+	PrimaryFileFolderPath zero.String `db:"primary_file_folder_path"`
+	PrimaryFileBasename   zero.String `db:"primary_file_basename"`
+}
+
+func (r *audioQueryRow) resolve() *models.Audio {
+	ret := &models.Audio{
+		ID:        r.ID,
+		Title:     r.Title.String,
+		Details:   r.Details.String,
+		Date:      r.Date.DatePtr(),
+		Rating:    nullIntPtr(r.Rating),
+		Organized: r.Organized,
+		StudioID:  nullIntPtr(r.StudioID),
+
+		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
+
+		CreatedAt: r.CreatedAt.Timestamp,
+		UpdatedAt: r.UpdatedAt.Timestamp,
+	}
+
+	if r.PrimaryFileFolderPath.Valid && r.PrimaryFileBasename.Valid {
+		ret.Path = filepath.Join(r.PrimaryFileFolderPath.String, r.PrimaryFileBasename.String)
+	}
+
+	return ret
+}
+
+type audioRowRecord struct {
+	updateRecord
+}
+
+func (r *audioRowRecord) fromPartial(o models.AudioPartial) {
+	r.setNullString("title", o.Title)
+	r.setNullString("details", o.Details)
+	r.setNullDate("date", o.Date)
+	r.setNullInt("rating", o.Rating)
+	r.setBool("organized", o.Organized)
+	r.setNullInt("studio_id", o.StudioID)
+	r.setTimestamp("created_at", o.CreatedAt)
+	r.setTimestamp("updated_at", o.UpdatedAt)
+}
+
+type AudioStore struct {
+	repository
+
+	tableMgr *table
+
+	fileStore *FileStore
+}
+
+func NewAudioStore(fileStore *FileStore) *AudioStore {
+	return &AudioStore{
+		repository: repository{
+			tableName: audioTable,
+			idColumn:  idColumn,
+		},
+		tableMgr:  audioTableMgr,
+		fileStore: fileStore,
+	}
+}
+
+func (qb *AudioStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *AudioStore) selectDataset() *goqu.SelectDataset {
+	table := qb.table()
+	files := fileTableMgr.table
+	folders := folderTableMgr.table
+
+	return dialect.From(table).LeftJoin(
+		audiosFilesJoinTable,
+		goqu.On(
+			audiosFilesJoinTable.Col(audioIDColumn).Eq(table.Col(idColumn)),
+			audiosFilesJoinTable.Col("primary").Eq(1),
+		),
+	).LeftJoin(
+		files,
+		goqu.On(files.Col(idColumn).Eq(audiosFilesJoinTable.Col(fileIDColumn))),
+	).LeftJoin(
+		folders,
+		goqu.On(folders.Col(idColumn).Eq(files.Col("parent_folder_id"))),
+	).Select(
+		qb.table().All(),
+		audiosFilesJoinTable.Col(fileIDColumn).As("primary_file_id"),
+		folders.Col("path").As("primary_file_folder_path"),
+		files.Col("basename").As("primary_file_basename"),
+	)
+}
+
+func (qb *AudioStore) Create(ctx context.Context, newObject *models.Audio, fileIDs []models.FileID) error {
+	var r audioRow
+	r.fromAudio(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	if len(fileIDs) > 0 {
+		const firstPrimary = true
+		if err := audiosFilesTableMgr.insertJoins(ctx, id, firstPrimary, fileIDs); err != nil {
+			return err
+		}
+	}
+
+	if newObject.PerformerIDs.Loaded() {
+		if err := audiosPerformersTableMgr.insertJoins(ctx, id, newObject.PerformerIDs.List()); err != nil {
+			return err
+		}
+	}
+	if newObject.TagIDs.Loaded() {
+		if err := audiosTagsTableMgr.insertJoins(ctx, id, newObject.TagIDs.List()); err != nil {
+			return err
+		}
+	}
+
+	updated, err := qb.find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *AudioStore) UpdatePartial(ctx context.Context, id int, partial models.AudioPartial) (*models.Audio, error) {
+	r := audioRowRecord{
+		updateRecord{
+			Record: make(exp.Record),
+		},
+	}
+
+	r.fromPartial(partial)
+
+	if len(r.Record) > 0 {
+		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
+			return nil, err
+		}
+	}
+
+	if partial.PerformerIDs != nil {
+		if err := audiosPerformersTableMgr.modifyJoins(ctx, id, partial.PerformerIDs.IDs, partial.PerformerIDs.Mode); err != nil {
+			return nil, err
+		}
+	}
+	if partial.TagIDs != nil {
+		if err := audiosTagsTableMgr.modifyJoins(ctx, id, partial.TagIDs.IDs, partial.TagIDs.Mode); err != nil {
+			return nil, err
+		}
+	}
+
+	if partial.PrimaryFileID != nil {
+		if err := audiosFilesTableMgr.setPrimary(ctx, id, *partial.PrimaryFileID); err != nil {
+			return nil, err
+		}
+	}
+
+	return qb.find(ctx, id)
+}
+
+func (qb *AudioStore) Destroy(ctx context.Context, id int) error {
+	return qb.tableMgr.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *AudioStore) Find(ctx context.Context, id int) (*models.Audio, error) {
+	ret, err := qb.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *AudioStore) FindMany(ctx context.Context, ids []int) ([]*models.Audio, error) {
+	audios := make([]*models.Audio, len(ids))
+
+	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
+		q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(batch))
+		unsorted, err := qb.getMany(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range unsorted {
+			i := sliceutil.Index(ids, s.ID)
+			audios[i] = s
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for i := range audios {
+		if audios[i] == nil {
+			return nil, fmt.Errorf("audio with id %d not found", ids[i])
+		}
+	}
+
+	return audios, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *AudioStore) find(ctx context.Context, id int) (*models.Audio, error) {
+	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+
+	ret, err := qb.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Audio, error) {
+	table := qb.table()
+
+	q := qb.selectDataset().Prepared(true).Where(
+		table.Col(idColumn).Eq(
+			sq,
+		),
+	)
+
+	return qb.getMany(ctx, q)
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *AudioStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.Audio, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *AudioStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Audio, error) {
+	const single = false
+	var ret []*models.Audio
+	var lastID int
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f audioQueryRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		i := f.resolve()
+
+		if i.ID == lastID {
+			return fmt.Errorf("internal error: multiple rows returned for single audio id %d", i.ID)
+		}
+		lastID = i.ID
+
+		ret = append(ret, i)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) GetFiles(ctx context.Context, id int) ([]models.File, error) {
+	fileIDs, err := qb.filesRepository().get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// use fileStore to load files
+	files, err := qb.fileStore.Find(ctx, fileIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (qb *AudioStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]models.FileID, error) {
+	const primaryOnly = false
+	return qb.filesRepository().getMany(ctx, ids, primaryOnly)
+}
+
+func (qb *AudioStore) FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Audio, error) {
+	table := qb.table()
+
+	sq := dialect.From(table).
+		InnerJoin(
+			audiosFilesJoinTable,
+			goqu.On(table.Col(idColumn).Eq(audiosFilesJoinTable.Col(audioIDColumn))),
+		).
+		Select(table.Col(idColumn)).Where(audiosFilesJoinTable.Col(fileIDColumn).Eq(fileID))
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting audio by file id %d: %w", fileID, err)
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
+	joinTable := audiosFilesJoinTable
+
+	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
+	return count(ctx, q)
+}
+
+func (qb *AudioStore) FindByFingerprints(ctx context.Context, fp []models.Fingerprint) ([]*models.Audio, error) {
+	table := qb.table()
+	fingerprintTable := fingerprintTableMgr.table
+
+	var ex []exp.Expression
+
+	for _, v := range fp {
+		ex = append(ex, goqu.And(
+			fingerprintTable.Col("type").Eq(v.Type),
+			fingerprintTable.Col("fingerprint").Eq(v.Fingerprint),
+		))
+	}
+
+	sq := dialect.From(table).
+		InnerJoin(
+			audiosFilesJoinTable,
+			goqu.On(table.Col(idColumn).Eq(audiosFilesJoinTable.Col(audioIDColumn))),
+		).
+		InnerJoin(
+			fingerprintTable,
+			goqu.On(fingerprintTable.Col(fileIDColumn).Eq(audiosFilesJoinTable.Col(fileIDColumn))),
+		).
+		Select(table.Col(idColumn)).Where(goqu.Or(ex...))
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting audio by fingerprints: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) Count(ctx context.Context) (int, error) {
+	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	return count(ctx, q)
+}
+
+func (qb *AudioStore) Size(ctx context.Context) (float64, error) {
+	table := qb.table()
+	fileTable := fileTableMgr.table
+	q := dialect.Select(
+		goqu.COALESCE(goqu.SUM(fileTable.Col("size")), 0),
+	).From(table).InnerJoin(
+		audiosFilesJoinTable,
+		goqu.On(table.Col(idColumn).Eq(audiosFilesJoinTable.Col(audioIDColumn))),
+	).InnerJoin(
+		fileTable,
+		goqu.On(audiosFilesJoinTable.Col(fileIDColumn).Eq(fileTable.Col(idColumn))),
+	)
+	var ret float64
+	if err := querySimple(ctx, q, &ret); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) Duration(ctx context.Context) (float64, error) {
+	table := qb.table()
+	audioFileTable := audioFileTableMgr.table
+
+	q := dialect.Select(
+		goqu.COALESCE(goqu.SUM(audioFileTable.Col("duration")), 0),
+	).From(table).InnerJoin(
+		audiosFilesJoinTable,
+		goqu.On(audiosFilesJoinTable.Col(audioIDColumn).Eq(table.Col(idColumn))),
+	).InnerJoin(
+		audioFileTable,
+		goqu.On(audioFileTable.Col(fileIDColumn).Eq(audiosFilesJoinTable.Col(fileIDColumn))),
+	)
+
+	var ret float64
+	if err := querySimple(ctx, q, &ret); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
+
+func (qb *AudioStore) All(ctx context.Context) ([]*models.Audio, error) {
+	return qb.getMany(ctx, qb.selectDataset())
+}
+
+func (qb *AudioStore) validateFilter(audioFilter *models.AudioFilterType) error {
+	const and = "AND"
+	const or = "OR"
+	const not = "NOT"
+
+	if audioFilter.And != nil {
+		if audioFilter.Or != nil {
+			return illegalFilterCombination(and, or)
+		}
+		if audioFilter.Not != nil {
+			return illegalFilterCombination(and, not)
+		}
+
+		return qb.validateFilter(audioFilter.And)
+	}
+
+	if audioFilter.Or != nil {
+		if audioFilter.Not != nil {
+			return illegalFilterCombination(or, not)
+		}
+
+		return qb.validateFilter(audioFilter.Or)
+	}
+
+	if audioFilter.Not != nil {
+		return qb.validateFilter(audioFilter.Not)
+	}
+
+	return nil
+}
+
+func (qb *AudioStore) makeFilter(ctx context.Context, audioFilter *models.AudioFilterType) *filterBuilder {
+	query := &filterBuilder{}
+
+	if audioFilter.And != nil {
+		query.and(qb.makeFilter(ctx, audioFilter.And))
+	}
+	if audioFilter.Or != nil {
+		query.or(qb.makeFilter(ctx, audioFilter.Or))
+	}
+	if audioFilter.Not != nil {
+		query.not(qb.makeFilter(ctx, audioFilter.Not))
+	}
+
+	query.handleCriterion(ctx, intCriterionHandler(audioFilter.ID, "audios.id", nil))
+	query.handleCriterion(ctx, stringCriterionHandler(audioFilter.Title, "audios.title"))
+	query.handleCriterion(ctx, stringCriterionHandler(audioFilter.Details, "audios.details"))
+	query.handleCriterion(ctx, pathCriterionHandler(audioFilter.Path, "folders.path", "files.basename", qb.addFoldersTable))
+	query.handleCriterion(ctx, criterionHandlerFunc(func(ctx context.Context, f *filterBuilder) {
+		if audioFilter.Checksum != nil {
+			qb.addAudiosFilesTable(f)
+			f.addInnerJoin(fingerprintTable, "fingerprints_md5", "audios_files.file_id = fingerprints_md5.file_id AND fingerprints_md5.type = 'md5'")
+		}
+
+		stringCriterionHandler(audioFilter.Checksum, "fingerprints_md5.fingerprint")(ctx, f)
+	}))
+	query.handleCriterion(ctx, intCriterionHandler(audioFilter.Rating100, "audios.rating", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(audioFilter.Organized, "audios.organized", nil))
+	query.handleCriterion(ctx, dateCriterionHandler(audioFilter.Date, "audios.date"))
+	query.handleCriterion(ctx, floatIntCriterionHandler(audioFilter.Duration, "audio_files.duration", qb.addAudioFilesTable))
+	query.handleCriterion(ctx, codecCriterionHandler(audioFilter.AudioCodec, "audio_files.audio_codec", qb.addAudioFilesTable))
+
+	query.handleCriterion(ctx, studioCriterionHandler(audioTable, audioFilter.Studios))
+	query.handleCriterion(ctx, audioTagsCriterionHandler(qb, audioFilter.Tags))
+	query.handleCriterion(ctx, audioPerformersCriterionHandler(qb, audioFilter.Performers))
+	query.handleCriterion(ctx, timestampCriterionHandler(audioFilter.CreatedAt, "audios.created_at"))
+	query.handleCriterion(ctx, timestampCriterionHandler(audioFilter.UpdatedAt, "audios.updated_at"))
+
+	return query
+}
+
+func (qb *AudioStore) addAudiosFilesTable(f *filterBuilder) {
+	f.addLeftJoin(audiosFilesTable, "", "audios_files.audio_id = audios.id")
+}
+
+func (qb *AudioStore) addFilesTable(f *filterBuilder) {
+	qb.addAudiosFilesTable(f)
+	f.addLeftJoin(fileTable, "", "audios_files.file_id = files.id")
+}
+
+func (qb *AudioStore) addFoldersTable(f *filterBuilder) {
+	qb.addFilesTable(f)
+	f.addLeftJoin(folderTable, "", "files.parent_folder_id = folders.id")
+}
+
+func (qb *AudioStore) addAudioFilesTable(f *filterBuilder) {
+	qb.addAudiosFilesTable(f)
+	f.addLeftJoin(audioFileTable, "", "audio_files.file_id = audios_files.file_id")
+}
+
+func (qb *AudioStore) makeQuery(ctx context.Context, audioFilter *models.AudioFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
+	if audioFilter == nil {
+		audioFilter = &models.AudioFilterType{}
+	}
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	query := qb.newQuery()
+	distinctIDs(&query, audioTable)
+
+	if q := findFilter.Q; q != nil && *q != "" {
+		query.addJoins(
+			join{
+				table:    audiosFilesTable,
+				onClause: "audios_files.audio_id = audios.id",
+			},
+			join{
+				table:    fileTable,
+				onClause: "audios_files.file_id = files.id",
+			},
+			join{
+				table:    folderTable,
+				onClause: "files.parent_folder_id = folders.id",
+			},
+		)
+
+		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
+		searchColumns := []string{"audios.title", "audios.details", filepathColumn}
+		query.parseQueryString(searchColumns, *q)
+	}
+
+	if err := models.ValidateFilter("audio_filter", audioFilter); err != nil {
+		return nil, err
+	}
+	if err := qb.validateFilter(audioFilter); err != nil {
+		return nil, err
+	}
+	filter := qb.makeFilter(ctx, audioFilter)
+
+	if err := query.addFilter(filter); err != nil {
+		return nil, err
+	}
+
+	qb.setAudioSortAndPagination(&query, findFilter)
+
+	return &query, nil
+}
+
+func (qb *AudioStore) Query(ctx context.Context, options models.AudioQueryOptions) (*models.AudioQueryResult, error) {
+	query, err := qb.makeQuery(ctx, options.AudioFilter, options.FindFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := qb.queryGroupedFields(ctx, options, *query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying aggregate fields: %w", err)
+	}
+
+	idsResult, err := query.findIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding IDs: %w", err)
+	}
+
+	result.IDs = idsResult
+	return result, nil
+}
+
+func (qb *AudioStore) queryGroupedFields(ctx context.Context, options models.AudioQueryOptions, query queryBuilder) (*models.AudioQueryResult, error) {
+	if !options.Count && !options.TotalDuration && !options.TotalSize {
+		// nothing to do - return empty result
+		return models.NewAudioQueryResult(qb), nil
+	}
+
+	aggregateQuery := qb.newQuery()
+
+	if options.Count {
+		aggregateQuery.addColumn("COUNT(DISTINCT temp.id) as total")
+	}
+
+	if options.TotalDuration {
+		query.addJoins(
+			join{
+				table:    audiosFilesTable,
+				onClause: "audios_files.audio_id = audios.id",
+			},
+			join{
+				table:    audioFileTable,
+				onClause: "audios_files.file_id = audio_files.file_id",
+			},
+		)
+		query.addColumn("COALESCE(audio_files.duration, 0) as duration")
+		aggregateQuery.addColumn("SUM(temp.duration) as duration")
+	}
+
+	if options.TotalSize {
+		query.addJoins(
+			join{
+				table:    audiosFilesTable,
+				onClause: "audios_files.audio_id = audios.id",
+			},
+			join{
+				table:    fileTable,
+				onClause: "audios_files.file_id = files.id",
+			},
+		)
+		query.addColumn("COALESCE(files.size, 0) as size")
+		aggregateQuery.addColumn("SUM(temp.size) as size")
+	}
+
+	const includeSortPagination = false
+	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(includeSortPagination))
+
+	out := struct {
+		Total    int
+		Duration null.Float
+		Size     null.Float
+	}{}
+	if err := qb.repository.queryStruct(ctx, aggregateQuery.toSQL(includeSortPagination), query.args, &out); err != nil {
+		return nil, err
+	}
+
+	ret := models.NewAudioQueryResult(qb)
+	ret.Count = out.Total
+	ret.TotalDuration = out.Duration.Float64
+	ret.TotalSize = out.Size.Float64
+	return ret, nil
+}
+
+func (qb *AudioStore) QueryCount(ctx context.Context, audioFilter *models.AudioFilterType, findFilter *models.FindFilterType) (int, error) {
+	query, err := qb.makeQuery(ctx, audioFilter, findFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	return query.executeCount(ctx)
+}
+
+func audioTagsCriterionHandler(qb *AudioStore, tags *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
+	h := joinedHierarchicalMultiCriterionHandlerBuilder{
+		tx: qb.tx,
+
+		primaryTable: audioTable,
+		foreignTable: tagTable,
+		foreignFK:    "tag_id",
+
+		relationsTable: "tags_relations",
+		joinAs:         "audio_tag",
+		joinTable:      audiosTagsTable,
+		primaryFK:      audioIDColumn,
+	}
+
+	return h.handler(tags)
+}
+
+func audioPerformersCriterionHandler(qb *AudioStore, performers *models.MultiCriterionInput) criterionHandlerFunc {
+	h := joinedMultiCriterionHandlerBuilder{
+		primaryTable: audioTable,
+		joinTable:    performersAudiosTable,
+		joinAs:       "performers_join",
+		primaryFK:    audioIDColumn,
+		foreignFK:    performerIDColumn,
+
+		addJoinTable: func(f *filterBuilder) {
+			qb.performersRepository().join(f, "performers_join", "audios.id")
+		},
+	}
+
+	return h.handler(performers)
+}
+
+func (qb *AudioStore) setAudioSortAndPagination(q *queryBuilder, findFilter *models.FindFilterType) {
+	sortClause := ""
+
+	if findFilter != nil && findFilter.Sort != nil && *findFilter.Sort != "" {
+		sort := findFilter.GetSort("title")
+		direction := findFilter.GetDirection()
+
+		// translate sort field
+		if sort == "file_mod_time" {
+			sort = "mod_time"
+		}
+
+		addFilesJoin := func() {
+			q.addJoins(
+				join{
+					table:    audiosFilesTable,
+					onClause: "audios_files.audio_id = audios.id",
+				},
+				join{
+					table:    fileTable,
+					onClause: "audios_files.file_id = files.id",
+				},
+			)
+		}
+
+		addFolderJoin := func() {
+			q.addJoins(join{
+				table:    folderTable,
+				onClause: "files.parent_folder_id = folders.id",
+			})
+		}
+
+		addAudioFileJoin := func() {
+			addFilesJoin()
+			q.addJoins(join{
+				table:    audioFileTable,
+				onClause: "audios_files.file_id = audio_files.file_id",
+			})
+		}
+
+		switch sort {
+		case "path":
+			addFilesJoin()
+			addFolderJoin()
+			sortClause = " ORDER BY COALESCE(folders.path, '') || COALESCE(files.basename, '') COLLATE NATURAL_CI " + direction
+		case "tag_count":
+			sortClause = getCountSort(audioTable, audiosTagsTable, audioIDColumn, direction)
+		case "performer_count":
+			sortClause = getCountSort(audioTable, performersAudiosTable, audioIDColumn, direction)
+		case "mod_time", "filesize":
+			addFilesJoin()
+			sortClause = getSort(sort, direction, "files")
+		case "duration", "bit_rate":
+			addAudioFileJoin()
+			sortClause = getSort(sort, direction, "audio_files")
+		case "title":
+			addFilesJoin()
+			addFolderJoin()
+			sortClause = " ORDER BY COALESCE(audios.title, files.basename) COLLATE NATURAL_CI " + direction + ", folders.path COLLATE NATURAL_CI " + direction
+		default:
+			sortClause = getSort(sort, direction, "audios")
+		}
+
+		// Whatever the sorting, always use title/id as a final sort
+		sortClause += ", COALESCE(audios.title, audios.id) COLLATE NATURAL_CI ASC"
+	}
+
+	q.sortAndPagination = sortClause + getPagination(findFilter)
+}
+
+func (qb *AudioStore) filesRepository() *filesRepository {
+	return &filesRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: audiosFilesTable,
+			idColumn:  audioIDColumn,
+		},
+	}
+}
+
+func (qb *AudioStore) AddFileID(ctx context.Context, id int, fileID models.FileID) error {
+	const firstPrimary = false
+	return audiosFilesTableMgr.insertJoins(ctx, id, firstPrimary, []models.FileID{fileID})
+}
+
+func (qb *AudioStore) performersRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: performersAudiosTable,
+			idColumn:  audioIDColumn,
+		},
+		fkColumn: performerIDColumn,
+	}
+}
+
+func (qb *AudioStore) GetPerformerIDs(ctx context.Context, audioID int) ([]int, error) {
+	return qb.performersRepository().getIDs(ctx, audioID)
+}
+
+func (qb *AudioStore) tagsRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: audiosTagsTable,
+			idColumn:  audioIDColumn,
+		},
+		fkColumn:     tagIDColumn,
+		foreignTable: tagTable,
+		orderBy:      "tags.name ASC",
+	}
+}
+
+func (qb *AudioStore) GetTagIDs(ctx context.Context, audioID int) ([]int, error) {
+	return qb.tagsRepository().getIDs(ctx, audioID)
+}
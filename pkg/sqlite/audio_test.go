@@ -0,0 +1,210 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createAudioFile(ctx context.Context, basename string, duration float64, codec string) (*models.AudioFile, error) {
+	f := &models.AudioFile{
+		BaseFile: &models.BaseFile{
+			Basename:       basename,
+			ParentFolderID: folderIDs[folderIdxWithFiles],
+			Fingerprints: []models.Fingerprint{
+				{
+					Type:        models.FingerprintTypeMD5,
+					Fingerprint: "md5_" + basename,
+				},
+			},
+			Size: 1024,
+		},
+		Format:     "mp3",
+		Duration:   duration,
+		AudioCodec: codec,
+		BitRate:    128000,
+	}
+
+	if err := db.File.Create(ctx, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func TestAudioCRUD(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Audio
+
+		f, err := createAudioFile(ctx, "audio_crud.mp3", 125.5, "mp3")
+		if err != nil {
+			t.Errorf("Error creating audio file: %s", err.Error())
+			return nil
+		}
+
+		newAudio := models.NewAudio()
+		newAudio.Title = "audio title"
+		newAudio.TagIDs = models.NewRelatedIDs([]int{tagIDs[tagIdxWithScene]})
+		newAudio.PerformerIDs = models.NewRelatedIDs([]int{performerIDs[performerIdxWithScene]})
+
+		if err := qb.Create(ctx, &newAudio, []models.FileID{f.ID}); err != nil {
+			t.Errorf("Error creating audio: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(ctx, newAudio.ID)
+		if err != nil {
+			t.Errorf("Error finding audio: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, "audio title", found.Title)
+		assert.Equal(t, f.ID, *found.PrimaryFileID)
+		assert.Equal(t, filepath.Join(getFolderPath(folderIdxWithFiles, nil), "audio_crud.mp3"), found.Path)
+
+		if err := found.LoadFiles(ctx, qb); err != nil {
+			t.Errorf("Error loading audio files: %s", err.Error())
+			return nil
+		}
+
+		primary, ok := found.Files.Primary().(*models.AudioFile)
+		if !ok {
+			t.Errorf("primary file is %T, expected *models.AudioFile", found.Files.Primary())
+			return nil
+		}
+		assert.Equal(t, 125.5, primary.Duration)
+		assert.Equal(t, "mp3", primary.AudioCodec)
+		assert.Equal(t, int64(128000), primary.BitRate)
+
+		tagIDs, err := qb.GetTagIDs(ctx, found.ID)
+		if err != nil {
+			t.Errorf("Error getting audio tags: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, tagIDs, 1)
+
+		byFile, err := qb.FindByFileID(ctx, f.ID)
+		if err != nil {
+			t.Errorf("Error finding audio by file id: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, byFile, 1)
+
+		byFingerprint, err := qb.FindByFingerprints(ctx, f.Fingerprints)
+		if err != nil {
+			t.Errorf("Error finding audio by fingerprints: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, byFingerprint, 1)
+
+		partial := models.NewAudioPartial()
+		partial.Organized = models.NewOptionalBool(true)
+		partial.TagIDs = &models.UpdateIDs{
+			Mode: models.RelationshipUpdateModeSet,
+		}
+
+		updated, err := qb.UpdatePartial(ctx, found.ID, partial)
+		if err != nil {
+			t.Errorf("Error updating audio: %s", err.Error())
+			return nil
+		}
+		assert.True(t, updated.Organized)
+
+		tagIDs, err = qb.GetTagIDs(ctx, found.ID)
+		if err != nil {
+			t.Errorf("Error getting audio tags: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, tagIDs, 0)
+
+		if err := qb.Destroy(ctx, found.ID); err != nil {
+			t.Errorf("Error destroying audio: %s", err.Error())
+			return nil
+		}
+
+		found, err = qb.Find(ctx, newAudio.ID)
+		if err != nil {
+			t.Errorf("Error finding audio: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
+
+func TestAudioQuery(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Audio
+
+		short, err := createAudioFile(ctx, "audio_short.ogg", 30, "vorbis")
+		if err != nil {
+			t.Errorf("Error creating audio file: %s", err.Error())
+			return nil
+		}
+		long, err := createAudioFile(ctx, "audio_long.flac", 3600, "flac")
+		if err != nil {
+			t.Errorf("Error creating audio file: %s", err.Error())
+			return nil
+		}
+
+		shortAudio := models.NewAudio()
+		if err := qb.Create(ctx, &shortAudio, []models.FileID{short.ID}); err != nil {
+			t.Errorf("Error creating audio: %s", err.Error())
+			return nil
+		}
+		longAudio := models.NewAudio()
+		if err := qb.Create(ctx, &longAudio, []models.FileID{long.ID}); err != nil {
+			t.Errorf("Error creating audio: %s", err.Error())
+			return nil
+		}
+
+		result, err := qb.Query(ctx, models.AudioQueryOptions{
+			QueryOptions: models.QueryOptions{
+				Count: true,
+			},
+			AudioFilter: &models.AudioFilterType{
+				Duration: &models.IntCriterionInput{
+					Value:    60,
+					Modifier: models.CriterionModifierGreaterThan,
+				},
+			},
+			TotalDuration: true,
+		})
+		if err != nil {
+			t.Errorf("Error querying audio: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, 1, result.Count)
+		assert.Equal(t, []int{longAudio.ID}, result.IDs)
+		assert.Equal(t, 3600.0, result.TotalDuration)
+
+		count, err := qb.QueryCount(ctx, &models.AudioFilterType{
+			AudioCodec: &models.StringCriterionInput{
+				Value:    "vorbis",
+				Modifier: models.CriterionModifierEquals,
+			},
+		}, nil)
+		if err != nil {
+			t.Errorf("Error counting audio: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 1, count)
+
+		duration, err := qb.Duration(ctx)
+		if err != nil {
+			t.Errorf("Error getting audio duration: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 3630.0, duration)
+
+		return nil
+	})
+}
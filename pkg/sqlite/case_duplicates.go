@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// fileJoinTables are the tables that join objects to their files, with the
+// column of the object ID.
+var fileJoinTables = []struct {
+	table    string
+	idColumn string
+}{
+	{scenesFilesTable, sceneIDColumn},
+	{imagesFilesTable, imageIDColumn},
+	{galleriesFilesTable, galleryIDColumn},
+	{audiosFilesTable, audioIDColumn},
+}
+
+// MergedPaths is the number of folders and files that were merged into
+// another folder or file with the same path apart from case.
+type MergedPaths struct {
+	Folders int `json:"folders"`
+	Files   int `json:"files"`
+}
+
+type caseDuplicateFolder struct {
+	ID   int    `db:"id"`
+	Path string `db:"path"`
+}
+
+// MergeCaseDuplicatePaths merges folders whose paths differ only by case
+// into the earliest created folder, and files in the same folder whose
+// basenames differ only by case into the earliest created file. The objects
+// of merged files are moved to the remaining file. Only the case of ASCII
+// characters is ignored. Must be called within a write transaction.
+func (db *Database) MergeCaseDuplicatePaths(ctx context.Context) (*MergedPaths, error) {
+	wrapper := dbWrapper{}
+	ret := &MergedPaths{}
+
+	// parent folders are merged before the folders within them
+	var folders []caseDuplicateFolder
+	if err := wrapper.Select(ctx, &folders, `SELECT id, path FROM folders
+WHERE lower(path) IN (SELECT lower(path) FROM folders GROUP BY lower(path) HAVING COUNT(*) > 1)
+ORDER BY length(path), id`); err != nil {
+		return nil, fmt.Errorf("finding duplicate folders: %w", err)
+	}
+
+	keep := make(map[string]int)
+	for _, f := range folders {
+		key := strings.ToLower(f.Path)
+		keepID, found := keep[key]
+		if !found {
+			keep[key] = f.ID
+			continue
+		}
+
+		if err := db.mergeFolder(ctx, keepID, f.ID, ret); err != nil {
+			return nil, fmt.Errorf("merging folder %q: %w", f.Path, err)
+		}
+		ret.Folders++
+	}
+
+	// files within the same folder that were not merged with their folders
+	type filePair struct {
+		Keep int `db:"keep_id"`
+		Dup  int `db:"dup_id"`
+	}
+	var files []filePair
+	if err := wrapper.Select(ctx, &files, `SELECT MIN(k.id) AS keep_id, f.id AS dup_id FROM files f
+INNER JOIN files k ON k.parent_folder_id = f.parent_folder_id AND lower(k.basename) = lower(f.basename) AND k.id < f.id
+GROUP BY f.id`); err != nil {
+		return nil, fmt.Errorf("finding duplicate files: %w", err)
+	}
+
+	for _, f := range files {
+		if err := db.mergeFile(ctx, f.Keep, f.Dup); err != nil {
+			return nil, fmt.Errorf("merging file %d: %w", f.Dup, err)
+		}
+		ret.Files++
+	}
+
+	return ret, nil
+}
+
+// mergeFolder moves the files and folders in the folder dup to the folder
+// keep, and deletes dup.
+func (db *Database) mergeFolder(ctx context.Context, keep, dup int, merged *MergedPaths) error {
+	wrapper := dbWrapper{}
+
+	type file struct {
+		ID       int    `db:"id"`
+		Basename string `db:"basename"`
+	}
+	var files []file
+	if err := wrapper.Select(ctx, &files, "SELECT id, basename FROM files WHERE parent_folder_id = ?", dup); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		var existing []int
+		if err := wrapper.Select(ctx, &existing, "SELECT id FROM files WHERE parent_folder_id = ? AND lower(basename) = lower(?) ORDER BY id LIMIT 1", keep, f.Basename); err != nil {
+			return err
+		}
+
+		if len(existing) > 0 {
+			if err := db.mergeFile(ctx, existing[0], f.ID); err != nil {
+				return fmt.Errorf("merging file %q: %w", f.Basename, err)
+			}
+			merged.Files++
+			continue
+		}
+
+		if _, err := wrapper.Exec(ctx, "UPDATE files SET parent_folder_id = ? WHERE id = ?", keep, f.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, stmt := range []string{
+		"UPDATE folders SET parent_folder_id = ?1 WHERE parent_folder_id = ?2",
+		// a folder can only have one gallery
+		"UPDATE galleries SET folder_id = ?1 WHERE folder_id = ?2 AND NOT EXISTS (SELECT 1 FROM galleries WHERE folder_id = ?1)",
+	} {
+		if _, err := wrapper.Exec(ctx, stmt, keep, dup); err != nil {
+			return err
+		}
+	}
+
+	_, err := wrapper.Exec(ctx, "DELETE FROM folders WHERE id = ?", dup)
+	return err
+}
+
+// mergeFile moves the objects of the file dup to the file keep, and deletes
+// dup. Objects that have dup as their primary file have keep as their
+// primary file.
+func (db *Database) mergeFile(ctx context.Context, keep, dup int) error {
+	wrapper := dbWrapper{}
+
+	for _, t := range fileJoinTables {
+		for _, stmt := range []string{
+			// objects joined to both files keep the primary flag of dup
+			fmt.Sprintf(`DELETE FROM %[1]s WHERE file_id = ?1 AND %[2]s IN (SELECT %[2]s FROM %[1]s WHERE file_id = ?2 AND "primary" = 1)`, t.table, t.idColumn),
+			fmt.Sprintf(`DELETE FROM %[1]s WHERE file_id = ?2 AND %[2]s IN (SELECT %[2]s FROM %[1]s WHERE file_id = ?1)`, t.table, t.idColumn),
+			fmt.Sprintf(`UPDATE %s SET file_id = ?1 WHERE file_id = ?2`, t.table),
+		} {
+			if _, err := wrapper.Exec(ctx, stmt, keep, dup); err != nil {
+				return fmt.Errorf("merging %s: %w", t.table, err)
+			}
+		}
+	}
+
+	for _, stmt := range []string{
+		"UPDATE files SET zip_file_id = ?1 WHERE zip_file_id = ?2",
+		"UPDATE folders SET zip_file_id = ?1 WHERE zip_file_id = ?2",
+		"DELETE FROM files WHERE id = ?2",
+	} {
+		if _, err := wrapper.Exec(ctx, stmt, keep, dup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
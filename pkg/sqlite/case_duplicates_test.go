@@ -0,0 +1,158 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestFolder(ctx context.Context, path string, parentID *models.FolderID) (*models.Folder, error) {
+	f := &models.Folder{
+		Path:           path,
+		ParentFolderID: parentID,
+		DirEntry: models.DirEntry{
+			ModTime: time.Now(),
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	return f, db.Folder.Create(ctx, f)
+}
+
+func createTestFile(ctx context.Context, folder *models.Folder, basename string) (*models.BaseFile, error) {
+	f := &models.BaseFile{
+		Path:           filepath.Join(folder.Path, basename),
+		ParentFolderID: folder.ID,
+		Basename:       basename,
+		DirEntry: models.DirEntry{
+			ModTime: time.Now(),
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	return f, db.File.Create(ctx, f)
+}
+
+func TestDatabase_MergeCaseDuplicatePaths(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		root := filepath.Join(string(filepath.Separator), "CaseDuplicates")
+
+		keepFolder, err := createTestFolder(ctx, root, nil)
+		if err != nil {
+			t.Errorf("Folder.Create() error = %v", err)
+			return nil
+		}
+		dupFolder, err := createTestFolder(ctx, filepath.Join(string(filepath.Separator), "caseduplicates"), nil)
+		if err != nil {
+			t.Errorf("Folder.Create() error = %v", err)
+			return nil
+		}
+		subFolder, err := createTestFolder(ctx, filepath.Join(dupFolder.Path, "sub"), &dupFolder.ID)
+		if err != nil {
+			t.Errorf("Folder.Create() error = %v", err)
+			return nil
+		}
+
+		keepFile, err := createTestFile(ctx, keepFolder, "a.mp4")
+		if err != nil {
+			t.Errorf("File.Create() error = %v", err)
+			return nil
+		}
+		dupFile, err := createTestFile(ctx, dupFolder, "A.MP4")
+		if err != nil {
+			t.Errorf("File.Create() error = %v", err)
+			return nil
+		}
+		otherFile, err := createTestFile(ctx, dupFolder, "b.mp4")
+		if err != nil {
+			t.Errorf("File.Create() error = %v", err)
+			return nil
+		}
+
+		scene := &models.Scene{}
+		if err := db.Scene.Create(ctx, scene, []models.FileID{dupFile.ID}); err != nil {
+			t.Errorf("Scene.Create() error = %v", err)
+			return nil
+		}
+
+		// paths are compared case-insensitively only if enabled
+		found, err := db.Folder.FindByPath(ctx, dupFolder.Path)
+		if err != nil {
+			t.Errorf("Folder.FindByPath() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, dupFolder.ID, found.ID)
+		}
+
+		db.SetCaseInsensitivePaths(true)
+		found, err = db.Folder.FindByPath(ctx, filepath.Join(string(filepath.Separator), "CASEDUPLICATES"))
+		foundFile, fileErr := db.File.FindByPath(ctx, filepath.Join(root, "B.mp4"))
+		db.SetCaseInsensitivePaths(false)
+
+		if err != nil {
+			t.Errorf("Folder.FindByPath() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, keepFolder.ID, found.ID)
+		}
+		if fileErr != nil {
+			t.Errorf("File.FindByPath() error = %v", fileErr)
+			return nil
+		}
+		if assert.NotNil(t, foundFile) {
+			assert.Equal(t, otherFile.ID, foundFile.Base().ID)
+		}
+
+		merged, err := db.MergeCaseDuplicatePaths(ctx)
+		if err != nil {
+			t.Errorf("MergeCaseDuplicatePaths() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 1, merged.Folders)
+		assert.Equal(t, 1, merged.Files)
+
+		_, err = db.Folder.Find(ctx, dupFolder.ID)
+		assert.NotNil(t, err)
+
+		found, err = db.Folder.Find(ctx, subFolder.ID)
+		if err != nil {
+			t.Errorf("Folder.Find() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, &keepFolder.ID, found.ParentFolderID)
+		}
+
+		files, err := db.File.Find(ctx, otherFile.ID)
+		if err != nil {
+			t.Errorf("File.Find() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, keepFolder.ID, files[0].Base().ParentFolderID)
+
+		_, err = db.File.Find(ctx, dupFile.ID)
+		assert.NotNil(t, err)
+
+		got, err := db.Scene.Find(ctx, scene.ID)
+		if err != nil {
+			t.Errorf("Scene.Find() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, got.PrimaryFileID) {
+			assert.Equal(t, keepFile.ID, *got.PrimaryFileID)
+		}
+
+		return nil
+	})
+}
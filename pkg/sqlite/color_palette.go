@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	colorColumn      = "color"
+	hueColumn        = "hue"
+	saturationColumn = "saturation"
+	brightnessColumn = "brightness"
+)
+
+// colorPaletteTable stores the ordered color palette of an object. The
+// first color is the dominant color.
+type colorPaletteTable struct {
+	table
+}
+
+func (t *colorPaletteTable) positionColumn() string {
+	return "position"
+}
+
+func (t *colorPaletteTable) get(ctx context.Context, id int) ([]models.Color, error) {
+	tbl := t.table.table
+	q := dialect.Select(tbl.Col(colorColumn)).From(tbl).Where(t.idColumn.Eq(id)).Order(tbl.Col(t.positionColumn()).Asc())
+
+	const single = false
+	var ret []models.Color
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return err
+		}
+
+		c, err := models.ParseHexColor(v)
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, c)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting colors from %s: %w", tbl.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+func (t *colorPaletteTable) replace(ctx context.Context, id int, palette []models.Color) error {
+	if err := t.destroy(ctx, []int{id}); err != nil {
+		return err
+	}
+
+	tbl := t.table.table
+	for i, c := range palette {
+		h, s, v := c.HSV()
+		q := dialect.Insert(tbl).Cols(
+			t.idColumn.GetCol(), t.positionColumn(), colorColumn, hueColumn, saturationColumn, brightnessColumn,
+		).Vals(
+			goqu.Vals{id, i, c.Hex(), h, s, v},
+		)
+
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", tbl.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+// findSimilar returns the ids of up to limit objects with the dominant color
+// closest to that of the object with the given id, closest first.
+func (t *colorPaletteTable) findSimilar(ctx context.Context, id int, limit int, restricted *goqu.SelectDataset) ([]int, error) {
+	tableName := t.table.table.GetTable()
+	idCol := t.idColumn.GetCol()
+
+	// exclude inaccessible objects before the limit is applied
+	restriction, err := restrictedClause(fmt.Sprintf("o.%s", idCol), restricted)
+	if err != nil {
+		return nil, err
+	}
+	if restriction != "" {
+		restriction = " AND " + restriction
+	}
+
+	// hue difference is circular and matters less for unsaturated colors
+	query := fmt.Sprintf(`SELECT o.%[2]s FROM %[1]s o
+INNER JOIN %[1]s s ON s.%[2]s = ? AND s.position = 0
+WHERE o.position = 0 AND o.%[2]s != s.%[2]s%[3]s
+ORDER BY (
+  (MIN(ABS(o.hue - s.hue), 360 - ABS(o.hue - s.hue)) / 180.0) * (MIN(ABS(o.hue - s.hue), 360 - ABS(o.hue - s.hue)) / 180.0) * ((o.saturation + s.saturation) / 2)
+  + (o.saturation - s.saturation) * (o.saturation - s.saturation)
+  + (o.brightness - s.brightness) * (o.brightness - s.brightness)
+), o.%[2]s
+LIMIT ?`, tableName, idCol, restriction)
+
+	var ret []int
+	wrapper := dbWrapper{}
+	if err := wrapper.Select(ctx, &ret, query, id, limit); err != nil {
+		return nil, fmt.Errorf("finding similar colors in %s: %w", tableName, err)
+	}
+
+	return ret, nil
+}
+
+// dominantCriterionHandler filters by the column of the dominant color,
+// such as hue or brightness.
+func (t *colorPaletteTable) dominantCriterionHandler(primaryTable string, column string, c *models.FloatCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		tableName := t.table.table.GetTable()
+		as := tableName + "_dominant_" + column
+		f.addLeftJoin(tableName, as, fmt.Sprintf("%[1]s.%[2]s = %[3]s.id AND %[1]s.position = 0", as, t.idColumn.GetCol(), primaryTable))
+
+		clause, args := getFloatCriterionWhereClause(as+"."+column, *c)
+		f.addWhere(clause, args...)
+	}
+}
+
+// dominantSort returns an ORDER BY clause sorting by the column of the
+// dominant color, such as hue or brightness.
+func (t *colorPaletteTable) dominantSort(primaryTable string, column string, direction string) string {
+	return fmt.Sprintf(" ORDER BY (SELECT %[1]s.%[2]s FROM %[1]s WHERE %[1]s.%[3]s = %[4]s.id AND %[1]s.position = 0) %[5]s",
+		t.table.table.GetTable(), column, t.idColumn.GetCol(), primaryTable, getSortDirection(direction))
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/jmoiron/sqlx"
@@ -10,6 +11,7 @@ import (
 
 type oCounterManager struct {
 	tableMgr *table
+	oDates   *oDatesTable
 }
 
 func (qb *oCounterManager) getOCounter(ctx context.Context, id int) (int, error) {
@@ -40,6 +42,10 @@ func (qb *oCounterManager) IncrementOCounter(ctx context.Context, id int) (int,
 		return 0, err
 	}
 
+	if err := qb.oDates.insertDates(ctx, id, []time.Time{time.Now()}); err != nil {
+		return 0, err
+	}
+
 	return qb.getOCounter(ctx, id)
 }
 
@@ -53,10 +59,18 @@ func (qb *oCounterManager) DecrementOCounter(ctx context.Context, id int) (int,
 		"o_counter": goqu.L("o_counter - 1"),
 	}).Where(qb.tableMgr.byID(id), goqu.L("o_counter > 0"))
 
-	if _, err := exec(ctx, q); err != nil {
+	res, err := exec(ctx, q)
+	if err != nil {
 		return 0, fmt.Errorf("updating %s: %w", table.GetTable(), err)
 	}
 
+	// only remove a date if the counter was decremented
+	if n, _ := res.RowsAffected(); n > 0 {
+		if err := qb.oDates.destroyLatest(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+
 	return qb.getOCounter(ctx, id)
 }
 
@@ -71,5 +85,9 @@ func (qb *oCounterManager) ResetOCounter(ctx context.Context, id int) (int, erro
 		return 0, err
 	}
 
+	if err := qb.oDates.destroy(ctx, []int{id}); err != nil {
+		return 0, err
+	}
+
 	return qb.getOCounter(ctx, id)
 }
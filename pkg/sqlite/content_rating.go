@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Content rating restrictions are applied by the stores using the maximum
+// content rating set in the context. Scenes and galleries with a higher content
+// rating are treated as if they do not exist, as are images in a restricted
+// gallery, and markers and relationships of a restricted scene.
+
+const contentRatingColumn = "content_rating"
+
+// restrictedSceneIDs returns a query selecting the ids of scenes that are not
+// accessible in ctx. Returns nil if content is not restricted.
+func restrictedSceneIDs(ctx context.Context) *goqu.SelectDataset {
+	max := models.MaxContentRating(ctx)
+	if max == nil {
+		return nil
+	}
+
+	table := sceneTableMgr.table
+	return dialect.From(table).Select(table.Col(idColumn)).Where(table.Col(contentRatingColumn).Gt(*max))
+}
+
+// restrictedGalleryIDs returns a query selecting the ids of galleries that are
+// not accessible in ctx. Returns nil if content is not restricted.
+func restrictedGalleryIDs(ctx context.Context) *goqu.SelectDataset {
+	max := models.MaxContentRating(ctx)
+	if max == nil {
+		return nil
+	}
+
+	table := galleryTableMgr.table
+	return dialect.From(table).Select(table.Col(idColumn)).Where(table.Col(contentRatingColumn).Gt(*max))
+}
+
+// restrictedImageIDs returns a query selecting the ids of images in galleries
+// that are not accessible in ctx. Returns nil if content is not restricted.
+func restrictedImageIDs(ctx context.Context) *goqu.SelectDataset {
+	galleryIDs := restrictedGalleryIDs(ctx)
+	if galleryIDs == nil {
+		return nil
+	}
+
+	table := galleriesImagesJoinTable
+	return dialect.From(table).Select(table.Col(imageIDColumn)).Where(table.Col(galleryIDColumn).In(galleryIDs))
+}
+
+func restrictDataset(q *goqu.SelectDataset, id exp.IdentifierExpression, restricted *goqu.SelectDataset) *goqu.SelectDataset {
+	if restricted == nil {
+		return q
+	}
+
+	return q.Where(id.NotIn(restricted))
+}
+
+// restrictScenes excludes rows of q where the sceneID column refers to a scene
+// that is not accessible in ctx.
+func restrictScenes(ctx context.Context, q *goqu.SelectDataset, sceneID exp.IdentifierExpression) *goqu.SelectDataset {
+	return restrictDataset(q, sceneID, restrictedSceneIDs(ctx))
+}
+
+// restrictGalleries excludes rows of q where the galleryID column refers to a
+// gallery that is not accessible in ctx.
+func restrictGalleries(ctx context.Context, q *goqu.SelectDataset, galleryID exp.IdentifierExpression) *goqu.SelectDataset {
+	return restrictDataset(q, galleryID, restrictedGalleryIDs(ctx))
+}
+
+// restrictImages excludes rows of q where the imageID column refers to an
+// image that is not accessible in ctx.
+func restrictImages(ctx context.Context, q *goqu.SelectDataset, imageID exp.IdentifierExpression) *goqu.SelectDataset {
+	return restrictDataset(q, imageID, restrictedImageIDs(ctx))
+}
+
+// restrictedClause returns a where clause excluding rows where idColumn is
+// selected by the restricted query. Returns an empty string if restricted is
+// nil.
+func restrictedClause(idColumn string, restricted *goqu.SelectDataset) (string, error) {
+	if restricted == nil {
+		return "", nil
+	}
+
+	sql, _, err := restricted.ToSQL()
+	if err != nil {
+		return "", fmt.Errorf("building content rating restriction: %w", err)
+	}
+
+	return fmt.Sprintf("%s NOT IN (%s)", idColumn, sql), nil
+}
+
+func restrictQuery(query *queryBuilder, idColumn string, restricted *goqu.SelectDataset) error {
+	clause, err := restrictedClause(idColumn, restricted)
+	if err != nil {
+		return err
+	}
+
+	// addWhere ignores empty clauses
+	query.addWhere(clause)
+	return nil
+}
+
+// restrictSceneQuery excludes rows of query where idColumn refers to a scene
+// that is not accessible in ctx.
+func restrictSceneQuery(ctx context.Context, query *queryBuilder, idColumn string) error {
+	return restrictQuery(query, idColumn, restrictedSceneIDs(ctx))
+}
+
+// restrictGalleryQuery excludes rows of query where idColumn refers to a
+// gallery that is not accessible in ctx.
+func restrictGalleryQuery(ctx context.Context, query *queryBuilder, idColumn string) error {
+	return restrictQuery(query, idColumn, restrictedGalleryIDs(ctx))
+}
+
+// restrictImageQuery excludes rows of query where idColumn refers to an image
+// that is not accessible in ctx.
+func restrictImageQuery(ctx context.Context, query *queryBuilder, idColumn string) error {
+	return restrictQuery(query, idColumn, restrictedImageIDs(ctx))
+}
+
+// accessibleIDs returns ids without those selected by the restricted query,
+// preserving order. id is the column selected by restricted.
+func accessibleIDs(ctx context.Context, ids []int, id exp.IdentifierExpression, restricted *goqu.SelectDataset) ([]int, error) {
+	if restricted == nil || len(ids) == 0 {
+		return ids, nil
+	}
+
+	excluded := make(map[int]bool)
+	q := restricted.Where(id.In(ids))
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return err
+		}
+		excluded[v] = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([]int, 0, len(ids))
+	for _, v := range ids {
+		if !excluded[v] {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,133 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentRatingRestriction(t *testing.T) {
+	runWithRollbackTxn(t, "restricted content", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		const restrictedRating = 2
+
+		sceneID := sceneIDs[sceneIdxWithMarkers]
+		scenePartial := models.NewScenePartial()
+		scenePartial.ContentRating = models.NewOptionalInt(restrictedRating)
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, scenePartial); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return
+		}
+
+		galleryID := galleryIDs[galleryIdxWithScene]
+		galleryPartial := models.NewGalleryPartial()
+		galleryPartial.ContentRating = models.NewOptionalInt(restrictedRating)
+		if _, err := db.Gallery.UpdatePartial(ctx, galleryID, galleryPartial); err != nil {
+			t.Errorf("Error updating gallery: %s", err.Error())
+			return
+		}
+
+		imageGalleryID := galleryIDs[galleryIdxWithTwoImages]
+		if _, err := db.Gallery.UpdatePartial(ctx, imageGalleryID, galleryPartial); err != nil {
+			t.Errorf("Error updating gallery: %s", err.Error())
+			return
+		}
+
+		unrestrictedSceneCount, err := db.Scene.Count(ctx)
+		if err != nil {
+			t.Errorf("Error counting scenes: %s", err.Error())
+			return
+		}
+
+		restrictedCtx := models.WithMaxContentRating(ctx, restrictedRating-1)
+
+		scene, err := db.Scene.Find(restrictedCtx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding scene: %s", err.Error())
+			return
+		}
+		assert.Nil(scene)
+
+		_, err = db.Scene.FindMany(restrictedCtx, []int{sceneID})
+		assert.Error(err)
+
+		sceneCount, err := db.Scene.Count(restrictedCtx)
+		if err != nil {
+			t.Errorf("Error counting scenes: %s", err.Error())
+			return
+		}
+		assert.Equal(unrestrictedSceneCount-1, sceneCount)
+
+		result, err := db.Scene.Query(restrictedCtx, models.SceneQueryOptions{
+			QueryOptions: models.QueryOptions{Count: true},
+		})
+		if err != nil {
+			t.Errorf("Error querying scenes: %s", err.Error())
+			return
+		}
+		assert.Equal(sceneCount, result.Count)
+		assert.NotContains(result.IDs, sceneID)
+
+		markers, err := db.SceneMarker.FindBySceneID(restrictedCtx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding markers: %s", err.Error())
+			return
+		}
+		assert.Len(markers, 0)
+
+		markerCount, err := db.SceneMarker.QueryCount(restrictedCtx, nil, nil)
+		if err != nil {
+			t.Errorf("Error counting markers: %s", err.Error())
+			return
+		}
+		allMarkerCount, err := db.SceneMarker.QueryCount(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error counting markers: %s", err.Error())
+			return
+		}
+		assert.Less(markerCount, allMarkerCount)
+
+		gallery, err := db.Gallery.Find(restrictedCtx, galleryID)
+		if err != nil {
+			t.Errorf("Error finding gallery: %s", err.Error())
+			return
+		}
+		assert.Nil(gallery)
+
+		galleryIDsForScene, err := db.Scene.GetGalleryIDs(restrictedCtx, sceneIDs[sceneIdxWithGallery])
+		if err != nil {
+			t.Errorf("Error getting gallery ids: %s", err.Error())
+			return
+		}
+		assert.Len(galleryIDsForScene, 0)
+
+		image, err := db.Image.Find(restrictedCtx, imageIDs[imageIdx1WithGallery])
+		if err != nil {
+			t.Errorf("Error finding image: %s", err.Error())
+			return
+		}
+		assert.Nil(image)
+
+		images, err := db.Image.FindByGalleryID(restrictedCtx, imageGalleryID)
+		if err != nil {
+			t.Errorf("Error finding images: %s", err.Error())
+			return
+		}
+		assert.Len(images, 0)
+
+		// accessible at the restricted content rating
+		accessibleCtx := models.WithMaxContentRating(ctx, restrictedRating)
+		scene, err = db.Scene.Find(accessibleCtx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding scene: %s", err.Error())
+			return
+		}
+		assert.NotNil(scene)
+	})
+}
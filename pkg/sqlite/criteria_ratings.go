@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	ratingCriterionIDColumn = "rating_criterion_id"
+
+	// criterionRatingSortPrefix is followed by the id of the rating
+	// criterion to sort by
+	criterionRatingSortPrefix = "criterion_rating_"
+	criteriaRatingAverageSort = "criteria_rating_average"
+)
+
+// criteriaRatingsTable stores the ratings of an object for each rating
+// criterion.
+type criteriaRatingsTable struct {
+	table
+}
+
+func (t *criteriaRatingsTable) get(ctx context.Context, id int) ([]models.CriterionRating, error) {
+	tbl := t.table.table
+	criteria := goqu.T(ratingCriteriaTable)
+	q := dialect.Select(tbl.Col(ratingCriterionIDColumn), tbl.Col(ratingColumn)).From(tbl).
+		InnerJoin(criteria, goqu.On(criteria.Col(idColumn).Eq(tbl.Col(ratingCriterionIDColumn)))).
+		Where(t.idColumn.Eq(id)).
+		Order(criteria.Col("name").Asc())
+
+	const single = false
+	var ret []models.CriterionRating
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var v models.CriterionRating
+		if err := rows.Scan(&v.CriterionID, &v.Rating); err != nil {
+			return err
+		}
+
+		ret = append(ret, v)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting criteria ratings from %s: %w", tbl.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+func (t *criteriaRatingsTable) set(ctx context.Context, id int, input models.CriteriaRatingsInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	tbl := t.table.table
+	for _, r := range input.Set {
+		q := dialect.Insert(tbl).Cols(
+			t.idColumn.GetCol(), ratingCriterionIDColumn, ratingColumn,
+		).Vals(
+			goqu.Vals{id, r.CriterionID, r.Rating},
+		).OnConflict(goqu.DoUpdate(
+			fmt.Sprintf("%s, %s", t.idColumn.GetCol(), ratingCriterionIDColumn),
+			goqu.Record{ratingColumn: r.Rating},
+		))
+
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", tbl.GetTable(), err)
+		}
+	}
+
+	if len(input.Remove) > 0 {
+		q := dialect.Delete(tbl).Where(t.idColumn.Eq(id), tbl.Col(ratingCriterionIDColumn).In(input.Remove))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("deleting from %s: %w", tbl.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+// criterionRatingColumn returns a column expression selecting the rating of the
+// object in primaryTable for the criterion, or NULL if it is not rated.
+func (t *criteriaRatingsTable) criterionRatingColumn(primaryTable string, criterionID int) string {
+	return fmt.Sprintf("(SELECT %[1]s.%[2]s FROM %[1]s WHERE %[1]s.%[3]s = %[4]s.id AND %[1]s.%[5]s = %[6]d)",
+		t.table.table.GetTable(), ratingColumn, t.idColumn.GetCol(), primaryTable, ratingCriterionIDColumn, criterionID)
+}
+
+// averageColumn returns a column expression selecting the rounded average of
+// the criteria ratings of the object in primaryTable, or NULL if it has none.
+func (t *criteriaRatingsTable) averageColumn(primaryTable string) string {
+	return fmt.Sprintf("(SELECT CAST(ROUND(AVG(%[1]s.%[2]s)) AS INTEGER) FROM %[1]s WHERE %[1]s.%[3]s = %[4]s.id)",
+		t.table.table.GetTable(), ratingColumn, t.idColumn.GetCol(), primaryTable)
+}
+
+// criterionHandler returns a handler filtering objects by their criteria
+// ratings. All criteria must match.
+func (t *criteriaRatingsTable) criterionHandler(primaryTable string, criteria []models.CriterionRatingCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		for _, c := range criteria {
+			criterionID, err := strconv.Atoi(c.CriterionID)
+			if err != nil {
+				f.setError(fmt.Errorf("converting criterion id: %w", err))
+				return
+			}
+
+			clause, args := getIntCriterionWhereClause(t.criterionRatingColumn(primaryTable, criterionID), c.Rating)
+			f.addWhere(clause, args...)
+		}
+	}
+}
+
+func (t *criteriaRatingsTable) averageCriterionHandler(primaryTable string, c *models.IntCriterionInput) criterionHandlerFunc {
+	return intCriterionHandler(c, t.averageColumn(primaryTable), nil)
+}
+
+// sort returns an ORDER BY clause if sort is the rating of a criterion or the
+// average of the criteria ratings.
+func (t *criteriaRatingsTable) sort(primaryTable string, sort string, direction string) (string, bool) {
+	direction = getSortDirection(direction)
+
+	if sort == criteriaRatingAverageSort {
+		return " ORDER BY " + t.averageColumn(primaryTable) + " " + direction, true
+	}
+
+	if strings.HasPrefix(sort, criterionRatingSortPrefix) {
+		criterionID, err := strconv.Atoi(strings.TrimPrefix(sort, criterionRatingSortPrefix))
+		if err != nil {
+			return "", false
+		}
+
+		return " ORDER BY " + t.criterionRatingColumn(primaryTable, criterionID) + " " + direction, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,226 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	customFieldsFieldColumn = "field"
+	customFieldsValueColumn = "value"
+)
+
+// customFieldsTable stores custom field values of an object as JSON.
+type customFieldsTable struct {
+	table
+}
+
+func (t *customFieldsTable) get(ctx context.Context, id int) (map[string]interface{}, error) {
+	tbl := t.table.table
+	q := dialect.Select(tbl.Col(customFieldsFieldColumn), tbl.Col(customFieldsValueColumn)).From(tbl).Where(t.idColumn.Eq(id))
+
+	const single = false
+	ret := make(map[string]interface{})
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var field, value string
+		if err := rows.Scan(&field, &value); err != nil {
+			return err
+		}
+
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return fmt.Errorf("decoding custom field %q: %w", field, err)
+		}
+
+		ret[field] = v
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting custom fields from %s: %w", tbl.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+func (t *customFieldsTable) set(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if input.Full != nil {
+		if err := t.destroy(ctx, []int{id}); err != nil {
+			return err
+		}
+
+		if err := t.upsert(ctx, id, input.Full); err != nil {
+			return err
+		}
+	}
+
+	if err := t.upsert(ctx, id, input.Partial); err != nil {
+		return err
+	}
+
+	if len(input.Remove) > 0 {
+		tbl := t.table.table
+		q := dialect.Delete(tbl).Where(t.idColumn.Eq(id), tbl.Col(customFieldsFieldColumn).In(input.Remove))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("deleting from %s: %w", tbl.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+func (t *customFieldsTable) upsert(ctx context.Context, id int, fields map[string]interface{}) error {
+	tbl := t.table.table
+	for field, v := range fields {
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding custom field %q: %w", field, err)
+		}
+
+		q := dialect.Insert(tbl).Cols(
+			t.idColumn.GetCol(), customFieldsFieldColumn, customFieldsValueColumn,
+		).Vals(
+			goqu.Vals{id, field, string(value)},
+		).OnConflict(goqu.DoUpdate(
+			fmt.Sprintf("%s, %s", t.idColumn.GetCol(), customFieldsFieldColumn),
+			goqu.Record{customFieldsValueColumn: string(value)},
+		))
+
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", tbl.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+// criterionHandler returns a handler filtering objects by their custom
+// fields. primaryIDCol is the ID column of the filtered table. All criteria
+// must match.
+func (t *customFieldsTable) criterionHandler(primaryIDCol string, criteria []models.CustomFieldCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		for _, c := range criteria {
+			clause, args, err := t.criterionClause(primaryIDCol, c)
+			if err != nil {
+				f.setError(err)
+				return
+			}
+
+			f.addWhere(clause, args...)
+		}
+	}
+}
+
+func (t *customFieldsTable) criterionClause(primaryIDCol string, c models.CustomFieldCriterionInput) (string, []interface{}, error) {
+	if err := models.ValidateCustomFieldName(c.Field); err != nil {
+		return "", nil, err
+	}
+
+	values := make([]interface{}, len(c.Value))
+	for i, v := range c.Value {
+		var err error
+		values[i], err = customFieldSQLValue(v)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	var want int
+	switch c.Modifier {
+	case models.CriterionModifierIsNull, models.CriterionModifierNotNull:
+		want = 0
+	case models.CriterionModifierBetween, models.CriterionModifierNotBetween:
+		want = 2
+	default:
+		want = 1
+	}
+	if len(values) != want {
+		return "", nil, fmt.Errorf("custom field criterion with modifier %s requires %d values", c.Modifier, want)
+	}
+
+	// in selects the objects with the field, and a value matching cond if
+	// it is not empty
+	in := func(not bool, cond string, condArgs ...interface{}) (string, []interface{}) {
+		tbl := t.table.table.GetTable()
+		op := "IN"
+		if not {
+			op = "NOT IN"
+		}
+
+		where := customFieldsFieldColumn + " = ?"
+		if cond != "" {
+			where += " AND " + cond
+		}
+
+		return fmt.Sprintf("%s %s (SELECT %s FROM %s WHERE %s)", primaryIDCol, op, t.idColumn.GetCol(), tbl, where),
+			append([]interface{}{c.Field}, condArgs...)
+	}
+
+	value := fmt.Sprintf("json_extract(%s, '$')", customFieldsValueColumn)
+
+	var clause string
+	var args []interface{}
+	switch c.Modifier {
+	case models.CriterionModifierEquals:
+		clause, args = in(false, value+" = ?", values[0])
+	case models.CriterionModifierNotEquals:
+		clause, args = in(true, value+" = ?", values[0])
+	case models.CriterionModifierGreaterThan:
+		clause, args = in(false, value+" > ?", values[0])
+	case models.CriterionModifierLessThan:
+		clause, args = in(false, value+" < ?", values[0])
+	case models.CriterionModifierBetween:
+		clause, args = in(false, value+" BETWEEN ? AND ?", values[0], values[1])
+	case models.CriterionModifierNotBetween:
+		clause, args = in(true, value+" BETWEEN ? AND ?", values[0], values[1])
+	case models.CriterionModifierIncludes:
+		clause, args = in(false, value+" LIKE ?", "%"+fmt.Sprint(values[0])+"%")
+	case models.CriterionModifierExcludes:
+		clause, args = in(true, value+" LIKE ?", "%"+fmt.Sprint(values[0])+"%")
+	case models.CriterionModifierMatchesRegex, models.CriterionModifierNotMatchesRegex:
+		re := fmt.Sprint(values[0])
+		if _, err := regexp.Compile(re); err != nil {
+			return "", nil, err
+		}
+		clause, args = in(c.Modifier == models.CriterionModifierNotMatchesRegex, value+" regexp ?", re)
+	case models.CriterionModifierIsNull:
+		clause, args = in(true, "")
+	case models.CriterionModifierNotNull:
+		clause, args = in(false, "")
+	default:
+		return "", nil, fmt.Errorf("custom field criterion does not support modifier %s", c.Modifier)
+	}
+
+	return clause, args, nil
+}
+
+// customFieldSQLValue returns the value of a custom field criterion as it is
+// compared by SQLite. Booleans are stored in JSON as true or false, which
+// SQLite extracts as 1 or 0.
+func customFieldSQLValue(v interface{}) (interface{}, error) {
+	if err := models.ValidateCustomFieldValue(v); err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case json.Number:
+		return v.Float64()
+	}
+
+	return v, nil
+}
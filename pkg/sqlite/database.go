@@ -33,7 +33,7 @@ const (
 	dbConnTimeout = 30
 )
 
-var appSchemaVersion uint = 52
+var appSchemaVersion uint = 78
 
 //go:embed migrations/*.sql
 var migrationsBox embed.FS
@@ -65,25 +65,32 @@ func (e *MismatchedSchemaVersionError) Error() string {
 }
 
 type Database struct {
-	Blobs          *BlobStore
-	File           *FileStore
-	Folder         *FolderStore
-	Image          *ImageStore
-	Gallery        *GalleryStore
-	GalleryChapter *GalleryChapterStore
-	Scene          *SceneStore
-	SceneMarker    *SceneMarkerStore
-	Performer      *PerformerStore
-	SavedFilter    *SavedFilterStore
-	Studio         *StudioStore
-	Tag            *TagStore
-	Movie          *MovieStore
+	Audio             *AudioStore
+	Blobs             *BlobStore
+	File              *FileStore
+	Folder            *FolderStore
+	Image             *ImageStore
+	Gallery           *GalleryStore
+	GalleryChapter    *GalleryChapterStore
+	Scene             *SceneStore
+	SceneMarker       *SceneMarkerStore
+	SceneRelationship *SceneRelationshipStore
+	Performer         *PerformerStore
+	SavedFilter       *SavedFilterStore
+	SelectionSet      *SelectionSetStore
+	EditHistory       *EditHistoryStore
+	RatingCriterion   *RatingCriterionStore
+	Studio            *StudioStore
+	Tag               *TagStore
+	Movie             *MovieStore
 
 	db     *sqlx.DB
 	dbPath string
 
 	schemaVersion uint
 
+	libraries *libraryPaths
+
 	lockChan chan struct{}
 }
 
@@ -91,22 +98,29 @@ func NewDatabase() *Database {
 	fileStore := NewFileStore()
 	folderStore := NewFolderStore()
 	blobStore := NewBlobStore(BlobStoreOptions{})
+	libraries := &libraryPaths{}
 
 	ret := &Database{
-		Blobs:          blobStore,
-		File:           fileStore,
-		Folder:         folderStore,
-		Scene:          NewSceneStore(fileStore, blobStore),
-		SceneMarker:    NewSceneMarkerStore(),
-		Image:          NewImageStore(fileStore),
-		Gallery:        NewGalleryStore(fileStore, folderStore),
-		GalleryChapter: NewGalleryChapterStore(),
-		Performer:      NewPerformerStore(blobStore),
-		Studio:         NewStudioStore(blobStore),
-		Tag:            NewTagStore(blobStore),
-		Movie:          NewMovieStore(blobStore),
-		SavedFilter:    NewSavedFilterStore(),
-		lockChan:       make(chan struct{}, 1),
+		Audio:             NewAudioStore(fileStore),
+		Blobs:             blobStore,
+		File:              fileStore,
+		Folder:            folderStore,
+		Scene:             NewSceneStore(fileStore, blobStore, libraries),
+		SceneMarker:       NewSceneMarkerStore(),
+		SceneRelationship: NewSceneRelationshipStore(),
+		Image:             NewImageStore(fileStore, libraries),
+		Gallery:           NewGalleryStore(fileStore, folderStore, libraries),
+		GalleryChapter:    NewGalleryChapterStore(),
+		Performer:         NewPerformerStore(blobStore),
+		Studio:            NewStudioStore(blobStore),
+		Tag:               NewTagStore(blobStore),
+		Movie:             NewMovieStore(blobStore),
+		SavedFilter:       NewSavedFilterStore(),
+		SelectionSet:      NewSelectionSetStore(),
+		EditHistory:       NewEditHistoryStore(),
+		RatingCriterion:   NewRatingCriterionStore(),
+		libraries:         libraries,
+		lockChan:          make(chan struct{}, 1),
 	}
 
 	return ret
@@ -116,6 +130,19 @@ func (db *Database) SetBlobStoreOptions(options BlobStoreOptions) {
 	*db.Blobs = *NewBlobStore(options)
 }
 
+// SetCaseInsensitivePaths sets whether file and folder paths are compared
+// case-insensitively when finding files and folders by path.
+func (db *Database) SetCaseInsensitivePaths(v bool) {
+	db.File.caseInsensitivePaths = v
+	db.Folder.caseInsensitivePaths = v
+}
+
+// SetLibraryPaths sets the stash paths of the libraries, keyed by library
+// name, used when filtering by library.
+func (db *Database) SetLibraryPaths(paths map[string][]string) {
+	db.libraries.set(paths)
+}
+
 // Ready returns an error if the database is not ready to begin transactions.
 func (db *Database) Ready() error {
 	if db.db == nil {
@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// orphanCheck identifies rows in a table that are inconsistent with the rest
+// of the database.
+type orphanCheck struct {
+	name  string
+	table string
+	where string
+}
+
+// referenceCheck returns a check for rows of table where column references a
+// row of refTable that does not exist.
+func referenceCheck(table, column, refTable, refColumn string) orphanCheck {
+	return orphanCheck{
+		name:  fmt.Sprintf("%s.%s without %s", table, column, refTable),
+		table: table,
+		where: fmt.Sprintf("%[1]s IS NOT NULL AND %[1]s NOT IN (SELECT %[2]s FROM %[3]s)", column, refColumn, refTable),
+	}
+}
+
+// editHistoryCheck returns a check for the edit history of objects of
+// objectType that no longer exist.
+func editHistoryCheck(objectType models.EditObjectType, refTable string) orphanCheck {
+	return orphanCheck{
+		name:  fmt.Sprintf("%s of %s without %s", editHistoryTable, objectType, refTable),
+		table: editHistoryTable,
+		where: fmt.Sprintf("object_type = '%s' AND object_id NOT IN (SELECT %s FROM %s)", objectType, idColumn, refTable),
+	}
+}
+
+// orphanChecks are performed in order, so that rows orphaned by the repair
+// of an earlier check are repaired by a later check.
+var orphanChecks = []orphanCheck{
+	// scenes
+	referenceCheck(scenesFilesTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesFilesTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(performersScenesTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(performersScenesTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(scenesTagsTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesTagsTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck(scenesGalleriesTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesGalleriesTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(moviesScenesTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(moviesScenesTable, movieIDColumn, movieTable, idColumn),
+	referenceCheck(scenesURLsTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesColorsTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck("scene_stash_ids", sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(sceneCustomFieldsTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesODatesTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesRatingHistoryTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesCriteriaRatingsTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(scenesCriteriaRatingsTable, ratingCriterionIDColumn, ratingCriteriaTable, idColumn),
+	referenceCheck(sceneRelationshipTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(sceneRelationshipTable, "related_scene_id", sceneTable, idColumn),
+
+	// scene markers
+	referenceCheck(sceneMarkerTable, sceneIDColumn, sceneTable, idColumn),
+	referenceCheck(sceneMarkerTable, "primary_tag_id", tagTable, idColumn),
+	referenceCheck("scene_markers_tags", "scene_marker_id", sceneMarkerTable, idColumn),
+	referenceCheck("scene_markers_tags", tagIDColumn, tagTable, idColumn),
+
+	// images
+	referenceCheck(imagesFilesTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imagesFilesTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(performersImagesTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(performersImagesTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(imagesTagsTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imagesTagsTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck(galleriesImagesTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(galleriesImagesTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(imagesURLsTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imagesColorsTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imageCustomFieldsTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imagesODatesTable, imageIDColumn, imageTable, idColumn),
+	referenceCheck(imagesRatingHistoryTable, imageIDColumn, imageTable, idColumn),
+
+	// galleries
+	referenceCheck(galleriesFilesTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(galleriesFilesTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(performersGalleriesTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(performersGalleriesTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(galleriesTagsTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(galleriesTagsTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck(galleriesChaptersTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(galleriesURLsTable, galleryIDColumn, galleryTable, idColumn),
+	referenceCheck(galleryCustomFieldsTable, galleryIDColumn, galleryTable, idColumn),
+
+	// audios
+	referenceCheck(audiosFilesTable, audioIDColumn, audioTable, idColumn),
+	referenceCheck(audiosFilesTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(performersAudiosTable, audioIDColumn, audioTable, idColumn),
+	referenceCheck(performersAudiosTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(audiosTagsTable, audioIDColumn, audioTable, idColumn),
+	referenceCheck(audiosTagsTable, tagIDColumn, tagTable, idColumn),
+
+	// performers, studios and tags
+	referenceCheck(performersTagsTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(performersTagsTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck(performersAliasesTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck("performer_stash_ids", performerIDColumn, performerTable, idColumn),
+	referenceCheck(performerCustomFieldsTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(performersMergedIDsTable, performerIDColumn, performerTable, idColumn),
+	referenceCheck(studioAliasesTable, studioIDColumn, studioTable, idColumn),
+	referenceCheck("studio_stash_ids", studioIDColumn, studioTable, idColumn),
+	referenceCheck(studioCustomFieldsTable, studioIDColumn, studioTable, idColumn),
+	referenceCheck(tagAliasesTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck(tagCustomFieldsTable, tagIDColumn, tagTable, idColumn),
+	referenceCheck("tags_relations", "parent_id", tagTable, idColumn),
+	referenceCheck("tags_relations", "child_id", tagTable, idColumn),
+
+	// movies
+	referenceCheck(movieCustomFieldsTable, movieIDColumn, movieTable, idColumn),
+
+	// edit history
+	editHistoryCheck(models.EditObjectTypeScene, sceneTable),
+	editHistoryCheck(models.EditObjectTypeImage, imageTable),
+	editHistoryCheck(models.EditObjectTypeGallery, galleryTable),
+	editHistoryCheck(models.EditObjectTypePerformer, performerTable),
+	editHistoryCheck(models.EditObjectTypeStudio, studioTable),
+	editHistoryCheck(models.EditObjectTypeTag, tagTable),
+	editHistoryCheck(models.EditObjectTypeMovie, movieTable),
+
+	// generated_access is keyed by the paths of generated files rather than
+	// by objects, so it is not checked here. Access times of generated files
+	// that no longer exist are removed by the clean generated files task.
+
+	// files
+	{
+		name:  "files without scene, image, gallery or audio",
+		table: fileTable,
+		where: fmt.Sprintf(`%[1]s NOT IN (SELECT %[2]s FROM %[3]s)
+AND %[1]s NOT IN (SELECT %[2]s FROM %[4]s)
+AND %[1]s NOT IN (SELECT %[2]s FROM %[5]s)
+AND %[1]s NOT IN (SELECT %[2]s FROM %[8]s)
+AND %[1]s NOT IN (SELECT zip_file_id FROM %[6]s WHERE zip_file_id IS NOT NULL)
+AND %[1]s NOT IN (SELECT zip_file_id FROM %[7]s WHERE zip_file_id IS NOT NULL)`,
+			idColumn, fileIDColumn, scenesFilesTable, imagesFilesTable, galleriesFilesTable, fileTable, folderTable, audiosFilesTable),
+	},
+	referenceCheck(fingerprintTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(videoFileTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(imageFileTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(audioFileTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(documentFileTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(videoCaptionsTable, fileIDColumn, videoFileTable, fileIDColumn),
+	referenceCheck(fileLinksTable, fileIDColumn, fileTable, idColumn),
+	referenceCheck(fileVerificationsTable, fileIDColumn, fileTable, idColumn),
+}
+
+// OrphanedRows is the number of inconsistent rows of a single type.
+type OrphanedRows struct {
+	// Type describes the inconsistency
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// FindOrphanedRows returns the number of rows of each type that reference
+// objects that do not exist. Types without inconsistent rows are omitted.
+// Must be called within a transaction.
+func (db *Database) FindOrphanedRows(ctx context.Context) ([]OrphanedRows, error) {
+	wrapper := dbWrapper{}
+
+	var ret []OrphanedRows
+	for _, c := range orphanChecks {
+		var count int
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", c.table, c.where)
+		if err := wrapper.Get(ctx, &count, query); err != nil {
+			return nil, fmt.Errorf("checking %s: %w", c.name, err)
+		}
+
+		if count > 0 {
+			ret = append(ret, OrphanedRows{Type: c.name, Count: count})
+		}
+	}
+
+	return ret, nil
+}
+
+// DeleteOrphanedRows deletes rows that reference objects that do not exist.
+// Returns the number of rows deleted of each type. Types without inconsistent
+// rows are omitted. Must be called within a write transaction.
+func (db *Database) DeleteOrphanedRows(ctx context.Context) ([]OrphanedRows, error) {
+	wrapper := dbWrapper{}
+
+	var ret []OrphanedRows
+	for _, c := range orphanChecks {
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s", c.table, c.where)
+		result, err := wrapper.Exec(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("repairing %s: %w", c.name, err)
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("repairing %s: %w", c.name, err)
+		}
+
+		if count > 0 {
+			ret = append(ret, OrphanedRows{Type: c.name, Count: int(count)})
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,135 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	orphanedFilesType            = "files without scene, image, gallery or audio"
+	orphanedSceneEditHistoryType = "edit_history of SCENE without scenes"
+)
+
+func orphanedCount(rows []sqlite.OrphanedRows, typ string) int {
+	for _, r := range rows {
+		if r.Type == typ {
+			return r.Count
+		}
+	}
+
+	return 0
+}
+
+func TestDatabase_DeleteOrphanedRows(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		before, err := db.FindOrphanedRows(ctx)
+		if err != nil {
+			t.Errorf("FindOrphanedRows() error = %v", err)
+			return nil
+		}
+
+		// a file that is not associated with any object
+		f := &models.BaseFile{
+			Path:           getFilePath(folderIdxWithFiles, "orphaned"),
+			ParentFolderID: folderIDs[folderIdxWithFiles],
+			Basename:       "orphaned",
+			DirEntry: models.DirEntry{
+				ModTime: time.Now(),
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := db.File.Create(ctx, f); err != nil {
+			t.Errorf("File.Create() error = %v", err)
+			return nil
+		}
+
+		// a file of an audio is not orphaned
+		audioFile := &models.BaseFile{
+			Path:           getFilePath(folderIdxWithFiles, "audio"),
+			ParentFolderID: folderIDs[folderIdxWithFiles],
+			Basename:       "audio",
+			DirEntry: models.DirEntry{
+				ModTime: time.Now(),
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := db.File.Create(ctx, audioFile); err != nil {
+			t.Errorf("File.Create() error = %v", err)
+			return nil
+		}
+
+		audio := models.NewAudio()
+		if err := db.Audio.Create(ctx, &audio, []models.FileID{audioFile.ID}); err != nil {
+			t.Errorf("Audio.Create() error = %v", err)
+			return nil
+		}
+
+		// the edit history of a destroyed scene is orphaned
+		scene := &models.Scene{}
+		if err := db.Scene.Create(ctx, scene, nil); err != nil {
+			t.Errorf("Scene.Create() error = %v", err)
+			return nil
+		}
+		editCtx := models.WithEditSource(ctx, models.EditSourcePlugin)
+		if _, err := db.Scene.UpdatePartial(editCtx, scene.ID, models.ScenePartial{
+			Title: models.NewOptionalString("edited"),
+		}); err != nil {
+			t.Errorf("Scene.UpdatePartial() error = %v", err)
+			return nil
+		}
+		if err := db.Scene.Destroy(ctx, scene.ID); err != nil {
+			t.Errorf("Scene.Destroy() error = %v", err)
+			return nil
+		}
+
+		found, err := db.FindOrphanedRows(ctx)
+		if err != nil {
+			t.Errorf("FindOrphanedRows() error = %v", err)
+			return nil
+		}
+
+		wantCount := orphanedCount(before, orphanedFilesType) + 1
+		assert.Equal(t, wantCount, orphanedCount(found, orphanedFilesType))
+
+		wantCount = orphanedCount(before, orphanedSceneEditHistoryType) + 1
+		assert.Equal(t, wantCount, orphanedCount(found, orphanedSceneEditHistoryType))
+
+		deleted, err := db.DeleteOrphanedRows(ctx)
+		if err != nil {
+			t.Errorf("DeleteOrphanedRows() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, found, deleted)
+
+		// file should no longer exist
+		_, err = db.File.Find(ctx, f.ID)
+		assert.NotNil(t, err)
+
+		audioFiles, err := db.File.Find(ctx, audioFile.ID)
+		if err != nil {
+			t.Errorf("File.Find() error = %v", err)
+			return nil
+		}
+		assert.Len(t, audioFiles, 1)
+
+		after, err := db.FindOrphanedRows(ctx)
+		if err != nil {
+			t.Errorf("FindOrphanedRows() error = %v", err)
+			return nil
+		}
+		assert.Len(t, after, 0)
+
+		return nil
+	})
+}
@@ -0,0 +1,564 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	editHistoryTable = "edit_history"
+)
+
+// editHistoryObjectTables are the tables of the objects whose changes are
+// recorded.
+var editHistoryObjectTables = map[models.EditObjectType]*table{
+	models.EditObjectTypeScene:     sceneTableMgr,
+	models.EditObjectTypeImage:     imageTableMgr,
+	models.EditObjectTypeGallery:   galleryTableMgr,
+	models.EditObjectTypePerformer: performerTableMgr,
+	models.EditObjectTypeStudio:    studioTableMgr,
+	models.EditObjectTypeTag:       tagTableMgr,
+	models.EditObjectTypeMovie:     movieTableMgr,
+}
+
+// editHistoryJoins are the relationships of the objects whose changes are
+// recorded, keyed by the field name that they are recorded as.
+var editHistoryJoins = map[models.EditObjectType]map[string]editHistoryJoin{
+	models.EditObjectTypeScene: {
+		"urls":          newEditHistoryJoin[string](scenesURLsTableMgr, nil),
+		"performer_ids": newEditHistoryJoin[int](scenesPerformersTableMgr, lessInt),
+		"tag_ids":       newEditHistoryJoin[int](scenesTagsTableMgr, lessInt),
+		"gallery_ids":   newEditHistoryJoin[int](scenesGalleriesTableMgr, lessInt),
+		"stash_ids":     newEditHistoryJoin[models.StashID](scenesStashIDsTableMgr, lessStashID),
+		"movies":        newEditHistoryJoin[models.MoviesScenes](scenesMoviesTableMgr, lessMoviesScenes),
+	},
+	models.EditObjectTypeImage: {
+		"urls":          newEditHistoryJoin[string](imagesURLsTableMgr, nil),
+		"performer_ids": newEditHistoryJoin[int](imagesPerformersTableMgr, lessInt),
+		"tag_ids":       newEditHistoryJoin[int](imagesTagsTableMgr, lessInt),
+		"gallery_ids":   newEditHistoryJoin[int](imageGalleriesTableMgr, lessInt),
+	},
+	models.EditObjectTypeGallery: {
+		"urls":          newEditHistoryJoin[string](galleriesURLsTableMgr, nil),
+		"performer_ids": newEditHistoryJoin[int](galleriesPerformersTableMgr, lessInt),
+		"tag_ids":       newEditHistoryJoin[int](galleriesTagsTableMgr, lessInt),
+		"scene_ids":     newEditHistoryJoin[int](galleriesScenesTableMgr, lessInt),
+	},
+	models.EditObjectTypePerformer: {
+		"aliases":   newEditHistoryJoin[string](performersAliasesTableMgr, lessString),
+		"tag_ids":   newEditHistoryJoin[int](performersTagsTableMgr, lessInt),
+		"stash_ids": newEditHistoryJoin[models.StashID](performersStashIDsTableMgr, lessStashID),
+	},
+	models.EditObjectTypeStudio: {
+		"aliases":   newEditHistoryJoin[string](studiosAliasesTableMgr, lessString),
+		"stash_ids": newEditHistoryJoin[models.StashID](studiosStashIDsTableMgr, lessStashID),
+	},
+}
+
+// editHistoryIgnoredFields are columns whose changes are not recorded.
+var editHistoryIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+type editHistoryRow struct {
+	ID         int                   `db:"id" goqu:"skipinsert"`
+	ObjectType models.EditObjectType `db:"object_type"`
+	ObjectID   int                   `db:"object_id"`
+	Field      string                `db:"field"`
+	OldValue   null.String           `db:"old_value"`
+	NewValue   null.String           `db:"new_value"`
+	Source     models.EditSource     `db:"source"`
+	CreatedAt  Timestamp             `db:"created_at"`
+	RevertedAt NullTimestamp         `db:"reverted_at"`
+}
+
+func (r *editHistoryRow) resolve() *models.EditHistory {
+	return &models.EditHistory{
+		ID:         r.ID,
+		ObjectType: r.ObjectType,
+		ObjectID:   r.ObjectID,
+		Field:      r.Field,
+		OldValue:   r.OldValue.Ptr(),
+		NewValue:   r.NewValue.Ptr(),
+		Source:     r.Source,
+		CreatedAt:  r.CreatedAt.Timestamp,
+		RevertedAt: r.RevertedAt.TimePtr(),
+	}
+}
+
+type EditHistoryStore struct {
+	repository
+	tableMgr *table
+}
+
+func NewEditHistoryStore() *EditHistoryStore {
+	return &EditHistoryStore{
+		repository: repository{
+			tableName: editHistoryTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: editHistoryTableMgr,
+	}
+}
+
+func (qb *EditHistoryStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *EditHistoryStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+// returns nil, nil if not found
+func (qb *EditHistoryStore) Find(ctx context.Context, id int) (*models.EditHistory, error) {
+	ret, err := qb.get(ctx, qb.selectDataset().Where(qb.tableMgr.byID(id)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *EditHistoryStore) FindByObject(ctx context.Context, objectType models.EditObjectType, objectID int) ([]*models.EditHistory, error) {
+	// SELECT * FROM edit_history WHERE object_type = ? AND object_id = ? ORDER BY id DESC
+	table := qb.table()
+	sq := qb.selectDataset().Prepared(true).Where(
+		table.Col("object_type").Eq(objectType),
+		table.Col("object_id").Eq(objectID),
+	).Order(table.Col(idColumn).Desc())
+
+	return qb.getMany(ctx, sq)
+}
+
+func (qb *EditHistoryStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.EditHistory, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *EditHistoryStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.EditHistory, error) {
+	const single = false
+	var ret []*models.EditHistory
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f editHistoryRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// Revert sets the field of the change back to its old value. The revert is
+// itself recorded as a change, if the context has an edit source. Returns
+// models.ErrEditHistoryChanged if the field no longer has the new value of
+// the change, unless force is true.
+func (qb *EditHistoryStore) Revert(ctx context.Context, id int, force bool) error {
+	e, err := qb.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if e == nil {
+		return fmt.Errorf("edit history with id %d not found", id)
+	}
+
+	if e.RevertedAt != nil {
+		return fmt.Errorf("edit history with id %d has already been reverted", id)
+	}
+
+	t, ok := editHistoryObjectTables[e.ObjectType]
+	if !ok {
+		return fmt.Errorf("invalid object type %s", e.ObjectType)
+	}
+
+	if err := t.checkIDExists(ctx, e.ObjectID); err != nil {
+		return err
+	}
+
+	newValue := null.StringFromPtr(e.NewValue)
+	oldValue := null.StringFromPtr(e.OldValue)
+
+	if join, isJoin := editHistoryJoins[e.ObjectType][e.Field]; isJoin {
+		current, err := join.value(ctx, e.ObjectID)
+		if err != nil {
+			return err
+		}
+
+		if !force && !current.Equal(newValue) {
+			return fmt.Errorf("%w: %s", models.ErrEditHistoryChanged, e.Field)
+		}
+
+		snapshot, err := newEditHistorySnapshot(ctx, e.ObjectType, e.ObjectID)
+		if err != nil {
+			return err
+		}
+
+		if err := join.set(ctx, e.ObjectID, oldValue); err != nil {
+			return err
+		}
+
+		if err := snapshot.record(ctx); err != nil {
+			return err
+		}
+
+		if err := t.updateByID(ctx, e.ObjectID, goqu.Record{
+			"updated_at": Timestamp{Timestamp: time.Now()},
+		}); err != nil {
+			return err
+		}
+	} else {
+		current, err := editHistoryCurrentValues(ctx, t, e.ObjectID, []string{e.Field})
+		if err != nil {
+			return err
+		}
+
+		currentValue, err := editHistoryValue(current[e.Field])
+		if err != nil {
+			return err
+		}
+
+		if !force && !currentValue.Equal(newValue) {
+			return fmt.Errorf("%w: %s", models.ErrEditHistoryChanged, e.Field)
+		}
+
+		record := goqu.Record{
+			e.Field: oldValue,
+		}
+
+		if err := recordEditHistory(ctx, e.ObjectType, e.ObjectID, record); err != nil {
+			return err
+		}
+
+		record["updated_at"] = Timestamp{Timestamp: time.Now()}
+		if err := t.updateByID(ctx, e.ObjectID, record); err != nil {
+			return err
+		}
+	}
+
+	return qb.tableMgr.updateByID(ctx, id, goqu.Record{
+		"reverted_at": NullTimestamp{Timestamp: time.Now(), Valid: true},
+	})
+}
+
+// recordEditHistory records the changes to the fields of the object that
+// are about to be set to the values in record. Changes are only recorded if
+// the context has an edit source. Must be called before the object is
+// updated.
+func recordEditHistory(ctx context.Context, objectType models.EditObjectType, id int, record goqu.Record) error {
+	source, ok := models.EditSourceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	t := editHistoryObjectTables[objectType]
+
+	var fields []string
+	for field := range record {
+		if !editHistoryIgnoredFields[field] {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	sort.Strings(fields)
+
+	current, err := editHistoryCurrentValues(ctx, t, id, fields)
+	if err != nil {
+		return err
+	}
+
+	// the object does not exist, so nothing is changed
+	if len(current) == 0 {
+		return nil
+	}
+
+	now := Timestamp{Timestamp: time.Now()}
+	for _, field := range fields {
+		oldValue, err := editHistoryValue(current[field])
+		if err != nil {
+			return err
+		}
+		newValue, err := editHistoryValue(record[field])
+		if err != nil {
+			return err
+		}
+
+		if oldValue.Equal(newValue) {
+			continue
+		}
+
+		r := editHistoryRow{
+			ObjectType: objectType,
+			ObjectID:   id,
+			Field:      field,
+			OldValue:   oldValue,
+			NewValue:   newValue,
+			Source:     source,
+			CreatedAt:  now,
+		}
+
+		if _, err := editHistoryTableMgr.insert(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// editHistoryCurrentValues returns the current values of the fields of the
+// object, keyed by field. Returns an empty map if the object does not exist.
+func editHistoryCurrentValues(ctx context.Context, t *table, id int, fields []string) (map[string]interface{}, error) {
+	cols := make([]interface{}, len(fields))
+	for i, field := range fields {
+		cols[i] = field
+	}
+
+	q := dialect.From(t.table).Select(cols...).Where(t.byID(id))
+
+	const single = true
+	ret := make(map[string]interface{})
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		return rows.MapScan(ret)
+	}); err != nil {
+		return nil, fmt.Errorf("getting current values from %s: %w", t.table.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+// editHistorySnapshot holds the values of the relationships of an object
+// before they are updated, so that the changes can be recorded afterwards.
+type editHistorySnapshot struct {
+	objectType models.EditObjectType
+	id         int
+	source     models.EditSource
+	values     map[string]null.String
+}
+
+// newEditHistorySnapshot returns the current values of the relationships
+// of the object. Returns nil if the context has no edit source, or the
+// relationships of the object type are not recorded.
+func newEditHistorySnapshot(ctx context.Context, objectType models.EditObjectType, id int) (*editHistorySnapshot, error) {
+	source, ok := models.EditSourceFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	joins := editHistoryJoins[objectType]
+	if len(joins) == 0 {
+		return nil, nil
+	}
+
+	ret := &editHistorySnapshot{
+		objectType: objectType,
+		id:         id,
+		source:     source,
+		values:     make(map[string]null.String),
+	}
+
+	for field, join := range joins {
+		v, err := join.value(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		ret.values[field] = v
+	}
+
+	return ret, nil
+}
+
+// record records the changes to the relationships since the snapshot was
+// taken. Does nothing if s is nil.
+func (s *editHistorySnapshot) record(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	joins := editHistoryJoins[s.objectType]
+
+	fields := make([]string, 0, len(joins))
+	for field := range joins {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	now := Timestamp{Timestamp: time.Now()}
+	for _, field := range fields {
+		newValue, err := joins[field].value(ctx, s.id)
+		if err != nil {
+			return err
+		}
+
+		oldValue := s.values[field]
+		if oldValue.Equal(newValue) {
+			continue
+		}
+
+		r := editHistoryRow{
+			ObjectType: s.objectType,
+			ObjectID:   s.id,
+			Field:      field,
+			OldValue:   oldValue,
+			NewValue:   newValue,
+			Source:     s.source,
+			CreatedAt:  now,
+		}
+
+		if _, err := editHistoryTableMgr.insert(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// editHistoryJoin is a relationship of an object that is recorded in the
+// edit history as a field of the object.
+type editHistoryJoin interface {
+	// value returns the relationship as it is stored in the edit history.
+	value(ctx context.Context, id int) (null.String, error)
+	// set replaces the relationship with the stored value v.
+	set(ctx context.Context, id int, v null.String) error
+}
+
+type editHistoryJoinTable[T any] interface {
+	get(ctx context.Context, id int) ([]T, error)
+	replaceJoins(ctx context.Context, id int, v []T) error
+}
+
+// editHistoryJoinValues stores relationships as JSON arrays. Empty
+// relationships are stored as null.
+type editHistoryJoinValues[T any] struct {
+	table editHistoryJoinTable[T]
+	// less orders unordered relationships so that they can be compared.
+	// Nil if the order of the relationship is significant.
+	less func(a, b T) bool
+}
+
+func newEditHistoryJoin[T any](t editHistoryJoinTable[T], less func(a, b T) bool) editHistoryJoin {
+	return &editHistoryJoinValues[T]{
+		table: t,
+		less:  less,
+	}
+}
+
+func (j *editHistoryJoinValues[T]) value(ctx context.Context, id int) (null.String, error) {
+	v, err := j.table.get(ctx, id)
+	if err != nil {
+		return null.String{}, err
+	}
+
+	if len(v) == 0 {
+		return null.String{}, nil
+	}
+
+	if j.less != nil {
+		sort.Slice(v, func(a, b int) bool {
+			return j.less(v[a], v[b])
+		})
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return null.String{}, err
+	}
+
+	return null.StringFrom(string(data)), nil
+}
+
+func (j *editHistoryJoinValues[T]) set(ctx context.Context, id int, v null.String) error {
+	var values []T
+	if v.Valid {
+		if err := json.Unmarshal([]byte(v.String), &values); err != nil {
+			return fmt.Errorf("invalid edit history value: %w", err)
+		}
+	}
+
+	return j.table.replaceJoins(ctx, id, values)
+}
+
+func lessInt(a, b int) bool       { return a < b }
+func lessString(a, b string) bool { return a < b }
+
+func lessStashID(a, b models.StashID) bool {
+	if a.Endpoint != b.Endpoint {
+		return a.Endpoint < b.Endpoint
+	}
+	return a.StashID < b.StashID
+}
+
+func lessMoviesScenes(a, b models.MoviesScenes) bool {
+	return a.MovieID < b.MovieID
+}
+
+// editHistoryValue returns v as it is stored in the edit history. Values
+// are stored as text, which is converted back to the type of the column
+// when a change is reverted.
+func editHistoryValue(v interface{}) (null.String, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		var err error
+		v, err = valuer.Value()
+		if err != nil {
+			return null.String{}, err
+		}
+	}
+
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return null.String{}, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case bool:
+		if v {
+			s = "1"
+		} else {
+			s = "0"
+		}
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		// date columns are scanned as times at midnight
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			s = v.Format(sqliteDateLayout)
+		} else {
+			s = v.Format(time.RFC3339)
+		}
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	return null.StringFrom(s), nil
+}
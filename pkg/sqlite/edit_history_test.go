@@ -0,0 +1,184 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditHistoryRevert(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.EditHistory
+		sceneID := sceneIDs[sceneIdxWithGallery]
+
+		original, err := db.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		// changes are not recorded without a source
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			Title: models.NewOptionalString("untracked"),
+		}); err != nil {
+			return err
+		}
+
+		history, err := qb.FindByObject(ctx, models.EditObjectTypeScene, sceneID)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, history, 0)
+
+		date, _ := models.ParseDate("2001-02-03")
+		editCtx := models.WithEditSource(ctx, models.EditSourcePlugin)
+		if _, err := db.Scene.UpdatePartial(editCtx, sceneID, models.ScenePartial{
+			Title:     models.NewOptionalString("edited"),
+			Organized: models.NewOptionalBool(!original.Organized),
+			Date:      models.NewOptionalDate(date),
+			Rating:    models.NewOptionalInt(42),
+			// unchanged fields are not recorded
+			Code: models.NewOptionalString(original.Code),
+		}); err != nil {
+			return err
+		}
+
+		history, err = qb.FindByObject(ctx, models.EditObjectTypeScene, sceneID)
+		if err != nil {
+			return err
+		}
+
+		byField := make(map[string]*models.EditHistory)
+		for _, e := range history {
+			byField[e.Field] = e
+			assert.Equal(t, models.EditSourcePlugin, e.Source)
+		}
+		assert.Len(t, history, 4)
+		if assert.Contains(t, byField, "title") {
+			assert.Equal(t, "untracked", *byField["title"].OldValue)
+			assert.Equal(t, "edited", *byField["title"].NewValue)
+		}
+		assert.NotContains(t, byField, "code")
+
+		// revert each change
+		revertCtx := models.WithEditSource(ctx, models.EditSourceUI)
+		for _, e := range history {
+			if err := qb.Revert(revertCtx, e.ID, false); err != nil {
+				return err
+			}
+		}
+
+		reverted, err := db.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "untracked", reverted.Title)
+		assert.Equal(t, original.Organized, reverted.Organized)
+		assert.Equal(t, original.Date, reverted.Date)
+		assert.Equal(t, original.Rating, reverted.Rating)
+
+		e, err := qb.Find(ctx, byField["title"].ID)
+		if err != nil {
+			return err
+		}
+		assert.NotNil(t, e.RevertedAt)
+
+		// reverting is recorded
+		history, err = qb.FindByObject(ctx, models.EditObjectTypeScene, sceneID)
+		if err != nil {
+			return err
+		}
+		assert.Len(t, history, 8)
+		assert.Equal(t, models.EditSourceUI, history[0].Source)
+
+		// a change cannot be reverted twice
+		assert.NotNil(t, qb.Revert(revertCtx, e.ID, false))
+
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEditHistoryJoins(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.EditHistory
+		sceneID := sceneIDs[sceneIdxWithGallery]
+		tagID := tagIDs[tagIdx1WithScene]
+
+		original, err := db.Scene.GetTagIDs(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		editCtx := models.WithEditSource(ctx, models.EditSourceScraper)
+		if _, err := db.Scene.UpdatePartial(editCtx, sceneID, models.ScenePartial{
+			TagIDs: &models.UpdateIDs{
+				IDs:  []int{tagID},
+				Mode: models.RelationshipUpdateModeAdd,
+			},
+			URLs: &models.UpdateStrings{
+				Values: []string{"https://example.com/edited"},
+				Mode:   models.RelationshipUpdateModeSet,
+			},
+		}); err != nil {
+			return err
+		}
+
+		history, err := qb.FindByObject(ctx, models.EditObjectTypeScene, sceneID)
+		if err != nil {
+			return err
+		}
+
+		byField := make(map[string]*models.EditHistory)
+		for _, e := range history {
+			byField[e.Field] = e
+		}
+		assert.Contains(t, byField, "urls")
+		if !assert.Contains(t, byField, "tag_ids") {
+			return nil
+		}
+
+		tagsEdit := byField["tag_ids"]
+
+		// a change that has been changed since is not reverted without force
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			TagIDs: &models.UpdateIDs{
+				IDs:  []int{tagIDs[tagIdx2WithScene]},
+				Mode: models.RelationshipUpdateModeAdd,
+			},
+		}); err != nil {
+			return err
+		}
+
+		assert.ErrorIs(t, qb.Revert(ctx, tagsEdit.ID, false), models.ErrEditHistoryChanged)
+
+		if err := qb.Revert(ctx, tagsEdit.ID, true); err != nil {
+			return err
+		}
+
+		reverted, err := db.Scene.GetTagIDs(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		assert.ElementsMatch(t, original, reverted)
+
+		if err := qb.Revert(ctx, byField["urls"].ID, false); err != nil {
+			return err
+		}
+
+		urls, err := db.Scene.GetURLs(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		assert.NotContains(t, urls, "https://example.com/edited")
+
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
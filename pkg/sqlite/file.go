@@ -18,10 +18,15 @@ import (
 )
 
 const (
-	fileTable      = "files"
-	videoFileTable = "video_files"
-	imageFileTable = "image_files"
-	fileIDColumn   = "file_id"
+	fileTable         = "files"
+	videoFileTable    = "video_files"
+	imageFileTable    = "image_files"
+	audioFileTable    = "audio_files"
+	documentFileTable = "document_files"
+	fileIDColumn      = "file_id"
+
+	fileLinksTable         = "file_links"
+	fileVerificationsTable = "file_verifications"
 
 	videoCaptionsTable    = "video_captions"
 	captionCodeColumn     = "language_code"
@@ -63,6 +68,11 @@ type videoFileRow struct {
 	BitRate          int64         `db:"bit_rate"`
 	Interactive      bool          `db:"interactive"`
 	InteractiveSpeed null.Int      `db:"interactive_speed"`
+	ColorTransfer    string        `db:"color_transfer"`
+
+	InteractiveAverageSpeed  null.Int `db:"interactive_average_speed"`
+	InteractiveMaxSpeed      null.Int `db:"interactive_max_speed"`
+	InteractiveActionDensity null.Int `db:"interactive_action_density"`
 }
 
 func (f *videoFileRow) fromVideoFile(ff models.VideoFile) {
@@ -77,6 +87,10 @@ func (f *videoFileRow) fromVideoFile(ff models.VideoFile) {
 	f.BitRate = ff.BitRate
 	f.Interactive = ff.Interactive
 	f.InteractiveSpeed = intFromPtr(ff.InteractiveSpeed)
+	f.ColorTransfer = ff.ColorTransfer
+	f.InteractiveAverageSpeed = intFromPtr(ff.InteractiveAverageSpeed)
+	f.InteractiveMaxSpeed = intFromPtr(ff.InteractiveMaxSpeed)
+	f.InteractiveActionDensity = intFromPtr(ff.InteractiveActionDensity)
 }
 
 type imageFileRow struct {
@@ -93,6 +107,34 @@ func (f *imageFileRow) fromImageFile(ff models.ImageFile) {
 	f.Height = ff.Height
 }
 
+type audioFileRow struct {
+	FileID     models.FileID `db:"file_id"`
+	Format     string        `db:"format"`
+	Duration   float64       `db:"duration"`
+	AudioCodec string        `db:"audio_codec"`
+	BitRate    int64         `db:"bit_rate"`
+}
+
+func (f *audioFileRow) fromAudioFile(ff models.AudioFile) {
+	f.FileID = ff.ID
+	f.Format = ff.Format
+	f.Duration = ff.Duration
+	f.AudioCodec = ff.AudioCodec
+	f.BitRate = ff.BitRate
+}
+
+type documentFileRow struct {
+	FileID    models.FileID `db:"file_id"`
+	Format    string        `db:"format"`
+	PageCount int           `db:"page_count"`
+}
+
+func (f *documentFileRow) fromDocumentFile(ff models.DocumentFile) {
+	f.FileID = ff.ID
+	f.Format = ff.Format
+	f.PageCount = ff.PageCount
+}
+
 // we redefine this to change the columns around
 // otherwise, we collide with the image file columns
 type videoFileQueryRow struct {
@@ -107,6 +149,11 @@ type videoFileQueryRow struct {
 	BitRate          null.Int    `db:"bit_rate"`
 	Interactive      null.Bool   `db:"interactive"`
 	InteractiveSpeed null.Int    `db:"interactive_speed"`
+	ColorTransfer    null.String `db:"color_transfer"`
+
+	InteractiveAverageSpeed  null.Int `db:"interactive_average_speed"`
+	InteractiveMaxSpeed      null.Int `db:"interactive_max_speed"`
+	InteractiveActionDensity null.Int `db:"interactive_action_density"`
 }
 
 func (f *videoFileQueryRow) resolve() *models.VideoFile {
@@ -121,6 +168,11 @@ func (f *videoFileQueryRow) resolve() *models.VideoFile {
 		BitRate:          f.BitRate.Int64,
 		Interactive:      f.Interactive.Bool,
 		InteractiveSpeed: nullIntPtr(f.InteractiveSpeed),
+		ColorTransfer:    f.ColorTransfer.String,
+
+		InteractiveAverageSpeed:  nullIntPtr(f.InteractiveAverageSpeed),
+		InteractiveMaxSpeed:      nullIntPtr(f.InteractiveMaxSpeed),
+		InteractiveActionDensity: nullIntPtr(f.InteractiveActionDensity),
 	}
 }
 
@@ -138,6 +190,10 @@ func videoFileQueryColumns() []interface{} {
 		table.Col("bit_rate"),
 		table.Col("interactive"),
 		table.Col("interactive_speed"),
+		table.Col("color_transfer"),
+		table.Col("interactive_average_speed"),
+		table.Col("interactive_max_speed"),
+		table.Col("interactive_action_density"),
 	}
 }
 
@@ -166,6 +222,56 @@ func (f *imageFileQueryRow) resolve() *models.ImageFile {
 	}
 }
 
+// we redefine this to change the columns around
+// otherwise, we collide with the video file columns
+type audioFileQueryRow struct {
+	Format     null.String `db:"audio_format"`
+	Duration   null.Float  `db:"audio_duration"`
+	AudioCodec null.String `db:"audio_file_codec"`
+	BitRate    null.Int    `db:"audio_bit_rate"`
+}
+
+func (audioFileQueryRow) columns(table *table) []interface{} {
+	ex := table.table
+	return []interface{}{
+		ex.Col("format").As("audio_format"),
+		ex.Col("duration").As("audio_duration"),
+		ex.Col("audio_codec").As("audio_file_codec"),
+		ex.Col("bit_rate").As("audio_bit_rate"),
+	}
+}
+
+func (f *audioFileQueryRow) resolve() *models.AudioFile {
+	return &models.AudioFile{
+		Format:     f.Format.String,
+		Duration:   f.Duration.Float64,
+		AudioCodec: f.AudioCodec.String,
+		BitRate:    f.BitRate.Int64,
+	}
+}
+
+// we redefine this to change the columns around
+// otherwise, we collide with the other file columns
+type documentFileQueryRow struct {
+	Format    null.String `db:"document_format"`
+	PageCount null.Int    `db:"document_page_count"`
+}
+
+func (documentFileQueryRow) columns(table *table) []interface{} {
+	ex := table.table
+	return []interface{}{
+		ex.Col("format").As("document_format"),
+		ex.Col("page_count").As("document_page_count"),
+	}
+}
+
+func (f *documentFileQueryRow) resolve() *models.DocumentFile {
+	return &models.DocumentFile{
+		Format:    f.Format.String,
+		PageCount: int(f.PageCount.Int64),
+	}
+}
+
 type fileQueryRow struct {
 	FileID         null.Int      `db:"file_id"`
 	Basename       null.String   `db:"basename"`
@@ -183,6 +289,8 @@ type fileQueryRow struct {
 	fingerprintQueryRow
 	videoFileQueryRow
 	imageFileQueryRow
+	audioFileQueryRow
+	documentFileQueryRow
 }
 
 func (r *fileQueryRow) resolve() models.File {
@@ -222,6 +330,18 @@ func (r *fileQueryRow) resolve() models.File {
 		ret = imf
 	}
 
+	if r.audioFileQueryRow.Format.Valid {
+		af := r.audioFileQueryRow.resolve()
+		af.BaseFile = basic
+		ret = af
+	}
+
+	if r.documentFileQueryRow.Format.Valid {
+		df := r.documentFileQueryRow.resolve()
+		df.BaseFile = basic
+		ret = df
+	}
+
 	r.appendRelationships(basic)
 
 	return ret
@@ -277,6 +397,9 @@ type FileStore struct {
 	repository
 
 	tableMgr *table
+
+	// caseInsensitivePaths is true if paths are compared case-insensitively
+	caseInsensitivePaths bool
 }
 
 func NewFileStore() *FileStore {
@@ -315,6 +438,14 @@ func (qb *FileStore) Create(ctx context.Context, f models.File) error {
 		if err := qb.createImageFile(ctx, fileID, *ef); err != nil {
 			return err
 		}
+	case *models.AudioFile:
+		if err := qb.createAudioFile(ctx, fileID, *ef); err != nil {
+			return err
+		}
+	case *models.DocumentFile:
+		if err := qb.createDocumentFile(ctx, fileID, *ef); err != nil {
+			return err
+		}
 	}
 
 	if err := FingerprintReaderWriter.insertJoins(ctx, fileID, f.Base().Fingerprints); err != nil {
@@ -352,6 +483,14 @@ func (qb *FileStore) Update(ctx context.Context, f models.File) error {
 		if err := qb.updateOrCreateImageFile(ctx, id, *ef); err != nil {
 			return err
 		}
+	case *models.AudioFile:
+		if err := qb.updateOrCreateAudioFile(ctx, id, *ef); err != nil {
+			return err
+		}
+	case *models.DocumentFile:
+		if err := qb.updateOrCreateDocumentFile(ctx, id, *ef); err != nil {
+			return err
+		}
 	}
 
 	if err := FingerprintReaderWriter.replaceJoins(ctx, id, f.Base().Fingerprints); err != nil {
@@ -427,6 +566,68 @@ func (qb *FileStore) updateOrCreateImageFile(ctx context.Context, id models.File
 	return nil
 }
 
+func (qb *FileStore) createAudioFile(ctx context.Context, id models.FileID, f models.AudioFile) error {
+	var r audioFileRow
+	r.fromAudioFile(f)
+	r.FileID = id
+	if _, err := audioFileTableMgr.insert(ctx, r); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (qb *FileStore) updateOrCreateAudioFile(ctx context.Context, id models.FileID, f models.AudioFile) error {
+	exists, err := audioFileTableMgr.idExists(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return qb.createAudioFile(ctx, id, f)
+	}
+
+	var r audioFileRow
+	r.fromAudioFile(f)
+	r.FileID = id
+	if err := audioFileTableMgr.updateByID(ctx, id, r); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (qb *FileStore) createDocumentFile(ctx context.Context, id models.FileID, f models.DocumentFile) error {
+	var r documentFileRow
+	r.fromDocumentFile(f)
+	r.FileID = id
+	if _, err := documentFileTableMgr.insert(ctx, r); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (qb *FileStore) updateOrCreateDocumentFile(ctx context.Context, id models.FileID, f models.DocumentFile) error {
+	exists, err := documentFileTableMgr.idExists(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return qb.createDocumentFile(ctx, id, f)
+	}
+
+	var r documentFileRow
+	r.fromDocumentFile(f)
+	r.FileID = id
+	if err := documentFileTableMgr.updateByID(ctx, id, r); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 	table := qb.table()
 
@@ -434,6 +635,8 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 	fingerprintTable := fingerprintTableMgr.table
 	videoFileTable := videoFileTableMgr.table
 	imageFileTable := imageFileTableMgr.table
+	audioFileTable := audioFileTableMgr.table
+	documentFileTable := documentFileTableMgr.table
 
 	zipFileTable := table.As("zip_files")
 	zipFolderTable := folderTable.As("zip_files_folders")
@@ -456,6 +659,8 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 
 	cols = append(cols, videoFileQueryColumns()...)
 	cols = append(cols, imageFileQueryRow{}.columns(imageFileTableMgr)...)
+	cols = append(cols, audioFileQueryRow{}.columns(audioFileTableMgr)...)
+	cols = append(cols, documentFileQueryRow{}.columns(documentFileTableMgr)...)
 
 	ret := dialect.From(table).Select(cols...)
 
@@ -471,6 +676,12 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 	).LeftJoin(
 		imageFileTable,
 		goqu.On(table.Col(idColumn).Eq(imageFileTable.Col(fileIDColumn))),
+	).LeftJoin(
+		audioFileTable,
+		goqu.On(table.Col(idColumn).Eq(audioFileTable.Col(fileIDColumn))),
+	).LeftJoin(
+		documentFileTable,
+		goqu.On(table.Col(idColumn).Eq(documentFileTable.Col(fileIDColumn))),
 	).LeftJoin(
 		zipFileTable,
 		goqu.On(table.Col("zip_file_id").Eq(zipFileTable.Col("id"))),
@@ -487,6 +698,8 @@ func (qb *FileStore) countDataset() *goqu.SelectDataset {
 	fingerprintTable := fingerprintTableMgr.table
 	videoFileTable := videoFileTableMgr.table
 	imageFileTable := imageFileTableMgr.table
+	audioFileTable := audioFileTableMgr.table
+	documentFileTable := documentFileTableMgr.table
 
 	zipFileTable := table.As("zip_files")
 	zipFolderTable := folderTable.As("zip_files_folders")
@@ -505,6 +718,12 @@ func (qb *FileStore) countDataset() *goqu.SelectDataset {
 	).LeftJoin(
 		imageFileTable,
 		goqu.On(table.Col(idColumn).Eq(imageFileTable.Col(fileIDColumn))),
+	).LeftJoin(
+		audioFileTable,
+		goqu.On(table.Col(idColumn).Eq(audioFileTable.Col(fileIDColumn))),
+	).LeftJoin(
+		documentFileTable,
+		goqu.On(table.Col(idColumn).Eq(documentFileTable.Col(fileIDColumn))),
 	).LeftJoin(
 		zipFileTable,
 		goqu.On(table.Col("zip_file_id").Eq(zipFileTable.Col("id"))),
@@ -614,9 +833,17 @@ func (qb *FileStore) FindAllByPath(ctx context.Context, p string) ([]models.File
 		)
 	} else {
 		q = q.Where(
-			folderTable.Col("path").Eq(dirName),
-			table.Col("basename").Eq(basename),
+			pathEq(folderTable.Col("path"), dirName, qb.caseInsensitivePaths),
+			pathEq(table.Col("basename"), basename, qb.caseInsensitivePaths),
 		)
+
+		if qb.caseInsensitivePaths {
+			// prefer exact matches
+			q = q.Order(
+				goqu.L("? = ?", folderTable.Col("path"), dirName).Desc(),
+				goqu.L("? = ?", table.Col("basename"), basename).Desc(),
+			)
+		}
 	}
 
 	ret, err := qb.getMany(ctx, q)
@@ -679,6 +906,20 @@ func (qb *FileStore) CountAllInPaths(ctx context.Context, p []string) (int, erro
 	return count(ctx, q)
 }
 
+func (qb *FileStore) Size(ctx context.Context) (float64, error) {
+	table := qb.table()
+	q := dialect.Select(
+		goqu.COALESCE(goqu.SUM(table.Col("size")), 0),
+	).From(table).Where(table.Col("zip_file_id").IsNull())
+
+	var ret float64
+	if err := querySimple(ctx, q, &ret); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
+
 func (qb *FileStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]models.File, error) {
 	table := qb.table()
 
@@ -744,6 +985,7 @@ func (qb *FileStore) IsPrimary(ctx context.Context, fileID models.FileID) (bool,
 		scenesFilesJoinTable,
 		galleriesFilesJoinTable,
 		imagesFilesJoinTable,
+		audiosFilesJoinTable,
 	}
 
 	var sq *goqu.SelectDataset
@@ -844,6 +1086,9 @@ func (qb *FileStore) Query(ctx context.Context, options models.FileQueryOptions)
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("file_filter", fileFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(fileFilter); err != nil {
 		return nil, err
 	}
@@ -935,3 +1180,201 @@ func (qb *FileStore) GetCaptions(ctx context.Context, fileID models.FileID) ([]*
 func (qb *FileStore) UpdateCaptions(ctx context.Context, fileID models.FileID, captions []*models.VideoCaption) error {
 	return qb.captionRepository().replace(ctx, fileID, captions)
 }
+
+// GetLinks returns the alternate paths of the file.
+func (qb *FileStore) GetLinks(ctx context.Context, fileID models.FileID) ([]string, error) {
+	table := fileLinksJoinTable
+	q := dialect.From(table).Select(table.Col("path")).Where(table.Col(fileIDColumn).Eq(fileID)).Order(table.Col("path").Asc())
+
+	var ret []string
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return err
+		}
+
+		ret = append(ret, path)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting links for file %d: %w", fileID, err)
+	}
+
+	return ret, nil
+}
+
+// FindLinksInPaths returns the alternate paths that are within any of the
+// given paths. Returns all alternate paths if p is empty.
+func (qb *FileStore) FindLinksInPaths(ctx context.Context, p []string) ([]models.FileLink, error) {
+	table := fileLinksJoinTable
+	q := dialect.From(table).Select(table.Col(fileIDColumn), table.Col("path"))
+
+	if len(p) > 0 {
+		var conds []exp.Expression
+		for _, pp := range p {
+			conds = append(conds, table.Col("path").Like(pp+string(filepath.Separator)+"%"))
+		}
+		q = q.Where(goqu.Or(conds...))
+	}
+
+	var ret []models.FileLink
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var l models.FileLink
+		if err := rows.Scan(&l.FileID, &l.Path); err != nil {
+			return err
+		}
+
+		ret = append(ret, l)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting links in paths %v: %w", p, err)
+	}
+
+	return ret, nil
+}
+
+// FindByLink returns the file that has the given alternate path. Returns nil
+// if no file has the path as an alternate path.
+func (qb *FileStore) FindByLink(ctx context.Context, path string) (models.File, error) {
+	table := fileLinksJoinTable
+	sq := dialect.From(table).Select(table.Col(fileIDColumn)).Where(table.Col("path").Eq(path))
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting file by link %q: %w", path, err)
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+// AddLink adds an alternate path to the file. The path is moved from any
+// other file that has it as an alternate path.
+func (qb *FileStore) AddLink(ctx context.Context, fileID models.FileID, path string) error {
+	table := fileLinksJoinTable
+	q := dialect.Insert(table).Cols(fileIDColumn, "path").Vals(
+		goqu.Vals{fileID, path},
+	).OnConflict(goqu.DoUpdate("path", goqu.Record{fileIDColumn: fileID}))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("adding link %q to file %d: %w", path, fileID, err)
+	}
+
+	return nil
+}
+
+// DestroyLink removes the alternate path from its file.
+func (qb *FileStore) DestroyLink(ctx context.Context, path string) error {
+	table := fileLinksJoinTable
+	q := dialect.Delete(table).Where(table.Col("path").Eq(path))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("destroying link %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// FindForVerification returns files within any of the given paths that were
+// not verified since the given time, least recently verified first. Files in
+// zip files are not returned. Returns all files if limit is < 0.
+func (qb *FileStore) FindForVerification(ctx context.Context, p []string, before time.Time, limit int) ([]models.File, error) {
+	table := qb.table()
+	folderTable := folderTableMgr.table
+	verificationTable := fileVerificationsJoinTable
+
+	q := dialect.From(table).Prepared(true).InnerJoin(
+		folderTable,
+		goqu.On(table.Col("parent_folder_id").Eq(folderTable.Col(idColumn))),
+	).LeftJoin(
+		verificationTable,
+		goqu.On(table.Col(idColumn).Eq(verificationTable.Col(fileIDColumn))),
+	).Select(table.Col(idColumn)).Where(
+		table.Col("zip_file_id").IsNull(),
+		goqu.Or(
+			verificationTable.Col("verified_at").IsNull(),
+			verificationTable.Col("verified_at").Lt(Timestamp{Timestamp: before}),
+		),
+	).Order(verificationTable.Col("verified_at").Asc().NullsFirst(), table.Col(idColumn).Asc())
+
+	if len(p) > 0 {
+		q = qb.allInPaths(q, p)
+	}
+
+	if limit > -1 {
+		q = q.Limit(uint(limit))
+	}
+
+	ret, err := qb.findBySubquery(ctx, q)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting files for verification: %w", err)
+	}
+
+	return ret, nil
+}
+
+// CountForVerification returns the number of files within any of the given
+// paths that can be verified. Files in zip files are not counted, since they
+// are not verified. Returns count of all verifiable files if p is empty.
+func (qb *FileStore) CountForVerification(ctx context.Context, p []string) (int, error) {
+	table := qb.table()
+	q := qb.countDataset().Prepared(true).Where(table.Col("zip_file_id").IsNull())
+	if len(p) > 0 {
+		q = qb.allInPaths(q, p)
+	}
+
+	return count(ctx, q)
+}
+
+// UpdateVerification sets the result of the last verification of a file.
+func (qb *FileStore) UpdateVerification(ctx context.Context, v models.FileVerification) error {
+	table := fileVerificationsJoinTable
+	record := goqu.Record{
+		fileIDColumn:  v.FileID,
+		"verified_at": Timestamp{Timestamp: v.VerifiedAt},
+		"error":       null.NewString(v.Error, v.Error != ""),
+	}
+
+	q := dialect.Insert(table).Rows(record).OnConflict(goqu.DoUpdate(fileIDColumn, goqu.Record{
+		"verified_at": record["verified_at"],
+		"error":       record["error"],
+	}))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("updating verification of file %d: %w", v.FileID, err)
+	}
+
+	return nil
+}
+
+// FindFailedVerifications returns the results of the last verification of
+// files that failed verification, most recently verified first.
+func (qb *FileStore) FindFailedVerifications(ctx context.Context) ([]models.FileVerification, error) {
+	table := fileVerificationsJoinTable
+	q := dialect.From(table).Select(
+		table.Col(fileIDColumn), table.Col("verified_at"), table.Col("error"),
+	).Where(table.Col("error").IsNotNull()).Order(table.Col("verified_at").Desc())
+
+	var ret []models.FileVerification
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var (
+			v          models.FileVerification
+			verifiedAt Timestamp
+			errStr     null.String
+		)
+		if err := rows.Scan(&v.FileID, &verifiedAt, &errStr); err != nil {
+			return err
+		}
+
+		v.VerifiedAt = verifiedAt.Timestamp
+		v.Error = errStr.String
+		ret = append(ret, v)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting failed file verifications: %w", err)
+	}
+
+	return ret, nil
+}
@@ -136,6 +136,31 @@ func Test_fileFileStore_Create(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"document file",
+			&models.DocumentFile{
+				BaseFile: &models.BaseFile{
+					DirEntry: models.DirEntry{
+						ModTime: fileModTime,
+					},
+					Path:           getFilePath(folderIdxWithFiles, basename),
+					ParentFolderID: folderIDs[folderIdxWithFiles],
+					Basename:       basename,
+					Size:           size,
+					Fingerprints: []models.Fingerprint{
+						{
+							Type:        fingerprintType,
+							Fingerprint: fingerprintValue,
+						},
+					},
+					CreatedAt: createdAt,
+					UpdatedAt: updatedAt,
+				},
+				Format:    "pdf",
+				PageCount: 12,
+			},
+			false,
+		},
 		{
 			"duplicate path",
 			&models.BaseFile{
@@ -221,6 +246,9 @@ func Test_fileFileStore_Create(t *testing.T) {
 			case *models.ImageFile:
 				v := *t
 				copy = &v
+			case *models.DocumentFile:
+				v := *t
+				copy = &v
 			}
 
 			copy.Base().ID = s.Base().ID
@@ -262,6 +290,10 @@ func Test_fileStore_Update(t *testing.T) {
 		videoCodec       = "videoCodec"
 		audioCodec       = "audioCodec"
 		format           = "format"
+
+		interactiveAverageSpeed  = 150
+		interactiveMaxSpeed      = 400
+		interactiveActionDensity = 90
 	)
 
 	tests := []struct {
@@ -324,6 +356,10 @@ func Test_fileStore_Update(t *testing.T) {
 				Height:     height,
 				FrameRate:  framerate,
 				BitRate:    bitrate,
+
+				InteractiveAverageSpeed:  &interactiveAverageSpeed,
+				InteractiveMaxSpeed:      &interactiveMaxSpeed,
+				InteractiveActionDensity: &interactiveActionDensity,
 			},
 			false,
 		},
@@ -662,3 +698,200 @@ func TestFileStore_IsPrimary(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStore_Links(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.File
+		fileID := fileIDs[fileIdxStartVideoFiles]
+		otherID := fileIDs[fileIdxStartImageFiles]
+
+		folderPath := getFolderPath(folderIdxWithFiles, nil)
+		linkPath := filepath.Join(folderPath, "link.mp4")
+		otherPath := filepath.Join(getFolderPath(folderIdxWithParentFolder, nil), "link.mp4")
+
+		if err := qb.AddLink(ctx, fileID, linkPath); err != nil {
+			t.Errorf("FileStore.AddLink() error = %v", err)
+			return nil
+		}
+		if err := qb.AddLink(ctx, fileID, otherPath); err != nil {
+			t.Errorf("FileStore.AddLink() error = %v", err)
+			return nil
+		}
+
+		links, err := qb.GetLinks(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetLinks() error = %v", err)
+			return nil
+		}
+		assert.ElementsMatch(t, []string{linkPath, otherPath}, links)
+
+		found, err := qb.FindByLink(ctx, linkPath)
+		if err != nil {
+			t.Errorf("FileStore.FindByLink() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, fileID, found.Base().ID)
+		}
+
+		inPaths, err := qb.FindLinksInPaths(ctx, []string{folderPath})
+		if err != nil {
+			t.Errorf("FileStore.FindLinksInPaths() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []models.FileLink{{FileID: fileID, Path: linkPath}}, inPaths)
+
+		// adding an existing link moves it to the other file
+		if err := qb.AddLink(ctx, otherID, linkPath); err != nil {
+			t.Errorf("FileStore.AddLink() error = %v", err)
+			return nil
+		}
+
+		found, err = qb.FindByLink(ctx, linkPath)
+		if err != nil {
+			t.Errorf("FileStore.FindByLink() error = %v", err)
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, otherID, found.Base().ID)
+		}
+
+		if err := qb.DestroyLink(ctx, linkPath); err != nil {
+			t.Errorf("FileStore.DestroyLink() error = %v", err)
+			return nil
+		}
+
+		found, err = qb.FindByLink(ctx, linkPath)
+		if err != nil {
+			t.Errorf("FileStore.FindByLink() error = %v", err)
+			return nil
+		}
+		assert.Nil(t, found)
+
+		links, err = qb.GetLinks(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetLinks() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []string{otherPath}, links)
+
+		return nil
+	})
+}
+
+func TestFileStore_Verification(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.File
+		fileID := fileIDs[fileIdxStartVideoFiles]
+		otherID := fileIDs[fileIdxStartImageFiles]
+
+		verifiedAt := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := verifiedAt.Add(time.Hour)
+
+		if err := qb.UpdateVerification(ctx, models.FileVerification{
+			FileID:     fileID,
+			VerifiedAt: verifiedAt,
+		}); err != nil {
+			t.Errorf("FileStore.UpdateVerification() error = %v", err)
+			return nil
+		}
+
+		// files that were never verified are returned first
+		files, err := qb.FindForVerification(ctx, nil, before, -1)
+		if err != nil {
+			t.Errorf("FileStore.FindForVerification() error = %v", err)
+			return nil
+		}
+		var ids []models.FileID
+		for _, f := range files {
+			ids = append(ids, f.Base().ID)
+		}
+		assert.Contains(t, ids, fileID)
+		assert.Contains(t, ids, otherID)
+
+		// the count matches the files returned, excluding files in zip files
+		count, err := qb.CountForVerification(ctx, nil)
+		if err != nil {
+			t.Errorf("FileStore.CountForVerification() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, len(files), count)
+
+		files, err = qb.FindForVerification(ctx, nil, verifiedAt, -1)
+		if err != nil {
+			t.Errorf("FileStore.FindForVerification() error = %v", err)
+			return nil
+		}
+		for _, f := range files {
+			assert.NotEqual(t, fileID, f.Base().ID)
+		}
+
+		// update an existing verification with an error
+		if err := qb.UpdateVerification(ctx, models.FileVerification{
+			FileID:     fileID,
+			VerifiedAt: before,
+			Error:      "md5 mismatch",
+		}); err != nil {
+			t.Errorf("FileStore.UpdateVerification() error = %v", err)
+			return nil
+		}
+
+		failed, err := qb.FindFailedVerifications(ctx)
+		if err != nil {
+			t.Errorf("FileStore.FindFailedVerifications() error = %v", err)
+			return nil
+		}
+		if assert.Len(t, failed, 1) {
+			assert.Equal(t, fileID, failed[0].FileID)
+			assert.Equal(t, "md5 mismatch", failed[0].Error)
+			assert.True(t, before.Equal(failed[0].VerifiedAt))
+		}
+
+		return nil
+	})
+}
+
+func TestFileStore_Size(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.File
+
+		before, err := qb.Size(ctx)
+		if err != nil {
+			t.Errorf("FileStore.Size() error = %v", err)
+			return nil
+		}
+
+		const size = 12345
+		zipID := fileIDs[fileIdxZip]
+		for _, f := range []*models.BaseFile{
+			{
+				Basename:       "size.mp4",
+				ParentFolderID: folderIDs[folderIdxWithFiles],
+				Size:           size,
+			},
+			{
+				// files within zip files are not counted
+				DirEntry: models.DirEntry{
+					ZipFileID: &zipID,
+				},
+				Basename:       "size-in-zip.jpg",
+				ParentFolderID: folderIDs[folderIdxWithFiles],
+				Size:           size,
+			},
+		} {
+			if err := qb.Create(ctx, f); err != nil {
+				t.Errorf("FileStore.Create() error = %v", err)
+				return nil
+			}
+		}
+
+		after, err := qb.Size(ctx)
+		if err != nil {
+			t.Errorf("FileStore.Size() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, before+size, after)
+		return nil
+	})
+}
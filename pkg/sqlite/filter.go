@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/stashapp/stash/pkg/utils"
 
@@ -539,6 +540,72 @@ func getPathSearchClauseMany(pathColumn, basenameColumn, p string, addWildcards,
 	return getPathSearchClause(pathColumn, basenameColumn, trimmedQuery, addWildcards, not)
 }
 
+// libraryPaths holds the stash paths of the configured libraries, keyed by
+// library name. It is safe for concurrent use.
+type libraryPaths struct {
+	mutex sync.RWMutex
+	paths map[string][]string
+}
+
+func (l *libraryPaths) set(paths map[string][]string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.paths = paths
+}
+
+// get returns the stash paths of the named libraries.
+func (l *libraryPaths) get(names []string) []string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	var ret []string
+	for _, name := range names {
+		ret = append(ret, l.paths[name]...)
+	}
+
+	return ret
+}
+
+// likeEscaper escapes the wildcard characters of a LIKE pattern, for use
+// with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// libraryCriterionHandler filters by whether objects have a file within the
+// stash paths of the libraries. subquery selects the IDs of the objects with
+// a file in a folder matching the where clause given by its %s verb.
+func libraryCriterionHandler(c *models.LibraryCriterionInput, libraries *libraryPaths, idColumn string, subquery string) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		var clauses []string
+		var args []interface{}
+		for _, p := range libraries.get(c.Value) {
+			clauses = append(clauses, `folders.path = ? OR folders.path LIKE ? ESCAPE '\'`)
+			args = append(args, p, likeEscaper.Replace(p+string(filepath.Separator))+"%")
+		}
+
+		// match nothing if the libraries have no stash paths
+		where := "0"
+		if len(clauses) > 0 {
+			where = "(" + strings.Join(clauses, ") OR (") + ")"
+		}
+
+		query := fmt.Sprintf(subquery, where)
+
+		switch c.Modifier {
+		case models.CriterionModifierIncludes:
+			f.addWhere(fmt.Sprintf("%s IN (%s)", idColumn, query), args...)
+		case models.CriterionModifierExcludes:
+			f.addWhere(fmt.Sprintf("%s NOT IN (%s)", idColumn, query), args...)
+		default:
+			f.setError(fmt.Errorf("invalid modifier %s for library", c.Modifier))
+		}
+	}
+}
+
 func intCriterionHandler(c *models.IntCriterionInput, column string, addJoinFn func(f *filterBuilder)) criterionHandlerFunc {
 	return func(ctx context.Context, f *filterBuilder) {
 		if c != nil {
@@ -840,6 +907,36 @@ func studioCriterionHandler(primaryTable string, studios *models.HierarchicalMul
 	}
 }
 
+// parentsCriterionHandler filters the objects of table by their parent,
+// where parentFK is the column of table referring to the parent.
+func parentsCriterionHandler(tx dbWrapper, table string, parentFK string, parents *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
+	h := hierarchicalMultiCriterionHandlerBuilder{
+		tx: tx,
+
+		primaryTable: table,
+		foreignTable: table,
+		foreignFK:    parentFK,
+		parentFK:     parentFK,
+	}
+
+	return h.handler(parents)
+}
+
+// childCountCriterionHandler filters the objects of table by the number of
+// objects with them as their parent, where parentFK is the column of table
+// referring to the parent.
+func childCountCriterionHandler(table string, parentFK string, childCount *models.IntCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if childCount != nil {
+			alias := "child_" + table
+			f.addLeftJoin(table, alias, fmt.Sprintf("%s.%s = %s.id", alias, parentFK, table))
+			clause, args := getIntCriterionWhereClause(fmt.Sprintf("count(distinct %s.id)", alias), *childCount)
+
+			f.addHaving(clause, args...)
+		}
+	}
+}
+
 type hierarchicalMultiCriterionHandlerBuilder struct {
 	tx dbWrapper
 
@@ -84,6 +84,9 @@ type FolderStore struct {
 	repository
 
 	tableMgr *table
+
+	// caseInsensitivePaths is true if paths are compared case-insensitively
+	caseInsensitivePaths bool
 }
 
 func NewFolderStore() *FolderStore {
@@ -222,7 +225,12 @@ func (qb *FolderStore) Find(ctx context.Context, id models.FolderID) (*models.Fo
 }
 
 func (qb *FolderStore) FindByPath(ctx context.Context, p string) (*models.Folder, error) {
-	q := qb.selectDataset().Prepared(true).Where(qb.table().Col("path").Eq(p))
+	col := qb.table().Col("path")
+	q := qb.selectDataset().Prepared(true).Where(pathEq(col, p, qb.caseInsensitivePaths))
+	if qb.caseInsensitivePaths {
+		// prefer an exact match
+		q = q.Order(goqu.L("? = ?", col, p).Desc())
+	}
 
 	ret, err := qb.get(ctx, q)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -309,3 +317,13 @@ func (qb *FolderStore) FindByZipFileID(ctx context.Context, zipFileID models.Fil
 
 	return qb.getMany(ctx, q)
 }
+
+// pathEq returns an expression comparing col to the path p. The comparison
+// ignores the case of ASCII characters if caseInsensitive is true.
+func pathEq(col exp.IdentifierExpression, p string, caseInsensitive bool) exp.Expression {
+	if caseInsensitive {
+		return goqu.L("? = ? COLLATE NOCASE", col, p)
+	}
+
+	return col.Eq(p)
+}
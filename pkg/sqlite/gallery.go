@@ -26,8 +26,10 @@ const (
 	galleriesImagesTable     = "galleries_images"
 	galleriesScenesTable     = "scenes_galleries"
 	galleryIDColumn          = "gallery_id"
+	galleryParentIDColumn    = "parent_id"
 	galleriesURLsTable       = "gallery_urls"
 	galleriesURLColumn       = "url"
+	galleryCustomFieldsTable = "gallery_custom_fields"
 )
 
 type galleryRow struct {
@@ -36,12 +38,15 @@ type galleryRow struct {
 	Date    NullDate    `db:"date"`
 	Details zero.String `db:"details"`
 	// expressed as 1-100
-	Rating    null.Int  `db:"rating"`
-	Organized bool      `db:"organized"`
-	StudioID  null.Int  `db:"studio_id,omitempty"`
-	FolderID  null.Int  `db:"folder_id,omitempty"`
-	CreatedAt Timestamp `db:"created_at"`
-	UpdatedAt Timestamp `db:"updated_at"`
+	Rating        null.Int  `db:"rating"`
+	Organized     bool      `db:"organized"`
+	Favorite      bool      `db:"favorite"`
+	ContentRating int       `db:"content_rating"`
+	StudioID      null.Int  `db:"studio_id,omitempty"`
+	ParentID      null.Int  `db:"parent_id,omitempty"`
+	FolderID      null.Int  `db:"folder_id,omitempty"`
+	CreatedAt     Timestamp `db:"created_at"`
+	UpdatedAt     Timestamp `db:"updated_at"`
 }
 
 func (r *galleryRow) fromGallery(o models.Gallery) {
@@ -51,7 +56,10 @@ func (r *galleryRow) fromGallery(o models.Gallery) {
 	r.Details = zero.StringFrom(o.Details)
 	r.Rating = intFromPtr(o.Rating)
 	r.Organized = o.Organized
+	r.Favorite = o.Favorite
+	r.ContentRating = o.ContentRating
 	r.StudioID = intFromPtr(o.StudioID)
+	r.ParentID = intFromPtr(o.ParentID)
 	r.FolderID = nullIntFromFolderIDPtr(o.FolderID)
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
@@ -74,7 +82,10 @@ func (r *galleryQueryRow) resolve() *models.Gallery {
 		Details:       r.Details.String,
 		Rating:        nullIntPtr(r.Rating),
 		Organized:     r.Organized,
+		Favorite:      r.Favorite,
+		ContentRating: r.ContentRating,
 		StudioID:      nullIntPtr(r.StudioID),
+		ParentID:      nullIntPtr(r.ParentID),
 		FolderID:      nullIntFolderIDPtr(r.FolderID),
 		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
 		CreatedAt:     r.CreatedAt.Timestamp,
@@ -100,7 +111,10 @@ func (r *galleryRowRecord) fromPartial(o models.GalleryPartial) {
 	r.setNullString("details", o.Details)
 	r.setNullInt("rating", o.Rating)
 	r.setBool("organized", o.Organized)
+	r.setBool("favorite", o.Favorite)
+	r.setInt("content_rating", o.ContentRating)
 	r.setNullInt("studio_id", o.StudioID)
+	r.setNullInt("parent_id", o.ParentID)
 	r.setTimestamp("created_at", o.CreatedAt)
 	r.setTimestamp("updated_at", o.UpdatedAt)
 }
@@ -112,9 +126,10 @@ type GalleryStore struct {
 
 	fileStore   *FileStore
 	folderStore *FolderStore
+	libraries   *libraryPaths
 }
 
-func NewGalleryStore(fileStore *FileStore, folderStore *FolderStore) *GalleryStore {
+func NewGalleryStore(fileStore *FileStore, folderStore *FolderStore, libraries *libraryPaths) *GalleryStore {
 	return &GalleryStore{
 		repository: repository{
 			tableName: galleryTable,
@@ -123,6 +138,7 @@ func NewGalleryStore(fileStore *FileStore, folderStore *FolderStore) *GallerySto
 		tableMgr:    galleryTableMgr,
 		fileStore:   fileStore,
 		folderStore: folderStore,
+		libraries:   libraries,
 	}
 }
 
@@ -261,11 +277,20 @@ func (qb *GalleryStore) UpdatePartial(ctx context.Context, id int, partial model
 	r.fromPartial(partial)
 
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeGallery, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
 	}
 
+	joins, err := newEditHistorySnapshot(ctx, models.EditObjectTypeGallery, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if partial.URLs != nil {
 		if err := galleriesURLsTableMgr.modifyJoins(ctx, id, partial.URLs.Values, partial.URLs.Mode); err != nil {
 			return nil, err
@@ -287,6 +312,10 @@ func (qb *GalleryStore) UpdatePartial(ctx context.Context, id int, partial model
 		}
 	}
 
+	if err := joins.record(ctx); err != nil {
+		return nil, err
+	}
+
 	if partial.PrimaryFileID != nil {
 		if err := galleriesFilesTableMgr.setPrimary(ctx, id, *partial.PrimaryFileID); err != nil {
 			return nil, err
@@ -400,6 +429,8 @@ func (qb *GalleryStore) get(ctx context.Context, q *goqu.SelectDataset) (*models
 }
 
 func (qb *GalleryStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Gallery, error) {
+	q = restrictGalleries(ctx, q, qb.table().Col(idColumn))
+
 	const single = false
 	var ret []*models.Gallery
 	var lastID int
@@ -573,10 +604,31 @@ func (qb *GalleryStore) FindByImageID(ctx context.Context, imageID int) ([]*mode
 	return ret, nil
 }
 
+// FindChildren returns the galleries with the given gallery as their parent,
+// sorted by their displayed title.
+func (qb *GalleryStore) FindChildren(ctx context.Context, id int) ([]*models.Gallery, error) {
+	table := qb.table()
+
+	q := qb.selectDataset().Prepared(true).Where(
+		table.Col(galleryParentIDColumn).Eq(id),
+	).Order(
+		goqu.L("COALESCE(galleries.title, files.basename, basename(COALESCE(gallery_folder.path, ''))) COLLATE NATURAL_CI").Asc(),
+		table.Col(idColumn).Asc(),
+	)
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("getting child galleries of gallery %d: %w", id, err)
+	}
+
+	return ret, nil
+}
+
 func (qb *GalleryStore) CountByImageID(ctx context.Context, imageID int) (int, error) {
 	joinTable := galleriesImagesJoinTable
 
 	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(imageIDColumn).Eq(imageID))
+	q = restrictGalleries(ctx, q, joinTable.Col(galleryIDColumn))
 	return count(ctx, q)
 }
 
@@ -602,6 +654,7 @@ func (qb *GalleryStore) FindUserGalleryByTitle(ctx context.Context, title string
 
 func (qb *GalleryStore) Count(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q = restrictGalleries(ctx, q, qb.table().Col(idColumn))
 	return count(ctx, q)
 }
 
@@ -679,10 +732,20 @@ func (qb *GalleryStore) makeFilter(ctx context.Context, galleryFilter *models.Ga
 	}))
 
 	query.handleCriterion(ctx, qb.galleryPathCriterionHandler(galleryFilter.Path))
+	query.handleCriterion(ctx, libraryCriterionHandler(galleryFilter.Library, qb.libraries, "galleries.id", `SELECT folders.gallery_id FROM (
+SELECT galleries_files.gallery_id, folders.path FROM galleries_files
+INNER JOIN files ON files.id = galleries_files.file_id
+INNER JOIN folders ON folders.id = files.parent_folder_id
+UNION ALL
+SELECT galleries.id, folders.path FROM galleries
+INNER JOIN folders ON folders.id = galleries.folder_id
+) AS folders WHERE %s`))
 	query.handleCriterion(ctx, galleryFileCountCriterionHandler(qb, galleryFilter.FileCount))
 	query.handleCriterion(ctx, intCriterionHandler(galleryFilter.Rating100, "galleries.rating", nil))
 	query.handleCriterion(ctx, galleryURLsCriterionHandler(galleryFilter.URL))
 	query.handleCriterion(ctx, boolCriterionHandler(galleryFilter.Organized, "galleries.organized", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(galleryFilter.Favorite, "galleries.favorite", nil))
+	query.handleCriterion(ctx, intCriterionHandler(galleryFilter.ContentRating, "galleries.content_rating", nil))
 	query.handleCriterion(ctx, galleryIsMissingCriterionHandler(qb, galleryFilter.IsMissing))
 	query.handleCriterion(ctx, galleryTagsCriterionHandler(qb, galleryFilter.Tags))
 	query.handleCriterion(ctx, galleryTagCountCriterionHandler(qb, galleryFilter.TagCount))
@@ -690,6 +753,8 @@ func (qb *GalleryStore) makeFilter(ctx context.Context, galleryFilter *models.Ga
 	query.handleCriterion(ctx, galleryPerformerCountCriterionHandler(qb, galleryFilter.PerformerCount))
 	query.handleCriterion(ctx, hasChaptersCriterionHandler(galleryFilter.HasChapters))
 	query.handleCriterion(ctx, studioCriterionHandler(galleryTable, galleryFilter.Studios))
+	query.handleCriterion(ctx, parentsCriterionHandler(qb.tx, galleryTable, galleryParentIDColumn, galleryFilter.Parents))
+	query.handleCriterion(ctx, childCountCriterionHandler(galleryTable, galleryParentIDColumn, galleryFilter.ChildCount))
 	query.handleCriterion(ctx, galleryPerformerTagsCriterionHandler(qb, galleryFilter.PerformerTags))
 	query.handleCriterion(ctx, galleryAverageResolutionCriterionHandler(qb, galleryFilter.AverageResolution))
 	query.handleCriterion(ctx, galleryImageCountCriterionHandler(qb, galleryFilter.ImageCount))
@@ -698,6 +763,7 @@ func (qb *GalleryStore) makeFilter(ctx context.Context, galleryFilter *models.Ga
 	query.handleCriterion(ctx, dateCriterionHandler(galleryFilter.Date, "galleries.date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(galleryFilter.CreatedAt, "galleries.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(galleryFilter.UpdatedAt, "galleries.updated_at"))
+	query.handleCriterion(ctx, galleryCustomFieldsTableMgr.criterionHandler("galleries.id", galleryFilter.CustomFields))
 
 	return query
 }
@@ -762,6 +828,9 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("gallery_filter", galleryFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(galleryFilter); err != nil {
 		return nil, err
 	}
@@ -771,6 +840,10 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 		return nil, err
 	}
 
+	if err := restrictGalleryQuery(ctx, &query, "galleries.id"); err != nil {
+		return nil, err
+	}
+
 	qb.setGallerySort(&query, findFilter)
 	query.sortAndPagination += getPagination(findFilter)
 
@@ -1225,5 +1298,22 @@ func (qb *GalleryStore) scenesRepository() *joinRepository {
 }
 
 func (qb *GalleryStore) GetSceneIDs(ctx context.Context, id int) ([]int, error) {
-	return qb.scenesRepository().getIDs(ctx, id)
+	ids, err := qb.scenesRepository().getIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return accessibleIDs(ctx, ids, sceneTableMgr.table.Col(idColumn), restrictedSceneIDs(ctx))
+}
+
+func (qb *GalleryStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return galleryCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *GalleryStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return galleryCustomFieldsTableMgr.set(ctx, id, fields)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
@@ -19,18 +20,20 @@ const (
 )
 
 type galleryChapterRow struct {
-	ID         int       `db:"id" goqu:"skipinsert"`
-	Title      string    `db:"title"` // TODO: make db schema (and gql schema) nullable
-	ImageIndex int       `db:"image_index"`
-	GalleryID  int       `db:"gallery_id"`
-	CreatedAt  Timestamp `db:"created_at"`
-	UpdatedAt  Timestamp `db:"updated_at"`
+	ID            int       `db:"id" goqu:"skipinsert"`
+	Title         string    `db:"title"` // TODO: make db schema (and gql schema) nullable
+	ImageIndex    int       `db:"image_index"`
+	EndImageIndex null.Int  `db:"end_image_index"`
+	GalleryID     int       `db:"gallery_id"`
+	CreatedAt     Timestamp `db:"created_at"`
+	UpdatedAt     Timestamp `db:"updated_at"`
 }
 
 func (r *galleryChapterRow) fromGalleryChapter(o models.GalleryChapter) {
 	r.ID = o.ID
 	r.Title = o.Title
 	r.ImageIndex = o.ImageIndex
+	r.EndImageIndex = intFromPtr(o.EndImageIndex)
 	r.GalleryID = o.GalleryID
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
@@ -38,12 +41,13 @@ func (r *galleryChapterRow) fromGalleryChapter(o models.GalleryChapter) {
 
 func (r *galleryChapterRow) resolve() *models.GalleryChapter {
 	ret := &models.GalleryChapter{
-		ID:         r.ID,
-		Title:      r.Title,
-		ImageIndex: r.ImageIndex,
-		GalleryID:  r.GalleryID,
-		CreatedAt:  r.CreatedAt.Timestamp,
-		UpdatedAt:  r.UpdatedAt.Timestamp,
+		ID:            r.ID,
+		Title:         r.Title,
+		ImageIndex:    r.ImageIndex,
+		EndImageIndex: nullIntPtr(r.EndImageIndex),
+		GalleryID:     r.GalleryID,
+		CreatedAt:     r.CreatedAt.Timestamp,
+		UpdatedAt:     r.UpdatedAt.Timestamp,
 	}
 
 	return ret
@@ -61,6 +65,7 @@ func (r *galleryChapterRowRecord) fromPartial(o models.GalleryChapterPartial) {
 		r.set("title", o.Title.Value)
 	}
 	r.setInt("image_index", o.ImageIndex)
+	r.setNullInt("end_image_index", o.EndImageIndex)
 	r.setInt("gallery_id", o.GalleryID)
 	r.setTimestamp("created_at", o.CreatedAt)
 	r.setTimestamp("updated_at", o.UpdatedAt)
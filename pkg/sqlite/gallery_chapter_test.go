@@ -7,6 +7,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,6 +40,114 @@ func TestChapterFindByGalleryID(t *testing.T) {
 	})
 }
 
+func TestFindGalleryChapterImage(t *testing.T) {
+	galleryID := galleryIDs[galleryIdxWithTwoImages]
+
+	tests := []struct {
+		name       string
+		imageIndex int
+		want       int
+	}{
+		{"first", 1, imageIDs[imageIdx1WithGallery]},
+		{"second", 2, imageIDs[imageIdx2WithGallery]},
+		{"out of range", 3, 0},
+		{"zero", 0, 0},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := image.FindGalleryChapterImage(ctx, db.Image, galleryID, tt.imageIndex)
+			if err != nil {
+				t.Errorf("FindGalleryChapterImage() error = %v", err)
+				return
+			}
+
+			if tt.want == 0 {
+				assert.Nil(t, got)
+				return
+			}
+
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.ID)
+			}
+		})
+	}
+}
+
+func TestFindGalleryImageRange(t *testing.T) {
+	galleryID := galleryIDs[galleryIdxWithTwoImages]
+
+	tests := []struct {
+		name  string
+		start int
+		end   int
+		want  []int
+	}{
+		{"all", 1, 2, []int{imageIDs[imageIdx1WithGallery], imageIDs[imageIdx2WithGallery]}},
+		{"first", 1, 1, []int{imageIDs[imageIdx1WithGallery]}},
+		{"second", 2, 2, []int{imageIDs[imageIdx2WithGallery]}},
+		{"past end", 2, 5, []int{imageIDs[imageIdx2WithGallery]}},
+		{"out of range", 3, 5, nil},
+		{"empty", 2, 1, nil},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := image.FindGalleryImageRange(ctx, db.Image, galleryID, tt.start, tt.end)
+			if err != nil {
+				t.Errorf("FindGalleryImageRange() error = %v", err)
+				return
+			}
+
+			var ids []int
+			for _, img := range got {
+				ids = append(ids, img.ID)
+			}
+
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestChapterEndImageIndex(t *testing.T) {
+	runWithRollbackTxn(t, "end image index", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+		qb := db.GalleryChapter
+
+		endImageIndex := 2
+
+		chapter := models.NewGalleryChapter()
+		chapter.Title = "range"
+		chapter.ImageIndex = 1
+		chapter.EndImageIndex = &endImageIndex
+		chapter.GalleryID = galleryIDs[galleryIdxWithTwoImages]
+
+		if err := qb.Create(ctx, &chapter); err != nil {
+			t.Errorf("Error creating chapter: %s", err.Error())
+			return
+		}
+
+		found, err := qb.Find(ctx, chapter.ID)
+		if err != nil {
+			t.Errorf("Error finding chapter: %s", err.Error())
+			return
+		}
+
+		assert.Equal(&endImageIndex, found.EndImageIndex)
+
+		partial := models.NewGalleryChapterPartial()
+		partial.EndImageIndex = models.NewOptionalIntPtr(nil)
+
+		updated, err := qb.UpdatePartial(ctx, chapter.ID, partial)
+		if err != nil {
+			t.Errorf("Error updating chapter: %s", err.Error())
+			return
+		}
+
+		assert.Nil(updated.EndImageIndex)
+	})
+}
+
 // TODO Update
 // TODO Destroy
 // TODO Find
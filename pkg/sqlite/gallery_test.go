@@ -10,7 +10,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -1504,6 +1506,72 @@ func verifyGalleriesPath(ctx context.Context, t *testing.T, pathCriterion models
 	}
 }
 
+func TestGalleryQueryLibrary(t *testing.T) {
+	const galleryIdx = 1
+	galleryID := galleryIDs[galleryIdx]
+
+	db.SetLibraryPaths(map[string][]string{
+		"galleries": {folderPaths[folderIdxWithGalleryFiles]},
+		"scenes":    {folderPaths[folderIdxWithSceneFiles]},
+	})
+	defer db.SetLibraryPaths(nil)
+
+	tests := []struct {
+		name     string
+		input    models.LibraryCriterionInput
+		included bool
+	}{
+		{
+			"includes folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"galleries"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			true,
+		},
+		{
+			"includes other folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"scenes"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			false,
+		},
+		{
+			"excludes folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"galleries"},
+				Modifier: models.CriterionModifierExcludes,
+			},
+			false,
+		},
+	}
+
+	qb := db.Gallery
+	perPage := -1
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			galleries, _, err := qb.Query(ctx, &models.GalleryFilterType{
+				Library: &tt.input,
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				t.Errorf("GalleryStore.TestGalleryQueryLibrary() error = %v", err)
+				return
+			}
+
+			var ids []int
+			for _, g := range galleries {
+				ids = append(ids, g.ID)
+			}
+
+			assert.Equal(t, tt.included, sliceutil.Contains(ids, galleryID))
+		})
+	}
+}
+
 func TestGalleryQueryPathOr(t *testing.T) {
 	const gallery1Idx = 1
 	const gallery2Idx = 2
@@ -1760,6 +1828,31 @@ func verifyGalleriesRating100(t *testing.T, ratingCriterion models.IntCriterionI
 	})
 }
 
+func TestGalleryQueryFavorite(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := db.Gallery
+		galleryID := galleryIDs[galleryIdxWithScene]
+
+		partial := models.NewGalleryPartial()
+		partial.Favorite = models.NewOptionalBool(true)
+		if _, err := sqb.UpdatePartial(ctx, galleryID, partial); err != nil {
+			t.Errorf("UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		favorite := true
+		galleries := queryGallery(ctx, t, sqb, &models.GalleryFilterType{
+			Favorite: &favorite,
+		}, nil)
+		if assert.Len(t, galleries, 1) {
+			assert.Equal(t, galleryID, galleries[0].ID)
+			assert.True(t, galleries[0].Favorite)
+		}
+
+		return nil
+	})
+}
+
 func TestGalleryQueryIsMissingScene(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		qb := db.Gallery
@@ -2676,7 +2769,7 @@ func verifyGalleriesImageCount(t *testing.T, imageCountCriterion models.IntCrite
 					Count: true,
 				},
 				ImageFilter: &models.ImageFilterType{
-					Galleries: &models.MultiCriterionInput{
+					Galleries: &models.HierarchicalMultiCriterionInput{
 						Value:    []string{strconv.Itoa(gallery.ID)},
 						Modifier: models.CriterionModifierIncludes,
 					},
@@ -2947,6 +3040,139 @@ func TestGalleryQueryHasChapters(t *testing.T) {
 	})
 }
 
+func TestGalleryFindChildrenSorted(t *testing.T) {
+	assert := assert.New(t)
+
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Gallery
+
+		parent := models.NewGallery()
+		parent.Title = "parent gallery"
+		if err := qb.Create(ctx, &parent, nil); err != nil {
+			return err
+		}
+
+		var ids []int
+		for _, title := range []string{"child 10", "child 2", "Child 3"} {
+			child := models.NewGallery()
+			child.Title = title
+			child.ParentID = &parent.ID
+			if err := qb.Create(ctx, &child, nil); err != nil {
+				return err
+			}
+			ids = append(ids, child.ID)
+		}
+
+		children, err := qb.FindChildren(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal([]int{ids[1], ids[2], ids[0]}, galleriesToIDs(children))
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestGalleryParents(t *testing.T) {
+	assert := assert.New(t)
+
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Gallery
+
+		parent := models.NewGallery()
+		parent.Title = "parent gallery"
+		if err := qb.Create(ctx, &parent, nil); err != nil {
+			return err
+		}
+
+		child := models.NewGallery()
+		child.Title = "child gallery"
+		child.ParentID = &parent.ID
+		if err := qb.Create(ctx, &child, nil); err != nil {
+			return err
+		}
+
+		// the gallery with the image is a grandchild of parent
+		grandchildID := galleryIDs[galleryIdxWithImage]
+		partial := models.NewGalleryPartial()
+		partial.ParentID = models.NewOptionalInt(child.ID)
+		if _, err := qb.UpdatePartial(ctx, grandchildID, partial); err != nil {
+			return err
+		}
+
+		children, err := qb.FindChildren(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		assert.Len(children, 1)
+		assert.Equal(child.ID, children[0].ID)
+
+		// galleries cannot be their own ancestors
+		assert.ErrorIs(gallery.ValidateParent(ctx, parent.ID, grandchildID, qb), models.ErrOwnAncestor)
+		assert.Nil(gallery.ValidateParent(ctx, grandchildID, parent.ID, qb))
+
+		allDepth := -1
+		parents := models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(parent.ID)},
+			Modifier: models.CriterionModifierIncludes,
+		}
+		galleries := queryGallery(ctx, t, qb, &models.GalleryFilterType{
+			Parents: &parents,
+		}, nil)
+		assert.Equal([]int{child.ID}, galleriesToIDs(galleries))
+
+		parents.Depth = &allDepth
+		galleries = queryGallery(ctx, t, qb, &models.GalleryFilterType{
+			Parents: &parents,
+		}, nil)
+		assert.ElementsMatch([]int{child.ID, grandchildID}, galleriesToIDs(galleries))
+
+		galleries = queryGallery(ctx, t, qb, &models.GalleryFilterType{
+			ChildCount: &models.IntCriterionInput{
+				Value:    0,
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}, nil)
+		assert.ElementsMatch([]int{parent.ID, child.ID}, galleriesToIDs(galleries))
+
+		// images of child galleries are included with depth
+		imageGalleries := models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(parent.ID)},
+			Modifier: models.CriterionModifierIncludes,
+		}
+		images := queryImages(ctx, t, db.Image, &models.ImageFilterType{
+			Galleries: &imageGalleries,
+		}, nil)
+		assert.Len(images, 0)
+
+		imageGalleries.Depth = &allDepth
+		images = queryImages(ctx, t, db.Image, &models.ImageFilterType{
+			Galleries: &imageGalleries,
+		}, nil)
+		assert.Len(images, 1)
+		if len(images) > 0 {
+			assert.Equal(imageIDs[imageIdxWithGallery], images[0].ID)
+		}
+
+		// children are orphaned when the parent is destroyed
+		if err := qb.Destroy(ctx, parent.ID); err != nil {
+			return err
+		}
+
+		found, err := qb.Find(ctx, child.ID)
+		if err != nil {
+			return err
+		}
+		assert.Nil(found.ParentID)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 // TODO Count
 // TODO All
 // TODO Query
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	generatedAccessTable      = "generated_access"
+	generatedAccessedAtColumn = "accessed_at"
+)
+
+// SetGeneratedAccessTimes sets the time that each generated file was last
+// accessed. Files are keyed by their path relative to the generated
+// directory. Must be called within a write transaction.
+func (db *Database) SetGeneratedAccessTimes(ctx context.Context, times map[string]time.Time) error {
+	table := goqu.T(generatedAccessTable)
+	for p, t := range times {
+		record := goqu.Record{
+			"path":                    p,
+			generatedAccessedAtColumn: Timestamp{Timestamp: t},
+		}
+
+		q := dialect.Insert(table).Rows(record).OnConflict(goqu.DoUpdate("path", goqu.Record{
+			generatedAccessedAtColumn: record[generatedAccessedAtColumn],
+		}))
+
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("setting access time of %q: %w", p, err)
+		}
+	}
+
+	return nil
+}
+
+// GetGeneratedAccessTimes returns the time that each generated file was last
+// accessed, keyed by the path relative to the generated directory.
+func (db *Database) GetGeneratedAccessTimes(ctx context.Context) (map[string]time.Time, error) {
+	table := goqu.T(generatedAccessTable)
+	q := dialect.From(table).Select(table.Col("path"), table.Col(generatedAccessedAtColumn))
+
+	ret := make(map[string]time.Time)
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var (
+			p          string
+			accessedAt Timestamp
+		)
+		if err := rows.Scan(&p, &accessedAt); err != nil {
+			return err
+		}
+
+		ret[p] = accessedAt.Timestamp
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting generated access times: %w", err)
+	}
+
+	return ret, nil
+}
+
+// DeleteGeneratedAccessTimes removes the access times of the generated
+// files. Must be called within a write transaction.
+func (db *Database) DeleteGeneratedAccessTimes(ctx context.Context, paths []string) error {
+	table := goqu.T(generatedAccessTable)
+	return batchExec(paths, defaultBatchSize, func(batch []string) error {
+		q := dialect.Delete(table).Where(table.Col("path").In(batch))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("deleting generated access times: %w", err)
+		}
+		return nil
+	})
+}
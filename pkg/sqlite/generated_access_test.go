@@ -0,0 +1,53 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_GeneratedAccessTimes(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		second := first.AddDate(0, 1, 0)
+
+		if err := db.SetGeneratedAccessTimes(ctx, map[string]time.Time{
+			"vtt/a_sprite.jpg":  first,
+			"transcodes/a.mp4":  first,
+			"markers/a/10.webp": first,
+		}); err != nil {
+			t.Errorf("SetGeneratedAccessTimes() error = %v", err)
+			return nil
+		}
+
+		// later accesses replace earlier ones
+		if err := db.SetGeneratedAccessTimes(ctx, map[string]time.Time{
+			"vtt/a_sprite.jpg": second,
+		}); err != nil {
+			t.Errorf("SetGeneratedAccessTimes() error = %v", err)
+			return nil
+		}
+
+		if err := db.DeleteGeneratedAccessTimes(ctx, []string{"transcodes/a.mp4"}); err != nil {
+			t.Errorf("DeleteGeneratedAccessTimes() error = %v", err)
+			return nil
+		}
+
+		got, err := db.GetGeneratedAccessTimes(ctx)
+		if err != nil {
+			t.Errorf("GetGeneratedAccessTimes() error = %v", err)
+			return nil
+		}
+
+		assert.Len(t, got, 2)
+		assert.True(t, got["vtt/a_sprite.jpg"].Equal(second))
+		assert.True(t, got["markers/a/10.webp"].Equal(first))
+
+		return nil
+	})
+}
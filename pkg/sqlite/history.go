@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	scenesODatesTable        = "scenes_o_dates"
+	imagesODatesTable        = "images_o_dates"
+	scenesRatingHistoryTable = "scenes_rating_history"
+	imagesRatingHistoryTable = "images_rating_history"
+
+	oDateColumn             = "o_date"
+	ratingHistoryDateColumn = "date"
+	ratingColumn            = "rating"
+)
+
+// oDatesTable stores the times at which the o-counter of an object was
+// incremented.
+type oDatesTable struct {
+	table
+}
+
+func (t *oDatesTable) dateColumn() exp.IdentifierExpression {
+	return t.table.table.Col(oDateColumn)
+}
+
+func (t *oDatesTable) get(ctx context.Context, id int) ([]time.Time, error) {
+	q := dialect.Select(t.dateColumn()).From(t.table.table).Where(t.idColumn.Eq(id)).Order(t.dateColumn().Asc(), goqu.I("rowid").Asc())
+
+	const single = false
+	var ret []time.Time
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var v Timestamp
+		if err := rows.Scan(&v); err != nil {
+			return err
+		}
+
+		ret = append(ret, v.Timestamp)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting o dates from %s: %w", t.table.table.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+func (t *oDatesTable) insertDates(ctx context.Context, id int, dates []time.Time) error {
+	for _, d := range dates {
+		q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), oDateColumn).Vals(
+			goqu.Vals{id, Timestamp{Timestamp: d}},
+		)
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", t.table.table.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+func (t *oDatesTable) replaceDates(ctx context.Context, id int, dates []time.Time) error {
+	if err := t.destroy(ctx, []int{id}); err != nil {
+		return err
+	}
+
+	return t.insertDates(ctx, id, dates)
+}
+
+// destroyLatest removes the most recent date.
+func (t *oDatesTable) destroyLatest(ctx context.Context, id int) error {
+	tbl := t.table.table
+	latest := dialect.Select(goqu.I("rowid")).From(tbl).Where(t.idColumn.Eq(id)).Order(t.dateColumn().Desc(), goqu.I("rowid").Desc()).Limit(1)
+	q := dialect.Delete(tbl).Where(goqu.I("rowid").Eq(latest))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("destroying from %s: %w", tbl.GetTable(), err)
+	}
+
+	return nil
+}
+
+// ratingHistoryTable stores the ratings that were set on an object.
+type ratingHistoryTable struct {
+	table
+}
+
+func (t *ratingHistoryTable) get(ctx context.Context, id int) ([]models.RatingHistoryEntry, error) {
+	tbl := t.table.table
+	q := dialect.Select(tbl.Col(ratingColumn), tbl.Col(ratingHistoryDateColumn)).From(tbl).Where(t.idColumn.Eq(id)).Order(tbl.Col(ratingHistoryDateColumn).Asc(), goqu.I("rowid").Asc())
+
+	const single = false
+	var ret []models.RatingHistoryEntry
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var rating null.Int
+		var date Timestamp
+		if err := rows.Scan(&rating, &date); err != nil {
+			return err
+		}
+
+		ret = append(ret, models.RatingHistoryEntry{
+			Rating: nullIntPtr(rating),
+			Date:   date.Timestamp,
+		})
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting rating history from %s: %w", tbl.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+func (t *ratingHistoryTable) insertEntries(ctx context.Context, id int, entries []models.RatingHistoryEntry) error {
+	for _, e := range entries {
+		q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), ratingColumn, ratingHistoryDateColumn).Vals(
+			goqu.Vals{id, intFromPtr(e.Rating), Timestamp{Timestamp: e.Date}},
+		)
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", t.table.table.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+func (t *ratingHistoryTable) replaceEntries(ctx context.Context, id int, entries []models.RatingHistoryEntry) error {
+	if err := t.destroy(ctx, []int{id}); err != nil {
+		return err
+	}
+
+	return t.insertEntries(ctx, id, entries)
+}
+
+// recordInitial adds an entry for the rating of a newly created object, if
+// it has one.
+func (t *ratingHistoryTable) recordInitial(ctx context.Context, id int, rating *int) error {
+	if rating == nil {
+		return nil
+	}
+
+	return t.insertEntries(ctx, id, []models.RatingHistoryEntry{
+		{Rating: rating, Date: time.Now()},
+	})
+}
+
+// recordChange adds an entry for rating if it differs from the current
+// rating of the object in parent. Must be called before the rating is
+// updated.
+func (t *ratingHistoryTable) recordChange(ctx context.Context, parent *table, id int, rating *int) error {
+	q := dialect.From(parent.table).Select(ratingColumn).Where(parent.byID(id))
+
+	const single = true
+	var current null.Int
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		return rows.Scan(&current)
+	}); err != nil {
+		return err
+	}
+
+	existing := nullIntPtr(current)
+	if (existing == nil && rating == nil) || (existing != nil && rating != nil && *existing == *rating) {
+		return nil
+	}
+
+	return t.insertEntries(ctx, id, []models.RatingHistoryEntry{
+		{Rating: rating, Date: time.Now()},
+	})
+}
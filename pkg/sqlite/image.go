@@ -6,10 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/guregu/null.v4/zero"
 
@@ -20,12 +25,14 @@ import (
 var imageTable = "images"
 
 const (
-	imageIDColumn         = "image_id"
-	performersImagesTable = "performers_images"
-	imagesTagsTable       = "images_tags"
-	imagesFilesTable      = "images_files"
-	imagesURLsTable       = "image_urls"
-	imageURLColumn        = "url"
+	imageIDColumn          = "image_id"
+	performersImagesTable  = "performers_images"
+	imagesTagsTable        = "images_tags"
+	imagesFilesTable       = "images_files"
+	imagesURLsTable        = "image_urls"
+	imageURLColumn         = "url"
+	imageCustomFieldsTable = "image_custom_fields"
+	imagesColorsTable      = "images_colors"
 )
 
 type imageRow struct {
@@ -35,6 +42,7 @@ type imageRow struct {
 	Rating    null.Int  `db:"rating"`
 	Date      NullDate  `db:"date"`
 	Organized bool      `db:"organized"`
+	Favorite  bool      `db:"favorite"`
 	OCounter  int       `db:"o_counter"`
 	StudioID  null.Int  `db:"studio_id,omitempty"`
 	CreatedAt Timestamp `db:"created_at"`
@@ -47,6 +55,7 @@ func (r *imageRow) fromImage(i models.Image) {
 	r.Rating = intFromPtr(i.Rating)
 	r.Date = NullDateFromDatePtr(i.Date)
 	r.Organized = i.Organized
+	r.Favorite = i.Favorite
 	r.OCounter = i.OCounter
 	r.StudioID = intFromPtr(i.StudioID)
 	r.CreatedAt = Timestamp{Timestamp: i.CreatedAt}
@@ -68,6 +77,7 @@ func (r *imageQueryRow) resolve() *models.Image {
 		Rating:    nullIntPtr(r.Rating),
 		Date:      r.Date.DatePtr(),
 		Organized: r.Organized,
+		Favorite:  r.Favorite,
 		OCounter:  r.OCounter,
 		StudioID:  nullIntPtr(r.StudioID),
 
@@ -94,6 +104,7 @@ func (r *imageRowRecord) fromPartial(i models.ImagePartial) {
 	r.setNullInt("rating", i.Rating)
 	r.setNullDate("date", i.Date)
 	r.setBool("organized", i.Organized)
+	r.setBool("favorite", i.Favorite)
 	r.setInt("o_counter", i.OCounter)
 	r.setNullInt("studio_id", i.StudioID)
 	r.setTimestamp("created_at", i.CreatedAt)
@@ -107,17 +118,19 @@ type ImageStore struct {
 	oCounterManager
 
 	fileStore *FileStore
+	libraries *libraryPaths
 }
 
-func NewImageStore(fileStore *FileStore) *ImageStore {
+func NewImageStore(fileStore *FileStore, libraries *libraryPaths) *ImageStore {
 	return &ImageStore{
 		repository: repository{
 			tableName: imageTable,
 			idColumn:  idColumn,
 		},
 		tableMgr:        imageTableMgr,
-		oCounterManager: oCounterManager{imageTableMgr},
+		oCounterManager: oCounterManager{imageTableMgr, imagesODatesTableMgr},
 		fileStore:       fileStore,
+		libraries:       libraries,
 	}
 }
 
@@ -167,6 +180,10 @@ func (qb *ImageStore) Create(ctx context.Context, newObject *models.Image, fileI
 		return err
 	}
 
+	if err := imagesRatingHistoryTableMgr.recordInitial(ctx, id, newObject.Rating); err != nil {
+		return err
+	}
+
 	if len(fileIDs) > 0 {
 		const firstPrimary = true
 		if err := imagesFilesTableMgr.insertJoins(ctx, id, firstPrimary, fileIDs); err != nil {
@@ -217,12 +234,27 @@ func (qb *ImageStore) UpdatePartial(ctx context.Context, id int, partial models.
 
 	r.fromPartial(partial)
 
+	if partial.Rating.Set {
+		if err := imagesRatingHistoryTableMgr.recordChange(ctx, qb.tableMgr, id, partial.Rating.Ptr()); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeImage, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
 	}
 
+	joins, err := newEditHistorySnapshot(ctx, models.EditObjectTypeImage, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if partial.GalleryIDs != nil {
 		if err := imageGalleriesTableMgr.modifyJoins(ctx, id, partial.GalleryIDs.IDs, partial.GalleryIDs.Mode); err != nil {
 			return nil, err
@@ -245,6 +277,10 @@ func (qb *ImageStore) UpdatePartial(ctx context.Context, id int, partial models.
 		}
 	}
 
+	if err := joins.record(ctx); err != nil {
+		return nil, err
+	}
+
 	if partial.PrimaryFileID != nil {
 		if err := imagesFilesTableMgr.setPrimary(ctx, id, *partial.PrimaryFileID); err != nil {
 			return nil, err
@@ -258,6 +294,10 @@ func (qb *ImageStore) Update(ctx context.Context, updatedObject *models.Image) e
 	var r imageRow
 	r.fromImage(*updatedObject)
 
+	if err := imagesRatingHistoryTableMgr.recordChange(ctx, qb.tableMgr, updatedObject.ID, updatedObject.Rating); err != nil {
+		return err
+	}
+
 	if err := qb.tableMgr.updateByID(ctx, updatedObject.ID, r); err != nil {
 		return err
 	}
@@ -380,6 +420,8 @@ func (qb *ImageStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.I
 }
 
 func (qb *ImageStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Image, error) {
+	q = restrictImages(ctx, q, qb.table().Col(idColumn))
+
 	const single = false
 	var ret []*models.Image
 	var lastID int
@@ -530,6 +572,7 @@ func (qb *ImageStore) OCountByPerformerID(ctx context.Context, performerID int)
 	table := qb.table()
 	joinTable := performersImagesJoinTable
 	q := dialect.Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table).InnerJoin(joinTable, goqu.On(table.Col(idColumn).Eq(joinTable.Col(imageIDColumn)))).Where(joinTable.Col(performerIDColumn).Eq(performerID))
+	q = restrictImages(ctx, q, table.Col(idColumn))
 
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -591,6 +634,7 @@ func (qb *ImageStore) FindByZipFileID(ctx context.Context, zipFileID models.File
 
 func (qb *ImageStore) Count(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q = restrictImages(ctx, q, qb.table().Col(idColumn))
 	return count(ctx, q)
 }
 
@@ -606,6 +650,7 @@ func (qb *ImageStore) Size(ctx context.Context) (float64, error) {
 		fileTable,
 		goqu.On(imagesFilesJoinTable.Col(fileIDColumn).Eq(fileTable.Col(idColumn))),
 	)
+	q = restrictImages(ctx, q, table.Col(idColumn))
 	var ret float64
 	if err := querySimple(ctx, q, &ret); err != nil {
 		return 0, err
@@ -674,10 +719,17 @@ func (qb *ImageStore) makeFilter(ctx context.Context, imageFilter *models.ImageF
 	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.Title, "images.title"))
 
 	query.handleCriterion(ctx, pathCriterionHandler(imageFilter.Path, "folders.path", "files.basename", qb.addFoldersTable))
+	query.handleCriterion(ctx, libraryCriterionHandler(imageFilter.Library, qb.libraries, "images.id", `SELECT images_files.image_id FROM images_files
+INNER JOIN files ON files.id = images_files.file_id
+INNER JOIN folders ON folders.id = files.parent_folder_id
+WHERE %s`))
 	query.handleCriterion(ctx, imageFileCountCriterionHandler(qb, imageFilter.FileCount))
 	query.handleCriterion(ctx, intCriterionHandler(imageFilter.Rating100, "images.rating", nil))
 	query.handleCriterion(ctx, intCriterionHandler(imageFilter.OCounter, "images.o_counter", nil))
+	query.handleCriterion(ctx, imagesColorsTableMgr.dominantCriterionHandler(imageTable, hueColumn, imageFilter.DominantHue))
+	query.handleCriterion(ctx, imagesColorsTableMgr.dominantCriterionHandler(imageTable, brightnessColumn, imageFilter.DominantBrightness))
 	query.handleCriterion(ctx, boolCriterionHandler(imageFilter.Organized, "images.organized", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(imageFilter.Favorite, "images.favorite", nil))
 	query.handleCriterion(ctx, dateCriterionHandler(imageFilter.Date, "images.date"))
 	query.handleCriterion(ctx, imageURLsCriterionHandler(imageFilter.URL))
 
@@ -694,6 +746,7 @@ func (qb *ImageStore) makeFilter(ctx context.Context, imageFilter *models.ImageF
 	query.handleCriterion(ctx, imagePerformerFavoriteCriterionHandler(imageFilter.PerformerFavorite))
 	query.handleCriterion(ctx, timestampCriterionHandler(imageFilter.CreatedAt, "images.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(imageFilter.UpdatedAt, "images.updated_at"))
+	query.handleCriterion(ctx, imageCustomFieldsTableMgr.criterionHandler("images.id", imageFilter.CustomFields))
 
 	return query
 }
@@ -753,6 +806,9 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("image_filter", imageFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(imageFilter); err != nil {
 		return nil, err
 	}
@@ -762,6 +818,10 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 		return nil, err
 	}
 
+	if err := restrictImageQuery(ctx, &query, "images.id"); err != nil {
+		return nil, err
+	}
+
 	qb.setImageSortAndPagination(&query, findFilter)
 
 	return &query, nil
@@ -939,14 +999,19 @@ func imageTagCountCriterionHandler(qb *ImageStore, tagCount *models.IntCriterion
 	return h.handler(tagCount)
 }
 
-func imageGalleriesCriterionHandler(qb *ImageStore, galleries *models.MultiCriterionInput) criterionHandlerFunc {
-	addJoinsFunc := func(f *filterBuilder) {
-		if galleries.Modifier == models.CriterionModifierIncludes || galleries.Modifier == models.CriterionModifierIncludesAll {
-			f.addInnerJoin(galleriesImagesTable, "", "galleries_images.image_id = images.id")
-			f.addInnerJoin(galleryTable, "", "galleries_images.gallery_id = galleries.id")
-		}
+func imageGalleriesCriterionHandler(qb *ImageStore, galleries *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
+	h := joinedHierarchicalMultiCriterionHandlerBuilder{
+		tx: qb.tx,
+
+		primaryTable: imageTable,
+		foreignTable: galleryTable,
+		foreignFK:    galleryIDColumn,
+		parentFK:     galleryParentIDColumn,
+
+		joinAs:    "image_gallery",
+		joinTable: galleriesImagesTable,
+		primaryFK: imageIDColumn,
 	}
-	h := qb.getMultiCriterionHandlerBuilder(galleryTable, galleriesImagesTable, galleryIDColumn, addJoinsFunc)
 
 	return h.handler(galleries)
 }
@@ -1056,6 +1121,10 @@ func (qb *ImageStore) setImageSortAndPagination(q *queryBuilder, findFilter *mod
 			addFilesJoin()
 			addFolderJoin()
 			sortClause = " ORDER BY COALESCE(images.title, files.basename) COLLATE NATURAL_CI " + direction + ", folders.path COLLATE NATURAL_CI " + direction
+		case "dominant_hue":
+			sortClause = imagesColorsTableMgr.dominantSort(imageTable, hueColumn, direction)
+		case "dominant_brightness":
+			sortClause = imagesColorsTableMgr.dominantSort(imageTable, brightnessColumn, direction)
 		default:
 			sortClause = getSort(sort, direction, "images")
 		}
@@ -1094,7 +1163,12 @@ func (qb *ImageStore) AddFileID(ctx context.Context, id int, fileID models.FileI
 }
 
 func (qb *ImageStore) GetGalleryIDs(ctx context.Context, imageID int) ([]int, error) {
-	return qb.galleriesRepository().getIDs(ctx, imageID)
+	ids, err := qb.galleriesRepository().getIDs(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return accessibleIDs(ctx, ids, galleryTableMgr.table.Col(idColumn), restrictedGalleryIDs(ctx))
 }
 
 // func (qb *imageQueryBuilder) UpdateGalleries(ctx context.Context, imageID int, galleryIDs []int) error {
@@ -1147,3 +1221,151 @@ func (qb *ImageStore) UpdateTags(ctx context.Context, imageID int, tagIDs []int)
 func (qb *ImageStore) GetURLs(ctx context.Context, imageID int) ([]string, error) {
 	return imagesURLsTableMgr.get(ctx, imageID)
 }
+
+func (qb *ImageStore) GetColorPalette(ctx context.Context, imageID int) ([]models.Color, error) {
+	return imagesColorsTableMgr.get(ctx, imageID)
+}
+
+func (qb *ImageStore) UpdateColorPalette(ctx context.Context, imageID int, palette []models.Color) error {
+	if err := qb.tableMgr.checkIDExists(ctx, imageID); err != nil {
+		return err
+	}
+
+	return imagesColorsTableMgr.replace(ctx, imageID, palette)
+}
+
+// FindSimilarByColor returns up to limit images with a dominant color closest
+// to that of the image with the given id. Images that are not accessible in
+// ctx are excluded.
+func (qb *ImageStore) FindSimilarByColor(ctx context.Context, imageID int, limit int) ([]*models.Image, error) {
+	ids, err := imagesColorsTableMgr.findSimilar(ctx, imageID, limit, restrictedImageIDs(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.FindMany(ctx, ids)
+}
+
+var findExactDuplicateImagesQuery = `
+SELECT GROUP_CONCAT(DISTINCT image_id) as ids
+FROM (
+	SELECT images.id as image_id
+		, files.size as file_size
+		, files_fingerprints.fingerprint as phash
+	FROM images
+	INNER JOIN images_files ON (images.id = images_files.image_id)
+	INNER JOIN files ON (images_files.file_id = files.id)
+	INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+)
+GROUP BY phash
+HAVING COUNT(DISTINCT image_id) > 1
+ORDER BY SUM(file_size) DESC;
+`
+
+var findAllImagePhashesQuery = `
+SELECT images.id as id
+    , files_fingerprints.fingerprint as phash
+FROM images
+INNER JOIN images_files ON (images.id = images_files.image_id)
+INNER JOIN files ON (images_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+ORDER BY files.size DESC;
+`
+
+// FindDuplicates returns groups of images with perceptual hashes within
+// distance of each other.
+func (qb *ImageStore) FindDuplicates(ctx context.Context, distance int) ([][]*models.Image, error) {
+	var dupeIds [][]int
+	if distance == 0 {
+		var ids []string
+		if err := qb.tx.Select(ctx, &ids, findExactDuplicateImagesQuery); err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			var imageIds []int
+			for _, strId := range strings.Split(id, ",") {
+				if intId, err := strconv.Atoi(strId); err == nil {
+					imageIds = sliceutil.AppendUnique(imageIds, intId)
+				}
+			}
+
+			if len(imageIds) > 1 {
+				dupeIds = append(dupeIds, imageIds)
+			}
+		}
+	} else {
+		var hashes []*utils.Phash
+
+		if err := qb.queryFunc(ctx, findAllImagePhashesQuery, nil, false, func(rows *sqlx.Rows) error {
+			phash := utils.Phash{
+				Bucket:   -1,
+				Duration: -1,
+			}
+			if err := rows.StructScan(&phash); err != nil {
+				return err
+			}
+
+			hashes = append(hashes, &phash)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		// images have no duration, so the duration check is disabled
+		dupeIds = utils.FindDuplicates(hashes, distance, -1)
+	}
+
+	var duplicates [][]*models.Image
+	for _, imageIds := range dupeIds {
+		// inaccessible images are excluded from the group
+		q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(imageIds))
+		if images, err := qb.getMany(ctx, q); err == nil && len(images) > 1 {
+			duplicates = append(duplicates, images)
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return getFirstImagePath(duplicates[i]) < getFirstImagePath(duplicates[j])
+	})
+
+	return duplicates, nil
+}
+
+func getFirstImagePath(images []*models.Image) string {
+	var firstPath string
+	for i, image := range images {
+		if i == 0 || image.Path < firstPath {
+			firstPath = image.Path
+		}
+	}
+	return firstPath
+}
+
+func (qb *ImageStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return imageCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *ImageStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return imageCustomFieldsTableMgr.set(ctx, id, fields)
+}
+
+func (qb *ImageStore) GetODates(ctx context.Context, id int) ([]time.Time, error) {
+	return imagesODatesTableMgr.get(ctx, id)
+}
+
+func (qb *ImageStore) ReplaceODates(ctx context.Context, id int, dates []time.Time) error {
+	return imagesODatesTableMgr.replaceDates(ctx, id, dates)
+}
+
+func (qb *ImageStore) GetRatingHistory(ctx context.Context, id int) ([]models.RatingHistoryEntry, error) {
+	return imagesRatingHistoryTableMgr.get(ctx, id)
+}
+
+func (qb *ImageStore) ReplaceRatingHistory(ctx context.Context, id int, history []models.RatingHistoryEntry) error {
+	return imagesRatingHistoryTableMgr.replaceEntries(ctx, id, history)
+}
@@ -1918,6 +1918,31 @@ func verifyImageResolution(t *testing.T, height int, resolution models.Resolutio
 	}
 }
 
+func TestImageQueryFavorite(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := db.Image
+		imageID := imageIDs[imageIdxWithGallery]
+
+		partial := models.NewImagePartial()
+		partial.Favorite = models.NewOptionalBool(true)
+		if _, err := sqb.UpdatePartial(ctx, imageID, partial); err != nil {
+			t.Errorf("UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		favorite := true
+		images := queryImages(ctx, t, sqb, &models.ImageFilterType{
+			Favorite: &favorite,
+		}, nil)
+		if assert.Len(t, images, 1) {
+			assert.Equal(t, imageID, images[0].ID)
+			assert.True(t, images[0].Favorite)
+		}
+
+		return nil
+	})
+}
+
 func TestImageQueryIsMissingGalleries(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		sqb := db.Image
@@ -2086,7 +2111,7 @@ func TestImageQueryIsMissingRating(t *testing.T) {
 func TestImageQueryGallery(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		sqb := db.Image
-		galleryCriterion := models.MultiCriterionInput{
+		galleryCriterion := models.HierarchicalMultiCriterionInput{
 			Value: []string{
 				strconv.Itoa(galleryIDs[galleryIdxWithImage]),
 			},
@@ -2105,7 +2130,7 @@ func TestImageQueryGallery(t *testing.T) {
 			assert.True(t, image.ID == imageIDs[imageIdxWithGallery])
 		}
 
-		galleryCriterion = models.MultiCriterionInput{
+		galleryCriterion = models.HierarchicalMultiCriterionInput{
 			Value: []string{
 				strconv.Itoa(galleryIDs[galleryIdx1WithImage]),
 				strconv.Itoa(galleryIDs[galleryIdx2WithImage]),
@@ -2118,7 +2143,7 @@ func TestImageQueryGallery(t *testing.T) {
 		assert.Len(t, images, 1)
 		assert.Equal(t, imageIDs[imageIdxWithTwoGalleries], images[0].ID)
 
-		galleryCriterion = models.MultiCriterionInput{
+		galleryCriterion = models.HierarchicalMultiCriterionInput{
 			Value: []string{
 				strconv.Itoa(performerIDs[galleryIdx1WithImage]),
 			},
@@ -2983,6 +3008,53 @@ func TestImageQueryPagination(t *testing.T) {
 	})
 }
 
+func TestImageStore_FindDuplicates(t *testing.T) {
+	qb := db.Image
+
+	withRollbackTxn(func(ctx context.Context) error {
+		// give two images phashes one bit apart
+		phashes := map[int]int64{
+			imageIdxWithGallery:    0x1234,
+			imageIdx1WithPerformer: 0x1235,
+		}
+		for idx, phash := range phashes {
+			f := makeImageFileWithID(idx)
+			f.Fingerprints = f.Fingerprints.AppendUnique(models.Fingerprint{
+				Type:        models.FingerprintTypePhash,
+				Fingerprint: phash,
+			})
+			if err := db.File.Update(ctx, f); err != nil {
+				t.Errorf("FileStore.Update() error = %v", err)
+				return nil
+			}
+		}
+
+		got, err := qb.FindDuplicates(ctx, 0)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return nil
+		}
+
+		assert.Len(t, got, 0)
+
+		got, err = qb.FindDuplicates(ctx, 1)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			var ids []int
+			for _, i := range got[0] {
+				ids = append(ids, i.ID)
+			}
+			assert.ElementsMatch(t, []int{imageIDs[imageIdxWithGallery], imageIDs[imageIdx1WithPerformer]}, ids)
+		}
+
+		return nil
+	})
+}
+
 // TODO Count
 // TODO SizeCount
 // TODO All
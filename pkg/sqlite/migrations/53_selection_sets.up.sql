@@ -0,0 +1,16 @@
+CREATE TABLE `selection_sets` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `mode` varchar(255) not null,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_selection_sets_on_mode_name_unique` on `selection_sets` (`mode`, `name`);
+
+CREATE TABLE `selection_sets_objects` (
+  `selection_set_id` integer not null,
+  `object_id` integer not null,
+  foreign key(`selection_set_id`) references `selection_sets`(`id`) on delete CASCADE,
+  PRIMARY KEY(`selection_set_id`, `object_id`)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE `scenes` ADD COLUMN `content_rating` integer not null default 0;
+ALTER TABLE `galleries` ADD COLUMN `content_rating` integer not null default 0;
@@ -0,0 +1,21 @@
+CREATE TABLE `scenes_colors` (
+  `scene_id` integer not null,
+  `position` integer not null,
+  `color` varchar(7) not null,
+  `hue` real not null,
+  `saturation` real not null,
+  `brightness` real not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `position`)
+);
+
+CREATE TABLE `images_colors` (
+  `image_id` integer not null,
+  `position` integer not null,
+  `color` varchar(7) not null,
+  `hue` real not null,
+  `saturation` real not null,
+  `brightness` real not null,
+  foreign key(`image_id`) references `images`(`id`) on delete CASCADE,
+  PRIMARY KEY(`image_id`, `position`)
+);
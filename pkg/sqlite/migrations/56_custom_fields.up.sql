@@ -0,0 +1,55 @@
+CREATE TABLE `scene_custom_fields` (
+  `scene_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `field`)
+);
+
+CREATE TABLE `image_custom_fields` (
+  `image_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`image_id`) references `images`(`id`) on delete CASCADE,
+  PRIMARY KEY(`image_id`, `field`)
+);
+
+CREATE TABLE `gallery_custom_fields` (
+  `gallery_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`gallery_id`) references `galleries`(`id`) on delete CASCADE,
+  PRIMARY KEY(`gallery_id`, `field`)
+);
+
+CREATE TABLE `performer_custom_fields` (
+  `performer_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  PRIMARY KEY(`performer_id`, `field`)
+);
+
+CREATE TABLE `studio_custom_fields` (
+  `studio_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  PRIMARY KEY(`studio_id`, `field`)
+);
+
+CREATE TABLE `tag_custom_fields` (
+  `tag_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`tag_id`) references `tags`(`id`) on delete CASCADE,
+  PRIMARY KEY(`tag_id`, `field`)
+);
+
+CREATE TABLE `movie_custom_fields` (
+  `movie_id` integer not null,
+  `field` varchar(64) not null,
+  `value` text not null,
+  foreign key(`movie_id`) references `movies`(`id`) on delete CASCADE,
+  PRIMARY KEY(`movie_id`, `field`)
+);
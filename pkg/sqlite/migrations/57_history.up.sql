@@ -0,0 +1,33 @@
+CREATE TABLE `scenes_o_dates` (
+  `scene_id` integer not null,
+  `o_date` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_scenes_o_dates` ON `scenes_o_dates` (`scene_id`);
+
+CREATE TABLE `images_o_dates` (
+  `image_id` integer not null,
+  `o_date` datetime not null,
+  foreign key(`image_id`) references `images`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_images_o_dates` ON `images_o_dates` (`image_id`);
+
+CREATE TABLE `scenes_rating_history` (
+  `scene_id` integer not null,
+  `rating` tinyint,
+  `date` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_scenes_rating_history` ON `scenes_rating_history` (`scene_id`);
+
+CREATE TABLE `images_rating_history` (
+  `image_id` integer not null,
+  `rating` tinyint,
+  `date` datetime not null,
+  foreign key(`image_id`) references `images`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_images_rating_history` ON `images_rating_history` (`image_id`);
@@ -0,0 +1,55 @@
+CREATE TABLE `audio_files` (
+  `file_id` integer NOT NULL primary key,
+  `format` varchar(255) NOT NULL,
+  `duration` float NOT NULL,
+  `audio_codec` varchar(255) NOT NULL,
+  `bit_rate` integer NOT NULL,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
+
+CREATE TABLE `audios` (
+  `id` integer not null primary key autoincrement,
+  `title` varchar(255),
+  `details` text,
+  `date` date,
+  `rating` tinyint,
+  `organized` boolean not null default '0',
+  `studio_id` integer,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete SET NULL
+);
+
+CREATE INDEX `index_audios_on_studio_id` on `audios` (`studio_id`);
+
+CREATE TABLE `audios_files` (
+  `audio_id` integer NOT NULL,
+  `file_id` integer NOT NULL,
+  `primary` boolean NOT NULL,
+  foreign key(`audio_id`) references `audios`(`id`) on delete CASCADE,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE,
+  PRIMARY KEY(`audio_id`, `file_id`)
+);
+
+CREATE INDEX `index_audios_files_on_file_id` on `audios_files` (`file_id`);
+CREATE UNIQUE INDEX `unique_index_audios_files_on_primary` on `audios_files` (`audio_id`) WHERE `primary` = 1;
+
+CREATE TABLE `audios_tags` (
+  `audio_id` integer NOT NULL,
+  `tag_id` integer NOT NULL,
+  foreign key(`audio_id`) references `audios`(`id`) on delete CASCADE,
+  foreign key(`tag_id`) references `tags`(`id`) on delete CASCADE,
+  PRIMARY KEY(`audio_id`, `tag_id`)
+);
+
+CREATE INDEX `index_audios_tags_on_tag_id` on `audios_tags` (`tag_id`);
+
+CREATE TABLE `performers_audios` (
+  `performer_id` integer NOT NULL,
+  `audio_id` integer NOT NULL,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  foreign key(`audio_id`) references `audios`(`id`) on delete CASCADE,
+  PRIMARY KEY(`audio_id`, `performer_id`)
+);
+
+CREATE INDEX `index_performers_audios_on_performer_id` on `performers_audios` (`performer_id`);
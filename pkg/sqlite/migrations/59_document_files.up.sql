@@ -0,0 +1,6 @@
+CREATE TABLE `document_files` (
+  `file_id` integer NOT NULL primary key,
+  `format` varchar(255) NOT NULL,
+  `page_count` integer NOT NULL,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
@@ -0,0 +1,7 @@
+CREATE TABLE `file_links` (
+  `path` text NOT NULL primary key,
+  `file_id` integer NOT NULL,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_file_links_on_file_id` on `file_links` (`file_id`);
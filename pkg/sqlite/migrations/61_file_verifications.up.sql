@@ -0,0 +1,8 @@
+CREATE TABLE `file_verifications` (
+  `file_id` integer NOT NULL primary key,
+  `verified_at` datetime NOT NULL,
+  `error` text,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_file_verifications_on_verified_at` on `file_verifications` (`verified_at`);
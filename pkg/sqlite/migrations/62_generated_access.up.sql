@@ -0,0 +1,4 @@
+CREATE TABLE `generated_access` (
+  `path` varchar(255) NOT NULL primary key,
+  `accessed_at` datetime NOT NULL
+);
@@ -0,0 +1,2 @@
+ALTER TABLE `scenes` ADD COLUMN `quarantined_at` datetime;
+CREATE INDEX `index_scenes_on_quarantined_at` on `scenes` (`quarantined_at`);
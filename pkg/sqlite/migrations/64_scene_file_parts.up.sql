@@ -0,0 +1 @@
+ALTER TABLE `scenes_files` ADD COLUMN `part` integer;
@@ -0,0 +1,8 @@
+CREATE TABLE `scene_preview_options` (
+  `scene_id` integer NOT NULL primary key,
+  `segments` integer,
+  `segment_duration` real,
+  `exclude_start` varchar(255),
+  `exclude_end` varchar(255),
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
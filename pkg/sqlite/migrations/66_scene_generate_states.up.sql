@@ -0,0 +1,7 @@
+CREATE TABLE `scene_generate_states` (
+  `scene_id` integer NOT NULL,
+  `options_hash` varchar(255) NOT NULL,
+  `state_hash` varchar(255) NOT NULL,
+  primary key (`scene_id`, `options_hash`),
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
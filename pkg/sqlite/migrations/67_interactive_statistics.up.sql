@@ -0,0 +1,3 @@
+ALTER TABLE `video_files` ADD COLUMN `interactive_average_speed` int;
+ALTER TABLE `video_files` ADD COLUMN `interactive_max_speed` int;
+ALTER TABLE `video_files` ADD COLUMN `interactive_action_density` int;
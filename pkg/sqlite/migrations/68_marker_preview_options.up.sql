@@ -0,0 +1,3 @@
+ALTER TABLE `scene_markers` ADD COLUMN `preview_format` varchar(255);
+ALTER TABLE `scene_markers` ADD COLUMN `preview_duration` float;
+ALTER TABLE `scene_markers` ADD COLUMN `preview_width` integer;
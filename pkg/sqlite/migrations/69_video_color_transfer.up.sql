@@ -0,0 +1,2 @@
+-- special value for unset to be updated during scan
+ALTER TABLE `video_files` ADD COLUMN `color_transfer` varchar(255) NOT NULL DEFAULT 'unset';
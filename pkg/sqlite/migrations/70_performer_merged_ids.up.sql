@@ -0,0 +1,7 @@
+CREATE TABLE `performers_merged_ids` (
+  `performer_id` integer not null,
+  `merged_id` integer not null primary key,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_performers_merged_ids_on_performer_id` ON `performers_merged_ids` (`performer_id`);
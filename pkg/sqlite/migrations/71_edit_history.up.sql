@@ -0,0 +1,13 @@
+CREATE TABLE `edit_history` (
+  `id` integer not null primary key autoincrement,
+  `object_type` varchar(255) not null,
+  `object_id` integer not null,
+  `field` varchar(255) not null,
+  `old_value` text,
+  `new_value` text,
+  `source` varchar(255) not null,
+  `created_at` datetime not null,
+  `reverted_at` datetime
+);
+
+CREATE INDEX `index_edit_history_on_object` ON `edit_history` (`object_type`, `object_id`);
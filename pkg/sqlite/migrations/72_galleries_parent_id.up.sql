@@ -0,0 +1,3 @@
+ALTER TABLE `galleries` ADD COLUMN `parent_id` integer DEFAULT NULL CHECK (`id` IS NOT `parent_id`) REFERENCES `galleries`(`id`) ON DELETE SET NULL;
+
+CREATE INDEX `index_galleries_on_parent_id` ON `galleries` (`parent_id`);
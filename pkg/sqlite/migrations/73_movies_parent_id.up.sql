@@ -0,0 +1,3 @@
+ALTER TABLE `movies` ADD COLUMN `parent_id` integer DEFAULT NULL CHECK (`id` IS NOT `parent_id`) REFERENCES `movies`(`id`) ON DELETE SET NULL;
+
+CREATE INDEX `index_movies_on_parent_id` ON `movies` (`parent_id`);
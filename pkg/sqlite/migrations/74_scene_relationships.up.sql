@@ -0,0 +1,14 @@
+CREATE TABLE `scene_relationships` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer not null,
+  `related_scene_id` integer not null,
+  `type` varchar(255) not null,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`related_scene_id`) references `scenes`(`id`) on delete CASCADE,
+  CHECK (`scene_id` != `related_scene_id`)
+);
+
+CREATE UNIQUE INDEX `index_scene_relationships_on_scene_id_related_scene_id_type` ON `scene_relationships` (`scene_id`, `related_scene_id`, `type`);
+CREATE INDEX `index_scene_relationships_on_related_scene_id` ON `scene_relationships` (`related_scene_id`);
@@ -0,0 +1,19 @@
+CREATE TABLE `rating_criteria` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_rating_criteria_on_name` ON `rating_criteria` (`name`);
+
+CREATE TABLE `scenes_criteria_ratings` (
+  `scene_id` integer not null,
+  `rating_criterion_id` integer not null,
+  `rating` tinyint not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`rating_criterion_id`) references `rating_criteria`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `rating_criterion_id`)
+);
+
+CREATE INDEX `index_scenes_criteria_ratings_on_rating_criterion_id` ON `scenes_criteria_ratings` (`rating_criterion_id`);
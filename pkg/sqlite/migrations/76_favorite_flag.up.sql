@@ -0,0 +1,3 @@
+ALTER TABLE `scenes` ADD COLUMN `favorite` boolean not null default '0';
+ALTER TABLE `images` ADD COLUMN `favorite` boolean not null default '0';
+ALTER TABLE `galleries` ADD COLUMN `favorite` boolean not null default '0';
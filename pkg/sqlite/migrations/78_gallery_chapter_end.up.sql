@@ -0,0 +1 @@
+ALTER TABLE `galleries_chapters` ADD COLUMN `end_image_index` integer;
@@ -17,8 +17,10 @@ import (
 )
 
 const (
-	movieTable    = "movies"
-	movieIDColumn = "movie_id"
+	movieTable             = "movies"
+	movieIDColumn          = "movie_id"
+	movieParentIDColumn    = "parent_id"
+	movieCustomFieldsTable = "movie_custom_fields"
 
 	movieFrontImageBlobColumn = "front_image_blob"
 	movieBackImageBlobColumn  = "back_image_blob"
@@ -33,6 +35,7 @@ type movieRow struct {
 	// expressed as 1-100
 	Rating    null.Int    `db:"rating"`
 	StudioID  null.Int    `db:"studio_id,omitempty"`
+	ParentID  null.Int    `db:"parent_id,omitempty"`
 	Director  zero.String `db:"director"`
 	Synopsis  zero.String `db:"synopsis"`
 	URL       zero.String `db:"url"`
@@ -52,6 +55,7 @@ func (r *movieRow) fromMovie(o models.Movie) {
 	r.Date = NullDateFromDatePtr(o.Date)
 	r.Rating = intFromPtr(o.Rating)
 	r.StudioID = intFromPtr(o.StudioID)
+	r.ParentID = intFromPtr(o.ParentID)
 	r.Director = zero.StringFrom(o.Director)
 	r.Synopsis = zero.StringFrom(o.Synopsis)
 	r.URL = zero.StringFrom(o.URL)
@@ -68,6 +72,7 @@ func (r *movieRow) resolve() *models.Movie {
 		Date:      r.Date.DatePtr(),
 		Rating:    nullIntPtr(r.Rating),
 		StudioID:  nullIntPtr(r.StudioID),
+		ParentID:  nullIntPtr(r.ParentID),
 		Director:  r.Director.String,
 		Synopsis:  r.Synopsis.String,
 		URL:       r.URL.String,
@@ -89,6 +94,7 @@ func (r *movieRowRecord) fromPartial(o models.MoviePartial) {
 	r.setNullDate("date", o.Date)
 	r.setNullInt("rating", o.Rating)
 	r.setNullInt("studio_id", o.StudioID)
+	r.setNullInt("parent_id", o.ParentID)
 	r.setNullString("director", o.Director)
 	r.setNullString("synopsis", o.Synopsis)
 	r.setNullString("url", o.URL)
@@ -155,6 +161,10 @@ func (qb *MovieStore) UpdatePartial(ctx context.Context, id int, partial models.
 	r.fromPartial(partial)
 
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeMovie, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
@@ -338,10 +348,13 @@ func (qb *MovieStore) makeFilter(ctx context.Context, movieFilter *models.MovieF
 	query.handleCriterion(ctx, movieIsMissingCriterionHandler(qb, movieFilter.IsMissing))
 	query.handleCriterion(ctx, stringCriterionHandler(movieFilter.URL, "movies.url"))
 	query.handleCriterion(ctx, studioCriterionHandler(movieTable, movieFilter.Studios))
+	query.handleCriterion(ctx, parentsCriterionHandler(qb.tx, movieTable, movieParentIDColumn, movieFilter.Parents))
+	query.handleCriterion(ctx, childCountCriterionHandler(movieTable, movieParentIDColumn, movieFilter.ChildCount))
 	query.handleCriterion(ctx, moviePerformersCriterionHandler(qb, movieFilter.Performers))
 	query.handleCriterion(ctx, dateCriterionHandler(movieFilter.Date, "movies.date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(movieFilter.CreatedAt, "movies.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(movieFilter.UpdatedAt, "movies.updated_at"))
+	query.handleCriterion(ctx, movieCustomFieldsTableMgr.criterionHandler("movies.id", movieFilter.CustomFields))
 
 	return query
 }
@@ -362,6 +375,9 @@ func (qb *MovieStore) makeQuery(ctx context.Context, movieFilter *models.MovieFi
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("movie_filter", movieFilter); err != nil {
+		return nil, err
+	}
 	filter := qb.makeFilter(ctx, movieFilter)
 
 	if err := query.addFilter(filter); err != nil {
@@ -575,6 +591,17 @@ WHERE movies.studio_id = ?
 	return qb.queryMovies(ctx, query, args)
 }
 
+func (qb *MovieStore) FindChildren(ctx context.Context, id int) ([]*models.Movie, error) {
+	table := qb.table()
+
+	sq := qb.selectDataset().Where(table.Col(movieParentIDColumn).Eq(id)).Order(
+		table.Col("name").Asc(),
+		table.Col(idColumn).Asc(),
+	)
+
+	return qb.getMany(ctx, sq)
+}
+
 func (qb *MovieStore) CountByStudioID(ctx context.Context, studioID int) (int, error) {
 	query := `SELECT COUNT(1) AS count
 FROM movies
@@ -583,3 +610,69 @@ WHERE movies.studio_id = ?
 	args := []interface{}{studioID}
 	return qb.runCountQuery(ctx, query, args)
 }
+
+// GetSceneIndexes returns the scenes of the movie ordered by scene index.
+// Scenes without an index are returned last, ordered by scene id.
+func (qb *MovieStore) GetSceneIndexes(ctx context.Context, movieID int) ([]models.MovieScene, error) {
+	table := scenesMoviesJoinTable
+	q := dialect.Select(table.Col(sceneIDColumn), table.Col("scene_index")).From(table).Where(
+		table.Col(movieIDColumn).Eq(movieID),
+	).Order(
+		goqu.L("? IS NULL", table.Col("scene_index")).Asc(),
+		table.Col("scene_index").Asc(),
+		table.Col(sceneIDColumn).Asc(),
+	)
+	q = restrictScenes(ctx, q, table.Col(sceneIDColumn))
+
+	const single = false
+	var ret []models.MovieScene
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var v moviesScenesRow
+		if err := rows.StructScan(&v); err != nil {
+			return err
+		}
+
+		ret = append(ret, models.MovieScene{
+			SceneID:    int(v.SceneID.Int64),
+			SceneIndex: nullIntPtr(v.SceneIndex),
+		})
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting scene indexes for movie %d: %w", movieID, err)
+	}
+
+	return ret, nil
+}
+
+// UpdateSceneIndexes sets the scene index of each of the provided scenes in the movie.
+// Scenes not in the movie are ignored.
+func (qb *MovieStore) UpdateSceneIndexes(ctx context.Context, movieID int, indexes []models.MovieScene) error {
+	table := scenesMoviesJoinTable
+	for _, v := range indexes {
+		q := dialect.Update(table).Set(goqu.Record{
+			"scene_index": intFromPtr(v.SceneIndex),
+		}).Where(
+			table.Col(movieIDColumn).Eq(movieID),
+			table.Col(sceneIDColumn).Eq(v.SceneID),
+		)
+
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("updating scene index for scene %d in movie %d: %w", v.SceneID, movieID, err)
+		}
+	}
+
+	return nil
+}
+
+func (qb *MovieStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return movieCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *MovieStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return movieCustomFieldsTableMgr.set(ctx, id, fields)
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/movie"
+	"github.com/stashapp/stash/pkg/scene"
 )
 
 func TestMovieFindByName(t *testing.T) {
@@ -330,3 +332,132 @@ func TestMovieUpdateBackImage(t *testing.T) {
 // TODO Count
 // TODO All
 // TODO Query
+
+func TestMovieSceneIndexes(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		mqb := db.Movie
+
+		movieID := movieIDs[movieIdxWithScene]
+		sceneID := sceneIDs[sceneIdxWithMovie]
+
+		indexes, err := mqb.GetSceneIndexes(ctx, movieID)
+		if err != nil {
+			t.Errorf("Error getting scene indexes: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, indexes, 1)
+		assert.Equal(t, sceneID, indexes[0].SceneID)
+
+		index := 3
+		if err := mqb.UpdateSceneIndexes(ctx, movieID, []models.MovieScene{
+			{SceneID: sceneID, SceneIndex: &index},
+		}); err != nil {
+			t.Errorf("Error updating scene indexes: %s", err.Error())
+			return nil
+		}
+
+		indexes, err = mqb.GetSceneIndexes(ctx, movieID)
+		if err != nil {
+			t.Errorf("Error getting scene indexes: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, []models.MovieScene{
+			{SceneID: sceneID, SceneIndex: &index},
+		}, indexes)
+
+		return nil
+	})
+}
+
+func TestMovieParents(t *testing.T) {
+	assert := assert.New(t)
+
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Movie
+
+		series := models.NewMovie()
+		series.Name = "movie series"
+		if err := qb.Create(ctx, &series); err != nil {
+			return err
+		}
+
+		volume := models.NewMovie()
+		volume.Name = "movie series volume"
+		volume.ParentID = &series.ID
+		if err := qb.Create(ctx, &volume); err != nil {
+			return err
+		}
+
+		// the movie with the scene is a part of the volume
+		partID := movieIDs[movieIdxWithScene]
+		partial := models.NewMoviePartial()
+		partial.ParentID = models.NewOptionalInt(volume.ID)
+		if _, err := qb.UpdatePartial(ctx, partID, partial); err != nil {
+			return err
+		}
+
+		children, err := qb.FindChildren(ctx, series.ID)
+		if err != nil {
+			return err
+		}
+		assert.Equal([]int{volume.ID}, moviesToIDs(children))
+
+		// movies cannot be their own ancestors
+		assert.ErrorIs(movie.ValidateParent(ctx, series.ID, partID, qb), models.ErrOwnAncestor)
+		assert.Nil(movie.ValidateParent(ctx, partID, series.ID, qb))
+
+		allDepth := -1
+		parents := models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(series.ID)},
+			Modifier: models.CriterionModifierIncludes,
+		}
+		movies := queryMovie(ctx, t, qb, &models.MovieFilterType{
+			Parents: &parents,
+		}, nil)
+		assert.Equal([]int{volume.ID}, moviesToIDs(movies))
+
+		parents.Depth = &allDepth
+		movies = queryMovie(ctx, t, qb, &models.MovieFilterType{
+			Parents: &parents,
+		}, nil)
+		assert.ElementsMatch([]int{volume.ID, partID}, moviesToIDs(movies))
+
+		movies = queryMovie(ctx, t, qb, &models.MovieFilterType{
+			ChildCount: &models.IntCriterionInput{
+				Value:    0,
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}, nil)
+		assert.ElementsMatch([]int{series.ID, volume.ID}, moviesToIDs(movies))
+
+		// scenes of child movies are included with depth
+		count, err := scene.CountByMovieID(ctx, db.Scene, series.ID, nil)
+		if err != nil {
+			return err
+		}
+		assert.Equal(0, count)
+
+		count, err = scene.CountByMovieID(ctx, db.Scene, series.ID, &allDepth)
+		if err != nil {
+			return err
+		}
+		assert.Equal(1, count)
+
+		// children are orphaned when the parent is destroyed
+		if err := qb.Destroy(ctx, series.ID); err != nil {
+			return err
+		}
+
+		found, err := qb.Find(ctx, volume.ID)
+		if err != nil {
+			return err
+		}
+		assert.Nil(found.ParentID)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -19,11 +19,14 @@ import (
 )
 
 const (
-	performerTable         = "performers"
-	performerIDColumn      = "performer_id"
-	performersAliasesTable = "performer_aliases"
-	performerAliasColumn   = "alias"
-	performersTagsTable    = "performers_tags"
+	performerTable             = "performers"
+	performerIDColumn          = "performer_id"
+	performersAliasesTable     = "performer_aliases"
+	performerAliasColumn       = "alias"
+	performersTagsTable        = "performers_tags"
+	performerCustomFieldsTable = "performer_custom_fields"
+	performersMergedIDsTable   = "performers_merged_ids"
+	performerMergedIDColumn    = "merged_id"
 
 	performerImageBlobColumn = "image_blob"
 )
@@ -252,11 +255,20 @@ func (qb *PerformerStore) UpdatePartial(ctx context.Context, id int, partial mod
 	r.fromPartial(partial)
 
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypePerformer, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
 	}
 
+	joins, err := newEditHistorySnapshot(ctx, models.EditObjectTypePerformer, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if partial.Aliases != nil {
 		if err := performersAliasesTableMgr.modifyJoins(ctx, id, partial.Aliases.Values, partial.Aliases.Mode); err != nil {
 			return nil, err
@@ -274,6 +286,10 @@ func (qb *PerformerStore) UpdatePartial(ctx context.Context, id int, partial mod
 		}
 	}
 
+	if err := joins.record(ctx); err != nil {
+		return nil, err
+	}
+
 	return qb.find(ctx, id)
 }
 
@@ -669,6 +685,7 @@ func (qb *PerformerStore) makeFilter(ctx context.Context, filter *models.Perform
 	query.handleCriterion(ctx, dateCriterionHandler(filter.DeathDate, tableName+".death_date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(filter.CreatedAt, tableName+".created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(filter.UpdatedAt, tableName+".updated_at"))
+	query.handleCriterion(ctx, performerCustomFieldsTableMgr.criterionHandler(tableName+".id", filter.CustomFields))
 
 	return query
 }
@@ -690,6 +707,9 @@ func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("performer_filter", performerFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(performerFilter); err != nil {
 		return nil, err
 	}
@@ -1116,3 +1136,119 @@ func (qb *PerformerStore) FindByStashIDStatus(ctx context.Context, hasStashID bo
 
 	return ret, nil
 }
+
+func (qb *PerformerStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return performerCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *PerformerStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return performerCustomFieldsTableMgr.set(ctx, id, fields)
+}
+
+// Merge moves the scenes, images, galleries, audios, tags, stash IDs and
+// custom fields of the source performers to the destination performer, and
+// destroys the source performers. Custom fields already set on the
+// destination are kept. The source IDs are recorded as merged into the
+// destination. Aliases are not merged.
+func (qb *PerformerStore) Merge(ctx context.Context, source []int, destination int) error {
+	if len(source) == 0 {
+		return nil
+	}
+
+	inBinding := getInBinding(len(source))
+
+	srcArgs := make([]interface{}, len(source))
+	for i, id := range source {
+		if id == destination {
+			return errors.New("cannot merge where source == destination")
+		}
+		srcArgs[i] = id
+	}
+
+	if err := qb.tableMgr.checkIDExists(ctx, destination); err != nil {
+		return err
+	}
+
+	args := append([]interface{}{destination}, srcArgs...)
+
+	joinTables := map[string]string{
+		performersScenesTable:    sceneIDColumn,
+		performersImagesTable:    imageIDColumn,
+		performersGalleriesTable: galleryIDColumn,
+		performersAudiosTable:    audioIDColumn,
+		performersTagsTable:      tagIDColumn,
+	}
+
+	for table, idColumn := range joinTables {
+		if _, err := qb.tx.Exec(ctx, `UPDATE `+table+`
+SET performer_id = ?
+WHERE performer_id IN `+inBinding+`
+AND NOT EXISTS(SELECT 1 FROM `+table+` o WHERE o.`+idColumn+` = `+table+`.`+idColumn+` AND o.performer_id = ?)`,
+			append(args, destination)...,
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := qb.tx.Exec(ctx, `UPDATE performer_stash_ids
+SET performer_id = ?
+WHERE performer_id IN `+inBinding+`
+AND NOT EXISTS(SELECT 1 FROM performer_stash_ids o WHERE o.endpoint = performer_stash_ids.endpoint AND o.stash_id = performer_stash_ids.stash_id AND o.performer_id = ?)`,
+		append(args, destination)...,
+	); err != nil {
+		return err
+	}
+
+	// the destination keeps its own value of fields set on both
+	if _, err := qb.tx.Exec(ctx, "UPDATE OR IGNORE "+performerCustomFieldsTable+" SET performer_id = ? WHERE performer_id IN "+inBinding, args...); err != nil {
+		return err
+	}
+
+	// performers previously merged into the sources now redirect to the
+	// destination
+	if _, err := qb.tx.Exec(ctx, "UPDATE "+performersMergedIDsTable+" SET performer_id = ? WHERE performer_id IN "+inBinding, args...); err != nil {
+		return err
+	}
+
+	for _, id := range source {
+		if _, err := qb.tx.Exec(ctx, "INSERT INTO "+performersMergedIDsTable+" (performer_id, merged_id) VALUES (?, ?)", destination, id); err != nil {
+			return err
+		}
+
+		// remaining joins of the source are removed by cascade
+		if err := qb.Destroy(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMergedIDs returns the IDs of the performers that were merged into the
+// performer.
+func (qb *PerformerStore) GetMergedIDs(ctx context.Context, performerID int) ([]int, error) {
+	return performersMergedIDsTableMgr.get(ctx, performerID)
+}
+
+// FindByMergedID returns the performer that the performer with the given ID
+// was merged into. Returns nil if the ID was not merged.
+func (qb *PerformerStore) FindByMergedID(ctx context.Context, mergedID int) (*models.Performer, error) {
+	sq := dialect.From(performersMergedIDsJoinTable).Select(performersMergedIDsJoinTable.Col(performerIDColumn)).Where(
+		performersMergedIDsJoinTable.Col(performerMergedIDColumn).Eq(mergedID),
+	)
+
+	ret, err := qb.findBySubquery(ctx, sq)
+	if err != nil {
+		return nil, fmt.Errorf("getting performer merged from %d: %w", mergedID, err)
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
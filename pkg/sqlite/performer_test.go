@@ -5,6 +5,7 @@ package sqlite_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
@@ -2015,3 +2016,266 @@ func TestPerformerStore_FindByStashIDStatus(t *testing.T) {
 // TODO Destroy
 // TODO Find
 // TODO Query
+
+func TestPerformerCustomFields(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Performer
+		id := performerIDs[performerIdxWithScene]
+
+		assert := assert.New(t)
+
+		if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: map[string]interface{}{
+				"string": "value",
+				"number": float64(1),
+			},
+		}); err != nil {
+			t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+			return nil
+		}
+
+		if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{
+				"bool": true,
+			},
+			Remove: []string{"number"},
+		}); err != nil {
+			t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+			return nil
+		}
+
+		got, err := qb.GetCustomFields(ctx, id)
+		if err != nil {
+			t.Errorf("PerformerStore.GetCustomFields() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(map[string]interface{}{
+			"string": "value",
+			"bool":   true,
+		}, got)
+
+		err = qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{
+				" invalid": true,
+			},
+		})
+		assert.ErrorIs(err, models.ErrInvalidCustomFieldName)
+
+		err = qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{
+				"object": map[string]interface{}{"key": "value"},
+			},
+		})
+		assert.ErrorIs(err, models.ErrInvalidCustomFieldValue)
+
+		err = qb.SetCustomFields(ctx, invalidID, models.CustomFieldsInput{
+			Partial: map[string]interface{}{
+				"string": "value",
+			},
+		})
+		assert.NotNil(err)
+
+		return nil
+	})
+}
+
+func TestPerformerQueryCustomFields(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Performer
+		id1 := performerIDs[performerIdxWithScene]
+		id2 := performerIDs[performerIdxWithGallery]
+
+		for id, fields := range map[int]map[string]interface{}{
+			id1: {"string": "first value", "number": float64(1), "bool": true, "date": "2020-01-02"},
+			id2: {"string": "second value", "number": float64(5), "bool": false},
+		} {
+			if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{Full: fields}); err != nil {
+				t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+				return nil
+			}
+		}
+
+		tests := []struct {
+			name     string
+			criteria []models.CustomFieldCriterionInput
+			includes []int
+			excludes []int
+		}{
+			{
+				"equals string",
+				[]models.CustomFieldCriterionInput{{Field: "string", Value: []interface{}{"first value"}, Modifier: models.CriterionModifierEquals}},
+				[]int{id1},
+				[]int{id2},
+			},
+			{
+				"not equals bool",
+				[]models.CustomFieldCriterionInput{{Field: "bool", Value: []interface{}{true}, Modifier: models.CriterionModifierNotEquals}},
+				[]int{id2},
+				[]int{id1},
+			},
+			{
+				"greater than number",
+				[]models.CustomFieldCriterionInput{{Field: "number", Value: []interface{}{json.Number("2")}, Modifier: models.CriterionModifierGreaterThan}},
+				[]int{id2},
+				[]int{id1},
+			},
+			{
+				"between dates",
+				[]models.CustomFieldCriterionInput{{Field: "date", Value: []interface{}{"2020-01-01", "2020-12-31"}, Modifier: models.CriterionModifierBetween}},
+				[]int{id1},
+				[]int{id2},
+			},
+			{
+				"includes",
+				[]models.CustomFieldCriterionInput{{Field: "string", Value: []interface{}{"second"}, Modifier: models.CriterionModifierIncludes}},
+				[]int{id2},
+				[]int{id1},
+			},
+			{
+				"is null",
+				[]models.CustomFieldCriterionInput{{Field: "date", Modifier: models.CriterionModifierIsNull}},
+				[]int{id2},
+				[]int{id1},
+			},
+			{
+				"all criteria",
+				[]models.CustomFieldCriterionInput{
+					{Field: "number", Modifier: models.CriterionModifierNotNull},
+					{Field: "bool", Value: []interface{}{false}, Modifier: models.CriterionModifierEquals},
+				},
+				[]int{id2},
+				[]int{id1},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				performers := queryPerformers(ctx, t, &models.PerformerFilterType{
+					CustomFields: tt.criteria,
+				}, nil)
+
+				var ids []int
+				for _, p := range performers {
+					ids = append(ids, p.ID)
+				}
+				for _, id := range tt.includes {
+					assert.Contains(t, ids, id)
+				}
+				for _, id := range tt.excludes {
+					assert.NotContains(t, ids, id)
+				}
+			})
+		}
+
+		_, _, err := qb.Query(ctx, &models.PerformerFilterType{
+			CustomFields: []models.CustomFieldCriterionInput{{Field: "number", Modifier: models.CriterionModifierBetween}},
+		}, nil)
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
+
+func TestPerformerMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	// merge tests - perform these in a transaction that we'll rollback
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Performer
+
+		// try merging into same performer
+		err := qb.Merge(ctx, []int{performerIDs[performerIdx1WithScene]}, performerIDs[performerIdx1WithScene])
+		assert.NotNil(err)
+
+		srcIDs := []int{
+			performerIDs[performerIdx2WithScene],
+			performerIDs[performerIdxWithImage],
+			performerIDs[performerIdxWithGallery],
+		}
+		destID := performerIDs[performerIdx1WithScene]
+
+		if err := qb.Merge(ctx, srcIDs, destID); err != nil {
+			return err
+		}
+
+		// ensure source performers are deleted and redirect to the destination
+		for _, id := range srcIDs {
+			p, err := qb.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+			assert.Nil(p)
+
+			p, err = qb.FindByMergedID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if assert.NotNil(p) {
+				assert.Equal(destID, p.ID)
+			}
+		}
+
+		mergedIDs, err := qb.GetMergedIDs(ctx, destID)
+		if err != nil {
+			return err
+		}
+		assert.ElementsMatch(srcIDs, mergedIDs)
+
+		// ensure the scene shared with a source has the destination once
+		s, err := db.Scene.Find(ctx, sceneIDs[sceneIdxWithTwoPerformers])
+		if err != nil {
+			return err
+		}
+		if err := s.LoadPerformerIDs(ctx, db.Scene); err != nil {
+			return err
+		}
+		assert.Equal([]int{destID}, s.PerformerIDs.List())
+
+		// ensure image and gallery point to the destination
+		i, err := db.Image.Find(ctx, imageIDs[imageIdxWithPerformer])
+		if err != nil {
+			return err
+		}
+		if err := i.LoadPerformerIDs(ctx, db.Image); err != nil {
+			return err
+		}
+		assert.Contains(i.PerformerIDs.List(), destID)
+
+		g, err := db.Gallery.Find(ctx, galleryIDs[galleryIdxWithPerformer])
+		if err != nil {
+			return err
+		}
+		if err := g.LoadPerformerIDs(ctx, db.Gallery); err != nil {
+			return err
+		}
+		assert.Contains(g.PerformerIDs.List(), destID)
+
+		// merging the destination moves its merged ids
+		newDestID := performerIDs[performerIdx3WithScene]
+		if err := qb.Merge(ctx, []int{destID}, newDestID); err != nil {
+			return err
+		}
+
+		for _, id := range append(srcIDs, destID) {
+			p, err := qb.FindByMergedID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if assert.NotNil(p) {
+				assert.Equal(newDestID, p.ID)
+			}
+		}
+
+		// ids that were not merged are not found
+		p, err := qb.FindByMergedID(ctx, newDestID)
+		if err != nil {
+			return err
+		}
+		assert.Nil(p)
+
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+const (
+	ratingCriteriaTable = "rating_criteria"
+)
+
+type ratingCriterionRow struct {
+	ID        int       `db:"id" goqu:"skipinsert"`
+	Name      string    `db:"name"`
+	CreatedAt Timestamp `db:"created_at"`
+	UpdatedAt Timestamp `db:"updated_at"`
+}
+
+func (r *ratingCriterionRow) fromRatingCriterion(o models.RatingCriterion) {
+	r.ID = o.ID
+	r.Name = o.Name
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *ratingCriterionRow) resolve() *models.RatingCriterion {
+	ret := &models.RatingCriterion{
+		ID:        r.ID,
+		Name:      r.Name,
+		CreatedAt: r.CreatedAt.Timestamp,
+		UpdatedAt: r.UpdatedAt.Timestamp,
+	}
+
+	return ret
+}
+
+type ratingCriterionRowRecord struct {
+	updateRecord
+}
+
+func (r *ratingCriterionRowRecord) fromPartial(o models.RatingCriterionPartial) {
+	r.setString("name", o.Name)
+	r.setTimestamp("created_at", o.CreatedAt)
+	r.setTimestamp("updated_at", o.UpdatedAt)
+}
+
+type RatingCriterionStore struct {
+	repository
+
+	tableMgr *table
+}
+
+func NewRatingCriterionStore() *RatingCriterionStore {
+	return &RatingCriterionStore{
+		repository: repository{
+			tableName: ratingCriteriaTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: ratingCriteriaTableMgr,
+	}
+}
+
+func (qb *RatingCriterionStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *RatingCriterionStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *RatingCriterionStore) Create(ctx context.Context, newObject *models.RatingCriterion) error {
+	var r ratingCriterionRow
+	r.fromRatingCriterion(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *RatingCriterionStore) UpdatePartial(ctx context.Context, id int, partial models.RatingCriterionPartial) (*models.RatingCriterion, error) {
+	r := ratingCriterionRowRecord{
+		updateRecord{
+			Record: make(exp.Record),
+		},
+	}
+
+	r.fromPartial(partial)
+
+	if len(r.Record) > 0 {
+		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
+			return nil, err
+		}
+	}
+
+	return qb.find(ctx, id)
+}
+
+func (qb *RatingCriterionStore) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *RatingCriterionStore) Find(ctx context.Context, id int) (*models.RatingCriterion, error) {
+	ret, err := qb.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *RatingCriterionStore) FindMany(ctx context.Context, ids []int) ([]*models.RatingCriterion, error) {
+	ret := make([]*models.RatingCriterion, len(ids))
+
+	table := qb.table()
+	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range unsorted {
+		i := sliceutil.Index(ids, s.ID)
+		ret[i] = s
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("rating criterion with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+// returns nil, nil if not found
+func (qb *RatingCriterionStore) FindByName(ctx context.Context, name string) (*models.RatingCriterion, error) {
+	q := qb.selectDataset().Where(qb.table().Col("name").Eq(name))
+
+	ret, err := qb.get(ctx, q)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *RatingCriterionStore) All(ctx context.Context) ([]*models.RatingCriterion, error) {
+	return qb.getMany(ctx, qb.selectDataset().Order(qb.table().Col("name").Asc()))
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *RatingCriterionStore) find(ctx context.Context, id int) (*models.RatingCriterion, error) {
+	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+
+	ret, err := qb.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *RatingCriterionStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.RatingCriterion, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *RatingCriterionStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.RatingCriterion, error) {
+	const single = false
+	var ret []*models.RatingCriterion
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f ratingCriterionRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		s := f.resolve()
+
+		ret = append(ret, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,189 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatingCriterionStore(t *testing.T) {
+	runWithRollbackTxn(t, "crud", func(t *testing.T, ctx context.Context) {
+		qb := db.RatingCriterion
+
+		quality := models.NewRatingCriterion()
+		quality.Name = "Video quality"
+		if err := qb.Create(ctx, &quality); err != nil {
+			t.Errorf("Create() error = %v", err)
+			return
+		}
+
+		content := models.NewRatingCriterion()
+		content.Name = "Content"
+		if err := qb.Create(ctx, &content); err != nil {
+			t.Errorf("Create() error = %v", err)
+			return
+		}
+
+		duplicate := models.NewRatingCriterion()
+		duplicate.Name = quality.Name
+		assert.Error(t, qb.Create(ctx, &duplicate))
+
+		found, err := qb.FindByName(ctx, quality.Name)
+		if err != nil {
+			t.Errorf("FindByName() error = %v", err)
+			return
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, quality.ID, found.ID)
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("All() error = %v", err)
+			return
+		}
+		if assert.Len(t, all, 2) {
+			assert.Equal(t, content.ID, all[0].ID)
+		}
+
+		partial := models.NewRatingCriterionPartial()
+		partial.Name = models.NewOptionalString("Story")
+		updated, err := qb.UpdatePartial(ctx, content.ID, partial)
+		if err != nil {
+			t.Errorf("UpdatePartial() error = %v", err)
+			return
+		}
+		assert.Equal(t, "Story", updated.Name)
+
+		if err := qb.Destroy(ctx, content.ID); err != nil {
+			t.Errorf("Destroy() error = %v", err)
+			return
+		}
+
+		found, err = qb.Find(ctx, content.ID)
+		if err != nil {
+			t.Errorf("Find() error = %v", err)
+			return
+		}
+		assert.Nil(t, found)
+	})
+}
+
+func TestSceneCriteriaRatings(t *testing.T) {
+	runWithRollbackTxn(t, "criteria ratings", func(t *testing.T, ctx context.Context) {
+		sqb := db.Scene
+
+		quality := models.NewRatingCriterion()
+		quality.Name = "Video quality"
+		content := models.NewRatingCriterion()
+		content.Name = "Content"
+		for _, c := range []*models.RatingCriterion{&quality, &content} {
+			if err := db.RatingCriterion.Create(ctx, c); err != nil {
+				t.Errorf("Create() error = %v", err)
+				return
+			}
+		}
+
+		highID := sceneIDs[sceneIdxWithMovie]
+		lowID := sceneIDs[sceneIdxWithGallery]
+
+		set := func(id int, input models.CriteriaRatingsInput) bool {
+			if err := sqb.SetCriteriaRatings(ctx, id, input); err != nil {
+				t.Errorf("SetCriteriaRatings() error = %v", err)
+				return false
+			}
+			return true
+		}
+
+		if !set(highID, models.CriteriaRatingsInput{Set: []models.CriterionRating{
+			{CriterionID: quality.ID, Rating: 90},
+			{CriterionID: content.ID, Rating: 70},
+		}}) || !set(lowID, models.CriteriaRatingsInput{Set: []models.CriterionRating{
+			{CriterionID: quality.ID, Rating: 40},
+			{CriterionID: content.ID, Rating: 20},
+		}}) {
+			return
+		}
+
+		assert.ErrorIs(t, sqb.SetCriteriaRatings(ctx, highID, models.CriteriaRatingsInput{Set: []models.CriterionRating{
+			{CriterionID: quality.ID, Rating: 101},
+		}}), models.ErrInvalidCriterionRating)
+
+		got, err := sqb.GetCriteriaRatings(ctx, highID)
+		if err != nil {
+			t.Errorf("GetCriteriaRatings() error = %v", err)
+			return
+		}
+		assert.Equal(t, []models.CriterionRating{
+			{CriterionID: content.ID, Rating: 70},
+			{CriterionID: quality.ID, Rating: 90},
+		}, got)
+
+		sceneFilter := &models.SceneFilterType{
+			CriteriaRatings: []models.CriterionRatingCriterionInput{
+				{
+					CriterionID: strconv.Itoa(quality.ID),
+					Rating: models.IntCriterionInput{
+						Value:    50,
+						Modifier: models.CriterionModifierGreaterThan,
+					},
+				},
+			},
+		}
+		scenes := queryScene(ctx, t, sqb, sceneFilter, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, highID, scenes[0].ID)
+		}
+
+		sceneFilter = &models.SceneFilterType{
+			CriteriaRatingAverage: &models.IntCriterionInput{
+				Value:    30,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		scenes = queryScene(ctx, t, sqb, sceneFilter, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, lowID, scenes[0].ID)
+		}
+
+		sortBy := "criterion_rating_" + strconv.Itoa(content.ID)
+		direction := models.SortDirectionEnumDesc
+		scenes = queryScene(ctx, t, sqb, nil, &models.FindFilterType{
+			Sort:      &sortBy,
+			Direction: &direction,
+		})
+		if assert.GreaterOrEqual(t, len(scenes), 2) {
+			assert.Equal(t, highID, scenes[0].ID)
+			assert.Equal(t, lowID, scenes[1].ID)
+		}
+
+		// removing a rating changes the average
+		if !set(lowID, models.CriteriaRatingsInput{Remove: []int{content.ID}}) {
+			return
+		}
+		got, err = sqb.GetCriteriaRatings(ctx, lowID)
+		if err != nil {
+			t.Errorf("GetCriteriaRatings() error = %v", err)
+			return
+		}
+		assert.Equal(t, 40, *models.AverageCriteriaRating(got))
+
+		// destroying a criterion removes its ratings
+		if err := db.RatingCriterion.Destroy(ctx, quality.ID); err != nil {
+			t.Errorf("Destroy() error = %v", err)
+			return
+		}
+		got, err = sqb.GetCriteriaRatings(ctx, lowID)
+		if err != nil {
+			t.Errorf("GetCriteriaRatings() error = %v", err)
+			return
+		}
+		assert.Len(t, got, 0)
+	})
+}
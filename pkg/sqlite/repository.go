@@ -439,6 +439,15 @@ func (r *stashIDRepository) get(ctx context.Context, id int) ([]models.StashID,
 
 type filesRepository struct {
 	repository
+	// orderBy is appended to queries to order the non-primary files, if set
+	orderBy string
+}
+
+func (r *filesRepository) orderClause() string {
+	if r.orderBy == "" {
+		return ""
+	}
+	return " ORDER BY " + r.orderBy
 }
 
 type relatedFileRow struct {
@@ -453,7 +462,7 @@ func (r *filesRepository) getMany(ctx context.Context, ids []int, primaryOnly bo
 		primaryClause = " AND `primary` = 1"
 	}
 
-	query := fmt.Sprintf("SELECT %s as id, file_id, `primary` from %s WHERE %[1]s IN %[3]s%s", r.idColumn, r.tableName, getInBinding(len(ids)), primaryClause)
+	query := fmt.Sprintf("SELECT %s as id, file_id, `primary` from %s WHERE %[1]s IN %[3]s%s%s", r.idColumn, r.tableName, getInBinding(len(ids)), primaryClause, r.orderClause())
 
 	idi := make([]interface{}, len(ids))
 	for i, id := range ids {
@@ -497,7 +506,7 @@ func (r *filesRepository) getMany(ctx context.Context, ids []int, primaryOnly bo
 }
 
 func (r *filesRepository) get(ctx context.Context, id int) ([]models.FileID, error) {
-	query := fmt.Sprintf("SELECT file_id, `primary` from %s WHERE %s = ?", r.tableName, r.idColumn)
+	query := fmt.Sprintf("SELECT file_id, `primary` from %s WHERE %s = ?%s", r.tableName, r.idColumn, r.orderClause())
 
 	type relatedFile struct {
 		FileID  models.FileID `db:"file_id"`
@@ -23,15 +23,21 @@ import (
 )
 
 const (
-	sceneTable            = "scenes"
-	scenesFilesTable      = "scenes_files"
-	sceneIDColumn         = "scene_id"
-	performersScenesTable = "performers_scenes"
-	scenesTagsTable       = "scenes_tags"
-	scenesGalleriesTable  = "scenes_galleries"
-	moviesScenesTable     = "movies_scenes"
-	scenesURLsTable       = "scene_urls"
-	sceneURLColumn        = "url"
+	sceneTable                 = "scenes"
+	scenesFilesTable           = "scenes_files"
+	sceneIDColumn              = "scene_id"
+	performersScenesTable      = "performers_scenes"
+	scenesTagsTable            = "scenes_tags"
+	scenesGalleriesTable       = "scenes_galleries"
+	moviesScenesTable          = "movies_scenes"
+	scenesURLsTable            = "scene_urls"
+	sceneURLColumn             = "url"
+	sceneCustomFieldsTable     = "scene_custom_fields"
+	scenesColorsTable          = "scenes_colors"
+	scenesCriteriaRatingsTable = "scenes_criteria_ratings"
+
+	scenePreviewOptionsTable = "scene_preview_options"
+	sceneGenerateStatesTable = "scene_generate_states"
 
 	sceneCoverBlobColumn = "cover_blob"
 )
@@ -79,16 +85,19 @@ type sceneRow struct {
 	Director zero.String `db:"director"`
 	Date     NullDate    `db:"date"`
 	// expressed as 1-100
-	Rating       null.Int      `db:"rating"`
-	Organized    bool          `db:"organized"`
-	OCounter     int           `db:"o_counter"`
-	StudioID     null.Int      `db:"studio_id,omitempty"`
-	CreatedAt    Timestamp     `db:"created_at"`
-	UpdatedAt    Timestamp     `db:"updated_at"`
-	LastPlayedAt NullTimestamp `db:"last_played_at"`
-	ResumeTime   float64       `db:"resume_time"`
-	PlayDuration float64       `db:"play_duration"`
-	PlayCount    int           `db:"play_count"`
+	Rating        null.Int      `db:"rating"`
+	Organized     bool          `db:"organized"`
+	Favorite      bool          `db:"favorite"`
+	OCounter      int           `db:"o_counter"`
+	StudioID      null.Int      `db:"studio_id,omitempty"`
+	ContentRating int           `db:"content_rating"`
+	CreatedAt     Timestamp     `db:"created_at"`
+	UpdatedAt     Timestamp     `db:"updated_at"`
+	LastPlayedAt  NullTimestamp `db:"last_played_at"`
+	ResumeTime    float64       `db:"resume_time"`
+	PlayDuration  float64       `db:"play_duration"`
+	PlayCount     int           `db:"play_count"`
+	QuarantinedAt NullTimestamp `db:"quarantined_at"`
 
 	// not used in resolutions or updates
 	CoverBlob zero.String `db:"cover_blob"`
@@ -103,11 +112,14 @@ func (r *sceneRow) fromScene(o models.Scene) {
 	r.Date = NullDateFromDatePtr(o.Date)
 	r.Rating = intFromPtr(o.Rating)
 	r.Organized = o.Organized
+	r.Favorite = o.Favorite
 	r.OCounter = o.OCounter
 	r.StudioID = intFromPtr(o.StudioID)
+	r.ContentRating = o.ContentRating
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
 	r.LastPlayedAt = NullTimestampFromTimePtr(o.LastPlayedAt)
+	r.QuarantinedAt = NullTimestampFromTimePtr(o.QuarantinedAt)
 	r.ResumeTime = o.ResumeTime
 	r.PlayDuration = o.PlayDuration
 	r.PlayCount = o.PlayCount
@@ -124,16 +136,18 @@ type sceneQueryRow struct {
 
 func (r *sceneQueryRow) resolve() *models.Scene {
 	ret := &models.Scene{
-		ID:        r.ID,
-		Title:     r.Title.String,
-		Code:      r.Code.String,
-		Details:   r.Details.String,
-		Director:  r.Director.String,
-		Date:      r.Date.DatePtr(),
-		Rating:    nullIntPtr(r.Rating),
-		Organized: r.Organized,
-		OCounter:  r.OCounter,
-		StudioID:  nullIntPtr(r.StudioID),
+		ID:            r.ID,
+		Title:         r.Title.String,
+		Code:          r.Code.String,
+		Details:       r.Details.String,
+		Director:      r.Director.String,
+		Date:          r.Date.DatePtr(),
+		Rating:        nullIntPtr(r.Rating),
+		Organized:     r.Organized,
+		Favorite:      r.Favorite,
+		OCounter:      r.OCounter,
+		StudioID:      nullIntPtr(r.StudioID),
+		ContentRating: r.ContentRating,
 
 		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
 		OSHash:        r.PrimaryFileOshash.String,
@@ -142,10 +156,11 @@ func (r *sceneQueryRow) resolve() *models.Scene {
 		CreatedAt: r.CreatedAt.Timestamp,
 		UpdatedAt: r.UpdatedAt.Timestamp,
 
-		LastPlayedAt: r.LastPlayedAt.TimePtr(),
-		ResumeTime:   r.ResumeTime,
-		PlayDuration: r.PlayDuration,
-		PlayCount:    r.PlayCount,
+		LastPlayedAt:  r.LastPlayedAt.TimePtr(),
+		ResumeTime:    r.ResumeTime,
+		PlayDuration:  r.PlayDuration,
+		PlayCount:     r.PlayCount,
+		QuarantinedAt: r.QuarantinedAt.TimePtr(),
 	}
 
 	if r.PrimaryFileFolderPath.Valid && r.PrimaryFileBasename.Valid {
@@ -167,11 +182,14 @@ func (r *sceneRowRecord) fromPartial(o models.ScenePartial) {
 	r.setNullDate("date", o.Date)
 	r.setNullInt("rating", o.Rating)
 	r.setBool("organized", o.Organized)
+	r.setBool("favorite", o.Favorite)
 	r.setInt("o_counter", o.OCounter)
 	r.setNullInt("studio_id", o.StudioID)
+	r.setInt("content_rating", o.ContentRating)
 	r.setTimestamp("created_at", o.CreatedAt)
 	r.setTimestamp("updated_at", o.UpdatedAt)
 	r.setNullTimestamp("last_played_at", o.LastPlayedAt)
+	r.setNullTimestamp("quarantined_at", o.QuarantinedAt)
 	r.setFloat64("resume_time", o.ResumeTime)
 	r.setFloat64("play_duration", o.PlayDuration)
 	r.setInt("play_count", o.PlayCount)
@@ -185,9 +203,10 @@ type SceneStore struct {
 	oCounterManager
 
 	fileStore *FileStore
+	libraries *libraryPaths
 }
 
-func NewSceneStore(fileStore *FileStore, blobStore *BlobStore) *SceneStore {
+func NewSceneStore(fileStore *FileStore, blobStore *BlobStore, libraries *libraryPaths) *SceneStore {
 	return &SceneStore{
 		repository: repository{
 			tableName: sceneTable,
@@ -199,8 +218,9 @@ func NewSceneStore(fileStore *FileStore, blobStore *BlobStore) *SceneStore {
 		},
 
 		tableMgr:        sceneTableMgr,
-		oCounterManager: oCounterManager{sceneTableMgr},
+		oCounterManager: oCounterManager{sceneTableMgr, scenesODatesTableMgr},
 		fileStore:       fileStore,
+		libraries:       libraries,
 	}
 }
 
@@ -258,6 +278,10 @@ func (qb *SceneStore) Create(ctx context.Context, newObject *models.Scene, fileI
 		return err
 	}
 
+	if err := scenesRatingHistoryTableMgr.recordInitial(ctx, id, newObject.Rating); err != nil {
+		return err
+	}
+
 	if len(fileIDs) > 0 {
 		const firstPrimary = true
 		if err := scenesFilesTableMgr.insertJoins(ctx, id, firstPrimary, fileIDs); err != nil {
@@ -320,12 +344,27 @@ func (qb *SceneStore) UpdatePartial(ctx context.Context, id int, partial models.
 
 	r.fromPartial(partial)
 
+	if partial.Rating.Set {
+		if err := scenesRatingHistoryTableMgr.recordChange(ctx, qb.tableMgr, id, partial.Rating.Ptr()); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeScene, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
 	}
 
+	joins, err := newEditHistorySnapshot(ctx, models.EditObjectTypeScene, id)
+	if err != nil {
+		return nil, err
+	}
+
 	if partial.URLs != nil {
 		if err := scenesURLsTableMgr.modifyJoins(ctx, id, partial.URLs.Values, partial.URLs.Mode); err != nil {
 			return nil, err
@@ -356,6 +395,11 @@ func (qb *SceneStore) UpdatePartial(ctx context.Context, id int, partial models.
 			return nil, err
 		}
 	}
+
+	if err := joins.record(ctx); err != nil {
+		return nil, err
+	}
+
 	if partial.PrimaryFileID != nil {
 		if err := scenesFilesTableMgr.setPrimary(ctx, id, *partial.PrimaryFileID); err != nil {
 			return nil, err
@@ -369,6 +413,10 @@ func (qb *SceneStore) Update(ctx context.Context, updatedObject *models.Scene) e
 	var r sceneRow
 	r.fromScene(*updatedObject)
 
+	if err := scenesRatingHistoryTableMgr.recordChange(ctx, qb.tableMgr, updatedObject.ID, updatedObject.Rating); err != nil {
+		return err
+	}
+
 	if err := qb.tableMgr.updateByID(ctx, updatedObject.ID, r); err != nil {
 		return err
 	}
@@ -513,6 +561,8 @@ func (qb *SceneStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.S
 }
 
 func (qb *SceneStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.Scene, error) {
+	q = restrictScenes(ctx, q, qb.table().Col(idColumn))
+
 	const single = false
 	var ret []*models.Scene
 	var lastID int
@@ -704,6 +754,7 @@ func (qb *SceneStore) CountByPerformerID(ctx context.Context, performerID int) (
 	joinTable := scenesPerformersJoinTable
 
 	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(performerIDColumn).Eq(performerID))
+	q = restrictScenes(ctx, q, joinTable.Col(sceneIDColumn))
 	return count(ctx, q)
 }
 
@@ -712,6 +763,7 @@ func (qb *SceneStore) OCountByPerformerID(ctx context.Context, performerID int)
 	joinTable := scenesPerformersJoinTable
 
 	q := dialect.Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table).InnerJoin(joinTable, goqu.On(table.Col(idColumn).Eq(joinTable.Col(sceneIDColumn)))).Where(joinTable.Col(performerIDColumn).Eq(performerID))
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
 		return 0, err
@@ -724,6 +776,7 @@ func (qb *SceneStore) OCount(ctx context.Context) (int, error) {
 	table := qb.table()
 
 	q := dialect.Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table)
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
 		return 0, err
@@ -749,16 +802,19 @@ func (qb *SceneStore) CountByMovieID(ctx context.Context, movieID int) (int, err
 	joinTable := scenesMoviesJoinTable
 
 	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(movieIDColumn).Eq(movieID))
+	q = restrictScenes(ctx, q, joinTable.Col(sceneIDColumn))
 	return count(ctx, q)
 }
 
 func (qb *SceneStore) Count(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q = restrictScenes(ctx, q, qb.table().Col(idColumn))
 	return count(ctx, q)
 }
 
 func (qb *SceneStore) PlayCount(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COALESCE(goqu.SUM("play_count"), 0)).From(qb.table())
+	q = restrictScenes(ctx, q, qb.table().Col(idColumn))
 
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -771,6 +827,7 @@ func (qb *SceneStore) PlayCount(ctx context.Context) (int, error) {
 func (qb *SceneStore) UniqueScenePlayCount(ctx context.Context) (int, error) {
 	table := qb.table()
 	q := dialect.Select(goqu.COUNT("*")).From(table).Where(table.Col("play_count").Gt(0))
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 
 	return count(ctx, q)
 }
@@ -787,6 +844,7 @@ func (qb *SceneStore) Size(ctx context.Context) (float64, error) {
 		fileTable,
 		goqu.On(scenesFilesJoinTable.Col(fileIDColumn).Eq(fileTable.Col(idColumn))),
 	)
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 	var ret float64
 	if err := querySimple(ctx, q, &ret); err != nil {
 		return 0, err
@@ -807,6 +865,7 @@ func (qb *SceneStore) Duration(ctx context.Context) (float64, error) {
 		videoFileTable,
 		goqu.On(videoFileTable.Col("file_id").Eq(scenesFilesJoinTable.Col("file_id"))),
 	)
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 
 	var ret float64
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -820,6 +879,7 @@ func (qb *SceneStore) PlayDuration(ctx context.Context) (float64, error) {
 	table := qb.table()
 
 	q := dialect.Select(goqu.COALESCE(goqu.SUM("play_duration"), 0)).From(table)
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 
 	var ret float64
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -833,6 +893,7 @@ func (qb *SceneStore) CountByStudioID(ctx context.Context, studioID int) (int, e
 	table := qb.table()
 
 	q := dialect.Select(goqu.COUNT("*")).From(table).Where(table.Col(studioIDColumn).Eq(studioID))
+	q = restrictScenes(ctx, q, table.Col(idColumn))
 	return count(ctx, q)
 }
 
@@ -840,6 +901,7 @@ func (qb *SceneStore) CountByTagID(ctx context.Context, tagID int) (int, error)
 	joinTable := scenesTagsJoinTable
 
 	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(tagIDColumn).Eq(tagID))
+	q = restrictScenes(ctx, q, joinTable.Col(sceneIDColumn))
 	return count(ctx, q)
 }
 
@@ -940,6 +1002,10 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.ID, "scenes.id", nil))
 	query.handleCriterion(ctx, pathCriterionHandler(sceneFilter.Path, "folders.path", "files.basename", qb.addFoldersTable))
+	query.handleCriterion(ctx, libraryCriterionHandler(sceneFilter.Library, qb.libraries, "scenes.id", `SELECT scenes_files.scene_id FROM scenes_files
+INNER JOIN files ON files.id = scenes_files.file_id
+INNER JOIN folders ON folders.id = files.parent_folder_id
+WHERE %s`))
 	query.handleCriterion(ctx, sceneFileCountCriterionHandler(qb, sceneFilter.FileCount))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Title, "scenes.title"))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Code, "scenes.code"))
@@ -978,6 +1044,9 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.Rating100, "scenes.rating", nil))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.OCounter, "scenes.o_counter", nil))
 	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Organized, "scenes.organized", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Favorite, "scenes.favorite", nil))
+	query.handleCriterion(ctx, sceneQuarantinedCriterionHandler(sceneFilter.Quarantined))
+	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.ContentRating, "scenes.content_rating", nil))
 
 	query.handleCriterion(ctx, floatIntCriterionHandler(sceneFilter.Duration, "video_files.duration", qb.addVideoFilesTable))
 	query.handleCriterion(ctx, resolutionCriterionHandler(sceneFilter.Resolution, "video_files.height", "video_files.width", qb.addVideoFilesTable))
@@ -1004,12 +1073,17 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 
 	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Interactive, "video_files.interactive", qb.addVideoFilesTable))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.InteractiveSpeed, "video_files.interactive_speed", qb.addVideoFilesTable))
+	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.InteractiveAverageSpeed, "video_files.interactive_average_speed", qb.addVideoFilesTable))
+	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.InteractiveMaxSpeed, "video_files.interactive_max_speed", qb.addVideoFilesTable))
+	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.InteractiveActionDensity, "video_files.interactive_action_density", qb.addVideoFilesTable))
 
 	query.handleCriterion(ctx, sceneCaptionCriterionHandler(qb, sceneFilter.Captions))
 
 	query.handleCriterion(ctx, floatIntCriterionHandler(sceneFilter.ResumeTime, "scenes.resume_time", nil))
 	query.handleCriterion(ctx, floatIntCriterionHandler(sceneFilter.PlayDuration, "scenes.play_duration", nil))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.PlayCount, "scenes.play_count", nil))
+	query.handleCriterion(ctx, scenesColorsTableMgr.dominantCriterionHandler(sceneTable, hueColumn, sceneFilter.DominantHue))
+	query.handleCriterion(ctx, scenesColorsTableMgr.dominantCriterionHandler(sceneTable, brightnessColumn, sceneFilter.DominantBrightness))
 
 	query.handleCriterion(ctx, sceneTagsCriterionHandler(qb, sceneFilter.Tags))
 	query.handleCriterion(ctx, sceneTagCountCriterionHandler(qb, sceneFilter.TagCount))
@@ -1024,6 +1098,9 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, dateCriterionHandler(sceneFilter.Date, "scenes.date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneFilter.CreatedAt, "scenes.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneFilter.UpdatedAt, "scenes.updated_at"))
+	query.handleCriterion(ctx, sceneCustomFieldsTableMgr.criterionHandler("scenes.id", sceneFilter.CustomFields))
+	query.handleCriterion(ctx, scenesCriteriaRatingsTableMgr.criterionHandler(sceneTable, sceneFilter.CriteriaRatings))
+	query.handleCriterion(ctx, scenesCriteriaRatingsTableMgr.averageCriterionHandler(sceneTable, sceneFilter.CriteriaRatingAverage))
 
 	return query
 }
@@ -1087,6 +1164,9 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("scene_filter", sceneFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(sceneFilter); err != nil {
 		return nil, err
 	}
@@ -1096,6 +1176,15 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 		return nil, err
 	}
 
+	// quarantined scenes are only returned if requested
+	if sceneFilter.Quarantined == nil {
+		query.addWhere("scenes.quarantined_at IS NULL")
+	}
+
+	if err := restrictSceneQuery(ctx, &query, "scenes.id"); err != nil {
+		return nil, err
+	}
+
 	qb.setSceneSort(&query, findFilter)
 	query.sortAndPagination += getPagination(findFilter)
 
@@ -1192,6 +1281,20 @@ func (qb *SceneStore) QueryCount(ctx context.Context, sceneFilter *models.SceneF
 	return query.executeCount(ctx)
 }
 
+func sceneQuarantinedCriterionHandler(quarantined *bool) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if quarantined == nil {
+			return
+		}
+
+		if *quarantined {
+			f.addWhere("scenes.quarantined_at IS NOT NULL")
+		} else {
+			f.addWhere("scenes.quarantined_at IS NULL")
+		}
+	}
+}
+
 func sceneFileCountCriterionHandler(qb *SceneStore, fileCount *models.IntCriterionInput) criterionHandlerFunc {
 	h := countCriterionHandlerBuilder{
 		primaryTable: sceneTable,
@@ -1449,12 +1552,20 @@ func scenePerformerAgeCriterionHandler(performerAge *models.IntCriterionInput) c
 	}
 }
 
-func sceneMoviesCriterionHandler(qb *SceneStore, movies *models.MultiCriterionInput) criterionHandlerFunc {
-	addJoinsFunc := func(f *filterBuilder) {
-		qb.moviesRepository().join(f, "", "scenes.id")
-		f.addLeftJoin("movies", "", "movies_scenes.movie_id = movies.id")
+func sceneMoviesCriterionHandler(qb *SceneStore, movies *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
+	h := joinedHierarchicalMultiCriterionHandlerBuilder{
+		tx: qb.tx,
+
+		primaryTable: sceneTable,
+		foreignTable: movieTable,
+		foreignFK:    movieIDColumn,
+		parentFK:     movieParentIDColumn,
+
+		joinAs:    "scene_movie",
+		joinTable: moviesScenesTable,
+		primaryFK: sceneIDColumn,
 	}
-	h := qb.getMultiCriterionHandlerBuilder(movieTable, moviesScenesTable, "movie_id", addJoinsFunc)
+
 	return h.handler(movies)
 }
 
@@ -1590,7 +1701,7 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 	case "duration":
 		addVideoFileTable()
 		query.sortAndPagination += getSort(sort, direction, videoFileTable)
-	case "interactive", "interactive_speed":
+	case "interactive", "interactive_speed", "interactive_average_speed", "interactive_max_speed", "interactive_action_density":
 		addVideoFileTable()
 		query.sortAndPagination += getSort(sort, direction, videoFileTable)
 	case "title":
@@ -1600,7 +1711,16 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 	case "play_count":
 		// handle here since getSort has special handling for _count suffix
 		query.sortAndPagination += " ORDER BY scenes.play_count " + direction
+	case "dominant_hue":
+		query.sortAndPagination += scenesColorsTableMgr.dominantSort(sceneTable, hueColumn, direction)
+	case "dominant_brightness":
+		query.sortAndPagination += scenesColorsTableMgr.dominantSort(sceneTable, brightnessColumn, direction)
 	default:
+		if criteriaSort, ok := scenesCriteriaRatingsTableMgr.sort(sceneTable, sort, direction); ok {
+			query.sortAndPagination += criteriaSort
+			break
+		}
+
 		query.sortAndPagination += getSort(sort, direction, "scenes")
 	}
 
@@ -1668,6 +1788,30 @@ func (qb *SceneStore) GetURLs(ctx context.Context, sceneID int) ([]string, error
 	return scenesURLsTableMgr.get(ctx, sceneID)
 }
 
+func (qb *SceneStore) GetColorPalette(ctx context.Context, sceneID int) ([]models.Color, error) {
+	return scenesColorsTableMgr.get(ctx, sceneID)
+}
+
+func (qb *SceneStore) UpdateColorPalette(ctx context.Context, sceneID int, palette []models.Color) error {
+	if err := qb.tableMgr.checkIDExists(ctx, sceneID); err != nil {
+		return err
+	}
+
+	return scenesColorsTableMgr.replace(ctx, sceneID, palette)
+}
+
+// FindSimilarByColor returns up to limit scenes with a dominant color closest
+// to that of the scene with the given id. Scenes that are not accessible in
+// ctx are excluded.
+func (qb *SceneStore) FindSimilarByColor(ctx context.Context, sceneID int, limit int) ([]*models.Scene, error) {
+	ids, err := scenesColorsTableMgr.findSimilar(ctx, sceneID, limit, restrictedSceneIDs(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.FindMany(ctx, ids)
+}
+
 func (qb *SceneStore) GetCover(ctx context.Context, sceneID int) ([]byte, error) {
 	return qb.GetImage(ctx, sceneID, sceneCoverBlobColumn)
 }
@@ -1700,6 +1844,172 @@ func (qb *SceneStore) AssignFiles(ctx context.Context, sceneID int, fileIDs []mo
 	return scenesFilesTableMgr.insertJoins(ctx, sceneID, firstPrimary, fileIDs)
 }
 
+// GetPartFileIDs returns the IDs of the files of a multi-part scene, in part
+// order. Returns an empty slice if the scene does not have parts.
+func (qb *SceneStore) GetPartFileIDs(ctx context.Context, id int) ([]models.FileID, error) {
+	table := scenesFilesJoinTable
+	q := dialect.From(table).Select(table.Col(fileIDColumn)).Where(
+		table.Col(sceneIDColumn).Eq(id),
+		table.Col("part").IsNotNull(),
+	).Order(table.Col("part").Asc())
+
+	var ret []models.FileID
+	if err := queryFunc(ctx, q, false, func(rows *sqlx.Rows) error {
+		var fileID models.FileID
+		if err := rows.Scan(&fileID); err != nil {
+			return err
+		}
+
+		ret = append(ret, fileID)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting part files for scene %d: %w", id, err)
+	}
+
+	return ret, nil
+}
+
+// SetFileParts sets the files of the scene to be its parts, in order, and
+// makes the first part the primary file. Other files of the scene are not
+// parts. The files must already be assigned to the scene.
+func (qb *SceneStore) SetFileParts(ctx context.Context, sceneID int, fileIDs []models.FileID) error {
+	table := scenesFilesJoinTable
+
+	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+		"part": nil,
+	}).Where(table.Col(sceneIDColumn).Eq(sceneID))
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("clearing parts of scene %d: %w", sceneID, err)
+	}
+
+	for i, fileID := range fileIDs {
+		q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+			"part": i + 1,
+		}).Where(table.Col(sceneIDColumn).Eq(sceneID), table.Col(fileIDColumn).Eq(fileID))
+
+		res, err := exec(ctx, q)
+		if err != nil {
+			return fmt.Errorf("setting part of file %d: %w", fileID, err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("file %d is not a file of scene %d", fileID, sceneID)
+		}
+	}
+
+	if len(fileIDs) > 0 {
+		return scenesFilesTableMgr.setPrimary(ctx, sceneID, fileIDs[0])
+	}
+
+	return nil
+}
+
+// GetPreviewOptions returns the preview generation options of the scene.
+// Returns nil if the scene uses the default options.
+func (qb *SceneStore) GetPreviewOptions(ctx context.Context, id int) (*models.ScenePreviewOptions, error) {
+	table := scenePreviewOptionsJoinTable
+	q := dialect.From(table).Select(
+		table.Col("segments"), table.Col("segment_duration"), table.Col("exclude_start"), table.Col("exclude_end"),
+	).Where(table.Col(sceneIDColumn).Eq(id))
+
+	var ret *models.ScenePreviewOptions
+	if err := queryFunc(ctx, q, true, func(rows *sqlx.Rows) error {
+		var (
+			segments        null.Int
+			segmentDuration null.Float
+			excludeStart    null.String
+			excludeEnd      null.String
+		)
+		if err := rows.Scan(&segments, &segmentDuration, &excludeStart, &excludeEnd); err != nil {
+			return err
+		}
+
+		ret = &models.ScenePreviewOptions{
+			Segments:        nullIntPtr(segments),
+			SegmentDuration: nullFloatPtr(segmentDuration),
+			ExcludeStart:    excludeStart.Ptr(),
+			ExcludeEnd:      excludeEnd.Ptr(),
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting preview options for scene %d: %w", id, err)
+	}
+
+	return ret, nil
+}
+
+// SetPreviewOptions replaces the preview generation options of the scene.
+// The options are removed if none are set.
+func (qb *SceneStore) SetPreviewOptions(ctx context.Context, id int, options models.ScenePreviewOptions) error {
+	table := scenePreviewOptionsJoinTable
+
+	if options.IsEmpty() {
+		q := dialect.Delete(table).Where(table.Col(sceneIDColumn).Eq(id))
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("removing preview options for scene %d: %w", id, err)
+		}
+		return nil
+	}
+
+	record := goqu.Record{
+		"segments":         intFromPtr(options.Segments),
+		"segment_duration": null.FloatFromPtr(options.SegmentDuration),
+		"exclude_start":    null.StringFromPtr(options.ExcludeStart),
+		"exclude_end":      null.StringFromPtr(options.ExcludeEnd),
+	}
+
+	insert := goqu.Record{sceneIDColumn: id}
+	for k, v := range record {
+		insert[k] = v
+	}
+
+	q := dialect.Insert(table).Rows(insert).OnConflict(goqu.DoUpdate(sceneIDColumn, record))
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("setting preview options for scene %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetGenerateState returns the state hash recorded when the scene was last
+// fully generated with the options identified by optionsHash. Returns an
+// empty string if no state was recorded.
+func (qb *SceneStore) GetGenerateState(ctx context.Context, id int, optionsHash string) (string, error) {
+	table := sceneGenerateStatesJoinTable
+	q := dialect.From(table).Select(table.Col("state_hash")).Where(
+		table.Col(sceneIDColumn).Eq(id),
+		table.Col("options_hash").Eq(optionsHash),
+	)
+
+	var ret string
+	if err := querySimple(ctx, q, &ret); err != nil {
+		return "", fmt.Errorf("getting generate state for scene %d: %w", id, err)
+	}
+
+	return ret, nil
+}
+
+// SetGenerateState records the state hash of the scene for the options
+// identified by optionsHash, replacing any existing state.
+func (qb *SceneStore) SetGenerateState(ctx context.Context, id int, optionsHash string, stateHash string) error {
+	table := sceneGenerateStatesJoinTable
+
+	q := dialect.Insert(table).Rows(goqu.Record{
+		sceneIDColumn:  id,
+		"options_hash": optionsHash,
+		"state_hash":   stateHash,
+	}).OnConflict(goqu.DoUpdate(
+		fmt.Sprintf("%s, %s", sceneIDColumn, "options_hash"),
+		goqu.Record{"state_hash": stateHash},
+	))
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("setting generate state for scene %d: %w", id, err)
+	}
+
+	return nil
+}
+
 func (qb *SceneStore) moviesRepository() *repository {
 	return &repository{
 		tx:        qb.tx,
@@ -1733,6 +2043,8 @@ func (qb *SceneStore) filesRepository() *filesRepository {
 			tableName: scenesFilesTable,
 			idColumn:  sceneIDColumn,
 		},
+		// parts of a multi-part scene are returned in order
+		orderBy: "`part` IS NULL, `part`",
 	}
 }
 
@@ -1785,7 +2097,12 @@ func (qb *SceneStore) galleriesRepository() *joinRepository {
 }
 
 func (qb *SceneStore) GetGalleryIDs(ctx context.Context, id int) ([]int, error) {
-	return qb.galleriesRepository().getIDs(ctx, id)
+	ids, err := qb.galleriesRepository().getIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return accessibleIDs(ctx, ids, galleryTableMgr.table.Col(idColumn), restrictedGalleryIDs(ctx))
 }
 
 func (qb *SceneStore) AddGalleryIDs(ctx context.Context, sceneID int, galleryIDs []int) error {
@@ -1850,7 +2167,9 @@ func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, duration
 
 	var duplicates [][]*models.Scene
 	for _, sceneIds := range dupeIds {
-		if scenes, err := qb.FindMany(ctx, sceneIds); err == nil {
+		// inaccessible scenes are excluded from the group
+		q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(sceneIds))
+		if scenes, err := qb.getMany(ctx, q); err == nil && len(scenes) > 1 {
 			duplicates = append(duplicates, scenes)
 		}
 	}
@@ -1878,3 +2197,43 @@ func getFirstPath(scenes []*models.Scene) string {
 	}
 	return firstPath
 }
+
+func (qb *SceneStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return sceneCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *SceneStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return sceneCustomFieldsTableMgr.set(ctx, id, fields)
+}
+
+func (qb *SceneStore) GetCriteriaRatings(ctx context.Context, id int) ([]models.CriterionRating, error) {
+	return scenesCriteriaRatingsTableMgr.get(ctx, id)
+}
+
+func (qb *SceneStore) SetCriteriaRatings(ctx context.Context, id int, input models.CriteriaRatingsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return scenesCriteriaRatingsTableMgr.set(ctx, id, input)
+}
+
+func (qb *SceneStore) GetODates(ctx context.Context, id int) ([]time.Time, error) {
+	return scenesODatesTableMgr.get(ctx, id)
+}
+
+func (qb *SceneStore) ReplaceODates(ctx context.Context, id int, dates []time.Time) error {
+	return scenesODatesTableMgr.replaceDates(ctx, id, dates)
+}
+
+func (qb *SceneStore) GetRatingHistory(ctx context.Context, id int) ([]models.RatingHistoryEntry, error) {
+	return scenesRatingHistoryTableMgr.get(ctx, id)
+}
+
+func (qb *SceneStore) ReplaceRatingHistory(ctx context.Context, id int, history []models.RatingHistoryEntry) error {
+	return scenesRatingHistoryTableMgr.replaceEntries(ctx, id, history)
+}
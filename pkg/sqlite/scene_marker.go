@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/guregu/null.v4/zero"
 
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
@@ -24,23 +27,35 @@ GROUP BY scene_markers.id
 `
 
 type sceneMarkerRow struct {
-	ID           int       `db:"id" goqu:"skipinsert"`
-	Title        string    `db:"title"` // TODO: make db schema (and gql schema) nullable
-	Seconds      float64   `db:"seconds"`
-	PrimaryTagID int       `db:"primary_tag_id"`
-	SceneID      int       `db:"scene_id"`
-	CreatedAt    Timestamp `db:"created_at"`
-	UpdatedAt    Timestamp `db:"updated_at"`
+	ID           int        `db:"id" goqu:"skipinsert"`
+	Title        string     `db:"title"` // TODO: make db schema (and gql schema) nullable
+	Seconds      float64    `db:"seconds"`
+	EndSeconds   null.Float `db:"end_seconds"`
+	PrimaryTagID int        `db:"primary_tag_id"`
+	SceneID      int        `db:"scene_id"`
+	CreatedAt    Timestamp  `db:"created_at"`
+	UpdatedAt    Timestamp  `db:"updated_at"`
+
+	PreviewFormat   zero.String `db:"preview_format"`
+	PreviewDuration null.Float  `db:"preview_duration"`
+	PreviewWidth    null.Int    `db:"preview_width"`
 }
 
 func (r *sceneMarkerRow) fromSceneMarker(o models.SceneMarker) {
 	r.ID = o.ID
 	r.Title = o.Title
 	r.Seconds = o.Seconds
+	r.EndSeconds = null.FloatFromPtr(o.EndSeconds)
 	r.PrimaryTagID = o.PrimaryTagID
 	r.SceneID = o.SceneID
 	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+
+	if o.PreviewFormat != nil && o.PreviewFormat.IsValid() {
+		r.PreviewFormat = zero.StringFrom(o.PreviewFormat.String())
+	}
+	r.PreviewDuration = null.FloatFromPtr(o.PreviewDuration)
+	r.PreviewWidth = intFromPtr(o.PreviewWidth)
 }
 
 func (r *sceneMarkerRow) resolve() *models.SceneMarker {
@@ -48,10 +63,19 @@ func (r *sceneMarkerRow) resolve() *models.SceneMarker {
 		ID:           r.ID,
 		Title:        r.Title,
 		Seconds:      r.Seconds,
+		EndSeconds:   nullFloatPtr(r.EndSeconds),
 		PrimaryTagID: r.PrimaryTagID,
 		SceneID:      r.SceneID,
 		CreatedAt:    r.CreatedAt.Timestamp,
 		UpdatedAt:    r.UpdatedAt.Timestamp,
+
+		PreviewDuration: nullFloatPtr(r.PreviewDuration),
+		PreviewWidth:    nullIntPtr(r.PreviewWidth),
+	}
+
+	if r.PreviewFormat.ValueOrZero() != "" {
+		v := models.MarkerPreviewFormat(r.PreviewFormat.String)
+		ret.PreviewFormat = &v
 	}
 
 	return ret
@@ -69,10 +93,14 @@ func (r *sceneMarkerRowRecord) fromPartial(o models.SceneMarkerPartial) {
 		r.set("title", o.Title.Value)
 	}
 	r.setFloat64("seconds", o.Seconds)
+	r.setNullFloat64("end_seconds", o.EndSeconds)
 	r.setInt("primary_tag_id", o.PrimaryTagID)
 	r.setInt("scene_id", o.SceneID)
 	r.setTimestamp("created_at", o.CreatedAt)
 	r.setTimestamp("updated_at", o.UpdatedAt)
+	r.setNullString("preview_format", o.PreviewFormat)
+	r.setNullFloat64("preview_duration", o.PreviewDuration)
+	r.setNullInt("preview_width", o.PreviewWidth)
 }
 
 type SceneMarkerStore struct {
@@ -211,6 +239,8 @@ func (qb *SceneMarkerStore) get(ctx context.Context, q *goqu.SelectDataset) (*mo
 }
 
 func (qb *SceneMarkerStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.SceneMarker, error) {
+	q = restrictScenes(ctx, q, qb.table().Col(sceneIDColumn))
+
 	const single = false
 	var ret []*models.SceneMarker
 	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
@@ -231,14 +261,9 @@ func (qb *SceneMarkerStore) getMany(ctx context.Context, q *goqu.SelectDataset)
 }
 
 func (qb *SceneMarkerStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneMarker, error) {
-	query := `
-		SELECT scene_markers.* FROM scene_markers
-		WHERE scene_markers.scene_id = ?
-		GROUP BY scene_markers.id
-		ORDER BY scene_markers.seconds ASC
-	`
-	args := []interface{}{sceneID}
-	return qb.querySceneMarkers(ctx, query, args)
+	table := qb.table()
+	q := qb.selectDataset().Prepared(true).Where(table.Col(sceneIDColumn).Eq(sceneID)).Order(table.Col("seconds").Asc())
+	return qb.getMany(ctx, q)
 }
 
 func (qb *SceneMarkerStore) CountByTagID(ctx context.Context, tagID int) (int, error) {
@@ -247,9 +272,21 @@ func (qb *SceneMarkerStore) CountByTagID(ctx context.Context, tagID int) (int, e
 }
 
 func (qb *SceneMarkerStore) GetMarkerStrings(ctx context.Context, q *string, sort *string) ([]*models.MarkerStringsResultType, error) {
-	query := "SELECT count(*) as `count`, scene_markers.id as id, scene_markers.title as title FROM scene_markers"
+	var where []string
 	if q != nil {
-		query += " WHERE title LIKE '%" + *q + "%'"
+		where = append(where, "title LIKE '%"+*q+"%'")
+	}
+	restriction, err := restrictedClause("scene_markers.scene_id", restrictedSceneIDs(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if restriction != "" {
+		where = append(where, restriction)
+	}
+
+	query := "SELECT count(*) as `count`, scene_markers.id as id, scene_markers.title as title FROM scene_markers"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
 	}
 	query += " GROUP BY title"
 	if sort != nil && *sort == "count" {
@@ -279,6 +316,7 @@ func (qb *SceneMarkerStore) makeFilter(ctx context.Context, sceneMarkerFilter *m
 	query.handleCriterion(ctx, sceneMarkerTagsCriterionHandler(qb, sceneMarkerFilter.Tags))
 	query.handleCriterion(ctx, sceneMarkerSceneTagsCriterionHandler(qb, sceneMarkerFilter.SceneTags))
 	query.handleCriterion(ctx, sceneMarkerPerformersCriterionHandler(qb, sceneMarkerFilter.Performers))
+	query.handleCriterion(ctx, floatCriterionHandler(sceneMarkerFilter.Duration, "(scene_markers.end_seconds - scene_markers.seconds)", nil))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneMarkerFilter.CreatedAt, "scene_markers.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneMarkerFilter.UpdatedAt, "scene_markers.updated_at"))
 	query.handleCriterion(ctx, dateCriterionHandler(sceneMarkerFilter.SceneDate, "scenes.date"))
@@ -303,12 +341,19 @@ func (qb *SceneMarkerStore) makeQuery(ctx context.Context, sceneMarkerFilter *mo
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("scene_marker_filter", sceneMarkerFilter); err != nil {
+		return nil, err
+	}
 	filter := qb.makeFilter(ctx, sceneMarkerFilter)
 
 	if err := query.addFilter(filter); err != nil {
 		return nil, err
 	}
 
+	if err := restrictSceneQuery(ctx, &query, "scene_markers.scene_id"); err != nil {
+		return nil, err
+	}
+
 	query.sortAndPagination = qb.getSceneMarkerSort(&query, findFilter) + getPagination(findFilter)
 
 	return &query, nil
@@ -483,27 +528,10 @@ func (qb *SceneMarkerStore) getSceneMarkerSort(query *queryBuilder, findFilter *
 	}
 
 	additional := ", scene_markers.scene_id ASC, scene_markers.seconds ASC"
-	return getSort(sort, direction, tableName) + additional
-}
-
-func (qb *SceneMarkerStore) querySceneMarkers(ctx context.Context, query string, args []interface{}) ([]*models.SceneMarker, error) {
-	const single = false
-	var ret []*models.SceneMarker
-	if err := qb.queryFunc(ctx, query, args, single, func(r *sqlx.Rows) error {
-		var f sceneMarkerRow
-		if err := r.StructScan(&f); err != nil {
-			return err
-		}
-
-		s := f.resolve()
-
-		ret = append(ret, s)
-		return nil
-	}); err != nil {
-		return nil, err
+	if sort == "duration" {
+		return " ORDER BY (scene_markers.end_seconds - scene_markers.seconds) " + getSortDirection(direction) + additional
 	}
-
-	return ret, nil
+	return getSort(sort, direction, tableName) + additional
 }
 
 func (qb *SceneMarkerStore) queryMarkerStringsResultType(ctx context.Context, query string, args []interface{}) ([]*models.MarkerStringsResultType, error) {
@@ -551,6 +579,7 @@ func (qb *SceneMarkerStore) UpdateTags(ctx context.Context, id int, tagIDs []int
 
 func (qb *SceneMarkerStore) Count(ctx context.Context) (int, error) {
 	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q = restrictScenes(ctx, q, qb.table().Col(sceneIDColumn))
 	return count(ctx, q)
 }
 
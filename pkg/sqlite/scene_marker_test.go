@@ -42,6 +42,119 @@ func TestMarkerFindBySceneID(t *testing.T) {
 	})
 }
 
+func TestMarkerPreviewOverrides(t *testing.T) {
+	runWithRollbackTxn(t, "preview overrides", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+		mqb := db.SceneMarker
+
+		format := models.MarkerPreviewFormatStatic
+		duration := 12.5
+		width := 320
+
+		marker := models.NewSceneMarker()
+		marker.Title = "preview overrides"
+		marker.SceneID = sceneIDs[sceneIdxWithMarkers]
+		marker.PrimaryTagID = tagIDs[tagIdxWithMarkers]
+		marker.PreviewFormat = &format
+		marker.PreviewDuration = &duration
+		marker.PreviewWidth = &width
+
+		if err := mqb.Create(ctx, &marker); err != nil {
+			t.Errorf("Error creating marker: %s", err.Error())
+			return
+		}
+
+		assert.Equal(&format, marker.PreviewFormat)
+		assert.Equal(&duration, marker.PreviewDuration)
+		assert.Equal(&width, marker.PreviewWidth)
+
+		partial := models.NewSceneMarkerPartial()
+		partial.PreviewFormat = models.NewOptionalStringPtr(nil)
+		partial.PreviewWidth = models.NewOptionalIntPtr(nil)
+
+		updated, err := mqb.UpdatePartial(ctx, marker.ID, partial)
+		if err != nil {
+			t.Errorf("Error updating marker: %s", err.Error())
+			return
+		}
+
+		assert.Nil(updated.PreviewFormat)
+		assert.Equal(&duration, updated.PreviewDuration)
+		assert.Nil(updated.PreviewWidth)
+	})
+}
+
+func TestMarkerEndSeconds(t *testing.T) {
+	runWithRollbackTxn(t, "end seconds", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+		mqb := db.SceneMarker
+
+		endSeconds := 25.0
+
+		marker := models.NewSceneMarker()
+		marker.Title = "range"
+		marker.Seconds = 10
+		marker.EndSeconds = &endSeconds
+		marker.SceneID = sceneIDs[sceneIdxWithMarkers]
+		marker.PrimaryTagID = tagIDs[tagIdxWithMarkers]
+
+		if err := mqb.Create(ctx, &marker); err != nil {
+			t.Errorf("Error creating marker: %s", err.Error())
+			return
+		}
+
+		found, err := mqb.Find(ctx, marker.ID)
+		if err != nil {
+			t.Errorf("Error finding marker: %s", err.Error())
+			return
+		}
+
+		assert.Equal(&endSeconds, found.EndSeconds)
+
+		perPage := -1
+		queryDuration := func(modifier models.CriterionModifier, value float64) []int {
+			markers, _, err := mqb.Query(ctx, &models.SceneMarkerFilterType{
+				Duration: &models.FloatCriterionInput{
+					Value:    value,
+					Modifier: modifier,
+				},
+			}, &models.FindFilterType{
+				PerPage: &perPage,
+			})
+			if err != nil {
+				t.Errorf("Error querying markers: %s", err.Error())
+			}
+
+			var ids []int
+			for _, m := range markers {
+				ids = append(ids, m.ID)
+			}
+			return ids
+		}
+
+		assert.Contains(queryDuration(models.CriterionModifierEquals, 15), marker.ID)
+		assert.NotContains(queryDuration(models.CriterionModifierGreaterThan, 15), marker.ID)
+		assert.Contains(queryDuration(models.CriterionModifierNotNull, 0), marker.ID)
+
+		// markers without an end have no duration
+		nullIDs := queryDuration(models.CriterionModifierIsNull, 0)
+		assert.NotContains(nullIDs, marker.ID)
+		assert.Contains(nullIDs, markerIDs[markerIdxWithScene])
+
+		partial := models.NewSceneMarkerPartial()
+		partial.EndSeconds = models.NewOptionalFloat64Ptr(nil)
+
+		updated, err := mqb.UpdatePartial(ctx, marker.ID, partial)
+		if err != nil {
+			t.Errorf("Error updating marker: %s", err.Error())
+			return
+		}
+
+		assert.Nil(updated.EndSeconds)
+		assert.Nil(updated.Duration())
+	})
+}
+
 func TestMarkerCountByTagID(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		mqb := db.SceneMarker
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+const (
+	sceneRelationshipTable = "scene_relationships"
+
+	relatedSceneIDColumn = "related_scene_id"
+)
+
+type sceneRelationshipRow struct {
+	ID             int       `db:"id" goqu:"skipinsert"`
+	SceneID        int       `db:"scene_id"`
+	RelatedSceneID int       `db:"related_scene_id"`
+	Type           string    `db:"type"`
+	CreatedAt      Timestamp `db:"created_at"`
+	UpdatedAt      Timestamp `db:"updated_at"`
+}
+
+func (r *sceneRelationshipRow) fromSceneRelationship(o models.SceneRelationship) {
+	r.ID = o.ID
+	r.SceneID = o.SceneID
+	r.RelatedSceneID = o.RelatedSceneID
+	r.Type = o.Type.String()
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *sceneRelationshipRow) resolve() *models.SceneRelationship {
+	ret := &models.SceneRelationship{
+		ID:             r.ID,
+		SceneID:        r.SceneID,
+		RelatedSceneID: r.RelatedSceneID,
+		Type:           models.SceneRelationshipType(r.Type),
+		CreatedAt:      r.CreatedAt.Timestamp,
+		UpdatedAt:      r.UpdatedAt.Timestamp,
+	}
+
+	return ret
+}
+
+type sceneRelationshipRowRecord struct {
+	updateRecord
+}
+
+func (r *sceneRelationshipRowRecord) fromPartial(o models.SceneRelationshipPartial) {
+	r.setInt("scene_id", o.SceneID)
+	r.setInt("related_scene_id", o.RelatedSceneID)
+	r.setString("type", o.Type)
+	r.setTimestamp("created_at", o.CreatedAt)
+	r.setTimestamp("updated_at", o.UpdatedAt)
+}
+
+type SceneRelationshipStore struct {
+	repository
+
+	tableMgr *table
+}
+
+func NewSceneRelationshipStore() *SceneRelationshipStore {
+	return &SceneRelationshipStore{
+		repository: repository{
+			tableName: sceneRelationshipTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: sceneRelationshipTableMgr,
+	}
+}
+
+func (qb *SceneRelationshipStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *SceneRelationshipStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *SceneRelationshipStore) Create(ctx context.Context, newObject *models.SceneRelationship) error {
+	var r sceneRelationshipRow
+	r.fromSceneRelationship(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *SceneRelationshipStore) UpdatePartial(ctx context.Context, id int, partial models.SceneRelationshipPartial) (*models.SceneRelationship, error) {
+	r := sceneRelationshipRowRecord{
+		updateRecord{
+			Record: make(exp.Record),
+		},
+	}
+
+	r.fromPartial(partial)
+
+	if len(r.Record) > 0 {
+		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
+			return nil, err
+		}
+	}
+
+	return qb.find(ctx, id)
+}
+
+func (qb *SceneRelationshipStore) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *SceneRelationshipStore) Find(ctx context.Context, id int) (*models.SceneRelationship, error) {
+	ret, err := qb.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *SceneRelationshipStore) FindMany(ctx context.Context, ids []int) ([]*models.SceneRelationship, error) {
+	ret := make([]*models.SceneRelationship, len(ids))
+
+	table := qb.table()
+	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range unsorted {
+		i := sliceutil.Index(ids, s.ID)
+		ret[i] = s
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("scene relationship with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *SceneRelationshipStore) find(ctx context.Context, id int) (*models.SceneRelationship, error) {
+	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+
+	ret, err := qb.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *SceneRelationshipStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.SceneRelationship, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *SceneRelationshipStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.SceneRelationship, error) {
+	table := qb.table()
+	q = restrictScenes(ctx, q, table.Col(sceneIDColumn))
+	q = restrictScenes(ctx, q, table.Col(relatedSceneIDColumn))
+
+	const single = false
+	var ret []*models.SceneRelationship
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f sceneRelationshipRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		s := f.resolve()
+
+		ret = append(ret, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *SceneRelationshipStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneRelationship, error) {
+	table := qb.table()
+	q := qb.selectDataset().Where(
+		goqu.Or(
+			table.Col(sceneIDColumn).Eq(sceneID),
+			table.Col(relatedSceneIDColumn).Eq(sceneID),
+		),
+	).Order(table.Col(idColumn).Asc())
+
+	return qb.getMany(ctx, q)
+}
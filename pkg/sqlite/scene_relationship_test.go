@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneRelationshipStore(t *testing.T) {
+	runWithRollbackTxn(t, "crud", func(t *testing.T, ctx context.Context) {
+		qb := db.SceneRelationship
+
+		sceneID := sceneIDs[sceneIdxWithMovie]
+		relatedSceneID := sceneIDs[sceneIdxWithGallery]
+		otherSceneID := sceneIDs[sceneIdxWithTag]
+
+		remaster := models.NewSceneRelationship()
+		remaster.SceneID = sceneID
+		remaster.RelatedSceneID = relatedSceneID
+		remaster.Type = models.SceneRelationshipTypeRemaster
+		if err := qb.Create(ctx, &remaster); err != nil {
+			t.Errorf("Create() error = %v", err)
+			return
+		}
+
+		sequel := models.NewSceneRelationship()
+		sequel.SceneID = otherSceneID
+		sequel.RelatedSceneID = sceneID
+		sequel.Type = models.SceneRelationshipTypeSequel
+		if err := qb.Create(ctx, &sequel); err != nil {
+			t.Errorf("Create() error = %v", err)
+			return
+		}
+
+		// relationships are found from both ends
+		got, err := qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("FindBySceneID() error = %v", err)
+			return
+		}
+		assert.Len(t, got, 2)
+
+		got, err = qb.FindBySceneID(ctx, relatedSceneID)
+		if err != nil {
+			t.Errorf("FindBySceneID() error = %v", err)
+			return
+		}
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, remaster.ID, got[0].ID)
+			assert.Equal(t, sceneID, got[0].OtherSceneID(relatedSceneID))
+		}
+
+		partial := models.NewSceneRelationshipPartial()
+		partial.Type = models.NewOptionalString(models.SceneRelationshipTypeReEncode.String())
+		updated, err := qb.UpdatePartial(ctx, remaster.ID, partial)
+		if err != nil {
+			t.Errorf("UpdatePartial() error = %v", err)
+			return
+		}
+		assert.Equal(t, models.SceneRelationshipTypeReEncode, updated.Type)
+
+		if err := qb.Destroy(ctx, remaster.ID); err != nil {
+			t.Errorf("Destroy() error = %v", err)
+			return
+		}
+
+		found, err := qb.Find(ctx, remaster.ID)
+		if err != nil {
+			t.Errorf("Find() error = %v", err)
+			return
+		}
+		assert.Nil(t, found)
+	})
+
+	runWithRollbackTxn(t, "self", func(t *testing.T, ctx context.Context) {
+		sceneID := sceneIDs[sceneIdxWithMovie]
+
+		self := models.NewSceneRelationship()
+		self.SceneID = sceneID
+		self.RelatedSceneID = sceneID
+		self.Type = models.SceneRelationshipTypeOther
+		assert.Error(t, db.SceneRelationship.Create(ctx, &self))
+	})
+}
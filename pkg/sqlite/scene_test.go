@@ -2389,6 +2389,124 @@ func TestSceneQueryPath(t *testing.T) {
 	}
 }
 
+func TestSceneQueryLibrary(t *testing.T) {
+	const sceneIdx = 1
+	parentPath := folderPaths[folderIdxForObjectFiles]
+	sceneFolderPath := folderPaths[folderIdxWithSceneFiles]
+	otherPath := folderPaths[folderIdxWithImageFiles]
+	// matches the parent folder if the wildcard is not escaped
+	wildcardPath := parentPath[:len(parentPath)-1] + "_"
+
+	db.SetLibraryPaths(map[string][]string{
+		"scenes":   {sceneFolderPath},
+		"parent":   {otherPath, parentPath},
+		"other":    {otherPath},
+		"wildcard": {wildcardPath},
+	})
+	defer db.SetLibraryPaths(nil)
+
+	tests := []struct {
+		name        string
+		input       models.LibraryCriterionInput
+		mustInclude []int
+		mustExclude []int
+	}{
+		{
+			"includes folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"scenes"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			[]int{sceneIdx},
+			nil,
+		},
+		{
+			"includes parent folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"parent"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			[]int{sceneIdx},
+			nil,
+		},
+		{
+			"includes other folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"other"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			nil,
+			[]int{sceneIdx},
+		},
+		{
+			"includes escaped wildcard",
+			models.LibraryCriterionInput{
+				Value:    []string{"wildcard"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			nil,
+			[]int{sceneIdx},
+		},
+		{
+			"includes unknown library",
+			models.LibraryCriterionInput{
+				Value:    []string{"unknown"},
+				Modifier: models.CriterionModifierIncludes,
+			},
+			nil,
+			[]int{sceneIdx},
+		},
+		{
+			"excludes folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"scenes"},
+				Modifier: models.CriterionModifierExcludes,
+			},
+			nil,
+			[]int{sceneIdx},
+		},
+		{
+			"excludes other folder",
+			models.LibraryCriterionInput{
+				Value:    []string{"other"},
+				Modifier: models.CriterionModifierExcludes,
+			},
+			[]int{sceneIdx},
+			nil,
+		},
+	}
+
+	qb := db.Scene
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.Query(ctx, models.SceneQueryOptions{
+				SceneFilter: &models.SceneFilterType{
+					Library: &tt.input,
+				},
+			})
+
+			if err != nil {
+				t.Errorf("SceneStore.TestSceneQueryLibrary() error = %v", err)
+				return
+			}
+
+			mustInclude := indexesToIDs(sceneIDs, tt.mustInclude)
+			mustExclude := indexesToIDs(sceneIDs, tt.mustExclude)
+
+			missing := sliceutil.Exclude(mustInclude, got.IDs)
+			if len(missing) > 0 {
+				t.Errorf("SceneStore.TestSceneQueryLibrary() missing expected IDs: %v", missing)
+			}
+
+			notExcluded := sliceutil.Intersect(mustExclude, got.IDs)
+			if len(notExcluded) > 0 {
+				t.Errorf("SceneStore.TestSceneQueryLibrary() expected IDs to be excluded: %v", notExcluded)
+			}
+		})
+	}
+}
+
 func TestSceneQueryURL(t *testing.T) {
 	const sceneIdx = 1
 	sceneURL := getSceneStringValue(sceneIdx, urlField)
@@ -2757,6 +2875,91 @@ func TestSceneQueryOCounter(t *testing.T) {
 	verifyScenesOCounter(t, oCounterCriterion)
 }
 
+func TestSceneQueryFavorite(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := db.Scene
+		sceneID := sceneIDs[sceneIdxWithMovie]
+
+		partial := models.NewScenePartial()
+		partial.Favorite = models.NewOptionalBool(true)
+		if _, err := sqb.UpdatePartial(ctx, sceneID, partial); err != nil {
+			t.Errorf("UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		favorite := true
+		scenes := queryScene(ctx, t, sqb, &models.SceneFilterType{
+			Favorite: &favorite,
+		}, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, sceneID, scenes[0].ID)
+			assert.True(t, scenes[0].Favorite)
+		}
+
+		favorite = false
+		scenes = queryScene(ctx, t, sqb, &models.SceneFilterType{
+			Favorite: &favorite,
+		}, nil)
+		for _, s := range scenes {
+			assert.NotEqual(t, sceneID, s.ID)
+		}
+
+		sortBy := "favorite"
+		direction := models.SortDirectionEnumDesc
+		scenes = queryScene(ctx, t, sqb, nil, &models.FindFilterType{
+			Sort:      &sortBy,
+			Direction: &direction,
+		})
+		if assert.NotEmpty(t, scenes) {
+			assert.Equal(t, sceneID, scenes[0].ID)
+		}
+
+		return nil
+	})
+}
+
+func TestSceneQueryQuarantined(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := db.Scene
+		quarantinedAt := time.Now()
+		scene := &models.Scene{
+			Title:         "quarantined",
+			QuarantinedAt: &quarantinedAt,
+		}
+		if err := sqb.Create(ctx, scene, nil); err != nil {
+			t.Errorf("Create() error = %v", err)
+			return nil
+		}
+
+		sceneIDs := func(scenes []*models.Scene) []int {
+			var ret []int
+			for _, s := range scenes {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		// excluded by default
+		scenes := queryScene(ctx, t, sqb, nil, nil)
+		assert.NotEmpty(t, scenes)
+		assert.NotContains(t, sceneIDs(scenes), scene.ID)
+
+		quarantined := true
+		scenes = queryScene(ctx, t, sqb, &models.SceneFilterType{
+			Quarantined: &quarantined,
+		}, nil)
+		assert.Equal(t, []int{scene.ID}, sceneIDs(scenes))
+
+		quarantined = false
+		scenes = queryScene(ctx, t, sqb, &models.SceneFilterType{
+			Quarantined: &quarantined,
+		}, nil)
+		assert.NotContains(t, sceneIDs(scenes), scene.ID)
+
+		return nil
+	})
+}
+
 func verifyScenesOCounter(t *testing.T, oCounterCriterion models.IntCriterionInput) {
 	withTxn(func(ctx context.Context) error {
 		sqb := db.Scene
@@ -3899,7 +4102,7 @@ func TestSceneQueryStudioDepth(t *testing.T) {
 func TestSceneQueryMovies(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		sqb := db.Scene
-		movieCriterion := models.MultiCriterionInput{
+		movieCriterion := models.HierarchicalMultiCriterionInput{
 			Value: []string{
 				strconv.Itoa(movieIDs[movieIdxWithScene]),
 			},
@@ -3917,7 +4120,7 @@ func TestSceneQueryMovies(t *testing.T) {
 		// ensure id is correct
 		assert.Equal(t, sceneIDs[sceneIdxWithMovie], scenes[0].ID)
 
-		movieCriterion = models.MultiCriterionInput{
+		movieCriterion = models.HierarchicalMultiCriterionInput{
 			Value: []string{
 				strconv.Itoa(movieIDs[movieIdxWithScene]),
 			},
@@ -4345,6 +4548,94 @@ func TestSceneUpdateSceneCover(t *testing.T) {
 	}
 }
 
+func TestSceneColorPalette(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+
+		red := models.Color{R: 200, G: 10, B: 10}
+		orange := models.Color{R: 200, G: 100, B: 10}
+		blue := models.Color{R: 10, G: 10, B: 200}
+
+		redID := sceneIDs[sceneIdxWithMovie]
+		orangeID := sceneIDs[sceneIdxWithGallery]
+		blueID := sceneIDs[sceneIdxWithTag]
+
+		for id, palette := range map[int][]models.Color{
+			redID:    {red, blue},
+			orangeID: {orange},
+			blueID:   {blue, red},
+		} {
+			if err := qb.UpdateColorPalette(ctx, id, palette); err != nil {
+				t.Errorf("SceneStore.UpdateColorPalette() error = %v", err)
+				return nil
+			}
+		}
+
+		got, err := qb.GetColorPalette(ctx, redID)
+		if err != nil {
+			t.Errorf("SceneStore.GetColorPalette() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []models.Color{red, blue}, got)
+
+		similar, err := qb.FindSimilarByColor(ctx, redID, 2)
+		if err != nil {
+			t.Errorf("SceneStore.FindSimilarByColor() error = %v", err)
+			return nil
+		}
+		if assert.Len(t, similar, 2) {
+			assert.Equal(t, orangeID, similar[0].ID)
+			assert.Equal(t, blueID, similar[1].ID)
+		}
+
+		// restricted scenes are excluded before the limit is applied
+		restricted := models.NewScenePartial()
+		restricted.ContentRating = models.NewOptionalInt(2)
+		if _, err := qb.UpdatePartial(ctx, orangeID, restricted); err != nil {
+			t.Errorf("SceneStore.UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		similar, err = qb.FindSimilarByColor(models.WithMaxContentRating(ctx, 1), redID, 1)
+		if err != nil {
+			t.Errorf("SceneStore.FindSimilarByColor() error = %v", err)
+			return nil
+		}
+		if assert.Len(t, similar, 1) {
+			assert.Equal(t, blueID, similar[0].ID)
+		}
+
+		// blue hue is 240
+		scenes := queryScene(ctx, t, qb, &models.SceneFilterType{
+			DominantHue: &models.FloatCriterionInput{
+				Value:    200,
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, blueID, scenes[0].ID)
+		}
+
+		sort := "dominant_hue"
+		direction := models.SortDirectionEnumDesc
+		scenes = queryScene(ctx, t, qb, &models.SceneFilterType{
+			DominantBrightness: &models.FloatCriterionInput{
+				Value:    0.5,
+				Modifier: models.CriterionModifierGreaterThan,
+			},
+		}, &models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		if assert.Len(t, scenes, 3) {
+			assert.Equal(t, blueID, scenes[0].ID)
+			assert.Equal(t, redID, scenes[2].ID)
+		}
+
+		return nil
+	})
+}
+
 func TestSceneStashIDs(t *testing.T) {
 	if err := withTxn(func(ctx context.Context) error {
 		qb := db.Scene
@@ -4551,6 +4842,170 @@ func TestSceneStore_AssignFiles(t *testing.T) {
 	}
 }
 
+func TestSceneStore_SetFileParts(t *testing.T) {
+	qb := db.Scene
+
+	withRollbackTxn(func(ctx context.Context) error {
+		sceneID := sceneIDs[sceneIdx1WithPerformer]
+		firstID := sceneFileIDs[sceneIdx1WithPerformer]
+		secondID := sceneFileIDs[sceneIdx1WithStudio]
+
+		if err := qb.AssignFiles(ctx, sceneID, []models.FileID{secondID}); err != nil {
+			t.Errorf("SceneStore.AssignFiles() error = %v", err)
+			return nil
+		}
+
+		// make the assigned file the first part
+		parts := []models.FileID{secondID, firstID}
+		if err := qb.SetFileParts(ctx, sceneID, parts); err != nil {
+			t.Errorf("SceneStore.SetFileParts() error = %v", err)
+			return nil
+		}
+
+		got, err := qb.GetPartFileIDs(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetPartFileIDs() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, parts, got)
+
+		files, err := qb.GetFiles(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetFiles() error = %v", err)
+			return nil
+		}
+		if assert.Len(t, files, 2) {
+			assert.Equal(t, secondID, files[0].ID)
+			assert.Equal(t, firstID, files[1].ID)
+		}
+
+		s, err := qb.Find(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.Find() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, &secondID, s.PrimaryFileID)
+
+		// files not in the scene cannot be parts
+		if err := qb.SetFileParts(ctx, sceneID, []models.FileID{invalidFileID}); err == nil {
+			t.Errorf("SceneStore.SetFileParts() expected error for invalid file")
+		}
+
+		return nil
+	})
+}
+
+func TestSceneStore_SetPreviewOptions(t *testing.T) {
+	qb := db.Scene
+
+	withRollbackTxn(func(ctx context.Context) error {
+		sceneID := sceneIDs[sceneIdx1WithPerformer]
+
+		got, err := qb.GetPreviewOptions(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetPreviewOptions() error = %v", err)
+			return nil
+		}
+		assert.Nil(t, got)
+
+		segments := 5
+		excludeStart := "30s"
+		options := models.ScenePreviewOptions{
+			Segments:     &segments,
+			ExcludeStart: &excludeStart,
+		}
+		if err := qb.SetPreviewOptions(ctx, sceneID, options); err != nil {
+			t.Errorf("SceneStore.SetPreviewOptions() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetPreviewOptions(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetPreviewOptions() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, &options, got)
+
+		// setting again replaces the existing options
+		segmentDuration := 1.5
+		options = models.ScenePreviewOptions{
+			SegmentDuration: &segmentDuration,
+		}
+		if err := qb.SetPreviewOptions(ctx, sceneID, options); err != nil {
+			t.Errorf("SceneStore.SetPreviewOptions() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetPreviewOptions(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetPreviewOptions() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, &options, got)
+
+		// empty options are removed
+		if err := qb.SetPreviewOptions(ctx, sceneID, models.ScenePreviewOptions{}); err != nil {
+			t.Errorf("SceneStore.SetPreviewOptions() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetPreviewOptions(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetPreviewOptions() error = %v", err)
+			return nil
+		}
+		assert.Nil(t, got)
+
+		return nil
+	})
+}
+
+func TestSceneStore_SetGenerateState(t *testing.T) {
+	qb := db.Scene
+
+	withRollbackTxn(func(ctx context.Context) error {
+		sceneID := sceneIDs[sceneIdx1WithPerformer]
+
+		got, err := qb.GetGenerateState(ctx, sceneID, "options1")
+		if err != nil {
+			t.Errorf("SceneStore.GetGenerateState() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, "", got)
+
+		if err := qb.SetGenerateState(ctx, sceneID, "options1", "state1"); err != nil {
+			t.Errorf("SceneStore.SetGenerateState() error = %v", err)
+			return nil
+		}
+		if err := qb.SetGenerateState(ctx, sceneID, "options2", "state2"); err != nil {
+			t.Errorf("SceneStore.SetGenerateState() error = %v", err)
+			return nil
+		}
+
+		// setting again replaces the existing state for the options
+		if err := qb.SetGenerateState(ctx, sceneID, "options1", "state3"); err != nil {
+			t.Errorf("SceneStore.SetGenerateState() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetGenerateState(ctx, sceneID, "options1")
+		if err != nil {
+			t.Errorf("SceneStore.GetGenerateState() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, "state3", got)
+
+		got, err = qb.GetGenerateState(ctx, sceneID, "options2")
+		if err != nil {
+			t.Errorf("SceneStore.GetGenerateState() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, "state2", got)
+
+		return nil
+	})
+}
+
 func TestSceneStore_IncrementWatchCount(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -4700,5 +5155,137 @@ func TestSceneStore_SaveActivity(t *testing.T) {
 	}
 }
 
+func TestSceneHistory(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		id := sceneIDs[sceneIdxWithGallery]
+
+		assert := assert.New(t)
+
+		if _, err := qb.IncrementOCounter(ctx, id); err != nil {
+			t.Errorf("SceneStore.IncrementOCounter() error = %v", err)
+			return nil
+		}
+		if _, err := qb.IncrementOCounter(ctx, id); err != nil {
+			t.Errorf("SceneStore.IncrementOCounter() error = %v", err)
+			return nil
+		}
+		if _, err := qb.DecrementOCounter(ctx, id); err != nil {
+			t.Errorf("SceneStore.DecrementOCounter() error = %v", err)
+			return nil
+		}
+
+		oDates, err := qb.GetODates(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetODates() error = %v", err)
+			return nil
+		}
+		assert.Len(oDates, 1)
+
+		initialHistory, err := qb.GetRatingHistory(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetRatingHistory() error = %v", err)
+			return nil
+		}
+
+		// not a multiple of 20, so it differs from the fixture rating
+		rating := 55
+		for i := 0; i < 2; i++ {
+			if _, err := qb.UpdatePartial(ctx, id, models.ScenePartial{
+				Rating: models.NewOptionalInt(rating),
+			}); err != nil {
+				t.Errorf("SceneStore.UpdatePartial() error = %v", err)
+				return nil
+			}
+		}
+
+		// setting the same rating again should not add an entry
+		history, err := qb.GetRatingHistory(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetRatingHistory() error = %v", err)
+			return nil
+		}
+		if assert.Len(history, len(initialHistory)+1) {
+			assert.Equal(&rating, history[len(history)-1].Rating)
+		}
+
+		// full updates record the change as well
+		scene, err := qb.Find(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.Find() error = %v", err)
+			return nil
+		}
+		updatedRating := 35
+		scene.Rating = &updatedRating
+		if err := qb.Update(ctx, scene); err != nil {
+			t.Errorf("SceneStore.Update() error = %v", err)
+			return nil
+		}
+
+		history, err = qb.GetRatingHistory(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetRatingHistory() error = %v", err)
+			return nil
+		}
+		if assert.Len(history, len(initialHistory)+2) {
+			assert.Equal(&updatedRating, history[len(history)-1].Rating)
+		}
+
+		// the rating of a new scene is recorded on create
+		newScene := models.Scene{Rating: &rating}
+		if err := qb.Create(ctx, &newScene, nil); err != nil {
+			t.Errorf("SceneStore.Create() error = %v", err)
+			return nil
+		}
+
+		history, err = qb.GetRatingHistory(ctx, newScene.ID)
+		if err != nil {
+			t.Errorf("SceneStore.GetRatingHistory() error = %v", err)
+			return nil
+		}
+		if assert.Len(history, 1) {
+			assert.Equal(&rating, history[0].Rating)
+		}
+
+		date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		if err := qb.ReplaceODates(ctx, id, []time.Time{date}); err != nil {
+			t.Errorf("SceneStore.ReplaceODates() error = %v", err)
+			return nil
+		}
+		if err := qb.ReplaceRatingHistory(ctx, id, []models.RatingHistoryEntry{{Date: date}}); err != nil {
+			t.Errorf("SceneStore.ReplaceRatingHistory() error = %v", err)
+			return nil
+		}
+
+		oDates, err = qb.GetODates(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetODates() error = %v", err)
+			return nil
+		}
+		assert.Equal([]time.Time{date}, oDates)
+
+		history, err = qb.GetRatingHistory(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetRatingHistory() error = %v", err)
+			return nil
+		}
+		assert.Equal([]models.RatingHistoryEntry{{Date: date}}, history)
+
+		if _, err := qb.ResetOCounter(ctx, id); err != nil {
+			t.Errorf("SceneStore.ResetOCounter() error = %v", err)
+			return nil
+		}
+
+		oDates, err = qb.GetODates(ctx, id)
+		if err != nil {
+			t.Errorf("SceneStore.GetODates() error = %v", err)
+			return nil
+		}
+		assert.Len(oDates, 0)
+
+		return nil
+	})
+}
+
 // TODO Count
 // TODO SizeCount
@@ -0,0 +1,254 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+const (
+	selectionSetTable         = "selection_sets"
+	selectionSetsObjectsTable = "selection_sets_objects"
+	selectionSetIDColumn      = "selection_set_id"
+	selectionSetObjectColumn  = "object_id"
+)
+
+// selectionSetObjectTables are the tables of the objects of each selection
+// set mode.
+var selectionSetObjectTables = map[models.FilterMode]string{
+	models.FilterModeScenes:       sceneTable,
+	models.FilterModeImages:       imageTable,
+	models.FilterModeGalleries:    galleryTable,
+	models.FilterModePerformers:   performerTable,
+	models.FilterModeStudios:      studioTable,
+	models.FilterModeMovies:       movieTable,
+	models.FilterModeTags:         tagTable,
+	models.FilterModeSceneMarkers: sceneMarkerTable,
+}
+
+type selectionSetRow struct {
+	ID        int               `db:"id" goqu:"skipinsert"`
+	Name      string            `db:"name"`
+	Mode      models.FilterMode `db:"mode"`
+	CreatedAt Timestamp         `db:"created_at"`
+	UpdatedAt Timestamp         `db:"updated_at"`
+}
+
+func (r *selectionSetRow) fromSelectionSet(o models.SelectionSet) {
+	r.ID = o.ID
+	r.Name = o.Name
+	r.Mode = o.Mode
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *selectionSetRow) resolve() *models.SelectionSet {
+	return &models.SelectionSet{
+		ID:        r.ID,
+		Name:      r.Name,
+		Mode:      r.Mode,
+		CreatedAt: r.CreatedAt.Timestamp,
+		UpdatedAt: r.UpdatedAt.Timestamp,
+	}
+}
+
+type SelectionSetStore struct {
+	repository
+	tableMgr *table
+}
+
+func NewSelectionSetStore() *SelectionSetStore {
+	return &SelectionSetStore{
+		repository: repository{
+			tableName: selectionSetTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: selectionSetTableMgr,
+	}
+}
+
+func (qb *SelectionSetStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *SelectionSetStore) selectDataset() *goqu.SelectDataset {
+	return dialect.From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *SelectionSetStore) Create(ctx context.Context, newObject *models.SelectionSet) error {
+	var r selectionSetRow
+	r.fromSelectionSet(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *SelectionSetStore) Update(ctx context.Context, updatedObject *models.SelectionSet) error {
+	var r selectionSetRow
+	r.fromSelectionSet(*updatedObject)
+
+	if err := qb.tableMgr.updateByID(ctx, updatedObject.ID, r); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (qb *SelectionSetStore) UpdateObjectIDs(ctx context.Context, id int, objectIDs []int, mode models.RelationshipUpdateMode) error {
+	return selectionSetsObjectsTableMgr.modifyJoins(ctx, id, objectIDs, mode)
+}
+
+func (qb *SelectionSetStore) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *SelectionSetStore) Find(ctx context.Context, id int) (*models.SelectionSet, error) {
+	ret, err := qb.find(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return ret, err
+}
+
+func (qb *SelectionSetStore) FindMany(ctx context.Context, ids []int, ignoreNotFound bool) ([]*models.SelectionSet, error) {
+	ret := make([]*models.SelectionSet, len(ids))
+
+	table := qb.table()
+	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range unsorted {
+		i := sliceutil.Index(ids, s.ID)
+		ret[i] = s
+	}
+
+	if !ignoreNotFound {
+		for i := range ret {
+			if ret[i] == nil {
+				return nil, fmt.Errorf("selection set with id %d not found", ids[i])
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// returns nil, sql.ErrNoRows if not found
+func (qb *SelectionSetStore) find(ctx context.Context, id int) (*models.SelectionSet, error) {
+	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+
+	ret, err := qb.get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *SelectionSetStore) get(ctx context.Context, q *goqu.SelectDataset) (*models.SelectionSet, error) {
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return ret[0], nil
+}
+
+func (qb *SelectionSetStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.SelectionSet, error) {
+	const single = false
+	var ret []*models.SelectionSet
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f selectionSetRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		s := f.resolve()
+
+		ret = append(ret, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *SelectionSetStore) FindByMode(ctx context.Context, mode models.FilterMode) ([]*models.SelectionSet, error) {
+	// SELECT * FROM selection_sets WHERE mode = ? ORDER BY name ASC
+	table := qb.table()
+	sq := qb.selectDataset().Prepared(true).Where(
+		table.Col("mode").Eq(mode),
+	).Order(table.Col("name").Asc())
+
+	return qb.getMany(ctx, sq)
+}
+
+func (qb *SelectionSetStore) All(ctx context.Context) ([]*models.SelectionSet, error) {
+	return qb.getMany(ctx, qb.selectDataset().Order(qb.table().Col("name").Asc()))
+}
+
+// GetObjectIDs returns the IDs of the objects in the selection set. IDs of
+// objects that have since been destroyed are not returned.
+func (qb *SelectionSetStore) GetObjectIDs(ctx context.Context, id int) ([]int, error) {
+	s, err := qb.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, nil
+	}
+
+	objectTable, ok := selectionSetObjectTables[s.Mode]
+	if !ok {
+		return selectionSetsObjectsTableMgr.get(ctx, id)
+	}
+
+	t := selectionSetsObjectsTableMgr
+	existing := dialect.From(objectTable).Select(idColumn)
+	q := dialect.Select(t.fkColumn).From(t.table.table).Where(t.idColumn.Eq(id), t.fkColumn.In(existing))
+
+	const single = false
+	var ret []int
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var fk int
+		if err := rows.Scan(&fk); err != nil {
+			return err
+		}
+
+		ret = append(ret, fk)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting objects of selection set %d: %w", id, err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,127 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectionSetCRUD(t *testing.T) {
+	const setName = "selectionSet"
+
+	var id int
+
+	// create the selection set
+	if err := withTxn(func(ctx context.Context) error {
+		newSet := models.NewSelectionSet()
+		newSet.Name = setName
+		newSet.Mode = models.FilterModeScenes
+
+		if err := db.SelectionSet.Create(ctx, &newSet); err != nil {
+			return err
+		}
+
+		id = newSet.ID
+
+		return db.SelectionSet.UpdateObjectIDs(ctx, id, []int{sceneIDs[0], sceneIDs[1]}, models.RelationshipUpdateModeSet)
+	}); err != nil {
+		t.Errorf("Error creating selection set: %s", err.Error())
+		return
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.SelectionSet
+
+		found, err := qb.Find(ctx, id)
+		if err != nil {
+			t.Errorf("Error finding selection set: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, setName, found.Name)
+		assert.Equal(t, models.FilterModeScenes, found.Mode)
+
+		byMode, err := qb.FindByMode(ctx, models.FilterModeScenes)
+		if err != nil {
+			t.Errorf("Error finding selection sets by mode: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, byMode, 1)
+
+		byMode, err = qb.FindByMode(ctx, models.FilterModeImages)
+		if err != nil {
+			t.Errorf("Error finding selection sets by mode: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, byMode, 0)
+
+		// add and remove objects
+		if err := qb.UpdateObjectIDs(ctx, id, []int{sceneIDs[1], sceneIDs[2]}, models.RelationshipUpdateModeAdd); err != nil {
+			t.Errorf("Error adding selection set objects: %s", err.Error())
+			return nil
+		}
+		if err := qb.UpdateObjectIDs(ctx, id, []int{sceneIDs[0]}, models.RelationshipUpdateModeRemove); err != nil {
+			t.Errorf("Error removing selection set objects: %s", err.Error())
+			return nil
+		}
+
+		objectIDs, err := qb.GetObjectIDs(ctx, id)
+		if err != nil {
+			t.Errorf("Error getting selection set objects: %s", err.Error())
+			return nil
+		}
+
+		assert.ElementsMatch(t, []int{sceneIDs[1], sceneIDs[2]}, objectIDs)
+
+		// destroyed objects are not returned
+		if err := db.Scene.Destroy(ctx, sceneIDs[2]); err != nil {
+			t.Errorf("Error destroying scene: %s", err.Error())
+			return nil
+		}
+
+		objectIDs, err = qb.GetObjectIDs(ctx, id)
+		if err != nil {
+			t.Errorf("Error getting selection set objects: %s", err.Error())
+			return nil
+		}
+
+		assert.ElementsMatch(t, []int{sceneIDs[1]}, objectIDs)
+
+		return nil
+	})
+
+	// destroy the selection set
+	withTxn(func(ctx context.Context) error {
+		qb := db.SelectionSet
+
+		if err := qb.Destroy(ctx, id); err != nil {
+			t.Errorf("Error destroying selection set: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(ctx, id)
+		if err != nil {
+			t.Errorf("Error finding selection set: %s", err.Error())
+			return nil
+		}
+
+		assert.Nil(t, found)
+
+		objectIDs, err := qb.GetObjectIDs(ctx, id)
+		if err != nil {
+			t.Errorf("Error getting selection set objects: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, objectIDs, 0)
+
+		return nil
+	})
+}
@@ -18,13 +18,14 @@ import (
 )
 
 const (
-	studioTable           = "studios"
-	studioIDColumn        = "studio_id"
-	studioAliasesTable    = "studio_aliases"
-	studioAliasColumn     = "alias"
-	studioParentIDColumn  = "parent_id"
-	studioNameColumn      = "name"
-	studioImageBlobColumn = "image_blob"
+	studioTable             = "studios"
+	studioIDColumn          = "studio_id"
+	studioAliasesTable      = "studio_aliases"
+	studioAliasColumn       = "alias"
+	studioParentIDColumn    = "parent_id"
+	studioNameColumn        = "name"
+	studioImageBlobColumn   = "image_blob"
+	studioCustomFieldsTable = "studio_custom_fields"
 )
 
 type studioRow struct {
@@ -162,11 +163,20 @@ func (qb *StudioStore) UpdatePartial(ctx context.Context, input models.StudioPar
 	r.fromPartial(input)
 
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeStudio, input.ID, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, input.ID, r.Record); err != nil {
 			return nil, err
 		}
 	}
 
+	joins, err := newEditHistorySnapshot(ctx, models.EditObjectTypeStudio, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	if input.Aliases != nil {
 		if err := studio.EnsureAliasesUnique(ctx, input.ID, input.Aliases.Values, qb); err != nil {
 			return nil, err
@@ -183,6 +193,10 @@ func (qb *StudioStore) UpdatePartial(ctx context.Context, input models.StudioPar
 		}
 	}
 
+	if err := joins.record(ctx); err != nil {
+		return nil, err
+	}
+
 	return qb.Find(ctx, input.ID)
 }
 
@@ -219,6 +233,111 @@ func (qb *StudioStore) Destroy(ctx context.Context, id int) error {
 	return qb.destroyExisting(ctx, []int{id})
 }
 
+// Merge moves the scenes, images, galleries, audios, movies and child
+// studios of the source studios to the destination studio, and destroys the
+// source studios. The destination cannot be a descendant of a source. The
+// names and aliases of the sources become aliases of the destination. Stash
+// IDs are moved, and custom fields are moved where the destination does not
+// already have them.
+func (qb *StudioStore) Merge(ctx context.Context, source []int, destination int) error {
+	if len(source) == 0 {
+		return nil
+	}
+
+	inBinding := getInBinding(len(source))
+
+	args := []interface{}{destination}
+	srcArgs := make([]interface{}, len(source))
+	for i, id := range source {
+		if id == destination {
+			return errors.New("cannot merge where source == destination")
+		}
+		srcArgs[i] = id
+	}
+
+	args = append(args, srcArgs...)
+
+	// the children of the sources become children of the destination, so
+	// the destination cannot be a descendant of a source
+	if err := qb.validateMergeDestination(ctx, source, destination); err != nil {
+		return err
+	}
+
+	for _, table := range []string{sceneTable, imageTable, galleryTable, audioTable, movieTable} {
+		if _, err := qb.tx.Exec(ctx, "UPDATE "+table+" SET studio_id = ? WHERE studio_id IN "+inBinding, args...); err != nil {
+			return err
+		}
+	}
+
+	if _, err := qb.tx.Exec(ctx, "UPDATE "+studioTable+" SET parent_id = ? WHERE parent_id IN "+inBinding+" AND id != ?", append(args, destination)...); err != nil {
+		return err
+	}
+
+	// aliases are unique, so names that are already aliases are ignored
+	if _, err := qb.tx.Exec(ctx, "INSERT OR IGNORE INTO "+studioAliasesTable+" (studio_id, alias) SELECT ?, name FROM "+studioTable+" WHERE id IN "+inBinding, args...); err != nil {
+		return err
+	}
+
+	if _, err := qb.tx.Exec(ctx, "UPDATE OR IGNORE "+studioAliasesTable+" SET studio_id = ? WHERE studio_id IN "+inBinding, args...); err != nil {
+		return err
+	}
+
+	// the name of the destination cannot also be an alias
+	if _, err := qb.tx.Exec(ctx, "DELETE FROM "+studioAliasesTable+" WHERE studio_id = ? AND alias = (SELECT name FROM "+studioTable+" WHERE id = ?)", destination, destination); err != nil {
+		return err
+	}
+
+	if _, err := qb.tx.Exec(ctx, `UPDATE studio_stash_ids
+SET studio_id = ?
+WHERE studio_id IN `+inBinding+`
+AND NOT EXISTS(SELECT 1 FROM studio_stash_ids o WHERE o.endpoint = studio_stash_ids.endpoint AND o.stash_id = studio_stash_ids.stash_id AND o.studio_id = ?)`,
+		append(args, destination)...,
+	); err != nil {
+		return err
+	}
+
+	if _, err := qb.tx.Exec(ctx, "UPDATE OR IGNORE "+studioCustomFieldsTable+" SET studio_id = ? WHERE studio_id IN "+inBinding, args...); err != nil {
+		return err
+	}
+
+	for _, id := range source {
+		if err := qb.Destroy(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateMergeDestination returns an error if destination is a descendant
+// of any of the source studios.
+func (qb *StudioStore) validateMergeDestination(ctx context.Context, source []int, destination int) error {
+	isSource := make(map[int]bool, len(source))
+	for _, id := range source {
+		isSource[id] = true
+	}
+
+	seen := make(map[int]bool)
+	for id := destination; !seen[id]; {
+		seen[id] = true
+
+		s, err := qb.Find(ctx, id)
+		if err != nil {
+			return fmt.Errorf("finding studio %d: %w", id, err)
+		}
+		if s == nil || s.ParentID == nil {
+			return nil
+		}
+
+		id = *s.ParentID
+		if isSource[id] {
+			return fmt.Errorf("destination studio is a descendant of source studio %d: %w", id, models.ErrOwnAncestor)
+		}
+	}
+
+	return nil
+}
+
 // returns nil, nil if not found
 func (qb *StudioStore) Find(ctx context.Context, id int) (*models.Studio, error) {
 	ret, err := qb.find(ctx, id)
@@ -519,6 +638,7 @@ func (qb *StudioStore) makeFilter(ctx context.Context, studioFilter *models.Stud
 	query.handleCriterion(ctx, studioAliasCriterionHandler(qb, studioFilter.Aliases))
 	query.handleCriterion(ctx, timestampCriterionHandler(studioFilter.CreatedAt, studioTable+".created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(studioFilter.UpdatedAt, studioTable+".updated_at"))
+	query.handleCriterion(ctx, studioCustomFieldsTableMgr.criterionHandler(studioTable+".id", studioFilter.CustomFields))
 
 	return query
 }
@@ -540,6 +660,9 @@ func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.Studi
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("studio_filter", studioFilter); err != nil {
+		return nil, err
+	}
 	if err := qb.validateFilter(studioFilter); err != nil {
 		return nil, err
 	}
@@ -710,3 +833,15 @@ func (qb *StudioStore) GetStashIDs(ctx context.Context, studioID int) ([]models.
 func (qb *StudioStore) GetAliases(ctx context.Context, studioID int) ([]string, error) {
 	return studiosAliasesTableMgr.get(ctx, studioID)
 }
+
+func (qb *StudioStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return studioCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *StudioStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return studioCustomFieldsTableMgr.set(ctx, id, fields)
+}
@@ -1019,3 +1019,87 @@ func TestStudioQueryFast(t *testing.T) {
 // TODO All
 // TODO AllSlim
 // TODO Query
+
+func TestStudioMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	// merge tests - perform these in a transaction that we'll rollback
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Studio
+
+		// try merging into same studio
+		err := qb.Merge(ctx, []int{studioIDs[studioIdxWithScene]}, studioIDs[studioIdxWithScene])
+		assert.NotNil(err)
+
+		// try merging into a child or grandchild of a source
+		for _, srcIdx := range []int{studioIdxWithParentAndChild, studioIdxWithGrandChild} {
+			err := qb.Merge(ctx, []int{studioIDs[studioIdxWithTwoScenes], studioIDs[srcIdx]}, studioIDs[studioIdxWithGrandParent])
+			assert.ErrorIs(err, models.ErrOwnAncestor)
+		}
+
+		srcIDs := []int{
+			studioIDs[studioIdxWithTwoScenes],
+			studioIDs[studioIdxWithImage],
+			studioIDs[studioIdxWithChildStudio],
+		}
+		destID := studioIDs[studioIdxWithGrandParent]
+
+		sources, err := qb.FindMany(ctx, srcIDs)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.Merge(ctx, srcIDs, destID); err != nil {
+			return err
+		}
+
+		// ensure source studios are deleted
+		for _, id := range srcIDs {
+			s, err := qb.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+			assert.Nil(s)
+		}
+
+		// ensure the names of the sources are aliases of the destination
+		destAliases, err := qb.GetAliases(ctx, destID)
+		if err != nil {
+			return err
+		}
+		for _, s := range sources {
+			assert.Contains(destAliases, s.Name)
+		}
+
+		// ensure the parent of the destination is unchanged
+		dest, err := qb.Find(ctx, destID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(&studioIDs[studioIdxWithParentAndChild], dest.ParentID)
+
+		// ensure the children of the sources are children of the destination
+		child, err := qb.Find(ctx, studioIDs[studioIdxWithParentStudio])
+		if err != nil {
+			return err
+		}
+		assert.Equal(&destID, child.ParentID)
+
+		// ensure scene and image point to the destination
+		s, err := db.Scene.Find(ctx, sceneIDs[sceneIdx1WithStudio])
+		if err != nil {
+			return err
+		}
+		assert.Equal(&destID, s.StudioID)
+
+		i, err := db.Image.Find(ctx, imageIDs[imageIdxWithStudio])
+		if err != nil {
+			return err
+		}
+		assert.Equal(&destID, i.StudioID)
+
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
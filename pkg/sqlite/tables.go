@@ -14,6 +14,7 @@ var (
 	performersImagesJoinTable = goqu.T(performersImagesTable)
 	imagesFilesJoinTable      = goqu.T(imagesFilesTable)
 	imagesURLsJoinTable       = goqu.T(imagesURLsTable)
+	imagesColorsJoinTable     = goqu.T(imagesColorsTable)
 
 	galleriesFilesJoinTable      = goqu.T(galleriesFilesTable)
 	galleriesTagsJoinTable       = goqu.T(galleriesTagsTable)
@@ -27,13 +28,27 @@ var (
 	scenesStashIDsJoinTable   = goqu.T("scene_stash_ids")
 	scenesMoviesJoinTable     = goqu.T(moviesScenesTable)
 	scenesURLsJoinTable       = goqu.T(scenesURLsTable)
+	scenesColorsJoinTable     = goqu.T(scenesColorsTable)
 
-	performersAliasesJoinTable  = goqu.T(performersAliasesTable)
-	performersTagsJoinTable     = goqu.T(performersTagsTable)
-	performersStashIDsJoinTable = goqu.T("performer_stash_ids")
+	scenePreviewOptionsJoinTable = goqu.T(scenePreviewOptionsTable)
+	sceneGenerateStatesJoinTable = goqu.T(sceneGenerateStatesTable)
+
+	performersAliasesJoinTable   = goqu.T(performersAliasesTable)
+	performersTagsJoinTable      = goqu.T(performersTagsTable)
+	performersStashIDsJoinTable  = goqu.T("performer_stash_ids")
+	performersMergedIDsJoinTable = goqu.T(performersMergedIDsTable)
 
 	studiosAliasesJoinTable  = goqu.T(studioAliasesTable)
 	studiosStashIDsJoinTable = goqu.T("studio_stash_ids")
+
+	selectionSetsObjectsJoinTable = goqu.T(selectionSetsObjectsTable)
+
+	fileLinksJoinTable         = goqu.T(fileLinksTable)
+	fileVerificationsJoinTable = goqu.T(fileVerificationsTable)
+
+	audiosFilesJoinTable      = goqu.T(audiosFilesTable)
+	audiosTagsJoinTable       = goqu.T(audiosTagsTable)
+	audiosPerformersJoinTable = goqu.T(performersAudiosTable)
 )
 
 var (
@@ -42,6 +57,27 @@ var (
 		idColumn: goqu.T(imageTable).Col(idColumn),
 	}
 
+	imageCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(imageCustomFieldsTable),
+			idColumn: goqu.T(imageCustomFieldsTable).Col(imageIDColumn),
+		},
+	}
+
+	imagesODatesTableMgr = &oDatesTable{
+		table: table{
+			table:    goqu.T(imagesODatesTable),
+			idColumn: goqu.T(imagesODatesTable).Col(imageIDColumn),
+		},
+	}
+
+	imagesRatingHistoryTableMgr = &ratingHistoryTable{
+		table: table{
+			table:    goqu.T(imagesRatingHistoryTable),
+			idColumn: goqu.T(imagesRatingHistoryTable).Col(imageIDColumn),
+		},
+	}
+
 	imagesFilesTableMgr = &relatedFilesTable{
 		table: table{
 			table:    imagesFilesJoinTable,
@@ -80,6 +116,13 @@ var (
 		},
 		valueColumn: imagesURLsJoinTable.Col(imageURLColumn),
 	}
+
+	imagesColorsTableMgr = &colorPaletteTable{
+		table: table{
+			table:    imagesColorsJoinTable,
+			idColumn: imagesColorsJoinTable.Col(imageIDColumn),
+		},
+	}
 )
 
 var (
@@ -88,6 +131,13 @@ var (
 		idColumn: goqu.T(galleryTable).Col(idColumn),
 	}
 
+	galleryCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(galleryCustomFieldsTable),
+			idColumn: goqu.T(galleryCustomFieldsTable).Col(galleryIDColumn),
+		},
+	}
+
 	galleriesFilesTableMgr = &relatedFilesTable{
 		table: table{
 			table:    galleriesFilesJoinTable,
@@ -139,11 +189,37 @@ var (
 		idColumn: goqu.T(sceneTable).Col(idColumn),
 	}
 
+	sceneCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(sceneCustomFieldsTable),
+			idColumn: goqu.T(sceneCustomFieldsTable).Col(sceneIDColumn),
+		},
+	}
+
+	scenesODatesTableMgr = &oDatesTable{
+		table: table{
+			table:    goqu.T(scenesODatesTable),
+			idColumn: goqu.T(scenesODatesTable).Col(sceneIDColumn),
+		},
+	}
+
+	scenesRatingHistoryTableMgr = &ratingHistoryTable{
+		table: table{
+			table:    goqu.T(scenesRatingHistoryTable),
+			idColumn: goqu.T(scenesRatingHistoryTable).Col(sceneIDColumn),
+		},
+	}
+
 	sceneMarkerTableMgr = &table{
 		table:    goqu.T(sceneMarkerTable),
 		idColumn: goqu.T(sceneMarkerTable).Col(idColumn),
 	}
 
+	sceneRelationshipTableMgr = &table{
+		table:    goqu.T(sceneRelationshipTable),
+		idColumn: goqu.T(sceneRelationshipTable).Col(idColumn),
+	}
+
 	scenesFilesTableMgr = &relatedFilesTable{
 		table: table{
 			table:    scenesFilesJoinTable,
@@ -190,6 +266,20 @@ var (
 		},
 		valueColumn: scenesURLsJoinTable.Col(sceneURLColumn),
 	}
+
+	scenesColorsTableMgr = &colorPaletteTable{
+		table: table{
+			table:    scenesColorsJoinTable,
+			idColumn: scenesColorsJoinTable.Col(sceneIDColumn),
+		},
+	}
+
+	scenesCriteriaRatingsTableMgr = &criteriaRatingsTable{
+		table: table{
+			table:    goqu.T(scenesCriteriaRatingsTable),
+			idColumn: goqu.T(scenesCriteriaRatingsTable).Col(sceneIDColumn),
+		},
+	}
 )
 
 var (
@@ -208,6 +298,16 @@ var (
 		idColumn: goqu.T(imageFileTable).Col(fileIDColumn),
 	}
 
+	audioFileTableMgr = &table{
+		table:    goqu.T(audioFileTable),
+		idColumn: goqu.T(audioFileTable).Col(fileIDColumn),
+	}
+
+	documentFileTableMgr = &table{
+		table:    goqu.T(documentFileTable),
+		idColumn: goqu.T(documentFileTable).Col(fileIDColumn),
+	}
+
 	folderTableMgr = &table{
 		table:    goqu.T(folderTable),
 		idColumn: goqu.T(folderTable).Col(idColumn),
@@ -225,6 +325,13 @@ var (
 		idColumn: goqu.T(performerTable).Col(idColumn),
 	}
 
+	performerCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(performerCustomFieldsTable),
+			idColumn: goqu.T(performerCustomFieldsTable).Col(performerIDColumn),
+		},
+	}
+
 	performersAliasesTableMgr = &stringTable{
 		table: table{
 			table:    performersAliasesJoinTable,
@@ -247,6 +354,14 @@ var (
 			idColumn: performersStashIDsJoinTable.Col(performerIDColumn),
 		},
 	}
+
+	performersMergedIDsTableMgr = &joinTable{
+		table: table{
+			table:    performersMergedIDsJoinTable,
+			idColumn: performersMergedIDsJoinTable.Col(performerIDColumn),
+		},
+		fkColumn: performersMergedIDsJoinTable.Col(performerMergedIDColumn),
+	}
 )
 
 var (
@@ -255,6 +370,13 @@ var (
 		idColumn: goqu.T(studioTable).Col(idColumn),
 	}
 
+	studioCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(studioCustomFieldsTable),
+			idColumn: goqu.T(studioCustomFieldsTable).Col(studioIDColumn),
+		},
+	}
+
 	studiosAliasesTableMgr = &stringTable{
 		table: table{
 			table:    studiosAliasesJoinTable,
@@ -276,6 +398,13 @@ var (
 		table:    goqu.T(tagTable),
 		idColumn: goqu.T(tagTable).Col(idColumn),
 	}
+
+	tagCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(tagCustomFieldsTable),
+			idColumn: goqu.T(tagCustomFieldsTable).Col(tagIDColumn),
+		},
+	}
 )
 
 var (
@@ -283,6 +412,13 @@ var (
 		table:    goqu.T(movieTable),
 		idColumn: goqu.T(movieTable).Col(idColumn),
 	}
+
+	movieCustomFieldsTableMgr = &customFieldsTable{
+		table: table{
+			table:    goqu.T(movieCustomFieldsTable),
+			idColumn: goqu.T(movieCustomFieldsTable).Col(movieIDColumn),
+		},
+	}
 )
 
 var (
@@ -298,3 +434,62 @@ var (
 		idColumn: goqu.T(savedFilterTable).Col(idColumn),
 	}
 )
+
+var (
+	ratingCriteriaTableMgr = &table{
+		table:    goqu.T(ratingCriteriaTable),
+		idColumn: goqu.T(ratingCriteriaTable).Col(idColumn),
+	}
+)
+
+var (
+	editHistoryTableMgr = &table{
+		table:    goqu.T(editHistoryTable),
+		idColumn: goqu.T(editHistoryTable).Col(idColumn),
+	}
+)
+
+var (
+	selectionSetTableMgr = &table{
+		table:    goqu.T(selectionSetTable),
+		idColumn: goqu.T(selectionSetTable).Col(idColumn),
+	}
+
+	selectionSetsObjectsTableMgr = &joinTable{
+		table: table{
+			table:    selectionSetsObjectsJoinTable,
+			idColumn: selectionSetsObjectsJoinTable.Col(selectionSetIDColumn),
+		},
+		fkColumn: selectionSetsObjectsJoinTable.Col(selectionSetObjectColumn),
+	}
+)
+
+var (
+	audioTableMgr = &table{
+		table:    goqu.T(audioTable),
+		idColumn: goqu.T(audioTable).Col(idColumn),
+	}
+
+	audiosFilesTableMgr = &relatedFilesTable{
+		table: table{
+			table:    audiosFilesJoinTable,
+			idColumn: audiosFilesJoinTable.Col(audioIDColumn),
+		},
+	}
+
+	audiosTagsTableMgr = &joinTable{
+		table: table{
+			table:    audiosTagsJoinTable,
+			idColumn: audiosTagsJoinTable.Col(audioIDColumn),
+		},
+		fkColumn: audiosTagsJoinTable.Col(tagIDColumn),
+	}
+
+	audiosPerformersTableMgr = &joinTable{
+		table: table{
+			table:    audiosPerformersJoinTable,
+			idColumn: audiosPerformersJoinTable.Col(audioIDColumn),
+		},
+		fkColumn: audiosPerformersJoinTable.Col(performerIDColumn),
+	}
+)
@@ -18,10 +18,11 @@ import (
 )
 
 const (
-	tagTable        = "tags"
-	tagIDColumn     = "tag_id"
-	tagAliasesTable = "tag_aliases"
-	tagAliasColumn  = "alias"
+	tagTable             = "tags"
+	tagIDColumn          = "tag_id"
+	tagAliasesTable      = "tag_aliases"
+	tagAliasColumn       = "alias"
+	tagCustomFieldsTable = "tag_custom_fields"
 
 	tagImageBlobColumn = "image_blob"
 )
@@ -144,6 +145,10 @@ func (qb *TagStore) UpdatePartial(ctx context.Context, id int, partial models.Ta
 	r.fromPartial(partial)
 
 	if len(r.Record) > 0 {
+		if err := recordEditHistory(ctx, models.EditObjectTypeTag, id, r.Record); err != nil {
+			return nil, err
+		}
+
 		if err := qb.tableMgr.updateByID(ctx, id, r.Record); err != nil {
 			return nil, err
 		}
@@ -513,6 +518,7 @@ func (qb *TagStore) makeFilter(ctx context.Context, tagFilter *models.TagFilterT
 	query.handleCriterion(ctx, tagChildCountCriterionHandler(qb, tagFilter.ChildCount))
 	query.handleCriterion(ctx, timestampCriterionHandler(tagFilter.CreatedAt, "tags.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(tagFilter.UpdatedAt, "tags.updated_at"))
+	query.handleCriterion(ctx, tagCustomFieldsTableMgr.criterionHandler("tags.id", tagFilter.CustomFields))
 
 	return query
 }
@@ -534,6 +540,9 @@ func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType,
 		query.parseQueryString(searchColumns, *q)
 	}
 
+	if err := models.ValidateFilter("tag_filter", tagFilter); err != nil {
+		return nil, 0, err
+	}
 	if err := qb.validateFilter(tagFilter); err != nil {
 		return nil, 0, err
 	}
@@ -1121,3 +1130,15 @@ SELECT t.*, c.path FROM tags t INNER JOIN children c ON t.id = c.child_id
 
 	return qb.queryTagPaths(ctx, query, args)
 }
+
+func (qb *TagStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return tagCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *TagStore) SetCustomFields(ctx context.Context, id int, fields models.CustomFieldsInput) error {
+	if err := qb.tableMgr.checkIDExists(ctx, id); err != nil {
+		return err
+	}
+
+	return tagCustomFieldsTableMgr.set(ctx, id, fields)
+}
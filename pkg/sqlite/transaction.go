@@ -87,6 +87,42 @@ func (db *Database) Rollback(ctx context.Context) error {
 	return nil
 }
 
+// Savepoint creates a savepoint with the given name in the current
+// transaction.
+func (db *Database) Savepoint(ctx context.Context, name string) error {
+	return db.execSavepoint(ctx, "SAVEPOINT %s", name)
+}
+
+// RollbackToSavepoint rolls back the changes made since the named savepoint
+// and releases it.
+func (db *Database) RollbackToSavepoint(ctx context.Context, name string) error {
+	if err := db.execSavepoint(ctx, "ROLLBACK TO SAVEPOINT %s", name); err != nil {
+		return err
+	}
+
+	return db.ReleaseSavepoint(ctx, name)
+}
+
+// ReleaseSavepoint releases the named savepoint, keeping its changes in the
+// current transaction.
+func (db *Database) ReleaseSavepoint(ctx context.Context, name string) error {
+	return db.execSavepoint(ctx, "RELEASE SAVEPOINT %s", name)
+}
+
+func (db *Database) execSavepoint(ctx context.Context, format string, name string) error {
+	tx, err := getTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf(format, name)
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("%s: %w", stmt, err)
+	}
+
+	return nil
+}
+
 func (db *Database) txnComplete(ctx context.Context) {
 	if exclusive := ctx.Value(exclusiveKey).(bool); exclusive {
 		db.unlock()
@@ -125,18 +161,23 @@ func (db *Database) IsLocked(err error) bool {
 
 func (db *Database) Repository() models.Repository {
 	return models.Repository{
-		TxnManager:     db,
-		File:           db.File,
-		Folder:         db.Folder,
-		Gallery:        db.Gallery,
-		GalleryChapter: db.GalleryChapter,
-		Image:          db.Image,
-		Movie:          db.Movie,
-		Performer:      db.Performer,
-		Scene:          db.Scene,
-		SceneMarker:    db.SceneMarker,
-		Studio:         db.Studio,
-		Tag:            db.Tag,
-		SavedFilter:    db.SavedFilter,
+		TxnManager:        db,
+		Audio:             db.Audio,
+		File:              db.File,
+		Folder:            db.Folder,
+		Gallery:           db.Gallery,
+		GalleryChapter:    db.GalleryChapter,
+		Image:             db.Image,
+		Movie:             db.Movie,
+		Performer:         db.Performer,
+		Scene:             db.Scene,
+		SceneMarker:       db.SceneMarker,
+		SceneRelationship: db.SceneRelationship,
+		Studio:            db.Studio,
+		Tag:               db.Tag,
+		SavedFilter:       db.SavedFilter,
+		SelectionSet:      db.SelectionSet,
+		EditHistory:       db.EditHistory,
+		RatingCriterion:   db.RatingCriterion,
 	}
 }
@@ -14,6 +14,126 @@ import (
 	"github.com/stashapp/stash/pkg/txn"
 )
 
+func TestBatchSavepoint(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+
+	batch := &txn.Batch{
+		Manager: db,
+		Size:    2,
+	}
+
+	createTag := func(name string, fail bool, committed *bool) error {
+		return batch.Do(ctx, func(ctx context.Context) error {
+			if err := db.Tag.Create(ctx, &models.Tag{Name: name}); err != nil {
+				return err
+			}
+
+			txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+				*committed = true
+			})
+
+			if fail {
+				return errFailed
+			}
+
+			return nil
+		})
+	}
+
+	var committed [3]bool
+	if err := createTag("batch tag 1", false, &committed[0]); err != nil {
+		t.Errorf("creating first tag: %v", err)
+	}
+	if err := createTag("batch tag 2", true, &committed[1]); !errors.Is(err, errFailed) {
+		t.Errorf("creating second tag: expected %v, got %v", errFailed, err)
+	}
+
+	// the failed function should not count towards the batch size
+	if committed[0] {
+		t.Errorf("batch committed early")
+	}
+
+	if err := createTag("batch tag 3", false, &committed[2]); err != nil {
+		t.Errorf("creating third tag: %v", err)
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Errorf("committing batch: %v", err)
+	}
+
+	if !committed[0] || committed[1] || !committed[2] {
+		t.Errorf("unexpected post-commit hooks executed: %v", committed)
+	}
+
+	if err := withTxn(func(ctx context.Context) error {
+		for i, name := range []string{"batch tag 1", "batch tag 2", "batch tag 3"} {
+			tag, err := db.Tag.FindByName(ctx, name, false)
+			if err != nil {
+				return err
+			}
+
+			exists := tag != nil
+			if exists != (i != 1) {
+				t.Errorf("tag %q exists = %v", name, exists)
+			}
+
+			if tag != nil {
+				if err := db.Tag.Destroy(ctx, tag.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBatchMaxDuration(t *testing.T) {
+	ctx := context.Background()
+
+	batch := &txn.Batch{
+		Manager:     db,
+		Size:        100,
+		MaxDuration: time.Nanosecond,
+	}
+
+	const name = "batch duration tag"
+	committed := false
+	if err := batch.Do(ctx, func(ctx context.Context) error {
+		txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+			committed = true
+		})
+
+		return db.Tag.Create(ctx, &models.Tag{Name: name})
+	}); err != nil {
+		t.Errorf("creating tag: %v", err)
+	}
+
+	// the transaction has been open for longer than the maximum duration
+	if !committed {
+		t.Errorf("batch not committed after the maximum duration")
+	}
+
+	if err := withTxn(func(ctx context.Context) error {
+		tag, err := db.Tag.FindByName(ctx, name, false)
+		if err != nil {
+			return err
+		}
+
+		if tag == nil {
+			t.Errorf("tag %q not found", name)
+			return nil
+		}
+
+		return db.Tag.Destroy(ctx, tag.ID)
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
 // this test is left commented out as it is not deterministic.
 // func TestConcurrentExclusiveTxn(t *testing.T) {
 // 	const (
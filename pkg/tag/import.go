@@ -11,6 +11,7 @@ import (
 
 type ImporterReaderWriter interface {
 	models.TagCreatorUpdater
+	models.CustomFieldsWriter
 	FindByName(ctx context.Context, name string, nocase bool) (*models.Tag, error)
 }
 
@@ -75,6 +76,14 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		return fmt.Errorf("error setting parents: %v", err)
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting tag custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
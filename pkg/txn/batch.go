@@ -0,0 +1,161 @@
+package txn
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Savepointer is implemented by Managers that support savepoints, which allow
+// part of a transaction to be rolled back.
+type Savepointer interface {
+	Savepoint(ctx context.Context, name string) error
+	// RollbackToSavepoint rolls back the changes made since the savepoint
+	// and releases it.
+	RollbackToSavepoint(ctx context.Context, name string) error
+	ReleaseSavepoint(ctx context.Context, name string) error
+}
+
+const batchSavepoint = "batch"
+
+// Batch executes functions in shared exclusive transactions, to reduce the
+// overhead of executing many small functions in their own transaction. Each
+// function is executed within a savepoint, so that the changes of a failed
+// function are rolled back without affecting the rest of the batch. If the
+// Manager does not support savepoints, each function is executed in its own
+// transaction.
+//
+// Commit must be called after the last function to commit the remaining
+// changes. A Batch is not safe for concurrent use.
+type Batch struct {
+	Manager Manager
+	// Size is the maximum number of functions executed per transaction
+	Size int
+	// MaxDuration is the maximum time a transaction is kept open for, so
+	// that other writers are not blocked for long. Ignored if zero.
+	MaxDuration time.Duration
+
+	// ctx is the context the transaction was started with
+	ctx     context.Context
+	txnCtx  context.Context
+	count   int
+	started time.Time
+}
+
+// Do executes fn in the current batch transaction, beginning a new one if
+// necessary. If fn returns an error, the changes made by fn are rolled back
+// and the error is returned. The transaction is committed after Size
+// functions have been executed, or once it has been open for MaxDuration.
+func (b *Batch) Do(ctx context.Context, fn TxnFunc) error {
+	sp, ok := b.Manager.(Savepointer)
+	if !ok || b.Size <= 1 {
+		return WithTxn(ctx, b.Manager, fn)
+	}
+
+	if b.txnCtx == nil {
+		txnCtx, err := begin(ctx, b.Manager, true)
+		if err != nil {
+			return err
+		}
+
+		b.ctx = ctx
+		b.txnCtx = txnCtx
+		b.started = time.Now()
+	}
+
+	if err := b.savepoint(sp, fn); err != nil {
+		return err
+	}
+
+	b.count++
+	if b.count >= b.Size || (b.MaxDuration > 0 && time.Since(b.started) >= b.MaxDuration) {
+		if err := b.Commit(); err != nil {
+			return fmt.Errorf("committing batch: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (b *Batch) savepoint(sp Savepointer, fn TxnFunc) error {
+	if err := sp.Savepoint(b.txnCtx, batchSavepoint); err != nil {
+		b.rollback()
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+
+	// hooks added by fn are only kept if fn succeeds
+	hm := &hookManager{}
+	fnCtx := hm.register(b.txnCtx)
+
+	defer func() {
+		if p := recover(); p != nil {
+			// a panic occurred, rollback and repanic
+			b.rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(fnCtx); err != nil {
+		if rbErr := sp.RollbackToSavepoint(b.txnCtx, batchSavepoint); rbErr != nil {
+			b.rollback()
+			return fmt.Errorf("%w (batch rolled back: %v)", err, rbErr)
+		}
+
+		return err
+	}
+
+	if err := sp.ReleaseSavepoint(b.txnCtx, batchSavepoint); err != nil {
+		b.rollback()
+		return fmt.Errorf("releasing savepoint: %w", err)
+	}
+
+	hookManagerCtx(b.txnCtx).merge(hm)
+	return nil
+}
+
+// Commit commits the current batch transaction, if any.
+func (b *Batch) Commit() error {
+	if b.txnCtx == nil {
+		return nil
+	}
+
+	ctx, txnCtx := b.ctx, b.txnCtx
+	b.reset()
+
+	hookMgr := hookManagerCtx(txnCtx)
+
+	if err := hookMgr.executePreCommitHooks(txnCtx); err != nil {
+		rollback(txnCtx, b.Manager)
+		hookMgr.executePostRollbackHooks(ctx)
+		hookMgr.executePostCompleteHooks(ctx)
+		return err
+	}
+
+	if err := b.Manager.Commit(txnCtx); err != nil {
+		hookMgr.executePostRollbackHooks(ctx)
+		hookMgr.executePostCompleteHooks(ctx)
+		return err
+	}
+
+	hookMgr.executePostCommitHooks(ctx)
+	hookMgr.executePostCompleteHooks(ctx)
+	return nil
+}
+
+// rollback rolls back the current batch transaction.
+func (b *Batch) rollback() {
+	ctx, txnCtx := b.ctx, b.txnCtx
+	b.reset()
+
+	hookMgr := hookManagerCtx(txnCtx)
+	rollback(txnCtx, b.Manager)
+	hookMgr.executePostRollbackHooks(ctx)
+	hookMgr.executePostCompleteHooks(ctx)
+}
+
+func (b *Batch) reset() {
+	b.ctx = nil
+	b.txnCtx = nil
+	b.count = 0
+	b.started = time.Time{}
+}
@@ -62,6 +62,14 @@ func (m *hookManager) executePostCompleteHooks(ctx context.Context) {
 	executeMustHooks(ctx, m.postCompleteHooks)
 }
 
+// merge appends the hooks of o to m.
+func (m *hookManager) merge(o *hookManager) {
+	m.preCommitHooks = append(m.preCommitHooks, o.preCommitHooks...)
+	m.postCommitHooks = append(m.postCommitHooks, o.postCommitHooks...)
+	m.postRollbackHooks = append(m.postRollbackHooks, o.postRollbackHooks...)
+	m.postCompleteHooks = append(m.postCompleteHooks, o.postCompleteHooks...)
+}
+
 func AddPreCommitHook(ctx context.Context, hook TxnFunc) {
 	m := hookManagerCtx(ctx)
 	m.preCommitHooks = append(m.preCommitHooks, hook)
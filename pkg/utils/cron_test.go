@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{"* * * * *", false},
+		{"*/15 0-6 1,15 * 1-5", false},
+		{"@daily", false},
+		{"0 0 * * 7", false},
+		{"* * * *", true},
+		{"60 * * * *", true},
+		{"* 24 * * *", true},
+		{"* * 0 * *", true},
+		{"*/0 * * * *", true},
+		{"5-1 * * * *", true},
+		{"a * * * *", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if _, err := ParseCron(tt.expr); (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2023, 3, 15, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2023, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2023, 3, 16, 3, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2023, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2023, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2023, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// either the day of month or the day of week matches
+		{"0 0 1 * 5", time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("CronSchedule.Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -67,6 +67,9 @@ const SceneFilenameParser = lazyComponent(
 const SceneDuplicateChecker = lazyComponent(
   () => import("./components/SceneDuplicateChecker/SceneDuplicateChecker")
 );
+const ImageDuplicateChecker = lazyComponent(
+  () => import("./components/ImageDuplicateChecker/ImageDuplicateChecker")
+);
 
 const appleRendering = isPlatformUniquelyRenderedByApple();
 
@@ -212,6 +215,10 @@ export const App: React.FC = () => {
               path="/sceneDuplicateChecker"
               component={SceneDuplicateChecker}
             />
+            <Route
+              path="/imageDuplicateChecker"
+              component={ImageDuplicateChecker}
+            />
             <Route path="/setup" component={Setup} />
             <Route path="/migrate" component={Migrate} />
             <Route component={PageNotFound} />
@@ -79,6 +79,12 @@ export const GenerateDialog: React.FC<ISceneGenerateDialog> = ({
             existing.previewOptions?.previewExcludeEnd,
           previewPreset:
             general.previewPreset ?? existing.previewOptions?.previewPreset,
+          previewImageFormat:
+            general.previewImageFormat ??
+            existing.previewOptions?.previewImageFormat,
+          previewVideoCodec:
+            general.previewVideoCodec ??
+            existing.previewOptions?.previewVideoCodec,
         },
       }));
       setConfigRead(true);
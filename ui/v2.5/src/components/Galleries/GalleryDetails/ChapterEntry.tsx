@@ -26,6 +26,12 @@ export const ChapterEntries: React.FC<IChapterEntries> = ({
             onClick={() => onClickChapter(chapter.image_index)}
           >
             <div className="row">
+              <img
+                className="gallery-chapter-thumbnail"
+                src={chapter.thumbnail}
+                alt=""
+                loading="lazy"
+              />
               {chapter.title}
               {chapter.title.length > 0 ? " - #" : "#"}
               {chapter.image_index}
@@ -0,0 +1,308 @@
+import React, { useMemo, useState } from "react";
+import { Button, Card, Col, Dropdown, Form, Row, Table } from "react-bootstrap";
+import { Link, useHistory } from "react-router-dom";
+import { FormattedMessage, FormattedNumber, useIntl } from "react-intl";
+
+import * as GQL from "src/core/generated-graphql";
+import { LoadingIndicator } from "../Shared/LoadingIndicator";
+import { ErrorMessage } from "../Shared/ErrorMessage";
+import { HoverPopover } from "../Shared/HoverPopover";
+import { GalleryLink } from "../Shared/TagLink";
+import { Pagination } from "src/components/List/Pagination";
+import TextUtils from "src/utils/text";
+import { DeleteImagesDialog } from "src/components/Images/DeleteImagesDialog";
+import { objectTitle } from "src/core/files";
+
+const CLASSNAME = "duplicate-checker";
+
+export const ImageDuplicateChecker: React.FC = () => {
+  const intl = useIntl();
+  const history = useHistory();
+  const query = new URLSearchParams(history.location.search);
+  const currentPage = Number.parseInt(query.get("page") ?? "1", 10);
+  const pageSize = Number.parseInt(query.get("size") ?? "20", 10);
+  const hashDistance = Number.parseInt(query.get("distance") ?? "0", 10);
+
+  const [deletingImages, setDeletingImages] = useState(false);
+  const [selectedImages, setSelectedImages] = useState<
+    GQL.SlimImageDataFragment[]
+  >([]);
+  const [checkedImages, setCheckedImages] = useState<Record<string, boolean>>(
+    {}
+  );
+
+  const { data, loading, refetch } = GQL.useFindDuplicateImagesQuery({
+    fetchPolicy: "no-cache",
+    variables: {
+      distance: hashDistance,
+    },
+  });
+
+  const images = useMemo(() => data?.findDuplicateImages ?? [], [data]);
+
+  if (loading) return <LoadingIndicator />;
+  if (!data) return <ErrorMessage error="Error searching for duplicates." />;
+
+  const filteredImages = images.slice(
+    (currentPage - 1) * pageSize,
+    currentPage * pageSize
+  );
+  const checked = images.flat().filter((i) => checkedImages[i.id]);
+
+  const setQuery = (q: Record<string, string | number | undefined>) => {
+    const newQuery = new URLSearchParams(query);
+    for (const key of Object.keys(q)) {
+      const value = q[key];
+      if (value !== undefined) {
+        newQuery.set(key, String(value));
+      } else {
+        newQuery.delete(key);
+      }
+    }
+    history.push({ search: newQuery.toString() });
+  };
+
+  function onDeleteDialogClosed(deleted: boolean) {
+    setDeletingImages(false);
+    if (deleted) {
+      setSelectedImages([]);
+      setCheckedImages({});
+      refetch();
+    }
+  }
+
+  const fileSize = (image: GQL.SlimImageDataFragment) =>
+    image.visual_files.reduce((sum: number, f) => sum + f.size, 0);
+
+  const onSelectAllButLargest = () => {
+    const checkedArray: Record<string, boolean> = {};
+
+    filteredImages.forEach((group) => {
+      const largest = group.reduce((l, image) =>
+        fileSize(image) > fileSize(l) ? image : l
+      );
+      group.forEach((image) => {
+        if (image !== largest) {
+          checkedArray[image.id] = true;
+        }
+      });
+    });
+
+    setCheckedImages(checkedArray);
+  };
+
+  const handleDelete = (selected: GQL.SlimImageDataFragment[]) => {
+    setSelectedImages(selected);
+    setDeletingImages(true);
+  };
+
+  const renderFilesize = (filesize: number) => {
+    const { size: parsedSize, unit } = TextUtils.fileSize(filesize);
+    return (
+      <FormattedNumber
+        value={parsedSize}
+        style="unit"
+        unit={unit}
+        unitDisplay="narrow"
+        maximumFractionDigits={2}
+      />
+    );
+  };
+
+  function renderPagination() {
+    return (
+      <div className="d-flex mt-2 mb-2">
+        <h6 className="mr-auto align-self-center">
+          <FormattedMessage
+            id="dupe_check.found_sets"
+            values={{ setCount: images.length }}
+          />
+        </h6>
+        {checked.length > 0 && (
+          <Button variant="danger" onClick={() => handleDelete(checked)}>
+            <FormattedMessage id="actions.delete" />
+          </Button>
+        )}
+        <Pagination
+          itemsPerPage={pageSize}
+          currentPage={currentPage}
+          totalItems={images.length}
+          metadataByline={[]}
+          onChangePage={(newPage) => {
+            setQuery({ page: newPage === 1 ? undefined : newPage });
+            setCheckedImages({});
+          }}
+        />
+      </div>
+    );
+  }
+
+  return (
+    <Card id="image-duplicate-checker" className="col col-xl-12 mx-auto">
+      <div className={CLASSNAME}>
+        {deletingImages && (
+          <DeleteImagesDialog
+            selected={selectedImages}
+            onClose={onDeleteDialogClosed}
+          />
+        )}
+        <h4>
+          <FormattedMessage id="dupe_check.image_title" />
+        </h4>
+        <Form>
+          <Form.Group>
+            <Row noGutters>
+              <Form.Label>
+                <FormattedMessage id="dupe_check.search_accuracy_label" />
+              </Form.Label>
+              <Col xs="auto">
+                <Form.Control
+                  as="select"
+                  onChange={(e) =>
+                    setQuery({
+                      distance:
+                        e.currentTarget.value === "0"
+                          ? undefined
+                          : e.currentTarget.value,
+                      page: undefined,
+                    })
+                  }
+                  defaultValue={hashDistance}
+                  className="input-control ml-4"
+                >
+                  <option value={0}>
+                    {intl.formatMessage({ id: "dupe_check.options.exact" })}
+                  </option>
+                  <option value={4}>
+                    {intl.formatMessage({ id: "dupe_check.options.high" })}
+                  </option>
+                  <option value={8}>
+                    {intl.formatMessage({ id: "dupe_check.options.medium" })}
+                  </option>
+                  <option value={10}>
+                    {intl.formatMessage({ id: "dupe_check.options.low" })}
+                  </option>
+                </Form.Control>
+              </Col>
+            </Row>
+            <Form.Text>
+              <FormattedMessage id="dupe_check.description" />
+            </Form.Text>
+          </Form.Group>
+          <Form.Group>
+            <Dropdown>
+              <Dropdown.Toggle variant="secondary">
+                <FormattedMessage id="dupe_check.select_options" />
+              </Dropdown.Toggle>
+              <Dropdown.Menu className="bg-secondary text-white">
+                <Dropdown.Item onClick={() => setCheckedImages({})}>
+                  {intl.formatMessage({ id: "dupe_check.select_none" })}
+                </Dropdown.Item>
+                <Dropdown.Item onClick={() => onSelectAllButLargest()}>
+                  {intl.formatMessage({
+                    id: "dupe_check.select_all_but_largest_file",
+                  })}
+                </Dropdown.Item>
+              </Dropdown.Menu>
+            </Dropdown>
+          </Form.Group>
+        </Form>
+
+        {renderPagination()}
+
+        <Table responsive striped className={`${CLASSNAME}-table`}>
+          <thead>
+            <tr>
+              <th> </th>
+              <th> </th>
+              <th>{intl.formatMessage({ id: "details" })}</th>
+              <th>{intl.formatMessage({ id: "galleries" })}</th>
+              <th>{intl.formatMessage({ id: "filesize" })}</th>
+              <th>{intl.formatMessage({ id: "resolution" })}</th>
+              <th>{intl.formatMessage({ id: "actions.delete" })}</th>
+            </tr>
+          </thead>
+          <tbody>
+            {filteredImages.map((group, groupIndex) =>
+              group.map((image, i) => {
+                const file =
+                  image.visual_files.length > 0
+                    ? image.visual_files[0]
+                    : undefined;
+
+                return (
+                  <React.Fragment key={image.id}>
+                    {i === 0 && groupIndex !== 0 ? (
+                      <tr className="separator" />
+                    ) : undefined}
+                    <tr className={i === 0 ? "duplicate-group" : ""}>
+                      <td>
+                        <Form.Check
+                          checked={checkedImages[image.id] ?? false}
+                          onChange={(e) =>
+                            setCheckedImages({
+                              ...checkedImages,
+                              [image.id]: e.currentTarget.checked,
+                            })
+                          }
+                        />
+                      </td>
+                      <td>
+                        <HoverPopover
+                          content={
+                            <img
+                              src={image.paths.thumbnail ?? ""}
+                              alt=""
+                              width={600}
+                            />
+                          }
+                          placement="right"
+                        >
+                          <img
+                            src={image.paths.thumbnail ?? ""}
+                            alt=""
+                            width={100}
+                          />
+                        </HoverPopover>
+                      </td>
+                      <td className="text-left">
+                        <p>
+                          <Link to={`/images/${image.id}`}>
+                            {objectTitle(image)}
+                          </Link>
+                        </p>
+                        <p className="image-path">{file?.path ?? ""}</p>
+                      </td>
+                      <td>
+                        {image.galleries.map((g) => (
+                          <GalleryLink key={g.id} gallery={g} />
+                        ))}
+                      </td>
+                      <td>{renderFilesize(fileSize(image))}</td>
+                      <td>{`${file?.width ?? 0}x${file?.height ?? 0}`}</td>
+                      <td>
+                        <Button
+                          className="edit-button"
+                          variant="danger"
+                          onClick={() => handleDelete([image])}
+                        >
+                          <FormattedMessage id="actions.delete" />
+                        </Button>
+                      </td>
+                    </tr>
+                  </React.Fragment>
+                );
+              })
+            )}
+          </tbody>
+        </Table>
+        {images.length === 0 && (
+          <h4 className="text-center mt-4">No duplicates found.</h4>
+        )}
+        {renderPagination()}
+      </div>
+    </Card>
+  );
+};
+
+export default ImageDuplicateChecker;
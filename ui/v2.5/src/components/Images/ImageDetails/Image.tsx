@@ -265,7 +265,9 @@ const ImagePage: React.FC<IProps> = ({ image }) => {
   });
 
   const title = objectTitle(image);
-  const ImageView = isVideo(image.visual_files[0]) ? "video" : "img";
+  // animated images are played from their transcode, if generated
+  const asVideo = !!image.paths.video || isVideo(image.visual_files[0]);
+  const ImageView = asVideo ? "video" : "img";
 
   return (
     <div className="row">
@@ -293,17 +295,14 @@ const ImagePage: React.FC<IProps> = ({ image }) => {
       </div>
       <div className="image-container">
         <ImageView
-          loop={image.visual_files[0].__typename == "VideoFile"}
-          autoPlay={image.visual_files[0].__typename == "VideoFile"}
-          controls={image.visual_files[0].__typename == "VideoFile"}
+          loop={asVideo}
+          autoPlay={asVideo}
+          controls={asVideo}
+          muted={!!image.paths.video}
           className="m-sm-auto no-gutter image-image"
-          style={
-            image.visual_files[0].__typename == "VideoFile"
-              ? { width: "100%", height: "100%" }
-              : {}
-          }
+          style={asVideo ? { width: "100%", height: "100%" } : {}}
           alt={title}
-          src={image.paths.image ?? ""}
+          src={image.paths.video || image.paths.image || ""}
         />
       </div>
     </div>
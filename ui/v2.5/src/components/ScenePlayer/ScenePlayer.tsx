@@ -564,11 +564,19 @@ export const ScenePlayer: React.FC<IScenePlayerProps> = ({
       player.mobileUi(mobileUiOptions);
     }
 
+    function canPlayType(mimeType: string) {
+      if (window.MediaSource?.isTypeSupported(mimeType)) {
+        return true;
+      }
+      return document.createElement("video").canPlayType(mimeType) !== "";
+    }
+
     function isDirect(src: URL) {
       return (
         src.pathname.endsWith("/stream") ||
         src.pathname.endsWith("/stream.mpd") ||
-        src.pathname.endsWith("/stream.m3u8")
+        src.pathname.endsWith("/stream.m3u8") ||
+        src.pathname.endsWith("/stream_adaptive.m3u8")
       );
     }
 
@@ -580,7 +588,14 @@ export const ScenePlayer: React.FC<IScenePlayerProps> = ({
           const src = new URL(stream.url);
           const isFileTranscode = !isDirect(src);
 
-          return !(isFileTranscode && isSafari);
+          if (isFileTranscode && isSafari) return false;
+
+          // skip streams with codecs that the browser cannot decode
+          if (stream.mime_type?.includes("codecs=")) {
+            return canPlayType(stream.mime_type);
+          }
+
+          return true;
         })
         .map((stream) => {
           const src = new URL(stream.url);
@@ -609,10 +624,10 @@ export const ScenePlayer: React.FC<IScenePlayerProps> = ({
       return languageCode;
     }
 
-    if (scene.captions && scene.captions.length > 0) {
-      const languageCode = getDefaultLanguageCode();
-      let hasDefault = false;
+    const languageCode = getDefaultLanguageCode();
+    let hasDefault = false;
 
+    if (scene.captions && scene.captions.length > 0) {
       for (let caption of scene.captions) {
         const lang = caption.language_code;
         let label = lang;
@@ -638,6 +653,32 @@ export const ScenePlayer: React.FC<IScenePlayerProps> = ({
       }
     }
 
+    if (scene.embedded_captions && scene.embedded_captions.length > 0) {
+      for (let caption of scene.embedded_captions) {
+        const lang = caption.language_code;
+        let label = lang;
+        if (languageMap.has(lang)) {
+          label = languageMap.get(lang)!;
+        }
+
+        label = label + " (" + (caption.title ?? "embedded") + ")";
+        const setAsDefault = !hasDefault && languageCode == lang;
+        if (setAsDefault) {
+          hasDefault = true;
+        }
+        sourceSelector.addTextTrack(
+          {
+            src: `${scene.paths.caption}/embedded/${caption.track_index}`,
+            kind: "captions",
+            srclang: lang,
+            label: label,
+            default: setAsDefault,
+          },
+          false
+        );
+      }
+    }
+
     auto.current =
       autoplay ||
       (interfaceConfig?.autostartVideo ?? false) ||
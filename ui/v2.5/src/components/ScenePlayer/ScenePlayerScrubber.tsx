@@ -324,6 +324,12 @@ export const ScenePlayerScrubber: React.FC<IScenePlayerScrubberProps> = ({
               : undefined,
           }}
         />
+        {scene.paths.waveform && (
+          <div
+            className="scrubber-waveform"
+            style={{ backgroundImage: `url(${scene.paths.waveform})` }}
+          />
+        )}
         <div ref={indicatorEl} id="scrubber-position-indicator" />
         <div id="scrubber-current-position" />
         <div className="scrubber-viewport">
@@ -0,0 +1,184 @@
+import React, { useState } from "react";
+import { Form } from "react-bootstrap";
+import { FormattedMessage, useIntl } from "react-intl";
+import { faCut } from "@fortawesome/free-solid-svg-icons";
+import * as GQL from "src/core/generated-graphql";
+import { ModalComponent } from "src/components/Shared/Modal";
+import { DurationInput } from "src/components/Shared/DurationInput";
+import { useToast } from "src/hooks/Toast";
+import { useSceneExtractClip } from "src/core/StashService";
+import { getPlayerPosition } from "src/components/ScenePlayer/util";
+
+interface IExtractClipDialogProps {
+  scene: GQL.SceneDataFragment;
+  onClose: () => void;
+}
+
+export const ExtractClipDialog: React.FC<IExtractClipDialogProps> = ({
+  scene,
+  onClose,
+}) => {
+  const intl = useIntl();
+  const Toast = useToast();
+  const [extractClip] = useSceneExtractClip();
+
+  const duration = scene.files[0]?.duration ?? 0;
+  const initialStart = Math.round(getPlayerPosition() ?? 0);
+
+  const [start, setStart] = useState<number | undefined>(initialStart);
+  const [end, setEnd] = useState<number | undefined>(
+    Math.min(initialStart + 30, duration)
+  );
+  const [videoCodec, setVideoCodec] = useState<GQL.ClipVideoCodec | "">("");
+  const [addToLibrary, setAddToLibrary] = useState(false);
+  const [isRunning, setIsRunning] = useState(false);
+  const [downloadURL, setDownloadURL] = useState<string>();
+
+  const valid =
+    start !== undefined &&
+    end !== undefined &&
+    start >= 0 &&
+    end > start &&
+    (!duration || end <= duration);
+
+  async function onExtract() {
+    if (start === undefined || end === undefined) return;
+
+    setIsRunning(true);
+    try {
+      const result = await extractClip({
+        variables: {
+          input: {
+            id: scene.id,
+            start,
+            end,
+            video_codec: videoCodec || undefined,
+            add_to_library: addToLibrary,
+          },
+        },
+      });
+
+      const ret = result.data?.sceneExtractClip;
+      if (ret?.download_url) {
+        setDownloadURL(ret.download_url);
+      }
+
+      Toast.success({
+        content: intl.formatMessage(
+          { id: "config.tasks.added_job_to_queue" },
+          {
+            operation_name: intl.formatMessage({
+              id: "dialogs.extract_clip.title",
+            }),
+          }
+        ),
+      });
+
+      if (!ret?.download_url) {
+        onClose();
+      }
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsRunning(false);
+    }
+  }
+
+  if (downloadURL) {
+    return (
+      <ModalComponent
+        show
+        icon={faCut}
+        header={intl.formatMessage({ id: "dialogs.extract_clip.title" })}
+        accept={{
+          onClick: () => onClose(),
+          text: intl.formatMessage({ id: "actions.close" }),
+        }}
+      >
+        <p>
+          <FormattedMessage id="dialogs.extract_clip.download_when_finished" />
+        </p>
+        <a href={downloadURL} target="_blank" rel="noopener noreferrer">
+          {downloadURL}
+        </a>
+      </ModalComponent>
+    );
+  }
+
+  return (
+    <ModalComponent
+      show
+      icon={faCut}
+      header={intl.formatMessage({ id: "dialogs.extract_clip.title" })}
+      accept={{
+        onClick: onExtract,
+        text: intl.formatMessage({ id: "actions.extract" }),
+      }}
+      cancel={{
+        onClick: () => onClose(),
+        text: intl.formatMessage({ id: "actions.cancel" }),
+        variant: "secondary",
+      }}
+      isRunning={isRunning}
+      disabled={!valid}
+    >
+      <Form>
+        <Form.Group>
+          <Form.Label>
+            <FormattedMessage id="dialogs.extract_clip.start" />
+          </Form.Label>
+          <DurationInput
+            value={start}
+            setValue={setStart}
+            onReset={() => setStart(Math.round(getPlayerPosition() ?? 0))}
+          />
+        </Form.Group>
+        <Form.Group>
+          <Form.Label>
+            <FormattedMessage id="dialogs.extract_clip.end" />
+          </Form.Label>
+          <DurationInput
+            value={end}
+            setValue={setEnd}
+            onReset={() => setEnd(Math.round(getPlayerPosition() ?? 0))}
+          />
+        </Form.Group>
+        <Form.Group>
+          <Form.Label>
+            <FormattedMessage id="dialogs.extract_clip.video_codec" />
+          </Form.Label>
+          <Form.Control
+            as="select"
+            className="input-control"
+            value={videoCodec}
+            onChange={(e) =>
+              setVideoCodec(e.currentTarget.value as GQL.ClipVideoCodec | "")
+            }
+          >
+            <option value="">
+              {intl.formatMessage({ id: "dialogs.extract_clip.copy" })}
+            </option>
+            {Object.values(GQL.ClipVideoCodec).map((c) => (
+              <option key={c} value={c}>
+                {c}
+              </option>
+            ))}
+          </Form.Control>
+          <Form.Text className="text-muted">
+            <FormattedMessage id="dialogs.extract_clip.video_codec_desc" />
+          </Form.Text>
+        </Form.Group>
+        <Form.Check
+          id="add-to-library"
+          checked={addToLibrary}
+          label={intl.formatMessage({
+            id: "dialogs.extract_clip.add_to_library",
+          })}
+          onChange={() => setAddToLibrary(!addToLibrary)}
+        />
+      </Form>
+    </ModalComponent>
+  );
+};
+
+export default ExtractClipDialog;
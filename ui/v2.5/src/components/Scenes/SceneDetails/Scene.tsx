@@ -69,6 +69,7 @@ const DeleteScenesDialog = lazyComponent(() => import("../DeleteScenesDialog"));
 const GenerateDialog = lazyComponent(
   () => import("../../Dialogs/GenerateDialog")
 );
+const ExtractClipDialog = lazyComponent(() => import("./ExtractClipDialog"));
 const SceneVideoFilterPanel = lazyComponent(
   () => import("./SceneVideoFilterPanel")
 );
@@ -134,6 +135,8 @@ const ScenePage: React.FC<IProps> = ({
 
   const [isDeleteAlertOpen, setIsDeleteAlertOpen] = useState<boolean>(false);
   const [isGenerateDialogOpen, setIsGenerateDialogOpen] = useState(false);
+  const [isExtractClipDialogOpen, setIsExtractClipDialogOpen] =
+    useState(false);
 
   const onIncrementClick = async () => {
     try {
@@ -285,6 +288,17 @@ const ScenePage: React.FC<IProps> = ({
     }
   }
 
+  function maybeRenderExtractClipDialog() {
+    if (isExtractClipDialogOpen) {
+      return (
+        <ExtractClipDialog
+          scene={scene}
+          onClose={() => setIsExtractClipDialogOpen(false)}
+        />
+      );
+    }
+  }
+
   const renderOperations = () => (
     <Dropdown>
       <Dropdown.Toggle
@@ -326,6 +340,15 @@ const ScenePage: React.FC<IProps> = ({
         >
           <FormattedMessage id="actions.generate_thumb_default" />
         </Dropdown.Item>
+        {!!scene.files.length && (
+          <Dropdown.Item
+            key="extract-clip"
+            className="bg-secondary text-white"
+            onClick={() => setIsExtractClipDialogOpen(true)}
+          >
+            <FormattedMessage id="actions.extract_clip" />
+          </Dropdown.Item>
+        )}
         {boxes.length > 0 && (
           <Dropdown.Item
             key="submit"
@@ -505,6 +528,7 @@ const ScenePage: React.FC<IProps> = ({
         <title>{title}</title>
       </Helmet>
       {maybeRenderSceneGenerateDialog()}
+      {maybeRenderExtractClipDialog()}
       {maybeRenderDeleteDialog()}
       <div
         className={`scene-tabs order-xl-first order-last ${
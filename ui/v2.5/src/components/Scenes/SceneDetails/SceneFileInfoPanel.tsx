@@ -232,6 +232,22 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
     }
   }
 
+  function renderInteractiveStatistics() {
+    const stats = [
+      ["interactive_average_speed", props.scene.interactive_average_speed],
+      ["interactive_max_speed", props.scene.interactive_max_speed],
+      ["interactive_action_density", props.scene.interactive_action_density],
+    ] as const;
+
+    return stats.map(([id, value]) =>
+      value ? (
+        <TextField key={id} id={`media_info.${id}`}>
+          <FormattedNumber value={value} />
+        </TextField>
+      ) : undefined
+    );
+  }
+
   const filesPanel = useMemo(() => {
     if (props.scene.files.length === 0) {
       return;
@@ -306,6 +322,7 @@ export const SceneFileInfoPanel: React.FC<ISceneFileInfoPanelProps> = (
         )}
         {renderFunscript()}
         {renderInteractiveSpeed()}
+        {renderInteractiveStatistics()}
         <URLsField id="urls" urls={props.scene.urls} truncate />
         {renderStashIDs()}
         <TextField
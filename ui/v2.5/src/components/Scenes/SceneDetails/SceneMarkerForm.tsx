@@ -1,6 +1,6 @@
 import React, { useMemo } from "react";
 import { Button, Form } from "react-bootstrap";
-import { FormattedMessage } from "react-intl";
+import { FormattedMessage, useIntl } from "react-intl";
 import { useFormik } from "formik";
 import * as yup from "yup";
 import * as GQL from "src/core/generated-graphql";
@@ -33,6 +33,7 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
   const [sceneMarkerUpdate] = useSceneMarkerUpdate();
   const [sceneMarkerDestroy] = useSceneMarkerDestroy();
   const Toast = useToast();
+  const intl = useIntl();
 
   const isNew = marker === undefined;
 
@@ -41,6 +42,9 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
     seconds: yup.number().required(),
     primary_tag_id: yup.string().required(),
     tag_ids: yup.array(yup.string().required()).defined(),
+    preview_format: yup.string().ensure(),
+    preview_duration: yup.number().positive().nullable().defined(),
+    preview_width: yup.number().integer().positive().nullable().defined(),
   });
 
   // useMemo to only run getPlayerPosition when the input marker actually changes
@@ -50,6 +54,9 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
       seconds: marker?.seconds ?? Math.round(getPlayerPosition() ?? 0),
       primary_tag_id: marker?.primary_tag.id ?? "",
       tag_ids: marker?.tags.map((tag) => tag.id) ?? [],
+      preview_format: marker?.preview_format ?? "",
+      preview_duration: marker?.preview_duration ?? null,
+      preview_width: marker?.preview_width ?? null,
     }),
     [marker]
   );
@@ -63,7 +70,14 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
     onSubmit: (values) => onSave(values),
   });
 
-  async function onSave(input: InputValues) {
+  async function onSave(values: InputValues) {
+    const input = {
+      ...values,
+      preview_format: values.preview_format
+        ? (values.preview_format as GQL.MarkerPreviewFormat)
+        : null,
+    };
+
     try {
       if (isNew) {
         await sceneMarkerCreate({
@@ -107,6 +121,10 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
 
   const primaryTagId = formik.values.primary_tag_id;
 
+  function numberValue(value: string) {
+    return value === "" ? null : Number.parseInt(value, 10);
+  }
+
   return (
     <Form noValidate onSubmit={formik.handleSubmit}>
       <div>
@@ -177,6 +195,67 @@ export const SceneMarkerForm: React.FC<ISceneMarkerForm> = ({
             />
           </div>
         </Form.Group>
+        <Form.Group className="row">
+          <Form.Label className="col-sm-3 col-md-2 col-xl-12 col-form-label">
+            <FormattedMessage id="dialogs.scene_gen.marker_preview_format" />
+          </Form.Label>
+          <div className="col-sm-9 col-md-10 col-xl-12">
+            <Form.Control
+              as="select"
+              className="input-control"
+              {...formik.getFieldProps("preview_format")}
+            >
+              <option value="">
+                {intl.formatMessage({ id: "marker_preview_default" })}
+              </option>
+              {Object.values(GQL.MarkerPreviewFormat).map((f) => (
+                <option value={f} key={f}>
+                  {intl.formatMessage({
+                    id: `dialogs.scene_gen.marker_preview_formats.${f.toLowerCase()}`,
+                  })}
+                </option>
+              ))}
+            </Form.Control>
+          </div>
+        </Form.Group>
+        <Form.Group className="row">
+          <Form.Label className="col-sm-3 col-md-2 col-xl-12 col-form-label">
+            <FormattedMessage id="dialogs.scene_gen.marker_preview_duration" />
+          </Form.Label>
+          <div className="col-sm-9 col-md-10 col-xl-12">
+            <Form.Control
+              type="number"
+              className="text-input"
+              placeholder={intl.formatMessage({ id: "marker_preview_default" })}
+              value={formik.values.preview_duration ?? ""}
+              onChange={(e) =>
+                formik.setFieldValue(
+                  "preview_duration",
+                  numberValue(e.currentTarget.value)
+                )
+              }
+            />
+          </div>
+        </Form.Group>
+        <Form.Group className="row">
+          <Form.Label className="col-sm-3 col-md-2 col-xl-12 col-form-label">
+            <FormattedMessage id="dialogs.scene_gen.marker_preview_width" />
+          </Form.Label>
+          <div className="col-sm-9 col-md-10 col-xl-12">
+            <Form.Control
+              type="number"
+              className="text-input"
+              placeholder={intl.formatMessage({ id: "marker_preview_default" })}
+              value={formik.values.preview_width ?? ""}
+              onChange={(e) =>
+                formik.setFieldValue(
+                  "preview_width",
+                  numberValue(e.currentTarget.value)
+                )
+              }
+            />
+          </div>
+        </Form.Group>
       </div>
       <div className="buttons-container row">
         <div className="col d-flex">
@@ -273,6 +273,42 @@ export const SettingsConfigurationPanel: React.FC = () => {
           onChange={(v) => saveGeneral({ transcodeHardwareAcceleration: v })}
         />
 
+        <StringSetting
+          id="hardware-encoding-codec"
+          headingID="config.general.ffmpeg.hardware_acceleration_codec.heading"
+          subHeadingID="config.general.ffmpeg.hardware_acceleration_codec.desc"
+          value={general.transcodeHardwareAccelerationCodec ?? undefined}
+          onChange={(v) =>
+            saveGeneral({ transcodeHardwareAccelerationCodec: v })
+          }
+        />
+
+        <StringSetting
+          id="hardware-encoding-device"
+          headingID="config.general.ffmpeg.hardware_acceleration_device.heading"
+          subHeadingID="config.general.ffmpeg.hardware_acceleration_device.desc"
+          value={general.transcodeHardwareAccelerationDevice ?? undefined}
+          onChange={(v) =>
+            saveGeneral({ transcodeHardwareAccelerationDevice: v })
+          }
+        />
+
+        <BooleanSetting
+          id="hdr-tone-mapping"
+          headingID="config.general.ffmpeg.hdr_tone_mapping.heading"
+          subHeadingID="config.general.ffmpeg.hdr_tone_mapping.desc"
+          checked={general.hdrToneMapping ?? true}
+          onChange={(v) => saveGeneral({ hdrToneMapping: v })}
+        />
+
+        <NumberSetting
+          id="stream-cache-size"
+          headingID="config.general.ffmpeg.stream_cache_size.heading"
+          subHeadingID="config.general.ffmpeg.stream_cache_size.desc"
+          value={general.streamCacheSizeMB ?? undefined}
+          onChange={(v) => saveGeneral({ streamCacheSizeMB: v })}
+        />
+
         <StringListSetting
           id="transcode-input-args"
           headingID="config.general.ffmpeg.transcode.input_args.heading"
@@ -302,6 +338,70 @@ export const SettingsConfigurationPanel: React.FC = () => {
           onChange={(v) => saveGeneral({ liveTranscodeOutputArgs: v })}
           value={general.liveTranscodeOutputArgs ?? []}
         />
+
+        <BooleanSetting
+          id="live-transcode-loudnorm"
+          headingID="config.general.ffmpeg.live_transcode.loudnorm.heading"
+          subHeadingID="config.general.ffmpeg.live_transcode.loudnorm.desc"
+          checked={general.liveTranscodeLoudnorm ?? false}
+          onChange={(v) => saveGeneral({ liveTranscodeLoudnorm: v })}
+        />
+        <NumberSetting
+          id="live-transcode-loudnorm-target"
+          headingID="config.general.ffmpeg.live_transcode.loudnorm_target.heading"
+          subHeadingID="config.general.ffmpeg.live_transcode.loudnorm_target.desc"
+          disabled={!general.liveTranscodeLoudnorm}
+          value={general.liveTranscodeLoudnormTarget ?? undefined}
+          onChange={(v) => saveGeneral({ liveTranscodeLoudnormTarget: v })}
+        />
+      </SettingSection>
+
+      <SettingSection
+        headingID="config.general.ffmpeg.codec_args.live.heading"
+        subHeadingID="config.general.ffmpeg.codec_args.live.desc"
+      >
+        {general.ffmpegLiveCodecArgs?.map((c) => (
+          <StringListSetting
+            key={c.codec}
+            id={`ffmpeg-live-codec-args-${c.codec}`}
+            heading={c.codec}
+            subHeadingID={
+              c.builtIn
+                ? "config.general.ffmpeg.codec_args.built_in"
+                : undefined
+            }
+            value={c.args}
+            onChange={(v) =>
+              saveGeneral({
+                ffmpegLiveCodecArgs: [{ codec: c.codec, args: v }],
+              })
+            }
+          />
+        ))}
+      </SettingSection>
+
+      <SettingSection
+        headingID="config.general.ffmpeg.codec_args.generate.heading"
+        subHeadingID="config.general.ffmpeg.codec_args.generate.desc"
+      >
+        {general.ffmpegGenerateCodecArgs?.map((c) => (
+          <StringListSetting
+            key={c.codec}
+            id={`ffmpeg-generate-codec-args-${c.codec}`}
+            heading={c.codec}
+            subHeadingID={
+              c.builtIn
+                ? "config.general.ffmpeg.codec_args.built_in"
+                : undefined
+            }
+            value={c.args}
+            onChange={(v) =>
+              saveGeneral({
+                ffmpegGenerateCodecArgs: [{ codec: c.codec, args: v }],
+              })
+            }
+          />
+        ))}
       </SettingSection>
 
       <SettingSection headingID="config.general.parallel_scan_head">
@@ -312,6 +412,32 @@ export const SettingsConfigurationPanel: React.FC = () => {
           value={general.parallelTasks ?? undefined}
           onChange={(v) => saveGeneral({ parallelTasks: v })}
         />
+        <NumberSetting
+          id="image-thumbnail-parallel-tasks"
+          headingID="config.general.image_thumbnail_parallel_tasks.heading"
+          subHeadingID="config.general.image_thumbnail_parallel_tasks.description"
+          value={general.imageThumbnailParallelTasks ?? undefined}
+          onChange={(v) => saveGeneral({ imageThumbnailParallelTasks: v })}
+        />
+        <SelectSetting
+          id="image-thumbnail-backend"
+          headingID="config.general.image_thumbnail_backend.heading"
+          subHeadingID="config.general.image_thumbnail_backend.description"
+          value={general.imageThumbnailBackend ?? undefined}
+          onChange={(v) =>
+            saveGeneral({
+              imageThumbnailBackend: v as GQL.ImageThumbnailBackend,
+            })
+          }
+        >
+          {Object.values(GQL.ImageThumbnailBackend).map((b) => (
+            <option value={b} key={b}>
+              {intl.formatMessage({
+                id: `config.general.image_thumbnail_backend.options.${b.toLowerCase()}`,
+              })}
+            </option>
+          ))}
+        </SelectSetting>
       </SettingSection>
 
       <SettingSection headingID="config.general.preview_generation">
@@ -333,6 +459,54 @@ export const SettingsConfigurationPanel: React.FC = () => {
           ))}
         </SelectSetting>
 
+        <SelectSetting
+          id="scene-gen-preview-video-codec"
+          headingID="dialogs.scene_gen.preview_video_codec_head"
+          subHeadingID="dialogs.scene_gen.preview_video_codec_desc"
+          value={general.previewVideoCodec ?? undefined}
+          onChange={(v) =>
+            saveGeneral({
+              previewVideoCodec: (v as GQL.PreviewVideoCodec) ?? undefined,
+            })
+          }
+        >
+          {Object.values(GQL.PreviewVideoCodec).map((c) => (
+            <option value={c} key={c}>
+              {intl.formatMessage({
+                id: `dialogs.scene_gen.preview_video_codecs.${c.toLowerCase()}`,
+              })}
+            </option>
+          ))}
+        </SelectSetting>
+
+        <SelectSetting
+          id="scene-gen-preview-image-format"
+          headingID="dialogs.scene_gen.preview_image_format_head"
+          subHeadingID="dialogs.scene_gen.preview_image_format_desc"
+          value={general.previewImageFormat ?? undefined}
+          onChange={(v) =>
+            saveGeneral({
+              previewImageFormat: (v as GQL.PreviewImageFormat) ?? undefined,
+            })
+          }
+        >
+          {Object.values(GQL.PreviewImageFormat).map((f) => (
+            <option value={f} key={f}>
+              {intl.formatMessage({
+                id: `dialogs.scene_gen.preview_image_formats.${f.toLowerCase()}`,
+              })}
+            </option>
+          ))}
+        </SelectSetting>
+
+        <BooleanSetting
+          id="best-frame-covers"
+          headingID="config.general.best_frame_covers_head"
+          subHeadingID="config.general.best_frame_covers_desc"
+          checked={general.bestFrameCovers ?? false}
+          onChange={(v) => saveGeneral({ bestFrameCovers: v })}
+        />
+
         <BooleanSetting
           id="preview-include-audio"
           headingID="config.general.include_audio_head"
@@ -29,6 +29,18 @@ export const SettingsToolsPanel: React.FC = () => {
           }
         />
       </SettingSection>
+
+      <SettingSection headingID="config.tools.image_tools">
+        <Setting
+          heading={
+            <Link to="/imageDuplicateChecker">
+              <Button>
+                <FormattedMessage id="config.tools.image_duplicate_checker" />
+              </Button>
+            </Link>
+          }
+        />
+      </SettingSection>
     </>
   );
 };
@@ -1,6 +1,12 @@
 import React from "react";
+import { useIntl } from "react-intl";
 import * as GQL from "src/core/generated-graphql";
-import { BooleanSetting, ModalSetting } from "../Inputs";
+import {
+  BooleanSetting,
+  ModalSetting,
+  NumberSetting,
+  SelectSetting,
+} from "../Inputs";
 import {
   VideoPreviewInput,
   VideoPreviewSettingsInput,
@@ -17,13 +23,25 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
   options,
   setOptions: setOptionsState,
 }) => {
+  const intl = useIntl();
+
   const previewOptions: GQL.GeneratePreviewOptionsInput =
     options.previewOptions ?? {};
+  const markerPreviewOptions: GQL.GenerateMarkerPreviewOptionsInput =
+    options.markerPreviewOptions ?? {};
 
   function setOptions(input: Partial<GQL.GenerateMetadataInput>) {
     setOptionsState({ ...options, ...input });
   }
 
+  function setMarkerPreviewOptions(
+    input: Partial<GQL.GenerateMarkerPreviewOptionsInput>
+  ) {
+    setOptions({
+      markerPreviewOptions: { ...markerPreviewOptions, ...input },
+    });
+  }
+
   return (
     <>
       <BooleanSetting
@@ -109,6 +127,54 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
         tooltipID="dialogs.scene_gen.marker_screenshots_tooltip"
         onChange={(v) => setOptions({ markerScreenshots: v })}
       />
+      <SelectSetting
+        id="marker-preview-format"
+        className="sub-setting"
+        disabled={!options.markers}
+        headingID="dialogs.scene_gen.marker_preview_format"
+        subHeadingID="dialogs.scene_gen.marker_preview_format_desc"
+        value={
+          markerPreviewOptions.markerPreviewFormat ??
+          GQL.MarkerPreviewFormat.Video
+        }
+        onChange={(v) =>
+          setMarkerPreviewOptions({
+            markerPreviewFormat: v as GQL.MarkerPreviewFormat,
+          })
+        }
+      >
+        {Object.values(GQL.MarkerPreviewFormat).map((f) => (
+          <option value={f} key={f}>
+            {intl.formatMessage({
+              id: `dialogs.scene_gen.marker_preview_formats.${f.toLowerCase()}`,
+            })}
+          </option>
+        ))}
+      </SelectSetting>
+      <NumberSetting
+        id="marker-preview-duration"
+        className="sub-setting"
+        disabled={!options.markers}
+        headingID="dialogs.scene_gen.marker_preview_duration"
+        subHeadingID="dialogs.scene_gen.marker_preview_duration_desc"
+        value={markerPreviewOptions.markerPreviewDuration ?? undefined}
+        onChange={(v) =>
+          // 0 uses the default
+          setMarkerPreviewOptions({ markerPreviewDuration: v > 0 ? v : undefined })
+        }
+      />
+      <NumberSetting
+        id="marker-preview-width"
+        className="sub-setting"
+        disabled={!options.markers}
+        headingID="dialogs.scene_gen.marker_preview_width"
+        subHeadingID="dialogs.scene_gen.marker_preview_width_desc"
+        value={markerPreviewOptions.markerPreviewWidth ?? undefined}
+        onChange={(v) =>
+          // 0 uses the default
+          setMarkerPreviewOptions({ markerPreviewWidth: v > 0 ? v : undefined })
+        }
+      />
 
       <BooleanSetting
         id="transcode-task"
@@ -136,6 +202,20 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
         tooltipID="dialogs.scene_gen.phash_tooltip"
         onChange={(v) => setOptions({ phashes: v })}
       />
+      <BooleanSetting
+        id="image-phash-task"
+        checked={options.imagePhashes ?? false}
+        headingID="dialogs.scene_gen.image_phash"
+        tooltipID="dialogs.scene_gen.image_phash_tooltip"
+        onChange={(v) => setOptions({ imagePhashes: v })}
+      />
+      <BooleanSetting
+        id="image-thumbnail-task"
+        checked={options.imageThumbnails ?? false}
+        headingID="dialogs.scene_gen.image_thumbnails"
+        tooltipID="dialogs.scene_gen.image_thumbnails_tooltip"
+        onChange={(v) => setOptions({ imageThumbnails: v })}
+      />
 
       <BooleanSetting
         id="interactive-heatmap-speed-task"
@@ -143,12 +223,26 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
         headingID="dialogs.scene_gen.interactive_heatmap_speed"
         onChange={(v) => setOptions({ interactiveHeatmapsSpeeds: v })}
       />
+      <BooleanSetting
+        id="waveforms-task"
+        checked={options.waveforms ?? false}
+        headingID="dialogs.scene_gen.waveforms"
+        tooltipID="dialogs.scene_gen.waveforms_tooltip"
+        onChange={(v) => setOptions({ waveforms: v })}
+      />
       <BooleanSetting
         id="clip-previews"
         checked={options.clipPreviews ?? false}
         headingID="dialogs.scene_gen.clip_previews"
         onChange={(v) => setOptions({ clipPreviews: v })}
       />
+      <BooleanSetting
+        id="clip-transcodes"
+        checked={options.clipTranscodes ?? false}
+        headingID="dialogs.scene_gen.clip_transcodes"
+        tooltipID="dialogs.scene_gen.clip_transcodes_tooltip"
+        onChange={(v) => setOptions({ clipTranscodes: v })}
+      />
       <BooleanSetting
         id="overwrite"
         checked={options.overwrite ?? false}
@@ -153,6 +153,12 @@ export const LibraryTasks: React.FC = () => {
               existing.previewOptions?.previewExcludeEnd,
             previewPreset:
               general.previewPreset ?? existing.previewOptions?.previewPreset,
+            previewImageFormat:
+              general.previewImageFormat ??
+              existing.previewOptions?.previewImageFormat,
+            previewVideoCodec:
+              general.previewVideoCodec ??
+              existing.previewOptions?.previewVideoCodec,
           },
         }));
       }
@@ -647,6 +647,8 @@ export const useSceneResetO = (id: string) =>
 export const useSceneGenerateScreenshot = () =>
   GQL.useSceneGenerateScreenshotMutation();
 
+export const useSceneExtractClip = () => GQL.useSceneExtractClipMutation();
+
 export const mutateSceneSetPrimaryFile = (id: string, fileID: string) =>
   client.mutate<GQL.SceneUpdateMutation>({
     mutation: GQL.SceneUpdateDocument,
@@ -854,7 +854,7 @@ export const LightboxComponent: React.FC<IProps> = ({
               <div className={`${CLASSNAME_IMAGE}`} key={image.paths.image}>
                 {i >= currentIndex - 1 && i <= currentIndex + 1 ? (
                   <LightboxImage
-                    src={image.paths.image ?? ""}
+                    src={image.paths.video || image.paths.image || ""}
                     displayMode={displayMode}
                     scaleUp={lightboxSettings?.scaleUp ?? false}
                     scrollMode={
@@ -872,7 +872,10 @@ export const LightboxComponent: React.FC<IProps> = ({
                     debouncedScrollReset={debouncedScrollReset}
                     onLeft={handleLeft}
                     onRight={handleRight}
-                    isVideo={isVideo(image.visual_files?.[0] ?? {})}
+                    isVideo={
+                      !!image.paths.video ||
+                      isVideo(image.visual_files?.[0] ?? {})
+                    }
                   />
                 ) : undefined}
               </div>
@@ -4,6 +4,7 @@ interface IImagePaths {
   image?: GQL.Maybe<string>;
   thumbnail?: GQL.Maybe<string>;
   preview?: GQL.Maybe<string>;
+  video?: GQL.Maybe<string>;
 }
 
 interface IFiles {
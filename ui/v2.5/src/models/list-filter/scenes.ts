@@ -47,6 +47,9 @@ const sortByOptions = [
   "movie_scene_number",
   "interactive",
   "interactive_speed",
+  "interactive_average_speed",
+  "interactive_max_speed",
+  "interactive_action_density",
   "perceptual_similarity",
   ...MediaSortByOptions,
 ].map(ListFilterOptions.createSortBy);
@@ -95,6 +98,9 @@ const criterionOptions = [
   InteractiveCriterionOption,
   CaptionsCriterionOption,
   createMandatoryNumberCriterionOption("interactive_speed"),
+  createMandatoryNumberCriterionOption("interactive_average_speed"),
+  createMandatoryNumberCriterionOption("interactive_max_speed"),
+  createMandatoryNumberCriterionOption("interactive_action_density"),
   createMandatoryNumberCriterionOption("file_count"),
   createDateCriterionOption("date"),
   createMandatoryTimestampCriterionOption("created_at"),
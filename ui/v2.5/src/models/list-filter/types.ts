@@ -165,6 +165,9 @@ export type CriterionType =
   | "stash_id"
   | "interactive"
   | "interactive_speed"
+  | "interactive_average_speed"
+  | "interactive_max_speed"
+  | "interactive_action_density"
   | "captions"
   | "resume_time"
   | "play_count"